@@ -188,11 +188,273 @@ func (m *DriveLocateResponse) GetStatus() int32 {
 	return 0
 }
 
+type DriveFirmwareUpdateRequest struct {
+	DriveSerialNumber string `protobuf:"bytes,1,opt,name=driveSerialNumber,proto3" json:"driveSerialNumber,omitempty"`
+	// path to the firmware image on the node
+	Image                string   `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DriveFirmwareUpdateRequest) Reset()         { *m = DriveFirmwareUpdateRequest{} }
+func (m *DriveFirmwareUpdateRequest) String() string { return proto.CompactTextString(m) }
+func (*DriveFirmwareUpdateRequest) ProtoMessage()    {}
+func (*DriveFirmwareUpdateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_65bf77650f5c7dcf, []int{4}
+}
+
+func (m *DriveFirmwareUpdateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DriveFirmwareUpdateRequest.Unmarshal(m, b)
+}
+func (m *DriveFirmwareUpdateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DriveFirmwareUpdateRequest.Marshal(b, m, deterministic)
+}
+func (m *DriveFirmwareUpdateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DriveFirmwareUpdateRequest.Merge(m, src)
+}
+func (m *DriveFirmwareUpdateRequest) XXX_Size() int {
+	return xxx_messageInfo_DriveFirmwareUpdateRequest.Size(m)
+}
+func (m *DriveFirmwareUpdateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DriveFirmwareUpdateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DriveFirmwareUpdateRequest proto.InternalMessageInfo
+
+func (m *DriveFirmwareUpdateRequest) GetDriveSerialNumber() string {
+	if m != nil {
+		return m.DriveSerialNumber
+	}
+	return ""
+}
+
+func (m *DriveFirmwareUpdateRequest) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+type DriveFirmwareUpdateResponse struct {
+	// firmware version reported by the drive after update
+	Firmware             string   `protobuf:"bytes,1,opt,name=firmware,proto3" json:"firmware,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DriveFirmwareUpdateResponse) Reset()         { *m = DriveFirmwareUpdateResponse{} }
+func (m *DriveFirmwareUpdateResponse) String() string { return proto.CompactTextString(m) }
+func (*DriveFirmwareUpdateResponse) ProtoMessage()    {}
+func (*DriveFirmwareUpdateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_65bf77650f5c7dcf, []int{5}
+}
+
+func (m *DriveFirmwareUpdateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DriveFirmwareUpdateResponse.Unmarshal(m, b)
+}
+func (m *DriveFirmwareUpdateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DriveFirmwareUpdateResponse.Marshal(b, m, deterministic)
+}
+func (m *DriveFirmwareUpdateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DriveFirmwareUpdateResponse.Merge(m, src)
+}
+func (m *DriveFirmwareUpdateResponse) XXX_Size() int {
+	return xxx_messageInfo_DriveFirmwareUpdateResponse.Size(m)
+}
+func (m *DriveFirmwareUpdateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DriveFirmwareUpdateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DriveFirmwareUpdateResponse proto.InternalMessageInfo
+
+func (m *DriveFirmwareUpdateResponse) GetFirmware() string {
+	if m != nil {
+		return m.Firmware
+	}
+	return ""
+}
+
+type DriveNamespacesRequest struct {
+	DriveSerialNumber string `protobuf:"bytes,1,opt,name=driveSerialNumber,proto3" json:"driveSerialNumber,omitempty"`
+	// amount of NVMe namespaces of equal size, existing namespaces of the drive's controller are deleted
+	Count                int32    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DriveNamespacesRequest) Reset()         { *m = DriveNamespacesRequest{} }
+func (m *DriveNamespacesRequest) String() string { return proto.CompactTextString(m) }
+func (*DriveNamespacesRequest) ProtoMessage()    {}
+func (*DriveNamespacesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_65bf77650f5c7dcf, []int{6}
+}
+
+func (m *DriveNamespacesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DriveNamespacesRequest.Unmarshal(m, b)
+}
+func (m *DriveNamespacesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DriveNamespacesRequest.Marshal(b, m, deterministic)
+}
+func (m *DriveNamespacesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DriveNamespacesRequest.Merge(m, src)
+}
+func (m *DriveNamespacesRequest) XXX_Size() int {
+	return xxx_messageInfo_DriveNamespacesRequest.Size(m)
+}
+func (m *DriveNamespacesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DriveNamespacesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DriveNamespacesRequest proto.InternalMessageInfo
+
+func (m *DriveNamespacesRequest) GetDriveSerialNumber() string {
+	if m != nil {
+		return m.DriveSerialNumber
+	}
+	return ""
+}
+
+func (m *DriveNamespacesRequest) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type DriveNamespacesResponse struct {
+	// serial numbers of drives which correspond to created namespaces
+	SerialNumbers        []string `protobuf:"bytes,1,rep,name=serialNumbers,proto3" json:"serialNumbers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DriveNamespacesResponse) Reset()         { *m = DriveNamespacesResponse{} }
+func (m *DriveNamespacesResponse) String() string { return proto.CompactTextString(m) }
+func (*DriveNamespacesResponse) ProtoMessage()    {}
+func (*DriveNamespacesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_65bf77650f5c7dcf, []int{7}
+}
+
+func (m *DriveNamespacesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DriveNamespacesResponse.Unmarshal(m, b)
+}
+func (m *DriveNamespacesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DriveNamespacesResponse.Marshal(b, m, deterministic)
+}
+func (m *DriveNamespacesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DriveNamespacesResponse.Merge(m, src)
+}
+func (m *DriveNamespacesResponse) XXX_Size() int {
+	return xxx_messageInfo_DriveNamespacesResponse.Size(m)
+}
+func (m *DriveNamespacesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DriveNamespacesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DriveNamespacesResponse proto.InternalMessageInfo
+
+func (m *DriveNamespacesResponse) GetSerialNumbers() []string {
+	if m != nil {
+		return m.SerialNumbers
+	}
+	return nil
+}
+
+type VersionRequest struct {
+	// API versions supported by the client
+	Supported            []int32  `protobuf:"varint,1,rep,packed,name=supported,proto3" json:"supported,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VersionRequest) Reset()         { *m = VersionRequest{} }
+func (m *VersionRequest) String() string { return proto.CompactTextString(m) }
+func (*VersionRequest) ProtoMessage()    {}
+func (*VersionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_65bf77650f5c7dcf, []int{8}
+}
+
+func (m *VersionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VersionRequest.Unmarshal(m, b)
+}
+func (m *VersionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VersionRequest.Marshal(b, m, deterministic)
+}
+func (m *VersionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VersionRequest.Merge(m, src)
+}
+func (m *VersionRequest) XXX_Size() int {
+	return xxx_messageInfo_VersionRequest.Size(m)
+}
+func (m *VersionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VersionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VersionRequest proto.InternalMessageInfo
+
+func (m *VersionRequest) GetSupported() []int32 {
+	if m != nil {
+		return m.Supported
+	}
+	return nil
+}
+
+type VersionResponse struct {
+	// API version chosen by the server, the highest version supported by both sides
+	Version              int32    `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VersionResponse) Reset()         { *m = VersionResponse{} }
+func (m *VersionResponse) String() string { return proto.CompactTextString(m) }
+func (*VersionResponse) ProtoMessage()    {}
+func (*VersionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_65bf77650f5c7dcf, []int{9}
+}
+
+func (m *VersionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VersionResponse.Unmarshal(m, b)
+}
+func (m *VersionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VersionResponse.Marshal(b, m, deterministic)
+}
+func (m *VersionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VersionResponse.Merge(m, src)
+}
+func (m *VersionResponse) XXX_Size() int {
+	return xxx_messageInfo_VersionResponse.Size(m)
+}
+func (m *VersionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VersionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VersionResponse proto.InternalMessageInfo
+
+func (m *VersionResponse) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*DrivesRequest)(nil), "v1api.DrivesRequest")
 	proto.RegisterType((*DrivesResponse)(nil), "v1api.DrivesResponse")
 	proto.RegisterType((*DriveLocateRequest)(nil), "v1api.DriveLocateRequest")
 	proto.RegisterType((*DriveLocateResponse)(nil), "v1api.DriveLocateResponse")
+	proto.RegisterType((*DriveFirmwareUpdateRequest)(nil), "v1api.DriveFirmwareUpdateRequest")
+	proto.RegisterType((*DriveFirmwareUpdateResponse)(nil), "v1api.DriveFirmwareUpdateResponse")
+	proto.RegisterType((*DriveNamespacesRequest)(nil), "v1api.DriveNamespacesRequest")
+	proto.RegisterType((*DriveNamespacesResponse)(nil), "v1api.DriveNamespacesResponse")
+	proto.RegisterType((*VersionRequest)(nil), "v1api.VersionRequest")
+	proto.RegisterType((*VersionResponse)(nil), "v1api.VersionResponse")
 }
 
 func init() {
@@ -200,24 +462,36 @@ func init() {
 }
 
 var fileDescriptor_65bf77650f5c7dcf = []byte{
-	// 267 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x51, 0xcf, 0x4b, 0xc3, 0x30,
-	0x14, 0xb6, 0x4a, 0x2b, 0xbe, 0x6d, 0x82, 0x51, 0x47, 0xed, 0xa9, 0xe4, 0x62, 0x0f, 0x5a, 0x70,
-	0x7a, 0x16, 0x94, 0x81, 0x08, 0xc3, 0x43, 0xbc, 0xed, 0x96, 0xb5, 0x0f, 0x09, 0xba, 0xa6, 0xe6,
-	0xa5, 0x05, 0xff, 0x0c, 0xff, 0x63, 0x59, 0x92, 0xc2, 0xa6, 0x1e, 0xbf, 0x7c, 0x1f, 0xdf, 0x8f,
-	0x3c, 0x38, 0xa9, 0x8d, 0xea, 0x71, 0xfd, 0x66, 0xa8, 0xaf, 0xca, 0xd6, 0x68, 0xab, 0x59, 0xdc,
-	0xdf, 0xc8, 0x56, 0x65, 0x23, 0xfb, 0xd5, 0x22, 0xf9, 0x37, 0x7e, 0x09, 0x93, 0xf9, 0x46, 0x48,
-	0x02, 0x3f, 0x3b, 0x24, 0xcb, 0xa6, 0x90, 0x34, 0xba, 0xc6, 0xe7, 0x3a, 0x8d, 0xf2, 0xa8, 0x38,
-	0x12, 0x01, 0xf1, 0x3b, 0x38, 0x1e, 0x84, 0xd4, 0xea, 0x86, 0x90, 0x71, 0x88, 0x6b, 0x45, 0xef,
-	0x94, 0x46, 0xf9, 0x41, 0x31, 0x9a, 0x8d, 0x4b, 0x67, 0x5f, 0x3a, 0x95, 0xf0, 0x14, 0x5f, 0x02,
-	0x73, 0x78, 0xa1, 0x2b, 0x69, 0x71, 0xc8, 0xb8, 0x0a, 0xed, 0x5e, 0xd1, 0x28, 0xf9, 0xf1, 0xd2,
-	0xad, 0x57, 0x68, 0x42, 0xdc, 0x5f, 0x62, 0xd3, 0x48, 0x56, 0x56, 0xe9, 0x26, 0xdd, 0xcf, 0xa3,
-	0x22, 0x16, 0x01, 0xf1, 0x6b, 0x38, 0xdd, 0xf1, 0x0e, 0xb5, 0xa6, 0x90, 0x90, 0x95, 0xb6, 0x23,
-	0xe7, 0x18, 0x8b, 0x80, 0x66, 0xdf, 0x11, 0x8c, 0xe7, 0xc1, 0xbc, 0x57, 0x15, 0xb2, 0x7b, 0x98,
-	0x3c, 0xa1, 0xf5, 0xa3, 0x16, 0x8a, 0x2c, 0x3b, 0xdb, 0x5e, 0x30, 0x7c, 0x48, 0x76, 0xfe, 0xeb,
-	0xd5, 0xc7, 0xf0, 0x3d, 0xf6, 0x00, 0x89, 0x8f, 0x66, 0x17, 0xdb, 0x92, 0x9d, 0xa9, 0x59, 0xf6,
-	0x1f, 0x35, 0x58, 0x3c, 0x1e, 0x2e, 0xfd, 0x4d, 0x56, 0x89, 0xbb, 0xc6, 0xed, 0x4f, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0xa9, 0x6e, 0x7f, 0xab, 0xb6, 0x01, 0x00, 0x00,
+	// 454 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x25, 0x54, 0x4e, 0xc9, 0xb4, 0x09, 0xb0, 0x94, 0x10, 0x96, 0x0f, 0x85, 0x15, 0x12, 0x91,
+	0x80, 0x48, 0x14, 0x2e, 0x1c, 0x00, 0x01, 0x15, 0x15, 0x52, 0xd5, 0x83, 0x2b, 0x90, 0xa8, 0x38,
+	0xb0, 0xb5, 0x87, 0x6a, 0x05, 0xf6, 0x9a, 0x9d, 0xb5, 0x11, 0x37, 0x7e, 0x3a, 0xea, 0x7e, 0x18,
+	0xa7, 0x09, 0x1c, 0x72, 0x7c, 0x6f, 0xde, 0xbe, 0x79, 0x9e, 0x19, 0x19, 0xae, 0xe6, 0x46, 0x35,
+	0x58, 0x9c, 0x1a, 0x6a, 0xb2, 0x79, 0x65, 0xb4, 0xd5, 0x2c, 0x69, 0x9e, 0xc8, 0x4a, 0xf1, 0x2d,
+	0xfb, 0xab, 0x42, 0xf2, 0x9c, 0x78, 0x00, 0xc3, 0xbd, 0x33, 0x21, 0xa5, 0xf8, 0xa3, 0x46, 0xb2,
+	0x6c, 0x0c, 0xfd, 0x52, 0xe7, 0xf8, 0x3e, 0x9f, 0xf4, 0xa6, 0xbd, 0xd9, 0x20, 0x0d, 0x48, 0x3c,
+	0x83, 0x51, 0x14, 0x52, 0xa5, 0x4b, 0x42, 0x26, 0x20, 0xc9, 0x15, 0x7d, 0xa3, 0x49, 0x6f, 0xba,
+	0x31, 0xdb, 0xda, 0xdd, 0x9e, 0x3b, 0xfb, 0xb9, 0x53, 0xa5, 0xbe, 0x24, 0x8e, 0x81, 0x39, 0x7c,
+	0xa0, 0x33, 0x69, 0x31, 0xf6, 0x78, 0x14, 0xd2, 0x1d, 0xa1, 0x51, 0xf2, 0xfb, 0x61, 0x5d, 0x9c,
+	0xa0, 0x09, 0xed, 0x96, 0x0b, 0x67, 0x89, 0x64, 0x66, 0x95, 0x2e, 0x27, 0x17, 0xa7, 0xbd, 0x59,
+	0x92, 0x06, 0x24, 0x1e, 0xc3, 0xb5, 0x05, 0xef, 0x10, 0x6b, 0x0c, 0x7d, 0xb2, 0xd2, 0xd6, 0xe4,
+	0x1c, 0x93, 0x34, 0x20, 0xf1, 0x05, 0xb8, 0x93, 0xbf, 0x53, 0xa6, 0xf8, 0x29, 0x0d, 0x7e, 0xa8,
+	0xf2, 0xb5, 0x23, 0xed, 0x40, 0xa2, 0x0a, 0x79, 0x8a, 0x2e, 0xd1, 0x20, 0xf5, 0x40, 0x3c, 0x87,
+	0x5b, 0x2b, 0x3b, 0x84, 0x60, 0x1c, 0x2e, 0x7d, 0x0d, 0x95, 0xe0, 0xdc, 0x62, 0xf1, 0x19, 0xc6,
+	0xee, 0xe9, 0xa1, 0x2c, 0x90, 0x2a, 0x99, 0x21, 0xad, 0x1d, 0x2c, 0xd3, 0x75, 0x69, 0xc3, 0xa8,
+	0x3c, 0x10, 0xaf, 0xe0, 0xc6, 0x92, 0x7b, 0x08, 0x75, 0x1f, 0x86, 0xd4, 0x31, 0xf0, 0xcb, 0x1c,
+	0xa4, 0x8b, 0xa4, 0x98, 0xc3, 0xe8, 0x23, 0x1a, 0x52, 0xba, 0x8c, 0xb1, 0x6e, 0xc3, 0x80, 0xea,
+	0xaa, 0xd2, 0xc6, 0x62, 0xee, 0xde, 0x24, 0xe9, 0x5f, 0x42, 0x3c, 0x84, 0xcb, 0xad, 0x3e, 0x34,
+	0x9a, 0xc0, 0x66, 0xe3, 0xa9, 0xb0, 0x97, 0x08, 0x77, 0x7f, 0x6f, 0xc0, 0xf6, 0x5e, 0xf8, 0x92,
+	0x46, 0x65, 0xc8, 0x5e, 0xc2, 0x70, 0x1f, 0xad, 0xa3, 0xe8, 0x40, 0x91, 0x65, 0x3b, 0xdd, 0xd3,
+	0x8a, 0x93, 0xe1, 0xd7, 0xcf, 0xb1, 0xbe, 0x91, 0xb8, 0xc0, 0x5e, 0x43, 0xdf, 0xdf, 0x04, 0xbb,
+	0xd9, 0x95, 0x2c, 0xdc, 0x20, 0xe7, 0xab, 0x4a, 0xad, 0xc5, 0x27, 0x18, 0xf9, 0xed, 0xc5, 0x5d,
+	0xb2, 0x7b, 0x5d, 0xfd, 0xca, 0x1b, 0xe2, 0xe2, 0x7f, 0x92, 0xd6, 0xfa, 0x08, 0xae, 0xbc, 0x35,
+	0x28, 0x6d, 0x67, 0x1b, 0xec, 0x4e, 0xf7, 0xe5, 0xd2, 0x0d, 0xf0, 0xbb, 0xff, 0x2a, 0xb7, 0xa6,
+	0x2f, 0x00, 0xf6, 0xd1, 0x86, 0x99, 0xb3, 0x38, 0x99, 0xc5, 0x9d, 0xf1, 0xf1, 0x79, 0x3a, 0x3e,
+	0x7f, 0xb3, 0x79, 0xec, 0xff, 0x0d, 0x27, 0x7d, 0xf7, 0x57, 0x78, 0xfa, 0x67, 0x00, 0x14, 0x2c,
+	0xae, 0xae, 0x3e, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -234,6 +508,10 @@ const _ = grpc.SupportPackageIsVersion6
 type DriveServiceClient interface {
 	GetDrivesList(ctx context.Context, in *DrivesRequest, opts ...grpc.CallOption) (*DrivesResponse, error)
 	Locate(ctx context.Context, in *DriveLocateRequest, opts ...grpc.CallOption) (*DriveLocateResponse, error)
+	UpdateFirmware(ctx context.Context, in *DriveFirmwareUpdateRequest, opts ...grpc.CallOption) (*DriveFirmwareUpdateResponse, error)
+	CreateNamespaces(ctx context.Context, in *DriveNamespacesRequest, opts ...grpc.CallOption) (*DriveNamespacesResponse, error)
+	// servers without GetVersion support only v1 API
+	GetVersion(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error)
 }
 
 type driveServiceClient struct {
@@ -262,10 +540,41 @@ func (c *driveServiceClient) Locate(ctx context.Context, in *DriveLocateRequest,
 	return out, nil
 }
 
+func (c *driveServiceClient) UpdateFirmware(ctx context.Context, in *DriveFirmwareUpdateRequest, opts ...grpc.CallOption) (*DriveFirmwareUpdateResponse, error) {
+	out := new(DriveFirmwareUpdateResponse)
+	err := c.cc.Invoke(ctx, "/v1api.DriveService/UpdateFirmware", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driveServiceClient) CreateNamespaces(ctx context.Context, in *DriveNamespacesRequest, opts ...grpc.CallOption) (*DriveNamespacesResponse, error) {
+	out := new(DriveNamespacesResponse)
+	err := c.cc.Invoke(ctx, "/v1api.DriveService/CreateNamespaces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driveServiceClient) GetVersion(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error) {
+	out := new(VersionResponse)
+	err := c.cc.Invoke(ctx, "/v1api.DriveService/GetVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DriveServiceServer is the server API for DriveService service.
 type DriveServiceServer interface {
 	GetDrivesList(context.Context, *DrivesRequest) (*DrivesResponse, error)
 	Locate(context.Context, *DriveLocateRequest) (*DriveLocateResponse, error)
+	UpdateFirmware(context.Context, *DriveFirmwareUpdateRequest) (*DriveFirmwareUpdateResponse, error)
+	CreateNamespaces(context.Context, *DriveNamespacesRequest) (*DriveNamespacesResponse, error)
+	// servers without GetVersion support only v1 API
+	GetVersion(context.Context, *VersionRequest) (*VersionResponse, error)
 }
 
 // UnimplementedDriveServiceServer can be embedded to have forward compatible implementations.
@@ -278,6 +587,15 @@ func (*UnimplementedDriveServiceServer) GetDrivesList(ctx context.Context, req *
 func (*UnimplementedDriveServiceServer) Locate(ctx context.Context, req *DriveLocateRequest) (*DriveLocateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Locate not implemented")
 }
+func (*UnimplementedDriveServiceServer) UpdateFirmware(ctx context.Context, req *DriveFirmwareUpdateRequest) (*DriveFirmwareUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFirmware not implemented")
+}
+func (*UnimplementedDriveServiceServer) CreateNamespaces(ctx context.Context, req *DriveNamespacesRequest) (*DriveNamespacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNamespaces not implemented")
+}
+func (*UnimplementedDriveServiceServer) GetVersion(ctx context.Context, req *VersionRequest) (*VersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
 
 func RegisterDriveServiceServer(s *grpc.Server, srv DriveServiceServer) {
 	s.RegisterService(&_DriveService_serviceDesc, srv)
@@ -319,6 +637,60 @@ func _DriveService_Locate_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DriveService_UpdateFirmware_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DriveFirmwareUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriveServiceServer).UpdateFirmware(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1api.DriveService/UpdateFirmware",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriveServiceServer).UpdateFirmware(ctx, req.(*DriveFirmwareUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriveService_CreateNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DriveNamespacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriveServiceServer).CreateNamespaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1api.DriveService/CreateNamespaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriveServiceServer).CreateNamespaces(ctx, req.(*DriveNamespacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriveService_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriveServiceServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1api.DriveService/GetVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriveServiceServer).GetVersion(ctx, req.(*VersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _DriveService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "v1api.DriveService",
 	HandlerType: (*DriveServiceServer)(nil),
@@ -331,6 +703,18 @@ var _DriveService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Locate",
 			Handler:    _DriveService_Locate_Handler,
 		},
+		{
+			MethodName: "UpdateFirmware",
+			Handler:    _DriveService_UpdateFirmware_Handler,
+		},
+		{
+			MethodName: "CreateNamespaces",
+			Handler:    _DriveService_CreateNamespaces_Handler,
+		},
+		{
+			MethodName: "GetVersion",
+			Handler:    _DriveService_GetVersion_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "drivemgrsvc.proto",
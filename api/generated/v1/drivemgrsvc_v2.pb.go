@@ -0,0 +1,362 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: drivemgrsvc_v2.proto
+
+package v1api
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type DriveHealthDetails struct {
+	// reason of the drive health, e.g. SMART attribute which exceeded threshold
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// raw SMART attributes reported by the drive
+	SmartAttributes      map[string]string `protobuf:"bytes,2,rep,name=smartAttributes,proto3" json:"smartAttributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DriveHealthDetails) Reset()         { *m = DriveHealthDetails{} }
+func (m *DriveHealthDetails) String() string { return proto.CompactTextString(m) }
+func (*DriveHealthDetails) ProtoMessage()    {}
+func (*DriveHealthDetails) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60cb6bb9696086b7, []int{0}
+}
+
+func (m *DriveHealthDetails) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DriveHealthDetails.Unmarshal(m, b)
+}
+func (m *DriveHealthDetails) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DriveHealthDetails.Marshal(b, m, deterministic)
+}
+func (m *DriveHealthDetails) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DriveHealthDetails.Merge(m, src)
+}
+func (m *DriveHealthDetails) XXX_Size() int {
+	return xxx_messageInfo_DriveHealthDetails.Size(m)
+}
+func (m *DriveHealthDetails) XXX_DiscardUnknown() {
+	xxx_messageInfo_DriveHealthDetails.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DriveHealthDetails proto.InternalMessageInfo
+
+func (m *DriveHealthDetails) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *DriveHealthDetails) GetSmartAttributes() map[string]string {
+	if m != nil {
+		return m.SmartAttributes
+	}
+	return nil
+}
+
+type DriveSlotInfo struct {
+	Enclosure string `protobuf:"bytes,1,opt,name=enclosure,proto3" json:"enclosure,omitempty"`
+	Slot      string `protobuf:"bytes,2,opt,name=slot,proto3" json:"slot,omitempty"`
+	Bay       string `protobuf:"bytes,3,opt,name=bay,proto3" json:"bay,omitempty"`
+	// path to the SES device of the enclosure (backplane) which holds drive
+	Backplane            string   `protobuf:"bytes,4,opt,name=backplane,proto3" json:"backplane,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DriveSlotInfo) Reset()         { *m = DriveSlotInfo{} }
+func (m *DriveSlotInfo) String() string { return proto.CompactTextString(m) }
+func (*DriveSlotInfo) ProtoMessage()    {}
+func (*DriveSlotInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60cb6bb9696086b7, []int{1}
+}
+
+func (m *DriveSlotInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DriveSlotInfo.Unmarshal(m, b)
+}
+func (m *DriveSlotInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DriveSlotInfo.Marshal(b, m, deterministic)
+}
+func (m *DriveSlotInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DriveSlotInfo.Merge(m, src)
+}
+func (m *DriveSlotInfo) XXX_Size() int {
+	return xxx_messageInfo_DriveSlotInfo.Size(m)
+}
+func (m *DriveSlotInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_DriveSlotInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DriveSlotInfo proto.InternalMessageInfo
+
+func (m *DriveSlotInfo) GetEnclosure() string {
+	if m != nil {
+		return m.Enclosure
+	}
+	return ""
+}
+
+func (m *DriveSlotInfo) GetSlot() string {
+	if m != nil {
+		return m.Slot
+	}
+	return ""
+}
+
+func (m *DriveSlotInfo) GetBay() string {
+	if m != nil {
+		return m.Bay
+	}
+	return ""
+}
+
+func (m *DriveSlotInfo) GetBackplane() string {
+	if m != nil {
+		return m.Backplane
+	}
+	return ""
+}
+
+type DriveDetails struct {
+	Drive  *Drive              `protobuf:"bytes,1,opt,name=drive,proto3" json:"drive,omitempty"`
+	Health *DriveHealthDetails `protobuf:"bytes,2,opt,name=health,proto3" json:"health,omitempty"`
+	Slot   *DriveSlotInfo      `protobuf:"bytes,3,opt,name=slot,proto3" json:"slot,omitempty"`
+	// temperature in Celsius, 0 if drive manager doesn't report it
+	Temperature          int32    `protobuf:"varint,4,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DriveDetails) Reset()         { *m = DriveDetails{} }
+func (m *DriveDetails) String() string { return proto.CompactTextString(m) }
+func (*DriveDetails) ProtoMessage()    {}
+func (*DriveDetails) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60cb6bb9696086b7, []int{2}
+}
+
+func (m *DriveDetails) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DriveDetails.Unmarshal(m, b)
+}
+func (m *DriveDetails) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DriveDetails.Marshal(b, m, deterministic)
+}
+func (m *DriveDetails) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DriveDetails.Merge(m, src)
+}
+func (m *DriveDetails) XXX_Size() int {
+	return xxx_messageInfo_DriveDetails.Size(m)
+}
+func (m *DriveDetails) XXX_DiscardUnknown() {
+	xxx_messageInfo_DriveDetails.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DriveDetails proto.InternalMessageInfo
+
+func (m *DriveDetails) GetDrive() *Drive {
+	if m != nil {
+		return m.Drive
+	}
+	return nil
+}
+
+func (m *DriveDetails) GetHealth() *DriveHealthDetails {
+	if m != nil {
+		return m.Health
+	}
+	return nil
+}
+
+func (m *DriveDetails) GetSlot() *DriveSlotInfo {
+	if m != nil {
+		return m.Slot
+	}
+	return nil
+}
+
+func (m *DriveDetails) GetTemperature() int32 {
+	if m != nil {
+		return m.Temperature
+	}
+	return 0
+}
+
+type DrivesDetailsResponse struct {
+	Disks                []*DriveDetails `protobuf:"bytes,1,rep,name=disks,proto3" json:"disks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *DrivesDetailsResponse) Reset()         { *m = DrivesDetailsResponse{} }
+func (m *DrivesDetailsResponse) String() string { return proto.CompactTextString(m) }
+func (*DrivesDetailsResponse) ProtoMessage()    {}
+func (*DrivesDetailsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60cb6bb9696086b7, []int{3}
+}
+
+func (m *DrivesDetailsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DrivesDetailsResponse.Unmarshal(m, b)
+}
+func (m *DrivesDetailsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DrivesDetailsResponse.Marshal(b, m, deterministic)
+}
+func (m *DrivesDetailsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DrivesDetailsResponse.Merge(m, src)
+}
+func (m *DrivesDetailsResponse) XXX_Size() int {
+	return xxx_messageInfo_DrivesDetailsResponse.Size(m)
+}
+func (m *DrivesDetailsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DrivesDetailsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DrivesDetailsResponse proto.InternalMessageInfo
+
+func (m *DrivesDetailsResponse) GetDisks() []*DriveDetails {
+	if m != nil {
+		return m.Disks
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*DriveHealthDetails)(nil), "v1api.DriveHealthDetails")
+	proto.RegisterMapType((map[string]string)(nil), "v1api.DriveHealthDetails.SmartAttributesEntry")
+	proto.RegisterType((*DriveSlotInfo)(nil), "v1api.DriveSlotInfo")
+	proto.RegisterType((*DriveDetails)(nil), "v1api.DriveDetails")
+	proto.RegisterType((*DrivesDetailsResponse)(nil), "v1api.DrivesDetailsResponse")
+}
+
+func init() {
+	proto.RegisterFile("drivemgrsvc_v2.proto", fileDescriptor_60cb6bb9696086b7)
+}
+
+var fileDescriptor_60cb6bb9696086b7 = []byte{
+	// 399 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0xcf, 0x8e, 0x94, 0x40,
+	0x10, 0xc6, 0x65, 0x58, 0xc6, 0x6c, 0xb1, 0xeb, 0x9f, 0x16, 0x0d, 0x92, 0x3d, 0x4c, 0x38, 0x8d,
+	0x17, 0x92, 0xc5, 0x8b, 0xf1, 0xe6, 0x64, 0x37, 0x6a, 0xe2, 0x89, 0x4d, 0xd4, 0x78, 0x31, 0x0d,
+	0x5b, 0xba, 0x64, 0x18, 0x9a, 0xe9, 0x2a, 0x48, 0x78, 0x2c, 0x5f, 0xc3, 0xa7, 0x32, 0x74, 0x83,
+	0x32, 0xa3, 0xde, 0xba, 0x3f, 0xaa, 0xbe, 0x5f, 0xf5, 0x47, 0x41, 0x70, 0xab, 0xcb, 0x0e, 0x77,
+	0xdf, 0x35, 0x75, 0xc5, 0xd7, 0x2e, 0x4d, 0x1a, 0xad, 0x58, 0x09, 0xaf, 0xbb, 0x94, 0x4d, 0x19,
+	0xf9, 0xdc, 0x37, 0x48, 0x56, 0x8b, 0x1e, 0xcf, 0x2a, 0xad, 0x14, 0xff, 0x74, 0x40, 0x5c, 0x0d,
+	0xea, 0x3b, 0x94, 0x15, 0xdf, 0x5d, 0x21, 0xcb, 0xb2, 0x22, 0xf1, 0x0c, 0x96, 0x1a, 0x25, 0xa9,
+	0x3a, 0x74, 0x56, 0xce, 0xfa, 0x34, 0x1b, 0x6f, 0xe2, 0x33, 0x3c, 0xa4, 0x9d, 0xd4, 0xfc, 0x86,
+	0x59, 0x97, 0x79, 0xcb, 0x48, 0xe1, 0x62, 0xe5, 0xae, 0xfd, 0x34, 0x49, 0x0c, 0x2f, 0xf9, 0xdb,
+	0x2b, 0xb9, 0x39, 0x6c, 0xb8, 0xae, 0x59, 0xf7, 0xd9, 0xb1, 0x4d, 0xb4, 0x81, 0xe0, 0x5f, 0x85,
+	0xe2, 0x11, 0xb8, 0x5b, 0xec, 0xc7, 0x31, 0x86, 0xa3, 0x08, 0xc0, 0xeb, 0x64, 0xd5, 0x62, 0xb8,
+	0x30, 0x9a, 0xbd, 0xbc, 0x5e, 0xbc, 0x72, 0xe2, 0x3d, 0x9c, 0x1b, 0xfe, 0x4d, 0xa5, 0xf8, 0x7d,
+	0xfd, 0x4d, 0x89, 0x0b, 0x38, 0xc5, 0xba, 0xa8, 0x14, 0xb5, 0x1a, 0x47, 0x8b, 0x3f, 0x82, 0x10,
+	0x70, 0x42, 0x95, 0xe2, 0xd1, 0xc7, 0x9c, 0x07, 0x5c, 0x2e, 0xfb, 0xd0, 0xb5, 0xb8, 0x5c, 0xf6,
+	0x83, 0x47, 0x2e, 0x8b, 0x6d, 0x53, 0xc9, 0x1a, 0xc3, 0x13, 0xeb, 0xf1, 0x5b, 0x88, 0x7f, 0x38,
+	0x70, 0x66, 0x98, 0x53, 0x72, 0x31, 0x78, 0x26, 0x65, 0x83, 0xf3, 0xd3, 0xb3, 0x79, 0x2e, 0x99,
+	0xfd, 0x24, 0x2e, 0x61, 0x79, 0x67, 0x22, 0x32, 0x68, 0x3f, 0x7d, 0xfe, 0xdf, 0xf0, 0xb2, 0xb1,
+	0x50, 0xac, 0xc7, 0x59, 0x5d, 0xd3, 0x10, 0xcc, 0x1b, 0xa6, 0xd7, 0x8e, 0x2f, 0x58, 0x81, 0xcf,
+	0xb8, 0x6b, 0x50, 0x4b, 0x6e, 0xb5, 0x9d, 0xd8, 0xcb, 0xe6, 0x52, 0xbc, 0x81, 0xa7, 0xa6, 0x91,
+	0x26, 0x08, 0x52, 0xa3, 0x6a, 0x42, 0xf1, 0x02, 0xbc, 0xdb, 0x92, 0xb6, 0x14, 0x3a, 0xe6, 0x9f,
+	0x3e, 0x99, 0x53, 0xa6, 0x5a, 0x5b, 0x91, 0x7e, 0x82, 0x07, 0x16, 0x8e, 0xba, 0x2b, 0x0b, 0xfc,
+	0x98, 0x8a, 0x6b, 0x38, 0x7f, 0x8b, 0x6c, 0x8d, 0x3f, 0x94, 0xc4, 0xe2, 0x60, 0x48, 0xca, 0x70,
+	0xdf, 0x22, 0x71, 0x74, 0x71, 0xa0, 0x1e, 0x4d, 0x10, 0xdf, 0xdb, 0xdc, 0xff, 0x62, 0x37, 0x37,
+	0x5f, 0x9a, 0x05, 0x7d, 0xf9, 0x6b, 0x00, 0x47, 0xed, 0x5c, 0x27, 0xdf, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// DriveServiceV2Client is the client API for DriveServiceV2 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DriveServiceV2Client interface {
+	GetDrivesList(ctx context.Context, in *DrivesRequest, opts ...grpc.CallOption) (*DrivesDetailsResponse, error)
+}
+
+type driveServiceV2Client struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDriveServiceV2Client(cc grpc.ClientConnInterface) DriveServiceV2Client {
+	return &driveServiceV2Client{cc}
+}
+
+func (c *driveServiceV2Client) GetDrivesList(ctx context.Context, in *DrivesRequest, opts ...grpc.CallOption) (*DrivesDetailsResponse, error) {
+	out := new(DrivesDetailsResponse)
+	err := c.cc.Invoke(ctx, "/v1api.DriveServiceV2/GetDrivesList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DriveServiceV2Server is the server API for DriveServiceV2 service.
+type DriveServiceV2Server interface {
+	GetDrivesList(context.Context, *DrivesRequest) (*DrivesDetailsResponse, error)
+}
+
+// UnimplementedDriveServiceV2Server can be embedded to have forward compatible implementations.
+type UnimplementedDriveServiceV2Server struct {
+}
+
+func (*UnimplementedDriveServiceV2Server) GetDrivesList(ctx context.Context, req *DrivesRequest) (*DrivesDetailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDrivesList not implemented")
+}
+
+func RegisterDriveServiceV2Server(s *grpc.Server, srv DriveServiceV2Server) {
+	s.RegisterService(&_DriveServiceV2_serviceDesc, srv)
+}
+
+func _DriveServiceV2_GetDrivesList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrivesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriveServiceV2Server).GetDrivesList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1api.DriveServiceV2/GetDrivesList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriveServiceV2Server).GetDrivesList(ctx, req.(*DrivesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DriveServiceV2_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v1api.DriveServiceV2",
+	HandlerType: (*DriveServiceV2Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDrivesList",
+			Handler:    _DriveServiceV2_GetDrivesList_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "drivemgrsvc_v2.proto",
+}
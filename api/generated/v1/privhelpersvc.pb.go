@@ -0,0 +1,713 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: privhelpersvc.proto
+
+package v1api
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type CmdRequest struct {
+	// command name followed by its arguments
+	Args                 []string `protobuf:"bytes,1,rep,name=args,proto3" json:"args,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CmdRequest) Reset()         { *m = CmdRequest{} }
+func (m *CmdRequest) String() string { return proto.CompactTextString(m) }
+func (*CmdRequest) ProtoMessage()    {}
+func (*CmdRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_139e62c0e6da31ea, []int{0}
+}
+
+func (m *CmdRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CmdRequest.Unmarshal(m, b)
+}
+func (m *CmdRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CmdRequest.Marshal(b, m, deterministic)
+}
+func (m *CmdRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CmdRequest.Merge(m, src)
+}
+func (m *CmdRequest) XXX_Size() int {
+	return xxx_messageInfo_CmdRequest.Size(m)
+}
+func (m *CmdRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CmdRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CmdRequest proto.InternalMessageInfo
+
+func (m *CmdRequest) GetArgs() []string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+type CmdResponse struct {
+	Stdout string `protobuf:"bytes,1,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr string `protobuf:"bytes,2,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	// error of the command execution, empty if command succeeded
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CmdResponse) Reset()         { *m = CmdResponse{} }
+func (m *CmdResponse) String() string { return proto.CompactTextString(m) }
+func (*CmdResponse) ProtoMessage()    {}
+func (*CmdResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_139e62c0e6da31ea, []int{1}
+}
+
+func (m *CmdResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CmdResponse.Unmarshal(m, b)
+}
+func (m *CmdResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CmdResponse.Marshal(b, m, deterministic)
+}
+func (m *CmdResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CmdResponse.Merge(m, src)
+}
+func (m *CmdResponse) XXX_Size() int {
+	return xxx_messageInfo_CmdResponse.Size(m)
+}
+func (m *CmdResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CmdResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CmdResponse proto.InternalMessageInfo
+
+func (m *CmdResponse) GetStdout() string {
+	if m != nil {
+		return m.Stdout
+	}
+	return ""
+}
+
+func (m *CmdResponse) GetStderr() string {
+	if m != nil {
+		return m.Stderr
+	}
+	return ""
+}
+
+func (m *CmdResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type MountRequest struct {
+	// device, directory or name of pseudo file system, empty for remount
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Target string `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// mount options, for example "--bind" or "-o" followed by comma separated options
+	Opts                 []string `protobuf:"bytes,3,rep,name=opts,proto3" json:"opts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MountRequest) Reset()         { *m = MountRequest{} }
+func (m *MountRequest) String() string { return proto.CompactTextString(m) }
+func (*MountRequest) ProtoMessage()    {}
+func (*MountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_139e62c0e6da31ea, []int{2}
+}
+
+func (m *MountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MountRequest.Unmarshal(m, b)
+}
+func (m *MountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MountRequest.Marshal(b, m, deterministic)
+}
+func (m *MountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MountRequest.Merge(m, src)
+}
+func (m *MountRequest) XXX_Size() int {
+	return xxx_messageInfo_MountRequest.Size(m)
+}
+func (m *MountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MountRequest proto.InternalMessageInfo
+
+func (m *MountRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *MountRequest) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *MountRequest) GetOpts() []string {
+	if m != nil {
+		return m.Opts
+	}
+	return nil
+}
+
+type PathRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PathRequest) Reset()         { *m = PathRequest{} }
+func (m *PathRequest) String() string { return proto.CompactTextString(m) }
+func (*PathRequest) ProtoMessage()    {}
+func (*PathRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_139e62c0e6da31ea, []int{3}
+}
+
+func (m *PathRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PathRequest.Unmarshal(m, b)
+}
+func (m *PathRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PathRequest.Marshal(b, m, deterministic)
+}
+func (m *PathRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PathRequest.Merge(m, src)
+}
+func (m *PathRequest) XXX_Size() int {
+	return xxx_messageInfo_PathRequest.Size(m)
+}
+func (m *PathRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PathRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PathRequest proto.InternalMessageInfo
+
+func (m *PathRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type MkFSRequest struct {
+	// file system type, for example xfs or ext4
+	FsType string `protobuf:"bytes,1,opt,name=fs_type,json=fsType,proto3" json:"fs_type,omitempty"`
+	Device string `protobuf:"bytes,2,opt,name=device,proto3" json:"device,omitempty"`
+	// additional mkfs options
+	Opts                 []string `protobuf:"bytes,3,rep,name=opts,proto3" json:"opts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MkFSRequest) Reset()         { *m = MkFSRequest{} }
+func (m *MkFSRequest) String() string { return proto.CompactTextString(m) }
+func (*MkFSRequest) ProtoMessage()    {}
+func (*MkFSRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_139e62c0e6da31ea, []int{4}
+}
+
+func (m *MkFSRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MkFSRequest.Unmarshal(m, b)
+}
+func (m *MkFSRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MkFSRequest.Marshal(b, m, deterministic)
+}
+func (m *MkFSRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MkFSRequest.Merge(m, src)
+}
+func (m *MkFSRequest) XXX_Size() int {
+	return xxx_messageInfo_MkFSRequest.Size(m)
+}
+func (m *MkFSRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MkFSRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MkFSRequest proto.InternalMessageInfo
+
+func (m *MkFSRequest) GetFsType() string {
+	if m != nil {
+		return m.FsType
+	}
+	return ""
+}
+
+func (m *MkFSRequest) GetDevice() string {
+	if m != nil {
+		return m.Device
+	}
+	return ""
+}
+
+func (m *MkFSRequest) GetOpts() []string {
+	if m != nil {
+		return m.Opts
+	}
+	return nil
+}
+
+type SysfsRequest struct {
+	// path of the attribute, for example /sys/block/sda/queue/scheduler
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SysfsRequest) Reset()         { *m = SysfsRequest{} }
+func (m *SysfsRequest) String() string { return proto.CompactTextString(m) }
+func (*SysfsRequest) ProtoMessage()    {}
+func (*SysfsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_139e62c0e6da31ea, []int{5}
+}
+
+func (m *SysfsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SysfsRequest.Unmarshal(m, b)
+}
+func (m *SysfsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SysfsRequest.Marshal(b, m, deterministic)
+}
+func (m *SysfsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SysfsRequest.Merge(m, src)
+}
+func (m *SysfsRequest) XXX_Size() int {
+	return xxx_messageInfo_SysfsRequest.Size(m)
+}
+func (m *SysfsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SysfsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SysfsRequest proto.InternalMessageInfo
+
+func (m *SysfsRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *SysfsRequest) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*CmdRequest)(nil), "v1api.CmdRequest")
+	proto.RegisterType((*CmdResponse)(nil), "v1api.CmdResponse")
+	proto.RegisterType((*MountRequest)(nil), "v1api.MountRequest")
+	proto.RegisterType((*PathRequest)(nil), "v1api.PathRequest")
+	proto.RegisterType((*MkFSRequest)(nil), "v1api.MkFSRequest")
+	proto.RegisterType((*SysfsRequest)(nil), "v1api.SysfsRequest")
+}
+
+func init() {
+	proto.RegisterFile("privhelpersvc.proto", fileDescriptor_139e62c0e6da31ea)
+}
+
+var fileDescriptor_139e62c0e6da31ea = []byte{
+	// 371 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0x4f, 0xef, 0xd2, 0x40,
+	0x10, 0x15, 0x4b, 0x4b, 0x18, 0x38, 0xe8, 0x62, 0xb4, 0xf1, 0x84, 0x3d, 0x71, 0x22, 0x02, 0x07,
+	0x3d, 0x8b, 0x21, 0x5e, 0x9a, 0x90, 0x56, 0x43, 0xe2, 0xc5, 0x54, 0x3a, 0xc0, 0x06, 0xda, 0x5d,
+	0x67, 0xb7, 0x4d, 0xf8, 0x5c, 0x7e, 0xc1, 0x5f, 0xba, 0xfd, 0x93, 0x3d, 0x00, 0x09, 0xb7, 0x79,
+	0xd3, 0x79, 0xf3, 0xa6, 0xef, 0x65, 0x61, 0x22, 0x89, 0x97, 0x27, 0xbc, 0x48, 0x24, 0x55, 0xee,
+	0xe7, 0x92, 0x84, 0x16, 0xcc, 0x2d, 0x17, 0x89, 0xe4, 0xc1, 0x14, 0x60, 0x9d, 0xa5, 0x11, 0xfe,
+	0x2b, 0x50, 0x69, 0xc6, 0xa0, 0x9f, 0xd0, 0x51, 0xf9, 0xbd, 0xa9, 0x33, 0x1b, 0x46, 0xa6, 0x0e,
+	0x62, 0x18, 0x99, 0x09, 0x25, 0x45, 0xae, 0x90, 0xbd, 0x07, 0x4f, 0xe9, 0x54, 0x14, 0xda, 0xef,
+	0x4d, 0x7b, 0xb3, 0x61, 0xd4, 0xa0, 0xa6, 0x8f, 0x44, 0xfe, 0xeb, 0xae, 0x8f, 0x44, 0xec, 0x1d,
+	0xb8, 0x48, 0x24, 0xc8, 0x77, 0x4c, 0xbb, 0x06, 0x41, 0x04, 0xe3, 0x50, 0x14, 0xb9, 0x6e, 0x85,
+	0x2b, 0xb6, 0x28, 0x68, 0x8f, 0xdd, 0x56, 0x83, 0xaa, 0xbe, 0x4e, 0xe8, 0x88, 0xba, 0xdd, 0x5a,
+	0xa3, 0xea, 0x50, 0x21, 0xb5, 0xf2, 0x9d, 0xfa, 0xd0, 0xaa, 0x0e, 0x3e, 0xc1, 0x68, 0x9b, 0xe8,
+	0x93, 0xf5, 0x2f, 0x32, 0xd1, 0xa7, 0x66, 0xa1, 0xa9, 0x83, 0x08, 0x46, 0xe1, 0x79, 0x13, 0xb7,
+	0x23, 0x1f, 0x60, 0x70, 0x50, 0x7f, 0xf4, 0x55, 0x76, 0xb2, 0x07, 0xf5, 0xf3, 0x2a, 0x8d, 0x6c,
+	0x8a, 0x25, 0xdf, 0x63, 0x2b, 0x5b, 0xa3, 0x9b, 0xb2, 0x5f, 0x61, 0x1c, 0x5f, 0xd5, 0x41, 0x3d,
+	0xd0, 0xad, 0x4c, 0x28, 0x93, 0x4b, 0xd1, 0xae, 0xab, 0xc1, 0xf2, 0xbf, 0x03, 0x6f, 0xb6, 0xc4,
+	0x4b, 0x7e, 0xc1, 0x23, 0xa6, 0x3f, 0x4c, 0x40, 0x6c, 0x09, 0xae, 0x71, 0x86, 0x4d, 0xe6, 0x26,
+	0xa1, 0xb9, 0xed, 0xd3, 0x47, 0xd6, 0x34, 0xad, 0x44, 0x82, 0x57, 0x6c, 0x05, 0x83, 0x5f, 0x79,
+	0x66, 0x58, 0xed, 0x80, 0xe5, 0xc4, 0x1d, 0xd2, 0x02, 0xdc, 0xf0, 0xfc, 0x9d, 0xd3, 0x73, 0x94,
+	0x28, 0x7b, 0x8e, 0xb2, 0x04, 0x6f, 0xc7, 0x25, 0x6e, 0xe2, 0x27, 0x38, 0x9f, 0xa1, 0x1f, 0x9e,
+	0x2d, 0x86, 0x15, 0xd9, 0xdd, 0xc3, 0xbc, 0xa8, 0xc8, 0xd7, 0x59, 0xca, 0xde, 0xda, 0xdf, 0x1f,
+	0x51, 0xbe, 0x00, 0xec, 0x88, 0x6b, 0x34, 0xd9, 0x75, 0x66, 0xdb, 0x49, 0xde, 0x26, 0x7e, 0x1b,
+	0xfc, 0xae, 0x9f, 0xce, 0x5f, 0xcf, 0x3c, 0xa4, 0xd5, 0xcb, 0x00, 0xb7, 0x00, 0xe0, 0xfc, 0x5f,
+	0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PrivilegedHelperClient is the client API for PrivilegedHelper service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PrivilegedHelperClient interface {
+	// Mount mounts source (device, directory or pseudo file system) to the target directory
+	Mount(ctx context.Context, in *MountRequest, opts ...grpc.CallOption) (*CmdResponse, error)
+	// Unmount unmounts the directory
+	Unmount(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*CmdResponse, error)
+	// MkDir creates directory with its parents
+	MkDir(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*CmdResponse, error)
+	// RmDir removes directory recursively
+	RmDir(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*CmdResponse, error)
+	// WipeFS erases file system signatures of the device
+	WipeFS(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*CmdResponse, error)
+	// MkFS creates file system on the device
+	MkFS(ctx context.Context, in *MkFSRequest, opts ...grpc.CallOption) (*CmdResponse, error)
+	// RunCmd runs device management util from the allowlist (partitioning, LVM, integrity, file system check)
+	RunCmd(ctx context.Context, in *CmdRequest, opts ...grpc.CallOption) (*CmdResponse, error)
+	// WriteSysfs writes attribute of block device request queue (I/O scheduler, nr_requests, read_ahead_kb),
+	// sysfs is read-only in not privileged containers
+	WriteSysfs(ctx context.Context, in *SysfsRequest, opts ...grpc.CallOption) (*CmdResponse, error)
+}
+
+type privilegedHelperClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPrivilegedHelperClient(cc grpc.ClientConnInterface) PrivilegedHelperClient {
+	return &privilegedHelperClient{cc}
+}
+
+func (c *privilegedHelperClient) Mount(ctx context.Context, in *MountRequest, opts ...grpc.CallOption) (*CmdResponse, error) {
+	out := new(CmdResponse)
+	err := c.cc.Invoke(ctx, "/v1api.PrivilegedHelper/Mount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privilegedHelperClient) Unmount(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*CmdResponse, error) {
+	out := new(CmdResponse)
+	err := c.cc.Invoke(ctx, "/v1api.PrivilegedHelper/Unmount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privilegedHelperClient) MkDir(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*CmdResponse, error) {
+	out := new(CmdResponse)
+	err := c.cc.Invoke(ctx, "/v1api.PrivilegedHelper/MkDir", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privilegedHelperClient) RmDir(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*CmdResponse, error) {
+	out := new(CmdResponse)
+	err := c.cc.Invoke(ctx, "/v1api.PrivilegedHelper/RmDir", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privilegedHelperClient) WipeFS(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*CmdResponse, error) {
+	out := new(CmdResponse)
+	err := c.cc.Invoke(ctx, "/v1api.PrivilegedHelper/WipeFS", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privilegedHelperClient) MkFS(ctx context.Context, in *MkFSRequest, opts ...grpc.CallOption) (*CmdResponse, error) {
+	out := new(CmdResponse)
+	err := c.cc.Invoke(ctx, "/v1api.PrivilegedHelper/MkFS", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privilegedHelperClient) RunCmd(ctx context.Context, in *CmdRequest, opts ...grpc.CallOption) (*CmdResponse, error) {
+	out := new(CmdResponse)
+	err := c.cc.Invoke(ctx, "/v1api.PrivilegedHelper/RunCmd", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privilegedHelperClient) WriteSysfs(ctx context.Context, in *SysfsRequest, opts ...grpc.CallOption) (*CmdResponse, error) {
+	out := new(CmdResponse)
+	err := c.cc.Invoke(ctx, "/v1api.PrivilegedHelper/WriteSysfs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PrivilegedHelperServer is the server API for PrivilegedHelper service.
+type PrivilegedHelperServer interface {
+	// Mount mounts source (device, directory or pseudo file system) to the target directory
+	Mount(context.Context, *MountRequest) (*CmdResponse, error)
+	// Unmount unmounts the directory
+	Unmount(context.Context, *PathRequest) (*CmdResponse, error)
+	// MkDir creates directory with its parents
+	MkDir(context.Context, *PathRequest) (*CmdResponse, error)
+	// RmDir removes directory recursively
+	RmDir(context.Context, *PathRequest) (*CmdResponse, error)
+	// WipeFS erases file system signatures of the device
+	WipeFS(context.Context, *PathRequest) (*CmdResponse, error)
+	// MkFS creates file system on the device
+	MkFS(context.Context, *MkFSRequest) (*CmdResponse, error)
+	// RunCmd runs device management util from the allowlist (partitioning, LVM, integrity, file system check)
+	RunCmd(context.Context, *CmdRequest) (*CmdResponse, error)
+	// WriteSysfs writes attribute of block device request queue (I/O scheduler, nr_requests, read_ahead_kb),
+	// sysfs is read-only in not privileged containers
+	WriteSysfs(context.Context, *SysfsRequest) (*CmdResponse, error)
+}
+
+// UnimplementedPrivilegedHelperServer can be embedded to have forward compatible implementations.
+type UnimplementedPrivilegedHelperServer struct {
+}
+
+func (*UnimplementedPrivilegedHelperServer) Mount(ctx context.Context, req *MountRequest) (*CmdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Mount not implemented")
+}
+func (*UnimplementedPrivilegedHelperServer) Unmount(ctx context.Context, req *PathRequest) (*CmdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unmount not implemented")
+}
+func (*UnimplementedPrivilegedHelperServer) MkDir(ctx context.Context, req *PathRequest) (*CmdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MkDir not implemented")
+}
+func (*UnimplementedPrivilegedHelperServer) RmDir(ctx context.Context, req *PathRequest) (*CmdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RmDir not implemented")
+}
+func (*UnimplementedPrivilegedHelperServer) WipeFS(ctx context.Context, req *PathRequest) (*CmdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WipeFS not implemented")
+}
+func (*UnimplementedPrivilegedHelperServer) MkFS(ctx context.Context, req *MkFSRequest) (*CmdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MkFS not implemented")
+}
+func (*UnimplementedPrivilegedHelperServer) RunCmd(ctx context.Context, req *CmdRequest) (*CmdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunCmd not implemented")
+}
+func (*UnimplementedPrivilegedHelperServer) WriteSysfs(ctx context.Context, req *SysfsRequest) (*CmdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteSysfs not implemented")
+}
+
+func RegisterPrivilegedHelperServer(s *grpc.Server, srv PrivilegedHelperServer) {
+	s.RegisterService(&_PrivilegedHelper_serviceDesc, srv)
+}
+
+func _PrivilegedHelper_Mount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivilegedHelperServer).Mount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1api.PrivilegedHelper/Mount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivilegedHelperServer).Mount(ctx, req.(*MountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivilegedHelper_Unmount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivilegedHelperServer).Unmount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1api.PrivilegedHelper/Unmount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivilegedHelperServer).Unmount(ctx, req.(*PathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivilegedHelper_MkDir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivilegedHelperServer).MkDir(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1api.PrivilegedHelper/MkDir",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivilegedHelperServer).MkDir(ctx, req.(*PathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivilegedHelper_RmDir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivilegedHelperServer).RmDir(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1api.PrivilegedHelper/RmDir",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivilegedHelperServer).RmDir(ctx, req.(*PathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivilegedHelper_WipeFS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivilegedHelperServer).WipeFS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1api.PrivilegedHelper/WipeFS",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivilegedHelperServer).WipeFS(ctx, req.(*PathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivilegedHelper_MkFS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MkFSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivilegedHelperServer).MkFS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1api.PrivilegedHelper/MkFS",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivilegedHelperServer).MkFS(ctx, req.(*MkFSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivilegedHelper_RunCmd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CmdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivilegedHelperServer).RunCmd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1api.PrivilegedHelper/RunCmd",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivilegedHelperServer).RunCmd(ctx, req.(*CmdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivilegedHelper_WriteSysfs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SysfsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivilegedHelperServer).WriteSysfs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1api.PrivilegedHelper/WriteSysfs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivilegedHelperServer).WriteSysfs(ctx, req.(*SysfsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PrivilegedHelper_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v1api.PrivilegedHelper",
+	HandlerType: (*PrivilegedHelperServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Mount",
+			Handler:    _PrivilegedHelper_Mount_Handler,
+		},
+		{
+			MethodName: "Unmount",
+			Handler:    _PrivilegedHelper_Unmount_Handler,
+		},
+		{
+			MethodName: "MkDir",
+			Handler:    _PrivilegedHelper_MkDir_Handler,
+		},
+		{
+			MethodName: "RmDir",
+			Handler:    _PrivilegedHelper_RmDir_Handler,
+		},
+		{
+			MethodName: "WipeFS",
+			Handler:    _PrivilegedHelper_WipeFS_Handler,
+		},
+		{
+			MethodName: "MkFS",
+			Handler:    _PrivilegedHelper_MkFS_Handler,
+		},
+		{
+			MethodName: "RunCmd",
+			Handler:    _PrivilegedHelper_RunCmd_Handler,
+		},
+		{
+			MethodName: "WriteSysfs",
+			Handler:    _PrivilegedHelper_WriteSysfs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "privhelpersvc.proto",
+}
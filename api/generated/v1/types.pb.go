@@ -33,14 +33,24 @@ type Drive struct {
 	Usage  string `protobuf:"bytes,9,opt,name=Usage,proto3" json:"Usage,omitempty"`
 	NodeId string `protobuf:"bytes,10,opt,name=NodeId,proto3" json:"NodeId,omitempty"`
 	// path to the device. may not be set by drivemgr.
-	Path                 string   `protobuf:"bytes,11,opt,name=Path,proto3" json:"Path,omitempty"`
-	Enclosure            string   `protobuf:"bytes,12,opt,name=Enclosure,proto3" json:"Enclosure,omitempty"`
-	Slot                 string   `protobuf:"bytes,13,opt,name=Slot,proto3" json:"Slot,omitempty"`
-	Bay                  string   `protobuf:"bytes,14,opt,name=Bay,proto3" json:"Bay,omitempty"`
-	Firmware             string   `protobuf:"bytes,15,opt,name=Firmware,proto3" json:"Firmware,omitempty"`
-	Endurance            int64    `protobuf:"varint,16,opt,name=Endurance,proto3" json:"Endurance,omitempty"`
-	LEDState             string   `protobuf:"bytes,17,opt,name=LEDState,proto3" json:"LEDState,omitempty"`
-	IsSystem             bool     `protobuf:"varint,18,opt,name=IsSystem,proto3" json:"IsSystem,omitempty"`
+	Path      string `protobuf:"bytes,11,opt,name=Path,proto3" json:"Path,omitempty"`
+	Enclosure string `protobuf:"bytes,12,opt,name=Enclosure,proto3" json:"Enclosure,omitempty"`
+	Slot      string `protobuf:"bytes,13,opt,name=Slot,proto3" json:"Slot,omitempty"`
+	Bay       string `protobuf:"bytes,14,opt,name=Bay,proto3" json:"Bay,omitempty"`
+	Firmware  string `protobuf:"bytes,15,opt,name=Firmware,proto3" json:"Firmware,omitempty"`
+	Endurance int64  `protobuf:"varint,16,opt,name=Endurance,proto3" json:"Endurance,omitempty"`
+	LEDState  string `protobuf:"bytes,17,opt,name=LEDState,proto3" json:"LEDState,omitempty"`
+	IsSystem  bool   `protobuf:"varint,18,opt,name=IsSystem,proto3" json:"IsSystem,omitempty"`
+	// path to the SES device of the enclosure (backplane) which holds drive
+	Backplane string `protobuf:"bytes,19,opt,name=Backplane,proto3" json:"Backplane,omitempty"`
+	// NUMA node of the drive's PCIe/HBA path, empty if platform doesn't report it
+	NUMANode string `protobuf:"bytes,20,opt,name=NUMANode,proto3" json:"NUMANode,omitempty"`
+	// World Wide Name of the drive, empty if drive doesn't report it
+	WWN string `protobuf:"bytes,21,opt,name=WWN,proto3" json:"WWN,omitempty"`
+	// temperature of the drive in Celsius, 0 if drive doesn't report it
+	Temperature int32 `protobuf:"varint,22,opt,name=Temperature,proto3" json:"Temperature,omitempty"`
+	// IPMI SEL records of the drive bay or backplane of the drive, empty if SEL correlation is disabled
+	SELEvents            []string `protobuf:"bytes,23,rep,name=SELEvents,proto3" json:"SELEvents,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -197,6 +207,41 @@ func (m *Drive) GetIsSystem() bool {
 	return false
 }
 
+func (m *Drive) GetBackplane() string {
+	if m != nil {
+		return m.Backplane
+	}
+	return ""
+}
+
+func (m *Drive) GetNUMANode() string {
+	if m != nil {
+		return m.NUMANode
+	}
+	return ""
+}
+
+func (m *Drive) GetWWN() string {
+	if m != nil {
+		return m.WWN
+	}
+	return ""
+}
+
+func (m *Drive) GetTemperature() int32 {
+	if m != nil {
+		return m.Temperature
+	}
+	return 0
+}
+
+func (m *Drive) GetSELEvents() []string {
+	if m != nil {
+		return m.SELEvents
+	}
+	return nil
+}
+
 type Volume struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`
 	Location             string   `protobuf:"bytes,2,opt,name=Location,proto3" json:"Location,omitempty"`
@@ -212,6 +257,13 @@ type Volume struct {
 	CSIStatus            string   `protobuf:"bytes,12,opt,name=CSIStatus,proto3" json:"CSIStatus,omitempty"`
 	Usage                string   `protobuf:"bytes,13,opt,name=Usage,proto3" json:"Usage,omitempty"`
 	Ephemeral            bool     `protobuf:"varint,14,opt,name=Ephemeral,proto3" json:"Ephemeral,omitempty"`
+	Scratch              bool     `protobuf:"varint,15,opt,name=Scratch,proto3" json:"Scratch,omitempty"`
+	ImageSource          string   `protobuf:"bytes,16,opt,name=ImageSource,proto3" json:"ImageSource,omitempty"`
+	ImageChecksum        string   `protobuf:"bytes,17,opt,name=ImageChecksum,proto3" json:"ImageChecksum,omitempty"`
+	Integrity            string   `protobuf:"bytes,18,opt,name=Integrity,proto3" json:"Integrity,omitempty"`
+	ImageSecret          string   `protobuf:"bytes,19,opt,name=ImageSecret,proto3" json:"ImageSecret,omitempty"`
+	MkFSOptions          string   `protobuf:"bytes,20,opt,name=MkFSOptions,proto3" json:"MkFSOptions,omitempty"`
+	FormatProfile        string   `protobuf:"bytes,21,opt,name=FormatProfile,proto3" json:"FormatProfile,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -340,6 +392,55 @@ func (m *Volume) GetEphemeral() bool {
 	return false
 }
 
+func (m *Volume) GetScratch() bool {
+	if m != nil {
+		return m.Scratch
+	}
+	return false
+}
+
+func (m *Volume) GetImageSource() string {
+	if m != nil {
+		return m.ImageSource
+	}
+	return ""
+}
+
+func (m *Volume) GetImageChecksum() string {
+	if m != nil {
+		return m.ImageChecksum
+	}
+	return ""
+}
+
+func (m *Volume) GetIntegrity() string {
+	if m != nil {
+		return m.Integrity
+	}
+	return ""
+}
+
+func (m *Volume) GetImageSecret() string {
+	if m != nil {
+		return m.ImageSecret
+	}
+	return ""
+}
+
+func (m *Volume) GetMkFSOptions() string {
+	if m != nil {
+		return m.MkFSOptions
+	}
+	return ""
+}
+
+func (m *Volume) GetFormatProfile() string {
+	if m != nil {
+		return m.FormatProfile
+	}
+	return ""
+}
+
 type AvailableCapacity struct {
 	Location             string   `protobuf:"bytes,1,opt,name=Location,proto3" json:"Location,omitempty"`
 	NodeId               string   `protobuf:"bytes,2,opt,name=NodeId,proto3" json:"NodeId,omitempty"`
@@ -601,6 +702,113 @@ func (m *Node) GetAddresses() map[string]string {
 	return nil
 }
 
+type StorageQuota struct {
+	// limit of total size of volumes in bytes, 0 means unlimited
+	Bytes int64 `protobuf:"varint,1,opt,name=Bytes,proto3" json:"Bytes,omitempty"`
+	// limit of amount of volumes, 0 means unlimited
+	Volumes              int64    `protobuf:"varint,2,opt,name=Volumes,proto3" json:"Volumes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StorageQuota) Reset()         { *m = StorageQuota{} }
+func (m *StorageQuota) String() string { return proto.CompactTextString(m) }
+func (*StorageQuota) ProtoMessage()    {}
+func (*StorageQuota) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{6}
+}
+
+func (m *StorageQuota) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageQuota.Unmarshal(m, b)
+}
+func (m *StorageQuota) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageQuota.Marshal(b, m, deterministic)
+}
+func (m *StorageQuota) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageQuota.Merge(m, src)
+}
+func (m *StorageQuota) XXX_Size() int {
+	return xxx_messageInfo_StorageQuota.Size(m)
+}
+func (m *StorageQuota) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageQuota.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageQuota proto.InternalMessageInfo
+
+func (m *StorageQuota) GetBytes() int64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *StorageQuota) GetVolumes() int64 {
+	if m != nil {
+		return m.Volumes
+	}
+	return 0
+}
+
+type LVGPolicy struct {
+	// LVG storage class (for example SSDLVG) of LogicalVolumeGroups, drives of its type are combined on each node
+	StorageClass string `protobuf:"bytes,1,opt,name=StorageClass,proto3" json:"StorageClass,omitempty"`
+	// drives which size is equal or bigger aren't combined, 0 means any size
+	MaxDriveSize int64 `protobuf:"varint,2,opt,name=MaxDriveSize,proto3" json:"MaxDriveSize,omitempty"`
+	// label selector of drives which are combined, empty selector matches all drives
+	DriveSelector        string   `protobuf:"bytes,3,opt,name=DriveSelector,proto3" json:"DriveSelector,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LVGPolicy) Reset()         { *m = LVGPolicy{} }
+func (m *LVGPolicy) String() string { return proto.CompactTextString(m) }
+func (*LVGPolicy) ProtoMessage()    {}
+func (*LVGPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{7}
+}
+
+func (m *LVGPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LVGPolicy.Unmarshal(m, b)
+}
+func (m *LVGPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LVGPolicy.Marshal(b, m, deterministic)
+}
+func (m *LVGPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LVGPolicy.Merge(m, src)
+}
+func (m *LVGPolicy) XXX_Size() int {
+	return xxx_messageInfo_LVGPolicy.Size(m)
+}
+func (m *LVGPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_LVGPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LVGPolicy proto.InternalMessageInfo
+
+func (m *LVGPolicy) GetStorageClass() string {
+	if m != nil {
+		return m.StorageClass
+	}
+	return ""
+}
+
+func (m *LVGPolicy) GetMaxDriveSize() int64 {
+	if m != nil {
+		return m.MaxDriveSize
+	}
+	return 0
+}
+
+func (m *LVGPolicy) GetDriveSelector() string {
+	if m != nil {
+		return m.DriveSelector
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Drive)(nil), "v1api.Drive")
 	proto.RegisterType((*Volume)(nil), "v1api.Volume")
@@ -609,6 +817,8 @@ func init() {
 	proto.RegisterType((*LogicalVolumeGroup)(nil), "v1api.LogicalVolumeGroup")
 	proto.RegisterType((*Node)(nil), "v1api.Node")
 	proto.RegisterMapType((map[string]string)(nil), "v1api.Node.AddressesEntry")
+	proto.RegisterType((*StorageQuota)(nil), "v1api.StorageQuota")
+	proto.RegisterType((*LVGPolicy)(nil), "v1api.LVGPolicy")
 }
 
 func init() {
@@ -616,47 +826,62 @@ func init() {
 }
 
 var fileDescriptor_d938547f84707355 = []byte{
-	// 667 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0x95, 0xed, 0xc4, 0x4d, 0xa6, 0x69, 0x69, 0x57, 0xa8, 0x5a, 0x55, 0x15, 0x8a, 0x7c, 0xca,
-	0x01, 0x45, 0x02, 0x2e, 0x15, 0xe2, 0xd2, 0x36, 0x05, 0x2c, 0x95, 0xb6, 0x72, 0x68, 0x0f, 0xdc,
-	0xb6, 0xf1, 0xd0, 0x5a, 0x38, 0xb1, 0xb5, 0x6b, 0xa7, 0x32, 0x17, 0xf8, 0x05, 0x1c, 0xf8, 0x2b,
-	0xdc, 0xf9, 0x6d, 0x68, 0x76, 0xfd, 0x49, 0x73, 0x9b, 0x79, 0xb3, 0xb3, 0xb3, 0x7e, 0xef, 0x79,
-	0x60, 0x3b, 0x2b, 0x52, 0x54, 0xd3, 0x54, 0x26, 0x59, 0xc2, 0xfa, 0xeb, 0x57, 0x22, 0x8d, 0xbc,
-	0x3f, 0x0e, 0xf4, 0x67, 0x32, 0x5a, 0x23, 0x63, 0xd0, 0xbb, 0xb9, 0xf1, 0x67, 0xdc, 0x1a, 0x5b,
-	0x93, 0x61, 0xa0, 0x63, 0xb6, 0x07, 0xce, 0xad, 0x3f, 0xe3, 0xb6, 0x86, 0x28, 0x24, 0xe4, 0xda,
-	0x9f, 0x71, 0xc7, 0x20, 0xd7, 0xfe, 0x8c, 0x79, 0x30, 0x9a, 0xa3, 0x8c, 0x44, 0x7c, 0x99, 0x2f,
-	0xef, 0x50, 0xf2, 0x9e, 0x2e, 0x75, 0x30, 0x76, 0x00, 0xee, 0x47, 0x14, 0x71, 0xf6, 0xc0, 0xfb,
-	0xba, 0x5a, 0x66, 0x34, 0xf3, 0x73, 0x91, 0x22, 0x77, 0xcd, 0x4c, 0x8a, 0x09, 0x9b, 0x47, 0xdf,
-	0x91, 0x6f, 0x8d, 0xad, 0x89, 0x13, 0xe8, 0x98, 0xfa, 0xe7, 0x99, 0xc8, 0x72, 0xc5, 0x07, 0xa6,
-	0xdf, 0x64, 0xec, 0x39, 0xf4, 0x6f, 0x94, 0xb8, 0x47, 0x3e, 0xd4, 0xb0, 0x49, 0xe8, 0xf4, 0x65,
-	0x12, 0xa2, 0x1f, 0x72, 0x30, 0xa7, 0x4d, 0x46, 0x37, 0x5f, 0x8b, 0xec, 0x81, 0x6f, 0x9b, 0x69,
-	0x14, 0xb3, 0x23, 0x18, 0x9e, 0xaf, 0x16, 0x71, 0xa2, 0x72, 0x89, 0x7c, 0xa4, 0x0b, 0x0d, 0xa0,
-	0xdf, 0x12, 0x27, 0x19, 0xdf, 0x31, 0x1d, 0x14, 0x13, 0x03, 0xa7, 0xa2, 0xe0, 0xbb, 0x86, 0x81,
-	0x53, 0x51, 0xb0, 0x43, 0x18, 0xbc, 0x8f, 0xe4, 0xf2, 0x51, 0x48, 0xe4, 0xcf, 0x34, 0x5c, 0xe7,
-	0xe6, 0xfe, 0x30, 0x97, 0x62, 0xb5, 0x40, 0xbe, 0xa7, 0x3f, 0xa9, 0x01, 0xa8, 0xf3, 0xe2, 0x7c,
-	0x46, 0x1f, 0x83, 0x7c, 0xdf, 0x74, 0x56, 0x39, 0xd5, 0x7c, 0x35, 0x2f, 0x54, 0x86, 0x4b, 0xce,
-	0xc6, 0xd6, 0x64, 0x10, 0xd4, 0xb9, 0xf7, 0xd3, 0x01, 0xf7, 0x36, 0x89, 0xf3, 0x25, 0xb2, 0x5d,
-	0xb0, 0xfd, 0xb0, 0x14, 0xcd, 0xf6, 0x43, 0x7d, 0x65, 0xb2, 0x10, 0x59, 0x94, 0xac, 0x4a, 0xdd,
-	0xea, 0x9c, 0xa4, 0xaa, 0x62, 0x4d, 0xbb, 0x51, 0xb1, 0x83, 0x69, 0x39, 0xb3, 0x44, 0x8a, 0x7b,
-	0x3c, 0x8b, 0x85, 0x52, 0xb5, 0x9c, 0x2d, 0xac, 0x45, 0x70, 0xbf, 0x43, 0xf0, 0x01, 0xb8, 0x57,
-	0x8f, 0x2b, 0x94, 0x8a, 0xbb, 0x63, 0x87, 0x70, 0x93, 0x6d, 0x94, 0x94, 0x41, 0xef, 0x53, 0x12,
-	0x62, 0x29, 0xa8, 0x8e, 0x6b, 0x3b, 0x0c, 0x5b, 0x76, 0x68, 0xac, 0x03, 0x1d, 0xeb, 0xbc, 0x84,
-	0xfd, 0xab, 0x14, 0xa5, 0x7e, 0xb8, 0x88, 0x4b, 0x77, 0x18, 0x65, 0x9f, 0x16, 0x48, 0x86, 0xb3,
-	0xb9, 0x5f, 0x9e, 0x2a, 0x65, 0xae, 0x81, 0xc6, 0x46, 0x3b, 0x6d, 0x1b, 0x91, 0x74, 0xe9, 0x03,
-	0x2e, 0x51, 0x8a, 0x58, 0xcb, 0x3d, 0x08, 0x1a, 0xc0, 0xfb, 0x01, 0xfb, 0x27, 0x6b, 0x11, 0xc5,
-	0xe2, 0x2e, 0xc6, 0x33, 0x91, 0x8a, 0x45, 0x94, 0x15, 0x1d, 0xf2, 0xad, 0xff, 0xc8, 0x6f, 0x48,
-	0xb3, 0x3b, 0xa4, 0x79, 0x30, 0x52, 0x6d, 0xc2, 0x4b, 0x51, 0xda, 0x58, 0x4d, 0x60, 0xaf, 0x21,
-	0xd0, 0xfb, 0x65, 0xc1, 0xd1, 0x93, 0x17, 0x04, 0xa8, 0x50, 0xae, 0xcd, 0x40, 0x06, 0xbd, 0x4b,
-	0xb1, 0xc4, 0xea, 0x87, 0xa6, 0xf8, 0x89, 0xba, 0xf6, 0x06, 0x75, 0xab, 0x61, 0x4e, 0x4b, 0x2d,
-	0x0f, 0x46, 0xad, 0xab, 0xc9, 0x15, 0xa4, 0x6f, 0x07, 0xf3, 0xfe, 0x5a, 0xc0, 0x2e, 0x92, 0xfb,
-	0x68, 0x21, 0x62, 0xe3, 0xcd, 0x0f, 0x32, 0xc9, 0xd3, 0x8d, 0xcf, 0x20, 0x8c, 0xc4, 0xb7, 0x4b,
-	0x8c, 0xc4, 0x3f, 0x82, 0x61, 0xc5, 0x15, 0x91, 0x40, 0xf7, 0x37, 0xc0, 0x26, 0x06, 0xd8, 0x0b,
-	0x00, 0x33, 0x28, 0xc0, 0xaf, 0x8a, 0xf7, 0x75, 0x4b, 0x0b, 0x69, 0x6d, 0x0d, 0xb7, 0xb3, 0x35,
-	0x1a, 0x4b, 0x6d, 0xb5, 0x2d, 0xe5, 0xfd, 0xb6, 0xcc, 0xb3, 0x36, 0xae, 0xc2, 0x63, 0x18, 0x9e,
-	0x84, 0xa1, 0x44, 0xa5, 0x90, 0x68, 0x73, 0x26, 0xdb, 0xaf, 0x0f, 0xa7, 0x7a, 0x87, 0x4e, 0xa9,
-	0x67, 0x5a, 0x17, 0xcf, 0x57, 0x99, 0x2c, 0x82, 0xe6, 0xf0, 0xe1, 0x3b, 0xd8, 0xed, 0x16, 0x69,
-	0x85, 0x7c, 0xc3, 0xa2, 0xbc, 0x9e, 0x42, 0x72, 0xe0, 0x5a, 0xc4, 0x79, 0xc5, 0x88, 0x49, 0xde,
-	0xda, 0xc7, 0xd6, 0xe9, 0xd6, 0x17, 0xb3, 0xa9, 0xef, 0x5c, 0xbd, 0xb7, 0xdf, 0xfc, 0x0b, 0x00,
-	0x00, 0xff, 0xff, 0xde, 0x5a, 0xde, 0xda, 0xc6, 0x05, 0x00, 0x00,
+	// 905 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x95, 0xcd, 0x6e, 0xe3, 0x36,
+	0x10, 0xc7, 0x21, 0x7f, 0x25, 0x66, 0x9c, 0x74, 0xc3, 0x6e, 0x53, 0x22, 0x08, 0x0a, 0x43, 0xe8,
+	0xc1, 0x87, 0x22, 0x40, 0xdb, 0xcb, 0xa2, 0x28, 0x0a, 0xc4, 0xb1, 0xb3, 0x15, 0x90, 0x38, 0xae,
+	0xbc, 0x49, 0x80, 0xde, 0x18, 0x79, 0xd6, 0x16, 0x22, 0x89, 0x02, 0x49, 0x79, 0xab, 0x5e, 0xfa,
+	0x06, 0x3d, 0xf4, 0x81, 0xda, 0xf7, 0xe9, 0x53, 0x14, 0x43, 0xea, 0xb3, 0xf1, 0x6d, 0xe6, 0x4f,
+	0x0e, 0x67, 0x38, 0xfc, 0x91, 0x24, 0x47, 0x3a, 0x4f, 0x41, 0x5d, 0xa6, 0x52, 0x68, 0x41, 0xfb,
+	0xbb, 0x6f, 0x79, 0x1a, 0xba, 0xff, 0xf4, 0x48, 0x7f, 0x26, 0xc3, 0x1d, 0x50, 0x4a, 0x7a, 0x0f,
+	0x0f, 0xde, 0x8c, 0x39, 0x63, 0x67, 0x32, 0xf4, 0x8d, 0x4d, 0xdf, 0x90, 0xee, 0xa3, 0x37, 0x63,
+	0x1d, 0x23, 0x75, 0x1f, 0xad, 0xb2, 0xf4, 0x66, 0xac, 0x6b, 0x95, 0xa5, 0x37, 0xa3, 0x2e, 0x19,
+	0xad, 0x40, 0x86, 0x3c, 0x5a, 0x64, 0xf1, 0x33, 0x48, 0xd6, 0x33, 0x43, 0x2d, 0x8d, 0x9e, 0x91,
+	0xc1, 0xcf, 0xc0, 0x23, 0xbd, 0x65, 0x7d, 0x33, 0x5a, 0x78, 0x98, 0xf3, 0x43, 0x9e, 0x02, 0x1b,
+	0xd8, 0x9c, 0x68, 0xa3, 0xb6, 0x0a, 0x7f, 0x07, 0x76, 0x30, 0x76, 0x26, 0x5d, 0xdf, 0xd8, 0x18,
+	0xbf, 0xd2, 0x5c, 0x67, 0x8a, 0x1d, 0xda, 0x78, 0xeb, 0xd1, 0xb7, 0xa4, 0xff, 0xa0, 0xf8, 0x06,
+	0xd8, 0xd0, 0xc8, 0xd6, 0xc1, 0xd9, 0x0b, 0xb1, 0x06, 0x6f, 0xcd, 0x88, 0x9d, 0x6d, 0x3d, 0x5c,
+	0x79, 0xc9, 0xf5, 0x96, 0x1d, 0xd9, 0x6c, 0x68, 0xd3, 0x0b, 0x32, 0x9c, 0x27, 0x41, 0x24, 0x54,
+	0x26, 0x81, 0x8d, 0xcc, 0x40, 0x2d, 0x98, 0x5a, 0x22, 0xa1, 0xd9, 0xb1, 0x8d, 0x40, 0x1b, 0x3b,
+	0x30, 0xe5, 0x39, 0x3b, 0xb1, 0x1d, 0x98, 0xf2, 0x9c, 0x9e, 0x93, 0xc3, 0x9b, 0x50, 0xc6, 0x9f,
+	0xb8, 0x04, 0xf6, 0x99, 0x91, 0x2b, 0xdf, 0xae, 0xbf, 0xce, 0x24, 0x4f, 0x02, 0x60, 0x6f, 0xcc,
+	0x96, 0x6a, 0x01, 0x23, 0x6f, 0xe7, 0x33, 0xdc, 0x0c, 0xb0, 0x53, 0x1b, 0x59, 0xfa, 0x38, 0xe6,
+	0xa9, 0x55, 0xae, 0x34, 0xc4, 0x8c, 0x8e, 0x9d, 0xc9, 0xa1, 0x5f, 0xf9, 0xb8, 0xea, 0x94, 0x07,
+	0x2f, 0x69, 0xc4, 0x13, 0x60, 0x9f, 0xdb, 0xaa, 0x2b, 0x01, 0x23, 0x17, 0x0f, 0x77, 0x57, 0xb8,
+	0x6b, 0xf6, 0xd6, 0xae, 0x5a, 0xfa, 0x58, 0xfd, 0xd3, 0xd3, 0x82, 0x7d, 0x61, 0xab, 0x7f, 0x7a,
+	0x5a, 0xd0, 0x31, 0x39, 0xfa, 0x00, 0x71, 0x0a, 0x92, 0x6b, 0xec, 0xc1, 0xd9, 0xd8, 0x99, 0xf4,
+	0xfd, 0xa6, 0x84, 0xd9, 0x56, 0xf3, 0xdb, 0xf9, 0x0e, 0x12, 0xad, 0xd8, 0x97, 0xe3, 0x2e, 0x66,
+	0xab, 0x04, 0xf7, 0xdf, 0x1e, 0x19, 0x3c, 0x8a, 0x28, 0x8b, 0x81, 0x9e, 0x90, 0x8e, 0xb7, 0x2e,
+	0x00, 0xea, 0x78, 0x6b, 0xb3, 0x3d, 0x11, 0x70, 0x1d, 0x8a, 0xa4, 0x60, 0xa8, 0xf2, 0x11, 0x9b,
+	0xd2, 0x36, 0x08, 0x58, 0xa2, 0x5a, 0x9a, 0x41, 0x4b, 0x0b, 0xc9, 0x37, 0x70, 0x1d, 0x71, 0xa5,
+	0x2a, 0xb4, 0x1a, 0x5a, 0xe3, 0xb0, 0xfb, 0xad, 0xc3, 0x3e, 0x23, 0x83, 0xfb, 0x4f, 0x09, 0x48,
+	0xc5, 0x06, 0xa6, 0xe2, 0xc2, 0xdb, 0x8b, 0x17, 0x25, 0xbd, 0x3b, 0x6c, 0x96, 0x85, 0xcb, 0xd8,
+	0x15, 0x9a, 0xc3, 0x06, 0x9a, 0x35, 0xc6, 0xa4, 0x85, 0xf1, 0x37, 0xe4, 0xf4, 0xde, 0x74, 0x2b,
+	0x14, 0x09, 0x8f, 0x0a, 0x52, 0x2d, 0x65, 0xaf, 0x07, 0xb0, 0x9d, 0xd7, 0x2b, 0xaf, 0x98, 0x55,
+	0x20, 0x57, 0x09, 0x35, 0xd2, 0xc7, 0x4d, 0xa4, 0x11, 0xa3, 0x74, 0x0b, 0x31, 0x48, 0x1e, 0x19,
+	0xf4, 0x0e, 0xfd, 0x5a, 0xa0, 0x8c, 0x1c, 0xac, 0x02, 0xc9, 0x75, 0xb0, 0x35, 0xfc, 0x1d, 0xfa,
+	0xa5, 0x8b, 0x87, 0xeb, 0xc5, 0x7c, 0x03, 0x2b, 0x91, 0xc9, 0x02, 0xc0, 0xa1, 0xdf, 0x94, 0xe8,
+	0xd7, 0xe4, 0xd8, 0xb8, 0xd7, 0x5b, 0x08, 0x5e, 0x54, 0x16, 0x17, 0x1c, 0xb6, 0x45, 0xcc, 0xef,
+	0x25, 0x1a, 0x36, 0x32, 0xd4, 0xb9, 0xa1, 0x71, 0xe8, 0xd7, 0x42, 0x9d, 0x05, 0x02, 0x09, 0xba,
+	0x00, 0xb2, 0x29, 0xe1, 0x8c, 0xbb, 0x97, 0x9b, 0xd5, 0x7d, 0x8a, 0x9d, 0x50, 0x05, 0x95, 0x4d,
+	0x09, 0xeb, 0xb8, 0x11, 0x32, 0xe6, 0x7a, 0x29, 0xc5, 0xc7, 0x30, 0x82, 0x02, 0xd1, 0xb6, 0xe8,
+	0xfe, 0x41, 0x4e, 0xaf, 0x76, 0x3c, 0x8c, 0xf8, 0x73, 0x04, 0xd7, 0x3c, 0xe5, 0x01, 0xa6, 0x6f,
+	0x62, 0xe6, 0xfc, 0x0f, 0xb3, 0x1a, 0x8f, 0x4e, 0x0b, 0x0f, 0x97, 0x8c, 0x54, 0x13, 0xad, 0x02,
+	0xbf, 0xa6, 0x56, 0xa1, 0xd2, 0xab, 0x51, 0x71, 0xff, 0x74, 0xc8, 0xc5, 0xab, 0x0a, 0x7c, 0x50,
+	0x20, 0x77, 0x36, 0x21, 0x25, 0xbd, 0x05, 0x8f, 0xa1, 0x7c, 0x46, 0xd1, 0x7e, 0xc5, 0x71, 0x67,
+	0x0f, 0xc7, 0x65, 0xb2, 0x6e, 0x83, 0x4b, 0x97, 0x8c, 0x1a, 0x4b, 0x23, 0xff, 0x48, 0x72, 0x4b,
+	0x73, 0xff, 0x76, 0x08, 0xbd, 0x15, 0x9b, 0x30, 0xe0, 0x91, 0xbd, 0x85, 0xef, 0xa5, 0xc8, 0xd2,
+	0xbd, 0x65, 0xa0, 0x86, 0x98, 0x77, 0x0a, 0x0d, 0x31, 0xbf, 0x20, 0xc3, 0xb2, 0x57, 0xd8, 0x04,
+	0x73, 0xb7, 0x2b, 0x61, 0x5f, 0x07, 0xe8, 0x57, 0x84, 0xd8, 0x44, 0x3e, 0x7c, 0x54, 0xac, 0x6f,
+	0x42, 0x1a, 0x4a, 0xe3, 0xad, 0x1e, 0xb4, 0xde, 0xea, 0xfa, 0xf2, 0x1c, 0x34, 0x2f, 0x8f, 0xfb,
+	0x97, 0x63, 0xcb, 0xda, 0xfb, 0x01, 0xbd, 0x23, 0xc3, 0xab, 0xf5, 0x5a, 0x82, 0x52, 0x80, 0x6d,
+	0xeb, 0x4e, 0x8e, 0xbe, 0x3b, 0xbf, 0x34, 0x3f, 0xd7, 0x25, 0xc6, 0x5c, 0x56, 0x83, 0xf3, 0x44,
+	0xcb, 0xdc, 0xaf, 0x27, 0x9f, 0xff, 0x48, 0x4e, 0xda, 0x83, 0xf8, 0xf4, 0xbd, 0x40, 0x5e, 0x2c,
+	0x8f, 0x26, 0xde, 0xb5, 0x1d, 0x8f, 0xb2, 0xb2, 0x23, 0xd6, 0xf9, 0xa1, 0xf3, 0xce, 0x71, 0x7f,
+	0xaa, 0x4e, 0xec, 0x97, 0x4c, 0x68, 0x8e, 0x33, 0xa7, 0xb9, 0x06, 0x65, 0xa2, 0xbb, 0xbe, 0x75,
+	0xf0, 0xde, 0xd9, 0x8d, 0xdb, 0x23, 0xed, 0xfa, 0xa5, 0xeb, 0xe6, 0x64, 0x78, 0xfb, 0xf8, 0x7e,
+	0x29, 0xa2, 0x30, 0xc8, 0x5f, 0x1d, 0xbf, 0xb3, 0xe7, 0xf8, 0x5d, 0x32, 0xba, 0xe3, 0xbf, 0x99,
+	0x9f, 0xd8, 0x74, 0xdc, 0xae, 0xd7, 0xd2, 0xf0, 0x8a, 0x58, 0x07, 0x22, 0x08, 0xb4, 0x90, 0x05,
+	0xb4, 0x6d, 0x71, 0x7a, 0xf0, 0xab, 0xfd, 0xda, 0x9f, 0x07, 0xe6, 0xa3, 0xff, 0xfe, 0xbf, 0x01,
+	0x00, 0x9a, 0xb8, 0x62, 0x69, 0xf7, 0x07, 0x00, 0x00,
 }
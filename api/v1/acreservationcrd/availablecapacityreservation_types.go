@@ -25,6 +25,10 @@ import (
 // +kubebuilder:object:root=true
 
 // +kubebuilder:resource:scope=Cluster,shortName={acr,acrs}
+// +kubebuilder:printcolumn:name="STORAGE CLASS",type="string",JSONPath=".spec.StorageClass",description="StorageClass of AvailableCapacityReservation"
+// +kubebuilder:printcolumn:name="SIZE",type="integer",JSONPath=".spec.Size",description="Size of AvailableCapacityReservation in bytes"
+// +kubebuilder:printcolumn:name="RESERVATIONS",type="string",JSONPath=".spec.Reservations",description="List of reserved AvailableCapacity",priority=1
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // AvailableCapacityReservation is the Schema for the availablecapacitiereservations API
 type AvailableCapacityReservation struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -24,6 +24,11 @@ import (
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster,shortName={ac,acs}
+// +kubebuilder:printcolumn:name="SIZE",type="integer",JSONPath=".spec.Size",description="Size of AvailableCapacity in bytes"
+// +kubebuilder:printcolumn:name="STORAGE CLASS",type="string",JSONPath=".spec.storageClass",description="StorageClass of AvailableCapacity"
+// +kubebuilder:printcolumn:name="LOCATION",type="string",JSONPath=".spec.Location",description="Drive/LVG UUID used by AvailableCapacity"
+// +kubebuilder:printcolumn:name="NODE",type="string",JSONPath=".spec.NodeId",description="Node id of Available Capacity"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // AvailableCapacity is the Schema for the availablecapacities API
 type AvailableCapacity struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -23,6 +23,8 @@ const (
 	LVGKind                          = "LogicalVolumeGroup"
 	DriveKind                        = "Drive"
 	CSIBMNodeKind                    = "Node"
+	StorageQuotaKind                 = "StorageQuota"
+	LVGPolicyKind                    = "LVGPolicy"
 
 	Version = "v1"
 	CSICRsGroupVersion = "csi-baremetal.dell.com"
@@ -58,16 +60,55 @@ const (
 	DriveUsageFailed    = "FAILED"
 	DriveUsageRemoving  = "REMOVING"
 	DriveUsageRemoved   = "REMOVED"
+	// drive holds data which wasn't written by the driver, it isn't used until it is cleared by operator
+	DriveUsageNotClean = "NOT_CLEAN"
 
 	// Drive type
 	DriveTypeHDD  = "HDD"
 	DriveTypeSSD  = "SSD"
 	DriveTypeNVMe = "NVME"
+	// persistent memory namespace in fsdax mode
+	DriveTypePMEM = "PMEM"
+	// host-managed SMR drive or NVMe ZNS drive which requires sequential writes
+	DriveTypeZoned = "ZONED"
 
 	// Drive annotations
 	DriveAnnotationReplacement        = "replacement"
 	DriveAnnotationReplacementReady   = "ready"
 	DriveAnnotationVolumeStatusPrefix = "status"
+	// maintenance annotation should be set explicitly by user, firmware is flashed only for drive without volumes,
+	// update is postponed while drive has volumes
+	DriveAnnotationMaintenance               = "maintenance"
+	DriveAnnotationMaintenanceFirmwareUpdate = "firmware-update"
+	DriveAnnotationFirmwareImage             = "firmware-image"
+	DriveAnnotationFirmwareStatus            = "firmware-status"
+	DriveAnnotationFirmwareStatusUpdated     = "updated"
+	DriveAnnotationFirmwareStatusFailed      = "failed"
+	DriveAnnotationFirmwareStatusPostponed   = "postponed"
+	// NVMe namespaces are created only if none of the drives of the same NVMe controller has volumes or LVG
+	DriveAnnotationMaintenanceNVMeNamespaces   = "nvme-namespaces"
+	DriveAnnotationNVMeNamespaceCount          = "nvme-namespace-count"
+	DriveAnnotationNVMeNamespacesStatus        = "nvme-namespaces-status"
+	DriveAnnotationNVMeNamespacesStatusCreated = "created"
+	DriveAnnotationNVMeNamespacesStatusFailed  = "failed"
+	// hot spare annotation should be set explicitly by user, hot spare drive is excluded from allocation
+	// and is promoted automatically when another drive on the same node fails
+	DriveAnnotationHotSpare            = "hot-spare"
+	DriveAnnotationHotSpareEnabled     = "true"
+	DriveAnnotationHotSparePromotedFor = "hot-spare-promoted-for"
+	// evacuation moves data of the drive to other drives of its LogicalVolumeGroup and removes drive from it,
+	// evacuated drive isn't added to LogicalVolumeGroup by LVGPolicy until status annotation is removed
+	DriveAnnotationMaintenanceEvacuate    = "evacuate"
+	DriveAnnotationEvacuationStatus       = "evacuation-status"
+	DriveAnnotationEvacuationStatusDone   = "evacuated"
+	DriveAnnotationEvacuationStatusFailed = "failed"
+	// evacuation runs in background, status annotation holds this value until data of the drive is moved
+	DriveAnnotationEvacuationStatusInProgress = "evacuating"
+	// scratch partition annotation is set by node, it holds UUID of warm partition left after scratch volume deletion
+	DriveAnnotationScratchPartition = "scratch-partition"
+	// erase-data annotation is set by user to wipe NOT_CLEAN drive, value should be equal to drive serial number,
+	// annotation is removed by node once the drive is wiped
+	DriveAnnotationEraseData = "erase-data"
 
 	// Volume operational status
 	OperationalStatusOperative   = "OPERATIVE"
@@ -89,6 +130,38 @@ const (
 	VolumeAnnotationReleaseFailed = "failed"
 	VolumeAnnotationReleaseStatus = "status"
 
+	// File system check annotations, fsck annotation is set by user and is removed by node when check is finished
+	VolumeAnnotationFsck        = "fsck"
+	VolumeAnnotationFsckCheck   = "check"
+	VolumeAnnotationFsckRepair  = "repair"
+	VolumeAnnotationFsckOptions = "fsck-options"
+	VolumeAnnotationFsckStatus  = "fsck-status"
+	VolumeAnnotationFsckDone    = "done"
+	VolumeAnnotationFsckFailed  = "failed"
+
+	// Force release annotations, force-release is set by user for volume which node was removed from the cluster
+	VolumeAnnotationForceRelease              = "force-release"
+	VolumeAnnotationForceReleaseUnschedulable = "unschedulable"
+	VolumeAnnotationForceReleaseCleanup       = "cleanup"
+	VolumeAnnotationForceReleaseStatus        = "force-release-status"
+	VolumeAnnotationForceReleaseDone          = "done"
+	VolumeAnnotationForceReleaseFailed        = "failed"
+
+	// VolumeAnnotationStagingPath is set by node when volume is staged, volume is staged again on node boot
+	VolumeAnnotationStagingPath = "staging-path"
+	// VolumeAnnotationEraseData is set by user to confirm that file system of the volume could be recreated on stage,
+	// value should be equal to volume ID, annotation is removed by node once file system is recreated
+	VolumeAnnotationEraseData = "erase-data"
+	// VolumeAnnotationImport is set by importer on volume which adopts existing partition or logical volume,
+	// node checks the device and takes it into use instead of creating it
+	VolumeAnnotationImport = "import"
+
+	// Export annotations, export is set by user to hand the volume back to the OS, volume CR is removed by node
+	// while data and device are kept, export-status is set only if export failed
+	VolumeAnnotationExport       = "export"
+	VolumeAnnotationExportStatus = "export-status"
+	VolumeAnnotationExportFailed = "failed"
+
 	//Volume expansion annotations
 	VolumePreviousStatus   = "expansion/previous-status"
 	VolumePreviousCapacity = "expansion/previous-capacity"
@@ -98,17 +171,27 @@ const (
 	ModeRAW = "RAW"
 	ModeFS  = "FS"
 
+	// Volume integrity protection
+	IntegrityDMIntegrity = "dm-integrity"
+	IntegrityChecksum    = "checksum"
+
+	// Volume file system formatting profile
+	FormatProfileAligned  = "aligned"
+	FormatProfileHugepage = "hugepage"
+
 	// Volume location type
 	LocationTypeDrive = "DRIVE"
 	LocationTypeLVM   = "LVM"
 	LocationTypeNVMe  = "NVME"
 
 	// CSI StorageClass
-	// For volumes with storage class 'ANY' CSI will pick any AC except LVG AC
+	// For volumes with storage class 'ANY' CSI will pick any AC except LVG, PMEM and ZONED AC
 	StorageClassAny       = "ANY"
 	StorageClassHDD       = "HDD"
 	StorageClassSSD       = "SSD"
 	StorageClassNVMe      = "NVME"
+	StorageClassPMEM      = "PMEM"
+	StorageClassZoned     = "ZONED"
 	StorageClassHDDLVG    = "HDDLVG"
 	StorageClassSSDLVG    = "SSDLVG"
 	StorageClassNVMeLVG   = "NVMELVG"
@@ -0,0 +1,201 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivecrd
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// DriveConditionType is a type of the Drive CR condition
+type DriveConditionType string
+
+// Drive CR condition types
+const (
+	// DriveConditionDiscovered is true when drive is reported by drive manager
+	DriveConditionDiscovered DriveConditionType = "Discovered"
+	// DriveConditionHealthy is true when drive health is GOOD
+	DriveConditionHealthy DriveConditionType = "Healthy"
+	// DriveConditionSuspect is true when drive health is SUSPECT
+	DriveConditionSuspect DriveConditionType = "Suspect"
+	// DriveConditionFailed is true when drive health is BAD
+	DriveConditionFailed DriveConditionType = "Failed"
+	// DriveConditionRemoved is true when drive usage is REMOVED
+	DriveConditionRemoved DriveConditionType = "Removed"
+	// DriveConditionIOErrors is true when number of I/O errors of the drive during the last hour reaches threshold
+	DriveConditionIOErrors DriveConditionType = "IOErrors"
+)
+
+// Reasons of the Drive CR status changes
+const (
+	// ReasonDiscovered is used when drive was reported by drive manager first time
+	ReasonDiscovered = "Discovered"
+	// ReasonDriveMgrUpdate is used when drive manager reported changes of the drive
+	ReasonDriveMgrUpdate = "DriveManagerUpdate"
+	// ReasonNotDiscovered is used when drive manager stopped to report the drive
+	ReasonNotDiscovered = "NotDiscovered"
+	// ReasonUsageChanged is used when drive usage was changed during replacement procedure
+	ReasonUsageChanged = "UsageChanged"
+	// ReasonIOErrorCheck is used when node counted I/O errors of the drive
+	ReasonIOErrorCheck = "IOErrorCheck"
+)
+
+// DriveHistoryLimit is a maximum amount of records in the Drive CR status history
+const DriveHistoryLimit = 10
+
+// DriveCondition describes state of the drive at a certain point
+type DriveCondition struct {
+	Type               DriveConditionType     `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+}
+
+// DriveHistoryRecord holds health, status and usage of the drive after the change
+type DriveHistoryRecord struct {
+	Time   metav1.Time `json:"time"`
+	Health string      `json:"health,omitempty"`
+	Status string      `json:"status,omitempty"`
+	Usage  string      `json:"usage,omitempty"`
+	Reason string      `json:"reason,omitempty"`
+}
+
+// DriveIOErrors holds I/O errors of the drive detected by kernel of the node
+type DriveIOErrors struct {
+	// Count is a number of I/O errors since boot of the node
+	Count int64 `json:"count"`
+	// LastHour is a number of I/O errors during the last hour
+	LastHour int64 `json:"lastHour"`
+	// LastUpdateTime is a time when number of errors was changed
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// DriveStatus is the observed state of the drive
+type DriveStatus struct {
+	Conditions []DriveCondition `json:"conditions,omitempty"`
+	// History contains last DriveHistoryLimit changes of the drive health, status and usage
+	History []DriveHistoryRecord `json:"history,omitempty"`
+	// IOErrors is set once node counted I/O errors of the drive, nil if node can't count them
+	IOErrors *DriveIOErrors `json:"ioErrors,omitempty"`
+}
+
+// DeepCopyInto copies DriveStatus into out
+func (in *DriveStatus) DeepCopyInto(out *DriveStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		out.Conditions = make([]DriveCondition, len(in.Conditions))
+		for i := range in.Conditions {
+			out.Conditions[i] = in.Conditions[i]
+			in.Conditions[i].LastTransitionTime.DeepCopyInto(&out.Conditions[i].LastTransitionTime)
+		}
+	}
+	if in.History != nil {
+		out.History = make([]DriveHistoryRecord, len(in.History))
+		for i := range in.History {
+			out.History[i] = in.History[i]
+			in.History[i].Time.DeepCopyInto(&out.History[i].Time)
+		}
+	}
+	if in.IOErrors != nil {
+		out.IOErrors = new(DriveIOErrors)
+		*out.IOErrors = *in.IOErrors
+		in.IOErrors.LastUpdateTime.DeepCopyInto(&out.IOErrors.LastUpdateTime)
+	}
+}
+
+// GetCondition returns condition of the Drive CR with provided type or nil if it isn't set
+func (in *Drive) GetCondition(conditionType DriveConditionType) *DriveCondition {
+	for i := range in.Status.Conditions {
+		if in.Status.Conditions[i].Type == conditionType {
+			return &in.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// RefreshStatus recalculates conditions of the Drive CR based on its spec and
+// adds history record if drive health, status or usage was changed since the last record
+// Receives reason of the change and time of the change
+func (in *Drive) RefreshStatus(reason string, now metav1.Time) {
+	in.setCondition(DriveConditionDiscovered, in.Spec.Status == apiV1.DriveStatusOnline, reason, now)
+	in.setCondition(DriveConditionHealthy, in.Spec.Health == apiV1.HealthGood, reason, now)
+	in.setCondition(DriveConditionSuspect, in.Spec.Health == apiV1.HealthSuspect, reason, now)
+	in.setCondition(DriveConditionFailed, in.Spec.Health == apiV1.HealthBad, reason, now)
+	in.setCondition(DriveConditionRemoved, in.Spec.Usage == apiV1.DriveUsageRemoved, reason, now)
+
+	history := in.Status.History
+	if len(history) > 0 {
+		last := history[len(history)-1]
+		if last.Health == in.Spec.Health && last.Status == in.Spec.Status && last.Usage == in.Spec.Usage {
+			return
+		}
+	}
+	history = append(history, DriveHistoryRecord{
+		Time:   now,
+		Health: in.Spec.Health,
+		Status: in.Spec.Status,
+		Usage:  in.Spec.Usage,
+		Reason: reason,
+	})
+	if len(history) > DriveHistoryLimit {
+		history = history[len(history)-DriveHistoryLimit:]
+	}
+	in.Status.History = history
+}
+
+// SetIOErrors sets I/O errors of the drive and IOErrors condition which is true when number of errors during
+// the last hour reaches threshold, zero threshold disables the condition
+// Receives number of errors since boot of the node and during the last hour, threshold and time of the check
+// Returns true if status was changed
+func (in *Drive) SetIOErrors(count, lastHour, threshold int64, now metav1.Time) bool {
+	changed := false
+	if threshold > 0 {
+		exceeded := lastHour >= threshold
+		condition := in.GetCondition(DriveConditionIOErrors)
+		changed = condition == nil || (condition.Status == corev1.ConditionTrue) != exceeded
+		in.setCondition(DriveConditionIOErrors, exceeded, ReasonIOErrorCheck, now)
+	}
+	if errs := in.Status.IOErrors; errs != nil && errs.Count == count && errs.LastHour == lastHour {
+		return changed
+	}
+	in.Status.IOErrors = &DriveIOErrors{Count: count, LastHour: lastHour, LastUpdateTime: now}
+	return true
+}
+
+// setCondition sets condition status, transition time is changed only if status was changed
+func (in *Drive) setCondition(conditionType DriveConditionType, value bool, reason string, now metav1.Time) {
+	status := corev1.ConditionFalse
+	if value {
+		status = corev1.ConditionTrue
+	}
+	if condition := in.GetCondition(conditionType); condition != nil {
+		if condition.Status != status {
+			condition.Status = status
+			condition.LastTransitionTime = now
+			condition.Reason = reason
+		}
+		return
+	}
+	in.Status.Conditions = append(in.Status.Conditions, DriveCondition{
+		Type:               conditionType,
+		Status:             status,
+		LastTransitionTime: now,
+		Reason:             reason,
+	})
+}
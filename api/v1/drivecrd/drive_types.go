@@ -22,6 +22,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -31,12 +32,26 @@ import (
 
 // Drive is the Schema for the drives API
 //kubebuilder:object:generate=false
-// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:resource:scope=Cluster,shortName={drv,drvs}
+// +kubebuilder:printcolumn:name="SIZE",type="integer",JSONPath=".spec.Size",description="Drive size in bytes"
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.Type",description="Drive type"
+// +kubebuilder:printcolumn:name="HEALTH",type="string",JSONPath=".spec.Health",description="Drive health status"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".spec.Status",description="Drive status online/offline"
+// +kubebuilder:printcolumn:name="USAGE",type="string",JSONPath=".spec.Usage",description="Drive usage status"
+// +kubebuilder:printcolumn:name="SYSTEM",type="boolean",JSONPath=".spec.IsSystem",description="Whether drive is system",priority=1
+// +kubebuilder:printcolumn:name="PATH",type="string",JSONPath=".spec.Path",description="Drive path",priority=1
+// +kubebuilder:printcolumn:name="SERIAL NUMBER",type="string",JSONPath=".spec.SerialNumber",description="Drive serial number",priority=1
+// +kubebuilder:printcolumn:name="NODE",type="string",JSONPath=".spec.NodeId",description="Drive node location"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 type Drive struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Spec api.Drive `json:"spec,omitempty"`
+	// Status isn't a subresource unlike status of Volume CR: Drive CR is changed only by node service of its node
+	// and conditions and history are changed by the same updates as health, status and usage of the drive,
+	// so they are stored together with the spec in a single write
+	Status DriveStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -55,6 +70,11 @@ func (in *Drive) DeepCopyInto(out *Drive) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
+	if in.Spec.SELEvents != nil {
+		out.Spec.SELEvents = make([]string, len(in.Spec.SELEvents))
+		copy(out.Spec.SELEvents, in.Spec.SELEvents)
+	}
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 func init() {
@@ -70,13 +90,38 @@ func (in *Drive) Equals(drive *api.Drive) bool {
 		in.Spec.Health == drive.Health &&
 		in.Spec.Type == drive.Type &&
 		in.Spec.Size == drive.Size &&
-		in.Spec.Path == drive.Path
+		in.Spec.Path == drive.Path &&
+		in.Spec.Firmware == drive.Firmware &&
+		in.Spec.Enclosure == drive.Enclosure &&
+		in.Spec.Slot == drive.Slot &&
+		in.Spec.Backplane == drive.Backplane &&
+		in.Spec.NUMANode == drive.NUMANode &&
+		in.Spec.WWN == drive.WWN &&
+		equalStrings(in.Spec.SELEvents, drive.SELEvents)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsHotSpare checks whether drive is designated as a hot spare
+func (in *Drive) IsHotSpare() bool {
+	return in.Annotations[apiV1.DriveAnnotationHotSpare] == apiV1.DriveAnnotationHotSpareEnabled
 }
 
 func (in *Drive) GetDriveDescription() string {
 	return fmt.Sprintf("Drive Details: SN='%s', Node='%s',"+
 		" Type='%s', Model='%s %s',"+
-		" Size='%d', Firmware='%s'",
+		" Size='%d', Firmware='%s', Enclosure='%s', Slot='%s'",
 		in.Spec.SerialNumber, in.Spec.NodeId, in.Spec.Type,
-		in.Spec.VID, in.Spec.PID, in.Spec.Size, in.Spec.Firmware)
+		in.Spec.VID, in.Spec.PID, in.Spec.Size, in.Spec.Firmware,
+		in.Spec.Enclosure, in.Spec.Slot)
 }
@@ -26,6 +26,13 @@ import (
 
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:resource:scope=Cluster,shortName={lvg,lvgs}
+// +kubebuilder:printcolumn:name="SIZE",type="integer",JSONPath=".spec.Size",description="Size of LogicalVolumeGroup in bytes"
+// +kubebuilder:printcolumn:name="HEALTH",type="string",JSONPath=".spec.Health",description="LogicalVolumeGroup health status"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".spec.Status",description="LogicalVolumeGroup status"
+// +kubebuilder:printcolumn:name="LOCATIONS",type="string",JSONPath=".spec.Locations",description="LogicalVolumeGroup drives locations list"
+// +kubebuilder:printcolumn:name="VOLUMES",type="string",JSONPath=".spec.VolumeRefs",description="Volumes placed on LogicalVolumeGroup",priority=1
+// +kubebuilder:printcolumn:name="NODE",type="string",JSONPath=".spec.Node",description="LogicalVolumeGroup node location"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // LogicalVolumeGroup is the Schema for the LVGs API
 type LogicalVolumeGroup struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -0,0 +1,59 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package lvgpolicycrd
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// +kubebuilder:resource:scope=Cluster,shortName={lvgp,lvgps}
+// +kubebuilder:printcolumn:name="STORAGE CLASS",type="string",JSONPath=".spec.StorageClass",description="Storage class of LogicalVolumeGroups"
+// +kubebuilder:printcolumn:name="MAX DRIVE SIZE",type="integer",JSONPath=".spec.MaxDriveSize",description="Drives which size is equal or bigger aren't combined"
+// +kubebuilder:printcolumn:name="DRIVE SELECTOR",type="string",JSONPath=".spec.DriveSelector",description="Label selector of drives",priority=1
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// LVGPolicy is the Schema for the lvgpolicies API, it combines matching drives of each node into LogicalVolumeGroup
+type LVGPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              api.LVGPolicy `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// LVGPolicyList contains a list of LVGPolicy
+//+kubebuilder:object:generate=true
+type LVGPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LVGPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilderLVGPolicy.Register(&LVGPolicy{}, &LVGPolicyList{})
+}
+
+func (in *LVGPolicy) DeepCopyInto(out *LVGPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
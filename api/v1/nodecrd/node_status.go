@@ -0,0 +1,77 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecrd
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeCheckType is a type of the node pre-flight check
+type NodeCheckType string
+
+// Node pre-flight check types
+const (
+	// NodeCheckTool verifies that system util is available in PATH
+	NodeCheckTool NodeCheckType = "Tool"
+	// NodeCheckKernelModule verifies that kernel module is loaded or built in
+	NodeCheckKernelModule NodeCheckType = "KernelModule"
+	// NodeCheckUdev verifies that udev database of the host is accessible
+	NodeCheckUdev NodeCheckType = "Udev"
+	// NodeCheckHostPath verifies that host directory is mounted into the container
+	NodeCheckHostPath NodeCheckType = "HostPath"
+)
+
+// NodeCheck is a result of the single pre-flight check
+type NodeCheck struct {
+	Type   NodeCheckType `json:"type"`
+	Name   string        `json:"name"`
+	Passed bool          `json:"passed"`
+	// Message holds reason of the failure
+	Message string `json:"message,omitempty"`
+}
+
+// NodeStatus is the observed state of the node
+type NodeStatus struct {
+	// Validated is true when all pre-flight checks were passed
+	Validated          bool        `json:"validated"`
+	LastValidationTime metav1.Time `json:"lastValidationTime,omitempty"`
+	Checks             []NodeCheck `json:"checks,omitempty"`
+}
+
+// DeepCopyInto copies NodeStatus into out
+func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
+	*out = *in
+	in.LastValidationTime.DeepCopyInto(&out.LastValidationTime)
+	if in.Checks != nil {
+		out.Checks = make([]NodeCheck, len(in.Checks))
+		copy(out.Checks, in.Checks)
+	}
+}
+
+// SetValidationResult stores results of the pre-flight checks
+// Receives results of the checks and time of the validation
+func (in *Node) SetValidationResult(checks []NodeCheck, now metav1.Time) {
+	in.Status.Validated = true
+	for _, check := range checks {
+		if !check.Passed {
+			in.Status.Validated = false
+			break
+		}
+	}
+	in.Status.Checks = checks
+	in.Status.LastValidationTime = now
+}
@@ -25,12 +25,19 @@ import (
 // +kubebuilder:object:root=true
 
 // +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,shortName={csibmnode,csibmnodes}
+// +kubebuilder:printcolumn:name="UUID",type="string",JSONPath=".spec.UUID",description="Node Id"
+// +kubebuilder:printcolumn:name="HOSTNAME",type="string",JSONPath=".spec.Addresses.Hostname",description="Node hostname"
+// +kubebuilder:printcolumn:name="NODE_IP",type="string",JSONPath=".spec.Addresses.InternalIP",description="Node ip"
+// +kubebuilder:printcolumn:name="VALIDATED",type="boolean",JSONPath=".status.validated",description="Node pre-flight checks were passed",priority=1
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // Node is the Schema for the Node API
 type Node struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              api.Node `json:"spec,omitempty"`
+	Spec              api.Node   `json:"spec,omitempty"`
+	Status            NodeStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -52,4 +59,5 @@ func (in *Node) DeepCopyInto(out *Node) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 }
@@ -0,0 +1,57 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotacrd
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+)
+
+// +kubebuilder:object:root=true
+
+// +kubebuilder:resource:scope=Namespaced,shortName={sq,sqs}
+// +kubebuilder:printcolumn:name="BYTES",type="integer",JSONPath=".spec.Bytes",description="Limit of total size of volumes in bytes"
+// +kubebuilder:printcolumn:name="VOLUMES",type="integer",JSONPath=".spec.Volumes",description="Limit of amount of volumes"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// StorageQuota is the Schema for the storagequotas API, it limits capacity which volumes of the namespace consume
+type StorageQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              api.StorageQuota `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StorageQuotaList contains a list of StorageQuota
+//+kubebuilder:object:generate=true
+type StorageQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StorageQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilderStorageQuota.Register(&StorageQuota{}, &StorageQuotaList{})
+}
+
+func (in *StorageQuota) DeepCopyInto(out *StorageQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
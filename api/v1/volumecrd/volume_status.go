@@ -0,0 +1,270 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumecrd
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// VolumeConditionType is a type of the Volume CR condition
+type VolumeConditionType string
+
+// Volume CR condition types
+const (
+	// VolumeConditionProvisioned is true when volume was created on the node
+	VolumeConditionProvisioned VolumeConditionType = "Provisioned"
+	// VolumeConditionStaged is true when volume is staged (NodeStageVolume) and isn't unstaged yet
+	VolumeConditionStaged VolumeConditionType = "Staged"
+	// VolumeConditionPublished is true when volume is published to the pod
+	VolumeConditionPublished VolumeConditionType = "Published"
+	// VolumeConditionResizing is true while volume expansion is in progress
+	VolumeConditionResizing VolumeConditionType = "Resizing"
+	// VolumeConditionErrored is true when the last operation with the volume failed
+	VolumeConditionErrored VolumeConditionType = "Errored"
+	// VolumeConditionDeletionPending is true when volume removal was requested
+	VolumeConditionDeletionPending VolumeConditionType = "DeletionPending"
+	// VolumeConditionPending is true when operation with the volume is queued on the node
+	// because of the limit of concurrent operations
+	VolumeConditionPending VolumeConditionType = "Pending"
+	// VolumeConditionIntegrityError is true when integrity check of the volume detected corrupted data
+	VolumeConditionIntegrityError VolumeConditionType = "IntegrityError"
+	// VolumeConditionFsckPending is true when requested file system check waits for the volume to be unstaged
+	VolumeConditionFsckPending VolumeConditionType = "FsckPending"
+	// VolumeConditionFilesystemErrors is true when the last file system check left errors uncorrected
+	VolumeConditionFilesystemErrors VolumeConditionType = "FilesystemErrors"
+	// VolumeConditionNodeRemoved is true when node of the volume isn't found in the cluster
+	VolumeConditionNodeRemoved VolumeConditionType = "NodeRemoved"
+	// VolumeConditionPanicked is true when operation with the volume panicked, message points to the crash dump
+	VolumeConditionPanicked VolumeConditionType = "Panicked"
+	// VolumeConditionOnDiskMissing is true when partition or logical volume of the volume isn't found on the node,
+	// e.g. partition table was wiped outside of the driver
+	VolumeConditionOnDiskMissing VolumeConditionType = "OnDiskMissing"
+)
+
+// VolumePhase is a step of the volume provisioning which time is tracked
+type VolumePhase string
+
+// Volume provisioning phases
+const (
+	// VolumePhaseACSelected is finished when AvailableCapacity was chosen and Volume CR was created
+	VolumePhaseACSelected VolumePhase = "ACSelected"
+	// VolumePhasePartitionCreated is finished when partition or logical volume was created on the node
+	VolumePhasePartitionCreated VolumePhase = "PartitionCreated"
+	// VolumePhaseFormatted is finished when file system was created
+	VolumePhaseFormatted VolumePhase = "Formatted"
+	// VolumePhasePopulated is finished when image was written onto the volume
+	VolumePhasePopulated VolumePhase = "Populated"
+	// VolumePhaseStaged is finished when volume was staged for the first time
+	VolumePhaseStaged VolumePhase = "Staged"
+	// VolumePhasePublished is finished when volume was published for the first time
+	VolumePhasePublished VolumePhase = "Published"
+)
+
+// VolumeCondition describes state of the volume at a certain point
+type VolumeCondition struct {
+	Type               VolumeConditionType    `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	// Reason is the CSI status of the volume which caused transition
+	Reason string `json:"reason,omitempty"`
+	// Message holds human readable details of the condition
+	Message string `json:"message,omitempty"`
+}
+
+// VolumeStatus is the observed state of the volume
+type VolumeStatus struct {
+	// ObservedGeneration is the generation of the volume spec which status reflects
+	ObservedGeneration int64             `json:"observedGeneration,omitempty"`
+	Conditions         []VolumeCondition `json:"conditions,omitempty"`
+	// PhaseTimestamps holds time when each provisioning phase was finished
+	PhaseTimestamps map[VolumePhase]metav1.Time `json:"phaseTimestamps,omitempty"`
+}
+
+// DeepCopyInto copies VolumeStatus into out
+func (in *VolumeStatus) DeepCopyInto(out *VolumeStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		out.Conditions = make([]VolumeCondition, len(in.Conditions))
+		for i := range in.Conditions {
+			out.Conditions[i] = in.Conditions[i]
+			in.Conditions[i].LastTransitionTime.DeepCopyInto(&out.Conditions[i].LastTransitionTime)
+		}
+	}
+	if in.PhaseTimestamps != nil {
+		out.PhaseTimestamps = make(map[VolumePhase]metav1.Time, len(in.PhaseTimestamps))
+		for phase, t := range in.PhaseTimestamps {
+			out.PhaseTimestamps[phase] = *t.DeepCopy()
+		}
+	}
+}
+
+// GetCondition returns condition of the Volume CR with provided type or nil if it isn't set
+func (in *Volume) GetCondition(conditionType VolumeConditionType) *VolumeCondition {
+	for i := range in.Status.Conditions {
+		if in.Status.Conditions[i].Type == conditionType {
+			return &in.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsForceReleased returns true if the volume was force released after removal of its node and must not be used
+func (in *Volume) IsForceReleased() bool {
+	return in.Annotations[apiV1.VolumeAnnotationForceReleaseStatus] == apiV1.VolumeAnnotationForceReleaseDone
+}
+
+// RefreshStatus recalculates conditions of the Volume CR based on CSIStatus of the volume spec
+// and sets observed generation. CSIStatus is kept as an internal phase of the volume,
+// conditions are intended for external automation
+// Receives time of the change
+func (in *Volume) RefreshStatus(now metav1.Time) {
+	phase := in.Spec.CSIStatus
+	switch phase {
+	case apiV1.Creating:
+		in.RecordPhase(VolumePhaseACSelected, now)
+		in.setCondition(VolumeConditionProvisioned, false, phase, now)
+	case apiV1.Created:
+		in.setCondition(VolumeConditionProvisioned, true, phase, now)
+		in.setCondition(VolumeConditionStaged, false, phase, now)
+		in.setCondition(VolumeConditionPublished, false, phase, now)
+	case apiV1.VolumeReady:
+		in.RecordPhase(VolumePhaseStaged, now)
+		in.setCondition(VolumeConditionProvisioned, true, phase, now)
+		in.setCondition(VolumeConditionStaged, true, phase, now)
+		in.setCondition(VolumeConditionPublished, false, phase, now)
+	case apiV1.Published:
+		in.RecordPhase(VolumePhasePublished, now)
+		in.setCondition(VolumeConditionProvisioned, true, phase, now)
+		in.setCondition(VolumeConditionStaged, true, phase, now)
+		in.setCondition(VolumeConditionPublished, true, phase, now)
+	case apiV1.Resizing:
+		in.setCondition(VolumeConditionResizing, true, phase, now)
+	case apiV1.Resized:
+		in.setCondition(VolumeConditionResizing, false, phase, now)
+	}
+
+	in.setCondition(VolumeConditionErrored, phase == apiV1.Failed, phase, now)
+	in.setCondition(VolumeConditionDeletionPending,
+		phase == apiV1.Removing || phase == apiV1.Removed || in.DeletionTimestamp != nil, phase, now)
+	in.Status.ObservedGeneration = in.Generation
+}
+
+// RecordPhase stores time when provisioning phase was finished, time is kept if phase was already recorded
+func (in *Volume) RecordPhase(phase VolumePhase, at metav1.Time) {
+	if _, ok := in.Status.PhaseTimestamps[phase]; ok {
+		return
+	}
+	if in.Status.PhaseTimestamps == nil {
+		in.Status.PhaseTimestamps = make(map[VolumePhase]metav1.Time)
+	}
+	in.Status.PhaseTimestamps[phase] = at
+}
+
+// SetPending marks operation with the volume as queued or as started when slot for it is acquired
+// Receives whether operation is queued and time of the change
+func (in *Volume) SetPending(pending bool, now metav1.Time) {
+	in.setCondition(VolumeConditionPending, pending, in.Spec.CSIStatus, now)
+}
+
+// SetIntegrityError sets result of the volume integrity check, message is updated on each check
+// Receives whether corruption was detected, details of the check and time of the change
+func (in *Volume) SetIntegrityError(corrupted bool, message string, now metav1.Time) {
+	in.setCondition(VolumeConditionIntegrityError, corrupted, in.Spec.CSIStatus, now)
+	in.GetCondition(VolumeConditionIntegrityError).Message = message
+}
+
+// SetFsckPending marks requested file system check as waiting for the volume to be unstaged
+// Receives whether check is pending and time of the change
+func (in *Volume) SetFsckPending(pending bool, now metav1.Time) {
+	in.setCondition(VolumeConditionFsckPending, pending, in.Spec.CSIStatus, now)
+}
+
+// SetFsckResult records result of the file system check and resets FsckPending condition
+// Receives whether errors were left uncorrected, summary of the check and time of the change
+func (in *Volume) SetFsckResult(errorsLeft bool, message string, now metav1.Time) {
+	if in.GetCondition(VolumeConditionFsckPending) != nil {
+		in.SetFsckPending(false, now)
+	}
+	in.setCondition(VolumeConditionFilesystemErrors, errorsLeft, in.Spec.CSIStatus, now)
+	in.GetCondition(VolumeConditionFilesystemErrors).Message = message
+}
+
+// SetNodeRemoved records whether node of the volume is found in the cluster
+// Receives whether node was removed, details and time of the change
+// Returns true if condition was changed
+func (in *Volume) SetNodeRemoved(removed bool, message string, now metav1.Time) bool {
+	if condition := in.GetCondition(VolumeConditionNodeRemoved); condition != nil {
+		if (condition.Status == corev1.ConditionTrue) == removed && condition.Message == message {
+			return false
+		}
+	} else if !removed {
+		return false
+	}
+	in.setCondition(VolumeConditionNodeRemoved, removed, in.Spec.CSIStatus, now)
+	in.GetCondition(VolumeConditionNodeRemoved).Message = message
+	return true
+}
+
+// SetOnDiskMissing records whether partition or logical volume of the volume is found on the node
+// Receives whether allocation is missing, details and time of the change
+// Returns true if condition was changed
+func (in *Volume) SetOnDiskMissing(missing bool, message string, now metav1.Time) bool {
+	if condition := in.GetCondition(VolumeConditionOnDiskMissing); condition != nil {
+		if (condition.Status == corev1.ConditionTrue) == missing && condition.Message == message {
+			return false
+		}
+	} else if !missing {
+		return false
+	}
+	in.setCondition(VolumeConditionOnDiskMissing, missing, in.Spec.CSIStatus, now)
+	in.GetCondition(VolumeConditionOnDiskMissing).Message = message
+	return true
+}
+
+// SetPanicked records that operation with the volume panicked, condition is kept for the postmortem
+// Receives details of the panic and time of the change
+func (in *Volume) SetPanicked(message string, now metav1.Time) {
+	in.setCondition(VolumeConditionPanicked, true, in.Spec.CSIStatus, now)
+	condition := in.GetCondition(VolumeConditionPanicked)
+	condition.LastTransitionTime = now
+	condition.Message = message
+}
+
+// setCondition sets condition status, transition time is changed only if status was changed
+func (in *Volume) setCondition(conditionType VolumeConditionType, value bool, reason string, now metav1.Time) {
+	status := corev1.ConditionFalse
+	if value {
+		status = corev1.ConditionTrue
+	}
+	if condition := in.GetCondition(conditionType); condition != nil {
+		if condition.Status != status {
+			condition.Status = status
+			condition.LastTransitionTime = now
+			condition.Reason = reason
+		}
+		return
+	}
+	in.Status.Conditions = append(in.Status.Conditions, VolumeCondition{
+		Type:               conditionType,
+		Status:             status,
+		LastTransitionTime: now,
+		Reason:             reason,
+	})
+}
@@ -25,12 +25,24 @@ import (
 // +kubebuilder:object:root=true
 
 // Volume is the Schema for the volumes API
-// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:resource:scope=Namespaced,shortName={vol,vols}
+// +kubebuilder:printcolumn:name="SIZE",type="integer",JSONPath=".spec.Size",description="Volume allocated size in bytes"
+// +kubebuilder:printcolumn:name="STORAGE CLASS",type="string",JSONPath=".spec.StorageClass",description="Volume storage class"
+// +kubebuilder:printcolumn:name="HEALTH",type="string",JSONPath=".spec.Health",description="Volume health status"
+// +kubebuilder:printcolumn:name="CSI_STATUS",type="string",JSONPath=".spec.CSIStatus",description="Volume internal CSI status"
+// +kubebuilder:printcolumn:name="OP_STATUS",type="string",JSONPath=".spec.OperationalStatus",description="Volume operational status",priority=1
+// +kubebuilder:printcolumn:name="USAGE",type="string",JSONPath=".spec.Usage",description="Volume usage status",priority=1
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.Type",description="Volume fs type",priority=1
+// +kubebuilder:printcolumn:name="LOCATION",type="string",JSONPath=".spec.Location",description="Volume LVG or drive location"
+// +kubebuilder:printcolumn:name="NODE",type="string",JSONPath=".spec.NodeId",description="Volume node location"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
 type Volume struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec api.Volume `json:"spec,omitempty"`
+	Spec   api.Volume   `json:"spec,omitempty"`
+	Status VolumeStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -49,6 +61,7 @@ func (in *Volume) DeepCopyInto(out *Volume) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 func init() {
@@ -0,0 +1,90 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package for main function of diagnostics collector, it packs custom resources, logs of the driver and state
+// of the devices on each node into a tarball for support cases
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/controller/diagnostics"
+)
+
+var (
+	namespace = flag.String("namespace", "default", "Namespace where the driver is deployed")
+	output    = flag.String("output", "",
+		"Path of the tarball, csi-baremetal-diagnostics-<time>.tar.gz in the current directory is used if empty")
+	logTail  = flag.Int64("logtail", diagnostics.DefaultLogTail, "Number of the latest log lines collected from each container")
+	timeout  = flag.Duration("timeout", 5*time.Minute, "Time limit of the collection")
+	logLevel = flag.String("loglevel", base.InfoLevel,
+		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
+)
+
+func main() {
+	flag.Parse()
+
+	logger, _ := base.InitLogger("", *logLevel)
+	logger.SetOutput(os.Stderr)
+
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("csi-baremetal-diagnostics-%s.tar.gz", time.Now().UTC().Format("20060102T150405Z"))
+	}
+
+	k8sClient, err := k8s.GetK8SClient(k8s.RateLimits{})
+	if err != nil {
+		exit(logger, fmt.Errorf("unable to create k8s client: %v", err))
+	}
+	pods, err := diagnostics.NewKubePodReader(k8s.GetRestConfig(k8s.RateLimits{}))
+	if err != nil {
+		exit(logger, fmt.Errorf("unable to create k8s clientset: %v", err))
+	}
+	collector := diagnostics.New(k8s.NewKubeClient(k8sClient, logger, *namespace), pods, logger)
+	collector.SetLogTail(*logTail)
+
+	file, err := os.Create(path)
+	if err != nil {
+		exit(logger, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	failed, err := collector.Collect(ctx, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		exit(logger, fmt.Errorf("unable to write %s: %v", path, err))
+	}
+	if failed > 0 {
+		logger.Warnf("%d items weren't collected, they are listed in errors.txt of the tarball", failed)
+	}
+	fmt.Printf("Diagnostics are written to %s\n", path)
+}
+
+func exit(logger *logrus.Logger, err error) {
+	logger.Error(err)
+	os.Exit(1)
+}
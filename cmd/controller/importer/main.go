@@ -0,0 +1,103 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package for main function of volume importer, it adopts existing partition or logical volume as volume of the driver
+// and creates PV for it, so data of hostPath or local static provisioner volumes is moved under management of the driver
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/controller/importer"
+)
+
+var (
+	node          = flag.String("node", "", "Name of k8s node where device is located")
+	driveSerial   = flag.String("drive", "", "Serial number of the drive which holds partition")
+	partUUID      = flag.String("partuuid", "", "UUID of the partition, it becomes ID of the volume and name of PV")
+	lvg           = flag.String("lvg", "", "Name of LogicalVolumeGroup CR or volume group which holds logical volume")
+	lv            = flag.String("lv", "", "Name of the logical volume, it becomes ID of the volume and name of PV")
+	fsType        = flag.String("fstype", "", "Expected file system, file system found on the device is used if empty")
+	storageClass  = flag.String("storageclass", "", "Name of StorageClass of PV")
+	claim         = flag.String("claim", "", "PVC which PV is reserved for in <namespace>/<name> format, optional")
+	reclaimPolicy = flag.String("reclaimpolicy", string(corev1.PersistentVolumeReclaimRetain),
+		"Reclaim policy of PV, data is removed when PV with Delete policy is released")
+	timeout  = flag.Duration("timeout", 2*time.Minute, "Time to wait for node to import the device")
+	logLevel = flag.String("loglevel", base.InfoLevel,
+		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
+)
+
+func main() {
+	flag.Parse()
+
+	logger, _ := base.InitLogger("", *logLevel)
+	logger.SetOutput(os.Stderr)
+
+	req := importer.Request{
+		NodeName:      *node,
+		DriveSerial:   *driveSerial,
+		PartUUID:      *partUUID,
+		LVG:           *lvg,
+		LV:            *lv,
+		FSType:        *fsType,
+		StorageClass:  *storageClass,
+		ReclaimPolicy: corev1.PersistentVolumeReclaimPolicy(*reclaimPolicy),
+	}
+	if *claim != "" {
+		var err error
+		if req.ClaimNamespace, req.ClaimName, err = parseClaim(*claim); err != nil {
+			exit(logger, err)
+		}
+	}
+
+	k8sClient, err := k8s.GetK8SClient(k8s.RateLimits{})
+	if err != nil {
+		exit(logger, fmt.Errorf("unable to create k8s client: %v", err))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	pv, err := importer.New(k8s.NewKubeClient(k8sClient, logger, ""), logger).Import(ctx, req)
+	if err != nil {
+		exit(logger, err)
+	}
+	capacity := pv.Spec.Capacity[corev1.ResourceStorage]
+	fmt.Printf("PV %s of %s is created\n", pv.Name, capacity.String())
+}
+
+// parseClaim splits PVC reference in <namespace>/<name> format
+func parseClaim(value string) (string, string, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+		return parts[0], parts[1], nil
+	}
+	return "", "", fmt.Errorf("claim %s should be in <namespace>/<name> format", value)
+}
+
+func exit(logger *logrus.Logger, err error) {
+	logger.Error(err)
+	os.Exit(1)
+}
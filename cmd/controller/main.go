@@ -18,6 +18,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net"
@@ -26,21 +27,32 @@ import (
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"k8s.io/client-go/discovery"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 
 	// +kubebuilder:scaffold:imports
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	"github.com/dell/csi-baremetal/pkg/base/config"
+	"github.com/dell/csi-baremetal/pkg/base/crashdump"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/rpc"
+	"github.com/dell/csi-baremetal/pkg/base/startup"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/controller"
+	"github.com/dell/csi-baremetal/pkg/controller/notifier"
+	"github.com/dell/csi-baremetal/pkg/controller/reservation"
+	"github.com/dell/csi-baremetal/pkg/events"
 	"github.com/dell/csi-baremetal/pkg/metrics"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 )
 
+const componentName = "csi-baremetal-controller"
+
 var (
 	namespace  = flag.String("namespace", "", "Namespace in which controller service run")
 	healthIP   = flag.String("healthip", base.DefaultHealthIP, "IP for health service")
@@ -49,18 +61,67 @@ var (
 	logPath    = flag.String("logpath", "", "Log path for Controller service")
 	useACRs    = flag.Bool("extender", false,
 		"Whether controller should read AvailableCapacityReservation CR during CreateVolume request or not")
-	logLevel = flag.String("loglevel", base.InfoLevel,
+	useNUMAHint = flag.Bool("numahint", false,
+		"Whether controller should add NUMA node of the volume's drive to the volume context or not")
+	createVolumeParallelism = flag.Int("createvolumeparallelism", base.DefaultCreateVolumeParallelism,
+		"Amount of CreateVolume requests which select capacity in parallel, requests for the same node are serialized")
+	placementStrategy = flag.String("placementstrategy", capacityplanner.BestFitStrategy,
+		fmt.Sprintf("Strategy of AvailableCapacity selection for volume, supported values are %s, %s, %s, %s, %s",
+			capacityplanner.BestFitStrategy, capacityplanner.WorstFitStrategy, capacityplanner.SpreadStrategy,
+			capacityplanner.RoundRobinStrategy, capacityplanner.WearAwareStrategy))
+	orphanedVolumeGracePeriod = flag.Duration("orphanedvolumegraceperiod", 0,
+		"Volumes which PV doesn't exist longer than grace period are removed, 0 disables removal of orphaned volumes")
+	statefulSetReservation = flag.Bool("statefulsetreservation", false,
+		"Whether controller should reserve capacity for StatefulSet replicas set by reserve-replicas annotation or not")
+	topologyLabels = flag.String("topologylabels", "",
+		"Comma-separated node labels (for example rack or zone) which are reported as topology keys in addition to node ID")
+	pvcMetadata = flag.String("pvcmetadata", "",
+		"Comma-separated keys of PVC labels and annotations which are propagated to Volume CR and volume context")
+	imageSourceAllowlist = flag.String("imagesourceallowlist", "",
+		"Comma-separated image sources in scheme://host format which volumes could be populated from, "+
+			"PVC annotations with image source are honored only for sources from the list")
+	operationTimeouts = flag.String("operationtimeouts", "",
+		"Comma-separated server side deadlines of CSI calls by method name (for example CreateVolume=5m), "+
+			"DeadlineExceeded is returned while the operation continues, deadline of the client reduced by 1s is used if not set")
+	crashDumps = flag.Bool("crashdumps", true,
+		"Whether stack traces of panics in CSI calls are stored in "+crashdump.ConfigMapPrefix+
+			"controller ConfigMap and in Panicked condition of the affected Volume CR or not")
+	notifierConfig = flag.String("notifierconfig", "",
+		"Path to the YAML config of webhook, Slack and SMTP notifications about failed drives, exhausted capacity "+
+			"and stuck volumes, notifications are disabled if empty")
+	versionPath = flag.String("version-path", "/version",
+		"The HTTP path on metrics address where build version, revision and API versions are exposed in JSON, "+
+			"version endpoint is disabled if empty")
+	attachCompatibility = flag.Bool("attachcompatibility", false, "Report ControllerPublishVolume capability for "+
+		"external attacher which detaches VolumeAttachments created by previous versions, attach isn't required otherwise")
+	startupTimeout = flag.Duration("startuptimeout", startup.DefaultTimeout,
+		"Time which controller waits for k8s API and CRDs on start before exit")
+	kubeAPIQPS   = flag.Float64("kubeapiqps", k8s.DefaultQPS, "Average amount of k8s API calls per second")
+	kubeAPIBurst = flag.Int("kubeapiburst", k8s.DefaultBurst, "Amount of k8s API calls which could be done at once above QPS")
+	logLevel     = flag.String("loglevel", base.InfoLevel,
 		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
 	metricsAddress = flag.String("metrics-address", "", "The TCP network address where the prometheus metrics endpoint will run"+
 		"(example: :8080 which corresponds to port 8080 on local host). The default is empty string, which means metrics endpoint is disabled.")
 	metricspath = flag.String("metrics-path", "/metrics", "The HTTP path where prometheus metrics will be exposed. Default is /metrics.")
+	configPath  = flag.String("config", "", "Path to the config file, flags which are set explicitly have precedence over it")
 )
 
 func main() {
 	flag.Parse()
 
+	if *configPath != "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			logrus.Fatalf("fail to load config: %v", err)
+		}
+		if err = cfg.ApplyToFlags(flag.CommandLine); err != nil {
+			logrus.Fatalf("fail to apply config: %v", err)
+		}
+	}
+
 	featureConf := featureconfig.NewFeatureConfig()
 	featureConf.Update(featureconfig.FeatureACReservation, *useACRs)
+	featureConf.Update(featureconfig.FeatureNUMAHint, *useNUMAHint)
 
 	var enableMetrics bool
 	if *metricspath != "" {
@@ -74,14 +135,88 @@ func main() {
 
 	logger.Info("Starting controller ...")
 
+	if *configPath != "" {
+		cfgWatcher, err := config.NewWatcher(*configPath, logger)
+		if err != nil {
+			logger.Fatalf("fail to create config watcher: %v", err)
+		}
+		cfgWatcher.ApplyLogLevel(logger, flag.CommandLine)
+		go func() {
+			if err := cfgWatcher.Watch(make(chan struct{})); err != nil {
+				logger.Errorf("Config watcher failed with error: %v", err)
+			}
+		}()
+	}
+
 	csiControllerServer := rpc.NewServerRunner(nil, *endpoint, enableMetrics, logger)
+	timeouts, err := rpc.ParseOperationTimeouts(*operationTimeouts)
+	if err != nil {
+		logger.Fatalf("fail to parse operation timeouts: %v", err)
+	}
+	csiControllerServer.SetOperationTimeouts(timeouts)
+
+	// controller could be started before CRDs are installed, it waits for them instead of crash loop
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(
+		k8s.GetRestConfig(k8s.RateLimits{QPS: float32(*kubeAPIQPS), Burst: *kubeAPIBurst}))
+	if err != nil {
+		logger.Fatalf("fail to create kubernetes discovery client, error: %v", err)
+	}
+	kinds := []string{apiV1.VolumeKind, apiV1.AvailableCapacityKind, apiV1.DriveKind, apiV1.LVGKind}
+	if *useACRs {
+		kinds = append(kinds, apiV1.AvailableCapacityReservationKind)
+	}
+	gate := startup.NewGate(*startupTimeout, logger).
+		Add("k8s API", startup.KubeAPIProbe(discoveryClient)).
+		Add("CSI Baremetal CRDs", startup.CRDProbe(discoveryClient, apiV1.APIV1Version, kinds...))
+	if err := gate.Wait(context.Background()); err != nil {
+		logger.Fatalf("Controller service dependencies aren't ready: %v", err)
+	}
 
-	k8SClient, err := k8s.GetK8SClient()
+	k8SClient, err := k8s.GetK8SClient(k8s.RateLimits{QPS: float32(*kubeAPIQPS), Burst: *kubeAPIBurst})
 	if err != nil {
 		logger.Fatalf("fail to create kubernetes client, error: %v", err)
 	}
 	kubeClient := k8s.NewKubeClient(k8SClient, logger, *namespace)
+	if *crashDumps {
+		csiControllerServer.SetPanicHandler(crashdump.NewRecorder(kubeClient, "controller", logger).Record)
+	}
 	controllerService := controller.NewControllerService(kubeClient, logger, featureConf)
+	controllerService.SetCreateVolumeParallelism(*createVolumeParallelism)
+	controllerService.SetTopologyLabels(k8s.ParseTopologyLabels(*topologyLabels))
+	controllerService.SetPVCMetadataKeys(k8s.ParseTopologyLabels(*pvcMetadata))
+	controllerService.SetAttachCompatibility(*attachCompatibility)
+	strategy, err := capacityplanner.NewPlacementStrategy(*placementStrategy, kubeClient,
+		logger.WithField("component", "PlacementStrategy"))
+	if err != nil {
+		logger.Fatalf("fail to create placement strategy: %v", err)
+	}
+	controllerService.SetPlacementStrategy(strategy)
+	eventRecorder, err := prepareEventRecorder(logger)
+	if err != nil {
+		logger.Fatalf("fail to prepare event recorder: %v", err)
+	}
+	controllerService.SetEventRecorder(eventRecorder)
+	go controllerService.RunOrphanedVolumesJanitor(*orphanedVolumeGracePeriod)
+	go controllerService.RunLVGPolicyReconciler()
+	if *statefulSetReservation {
+		if *useACRs {
+			reservation.NewStatefulSetReserver(kubeClient, logger).Run()
+		} else {
+			logger.Warn("Reservation of capacity for StatefulSet replicas requires extender, it is disabled")
+		}
+	}
+	if err = controllerService.SetImageSourceAllowlist(*imageSourceAllowlist); err != nil {
+		logger.Fatalf("Unable to set image source allowlist: %v", err)
+	}
+	if *notifierConfig != "" {
+		notifierConf, err := notifier.LoadConfig(*notifierConfig)
+		if err != nil {
+			logger.Fatalf("fail to load notifier config: %v", err)
+		}
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+		notifier.NewNotifier(kubeClient, notifierConf, logger).Run(stopCh)
+	}
 	handler := util.NewSignalHandler(logger)
 	go handler.SetupSIGTERMHandler(csiControllerServer)
 
@@ -92,10 +227,14 @@ func main() {
 		grpc_prometheus.Register(csiControllerServer.GRPCServer)
 		grpc_prometheus.EnableHandlingTimeHistogram()
 		grpc_prometheus.EnableClientHandlingTimeHistogram()
-		prometheus.MustRegister(metrics.BuildInfo)
+		versionInfo := metrics.NewVersionInfo()
+		prometheus.MustRegister(versionInfo.Collector())
 
 		go func() {
-			http.Handle(*metricspath, promhttp.Handler())
+			http.Handle(*metricspath, metrics.Handler())
+			if *versionPath != "" {
+				http.Handle(*versionPath, versionInfo.Handler())
+			}
 			if err := http.ListenAndServe(*metricsAddress, nil); err != nil {
 				logger.Warnf("metric http returned: %s ", err)
 			}
@@ -110,9 +249,27 @@ func main() {
 			logger.Fatalf("Controller service failed with error: %v", err)
 		}
 	}()
+	// controller could be restarted in the middle of volume operations, state is rebuilt before serving requests
+	if err := controllerService.WarmStart(context.Background()); err != nil {
+		logger.Fatalf("fail to rebuild controller state, error: %v", err)
+	}
 	logger.Info("Starting CSIControllerService")
 	if err := csiControllerServer.RunServer(); err != nil && err != grpc.ErrServerStopped {
 		logger.Fatalf("fail to serve, error: %v", err)
 	}
 	logger.Info("Got SIGTERM signal")
 }
+
+// prepareEventRecorder creates EventRecorder which is used to report events about PVCs
+func prepareEventRecorder(logger *logrus.Logger) (*events.Recorder, error) {
+	k8SClientset, err := k8s.GetK8SClientset()
+	if err != nil {
+		return nil, fmt.Errorf("fail to create kubernetes client, error: %s", err)
+	}
+	scheme, err := k8s.PrepareScheme()
+	if err != nil {
+		return nil, fmt.Errorf("fail to prepare kubernetes scheme, error: %s", err)
+	}
+	opt := events.Options{Logger: logger.WithField("componentName", "Events")}
+	return events.New(componentName, "", k8SClientset.CoreV1().Events(""), scheme, opt)
+}
@@ -19,12 +19,18 @@ package main
 import (
 	"flag"
 	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
 
 	dmsetup "github.com/dell/csi-baremetal/cmd/drivemgr"
 	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/config"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ipmi"
 	"github.com/dell/csi-baremetal/pkg/base/rpc"
 	"github.com/dell/csi-baremetal/pkg/drivemgr/basemgr"
+	"github.com/dell/csi-baremetal/pkg/metrics"
 )
 
 var (
@@ -32,22 +38,75 @@ var (
 	logPath  = flag.String("logpath", "", "log path for DriveManager")
 	logLevel = flag.String("loglevel", base.InfoLevel,
 		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
+	firmwareTool = flag.String("firmwaretool", "",
+		"Vendor tool for drive firmware update invoked as '<tool> <device> <image>', update is disabled if empty")
+	discoveryWorkers = flag.Int("discoveryworkers", basemgr.DefaultProbeWorkers,
+		"Amount of drives which are probed in parallel during discovery")
+	discoveryTimeout = flag.Duration("discoverytimeout", basemgr.DefaultDiscoveryTimeout,
+		"Timeout of the whole discovery, drives list isn't returned if discovery isn't completed in time")
+	rescanInterval = flag.Duration("rescaninterval", basemgr.DefaultRescanInterval,
+		"Period after which SCSI drive is probed again even if its WWN, size and partitions aren't changed, "+
+			"drives are probed on each discovery if it is 0")
+	selCorrelation = flag.Bool("selcorrelation", false,
+		"Whether IPMI SEL records of drive bays and backplanes are read by ipmitool and reported for the drives or not")
+	metricsAddress = flag.String("metrics-address", "", "The TCP network address where the prometheus metrics endpoint will run"+
+		"(example: :8080 which corresponds to port 8080 on local host). The default is empty string, which means metrics endpoint is disabled.")
+	metricspath = flag.String("metrics-path", "/metrics", "The HTTP path where prometheus metrics will be exposed. Default is /metrics.")
+	configPath  = flag.String("config", "", "Path to the config file, flags which are set explicitly have precedence over it")
 )
 
 func main() {
 	flag.Parse()
 
+	if *configPath != "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			logrus.Fatalf("fail to load config: %v", err)
+		}
+		if err = cfg.ApplyToFlags(flag.CommandLine); err != nil {
+			logrus.Fatalf("fail to apply config: %v", err)
+		}
+	}
+
 	logger, err := base.InitLogger(*logPath, *logLevel)
 	if err != nil {
 		logger.Warnf("Can't set logger's output to %s. Using stdout instead.\n", *logPath)
 	}
 
+	if *configPath != "" {
+		cfgWatcher, err := config.NewWatcher(*configPath, logger)
+		if err != nil {
+			logger.Fatalf("fail to create config watcher: %v", err)
+		}
+		cfgWatcher.ApplyLogLevel(logger, flag.CommandLine)
+		go func() {
+			if err := cfgWatcher.Watch(make(chan struct{})); err != nil {
+				logger.Errorf("Config watcher failed with error: %v", err)
+			}
+		}()
+	}
+
 	// Server is insecure for now because credentials are nil
 	serverRunner := rpc.NewServerRunner(nil, *endpoint, false, logger)
 
 	e := command.NewExecutor(logger)
 
 	driveMgr := basemgr.New(e, logger)
+	driveMgr.SetFirmwareTool(*firmwareTool)
+	driveMgr.SetDiscoveryLimits(*discoveryWorkers, *discoveryTimeout)
+	driveMgr.SetRescanInterval(*rescanInterval)
+	if *selCorrelation {
+		driveMgr.SetIPMI(ipmi.NewIPMI(e))
+	}
+
+	if *metricsAddress != "" {
+		go func() {
+			http.Handle(*metricspath, metrics.Handler())
+			if err := http.ListenAndServe(*metricsAddress, nil); err != nil {
+				logger.Warnf("metric http returned: %s ", err)
+			}
+		}()
+	}
 
 	dmsetup.SetupAndRunDriveMgr(driveMgr, serverRunner, nil, logger)
 }
@@ -0,0 +1,120 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package for main function of drive manager doctor, it runs on the host and prints what basemgr
+// would discover without deploying the driver
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/drivemgr/basemgr"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var (
+	output   = flag.String("output", outputText, fmt.Sprintf("Output format, supported values are %s, %s", outputText, outputJSON))
+	logLevel = flag.String("loglevel", "",
+		fmt.Sprintf("Log level, support values are %s, %s, %s. Logs are disabled if empty",
+			base.InfoLevel, base.DebugLevel, base.TraceLevel))
+)
+
+func main() {
+	flag.Parse()
+
+	logger, _ := base.InitLogger("", *logLevel)
+	// logs are written to stderr to keep report readable
+	logger.SetOutput(os.Stderr)
+	if *logLevel == "" {
+		logger.SetLevel(logrus.PanicLevel)
+	}
+
+	report := basemgr.New(command.NewExecutor(logger), logger).Diagnose()
+
+	var err error
+	switch *output {
+	case outputJSON:
+		err = printJSON(report)
+	case outputText:
+		err = printText(report)
+	default:
+		err = fmt.Errorf("unsupported output format %s", *output)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	for _, device := range report.Devices {
+		if device.Included {
+			return
+		}
+	}
+	// there are no drives to report, it is the reason of empty Drive CRs
+	os.Exit(1)
+}
+
+func printJSON(report *basemgr.Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(string(data))
+	return err
+}
+
+func printText(report *basemgr.Report) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "TOOL\tAVAILABLE\tUSED FOR")
+	for _, tool := range report.Tools {
+		fmt.Fprintf(w, "%s\t%t\t%s\n", tool.Name, tool.Available, tool.Usage)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "DEVICE\tBUS\tINCLUDED\tSERIAL NUMBER\tTYPE\tHEALTH\tREASON")
+	for _, device := range report.Devices {
+		var sn, driveType, health string
+		if device.Drive != nil {
+			sn, driveType, health = device.Drive.SerialNumber, device.Drive.Type, device.Drive.Health
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\t%s\t%s\n",
+			device.Path, device.Bus, device.Included, sn, driveType, health, device.Reason)
+	}
+	fmt.Fprintln(w)
+
+	if len(report.Problems) == 0 {
+		fmt.Fprintln(w, "No problems found")
+	} else {
+		fmt.Fprintln(w, "PROBLEMS")
+		for _, problem := range report.Problems {
+			fmt.Fprintf(w, "- %s\n", problem)
+		}
+	}
+	return w.Flush()
+}
@@ -34,6 +34,7 @@ var (
 	logPath  = flag.String("logpath", "", "log path for DriveManager")
 	logLevel = flag.String("loglevel", base.InfoLevel,
 		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
+	idracIP = flag.String("idracip", "", "iDRAC IP, if it isn't set IP is detected with ipmitool")
 )
 
 func main() {
@@ -49,8 +50,14 @@ func main() {
 
 	e := command.NewExecutor(logger)
 
-	ipmiTool := ipmi.NewIPMI(e)
-	ip := ipmiTool.GetBmcIP()
+	ip := *idracIP
+	if ip == "" {
+		// ipmitool isn't available on some architectures, e.g. arm64
+		if !command.IsAvailable(ipmi.IpmitoolCmd) {
+			logger.Fatalf("%s isn't available, IDRAC IP should be provided with --idracip flag", ipmi.IpmitoolCmd)
+		}
+		ip = ipmi.NewIPMI(e).GetBmcIP()
+	}
 	if ip == "" {
 		logger.Fatal("IDRAC IP is not found")
 	}
@@ -37,9 +37,11 @@ import (
 )
 
 var (
-	endpoint = flag.String("drivemgrendpoint", base.DefaultDriveMgrEndpoint, "DriveManager Endpoint")
-	logPath  = flag.String("logpath", "", "log path for DriveManager")
-	logLevel = flag.String("loglevel", base.InfoLevel,
+	endpoint     = flag.String("drivemgrendpoint", base.DefaultDriveMgrEndpoint, "DriveManager Endpoint")
+	logPath      = flag.String("logpath", "", "log path for DriveManager")
+	kubeAPIQPS   = flag.Float64("kubeapiqps", k8s.DefaultQPS, "Average amount of k8s API calls per second")
+	kubeAPIBurst = flag.Int("kubeapiburst", k8s.DefaultBurst, "Amount of k8s API calls which could be done at once above QPS")
+	logLevel     = flag.String("loglevel", base.InfoLevel,
 		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
 	useNodeAnnotation = flag.Bool("usenodeannotation", false,
 		"Whether svc should read id from node annotation")
@@ -57,7 +59,7 @@ func main() {
 		logger.Warnf("Can't set logger's output to %s. Using stdout instead.\n", *logPath)
 	}
 
-	k8SClient, err := k8s.GetK8SClient()
+	k8SClient, err := k8s.GetK8SClient(k8s.RateLimits{QPS: float32(*kubeAPIQPS), Burst: *kubeAPIBurst})
 	if err != nil {
 		logger.Fatalf("fail to create kubernetes client, error: %v", err)
 	}
@@ -32,8 +32,11 @@ func SetupAndRunDriveMgr(d drivemgr.DriveManager, sr *rpc.ServerRunner, cleanupF
 	logger.Info("Start DriveManager")
 
 	driveServiceServer := drivemgr.NewDriveServer(logger, d)
+	driveServiceV2Server := drivemgr.NewDriveServerV2(logger, d)
 
+	// v1 API is served together with v2 for clients which weren't upgraded yet
 	api.RegisterDriveServiceServer(sr.GRPCServer, &driveServiceServer)
+	api.RegisterDriveServiceV2Server(sr.GRPCServer, &driveServiceV2Server)
 
 	handler := util.NewSignalHandler(logger)
 
@@ -25,17 +25,18 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -43,36 +44,59 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capabilities"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/config"
+	"github.com/dell/csi-baremetal/pkg/base/crashdump"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockqueue"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/base/rpc"
+	"github.com/dell/csi-baremetal/pkg/base/startup"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/crcontrollers/drive"
 	"github.com/dell/csi-baremetal/pkg/crcontrollers/lvg"
-	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	"github.com/dell/csi-baremetal/pkg/drivemgr"
 	"github.com/dell/csi-baremetal/pkg/events"
 	"github.com/dell/csi-baremetal/pkg/metrics"
 	"github.com/dell/csi-baremetal/pkg/node"
+	"github.com/dell/csi-baremetal/pkg/node/faults"
+	"github.com/dell/csi-baremetal/pkg/node/gateway"
+	"github.com/dell/csi-baremetal/pkg/node/preflight"
+	"github.com/dell/csi-baremetal/pkg/node/privhelper"
 )
 
 const (
 	componentName = "csi-baremetal-node"
+	// defaultDiscoveryInterval is used when discovery interval isn't configured
+	defaultDiscoveryInterval = 30 * time.Second
+	driveMgrNegotiateTimeout = 10 * time.Second
+	// operator registers new node and sets node ID annotation right after node joins the cluster
+	nodeIDWaitTimeout  = 5 * time.Minute
+	nodeIDWaitInterval = 5 * time.Second
+	// csiEndpointCheckInterval is interval between checks of CSI socket directory while it isn't writable
+	csiEndpointCheckInterval = 10 * time.Second
 )
 
 var (
 	namespace        = flag.String("namespace", "", "Namespace in which Node Service service run")
-	driveMgrEndpoint = flag.String("drivemgrendpoint", base.DefaultDriveMgrEndpoint, "Hardware Manager endpoint")
+	driveMgrEndpoint = flag.String("drivemgrendpoint", base.DefaultDriveMgrEndpoint, "Hardware Manager endpoints, comma separated")
 	healthIP         = flag.String("healthip", base.DefaultHealthIP, "Node health server ip")
 	csiEndpoint      = flag.String("csiendpoint", "unix:///tmp/csi.sock", "CSI endpoint")
-	nodeName         = flag.String("nodename", "", "node identification by k8s")
-	logPath          = flag.String("logpath", "", "Log path for Node Volume Manager service")
-	eventConfigPath  = flag.String("eventConfigPath", "/etc/config/alerts.yaml", "path for the events config file")
-	useACRs          = flag.Bool("extender", false,
+	csiSocketMode    = flag.String("csisocketmode", "", "Octal permissions of CSI unix socket (for example 0660), umask is applied if empty")
+	csiSocketOwner   = flag.String("csisocketowner", "",
+		"Owner of CSI unix socket in uid:gid format (for example 0:1000), owner of the process is kept if empty")
+	nodeName        = flag.String("nodename", "", "node identification by k8s")
+	logPath         = flag.String("logpath", "", "Log path for Node Volume Manager service")
+	eventConfigPath = flag.String("eventConfigPath", "/etc/config/alerts.yaml", "path for the events config file")
+	useACRs         = flag.Bool("extender", false,
 		"Whether node svc should read AvailableCapacityReservation CR during NodePublish request for ephemeral volumes or not")
 	useNodeAnnotation = flag.Bool("usenodeannotation", false,
 		"Whether node svc should read id from node annotation and use it as id for all CRs or not")
@@ -80,12 +104,106 @@ var (
 		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
 	metricsAddress = flag.String("metrics-address", "", "The TCP network address where the prometheus metrics endpoint will run"+
 		"(example: :8080 which corresponds to port 8080 on local host). The default is empty string, which means metrics endpoint is disabled.")
-	metricspath = flag.String("metrics-path", "/metrics", "The HTTP path where prometheus metrics will be exposed. Default is /metrics.")
+	metricspath        = flag.String("metrics-path", "/metrics", "The HTTP path where prometheus metrics will be exposed. Default is /metrics.")
+	configPath         = flag.String("config", "", "Path to the config file, flags which are set explicitly have precedence over it")
+	privHelperEndpoint = flag.String("privhelperendpoint", "",
+		"Endpoint of the privileged helper, if set mount, mkfs, partitioning and LVM operations are run by the helper")
+	volumeOperationsLimit = flag.Int("volumeoperationslimit", node.DefaultVolumeOperationsLimit,
+		"Amount of volumes which could be created or removed on the node simultaneously, excess volumes are queued")
+	integrityCheckInterval = flag.Duration("integritycheckinterval", node.DefaultIntegrityCheckInterval,
+		"Interval between integrity checks of the volumes with integrity protection")
+	onDiskCheckInterval = flag.Duration("ondiskcheckinterval", node.DefaultOnDiskCheckInterval,
+		"Interval between checks that partitions and logical volumes of the volumes exist on the node, "+
+			"volumes which allocation has vanished get OnDiskMissing condition")
+	preflightChecks = flag.Bool("preflight", true,
+		"Whether node svc should validate the node (system utils, kernel modules, udev and host paths) "+
+			"and stay not ready if validation failed, results are reported in the status of the Node CR")
+	preflightOnly = flag.Bool("preflightonly", false,
+		"Validate the node, report results in the status of the Node CR and exit. Non zero exit code means failed checks")
+	enduranceHysteresis = flag.Int("endurancehysteresis", node.DefaultEnduranceHysteresis,
+		"Minimal change of drive endurance in percents which is written into Drive CR, "+
+			"every change is exposed by drive_endurance_percent metric")
+	driveTemperatureThreshold = flag.Int("drivetemperaturethreshold", node.DefaultDriveTemperatureThreshold,
+		"Drive temperature in Celsius starting from which DriveTemperatureHigh event is raised, "+
+			"0 disables events, temperature is exposed by drive_temperature_celsius metric")
+	ioErrorCheckInterval = flag.Duration("ioerrorcheckinterval", node.DefaultIOErrorCheckInterval,
+		"Interval between checks of I/O error counters of the drives, counters are exposed by drive_io_errors metric")
+	imageSourceAllowlist = flag.String("imagesourceallowlist", "",
+		"Comma-separated image sources in scheme://host format which volumes could be populated from, "+
+			"any source is allowed if it is empty")
+	ioErrorThreshold = flag.Int("ioerrorthreshold", node.DefaultIOErrorThreshold,
+		"Number of I/O errors of the drive during the last hour starting from which drive gets IOErrors condition "+
+			"and DriveIOErrorsHigh event is raised, 0 disables condition and events")
+	remountReadOnlyOnShutdown = flag.Bool("remount-ro-on-shutdown", true,
+		"Whether node svc should flush and remount staged volumes read-only when it is stopped on graceful shutdown "+
+			"of the node reported by kubelet, so file systems are clean on the next boot")
+	faultInjection = flag.Bool("faultinjection", false,
+		"Inject failures set in "+faults.NodeAnnotation+" annotation of k8s Node, is used by chaos e2e tests only")
+	auditLog = flag.String("auditlog", "",
+		"Path of the file where format, wipe, partition and LV removal operations are recorded, stdout is used if empty")
+	selfTestPath = flag.String("selftest-path", "",
+		"The HTTP path on metrics address where POST request runs self-test of the node storage path "+
+			"(loop device is created, formatted, mounted and removed), self-test endpoint is disabled if empty")
+	gatewayPath = flag.String("gateway-path", "",
+		"The HTTP path prefix on metrics address where REST gateway exposes drives, capacity and volumes of the node "+
+			"and triggers discovery and locate LED, requests are authorized by k8s RBAC, gateway is disabled if empty")
+	versionPath = flag.String("version-path", "/version",
+		"The HTTP path on metrics address where build version, revision and API versions are exposed in JSON, "+
+			"version endpoint is disabled if empty")
+	topologyLabels = flag.String("topologylabels", "",
+		"Comma-separated node labels (for example rack or zone) which are reported as topology keys in addition to node ID")
+	operationTimeouts = flag.String("operationtimeouts", "",
+		"Comma-separated server side deadlines of CSI calls by method name (for example CreateVolume=5m), "+
+			"DeadlineExceeded is returned while the operation continues, deadline of the client reduced by 1s is used if not set")
+	crashDumps = flag.Bool("crashdumps", true,
+		"Whether stack traces of panics in CSI calls and reconcilers are stored in "+crashdump.ConfigMapPrefix+
+			"node-<node name> ConfigMap and in Panicked condition of the affected Volume CR or not")
+	kubeAPIQPS   = flag.Float64("kubeapiqps", k8s.DefaultQPS, "Average amount of k8s API calls per second")
+	kubeAPIBurst = flag.Int("kubeapiburst", k8s.DefaultBurst, "Amount of k8s API calls which could be done at once above QPS")
+	kubeletDir   = flag.String("kubelet-dir", base.DefaultKubeletDir,
+		"Root directory of kubelet on the node, should be set for distributions with non-standard location")
+	mountMode = flag.String("mountmode", node.MountModeAuto,
+		fmt.Sprintf("How mount operations are performed, support values are %s, %s, %s. "+
+			"In %s mode mount is run via nsenter in the host mount namespace if syscalls are filtered by seccomp, "+
+			"it requires hostPID, node service isn't ready without it",
+			node.MountModeAuto, node.MountModeDirect, node.MountModeNsenter, node.MountModeAuto))
+	fsMismatchPolicy = flag.String("fsmismatchpolicy", node.FSMismatchFail,
+		fmt.Sprintf("How volume which device holds file system of another type is staged, support values are %s, %s, %s. "+
+			"In %s mode file system is recreated only if %s annotation of the volume is set to volume ID",
+			node.FSMismatchFail, node.FSMismatchReuse, node.FSMismatchReformat,
+			node.FSMismatchReformat, apiV1.VolumeAnnotationEraseData))
+	readCacheLimit = flag.String("readcachelimit", "0",
+		"Total size of RAM read caches (readCache StorageClass parameter) of the volumes published on the node, "+
+			"for example 16Gi, 0 means no limit")
+	prewarmCommand = flag.String("prewarmcommand", "",
+		"Command which is run on publish of the volume which PVC has "+base.PVCAnnotationPrewarm+"=command annotation, "+
+			"target path of the volume is passed as the last argument")
+	ioScheduler = flag.String("ioscheduler", "",
+		"Comma-separated I/O schedulers of drives by drive type which are set on stage (for example HDD=mq-deadline,NVME=none), "+
+			"ioScheduler parameter of StorageClass takes precedence")
+	nrRequests = flag.String("nrrequests", "",
+		"Comma-separated nr_requests of drive queues by drive type which are set on stage (for example HDD=256), "+
+			"nrRequests parameter of StorageClass takes precedence")
+	readAheadKB = flag.String("readaheadkb", "",
+		"Comma-separated read_ahead_kb of drive queues by drive type which are set on stage (for example HDD=4096), "+
+			"readAheadKB parameter of StorageClass takes precedence")
+	startupTimeout = flag.Duration("startuptimeout", startup.DefaultTimeout,
+		"Time which node waits for k8s API and CRDs on start before exit")
 )
 
 func main() {
 	flag.Parse()
 
+	if *configPath != "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			logrus.Fatalf("fail to load config: %v", err)
+		}
+		if err = cfg.ApplyToFlags(flag.CommandLine); err != nil {
+			logrus.Fatalf("fail to apply config: %v", err)
+		}
+	}
+
 	featureConf := featureconfig.NewFeatureConfig()
 	featureConf.Update(featureconfig.FeatureACReservation, *useACRs)
 	featureConf.Update(featureconfig.FeatureNodeIDFromAnnotation, *useNodeAnnotation)
@@ -103,25 +221,99 @@ func main() {
 	logger.Info("Starting Node Service")
 
 	stopCH := ctrl.SetupSignalHandler()
+	// ctx is done on termination signal, long running operations use it to be interrupted
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCH
+		cancel()
+	}()
+
+	discoveryInterval := func() time.Duration { return defaultDiscoveryInterval }
+	if *configPath != "" {
+		cfgWatcher, err := config.NewWatcher(*configPath, logger)
+		if err != nil {
+			logger.Fatalf("fail to create config watcher: %v", err)
+		}
+		cfgWatcher.ApplyLogLevel(logger, flag.CommandLine)
+		discoveryInterval = func() time.Duration {
+			if interval := cfgWatcher.Get().Node.DiscoveryInterval; interval != 0 {
+				return interval
+			}
+			return defaultDiscoveryInterval
+		}
+		go func() {
+			if err := cfgWatcher.Watch(stopCH); err != nil {
+				logger.Errorf("Config watcher failed with error: %v", err)
+			}
+		}()
+	}
+
+	// gRPC clients for communication with DriveMgrs via TCP socket, drives from all of them are merged
+	clientToDriveMgr := drivemgr.NewRegistry(logger)
+	for _, endpoint := range strings.Split(*driveMgrEndpoint, ",") {
+		endpoint = strings.TrimSpace(endpoint)
+		gRPCClient, err := rpc.NewClient(nil, endpoint, enableMetrics, logger)
+		if err != nil {
+			logger.Fatalf("fail to create grpc client for endpoint %s, error: %v", endpoint, err)
+		}
+		if err := clientToDriveMgr.RegisterWithV2(endpoint, api.NewDriveServiceClient(gRPCClient.GRPCClient),
+			api.NewDriveServiceV2Client(gRPCClient.GRPCClient)); err != nil {
+			logger.Fatalf("fail to register drive manager: %v", err)
+		}
+	}
 
-	// gRPC client for communication with DriveMgr via TCP socket
-	gRPCClient, err := rpc.NewClient(nil, *driveMgrEndpoint, enableMetrics, logger)
+	// node waits for its dependencies instead of crash loop while they are starting together with it,
+	// all of them share one deadline, so CSI and health servers start before kubelet probes liveness
+	startupCtx, cancelStartup := context.WithTimeout(ctx, *startupTimeout)
+	restConfig := k8s.GetRestConfig(k8s.RateLimits{QPS: float32(*kubeAPIQPS), Burst: *kubeAPIBurst})
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
 	if err != nil {
-		logger.Fatalf("fail to create grpc client for endpoint %s, error: %v", *driveMgrEndpoint, err)
+		logger.Fatalf("fail to create kubernetes discovery client, error: %v", err)
+	}
+	kubeGate := startup.NewGate(*startupTimeout, logger).
+		Add("k8s API", startup.KubeAPIProbe(discoveryClient)).
+		Add("CSI Baremetal CRDs", startup.CRDProbe(discoveryClient, apiV1.APIV1Version,
+			apiV1.DriveKind, apiV1.AvailableCapacityKind, apiV1.VolumeKind, apiV1.LVGKind))
+	if err := kubeGate.Wait(startupCtx); err != nil {
+		logger.Fatalf("Node service dependencies aren't ready: %v", err)
+	}
+	cancelStartup()
+
+	// API version is negotiated once for version information, node doesn't wait for drive manager since
+	// discovery negotiates version with drive managers which weren't available and isn't ready until it succeeds
+	apiVersion := drivemgr.APIVersionV1
+	negotiateCtx, cancelNegotiate := context.WithTimeout(ctx, driveMgrNegotiateTimeout)
+	if version, err := drivemgr.NegotiateAPIVersion(negotiateCtx, clientToDriveMgr); err != nil {
+		logger.Warnf("Unable to negotiate API version with drive manager, v1 is reported: %v", err)
+	} else {
+		apiVersion = version
 	}
-	clientToDriveMgr := api.NewDriveServiceClient(gRPCClient.GRPCClient)
+	cancelNegotiate()
+	logger.Infof("Drive manager API version: v%d", apiVersion)
+	versionInfo := metrics.NewVersionInfo()
+	versionInfo.SetDriveMgrAPIVersion(apiVersion)
 
 	// gRPC server that will serve requests (node CSI) from k8s via unix socket
 	csiUDSServer := rpc.NewServerRunner(nil, *csiEndpoint, enableMetrics, logger)
+	socketMode, socketUID, socketGID, err := rpc.ParseSocketPermissions(*csiSocketMode, *csiSocketOwner)
+	if err != nil {
+		logger.Fatalf("fail to parse CSI socket permissions: %v", err)
+	}
+	csiUDSServer.SetSocketPermissions(socketMode, socketUID, socketGID)
+	timeouts, err := rpc.ParseOperationTimeouts(*operationTimeouts)
+	if err != nil {
+		logger.Fatalf("fail to parse operation timeouts: %v", err)
+	}
+	csiUDSServer.SetOperationTimeouts(timeouts)
 
-	k8SClient, err := k8s.GetK8SClient()
+	k8SClient, err := k8s.GetK8SClient(k8s.RateLimits{QPS: float32(*kubeAPIQPS), Burst: *kubeAPIBurst})
 	if err != nil {
 		logger.Fatalf("fail to create kubernetes client, error: %v", err)
 	}
 	wrappedK8SClient := k8s.NewKubeClient(k8SClient, logger, *namespace)
 
 	kubeCache, err := k8s.InitKubeCache(logger, stopCH,
-		&drivecrd.Drive{}, &accrd.AvailableCapacity{}, &volumecrd.Volume{})
+		&drivecrd.Drive{}, &accrd.AvailableCapacity{}, &volumecrd.Volume{}, &lvgcrd.LogicalVolumeGroup{})
 	if err != nil {
 		logger.Fatalf("fail to start kubeCache, error: %v", err)
 	}
@@ -138,14 +330,94 @@ func main() {
 	// Wait till all events are sent/handled
 	defer eventRecorder.Wait()
 
+	var (
+		executor     command.CmdExecutor = command.NewExecutor(logger)
+		readinessErr error
+	)
+	if *privHelperEndpoint != "" {
+		// gRPC client for communication with privileged helper via unix socket
+		helperClient, err := rpc.NewClient(nil, *privHelperEndpoint, false, logger)
+		if err != nil {
+			logger.Fatalf("fail to create grpc client for endpoint %s, error: %v", *privHelperEndpoint, err)
+		}
+		executor = privhelper.NewExecutor(api.NewPrivilegedHelperClient(helperClient.GRPCClient), executor, logger)
+	} else {
+		capStatus, err := capabilities.ReadStatus(capabilities.ProcSelfStatus)
+		if err != nil {
+			logger.Warnf("Unable to detect capabilities of the node container: %v", err)
+		} else if executor, readinessErr = node.PrepareMountExecutor(executor, *mountMode, capStatus, logger); readinessErr != nil {
+			logger.Errorf("Node service will not be ready: %v", readinessErr)
+		}
+	}
+
+	// mounts which are performed in the node container have to propagate to the host,
+	// helper and nsenter perform mounts outside of the container, so they have to be visible in it only
+	propagation := fs.PropagationShared
+	if _, nsenter := executor.(*command.NsenterExecutor); nsenter || *privHelperEndpoint != "" {
+		propagation = fs.PropagationSlave
+	}
+	var preflightErr error
+	if *preflightChecks || *preflightOnly {
+		preflightErr = validateNode(wrappedK8SClient, nodeID, *nodeName, *kubeletDir, propagation, logger)
+		if *preflightOnly {
+			if preflightErr != nil {
+				logger.Fatal(preflightErr)
+			}
+			logger.Info("Node pre-flight checks passed")
+			return
+		}
+	}
+
 	csiNodeService := node.NewCSINodeService(
-		clientToDriveMgr, nodeID, logger, wrappedK8SClient, kubeCache, eventRecorder, featureConf)
+		clientToDriveMgr, executor, nodeID, logger, wrappedK8SClient, kubeCache, eventRecorder, featureConf)
+	if readinessErr == nil && preflightErr != nil {
+		logger.Errorf("Node service will not be ready: %v", preflightErr)
+		readinessErr = preflightErr
+	}
+	csiNodeService.SetReadinessError(readinessErr)
+	csiNodeService.SetVersionInfo(versionInfo)
+	csiNodeService.SetVolumeOperationsLimit(*volumeOperationsLimit)
+	csiNodeService.SetKubeletDir(*kubeletDir)
+	csiNodeService.SetEnduranceHysteresis(*enduranceHysteresis)
+	csiNodeService.SetDriveTemperatureThreshold(*driveTemperatureThreshold)
+	csiNodeService.SetIOErrorThreshold(*ioErrorThreshold)
+	csiNodeService.SetTopologyLabels(k8s.ParseTopologyLabels(*topologyLabels))
+	if err = csiNodeService.SetFSMismatchPolicy(*fsMismatchPolicy); err != nil {
+		logger.Fatalf("Unable to set file system mismatch policy: %v", err)
+	}
+	if err = csiNodeService.SetReadCacheLimit(*readCacheLimit); err != nil {
+		logger.Fatalf("Unable to set read cache limit: %v", err)
+	}
+	csiNodeService.SetPrewarmCommand(*prewarmCommand)
+	ioTuning, err := blockqueue.ParseByDriveType(*ioScheduler, *nrRequests, *readAheadKB)
+	if err != nil {
+		logger.Fatalf("Unable to parse I/O tuning of drives: %v", err)
+	}
+	csiNodeService.SetIOTuning(ioTuning)
+	if *faultInjection {
+		logger.Warn("Fault injection is enabled")
+		csiNodeService.SetFaultInjector(faults.NewInjector(k8SClient, *nodeName, logger))
+	}
+	if *auditLog != "" {
+		if err = csiNodeService.SetAuditLogFile(*auditLog); err != nil {
+			logger.Fatalf("Unable to open audit log %s: %v", *auditLog, err)
+		}
+	}
+	if err = csiNodeService.SetImageSourceAllowlist(*imageSourceAllowlist); err != nil {
+		logger.Fatalf("Unable to set image source allowlist: %v", err)
+	}
+
+	lvgController := lvg.NewController(wrappedK8SClient, nodeID, eventRecorder, logger)
+	driveController := drive.NewController(wrappedK8SClient, nodeID, clientToDriveMgr, eventRecorder, logger)
+	if *crashDumps {
+		crashRecorder := crashdump.NewRecorder(wrappedK8SClient, "node-"+*nodeName, logger)
+		csiUDSServer.SetPanicHandler(crashRecorder.Record)
+		csiNodeService.SetCrashRecorder(crashRecorder)
+		lvgController.SetCrashRecorder(crashRecorder)
+		driveController.SetCrashRecorder(crashRecorder)
+	}
 
-	mgr := prepareCRDControllerManagers(
-		csiNodeService,
-		lvg.NewController(wrappedK8SClient, nodeID, logger),
-		drive.NewController(wrappedK8SClient, nodeID, clientToDriveMgr, eventRecorder, logger),
-		logger)
+	mgr := prepareCRDControllerManagers(csiNodeService, lvgController, driveController, logger)
 
 	// register CSI calls handler
 	csi.RegisterNodeServer(csiUDSServer.GRPCServer, csiNodeService)
@@ -157,10 +429,21 @@ func main() {
 		grpc_prometheus.Register(csiUDSServer.GRPCServer)
 		grpc_prometheus.EnableHandlingTimeHistogram()
 		grpc_prometheus.EnableClientHandlingTimeHistogram()
-		prometheus.MustRegister(metrics.BuildInfo)
+		prometheus.MustRegister(versionInfo.Collector())
 
 		go func() {
-			http.Handle(*metricspath, promhttp.Handler())
+			http.Handle(*metricspath, metrics.Handler())
+			if *versionPath != "" {
+				http.Handle(*versionPath, versionInfo.Handler())
+			}
+			if *selfTestPath != "" {
+				http.Handle(*selfTestPath, csiNodeService.SelfTestHandler())
+			}
+			if *gatewayPath != "" {
+				prefix := strings.TrimSuffix(*gatewayPath, "/") + "/"
+				restGateway := gateway.NewGateway(wrappedK8SClient, clientToDriveMgr, csiNodeService, nodeID, logger)
+				http.Handle(prefix, restGateway.Handler(prefix))
+			}
 			if err := http.ListenAndServe(*metricsAddress, nil); err != nil {
 				logger.Warnf("metric http returned: %s ", err)
 			}
@@ -174,39 +457,73 @@ func main() {
 			logger.Fatalf("Node service failed with error: %v", err)
 		}
 	}()
+	// operations interrupted by crash are finished or reverted before volumes are reconciled
+	if err := csiNodeService.RecoverOperations(ctx); err != nil {
+		logger.Errorf("Recovery of interrupted operations failed: %v", err)
+	}
 	go func() {
 		logger.Info("Starting CRD Controller Manager ...")
 		if err := mgr.Start(stopCH); err != nil {
 			logger.Fatalf("CRD Controller Manager failed with error: %v", err)
 		}
 	}()
-	go Discovering(csiNodeService, logger)
+	go csiNodeService.RunDiscovery(ctx, discoveryInterval)
+	go csiNodeService.RunIntegrityCheck(ctx, *integrityCheckInterval)
+	go csiNodeService.RunOnDiskCheck(ctx, *onDiskCheckInterval)
+	go csiNodeService.RunIOErrorCheck(ctx, *ioErrorCheckInterval)
+
+	// volumes aren't mounted after node reboot, they are staged again before kubelet publishes them
+	if readinessErr == nil {
+		if err := csiNodeService.RestageVolumes(ctx); err != nil {
+			logger.Errorf("Restage of volumes failed: %v", err)
+		}
+	}
 
+	waitCSIEndpoint(csiUDSServer, csiNodeService, readinessErr, logger)
 	logger.Info("Starting handle CSI calls ...")
 	if err := csiUDSServer.RunServer(); err != nil && err != grpc.ErrServerStopped {
 		logger.Fatalf("fail to serve: %v", err)
 	}
 
 	logger.Info("Got SIGTERM signal")
+	if *remountReadOnlyOnShutdown {
+		// ctx is already done, volumes are remounted in the rest of the termination grace period
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), node.ShutdownHookTimeout)
+		defer cancelShutdown()
+		if err := csiNodeService.RemountReadOnlyOnShutdown(shutdownCtx, *nodeName); err != nil {
+			logger.Errorf("Unable to remount volumes read-only on shutdown: %v", err)
+		}
+	}
 }
 
-// Discovering performs Discover method of the Node each 30 seconds
-func Discovering(c *node.CSINodeService, logger *logrus.Logger) {
-	var err error
-	discoveringWaitTime := 10 * time.Second
-	checker := c.GetLivenessHelper()
-	for {
-		time.Sleep(discoveringWaitTime)
-		if err = c.Discover(); err != nil {
-			checker.Fail()
-			logger.Errorf("Discover finished with error: %v", err)
-		} else {
-			checker.OK()
-			logger.Tracef("Discover finished successful")
-			// Increase wait time, because we don't need to call API often after node initialization
-			discoveringWaitTime = 30 * time.Second
-		}
+// waitCSIEndpoint reports node svc as not ready until CSI socket could be created,
+// so node pod isn't restarted in a loop while directory of the socket isn't writable
+func waitCSIEndpoint(server *rpc.ServerRunner, c *node.CSINodeService, readinessErr error, logger *logrus.Logger) {
+	err := server.CheckEndpoint()
+	if err == nil {
+		return
+	}
+	for ; err != nil; err = server.CheckEndpoint() {
+		logger.Errorf("Node service will not be ready: %v", err)
+		c.SetReadinessError(err)
+		time.Sleep(csiEndpointCheckInterval)
+	}
+	// restore reason which was set before the check
+	c.SetReadinessError(readinessErr)
+}
+
+// validateNode runs pre-flight checks of the node and reports results in the status of the Node CR,
+// failure of the report doesn't affect result of the validation
+// Returns error if any of the checks failed
+func validateNode(client *k8s.KubeClient, nodeID, nodeName, kubeletDir string,
+	propagation fs.MountPropagation, logger *logrus.Logger) error {
+	validator := preflight.NewValidator(kubeletDir, logger)
+	validator.SetMountPropagation(propagation)
+	checks, err := validator.Validate()
+	if reportErr := preflight.Report(context.Background(), client, nodeID, nodeName, checks); reportErr != nil {
+		logger.Warnf("Unable to report results of the pre-flight checks: %v", reportErr)
 	}
+	return err
 }
 
 // prepareCRDControllerManagers prepares CRD ControllerManagers to work with CSI custom resources
@@ -235,7 +552,7 @@ func prepareCRDControllerManagers(volumeCtrl *node.CSINodeService, lvgCtrl *lvg.
 		logrus.Fatal(err)
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	mgr, err := ctrl.NewManager(k8s.GetRestConfig(k8s.RateLimits{QPS: float32(*kubeAPIQPS), Burst: *kubeAPIBurst}), ctrl.Options{
 		Scheme: scheme,
 	})
 	if err != nil {
@@ -259,19 +576,18 @@ func prepareCRDControllerManagers(volumeCtrl *node.CSINodeService, lvgCtrl *lvg.
 	return mgr
 }
 
-func getNodeID(client k8sClient.Client, nodeName string, featureChecker featureconfig.FeatureChecker) (string, error) {
+func getNodeID(client *k8s.KubeClient, nodeName string, featureChecker featureconfig.FeatureChecker) (string, error) {
+	if featureChecker.IsEnabled(featureconfig.FeatureNodeIDFromAnnotation) {
+		// wait for registration of the new node instead of restarts
+		ctx, cancel := context.WithTimeout(context.Background(), nodeIDWaitTimeout)
+		defer cancel()
+		return client.WaitNodeIDAnnotation(ctx, nodeName, nodeIDWaitInterval)
+	}
+
 	k8sNode := corev1.Node{}
 	if err := client.Get(context.Background(), k8sClient.ObjectKey{Name: nodeName}, &k8sNode); err != nil {
 		return "", err
 	}
-
-	if featureChecker.IsEnabled(featureconfig.FeatureNodeIDFromAnnotation) {
-		if val, ok := k8sNode.GetAnnotations()[csibmnodeconst.NodeIDAnnotationKey]; ok {
-			return val, nil
-		}
-		return "", fmt.Errorf("annotation %s hadn't been set for node %s", csibmnodeconst.NodeIDAnnotationKey, nodeName)
-	}
-
 	return string(k8sNode.UID), nil
 }
 
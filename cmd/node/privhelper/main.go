@@ -0,0 +1,72 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package for main function of the privileged helper of the Node service
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/rpc"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/node/privhelper"
+)
+
+var (
+	endpoint    = flag.String("endpoint", base.DefaultPrivHelperEndpoint, "Privileged helper endpoint")
+	socketMode  = flag.String("socketmode", "", "Octal permissions of unix socket (for example 0600), umask is applied if empty")
+	socketOwner = flag.String("socketowner", "",
+		"Owner of unix socket in uid:gid format (for example 0:1000), owner of the process is kept if empty")
+	kubeletDir = flag.String("kubelet-dir", base.DefaultKubeletDir,
+		"Root directory of kubelet, directories of the privileged operations have to be under it")
+	logPath  = flag.String("logpath", "", "Log path for privileged helper")
+	logLevel = flag.String("loglevel", base.InfoLevel,
+		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
+)
+
+func main() {
+	flag.Parse()
+
+	logger, err := base.InitLogger(*logPath, *logLevel)
+	if err != nil {
+		logger.Warnf("Can't set logger's output to %s. Using stdout instead.\n", *logPath)
+	}
+
+	logger.Info("Starting privileged helper")
+
+	// Server is insecure because it is available only via unix socket shared with the node container
+	serverRunner := rpc.NewServerRunner(nil, *endpoint, false, logger)
+	mode, uid, gid, err := rpc.ParseSocketPermissions(*socketMode, *socketOwner)
+	if err != nil {
+		logger.Fatalf("Failed to parse socket permissions: %v", err)
+	}
+	serverRunner.SetSocketPermissions(mode, uid, gid)
+	api.RegisterPrivilegedHelperServer(serverRunner.GRPCServer,
+		privhelper.NewServer(command.NewExecutor(logger), *kubeletDir, logger))
+
+	handler := util.NewSignalHandler(logger)
+	go handler.SetupSIGTERMHandler(serverRunner)
+
+	if err := serverRunner.RunServer(); err != nil && err != grpc.ErrServerStopped {
+		logger.Fatalf("Failed to serve on %s. Error: %v", *endpoint, err)
+	}
+}
@@ -18,19 +18,28 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/sirupsen/logrus"
+	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	nodecrd "github.com/dell/csi-baremetal/api/v1/nodecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
-	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/crcontrollers/operator"
+	"github.com/dell/csi-baremetal/pkg/crcontrollers/operator/crds"
+	"github.com/dell/csi-baremetal/pkg/crcontrollers/operator/csidriver"
+	"github.com/dell/csi-baremetal/pkg/render"
 )
 
 var (
@@ -39,16 +48,38 @@ var (
 	version      = flag.String("version", "", "CSI version to deploy charts")
 	drivemgr     = flag.String("drivemgr", "basemgr", "CSI drive manager type used in charts")
 	deploy       = flag.Bool("deploy", false, "Deploy indicates if csi-operator should deploy charts. False by default")
+	chartPath    = flag.String("chart", defaultChartPath, "Path to csi-baremetal-driver chart which is deployed")
+	crdsDirs     = flag.String("crds", "", "Comma-separated directories with CRD manifests which operator installs "+
+		"and upgrades on start, CRDs aren't managed if empty")
+	manageCSIDriver = flag.Bool("csidriver", false, "Operator creates and maintains CSIDriver object of the driver, "+
+		"CSIDriver of the deployed chart is disabled")
+	attacher      = flag.Bool("attacher", false, "External attacher is deployed with controller in attach compatibility mode")
+	ephemeral     = flag.Bool("ephemeral", true, "Inline ephemeral volumes are allowed, sets volumeLifecycleModes of CSIDriver")
+	fsGroupPolicy = flag.String("fsgrouppolicy", csidriver.FSGroupPolicyFile,
+		fmt.Sprintf("fsGroupPolicy of CSIDriver, supported values are %s, %s, %s", csidriver.FSGroupPolicyNone,
+			csidriver.FSGroupPolicyFile, csidriver.FSGroupPolicyReadWriteOnceWithFSType))
+	kubeAPIQPS   = flag.Float64("kubeapiqps", k8s.DefaultQPS, "Average amount of k8s API calls per second")
+	kubeAPIBurst = flag.Int("kubeapiburst", k8s.DefaultBurst, "Amount of k8s API calls which could be done at once above QPS")
 	logLevel     = flag.String("loglevel", base.InfoLevel,
 		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
 	logFormat = flag.String("logformat", base.LogFormatText,
 		fmt.Sprintf("Log level, supported value is %s. Json format is used by default", base.LogFormatText))
 )
 
-// HelmInstallCSICmdTmpl is a template for helm command
-const HelmInstallCSICmdTmpl = "helm install csi-baremetal /csi-baremetal-driver --set image.tag=%s --set drivemgr.type=%s"
+// defaultChartPath is the path of csi-baremetal-driver chart in operator image
+const defaultChartPath = "/csi-baremetal-driver"
+
+// renderCmd is the subcommand which prints manifests of the driver instead of starting operator
+const renderCmd = "render"
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == renderCmd {
+		if err := runRender(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	flag.Parse()
 
 	// TODO: refactor this after https://github.com/dell/csi-baremetal/issues/83 will be closed
@@ -62,15 +93,26 @@ func main() {
 		fmt.Println("Unable to initialize logger")
 		os.Exit(1)
 	}
+	if *crdsDirs != "" {
+		if err = installCRDs(logger); err != nil {
+			logger.Fatalf("Unable to install CRDs: %v", err)
+		}
+	}
+
+	var csiDriverMgr *csidriver.Manager
+	if *manageCSIDriver {
+		if csiDriverMgr, err = ensureCSIDriver(logger); err != nil {
+			logger.Fatalf("Unable to maintain CSIDriver: %v", err)
+		}
+	}
+
 	if *deploy && *version != "" {
-		executor := command.NewExecutor(logger)
-		cmd := fmt.Sprintf(HelmInstallCSICmdTmpl, *version, *drivemgr)
-		if _, _, err = executor.RunCmd(cmd); err != nil {
-			logger.Fatal("Failed to install CSI charts")
+		if err = deployDriver(logger); err != nil {
+			logger.Fatalf("Failed to deploy CSI driver: %v", err)
 		}
 	}
 
-	k8sClient, err := k8s.GetK8SClient()
+	k8sClient, err := k8s.GetK8SClient(k8s.RateLimits{QPS: float32(*kubeAPIQPS), Burst: *kubeAPIBurst})
 	if err != nil {
 		logger.Fatalf("Unable to create k8s client: %v", err)
 	}
@@ -90,6 +132,12 @@ func main() {
 	if err = nodeCtrl.SetupWithManager(mgr); err != nil {
 		logger.Fatal(err)
 	}
+	// CSIDriver object is corrected if it is edited or deleted later
+	if csiDriverMgr != nil {
+		if err = mgr.Add(csiDriverMgr); err != nil {
+			logger.Fatal(err)
+		}
+	}
 
 	logger.Info("Starting Node Controller Manager ...")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -97,6 +145,125 @@ func main() {
 	}
 }
 
+// installCRDs creates or upgrades CRDs from manifests in crdsDirs and migrates objects stored in removed versions
+func installCRDs(logger *logrus.Logger) error {
+	scheme, err := k8s.PrepareScheme()
+	if err != nil {
+		return err
+	}
+	if err = apiextv1beta1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	client, err := k8sCl.New(k8s.GetRestConfig(k8s.RateLimits{QPS: float32(*kubeAPIQPS), Burst: *kubeAPIBurst}),
+		k8sCl.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	var manifests []*apiextv1beta1.CustomResourceDefinition
+	for _, dir := range strings.Split(*crdsDirs, ",") {
+		dirManifests, err := crds.LoadDir(strings.TrimSpace(dir))
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, dirManifests...)
+	}
+	return crds.NewInstaller(client, logger).Install(context.Background(), manifests)
+}
+
+// ensureCSIDriver creates or recreates CSIDriver object with fields derived from features of the driver
+// Returns manager of CSIDriver object which maintains it afterwards or error if object wasn't ensured
+func ensureCSIDriver(logger *logrus.Logger) (*csidriver.Manager, error) {
+	client, err := k8s.GetK8SClient(k8s.RateLimits{QPS: float32(*kubeAPIQPS), Burst: *kubeAPIBurst})
+	if err != nil {
+		return nil, err
+	}
+	features := csidriver.Features{
+		Attacher:         *attacher,
+		EphemeralVolumes: *ephemeral,
+		FSGroupPolicy:    *fsGroupPolicy,
+	}
+	csiDriverMgr := csidriver.NewManager(client, features, logger)
+	return csiDriverMgr, csiDriverMgr.Ensure(context.Background())
+}
+
+// deployDriver renders manifests of the driver chart and applies them, CRDs are managed by installCRDs
+// and CSIDriver object is managed by ensureCSIDriver if it is enabled
+func deployDriver(logger *logrus.Logger) error {
+	var manifests bytes.Buffer
+	cfg := render.Config{
+		ChartPath: *chartPath,
+		Namespace: *namespace,
+		Tag:       *version,
+		DriveMgr:  *drivemgr,
+	}
+	if *manageCSIDriver {
+		// attacher is deployed only in attach compatibility mode
+		cfg.Set = map[string]string{
+			"csiDriver.create": "false",
+			"attacher.deploy":  strconv.FormatBool(*attacher),
+		}
+	}
+	if err := render.Render(cfg, &manifests); err != nil {
+		return err
+	}
+	objs, err := render.Objects(manifests.Bytes())
+	if err != nil {
+		return err
+	}
+	client, err := k8s.GetK8SClient(k8s.RateLimits{QPS: float32(*kubeAPIQPS), Burst: *kubeAPIBurst})
+	if err != nil {
+		return err
+	}
+	return render.Apply(context.Background(), client, objs, logger)
+}
+
+// repeatedFlag collects values of flag which could be passed several times
+type repeatedFlag []string
+
+func (f *repeatedFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *repeatedFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// runRender prints manifests of the driver chart to stdout, args are flags of render subcommand
+func runRender(args []string) error {
+	var (
+		cfg         render.Config
+		valuesFiles repeatedFlag
+		set         repeatedFlag
+		fs          = flag.NewFlagSet(renderCmd, flag.ContinueOnError)
+	)
+	fs.StringVar(&cfg.ChartPath, "chart", defaultChartPath, "Path to csi-baremetal-driver chart")
+	fs.StringVar(&cfg.ReleaseName, "name", render.DefaultReleaseName, "Release name used in manifests")
+	fs.StringVar(&cfg.Namespace, "namespace", render.DefaultNamespace, "Namespace of rendered objects")
+	fs.StringVar(&cfg.Registry, "registry", "", "Registry of images, value of chart is used if empty")
+	fs.StringVar(&cfg.Tag, "version", "", "Tag of images, value of chart is used if empty")
+	fs.StringVar(&cfg.DriveMgr, "drivemgr", "", "Drive manager type, value of chart is used if empty")
+	fs.BoolVar(&cfg.IncludeCRDs, "crds", false, "Render CRDs of the chart before other manifests")
+	fs.Var(&valuesFiles, "values", "YAML file with values of the chart, could be repeated")
+	fs.Var(&set, "set", "Value of the chart in path=value format, for example node.mountMode=bidirectional, "+
+		"could be repeated")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg.ValuesFiles = valuesFiles
+	cfg.Set = make(map[string]string, len(set))
+	for _, pair := range set {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("value %s isn't in path=value format", pair)
+		}
+		cfg.Set[kv[0]] = kv[1]
+	}
+	return render.Render(cfg, os.Stdout)
+}
+
 func prepareK8sRuntimeManager() (ctrl.Manager, error) {
 	var (
 		scheme = runtime.NewScheme()
@@ -112,7 +279,7 @@ func prepareK8sRuntimeManager() (ctrl.Manager, error) {
 		return nil, err
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	mgr, err := ctrl.NewManager(k8s.GetRestConfig(k8s.RateLimits{QPS: float32(*kubeAPIQPS), Burst: *kubeAPIBurst}), ctrl.Options{
 		Scheme:    scheme,
 		Namespace: *namespace,
 	})
@@ -28,10 +28,11 @@ import (
 
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/metrics"
 	"github.com/dell/csi-baremetal/pkg/scheduler/extender"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var (
@@ -43,9 +44,15 @@ var (
 	logLevel          = flag.String("loglevel", base.InfoLevel, "Log level")
 	useNodeAnnotation = flag.Bool("usenodeannotation", false,
 		"Whether extender should read id from node annotation and use it as id for all CRs or not")
+	kubeAPIQPS     = flag.Float64("kubeapiqps", k8s.DefaultQPS, "Average amount of k8s API calls per second")
+	kubeAPIBurst   = flag.Int("kubeapiburst", k8s.DefaultBurst, "Amount of k8s API calls which could be done at once above QPS")
 	metricsAddress = flag.String("metrics-address", "", "The TCP network address where the prometheus metrics endpoint will run"+
 		"(example: :8080 which corresponds to port 8080 on local host). The default is empty string, which means metrics endpoint is disabled.")
-	metricspath = flag.String("metrics-path", "/metrics", "The HTTP path where prometheus metrics will be exposed. Default is /metrics.")
+	metricspath       = flag.String("metrics-path", "/metrics", "The HTTP path where prometheus metrics will be exposed. Default is /metrics.")
+	placementStrategy = flag.String("placementstrategy", capacityplanner.BestFitStrategy,
+		fmt.Sprintf("Strategy of AvailableCapacity selection for volume on node, supported values are %s, %s, %s, %s, %s",
+			capacityplanner.BestFitStrategy, capacityplanner.WorstFitStrategy, capacityplanner.SpreadStrategy,
+			capacityplanner.RoundRobinStrategy, capacityplanner.WearAwareStrategy))
 )
 
 // TODO should be passed as parameters https://github.com/dell/csi-baremetal/issues/78
@@ -53,6 +60,7 @@ const (
 	FilterPattern     string = "/filter"
 	PrioritizePattern string = "/prioritize"
 	BindPattern       string = "/bind"
+	DebugPattern      string = "/debug"
 )
 
 func main() {
@@ -64,7 +72,7 @@ func main() {
 
 	if *metricspath != "" {
 		go func() {
-			http.Handle(*metricspath, promhttp.Handler())
+			http.Handle(*metricspath, metrics.Handler())
 			if err := http.ListenAndServe(*metricsAddress, nil); err != nil {
 				logger.Warnf("metric http returned: %s ", err)
 			}
@@ -74,7 +82,7 @@ func main() {
 	featureConf := featureconfig.NewFeatureConfig()
 	featureConf.Update(featureconfig.FeatureNodeIDFromAnnotation, *useNodeAnnotation)
 
-	k8sClient, err := k8s.GetK8SClient()
+	k8sClient, err := k8s.GetK8SClient(k8s.RateLimits{QPS: float32(*kubeAPIQPS), Burst: *kubeAPIBurst})
 	if err != nil {
 		logger.Fatal(err)
 	}
@@ -93,6 +101,12 @@ func main() {
 	if err != nil {
 		logger.Fatalf("Fail to create extender: %v", err)
 	}
+	strategy, err := capacityplanner.NewPlacementStrategy(*placementStrategy, kubeClient,
+		logger.WithField("component", "PlacementStrategy"))
+	if err != nil {
+		logger.Fatalf("Fail to create placement strategy: %v", err)
+	}
+	newExtender.SetPlacementStrategy(strategy)
 
 	logger.Infof("Starting extender on port %d ...", *port)
 	// filter stage
@@ -107,6 +121,10 @@ func main() {
 	logger.Infof("Registering for bind stage ... ")
 	http.HandleFunc(BindPattern, newExtender.BindHandler)
 
+	// explanation of filter results for troubleshooting
+	logger.Infof("Registering debug endpoint ... ")
+	http.HandleFunc(DebugPattern, newExtender.DebugHandler)
+
 	var addr = fmt.Sprintf(":%d", *port)
 	if *certFile != "" && *privateKeyFile != "" {
 		logger.Info("Handle with TLS")
@@ -16,7 +16,7 @@ require (
 	github.com/onsi/gomega v1.7.1
 	github.com/prometheus/client_golang v0.9.2
 	github.com/sirupsen/logrus v1.4.2
-	github.com/stretchr/testify v1.4.0
+	github.com/stretchr/testify v1.6.1
 	golang.org/x/crypto v0.0.0-20191011191535-87dc89f01550 // indirect
 	golang.org/x/net v0.0.0-20191209160850-c0dbc17a3553
 	golang.org/x/time v0.0.0-20190308202827-9d24e82272b4 // indirect
@@ -24,10 +24,12 @@ require (
 	gopkg.in/yaml.v2 v2.2.5
 	gotest.tools v2.2.0+incompatible
 	k8s.io/api v1.16.4
+	k8s.io/apiextensions-apiserver v0.16.4
 	k8s.io/apimachinery v0.16.4
 	k8s.io/client-go v1.16.4
 	k8s.io/kubernetes v1.16.4
-	k8s.io/utils v0.0.0-20190801114015-581e00157fb1
+	k8s.io/mount-utils v0.20.0
+	k8s.io/utils v0.0.0-20201110183641-67b214c5f920
 	sigs.k8s.io/controller-runtime v0.4.0
 	sigs.k8s.io/yaml v1.1.0
 )
@@ -46,6 +48,8 @@ replace (
 	k8s.io/component-base => k8s.io/component-base v0.16.4
 	k8s.io/cri-api => k8s.io/cri-api v0.16.4
 	k8s.io/csi-translation-lib => k8s.io/csi-translation-lib v0.16.4
+	// klog/v2 which is required by mount-utils uses logr of controller-runtime
+	k8s.io/klog/v2 => k8s.io/klog/v2 v2.0.0
 	k8s.io/kube-aggregator => k8s.io/kube-aggregator v0.16.4
 	k8s.io/kube-controller-manager => k8s.io/kube-controller-manager v0.16.4
 	k8s.io/kube-proxy => k8s.io/kube-proxy v0.16.4
@@ -0,0 +1,87 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit contains structured log of destructive operations with drives
+package audit
+
+import (
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Destructive operations
+const (
+	OperationFormat          = "format"
+	OperationWipe            = "wipe"
+	OperationPartitionDelete = "partition-delete"
+	OperationLVRemove        = "lv-remove"
+)
+
+// Outcomes of operations
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Logger writes records about destructive operations as JSON lines into dedicated stream,
+// it is separated from the component log to be kept for compliance review after data-loss incidents
+type Logger struct {
+	log *logrus.Logger
+}
+
+// NewLogger is the constructor for Logger, records are written to stdout until SetOutputFile is called
+func NewLogger() *Logger {
+	log := logrus.New()
+	log.SetOutput(os.Stdout)
+	log.SetFormatter(&logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano})
+	return &Logger{log: log}
+}
+
+// SetOutputFile makes Logger append records to file
+// Receives path of the file, file is created if it doesn't exist
+// Returns error if file could not be opened
+func (l *Logger) SetOutputFile(path string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+	l.log.SetOutput(file)
+	return nil
+}
+
+// Record writes record about destructive operation, nothing is written by nil Logger
+// Receives operation, ID of request (volume ID) which initiated it, target device, serial number of the drive
+// and error which operation finished with
+func (l *Logger) Record(operation, requestID, device, serial string, err error) {
+	if l == nil {
+		return
+	}
+	entry := l.log.WithFields(logrus.Fields{
+		"audit":     true,
+		"operation": operation,
+		"requestID": requestID,
+		"device":    device,
+		"serial":    serial,
+	})
+	if err != nil {
+		entry.WithFields(logrus.Fields{"outcome": OutcomeFailure, "error": err.Error()}).
+			Warnf("%s of %s failed", operation, device)
+		return
+	}
+	entry.WithField("outcome", OutcomeSuccess).Infof("%s of %s", operation, device)
+}
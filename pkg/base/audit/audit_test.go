@@ -0,0 +1,65 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_Record(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "audit.log")
+
+	logger := NewLogger()
+	assert.Nil(t, logger.SetOutputFile(path))
+	logger.Record(OperationWipe, "pvc-1", "/dev/sda", "sn-1", nil)
+	logger.Record(OperationPartitionDelete, "pvc-1", "/dev/sda", "sn-1", errors.New("device is busy"))
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 2)
+
+	records := make([]map[string]interface{}, len(lines))
+	for i, line := range lines {
+		assert.Nil(t, json.Unmarshal([]byte(line), &records[i]))
+		assert.NotEmpty(t, records[i]["time"])
+		assert.Equal(t, "pvc-1", records[i]["requestID"])
+		assert.Equal(t, "/dev/sda", records[i]["device"])
+		assert.Equal(t, "sn-1", records[i]["serial"])
+	}
+	assert.Equal(t, OperationWipe, records[0]["operation"])
+	assert.Equal(t, OutcomeSuccess, records[0]["outcome"])
+	assert.Equal(t, OperationPartitionDelete, records[1]["operation"])
+	assert.Equal(t, OutcomeFailure, records[1]["outcome"])
+	assert.Equal(t, "device is busy", records[1]["error"])
+
+	// nil logger is no-op
+	var nilLogger *Logger
+	nilLogger.Record(OperationFormat, "pvc-1", "/dev/sda", "sn-1", nil)
+	assert.NotNil(t, logger.SetOutputFile(filepath.Join(dir, "absent", "audit.log")))
+}
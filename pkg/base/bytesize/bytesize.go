@@ -0,0 +1,135 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bytesize contains helpers for sizes in bytes which are used in capacity accounting: parsing and formatting
+// of sizes with decimal and binary units and overflow safe alignment, all sizes are int64 bytes
+package bytesize
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Decimal units, they are used by lsscsi and by Kubernetes quantities without "i" (e.g. 100G)
+const (
+	KB int64 = 1000
+	MB       = 1000 * KB
+	GB       = 1000 * MB
+	TB       = 1000 * GB
+	PB       = 1000 * TB
+)
+
+// Binary units, they are used by LVM, df and by Kubernetes quantities with "i" (e.g. 100Gi)
+const (
+	KiB int64 = 1 << 10
+	MiB       = KiB << 10
+	GiB       = MiB << 10
+	TiB       = GiB << 10
+	PiB       = TiB << 10
+)
+
+// sizeFmt matches number with optional fraction and optional unit, e.g. "32.3GB", "101Mi", "1024"
+var sizeFmt = regexp.MustCompile(`(\d+)(?:\.(\d+))?\s*([A-Za-z][A-Za-z0-9]*)?`)
+
+// units maps lower case unit to its size in bytes, single letter units are binary as in output of LVM and df,
+// units with "b" suffix are decimal as in output of lsscsi
+var units = map[string]int64{
+	"": 1, "b": 1,
+	"k": KiB, "ki": KiB, "kib": KiB, "kb": KB, "e3": KB,
+	"m": MiB, "mi": MiB, "mib": MiB, "mb": MB, "e6": MB,
+	"g": GiB, "gi": GiB, "gib": GiB, "gb": GB, "e9": GB,
+	"t": TiB, "ti": TiB, "tib": TiB, "tb": TB, "e12": TB,
+	"p": PiB, "pi": PiB, "pib": PiB, "pb": PB, "e15": PB,
+}
+
+// binaryUnits are used for formatting, from the largest one
+var binaryUnits = []struct {
+	name string
+	size int64
+}{{"PiB", PiB}, {"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB}}
+
+// Parse returns size in bytes of the first value found in the string, e.g. "32.3GB" -> 32300000000,
+// "1.5Gi" -> 1610612736, "Disk has 5 gb" -> 5000000000, value without unit is treated as bytes
+// Fraction is converted with integer math, fractional bytes are truncated
+// Returns error if value isn't found, has unknown unit or doesn't fit into int64
+func Parse(str string) (int64, error) {
+	match := sizeFmt.FindStringSubmatch(str)
+	if match == nil {
+		return 0, fmt.Errorf("unparseable size definition: %v", str)
+	}
+	unit, ok := units[strings.ToLower(match[3])]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %v in supplied value %v", match[3], str)
+	}
+	whole, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil || whole > math.MaxInt64/unit {
+		return 0, fmt.Errorf("size %v exceeds %d bytes", str, int64(math.MaxInt64))
+	}
+	size := whole * unit
+	if fraction := strings.TrimRight(match[2], "0"); fraction != "" {
+		// fraction of the unit is always less than the unit, but fraction * unit could overflow int64
+		numerator, _ := new(big.Int).SetString(fraction, 10)
+		denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(len(fraction))), nil)
+		part := numerator.Mul(numerator, big.NewInt(unit)).Quo(numerator, denominator).Int64()
+		if size > math.MaxInt64-part {
+			return 0, fmt.Errorf("size %v exceeds %d bytes", str, int64(math.MaxInt64))
+		}
+		size += part
+	}
+	return size, nil
+}
+
+// Format returns exact representation of the size with the largest binary unit which divides it,
+// e.g. 4194304 -> "4MiB", 1610612736 -> "1536MiB", 1000 -> "1000B", result is accepted by Parse
+func Format(size int64) string {
+	for _, u := range binaryUnits {
+		if size != 0 && size%u.size == 0 {
+			return fmt.Sprintf("%d%s", size/u.size, u.name)
+		}
+	}
+	return fmt.Sprintf("%dB", size)
+}
+
+// AlignUp rounds size up to the multiple of alignment, result is saturated by the largest multiple
+// which fits into int64 instead of wrapping around to negative value
+func AlignUp(size, alignment int64) int64 {
+	remainder := size % alignment
+	if remainder <= 0 {
+		// remainder has sign of size, negative size is rounded up towards zero
+		return size - remainder
+	}
+	if size > math.MaxInt64-(alignment-remainder) {
+		return math.MaxInt64 - math.MaxInt64%alignment
+	}
+	return size + alignment - remainder
+}
+
+// AlignDown rounds size down to the multiple of alignment, result is saturated by the smallest multiple
+// which fits into int64 instead of wrapping around to positive value
+func AlignDown(size, alignment int64) int64 {
+	remainder := size % alignment
+	if remainder >= 0 {
+		return size - remainder
+	}
+	if size < math.MinInt64+(alignment+remainder) {
+		return math.MinInt64 - math.MinInt64%alignment
+	}
+	return size - alignment - remainder
+}
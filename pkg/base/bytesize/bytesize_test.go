@@ -0,0 +1,133 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bytesize
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	for str, expected := range map[string]int64{
+		"15 b":                             15,
+		"601B":                             601,
+		"1024":                             1024,
+		"48e3":                             48 * KB,
+		"102 KB":                           102 * KB,
+		"9851 Mi":                          9851 * MiB,
+		"3gb":                              3 * GB,
+		"7t":                               7 * TiB,
+		"32.3GB":                           32300 * MB,
+		"1.5Gi":                            GiB + GiB/2,
+		"0.1Ki":                            102,
+		"101Mi":                            101 * MiB,
+		"This disk has 5 gb of free space": 5 * GB,
+		"8191P":                            8191 * PiB,
+	} {
+		size, err := Parse(str)
+		assert.Nil(t, err, str)
+		assert.Equal(t, expected, size, str)
+	}
+
+	for _, str := range []string{"foo", "15 Cm", "8192Pi", "9223372036854775808", "9007199254740992k"} {
+		_, err := Parse(str)
+		assert.NotNil(t, err, str)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "512B", Format(512))
+	assert.Equal(t, "4MiB", Format(4*MiB))
+	assert.Equal(t, "1536MiB", Format(GiB+GiB/2))
+	assert.Equal(t, "1000B", Format(KB))
+	assert.Equal(t, "0B", Format(0))
+}
+
+func TestAlign(t *testing.T) {
+	assert.Equal(t, 2*MiB, AlignUp(MiB+1, MiB))
+	assert.Equal(t, MiB, AlignUp(MiB, MiB))
+	assert.Equal(t, int64(0), AlignUp(-1, MiB))
+	assert.Equal(t, -MiB, AlignUp(-MiB-1, MiB))
+	assert.Equal(t, MiB, AlignDown(2*MiB-1, MiB))
+	assert.Equal(t, -MiB, AlignDown(-1, MiB))
+	// result is saturated instead of overflow
+	assert.Equal(t, AlignDown(math.MaxInt64, 4*MiB), AlignUp(math.MaxInt64-1, 4*MiB))
+	assert.Equal(t, AlignUp(math.MinInt64+1, 3*MB), AlignDown(math.MinInt64+1, 3*MB))
+}
+
+func TestParseFormatProperties(t *testing.T) {
+	// formatted sizes are parsed back to the same value
+	exact := func(value uint32, unit uint8) bool {
+		u := binaryUnits[int(unit)%len(binaryUnits)]
+		size := int64(value%8192) * u.size
+		parsed, err := Parse(Format(size))
+		return err == nil && parsed == size
+	}
+	assert.Nil(t, quick.Check(exact, nil))
+
+	arbitrary := func(size int64) bool {
+		if size < 0 {
+			size = -(size + 1)
+		}
+		parsed, err := Parse(Format(size))
+		return err == nil && parsed == size
+	}
+	assert.Nil(t, quick.Check(arbitrary, nil))
+
+	// decimal and binary units of the same value are never mixed up
+	units := func(value uint32) bool {
+		decimal, err1 := Parse(fmt.Sprintf("%dGB", value))
+		binary, err2 := Parse(fmt.Sprintf("%dGi", value))
+		return err1 == nil && err2 == nil && decimal == int64(value)*GB && binary == int64(value)*GiB
+	}
+	assert.Nil(t, quick.Check(units, nil))
+}
+
+func TestAlignProperties(t *testing.T) {
+	alignments := []int64{512, 4 * KiB, MiB, 4 * MiB, 3 * MB, GB}
+	up := func(size int64, i uint8) bool {
+		alignment := alignments[int(i)%len(alignments)]
+		aligned := AlignUp(size, alignment)
+		if aligned%alignment != 0 {
+			return false
+		}
+		// saturated result is the largest multiple of alignment
+		if aligned < size {
+			return aligned == AlignDown(math.MaxInt64, alignment)
+		}
+		return aligned-size < alignment
+	}
+	assert.Nil(t, quick.Check(up, nil))
+
+	down := func(size int64, i uint8) bool {
+		alignment := alignments[int(i)%len(alignments)]
+		aligned := AlignDown(size, alignment)
+		if aligned%alignment != 0 {
+			return false
+		}
+		// saturated result is the smallest multiple of alignment
+		if aligned > size {
+			return aligned == AlignUp(math.MinInt64, alignment)
+		}
+		return size-aligned < alignment
+	}
+	assert.Nil(t, quick.Check(down, nil))
+}
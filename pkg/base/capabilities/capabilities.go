@@ -0,0 +1,104 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package capabilities contains code for detection of the process capabilities and seccomp mode
+// which is used to check whether privileged operations (such as mount) could be performed
+package capabilities
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ProcSelfStatus is a path to the status file of the current process
+	ProcSelfStatus = "/proc/self/status"
+
+	// CapSysAdmin is a number of CAP_SYS_ADMIN capability, it is required for mount/umount
+	CapSysAdmin = 21
+
+	// SeccompDisabled means that seccomp isn't applied to the process
+	SeccompDisabled = 0
+	// SeccompStrict means that only read, write, exit and sigreturn syscalls are allowed
+	SeccompStrict = 1
+	// SeccompFilter means that syscalls are filtered by the seccomp profile
+	SeccompFilter = 2
+
+	capEffField   = "CapEff"
+	seccompField  = "Seccomp"
+	fieldSplitter = ":"
+)
+
+// Status holds effective capabilities and seccomp mode of the process
+type Status struct {
+	Effective uint64
+	Seccomp   int
+}
+
+// ReadStatus reads effective capabilities and seccomp mode from the process status file
+// Receives path to the status file, for current process it is ProcSelfStatus
+// Returns Status or error if file couldn't be read or parsed
+func ReadStatus(path string) (*Status, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		s            = &Status{}
+		capEffParsed bool
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), fieldSplitter, 2)
+		if len(fields) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(fields[1])
+		switch fields[0] {
+		case capEffField:
+			if s.Effective, err = strconv.ParseUint(value, 16, 64); err != nil {
+				return nil, fmt.Errorf("unable to parse %s value %s: %w", capEffField, value, err)
+			}
+			capEffParsed = true
+		case seccompField:
+			if s.Seccomp, err = strconv.Atoi(value); err != nil {
+				return nil, fmt.Errorf("unable to parse %s value %s: %w", seccompField, value, err)
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !capEffParsed {
+		return nil, fmt.Errorf("%s field wasn't found in %s", capEffField, path)
+	}
+	return s, nil
+}
+
+// Has checks whether capability is in effective set
+func (s *Status) Has(capability uint) bool {
+	return s.Effective&(1<<capability) != 0
+}
+
+// SeccompFiltered checks whether syscalls of the process are restricted by seccomp
+func (s *Status) SeccompFiltered() bool {
+	return s.Seccomp != SeccompDisabled
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capabilities
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const statusContent = `Name:	node
+Umask:	0022
+State:	S (sleeping)
+CapInh:	0000000000000000
+CapPrm:	00000000a80425fb
+CapEff:	00000000a80425fb
+CapBnd:	00000000a80425fb
+NoNewPrivs:	0
+Seccomp:	2
+`
+
+func TestReadStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "caps")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	statusPath := path.Join(dir, "status")
+	assert.Nil(t, ioutil.WriteFile(statusPath, []byte(statusContent), 0644))
+
+	s, err := ReadStatus(statusPath)
+	assert.Nil(t, err)
+	assert.False(t, s.Has(CapSysAdmin))
+	assert.True(t, s.Has(0)) // CAP_CHOWN
+	assert.True(t, s.SeccompFiltered())
+
+	// privileged container
+	assert.Nil(t, ioutil.WriteFile(statusPath, []byte("CapEff:\t0000003fffffffff\nSeccomp:\t0\n"), 0644))
+	s, err = ReadStatus(statusPath)
+	assert.Nil(t, err)
+	assert.True(t, s.Has(CapSysAdmin))
+	assert.False(t, s.SeccompFiltered())
+
+	assert.Nil(t, ioutil.WriteFile(statusPath, []byte("Seccomp:\t0\n"), 0644))
+	_, err = ReadStatus(statusPath)
+	assert.NotNil(t, err)
+
+	_, err = ReadStatus(path.Join(dir, "not-exist"))
+	assert.NotNil(t, err)
+}
@@ -16,7 +16,10 @@ limitations under the License.
 
 package capacityplanner
 
-import "github.com/dell/csi-baremetal/pkg/base/util"
+import (
+	"github.com/dell/csi-baremetal/pkg/base/bytesize"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
 
 // AcSizeMinThresholdBytes means that if AC size becomes lower then AcSizeMinThresholdBytes that AC should be deleted
 const AcSizeMinThresholdBytes = int64(util.MBYTE) // 1MB
@@ -31,20 +34,14 @@ const DefaultPESize = 4 * int64(util.MBYTE)
 // AlignSizeByPE make size aligned with default PE
 // TODO: use non default PE size - https://github.com/dell/csi-baremetal/issues/85
 func AlignSizeByPE(size int64) int64 {
-	var alignement int64
-	reminder := size % DefaultPESize
-	if reminder != 0 {
-		alignement = DefaultPESize - reminder
-	}
-	return size + alignement
+	return bytesize.AlignUp(size, DefaultPESize)
 }
 
-// SubtractLVMMetadataSize subtracts LVM metadata size from raw drive size
+// SubtractLVMMetadataSize subtracts LVM metadata size from raw drive size and aligns the rest with default PE,
+// drive which is smaller than metadata has no usable size
 func SubtractLVMMetadataSize(size int64) int64 {
-	reminder := size % DefaultPESize
-	result := size - reminder
-	if reminder < LvgDefaultMetadataSize {
-		return result - DefaultPESize
+	if size <= LvgDefaultMetadataSize {
+		return 0
 	}
-	return result
+	return bytesize.AlignDown(size-LvgDefaultMetadataSize, DefaultPESize)
 }
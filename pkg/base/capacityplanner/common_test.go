@@ -16,7 +16,10 @@ limitations under the License.
 
 package capacityplanner
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestSubtractLVMMetadataSize(t *testing.T) {
 	type args struct {
@@ -55,6 +58,13 @@ func TestSubtractLVMMetadataSize(t *testing.T) {
 			},
 			want: 520093696,
 		},
+		{
+			name: "1MiB",
+			args: args{
+				size: 1048576,
+			},
+			want: 0,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -64,3 +74,23 @@ func TestSubtractLVMMetadataSize(t *testing.T) {
 		})
 	}
 }
+
+func TestAlignSizeByPE(t *testing.T) {
+	tests := []struct {
+		name string
+		size int64
+		want int64
+	}{
+		{name: "aligned", size: 8388608, want: 8388608},
+		{name: "1B", size: 1, want: 4194304},
+		{name: "100GB", size: 100000000000, want: 100000595968},
+		{name: "max", size: math.MaxInt64, want: math.MaxInt64 - 4194303},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AlignSizeByPE(tt.size); got != tt.want {
+				t.Errorf("AlignSizeByPE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
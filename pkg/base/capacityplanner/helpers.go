@@ -28,6 +28,7 @@ import (
 	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
 	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/metrics"
@@ -89,7 +90,7 @@ func (rh *ReservationHelper) CreateReservation(ctx context.Context, placingPlan
 			Reservations: acsNames,
 		})
 		if createErr = rh.client.CreateCR(ctx, acrCR.Name, acrCR); createErr != nil {
-			createErr = fmt.Errorf("unable to create ACR CR %v for volume %v: %v", acrCR.Spec, v, createErr)
+			createErr = fmt.Errorf("unable to create ACR CR %v for volume %v: %w", acrCR.Spec, v, createErr)
 			break
 		}
 		createdACRs = append(createdACRs, acrCR)
@@ -121,7 +122,8 @@ func (rh *ReservationHelper) ReleaseReservation(
 	// we should select ACR to remove from ACRs which have same size and SC as volume
 	filteredACRMap, filteredACNameToACR := buildACRMaps(
 		FilterACRList(rh.acrList, func(acr acrcrd.AvailableCapacityReservation) bool {
-			return acr.Spec.StorageClass == volume.StorageClass && acr.Spec.Size == volume.Size
+			return acr.Spec.StorageClass == volume.StorageClass && acr.Spec.Size == volume.Size &&
+				!IsPreReservation(acr)
 		}))
 	_, acrToRemove := choseACFromOldestACR(ACMap{ac.Name: ac}, filteredACRMap, filteredACNameToACR)
 	if acrToRemove == nil {
@@ -313,6 +315,13 @@ func FilterACRList(
 	return result
 }
 
+// IsPreReservation returns true if ACR was created in advance for PVC which doesn't exist yet,
+// such ACR holds capacity but isn't consumed during volume creation
+func IsPreReservation(acr acrcrd.AvailableCapacityReservation) bool {
+	_, ok := acr.Annotations[base.ACRAnnotationReservedFor]
+	return ok
+}
+
 // FilterACList filter for AC list
 func FilterACList(
 	acs []accrd.AvailableCapacity, filter func(ac accrd.AvailableCapacity) bool) []accrd.AvailableCapacity {
@@ -18,6 +18,7 @@ package capacityplanner
 
 import (
 	"math"
+	"sort"
 
 	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
 	v1 "github.com/dell/csi-baremetal/api/v1"
@@ -30,6 +31,18 @@ type nodeCapacity struct {
 	capacity ACMap
 	// store original versions of modified ACs
 	origAC ACMap
+	// selects AC among ACs of the same storage class
+	strategy PlacementStrategy
+	// volume ID to strategy mapping, overrides strategy
+	volumeStrategies map[string]PlacementStrategy
+}
+
+// strategyForVolume returns strategy which selects AC for volume
+func (nc *nodeCapacity) strategyForVolume(vol *genV1.Volume) PlacementStrategy {
+	if strategy, ok := nc.volumeStrategies[vol.Id]; ok {
+		return strategy
+	}
+	return nc.strategy
 }
 
 // registerAC register AC in internal cache
@@ -88,8 +101,8 @@ func (nc *nodeCapacity) selectACForFullDriveVolume(vol *genV1.Volume) *accrd.Ava
 	filteredMap := SCToACMap{}
 	if vol.StorageClass == v1.StorageClassAny {
 		for sc, acs := range scToACMap {
-			// for any SC we need to check for non LVG only
-			if !util.IsStorageClassLVG(sc) {
+			// for any SC we need to check for non LVG only, PMEM and ZONED are used only if they are requested explicitly
+			if !util.IsStorageClassLVG(sc) && !util.IsStorageClassOptIn(sc) {
 				// TODO Take into account drive technology for SC ANY https://github.com/dell/csi-baremetal/issues/231
 				// map must be sorted HDD->SSD->NVMe
 				filteredMap[sc] = acs
@@ -98,10 +111,15 @@ func (nc *nodeCapacity) selectACForFullDriveVolume(vol *genV1.Volume) *accrd.Ava
 	} else {
 		filteredMap[vol.StorageClass] = scToACMap[vol.StorageClass]
 	}
-	// try to find free capacity
+	// try to find free capacity, storage classes are sorted to select the same AC for the same capacity
+	scs := make([]string, 0, len(filteredMap))
+	for sc := range filteredMap {
+		scs = append(scs, sc)
+	}
+	sort.Strings(scs)
 	var foundAC *accrd.AvailableCapacity
-	for _, acs := range filteredMap {
-		foundAC = searchACWithClosestSize(acs, requiredSize, nil)
+	for _, sc := range scs {
+		foundAC = nc.strategyForVolume(vol).SelectAC(filteredMap[sc], requiredSize, nil)
 		if foundAC != nil {
 			break
 		}
@@ -133,12 +151,12 @@ func (nc *nodeCapacity) selectACForLVMVolume(vol *genV1.Volume) *accrd.Available
 	requiredSize := AlignSizeByPE(vol.GetSize())
 
 	// try to find free capacity with StorageClass from volume creation request
-	foundAC := searchACWithClosestSize(scToACMap[vol.StorageClass], requiredSize, nil)
+	foundAC := nc.strategyForVolume(vol).SelectAC(scToACMap[vol.StorageClass], requiredSize, nil)
 
 	// for LVG SC try to reserve AC to create new LVG since no free space found on existing
 	if foundAC == nil {
 		// search AC in sub storage class
-		foundAC = searchACWithClosestSize(scToACMap[subSC], requiredSize, SubtractLVMMetadataSize)
+		foundAC = nc.strategyForVolume(vol).SelectAC(scToACMap[subSC], requiredSize, SubtractLVMMetadataSize)
 	}
 	// return if available capacity not found
 	if foundAC == nil {
@@ -174,12 +192,8 @@ func searchACWithClosestSize(acs ACMap, size int64, sizeRoundFunc func(int64) in
 		pickedAC *accrd.AvailableCapacity
 	)
 
-	for _, ac := range acs {
-		acSize := ac.Spec.Size
-		if sizeRoundFunc != nil {
-			acSize = sizeRoundFunc(acSize)
-		}
-		if acSize >= size && acSize < maxSize {
+	for _, ac := range suitableACs(acs, size, sizeRoundFunc) {
+		if acSize := roundACSize(ac, sizeRoundFunc); acSize < maxSize {
 			pickedAC = ac
 			maxSize = acSize
 		}
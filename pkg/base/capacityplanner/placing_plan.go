@@ -51,7 +51,7 @@ func (vpp *VolumesPlacingPlan) GetVolumesToACMapping(node string) VolToACMap {
 	return plan
 }
 
-// SelectNode returns less loaded node which has required capacity to create volume
+// SelectNode returns less loaded node which has required capacity to create volume, ties are broken by node ID
 func (vpp *VolumesPlacingPlan) SelectNode() string {
 	suitableNodes := make([]string, 0, len(vpp.plan))
 	for node := range vpp.plan {
@@ -61,7 +61,11 @@ func (vpp *VolumesPlacingPlan) SelectNode() string {
 		return ""
 	}
 	sort.Slice(suitableNodes, func(i, j int) bool {
-		return len(vpp.capacity[suitableNodes[i]]) > len(vpp.capacity[suitableNodes[j]])
+		left, right := len(vpp.capacity[suitableNodes[i]]), len(vpp.capacity[suitableNodes[j]])
+		if left != right {
+			return left > right
+		}
+		return suitableNodes[i] < suitableNodes[j]
 	})
 	return suitableNodes[0]
 }
@@ -80,18 +80,33 @@ type CapacityManagerBuilder interface {
 }
 
 // DefaultCapacityManagerBuilder is a builder for default CapacityManagers
-type DefaultCapacityManagerBuilder struct{}
+type DefaultCapacityManagerBuilder struct {
+	// Strategy is used by CapacityManagers to select AC, best-fit is used if it isn't set
+	Strategy PlacementStrategy
+	// VolumeStrategies overrides Strategy for volumes by volume IDs, e.g. with strategy from StorageClass parameters
+	VolumeStrategies map[string]PlacementStrategy
+}
 
 // GetCapacityManager returns default implementation of CapacityManager
 func (dcmb *DefaultCapacityManagerBuilder) GetCapacityManager(
 	logger *logrus.Entry, capReader CapacityReader) CapacityPlaner {
-	return NewCapacityManager(logger, capReader)
+	cm := NewCapacityManager(logger, capReader)
+	if dcmb.Strategy != nil {
+		cm.strategy = dcmb.Strategy
+	}
+	cm.volumeStrategies = dcmb.VolumeStrategies
+	return cm
 }
 
 // GetReservedCapacityManager returns default implementation of ReservedCapacityManager
 func (dcmb *DefaultCapacityManagerBuilder) GetReservedCapacityManager(
 	logger *logrus.Entry, capReader CapacityReader, resReader ReservationReader) CapacityPlaner {
-	return NewReservedCapacityManager(logger, capReader, resReader)
+	rcm := NewReservedCapacityManager(logger, capReader, resReader)
+	if dcmb.Strategy != nil {
+		rcm.strategy = dcmb.Strategy
+	}
+	rcm.volumeStrategies = dcmb.VolumeStrategies
+	return rcm
 }
 
 // NewCapacityManager return new instance of CapacityManager
@@ -99,6 +114,7 @@ func NewCapacityManager(logger *logrus.Entry, capReader CapacityReader) *Capacit
 	return &CapacityManager{
 		logger:    logger,
 		capReader: capReader,
+		strategy:  &bestFit{},
 	}
 }
 
@@ -106,6 +122,9 @@ func NewCapacityManager(logger *logrus.Entry, capReader CapacityReader) *Capacit
 type CapacityManager struct {
 	logger    *logrus.Entry
 	capReader CapacityReader
+	strategy  PlacementStrategy
+	// volume ID to strategy mapping, overrides strategy
+	volumeStrategies map[string]PlacementStrategy
 
 	// nodeID to nodeCapacity
 	nodesCapacity map[string]*nodeCapacity
@@ -163,6 +182,10 @@ func (cm *CapacityManager) update(ctx context.Context) error {
 		logger.Errorf("Failed to read capacity: %s", err.Error())
 		return err
 	}
+	if err = updateStrategies(ctx, cm.strategy, cm.volumeStrategies); err != nil {
+		logger.Errorf("Failed to update placement strategy: %s", err.Error())
+		return err
+	}
 	for _, c := range capacity {
 		c := c
 		nodeID := c.Spec.NodeId
@@ -182,11 +205,31 @@ func (cm *CapacityManager) convertCapacityToMap() NodeCapacityMap {
 
 func (cm *CapacityManager) registerNodeCapacity(node string, capacity *accrd.AvailableCapacity) {
 	if _, ok := cm.nodesCapacity[node]; !ok {
-		cm.nodesCapacity[node] = &nodeCapacity{capacity: ACMap{}}
+		cm.nodesCapacity[node] = &nodeCapacity{capacity: ACMap{}, strategy: cm.strategy,
+			volumeStrategies: cm.volumeStrategies}
 	}
 	cm.nodesCapacity[node].registerAC(capacity)
 }
 
+// updateStrategies updates default strategy and strategies of volumes, every strategy is updated once
+func updateStrategies(ctx context.Context, strategy PlacementStrategy,
+	volumeStrategies map[string]PlacementStrategy) error {
+	if err := strategy.Update(ctx); err != nil {
+		return err
+	}
+	updated := map[PlacementStrategy]bool{strategy: true}
+	for _, volumeStrategy := range volumeStrategies {
+		if updated[volumeStrategy] {
+			continue
+		}
+		if err := volumeStrategy.Update(ctx); err != nil {
+			return err
+		}
+		updated[volumeStrategy] = true
+	}
+	return nil
+}
+
 // NewReservedCapacityManager returns new instance of ReservedCapacityManager
 func NewReservedCapacityManager(
 	logger *logrus.Entry, capReader CapacityReader, resReader ReservationReader) *ReservedCapacityManager {
@@ -194,6 +237,7 @@ func NewReservedCapacityManager(
 		logger:    logger,
 		capReader: capReader,
 		resReader: resReader,
+		strategy:  &bestFit{},
 	}
 }
 
@@ -202,6 +246,10 @@ type ReservedCapacityManager struct {
 	logger    *logrus.Entry
 	capReader CapacityReader
 	resReader ReservationReader
+	// selects AC among ACs reserved for volume on node
+	strategy PlacementStrategy
+	// volume ID to strategy mapping, overrides strategy
+	volumeStrategies map[string]PlacementStrategy
 
 	nodeCapacityMap     NodeCapacityMap
 	acrMap              ACRMap
@@ -223,7 +271,15 @@ func (rcm *ReservedCapacityManager) PlanVolumesPlacing(
 	if err != nil {
 		return nil, err
 	}
-	selectedACs := rcm.selectBestACForNodes(ctx)
+	strategy := rcm.strategy
+	if volumeStrategy, ok := rcm.volumeStrategies[volume.Id]; ok {
+		strategy = volumeStrategy
+	}
+	if err := strategy.Update(ctx); err != nil {
+		logger.Errorf("Failed to update placement strategy: %s", err.Error())
+		return nil, err
+	}
+	selectedACs := rcm.selectBestACForNodes(ctx, strategy)
 	if len(selectedACs) == 0 {
 		logger.Info("Required capacity for volumes not found")
 		return nil, nil
@@ -253,7 +309,8 @@ func (rcm *ReservedCapacityManager) update(ctx context.Context, volume *genV1.Vo
 		return err
 	}
 	filteredACRs := FilterACRList(acrList, func(acr acrcrd.AvailableCapacityReservation) bool {
-		return acr.Spec.StorageClass == volume.StorageClass && acr.Spec.Size == volume.Size
+		return acr.Spec.StorageClass == volume.StorageClass && acr.Spec.Size == volume.Size &&
+			!IsPreReservation(acr)
 	})
 	resFilter := NewReservationFilter()
 	reservedACs := resFilter.FilterByReservation(true, acList, filteredACRs)
@@ -263,17 +320,30 @@ func (rcm *ReservedCapacityManager) update(ctx context.Context, volume *genV1.Vo
 	return nil
 }
 
-// selectBestACForNode select best AC for volume on node
-func (rcm *ReservedCapacityManager) selectBestACForNodes(ctx context.Context) NodeCapacityMap {
+// selectBestACForNode select best AC for volume on node, AC is selected by strategy among ACs of the oldest ACR
+func (rcm *ReservedCapacityManager) selectBestACForNodes(ctx context.Context, strategy PlacementStrategy) NodeCapacityMap {
 	logger := util.AddCommonFields(ctx, rcm.logger, "CapacityManager.selectBestACForNodes")
 	selectedCapacityMap := NodeCapacityMap{}
 	for node := range rcm.nodeCapacityMap {
-		acForNode, _ := choseACFromOldestACR(rcm.nodeCapacityMap[node], rcm.acrMap, rcm.acNameToACRNamesMap)
-		if acForNode == nil {
+		_, oldestACR := choseACFromOldestACR(rcm.nodeCapacityMap[node], rcm.acrMap, rcm.acNameToACRNamesMap)
+		if oldestACR == nil {
 			continue
 		}
-		if acForNode.Spec.Size == 0 {
-			logger.Warning("AvailableCapacity with zero size was selected. AvailableCapacity will be ignored.")
+		reservedACs := ACMap{}
+		for acName, ac := range rcm.nodeCapacityMap[node] {
+			if !util.ContainsString(rcm.acNameToACRNamesMap[acName], oldestACR.Name) {
+				continue
+			}
+			if ac.Spec.Size == 0 {
+				logger.Warningf("AvailableCapacity %s with zero size is reserved. AvailableCapacity will be ignored.",
+					acName)
+				continue
+			}
+			reservedACs[acName] = ac
+		}
+		// reserved ACs fit volume, so strategy only chooses between them
+		acForNode := strategy.SelectAC(reservedACs, 0, nil)
+		if acForNode == nil {
 			continue
 		}
 		selectedCapacityMap[node] = ACMap{acForNode.Name: acForNode}
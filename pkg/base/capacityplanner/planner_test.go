@@ -31,6 +31,7 @@ import (
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base"
 )
 
 var (
@@ -189,6 +190,25 @@ func TestCapacityManager(t *testing.T) {
 		assert.Nil(t, plan)
 		assert.Nil(t, err)
 	})
+	t.Run("ANY StorageClass with PMEM and ZONED AC", func(t *testing.T) {
+		testVols := []*genV1.Volume{
+			getTestVol(testNode1, testSmallSize, apiV1.StorageClassAny),
+		}
+		testACS := []*accrd.AvailableCapacity{
+			getTestAC(testNode1, testSmallSize, apiV1.StorageClassPMEM),
+			getTestAC(testNode1, testSmallSize, apiV1.StorageClassZoned),
+		}
+		plan, err := callPlanVolumesPlacing(getCapReaderMock(testACS, nil), testVols)
+		assert.Nil(t, plan)
+		assert.Nil(t, err)
+
+		testVols = []*genV1.Volume{
+			getTestVol(testNode1, testSmallSize, apiV1.StorageClassPMEM),
+		}
+		plan, err = callPlanVolumesPlacing(getCapReaderMock(testACS, nil), testVols)
+		assert.NotNil(t, plan)
+		assert.Nil(t, err)
+	})
 	t.Run("Find AC on multiple nodes", func(t *testing.T) {
 		testVols := []*genV1.Volume{
 			getTestVol("", testSmallSize, apiV1.StorageClassAny),
@@ -329,4 +349,20 @@ func TestReservedCapacityManager(t *testing.T) {
 			assert.Equal(t, testACS[0], plan.GetACForVolume(testNode1, testVols[0]))
 		}
 	})
+	t.Run("Should skip reservation created in advance", func(t *testing.T) {
+		testVols := []*genV1.Volume{
+			getTestVol("", testSmallSize, apiV1.StorageClassAny),
+		}
+		testACS := []*accrd.AvailableCapacity{
+			getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDD),
+		}
+		acr := getTestACR(testSmallSize, apiV1.StorageClassAny, testACS)
+		acr.Annotations = map[string]string{base.ACRAnnotationReservedFor: "default/data-web-1"}
+		plan, err := callPlanVolumesPlacing(
+			getCapReaderMock(testACS, nil),
+			getResReaderMock([]*acrcrd.AvailableCapacityReservation{acr}, nil),
+			testVols)
+		assert.Nil(t, plan)
+		assert.Nil(t, err)
+	})
 }
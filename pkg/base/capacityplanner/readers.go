@@ -63,6 +63,35 @@ func (acr *ACReader) ReadCapacity(ctx context.Context) ([]accrd.AvailableCapacit
 	return acList.Items, nil
 }
 
+// NewLocationFilterACReader returns instance of LocationFilterACReader
+func NewLocationFilterACReader(reader CapacityReader, locations []string) *LocationFilterACReader {
+	return &LocationFilterACReader{
+		reader:    reader,
+		locations: locations,
+	}
+}
+
+// LocationFilterACReader returns only AC which are placed on provided locations, it is used for pinned volumes
+type LocationFilterACReader struct {
+	reader    CapacityReader
+	locations []string
+}
+
+// ReadCapacity returns AC list which was read by underlying reader and filtered by location
+func (lr *LocationFilterACReader) ReadCapacity(ctx context.Context) ([]accrd.AvailableCapacity, error) {
+	acs, err := lr.reader.ReadCapacity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]accrd.AvailableCapacity, 0)
+	for _, ac := range acs {
+		if util.ContainsString(lr.locations, ac.Spec.Location) {
+			filtered = append(filtered, ac)
+		}
+	}
+	return filtered, nil
+}
+
 // NewACRReader returns instance of ACReader
 func NewACRReader(client *k8s.KubeClient, logger *logrus.Entry, cached bool) *ACRReader {
 	return &ACRReader{
@@ -82,3 +82,21 @@ func TestUnreservedACReader(t *testing.T) {
 	assert.Len(t, resp, 1)
 	assert.Equal(t, *testACs[2], resp[0])
 }
+
+func TestLocationFilterACReader(t *testing.T) {
+	ctx := context.Background()
+	logger := testLogger.WithField("component", "test")
+	client := getKubeClient(t)
+	testACs := []*accrd.AvailableCapacity{
+		getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDD),
+		getTestAC(testNode2, testLargeSize, apiV1.StorageClassSSD),
+	}
+	testACs[0].Spec.Location = "drive-1"
+	testACs[1].Spec.Location = "drive-2"
+	createACsInAPi(t, client, testACs)
+	reader := NewLocationFilterACReader(NewACReader(client, logger, true), []string{"drive-2"})
+	resp, err := reader.ReadCapacity(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, "drive-2", resp[0].Spec.Location)
+}
@@ -0,0 +1,232 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityplanner
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
+
+const (
+	// BestFitStrategy selects AC with the closest size, it is the default strategy
+	BestFitStrategy = "best-fit"
+	// WorstFitStrategy selects the largest AC, it keeps free space on drives for volumes expansion
+	WorstFitStrategy = "worst-fit"
+	// SpreadStrategy is an alias of WorstFitStrategy, volumes are spread between drives with the most free space
+	SpreadStrategy = "spread"
+	// RoundRobinStrategy selects suitable ACs in turn to spread volumes between drives
+	RoundRobinStrategy = "round-robin"
+	// WearAwareStrategy selects AC on the drive with the highest endurance
+	WearAwareStrategy = "wear-aware"
+)
+
+// PlacementStrategy describes how AC is selected for volume among ACs of the same storage class on node
+type PlacementStrategy interface {
+	// Update refreshes data which is required by strategy, it is called once before volumes placing planning
+	Update(ctx context.Context) error
+	// SelectAC returns AC which size (rounded by sizeRoundFunc if set) is enough for volume or nil
+	SelectAC(acs ACMap, size int64, sizeRoundFunc func(int64) int64) *accrd.AvailableCapacity
+}
+
+// NewPlacementStrategy returns PlacementStrategy by its name, empty name means default strategy
+// Receives strategy name, k8s client (used by wear-aware strategy to read drives) and logrus logger
+func NewPlacementStrategy(name string, client *k8s.KubeClient, logger *logrus.Entry) (PlacementStrategy, error) {
+	switch name {
+	case "", BestFitStrategy:
+		return &bestFit{}, nil
+	case WorstFitStrategy, SpreadStrategy:
+		return &worstFit{}, nil
+	case RoundRobinStrategy:
+		return &roundRobin{}, nil
+	case WearAwareStrategy:
+		return newWearAware(client, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown placement strategy %s, supported strategies: %s, %s, %s, %s, %s", name,
+			BestFitStrategy, WorstFitStrategy, SpreadStrategy, RoundRobinStrategy, WearAwareStrategy)
+	}
+}
+
+// All strategies break ties by AC name, so the same AC is selected for the same capacity
+
+// bestFit selects AC with the closest size to keep large ACs for large volumes
+type bestFit struct{}
+
+// Update does nothing, best-fit doesn't require any data except ACs
+func (bf *bestFit) Update(ctx context.Context) error {
+	return nil
+}
+
+// SelectAC returns the smallest AC which fits volume
+func (bf *bestFit) SelectAC(acs ACMap, size int64, sizeRoundFunc func(int64) int64) *accrd.AvailableCapacity {
+	return searchACWithClosestSize(acs, size, sizeRoundFunc)
+}
+
+// worstFit selects the largest AC
+type worstFit struct{}
+
+// Update does nothing, worst-fit doesn't require any data except ACs
+func (wf *worstFit) Update(ctx context.Context) error {
+	return nil
+}
+
+// SelectAC returns the largest AC which fits volume
+func (wf *worstFit) SelectAC(acs ACMap, size int64, sizeRoundFunc func(int64) int64) *accrd.AvailableCapacity {
+	var (
+		maxSize  int64 = -1
+		pickedAC *accrd.AvailableCapacity
+	)
+	for _, ac := range suitableACs(acs, size, sizeRoundFunc) {
+		if acSize := roundACSize(ac, sizeRoundFunc); acSize > maxSize {
+			pickedAC = ac
+			maxSize = acSize
+		}
+	}
+	return pickedAC
+}
+
+// roundRobin selects ACs in order of their names starting after the last selected one
+type roundRobin struct {
+	sync.Mutex
+	last string
+}
+
+// Update does nothing, round-robin keeps only the last selected AC
+func (rr *roundRobin) Update(ctx context.Context) error {
+	return nil
+}
+
+// SelectAC returns the next AC after the last selected one which fits volume
+func (rr *roundRobin) SelectAC(acs ACMap, size int64, sizeRoundFunc func(int64) int64) *accrd.AvailableCapacity {
+	suitable := suitableACs(acs, size, sizeRoundFunc)
+	if len(suitable) == 0 {
+		return nil
+	}
+	rr.Lock()
+	defer rr.Unlock()
+	pickedAC := suitable[0]
+	for _, ac := range suitable {
+		if ac.Name > rr.last {
+			pickedAC = ac
+			break
+		}
+	}
+	rr.last = pickedAC.Name
+	return pickedAC
+}
+
+// wearAware selects AC on the drive with the highest endurance, LVG endurance is endurance of its most worn drive
+// Drives which don't report endurance are selected last
+type wearAware struct {
+	sync.RWMutex
+	client *k8s.KubeClient
+	logger *logrus.Entry
+	// AC location (drive UUID or LVG name) to endurance mapping
+	endurance map[string]int64
+}
+
+func newWearAware(client *k8s.KubeClient, logger *logrus.Entry) *wearAware {
+	return &wearAware{
+		client:    client,
+		logger:    logger,
+		endurance: map[string]int64{},
+	}
+}
+
+// Update reads endurance of drives and LVGs
+func (wa *wearAware) Update(ctx context.Context) error {
+	logger := util.AddCommonFields(ctx, wa.logger, "wearAware.Update")
+	driveList := &drivecrd.DriveList{}
+	if err := wa.client.ReadList(ctx, driveList); err != nil {
+		logger.Errorf("failed to read drive list: %s", err.Error())
+		return err
+	}
+	lvgList := &lvgcrd.LogicalVolumeGroupList{}
+	if err := wa.client.ReadList(ctx, lvgList); err != nil {
+		logger.Errorf("failed to read LVG list: %s", err.Error())
+		return err
+	}
+	endurance := make(map[string]int64, len(driveList.Items)+len(lvgList.Items))
+	for _, drive := range driveList.Items {
+		endurance[drive.Spec.UUID] = drive.Spec.Endurance
+	}
+	for _, lvg := range lvgList.Items {
+		var lvgEndurance int64 = math.MaxInt64
+		for _, location := range lvg.Spec.Locations {
+			if endurance[location] < lvgEndurance {
+				lvgEndurance = endurance[location]
+			}
+		}
+		if len(lvg.Spec.Locations) > 0 {
+			endurance[lvg.Name] = lvgEndurance
+		}
+	}
+	wa.Lock()
+	wa.endurance = endurance
+	wa.Unlock()
+	return nil
+}
+
+// SelectAC returns AC with the highest endurance which fits volume, AC with the closest size is selected on ties
+func (wa *wearAware) SelectAC(acs ACMap, size int64, sizeRoundFunc func(int64) int64) *accrd.AvailableCapacity {
+	wa.RLock()
+	defer wa.RUnlock()
+	var (
+		maxEndurance int64 = -1
+		pickedAC     *accrd.AvailableCapacity
+	)
+	for _, ac := range suitableACs(acs, size, sizeRoundFunc) {
+		endurance := wa.endurance[ac.Spec.Location]
+		if endurance > maxEndurance ||
+			(endurance == maxEndurance && roundACSize(ac, sizeRoundFunc) < roundACSize(pickedAC, sizeRoundFunc)) {
+			pickedAC = ac
+			maxEndurance = endurance
+		}
+	}
+	return pickedAC
+}
+
+// suitableACs returns ACs which size is enough for volume sorted by name
+func suitableACs(acs ACMap, size int64, sizeRoundFunc func(int64) int64) []*accrd.AvailableCapacity {
+	result := make([]*accrd.AvailableCapacity, 0, len(acs))
+	for _, ac := range acs {
+		if roundACSize(ac, sizeRoundFunc) >= size {
+			result = append(result, ac)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func roundACSize(ac *accrd.AvailableCapacity, sizeRoundFunc func(int64) int64) int64 {
+	if sizeRoundFunc != nil {
+		return sizeRoundFunc(ac.Spec.Size)
+	}
+	return ac.Spec.Size
+}
@@ -0,0 +1,191 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityplanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+)
+
+func TestNewPlacementStrategy(t *testing.T) {
+	logger := testLogger.WithField("component", "test")
+	for _, name := range []string{"", BestFitStrategy, WorstFitStrategy, SpreadStrategy, RoundRobinStrategy,
+		WearAwareStrategy} {
+		strategy, err := NewPlacementStrategy(name, nil, logger)
+		assert.Nil(t, err)
+		assert.NotNil(t, strategy)
+	}
+	_, err := NewPlacementStrategy("first-fit", nil, logger)
+	assert.NotNil(t, err)
+}
+
+func TestPlacementStrategy_SelectAC(t *testing.T) {
+	small := getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDD)
+	small.Name = "ac-1"
+	large := getTestAC(testNode1, testLargeSize, apiV1.StorageClassHDD)
+	large.Name = "ac-2"
+	acs := ACMap{small.Name: small, large.Name: large}
+
+	t.Run("best-fit", func(t *testing.T) {
+		strategy := &bestFit{}
+		assert.Equal(t, small, strategy.SelectAC(acs, testSmallSize, nil))
+		assert.Equal(t, large, strategy.SelectAC(acs, testSmallSize+1, nil))
+		assert.Nil(t, strategy.SelectAC(acs, testLargeSize+1, nil))
+	})
+	t.Run("worst-fit", func(t *testing.T) {
+		strategy := &worstFit{}
+		assert.Equal(t, large, strategy.SelectAC(acs, testSmallSize, nil))
+		assert.Nil(t, strategy.SelectAC(acs, testLargeSize+1, nil))
+	})
+	t.Run("round-robin", func(t *testing.T) {
+		strategy := &roundRobin{}
+		assert.Equal(t, small, strategy.SelectAC(acs, testSmallSize, nil))
+		assert.Equal(t, large, strategy.SelectAC(acs, testSmallSize, nil))
+		assert.Equal(t, small, strategy.SelectAC(acs, testSmallSize, nil))
+		// only large AC fits volume
+		assert.Equal(t, large, strategy.SelectAC(acs, testLargeSize, nil))
+		assert.Nil(t, strategy.SelectAC(acs, testLargeSize+1, nil))
+	})
+	t.Run("wear-aware", func(t *testing.T) {
+		strategy := newWearAware(nil, testLogger.WithField("component", "test"))
+		// endurance is unknown, the closest size is selected
+		assert.Equal(t, small, strategy.SelectAC(acs, testSmallSize, nil))
+		small.Spec.Location, large.Spec.Location = "drive-1", "drive-2"
+		strategy.endurance = map[string]int64{"drive-1": 10, "drive-2": 90}
+		assert.Equal(t, large, strategy.SelectAC(acs, testSmallSize, nil))
+		strategy.endurance = map[string]int64{"drive-1": 90, "drive-2": 10}
+		assert.Equal(t, small, strategy.SelectAC(acs, testSmallSize, nil))
+		assert.Equal(t, large, strategy.SelectAC(acs, testLargeSize, nil))
+	})
+}
+
+func TestPlacementStrategy_TieBreaking(t *testing.T) {
+	acs := ACMap{}
+	for _, name := range []string{"ac-3", "ac-1", "ac-2"} {
+		ac := getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDD)
+		ac.Name = name
+		acs[name] = ac
+	}
+	for _, strategy := range []PlacementStrategy{&bestFit{}, &worstFit{}, &roundRobin{},
+		newWearAware(nil, testLogger.WithField("component", "test"))} {
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, "ac-1", strategy.SelectAC(acs, testSmallSize, nil).Name)
+			if rr, ok := strategy.(*roundRobin); ok {
+				rr.last = ""
+			}
+		}
+	}
+
+	plan := NewVolumesPlacingPlan(VolumesPlanMap{"node-2": VolToACMap{}, "node-1": VolToACMap{}, "node-3": VolToACMap{}},
+		NodeCapacityMap{"node-1": ACMap{}, "node-2": ACMap{}, "node-3": ACMap{"ac-1": acs["ac-1"]}})
+	assert.Equal(t, "node-3", plan.SelectNode())
+	delete(plan.capacity, "node-3")
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "node-1", plan.SelectNode())
+	}
+}
+
+func TestWearAware_Update(t *testing.T) {
+	client := getKubeClient(t)
+	drives := []*drivecrd.Drive{
+		client.ConstructDriveCR("drive-1", genV1.Drive{UUID: "drive-1", Endurance: 80}),
+		client.ConstructDriveCR("drive-2", genV1.Drive{UUID: "drive-2", Endurance: 30}),
+	}
+	for _, drive := range drives {
+		assert.Nil(t, client.CreateCR(context.Background(), drive.Name, drive))
+	}
+	lvg := client.ConstructLVGCR("lvg-1", genV1.LogicalVolumeGroup{Name: "lvg-1", Locations: []string{"drive-1", "drive-2"}})
+	assert.Nil(t, client.CreateCR(context.Background(), lvg.Name, lvg))
+
+	strategy := newWearAware(client, testLogger.WithField("component", "test"))
+	assert.Nil(t, strategy.Update(context.Background()))
+	assert.Equal(t, map[string]int64{"drive-1": 80, "drive-2": 30, "lvg-1": 30}, strategy.endurance)
+}
+
+func TestCapacityManager_PlacementStrategy(t *testing.T) {
+	logger := testLogger.WithField("component", "test")
+	small := getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDD)
+	large := getTestAC(testNode1, testLargeSize, apiV1.StorageClassHDD)
+	vol := getTestVol("", testSmallSize, apiV1.StorageClassHDD)
+
+	builder := &DefaultCapacityManagerBuilder{}
+	plan, err := builder.GetCapacityManager(logger, getCapReaderMock([]*accrd.AvailableCapacity{small, large}, nil)).
+		PlanVolumesPlacing(context.Background(), []*genV1.Volume{vol})
+	assert.Nil(t, err)
+	assert.Equal(t, small.Name, plan.GetACForVolume(testNode1, vol).Name)
+
+	builder = &DefaultCapacityManagerBuilder{Strategy: &worstFit{}}
+	plan, err = builder.GetCapacityManager(logger, getCapReaderMock([]*accrd.AvailableCapacity{small, large}, nil)).
+		PlanVolumesPlacing(context.Background(), []*genV1.Volume{vol})
+	assert.Nil(t, err)
+	assert.Equal(t, large.Name, plan.GetACForVolume(testNode1, vol).Name)
+}
+
+func TestCapacityManager_VolumeStrategies(t *testing.T) {
+	logger := testLogger.WithField("component", "test")
+	small := getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDD)
+	large := getTestAC(testNode1, testLargeSize, apiV1.StorageClassHDD)
+	vol := getTestVol("", testSmallSize, apiV1.StorageClassHDD)
+
+	builder := &DefaultCapacityManagerBuilder{VolumeStrategies: map[string]PlacementStrategy{vol.Id: &worstFit{}}}
+	plan, err := builder.GetCapacityManager(logger, getCapReaderMock([]*accrd.AvailableCapacity{small, large}, nil)).
+		PlanVolumesPlacing(context.Background(), []*genV1.Volume{vol})
+	assert.Nil(t, err)
+	assert.Equal(t, large.Name, plan.GetACForVolume(testNode1, vol).Name)
+
+	// other volumes are placed with default strategy
+	other := getTestVol("", testSmallSize, apiV1.StorageClassHDD)
+	plan, err = builder.GetCapacityManager(logger, getCapReaderMock([]*accrd.AvailableCapacity{small, large}, nil)).
+		PlanVolumesPlacing(context.Background(), []*genV1.Volume{other})
+	assert.Nil(t, err)
+	assert.Equal(t, small.Name, plan.GetACForVolume(testNode1, other).Name)
+}
+
+func TestReservedCapacityManager_PlacementStrategy(t *testing.T) {
+	logger := testLogger.WithField("component", "test")
+	small := getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDD)
+	large := getTestAC(testNode1, testLargeSize, apiV1.StorageClassHDD)
+	acs := []*accrd.AvailableCapacity{small, large}
+	vol := getTestVol("", testSmallSize, apiV1.StorageClassHDD)
+	acrs := []*acrcrd.AvailableCapacityReservation{getTestACR(testSmallSize, apiV1.StorageClassHDD, acs)}
+
+	builder := &DefaultCapacityManagerBuilder{}
+	plan, err := builder.GetReservedCapacityManager(logger, getCapReaderMock(acs, nil), getResReaderMock(acrs, nil)).
+		PlanVolumesPlacing(context.Background(), []*genV1.Volume{vol})
+	assert.Nil(t, err)
+	assert.Equal(t, small.Name, plan.GetACForVolume(testNode1, vol).Name)
+
+	builder = &DefaultCapacityManagerBuilder{Strategy: &worstFit{}}
+	plan, err = builder.GetReservedCapacityManager(logger, getCapReaderMock(acs, nil), getResReaderMock(acrs, nil)).
+		PlanVolumesPlacing(context.Background(), []*genV1.Volume{vol})
+	assert.Nil(t, err)
+	assert.Equal(t, large.Name, plan.GetACForVolume(testNode1, vol).Name)
+
+	builder = &DefaultCapacityManagerBuilder{VolumeStrategies: map[string]PlacementStrategy{vol.Id: &worstFit{}}}
+	plan, err = builder.GetReservedCapacityManager(logger, getCapReaderMock(acs, nil), getResReaderMock(acrs, nil)).
+		PlanVolumesPlacing(context.Background(), []*genV1.Volume{vol})
+	assert.Nil(t, err)
+	assert.Equal(t, large.Name, plan.GetACForVolume(testNode1, vol).Name)
+}
@@ -115,7 +115,7 @@ func (e *Executor) RunCmdWithAttempts(cmd interface{}, attempts int, timeout tim
 		ll.Warnf("Unable to execute cmd: %v. Attempt %d out of %d.", err, i, attempts)
 		<-time.After(timeout)
 	}
-	errMsg := fmt.Errorf("failed to execute command after %d attempt, error: %v", attempts, err)
+	errMsg := fmt.Errorf("failed to execute command after %d attempt, error: %w", attempts, err)
 	return stdout, stderr, errMsg
 }
 
@@ -186,3 +186,13 @@ func (e *Executor) runCmdFromCmdObj(cmd *exec.Cmd) (outStr string, errStr string
 		Logf(level, "stdout: %s%s%s", outStr, stdErrPart, errPart)
 	return outStr, errStr, err
 }
+
+// LookPath searches executable in PATH, it is a variable to be overridden in tests
+var LookPath = exec.LookPath
+
+// IsAvailable checks whether system util could be found in PATH
+// Is used for runtime detection of utils which availability depends on architecture or distribution
+func IsAvailable(tool string) bool {
+	_, err := LookPath(tool)
+	return err == nil
+}
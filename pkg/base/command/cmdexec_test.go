@@ -77,3 +77,51 @@ func TestExecutorFromStrAndExpectError(t *testing.T) {
 		assert.Contains(t, err.Error(), test.err.Error())
 	}
 }
+
+func TestIsAvailable(t *testing.T) {
+	defer func() { LookPath = exec.LookPath }()
+
+	LookPath = func(file string) (string, error) {
+		if file == "lsscsi" {
+			return "/usr/bin/lsscsi", nil
+		}
+		return "", exec.ErrNotFound
+	}
+
+	assert.True(t, IsAvailable("lsscsi"))
+	assert.False(t, IsAvailable("ipmitool"))
+}
+
+// recordingExecutor saves last command instead of running it
+type recordingExecutor struct {
+	Executor
+	last interface{}
+}
+
+func (r *recordingExecutor) RunCmd(cmd interface{}, opts ...Options) (string, string, error) {
+	r.last = cmd
+	return "", "", nil
+}
+
+func TestNsenterExecutor_RunCmd(t *testing.T) {
+	r := &recordingExecutor{}
+	e := NewNsenterExecutor(r, logrus.New(), "mount", "umount")
+
+	_, _, err := e.RunCmd("mount  /dev/sda /mnt")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"nsenter", "--target", "1", "--mount", "--", "mount", "/dev/sda", "/mnt"},
+		r.last.(*exec.Cmd).Args)
+
+	// arguments with spaces aren't split
+	umount := exec.Command("umount", "/mnt/volume with spaces")
+	umount.Env = []string{"LC_ALL=C"}
+	_, _, err = e.RunCmd(umount)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"nsenter", "--target", "1", "--mount", "--", "umount", "/mnt/volume with spaces"},
+		r.last.(*exec.Cmd).Args)
+	assert.Equal(t, umount.Env, r.last.(*exec.Cmd).Env)
+
+	_, _, err = e.RunCmd("mkfs.xfs /dev/sda")
+	assert.Nil(t, err)
+	assert.Equal(t, "mkfs.xfs /dev/sda", r.last)
+}
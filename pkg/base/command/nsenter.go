@@ -0,0 +1,97 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NsenterCmd is a name of the util which runs program in the namespaces of other process
+const NsenterCmd = "nsenter"
+
+// nsenterMountArgs runs command, which follows them, in the mount namespace of the host init process
+var nsenterMountArgs = []string{"--target", "1", "--mount", "--"}
+
+// NsenterExecutor is the implementation of CmdExecutor which runs specified commands (mount, umount)
+// in the mount namespace of the host via nsenter and other commands as is.
+// It is used as a fallback when mount syscall is restricted for the container
+type NsenterExecutor struct {
+	CmdExecutor
+	cmds map[string]bool
+	log  *logrus.Entry
+}
+
+// NewNsenterExecutor is the constructor for NsenterExecutor
+// Receives CmdExecutor which runs commands, logrus logger and names of the commands which should be run via nsenter
+// Returns an instance of NsenterExecutor
+func NewNsenterExecutor(e CmdExecutor, logger *logrus.Logger, cmds ...string) *NsenterExecutor {
+	n := &NsenterExecutor{
+		CmdExecutor: e,
+		cmds:        make(map[string]bool, len(cmds)),
+		log:         logger.WithField("component", "NsenterExecutor"),
+	}
+	for _, c := range cmds {
+		n.cmds[c] = true
+	}
+	return n
+}
+
+// RunCmd runs specified command via nsenter if command is in the list, otherwise runs it as is
+// Receives command as empty interface. It could be string or instance of exec.Cmd
+// Returns stdout as string, stderr as string and golang error if something went wrong
+func (n *NsenterExecutor) RunCmd(cmd interface{}, opts ...Options) (string, string, error) {
+	return n.CmdExecutor.RunCmd(n.wrap(cmd), opts...)
+}
+
+// RunCmdWithAttempts runs specified command via nsenter if command is in the list, otherwise runs it as is
+// Receives command as empty interface, It could be string or instance of exec.Cmd; number of attempts; timeout.
+// Returns stdout as string, stderr as string and golang error if something went wrong
+func (n *NsenterExecutor) RunCmdWithAttempts(cmd interface{}, attempts int, timeout time.Duration,
+	opts ...Options) (string, string, error) {
+	return n.CmdExecutor.RunCmdWithAttempts(n.wrap(cmd), attempts, timeout, opts...)
+}
+
+// wrap prepends nsenter to the command if it is in the list, arguments of the command are passed as is
+func (n *NsenterExecutor) wrap(cmd interface{}) interface{} {
+	switch c := cmd.(type) {
+	case string:
+		fields := strings.Fields(c)
+		if len(fields) > 0 && n.cmds[fields[0]] {
+			n.log.Debugf("Run %s in the host mount namespace", fields[0])
+			return nsenterCommand(fields)
+		}
+	case *exec.Cmd:
+		if len(c.Args) > 0 && n.cmds[c.Args[0]] {
+			n.log.Debugf("Run %s in the host mount namespace", c.Args[0])
+			wrapped := nsenterCommand(c.Args)
+			wrapped.Env, wrapped.Dir, wrapped.Stdin = c.Env, c.Dir, c.Stdin
+			return wrapped
+		}
+	}
+	return cmd
+}
+
+// nsenterCommand returns nsenter command which runs program with provided argv in the host mount namespace
+func nsenterCommand(argv []string) *exec.Cmd {
+	args := make([]string, 0, len(nsenterMountArgs)+len(argv))
+	args = append(args, nsenterMountArgs...)
+	return exec.Command(NsenterCmd, append(args, argv...)...)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config contains structured configuration of CSI components which is read from ConfigMap mounted YAML file
+// Values from config are applied to the flags which weren't set explicitly in command line,
+// so flags keep working and have precedence over config file
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+)
+
+const (
+	// minDiscoveryInterval is a minimal allowed interval between drives discovering on node
+	minDiscoveryInterval = time.Second
+	// logLevelFlag is a name of command line flag with log level
+	logLevelFlag = "loglevel"
+)
+
+// Config is a structured configuration of CSI components
+type Config struct {
+	Log        LogConfig        `yaml:"log"`
+	Metrics    MetricsConfig    `yaml:"metrics"`
+	Features   FeaturesConfig   `yaml:"features"`
+	Controller ControllerConfig `yaml:"controller"`
+	Node       NodeConfig       `yaml:"node"`
+	DriveMgr   DriveMgrConfig   `yaml:"driveMgr"`
+}
+
+// LogConfig holds logging settings, level is reloaded without restart unless it is set by --loglevel flag
+type LogConfig struct {
+	Level string `yaml:"level"`
+	Path  string `yaml:"path"`
+}
+
+// MetricsConfig holds prometheus endpoint settings
+type MetricsConfig struct {
+	Address string `yaml:"address"`
+	Path    string `yaml:"path"`
+}
+
+// FeaturesConfig holds feature flags, nil value means that feature isn't configured
+type FeaturesConfig struct {
+	Extender               *bool `yaml:"extender"`
+	UseNodeAnnotation      *bool `yaml:"useNodeAnnotation"`
+	NUMAHint               *bool `yaml:"numaHint"`
+	StatefulSetReservation *bool `yaml:"statefulSetReservation"`
+}
+
+// ControllerConfig holds controller service settings
+type ControllerConfig struct {
+	CreateVolumeParallelism   int           `yaml:"createVolumeParallelism"`
+	OrphanedVolumeGracePeriod time.Duration `yaml:"orphanedVolumeGracePeriod"`
+}
+
+// NodeConfig holds node service settings, discovery interval is reloaded without restart
+type NodeConfig struct {
+	DiscoveryInterval     time.Duration `yaml:"discoveryInterval"`
+	EventConfigPath       string        `yaml:"eventConfigPath"`
+	VolumeOperationsLimit int           `yaml:"volumeOperationsLimit"`
+}
+
+// DriveMgrConfig holds drive manager settings, endpoint is used by both drive manager and node service
+type DriveMgrConfig struct {
+	Endpoint     string `yaml:"endpoint"`
+	FirmwareTool string `yaml:"firmwareTool"`
+}
+
+// Load reads config from YAML file and validates it
+// Returns config or error if file can't be read or config is invalid
+func Load(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file %s: %w", path, err)
+	}
+	return Parse(data)
+}
+
+// Parse unmarshalls config from YAML and validates it
+func Parse(data []byte) (*Config, error) {
+	c := &Config{}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal config: %w", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate checks that config values are acceptable
+func (c *Config) Validate() error {
+	switch strings.ToLower(c.Log.Level) {
+	case "", base.InfoLevel, base.DebugLevel, base.TraceLevel:
+	default:
+		return fmt.Errorf("unsupported log level %s, supported values are %s, %s, %s",
+			c.Log.Level, base.InfoLevel, base.DebugLevel, base.TraceLevel)
+	}
+	if c.Metrics.Path != "" && !strings.HasPrefix(c.Metrics.Path, "/") {
+		return fmt.Errorf("metrics path %s should start with /", c.Metrics.Path)
+	}
+	if c.Controller.CreateVolumeParallelism < 0 {
+		return fmt.Errorf("create volume parallelism %d should be positive", c.Controller.CreateVolumeParallelism)
+	}
+	if c.Controller.OrphanedVolumeGracePeriod < 0 {
+		return fmt.Errorf("orphaned volume grace period %s should be positive", c.Controller.OrphanedVolumeGracePeriod)
+	}
+	if c.Node.VolumeOperationsLimit < 0 {
+		return fmt.Errorf("volume operations limit %d should be positive", c.Node.VolumeOperationsLimit)
+	}
+	if c.Node.DiscoveryInterval != 0 && c.Node.DiscoveryInterval < minDiscoveryInterval {
+		return fmt.Errorf("discovery interval %s is less than %s", c.Node.DiscoveryInterval, minDiscoveryInterval)
+	}
+	return nil
+}
+
+// flagValues returns values of configured fields by names of corresponding command line flags
+func (c *Config) flagValues() map[string]string {
+	values := map[string]string{
+		logLevelFlag:       c.Log.Level,
+		"logpath":          c.Log.Path,
+		"metrics-address":  c.Metrics.Address,
+		"metrics-path":     c.Metrics.Path,
+		"eventConfigPath":  c.Node.EventConfigPath,
+		"drivemgrendpoint": c.DriveMgr.Endpoint,
+		"firmwaretool":     c.DriveMgr.FirmwareTool,
+	}
+	if c.Controller.CreateVolumeParallelism > 0 {
+		values["createvolumeparallelism"] = strconv.Itoa(c.Controller.CreateVolumeParallelism)
+	}
+	if c.Controller.OrphanedVolumeGracePeriod > 0 {
+		values["orphanedvolumegraceperiod"] = c.Controller.OrphanedVolumeGracePeriod.String()
+	}
+	if c.Node.VolumeOperationsLimit > 0 {
+		values["volumeoperationslimit"] = strconv.Itoa(c.Node.VolumeOperationsLimit)
+	}
+	for name, feature := range map[string]*bool{
+		"extender":               c.Features.Extender,
+		"usenodeannotation":      c.Features.UseNodeAnnotation,
+		"numahint":               c.Features.NUMAHint,
+		"statefulsetreservation": c.Features.StatefulSetReservation,
+	} {
+		if feature != nil {
+			values[name] = strconv.FormatBool(*feature)
+		}
+	}
+	return values
+}
+
+// ApplyToFlags sets config values to the flags which are defined in flag set and weren't set in command line
+// Should be called after flag set was parsed
+func (c *Config) ApplyToFlags(fs *flag.FlagSet) error {
+	explicit := explicitFlags(fs)
+	for name, value := range c.flagValues() {
+		if value == "" || explicit[name] || fs.Lookup(name) == nil {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("unable to set flag %s from config: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// explicitFlags returns names of the flags which were set in command line
+func explicitFlags(fs *flag.FlagSet) map[string]bool {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+	return explicit
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfig = `
+log:
+  level: debug
+metrics:
+  path: /metrics
+features:
+  extender: true
+controller:
+  createVolumeParallelism: 4
+  orphanedVolumeGracePeriod: 1h
+node:
+  discoveryInterval: 45s
+driveMgr:
+  endpoint: tcp://localhost:8888
+`
+
+func TestParse(t *testing.T) {
+	c, err := Parse([]byte(testConfig))
+	assert.Nil(t, err)
+	assert.Equal(t, "debug", c.Log.Level)
+	assert.Equal(t, 45*time.Second, c.Node.DiscoveryInterval)
+	assert.True(t, *c.Features.Extender)
+	assert.Nil(t, c.Features.UseNodeAnnotation)
+
+	for _, invalid := range []string{
+		"log:\n  level: warn",
+		"metrics:\n  path: metrics",
+		"node:\n  discoveryInterval: 10ms",
+		"controller:\n  createVolumeParallelism: -1",
+		"controller:\n  orphanedVolumeGracePeriod: -1m",
+		"node:\n  volumeOperationsLimit: -1",
+		"unknown: field",
+	} {
+		_, err = Parse([]byte(invalid))
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestConfig_ApplyToFlags(t *testing.T) {
+	c, err := Parse([]byte(testConfig))
+	assert.Nil(t, err)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	logLevel := fs.String("loglevel", "info", "")
+	endpoint := fs.String("drivemgrendpoint", "tcp://localhost:7777", "")
+	extender := fs.Bool("extender", false, "")
+	useNodeAnnotation := fs.Bool("usenodeannotation", false, "")
+	parallelism := fs.Int("createvolumeparallelism", 10, "")
+	gracePeriod := fs.Duration("orphanedvolumegraceperiod", 0, "")
+	assert.Nil(t, fs.Parse([]string{"--drivemgrendpoint=tcp://localhost:9999"}))
+
+	assert.Nil(t, c.ApplyToFlags(fs))
+	assert.Equal(t, "debug", *logLevel)
+	// explicitly set flag has precedence
+	assert.Equal(t, "tcp://localhost:9999", *endpoint)
+	assert.True(t, *extender)
+	assert.False(t, *useNodeAnnotation)
+	assert.Equal(t, 4, *parallelism)
+	assert.Equal(t, time.Hour, *gracePeriod)
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+
+	_, err = NewWatcher(path, logrus.New())
+	assert.NotNil(t, err)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte(testConfig), 0644))
+	w, err := NewWatcher(path, logrus.New())
+	assert.Nil(t, err)
+	assert.Equal(t, "debug", w.Get().Log.Level)
+
+	var notified *Config
+	w.OnChange(func(c *Config) {
+		notified = c
+	})
+
+	// invalid config is ignored
+	assert.Nil(t, ioutil.WriteFile(path, []byte("log:\n  level: warn"), 0644))
+	w.reload()
+	assert.Nil(t, notified)
+	assert.Equal(t, "debug", w.Get().Log.Level)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("log:\n  level: trace"), 0644))
+	w.reload()
+	assert.Equal(t, "trace", w.Get().Log.Level)
+	assert.Equal(t, w.Get(), notified)
+}
+
+func TestWatcher_ApplyLogLevel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(testConfig), 0644))
+
+	// level of reloaded config is applied if flag isn't set
+	w, err := NewWatcher(path, logrus.New())
+	assert.Nil(t, err)
+	logger := logrus.New()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String(logLevelFlag, "info", "")
+	assert.Nil(t, fs.Parse([]string{}))
+	w.ApplyLogLevel(logger, fs)
+	assert.Nil(t, ioutil.WriteFile(path, []byte("log:\n  level: trace"), 0644))
+	w.reload()
+	assert.Equal(t, logrus.TraceLevel, logger.GetLevel())
+
+	// explicitly set flag has precedence
+	w, err = NewWatcher(path, logrus.New())
+	assert.Nil(t, err)
+	logger = logrus.New()
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String(logLevelFlag, "info", "")
+	assert.Nil(t, fs.Parse([]string{"--loglevel=info"}))
+	w.ApplyLogLevel(logger, fs)
+	assert.Nil(t, ioutil.WriteFile(path, []byte("log:\n  level: debug"), 0644))
+	w.reload()
+	assert.Equal(t, "debug", w.Get().Log.Level)
+	assert.Equal(t, logrus.InfoLevel, logger.GetLevel())
+}
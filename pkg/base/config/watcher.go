@@ -0,0 +1,142 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"flag"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+)
+
+// Watcher holds current config and reloads it when ConfigMap mounted file is changed
+type Watcher struct {
+	path string
+	log  *logrus.Entry
+
+	sync.RWMutex
+	current   *Config
+	listeners []func(*Config)
+}
+
+// NewWatcher is a constructor for Watcher, reads initial config from path
+// Returns Watcher or error if initial config can't be loaded
+func NewWatcher(path string, logger *logrus.Logger) (*Watcher, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Watcher{
+		path:    path,
+		log:     logger.WithField("component", "ConfigWatcher"),
+		current: c,
+	}, nil
+}
+
+// Get returns current config
+func (w *Watcher) Get() *Config {
+	w.RLock()
+	defer w.RUnlock()
+	return w.current
+}
+
+// OnChange registers listener which is called with new config after each successful reload
+func (w *Watcher) OnChange(listener func(*Config)) {
+	w.Lock()
+	defer w.Unlock()
+	w.listeners = append(w.listeners, listener)
+}
+
+// Watch reloads config on each change of the file until stopCh is closed
+// Invalid config is logged and ignored, previous config stays active
+func (w *Watcher) Watch(stopCh <-chan struct{}) error {
+	ll := w.log.WithField("method", "Watch")
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck
+	defer watcher.Close()
+	if err = watcher.Add(w.path); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stopCh:
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				ll.Info("file watcher is closed")
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			// ConfigMap volume replaces file with a new one, so watch should be renewed
+			if event.Op&fsnotify.Remove != 0 {
+				_ = watcher.Remove(w.path)
+				if err = watcher.Add(w.path); err != nil {
+					return err
+				}
+			}
+			w.reload()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			ll.Errorf("file watcher error: %v", err)
+		}
+	}
+}
+
+// reload loads config from file and notifies listeners if it is valid
+func (w *Watcher) reload() {
+	ll := w.log.WithField("method", "reload")
+	c, err := Load(w.path)
+	if err != nil {
+		ll.Errorf("Config isn't reloaded: %v", err)
+		return
+	}
+	w.Lock()
+	w.current = c
+	listeners := append([]func(*Config){}, w.listeners...)
+	w.Unlock()
+
+	ll.Infof("Config %s is reloaded", w.path)
+	for _, listener := range listeners {
+		listener(c)
+	}
+}
+
+// ApplyLogLevel registers listener which sets log level from reloaded config to the logger,
+// level isn't reloaded if it was set explicitly by loglevel flag of the flag set, flag has precedence over config
+func (w *Watcher) ApplyLogLevel(logger *logrus.Logger, fs *flag.FlagSet) {
+	if explicitFlags(fs)[logLevelFlag] {
+		w.log.WithField("method", "ApplyLogLevel").
+			Infof("Log level is set by %s flag, log level of config isn't applied", logLevelFlag)
+		return
+	}
+	w.OnChange(func(c *Config) {
+		if c.Log.Level != "" {
+			logger.SetLevel(base.ParseLogLevel(c.Log.Level))
+		}
+	})
+}
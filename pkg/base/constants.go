@@ -27,6 +27,14 @@ const (
 	RequestUUID CtxKey = "RequestUUID"
 	// VolumeNamespace is the constant for context request
 	VolumeNamespace CtxKey = "VolumeNamespace"
+	// VolumeLocations is the constant for context request, holds locations to which volume is pinned
+	VolumeLocations CtxKey = "VolumeLocations"
+	// VolumePlacementStrategy is the constant for context request, holds name of placement strategy of the volume
+	VolumePlacementStrategy CtxKey = "VolumePlacementStrategy"
+	// VolumeLabels is the constant for context request, holds PVC labels which are propagated to Volume CR
+	VolumeLabels CtxKey = "VolumeLabels"
+	// VolumeAnnotations is the constant for context request, holds PVC annotations which are propagated to Volume CR
+	VolumeAnnotations CtxKey = "VolumeAnnotations"
 	// PluginName is a name of current CSI plugin
 	PluginName = "csi-baremetal"
 	// PluginVersion is a version of current CSI plugin
@@ -34,6 +42,8 @@ const (
 	PluginVersion = "0.0.13"
 	// DefaultDriveMgrEndpoint is the default gRPC endpoint for drivemgr
 	DefaultDriveMgrEndpoint = "tcp://:8888"
+	// DefaultPrivHelperEndpoint is the default gRPC endpoint for privileged helper of the node service
+	DefaultPrivHelperEndpoint = "unix:///run/csi-baremetal/privhelper.sock"
 	// DefaultHealthIP is the default gRPC IP for Health server
 	DefaultHealthIP = ""
 	// DefaultHealthPort is the default gRPC port for Health Server
@@ -41,8 +51,12 @@ const (
 	// DefaultExtenderPort is the default http port for scheduler extender
 	DefaultExtenderPort = 8889
 
-	// KubeletRootPath is the pods' path on the node
-	KubeletRootPath = "/var/lib/kubelet/pods"
+	// DefaultKubeletDir is the default root directory of kubelet on the node
+	DefaultKubeletDir = "/var/lib/kubelet"
+	// KubeletPodsDir is the pods' directory relative to the kubelet root directory
+	KubeletPodsDir = "pods"
+	// KubeletCSIPluginsDir is the directory of CSI plugins relative to the kubelet root directory
+	KubeletCSIPluginsDir = "plugins/kubernetes.io/csi"
 
 	// HostRootPath is root mount
 	HostRootPath = "/hostroot"
@@ -53,6 +67,9 @@ const (
 	// DefaultRequeueForVolume is the interval for volume reconcile
 	DefaultRequeueForVolume = 5 * time.Second
 
+	// DefaultCreateVolumeParallelism is the amount of CreateVolume requests which controller processes in parallel
+	DefaultCreateVolumeParallelism = 10
+
 	// DefaultFsType FS type that used by default
 	DefaultFsType = "xfs"
 
@@ -62,6 +79,82 @@ const (
 	SizeKey = "size"
 	// DefaultNamespace represents default namespace in Kubernetes
 	DefaultNamespace = "default"
+	// NUMANodeKey is a key in volume_context of CreateVolumeResponse with NUMA node of the volume's drive
+	NUMANodeKey = "numaNode"
+	// PVCMetadataKeyPrefix is a prefix of keys in volume_context of CreateVolumeResponse with propagated PVC labels
+	// and annotations, it is followed by the key of label or annotation
+	PVCMetadataKeyPrefix = "pvc.csi-baremetal.dell.com/"
 	// PVCNamespaceKey is a key from volume_context in CreateVolumeRequest of NodePublishVolumeRequest
 	PVCNamespaceKey = "csi.storage.k8s.io/pvc/namespace"
+	// PVCNameKey is a key from volume_context in CreateVolumeRequest of NodePublishVolumeRequest
+	PVCNameKey = "csi.storage.k8s.io/pvc/name"
+	// PinnedDriveKey is a key from StorageClass parameters with serial number or WWN of the drive to which volume is pinned
+	PinnedDriveKey = "pinnedDrive"
+	// PinnedDriveLabelKey is a key from StorageClass parameters with label selector of drives to which volume is pinned
+	PinnedDriveLabelKey = "pinnedDriveLabel"
+	// DriveSelectorKey is a key from StorageClass parameters with label selector of drives from which capacity is taken
+	DriveSelectorKey = "driveSelector"
+	// PlacementStrategyKey is a key from StorageClass parameters with name of the strategy which selects
+	// AvailableCapacity for volume, it overrides strategy set in controller configuration
+	PlacementStrategyKey = "placementStrategy"
+	// ScratchKey is a key from StorageClass parameters which enables scratch mode for volumes,
+	// partitions of scratch volumes aren't wiped on delete, they are reformatted and reused by next scratch volumes
+	ScratchKey = "scratch"
+	// ScratchAllowDataRemanenceKey is a key from StorageClass parameters which has to be set to "true" along with
+	// ScratchKey to acknowledge that data of deleted scratch volume could be read from the drive by next volume
+	ScratchAllowDataRemanenceKey = "scratchAllowDataRemanence"
+	// ImageSourceKey is a key from StorageClass parameters with URL of the image which is written onto the new volume,
+	// supported schemes are http(s)://, s3://bucket/key and oci://registry/repository:tag
+	ImageSourceKey = "imageSource"
+	// ImageChecksumKey is a key from StorageClass parameters with checksum of the image in sha256:<hex> format
+	ImageChecksumKey = "imageChecksum"
+	// ImageSourceOverrideKey is a key from StorageClass parameters which has to be set to "true" to honor
+	// PVCAnnotationImageSource, image source of PVC has to be in the image source allowlist of the driver
+	ImageSourceOverrideKey = "allowImageSourceOverride"
+	// ImageUsernameSecretKey is a key from provisioner secrets of StorageClass with username for private image source
+	ImageUsernameSecretKey = "imageUsername"
+	// ImagePasswordSecretKey is a key from provisioner secrets of StorageClass with password for private image source
+	ImagePasswordSecretKey = "imagePassword"
+	// ImageTokenSecretKey is a key from provisioner secrets of StorageClass with bearer token for private image source
+	ImageTokenSecretKey = "imageToken"
+	// ImageSecretPrefix is a prefix of Secret name which holds image credentials of the volume until it is populated
+	ImageSecretPrefix = "image-credentials-"
+	// IntegrityKey is a key from StorageClass parameters which enables integrity protection of the volume data,
+	// supported values are "dm-integrity" (block layer checksums) and "checksum" (ext4 metadata_csum)
+	IntegrityKey = "integrity"
+	// MkFSOptionsKey is a key from StorageClass parameters with additional mkfs options of the volume file system,
+	// e.g. "-O ^has_journal -I 512" for ext4 or "-m reflink=1" for xfs
+	MkFSOptionsKey = "mkfsOptions"
+	// FormatProfileKey is a key from StorageClass parameters with formatting profile of the volume file system,
+	// "aligned" derives block size and stripe geometry from the device, "hugepage" also aligns it by 2MiB
+	FormatProfileKey = "formatProfile"
+	// ReadCacheKey is a key from StorageClass parameters with size of RAM read cache of the volume (e.g. "4Gi"),
+	// volume is published read-only through overlay of tmpfs with copy of its files over the staged file system
+	ReadCacheKey = "readCache"
+	// IOSchedulerKey is a key from StorageClass parameters with I/O scheduler of the drive of the volume
+	// (none, mq-deadline, bfq or kyber) which is set on stage
+	IOSchedulerKey = "ioScheduler"
+	// NrRequestsKey is a key from StorageClass parameters with nr_requests of the queue of the drive of the volume
+	NrRequestsKey = "nrRequests"
+	// ReadAheadKBKey is a key from StorageClass parameters with read_ahead_kb of the queue of the drive of the volume
+	ReadAheadKBKey = "readAheadKB"
+	// PVCAnnotationPinnedDrive is PVC annotation which overrides PinnedDriveKey parameter of StorageClass
+	PVCAnnotationPinnedDrive = "csi-baremetal.dell.com/pinned-drive"
+	// PVCAnnotationPinnedDriveLabel is PVC annotation which overrides PinnedDriveLabelKey parameter of StorageClass
+	PVCAnnotationPinnedDriveLabel = "csi-baremetal.dell.com/pinned-drive-label"
+	// PVCAnnotationImageSource is PVC annotation which overrides ImageSourceKey parameter of StorageClass
+	PVCAnnotationImageSource = "csi-baremetal.dell.com/image-source"
+	// PVCAnnotationImageChecksum is PVC annotation which overrides ImageChecksumKey parameter of StorageClass
+	PVCAnnotationImageChecksum = "csi-baremetal.dell.com/image-checksum"
+	// PVCAnnotationPrewarm is PVC annotation which enables prewarm of page cache on publish, supported values are
+	// "readahead" (data of the volume is read sequentially) and "command" (prewarm command of the node is run)
+	PVCAnnotationPrewarm = "csi-baremetal.dell.com/prewarm"
+	// LVGPolicyLabel is label of LogicalVolumeGroup with name of LVGPolicy which manages it
+	LVGPolicyLabel = "csi-baremetal.dell.com/lvg-policy"
+	// StatefulSetAnnotationReserveReplicas is StatefulSet annotation with amount of replicas which capacity
+	// is reserved in advance, capacity of replicas which aren't created yet is held in AvailableCapacityReservations
+	StatefulSetAnnotationReserveReplicas = "csi-baremetal.dell.com/reserve-replicas"
+	// ACRAnnotationReservedFor is AvailableCapacityReservation annotation with <namespace>/<name> of PVC
+	// which isn't created yet, it marks reservations created in advance for StatefulSet replicas
+	ACRAnnotationReservedFor = "csi-baremetal.dell.com/reserved-for"
 )
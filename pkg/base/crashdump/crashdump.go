@@ -0,0 +1,163 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crashdump stores details of panics in the cluster, so postmortems don't rely on rotated pod logs
+package crashdump
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+const (
+	// ConfigMapPrefix is the prefix of the ConfigMap name which holds crash dumps of the component
+	ConfigMapPrefix = "crash-dump-"
+	// MaxDumps is the number of the latest crash dumps kept in the ConfigMap
+	MaxDumps = 5
+	// MaxStackSize limits size of the stored stack trace, size of the ConfigMap is limited by 1MiB
+	MaxStackSize = 64 * 1024
+
+	// keyLayout is the time layout of the ConfigMap keys, keys are sorted in the order of the crashes
+	keyLayout = "20060102T150405.000Z"
+	// recordTimeout limits time during which the dying process tries to store the dump
+	recordTimeout = 10 * time.Second
+)
+
+// Recorder stores stack trace of the panic into the ConfigMap of the component
+// and marks the affected Volume CR with Panicked condition
+type Recorder struct {
+	client    *k8s.KubeClient
+	crHelper  *k8s.CRHelper
+	component string
+	log       *logrus.Entry
+}
+
+// NewRecorder is the constructor for Recorder
+// Receives k8s client which namespace is used for the ConfigMap, name of the component (e.g. controller
+// or node-<node name>) and logrus logger
+// Returns an instance of Recorder
+func NewRecorder(client *k8s.KubeClient, component string, logger *logrus.Logger) *Recorder {
+	return &Recorder{
+		client:    client,
+		crHelper:  k8s.NewCRHelper(client, logger),
+		component: component,
+		log:       logger.WithField("component", "CrashRecorder"),
+	}
+}
+
+// ConfigMapName returns name of the ConfigMap with crash dumps of the component
+func (r *Recorder) ConfigMapName() string {
+	return ConfigMapPrefix + r.component
+}
+
+// Recover records panic of the operation and panics again, so the process exits as it would without Recorder.
+// Must be called directly by defer statement, nil Recorder only re-panics
+// Receives name of the operation and ID of the affected volume, empty ID if operation isn't related to the volume
+func (r *Recorder) Recover(operation, volumeID string) {
+	if reason := recover(); reason != nil {
+		r.Record(operation, volumeID, reason, debug.Stack())
+		panic(reason)
+	}
+}
+
+// Record stores dump of the panic, errors are only logged because the process is about to exit
+// Receives name of the operation, ID of the affected volume, value passed to panic and stack trace
+func (r *Recorder) Record(operation, volumeID string, reason interface{}, stack []byte) {
+	if r == nil {
+		return
+	}
+	ll := r.log.WithFields(logrus.Fields{
+		"method":    "Record",
+		"operation": operation,
+		"volumeID":  volumeID,
+	})
+	ll.Errorf("Panic: %v", reason)
+
+	ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	key := now.Format(keyLayout)
+	if err := r.storeDump(ctx, key, formatDump(operation, volumeID, reason, stack)); err != nil {
+		ll.Errorf("Unable to store crash dump in ConfigMap %s: %v", r.ConfigMapName(), err)
+	}
+	if volumeID == "" {
+		return
+	}
+	message := fmt.Sprintf("%s panicked: %v, stack trace is stored in ConfigMap %s/%s under key %s",
+		operation, reason, r.client.Namespace, r.ConfigMapName(), key)
+	if err := r.markVolume(ctx, volumeID, message, metav1.NewTime(now)); err != nil {
+		ll.Errorf("Unable to set Panicked condition of the volume: %v", err)
+	}
+}
+
+// storeDump adds the dump into the ConfigMap of the component, the oldest dumps are removed to keep MaxDumps
+func (r *Recorder) storeDump(ctx context.Context, key, dump string) error {
+	cm := &corev1.ConfigMap{}
+	err := r.client.ReadCR(ctx, r.ConfigMapName(), "", cm)
+	switch {
+	case k8sError.IsNotFound(err):
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: r.ConfigMapName(), Namespace: r.client.Namespace},
+			Data:       map[string]string{key: dump},
+		}
+		return r.client.Create(ctx, cm)
+	case err != nil:
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[key] = dump
+	keys := make([]string, 0, len(cm.Data))
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for len(keys) > MaxDumps {
+		delete(cm.Data, keys[0])
+		keys = keys[1:]
+	}
+	return r.client.Update(ctx, cm)
+}
+
+// markVolume sets Panicked condition of the Volume CR
+func (r *Recorder) markVolume(ctx context.Context, volumeID, message string, now metav1.Time) error {
+	volume, err := r.crHelper.GetVolumeByID(ctx, volumeID)
+	if err != nil {
+		return err
+	}
+	volume.SetPanicked(message, now)
+	return r.client.UpdateStatus(ctx, volume)
+}
+
+// formatDump returns text of the dump, stack trace is truncated to MaxStackSize
+func formatDump(operation, volumeID string, reason interface{}, stack []byte) string {
+	if len(stack) > MaxStackSize {
+		stack = append(stack[:MaxStackSize:MaxStackSize], []byte("\n... truncated")...)
+	}
+	return fmt.Sprintf("operation: %s\nvolumeID: %s\npanic: %v\n\n%s", operation, volumeID, reason, stack)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crashdump
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+const (
+	testNs       = "default"
+	testVolumeID = "pvc-aaaa-bbbb"
+)
+
+var testLogger = logrus.New()
+
+func TestRecorder_Recover(t *testing.T) {
+	client, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	volume := client.ConstructVolumeCR(testVolumeID, testNs, api.Volume{Id: testVolumeID, CSIStatus: apiV1.Created})
+	assert.Nil(t, client.CreateCR(context.Background(), testVolumeID, volume))
+
+	r := NewRecorder(client, "node-1", testLogger)
+	assert.PanicsWithValue(t, "boom", func() {
+		defer r.Recover("volume/Reconcile", testVolumeID)
+		panic("boom")
+	})
+
+	cm := &corev1.ConfigMap{}
+	assert.Nil(t, client.ReadCR(context.Background(), ConfigMapPrefix+"node-1", "", cm))
+	assert.Len(t, cm.Data, 1)
+	for _, dump := range cm.Data {
+		assert.Contains(t, dump, "operation: volume/Reconcile")
+		assert.Contains(t, dump, "panic: boom")
+		assert.Contains(t, dump, "TestRecorder_Recover")
+	}
+
+	assert.Nil(t, client.ReadCR(context.Background(), testVolumeID, testNs, volume))
+	condition := volume.GetCondition(volumecrd.VolumeConditionPanicked)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Contains(t, condition.Message, ConfigMapPrefix+"node-1")
+
+	// nil recorder only panics again
+	var nilRecorder *Recorder
+	assert.PanicsWithValue(t, "boom", func() {
+		defer nilRecorder.Recover("volume/Reconcile", testVolumeID)
+		panic("boom")
+	})
+}
+
+func TestRecorder_storeDump(t *testing.T) {
+	client, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	r := NewRecorder(client, "controller", testLogger)
+
+	keys := []string{"20261016T100000.000Z", "20261016T100001.000Z", "20261016T100002.000Z",
+		"20261016T100003.000Z", "20261016T100004.000Z", "20261016T100005.000Z"}
+	for _, key := range keys {
+		assert.Nil(t, r.storeDump(context.Background(), key, "dump "+key))
+	}
+	cm := &corev1.ConfigMap{}
+	assert.Nil(t, client.ReadCR(context.Background(), r.ConfigMapName(), "", cm))
+	// the oldest dump is removed
+	assert.Len(t, cm.Data, MaxDumps)
+	assert.NotContains(t, cm.Data, keys[0])
+	assert.Equal(t, "dump "+keys[5], cm.Data[keys[5]])
+}
+
+func TestFormatDump(t *testing.T) {
+	stack := []byte(strings.Repeat("a", MaxStackSize+10))
+	dump := formatDump("CreateVolume", "", "boom", stack)
+	assert.True(t, strings.HasSuffix(dump, "... truncated"))
+	assert.Less(t, len(dump), MaxStackSize+100)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package error
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcCodes maps shared errors to codes of CSI gRPC status, the first matched error wins
+var grpcCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{ErrCapacityExhausted, codes.ResourceExhausted},
+	{ErrDriveNotFound, codes.NotFound},
+	{ErrorNotFound, codes.NotFound},
+	{ErrNotClean, codes.FailedPrecondition},
+	{ErrOperationInProgress, codes.DeadlineExceeded},
+	{ErrorEmptyParameter, codes.InvalidArgument},
+	{ErrorFailedParsing, codes.InvalidArgument},
+	{context.DeadlineExceeded, codes.DeadlineExceeded},
+	{context.Canceled, codes.Canceled},
+}
+
+// grpcStatus is implemented by errors of gRPC status package
+type grpcStatus interface {
+	GRPCStatus() *status.Status
+}
+
+// GRPCCode classifies error by the shared errors which it wraps
+// Receives error
+// Returns OK for nil error, code of the wrapped gRPC status if error doesn't wrap any of shared errors
+// or Unknown otherwise
+func GRPCCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	for _, c := range grpcCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	var s grpcStatus
+	if errors.As(err, &s) {
+		return s.GRPCStatus().Code()
+	}
+	return codes.Unknown
+}
+
+// ToGRPCStatus converts error to gRPC status error which is returned by CSI handlers
+// Receives error
+// Returns nil for nil error, error itself if it is gRPC status error already or status error with code
+// from GRPCCode and message of the error otherwise
+func ToGRPCStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(grpcStatus); ok {
+		return err
+	}
+	return status.Error(GRPCCode(err), err.Error())
+}
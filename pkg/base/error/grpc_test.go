@@ -0,0 +1,55 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package error
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCCode(t *testing.T) {
+	for err, code := range map[error]codes.Code{
+		nil: codes.OK,
+		fmt.Errorf("%w: no suitable drive", ErrCapacityExhausted):             codes.ResourceExhausted,
+		fmt.Errorf("%w: drive sn1", ErrDriveNotFound):                         codes.NotFound,
+		fmt.Errorf("volume doesn't exist: %w", ErrorNotFound):                 codes.NotFound,
+		fmt.Errorf("%w: drive sn1 has NOT_CLEAN usage", ErrNotClean):          codes.FailedPrecondition,
+		fmt.Errorf("%w: volume has CREATING status", ErrOperationInProgress):  codes.DeadlineExceeded,
+		fmt.Errorf("unable to read CR: %w", context.Canceled):                 codes.Canceled,
+		fmt.Errorf("wrapped: %w", status.Error(codes.Aborted, "unavailable")): codes.Aborted,
+		errors.New("mount error"):                                             codes.Unknown,
+	} {
+		assert.Equal(t, code, GRPCCode(err), err)
+	}
+}
+
+func TestToGRPCStatus(t *testing.T) {
+	assert.Nil(t, ToGRPCStatus(nil))
+
+	err := ToGRPCStatus(fmt.Errorf("%w: there is no suitable drive", ErrCapacityExhausted))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, "capacity exhausted: there is no suitable drive", status.Convert(err).Message())
+
+	statusErr := status.Error(codes.InvalidArgument, "invalid drive selector")
+	assert.Equal(t, statusErr, ToGRPCStatus(statusErr))
+}
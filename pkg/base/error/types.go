@@ -1,3 +1,5 @@
+// Package error contains errors which are shared by components, errors are wrapped with details by producers
+// and are classified with errors.Is by consumers, GRPCCode maps them to codes of CSI gRPC status
 package error
 
 import "errors"
@@ -8,3 +10,16 @@ var (
 	ErrorEmptyParameter = errors.New("empty parameter")
 	ErrorFailedParsing  = errors.New("failed to parse")
 )
+
+var (
+	// ErrCapacityExhausted indicates that there is no capacity for the volume: no suitable drive, quota or limit
+	// of the node is exceeded
+	ErrCapacityExhausted = errors.New("capacity exhausted")
+	// ErrDriveNotFound indicates that drive isn't found by its location, serial number or selector
+	ErrDriveNotFound = errors.New("drive not found")
+	// ErrNotClean indicates that drive contains data which wasn't created by the driver
+	ErrNotClean = errors.New("drive isn't clean")
+	// ErrOperationInProgress indicates that deadline of the request was exceeded while volume operation is still
+	// performed, request should be repeated to get result of the operation
+	ErrOperationInProgress = errors.New("operation is in progress")
+)
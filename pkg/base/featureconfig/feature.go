@@ -23,6 +23,8 @@ const (
 	FeatureACReservation = "ACReservation"
 	// FeatureNodeIDFromAnnotation store name for NodeIDFromAnnotation feature
 	FeatureNodeIDFromAnnotation = "NodeIDFromAnnotation"
+	// FeatureNUMAHint store name for NUMAHint feature
+	FeatureNUMAHint = "NUMAHint"
 )
 
 // FeatureChecker is a "read" interface for FeatureConfig
@@ -0,0 +1,418 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package imagesource contains code for fetching of volume images from HTTP servers, S3 buckets and OCI registries
+// and for writing them onto the volumes
+package imagesource
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SchemeHTTP is a scheme of the image which is downloaded from HTTP server
+	SchemeHTTP = "http"
+	// SchemeHTTPS is a scheme of the image which is downloaded from HTTPS server
+	SchemeHTTPS = "https"
+	// SchemeS3 is a scheme of the image which is stored as S3 object, s3://bucket/key
+	SchemeS3 = "s3"
+	// SchemeOCI is a scheme of the image which is stored as OCI artifact, oci://registry/repository:tag,
+	// first layer of the artifact is used as image
+	SchemeOCI = "oci"
+
+	// ChecksumSHA256Prefix is a prefix of the image checksum
+	ChecksumSHA256Prefix = "sha256:"
+
+	// S3EndpointTmpl is a template of URL for S3 object, add bucket and key
+	S3EndpointTmpl = "https://%s.s3.amazonaws.com/%s"
+
+	ociManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+	ociDefaultTag           = "latest"
+
+	// responseHeaderTimeout is a time to wait for response headers, body of the image isn't limited by time
+	responseHeaderTimeout = 30 * time.Second
+	// maxRedirects is a number of redirects after which download fails, it is the same as in http.Client
+	maxRedirects = 10
+)
+
+// Validate checks that image source has supported scheme and checksum has supported format
+// Returns error if image source or checksum can't be used
+func Validate(source, checksum string) error {
+	u, err := url.Parse(source)
+	if err != nil {
+		return fmt.Errorf("invalid image source %s: %w", source, err)
+	}
+	if !supportedScheme(u.Scheme) {
+		return fmt.Errorf("unsupported scheme of image source %s", source)
+	}
+	if u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return fmt.Errorf("image source %s has no host or path", source)
+	}
+	if checksum != "" {
+		if _, err := parseChecksum(checksum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func supportedScheme(scheme string) bool {
+	switch scheme {
+	case SchemeHTTP, SchemeHTTPS, SchemeS3, SchemeOCI:
+		return true
+	}
+	return false
+}
+
+// Allowlist is a list of image servers, S3 buckets and registries in scheme://host format
+// which volumes could be populated from
+type Allowlist []string
+
+// ParseAllowlist parses comma separated list of image sources in scheme://host format,
+// for example "https://images.example.com,oci://registry.example.com:5000,s3://bucket"
+// Returns Allowlist or error if some of the sources has unsupported scheme, no host or has path
+func ParseAllowlist(value string) (Allowlist, error) {
+	var list Allowlist
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		u, err := url.Parse(entry)
+		if err != nil || !supportedScheme(u.Scheme) || u.Host == "" || strings.Trim(u.Path, "/") != "" {
+			return nil, fmt.Errorf("invalid image source %s in allowlist, scheme://host is expected", entry)
+		}
+		list = append(list, u.Scheme+"://"+strings.ToLower(u.Host))
+	}
+	return list, nil
+}
+
+// Allows checks whether scheme and host of the image source are in the list
+func (a Allowlist) Allows(source string) bool {
+	u, err := url.Parse(source)
+	if err != nil {
+		return false
+	}
+	origin := urlOrigin(u)
+	for _, entry := range a {
+		if entry == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// urlOrigin returns scheme and host of URL in scheme://host format
+func urlOrigin(u *url.URL) string {
+	return u.Scheme + "://" + strings.ToLower(u.Host)
+}
+
+// Credentials are used to download images from private servers and registries, basic authentication is used when
+// Username is set, Token is sent as bearer token
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// IsEmpty returns true if no credentials are set and image is downloaded anonymously
+func (c Credentials) IsEmpty() bool {
+	return c.Username == "" && c.Password == "" && c.Token == ""
+}
+
+// Validate checks that credentials contain either username with password or token
+func (c Credentials) Validate() error {
+	switch {
+	case c.Token != "" && (c.Username != "" || c.Password != ""):
+		return fmt.Errorf("image credentials can't contain both token and username with password")
+	case c.Username != "" && c.Password == "":
+		return fmt.Errorf("password is required for image username %s", c.Username)
+	case c.Username == "" && c.Password != "":
+		return fmt.Errorf("username is required for image password")
+	}
+	return nil
+}
+
+// Fetcher downloads images of the volumes
+type Fetcher struct {
+	client *http.Client
+	// allowlist restricts sources of the images if it isn't empty
+	allowlist Allowlist
+	// s3EndpointTmpl is a template of URL for S3 object, could be overridden in tests
+	s3EndpointTmpl string
+	log            *logrus.Entry
+}
+
+// NewFetcher is a constructor for Fetcher
+func NewFetcher(log *logrus.Logger) *Fetcher {
+	f := &Fetcher{
+		client: &http.Client{Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			ResponseHeaderTimeout: responseHeaderTimeout,
+		}},
+		s3EndpointTmpl: S3EndpointTmpl,
+		log:            log.WithField("component", "ImageFetcher"),
+	}
+	f.client.CheckRedirect = f.checkRedirect
+	return f
+}
+
+// checkRedirect allows redirects within the origin of the first request, redirects to other servers are followed
+// only if they are in the allowlist, so image servers and registries can't send the node to arbitrary hosts
+func (f *Fetcher) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if len(f.allowlist) == 0 || urlOrigin(req.URL) == urlOrigin(via[0].URL) || f.allowlist.Allows(req.URL.String()) {
+		return nil
+	}
+	return fmt.Errorf("redirect to %s isn't in the allowlist", urlOrigin(req.URL))
+}
+
+// SetAllowlist restricts sources of the images, images are downloaded from any source if allowlist is empty
+func (f *Fetcher) SetAllowlist(allowlist Allowlist) {
+	f.allowlist = allowlist
+}
+
+// Open starts download of the image from source
+// Content of OCI artifact is verified with digest of its layer when it is read till the end
+// Receives golang context, URL of the image and credentials, empty credentials are used for public images
+// Returns content of the image which should be closed by the caller or error if download wasn't started
+func (f *Fetcher) Open(ctx context.Context, source string, creds Credentials) (io.ReadCloser, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image source %s: %w", source, err)
+	}
+	if len(f.allowlist) > 0 && !f.allowlist.Allows(source) {
+		return nil, fmt.Errorf("image source %s isn't in the allowlist", source)
+	}
+	f.log.WithField("method", "Open").Infof("Fetching image %s", source)
+
+	switch u.Scheme {
+	case SchemeHTTP, SchemeHTTPS:
+		return f.get(ctx, source, "", creds)
+	case SchemeS3:
+		return f.get(ctx, fmt.Sprintf(f.s3EndpointTmpl, u.Host, strings.TrimPrefix(u.Path, "/")), "", creds)
+	case SchemeOCI:
+		return f.openOCI(ctx, u, creds)
+	}
+	return nil, fmt.Errorf("unsupported scheme of image source %s", source)
+}
+
+// get sends GET request to rawURL and returns body of the response with 200 status
+func (f *Fetcher) get(ctx context.Context, rawURL, accept string, creds Credentials) (io.ReadCloser, error) {
+	resp, err := f.do(ctx, rawURL, accept, creds)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unable to download %s: %s", rawURL, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (f *Fetcher) do(ctx context.Context, rawURL, accept string, creds Credentials) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	switch {
+	case creds.Username != "":
+		req.SetBasicAuth(creds.Username, creds.Password)
+	case creds.Token != "":
+		req.Header.Set("Authorization", "Bearer "+creds.Token)
+	}
+	return f.client.Do(req)
+}
+
+// ociManifest is a part of OCI image manifest which is required to find layer with image
+type ociManifest struct {
+	Layers []struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+		Size      int64  `json:"size"`
+	} `json:"layers"`
+}
+
+// openOCI downloads first layer of OCI artifact, token from credentials is used as is, otherwise token is requested
+// if registry requires it (anonymously or with username and password from credentials)
+func (f *Fetcher) openOCI(ctx context.Context, u *url.URL, creds Credentials) (io.ReadCloser, error) {
+	repository, reference := parseOCIReference(strings.TrimPrefix(u.Path, "/"))
+	repoURL := fmt.Sprintf("https://%s/v2/%s", u.Host, repository)
+	accept := ociManifestMediaType + ", " + dockerManifestMediaType
+
+	manifestURL := fmt.Sprintf("%s/manifests/%s", repoURL, reference)
+	auth := Credentials{Token: creds.Token}
+	resp, err := f.do(ctx, manifestURL, accept, auth)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && creds.Token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+		if auth.Token, err = f.requestToken(ctx, u.Host, challenge, creds); err != nil {
+			return nil, err
+		}
+		if resp, err = f.do(ctx, manifestURL, accept, auth); err != nil {
+			return nil, err
+		}
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to download manifest %s: %s", manifestURL, resp.Status)
+	}
+
+	manifest := &ociManifest{}
+	if err = json.NewDecoder(resp.Body).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("unable to decode manifest %s: %w", manifestURL, err)
+	}
+	if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("manifest %s has no layers", manifestURL)
+	}
+	digest := manifest.Layers[0].Digest
+
+	blob, err := f.get(ctx, fmt.Sprintf("%s/blobs/%s", repoURL, digest), "", auth)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifyingReader(blob, digest)
+}
+
+// requestToken requests bearer token according to WWW-Authenticate challenge of the registry,
+// username and password from credentials are used for authentication on token service if they are set.
+// Token service should be on the registry host or in the allowlist
+func (f *Fetcher) requestToken(ctx context.Context, registry, challenge string, creds Credentials) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported authentication challenge of registry: %s", challenge)
+	}
+	params := make(map[string]string)
+	for _, part := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		if kv := strings.SplitN(strings.TrimSpace(part), "=", 2); len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid realm in authentication challenge of registry: %s", challenge)
+	}
+	if len(f.allowlist) > 0 && urlOrigin(realm) != SchemeHTTPS+"://"+strings.ToLower(registry) &&
+		!f.allowlist.Allows(realm.String()) {
+		return "", fmt.Errorf("token service %s of registry %s isn't in the allowlist", urlOrigin(realm), registry)
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value, ok := params[key]; ok {
+			query.Set(key, value)
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	body, err := f.get(ctx, realm.String(), "", Credentials{Username: creds.Username, Password: creds.Password})
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = body.Close() }()
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(body).Decode(&token); err != nil {
+		return "", fmt.Errorf("unable to decode registry token: %w", err)
+	}
+	if token.Token == "" {
+		return token.AccessToken, nil
+	}
+	return token.Token, nil
+}
+
+// parseOCIReference splits path of OCI artifact into repository and tag or digest
+func parseOCIReference(path string) (repository, reference string) {
+	if i := strings.LastIndex(path, "@"); i >= 0 {
+		return path[:i], path[i+1:]
+	}
+	if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") {
+		return path[:i], path[i+1:]
+	}
+	return path, ociDefaultTag
+}
+
+// parseChecksum returns expected sha256 sum from checksum in sha256:<hex> format
+func parseChecksum(checksum string) ([]byte, error) {
+	if !strings.HasPrefix(checksum, ChecksumSHA256Prefix) {
+		return nil, fmt.Errorf("unsupported checksum %s, only %s is supported", checksum, ChecksumSHA256Prefix)
+	}
+	sum, err := hex.DecodeString(strings.TrimPrefix(checksum, ChecksumSHA256Prefix))
+	if err != nil || len(sum) != sha256.Size {
+		return nil, fmt.Errorf("invalid sha256 checksum %s", checksum)
+	}
+	return sum, nil
+}
+
+// verifyingReader computes checksum of the content and returns error instead of EOF if checksum doesn't match
+type verifyingReader struct {
+	io.ReadCloser
+	hash     hash.Hash
+	expected []byte
+	checksum string
+}
+
+// NewVerifyingReader wraps reader to verify its content against checksum in sha256:<hex> format
+func NewVerifyingReader(r io.ReadCloser, checksum string) (io.ReadCloser, error) {
+	expected, err := parseChecksum(checksum)
+	if err != nil {
+		_ = r.Close()
+		return nil, err
+	}
+	return &verifyingReader{ReadCloser: r, hash: sha256.New(), expected: expected, checksum: checksum}, nil
+}
+
+// Read reads content and verifies its checksum when content ends
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.ReadCloser.Read(p)
+	v.hash.Write(p[:n])
+	if err == io.EOF {
+		if actual := v.hash.Sum(nil); !bytes.Equal(actual, v.expected) {
+			return n, fmt.Errorf("checksum of the image %s%x doesn't match %s",
+				ChecksumSHA256Prefix, actual, v.checksum)
+		}
+	}
+	return n, err
+}
+
+// Drain reads rest of the content, is used to verify checksum of the content which wasn't read till the end
+func Drain(r io.Reader) error {
+	_, err := io.Copy(ioutil.Discard, r)
+	return err
+}
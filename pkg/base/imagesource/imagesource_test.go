@@ -0,0 +1,302 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagesource
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testCtx     = context.Background()
+	testContent = []byte("pre-seeded dataset")
+	testSum     = fmt.Sprintf("%s%x", ChecksumSHA256Prefix, sha256.Sum256(testContent))
+)
+
+func TestValidate(t *testing.T) {
+	for _, source := range []string{
+		"http://images.local/dataset.tar", "https://images.local/a/b.img",
+		"s3://bucket/dataset.tar.gz", "oci://registry.local/datasets/mnist:v1",
+	} {
+		assert.Nil(t, Validate(source, ""), source)
+	}
+	assert.Nil(t, Validate("https://images.local/dataset.tar", testSum))
+
+	assert.NotNil(t, Validate("ftp://images.local/dataset.tar", ""))
+	assert.NotNil(t, Validate("s3://bucket", ""))
+	assert.NotNil(t, Validate("https://images.local/dataset.tar", "md5:1234"))
+	assert.NotNil(t, Validate("https://images.local/dataset.tar", "sha256:1234"))
+}
+
+func TestFetcher_OpenHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bucket/dataset.img" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(testContent)
+	}))
+	defer server.Close()
+
+	f := NewFetcher(logrus.New())
+	f.s3EndpointTmpl = server.URL + "/%s/%s"
+
+	for _, source := range []string{server.URL + "/bucket/dataset.img", "s3://bucket/dataset.img"} {
+		rc, err := f.Open(testCtx, source, Credentials{})
+		assert.Nil(t, err)
+		content, err := ioutil.ReadAll(rc)
+		assert.Nil(t, err)
+		assert.Equal(t, testContent, content)
+		assert.Nil(t, rc.Close())
+	}
+
+	_, err := f.Open(testCtx, server.URL+"/unknown.img", Credentials{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestFetcher_OpenOCI(t *testing.T) {
+	const token = "anonymous-token"
+	var (
+		digest = testSum
+		server *httptest.Server
+	)
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			assert.Equal(t, "repository:datasets/mnist:pull", r.URL.Query().Get("scope"))
+			_, _ = fmt.Fprintf(w, `{"token": "%s"}`, token)
+		case r.Header.Get("Authorization") != "Bearer "+token:
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+				`Bearer realm="%s/token",service="registry",scope="repository:datasets/mnist:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/datasets/mnist/manifests/v1":
+			_, _ = fmt.Fprintf(w, `{"layers": [{"mediaType": "application/octet-stream", "digest": "%s"}]}`, digest)
+		case r.URL.Path == "/v2/datasets/mnist/blobs/"+digest:
+			_, _ = w.Write(testContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	f := NewFetcher(logrus.New())
+	f.client = server.Client()
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	rc, err := f.Open(testCtx, "oci://"+host+"/datasets/mnist:v1", Credentials{})
+	assert.Nil(t, err)
+	content, err := ioutil.ReadAll(rc)
+	assert.Nil(t, err)
+	assert.Equal(t, testContent, content)
+
+	// layer doesn't match its digest
+	digest = ChecksumSHA256Prefix + strings.Repeat("0", 64)
+	rc, err = f.Open(testCtx, "oci://"+host+"/datasets/mnist:v1", Credentials{})
+	assert.Nil(t, err)
+	_, err = ioutil.ReadAll(rc)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "doesn't match")
+}
+
+func TestFetcher_OpenWithCredentials(t *testing.T) {
+	const token = "user-token"
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, basic := r.BasicAuth()
+		switch {
+		case r.URL.Path == "/private/dataset.img" && basic && username == "user" && password == "secret":
+			_, _ = w.Write(testContent)
+		case r.URL.Path == "/token" && basic && username == "user" && password == "secret":
+			_, _ = fmt.Fprintf(w, `{"access_token": "%s"}`, token)
+		case r.URL.Path == "/token":
+			w.WriteHeader(http.StatusForbidden)
+		case r.Header.Get("Authorization") != "Bearer "+token:
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/datasets/private/manifests/latest":
+			_, _ = fmt.Fprintf(w, `{"layers": [{"mediaType": "application/octet-stream", "digest": "%s"}]}`, testSum)
+		case r.URL.Path == "/v2/datasets/private/blobs/"+testSum:
+			_, _ = w.Write(testContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	f := NewFetcher(logrus.New())
+	f.client = server.Client()
+	host := strings.TrimPrefix(server.URL, "https://")
+	basic := Credentials{Username: "user", Password: "secret"}
+
+	for _, tc := range []struct {
+		source string
+		creds  Credentials
+	}{
+		{server.URL + "/private/dataset.img", basic},
+		{"oci://" + host + "/datasets/private", basic},
+		{"oci://" + host + "/datasets/private", Credentials{Token: token}},
+	} {
+		rc, err := f.Open(testCtx, tc.source, tc.creds)
+		assert.Nil(t, err, tc.source)
+		content, err := ioutil.ReadAll(rc)
+		assert.Nil(t, err, tc.source)
+		assert.Equal(t, testContent, content, tc.source)
+	}
+
+	// anonymous access is rejected
+	_, err := f.Open(testCtx, server.URL+"/private/dataset.img", Credentials{})
+	assert.NotNil(t, err)
+	_, err = f.Open(testCtx, "oci://"+host+"/datasets/private", Credentials{})
+	assert.NotNil(t, err)
+}
+
+func TestCredentials_Validate(t *testing.T) {
+	assert.Nil(t, Credentials{}.Validate())
+	assert.Nil(t, Credentials{Username: "user", Password: "secret"}.Validate())
+	assert.Nil(t, Credentials{Token: "token"}.Validate())
+	assert.NotNil(t, Credentials{Username: "user", Token: "token"}.Validate())
+	assert.NotNil(t, Credentials{Username: "user"}.Validate())
+	assert.NotNil(t, Credentials{Password: "secret"}.Validate())
+}
+
+func TestParseOCIReference(t *testing.T) {
+	for path, expected := range map[string][2]string{
+		"datasets/mnist:v1":            {"datasets/mnist", "v1"},
+		"datasets/mnist":               {"datasets/mnist", ociDefaultTag},
+		"datasets/mnist@sha256:abcdef": {"datasets/mnist", "sha256:abcdef"},
+	} {
+		repository, reference := parseOCIReference(path)
+		assert.Equal(t, expected, [2]string{repository, reference}, path)
+	}
+}
+
+func TestNewVerifyingReader(t *testing.T) {
+	rc, err := NewVerifyingReader(ioutil.NopCloser(strings.NewReader(string(testContent))), testSum)
+	assert.Nil(t, err)
+	assert.Nil(t, Drain(rc))
+
+	rc, err = NewVerifyingReader(ioutil.NopCloser(strings.NewReader("other content")), testSum)
+	assert.Nil(t, err)
+	assert.NotNil(t, Drain(rc))
+
+	_, err = NewVerifyingReader(ioutil.NopCloser(strings.NewReader("")), "sha1:1234")
+	assert.NotNil(t, err)
+}
+
+func TestAllowlist(t *testing.T) {
+	list, err := ParseAllowlist("https://Images.local, oci://registry.local:5000,,s3://bucket")
+	assert.Nil(t, err)
+	assert.Equal(t, Allowlist{"https://images.local", "oci://registry.local:5000", "s3://bucket"}, list)
+
+	assert.True(t, list.Allows("https://images.local/dataset.tar"))
+	assert.True(t, list.Allows("oci://registry.local:5000/datasets/mnist:v1"))
+	assert.True(t, list.Allows("s3://bucket/dataset.tar"))
+	assert.False(t, list.Allows("http://images.local/dataset.tar"))
+	assert.False(t, list.Allows("https://169.254.169.254/latest/meta-data"))
+	assert.False(t, list.Allows("oci://registry.local/datasets/mnist:v1"))
+	assert.False(t, list.Allows("::"))
+	assert.False(t, Allowlist{}.Allows("https://images.local/dataset.tar"))
+
+	for _, value := range []string{"ftp://images.local", "images.local", "https://images.local/datasets"} {
+		_, err = ParseAllowlist(value)
+		assert.NotNil(t, err, value)
+	}
+
+	f := NewFetcher(logrus.New())
+	f.SetAllowlist(list)
+	_, err = f.Open(testCtx, "http://127.0.0.1/dataset.img", Credentials{})
+	assert.NotNil(t, err)
+}
+
+func TestFetcher_AllowlistRedirect(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(testContent)
+	}))
+	defer other.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved.img":
+			http.Redirect(w, r, "/dataset.img", http.StatusFound)
+		case "/external.img":
+			http.Redirect(w, r, other.URL+"/dataset.img", http.StatusFound)
+		default:
+			_, _ = w.Write(testContent)
+		}
+	}))
+	defer server.Close()
+
+	list, err := ParseAllowlist(server.URL)
+	assert.Nil(t, err)
+	f := NewFetcher(logrus.New())
+	f.SetAllowlist(list)
+
+	// redirect within the server is followed
+	rc, err := f.Open(testCtx, server.URL+"/moved.img", Credentials{})
+	assert.Nil(t, err)
+	assert.Nil(t, rc.Close())
+
+	// redirect to the server which isn't in the allowlist
+	_, err = f.Open(testCtx, server.URL+"/external.img", Credentials{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "isn't in the allowlist")
+
+	list = append(list, other.URL)
+	f.SetAllowlist(list)
+	rc, err = f.Open(testCtx, server.URL+"/external.img", Credentials{})
+	assert.Nil(t, err)
+	assert.Nil(t, rc.Close())
+}
+
+func TestFetcher_AllowlistTokenRealm(t *testing.T) {
+	tokenServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"token": "token"}`)
+	}))
+	defer tokenServer.Close()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token"`, tokenServer.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	list, err := ParseAllowlist("oci://" + host)
+	assert.Nil(t, err)
+	f := NewFetcher(logrus.New())
+	f.client = server.Client()
+	f.SetAllowlist(list)
+
+	_, err = f.Open(testCtx, "oci://"+host+"/datasets/mnist:v1", Credentials{Username: "user", Password: "pass"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "isn't in the allowlist")
+}
+
+func TestFetcher_OpenInvalidSource(t *testing.T) {
+	_, err := NewFetcher(logrus.New()).Open(testCtx, "ftp://images.local/dataset.img", Credentials{})
+	assert.NotNil(t, err)
+	_, err = NewFetcher(logrus.New()).Open(testCtx, "::", Credentials{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagesource
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gzipMagic is a header of gzip compressed content
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// WriteRaw writes content of raw image onto block device
+// Receives content of the image and path of the device
+// Returns error if something went wrong
+func WriteRaw(image io.Reader, device string) error {
+	f, err := os.OpenFile(device, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("unable to open device %s: %w", device, err)
+	}
+	if _, err = io.Copy(f, image); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("unable to write image to device %s: %w", device, err)
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("unable to sync device %s: %w", device, err)
+	}
+	return f.Close()
+}
+
+// ExtractTar extracts tar archive (optionally gzip compressed) into directory
+// Entries which point outside of the directory are rejected, special files are skipped
+// Receives content of the image and path of the directory
+// Returns error if something went wrong
+func ExtractTar(image io.Reader, dir string) error {
+	br := bufio.NewReader(image)
+	var r io.Reader = br
+	if magic, err := br.Peek(len(gzipMagic)); err == nil && string(magic) == string(gzipMagic) {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("unable to decompress image: %w", err)
+		}
+		defer func() { _ = gr.Close() }()
+		r = gr
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read image archive: %w", err)
+		}
+		if err = extractEntry(tr, hdr, dir); err != nil {
+			return err
+		}
+	}
+	// rest of the content is read to let the caller verify checksum of the whole image
+	return Drain(br)
+}
+
+// extractEntry creates file, directory or link from tar header in directory dir
+func extractEntry(tr *tar.Reader, hdr *tar.Header, dir string) error {
+	target, err := securePath(dir, hdr.Name)
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(hdr.Mode).Perm()
+	// existing file or symlink is replaced to not write through it
+	if fi, err := os.Lstat(target); err == nil && !fi.IsDir() && hdr.Typeflag != tar.TypeDir {
+		if err = os.Remove(target); err != nil {
+			return err
+		}
+	}
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err = os.MkdirAll(target, mode); err != nil {
+			return err
+		}
+	case tar.TypeReg, tar.TypeRegA:
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(f, tr); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("unable to extract %s: %w", hdr.Name, err)
+		}
+		if err = f.Close(); err != nil {
+			return err
+		}
+	case tar.TypeSymlink:
+		// symlink could point anywhere, entries aren't written through symlinks which point outside of dir
+		if err = os.Symlink(hdr.Linkname, target); err != nil {
+			return err
+		}
+	case tar.TypeLink:
+		source, err := securePath(dir, hdr.Linkname)
+		if err != nil {
+			return err
+		}
+		if err = os.Link(source, target); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	if os.Geteuid() == 0 {
+		return os.Lchown(target, hdr.Uid, hdr.Gid)
+	}
+	return nil
+}
+
+// securePath returns path of the entry inside of directory dir, missing parent directories are created
+// Returns error if path or its parent directory (through symlinks) points outside of dir
+func securePath(dir, name string) (string, error) {
+	target := filepath.Join(dir, filepath.Clean(string(filepath.Separator)+name))
+	if target == filepath.Clean(dir) {
+		return target, nil
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	// resolve the deepest existing parent, directories below it are created as is
+	existing := filepath.Dir(target)
+	for {
+		if _, err = os.Lstat(existing); err == nil || existing == filepath.Clean(dir) {
+			break
+		}
+		existing = filepath.Dir(existing)
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", err
+	}
+	if resolved != root && !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("entry %s of the image points outside of the volume", name)
+	}
+	if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", err
+	}
+	return target, nil
+}
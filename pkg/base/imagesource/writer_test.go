@@ -0,0 +1,141 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagesource
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	content  string
+}
+
+func buildTar(t *testing.T, compress bool, entries ...tarEntry) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	var (
+		gw *gzip.Writer
+		tw *tar.Writer
+	)
+	if compress {
+		gw = gzip.NewWriter(buf)
+		tw = tar.NewWriter(gw)
+	} else {
+		tw = tar.NewWriter(buf)
+	}
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: e.typeflag, Linkname: e.linkname, Mode: 0644,
+			Size: int64(len(e.content))}
+		if e.typeflag == tar.TypeDir {
+			hdr.Mode = 0755
+		}
+		assert.Nil(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(e.content))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tw.Close())
+	if gw != nil {
+		assert.Nil(t, gw.Close())
+	}
+	return buf
+}
+
+func TestExtractTar(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		dir, err := ioutil.TempDir("", "extract")
+		assert.Nil(t, err)
+
+		image := buildTar(t, compress,
+			tarEntry{name: "data/", typeflag: tar.TypeDir},
+			tarEntry{name: "data/train.csv", typeflag: tar.TypeReg, content: "1,2,3"},
+			tarEntry{name: "nested/dir/file", typeflag: tar.TypeReg, content: "nested"},
+			tarEntry{name: "latest", typeflag: tar.TypeSymlink, linkname: "data/train.csv"},
+			tarEntry{name: "copy.csv", typeflag: tar.TypeLink, linkname: "data/train.csv"},
+		)
+		assert.Nil(t, ExtractTar(image, dir))
+
+		for name, expected := range map[string]string{
+			"data/train.csv": "1,2,3", "nested/dir/file": "nested", "latest": "1,2,3", "copy.csv": "1,2,3",
+		} {
+			content, err := ioutil.ReadFile(filepath.Join(dir, name))
+			assert.Nil(t, err, name)
+			assert.Equal(t, expected, string(content), name)
+		}
+		assert.Nil(t, os.RemoveAll(dir))
+	}
+}
+
+func TestExtractTar_OutsideOfDirectory(t *testing.T) {
+	root, err := ioutil.TempDir("", "extract")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(root) }()
+	var (
+		dir     = filepath.Join(root, "volume")
+		outside = filepath.Join(root, "outside")
+	)
+	assert.Nil(t, os.Mkdir(dir, 0755))
+	assert.Nil(t, os.Mkdir(outside, 0755))
+
+	// path traversal is limited by directory
+	assert.Nil(t, ExtractTar(buildTar(t, false,
+		tarEntry{name: "../outside/file", typeflag: tar.TypeReg, content: "data"}), dir))
+	_, err = os.Stat(filepath.Join(outside, "file"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "outside", "file"))
+	assert.Nil(t, err)
+
+	// entry can't be written through symlink which points outside of directory
+	err = ExtractTar(buildTar(t, false,
+		tarEntry{name: "escape", typeflag: tar.TypeSymlink, linkname: outside},
+		tarEntry{name: "escape/new/file", typeflag: tar.TypeReg, content: "data"}), dir)
+	assert.NotNil(t, err)
+	_, err = os.Stat(filepath.Join(outside, "new"))
+	assert.True(t, os.IsNotExist(err))
+
+	// symlink to file outside of directory is replaced, not written through
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(outside, "passwd"), []byte("root"), 0644))
+	assert.Nil(t, ExtractTar(buildTar(t, false,
+		tarEntry{name: "passwd", typeflag: tar.TypeSymlink, linkname: filepath.Join(outside, "passwd")},
+		tarEntry{name: "passwd", typeflag: tar.TypeReg, content: "data"}), dir))
+	content, err := ioutil.ReadFile(filepath.Join(outside, "passwd"))
+	assert.Nil(t, err)
+	assert.Equal(t, "root", string(content))
+}
+
+func TestWriteRaw(t *testing.T) {
+	device, err := ioutil.TempFile("", "device")
+	assert.Nil(t, err)
+	defer func() { _ = os.Remove(device.Name()) }()
+	assert.Nil(t, device.Close())
+
+	assert.Nil(t, WriteRaw(bytes.NewReader(testContent), device.Name()))
+	content, err := ioutil.ReadFile(device.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, testContent, content)
+
+	assert.NotNil(t, WriteRaw(bytes.NewReader(testContent), filepath.Join(device.Name(), "missing")))
+}
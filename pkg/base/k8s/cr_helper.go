@@ -58,17 +58,25 @@ func (cs *CRHelper) SetReader(reader CRReader) *CRHelper {
 	return cs
 }
 
+// readList reads CR list using node index of the reader if node is provided and reader supports it
+func (cs *CRHelper) readList(ctx context.Context, obj runtime.Object, node ...string) error {
+	if indexed, ok := cs.reader.(nodeIndexedReader); ok && len(node) > 0 {
+		return indexed.ReadListByNode(ctx, obj, node[0])
+	}
+	return cs.reader.ReadList(ctx, obj)
+}
+
 // GetACByLocation reads the whole list of AC CRs from a cluster and searches the AC with provided location
-// Receive context and location name which should be equal to AvailableCapacity.Spec.Location
+// Receives golang context and location name which should be equal to AvailableCapacity.Spec.Location
 // Returns a pointer to the instance of accrd.AvailableCapacity or nil
-func (cs *CRHelper) GetACByLocation(location string) (*accrd.AvailableCapacity, error) {
+func (cs *CRHelper) GetACByLocation(ctx context.Context, location string) (*accrd.AvailableCapacity, error) {
 	ll := cs.log.WithFields(logrus.Fields{
 		"method":   "GetACByLocation",
 		"location": location,
 	})
 
 	acList := &accrd.AvailableCapacityList{}
-	if err := cs.reader.ReadList(context.Background(), acList); err != nil {
+	if err := cs.reader.ReadList(ctx, acList); err != nil {
 		ll.Errorf("Failed to get available capacity CR list, error %v", err)
 		return nil, err
 	}
@@ -85,13 +93,13 @@ func (cs *CRHelper) GetACByLocation(location string) (*accrd.AvailableCapacity,
 }
 
 // DeleteACsByNodeID deletes AC CRs for specific node ID
-// Receives unique identifier of the node
+// Receives golang context and unique identifier of the node
 // Returns error or nil
-func (cs *CRHelper) DeleteACsByNodeID(nodeID string) error {
+func (cs *CRHelper) DeleteACsByNodeID(ctx context.Context, nodeID string) error {
 	ll := cs.log.WithFields(logrus.Fields{"method": "DeleteACsByNodeID", "nodeID": nodeID})
 
 	acList := &accrd.AvailableCapacityList{}
-	if err := cs.reader.ReadList(context.Background(), acList); err != nil {
+	if err := cs.reader.ReadList(ctx, acList); err != nil {
 		ll.Errorf("Failed to get available capacity CR list, error %v", err)
 		return err
 	}
@@ -102,7 +110,7 @@ func (cs *CRHelper) DeleteACsByNodeID(nodeID string) error {
 		if strings.EqualFold(ac.Spec.NodeId, nodeID) {
 			// todo fix linter issue - https://github.com/kyoh86/scopelint/issues/5
 			// nolint:scopelint
-			if err := cs.k8sClient.DeleteCR(context.Background(), &ac); err != nil {
+			if err := cs.k8sClient.DeleteCR(ctx, &ac); err != nil {
 				ll.Warningf("Unable to delete AC %s: %s", ac.Name, err)
 				isError = true
 			}
@@ -170,21 +178,25 @@ func (cs *CRHelper) GetLVGByDrive(ctx context.Context, driveUUID string) (*lvgcr
 		ll.Errorf("Failed to get LogicalVolumeGroup CR list, error %v", err)
 		return nil, err
 	}
+	// LogicalVolumeGroup of LVGPolicy could combine several drives
 	for _, lvg := range lvgList.Items {
-		if len(lvg.Spec.Locations) > 0 && lvg.Spec.Locations[0] == driveUUID {
-			return &lvg, nil
+		for _, location := range lvg.Spec.Locations {
+			if location == driveUUID {
+				lvg := lvg
+				return &lvg, nil
+			}
 		}
 	}
 	return nil, nil
 }
 
 // UpdateVolumesOpStatusOnNode updates operational status of volumes on a node without taking into account current state
-// Receives unique identifier of the node and operational status to be set
+// Receives golang context, unique identifier of the node and operational status to be set
 // Returns error or nil
-func (cs *CRHelper) UpdateVolumesOpStatusOnNode(nodeID, opStatus string) error {
+func (cs *CRHelper) UpdateVolumesOpStatusOnNode(ctx context.Context, nodeID, opStatus string) error {
 	ll := cs.log.WithFields(logrus.Fields{"method": "UpdateVolumesOpStatus", "nodeID": nodeID})
 	// TODO: check that operational status is valid https://github.com/dell/csi-baremetal/issues/80
-	volumes, err := cs.GetVolumeCRs(nodeID)
+	volumes, err := cs.GetVolumeCRs(ctx, nodeID)
 	if err != nil {
 		return err
 	}
@@ -193,7 +205,7 @@ func (cs *CRHelper) UpdateVolumesOpStatusOnNode(nodeID, opStatus string) error {
 	for _, volume := range volumes {
 		if volume.Spec.OperationalStatus != opStatus {
 			volume.Spec.OperationalStatus = opStatus
-			ctxWithID := context.WithValue(context.Background(), base.RequestUUID, volume.Spec.Id)
+			ctxWithID := context.WithValue(ctx, base.RequestUUID, volume.Spec.Id)
 			// todo fix linter issue - https://github.com/kyoh86/scopelint/issues/5
 			// nolint:scopelint
 			if err := cs.k8sClient.UpdateCR(ctxWithID, &volume); err != nil {
@@ -211,8 +223,8 @@ func (cs *CRHelper) UpdateVolumesOpStatusOnNode(nodeID, opStatus string) error {
 }
 
 // GetVolumeByID reads volume CRs and returns volumes CR if it .Spec.Id == volId
-func (cs *CRHelper) GetVolumeByID(volID string) (*volumecrd.Volume, error) {
-	volumeCRs, err := cs.GetVolumeCRs()
+func (cs *CRHelper) GetVolumeByID(ctx context.Context, volID string) (*volumecrd.Volume, error) {
+	volumeCRs, err := cs.GetVolumeCRs(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -232,13 +244,13 @@ func (cs *CRHelper) GetVolumeByID(volID string) (*volumecrd.Volume, error) {
 // GetVolumeCRs collect volume CRs that locate on node, use just node[0] element
 // if node isn't provided - return all volume CRs
 // if error occurs - return nil and error
-func (cs *CRHelper) GetVolumeCRs(node ...string) ([]volumecrd.Volume, error) {
+func (cs *CRHelper) GetVolumeCRs(ctx context.Context, node ...string) ([]volumecrd.Volume, error) {
 	var (
 		vList = &volumecrd.VolumeList{}
 		err   error
 	)
 
-	if err = cs.reader.ReadList(context.Background(), vList); err != nil {
+	if err = cs.readList(ctx, vList, node...); err != nil {
 		return nil, err
 	}
 
@@ -257,12 +269,12 @@ func (cs *CRHelper) GetVolumeCRs(node ...string) ([]volumecrd.Volume, error) {
 }
 
 // UpdateDrivesStatusOnNode updates status of drives on a node without taking into account current state
-// Receives unique identifier of the node and status to be set
+// Receives golang context, unique identifier of the node and status to be set
 // Returns error or nil
-func (cs *CRHelper) UpdateDrivesStatusOnNode(nodeID, status string) error {
+func (cs *CRHelper) UpdateDrivesStatusOnNode(ctx context.Context, nodeID, status string) error {
 	ll := cs.log.WithFields(logrus.Fields{"method": "UpdateDrivesStatusOnNode", "nodeID": nodeID})
 	// TODO: check that drive status is valid - https://github.com/dell/csi-baremetal/issues/80
-	drives, _ := cs.GetDriveCRs(nodeID)
+	drives, _ := cs.GetDriveCRs(ctx, nodeID)
 	// node might not have drives reported to CSI. For example, filtered in drive manager level
 	if drives == nil {
 		return nil
@@ -274,7 +286,7 @@ func (cs *CRHelper) UpdateDrivesStatusOnNode(nodeID, status string) error {
 			drive.Spec.Status = status
 			// todo fix linter issue - https://github.com/kyoh86/scopelint/issues/5
 			// nolint:scopelint
-			if err := cs.k8sClient.UpdateCR(context.Background(), &drive); err != nil {
+			if err := cs.k8sClient.UpdateCR(ctx, &drive); err != nil {
 				ll.Errorf("Unable to update status for drive ID %s: %s", drive.Spec.UUID, err)
 				isError = true
 			}
@@ -291,13 +303,13 @@ func (cs *CRHelper) UpdateDrivesStatusOnNode(nodeID, status string) error {
 // GetDriveCRs collect Drives CR that locate on node, use just node[0] element
 // if node isn't provided - return all Drives CR
 // if error occurs - return nil and error
-func (cs *CRHelper) GetDriveCRs(node ...string) ([]drivecrd.Drive, error) {
+func (cs *CRHelper) GetDriveCRs(ctx context.Context, node ...string) ([]drivecrd.Drive, error) {
 	var (
 		dList = &drivecrd.DriveList{}
 		err   error
 	)
 
-	if err = cs.reader.ReadList(context.Background(), dList); err != nil {
+	if err = cs.readList(ctx, dList, node...); err != nil {
 		return nil, err
 	}
 
@@ -318,13 +330,13 @@ func (cs *CRHelper) GetDriveCRs(node ...string) ([]drivecrd.Drive, error) {
 // GetACCRs collect ACs CR that locate on node, use just node[0] element
 // if node isn't provided - return all ACs CR
 // if error occurs - return nil and error
-func (cs *CRHelper) GetACCRs(node ...string) ([]accrd.AvailableCapacity, error) {
+func (cs *CRHelper) GetACCRs(ctx context.Context, node ...string) ([]accrd.AvailableCapacity, error) {
 	var (
 		acsList = &accrd.AvailableCapacityList{}
 		err     error
 	)
 
-	if err = cs.reader.ReadList(context.Background(), acsList); err != nil {
+	if err = cs.readList(ctx, acsList, node...); err != nil {
 		return nil, err
 	}
 
@@ -343,8 +355,8 @@ func (cs *CRHelper) GetACCRs(node ...string) ([]accrd.AvailableCapacity, error)
 }
 
 // GetDriveCRByUUID reads drive CRs and returns drive CR with uuid dUUID
-func (cs *CRHelper) GetDriveCRByUUID(dUUID string) *drivecrd.Drive {
-	driveCRs, _ := cs.GetDriveCRs()
+func (cs *CRHelper) GetDriveCRByUUID(ctx context.Context, dUUID string) *drivecrd.Drive {
+	driveCRs, _ := cs.GetDriveCRs(ctx)
 	for _, d := range driveCRs {
 		if d.Spec.UUID == dUUID {
 			return &d
@@ -359,7 +371,7 @@ func (cs *CRHelper) GetDriveCRByUUID(dUUID string) *drivecrd.Drive {
 }
 
 // GetDriveCRByVolume reads drive CRs and returns CR for drive on which volume is located
-func (cs *CRHelper) GetDriveCRByVolume(volume *volumecrd.Volume) (*drivecrd.Drive, error) {
+func (cs *CRHelper) GetDriveCRByVolume(ctx context.Context, volume *volumecrd.Volume) (*drivecrd.Drive, error) {
 	ll := cs.log.WithFields(logrus.Fields{
 		"method": "GetDriveCRByVolume",
 		"volume": volume.Name,
@@ -369,7 +381,7 @@ func (cs *CRHelper) GetDriveCRByVolume(volume *volumecrd.Volume) (*drivecrd.Driv
 
 	if volume.Spec.LocationType == apiV1.LocationTypeLVM {
 		lvgObj := &lvgcrd.LogicalVolumeGroup{}
-		err := cs.reader.ReadCR(context.Background(), volume.Spec.Location, "", lvgObj)
+		err := cs.reader.ReadCR(ctx, volume.Spec.Location, "", lvgObj)
 		if err != nil {
 			ll.Errorf("failed to read LogicalVolumeGroup CR list: %s", err.Error())
 			return nil, err
@@ -379,15 +391,15 @@ func (cs *CRHelper) GetDriveCRByVolume(volume *volumecrd.Volume) (*drivecrd.Driv
 		}
 		dUUID = lvgObj.Spec.Locations[0]
 	}
-	return cs.GetDriveCRByUUID(dUUID), nil
+	return cs.GetDriveCRByUUID(ctx, dUUID), nil
 }
 
 // GetVGNameByLVGCRName read LogicalVolumeGroup CR with name lvgCRName and returns LogicalVolumeGroup CR.Spec.Name
 // method is used for LogicalVolumeGroup based on system VG because system VG name != LogicalVolumeGroup CR name
 // in case of error returns empty string and error
-func (cs *CRHelper) GetVGNameByLVGCRName(lvgCRName string) (string, error) {
+func (cs *CRHelper) GetVGNameByLVGCRName(ctx context.Context, lvgCRName string) (string, error) {
 	lvgCR := lvgcrd.LogicalVolumeGroup{}
-	if err := cs.reader.ReadCR(context.Background(), lvgCRName, "", &lvgCR); err != nil {
+	if err := cs.reader.ReadCR(ctx, lvgCRName, "", &lvgCR); err != nil {
 		return "", err
 	}
 	return lvgCR.Spec.Name, nil
@@ -396,13 +408,13 @@ func (cs *CRHelper) GetVGNameByLVGCRName(lvgCRName string) (string, error) {
 // GetLVGCRs collect LogicalVolumeGroup CRs that locate on node, use just node[0] element
 // if node isn't provided - return all volume CRs
 // if error occurs - return nil
-func (cs *CRHelper) GetLVGCRs(node ...string) ([]lvgcrd.LogicalVolumeGroup, error) {
+func (cs *CRHelper) GetLVGCRs(ctx context.Context, node ...string) ([]lvgcrd.LogicalVolumeGroup, error) {
 	var (
 		lvgList = &lvgcrd.LogicalVolumeGroupList{}
 		err     error
 	)
 
-	if err = cs.reader.ReadList(context.Background(), lvgList); err != nil {
+	if err = cs.readList(ctx, lvgList, node...); err != nil {
 		return nil, err
 	}
 
@@ -422,13 +434,13 @@ func (cs *CRHelper) GetLVGCRs(node ...string) ([]lvgcrd.LogicalVolumeGroup, erro
 
 // UpdateVolumeCRSpec reads volume CR with name volName and update it's spec to newSpec
 // returns nil or error in case of error
-func (cs *CRHelper) UpdateVolumeCRSpec(volName string, namespace string, newSpec api.Volume) error {
+func (cs *CRHelper) UpdateVolumeCRSpec(ctx context.Context, volName string, namespace string, newSpec api.Volume) error {
 	var (
 		volumeCR = &volumecrd.Volume{}
 		err      error
 	)
 
-	ctxWithID := context.WithValue(context.Background(), base.RequestUUID, volumeCR.Spec.Id)
+	ctxWithID := context.WithValue(ctx, base.RequestUUID, volumeCR.Spec.Id)
 	if err = cs.reader.ReadCR(ctxWithID, volName, namespace, volumeCR); err != nil {
 		return err
 	}
@@ -446,5 +458,5 @@ func (cs *CRHelper) DeleteObjectByName(ctx context.Context, name string, namespa
 		return err
 	}
 
-	return cs.k8sClient.DeleteCR(context.Background(), obj)
+	return cs.k8sClient.DeleteCR(ctx, obj)
 }
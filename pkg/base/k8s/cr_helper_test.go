@@ -42,12 +42,12 @@ func TestCRHelper_GetACByLocation(t *testing.T) {
 	err := ch.k8sClient.CreateCR(testCtx, expectedAC.Name, &expectedAC)
 	assert.Nil(t, err)
 
-	currentAC, err := ch.GetACByLocation(testACCR.Spec.Location)
+	currentAC, err := ch.GetACByLocation(testCtx, testACCR.Spec.Location)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedAC.Spec, currentAC.Spec)
 
 	// expected nil because of empty string as a location
-	currentAC, err = ch.GetACByLocation("")
+	currentAC, err = ch.GetACByLocation(testCtx, "")
 	assert.Equal(t, err, errTypes.ErrorNotFound)
 }
 
@@ -83,13 +83,13 @@ func TestCRHelper_GetVolumeByID(t *testing.T) {
 	err := ch.k8sClient.CreateCR(testCtx, expectedV.Name, &expectedV)
 	assert.Nil(t, err)
 
-	currentV, err := ch.GetVolumeByID(expectedV.Spec.Id)
+	currentV, err := ch.GetVolumeByID(testCtx, expectedV.Spec.Id)
 	assert.Nil(t, err)
 	assert.NotNil(t, currentV)
 	assert.Equal(t, expectedV.Spec, currentV.Spec)
 
 	// expected nil because of empty string as a ID
-	volume, err := ch.GetVolumeByID("")
+	volume, err := ch.GetVolumeByID(testCtx, "")
 	assert.NotNil(t, err)
 	assert.Nil(t, volume)
 }
@@ -100,12 +100,12 @@ func TestCRHelper_GetDriveCRByUUID(t *testing.T) {
 	err := ch.k8sClient.CreateCR(testCtx, expectedD.Name, &expectedD)
 	assert.Nil(t, err)
 
-	currentD := ch.GetDriveCRByUUID(expectedD.Spec.UUID)
+	currentD := ch.GetDriveCRByUUID(testCtx, expectedD.Spec.UUID)
 	assert.NotNil(t, currentD)
 	assert.Equal(t, expectedD.Spec, currentD.Spec)
 
 	// expected nil because of empty string as a ID
-	assert.Nil(t, ch.GetDriveCRByUUID(""))
+	assert.Nil(t, ch.GetDriveCRByUUID(testCtx, ""))
 }
 
 func TestCRHelper_GetDriveCRByVolume(t *testing.T) {
@@ -121,7 +121,7 @@ func TestCRHelper_GetDriveCRByVolume(t *testing.T) {
 	assert.Nil(t, err)
 	err = ch.k8sClient.CreateCR(testCtx, testDriveCR.Name, &testDriveCR)
 	assert.Nil(t, err)
-	drive, err := ch.GetDriveCRByVolume(expectedV)
+	drive, err := ch.GetDriveCRByVolume(testCtx, expectedV)
 	assert.NotNil(t, drive)
 	assert.Nil(t, err)
 }
@@ -139,12 +139,12 @@ func TestCRHelper_GetVolumeCRs(t *testing.T) {
 	assert.Nil(t, err)
 
 	// node as empty string - expected all volumes
-	currentVs, _ := ch.GetVolumeCRs()
+	currentVs, _ := ch.GetVolumeCRs(testCtx)
 	assert.NotNil(t, currentVs)
 	assert.Equal(t, 2, len(currentVs))
 
 	// expected one volume
-	currentVs, _ = ch.GetVolumeCRs(v1.Spec.NodeId)
+	currentVs, _ = ch.GetVolumeCRs(testCtx, v1.Spec.NodeId)
 	assert.NotNil(t, currentVs)
 	assert.Equal(t, 1, len(currentVs))
 	assert.Equal(t, v1.Spec, currentVs[0].Spec)
@@ -163,12 +163,12 @@ func TestCRHelper_GetDriveCRs(t *testing.T) {
 	assert.Nil(t, err)
 
 	// node as empty string - expected all drives
-	currentDs, _ := ch.GetDriveCRs()
+	currentDs, _ := ch.GetDriveCRs(testCtx)
 	assert.NotNil(t, currentDs)
 	assert.Equal(t, 2, len(currentDs))
 
 	// expected one volume
-	currentDs, _ = ch.GetDriveCRs(d1.Spec.NodeId)
+	currentDs, _ = ch.GetDriveCRs(testCtx, d1.Spec.NodeId)
 	assert.NotNil(t, currentDs)
 	assert.Equal(t, 1, len(currentDs))
 	assert.Equal(t, d1.Spec, currentDs[0].Spec)
@@ -180,12 +180,12 @@ func TestCRHelper_GetVGNameByLVGCRName(t *testing.T) {
 	err := ch.k8sClient.CreateCR(testCtx, lvgCR.Name, &lvgCR)
 	assert.Nil(t, err)
 
-	currentVGName, err := ch.GetVGNameByLVGCRName(lvgCR.Name)
+	currentVGName, err := ch.GetVGNameByLVGCRName(testCtx, lvgCR.Name)
 	assert.Nil(t, err)
 	assert.Equal(t, lvgCR.Spec.Name, currentVGName)
 
 	// expected that LVG will not be found
-	currentVGName, err = ch.GetVGNameByLVGCRName("randomName")
+	currentVGName, err = ch.GetVGNameByLVGCRName(testCtx, "randomName")
 	assert.NotNil(t, err)
 	assert.Equal(t, "", currentVGName)
 }
@@ -196,7 +196,7 @@ func TestCRHelper_DeleteACsByNodeID(t *testing.T) {
 	err := mock.k8sClient.CreateCR(testCtx, testACCR.Name, &testACCR)
 	assert.Nil(t, err)
 
-	err = mock.DeleteACsByNodeID(testACCR.Spec.NodeId)
+	err = mock.DeleteACsByNodeID(testCtx, testACCR.Spec.NodeId)
 	assert.Nil(t, err)
 }
 
@@ -206,10 +206,10 @@ func TestCRHelper_UpdateDrivesStatusOnNode(t *testing.T) {
 	err := mock.k8sClient.CreateCR(testCtx, testDriveCR.Name, &testDriveCR)
 	assert.Nil(t, err)
 
-	err = mock.UpdateDrivesStatusOnNode(testDriveCR.Spec.NodeId, v1.DriveStatusOffline)
+	err = mock.UpdateDrivesStatusOnNode(testCtx, testDriveCR.Spec.NodeId, v1.DriveStatusOffline)
 	assert.Nil(t, err)
 
-	drive := mock.GetDriveCRByUUID(testDriveCR.Name)
+	drive := mock.GetDriveCRByUUID(testCtx, testDriveCR.Name)
 	assert.Equal(t, drive.Spec.Status, v1.DriveStatusOffline)
 }
 
@@ -219,10 +219,10 @@ func TestCRHelper_UpdateVolumesOpStatusOnNode(t *testing.T) {
 	err := mock.k8sClient.CreateCR(testCtx, testVolume.Name, &testVolume)
 	assert.Nil(t, err)
 
-	err = mock.UpdateVolumesOpStatusOnNode(testVolume.Spec.NodeId, v1.OperationalStatusMissing)
+	err = mock.UpdateVolumesOpStatusOnNode(testCtx, testVolume.Spec.NodeId, v1.OperationalStatusMissing)
 	assert.Nil(t, err)
 
-	volume, err := mock.GetVolumeByID(testVolume.Name)
+	volume, err := mock.GetVolumeByID(testCtx, testVolume.Name)
 	assert.Nil(t, err)
 	assert.Equal(t, volume.Spec.OperationalStatus, v1.OperationalStatusMissing)
 }
@@ -0,0 +1,119 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+)
+
+const (
+	// NodeIndexField is the name of the cache index which holds node ID of CR
+	NodeIndexField = "spec.node"
+	// ListPageSize is the amount of objects which are requested from API server by one list call
+	ListPageSize = 500
+)
+
+// nodeIndex describes how node ID is extracted from CR of the kind for which cache index is created
+type nodeIndex struct {
+	obj  runtime.Object
+	list runtime.Object
+	node func(obj runtime.Object) string
+}
+
+// nodeIndexes holds CRs which are located on the particular node and could be listed by node from cache
+var nodeIndexes = []nodeIndex{
+	{obj: &drivecrd.Drive{}, list: &drivecrd.DriveList{},
+		node: func(obj runtime.Object) string { return obj.(*drivecrd.Drive).Spec.NodeId }},
+	{obj: &accrd.AvailableCapacity{}, list: &accrd.AvailableCapacityList{},
+		node: func(obj runtime.Object) string { return obj.(*accrd.AvailableCapacity).Spec.NodeId }},
+	{obj: &volumecrd.Volume{}, list: &volumecrd.VolumeList{},
+		node: func(obj runtime.Object) string { return obj.(*volumecrd.Volume).Spec.NodeId }},
+	{obj: &lvgcrd.LogicalVolumeGroup{}, list: &lvgcrd.LogicalVolumeGroupList{},
+		node: func(obj runtime.Object) string { return obj.(*lvgcrd.LogicalVolumeGroup).Spec.Node }},
+}
+
+// nodeIndexedReader is implemented by readers which are able to list CRs of the node using index
+type nodeIndexedReader interface {
+	// ReadListByNode reads CR list of the node
+	ReadListByNode(ctx context.Context, obj runtime.Object, node string) error
+}
+
+// addNodeIndexes creates node index in indexer for objects which support it
+// Returns types of the lists which could be read by node
+func addNodeIndexes(indexer k8sCl.FieldIndexer, objects ...runtime.Object) (map[reflect.Type]bool, error) {
+	indexed := make(map[reflect.Type]bool)
+	for _, obj := range objects {
+		for _, index := range nodeIndexes {
+			if reflect.TypeOf(obj) != reflect.TypeOf(index.obj) {
+				continue
+			}
+			nodeOf := index.node
+			if err := indexer.IndexField(obj, NodeIndexField, func(o runtime.Object) []string {
+				return []string{nodeOf(o)}
+			}); err != nil {
+				return nil, err
+			}
+			indexed[reflect.TypeOf(index.list)] = true
+		}
+	}
+	return indexed, nil
+}
+
+// listPaged reads list of objects by pages of ListPageSize and merges them into obj
+func listPaged(ctx context.Context, reader k8sCl.Reader, obj runtime.Object) error {
+	var (
+		items     = make([]runtime.Object, 0)
+		continued string
+	)
+	for {
+		page := obj.DeepCopyObject()
+		if meta.LenList(page) > 0 {
+			if err := meta.SetList(page, nil); err != nil {
+				return err
+			}
+		}
+		opts := []k8sCl.ListOption{k8sCl.Limit(ListPageSize)}
+		if continued != "" {
+			opts = append(opts, k8sCl.Continue(continued))
+		}
+		if err := reader.List(ctx, page, opts...); err != nil {
+			return err
+		}
+		pageItems, err := meta.ExtractList(page)
+		if err != nil {
+			return err
+		}
+		items = append(items, pageItems...)
+		listMeta, err := meta.ListAccessor(page)
+		if err != nil {
+			return err
+		}
+		if continued = listMeta.GetContinue(); continued == "" {
+			return meta.SetList(obj, items)
+		}
+	}
+}
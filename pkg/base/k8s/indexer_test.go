@@ -0,0 +1,132 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	nodecrd "github.com/dell/csi-baremetal/api/v1/nodecrd"
+)
+
+// pagedReader imitates API server which returns drives by pages and records options of the list calls
+type pagedReader struct {
+	drives []drivecrd.Drive
+	calls  []*k8sCl.ListOptions
+}
+
+func (r *pagedReader) Get(ctx context.Context, key k8sCl.ObjectKey, obj runtime.Object) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (r *pagedReader) List(ctx context.Context, list runtime.Object, opts ...k8sCl.ListOption) error {
+	listOpts := &k8sCl.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	r.calls = append(r.calls, listOpts)
+
+	start := 0
+	if listOpts.Continue != "" {
+		start, _ = strconv.Atoi(listOpts.Continue)
+	}
+	end := len(r.drives)
+	if listOpts.Limit > 0 && start+int(listOpts.Limit) < end {
+		end = start + int(listOpts.Limit)
+	}
+	dList := list.(*drivecrd.DriveList)
+	dList.Items = append([]drivecrd.Drive{}, r.drives[start:end]...)
+	dList.Continue = ""
+	if end < len(r.drives) {
+		dList.Continue = strconv.Itoa(end)
+	}
+	return nil
+}
+
+// fieldIndexer records indexes which are created
+type fieldIndexer struct {
+	fields map[reflect.Type]k8sCl.IndexerFunc
+}
+
+func (f *fieldIndexer) IndexField(obj runtime.Object, field string, extractValue k8sCl.IndexerFunc) error {
+	if field != NodeIndexField {
+		return fmt.Errorf("unexpected field %s", field)
+	}
+	f.fields[reflect.TypeOf(obj)] = extractValue
+	return nil
+}
+
+func TestListPaged(t *testing.T) {
+	reader := &pagedReader{}
+	for i := 0; i < 2*ListPageSize+1; i++ {
+		reader.drives = append(reader.drives, drivecrd.Drive{
+			ObjectMeta: k8smetav1.ObjectMeta{Name: strconv.Itoa(i)},
+		})
+	}
+
+	dList := &drivecrd.DriveList{}
+	assert.Nil(t, listPaged(context.Background(), reader, dList))
+	assert.Len(t, dList.Items, len(reader.drives))
+	assert.Equal(t, reader.drives[len(reader.drives)-1].Name, dList.Items[len(dList.Items)-1].Name)
+	assert.Len(t, reader.calls, 3)
+	for _, call := range reader.calls {
+		assert.Equal(t, int64(ListPageSize), call.Limit)
+	}
+	assert.Equal(t, "", reader.calls[0].Continue)
+	assert.Equal(t, strconv.Itoa(2*ListPageSize), reader.calls[2].Continue)
+
+	// empty list
+	reader = &pagedReader{}
+	dList = &drivecrd.DriveList{Items: []drivecrd.Drive{{}}}
+	assert.Nil(t, listPaged(context.Background(), reader, dList))
+	assert.Len(t, dList.Items, 0)
+	assert.Len(t, reader.calls, 1)
+}
+
+func TestAddNodeIndexes(t *testing.T) {
+	indexer := &fieldIndexer{fields: make(map[reflect.Type]k8sCl.IndexerFunc)}
+	indexed, err := addNodeIndexes(indexer, &drivecrd.Drive{}, &nodecrd.Node{})
+	assert.Nil(t, err)
+	assert.Equal(t, map[reflect.Type]bool{reflect.TypeOf(&drivecrd.DriveList{}): true}, indexed)
+	assert.Len(t, indexer.fields, 1)
+
+	extract := indexer.fields[reflect.TypeOf(&drivecrd.Drive{})]
+	assert.NotNil(t, extract)
+	assert.Equal(t, []string{testNode1Name}, extract(&drivecrd.Drive{Spec: api.Drive{NodeId: testNode1Name}}))
+}
+
+func TestKubeCache_ReadListByNode(t *testing.T) {
+	reader := &pagedReader{}
+	kubeCache := NewKubeCache(reader, testLogger)
+
+	// index doesn't exist, all CRs are read
+	assert.Nil(t, kubeCache.ReadListByNode(context.Background(), &drivecrd.DriveList{}, testNode1Name))
+	assert.Nil(t, reader.calls[0].FieldSelector)
+
+	kubeCache.nodeIndexed = map[reflect.Type]bool{reflect.TypeOf(&drivecrd.DriveList{}): true}
+	assert.Nil(t, kubeCache.ReadListByNode(context.Background(), &drivecrd.DriveList{}, testNode1Name))
+	assert.NotNil(t, reader.calls[1].FieldSelector)
+	assert.Equal(t, NodeIndexField+"="+testNode1Name, reader.calls[1].FieldSelector.String())
+}
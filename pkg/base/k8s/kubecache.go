@@ -18,6 +18,7 @@ package k8s
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -49,7 +50,9 @@ func GetK8SCache() (cache.Cache, error) {
 // KubeCache is a wrapper for controller-runtime cache
 type KubeCache struct {
 	k8sCl.Reader
-	log *logrus.Entry
+	// types of the lists which could be read by node index
+	nodeIndexed map[reflect.Type]bool
+	log         *logrus.Entry
 }
 
 // ReadCR CRReader implementation
@@ -62,6 +65,14 @@ func (k KubeCache) ReadList(ctx context.Context, obj runtime.Object) error {
 	return k.List(ctx, obj)
 }
 
+// ReadListByNode reads CR list of the node using node index if it exists for CR, otherwise reads all CRs
+func (k KubeCache) ReadListByNode(ctx context.Context, obj runtime.Object, node string) error {
+	if k.nodeIndexed[reflect.TypeOf(obj)] {
+		return k.List(ctx, obj, k8sCl.MatchingFields{NodeIndexField: node})
+	}
+	return k.List(ctx, obj)
+}
+
 // NewKubeCache is the constructor for KubeCache struct
 // Receives basic reader from controller-runtime, logrus logger
 // Returns an instance of KubeCache struct
@@ -74,12 +85,18 @@ func NewKubeCache(reader k8sCl.Reader, logger *logrus.Logger) *KubeCache {
 
 // InitKubeCache creates and starts KubeCache,
 // if objects passed the function will block until cache synced for these objects
+// CRs located on the node are indexed by node ID to be listed without iteration over all CRs of the cluster
 func InitKubeCache(logger *logrus.Logger, stopCH <-chan struct{}, objects ...runtime.Object) (*KubeCache, error) {
 	k8sCache, err := GetK8SCache()
 	if err != nil {
 		logger.Errorf("fail to create cache for kubernetes resources, error: %v", err)
 		return nil, err
 	}
+	nodeIndexed, err := addNodeIndexes(k8sCache, objects...)
+	if err != nil {
+		logger.Errorf("fail to create node index in cache, error: %v", err)
+		return nil, err
+	}
 	for _, obj := range objects {
 		_, err := k8sCache.GetInformer(obj)
 		if err != nil {
@@ -95,5 +112,7 @@ func InitKubeCache(logger *logrus.Logger, stopCH <-chan struct{}, objects ...run
 
 	k8sCache.WaitForCacheSync(stopCH)
 
-	return NewKubeCache(k8sCache, logger), nil
+	kubeCache := NewKubeCache(k8sCache, logger)
+	kubeCache.nodeIndexed = nodeIndexed
+	return kubeCache, nil
 }
@@ -18,6 +18,7 @@ package k8s
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -25,10 +26,11 @@ import (
 	v1 "k8s.io/api/apps/v1"
 	coreV1 "k8s.io/api/core/v1"
 	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	apisV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
-	ctrl "sigs.k8s.io/controller-runtime"
+	"k8s.io/client-go/util/retry"
 	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
@@ -37,7 +39,9 @@ import (
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgpolicycrd"
 	nodecrd "github.com/dell/csi-baremetal/api/v1/nodecrd"
+	quotacrd "github.com/dell/csi-baremetal/api/v1/storagequotacrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/dell/csi-baremetal/pkg/metrics"
@@ -63,6 +67,12 @@ type KubeClient struct {
 	metrics   metrics.Statistic
 }
 
+// statusRefresher is implemented by CRs which status is derived from the spec and stored in status subresource
+type statusRefresher interface {
+	runtime.Object
+	RefreshStatus(now apisV1.Time)
+}
+
 // CRReader is a reader interface for k8s client wrapper
 type CRReader interface {
 	// ReadCR reads CR
@@ -123,11 +133,12 @@ func (k *KubeClient) ReadCR(ctx context.Context, name string, namespace string,
 }
 
 // ReadList reads a list of specified resources into k8s resource List struct (for example v1.PodList)
+// List is requested by pages of ListPageSize objects to not overload API server in large clusters
 // Receives golang context, and List object pointer where to read
 // Returns error if something went wrong
 func (k *KubeClient) ReadList(ctx context.Context, obj runtime.Object) error {
 	defer k.metrics.EvaluateDurationForMethod("ReadList")()
-	return k.List(ctx, obj)
+	return listPaged(ctx, k.Client, obj)
 }
 
 // UpdateCR updates provided resource on k8s cluster
@@ -148,6 +159,29 @@ func (k *KubeClient) UpdateCR(ctx context.Context, obj runtime.Object) error {
 	return k.Update(ctx, obj)
 }
 
+// UpdateStatus stores status of provided resource in status subresource, status which is derived from the spec
+// is refreshed before. Spec update doesn't change status subresource and returns status stored in the API,
+// so status has to be changed after UpdateCR
+// Receives golang context and object that implements k8s runtime.Object interface
+// Returns error if something went wrong
+func (k *KubeClient) UpdateStatus(ctx context.Context, obj runtime.Object) error {
+	defer k.metrics.EvaluateDurationForMethod("UpdateStatus")()
+	requestUUID := ctx.Value(base.RequestUUID)
+	if requestUUID == nil {
+		requestUUID = DefaultVolumeID
+	}
+
+	k.log.WithFields(logrus.Fields{
+		"method":      "UpdateStatus",
+		"requestUUID": requestUUID.(string),
+	}).Debugf("Updating status of CR %s, %v", obj.GetObjectKind().GroupVersionKind().Kind, obj)
+
+	if r, ok := obj.(statusRefresher); ok {
+		r.RefreshStatus(apisV1.Now())
+	}
+	return k.Status().Update(ctx, obj)
+}
+
 // DeleteCR deletes provided resource from k8s cluster
 // Receives golang context and removable object that implements k8s runtime.Object interface
 // Returns error if something went wrong
@@ -267,7 +301,7 @@ func (k *KubeClient) ConstructCSIBMNodeCR(name string, csiNode api.Node) *nodecr
 // with specified amount of attempts. Fails right away if resource is not found
 // Receives golang context, name of the read object, and object pointer where to read
 // Returns error if something went wrong
-func (k *KubeClient) ReadCRWithAttempts(name string, namespace string, obj runtime.Object, attempts int) error {
+func (k *KubeClient) ReadCRWithAttempts(ctx context.Context, name string, namespace string, obj runtime.Object, attempts int) error {
 	ll := k.log.WithFields(logrus.Fields{
 		"method":   "ReadCRWithAttempts",
 		"volumeID": name,
@@ -282,13 +316,17 @@ func (k *KubeClient) ReadCRWithAttempts(name string, namespace string, obj runti
 
 	// read volume into v
 	for i := 0; i < attempts; i++ {
-		if err = k.ReadCR(context.Background(), name, namespace, obj); err == nil {
+		if err = k.ReadCR(ctx, name, namespace, obj); err == nil {
 			return nil
 		} else if k8sError.IsNotFound(err) {
 			return err
 		}
 		ll.Warnf("Unable to read CR: %v. Attempt %d out of %d.", err, i, attempts)
-		<-ticker.C
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("unable to read CR %s: %w", name, ctx.Err())
+		}
 	}
 	return err
 }
@@ -324,6 +362,37 @@ func (k *KubeClient) UpdateCRWithAttempts(ctx context.Context, obj runtime.Objec
 	return err
 }
 
+// UpdateCRWithConflictRetry applies mutate to provided resource and updates it on k8s cluster.
+// On conflict the resource is re-read, mutate is applied to the fresh copy and update is repeated,
+// this allows concurrent requests to change the same CR (e.g. AvailableCapacity size) without losing updates
+// Receives golang context, object that implements k8s runtime.Object interface and function which modifies it
+// Returns error if something went wrong
+func (k *KubeClient) UpdateCRWithConflictRetry(ctx context.Context, obj runtime.Object, mutate func() error) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	ll := k.log.WithFields(logrus.Fields{
+		"method": "UpdateCRWithConflictRetry",
+		"name":   accessor.GetName(),
+	})
+
+	first := true
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if !first {
+			ll.Info("CR was modified concurrently, retrying with the latest version")
+			if err := k.ReadCR(ctx, accessor.GetName(), accessor.GetNamespace(), obj); err != nil {
+				return err
+			}
+		}
+		first = false
+		if err := mutate(); err != nil {
+			return err
+		}
+		return k.UpdateCR(ctx, obj)
+	})
+}
+
 // GetPods returns list of pods which names contain mask
 // Receives golang context and mask for pods filtering
 // Returns slice of coreV1.Pod or error if something went wrong
@@ -362,11 +431,11 @@ func (k *KubeClient) GetNodes(ctx context.Context) ([]coreV1.Node, error) {
 // GetSystemDriveUUIDs returns system drives uuid
 // Receives golang context
 // Returns return slice of string - system drives uuids
-func (k *KubeClient) GetSystemDriveUUIDs() []string {
+func (k *KubeClient) GetSystemDriveUUIDs(ctx context.Context) []string {
 	defer k.metrics.EvaluateDurationForMethod("GetSystemDriveUUIDs")()
 	ll := k.log.WithField("method", "GetSystemDriveUUIDs")
 	var driveList drivecrd.DriveList
-	if err := k.ReadList(context.Background(), &driveList); err != nil {
+	if err := k.ReadList(ctx, &driveList); err != nil {
 		ll.Errorf("Failed to read Drive list, error: %v", err)
 		return nil
 	}
@@ -383,13 +452,14 @@ func (k *KubeClient) GetSystemDriveUUIDs() []string {
 }
 
 // GetK8SClient returns controller-runtime k8s client with modified scheme which includes CSI custom resources
+// Receives client-side rate limits of k8s API calls
 // Returns controller-runtime/pkg/Client which can work with CSI CRs or error if something went wrong
-func GetK8SClient() (k8sCl.Client, error) {
+func GetK8SClient(limits RateLimits) (k8sCl.Client, error) {
 	scheme, err := PrepareScheme()
 	if err != nil {
 		return nil, err
 	}
-	cl, err := k8sCl.New(ctrl.GetConfigOrDie(), k8sCl.Options{
+	cl, err := k8sCl.New(GetRestConfig(limits), k8sCl.Options{
 		Scheme: scheme,
 	})
 	if err != nil {
@@ -434,6 +504,14 @@ func PrepareScheme() (*runtime.Scheme, error) {
 	if err := nodecrd.AddToSchemeCSIBMNode(scheme); err != nil {
 		return nil, err
 	}
+	// register storage quota crd
+	if err := quotacrd.AddToSchemeStorageQuota(scheme); err != nil {
+		return nil, err
+	}
+	// register LVG policy crd
+	if err := lvgpolicycrd.AddToSchemeLVGPolicy(scheme); err != nil {
+		return nil, err
+	}
 
 	return scheme, nil
 }
@@ -18,6 +18,7 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -27,7 +28,10 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/sirupsen/logrus"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
@@ -371,6 +375,118 @@ var _ = Describe("Working with CRD", func() {
 			Expect(err).To(BeNil())
 			Expect(driveCR.Spec).To(Equal(driveCopy.Spec))
 		})
+
+		It("Should Volume status be updated explicitly", func() {
+			volumeCR := testVolume
+			volumeCR.Spec.CSIStatus = apiV1.Creating
+			err := k8sclient.CreateCR(testCtx, testID, &volumeCR)
+			Expect(err).To(BeNil())
+			Expect(volumeCR.Status.Conditions).To(BeEmpty())
+			err = k8sclient.UpdateStatus(testCtx, &volumeCR)
+			Expect(err).To(BeNil())
+			Expect(volumeCR.GetCondition(vcrd.VolumeConditionProvisioned).Status).To(Equal(coreV1.ConditionFalse))
+
+			volumeCR.Spec.CSIStatus = apiV1.Published
+			err = k8sclient.UpdateCR(testCtx, &volumeCR)
+			Expect(err).To(BeNil())
+			Expect(volumeCR.GetCondition(vcrd.VolumeConditionProvisioned).Status).To(Equal(coreV1.ConditionFalse))
+			err = k8sclient.UpdateStatus(testCtx, &volumeCR)
+			Expect(err).To(BeNil())
+
+			rVolume := &vcrd.Volume{}
+			err = k8sclient.ReadCR(testCtx, testID, volumeCR.Namespace, rVolume)
+			Expect(err).To(BeNil())
+			Expect(rVolume.GetCondition(vcrd.VolumeConditionProvisioned).Status).To(Equal(coreV1.ConditionTrue))
+			Expect(rVolume.GetCondition(vcrd.VolumeConditionStaged).Status).To(Equal(coreV1.ConditionTrue))
+			Expect(rVolume.GetCondition(vcrd.VolumeConditionPublished).Status).To(Equal(coreV1.ConditionTrue))
+			Expect(rVolume.GetCondition(vcrd.VolumeConditionErrored).Status).To(Equal(coreV1.ConditionFalse))
+			Expect(rVolume.GetCondition(vcrd.VolumeConditionPublished).Reason).To(Equal(apiV1.Published))
+
+			volumeCR.Spec.CSIStatus = apiV1.Removing
+			err = k8sclient.UpdateCR(testCtx, &volumeCR)
+			Expect(err).To(BeNil())
+			err = k8sclient.UpdateStatus(testCtx, &volumeCR)
+			Expect(err).To(BeNil())
+			Expect(volumeCR.GetCondition(vcrd.VolumeConditionDeletionPending).Status).To(Equal(coreV1.ConditionTrue))
+			Expect(volumeCR.Status.ObservedGeneration).To(Equal(volumeCR.Generation))
+		})
+
+		It("Should Volume status update fail if Volume is removed", func() {
+			volumeCR := testVolume
+			err := k8sclient.UpdateStatus(testCtx, &volumeCR)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("Should Volume phase timestamps be kept on status update", func() {
+			volumeCR := testVolume
+			volumeCR.Spec.CSIStatus = apiV1.Creating
+			err := k8sclient.CreateCR(testCtx, testID, &volumeCR)
+			Expect(err).To(BeNil())
+			err = k8sclient.UpdateStatus(testCtx, &volumeCR)
+			Expect(err).To(BeNil())
+			Expect(volumeCR.Status.PhaseTimestamps).To(HaveKey(vcrd.VolumePhaseACSelected))
+
+			volumeCR.Spec.CSIStatus = apiV1.Created
+			err = k8sclient.UpdateCR(testCtx, &volumeCR)
+			Expect(err).To(BeNil())
+			volumeCR.RecordPhase(vcrd.VolumePhaseFormatted, k8smetav1.Now())
+			err = k8sclient.UpdateStatus(testCtx, &volumeCR)
+			Expect(err).To(BeNil())
+
+			volumeCR.Spec.CSIStatus = apiV1.VolumeReady
+			err = k8sclient.UpdateCR(testCtx, &volumeCR)
+			Expect(err).To(BeNil())
+			err = k8sclient.UpdateStatus(testCtx, &volumeCR)
+			Expect(err).To(BeNil())
+
+			rVolume := &vcrd.Volume{}
+			err = k8sclient.ReadCR(testCtx, testID, volumeCR.Namespace, rVolume)
+			Expect(err).To(BeNil())
+			Expect(rVolume.Status.PhaseTimestamps).To(HaveKey(vcrd.VolumePhaseACSelected))
+			Expect(rVolume.Status.PhaseTimestamps).To(HaveKey(vcrd.VolumePhaseFormatted))
+			Expect(rVolume.Status.PhaseTimestamps).To(HaveKey(vcrd.VolumePhaseStaged))
+			Expect(rVolume.Status.PhaseTimestamps).NotTo(HaveKey(vcrd.VolumePhasePublished))
+		})
+	})
+
+	Context("Update CR with conflict retry", func() {
+		It("Should re-read AC and apply change again on conflict", func() {
+			acCR := testACCR
+			err := k8sclient.CreateCR(testCtx, testACName, &acCR)
+			Expect(err).To(BeNil())
+
+			// another request decreases AC size concurrently
+			cl := NewKubeClient(&conflictClient{Client: k8sclient.Client, conflicts: 1, onConflict: func() {
+				concurrent := &accrd.AvailableCapacity{}
+				Expect(k8sclient.ReadCR(testCtx, testACName, "", concurrent)).To(BeNil())
+				concurrent.Spec.Size -= 100
+				Expect(k8sclient.UpdateCR(testCtx, concurrent)).To(BeNil())
+			}}, testLogger, testNs)
+
+			calls := 0
+			err = cl.UpdateCRWithConflictRetry(testCtx, &acCR, func() error {
+				calls++
+				acCR.Spec.Size -= 200
+				return nil
+			})
+			Expect(err).To(BeNil())
+			Expect(calls).To(Equal(2))
+
+			rAC := &accrd.AvailableCapacity{}
+			Expect(k8sclient.ReadCR(testCtx, testACName, "", rAC)).To(BeNil())
+			Expect(rAC.Spec.Size).To(Equal(testACCR.Spec.Size - 300))
+		})
+
+		It("Should return error of mutate function", func() {
+			acCR := testACCR
+			err := k8sclient.CreateCR(testCtx, testACName, &acCR)
+			Expect(err).To(BeNil())
+
+			err = k8sclient.UpdateCRWithConflictRetry(testCtx, &acCR, func() error {
+				return errors.New("mutate error")
+			})
+			Expect(err).NotTo(BeNil())
+		})
 	})
 
 	Context("Delete CR", func() {
@@ -413,14 +529,14 @@ var _ = Describe("Working with CRD", func() {
 			err := k8sclient.CreateCR(testCtx, testUUID, &testDriveCR)
 			Expect(err).To(BeNil())
 
-			driveUUID := k8sclient.GetSystemDriveUUIDs()
+			driveUUID := k8sclient.GetSystemDriveUUIDs(testCtx)
 			Expect(err).To(BeNil())
 			Expect(driveUUID).To(Equal([]string{}))
 
 			err = k8sclient.CreateCR(testCtx, testUUID2, &testDriveCR2)
 			Expect(err).To(BeNil())
 
-			driveUUID = k8sclient.GetSystemDriveUUIDs()
+			driveUUID = k8sclient.GetSystemDriveUUIDs(testCtx)
 			Expect(err).To(BeNil())
 			Expect(driveUUID).To(Equal([]string{testDriveCR2.Spec.UUID}))
 		})
@@ -481,6 +597,22 @@ var _ = Describe("Constructor methods", func() {
 	})
 })
 
+// conflictClient returns conflict error on first updates and calls onConflict to imitate concurrent modification
+type conflictClient struct {
+	k8sCl.Client
+	conflicts  int
+	onConflict func()
+}
+
+func (c *conflictClient) Update(ctx context.Context, obj runtime.Object, opts ...k8sCl.UpdateOption) error {
+	if c.conflicts > 0 {
+		c.conflicts--
+		c.onConflict()
+		return k8sError.NewConflict(schema.GroupResource{}, "", errors.New("object was modified"))
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
 // remove all crds (volume and ac)
 func removeAllCrds(s *KubeClient) {
 	var (
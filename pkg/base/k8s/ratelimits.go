@@ -0,0 +1,77 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/dell/csi-baremetal/pkg/metrics/common"
+)
+
+// Default client-side rate limits of k8s API calls, they are the same as client-go defaults
+const (
+	DefaultQPS   = 5
+	DefaultBurst = 10
+)
+
+// RateLimits holds client-side rate limits of k8s API calls
+type RateLimits struct {
+	// QPS is the average amount of API calls per second
+	QPS float32
+	// Burst is the amount of API calls which could be done at once above QPS
+	Burst int
+}
+
+// GetRestConfig returns config of k8s API client with provided rate limits, non-positive limits are replaced by defaults
+// Time which API calls wait for the rate limiter is observed by kubeclient_throttle_duration_seconds metric
+func GetRestConfig(limits RateLimits) *rest.Config {
+	if limits.QPS <= 0 {
+		limits.QPS = DefaultQPS
+	}
+	if limits.Burst <= 0 {
+		limits.Burst = DefaultBurst
+	}
+	config := ctrl.GetConfigOrDie()
+	config.QPS = limits.QPS
+	config.Burst = limits.Burst
+	config.RateLimiter = &throttleObserver{
+		RateLimiter: flowcontrol.NewTokenBucketRateLimiter(limits.QPS, limits.Burst),
+	}
+	return config
+}
+
+// throttleObserver is the rate limiter which observes time which API calls wait for it
+type throttleObserver struct {
+	flowcontrol.RateLimiter
+}
+
+// Accept blocks until API call is allowed by rate limiter
+func (t *throttleObserver) Accept() {
+	defer common.KubeclientThrottleDuration.EvaluateDuration(prometheus.Labels{})()
+	t.RateLimiter.Accept()
+}
+
+// Wait blocks until API call is allowed by rate limiter or context is done
+func (t *throttleObserver) Wait(ctx context.Context) error {
+	defer common.KubeclientThrottleDuration.EvaluateDuration(prometheus.Labels{})()
+	return t.RateLimiter.Wait(ctx)
+}
@@ -0,0 +1,48 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// throttleSamples returns amount of observations of kubeclient_throttle_duration_seconds metric
+func throttleSamples(t *testing.T) uint64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.Nil(t, err)
+	for _, family := range families {
+		if family.GetName() == "kubeclient_throttle_duration_seconds" {
+			return family.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	return 0
+}
+
+func TestThrottleObserver(t *testing.T) {
+	limiter := &throttleObserver{RateLimiter: flowcontrol.NewFakeAlwaysRateLimiter()}
+	before := throttleSamples(t)
+
+	limiter.Accept()
+	assert.Nil(t, limiter.Wait(context.Background()))
+	assert.True(t, limiter.TryAccept())
+	assert.Equal(t, before+2, throttleSamples(t))
+}
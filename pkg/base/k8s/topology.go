@@ -0,0 +1,94 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+)
+
+// ParseTopologyLabels parses comma-separated list of node labels which are reported as topology keys,
+// for example "topology.kubernetes.io/zone,example.com/rack"
+func ParseTopologyLabels(value string) []string {
+	labels := make([]string, 0)
+	for _, label := range strings.Split(value, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// TopologySegments returns values of topology labels of the node, labels which aren't set on the node are skipped
+func TopologySegments(node *coreV1.Node, labels []string) map[string]string {
+	segments := make(map[string]string, len(labels))
+	for _, label := range labels {
+		if value, ok := node.GetLabels()[label]; ok {
+			segments[label] = value
+		}
+	}
+	return segments
+}
+
+// GetNodeByID returns k8s node by node ID, which is a k8s node UID or value of node ID annotation
+// Receives golang context and node ID
+// Returns k8s node or error if nodes can't be read or node isn't found
+func (k *KubeClient) GetNodeByID(ctx context.Context, nodeID string) (*coreV1.Node, error) {
+	nodes, err := k.GetNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodes {
+		id, ok := nodes[i].GetAnnotations()[csibmnodeconst.NodeIDAnnotationKey]
+		if string(nodes[i].UID) == nodeID || (ok && id == nodeID) {
+			return &nodes[i], nil
+		}
+	}
+	return nil, fmt.Errorf("node with ID %s isn't found", nodeID)
+}
+
+// WaitNodeIDAnnotation waits till node ID annotation is set on k8s node, operator sets it right after node joins
+// the cluster, so services of the new node start drives discovery without restarts
+// Receives golang context, k8s node name and interval between checks
+// Returns node ID or error if context is done before annotation is set
+func (k *KubeClient) WaitNodeIDAnnotation(ctx context.Context, nodeName string, interval time.Duration) (string, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		node := &coreV1.Node{}
+		err := k.Get(ctx, k8sCl.ObjectKey{Name: nodeName}, node)
+		if err == nil {
+			if id, ok := node.GetAnnotations()[csibmnodeconst.NodeIDAnnotationKey]; ok {
+				return id, nil
+			}
+			err = fmt.Errorf("annotation %s hadn't been set for node %s", csibmnodeconst.NodeIDAnnotationKey, nodeName)
+		}
+		k.log.WithField("method", "WaitNodeIDAnnotation").Infof("Waiting for node ID: %v", err)
+		select {
+		case <-ctx.Done():
+			return "", err
+		case <-ticker.C:
+		}
+	}
+}
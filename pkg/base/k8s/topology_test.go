@@ -0,0 +1,54 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+)
+
+func TestParseTopologyLabels(t *testing.T) {
+	assert.Equal(t, []string{}, ParseTopologyLabels(""))
+	assert.Equal(t, []string{"topology.kubernetes.io/zone", "example.com/rack"},
+		ParseTopologyLabels(" topology.kubernetes.io/zone, ,example.com/rack "))
+}
+
+func TestKubeClient_WaitNodeIDAnnotation(t *testing.T) {
+	k, err := GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	node := &coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-1"}}
+	assert.Nil(t, k.Create(testCtx, node))
+
+	// annotation isn't set till timeout
+	ctx, cancel := context.WithTimeout(testCtx, 50*time.Millisecond)
+	defer cancel()
+	_, err = k.WaitNodeIDAnnotation(ctx, node.Name, 10*time.Millisecond)
+	assert.NotNil(t, err)
+
+	node.Annotations = map[string]string{csibmnodeconst.NodeIDAnnotationKey: "uuid-1"}
+	assert.Nil(t, k.Update(testCtx, node))
+	id, err := k.WaitNodeIDAnnotation(testCtx, node.Name, 10*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, "uuid-1", id)
+}
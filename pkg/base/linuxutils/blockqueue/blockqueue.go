@@ -0,0 +1,275 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package blockqueue contains code for tuning of block layer request queue (I/O scheduler, nr_requests,
+// read_ahead_kb) of block devices and for reading of their I/O geometry through sysfs
+package blockqueue
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils"
+)
+
+const (
+	// SchedulerNone passes requests to the device as is, suits NVMe and other fast devices
+	SchedulerNone = "none"
+	// SchedulerMQDeadline prefers reads and prevents starvation, suits databases on HDD and SSD
+	SchedulerMQDeadline = "mq-deadline"
+	// SchedulerBFQ shares bandwidth fairly between processes, suits mixed workloads on slow devices
+	SchedulerBFQ = "bfq"
+	// SchedulerKyber throttles requests to reach target latencies on fast devices
+	SchedulerKyber = "kyber"
+
+	// attributes of /sys/block/<device>/queue
+	schedulerAttr  = "scheduler"
+	nrRequestsAttr = "nr_requests"
+	readAheadAttr  = "read_ahead_kb"
+	// I/O geometry attributes of the queue
+	logicalBlockSizeAttr  = "logical_block_size"
+	physicalBlockSizeAttr = "physical_block_size"
+	minimumIOSizeAttr     = "minimum_io_size"
+	optimalIOSizeAttr     = "optimal_io_size"
+)
+
+// Settings are block layer queue settings of the device, zero values are kept as they are on the device
+type Settings struct {
+	Scheduler   string
+	NrRequests  int
+	ReadAheadKB int
+}
+
+// IsEmpty returns true if no setting is set
+func (s Settings) IsEmpty() bool {
+	return s == Settings{}
+}
+
+// Merge returns settings where values of the override replace values of s if they are set
+func (s Settings) Merge(override Settings) Settings {
+	if override.Scheduler != "" {
+		s.Scheduler = override.Scheduler
+	}
+	if override.NrRequests != 0 {
+		s.NrRequests = override.NrRequests
+	}
+	if override.ReadAheadKB != 0 {
+		s.ReadAheadKB = override.ReadAheadKB
+	}
+	return s
+}
+
+// String returns settings in "scheduler=<>,nr_requests=<>,read_ahead_kb=<>" format, zero values are omitted
+func (s Settings) String() string {
+	var parts []string
+	if s.Scheduler != "" {
+		parts = append(parts, schedulerAttr+"="+s.Scheduler)
+	}
+	if s.NrRequests != 0 {
+		parts = append(parts, fmt.Sprintf("%s=%d", nrRequestsAttr, s.NrRequests))
+	}
+	if s.ReadAheadKB != 0 {
+		parts = append(parts, fmt.Sprintf("%s=%d", readAheadAttr, s.ReadAheadKB))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Geometry is an I/O geometry of block device reported by kernel in bytes, RAID and striped LVM devices report
+// chunk size as minimum I/O size and full stripe as optimal I/O size, optimal I/O size is 0 if it isn't reported
+type Geometry struct {
+	LogicalBlockSize  int64
+	PhysicalBlockSize int64
+	MinimumIOSize     int64
+	OptimalIOSize     int64
+}
+
+// ParseSettings parses and validates settings, empty values aren't set
+// Receives name of I/O scheduler, nr_requests and read_ahead_kb as strings
+// Returns settings or error if scheduler is unknown or numbers aren't positive
+func ParseSettings(scheduler, nrRequests, readAheadKB string) (Settings, error) {
+	var (
+		s   = Settings{Scheduler: strings.TrimSpace(scheduler)}
+		err error
+	)
+	switch s.Scheduler {
+	case "", SchedulerNone, SchedulerMQDeadline, SchedulerBFQ, SchedulerKyber:
+	default:
+		return Settings{}, fmt.Errorf("unknown I/O scheduler %s, supported values are %s, %s, %s, %s",
+			s.Scheduler, SchedulerNone, SchedulerMQDeadline, SchedulerBFQ, SchedulerKyber)
+	}
+	if s.NrRequests, err = parsePositive(nrRequestsAttr, nrRequests); err != nil {
+		return Settings{}, err
+	}
+	if s.ReadAheadKB, err = parsePositive(readAheadAttr, readAheadKB); err != nil {
+		return Settings{}, err
+	}
+	return s, nil
+}
+
+// ParseByDriveType parses comma-separated settings by drive type, e.g. "HDD=mq-deadline,NVME=none" for scheduler
+// Receives scheduler, nr_requests and read_ahead_kb lists
+// Returns settings by upper case drive type or error if any value is invalid
+func ParseByDriveType(schedulers, nrRequests, readAheadKB string) (map[string]Settings, error) {
+	values := map[string][3]string{}
+	for i, list := range []string{schedulers, nrRequests, readAheadKB} {
+		for _, item := range strings.Split(list, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			parts := strings.SplitN(item, "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				return nil, fmt.Errorf("invalid setting %s, <drive type>=<value> is expected", item)
+			}
+			driveType := strings.ToUpper(strings.TrimSpace(parts[0]))
+			v := values[driveType]
+			v[i] = parts[1]
+			values[driveType] = v
+		}
+	}
+	result := make(map[string]Settings, len(values))
+	for driveType, v := range values {
+		s, err := ParseSettings(v[0], v[1], v[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid settings of drive type %s: %w", driveType, err)
+		}
+		result[driveType] = s
+	}
+	return result, nil
+}
+
+func parsePositive(name, value string) (int, error) {
+	if value = strings.TrimSpace(value); value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s %s, positive number is expected", name, value)
+	}
+	return n, nil
+}
+
+// WrapBlockQueue is an interface that encapsulates tuning of block device request queue
+type WrapBlockQueue interface {
+	Apply(devicePath string, settings Settings) error
+	Geometry(devicePath string) (Geometry, error)
+}
+
+// SysfsWriter writes attributes of sysfs on behalf of the caller, it is implemented by executors which run
+// privileged operations out of the container (privileged helper), since sysfs is read-only in not privileged containers
+type SysfsWriter interface {
+	WriteSysfs(path, value string) error
+}
+
+// BlockQueue is an implementation of WrapBlockQueue interface based on sysfs
+type BlockQueue struct {
+	sysfs string
+	write func(path, value string) error
+	log   *logrus.Entry
+}
+
+// NewBlockQueue is a constructor for BlockQueue struct
+// Receives CmdExecutor, attributes are written by it if it implements SysfsWriter, otherwise directly, and logrus logger
+// Returns an instance of BlockQueue
+func NewBlockQueue(e command.CmdExecutor, logger *logrus.Logger) *BlockQueue {
+	b := &BlockQueue{sysfs: linuxutils.SysfsPath, write: writeAttr, log: logger.WithField("component", "BlockQueue")}
+	if w, ok := e.(SysfsWriter); ok {
+		b.write = w.WriteSysfs
+	}
+	return b
+}
+
+// writeAttr writes attribute of sysfs directly
+func writeAttr(path, value string) error {
+	return ioutil.WriteFile(path, []byte(value), 0644)
+}
+
+// Apply writes settings into request queue of the whole block device with provided path, e.g. /dev/sda,
+// settings are shared by all partitions and volumes of the device
+// Returns error if device doesn't have request queue or setting isn't accepted by kernel (e.g. scheduler isn't loaded)
+func (b *BlockQueue) Apply(devicePath string, settings Settings) error {
+	ll := b.log.WithField("method", "Apply")
+
+	queueDir := filepath.Join(b.sysfs, "block", filepath.Base(devicePath), "queue")
+	for _, attr := range []struct {
+		name  string
+		value string
+	}{
+		// nr_requests depends on the scheduler, so scheduler is set first
+		{schedulerAttr, settings.Scheduler},
+		{nrRequestsAttr, strconv.Itoa(settings.NrRequests)},
+		{readAheadAttr, strconv.Itoa(settings.ReadAheadKB)},
+	} {
+		if attr.value == "" || attr.value == "0" {
+			continue
+		}
+		if err := b.write(filepath.Join(queueDir, attr.name), attr.value); err != nil {
+			return fmt.Errorf("unable to set %s of %s to %s: %w", attr.name, devicePath, attr.value, err)
+		}
+	}
+	ll.Infof("Queue of %s is tuned: %s", devicePath, settings)
+	return nil
+}
+
+// Geometry reads I/O geometry of the block device with provided path, path could be a partition or a symlink
+// to device mapper device (e.g. /dev/<vg>/<lv>), partitions report geometry of the whole device
+// Returns geometry or error if device isn't found in sysfs
+func (b *BlockQueue) Geometry(devicePath string) (Geometry, error) {
+	ll := b.log.WithField("method", "Geometry")
+
+	if resolved, err := filepath.EvalSymlinks(devicePath); err == nil {
+		devicePath = resolved
+	}
+	devDir, err := filepath.EvalSymlinks(filepath.Join(b.sysfs, "class", "block", filepath.Base(devicePath)))
+	if err != nil {
+		return Geometry{}, fmt.Errorf("unable to find %s in sysfs: %w", devicePath, err)
+	}
+	queueDir := filepath.Join(devDir, "queue")
+	if _, err = os.Stat(queueDir); os.IsNotExist(err) {
+		// partition doesn't have request queue, it is located in the directory of the whole device
+		queueDir = filepath.Join(filepath.Dir(devDir), "queue")
+	}
+
+	var (
+		g     Geometry
+		attrs = []struct {
+			name  string
+			value *int64
+		}{
+			{logicalBlockSizeAttr, &g.LogicalBlockSize},
+			{physicalBlockSizeAttr, &g.PhysicalBlockSize},
+			{minimumIOSizeAttr, &g.MinimumIOSize},
+			{optimalIOSizeAttr, &g.OptimalIOSize},
+		}
+	)
+	for _, attr := range attrs {
+		data, err := ioutil.ReadFile(filepath.Join(queueDir, attr.name))
+		if err != nil {
+			return Geometry{}, fmt.Errorf("unable to read %s of %s: %w", attr.name, devicePath, err)
+		}
+		if *attr.value, err = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err != nil {
+			return Geometry{}, fmt.Errorf("invalid %s of %s: %w", attr.name, devicePath, err)
+		}
+	}
+	ll.Debugf("Geometry of %s: %+v", devicePath, g)
+	return g, nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blockqueue
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dell/csi-baremetal/pkg/base/command"
+)
+
+func TestParseSettings(t *testing.T) {
+	s, err := ParseSettings(SchedulerMQDeadline, "256", "")
+	assert.Nil(t, err)
+	assert.Equal(t, Settings{Scheduler: SchedulerMQDeadline, NrRequests: 256}, s)
+	assert.Equal(t, "scheduler=mq-deadline,nr_requests=256", s.String())
+
+	s, err = ParseSettings("", "", "")
+	assert.Nil(t, err)
+	assert.True(t, s.IsEmpty())
+
+	for _, args := range [][3]string{{"cfq", "", ""}, {"", "0", ""}, {"", "", "-1"}, {"", "many", ""}} {
+		_, err = ParseSettings(args[0], args[1], args[2])
+		assert.NotNil(t, err, args)
+	}
+}
+
+func TestParseByDriveType(t *testing.T) {
+	byType, err := ParseByDriveType("HDD=mq-deadline,nvme=none", "HDD=256", "HDD=4096,SSD=128")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]Settings{
+		"HDD":  {Scheduler: SchedulerMQDeadline, NrRequests: 256, ReadAheadKB: 4096},
+		"NVME": {Scheduler: SchedulerNone},
+		"SSD":  {ReadAheadKB: 128},
+	}, byType)
+
+	byType, err = ParseByDriveType("", "", "")
+	assert.Nil(t, err)
+	assert.Empty(t, byType)
+
+	for _, schedulers := range []string{"HDD", "=none", "HDD=noop"} {
+		_, err = ParseByDriveType(schedulers, "", "")
+		assert.NotNil(t, err, schedulers)
+	}
+}
+
+func TestSettings_Merge(t *testing.T) {
+	defaults := Settings{Scheduler: SchedulerMQDeadline, NrRequests: 256}
+	assert.Equal(t, Settings{Scheduler: SchedulerBFQ, NrRequests: 256, ReadAheadKB: 1024},
+		defaults.Merge(Settings{Scheduler: SchedulerBFQ, ReadAheadKB: 1024}))
+	assert.Equal(t, defaults, defaults.Merge(Settings{}))
+}
+
+func TestBlockQueue_Apply(t *testing.T) {
+	root, err := ioutil.TempDir("", "sysfs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(root)
+
+	queueDir := filepath.Join(root, "block", "sda", "queue")
+	assert.Nil(t, os.MkdirAll(queueDir, 0755))
+	for attr, value := range map[string]string{
+		schedulerAttr:  "[none] mq-deadline\n",
+		nrRequestsAttr: "64\n",
+		readAheadAttr:  "128\n",
+	} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(queueDir, attr), []byte(value), 0644))
+	}
+
+	b := NewBlockQueue(command.NewExecutor(logrus.New()), logrus.New())
+	b.sysfs = root
+	assert.Nil(t, b.Apply("/dev/sda", Settings{Scheduler: SchedulerMQDeadline, ReadAheadKB: 4096}))
+	for attr, expected := range map[string]string{
+		schedulerAttr:  SchedulerMQDeadline,
+		nrRequestsAttr: "64\n",
+		readAheadAttr:  "4096",
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(queueDir, attr))
+		assert.Nil(t, err)
+		assert.Equal(t, expected, string(data), attr)
+	}
+
+	// device without request queue
+	assert.NotNil(t, b.Apply("/dev/sdb", Settings{NrRequests: 128}))
+
+	// attributes are written by executor which implements SysfsWriter
+	w := &sysfsWriter{CmdExecutor: command.NewExecutor(logrus.New()), written: map[string]string{}}
+	b = NewBlockQueue(w, logrus.New())
+	b.sysfs = root
+	assert.Nil(t, b.Apply("/dev/sda", Settings{NrRequests: 128}))
+	assert.Equal(t, map[string]string{filepath.Join(queueDir, nrRequestsAttr): "128"}, w.written)
+}
+
+// sysfsWriter records attributes instead of writing them
+type sysfsWriter struct {
+	command.CmdExecutor
+	written map[string]string
+}
+
+func (w *sysfsWriter) WriteSysfs(path, value string) error {
+	w.written[path] = value
+	return nil
+}
+
+func TestBlockQueue_Geometry(t *testing.T) {
+	root, err := ioutil.TempDir("", "sysfs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(root)
+
+	var (
+		deviceDir = filepath.Join(root, "devices", "md0")
+		queueDir  = filepath.Join(deviceDir, "queue")
+		classDir  = filepath.Join(root, "class", "block")
+	)
+	assert.Nil(t, os.MkdirAll(queueDir, 0755))
+	assert.Nil(t, os.MkdirAll(filepath.Join(deviceDir, "md0p1"), 0755))
+	assert.Nil(t, os.MkdirAll(classDir, 0755))
+	assert.Nil(t, os.Symlink(deviceDir, filepath.Join(classDir, "md0")))
+	assert.Nil(t, os.Symlink(filepath.Join(deviceDir, "md0p1"), filepath.Join(classDir, "md0p1")))
+	for attr, value := range map[string]string{
+		logicalBlockSizeAttr:  "512\n",
+		physicalBlockSizeAttr: "4096\n",
+		minimumIOSizeAttr:     "524288\n",
+		optimalIOSizeAttr:     "1572864\n",
+	} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(queueDir, attr), []byte(value), 0644))
+	}
+
+	b := NewBlockQueue(command.NewExecutor(logrus.New()), logrus.New())
+	b.sysfs = root
+	expected := Geometry{LogicalBlockSize: 512, PhysicalBlockSize: 4096, MinimumIOSize: 524288, OptimalIOSize: 1572864}
+	for _, device := range []string{"/dev/md0", "/dev/md0p1"} {
+		g, err := b.Geometry(device)
+		assert.Nil(t, err, device)
+		assert.Equal(t, expected, g, device)
+	}
+
+	_, err = b.Geometry("/dev/sdz")
+	assert.NotNil(t, err)
+
+	assert.Nil(t, os.Remove(filepath.Join(queueDir, optimalIOSizeAttr)))
+	_, err = b.Geometry("/dev/md0")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package linuxutils consists of thin wrappers around system utils that are shared between node and drive managers.
+Each subpackage exposes an interface which is implemented over command.CmdExecutor, so that the code that uses it
+could be covered by unit tests without root privileges or real devices. Mocks for all interfaces are placed in
+pkg/mocks/linuxutils.
+
+Interfaces descriptions:
+1. fs.WrapFS works with file systems: mkfs, wipefs, mount/umount and so on
+2. ipmi.WrapIpmi reads BMC information with ipmitool
+3. lsblk.WrapLsblk lists block devices
+4. lsscsi.WrapLsscsi lists SCSI devices with lsscsi or directly from sysfs if lsscsi can't be used (e.g. on arm64)
+5. lvm.WrapLVM works with LVM: PVs, VGs and LVs
+6. nvmecli.WrapNvmecli lists NVMe devices
+7. partitionhelper.WrapPartition works with partition tables and partitions (parted, sgdisk, partprobe)
+8. smartctl.WrapSmartctl reads SMART information
+9. ses.WrapSES reads drive location in SCSI enclosure (enclosure ID, slot, backplane) directly from sysfs
+10. numa.WrapNUMA reads NUMA node of the drive's PCIe/HBA path directly from sysfs
+11. integrity.WrapIntegrity protects volumes with dm-integrity or ext4 metadata checksums and reads detected errors
+12. ndctl.WrapNdctl lists persistent memory (PMEM) namespaces in fsdax mode
+13. zoned.WrapZoned detects zoned block devices (SMR/ZNS), resets their zones and creates zone aware file systems
+14. blockqueue.WrapBlockQueue sets I/O scheduler, nr_requests and read_ahead_kb of block devices (via privileged helper if it is used) and reads their I/O geometry directly in sysfs
+15. ioerrors.WrapIOErrors counts I/O errors of block devices in sysfs and kernel log
+*/
+package linuxutils
@@ -21,12 +21,14 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"regexp"
 	"strings"
 	"sync"
-	"time"
 
+	"k8s.io/mount-utils"
+
+	"github.com/dell/csi-baremetal/pkg/base/bytesize"
 	"github.com/dell/csi-baremetal/pkg/base/command"
-	"github.com/dell/csi-baremetal/pkg/base/util"
 )
 
 // FileSystem is type for storing FS string representation
@@ -39,6 +41,10 @@ const (
 	EXT4 FileSystem = "ext4"
 	// EXT3 file system
 	EXT3 FileSystem = "ext3"
+	// F2FS file system, supports zoned devices
+	F2FS FileSystem = "f2fs"
+	// BTRFS file system, supports zoned devices
+	BTRFS FileSystem = "btrfs"
 
 	// wipefs is a system utility
 	wipefs = "wipefs "
@@ -66,6 +72,59 @@ const (
 	UnmountCmdTmpl = "umount %s"
 	// BindOption option for mount operation
 	BindOption = "--bind"
+	// DAXOption option for mount operation, enables direct access to persistent memory bypassing page cache
+	DAXOption = "-o dax"
+	// ExtCheckCmdTmpl ext3/ext4 check cmd, add mode (-n - check only, -y - repair), options and device
+	ExtCheckCmdTmpl = "e2fsck -f %s %s %s"
+	// XFSCheckCmdTmpl xfs check cmd, add mode (-n - check only, empty - repair), options and device
+	XFSCheckCmdTmpl = "xfs_repair %s %s %s"
+)
+
+// mkfsOptions are mkfs flags which could be passed from StorageClass for each file system, each flag requires value,
+// flags which change target device or force overwrite of existing data aren't allowed
+var mkfsOptions = map[FileSystem]map[string]bool{
+	EXT3: {"-O": true, "-E": true, "-I": true, "-i": true, "-N": true, "-m": true, "-b": true, "-T": true},
+	EXT4: {"-O": true, "-E": true, "-I": true, "-i": true, "-N": true, "-m": true, "-b": true, "-T": true},
+	XFS:  {"-m": true, "-i": true, "-b": true, "-d": true, "-l": true, "-n": true, "-s": true},
+}
+
+// mkfsOptionValue is a format of mkfs option value, paths aren't allowed to not redirect mkfs to other device
+var mkfsOptionValue = regexp.MustCompile(`^[A-Za-z0-9_^][A-Za-z0-9_^=,.:+-]*$`)
+
+// ValidateMkFSOptions checks that mkfs options could be used for the file system, options are pairs of flag
+// and value separated by spaces, e.g. "-O ^has_journal -I 512" for ext4 or "-m reflink=1" for xfs
+// Returns error if options can't be used
+func ValidateMkFSOptions(fsType FileSystem, opts string) error {
+	allowed, ok := mkfsOptions[fsType]
+	if !ok {
+		return fmt.Errorf("mkfs options aren't supported for file system %s", fsType)
+	}
+	fields := strings.Fields(opts)
+	if len(fields)%2 != 0 {
+		return fmt.Errorf("mkfs options %q should be pairs of flag and value", opts)
+	}
+	for i := 0; i < len(fields); i += 2 {
+		if !allowed[fields[i]] {
+			return fmt.Errorf("mkfs option %s isn't supported for file system %s", fields[i], fsType)
+		}
+		if !mkfsOptionValue.MatchString(fields[i+1]) {
+			return fmt.Errorf("invalid value %s of mkfs option %s", fields[i+1], fields[i])
+		}
+	}
+	return nil
+}
+
+// FSCheckResult is a result of file system check
+type FSCheckResult string
+
+const (
+	// FSCheckClean means that file system has no errors, xfs_repair doesn't report whether errors were repaired,
+	// so repaired xfs file system is reported as clean
+	FSCheckClean FSCheckResult = "clean"
+	// FSCheckRepaired means that errors were found and repaired
+	FSCheckRepaired FSCheckResult = "repaired"
+	// FSCheckErrorsLeft means that errors were found and left uncorrected, e.g. file system was checked without repair
+	FSCheckErrorsLeft FSCheckResult = "errors-left"
 )
 
 // WrapFS is an interface that encapsulates operation with file systems
@@ -74,7 +133,7 @@ type WrapFS interface {
 	MkDir(src string) error
 	MkFile(src string) error
 	RmDir(src string) error
-	CreateFS(fsType FileSystem, device string) error
+	CreateFS(fsType FileSystem, device string, opts ...string) error
 	WipeFS(device string) error
 	GetFSType(device string) (FileSystem, error)
 	// Mount operations
@@ -82,17 +141,28 @@ type WrapFS interface {
 	FindMountPoint(target string) (string, error)
 	Mount(src, dst string, opts ...string) error
 	Unmount(src string) error
+	// CheckFS checks unmounted file system and repairs it if repair is true
+	CheckFS(fsType FileSystem, device string, repair bool, opts string) (FSCheckResult, error)
 }
 
 // WrapFSImpl is a WrapFS implementer
 type WrapFSImpl struct {
-	e       command.CmdExecutor
+	e command.CmdExecutor
+	// mounter performs mount and unmount in the mount namespace of the process,
+	// it is nil if they are delegated by executor to the privileged helper or to the host mount namespace
+	mounter mount.Interface
 	opMutex sync.Mutex
 }
 
 // NewFSImpl is a constructor for WrapFSImpl struct
+// Mount and unmount are performed with mount-utils if commands of the executor are run in the current process,
+// otherwise they are sent to the executor
 func NewFSImpl(e command.CmdExecutor) *WrapFSImpl {
-	return &WrapFSImpl{e: e}
+	h := &WrapFSImpl{e: e}
+	if _, local := e.(*command.Executor); local {
+		h.mounter = mount.New("")
+	}
+	return h
 }
 
 // GetFSSpace calls df command and return available space on the provided file system (src)
@@ -119,7 +189,7 @@ func (h *WrapFSImpl) GetFSSpace(src string) (int64, error) {
 			if strings.Contains(output[0], src) && len(output[0]) == 1 {
 				// Try to get size from string, e.g. "/dev       7982M"
 				sizeIdx := len(output) - 1
-				freeBytes, err := util.StrToBytes(output[sizeIdx])
+				freeBytes, err := bytesize.Parse(output[sizeIdx])
 				if err != nil {
 					return 0, err
 				}
@@ -139,7 +209,7 @@ func (h *WrapFSImpl) MkDir(src string) error {
 	if _, _, err := h.e.RunCmd(cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(MkDirCmdTmpl, "")))); err != nil {
-		return fmt.Errorf("failed to create dir %s: %v", src, err)
+		return fmt.Errorf("failed to create dir %s: %w", src, err)
 	}
 	return nil
 }
@@ -179,29 +249,33 @@ func (h *WrapFSImpl) RmDir(src string) error {
 	if _, _, err := h.e.RunCmd(cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(RmDirCmdTmpl, "")))); err != nil {
-		return fmt.Errorf("failed to delete path %s: %v", src, err)
+		return fmt.Errorf("failed to delete path %s: %w", src, err)
 	}
 	return nil
 }
 
 // CreateFS creates specified file system on the provided device using mkfs
-// Receives file system as a var of FileSystem type and path of the device as a string
+// Receives file system as a var of FileSystem type, path of the device as a string and additional mkfs options
+// which are appended after default ones and should be checked with ValidateMkFSOptions
 // Returns error if something went wrong
-func (h *WrapFSImpl) CreateFS(fsType FileSystem, device string) error {
+func (h *WrapFSImpl) CreateFS(fsType FileSystem, device string, opts ...string) error {
 	var cmd string
 	switch fsType {
-	case XFS:
+	case XFS, F2FS, BTRFS:
 		cmd = fmt.Sprintf(MkFSCmdTmpl, fsType, device)
 	case EXT3, EXT4:
 		cmd = fmt.Sprintf(MkFSCmdTmpl, fsType, device) + SpeedUpFsCreationOpts
 	default:
 		return fmt.Errorf("unsupported file system %v", fsType)
 	}
+	if len(opts) > 0 {
+		cmd += " " + strings.Join(opts, " ")
+	}
 
 	if _, _, err := h.e.RunCmd(cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(MkFSCmdTmpl, "", "")))); err != nil {
-		return fmt.Errorf("failed to create file system on %s: %v", device, err)
+		return fmt.Errorf("failed to create file system on %s: %w", device, err)
 	}
 	return nil
 }
@@ -215,7 +289,7 @@ func (h *WrapFSImpl) WipeFS(device string) error {
 	if _, _, err := h.e.RunCmd(cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(WipeFSCmdTmpl, "")))); err != nil {
-		return fmt.Errorf("failed to wipe file system on %s: %v", device, err)
+		return fmt.Errorf("failed to wipe file system on %s: %w", device, err)
 	}
 	return nil
 }
@@ -233,32 +307,25 @@ func (h *WrapFSImpl) GetFSType(device string) (FileSystem, error) {
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(GetFSTypeCmdTmpl, ""))))
 	if err != nil {
-		return "", fmt.Errorf("unable to retrieve FS type for device %s: %v", device, err)
+		return "", fmt.Errorf("unable to retrieve FS type for device %s: %w", device, err)
 	}
 
 	return FileSystem(strings.TrimSpace(stdout)), nil
 }
 
-// IsMounted checks if the path is presented in /proc/self/mountinfo
+// IsMounted checks if the path is a mount point in /proc/self/mountinfo
 // Receives path as a string
 // Returns bool that represents mount status or error if something went wrong
 func (h *WrapFSImpl) IsMounted(path string) (bool, error) {
 	h.opMutex.Lock()
 	defer h.opMutex.Unlock()
 
-	procMounts, err := util.ConsistentRead(MountInfoFile, 5, time.Millisecond)
-	if err != nil || len(procMounts) == 0 {
-		return false, fmt.Errorf("unable to check whether %s mounted or no, error: %v", path, err)
+	mountPoints, err := ReadMountPoints(MountInfoFile)
+	if err != nil || len(mountPoints) == 0 {
+		return false, fmt.Errorf("unable to check whether %s mounted or no, error: %w", path, err)
 	}
-
-	// parse /proc/mounts content and search path entry
-	for _, line := range strings.Split(string(procMounts), "\n") {
-		if strings.Contains(line, path) {
-			return true, nil
-		}
-	}
-
-	return false, nil
+	_, mounted := FindMount(mountPoints, path)
+	return mounted, nil
 }
 
 // FindMountPoint returns source of mount point for target
@@ -288,6 +355,16 @@ func (h *WrapFSImpl) FindMountPoint(target string) (string, error) {
 // Receives source path and destination dir and also opts parameters that are used for mount command for example --bind
 // Returns error if something went wrong
 func (h *WrapFSImpl) Mount(src, dir string, opts ...string) error {
+	if h.mounter != nil {
+		fsType, options, err := mountOptions(opts)
+		if err != nil {
+			return err
+		}
+		h.opMutex.Lock()
+		defer h.opMutex.Unlock()
+		return h.mounter.Mount(src, dir, fsType, options)
+	}
+
 	cmd := fmt.Sprintf(MountCmdTmpl, strings.Join(opts, " "), src, dir)
 	h.opMutex.Lock()
 	_, _, err := h.e.RunCmd(cmd,
@@ -302,6 +379,12 @@ func (h *WrapFSImpl) Mount(src, dir string, opts ...string) error {
 // Receives path where the device is mounted
 // Returns error if something went wrong
 func (h *WrapFSImpl) Unmount(path string) error {
+	if h.mounter != nil {
+		h.opMutex.Lock()
+		defer h.opMutex.Unlock()
+		return h.mounter.Unmount(path)
+	}
+
 	cmd := fmt.Sprintf(UnmountCmdTmpl, path)
 
 	h.opMutex.Lock()
@@ -312,3 +395,85 @@ func (h *WrapFSImpl) Unmount(path string) error {
 
 	return err
 }
+
+// mountOptions converts options of mount command (BindOption, "-t" and "-o" followed by value) to the file system type
+// and options of mount-utils
+// Returns file system type, options or error if some option isn't supported
+func mountOptions(opts []string) (string, []string, error) {
+	var (
+		fsType  string
+		options []string
+		fields  = strings.Fields(strings.Join(opts, " "))
+	)
+	for i := 0; i < len(fields); i++ {
+		switch opt := fields[i]; opt {
+		case BindOption:
+			options = append(options, "bind")
+		case "-t", "-o":
+			if i+1 == len(fields) {
+				return "", nil, fmt.Errorf("mount option %s requires value", opt)
+			}
+			i++
+			if opt == "-t" {
+				fsType = fields[i]
+			} else {
+				options = append(options, strings.Split(fields[i], ",")...)
+			}
+		default:
+			return "", nil, fmt.Errorf("mount option %s isn't supported", opt)
+		}
+	}
+	return fsType, options, nil
+}
+
+// CheckFS checks file system on the unmounted device with e2fsck or xfs_repair and repairs it if repair is true
+// Receives file system, path of the device, repair flag and additional options of the check util
+// Returns result of the check or error if check wasn't completed
+func (h *WrapFSImpl) CheckFS(fsType FileSystem, device string, repair bool, opts string) (FSCheckResult, error) {
+	var cmd string
+	switch fsType {
+	case EXT3, EXT4:
+		mode := "-n"
+		if repair {
+			mode = "-y"
+		}
+		cmd = fmt.Sprintf(ExtCheckCmdTmpl, mode, opts, device)
+	case XFS:
+		mode := "-n"
+		if repair {
+			mode = ""
+		}
+		cmd = fmt.Sprintf(XFSCheckCmdTmpl, mode, opts, device)
+	default:
+		return "", fmt.Errorf("unsupported file system %v", fsType)
+	}
+
+	_, stderr, err := h.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.Fields(cmd)[0]))
+	if err == nil {
+		return FSCheckClean, nil
+	}
+	code, ok := exitCode(err)
+	switch {
+	case !ok:
+	case fsType == XFS && code == 1 && !repair:
+		return FSCheckErrorsLeft, nil
+	case fsType != XFS && code&^3 == 0:
+		// e2fsck exit codes: 1 - errors corrected, 2 - errors corrected, system should be rebooted
+		return FSCheckRepaired, nil
+	case fsType != XFS && code == 4:
+		return FSCheckErrorsLeft, nil
+	}
+	return "", fmt.Errorf("failed to check file system on %s: %v, stderr: %s", device, err, stderr)
+}
+
+// exitCode returns exit code of the failed command, error could be returned by local or privileged helper executor,
+// in the last case only message of the error is available
+func exitCode(err error) (int, bool) {
+	var code int
+	if n, scanErr := fmt.Sscanf(err.Error(), "exit status %d", &code); scanErr != nil || n != 1 {
+		return 0, false
+	}
+	return code, true
+}
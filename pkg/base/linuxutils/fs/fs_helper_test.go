@@ -22,8 +22,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/mount-utils"
 
-	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/base/bytesize"
 	"github.com/dell/csi-baremetal/pkg/mocks"
 )
 
@@ -101,7 +102,7 @@ func TestGetFSSpace_Success(t *testing.T) {
 		Return(cmdResult, "", nil)
 	freeBytes, err := fh.GetFSSpace(path)
 	assert.Nil(t, err)
-	expectedRes, err := util.StrToBytes(sizeStr)
+	expectedRes, err := bytesize.Parse(sizeStr)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedRes, freeBytes)
 }
@@ -167,6 +168,29 @@ func TestCreateFS(t *testing.T) {
 	err = fh.CreateFS("anotherFS", device)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unsupported file system")
+
+	// options are appended after default ones
+	e.OnCommand(fmt.Sprintf(MkFSCmdTmpl, EXT4, device)+SpeedUpFsCreationOpts+" -O ^has_journal -I 512").
+		Return("", "", nil).Times(1)
+	err = fh.CreateFS(EXT4, device, "-O", "^has_journal", "-I", "512")
+	assert.Nil(t, err)
+}
+
+func TestValidateMkFSOptions(t *testing.T) {
+	assert.Nil(t, ValidateMkFSOptions(EXT4, "-O ^has_journal,metadata_csum -I 512 -E lazy_itable_init=0"))
+	assert.Nil(t, ValidateMkFSOptions(XFS, "-m reflink=1 -i size=512"))
+
+	for fsType, opts := range map[FileSystem]string{
+		EXT4:  "-O",                       // flag without value
+		XFS:   "-f -m reflink=1",          // force isn't allowed
+		EXT3:  "-E root_owner=0:0;reboot", // value with shell characters
+		BTRFS: "-O zoned",                 // options aren't supported for btrfs
+		F2FS:  "",                         // options aren't supported for f2fs
+	} {
+		assert.NotNil(t, ValidateMkFSOptions(fsType, opts), opts)
+	}
+	// device path isn't allowed in values
+	assert.NotNil(t, ValidateMkFSOptions(XFS, "-d name=/dev/sdb"))
 }
 
 func TestWipeFS(t *testing.T) {
@@ -248,3 +272,80 @@ func TestUnmount(t *testing.T) {
 	err = fh.Unmount(path)
 	assert.NotNil(t, err)
 }
+
+func TestMount_Mounter(t *testing.T) {
+	var (
+		mounter = &mount.FakeMounter{}
+		fh      = &WrapFSImpl{mounter: mounter}
+		src     = "/dev/sda1"
+		dst     = "/mnt/pod1"
+	)
+
+	assert.Nil(t, fh.Mount(src, dst, "-t xfs", DAXOption))
+	assert.Nil(t, fh.Mount(dst, "/mnt/pod2", BindOption))
+	assert.Nil(t, fh.Mount("", dst, "-o remount,ro"))
+	assert.Equal(t, []mount.MountPoint{
+		{Device: src, Path: dst, Type: "xfs", Opts: []string{"dax"}},
+		{Device: src, Path: "/mnt/pod2", Opts: []string{"bind"}},
+		{Path: dst, Opts: []string{"remount", "ro"}},
+	}, mounter.MountPoints)
+
+	// option isn't supported
+	assert.NotNil(t, fh.Mount(src, dst, "--rbind"))
+	// option without value
+	assert.NotNil(t, fh.Mount(src, dst, "-o"))
+
+	assert.Nil(t, fh.Unmount("/mnt/pod2"))
+	assert.Len(t, mounter.MountPoints, 2)
+}
+
+func TestCheckFS(t *testing.T) {
+	var (
+		e      = &mocks.GoMockExecutor{}
+		fh     = NewFSImpl(e)
+		device = "/dev/sda1"
+	)
+
+	// unsupported FS
+	_, err := fh.CheckFS("ntfs", device, false, "")
+	assert.NotNil(t, err)
+
+	// ext4 is clean
+	e.OnCommand(fmt.Sprintf(ExtCheckCmdTmpl, "-n", "", device)).Return("", "", nil).Times(1)
+	res, err := fh.CheckFS(EXT4, device, false, "")
+	assert.Nil(t, err)
+	assert.Equal(t, FSCheckClean, res)
+
+	// ext4 errors were found in check only mode
+	e.OnCommand(fmt.Sprintf(ExtCheckCmdTmpl, "-n", "", device)).
+		Return("", "", errors.New("exit status 4")).Times(1)
+	res, err = fh.CheckFS(EXT4, device, false, "")
+	assert.Nil(t, err)
+	assert.Equal(t, FSCheckErrorsLeft, res)
+
+	// ext4 errors were repaired, options are passed to e2fsck
+	e.OnCommand(fmt.Sprintf(ExtCheckCmdTmpl, "-y", "-D", device)).
+		Return("", "", errors.New("exit status 1")).Times(1)
+	res, err = fh.CheckFS(EXT4, device, true, "-D")
+	assert.Nil(t, err)
+	assert.Equal(t, FSCheckRepaired, res)
+
+	// e2fsck operational error
+	e.OnCommand(fmt.Sprintf(ExtCheckCmdTmpl, "-y", "", device)).
+		Return("", "device is mounted", errors.New("exit status 8")).Times(1)
+	_, err = fh.CheckFS(EXT4, device, true, "")
+	assert.NotNil(t, err)
+
+	// xfs errors were found in check only mode
+	e.OnCommand(fmt.Sprintf(XFSCheckCmdTmpl, "-n", "", device)).
+		Return("", "", errors.New("exit status 1")).Times(1)
+	res, err = fh.CheckFS(XFS, device, false, "")
+	assert.Nil(t, err)
+	assert.Equal(t, FSCheckErrorsLeft, res)
+
+	// xfs repair failed
+	e.OnCommand(fmt.Sprintf(XFSCheckCmdTmpl, "", "", device)).
+		Return("", "", errors.New("exit status 2")).Times(1)
+	_, err = fh.CheckFS(XFS, device, true, "")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// MountPropagation is a propagation type of the mount point, see mount_namespaces(7)
+type MountPropagation string
+
+const (
+	// PropagationShared means that mounts propagate to and from the host (Bidirectional in Kubernetes)
+	PropagationShared MountPropagation = "shared"
+	// PropagationSlave means that mounts propagate only from the host (HostToContainer in Kubernetes)
+	PropagationSlave MountPropagation = "slave"
+	// PropagationPrivate means that mounts don't propagate (None in Kubernetes)
+	PropagationPrivate MountPropagation = "private"
+
+	// sharedTag and masterTag are prefixes of the optional fields of mountinfo line
+	sharedTag = "shared:"
+	masterTag = "master:"
+	// deletedSuffix is added to the mount point if its directory was removed
+	deletedSuffix = "\\040(deleted)"
+)
+
+// MountPoint is a mount point of the process
+type MountPoint struct {
+	Path        string
+	Propagation MountPropagation
+}
+
+// propagation returns propagation type based on the optional fields of mountinfo line,
+// mount which is a peer and a slave at the same time propagates in both directions
+func propagation(optionalFields []string) MountPropagation {
+	result := PropagationPrivate
+	for _, field := range optionalFields {
+		switch {
+		case strings.HasPrefix(field, sharedTag):
+			return PropagationShared
+		case strings.HasPrefix(field, masterTag):
+			result = PropagationSlave
+		}
+	}
+	return result
+}
+
+// Satisfies checks whether mounts with propagation p are visible as required by propagation required,
+// shared mount satisfies any requirement, slave mount satisfies slave and private requirements
+func (p MountPropagation) Satisfies(required MountPropagation) bool {
+	switch required {
+	case PropagationShared:
+		return p == PropagationShared
+	case PropagationSlave:
+		return p == PropagationShared || p == PropagationSlave
+	}
+	return true
+}
+
+// FindMount returns the last (top) mount with provided target path, mount point of removed directory matches too
+// Returns found mount point and true or empty mount point and false if target isn't a mount point
+func FindMount(mountPoints []MountPoint, target string) (MountPoint, bool) {
+	target = filepath.Clean(target)
+	for i := len(mountPoints) - 1; i >= 0; i-- {
+		if mountPoints[i].Path == target || mountPoints[i].Path == target+deletedSuffix {
+			return mountPoints[i], true
+		}
+	}
+	return MountPoint{}, false
+}
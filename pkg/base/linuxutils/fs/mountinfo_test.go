@@ -0,0 +1,77 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMountInfo = `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+1232 1190 0:112 / /var/lib/kubelet/pods rw,relatime shared:5 master:1 - ext4 /dev/sda1 rw
+1233 1190 0:112 / /var/lib/kubelet/plugins/kubernetes.io/csi rw,relatime master:1 - ext4 /dev/sda1 rw
+1234 1190 0:5 / /dev rw,nosuid - devtmpfs udev rw
+1235 1232 8:17 / /var/lib/kubelet/pods/uid/volumes/kubernetes.io~csi/pvc-1/mount\040(deleted) rw - xfs /dev/sdb1 rw
+`
+
+func TestReadMountPoints(t *testing.T) {
+	f, err := ioutil.TempFile("", "mountinfo")
+	assert.Nil(t, err)
+	defer func() { _ = os.Remove(f.Name()) }()
+	_, err = f.WriteString(testMountInfo)
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+
+	mountPoints, err := ReadMountPoints(f.Name())
+	assert.Nil(t, err)
+	assert.Len(t, mountPoints, 5)
+
+	mp, ok := FindMount(mountPoints, "/var/lib/kubelet/pods/")
+	assert.True(t, ok)
+	assert.Equal(t, PropagationShared, mp.Propagation)
+
+	mp, ok = FindMount(mountPoints, "/var/lib/kubelet/plugins/kubernetes.io/csi")
+	assert.True(t, ok)
+	assert.Equal(t, PropagationSlave, mp.Propagation)
+
+	mp, ok = FindMount(mountPoints, "/dev")
+	assert.True(t, ok)
+	assert.Equal(t, PropagationPrivate, mp.Propagation)
+
+	// mount point of the removed directory
+	_, ok = FindMount(mountPoints, "/var/lib/kubelet/pods/uid/volumes/kubernetes.io~csi/pvc-1/mount")
+	assert.True(t, ok)
+
+	// path is a part of the mount point, but isn't mounted itself
+	_, ok = FindMount(mountPoints, "/var/lib/kubelet")
+	assert.False(t, ok)
+
+	_, err = ReadMountPoints("/not/exist")
+	assert.NotNil(t, err)
+}
+
+func TestMountPropagation_Satisfies(t *testing.T) {
+	assert.True(t, PropagationShared.Satisfies(PropagationShared))
+	assert.True(t, PropagationShared.Satisfies(PropagationSlave))
+	assert.False(t, PropagationSlave.Satisfies(PropagationShared))
+	assert.True(t, PropagationSlave.Satisfies(PropagationSlave))
+	assert.False(t, PropagationPrivate.Satisfies(PropagationSlave))
+	assert.True(t, PropagationPrivate.Satisfies(PropagationPrivate))
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"k8s.io/mount-utils"
+)
+
+// ReadMountPoints reads mount points from the mountinfo file, for current process it is MountInfoFile
+// Returns mount points in order of mounting or error if file couldn't be read or parsed
+func ReadMountPoints(mountInfoFile string) ([]MountPoint, error) {
+	infos, err := mount.ParseMountInfo(mountInfoFile)
+	if err != nil {
+		return nil, err
+	}
+	mountPoints := make([]MountPoint, 0, len(infos))
+	for _, info := range infos {
+		mountPoints = append(mountPoints, MountPoint{Path: info.MountPoint, Propagation: propagation(info.OptionalFields)})
+	}
+	return mountPoints, nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package integrity contains code for protecting volume data from silent corruption with dm-integrity (integritysetup)
+// or with ext4 metadata checksums and for reading results of integrity checks
+package integrity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+)
+
+const (
+	// devicePrefix is a prefix of device mapper name of the integrity device
+	devicePrefix = "csi-integrity-"
+	// mapperDir is a directory where device mapper devices are placed
+	mapperDir = "/dev/mapper/"
+	// FormatCmdTmpl initializes dm-integrity superblock on device, data isn't wiped, tags are recalculated on open
+	FormatCmdTmpl = "integritysetup format --batch-mode --no-wipe %s" // add device
+	// OpenCmdTmpl activates dm-integrity device with provided name on top of device
+	OpenCmdTmpl = "integritysetup open --integrity-recalculate %s %s" // add device and name
+	// CloseCmdTmpl deactivates dm-integrity device
+	CloseCmdTmpl = "integritysetup close %s" // add name
+	// StatusCmdTmpl prints status of device mapper device
+	StatusCmdTmpl = "dmsetup status %s" // add name
+	// ChecksumFSOpts options which enable checksums of ext4 metadata
+	ChecksumFSOpts = " -O metadata_csum"
+	// FSErrorsCmdTmpl prints superblock of ext4 file system
+	FSErrorsCmdTmpl = "dumpe2fs -h %s" // add device
+	// fsErrorCountField is a field of dumpe2fs output with number of errors detected in file system
+	fsErrorCountField = "FS Error count:"
+	// integrityTarget is a name of device mapper target in dmsetup status output
+	integrityTarget = "integrity"
+	// noSuchDevice is reported by dmsetup when device mapper device doesn't exist
+	noSuchDevice = "No such device"
+)
+
+// WrapIntegrity is an interface that encapsulates operations with integrity protection of the volumes
+type WrapIntegrity interface {
+	Format(device string) error
+	Open(device, name string) error
+	Close(name string) error
+	IsOpened(name string) (bool, error)
+	GetMismatches(name string) (int64, error)
+	CreateChecksumFS(device string, opts ...string) error
+	GetFSErrorCount(device string) (int64, error)
+}
+
+// Integrity is an implementation of WrapIntegrity interface based on integritysetup, dmsetup and e2fsprogs
+type Integrity struct {
+	e command.CmdExecutor
+}
+
+// NewIntegrity is a constructor for Integrity struct
+func NewIntegrity(e command.CmdExecutor) *Integrity {
+	return &Integrity{e: e}
+}
+
+// DeviceName returns device mapper name of the integrity device for volume with provided ID
+func DeviceName(volumeID string) string {
+	return devicePrefix + volumeID
+}
+
+// DevicePath returns path of the opened integrity device with provided device mapper name
+func DevicePath(name string) string {
+	return mapperDir + name
+}
+
+// Format initializes dm-integrity metadata on the device, all data on the device becomes inaccessible
+// Receives path of the device
+// Returns error if something went wrong
+func (i *Integrity) Format(device string) error {
+	cmd := fmt.Sprintf(FormatCmdTmpl, device)
+	if _, _, err := i.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(FormatCmdTmpl, "")))); err != nil {
+		return fmt.Errorf("failed to format integrity device on %s: %w", device, err)
+	}
+	return nil
+}
+
+// Open activates dm-integrity device with provided name, device is available by DevicePath(name) afterwards
+// Receives path of the formatted device and device mapper name
+// Returns error if something went wrong
+func (i *Integrity) Open(device, name string) error {
+	cmd := fmt.Sprintf(OpenCmdTmpl, device, name)
+	if _, _, err := i.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(OpenCmdTmpl, "", "")))); err != nil {
+		return fmt.Errorf("failed to open integrity device %s on %s: %w", name, device, err)
+	}
+	return nil
+}
+
+// Close deactivates dm-integrity device with provided name
+// Returns error if something went wrong
+func (i *Integrity) Close(name string) error {
+	cmd := fmt.Sprintf(CloseCmdTmpl, name)
+	if _, _, err := i.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(CloseCmdTmpl, "")))); err != nil {
+		return fmt.Errorf("failed to close integrity device %s: %w", name, err)
+	}
+	return nil
+}
+
+// IsOpened checks whether device mapper device with provided name is active
+// Returns true if device is active or error if its status can't be read
+func (i *Integrity) IsOpened(name string) (bool, error) {
+	cmd := fmt.Sprintf(StatusCmdTmpl, name)
+	_, stderr, err := i.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(StatusCmdTmpl, ""))))
+	if err == nil {
+		return true, nil
+	}
+	if strings.Contains(stderr, noSuchDevice) {
+		return false, nil
+	}
+	return false, fmt.Errorf("unable to read status of device %s: %w", name, err)
+}
+
+// GetMismatches returns number of data blocks which checksums didn't match since the device was opened,
+// each mismatch means that data on the underlying drive was silently corrupted
+// Receives device mapper name of the active integrity device
+// Returns number of mismatches or error if something went wrong
+func (i *Integrity) GetMismatches(name string) (int64, error) {
+	/*
+		Example of output:
+			~# dmsetup status csi-integrity-pvc-1
+			0 2031880 integrity 0 2031880 -
+		Where fourth field is a number of mismatches
+	*/
+	cmd := fmt.Sprintf(StatusCmdTmpl, name)
+	stdout, _, err := i.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(StatusCmdTmpl, ""))))
+	if err != nil {
+		return 0, fmt.Errorf("unable to read status of integrity device %s: %w", name, err)
+	}
+	fields := strings.Fields(stdout)
+	if len(fields) < 4 || fields[2] != integrityTarget {
+		return 0, fmt.Errorf("device %s isn't an integrity device, status: %s", name, stdout)
+	}
+	mismatches, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse number of mismatches of device %s: %w", name, err)
+	}
+	return mismatches, nil
+}
+
+// CreateChecksumFS creates ext4 file system with checksums of metadata on the provided device,
+// additional mkfs options are appended after default ones
+// Returns error if something went wrong
+func (i *Integrity) CreateChecksumFS(device string, opts ...string) error {
+	cmd := fmt.Sprintf(fs.MkFSCmdTmpl, fs.EXT4, device) + fs.SpeedUpFsCreationOpts + ChecksumFSOpts
+	if len(opts) > 0 {
+		cmd += " " + strings.Join(opts, " ")
+	}
+	if _, _, err := i.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(fs.MkFSCmdTmpl, "", "")))); err != nil {
+		return fmt.Errorf("failed to create file system with checksums on %s: %w", device, err)
+	}
+	return nil
+}
+
+// GetFSErrorCount returns number of errors which ext4 recorded in superblock, including checksum errors
+// found during normal operation of the mounted file system
+// Receives path of the device with ext4 file system
+// Returns number of errors or error if something went wrong
+func (i *Integrity) GetFSErrorCount(device string) (int64, error) {
+	/*
+		Example of output:
+			~# dumpe2fs -h /dev/sdb1
+			...
+			Checksum type:            crc32c
+			FS Error count:           2
+			...
+		Field is absent if file system has no errors
+	*/
+	cmd := fmt.Sprintf(FSErrorsCmdTmpl, device)
+	stdout, _, err := i.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(FSErrorsCmdTmpl, ""))))
+	if err != nil {
+		return 0, fmt.Errorf("unable to read superblock of %s: %w", device, err)
+	}
+	for _, line := range strings.Split(stdout, "\n") {
+		if !strings.HasPrefix(line, fsErrorCountField) {
+			continue
+		}
+		count, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, fsErrorCountField)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse error count of %s: %w", device, err)
+		}
+		return count, nil
+	}
+	return 0, nil
+}
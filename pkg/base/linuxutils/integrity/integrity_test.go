@@ -0,0 +1,112 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+const (
+	testDevice = "/dev/sda1"
+	testName   = "csi-integrity-pvc-1"
+)
+
+func TestIntegrity_FormatAndOpen(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	i := NewIntegrity(e)
+
+	e.On(mocks.RunCmd, fmt.Sprintf(FormatCmdTmpl, testDevice)).Return("", "", nil).Times(1)
+	e.On(mocks.RunCmd, fmt.Sprintf(OpenCmdTmpl, testDevice, testName)).Return("", "", nil).Times(1)
+	assert.Nil(t, i.Format(testDevice))
+	assert.Nil(t, i.Open(testDevice, testName))
+	assert.Equal(t, "/dev/mapper/"+testName, DevicePath(DeviceName("pvc-1")))
+
+	expectedErr := errors.New("integritysetup failed")
+	e.On(mocks.RunCmd, fmt.Sprintf(CloseCmdTmpl, testName)).Return("", "", expectedErr).Times(1)
+	assert.NotNil(t, i.Close(testName))
+}
+
+func TestIntegrity_IsOpened(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	i := NewIntegrity(e)
+	cmd := fmt.Sprintf(StatusCmdTmpl, testName)
+
+	e.On(mocks.RunCmd, cmd).Return("0 2031880 integrity 0 2031880 -", "", nil).Times(1)
+	opened, err := i.IsOpened(testName)
+	assert.Nil(t, err)
+	assert.True(t, opened)
+
+	e.On(mocks.RunCmd, cmd).Return("", "Device does not exist.\nCommand failed.\nNo such device or address",
+		errors.New("exit status 1")).Times(1)
+	opened, err = i.IsOpened(testName)
+	assert.Nil(t, err)
+	assert.False(t, opened)
+
+	e.On(mocks.RunCmd, cmd).Return("", "Permission denied", errors.New("exit status 1")).Times(1)
+	_, err = i.IsOpened(testName)
+	assert.NotNil(t, err)
+}
+
+func TestIntegrity_GetMismatches(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	i := NewIntegrity(e)
+	cmd := fmt.Sprintf(StatusCmdTmpl, testName)
+
+	e.On(mocks.RunCmd, cmd).Return("0 2031880 integrity 12 2031880 -\n", "", nil).Times(1)
+	mismatches, err := i.GetMismatches(testName)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(12), mismatches)
+
+	e.On(mocks.RunCmd, cmd).Return("0 2031880 linear", "", nil).Times(1)
+	_, err = i.GetMismatches(testName)
+	assert.NotNil(t, err)
+
+	e.On(mocks.RunCmd, cmd).Return("0 2031880 integrity x 2031880 -", "", nil).Times(1)
+	_, err = i.GetMismatches(testName)
+	assert.NotNil(t, err)
+}
+
+func TestIntegrity_GetFSErrorCount(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	i := NewIntegrity(e)
+	cmd := fmt.Sprintf(FSErrorsCmdTmpl, testDevice)
+
+	e.On(mocks.RunCmd, fmt.Sprintf("mkfs.ext4 %s", testDevice)+
+		" -E lazy_journal_init=1,lazy_itable_init=1,discard -O metadata_csum").Return("", "", nil).Times(1)
+	assert.Nil(t, i.CreateChecksumFS(testDevice))
+
+	e.On(mocks.RunCmd, cmd).Return("Checksum type:            crc32c\nFS Error count:           2\n",
+		"", nil).Times(1)
+	count, err := i.GetFSErrorCount(testDevice)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+
+	e.On(mocks.RunCmd, cmd).Return("Checksum type:            crc32c\n", "", nil).Times(1)
+	count, err = i.GetFSErrorCount(testDevice)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	e.On(mocks.RunCmd, cmd).Return("", "", errors.New("dumpe2fs failed")).Times(1)
+	_, err = i.GetFSErrorCount(testDevice)
+	assert.NotNil(t, err)
+}
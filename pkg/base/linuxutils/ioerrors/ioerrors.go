@@ -0,0 +1,154 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ioerrors contains code for counting of I/O errors of block devices detected by kernel,
+// counters of SCSI devices are read from sysfs, errors of other devices (e.g. NVMe) are counted in kernel log
+package ioerrors
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils"
+)
+
+const (
+	// KmsgPath is a device which gives records of kernel ring buffer
+	KmsgPath = "/dev/kmsg"
+
+	// ioErrCntAttr is an attribute of SCSI device with hex number of commands completed with error,
+	// /sys/block/<device>/device/ioerr_cnt
+	ioErrCntAttr = "ioerr_cnt"
+)
+
+// ErrNotSupported indicates that device doesn't have error counter and errors aren't counted in kernel log
+var ErrNotSupported = errors.New("I/O error counter isn't supported")
+
+// ioErrorRecord matches records which block layer writes for failed requests, e.g.
+// "blk_update_request: I/O error, dev sdb, sector 2048 op 0x0:(READ)" or "print_req_error: I/O error, dev nvme0n1"
+var ioErrorRecord = regexp.MustCompile(`\bI/O error, dev ([a-z0-9]+)`)
+
+// WrapIOErrors is an interface that encapsulates counting of I/O errors of block devices
+type WrapIOErrors interface {
+	GetErrorCount(devicePath string) (int64, error)
+	TailKernelLog(ctx context.Context) error
+}
+
+// IOErrors is an implementation of WrapIOErrors interface based on sysfs and kernel log
+type IOErrors struct {
+	sysfs string
+	kmsg  string
+	log   *logrus.Entry
+
+	mu sync.Mutex
+	// errors found in kernel log by device name, nil until kernel log is tailed
+	logged map[string]int64
+}
+
+// NewIOErrors is a constructor for IOErrors struct
+func NewIOErrors(logger *logrus.Logger) *IOErrors {
+	return &IOErrors{sysfs: linuxutils.SysfsPath, kmsg: KmsgPath, log: logger.WithField("component", "IOErrors")}
+}
+
+// GetErrorCount returns number of I/O errors of the whole block device with provided path, e.g. /dev/sda,
+// since boot of the node. Counter of SCSI device is read from sysfs, errors of other devices are counted in kernel log
+// Returns ErrNotSupported if device doesn't have counter and kernel log isn't tailed or other error if counter
+// can't be read
+func (e *IOErrors) GetErrorCount(devicePath string) (int64, error) {
+	name := filepath.Base(devicePath)
+	data, err := ioutil.ReadFile(filepath.Join(e.sysfs, "block", name, "device", ioErrCntAttr))
+	switch {
+	case err == nil:
+		count, err := strconv.ParseInt(strings.TrimSpace(string(data)), 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s of %s: %w", ioErrCntAttr, devicePath, err)
+		}
+		return count, nil
+	case !os.IsNotExist(err):
+		return 0, fmt.Errorf("unable to read %s of %s: %w", ioErrCntAttr, devicePath, err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.logged == nil {
+		return 0, fmt.Errorf("%w for %s", ErrNotSupported, devicePath)
+	}
+	return e.logged[name], nil
+}
+
+// TailKernelLog counts I/O errors in kernel ring buffer from the boot of the node and then in new records,
+// it blocks until context is done
+// Receives golang context
+// Returns error if kernel log can't be read
+func (e *IOErrors) TailKernelLog(ctx context.Context) error {
+	f, err := os.Open(e.kmsg)
+	if err != nil {
+		return fmt.Errorf("unable to open %s: %w", e.kmsg, err)
+	}
+	go func() {
+		<-ctx.Done()
+		_ = f.Close()
+	}()
+
+	err = e.tail(f)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return fmt.Errorf("unable to read %s: %w", e.kmsg, err)
+}
+
+// tail counts I/O errors in records of kernel log until reader fails
+func (e *IOErrors) tail(r io.Reader) error {
+	ll := e.log.WithField("method", "tail")
+
+	e.mu.Lock()
+	e.logged = make(map[string]int64)
+	e.mu.Unlock()
+
+	reader := bufio.NewReader(r)
+	for {
+		record, err := reader.ReadString('\n')
+		if err != nil {
+			// records were overwritten in ring buffer before they were read, next read returns the oldest one
+			if errors.Is(err, syscall.EPIPE) {
+				ll.Warn("Kernel log records were missed")
+				continue
+			}
+			return err
+		}
+		match := ioErrorRecord.FindStringSubmatch(record)
+		if match == nil {
+			continue
+		}
+		e.mu.Lock()
+		e.logged[match[1]]++
+		e.mu.Unlock()
+		ll.Debugf("I/O error of %s: %s", match[1], strings.TrimSpace(record))
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ioerrors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+const kernelLog = `6,1021,5140900,-;sd 0:0:1:0: [sdb] tag#0 FAILED Result: hostbyte=DID_OK driverbyte=DRIVER_SENSE
+3,1022,5140912,-;blk_update_request: I/O error, dev sdb, sector 2048 op 0x0:(READ) flags 0x0 phys_seg 1 prio class 0
+3,1023,5140930,-;Buffer I/O error on dev sdb1, logical block 0, async page read
+3,1024,6140912,-;print_req_error: I/O error, dev nvme0n1, sector 0
+3,1025,7140912,-;blk_update_request: I/O error, dev nvme0n1, sector 8 op 0x1:(WRITE) flags 0x0 phys_seg 1 prio class 0
+`
+
+func TestIOErrors_GetErrorCount(t *testing.T) {
+	root, err := ioutil.TempDir("", "sysfs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(root)
+
+	deviceDir := filepath.Join(root, "block", "sda", "device")
+	assert.Nil(t, os.MkdirAll(deviceDir, 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(deviceDir, ioErrCntAttr), []byte("0x1f\n"), 0644))
+
+	e := NewIOErrors(logrus.New())
+	e.sysfs = root
+
+	count, err := e.GetErrorCount("/dev/sda")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(31), count)
+
+	// NVMe device doesn't have counter, kernel log isn't tailed
+	_, err = e.GetErrorCount("/dev/nvme0n1")
+	assert.True(t, errors.Is(err, ErrNotSupported))
+
+	assert.Equal(t, io.EOF, e.tail(strings.NewReader(kernelLog)))
+	count, err = e.GetErrorCount("/dev/nvme0n1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+	// device without errors in kernel log
+	count, err = e.GetErrorCount("/dev/nvme1n1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(deviceDir, ioErrCntAttr), []byte("many"), 0644))
+	_, err = e.GetErrorCount("/dev/sda")
+	assert.NotNil(t, err)
+}
+
+func TestIOErrors_TailKernelLog(t *testing.T) {
+	e := NewIOErrors(logrus.New())
+	e.kmsg = "/not/existing/kmsg"
+	assert.NotNil(t, e.TailKernelLog(context.Background()))
+}
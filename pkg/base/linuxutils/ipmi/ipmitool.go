@@ -25,13 +25,39 @@ import (
 )
 
 const (
+	// IpmitoolCmd is a name of system ipmitool util
+	IpmitoolCmd = "ipmitool"
 	// LanPrintCmd print bmc ip cmd with ipmitool
 	LanPrintCmd = " ipmitool lan print"
+	// SELElistCmd prints System Event Log with resolved sensor names
+	SELElistCmd = IpmitoolCmd + " sel elist"
 )
 
+// SELRecord is a record of IPMI System Event Log
+type SELRecord struct {
+	ID   string
+	Date string
+	Time string
+	// Sensor contains sensor type and sensor name or number, e.g. "Drive Slot / Bay Drive 5"
+	Sensor string
+	Event  string
+	// Direction is Asserted or Deasserted, it could be empty for some events
+	Direction string
+}
+
+// String returns SEL record as it is printed by ipmitool without ID
+func (r SELRecord) String() string {
+	fields := []string{r.Date + " " + r.Time, r.Sensor, r.Event}
+	if r.Direction != "" {
+		fields = append(fields, r.Direction)
+	}
+	return strings.Join(fields, " | ")
+}
+
 // WrapIpmi is an interface that encapsulates operation with system ipmi util
 type WrapIpmi interface {
 	GetBmcIP() string
+	GetSELRecords() ([]SELRecord, error)
 }
 
 // IPMI is implementation for WrapImpi interface
@@ -39,7 +65,7 @@ type IPMI struct {
 	e command.CmdExecutor
 }
 
-// NewIPMI is a constructor for LSBLK struct
+// NewIPMI is a constructor for IPMI struct
 func NewIPMI(e command.CmdExecutor) *IPMI {
 	return &IPMI{e: e}
 }
@@ -76,3 +102,33 @@ func (i *IPMI) GetBmcIP() string {
 	}
 	return ip
 }
+
+// GetSELRecords returns records of System Event Log from the oldest to the newest
+func (i *IPMI) GetSELRecords() ([]SELRecord, error) {
+	/* Sample output
+	   1 | 10/14/2026 | 09:12:44 | Drive Slot / Bay Drive 5 | Drive Fault | Asserted
+	   2 | 10/14/2026 | 09:13:01 | Cable / Interconnect Backplane Cable | Config Error | Asserted
+	*/
+	strOut, _, err := i.e.RunCmd(SELElistCmd,
+		command.UseMetrics(true),
+		command.CmdName(SELElistCmd))
+	if err != nil {
+		return nil, err
+	}
+	records := make([]SELRecord, 0)
+	for _, line := range strings.Split(strOut, "\n") {
+		fields := strings.Split(line, "|")
+		if len(fields) < 5 {
+			continue
+		}
+		for j := range fields {
+			fields[j] = strings.TrimSpace(fields[j])
+		}
+		record := SELRecord{ID: fields[0], Date: fields[1], Time: fields[2], Sensor: fields[3], Event: fields[4]}
+		if len(fields) > 5 {
+			record.Direction = fields[5]
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
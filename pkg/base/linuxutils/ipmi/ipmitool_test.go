@@ -45,3 +45,27 @@ func TestIPMI_GetBmcIP(t *testing.T) {
 	ip = l.GetBmcIP()
 	assert.Equal(t, "", ip)
 }
+
+func TestIPMI_GetSELRecords(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	l := NewIPMI(e)
+
+	strOut := "   1 | 10/14/2026 | 09:12:44 | Drive Slot / Bay Drive 5 | Drive Fault | Asserted\n" +
+		"   2 | 10/14/2026 | 09:13:01 | Power Unit #0x01 | Redundancy Lost\n" +
+		"SEL has no entries\n"
+	e.On(mocks.RunCmd, SELElistCmd).Return(strOut, "", nil).Times(1)
+	records, err := l.GetSELRecords()
+	assert.Nil(t, err)
+	assert.Equal(t, []SELRecord{
+		{ID: "1", Date: "10/14/2026", Time: "09:12:44", Sensor: "Drive Slot / Bay Drive 5",
+			Event: "Drive Fault", Direction: "Asserted"},
+		{ID: "2", Date: "10/14/2026", Time: "09:13:01", Sensor: "Power Unit #0x01", Event: "Redundancy Lost"},
+	}, records)
+	assert.Equal(t, "10/14/2026 09:12:44 | Drive Slot / Bay Drive 5 | Drive Fault | Asserted", records[0].String())
+	assert.Equal(t, "10/14/2026 09:13:01 | Power Unit #0x01 | Redundancy Lost", records[1].String())
+
+	expectedError := errors.New("ipmitool failed")
+	e.On(mocks.RunCmd, SELElistCmd).Return("", "", expectedError).Times(1)
+	_, err = l.GetSELRecords()
+	assert.Equal(t, expectedError, err)
+}
@@ -32,7 +32,7 @@ import (
 const (
 	// CmdTmpl adds device name, if add empty string - command will print info about all devices
 	CmdTmpl = "lsblk %s --paths --json --bytes --fs " +
-		"--output NAME,TYPE,SIZE,ROTA,SERIAL,WWN,VENDOR,MODEL,REV,MOUNTPOINT,FSTYPE,PARTUUID"
+		"--output NAME,TYPE,SIZE,ROTA,SERIAL,WWN,VENDOR,MODEL,REV,MOUNTPOINT,FSTYPE,PARTUUID,PTTYPE"
 	// outputKey is the key to find block devices in lsblk json output
 	outputKey = "blockdevices"
 	// romDeviceType is the constant that represents rom devices to exclude them from lsblk output
@@ -122,6 +122,7 @@ type BlockDevice struct {
 	MountPoint string        `json:"mountpoint,omitempty"`
 	FSType     string        `json:"fstype,omitempty"`
 	PartUUID   string        `json:"partuuid,omitempty"`
+	PTType     string        `json:"pttype,omitempty"`
 	Children   []BlockDevice `json:"children,omitempty"`
 }
 
@@ -139,7 +140,7 @@ func (l *LSBLK) GetBlockDevices(device string) ([]BlockDevice, error) {
 	rawOut := make(map[string][]BlockDevice, 1)
 	err = json.Unmarshal([]byte(strOut), &rawOut)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal output to BlockDevice instance, error: %v", err)
+		return nil, fmt.Errorf("unable to unmarshal output to BlockDevice instance, error: %w", err)
 	}
 	res := make([]BlockDevice, 0)
 	var (
@@ -19,18 +19,23 @@ package lsscsi
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/dell/csi-baremetal/pkg/base/bytesize"
 	"github.com/dell/csi-baremetal/pkg/base/command"
-	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils"
 )
 
 const (
+	// LsscsiCmd is a name of system lsscsi util
+	LsscsiCmd = "lsscsi"
 	// LsscsiCmdImpl is a base CMD for lsscsi
-	LsscsiCmdImpl = "lsscsi --no-nvme"
+	LsscsiCmdImpl = LsscsiCmd + " --no-nvme"
 	// SCSIDeviceSizeCmdImpl is a CMD to get devices size by id
 	SCSIDeviceSizeCmdImpl = LsscsiCmdImpl + " --brief --size %s"
 	// SCSIDeviceCmdImpl is a CMD to get devices information about Vendor, Model and etc
@@ -46,8 +51,9 @@ type WrapLsscsi interface {
 
 // LSSCSI is a wrap for system lsscsi util
 type LSSCSI struct {
-	e   command.CmdExecutor
-	log *logrus.Entry
+	e     command.CmdExecutor
+	sysfs string
+	log   *logrus.Entry
 }
 
 // SCSIDevice represents devices in lsscsi output
@@ -62,7 +68,7 @@ type SCSIDevice struct {
 
 // NewLSSCSI is a constructor for LSSCSI
 func NewLSSCSI(e command.CmdExecutor, logger *logrus.Logger) *LSSCSI {
-	return &LSSCSI{e: e, log: logger.WithField("component", "LSSCSI")}
+	return &LSSCSI{e: e, sysfs: linuxutils.SysfsPath, log: logger.WithField("component", "LSSCSI")}
 }
 
 // GetSCSIDevices gets information about SCSIDevice using lsscsi util
@@ -115,8 +121,14 @@ func (la *LSSCSI) getSCSIDevicesBasicInfo() ([]*SCSIDevice, error) {
 }
 
 // fillDeviceSize fill information about device size
-// lsscsi --no-nvme --brief --size is easy to parse because size on the last position.
+// Size is read from /sys/block/<dev>/size in 512 bytes sectors, since lsscsi rounds it to 3 significant digits,
+// lsscsi --no-nvme --brief --size is used if sysfs isn't available, it is easy to parse because size on the last position.
 func (la *LSSCSI) fillDeviceSize(device *SCSIDevice) error {
+	sizeAttr := filepath.Join(la.sysfs, "block", filepath.Base(device.Path), "size")
+	if sectors, err := strconv.ParseInt(readAttr(sizeAttr), 10, 64); err == nil {
+		device.Size = sectors * sectorSize
+		return nil
+	}
 	/*
 	 [2:0:0:0]    /dev/sda   32.3GB
 	*/
@@ -129,9 +141,9 @@ func (la *LSSCSI) fillDeviceSize(device *SCSIDevice) error {
 	var re = regexp.MustCompile(`(\s+)`)
 	s := re.ReplaceAllString(strings.TrimSpace(strOut), " ")
 	output := strings.Split(s, " ")
-	bytes, err := util.StrToBytes(output[len(output)-1])
+	bytes, err := bytesize.Parse(output[len(output)-1])
 	if err != nil {
-		return fmt.Errorf("unable to parse from %s for device %v", output[2], device)
+		return fmt.Errorf("unable to parse size from %s for device %v: %v", output[len(output)-1], device, err)
 	}
 	device.Size = bytes
 	return nil
@@ -18,6 +18,9 @@ package lsscsi
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/dell/csi-baremetal/pkg/mocks"
@@ -69,7 +72,26 @@ func TestLSSCSI_getSCSIDeviceSize(t *testing.T) {
 
 	err := l.fillDeviceSize(devs)
 	assert.Nil(t, err)
-	assert.Equal(t, int64(34681860915), devs.Size)
+	assert.Equal(t, int64(32300000000), devs.Size)
+}
+
+func TestLSSCSI_getSCSIDeviceSizeSysfs(t *testing.T) {
+	root, err := ioutil.TempDir("", "sysfs")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(root) }()
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "block", "sda"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(root, "block", "sda", "size"), []byte("63082496\n"), 0644))
+
+	e := &mocks.GoMockExecutor{}
+	l := NewLSSCSI(e, testLogger)
+	l.sysfs = root
+	devs := &SCSIDevice{ID: "[2:0:0:0]", Path: "/dev/sda"}
+
+	// exact size is taken from sysfs, lsscsi isn't called
+	err = l.fillDeviceSize(devs)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(63082496*512), devs.Size)
+	e.AssertNotCalled(t, "RunCmd", fmt.Sprintf(SCSIDeviceSizeCmdImpl, devs.ID))
 }
 
 func TestLSSCSI_getSCSIDeviceSizeWrongSizeFormat(t *testing.T) {
@@ -0,0 +1,91 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsscsi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils"
+)
+
+const (
+	// sectorSize is a size of sector in which /sys/block/<dev>/size is reported
+	sectorSize = 512
+)
+
+// SysfsSCSI is an implementation of WrapLsscsi interface which reads SCSI disks from sysfs
+// It is used on systems where lsscsi is absent or too old to support --no-nvme option (e.g. some arm64 distributions)
+type SysfsSCSI struct {
+	sysfs string
+	log   *logrus.Entry
+}
+
+// NewSysfsSCSI is a constructor for SysfsSCSI
+func NewSysfsSCSI(logger *logrus.Logger) *SysfsSCSI {
+	return &SysfsSCSI{sysfs: linuxutils.SysfsPath, log: logger.WithField("component", "SysfsSCSI")}
+}
+
+// GetSCSIDevices gets information about SCSI disks from /sys/class/scsi_disk
+func (s *SysfsSCSI) GetSCSIDevices() ([]*SCSIDevice, error) {
+	ll := s.log.WithField("method", "GetSCSIDevices")
+	classDir := filepath.Join(s.sysfs, "class", "scsi_disk")
+	entries, err := ioutil.ReadDir(classDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", classDir, err)
+	}
+	devices := make([]*SCSIDevice, 0, len(entries))
+	for _, entry := range entries {
+		// entry name is H:C:T:L
+		deviceDir := filepath.Join(classDir, entry.Name(), "device")
+		blocks, err := ioutil.ReadDir(filepath.Join(deviceDir, "block"))
+		if err != nil || len(blocks) == 0 {
+			ll.Errorf("Unable to find block device for SCSI device %s: %v", entry.Name(), err)
+			continue
+		}
+		name := blocks[0].Name()
+		device := &SCSIDevice{
+			ID:       fmt.Sprintf("[%s]", entry.Name()),
+			Path:     filepath.Join("/dev", name),
+			Vendor:   readAttr(filepath.Join(deviceDir, "vendor")),
+			Model:    readAttr(filepath.Join(deviceDir, "model")),
+			Firmware: readAttr(filepath.Join(deviceDir, "rev")),
+		}
+		sectors, err := strconv.ParseInt(readAttr(filepath.Join(s.sysfs, "block", name, "size")), 10, 64)
+		if err != nil {
+			ll.Errorf("Unable to get size of device %s: %v", device.Path, err)
+		} else {
+			device.Size = sectors * sectorSize
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// readAttr returns trimmed content of sysfs attribute or empty string if it can't be read
+func readAttr(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
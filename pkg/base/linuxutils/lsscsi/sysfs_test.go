@@ -0,0 +1,61 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsscsi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSysfsSCSI_GetSCSIDevices(t *testing.T) {
+	root, err := ioutil.TempDir("", "sysfs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(root)
+
+	s := NewSysfsSCSI(logrus.New())
+	s.sysfs = root
+
+	// scsi_disk class is absent
+	_, err = s.GetSCSIDevices()
+	assert.NotNil(t, err)
+
+	deviceDir := filepath.Join(root, "class", "scsi_disk", "0:0:1:0", "device")
+	assert.Nil(t, os.MkdirAll(filepath.Join(deviceDir, "block", "sdb"), 0755))
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "block", "sdb"), 0755))
+	// SCSI device without block device is skipped
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "class", "scsi_disk", "0:0:2:0", "device"), 0755))
+	for attr, value := range map[string]string{"vendor": "ATA     \n", "model": "Virtual disk    \n", "rev": "2.0 \n"} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(deviceDir, attr), []byte(value), 0644))
+	}
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(root, "block", "sdb", "size"), []byte("2048\n"), 0644))
+
+	devices, err := s.GetSCSIDevices()
+	assert.Nil(t, err)
+	assert.Equal(t, []*SCSIDevice{{
+		ID:       "[0:0:1:0]",
+		Path:     "/dev/sdb",
+		Size:     2048 * 512,
+		Vendor:   "ATA",
+		Model:    "Virtual disk",
+		Firmware: "2.0",
+	}}, devices)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lvm
+
+import (
+	"strings"
+	"sync"
+
+	metricsC "github.com/dell/csi-baremetal/pkg/metrics/common"
+)
+
+// vgLocks serializes mutations of the same VG. Concurrent lvm commands which change metadata of the same VG
+// intermittently fail, so lock is shared by all LVM instances of the process rather than kept in LVM struct
+var vgLocks = newLockMap()
+
+// lockMap holds mutex per VG name, so mutations of different VGs never wait for each other
+// Mutex is removed from the map when it has no holders and waiters
+type lockMap struct {
+	sync.Mutex
+	locks map[string]*refMutex
+}
+
+// refMutex is a mutex with amount of its holders and waiters
+type refMutex struct {
+	sync.Mutex
+	refs int
+}
+
+// newLockMap is a constructor for lockMap
+func newLockMap() *lockMap {
+	return &lockMap{locks: make(map[string]*refMutex)}
+}
+
+// lock blocks until mutex of the name is acquired
+func (m *lockMap) lock(name string) {
+	m.Lock()
+	l, ok := m.locks[name]
+	if !ok {
+		l = &refMutex{}
+		m.locks[name] = l
+	}
+	l.refs++
+	m.Unlock()
+
+	l.Lock()
+}
+
+// unlock releases mutex of the name, it must be locked before
+func (m *lockMap) unlock(name string) {
+	m.Lock()
+	l := m.locks[name]
+	l.refs--
+	if l.refs == 0 {
+		delete(m.locks, name)
+	}
+	m.Unlock()
+
+	l.Unlock()
+}
+
+// lockVG blocks until the lock of VG vgName is acquired, callers are queued while VG is being mutated
+// Receives VG name and type of the mutation which is used as a metrics label
+// Returns function which releases the lock
+func lockVG(vgName, op string) func() {
+	metricsC.LVMLockQueue.Inc()
+	evaluate := metricsC.LVMLockWaitDuration.EvaluateDurationForType(op)
+	vgLocks.lock(vgName)
+	evaluate()
+	metricsC.LVMLockQueue.Dec()
+	return func() {
+		vgLocks.unlock(vgName)
+	}
+}
+
+// vgNameFromLVPath returns VG name from LV path like /dev/<vg>/<lv>
+// Returns false if path doesn't match this format, e.g. /dev/mapper/<vg>-<lv> or /dev/dm-1
+func vgNameFromLVPath(lvPath string) (string, bool) {
+	parts := strings.Split(strings.TrimPrefix(lvPath, "/dev/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[0] == "mapper" {
+		return "", false
+	}
+	return parts[0], true
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lvm
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockVG_Serializes(t *testing.T) {
+	var (
+		wg      sync.WaitGroup
+		running int32
+		maxRun  int32
+	)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer lockVG("test-vg", "lvcreate")()
+			cur := atomic.AddInt32(&running, 1)
+			for {
+				prev := atomic.LoadInt32(&maxRun)
+				if cur <= prev || atomic.CompareAndSwapInt32(&maxRun, prev, cur) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), maxRun)
+}
+
+func TestLockVG_DifferentVGs(t *testing.T) {
+	unlock := lockVG("vg-1", "lvcreate")
+	locked := make(chan struct{})
+	go func() {
+		defer lockVG("vg-2", "lvcreate")()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Error("lock of vg-2 waits for vg-1")
+	}
+	unlock()
+
+	// mutexes without holders aren't kept
+	vgLocks.Lock()
+	defer vgLocks.Unlock()
+	assert.Empty(t, vgLocks.locks)
+}
+
+func TestVGNameFromLVPath(t *testing.T) {
+	vg, ok := vgNameFromLVPath("/dev/vg-1/lv-1")
+	assert.True(t, ok)
+	assert.Equal(t, "vg-1", vg)
+	_, ok = vgNameFromLVPath("/dev/mapper/vg--1-lv--1")
+	assert.False(t, ok)
+	_, ok = vgNameFromLVPath("/dev/dm-1")
+	assert.False(t, ok)
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/dell/csi-baremetal/pkg/base/bytesize"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 )
@@ -46,6 +47,12 @@ const (
 	VGCreateCmdTmpl = lvmPath + "vgcreate --yes %s %s" // add VG name and PV names
 	// VGRemoveCmdTmpl remove VG cmd
 	VGRemoveCmdTmpl = lvmPath + "vgremove --yes %s" // add VG name
+	// VGExtendCmdTmpl adds PVs to the volume group
+	VGExtendCmdTmpl = lvmPath + "vgextend --yes %s %s" // add VG name and PV names
+	// VGReduceCmdTmpl removes PVs from the volume group
+	VGReduceCmdTmpl = lvmPath + "vgreduce --yes %s %s" // add VG name and PV names
+	// PVMoveCmdTmpl moves allocated extents from PV to other PVs of the volume group
+	PVMoveCmdTmpl = lvmPath + "pvmove --yes %s" // add PV name
 	// AllPVsCmd returns all physical volumes on the system
 	AllPVsCmd = lvmPath + "pvs --options pv_name --noheadings"
 	// VGFreeSpaceCmdTmpl check VG free space cmd
@@ -54,6 +61,8 @@ const (
 	LVCreateCmdTmpl = lvmPath + "lvcreate --yes --name %s --size %s %s" // add LV name, size and VG name
 	// LVRemoveCmdTmpl remove LV cmd
 	LVRemoveCmdTmpl = lvmPath + "lvremove --yes %s" // add full LV name
+	// VGOfLVCmdTmpl print VG name of LV cmd, LV could be passed by any path including /dev/mapper one
+	VGOfLVCmdTmpl = lvmPath + "lvs --options vg_name --noheadings %s" // add LV path
 	// LVsInVGCmdTmpl print LVs in VG cmd
 	LVsInVGCmdTmpl = lvmPath + "lvs --select vg_name=%s -o lv_name --noheadings" // add VG name
 	// PVInfoCmdTmpl returns colon (:) separated output, where pv name on first place and vg on second
@@ -70,6 +79,9 @@ type WrapLVM interface {
 	PVRemove(name string) error
 	VGCreate(name string, pvs ...string) error
 	VGRemove(name string) error
+	VGExtend(name string, pvs ...string) error
+	VGReduce(name string, pvs ...string) error
+	PVMove(name string) error
 	LVCreate(name, size, vgName string) error
 	LVRemove(fullLVName string) error
 	IsVGContainsLVs(vgName string) bool
@@ -77,11 +89,13 @@ type WrapLVM interface {
 	GetVgFreeSpace(vgName string) (int64, error)
 	GetAllPVs() ([]string, error)
 	GetLVsInVG(vgName string) ([]string, error)
+	GetPVsInVG(vgName string) ([]string, error)
 	GetVGNameByPVName(pvName string) (string, error)
 	ExpandLV(lvName string, requiredSize int64) error
 }
 
 // LVM is an implementation of WrapLVM interface and is a wrap for system /sbin/lvm util in
+// VG mutations (vgcreate/vgremove/vgextend/vgreduce, pvmove, lvcreate/lvremove/lvextend) are serialized per VG
 type LVM struct {
 	e   command.CmdExecutor
 	log *logrus.Entry
@@ -124,6 +138,7 @@ func (l *LVM) PVRemove(name string) error {
 // Receives full name of a logical volume and requiredSize to resize
 // Returns error if something went wrong
 func (l *LVM) ExpandLV(lvName string, requiredSize int64) error {
+	defer lockVG(l.vgOfLV(lvName), "lvextend")()
 	cmd := fmt.Sprintf(LVExpandCmdTmpl, strconv.FormatInt(requiredSize, 10), lvName)
 	_, _, err := l.e.RunCmd(cmd,
 		command.UseMetrics(true),
@@ -138,6 +153,7 @@ func (l *LVM) ExpandLV(lvName string, requiredSize int64) error {
 // Receives name of VG to create and names of physical volumes which VG should based on
 // Returns error if something went wrong
 func (l *LVM) VGCreate(name string, pvs ...string) error {
+	defer lockVG(name, "vgcreate")()
 	cmd := fmt.Sprintf(VGCreateCmdTmpl, name, strings.Join(pvs, " "))
 	_, stdErr, err := l.e.RunCmd(cmd,
 		command.UseMetrics(true),
@@ -152,6 +168,7 @@ func (l *LVM) VGCreate(name string, pvs ...string) error {
 // Receives name of VG to remove
 // Returns error if something went wrong
 func (l *LVM) VGRemove(name string) error {
+	defer lockVG(name, "vgremove")()
 	cmd := fmt.Sprintf(VGRemoveCmdTmpl, name)
 	_, stdErr, err := l.e.RunCmd(cmd,
 		command.UseMetrics(true),
@@ -162,10 +179,56 @@ func (l *LVM) VGRemove(name string) error {
 	return err
 }
 
+// VGExtend adds physical volumes (pvs) to the volume group
+// Receives name of VG to extend and names of physical volumes which are added
+// Returns error if something went wrong
+func (l *LVM) VGExtend(name string, pvs ...string) error {
+	defer lockVG(name, "vgextend")()
+	cmd := fmt.Sprintf(VGExtendCmdTmpl, name, strings.Join(pvs, " "))
+	_, _, err := l.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(VGExtendCmdTmpl, "", ""))))
+	return err
+}
+
+// VGReduce removes physical volumes (pvs) from the volume group, PVs mustn't have allocated extents
+// Receives name of VG to reduce and names of physical volumes which are removed
+// Returns error if something went wrong
+func (l *LVM) VGReduce(name string, pvs ...string) error {
+	defer lockVG(name, "vgreduce")()
+	cmd := fmt.Sprintf(VGReduceCmdTmpl, name, strings.Join(pvs, " "))
+	_, _, err := l.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(VGReduceCmdTmpl, "", ""))))
+	return err
+}
+
+// PVMove moves allocated extents of physical volume to free extents of other PVs in the same volume group
+// Receives name of PV to evacuate
+// Returns error if something went wrong
+func (l *LVM) PVMove(name string) error {
+	vgName, err := l.GetVGNameByPVName(name)
+	if err != nil {
+		// PV without VG has nothing to move, the lock is taken by PV name to report error of pvmove itself
+		vgName = name
+	}
+	defer lockVG(vgName, "pvmove")()
+	cmd := fmt.Sprintf(PVMoveCmdTmpl, name)
+	_, stdErr, err := l.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(PVMoveCmdTmpl, ""))))
+	// PV without allocated extents is already evacuated
+	if strings.Contains(stdErr, "No data to move") {
+		return nil
+	}
+	return err
+}
+
 // LVCreate created logical volume in volume group, ignore error if LV already exists
 // Receives name of created LV, size which is a string like 1.2G, 100M and name of VG which LV should be based on
 // Returns error if something went wrong
 func (l *LVM) LVCreate(name, size, vgName string) error {
+	defer lockVG(vgName, "lvcreate")()
 	cmd := fmt.Sprintf(LVCreateCmdTmpl, name, size, vgName)
 	_, stdErr, err := l.e.RunCmd(cmd,
 		command.UseMetrics(true),
@@ -180,6 +243,7 @@ func (l *LVM) LVCreate(name, size, vgName string) error {
 // Receives fullLVName that is a path to LV
 // Returns error if something went wrong
 func (l *LVM) LVRemove(fullLVName string) error {
+	defer lockVG(l.vgOfLV(fullLVName), "lvremove")()
 	cmd := fmt.Sprintf(LVRemoveCmdTmpl, fullLVName)
 	_, stdErr, err := l.e.RunCmdWithAttempts(cmd, 5, timeoutBetweenAttempts, command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(LVRemoveCmdTmpl, ""))))
@@ -221,6 +285,21 @@ func (l *LVM) GetLVsInVG(vgName string) ([]string, error) {
 	return util.SplitAndTrimSpace(stdout, "\n"), nil
 }
 
+// GetPVsInVG collects PVs of given volume group
+// Receives Volume Group name
+// Returns slice of found physical volumes
+func (l *LVM) GetPVsInVG(vgName string) ([]string, error) {
+	cmd := fmt.Sprintf(PVsInVGCmdTmpl, vgName)
+	stdout, _, err := l.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(PVsInVGCmdTmpl, ""))))
+	if err != nil {
+		return nil, err
+	}
+
+	return util.SplitAndTrimSpace(stdout, "\n"), nil
+}
+
 // RemoveOrphanPVs removes PVs that do not have VG
 // Returns error if something went wrong
 func (l *LVM) RemoveOrphanPVs() error {
@@ -269,7 +348,7 @@ func (l *LVM) GetVgFreeSpace(vgName string) (int64, error) {
 		return -1, err
 	}
 
-	bytes, err := util.StrToBytes(strings.TrimSpace(strOut))
+	bytes, err := bytesize.Parse(strings.TrimSpace(strOut))
 	if err != nil {
 		return -1, err
 	}
@@ -289,6 +368,24 @@ func (l *LVM) GetAllPVs() ([]string, error) {
 	return util.SplitAndTrimSpace(stdOut, "\n"), nil
 }
 
+// vgOfLV returns name of VG of the LV which is used as a key of VG lock
+// VG name is taken from /dev/<vg>/<lv> path, lvs is requested for other paths, e.g. /dev/mapper/<vg>-<lv>,
+// since dashes in VG and LV names are doubled there. LV path is returned if VG can't be found,
+// so operation is still serialized with operations on the same path
+func (l *LVM) vgOfLV(lvPath string) string {
+	if vgName, ok := vgNameFromLVPath(lvPath); ok {
+		return vgName
+	}
+	stdOut, _, err := l.e.RunCmd(fmt.Sprintf(VGOfLVCmdTmpl, lvPath),
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(VGOfLVCmdTmpl, ""))))
+	if vgName := strings.TrimSpace(stdOut); err == nil && vgName != "" {
+		return vgName
+	}
+	l.log.WithField("method", "vgOfLV").Warnf("Unable to find VG of LV %s, Error: %v", lvPath, err)
+	return lvPath
+}
+
 // GetVGNameByPVName finds out volume group name based on physical volume name
 func (l *LVM) GetVGNameByPVName(pvName string) (string, error) {
 	cmd := fmt.Sprintf(PVInfoCmdTmpl, pvName)
@@ -119,6 +119,78 @@ func TestLinuxUtils_VGRemove(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 }
 
+func TestLinuxUtils_VGExtend(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		vg          = "test-lvg"
+		dev         = "/dev/sdc"
+		cmd         = fmt.Sprintf(VGExtendCmdTmpl, vg, dev)
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	assert.Nil(t, l.VGExtend(vg, dev))
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	assert.Equal(t, expectedErr, l.VGExtend(vg, dev))
+}
+
+func TestLinuxUtils_VGReduce(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		vg          = "test-lvg"
+		dev         = "/dev/sdc"
+		cmd         = fmt.Sprintf(VGReduceCmdTmpl, vg, dev)
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	assert.Nil(t, l.VGReduce(vg, dev))
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	assert.Equal(t, expectedErr, l.VGReduce(vg, dev))
+}
+
+func TestLinuxUtils_PVMove(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		dev         = "/dev/sdc"
+		cmd         = fmt.Sprintf(PVMoveCmdTmpl, dev)
+		expectedErr = errors.New("error")
+	)
+	// VG of the PV is locked
+	e.OnCommand(fmt.Sprintf(PVInfoCmdTmpl, dev)).Return(dev+":test-lvg:936701952:-1:8:8:-1:4096:114343:77478:36865:id", "", nil)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	assert.Nil(t, l.PVMove(dev))
+
+	e.OnCommand(cmd).Return("", "  No data to move for test-lvg.", expectedErr).Times(1)
+	assert.Nil(t, l.PVMove(dev))
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	assert.Equal(t, expectedErr, l.PVMove(dev))
+}
+
+func TestLinuxUtils_vgOfLV(t *testing.T) {
+	var (
+		e      = &mocks.GoMockExecutor{}
+		l      = NewLVM(e, testLogger)
+		mapper = "/dev/mapper/test--lvg-test--lv"
+	)
+
+	assert.Equal(t, "test-lvg", l.vgOfLV("/dev/test-lvg/test-lv"))
+
+	e.OnCommand(fmt.Sprintf(VGOfLVCmdTmpl, mapper)).Return("  test-lvg\n", "", nil).Times(1)
+	assert.Equal(t, "test-lvg", l.vgOfLV(mapper))
+
+	// LV path is used as a lock key if VG isn't found
+	e.OnCommand(fmt.Sprintf(VGOfLVCmdTmpl, mapper)).Return("", "", errors.New("error")).Times(1)
+	assert.Equal(t, mapper, l.vgOfLV(mapper))
+}
+
 func TestLinuxUtils_LVCreate(t *testing.T) {
 	var (
 		e           = &mocks.GoMockExecutor{}
@@ -213,6 +285,26 @@ func TestLinuxUtils_GetLVsInVG(t *testing.T) {
 	assert.Empty(t, res)
 }
 
+func TestLinuxUtils_GetPVsInVG(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		vg          = "test-lvg"
+		cmd         = fmt.Sprintf(PVsInVGCmdTmpl, vg)
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("  /dev/sda\n  /dev/sdb\n", "", nil).Times(1)
+	res, err := l.GetPVsInVG(vg)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/dev/sda", "/dev/sdb"}, res)
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	res, err = l.GetPVsInVG(vg)
+	assert.NotNil(t, err)
+	assert.Empty(t, res)
+}
+
 func TestLinuxUtils_RemoveOrphanPVs(t *testing.T) {
 	var (
 		e           = &mocks.GoMockExecutor{}
@@ -273,8 +365,8 @@ func TestLinuxUtils_GetVgFreeSpace(t *testing.T) {
 	assert.Equal(t, int64(-1), currentSize)
 	assert.Equal(t, errors.New("VG name shouldn't be an empty string"), err)
 
-	// empty string, unable to convert to int
-	e.OnCommand(cmd).Return(fmt.Sprintf("\t\t %d \n", expectedSize), "", nil).Times(1)
+	// unknown unit, unable to convert to bytes
+	e.OnCommand(cmd).Return(fmt.Sprintf("\t\t %dX \n", expectedSize), "", nil).Times(1)
 	currentSize, err = l.GetVgFreeSpace(vgName)
 	assert.Equal(t, int64(-1), currentSize)
 	assert.Contains(t, err.Error(), "unknown size unit")
@@ -0,0 +1,103 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ndctl contains code for reading persistent memory (PMEM) namespaces with system ndctl util
+package ndctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dell/csi-baremetal/pkg/base/command"
+)
+
+const (
+	// NdctlCmdImpl is a base CMD for ndctl util
+	NdctlCmdImpl = "ndctl"
+	// NdctlListFSDAXCmdImpl is a CMD for listing PMEM namespaces in fsdax mode in JSON format
+	NdctlListFSDAXCmdImpl = NdctlCmdImpl + " list --namespaces --mode=" + ModeFSDAX
+	// ModeFSDAX is a mode of PMEM namespace which is exposed as /dev/pmem block device with DAX support
+	ModeFSDAX = "fsdax"
+	// DevicePathPrefix is a prefix of PMEM block device path
+	DevicePathPrefix = "/dev/"
+)
+
+// WrapNdctl is an interface that encapsulates operation with system ndctl util
+type WrapNdctl interface {
+	GetPMEMDevices() ([]PMEMDevice, error)
+}
+
+// PMEMDevice represents PMEM namespace from ndctl list output
+type PMEMDevice struct {
+	// name of the namespace, e.g. namespace0.0
+	Dev  string `json:"dev"`
+	Mode string `json:"mode"`
+	Size int64  `json:"size"`
+	UUID string `json:"uuid"`
+	// name of the block device, e.g. pmem0
+	BlockDev string `json:"blockdev"`
+}
+
+// Path returns path of PMEM block device
+func (d PMEMDevice) Path() string {
+	return DevicePathPrefix + d.BlockDev
+}
+
+// NDCTL is a wrap for system ndctl util
+type NDCTL struct {
+	e command.CmdExecutor
+}
+
+// NewNDCTL is a constructor for NDCTL
+func NewNDCTL(e command.CmdExecutor) *NDCTL {
+	return &NDCTL{e: e}
+}
+
+// GetPMEMDevices gets PMEM namespaces in fsdax mode using ndctl util
+// Namespaces without block device are skipped
+func (nd *NDCTL) GetPMEMDevices() ([]PMEMDevice, error) {
+	strOut, _, err := nd.e.RunCmd(NdctlListFSDAXCmdImpl,
+		command.UseMetrics(true),
+		command.CmdName(NdctlListFSDAXCmdImpl))
+	if err != nil {
+		return nil, err
+	}
+	strOut = strings.TrimSpace(strOut)
+	var rawDevs []PMEMDevice
+	switch {
+	case strOut == "":
+		// ndctl prints nothing if there are no namespaces
+	case strings.HasPrefix(strOut, "{"):
+		// ndctl prints single object instead of list if there is only one namespace
+		dev := PMEMDevice{}
+		if err = json.Unmarshal([]byte(strOut), &dev); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal output to PMEMDevice instance, error: %w", err)
+		}
+		rawDevs = append(rawDevs, dev)
+	default:
+		if err = json.Unmarshal([]byte(strOut), &rawDevs); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal output to []PMEMDevice instance, error: %w", err)
+		}
+	}
+	devs := make([]PMEMDevice, 0, len(rawDevs))
+	for _, d := range rawDevs {
+		if d.Mode == ModeFSDAX && d.BlockDev != "" {
+			devs = append(devs, d)
+		}
+	}
+	return devs, nil
+}
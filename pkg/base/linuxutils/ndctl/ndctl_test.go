@@ -0,0 +1,74 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ndctl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+func TestNDCTL_GetPMEMDevices(t *testing.T) {
+	single := `{
+		"dev":"namespace0.0",
+		"mode":"fsdax",
+		"map":"dev",
+		"size":266352984064,
+		"uuid":"e5f3a5b2-3c4d-4b8e-9a1f-0d2c3b4a5e6f",
+		"sector_size":512,
+		"align":2097152,
+		"blockdev":"pmem0"
+	}`
+	list := `[
+		{"dev":"namespace1.0","mode":"fsdax","size":266352984064,"uuid":"uuid-1","blockdev":"pmem1"},
+		{"dev":"namespace0.0","mode":"fsdax","size":266352984064,"uuid":"uuid-0","blockdev":"pmem0"},
+		{"dev":"namespace2.0","mode":"fsdax","size":0,"uuid":"uuid-2"}
+	]`
+
+	e := &mocks.GoMockExecutor{}
+	nd := NewNDCTL(e)
+
+	e.On("RunCmd", NdctlListFSDAXCmdImpl).Return(single, "", nil).Once()
+	devs, err := nd.GetPMEMDevices()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(devs))
+	assert.Equal(t, "/dev/pmem0", devs[0].Path())
+	assert.Equal(t, int64(266352984064), devs[0].Size)
+	assert.Equal(t, "e5f3a5b2-3c4d-4b8e-9a1f-0d2c3b4a5e6f", devs[0].UUID)
+
+	e.On("RunCmd", NdctlListFSDAXCmdImpl).Return(list, "", nil).Once()
+	devs, err = nd.GetPMEMDevices()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(devs))
+	assert.Equal(t, "uuid-1", devs[0].UUID)
+
+	e.On("RunCmd", NdctlListFSDAXCmdImpl).Return("", "", nil).Once()
+	devs, err = nd.GetPMEMDevices()
+	assert.Nil(t, err)
+	assert.Empty(t, devs)
+
+	e.On("RunCmd", NdctlListFSDAXCmdImpl).Return("[{", "", nil).Once()
+	_, err = nd.GetPMEMDevices()
+	assert.NotNil(t, err)
+
+	e.On("RunCmd", NdctlListFSDAXCmdImpl).Return("", "", fmt.Errorf("error")).Once()
+	_, err = nd.GetPMEMDevices()
+	assert.NotNil(t, err)
+}
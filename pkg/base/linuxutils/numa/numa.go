@@ -0,0 +1,80 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package numa contains code for discovering NUMA locality of block devices through sysfs
+package numa
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils"
+)
+
+const (
+	// numaNodeAttr is an attribute of PCI device (HBA, NVMe controller) which contains NUMA node
+	numaNodeAttr = "numa_node"
+	// noNUMANode is reported by kernel when platform doesn't provide NUMA affinity for device
+	noNUMANode = "-1"
+)
+
+// WrapNUMA is an interface that encapsulates discovering of device's NUMA node
+type WrapNUMA interface {
+	GetDeviceNUMANode(devicePath string) (string, error)
+}
+
+// NUMA is an implementation of WrapNUMA interface based on sysfs
+type NUMA struct {
+	sysfs string
+	log   *logrus.Entry
+}
+
+// NewNUMA is a constructor for NUMA struct
+func NewNUMA(logger *logrus.Logger) *NUMA {
+	return &NUMA{sysfs: linuxutils.SysfsPath, log: logger.WithField("component", "NUMA")}
+}
+
+// GetDeviceNUMANode returns NUMA node of the PCIe path of block device with provided path, e.g. /dev/sda
+// Walks from the block device up to the root of sysfs device tree and reads numa_node of the closest parent
+// Returns empty string if NUMA node isn't reported by platform or error
+func (n *NUMA) GetDeviceNUMANode(devicePath string) (string, error) {
+	ll := n.log.WithField("method", "GetDeviceNUMANode")
+
+	blockLink := filepath.Join(n.sysfs, "block", filepath.Base(devicePath))
+	// /sys/devices/pci0000:00/0000:00:1f.2/.../block/sda
+	deviceDir, err := filepath.EvalSymlinks(blockLink)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve %s: %w", blockLink, err)
+	}
+	root := filepath.Clean(n.sysfs)
+	for dir := deviceDir; dir != root && dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		data, err := ioutil.ReadFile(filepath.Join(dir, numaNodeAttr))
+		if err != nil {
+			continue
+		}
+		node := strings.TrimSpace(string(data))
+		if node == noNUMANode {
+			return "", nil
+		}
+		return node, nil
+	}
+	ll.Debugf("NUMA node isn't found for device %s", devicePath)
+	return "", nil
+}
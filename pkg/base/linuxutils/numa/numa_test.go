@@ -0,0 +1,76 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numa
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// prepareSysfs creates minimal sysfs tree with sda behind HBA on NUMA node 1,
+// nvme0n1 on controller without NUMA affinity and sdb without PCI parent
+func prepareSysfs(t *testing.T) string {
+	root, err := ioutil.TempDir("", "sysfs")
+	assert.Nil(t, err)
+
+	hbaDir := filepath.Join(root, "devices", "pci0000:80", "0000:80:01.0")
+	sdaDir := filepath.Join(hbaDir, "host0", "target0:0:0", "0:0:0:0", "block", "sda")
+	nvmeDir := filepath.Join(root, "devices", "pci0000:00", "0000:00:02.0", "nvme", "nvme0", "nvme0n1")
+	sdbDir := filepath.Join(root, "devices", "virtual", "block", "sdb")
+	for _, dir := range []string{sdaDir, nvmeDir, sdbDir, filepath.Join(root, "block")} {
+		assert.Nil(t, os.MkdirAll(dir, 0755))
+	}
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(hbaDir, numaNodeAttr), []byte("1\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(root, "devices", "pci0000:00", "0000:00:02.0", numaNodeAttr),
+		[]byte("-1\n"), 0644))
+
+	assert.Nil(t, os.Symlink(sdaDir, filepath.Join(root, "block", "sda")))
+	assert.Nil(t, os.Symlink(nvmeDir, filepath.Join(root, "block", "nvme0n1")))
+	assert.Nil(t, os.Symlink(sdbDir, filepath.Join(root, "block", "sdb")))
+	return root
+}
+
+func TestNUMA_GetDeviceNUMANode(t *testing.T) {
+	root := prepareSysfs(t)
+	defer os.RemoveAll(root)
+
+	n := NewNUMA(logrus.New())
+	n.sysfs = root
+
+	node, err := n.GetDeviceNUMANode("/dev/sda")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", node)
+
+	// platform doesn't provide NUMA affinity
+	node, err = n.GetDeviceNUMANode("/dev/nvme0n1")
+	assert.Nil(t, err)
+	assert.Equal(t, "", node)
+
+	// device without PCI parent
+	node, err = n.GetDeviceNUMANode("/dev/sdb")
+	assert.Nil(t, err)
+	assert.Equal(t, "", node)
+
+	// device doesn't exist
+	_, err = n.GetDeviceNUMANode("/dev/sdc")
+	assert.NotNil(t, err)
+}
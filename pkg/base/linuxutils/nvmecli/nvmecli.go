@@ -19,6 +19,8 @@ package nvmecli
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -36,6 +38,21 @@ const (
 	NVMeHealthCmdImpl = NVMCliCmdImpl + " smart-log %s --output-format=json"
 	// NVMeVendorCmdImpl is a CMD to get SMART information about NVMe device in JSON format
 	NVMeVendorCmdImpl = NVMCliCmdImpl + " id-ctrl %s --output-format=json"
+	// NVMeIDNamespaceCmdImpl is a CMD to get information about NVMe namespace in JSON format
+	NVMeIDNamespaceCmdImpl = NVMCliCmdImpl + " id-ns %s --output-format=json"
+	// NVMeCreateNamespaceCmdImpl is a CMD to create NVMe namespace, receives controller path, size and capacity
+	// in blocks and LBA format
+	NVMeCreateNamespaceCmdImpl = NVMCliCmdImpl + " create-ns %s --nsze=%d --ncap=%d --flbas=%d"
+	// NVMeDeleteNamespaceCmdImpl is a CMD to delete NVMe namespace, receives controller path and namespace id
+	NVMeDeleteNamespaceCmdImpl = NVMCliCmdImpl + " delete-ns %s --namespace-id=%d"
+	// NVMeAttachNamespaceCmdImpl is a CMD to attach NVMe namespace to controller,
+	// receives controller path, namespace id and controller id
+	NVMeAttachNamespaceCmdImpl = NVMCliCmdImpl + " attach-ns %s --namespace-id=%d --controllers=%d"
+	// NVMeDetachNamespaceCmdImpl is a CMD to detach NVMe namespace from controller,
+	// receives controller path, namespace id and controller id
+	NVMeDetachNamespaceCmdImpl = NVMCliCmdImpl + " detach-ns %s --namespace-id=%d --controllers=%d"
+	// NVMeRescanNamespacesCmdImpl is a CMD to rescan namespaces of NVMe controller
+	NVMeRescanNamespacesCmdImpl = NVMCliCmdImpl + " ns-rescan %s"
 	// DevicesKey is the key to find NVMe devices in nvme json output
 	DevicesKey = "Devices"
 )
@@ -43,8 +60,18 @@ const (
 // WrapNvmecli is an interface that encapsulates operation with system nvme util
 type WrapNvmecli interface {
 	GetNVMDevices() ([]NVMDevice, error)
+	CreateNamespaces(devices []NVMDevice, count int) ([]int, error)
 }
 
+var (
+	// namespaceSuffix is a suffix of serial number of drive which corresponds to NVMe namespace
+	namespaceSuffix = regexp.MustCompile(`-ns\d+$`)
+	// namespacePathSuffix is a suffix of NVMe namespace block device path, e.g. n1 in /dev/nvme0n1
+	namespacePathSuffix = regexp.MustCompile(`n\d+$`)
+	// createdNamespace matches id of created namespace in create-ns output, e.g. "create-ns: Success, created nsid:3"
+	createdNamespace = regexp.MustCompile(`nsid:\s*(\d+)`)
+)
+
 // NVMDevice represents devices from nvme list output
 type NVMDevice struct {
 	DevicePath   string `json:"DevicePath,omitempty"`
@@ -52,14 +79,55 @@ type NVMDevice struct {
 	ModelNumber  string `json:"ModelNumber,omitempty"`
 	SerialNumber string `json:"SerialNumber,omitempty"`
 	PhysicalSize int64  `json:"PhysicalSize,omitempty"`
+	NameSpace    int    `json:"NameSpace,omitempty"`
 	// Can VID be string for nvme?
 	Vendor int `json:"vid,omitempty"`
 	Health string
+	// temperature in Celsius, 0 if device doesn't report it
+	Temperature int32
+	// percentage of device life used, nil if device doesn't report it
+	PercentageUsed *int64
 }
 
 // SMARTLog represents SMART information for NVMe devices
 type SMARTLog struct {
 	CriticalWarning int `json:"critical_warning,omitempty"`
+	// composite temperature in Kelvin
+	Temperature int32 `json:"temperature,omitempty"`
+	// percentage of device life used, could exceed 100
+	PercentUsed *int64 `json:"percent_used,omitempty"`
+}
+
+// kelvinOffset is used to convert temperature reported by smart-log into Celsius
+const kelvinOffset = 273
+
+// controllerInfo represents namespace management fields of nvme id-ctrl output
+type controllerInfo struct {
+	ControllerID int `json:"cntlid"`
+	// total NVM capacity in bytes, nvme-cli could print it in floating point format
+	TotalCapacity float64 `json:"tnvmcap"`
+	// maximum amount of namespaces supported by controller
+	NamespaceCount int `json:"nn"`
+}
+
+// namespaceInfo represents LBA format fields of nvme id-ns output
+type namespaceInfo struct {
+	FormattedLBASize int `json:"flbas"`
+	LBAFormats       []struct {
+		// LBA data size as a power of two
+		DataSize int `json:"ds"`
+	} `json:"lbafs"`
+}
+
+// NamespaceSerialNumber returns serial number of drive which corresponds to NVMe namespace
+func NamespaceSerialNumber(serialNumber string, namespace int) string {
+	return fmt.Sprintf("%s-ns%d", serialNumber, namespace)
+}
+
+// ControllerSerialNumber returns serial number of NVMe controller for drive serial number
+// which could correspond to NVMe namespace
+func ControllerSerialNumber(serialNumber string) string {
+	return namespaceSuffix.ReplaceAllString(serialNumber, "")
 }
 
 // NVMECLI is a wrap for system nvem_cli util
@@ -85,7 +153,7 @@ func (na *NVMECLI) GetNVMDevices() ([]NVMDevice, error) {
 	rawOut := make(map[string][]NVMDevice)
 	err = json.Unmarshal([]byte(strOut), &rawOut)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal output to NVMDevice instance, error: %v", err)
+		return nil, fmt.Errorf("unable to unmarshal output to NVMDevice instance, error: %w", err)
 	}
 	var (
 		devs []NVMDevice
@@ -96,37 +164,42 @@ func (na *NVMECLI) GetNVMDevices() ([]NVMDevice, error) {
 		return nil, fmt.Errorf("unexpected nvme list output format")
 	}
 	for i, d := range devs {
-		devs[i].Health = na.getNVMDeviceHealth(d.DevicePath)
+		devs[i].Health, devs[i].Temperature, devs[i].PercentageUsed = na.getNVMDeviceSMART(d.DevicePath)
 		na.fillNVMDeviceVendor(&devs[i])
 	}
 	return devs, nil
 }
 
-// getNVMDeviceHealth gets information about device health based on critical_warning SMART attribute using nvme_cli smart-log util
-func (na *NVMECLI) getNVMDeviceHealth(path string) string {
-	ll := na.log.WithField("method", "getNVMDeviceHealth")
+// getNVMDeviceSMART gets information about device health based on critical_warning SMART attribute
+// device temperature in Celsius and percentage of device life used using nvme_cli smart-log util
+func (na *NVMECLI) getNVMDeviceSMART(path string) (string, int32, *int64) {
+	ll := na.log.WithField("method", "getNVMDeviceSMART")
 	cmd := fmt.Sprintf(NVMeHealthCmdImpl, path)
 	strOut, _, err := na.e.RunCmd(cmd,
 		command.UseMetrics(true),
 		command.CmdName(strings.TrimSpace(fmt.Sprintf(NVMeHealthCmdImpl, ""))))
 	if err != nil {
 		ll.Errorf("%s failed, set health as %s", cmd, apiV1.HealthUnknown)
-		return apiV1.HealthUnknown
+		return apiV1.HealthUnknown, 0, nil
 	}
 	smartLog := &SMARTLog{}
 	err = json.Unmarshal([]byte(strOut), &smartLog)
 	if err != nil {
 		ll.Errorf("unable to unmarshal output to SMARTLog, set health as %s", apiV1.HealthUnknown)
-		return apiV1.HealthUnknown
+		return apiV1.HealthUnknown, 0, nil
+	}
+	var temperature int32
+	if smartLog.Temperature > kelvinOffset {
+		temperature = smartLog.Temperature - kelvinOffset
 	}
 	health := smartLog.CriticalWarning
 	if na.isOneOfBitsSet(uint64(health), 0, 3) {
-		return apiV1.HealthSuspect
+		return apiV1.HealthSuspect, temperature, smartLog.PercentUsed
 	}
 	if na.isOneOfBitsSet(uint64(health), 2, 4, 5) {
-		return apiV1.HealthBad
+		return apiV1.HealthBad, temperature, smartLog.PercentUsed
 	}
-	return apiV1.HealthGood
+	return apiV1.HealthGood, temperature, smartLog.PercentUsed
 }
 
 // fillNVMDeviceVendor gets information about device vendor id
@@ -145,6 +218,86 @@ func (na *NVMECLI) fillNVMDeviceVendor(device *NVMDevice) {
 	}
 }
 
+// CreateNamespaces replaces namespaces of NVMe controller with provided amount of namespaces of equal size
+// Receives all namespaces of the controller which are listed by GetNVMDevices and amount of new namespaces
+// Returns ids of created namespaces
+func (na *NVMECLI) CreateNamespaces(devices []NVMDevice, count int) ([]int, error) {
+	ll := na.log.WithField("method", "CreateNamespaces")
+	if len(devices) == 0 {
+		return nil, fmt.Errorf("NVMe controller has no namespaces")
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("amount of namespaces should be positive, got %d", count)
+	}
+	ctrlPath := namespacePathSuffix.ReplaceAllString(devices[0].DevicePath, "")
+
+	ctrl := &controllerInfo{}
+	if err := na.runJSONCmd(fmt.Sprintf(NVMeVendorCmdImpl, ctrlPath), ctrl); err != nil {
+		return nil, err
+	}
+	if count > ctrl.NamespaceCount {
+		return nil, fmt.Errorf("controller %s supports up to %d namespaces, requested %d",
+			ctrlPath, ctrl.NamespaceCount, count)
+	}
+	ns := &namespaceInfo{}
+	if err := na.runJSONCmd(fmt.Sprintf(NVMeIDNamespaceCmdImpl, devices[0].DevicePath), ns); err != nil {
+		return nil, err
+	}
+	lbaFormat := ns.FormattedLBASize & 0xf
+	if lbaFormat >= len(ns.LBAFormats) {
+		return nil, fmt.Errorf("LBA format %d isn't reported for %s", lbaFormat, devices[0].DevicePath)
+	}
+	blockSize := int64(1) << uint(ns.LBAFormats[lbaFormat].DataSize)
+	blocks := int64(ctrl.TotalCapacity) / blockSize / int64(count)
+	if blocks == 0 {
+		return nil, fmt.Errorf("capacity of controller %s is too small for %d namespaces", ctrlPath, count)
+	}
+
+	for _, d := range devices {
+		ll.Infof("Deleting namespace %d of %s", d.NameSpace, ctrlPath)
+		if _, _, err := na.e.RunCmd(fmt.Sprintf(NVMeDetachNamespaceCmdImpl, ctrlPath, d.NameSpace, ctrl.ControllerID)); err != nil {
+			return nil, err
+		}
+		if _, _, err := na.e.RunCmd(fmt.Sprintf(NVMeDeleteNamespaceCmdImpl, ctrlPath, d.NameSpace)); err != nil {
+			return nil, err
+		}
+	}
+
+	namespaces := make([]int, 0, count)
+	for i := 0; i < count; i++ {
+		strOut, _, err := na.e.RunCmd(fmt.Sprintf(NVMeCreateNamespaceCmdImpl, ctrlPath, blocks, blocks, lbaFormat))
+		if err != nil {
+			return nil, err
+		}
+		match := createdNamespace.FindStringSubmatch(strOut)
+		if match == nil {
+			return nil, fmt.Errorf("unable to find id of created namespace in output: %s", strOut)
+		}
+		nsid, _ := strconv.Atoi(match[1])
+		ll.Infof("Namespace %d of %d blocks is created on %s", nsid, blocks, ctrlPath)
+		if _, _, err = na.e.RunCmd(fmt.Sprintf(NVMeAttachNamespaceCmdImpl, ctrlPath, nsid, ctrl.ControllerID)); err != nil {
+			return nil, err
+		}
+		namespaces = append(namespaces, nsid)
+	}
+	if _, _, err := na.e.RunCmd(fmt.Sprintf(NVMeRescanNamespacesCmdImpl, ctrlPath)); err != nil {
+		return nil, err
+	}
+	return namespaces, nil
+}
+
+// runJSONCmd runs nvme_cli command and unmarshals its JSON output to provided value
+func (na *NVMECLI) runJSONCmd(cmd string, v interface{}) error {
+	strOut, _, err := na.e.RunCmd(cmd)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal([]byte(strOut), v); err != nil {
+		return fmt.Errorf("unable to unmarshal output of %s, error: %w", cmd, err)
+	}
+	return nil
+}
+
 // isOneOfBitsSet returns true then one of bits in slice is set in value
 func (na *NVMECLI) isOneOfBitsSet(value uint64, bits ...int) bool {
 	ll := na.log.WithField("method", "isOneOfBitsSet")
@@ -55,7 +55,7 @@ func TestNVMECLI_GetNVMDevicesSuccess(t *testing.T) {
  		"temperature" : 302,
   		"avail_spare" : 100,
   		"spare_thresh" : 10,
-  		"percent_used" : 0,
+  		"percent_used" : 3,
   		"data_units_read" : 97704077
 	}
 `
@@ -88,6 +88,8 @@ func TestNVMECLI_GetNVMDevicesSuccess(t *testing.T) {
 	assert.Equal(t, "Dell Express Flash NVMe P4510 4TB SFF", devices[0].ModelNumber)
 	assert.Equal(t, apiV1.HealthGood, devices[0].Health)
 	assert.Equal(t, 32902, devices[0].Vendor)
+	assert.Equal(t, int32(29), devices[0].Temperature)
+	assert.Equal(t, int64(3), *devices[0].PercentageUsed)
 }
 
 func TestNVMECLI_GetNVMDevicesFails(t *testing.T) {
@@ -152,7 +154,7 @@ func TestNVMECLI_GetNVMDevicesWrongKey(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestNVMECLI_getNVMDeviceHealthBad(t *testing.T) {
+func TestNVMECLI_getNVMDeviceSMARTBad(t *testing.T) {
 	e := &mocks.GoMockExecutor{}
 	l := NewNVMECLI(e, testLogger)
 
@@ -161,10 +163,10 @@ func TestNVMECLI_getNVMDeviceHealthBad(t *testing.T) {
 	}
 	`
 	e.On("RunCmd", fmt.Sprintf(NVMeHealthCmdImpl, testPath)).Return(health, "", nil)
-	deviceHealth := l.getNVMDeviceHealth(testPath)
+	deviceHealth, _, _ := l.getNVMDeviceSMART(testPath)
 	assert.Equal(t, apiV1.HealthBad, deviceHealth)
 }
-func TestNVMECLI_getNVMDeviceHealthSuspect(t *testing.T) {
+func TestNVMECLI_getNVMDeviceSMARTSuspect(t *testing.T) {
 	e := &mocks.GoMockExecutor{}
 	l := NewNVMECLI(e, testLogger)
 	health := `{
@@ -172,11 +174,11 @@ func TestNVMECLI_getNVMDeviceHealthSuspect(t *testing.T) {
 	}
 	`
 	e.On("RunCmd", fmt.Sprintf(NVMeHealthCmdImpl, testPath)).Return(health, "", nil)
-	deviceHealth := l.getNVMDeviceHealth(testPath)
+	deviceHealth, _, _ := l.getNVMDeviceSMART(testPath)
 	assert.Equal(t, apiV1.HealthSuspect, deviceHealth)
 }
 
-func TestNVMECLI_getNVMDeviceHealthGood(t *testing.T) {
+func TestNVMECLI_getNVMDeviceSMARTGood(t *testing.T) {
 	e := &mocks.GoMockExecutor{}
 	l := NewNVMECLI(e, testLogger)
 	health := `{
@@ -184,11 +186,11 @@ func TestNVMECLI_getNVMDeviceHealthGood(t *testing.T) {
 	}
 	`
 	e.On("RunCmd", fmt.Sprintf(NVMeHealthCmdImpl, testPath)).Return(health, "", nil)
-	deviceHealth := l.getNVMDeviceHealth(testPath)
+	deviceHealth, _, _ := l.getNVMDeviceSMART(testPath)
 	assert.Equal(t, apiV1.HealthGood, deviceHealth)
 }
 
-func TestNVMECLI_getNVMDeviceHealthUnmarshallError(t *testing.T) {
+func TestNVMECLI_getNVMDeviceSMARTUnmarshallError(t *testing.T) {
 	e := &mocks.GoMockExecutor{}
 	l := NewNVMECLI(e, testLogger)
 	//unmarshall error
@@ -197,15 +199,15 @@ func TestNVMECLI_getNVMDeviceHealthUnmarshallError(t *testing.T) {
 	}
 	`
 	e.On("RunCmd", fmt.Sprintf(NVMeHealthCmdImpl, testPath)).Return(health, "", nil)
-	deviceHealth := l.getNVMDeviceHealth(testPath)
+	deviceHealth, _, _ := l.getNVMDeviceSMART(testPath)
 	assert.Equal(t, apiV1.HealthUnknown, deviceHealth)
 }
 
-func TestNVMECLI_getNVMDeviceHealthCMDError(t *testing.T) {
+func TestNVMECLI_getNVMDeviceSMARTCMDError(t *testing.T) {
 	e := &mocks.GoMockExecutor{}
 	l := NewNVMECLI(e, testLogger)
 	e.On("RunCmd", fmt.Sprintf(NVMeHealthCmdImpl, testPath)).Return("", "", fmt.Errorf("error"))
-	deviceHealth := l.getNVMDeviceHealth(testPath)
+	deviceHealth, _, _ := l.getNVMDeviceSMART(testPath)
 	assert.Equal(t, apiV1.HealthUnknown, deviceHealth)
 }
 
@@ -235,6 +237,44 @@ func TestNVMECLI_getNVMDeviceVendorUnmarshalError(t *testing.T) {
 	assert.Equal(t, 0, device.Vendor)
 }
 
+func TestNVMECLI_CreateNamespaces(t *testing.T) {
+	var (
+		ctrlPath = "/dev/nvme9"
+		ctrl     = `{"cntlid" : 4, "tnvmcap" : 4000000000000, "nn" : 32}`
+		ns       = `{"nsze" : 7812500000, "flbas" : 1, "lbafs" : [{"ms" : 0, "ds" : 9}, {"ms" : 0, "ds" : 12}]}`
+		devices  = []NVMDevice{{DevicePath: "/dev/nvme9n1", NameSpace: 1}, {DevicePath: "/dev/nvme9n2", NameSpace: 2}}
+		blocks   = int64(4000000000000 / 4096 / 2)
+	)
+	e := &mocks.GoMockExecutor{}
+	l := NewNVMECLI(e, testLogger)
+
+	// amount of namespaces exceeds controller capabilities
+	e.On("RunCmd", fmt.Sprintf(NVMeVendorCmdImpl, ctrlPath)).Return(ctrl, "", nil)
+	_, err := l.CreateNamespaces(devices, 64)
+	assert.NotNil(t, err)
+
+	e.On("RunCmd", fmt.Sprintf(NVMeIDNamespaceCmdImpl, "/dev/nvme9n1")).Return(ns, "", nil)
+	for _, d := range devices {
+		e.On("RunCmd", fmt.Sprintf(NVMeDetachNamespaceCmdImpl, ctrlPath, d.NameSpace, 4)).Return("", "", nil)
+		e.On("RunCmd", fmt.Sprintf(NVMeDeleteNamespaceCmdImpl, ctrlPath, d.NameSpace)).Return("", "", nil)
+	}
+	createCmd := fmt.Sprintf(NVMeCreateNamespaceCmdImpl, ctrlPath, blocks, blocks, 1)
+	e.On("RunCmd", createCmd).Return("create-ns: Success, created nsid:1", "", nil).Once()
+	e.On("RunCmd", createCmd).Return("create-ns: Success, created nsid:2", "", nil).Once()
+	e.On("RunCmd", fmt.Sprintf(NVMeAttachNamespaceCmdImpl, ctrlPath, 1, 4)).Return("", "", nil)
+	e.On("RunCmd", fmt.Sprintf(NVMeAttachNamespaceCmdImpl, ctrlPath, 2, 4)).Return("", "", nil)
+	e.On("RunCmd", fmt.Sprintf(NVMeRescanNamespacesCmdImpl, ctrlPath)).Return("", "", nil)
+	namespaces, err := l.CreateNamespaces(devices, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2}, namespaces)
+}
+
+func TestNVMECLI_SerialNumbers(t *testing.T) {
+	assert.Equal(t, "SN-ns2", NamespaceSerialNumber("SN", 2))
+	assert.Equal(t, "SN", ControllerSerialNumber("SN-ns2"))
+	assert.Equal(t, "SN", ControllerSerialNumber("SN"))
+}
+
 func TestNVMECLI_isOneOfBitsSet(t *testing.T) {
 	e := &mocks.GoMockExecutor{}
 	l := NewNVMECLI(e, testLogger)
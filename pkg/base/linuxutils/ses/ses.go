@@ -0,0 +1,119 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ses contains code for discovering drive location in SCSI enclosures (SES) through sysfs
+package ses
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils"
+)
+
+const (
+	// enclosureDevicePrefix is a prefix of the link from block device to the enclosure component (slot),
+	// which is created by ses kernel module, e.g. /sys/block/sda/device/enclosure_device:Slot 01
+	enclosureDevicePrefix = "enclosure_device:"
+)
+
+// WrapSES is an interface that encapsulates discovering of drive location in SCSI enclosure
+type WrapSES interface {
+	GetDriveLocation(devicePath string) (*DriveLocation, error)
+}
+
+// DriveLocation represents drive placement in SCSI enclosure
+type DriveLocation struct {
+	// logical identifier of the enclosure, usually SAS address
+	EnclosureID string
+	// slot number of the enclosure component which holds drive
+	Slot string
+	// path to the SES device of the enclosure (backplane), e.g. /dev/sg5
+	Backplane string
+}
+
+// SES is an implementation of WrapSES interface based on sysfs
+type SES struct {
+	sysfs string
+	log   *logrus.Entry
+}
+
+// NewSES is a constructor for SES struct
+func NewSES(logger *logrus.Logger) *SES {
+	return &SES{sysfs: linuxutils.SysfsPath, log: logger.WithField("component", "SES")}
+}
+
+// GetDriveLocation searches enclosure slot which holds block device with provided path, e.g. /dev/sda
+// Returns nil DriveLocation if device isn't placed in SCSI enclosure (NVMe, virtual disks, etc.) or error
+func (s *SES) GetDriveLocation(devicePath string) (*DriveLocation, error) {
+	ll := s.log.WithField("method", "GetDriveLocation")
+
+	deviceDir := filepath.Join(s.sysfs, "block", filepath.Base(devicePath), "device")
+	entries, err := ioutil.ReadDir(deviceDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", deviceDir, err)
+	}
+	var componentName string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), enclosureDevicePrefix) {
+			componentName = entry.Name()
+			break
+		}
+	}
+	if componentName == "" {
+		ll.Debugf("Device %s isn't placed in SCSI enclosure", devicePath)
+		return nil, nil
+	}
+
+	// /sys/devices/.../enclosure/<H:C:T:L>/<component>
+	componentDir, err := filepath.EvalSymlinks(filepath.Join(deviceDir, componentName))
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve enclosure component for %s: %w", devicePath, err)
+	}
+	enclosureDir := filepath.Dir(componentDir)
+
+	location := &DriveLocation{
+		EnclosureID: readAttr(filepath.Join(enclosureDir, "id")),
+		Slot:        readAttr(filepath.Join(componentDir, "slot")),
+	}
+	// fallback to the names from sysfs if attributes aren't provided by enclosure
+	if location.EnclosureID == "" {
+		location.EnclosureID = filepath.Base(enclosureDir)
+	}
+	if location.Slot == "" {
+		location.Slot = strings.TrimPrefix(componentName, enclosureDevicePrefix)
+	}
+	sgEntries, err := ioutil.ReadDir(filepath.Join(enclosureDir, "device", "scsi_generic"))
+	if err != nil || len(sgEntries) == 0 {
+		ll.Warnf("Unable to find SES device of enclosure %s: %v", location.EnclosureID, err)
+	} else {
+		location.Backplane = filepath.Join("/dev", sgEntries[0].Name())
+	}
+	return location, nil
+}
+
+// readAttr returns trimmed content of sysfs attribute or empty string if it can't be read
+func readAttr(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ses
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// prepareSysfs creates minimal sysfs tree with sda placed in enclosure slot and sdb without enclosure
+func prepareSysfs(t *testing.T) string {
+	root, err := ioutil.TempDir("", "sysfs")
+	assert.Nil(t, err)
+
+	enclosureDir := filepath.Join(root, "devices", "enclosure", "0:0:8:0")
+	componentDir := filepath.Join(enclosureDir, "Slot 01")
+	assert.Nil(t, os.MkdirAll(componentDir, 0755))
+	assert.Nil(t, os.MkdirAll(filepath.Join(enclosureDir, "device", "scsi_generic", "sg5"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(enclosureDir, "id"), []byte("0x500056b36789abff\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(componentDir, "slot"), []byte("1\n"), 0644))
+
+	sdaDir := filepath.Join(root, "block", "sda", "device")
+	assert.Nil(t, os.MkdirAll(sdaDir, 0755))
+	assert.Nil(t, os.Symlink(componentDir, filepath.Join(sdaDir, enclosureDevicePrefix+"Slot 01")))
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "block", "sdb", "device"), 0755))
+	return root
+}
+
+func TestSES_GetDriveLocation(t *testing.T) {
+	root := prepareSysfs(t)
+	defer os.RemoveAll(root)
+
+	s := NewSES(logrus.New())
+	s.sysfs = root
+
+	location, err := s.GetDriveLocation("/dev/sda")
+	assert.Nil(t, err)
+	assert.Equal(t, &DriveLocation{EnclosureID: "0x500056b36789abff", Slot: "1", Backplane: "/dev/sg5"}, location)
+
+	// not in enclosure
+	location, err = s.GetDriveLocation("/dev/sdb")
+	assert.Nil(t, err)
+	assert.Nil(t, location)
+
+	// device doesn't exist
+	_, err = s.GetDriveLocation("/dev/sdc")
+	assert.NotNil(t, err)
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package smartctl
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os/exec"
 	"strings"
 
 	"github.com/dell/csi-baremetal/pkg/base/command"
@@ -29,13 +31,13 @@ const (
 	SmartctlCmdImpl = "smartctl"
 	// SmartctlDeviceInfoCmdImpl is a CMD to get basic SMART information and health about device in JSON format
 	SmartctlDeviceInfoCmdImpl = SmartctlCmdImpl + " --info --json %s"
-	// SmartctlHealthCmdImpl is a CMD to get  SMART status of device in JSON format
-	SmartctlHealthCmdImpl = SmartctlCmdImpl + " --health --json %s"
+	// SmartctlHealthCmdImpl is a CMD to get SMART status and attributes (temperature) of device in JSON format
+	SmartctlHealthCmdImpl = SmartctlCmdImpl + " --health --attributes --json %s"
 )
 
 // WrapSmartctl is an interface that encapsulates operation with system smartctl util
 type WrapSmartctl interface {
-	GetDriveInfoByPath(path string) (*DeviceSMARTInfo, error)
+	GetDriveInfoByPath(ctx context.Context, path string) (*DeviceSMARTInfo, error)
 }
 
 // DeviceSMARTInfo represents SMART information about device
@@ -43,6 +45,58 @@ type DeviceSMARTInfo struct {
 	SerialNumber string          `json:"serial_number"`
 	SmartStatus  map[string]bool `json:"smart_status"`
 	Rotation     int             `json:"rotation_rate"`
+	WWN          *DeviceWWN      `json:"wwn,omitempty"`
+	Temperature  *DeviceTemp     `json:"temperature,omitempty"`
+	// percentage of device life used, it is reported by SCSI SSDs only
+	PercentageUsed *int64 `json:"scsi_percentage_used_endurance_indicator,omitempty"`
+}
+
+// DeviceTemp represents temperature of device in Celsius as it is reported by smartctl
+type DeviceTemp struct {
+	Current int32 `json:"current"`
+}
+
+// DeviceWWN represents World Wide Name of device as it is reported by smartctl
+type DeviceWWN struct {
+	NAA uint64 `json:"naa"`
+	OUI uint64 `json:"oui"`
+	ID  uint64 `json:"id"`
+}
+
+// String returns WWN in the form which is used by lsblk and udev, for example 0x5000c500a1b2c3d4
+func (w *DeviceWWN) String() string {
+	if w == nil {
+		return ""
+	}
+	return fmt.Sprintf("0x%x%06x%09x", w.NAA, w.OUI, w.ID)
+}
+
+// CurrentTemperature returns current temperature of device in Celsius, 0 if device doesn't report it
+func (i *DeviceSMARTInfo) CurrentTemperature() int32 {
+	if i.Temperature == nil {
+		return 0
+	}
+	return i.Temperature.Current
+}
+
+// Endurance returns remaining endurance of device in percents, 0 if device doesn't report it
+func (i *DeviceSMARTInfo) Endurance() int64 {
+	if i.PercentageUsed == nil {
+		return 0
+	}
+	return EnduranceFromPercentageUsed(*i.PercentageUsed)
+}
+
+// EnduranceFromPercentageUsed converts percentage of device life used into remaining endurance in percents,
+// percentage used could exceed 100 when device is used beyond its rated life
+func EnduranceFromPercentageUsed(percentageUsed int64) int64 {
+	switch {
+	case percentageUsed >= 100:
+		return 0
+	case percentageUsed <= 0:
+		return 100
+	}
+	return 100 - percentageUsed
 }
 
 // SMARTCTL is a wrap for system smartctl util
@@ -56,10 +110,9 @@ func NewSMARTCTL(e command.CmdExecutor) *SMARTCTL {
 }
 
 // GetDriveInfoByPath gets SMART information about device by its Path using smartctl util
-func (sa *SMARTCTL) GetDriveInfoByPath(path string) (*DeviceSMARTInfo, error) {
-	strOut, _, err := sa.e.RunCmd(fmt.Sprintf(SmartctlDeviceInfoCmdImpl, path),
-		command.UseMetrics(true),
-		command.CmdName(strings.TrimSpace(fmt.Sprintf(SmartctlDeviceInfoCmdImpl, ""))))
+// smartctl is killed once ctx is done, so hung device doesn't block the caller
+func (sa *SMARTCTL) GetDriveInfoByPath(ctx context.Context, path string) (*DeviceSMARTInfo, error) {
+	strOut, err := sa.run(ctx, SmartctlDeviceInfoCmdImpl, path)
 	if err != nil {
 		return nil, err
 	}
@@ -67,27 +120,35 @@ func (sa *SMARTCTL) GetDriveInfoByPath(path string) (*DeviceSMARTInfo, error) {
 	bytes := []byte(strOut)
 	err = json.Unmarshal(bytes, deviceInfo)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal output to []DeviceSMARTInfo instance, error: %v", err)
+		return nil, fmt.Errorf("unable to unmarshal output to []DeviceSMARTInfo instance, error: %w", err)
 	}
-	err = sa.fillSmartStatus(deviceInfo, path)
+	err = sa.fillSmartStatus(ctx, deviceInfo, path)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get SMART status for device %s, error: %v", path, err)
+		return nil, fmt.Errorf("unable to get SMART status for device %s, error: %w", path, err)
 	}
 	return deviceInfo, nil
 }
 
-// fillSmartStatus fill smart_status field in DeviceSMARTInfo using smartctl command
-func (sa *SMARTCTL) fillSmartStatus(dev *DeviceSMARTInfo, path string) error {
-	strOut, _, err := sa.e.RunCmd(fmt.Sprintf(SmartctlHealthCmdImpl, path),
-		command.UseMetrics(true),
-		command.CmdName(strings.TrimSpace(fmt.Sprintf(SmartctlHealthCmdImpl, ""))))
+// fillSmartStatus fill smart_status, temperature and endurance fields in DeviceSMARTInfo using smartctl command
+func (sa *SMARTCTL) fillSmartStatus(ctx context.Context, dev *DeviceSMARTInfo, path string) error {
+	strOut, err := sa.run(ctx, SmartctlHealthCmdImpl, path)
 	if err != nil {
 		return err
 	}
 	bytes := []byte(strOut)
 	err = json.Unmarshal(bytes, dev)
 	if err != nil {
-		return fmt.Errorf("unable to unmarshal output to []Device instance, error: %v", err)
+		return fmt.Errorf("unable to unmarshal output to []Device instance, error: %w", err)
 	}
 	return nil
 }
+
+// run runs smartctl command from template for device path, command is killed once ctx is done
+// Returns stdout of the command
+func (sa *SMARTCTL) run(ctx context.Context, tmpl, path string) (string, error) {
+	args := append(strings.Fields(fmt.Sprintf(tmpl, "")), path)
+	strOut, _, err := sa.e.RunCmd(exec.CommandContext(ctx, args[0], args[1:]...),
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(tmpl, ""))))
+	return strOut, err
+}
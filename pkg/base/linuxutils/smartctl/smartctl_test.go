@@ -17,7 +17,9 @@ limitations under the License.
 package smartctl
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,25 +34,42 @@ func TestSMARCTL_GetDriveInfoByPath(t *testing.T) {
 					"name": "/dev/sdd", 
 					"info_name": "/dev/sdd [SAT]", 
 					"type": "sat", "protocol": "ATA"}, 
-					"rotation_rate": 7200
+					"rotation_rate": 7200,
+					"wwn": {"naa": 5, "oui": 3152, "id": 2712847316}
 				}`
 	outputHealth := `{
     "smart_status": {
         "passed": true
-    }}`
+    },
+    "temperature": {
+        "current": 37
+    },
+    "scsi_percentage_used_endurance_indicator": 12}`
 	cmd := fmt.Sprintf(SmartctlDeviceInfoCmdImpl, "/dev/sdd")
 	cmdHealth := fmt.Sprintf(SmartctlHealthCmdImpl, "/dev/sdd")
 	e := &mocks.GoMockExecutor{}
 	l := NewSMARTCTL(e)
 
-	e.On("RunCmd", cmd).Return(output, "", nil)
-	e.On("RunCmd", cmdHealth).Return(outputHealth, "", nil)
-	smartInfo, err := l.GetDriveInfoByPath("/dev/sdd")
+	e.OnCommandArgs(strings.Fields(cmd)...).Return(output, "", nil)
+	e.OnCommandArgs(strings.Fields(cmdHealth)...).Return(outputHealth, "", nil)
+	smartInfo, err := l.GetDriveInfoByPath(context.Background(), "/dev/sdd")
 	assert.Nil(t, err)
 
 	assert.Equal(t, smartInfo.SerialNumber, "29P4K65PF9NF")
 	assert.Equal(t, smartInfo.Rotation, 7200)
 	assert.Equal(t, smartInfo.SmartStatus, map[string]bool{"passed": true})
+	assert.Equal(t, "0x5000c500a1b2c3d4", smartInfo.WWN.String())
+	assert.Equal(t, int32(37), smartInfo.CurrentTemperature())
+	assert.Equal(t, int32(0), (&DeviceSMARTInfo{}).CurrentTemperature())
+	assert.Equal(t, int64(88), smartInfo.Endurance())
+	assert.Equal(t, int64(0), (&DeviceSMARTInfo{}).Endurance())
+}
+
+func TestEnduranceFromPercentageUsed(t *testing.T) {
+	assert.Equal(t, int64(100), EnduranceFromPercentageUsed(0))
+	assert.Equal(t, int64(75), EnduranceFromPercentageUsed(25))
+	assert.Equal(t, int64(0), EnduranceFromPercentageUsed(100))
+	assert.Equal(t, int64(0), EnduranceFromPercentageUsed(255))
 }
 
 func TestSMARCTL_GetDriveInfoByPathFails(t *testing.T) {
@@ -58,9 +77,9 @@ func TestSMARCTL_GetDriveInfoByPathFails(t *testing.T) {
 	e := &mocks.GoMockExecutor{}
 	l := NewSMARTCTL(e)
 
-	e.On("RunCmd", cmd).Return("", "", fmt.Errorf("error"))
+	e.OnCommandArgs(strings.Fields(cmd)...).Return("", "", fmt.Errorf("error"))
 
-	_, err := l.GetDriveInfoByPath("/dev/sdd")
+	_, err := l.GetDriveInfoByPath(context.Background(), "/dev/sdd")
 	assert.NotNil(t, err)
 }
 
@@ -80,9 +99,9 @@ func TestSMARCTL_GetDriveInfoByPathUnmarshallError(t *testing.T) {
 	e := &mocks.GoMockExecutor{}
 	l := NewSMARTCTL(e)
 
-	e.On("RunCmd", cmd).Return(output, "", nil)
+	e.OnCommandArgs(strings.Fields(cmd)...).Return(output, "", nil)
 
-	_, err := l.GetDriveInfoByPath("/dev/sdd")
+	_, err := l.GetDriveInfoByPath(context.Background(), "/dev/sdd")
 	assert.NotNil(t, err)
 }
 
@@ -91,9 +110,9 @@ func TestSMARCTL_fillSmartStatus(t *testing.T) {
 	e := &mocks.GoMockExecutor{}
 	l := NewSMARTCTL(e)
 
-	e.On("RunCmd", cmd).Return("", "", fmt.Errorf("error"))
+	e.OnCommandArgs(strings.Fields(cmd)...).Return("", "", fmt.Errorf("error"))
 
-	err := l.fillSmartStatus(&DeviceSMARTInfo{}, "/dev/sdd")
+	err := l.fillSmartStatus(context.Background(), &DeviceSMARTInfo{}, "/dev/sdd")
 	assert.NotNil(t, err)
 }
 
@@ -107,8 +126,8 @@ func TestSMARCTL_fillSmartStatusUnmarshallError(t *testing.T) {
 	e := &mocks.GoMockExecutor{}
 	l := NewSMARTCTL(e)
 
-	e.On("RunCmd", cmd).Return(output, "", nil)
+	e.OnCommandArgs(strings.Fields(cmd)...).Return(output, "", nil)
 
-	err := l.fillSmartStatus(&DeviceSMARTInfo{}, "/dev/sdd")
+	err := l.fillSmartStatus(context.Background(), &DeviceSMARTInfo{}, "/dev/sdd")
 	assert.NotNil(t, err)
 }
@@ -0,0 +1,115 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package zoned contains code for working with zoned block devices (host-managed SMR drives and NVMe ZNS)
+package zoned
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+)
+
+const (
+	// zonedAttr is an attribute of block device queue which contains zoned model of the device
+	zonedAttr = "queue/zoned"
+
+	// ModelNone is reported for regular block devices
+	ModelNone = "none"
+	// ModelHostAware is reported for devices which accept random writes, they are used as regular devices
+	ModelHostAware = "host-aware"
+	// ModelHostManaged is reported for devices which require sequential writes within zone
+	ModelHostManaged = "host-managed"
+
+	// BlkZoneResetCmdTmpl cmd for resetting write pointers of all zones of the device
+	BlkZoneResetCmdTmpl = "blkzone reset %s"
+	// MkF2FSZonedCmdTmpl cmd for creating f2fs in zoned mode
+	MkF2FSZonedCmdTmpl = "mkfs.f2fs -m %s"
+	// MkBtrfsZonedCmdTmpl cmd for creating btrfs in zoned mode
+	MkBtrfsZonedCmdTmpl = "mkfs.btrfs -O zoned %s"
+)
+
+// WrapZoned is an interface that encapsulates operations with zoned block devices
+type WrapZoned interface {
+	// GetZonedModel returns zoned model of block device with provided path, e.g. /dev/sda
+	GetZonedModel(devicePath string) (string, error)
+	// ResetZones resets write pointers of all zones of the device, data on the device is lost
+	ResetZones(device string) error
+	// CreateFS creates zone aware file system on the whole device
+	CreateFS(fsType fs.FileSystem, device string) error
+}
+
+// Zoned is an implementation of WrapZoned interface based on sysfs and system utils
+type Zoned struct {
+	e     command.CmdExecutor
+	sysfs string
+}
+
+// NewZoned is a constructor for Zoned struct
+func NewZoned(e command.CmdExecutor) *Zoned {
+	return &Zoned{e: e, sysfs: linuxutils.SysfsPath}
+}
+
+// GetZonedModel reads zoned model of block device from sysfs
+// Returns ModelNone if kernel doesn't report zoned model of the device
+func (z *Zoned) GetZonedModel(devicePath string) (string, error) {
+	attr := filepath.Join(z.sysfs, "block", filepath.Base(devicePath), zonedAttr)
+	data, err := ioutil.ReadFile(attr)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ModelNone, nil
+		}
+		return "", fmt.Errorf("unable to read %s: %w", attr, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ResetZones resets write pointers of all zones of the device using blkzone
+func (z *Zoned) ResetZones(device string) error {
+	cmd := fmt.Sprintf(BlkZoneResetCmdTmpl, device)
+	if _, _, err := z.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.TrimSpace(fmt.Sprintf(BlkZoneResetCmdTmpl, "")))); err != nil {
+		return fmt.Errorf("failed to reset zones of %s: %w", device, err)
+	}
+	return nil
+}
+
+// CreateFS creates f2fs or btrfs in zoned mode on the device, other file systems don't support zoned devices
+func (z *Zoned) CreateFS(fsType fs.FileSystem, device string) error {
+	var cmd string
+	switch fsType {
+	case fs.F2FS:
+		cmd = fmt.Sprintf(MkF2FSZonedCmdTmpl, device)
+	case fs.BTRFS:
+		cmd = fmt.Sprintf(MkBtrfsZonedCmdTmpl, device)
+	default:
+		return fmt.Errorf("file system %v doesn't support zoned devices, use %s or %s", fsType, fs.F2FS, fs.BTRFS)
+	}
+
+	if _, _, err := z.e.RunCmd(cmd,
+		command.UseMetrics(true),
+		command.CmdName(strings.Fields(cmd)[0])); err != nil {
+		return fmt.Errorf("failed to create file system on %s: %w", device, err)
+	}
+	return nil
+}
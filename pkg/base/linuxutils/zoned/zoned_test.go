@@ -0,0 +1,77 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zoned
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+func TestZoned_GetZonedModel(t *testing.T) {
+	sysfs, err := ioutil.TempDir("", "sysfs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(sysfs)
+
+	queue := filepath.Join(sysfs, "block", "sda", "queue")
+	assert.Nil(t, os.MkdirAll(queue, 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(queue, "zoned"), []byte("host-managed\n"), 0644))
+
+	z := NewZoned(&mocks.GoMockExecutor{})
+	z.sysfs = sysfs
+
+	model, err := z.GetZonedModel("/dev/sda")
+	assert.Nil(t, err)
+	assert.Equal(t, ModelHostManaged, model)
+
+	// attribute isn't reported
+	model, err = z.GetZonedModel("/dev/sdb")
+	assert.Nil(t, err)
+	assert.Equal(t, ModelNone, model)
+}
+
+func TestZoned_ResetZones(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	z := NewZoned(e)
+	cmd := fmt.Sprintf(BlkZoneResetCmdTmpl, "/dev/sda")
+
+	e.OnCommand(cmd).Return("", "", nil).Once()
+	assert.Nil(t, z.ResetZones("/dev/sda"))
+
+	e.OnCommand(cmd).Return("", "", fmt.Errorf("error")).Once()
+	assert.NotNil(t, z.ResetZones("/dev/sda"))
+}
+
+func TestZoned_CreateFS(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	z := NewZoned(e)
+
+	e.OnCommand(fmt.Sprintf(MkF2FSZonedCmdTmpl, "/dev/sda")).Return("", "", nil).Once()
+	assert.Nil(t, z.CreateFS(fs.F2FS, "/dev/sda"))
+
+	e.OnCommand(fmt.Sprintf(MkBtrfsZonedCmdTmpl, "/dev/sda")).Return("", "", fmt.Errorf("error")).Once()
+	assert.NotNil(t, z.CreateFS(fs.BTRFS, "/dev/sda"))
+
+	assert.NotNil(t, z.CreateFS(fs.XFS, "/dev/sda"))
+}
@@ -53,20 +53,7 @@ func InitLogger(logPath string, logLevel string) (*logrus.Logger, error) {
 		logger.SetFormatter(&logrus.JSONFormatter{})
 	}
 
-	var level logrus.Level
-	// set log level
-	switch strings.ToLower(logLevel) {
-	case InfoLevel:
-		level = logrus.InfoLevel
-	case DebugLevel:
-		level = logrus.DebugLevel
-	case TraceLevel:
-		level = logrus.TraceLevel
-	default:
-		level = logrus.InfoLevel
-	}
-
-	logger.SetLevel(level)
+	logger.SetLevel(ParseLogLevel(logLevel))
 
 	// set output
 	if logPath != "" {
@@ -82,3 +69,16 @@ func InitLogger(logPath string, logLevel string) (*logrus.Logger, error) {
 
 	return logger, nil
 }
+
+// ParseLogLevel converts log level string to logrus.Level
+// Receives log level string (info, debug, trace), unknown value is treated as info
+func ParseLogLevel(logLevel string) logrus.Level {
+	switch strings.ToLower(logLevel) {
+	case DebugLevel:
+		return logrus.DebugLevel
+	case TraceLevel:
+		return logrus.TraceLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
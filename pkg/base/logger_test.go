@@ -20,6 +20,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -55,3 +56,10 @@ func TestInitLoggerWrongPath(t *testing.T) {
 
 	assert.Equal(t, logger.Out, os.Stdout, "Logger's defalut output should be set to the stdout")
 }
+
+func TestParseLogLevel(t *testing.T) {
+	assert.Equal(t, logrus.DebugLevel, ParseLogLevel(DebugLevel))
+	assert.Equal(t, logrus.TraceLevel, ParseLogLevel("TRACE"))
+	assert.Equal(t, logrus.InfoLevel, ParseLogLevel(InfoLevel))
+	assert.Equal(t, logrus.InfoLevel, ParseLogLevel("unknown"))
+}
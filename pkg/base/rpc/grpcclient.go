@@ -47,7 +47,7 @@ func NewClient(creds credentials.TransportCredentials, endpoint string, enableMe
 	client.SetLogger(logger)
 	err := client.initClient()
 	if err != nil {
-		return nil, fmt.Errorf("unable to create client, error: %v", err)
+		return nil, fmt.Errorf("unable to create client, error: %w", err)
 	}
 	return client, nil
 }
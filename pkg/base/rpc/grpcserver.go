@@ -17,10 +17,20 @@ limitations under the License.
 package rpc
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
 	"net"
 	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/container-storage-interface/spec/lib/go/csi"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
@@ -30,6 +40,11 @@ import (
 const (
 	tcp  string = "tcp"
 	unix string = "unix"
+
+	// staleSocketDialTimeout is the time during which server which owns existing socket should accept connection
+	staleSocketDialTimeout = time.Second
+	// DeadlineMargin is reserved from deadline of the client, so server responds before the client stops waiting
+	DeadlineMargin = time.Second
 )
 
 // ServerRunner encapsulates logic for creating/starting/stopping gRPC server
@@ -40,8 +55,21 @@ type ServerRunner struct {
 	Endpoint       string
 	log            *logrus.Entry
 	metricsEnabled bool
+	// socketMode, socketUID and socketGID are applied to unix socket once it is created, 0 mode and -1 ids are skipped
+	socketMode os.FileMode
+	socketUID  int
+	socketGID  int
+	// operationTimeouts maps gRPC method name (e.g. CreateVolume) to server side deadline of its requests
+	operationTimeouts map[string]time.Duration
+	// panicHandler records panic of the request handler before the process exits
+	panicHandler PanicHandler
 }
 
+// PanicHandler is called when handler of the request panics, process exits once it returns
+// Receives gRPC method name, ID of the volume from the request (empty if request isn't related to the volume),
+// value passed to panic and stack trace
+type PanicHandler func(method, volumeID string, reason interface{}, stack []byte)
+
 // NewServerRunner returns ServerRunner object based on parameters that had provided
 // Receives credentials for connection, connection endpoint (for example 'tcp://localhost:8888') and logrus logger
 // Returns an instance of ServerRunner struct
@@ -50,6 +78,8 @@ func NewServerRunner(creds credentials.TransportCredentials, endpoint string, en
 		Creds:          creds,
 		Endpoint:       endpoint,
 		metricsEnabled: enableMetrics,
+		socketUID:      -1,
+		socketGID:      -1,
 	}
 	sr.SetLogger(logger)
 	sr.init()
@@ -64,37 +94,219 @@ func (sr *ServerRunner) SetLogger(logger *logrus.Logger) {
 	sr.log = logger.WithField("component", "ServerRunner")
 }
 
+// SetSocketPermissions sets permissions and ownership which are applied to unix socket once it is created,
+// 0 mode keeps permissions set by umask and -1 uid or gid keeps corresponding id of the process
+func (sr *ServerRunner) SetSocketPermissions(mode os.FileMode, uid, gid int) {
+	sr.socketMode = mode
+	sr.socketUID = uid
+	sr.socketGID = gid
+}
+
+// SetOperationTimeouts sets server side deadlines of requests by gRPC method name (e.g. CreateVolume),
+// should be called before RunServer
+func (sr *ServerRunner) SetOperationTimeouts(timeouts map[string]time.Duration) {
+	sr.operationTimeouts = timeouts
+}
+
+// SetPanicHandler sets handler which records panics of the request handlers, should be called before RunServer
+func (sr *ServerRunner) SetPanicHandler(handler PanicHandler) {
+	sr.panicHandler = handler
+}
+
 // init initializes GRPCServer field of ServerRunner struct
 func (sr *ServerRunner) init() {
-	opts := make([]grpc.ServerOption, 0)
+	opts := []grpc.ServerOption{grpc.UnaryInterceptor(sr.unaryInterceptor)}
 	if sr.Creds != nil {
 		opts = append(opts, grpc.Creds(sr.Creds))
 	}
 
 	if sr.metricsEnabled {
-		opts = append(opts, grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor), grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor))
+		opts = append(opts, grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor))
 	}
 	sr.GRPCServer = grpc.NewServer(opts...)
 }
 
+// unaryInterceptor sets deadline of the request, records panics and collects metrics if they are enabled,
+// grpc server supports the only one unary interceptor
+func (sr *ServerRunner) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	method := path.Base(info.FullMethod)
+	if sr.panicHandler != nil {
+		defer func() {
+			if reason := recover(); reason != nil {
+				sr.panicHandler(method, requestVolumeID(req), reason, debug.Stack())
+				panic(reason)
+			}
+		}()
+	}
+	ctx, cancel := sr.withDeadline(ctx, method)
+	defer cancel()
+	if sr.metricsEnabled {
+		return grpc_prometheus.UnaryServerInterceptor(ctx, req, info, handler)
+	}
+	return handler(ctx, req)
+}
+
+// requestVolumeID returns ID of the volume from CSI request, name of the volume is its ID for CreateVolume
+func requestVolumeID(req interface{}) string {
+	switch r := req.(type) {
+	case interface{ GetVolumeId() string }:
+		return r.GetVolumeId()
+	case *csi.CreateVolumeRequest:
+		return r.GetName()
+	}
+	return ""
+}
+
+// withDeadline returns context which is done before the client stops waiting for response
+// or once timeout of the method is expired if it is configured
+func (sr *ServerRunner) withDeadline(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if ok && time.Until(deadline) > DeadlineMargin {
+		deadline = deadline.Add(-DeadlineMargin)
+	}
+	if timeout, configured := sr.operationTimeouts[method]; configured {
+		if operationDeadline := time.Now().Add(timeout); !ok || operationDeadline.Before(deadline) {
+			deadline, ok = operationDeadline, true
+		}
+	}
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, deadline)
+}
+
 // RunServer creates Listener and starts gRPC server on endpoint
 // Receives error if error occurred during Listener creation or during GRPCServer.Serve
 func (sr *ServerRunner) RunServer() error {
 	var err error
 	endpoint, socket := sr.GetEndpoint()
 	if socket == unix {
-		// try to remove
-		_ = os.Remove(endpoint)
+		// socket is left after unclean restart
+		if err = sr.removeStaleSocket(endpoint); err != nil {
+			sr.log.Errorf("failed to remove stale socket %s: %v", endpoint, err)
+			return err
+		}
 	}
 	sr.listener, err = net.Listen(socket, endpoint)
 	if err != nil {
 		sr.log.Errorf("failed to create listener for endpoint %s: %v", endpoint, err)
 		return err
 	}
+	if socket == unix {
+		if err = sr.applySocketPermissions(endpoint); err != nil {
+			sr.log.Errorf("failed to set permissions of socket %s: %v", endpoint, err)
+			_ = sr.listener.Close()
+			return err
+		}
+	}
 	sr.log.Infof("Starting gRPC server for endpoint %s and socket %s", endpoint, socket)
 	return sr.GRPCServer.Serve(sr.listener)
 }
 
+// CheckEndpoint checks that unix socket could be created, directory of the socket should exist and be writable
+// Returns error if socket couldn't be created, nil for TCP endpoint
+func (sr *ServerRunner) CheckEndpoint() error {
+	endpoint, socket := sr.GetEndpoint()
+	if socket != unix {
+		return nil
+	}
+	dir := filepath.Dir(endpoint)
+	probe, err := ioutil.TempFile(dir, ".probe-")
+	if err != nil {
+		return fmt.Errorf("directory %s of socket %s isn't writable: %w", dir, endpoint, err)
+	}
+	_ = probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// removeStaleSocket removes socket file which isn't served by any process
+// Returns error if path isn't a socket or if socket is served by another server
+func (sr *ServerRunner) removeStaleSocket(endpoint string) error {
+	info, err := os.Lstat(endpoint)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s isn't a socket, it isn't removed", endpoint)
+	}
+	if conn, err := net.DialTimeout(unix, endpoint, staleSocketDialTimeout); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("socket %s is served by another server: address already in use", endpoint)
+	}
+	sr.log.Infof("Removing stale socket %s", endpoint)
+	return os.Remove(endpoint)
+}
+
+// applySocketPermissions sets permissions and ownership of the socket if they are configured
+func (sr *ServerRunner) applySocketPermissions(endpoint string) error {
+	if sr.socketUID != -1 || sr.socketGID != -1 {
+		if err := os.Chown(endpoint, sr.socketUID, sr.socketGID); err != nil {
+			return err
+		}
+	}
+	if sr.socketMode != 0 {
+		return os.Chmod(endpoint, sr.socketMode)
+	}
+	return nil
+}
+
+// ParseOperationTimeouts parses comma-separated timeouts of gRPC methods, e.g. "CreateVolume=5m,DeleteVolume=2m"
+func ParseOperationTimeouts(str string) (map[string]time.Duration, error) {
+	timeouts := map[string]time.Duration{}
+	for _, item := range strings.Split(str, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid operation timeout %s, method=duration is expected", item)
+		}
+		timeout, err := time.ParseDuration(parts[1])
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout %s of method %s, positive duration is expected", parts[1], parts[0])
+		}
+		timeouts[parts[0]] = timeout
+	}
+	return timeouts, nil
+}
+
+// ParseSocketPermissions parses octal mode (e.g. "0660") and owner in "uid:gid" format (e.g. "0:1000" or ":1000"),
+// empty mode is returned as 0 and empty ids are returned as -1, they are skipped by SetSocketPermissions
+func ParseSocketPermissions(mode, owner string) (os.FileMode, int, int, error) {
+	var (
+		fileMode os.FileMode
+		ids      = []int{-1, -1}
+	)
+	if mode != "" {
+		m, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil || m > uint64(os.ModePerm) {
+			return 0, -1, -1, fmt.Errorf("invalid socket mode %s, octal permissions are expected", mode)
+		}
+		fileMode = os.FileMode(m)
+	}
+	if owner != "" {
+		parts := strings.Split(owner, ":")
+		if len(parts) > 2 {
+			return 0, -1, -1, fmt.Errorf("invalid socket owner %s, uid:gid is expected", owner)
+		}
+		for i, part := range parts {
+			if part == "" {
+				continue
+			}
+			id, err := strconv.Atoi(part)
+			if err != nil || id < 0 {
+				return 0, -1, -1, fmt.Errorf("invalid socket owner %s, uid:gid is expected", owner)
+			}
+			ids[i] = id
+		}
+	}
+	return fileMode, ids[0], ids[1], nil
+}
+
 // StopServer gracefully stops gRPC server and closes listener
 func (sr *ServerRunner) StopServer() {
 	sr.log.Info("Stopping server")
@@ -17,13 +17,20 @@ limitations under the License.
 package rpc
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
 
 	basenet "github.com/dell/csi-baremetal/pkg/base/net"
 )
@@ -89,6 +96,166 @@ func TestServerRunner_GetEndpoint(t *testing.T) {
 	assert.Equal(t, "/tmp/csi.sock", endpoint)
 }
 
+func TestServerRunner_RunServerUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sock")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	socketPath := filepath.Join(dir, "csi.sock")
+
+	// stale socket is left by killed process
+	addr, err := net.ResolveUnixAddr(unix, socketPath)
+	assert.Nil(t, err)
+	stale, err := net.ListenUnix(unix, addr)
+	assert.Nil(t, err)
+	stale.SetUnlinkOnClose(false)
+	assert.Nil(t, stale.Close())
+	_, err = os.Stat(socketPath)
+	assert.Nil(t, err)
+
+	sr := NewServerRunner(nil, "unix://"+socketPath, false, serverLogger)
+	sr.SetSocketPermissions(0600, os.Getuid(), os.Getgid())
+	assert.Nil(t, sr.CheckEndpoint())
+	go func() {
+		_ = sr.RunServer()
+	}()
+	defer sr.StopServer()
+	assert.Eventually(t, func() bool {
+		conn, err := net.Dial(unix, socketPath)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	info, err := os.Stat(socketPath)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// socket which is served isn't removed
+	sr2 := NewServerRunner(nil, "unix://"+socketPath, false, serverLogger)
+	err = sr2.RunServer()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "address already in use")
+	_, err = os.Stat(socketPath)
+	assert.Nil(t, err)
+
+	// regular file isn't removed
+	filePath := filepath.Join(dir, "file")
+	assert.Nil(t, ioutil.WriteFile(filePath, []byte("data"), 0600))
+	sr3 := NewServerRunner(nil, "unix://"+filePath, false, serverLogger)
+	err = sr3.RunServer()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "isn't a socket")
+	_, err = os.Stat(filePath)
+	assert.Nil(t, err)
+}
+
+func TestServerRunner_CheckEndpoint(t *testing.T) {
+	assert.Nil(t, nonSecureSR.CheckEndpoint())
+
+	sr := NewServerRunner(nil, "unix:///not/existing/dir/csi.sock", false, serverLogger)
+	err := sr.CheckEndpoint()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "isn't writable")
+}
+
+func TestServerRunner_withDeadline(t *testing.T) {
+	sr := NewServerRunner(nil, endpoint, false, serverLogger)
+	sr.SetOperationTimeouts(map[string]time.Duration{"CreateVolume": 5 * time.Second, "DeleteVolume": time.Minute})
+
+	// request without deadline and timeout
+	ctx, cancel := sr.withDeadline(context.Background(), "NodeGetInfo")
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+	cancel()
+
+	// timeout is set for request without deadline
+	ctx, cancel = sr.withDeadline(context.Background(), "CreateVolume")
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.InDelta(t, 5*time.Second, time.Until(deadline), float64(100*time.Millisecond))
+	cancel()
+
+	clientCtx, clientCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer clientCancel()
+	// timeout is shorter than deadline of the client
+	ctx, cancel = sr.withDeadline(clientCtx, "CreateVolume")
+	deadline, _ = ctx.Deadline()
+	assert.InDelta(t, 5*time.Second, time.Until(deadline), float64(100*time.Millisecond))
+	cancel()
+	// server responds before the client stops waiting
+	for _, method := range []string{"DeleteVolume", "NodeGetInfo"} {
+		ctx, cancel = sr.withDeadline(clientCtx, method)
+		deadline, _ = ctx.Deadline()
+		assert.InDelta(t, 10*time.Second-DeadlineMargin, time.Until(deadline), float64(100*time.Millisecond))
+		cancel()
+	}
+}
+
+func TestServerRunner_unaryInterceptorPanic(t *testing.T) {
+	sr := NewServerRunner(nil, endpoint, false, serverLogger)
+	var method, volumeID string
+	sr.SetPanicHandler(func(m, v string, reason interface{}, stack []byte) {
+		method, volumeID = m, v
+		assert.Equal(t, "boom", reason)
+		assert.Contains(t, string(stack), "TestServerRunner_unaryInterceptorPanic")
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: "/csi.v1.Node/NodeStageVolume"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { panic("boom") }
+
+	// panic is recorded and raised again
+	assert.PanicsWithValue(t, "boom", func() {
+		_, _ = sr.unaryInterceptor(context.Background(), &csi.NodeStageVolumeRequest{VolumeId: "volume-1"}, info, handler)
+	})
+	assert.Equal(t, "NodeStageVolume", method)
+	assert.Equal(t, "volume-1", volumeID)
+
+	info.FullMethod = "/csi.v1.Controller/CreateVolume"
+	assert.Panics(t, func() {
+		_, _ = sr.unaryInterceptor(context.Background(), &csi.CreateVolumeRequest{Name: "pvc-1"}, info, handler)
+	})
+	assert.Equal(t, "pvc-1", volumeID)
+}
+
+func TestParseOperationTimeouts(t *testing.T) {
+	timeouts, err := ParseOperationTimeouts("")
+	assert.Nil(t, err)
+	assert.Empty(t, timeouts)
+
+	timeouts, err = ParseOperationTimeouts("CreateVolume=5m, NodePublishVolume=30s,")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]time.Duration{"CreateVolume": 5 * time.Minute, "NodePublishVolume": 30 * time.Second}, timeouts)
+
+	for _, str := range []string{"CreateVolume", "=5m", "CreateVolume=5", "CreateVolume=-1m"} {
+		_, err = ParseOperationTimeouts(str)
+		assert.NotNil(t, err, str)
+	}
+}
+
+func TestParseSocketPermissions(t *testing.T) {
+	mode, uid, gid, err := ParseSocketPermissions("", "")
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0), mode)
+	assert.Equal(t, -1, uid)
+	assert.Equal(t, -1, gid)
+
+	mode, uid, gid, err = ParseSocketPermissions("0660", "0:1000")
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0660), mode)
+	assert.Equal(t, 0, uid)
+	assert.Equal(t, 1000, gid)
+
+	_, uid, gid, err = ParseSocketPermissions("", ":1000")
+	assert.Nil(t, err)
+	assert.Equal(t, -1, uid)
+	assert.Equal(t, 1000, gid)
+
+	for _, params := range [][2]string{{"0888", ""}, {"17777", ""}, {"", "root"}, {"", "1:2:3"}, {"", "-5:1"}} {
+		_, _, _, err = ParseSocketPermissions(params[0], params[1])
+		assert.NotNil(t, err, params)
+	}
+}
+
 func TestServerRunner_StopServer(t *testing.T) {
 	// stop server
 	nonSecureSR.StopServer()
@@ -0,0 +1,139 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package startup contains code for ordering of component startup by its dependencies (k8s API, CRDs),
+// component waits for them with timeout instead of crashing in a loop
+package startup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/discovery"
+)
+
+const (
+	// DefaultTimeout is time which component waits for all its dependencies, it is less than initial delay of
+	// liveness probes of the chart (300s), so component serves probes before kubelet starts them
+	DefaultTimeout = 4 * time.Minute
+	// DefaultInterval is interval between probes of the dependency which isn't ready
+	DefaultInterval = 5 * time.Second
+)
+
+// Probe checks whether dependency is ready, it returns error which describes why it isn't
+type Probe func(ctx context.Context) error
+
+// check is a named dependency of the component
+type check struct {
+	name  string
+	probe Probe
+}
+
+// Gate waits for dependencies of the component in order in which they were added
+type Gate struct {
+	checks   []check
+	timeout  time.Duration
+	interval time.Duration
+	log      *logrus.Entry
+}
+
+// NewGate is a constructor for Gate struct
+// Receives total timeout of waiting, DefaultTimeout is used if it isn't positive, and logrus logger
+// Returns an instance of Gate
+func NewGate(timeout time.Duration, logger *logrus.Logger) *Gate {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Gate{
+		timeout:  timeout,
+		interval: DefaultInterval,
+		log:      logger.WithField("component", "StartupGate"),
+	}
+}
+
+// Add appends dependency which is waited after all previously added ones
+// Receives name of the dependency which is used in status reporting and probe of its readiness
+// Returns the gate to chain calls
+func (g *Gate) Add(name string, probe Probe) *Gate {
+	g.checks = append(g.checks, check{name: name, probe: probe})
+	return g
+}
+
+// Wait probes dependencies one by one until each of them is ready
+// Receives golang context which interrupts waiting
+// Returns error with name of the dependency which isn't ready within timeout or if context is done
+func (g *Gate) Wait(ctx context.Context) error {
+	ll := g.log.WithField("method", "Wait")
+
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	start := time.Now()
+	for _, c := range g.checks {
+		var lastErr error
+		for {
+			err := c.probe(ctx)
+			if err == nil {
+				break
+			}
+			err = fmt.Errorf("waiting for %s: %w", c.name, err)
+			// the same reason is logged once to keep logs readable during long waiting
+			if lastErr == nil || lastErr.Error() != err.Error() {
+				ll.Warnf("Component isn't ready, %v", err)
+			}
+			lastErr = err
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%s isn't ready in %s: %v", c.name, time.Since(start).Round(time.Second), lastErr)
+			case <-time.After(g.interval):
+			}
+		}
+		ll.Infof("%s is ready", c.name)
+	}
+	return nil
+}
+
+// KubeAPIProbe returns probe which passes once k8s API server responds
+func KubeAPIProbe(client discovery.DiscoveryInterface) Probe {
+	return func(context.Context) error {
+		_, err := client.ServerVersion()
+		return err
+	}
+}
+
+// CRDProbe returns probe which passes once k8s API serves all provided kinds of group version,
+// CRD is served only after it is established
+func CRDProbe(client discovery.DiscoveryInterface, groupVersion string, kinds ...string) Probe {
+	return func(context.Context) error {
+		resources, err := client.ServerResourcesForGroupVersion(groupVersion)
+		if err != nil {
+			return fmt.Errorf("unable to discover resources of %s: %w", groupVersion, err)
+		}
+		served := make(map[string]bool, len(resources.APIResources))
+		for _, r := range resources.APIResources {
+			served[r.Kind] = true
+		}
+		for _, kind := range kinds {
+			if !served[kind] {
+				return fmt.Errorf("CRD of %s %s isn't established", groupVersion, kind)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package startup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+func TestGate_Wait(t *testing.T) {
+	var (
+		order    []string
+		attempts int
+	)
+	g := NewGate(time.Second, logrus.New())
+	g.interval = time.Millisecond
+	g.Add("k8s API", func(context.Context) error {
+		order = append(order, "k8s API")
+		return nil
+	}).Add("drive manager", func(context.Context) error {
+		order = append(order, "drive manager")
+		if attempts++; attempts < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	assert.Nil(t, g.Wait(context.Background()))
+	assert.Equal(t, []string{"k8s API", "drive manager", "drive manager", "drive manager"}, order)
+
+	// dependency which isn't ready within timeout is named in error and the next ones aren't probed
+	g = NewGate(20*time.Millisecond, logrus.New())
+	g.interval = time.Millisecond
+	probed := false
+	g.Add("CRDs", func(context.Context) error { return errors.New("not found") }).
+		Add("drive manager", func(context.Context) error {
+			probed = true
+			return nil
+		})
+	err := g.Wait(context.Background())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "CRDs isn't ready")
+	assert.Contains(t, err.Error(), "not found")
+	assert.False(t, probed)
+}
+
+func TestProbes(t *testing.T) {
+	client := &fake.FakeDiscovery{Fake: &k8stesting.Fake{}}
+	assert.Nil(t, KubeAPIProbe(client)(context.Background()))
+
+	probe := CRDProbe(client, apiV1.APIV1Version, apiV1.VolumeKind, apiV1.DriveKind)
+	assert.NotNil(t, probe(context.Background()))
+
+	client.Resources = []*metav1.APIResourceList{{
+		GroupVersion: apiV1.APIV1Version,
+		APIResources: []metav1.APIResource{{Kind: apiV1.VolumeKind}},
+	}}
+	assert.NotNil(t, probe(context.Background()))
+
+	client.Resources[0].APIResources = append(client.Resources[0].APIResources, metav1.APIResource{Kind: apiV1.DriveKind})
+	assert.Nil(t, probe(context.Background()))
+}
@@ -64,6 +64,8 @@ func ConvertStorageClass(strSC string) string {
 	case api.StorageClassHDD,
 		api.StorageClassSSD,
 		api.StorageClassNVMe,
+		api.StorageClassPMEM,
+		api.StorageClassZoned,
 		api.StorageClassHDDLVG,
 		api.StorageClassSSDLVG,
 		api.StorageClassNVMeLVG,
@@ -86,6 +88,10 @@ func ConvertDriveTypeToStorageClass(driveType string) string {
 		return api.StorageClassSSD
 	case api.DriveTypeNVMe:
 		return api.StorageClassNVMe
+	case api.DriveTypePMEM:
+		return api.StorageClassPMEM
+	case api.DriveTypeZoned:
+		return api.StorageClassZoned
 	default:
 		return api.StorageClassAny
 	}
@@ -114,6 +120,13 @@ func IsStorageClassLVG(sc string) bool {
 		sc == api.StorageClassSystemLVG
 }
 
+// IsStorageClassOptIn returns whether provided sc relates to drives which are used only if they are requested
+// explicitly, such drives are never picked for storage class ANY
+func IsStorageClassOptIn(sc string) bool {
+	return sc == api.StorageClassPMEM ||
+		sc == api.StorageClassZoned
+}
+
 // ContainsString return true if slice contains string str
 // Receives slice of strings and string to find
 // Returns true if contains or false if not
@@ -105,6 +105,8 @@ var strToSC = []struct {
 	{"hdd", api.StorageClassHDD},
 	{"ssd", api.StorageClassSSD},
 	{"nvme", api.StorageClassNVMe},
+	{"pmem", api.StorageClassPMEM},
+	{"zoned", api.StorageClassZoned},
 	{"hddlvg", api.StorageClassHDDLVG},
 	{"ssdlvg", api.StorageClassSSDLVG},
 	{"nvmelvg", api.StorageClassNVMeLVG},
@@ -130,6 +132,8 @@ var driveTypeToSC = []struct {
 	{api.DriveTypeHDD, api.StorageClassHDD},
 	{api.DriveTypeSSD, api.StorageClassSSD},
 	{api.DriveTypeNVMe, api.StorageClassNVMe},
+	{api.DriveTypePMEM, api.StorageClassPMEM},
+	{api.DriveTypeZoned, api.StorageClassZoned},
 	{"random", api.StorageClassAny}, // random drive type
 }
 
@@ -18,13 +18,9 @@ package util
 
 import (
 	"fmt"
-	"regexp"
-	"strconv"
-	"strings"
+	"math"
 )
 
-var sizeStrFmt = regexp.MustCompile(`(\d+(\.\d+)?)\s*(\S+)`)
-
 // SizeUnit is the type for unit of information
 type SizeUnit int64
 
@@ -41,39 +37,32 @@ const (
 	BYTE SizeUnit = 1
 )
 
-// StrToBytes parses provided string and returns its value in bytes. Example: "15 Kb" -> 15360, "1GB" -> 1073741824
-// Receives string value of information size with literal
-// Returns provided size in bytes or error if something went wrong
-func StrToBytes(str string) (int64, error) {
-	var matches = sizeStrFmt.FindAllStringSubmatch(str, -1)
-	if matches == nil {
-		return 0, fmt.Errorf("unparseable size definition: %v", str)
-	}
-	value, _ := strconv.ParseFloat(matches[0][1], 64) // We don't expect error here, because number is validated by regex
-	var mod int64
-	switch strings.ToLower(matches[0][3]) {
-	case "t", "tb", "ti", "tib", "e12":
-		mod = int64(TBYTE)
-	case "g", "gb", "gi", "gib", "e9":
-		mod = int64(GBYTE)
-	case "m", "mb", "mi", "mib", "e6":
-		mod = int64(MBYTE)
-	case "k", "kb", "ki", "kib", "e3":
-		mod = int64(KBYTE)
-	case "b":
-		mod = int64(BYTE)
-	default:
-		return 0, fmt.Errorf("unknown size unit %v in supplied value %v", matches[0][2], str)
-	}
-	return int64(float64(mod) * value), nil
-}
-
 // ToSizeUnit converts value from specified size unit to another unit
 // Receives size as value, 'from' as provided size unit and 'to' as size unit to convert
-// Returns error if conversion leads to precision loss.
+// Returns error if conversion leads to precision loss or if result doesn't fit into int64.
 func ToSizeUnit(value int64, from SizeUnit, to SizeUnit) (int64, error) {
 	var fromMod = int64(from)
 	var toMod = int64(to)
+	if toMod%fromMod == 0 {
+		// conversion to larger unit, value in bytes could overflow int64 so it isn't calculated
+		var ratio = toMod / fromMod
+		var res = value / ratio
+		if value%ratio != 0 {
+			// The error can be ignored, if precision loss is OK for you
+			return res, fmt.Errorf("precision loss prohibited in conversion from value %d with unit size %d to unit with size %d", value, fromMod, toMod)
+		}
+		return res, nil
+	}
+	if fromMod%toMod == 0 {
+		var ratio = fromMod / toMod
+		if value > math.MaxInt64/ratio || value < math.MinInt64/ratio {
+			return 0, fmt.Errorf("overflow in conversion from value %d with unit size %d to unit with size %d", value, fromMod, toMod)
+		}
+		return value * ratio, nil
+	}
+	if value > math.MaxInt64/fromMod || value < math.MinInt64/fromMod {
+		return 0, fmt.Errorf("overflow in conversion from value %d with unit size %d to unit with size %d", value, fromMod, toMod)
+	}
 	var byteValue = fromMod * value
 	var res = byteValue / toMod
 	if byteValue%toMod != 0 {
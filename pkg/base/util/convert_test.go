@@ -21,56 +21,6 @@ import (
 	"testing"
 )
 
-// Test byte value parsing from unparseable string. Error expected.
-func TestStrToBytesIncorrect(t *testing.T) {
-	got, err := StrToBytes("foo")
-	if err == nil {
-		t.Errorf("No error got when trying to parse unparseable value. Returned value: %d", got)
-	}
-	if !strings.Contains(err.Error(), "unparseable") {
-		t.Errorf("Unexpected error text. Received error: %s", err.Error())
-	}
-}
-
-// Test byte value parsing from string with unknown size unit. Error expected.
-func TestStrToBytesWrongUnit(t *testing.T) {
-	var unit = "Cm"
-	got, err := StrToBytes("15" + unit)
-	if err == nil {
-		t.Errorf("No error got when trying to parse value with incorrect unit \"%s\". Returned value: %d", unit, got)
-	}
-	if !strings.Contains(err.Error(), "unknown") {
-		t.Errorf("Unexpected error text. Received error: %s", err.Error())
-	}
-}
-
-var sizeTests = []struct {
-	str   string
-	bytes int64
-}{
-	{"15 b", 15},
-	{"601B", 601},
-	{"48e3", 48 * 1024},
-	{"102 KB", 102 * 1024},
-	{"9851 Mi", 9851 * 1024 * 1024},
-	{"3gb", 3 * 1024 * 1024 * 1024},
-	{"7t", 7 * 1024 * 1024 * 1024 * 1024},
-	{"This disk has 5 gb of free space", 5 * 1024 * 1024 * 1024},
-}
-
-// Test byte value parsing from strings containing correct values
-func TestStrToBytesCorrectBytes(t *testing.T) {
-	for _, test := range sizeTests {
-		got, err := StrToBytes(test.str)
-		if err != nil {
-			t.Errorf("Unexpected error got when trying to parse value \"%s\". Received error: %s", test.str, err.Error())
-		}
-		if got != test.bytes {
-			t.Errorf("Unexpected conversion result: %d (bytes) when parsing value \"%s\". Expected: %d", got, test.str, test.bytes)
-		}
-	}
-}
-
 // Test value unit conversion for precision loss case. Error expected.
 func TestToSizeUnitPrecisionLoss(t *testing.T) {
 	got, err := ToSizeUnit(4095, KBYTE, MBYTE) // 4095KB doesn't represent integer value of megabytes
@@ -103,6 +53,19 @@ var unitConversionTests = []struct {
 	{9 * 1024 * 1024, MBYTE, TBYTE, 9},
 }
 
+// Test value unit conversion for overflow case. Error expected.
+func TestToSizeUnitOverflow(t *testing.T) {
+	_, err := ToSizeUnit(8*1024*1024, TBYTE, BYTE) // 8EB doesn't fit into int64
+	if err == nil || !strings.Contains(err.Error(), "overflow") {
+		t.Errorf("Unexpected error for overflow conversion: %v", err)
+	}
+	// value in bytes doesn't fit into int64, but result does
+	got, err := ToSizeUnit(9*1024*1024*1024*1024*1024, MBYTE, TBYTE)
+	if err != nil || got != 9*1024*1024*1024 {
+		t.Errorf("Unexpected conversion result: %d, error: %v", got, err)
+	}
+}
+
 // Test size value conversion for correct params
 func TestToSizeUnitCorrect(t *testing.T) {
 	for _, test := range unitConversionTests {
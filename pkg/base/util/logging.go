@@ -18,7 +18,9 @@ package util
 
 import (
 	"context"
+	"reflect"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/sirupsen/logrus"
 
 	"github.com/dell/csi-baremetal/pkg/base"
@@ -30,3 +32,26 @@ func AddCommonFields(ctx context.Context, logger *logrus.Entry, method string) *
 		"volumeID": ctx.Value(base.RequestUUID),
 		"method":   method})
 }
+
+// strippedSecret replaces values of secrets in logged requests
+const strippedSecret = "***stripped***"
+
+// StripSecrets returns copy of CSI request with values of Secrets field replaced, it is used to log requests
+// without credentials which are passed from StorageClass, request without secrets is returned as is
+func StripSecrets(req proto.Message) proto.Message {
+	v := reflect.ValueOf(req)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return req
+	}
+	field := v.Elem().FieldByName("Secrets")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(map[string]string{}) || field.Len() == 0 {
+		return req
+	}
+	secrets := make(map[string]string, field.Len())
+	for _, key := range field.MapKeys() {
+		secrets[key.String()] = strippedSecret
+	}
+	stripped := proto.Clone(req)
+	reflect.ValueOf(stripped).Elem().FieldByName("Secrets").Set(reflect.ValueOf(secrets))
+	return stripped
+}
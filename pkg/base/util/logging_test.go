@@ -0,0 +1,42 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripSecrets(t *testing.T) {
+	req := &csi.CreateVolumeRequest{
+		Name:       "pvc-1",
+		Parameters: map[string]string{"storageType": "HDD"},
+		Secrets:    map[string]string{"imagePassword": "secret"},
+	}
+	logged := fmt.Sprintf("%v", StripSecrets(req))
+	assert.NotContains(t, logged, `"secret"`)
+	assert.Contains(t, logged, "imagePassword")
+	assert.Contains(t, logged, "pvc-1")
+	// original request isn't changed
+	assert.Equal(t, "secret", req.Secrets["imagePassword"])
+
+	noSecrets := &csi.NodeUnstageVolumeRequest{VolumeId: "pvc-1"}
+	assert.Equal(t, noSecrets, StripSecrets(noSecrets))
+}
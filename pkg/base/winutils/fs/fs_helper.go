@@ -0,0 +1,283 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fs contains Windows implementation of fs.WrapFS interface based on PowerShell storage cmdlets
+// Disks are addressed by disk number (Get-Disk), each disk holds single NTFS partition.
+// Staging and publishing use directory symlinks (mklink) instead of mount --bind.
+// CSI Proxy isn't used, cmdlets are run by the node service itself, so node container must run as HostProcess
+// container. Only file system operations are implemented, drive discovery, partitioning and LVM are Linux only.
+package fs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+)
+
+const (
+	// NTFS file system
+	NTFS fs.FileSystem = "ntfs"
+
+	// PowerShellCmd is a name of PowerShell executable, cmdlets are passed to it as a single script argument,
+	// so paths with spaces aren't split. Paths and disk numbers are inserted into scripts as quoted literals
+	PowerShellCmd = "powershell"
+	// CheckSpaceCmdTmpl cmd for getting free bytes on the volume which holds path
+	CheckSpaceCmdTmpl = "(Get-Volume -FilePath %s).SizeRemaining"
+	// MkDirCmdTmpl cmd for creating directory, -Path of New-Item isn't expanded as wildcard
+	MkDirCmdTmpl = "New-Item -ItemType Directory -Force -Path %s"
+	// MkFileCmdTmpl cmd for creating file, -Path of New-Item isn't expanded as wildcard
+	MkFileCmdTmpl = "New-Item -ItemType File -Force -Path %s"
+	// RmDirCmdTmpl cmd for removing path
+	RmDirCmdTmpl = "Remove-Item -Recurse -Force -LiteralPath %s"
+	// CreateFSCmdTmpl cmd for partitioning disk and formatting it, add disk number and FS type
+	CreateFSCmdTmpl = "Initialize-Disk -Number %s -PartitionStyle GPT -PassThru | " +
+		"New-Partition -UseMaximumSize | Format-Volume -FileSystem %s -Confirm:$false"
+	// WipeFSCmdTmpl cmd for removing all partitions and data from disk
+	WipeFSCmdTmpl = "Clear-Disk -Number %s -RemoveData -RemoveOEM -Confirm:$false"
+	// GetFSTypeCmdTmpl cmd for retrieving FS type of volume on disk
+	GetFSTypeCmdTmpl = "(Get-Disk -Number %s | Get-Partition | Get-Volume).FileSystemType"
+	// GetVolumePathCmdTmpl cmd for retrieving volume path (\\?\Volume{GUID}\) of disk
+	GetVolumePathCmdTmpl = "(Get-Disk -Number %s | Get-Partition | Get-Volume).Path"
+	// GetLinkTargetCmdTmpl cmd for retrieving target of the symlink, output is empty if path isn't a link
+	GetLinkTargetCmdTmpl = "(Get-Item -LiteralPath %s).Target"
+	// MountCmdTmpl cmd for creating directory symlink, add link and target
+	MountCmdTmpl = "cmd /c mklink /D %s %s"
+	// UnmountCmdTmpl cmd for removing directory symlink without touching target
+	UnmountCmdTmpl = "cmd /c rmdir %s"
+	// CheckFSCmdTmpl cmd for checking volume on disk, add disk number, mode (-Scan or -OfflineScanAndFix) and options
+	CheckFSCmdTmpl = "Get-Disk -Number %s | Get-Partition | Get-Volume | Repair-Volume %s %s"
+	// checkFSNoErrors is reported by Repair-Volume if volume is healthy
+	checkFSNoErrors = "NoErrorsFound"
+)
+
+// WrapFSImpl is a Windows implementer of fs.WrapFS interface
+type WrapFSImpl struct {
+	e       command.CmdExecutor
+	opMutex sync.Mutex
+}
+
+// NewFSImpl is a constructor for WrapFSImpl struct
+func NewFSImpl(e command.CmdExecutor) *WrapFSImpl {
+	return &WrapFSImpl{e: e}
+}
+
+// GetFSSpace returns available space on the volume which holds src
+func (h *WrapFSImpl) GetFSSpace(src string) (int64, error) {
+	stdout, err := h.runPowerShell(CheckSpaceCmdTmpl, quote(src))
+	if err != nil {
+		return 0, err
+	}
+	freeBytes, err := strconv.ParseInt(strings.TrimSpace(stdout), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("wrong Get-Volume output %s", stdout)
+	}
+	return freeBytes, nil
+}
+
+// MkDir creates specified path if it doesn't exist
+func (h *WrapFSImpl) MkDir(src string) error {
+	if _, err := h.runPowerShell(MkDirCmdTmpl, quote(src)); err != nil {
+		return fmt.Errorf("failed to create dir %s: %w", src, err)
+	}
+	return nil
+}
+
+// MkFile creates file with specified path
+func (h *WrapFSImpl) MkFile(src string) error {
+	if _, err := h.runPowerShell(MkFileCmdTmpl, quote(src)); err != nil {
+		return fmt.Errorf("failed to create file %s: %w", src, err)
+	}
+	return nil
+}
+
+// RmDir removes specified path
+func (h *WrapFSImpl) RmDir(src string) error {
+	if _, err := h.runPowerShell(RmDirCmdTmpl, quote(src)); err != nil {
+		return fmt.Errorf("failed to delete path %s: %w", src, err)
+	}
+	return nil
+}
+
+// CreateFS creates GPT partition table with single partition on the disk and formats it
+// Receives file system and disk number as a string, mkfs options aren't supported by Format-Volume
+func (h *WrapFSImpl) CreateFS(fsType fs.FileSystem, device string, opts ...string) error {
+	if fsType != NTFS {
+		return fmt.Errorf("unsupported file system %v", fsType)
+	}
+	if len(opts) > 0 {
+		return fmt.Errorf("file system options %v aren't supported", opts)
+	}
+	if _, err := h.runPowerShell(CreateFSCmdTmpl, quote(device), strings.ToUpper(string(fsType))); err != nil {
+		return fmt.Errorf("failed to create file system on disk %s: %w", device, err)
+	}
+	return nil
+}
+
+// WipeFS removes all partitions and data from the disk
+func (h *WrapFSImpl) WipeFS(device string) error {
+	if _, err := h.runPowerShell(WipeFSCmdTmpl, quote(device)); err != nil {
+		return fmt.Errorf("failed to wipe file system on disk %s: %w", device, err)
+	}
+	return nil
+}
+
+// GetFSType returns FS type of volume on the disk in lower case, e.g. ntfs
+func (h *WrapFSImpl) GetFSType(device string) (fs.FileSystem, error) {
+	stdout, err := h.runPowerShell(GetFSTypeCmdTmpl, quote(device))
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve FS type for disk %s: %w", device, err)
+	}
+	return fs.FileSystem(strings.ToLower(strings.TrimSpace(stdout))), nil
+}
+
+// GetVolumePath returns volume path of the disk which could be used as a source for Mount
+func (h *WrapFSImpl) GetVolumePath(device string) (string, error) {
+	stdout, err := h.runPowerShell(GetVolumePathCmdTmpl, quote(device))
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve volume path for disk %s: %w", device, err)
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// IsMounted checks whether path is a symlink created by Mount
+func (h *WrapFSImpl) IsMounted(path string) (bool, error) {
+	target, err := h.FindMountPoint(path)
+	if err != nil {
+		return false, fmt.Errorf("unable to check whether %s mounted or no, error: %w", path, err)
+	}
+	return target != "", nil
+}
+
+// FindMountPoint returns target of the symlink or empty string if path isn't a link
+func (h *WrapFSImpl) FindMountPoint(target string) (string, error) {
+	h.opMutex.Lock()
+	defer h.opMutex.Unlock()
+
+	stdout, err := h.runPowerShell(GetLinkTargetCmdTmpl, quote(target))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// Mount links dir to the source (volume path or directory), opts aren't supported and ignored
+// mklink requires that link doesn't exist, so dir is removed before linking if it is an empty directory
+// (created by kubelet) or a link left by previous Mount. Directories with files are never removed
+func (h *WrapFSImpl) Mount(src, dir string, opts ...string) error {
+	h.opMutex.Lock()
+	defer h.opMutex.Unlock()
+
+	info, err := os.Lstat(dir)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return err
+	case info.Mode()&os.ModeSymlink != 0:
+		// rmdir removes link without touching its target
+		if _, err = h.run(UnmountCmdTmpl, dir); err != nil {
+			return err
+		}
+	case info.IsDir():
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		if len(files) > 0 {
+			return fmt.Errorf("unable to link %s to %s: directory isn't empty", dir, src)
+		}
+		if _, err = h.run(UnmountCmdTmpl, dir); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unable to link %s to %s: path isn't a directory", dir, src)
+	}
+	_, err = h.run(MountCmdTmpl, dir, src)
+	return err
+}
+
+// Unmount removes symlink created by Mount
+func (h *WrapFSImpl) Unmount(path string) error {
+	h.opMutex.Lock()
+	defer h.opMutex.Unlock()
+
+	_, err := h.run(UnmountCmdTmpl, path)
+	return err
+}
+
+// CheckFS scans volume on the disk with Repair-Volume and fixes it offline if repair is true
+// Receives file system, disk number, repair flag and additional options of Repair-Volume
+// Returns result of the check or error if check wasn't completed
+func (h *WrapFSImpl) CheckFS(fsType fs.FileSystem, device string, repair bool, opts string) (fs.FSCheckResult, error) {
+	if fsType != NTFS {
+		return "", fmt.Errorf("unsupported file system %v", fsType)
+	}
+	mode := "-Scan"
+	if repair {
+		mode = "-OfflineScanAndFix"
+	}
+	stdout, err := h.runPowerShell(CheckFSCmdTmpl, quote(device), mode, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to check volume on disk %s: %w", device, err)
+	}
+	switch {
+	case strings.TrimSpace(stdout) == checkFSNoErrors:
+		return fs.FSCheckClean, nil
+	case repair:
+		return fs.FSCheckRepaired, nil
+	}
+	return fs.FSCheckErrorsLeft, nil
+}
+
+// run formats cmd template with args and runs it with metrics
+func (h *WrapFSImpl) run(tmpl string, args ...interface{}) (string, error) {
+	stdout, _, err := h.e.RunCmd(fmt.Sprintf(tmpl, args...), command.UseMetrics(true), command.CmdName(cmdName(tmpl)))
+	return stdout, err
+}
+
+// runPowerShell formats script template with args and runs it by PowerShell with metrics,
+// script is passed as a single argument, so PowerShell doesn't join it from parts split by spaces
+func (h *WrapFSImpl) runPowerShell(tmpl string, args ...interface{}) (string, error) {
+	stdout, _, err := h.e.RunCmd(exec.Command(PowerShellCmd, powerShellArgs(fmt.Sprintf(tmpl, args...))...),
+		command.UseMetrics(true), command.CmdName(cmdName(tmpl)))
+	return stdout, err
+}
+
+// powerShellArgs returns arguments of PowerShell which run provided script non-interactively
+func powerShellArgs(script string) []string {
+	return []string{"-NoProfile", "-NonInteractive", "-Command", script}
+}
+
+// quote returns PowerShell single-quoted string literal, content of such literal isn't expanded,
+// single quotes are escaped by doubling
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// cmdName returns template without arguments which is used as a name of the command in metrics
+func cmdName(tmpl string) string {
+	empty := make([]interface{}, strings.Count(tmpl, "%s"))
+	for i := range empty {
+		empty[i] = ""
+	}
+	return strings.TrimSpace(fmt.Sprintf(tmpl, empty...))
+}
@@ -0,0 +1,184 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+var testError = errors.New("error")
+
+func TestWrapFSImpl_GetFSSpace(t *testing.T) {
+	var (
+		e   = &mocks.GoMockExecutor{}
+		fh  = NewFSImpl(e)
+		cmd = psCmd(CheckSpaceCmdTmpl, `'C:\var\lib'`)
+	)
+
+	e.OnCommandArgs(cmd...).Return("1073741824\r\n", "", nil).Once()
+	size, err := fh.GetFSSpace(`C:\var\lib`)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1073741824), size)
+
+	e.OnCommandArgs(cmd...).Return("wrong", "", nil).Once()
+	_, err = fh.GetFSSpace(`C:\var\lib`)
+	assert.NotNil(t, err)
+
+	e.OnCommandArgs(cmd...).Return("", "", testError).Once()
+	_, err = fh.GetFSSpace(`C:\var\lib`)
+	assert.Equal(t, testError, err)
+}
+
+func TestWrapFSImpl_CreateFS(t *testing.T) {
+	var (
+		e  = &mocks.GoMockExecutor{}
+		fh = NewFSImpl(e)
+	)
+
+	assert.NotNil(t, fh.CreateFS(fs.XFS, "1"))
+
+	e.OnCommandArgs(psCmd(CreateFSCmdTmpl, "'1'", "NTFS")...).Return("", "", nil).Once()
+	assert.Nil(t, fh.CreateFS(NTFS, "1"))
+
+	e.OnCommandArgs(psCmd(CreateFSCmdTmpl, "'2'", "NTFS")...).Return("", "", testError).Once()
+	assert.NotNil(t, fh.CreateFS(NTFS, "2"))
+}
+
+func TestWrapFSImpl_GetFSType(t *testing.T) {
+	var (
+		e  = &mocks.GoMockExecutor{}
+		fh = NewFSImpl(e)
+	)
+
+	e.OnCommandArgs(psCmd(GetFSTypeCmdTmpl, "'1'")...).Return("NTFS\r\n", "", nil).Once()
+	fsType, err := fh.GetFSType("1")
+	assert.Nil(t, err)
+	assert.Equal(t, NTFS, fsType)
+
+	e.OnCommandArgs(psCmd(GetFSTypeCmdTmpl, "'2'")...).Return("", "", testError).Once()
+	_, err = fh.GetFSType("2")
+	assert.NotNil(t, err)
+}
+
+func TestWrapFSImpl_MountUnmount(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubelet")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	var (
+		e      = &mocks.GoMockExecutor{}
+		fh     = NewFSImpl(e)
+		src    = `\\?\Volume{0b6f0ee5-c1b2-4c3a-8f0c-5a3c1b2a4d11}\`
+		target = filepath.Join(dir, "staging")
+	)
+
+	// target doesn't exist
+	e.OnCommand(fmt.Sprintf(MountCmdTmpl, target, src)).Return("", "", nil).Once()
+	assert.Nil(t, fh.Mount(src, target))
+
+	// empty directory created by kubelet and link left by previous mount are removed before linking
+	assert.Nil(t, os.Mkdir(target, 0700))
+	e.OnCommand(fmt.Sprintf(UnmountCmdTmpl, target)).Return("", "", nil).Once()
+	e.OnCommand(fmt.Sprintf(MountCmdTmpl, target, src)).Return("", "", nil).Once()
+	assert.Nil(t, fh.Mount(src, target))
+	assert.Nil(t, os.Remove(target))
+	assert.Nil(t, os.Symlink(dir, target))
+	e.OnCommand(fmt.Sprintf(UnmountCmdTmpl, target)).Return("", "", nil).Once()
+	e.OnCommand(fmt.Sprintf(MountCmdTmpl, target, src)).Return("", "", nil).Once()
+	assert.Nil(t, fh.Mount(src, target))
+	assert.Nil(t, os.Remove(target))
+
+	// directory with files and file are never removed
+	assert.Nil(t, os.MkdirAll(filepath.Join(target, "data"), 0700))
+	assert.NotNil(t, fh.Mount(src, target))
+	assert.Nil(t, os.RemoveAll(target))
+	assert.Nil(t, ioutil.WriteFile(target, []byte("data"), 0600))
+	assert.NotNil(t, fh.Mount(src, target))
+	e.AssertExpectations(t)
+
+	e.OnCommandArgs(psCmd(GetLinkTargetCmdTmpl, quote(target))...).Return(src+"\r\n", "", nil).Once()
+	mounted, err := fh.IsMounted(target)
+	assert.Nil(t, err)
+	assert.True(t, mounted)
+
+	e.OnCommand(fmt.Sprintf(UnmountCmdTmpl, target)).Return("", "", nil).Once()
+	assert.Nil(t, fh.Unmount(target))
+
+	e.OnCommandArgs(psCmd(GetLinkTargetCmdTmpl, quote(target))...).Return("", "", nil).Once()
+	mounted, err = fh.IsMounted(target)
+	assert.Nil(t, err)
+	assert.False(t, mounted)
+
+	e.OnCommandArgs(psCmd(GetLinkTargetCmdTmpl, quote(target))...).Return("", "", testError).Once()
+	_, err = fh.IsMounted(target)
+	assert.NotNil(t, err)
+}
+
+func TestWrapFSImpl_CheckFS(t *testing.T) {
+	var (
+		e  = &mocks.GoMockExecutor{}
+		fh = NewFSImpl(e)
+	)
+
+	_, err := fh.CheckFS(fs.XFS, "1", false, "")
+	assert.NotNil(t, err)
+
+	e.OnCommandArgs(psCmd(CheckFSCmdTmpl, "'1'", "-Scan", "")...).Return("NoErrorsFound\r\n", "", nil).Once()
+	res, err := fh.CheckFS(NTFS, "1", false, "")
+	assert.Nil(t, err)
+	assert.Equal(t, fs.FSCheckClean, res)
+
+	e.OnCommandArgs(psCmd(CheckFSCmdTmpl, "'1'", "-Scan", "")...).Return("SpotFixNeeded\r\n", "", nil).Once()
+	res, err = fh.CheckFS(NTFS, "1", false, "")
+	assert.Nil(t, err)
+	assert.Equal(t, fs.FSCheckErrorsLeft, res)
+
+	e.OnCommandArgs(psCmd(CheckFSCmdTmpl, "'1'", "-OfflineScanAndFix", "")...).Return("", "", testError).Once()
+	_, err = fh.CheckFS(NTFS, "1", true, "")
+	assert.NotNil(t, err)
+}
+
+// psCmd returns arguments of PowerShell command which runs script formatted from template
+func psCmd(tmpl string, args ...interface{}) []string {
+	return append([]string{PowerShellCmd}, powerShellArgs(fmt.Sprintf(tmpl, args...))...)
+}
+
+func TestWrapFSImpl_PathsWithQuotes(t *testing.T) {
+	var (
+		e    = &mocks.GoMockExecutor{}
+		fh   = NewFSImpl(e)
+		path = `C:\var\lib\kubelet\pods\pod's volume; Remove-Item C:\ -Recurse`
+	)
+
+	e.OnCommandArgs(psCmd(RmDirCmdTmpl, `'C:\var\lib\kubelet\pods\pod''s volume; Remove-Item C:\ -Recurse'`)...).
+		Return("", "", nil).Once()
+	assert.Nil(t, fh.RmDir(path))
+
+	e.OnCommandArgs(psCmd(MkDirCmdTmpl, `'C:\var\lib\kubelet\pods\pod''s volume; Remove-Item C:\ -Recurse'`)...).
+		Return("", "", nil).Once()
+	assert.Nil(t, fh.MkDir(path))
+	e.AssertExpectations(t)
+}
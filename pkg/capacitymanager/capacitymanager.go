@@ -0,0 +1,114 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package capacitymanager owns lifecycle of AvailableCapacity (AC) and AvailableCapacityReservation (ACR) CRs:
+// capacity is reserved by scheduler extender, allocated by controller when volume is created or expanded
+// and released when volume is removed or its expansion fails
+package capacitymanager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
+
+// Manager is the interface for AC and ACR lifecycle operations
+type Manager interface {
+	// Reserve creates ACRs for the volumes of the placing plan
+	Reserve(ctx context.Context, plan *capacityplanner.VolumesPlacingPlan) error
+	// ExtendReservations adds AC newACName to ACRs which hold AC ac, it is used before AC is converted to LVG AC
+	ExtendReservations(ctx context.Context, ac *accrd.AvailableCapacity, newACName string) error
+	// ReleaseReservation removes ACR of the volume which is created on AC ac (or acReplacement if AC was converted)
+	ReleaseReservation(ctx context.Context, volume *api.Volume, ac, acReplacement *accrd.AvailableCapacity) error
+	// Allocate decreases size of AC by size bytes
+	Allocate(ctx context.Context, ac *accrd.AvailableCapacity, size int64) error
+	// Release increases size of AC by size bytes
+	Release(ctx context.Context, ac *accrd.AvailableCapacity, size int64) error
+}
+
+// ManagerImpl is the basic implementation of Manager interface based on AC and ACR CRs
+type ManagerImpl struct {
+	k8sClient *k8s.KubeClient
+	log       *logrus.Entry
+}
+
+// NewManagerImpl is the constructor for ManagerImpl struct
+// Receives an instance of k8s.KubeClient and logrus logger
+// Returns an instance of ManagerImpl
+func NewManagerImpl(k8sClient *k8s.KubeClient, logger *logrus.Logger) *ManagerImpl {
+	return &ManagerImpl{
+		k8sClient: k8sClient,
+		log:       logger.WithField("component", "CapacityManager"),
+	}
+}
+
+// Reserve creates ACR for each volume of the placing plan, ACR holds ACs selected for the volume on all nodes.
+// Created ACRs are removed if some of them can't be created
+func (m *ManagerImpl) Reserve(ctx context.Context, plan *capacityplanner.VolumesPlacingPlan) error {
+	if plan == nil {
+		return nil
+	}
+	return m.reservationHelper().CreateReservation(ctx, plan)
+}
+
+// ExtendReservations adds AC newACName to all ACRs which hold AC ac, so capacity stays reserved
+// when AC is converted to LVG AC and scheduler extender reads the new AC
+func (m *ManagerImpl) ExtendReservations(ctx context.Context, ac *accrd.AvailableCapacity, newACName string) error {
+	return m.reservationHelper().ExtendReservations(ctx, ac, newACName)
+}
+
+// ReleaseReservation removes ACR which holds AC ac for the volume, if AC was converted to acReplacement
+// ac is removed from the rest of ACRs
+func (m *ManagerImpl) ReleaseReservation(ctx context.Context, volume *api.Volume,
+	ac, acReplacement *accrd.AvailableCapacity) error {
+	return m.reservationHelper().ReleaseReservation(ctx, volume, ac, acReplacement)
+}
+
+// Allocate decreases size of AC by size bytes, AC is re-read and decreased again if it was modified concurrently
+func (m *ManagerImpl) Allocate(ctx context.Context, ac *accrd.AvailableCapacity, size int64) error {
+	return m.resize(ctx, "Allocate", ac, -size)
+}
+
+// Release increases size of AC by size bytes, AC is re-read and increased again if it was modified concurrently
+func (m *ManagerImpl) Release(ctx context.Context, ac *accrd.AvailableCapacity, size int64) error {
+	return m.resize(ctx, "Release", ac, size)
+}
+
+func (m *ManagerImpl) resize(ctx context.Context, method string, ac *accrd.AvailableCapacity, delta int64) error {
+	ll := util.AddCommonFields(ctx, m.log, method)
+	if err := m.k8sClient.UpdateCRWithConflictRetry(ctx, ac, func() error {
+		ac.Spec.Size += delta
+		return nil
+	}); err != nil {
+		return fmt.Errorf("unable to change size of AC %s by %d: %v", ac.Name, delta, err)
+	}
+	ll.Debugf("Size of AC %s is changed by %d to %d", ac.Name, delta, ac.Spec.Size)
+	return nil
+}
+
+// reservationHelper returns ReservationHelper which reads the latest ACs and ACRs on first use
+func (m *ManagerImpl) reservationHelper() *capacityplanner.ReservationHelper {
+	return capacityplanner.NewReservationHelper(m.log, m.k8sClient,
+		capacityplanner.NewACReader(m.k8sClient, m.log, true),
+		capacityplanner.NewACRReader(m.k8sClient, m.log, true))
+}
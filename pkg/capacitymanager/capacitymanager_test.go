@@ -0,0 +1,195 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacitymanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+const (
+	testNS    = "default"
+	testNode1 = "node-1"
+	testNode2 = "node-2"
+	testSize  = int64(1024 * 1024 * 1024)
+)
+
+var (
+	testLogger = logrus.New()
+	testCtx    = context.Background()
+)
+
+func TestManagerImpl_Reserve(t *testing.T) {
+	t.Run("Nil plan", func(t *testing.T) {
+		m := setupManager(t)
+		assert.Nil(t, m.Reserve(testCtx, nil))
+		assert.Empty(t, readACRs(t, m))
+	})
+	t.Run("ACR holds ACs on all nodes", func(t *testing.T) {
+		m := setupManager(t)
+		vol := &api.Volume{Id: uuid.New().String(), Size: testSize, StorageClass: apiV1.StorageClassHDD}
+		ac1 := createAC(t, m, testNode1, testSize, apiV1.StorageClassHDD)
+		ac2 := createAC(t, m, testNode2, testSize, apiV1.StorageClassHDD)
+		plan := capacityplanner.NewVolumesPlacingPlan(
+			capacityplanner.VolumesPlanMap{
+				testNode1: capacityplanner.VolToACMap{vol: ac1},
+				testNode2: capacityplanner.VolToACMap{vol: ac2},
+			},
+			capacityplanner.NodeCapacityMap{
+				testNode1: capacityplanner.ACMap{ac1.Name: ac1},
+				testNode2: capacityplanner.ACMap{ac2.Name: ac2},
+			})
+
+		assert.Nil(t, m.Reserve(testCtx, plan))
+		acrs := readACRs(t, m)
+		assert.Len(t, acrs, 1)
+		assert.Equal(t, testSize, acrs[0].Spec.Size)
+		assert.Equal(t, apiV1.StorageClassHDD, acrs[0].Spec.StorageClass)
+		assert.ElementsMatch(t, []string{ac1.Name, ac2.Name}, acrs[0].Spec.Reservations)
+	})
+}
+
+func TestManagerImpl_ReleaseReservation(t *testing.T) {
+	t.Run("ACR is removed", func(t *testing.T) {
+		m := setupManager(t)
+		ac := createAC(t, m, testNode1, testSize, apiV1.StorageClassHDD)
+		acr := createACR(t, m, testSize, apiV1.StorageClassHDD, ac.Name)
+		vol := &api.Volume{Size: testSize, StorageClass: apiV1.StorageClassHDD}
+
+		assert.Nil(t, m.ReleaseReservation(testCtx, vol, ac, ac))
+		err := m.k8sClient.ReadCR(testCtx, acr.Name, "", &acrcrd.AvailableCapacityReservation{})
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+	t.Run("ACR of another volume size isn't removed", func(t *testing.T) {
+		m := setupManager(t)
+		ac := createAC(t, m, testNode1, testSize*2, apiV1.StorageClassHDD)
+		createACR(t, m, testSize*2, apiV1.StorageClassHDD, ac.Name)
+		vol := &api.Volume{Size: testSize, StorageClass: apiV1.StorageClassHDD}
+
+		assert.Nil(t, m.ReleaseReservation(testCtx, vol, ac, ac))
+		assert.Len(t, readACRs(t, m), 1)
+	})
+	t.Run("Converted AC is removed from the rest of ACRs", func(t *testing.T) {
+		m := setupManager(t)
+		ac := createAC(t, m, testNode1, testSize*2, apiV1.StorageClassHDD)
+		lvgAC := createAC(t, m, testNode1, testSize*2, apiV1.StorageClassHDDLVG)
+		createACR(t, m, testSize, apiV1.StorageClassHDDLVG, ac.Name, lvgAC.Name)
+		createACR(t, m, testSize, apiV1.StorageClassHDDLVG, ac.Name, lvgAC.Name)
+		vol := &api.Volume{Size: testSize, StorageClass: apiV1.StorageClassHDDLVG}
+
+		assert.Nil(t, m.ReleaseReservation(testCtx, vol, ac, lvgAC))
+		acrs := readACRs(t, m)
+		assert.Len(t, acrs, 1)
+		assert.Equal(t, []string{lvgAC.Name}, acrs[0].Spec.Reservations)
+	})
+}
+
+func TestManagerImpl_ExtendReservations(t *testing.T) {
+	m := setupManager(t)
+	ac := createAC(t, m, testNode1, testSize, apiV1.StorageClassHDD)
+	other := createAC(t, m, testNode2, testSize, apiV1.StorageClassHDD)
+	createACR(t, m, testSize, apiV1.StorageClassHDDLVG, ac.Name)
+	createACR(t, m, testSize, apiV1.StorageClassHDDLVG, other.Name)
+	newACName := uuid.New().String()
+
+	assert.Nil(t, m.ExtendReservations(testCtx, ac, newACName))
+	// extension is idempotent
+	assert.Nil(t, m.ExtendReservations(testCtx, ac, newACName))
+	for _, acr := range readACRs(t, m) {
+		if acr.Spec.Reservations[0] == ac.Name {
+			assert.Equal(t, []string{ac.Name, newACName}, acr.Spec.Reservations)
+		} else {
+			assert.Equal(t, []string{other.Name}, acr.Spec.Reservations)
+		}
+	}
+}
+
+func TestManagerImpl_AllocateRelease(t *testing.T) {
+	t.Run("Size is changed", func(t *testing.T) {
+		m := setupManager(t)
+		ac := createAC(t, m, testNode1, testSize*3, apiV1.StorageClassHDDLVG)
+
+		assert.Nil(t, m.Allocate(testCtx, ac, testSize*2))
+		assert.Equal(t, testSize, readAC(t, m, ac.Name).Spec.Size)
+		assert.Nil(t, m.Release(testCtx, ac, testSize))
+		assert.Equal(t, testSize*2, readAC(t, m, ac.Name).Spec.Size)
+	})
+	t.Run("AC doesn't exist", func(t *testing.T) {
+		m := setupManager(t)
+		ac := m.k8sClient.ConstructACCR(uuid.New().String(), api.AvailableCapacity{Size: testSize})
+
+		assert.NotNil(t, m.Allocate(testCtx, ac, testSize))
+		assert.NotNil(t, m.Release(testCtx, ac, testSize))
+	})
+}
+
+func setupManager(t *testing.T) *ManagerImpl {
+	client, err := k8s.GetFakeKubeClient(testNS, testLogger)
+	assert.Nil(t, err)
+	return NewManagerImpl(client, testLogger)
+}
+
+func createAC(t *testing.T, m *ManagerImpl, node string, size int64, sc string) *accrd.AvailableCapacity {
+	name := uuid.New().String()
+	ac := m.k8sClient.ConstructACCR(name, api.AvailableCapacity{
+		Location:     uuid.New().String(),
+		NodeId:       node,
+		StorageClass: sc,
+		Size:         size,
+	})
+	assert.Nil(t, m.k8sClient.CreateCR(testCtx, name, ac))
+	return ac
+}
+
+func createACR(t *testing.T, m *ManagerImpl, size int64, sc string,
+	acs ...string) *acrcrd.AvailableCapacityReservation {
+	acr := m.k8sClient.ConstructACRCR(api.AvailableCapacityReservation{
+		Name:         uuid.New().String(),
+		StorageClass: sc,
+		Size:         size,
+		Reservations: acs,
+	})
+	// fake client doesn't set creation timestamp, ACR without it isn't released
+	acr.CreationTimestamp = metaV1.Now()
+	assert.Nil(t, m.k8sClient.CreateCR(testCtx, acr.Name, acr))
+	return acr
+}
+
+func readAC(t *testing.T, m *ManagerImpl, name string) *accrd.AvailableCapacity {
+	ac := &accrd.AvailableCapacity{}
+	assert.Nil(t, m.k8sClient.ReadCR(testCtx, name, "", ac))
+	return ac
+}
+
+func readACRs(t *testing.T, m *ManagerImpl) []acrcrd.AvailableCapacityReservation {
+	acrList := &acrcrd.AvailableCapacityReservationList{}
+	assert.Nil(t, m.k8sClient.ReadList(testCtx, acrList))
+	return acrList.Items
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -36,11 +37,14 @@ import (
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/bytesize"
 	"github.com/dell/csi-baremetal/pkg/base/cache"
 	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
 	fc "github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/capacitymanager"
 	"github.com/dell/csi-baremetal/pkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -59,10 +63,15 @@ type VolumeOperations interface {
 // VolumeOperationsImpl is the basic implementation of VolumeOperations interface
 type VolumeOperationsImpl struct {
 	acProvider             AvailableCapacityOperations
+	capacity               capacitymanager.Manager
 	k8sClient              *k8s.KubeClient
 	capacityManagerBuilder capacityplanner.CapacityManagerBuilder
 	crHelper               *k8s.CRHelper
 
+	// placement strategies which are set by StorageClass parameter, they are created on first use
+	strategies   map[string]capacityplanner.PlacementStrategy
+	strategiesMu sync.Mutex
+
 	metrics        metrics.Statistic
 	cache          cache.Interface
 	featureChecker fc.FeatureChecker
@@ -87,16 +96,42 @@ func NewVolumeOperationsImpl(k8sClient *k8s.KubeClient, logger *logrus.Logger, c
 		k8sClient:              k8sClient,
 		crHelper:               k8s.NewCRHelper(k8sClient, logger),
 		acProvider:             NewACOperationsImpl(k8sClient, logger),
+		capacity:               capacitymanager.NewManagerImpl(k8sClient, logger),
 		log:                    logger.WithField("component", "VolumeOperationsImpl"),
 		featureChecker:         featureConf,
 		capacityManagerBuilder: &capacityplanner.DefaultCapacityManagerBuilder{},
+		strategies:             map[string]capacityplanner.PlacementStrategy{},
 		cache:                  cache,
 		metrics:                volumeMetrics,
 	}
-	vo.fillCache()
+	if err := vo.RebuildCache(context.Background()); err != nil {
+		vo.log.WithField("method", "NewVolumeOperationsImpl").Errorf("Failed to fill volume cache: %v", err)
+	}
 	return vo
 }
 
+// SetPlacementStrategy sets strategy which is used to select AC for volume, when ACR reservation is enabled
+// it selects among ACs reserved for volume on node
+func (vo *VolumeOperationsImpl) SetPlacementStrategy(strategy capacityplanner.PlacementStrategy) {
+	vo.capacityManagerBuilder = &capacityplanner.DefaultCapacityManagerBuilder{Strategy: strategy}
+}
+
+// placementStrategy returns placement strategy by name, strategies are shared between volumes
+func (vo *VolumeOperationsImpl) placementStrategy(name string) (capacityplanner.PlacementStrategy, error) {
+	vo.strategiesMu.Lock()
+	defer vo.strategiesMu.Unlock()
+	if strategy, ok := vo.strategies[name]; ok {
+		return strategy, nil
+	}
+	strategy, err := capacityplanner.NewPlacementStrategy(name, vo.k8sClient,
+		vo.log.WithField("component", "PlacementStrategy"))
+	if err != nil {
+		return nil, err
+	}
+	vo.strategies[name] = strategy
+	return strategy, nil
+}
+
 // CreateVolume searches AC and creates volume CR or returns existed volume CR
 // Receives golang context and api.Volume which is Spec of Volume CR to create
 // Returns api.Volume instance that took the place of chosen by SearchAC method AvailableCapacity CR
@@ -128,7 +163,7 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 		}
 		// check that volume is in created state or time is over (for creating)
 		expiredAt := volumeCR.ObjectMeta.GetCreationTimestamp().Add(base.DefaultTimeoutForVolumeOperations)
-		if expiredAt.Before(time.Now()) {
+		if volumeCR.Spec.CSIStatus == apiV1.Creating && expiredAt.Before(time.Now()) {
 			ll.Errorf("Timeout of %s for volume creation exceeded.", base.DefaultTimeoutForVolumeOperations)
 			volumeCR.Spec.CSIStatus = apiV1.Failed
 			_ = vo.k8sClient.UpdateCRWithAttempts(ctxWithID, volumeCR, 5)
@@ -142,28 +177,38 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 		var (
 			ac             *accrd.AvailableCapacity
 			sc             string
-			requiredBytes  = v.Size
 			allocatedBytes int64
 			locationType   string
 			csiStatus      = apiV1.Creating
 		)
 
-		if util.IsStorageClassLVG(sc) {
-			requiredBytes = capacityplanner.AlignSizeByPE(requiredBytes)
-		}
-
 		capReader := capacityplanner.NewACReader(vo.k8sClient, vo.log, true)
 		resReader := capacityplanner.NewACRReader(vo.k8sClient, vo.log, true)
 
-		capacityManager := vo.createCapacityManager(capReader, resReader)
+		var planReader capacityplanner.CapacityReader = capReader
+		if locations, ok := ctx.Value(base.VolumeLocations).([]string); ok && len(locations) > 0 {
+			ll.Infof("Volume is pinned to locations %v", locations)
+			planReader = capacityplanner.NewLocationFilterACReader(capReader, locations)
+		}
+		capacityManagerBuilder := vo.capacityManagerBuilder
+		if name, ok := ctx.Value(base.VolumePlacementStrategy).(string); ok && name != "" {
+			strategy, err := vo.placementStrategy(name)
+			if err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			ll.Infof("Volume is placed with %s strategy", name)
+			capacityManagerBuilder = &capacityplanner.DefaultCapacityManagerBuilder{Strategy: strategy}
+		}
+		capacityManager := vo.createCapacityManager(capacityManagerBuilder, planReader, resReader)
 		plan, err := capacityManager.PlanVolumesPlacing(ctxWithID, []*api.Volume{&v})
 		if err != nil {
 			ll.Errorf("error while planning placing for volume: %s", err.Error())
 			return nil, err
 		}
-		noResourceMsg := fmt.Sprintf("there is no suitable drive for volume %s", v.Id)
+		noResourceErr := fmt.Errorf("%w: there is no suitable drive for volume %s of size %s",
+			errTypes.ErrCapacityExhausted, v.Id, bytesize.Format(v.Size))
 		if plan == nil {
-			return nil, status.Error(codes.ResourceExhausted, noResourceMsg)
+			return nil, noResourceErr
 		}
 		if v.NodeId == "" {
 			v.NodeId = plan.SelectNode()
@@ -171,17 +216,15 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 		ll.Infof("Try to create volume on node %s", v.NodeId)
 		ac = plan.GetACForVolume(v.NodeId, &v)
 		if ac == nil {
-			return nil, status.Error(codes.ResourceExhausted, noResourceMsg)
+			return nil, noResourceErr
 		}
 
-		resHelper := capacityplanner.NewReservationHelper(vo.log, vo.k8sClient, capReader, resReader)
-
 		origAC := ac
 		if ac.Spec.StorageClass != v.StorageClass && util.IsStorageClassLVG(v.StorageClass) {
 			// we need to create reservation for newly created LogicalVolumeGroup AC before we sent LogicalVolumeGroup AC to kube-api
 			// this required to prevent race condition between csi-controller and scheduler extender
 			newACName := uuid.New().String()
-			if err := resHelper.ExtendReservations(ctx, origAC, newACName); err != nil {
+			if err := vo.capacity.ExtendReservations(ctx, origAC, newACName); err != nil {
 				return nil, status.Errorf(codes.Internal,
 					"failed to extender reservation after AC conversion %v", err)
 			}
@@ -198,7 +241,8 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 		sc = ac.Spec.StorageClass
 
 		if util.IsStorageClassLVG(sc) {
-			allocatedBytes = requiredBytes
+			// LV size is rounded up by extents, aligned size is accounted in AC and reported as volume size
+			allocatedBytes = capacityplanner.AlignSizeByPE(v.Size)
 			locationType = apiV1.LocationTypeLVM
 		} else {
 			allocatedBytes = ac.Spec.Size
@@ -214,6 +258,13 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 			CSIStatus:         csiStatus,
 			StorageClass:      sc,
 			Ephemeral:         v.Ephemeral,
+			Scratch:           v.Scratch && locationType == apiV1.LocationTypeDrive,
+			ImageSource:       v.ImageSource,
+			ImageChecksum:     v.ImageChecksum,
+			ImageSecret:       v.ImageSecret,
+			MkFSOptions:       v.MkFSOptions,
+			FormatProfile:     v.FormatProfile,
+			Integrity:         v.Integrity,
 			Health:            apiV1.HealthGood,
 			LocationType:      locationType,
 			OperationalStatus: apiV1.OperationalStatusOperative,
@@ -222,20 +273,29 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 			Type:              v.Type,
 		}
 		volumeCR = vo.k8sClient.ConstructVolumeCR(v.Id, namespace, apiVolume)
+		// PVC labels and annotations selected by controller are propagated to Volume CR
+		if volumeLabels, ok := ctx.Value(base.VolumeLabels).(map[string]string); ok {
+			volumeCR.Labels = volumeLabels
+		}
+		if volumeAnnotations, ok := ctx.Value(base.VolumeAnnotations).(map[string]string); ok {
+			volumeCR.Annotations = volumeAnnotations
+		}
 
 		if err = vo.k8sClient.CreateCR(ctxWithID, v.Id, volumeCR); err != nil {
 			ll.Errorf("Unable to create CR, error: %v", err)
 			return nil, status.Errorf(codes.Internal, "unable to create volume CR")
 		}
 		vo.cache.Set(v.Id, namespace)
+		// status is refreshed by node service too, so volume is provisioned even if it wasn't stored here
+		if err = vo.k8sClient.UpdateStatus(ctxWithID, volumeCR); err != nil {
+			ll.Warnf("Unable to set status of volume CR: %v", err)
+		}
 
-		// decrease AC size
-		ac.Spec.Size -= allocatedBytes
-		if err = vo.k8sClient.UpdateCRWithAttempts(ctxWithID, ac, 5); err != nil {
-			ll.Errorf("Unable to set size for AC %s to %d, error: %v", ac.Name, ac.Spec.Size, err)
+		if err = vo.capacity.Allocate(ctxWithID, ac, allocatedBytes); err != nil {
+			ll.Errorf("Unable to allocate %d bytes on AC: %v", allocatedBytes, err)
 		}
 		if vo.featureChecker.IsEnabled(fc.FeatureACReservation) {
-			if err = resHelper.ReleaseReservation(ctxWithID, &v, origAC, ac); err != nil {
+			if err = vo.capacity.ReleaseReservation(ctxWithID, &v, origAC, ac); err != nil {
 				ll.Errorf("Unable to remove ACR reservation for AC %s, error: %v", ac.Name, err)
 			}
 		}
@@ -243,12 +303,12 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 	return &volumeCR.Spec, nil
 }
 
-func (vo *VolumeOperationsImpl) createCapacityManager(capReader capacityplanner.CapacityReader,
-	resReader capacityplanner.ReservationReader) capacityplanner.CapacityPlaner {
+func (vo *VolumeOperationsImpl) createCapacityManager(builder capacityplanner.CapacityManagerBuilder,
+	capReader capacityplanner.CapacityReader, resReader capacityplanner.ReservationReader) capacityplanner.CapacityPlaner {
 	if vo.featureChecker.IsEnabled(fc.FeatureACReservation) {
-		return vo.capacityManagerBuilder.GetReservedCapacityManager(vo.log, capReader, resReader)
+		return builder.GetReservedCapacityManager(vo.log, capReader, resReader)
 	}
-	return vo.capacityManagerBuilder.GetCapacityManager(vo.log, capReader)
+	return builder.GetCapacityManager(vo.log, capReader)
 }
 
 // DeleteVolume changes volume CR state and updates it,
@@ -268,10 +328,10 @@ func (vo *VolumeOperationsImpl) DeleteVolume(ctx context.Context, volumeID strin
 		err      error
 	)
 
-	namespace, err := vo.cache.Get(volumeID)
+	namespace, err := vo.volumeNamespace(ctx, volumeID)
 	if err != nil {
-		ll.Errorf("Unable to get volume namespace, volume doesn't exists: %v", err)
-		return status.Errorf(codes.NotFound, "volume doesn't exists in cache")
+		ll.Errorf("Unable to get volume namespace: %v", err)
+		return err
 	}
 	if err = vo.k8sClient.ReadCR(ctx, volumeID, namespace, volumeCR); err != nil {
 		return err
@@ -318,7 +378,7 @@ func (vo *VolumeOperationsImpl) UpdateCRsAfterVolumeDeletion(ctx context.Context
 		err      error
 	)
 
-	namespace, err := vo.cache.Get(volumeID)
+	namespace, err := vo.volumeNamespace(ctx, volumeID)
 	if err != nil {
 		ll.Errorf("Unable to get volume namespace: %v", err)
 		return
@@ -367,23 +427,22 @@ func (vo *VolumeOperationsImpl) UpdateCRsAfterVolumeDeletion(ctx context.Context
 			return
 		}
 
-		if isDeleted, err = vo.deleteLVGIfVolumesNotExistOrUpdate(lvg, volumeCR.Name, &acCR); err != nil {
+		if isDeleted, err = vo.deleteLVGIfVolumesNotExistOrUpdate(ctx, lvg, volumeCR.Name, &acCR); err != nil {
 			ll.Errorf("Unable to remove volume reference from LogicalVolumeGroup %s: %v", volumeCR.Spec.Location, err)
 		}
 	}
 
 	// if LogicalVolumeGroup wasn't deleted increase AC size
 	if !isDeleted {
-		// Increase size of AC using volume size
-		acCR.Spec.Size += volumeCR.Spec.Size
-		if err = vo.k8sClient.UpdateCRWithAttempts(ctx, &acCR, 5); err != nil {
-			ll.Errorf("Unable to update AC %s size: %v", acCR.Name, err)
+		if err = vo.capacity.Release(ctx, &acCR, volumeCR.Spec.Size); err != nil {
+			ll.Errorf("Unable to release volume capacity: %v", err)
 		}
 	}
 }
 
 // WaitStatus check volume status until it will be reached one of the statuses
 // return error if context is done or volume reaches failed status, return nil if reached status != failed
+// ErrOperationInProgress is returned if context is done, volume CR isn't changed
 func (vo *VolumeOperationsImpl) WaitStatus(ctx context.Context, volumeID string, statuses ...string) error {
 	defer vo.metrics.EvaluateDurationForMethod("WaitStatus")()
 	ll := vo.log.WithFields(logrus.Fields{
@@ -396,9 +455,10 @@ func (vo *VolumeOperationsImpl) WaitStatus(ctx context.Context, volumeID string,
 	var (
 		v                   = &volumecrd.Volume{}
 		timeoutBetweenCheck = time.Second
+		currentStatus       = "unknown"
 		err                 error
 	)
-	namespace, err := vo.cache.Get(volumeID)
+	namespace, err := vo.volumeNamespace(ctx, volumeID)
 	if err != nil {
 		ll.Errorf("Unable to get volume namespace: %v", err)
 		return fmt.Errorf("unable to get volume namespace")
@@ -407,7 +467,8 @@ func (vo *VolumeOperationsImpl) WaitStatus(ctx context.Context, volumeID string,
 		select {
 		case <-ctx.Done():
 			ll.Warnf("Context is done but volume still not reach one of the expected status: %v", statuses)
-			return fmt.Errorf("volume context is done")
+			return fmt.Errorf("%w: volume %s has %s status, expected one of %v",
+				errTypes.ErrOperationInProgress, volumeID, currentStatus, statuses)
 		case <-time.After(timeoutBetweenCheck):
 			if err = vo.k8sClient.ReadCR(ctx, volumeID, namespace, v); err != nil {
 				ll.Errorf("Unable to read volume CR: %v", err)
@@ -417,6 +478,7 @@ func (vo *VolumeOperationsImpl) WaitStatus(ctx context.Context, volumeID string,
 				}
 				continue
 			}
+			currentStatus = v.Spec.CSIStatus
 			for _, s := range statuses {
 				if v.Spec.CSIStatus == s {
 					if s == apiV1.Failed {
@@ -448,7 +510,7 @@ func (vo *VolumeOperationsImpl) ExpandVolume(ctx context.Context, volume *volume
 			return status.Error(codes.FailedPrecondition,
 				fmt.Sprintf("StorageClass %s doesn't support resizing", volume.Spec.StorageClass))
 		}
-		capacity, err := vo.crHelper.GetACByLocation(volume.Spec.Location)
+		capacity, err := vo.crHelper.GetACByLocation(ctx, volume.Spec.Location)
 		if err != nil {
 			ll.Errorf("Failed to get AC by location %s", volume.Spec.Location)
 			return status.Error(codes.Internal, "Unable to read AC")
@@ -459,8 +521,7 @@ func (vo *VolumeOperationsImpl) ExpandVolume(ctx context.Context, volume *volume
 			return status.Error(codes.OutOfRange,
 				fmt.Sprintf("Not enough capacity to expand volume: requested - %d, available - %d", requiredBytes, capacity.Spec.Size))
 		}
-		capacity.Spec.Size -= acSize
-		if err := vo.k8sClient.UpdateCRWithAttempts(ctx, capacity, 5); err != nil {
+		if err := vo.capacity.Allocate(ctx, capacity, acSize); err != nil {
 			ll.Errorf("Failed to update AC, error: %v", err)
 			return status.Error(codes.Internal, "Unable to reserve AC")
 		}
@@ -497,9 +558,9 @@ func (vo *VolumeOperationsImpl) UpdateCRsAfterVolumeExpansion(ctx context.Contex
 		err       error
 		volume    = &volumecrd.Volume{}
 	)
-	namespace, err = vo.cache.Get(volID)
+	namespace, err = vo.volumeNamespace(ctx, volID)
 	if err != nil {
-		ll.Errorf("Failed to get namespace from cache, error: %v", err)
+		ll.Errorf("Failed to get volume namespace, error: %v", err)
 		return
 	}
 	if err = vo.k8sClient.ReadCR(ctx, volID, namespace, volume); err != nil {
@@ -514,13 +575,11 @@ func (vo *VolumeOperationsImpl) UpdateCRsAfterVolumeExpansion(ctx context.Contex
 			return
 		}
 		volume.Spec.Size = capacity
-		ac, err := vo.crHelper.GetACByLocation(volume.Spec.Location)
+		ac, err := vo.crHelper.GetACByLocation(ctx, volume.Spec.Location)
 		if err != nil {
 			ll.Errorf("Failed to read AC: %v", err)
 		} else {
-			acSize := requiredBytes - volume.Spec.Size
-			ac.Spec.Size += acSize
-			if err = vo.k8sClient.UpdateCRWithAttempts(ctx, ac, 5); err != nil {
+			if err = vo.capacity.Release(ctx, ac, requiredBytes-volume.Spec.Size); err != nil {
 				ll.Errorf("Failed to update AC: %v", err)
 			}
 		}
@@ -543,24 +602,26 @@ func (vo *VolumeOperationsImpl) UpdateCRsAfterVolumeExpansion(ctx context.Contex
 
 // deleteLVGIfVolumesNotExistOrUpdate tries to remove volume ID into VolumeRefs slice from LogicalVolumeGroup struct
 // and updates according LogicalVolumeGroup
-// If VolumeRefs length equals 0, then deletes according AC and LogicalVolumeGroup
-// Receives LogicalVolumeGroup and volumeID of a Volume CR which should be removed
+// If VolumeRefs length equals 0, then deletes according AC and LogicalVolumeGroup unless it belongs to LVGPolicy
+// Receives golang context, LogicalVolumeGroup and volumeID of a Volume CR which should be removed
 // Returns true if LogicalVolumeGroup CR was deleted and false otherwise, error if something went wrong
-func (vo *VolumeOperationsImpl) deleteLVGIfVolumesNotExistOrUpdate(lvg *lvgcrd.LogicalVolumeGroup,
+func (vo *VolumeOperationsImpl) deleteLVGIfVolumesNotExistOrUpdate(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup,
 	volID string, ac *accrd.AvailableCapacity) (bool, error) {
 	log := vo.log.WithFields(logrus.Fields{
 		"method":   "deleteLVGIfVolumesNotExistOrUpdate",
 		"volumeID": volID,
 	})
 
-	drivesUUIDs := vo.k8sClient.GetSystemDriveUUIDs()
+	drivesUUIDs := vo.k8sClient.GetSystemDriveUUIDs(ctx)
+	// LogicalVolumeGroup of LVGPolicy is kept without volumes
+	_, isPolicyLVG := lvg.Labels[base.LVGPolicyLabel]
 	// if only one volume remains - remove AC first and LogicalVolumeGroup then
-	if len(lvg.Spec.VolumeRefs) == 1 && !util.ContainsString(drivesUUIDs, lvg.Spec.Locations[0]) {
-		if err := vo.k8sClient.DeleteCR(context.Background(), ac); err != nil {
+	if len(lvg.Spec.VolumeRefs) == 1 && !isPolicyLVG && !util.ContainsString(drivesUUIDs, lvg.Spec.Locations[0]) {
+		if err := vo.k8sClient.DeleteCR(ctx, ac); err != nil {
 			log.Errorf("Unable to delete AC %s: %v", ac.Name, err)
 			return false, err
 		}
-		return true, vo.k8sClient.DeleteCR(context.Background(), lvg)
+		return true, vo.k8sClient.DeleteCR(ctx, lvg)
 	}
 
 	// search for volume index
@@ -571,7 +632,7 @@ func (vo *VolumeOperationsImpl) deleteLVGIfVolumesNotExistOrUpdate(lvg *lvgcrd.L
 			lvg.Spec.VolumeRefs[i] = lvg.Spec.VolumeRefs[l-1]
 			lvg.Spec.VolumeRefs = lvg.Spec.VolumeRefs[:l-1]
 
-			return false, vo.k8sClient.UpdateCR(context.Background(), lvg)
+			return false, vo.k8sClient.UpdateCR(ctx, lvg)
 		}
 	}
 
@@ -579,16 +640,38 @@ func (vo *VolumeOperationsImpl) deleteLVGIfVolumesNotExistOrUpdate(lvg *lvgcrd.L
 	return false, errors.New("lvg CR wasn't updated")
 }
 
-// fillCache tries to fill volume/namespace cache after VolumeOperationsImpl initialization
-func (vo *VolumeOperationsImpl) fillCache() {
-	ll := vo.log.WithFields(logrus.Fields{
-		"method": "fillCache",
-	})
+// RebuildCache fills volume/namespace cache from volume CRs, it is called after VolumeOperationsImpl initialization
+// and on warm start of the controller, so volumes created before restart could be found
+// Receives golang context
+// Returns error if volume CRs can't be read
+func (vo *VolumeOperationsImpl) RebuildCache(ctx context.Context) error {
 	volList := &volumecrd.VolumeList{}
-	if err := vo.k8sClient.ReadList(context.Background(), volList); err != nil {
-		ll.Errorf("Failed to fill volume cache, error %v", err)
+	if err := vo.k8sClient.ReadList(ctx, volList); err != nil {
+		return err
 	}
 	for _, volume := range volList.Items {
 		vo.cache.Set(volume.Name, volume.Namespace)
 	}
+	return nil
+}
+
+// volumeNamespace returns namespace of the volume from cache, on cache miss volume CR is searched in all namespaces,
+// cache could miss the volume if it wasn't filled on start because API server was unavailable
+// Receives golang context and volume ID
+// Returns namespace or error which wraps ErrorNotFound if volume CR doesn't exist
+func (vo *VolumeOperationsImpl) volumeNamespace(ctx context.Context, volumeID string) (string, error) {
+	if namespace, err := vo.cache.Get(volumeID); err == nil {
+		return namespace, nil
+	}
+	volList := &volumecrd.VolumeList{}
+	if err := vo.k8sClient.ReadList(ctx, volList); err != nil {
+		return "", status.Errorf(codes.Aborted, "unable to read volumes: %v", err)
+	}
+	for _, volume := range volList.Items {
+		if volume.Name == volumeID {
+			vo.cache.Set(volume.Name, volume.Namespace)
+			return volume.Namespace, nil
+		}
+	}
+	return "", fmt.Errorf("volume %s doesn't exist: %w", volumeID, errTypes.ErrorNotFound)
 }
@@ -18,6 +18,7 @@ package common
 
 import (
 	"context"
+	"errors"
 	"strconv"
 	"testing"
 	"time"
@@ -37,6 +38,7 @@ import (
 	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/dell/csi-baremetal/pkg/base/cache"
 	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/util"
@@ -108,6 +110,33 @@ func TestVolumeOperationsImpl_CreateVolume_HDDVolumeCreated(t *testing.T) {
 	assert.Equal(t, expectedVolume, createdVolume)
 }
 
+// Placement strategy from StorageClass parameter selects AC
+func TestVolumeOperationsImpl_CreateVolume_PlacementStrategy(t *testing.T) {
+	var (
+		svc     = setupVOOperationsTest(t)
+		smallAC = svc.k8sClient.ConstructACCR("ac-small", api.AvailableCapacity{
+			Location: testDrive1UUID, NodeId: testNode1Name, StorageClass: apiV1.StorageClassHDD, Size: int64(util.GBYTE)})
+		largeAC = svc.k8sClient.ConstructACCR("ac-large", api.AvailableCapacity{
+			Location: testDrive2UUID, NodeId: testNode1Name, StorageClass: apiV1.StorageClassHDD,
+			Size: int64(util.GBYTE) * 2})
+		ctx = context.WithValue(testCtx, base.VolumeNamespace, testNS)
+	)
+	for _, ac := range []*accrd.AvailableCapacity{smallAC, largeAC} {
+		assert.Nil(t, svc.k8sClient.CreateCR(testCtx, ac.Name, ac))
+	}
+
+	createdVolume, err := svc.CreateVolume(context.WithValue(ctx, base.VolumePlacementStrategy, "first-fit"),
+		api.Volume{Id: "pvc-1", StorageClass: apiV1.StorageClassHDD, Size: int64(util.GBYTE)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Nil(t, createdVolume)
+
+	spreadCtx := context.WithValue(ctx, base.VolumePlacementStrategy, capacityplanner.SpreadStrategy)
+	createdVolume, err = svc.CreateVolume(spreadCtx,
+		api.Volume{Id: "pvc-2", StorageClass: apiV1.StorageClassHDD, Size: int64(util.GBYTE)})
+	assert.Nil(t, err)
+	assert.Equal(t, testDrive2UUID, createdVolume.Location)
+}
+
 // Volume CR was successfully created, HDDLVG SC
 func TestVolumeOperationsImpl_CreateVolume_HDDLVGVolumeCreated(t *testing.T) {
 	var (
@@ -166,6 +195,46 @@ func TestVolumeOperationsImpl_CreateVolume_HDDLVGVolumeCreated(t *testing.T) {
 	assert.Equal(t, expectedVolume, *createdVolume)
 }
 
+// Size of LVG volume is aligned with extent size, aligned size is accounted in AC
+func TestVolumeOperationsImpl_CreateVolume_LVGVolumeSizeAligned(t *testing.T) {
+	var (
+		svc       = setupVOOperationsTest(t)
+		volumeID  = "pvc-aaaa-bbbb"
+		ctxWithID = context.WithValue(testCtx, base.RequestUUID, volumeID)
+		ac        = &accrd.AvailableCapacity{
+			TypeMeta:   v1.TypeMeta{Kind: "AvailableCapacity", APIVersion: apiV1.APIV1Version},
+			ObjectMeta: v1.ObjectMeta{Name: "lvg-ac"},
+			Spec: api.AvailableCapacity{
+				Location:     testLVG.Spec.Name,
+				NodeId:       testNode1Name,
+				StorageClass: apiV1.StorageClassHDDLVG,
+				Size:         int64(util.GBYTE),
+			},
+		}
+		alignedBytes = 2 * capacityplanner.DefaultPESize
+	)
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, ac.Name, ac))
+
+	capMBuilder, capMMock := getCapacityManagerMock()
+	svc.capacityManagerBuilder = capMBuilder
+	capMMock.On("PlanVolumesPlacing", ctxWithID, mock.Anything).
+		Return(buildVolumePlacingPlan(testNode1Name, &api.Volume{Id: volumeID}, ac), nil).Times(1)
+
+	ctx := context.WithValue(testCtx, base.VolumeNamespace, testNS)
+	createdVolume, err := svc.CreateVolume(ctx, api.Volume{
+		Id:           volumeID,
+		StorageClass: apiV1.StorageClassAny,
+		Size:         capacityplanner.DefaultPESize + 1,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, apiV1.StorageClassHDDLVG, createdVolume.StorageClass)
+	assert.Equal(t, alignedBytes, createdVolume.Size)
+
+	updatedAC := &accrd.AvailableCapacity{}
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, ac.Name, "", updatedAC))
+	assert.Equal(t, int64(util.GBYTE)-alignedBytes, updatedAC.Spec.Size)
+}
+
 // Volume CR exists and has "failed" CSIStatus
 func TestVolumeOperationsImpl_CreateVolume_FaileCauseExist(t *testing.T) {
 	svc := setupVOOperationsTest(t)
@@ -190,6 +259,7 @@ func TestVolumeOperationsImpl_CreateVolume_FailCauseTimeout(t *testing.T) {
 	v.ObjectMeta.CreationTimestamp = v1.Time{
 		Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.Local),
 	}
+	v.Spec.CSIStatus = apiV1.Creating
 	ctx := context.WithValue(testCtx, base.VolumeNamespace, testNS)
 	err := svc.k8sClient.CreateCR(ctx, v.Name, &v)
 	assert.Nil(t, err)
@@ -200,6 +270,25 @@ func TestVolumeOperationsImpl_CreateVolume_FailCauseTimeout(t *testing.T) {
 	assert.Nil(t, createdVolume)
 }
 
+// Volume CR exists, was created long time ago and has "created" CSIStatus
+func TestVolumeOperationsImpl_CreateVolume_ExistAfterTimeout(t *testing.T) {
+	var (
+		svc = setupVOOperationsTest(t)
+		v   = testVolume1
+	)
+	v.ObjectMeta.CreationTimestamp = v1.Time{
+		Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.Local),
+	}
+	v.Spec.CSIStatus = apiV1.Created
+	ctx := context.WithValue(testCtx, base.VolumeNamespace, testNS)
+	err := svc.k8sClient.CreateCR(ctx, v.Name, &v)
+	assert.Nil(t, err)
+
+	createdVolume, err := svc.CreateVolume(ctx, api.Volume{Id: v.Name})
+	assert.Nil(t, err)
+	assert.Equal(t, apiV1.Created, createdVolume.CSIStatus)
+}
+
 // There is no suitable AC
 func TestVolumeOperationsImpl_CreateVolume_FailNoAC(t *testing.T) {
 	var (
@@ -223,8 +312,7 @@ func TestVolumeOperationsImpl_CreateVolume_FailNoAC(t *testing.T) {
 		NodeId:       requiredNode,
 		Size:         requiredBytes,
 	})
-	assert.NotNil(t, err)
-	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.True(t, errors.Is(err, errTypes.ErrCapacityExhausted))
 	assert.Nil(t, createdVolume)
 }
 
@@ -374,6 +462,47 @@ func TestVolumeOperationsImpl_DeleteVolume_SetStatus(t *testing.T) {
 	assert.Equal(t, apiV1.Removing, updatedVol.Spec.CSIStatus)
 }
 
+// volume was created before restart and is missing in cache
+func TestVolumeOperationsImpl_DeleteVolume_CacheMiss(t *testing.T) {
+	var (
+		svc        = setupVOOperationsTest(t)
+		v          = testVolume1
+		updatedVol = volumecrd.Volume{}
+		err        error
+	)
+
+	err = svc.DeleteVolume(testCtx, testVolume1Name)
+	assert.True(t, errors.Is(err, errTypes.ErrorNotFound))
+
+	v.Spec.CSIStatus = apiV1.Created
+	err = svc.k8sClient.CreateCR(testCtx, testVolume1Name, &v)
+	assert.Nil(t, err)
+
+	err = svc.DeleteVolume(testCtx, testVolume1Name)
+	assert.Nil(t, err)
+
+	err = svc.k8sClient.ReadCR(testCtx, testVolume1Name, testVolume1.Namespace, &updatedVol)
+	assert.Nil(t, err)
+	assert.Equal(t, apiV1.Removing, updatedVol.Spec.CSIStatus)
+
+	namespace, err := svc.cache.Get(testVolume1Name)
+	assert.Nil(t, err)
+	assert.Equal(t, testVolume1.Namespace, namespace)
+}
+
+func TestVolumeOperationsImpl_RebuildCache(t *testing.T) {
+	svc := setupVOOperationsTest(t)
+
+	v := testVolume1
+	err := svc.k8sClient.CreateCR(testCtx, testVolume1Name, &v)
+	assert.Nil(t, err)
+
+	assert.Nil(t, svc.RebuildCache(testCtx))
+	namespace, err := svc.cache.Get(testVolume1Name)
+	assert.Nil(t, err)
+	assert.Equal(t, testVolume1.Namespace, namespace)
+}
+
 func TestVolumeOperationsImpl_WaitStatus_Success(t *testing.T) {
 	svc := setupVOOperationsTest(t)
 
@@ -411,6 +540,7 @@ func TestVolumeOperationsImpl_WaitStatus_Fails(t *testing.T) {
 	// volume CR wasn't found
 	err = svc.WaitStatus(ctx, testVolume1Name, apiV1.Created)
 	assert.NotNil(t, err)
+	assert.True(t, errors.Is(err, errTypes.ErrOperationInProgress))
 }
 
 func TestVolumeOperationsImpl_UpdateCRsAfterVolumeDeletion(t *testing.T) {
@@ -553,20 +683,20 @@ func TestVolumeOperationsImpl_UpdateCRsAfterVolumeExpansion(t *testing.T) {
 	// volume doesn't have annotation
 	svc.UpdateCRsAfterVolumeExpansion(testCtx, volumeCR.Spec.Id, int64(util.GBYTE)*100)
 
-	capacity, err := svc.crHelper.GetACByLocation(volumeCR.Spec.Location)
+	capacity, err := svc.crHelper.GetACByLocation(testCtx, volumeCR.Spec.Location)
 	assert.Nil(t, err)
 	assert.Equal(t, volAC.Spec.Size, capacity.Spec.Size)
 
 	// volume has annotation and status failed
 	volumeCR.Annotations = map[string]string{apiV1.VolumePreviousCapacity: strconv.FormatInt(int64(util.MBYTE), 10)}
 	err = svc.k8sClient.UpdateCR(testCtx, &volumeCR)
-	pAC, err := svc.crHelper.GetACByLocation(volumeCR.Spec.Location)
+	pAC, err := svc.crHelper.GetACByLocation(testCtx, volumeCR.Spec.Location)
 	assert.Nil(t, err)
 	svc.UpdateCRsAfterVolumeExpansion(testCtx, volumeCR.Spec.Id, int64(util.GBYTE)*100)
 
 	err = svc.k8sClient.ReadCR(testCtx, volumeCR.Name, volumeCR.Namespace, &volumeCR)
 	assert.Nil(t, err)
-	capacity, err = svc.crHelper.GetACByLocation(volumeCR.Spec.Location)
+	capacity, err = svc.crHelper.GetACByLocation(testCtx, volumeCR.Spec.Location)
 	assert.Nil(t, err)
 	assert.Equal(t, pAC.Spec.Size+int64(util.GBYTE)*100-int64(util.MBYTE), capacity.Spec.Size)
 
@@ -596,7 +726,7 @@ func TestVolumeOperationsImpl_deleteLVGIfVolumesNotExistOrUpdate(t *testing.T) {
 
 	// CR not found error
 	testLVG.Spec.VolumeRefs = [](string){volumeID, volumeID1}
-	isDeleted, err := svc.deleteLVGIfVolumesNotExistOrUpdate(&testLVG, volumeID, &testAC4)
+	isDeleted, err := svc.deleteLVGIfVolumesNotExistOrUpdate(context.Background(), &testLVG, volumeID, &testAC4)
 	assert.False(t, isDeleted)
 	assert.NotNil(t, err)
 	assert.True(t, k8sError.IsNotFound(err))
@@ -607,7 +737,7 @@ func TestVolumeOperationsImpl_deleteLVGIfVolumesNotExistOrUpdate(t *testing.T) {
 	assert.Nil(t, err)
 
 	// test deletion
-	isDeleted, err = svc.deleteLVGIfVolumesNotExistOrUpdate(&testLVG, volumeID, &testAC4)
+	isDeleted, err = svc.deleteLVGIfVolumesNotExistOrUpdate(context.Background(), &testLVG, volumeID, &testAC4)
 	assert.True(t, isDeleted)
 	assert.Nil(t, err)
 	lvg := &lvgcrd.LogicalVolumeGroup{}
@@ -618,11 +748,31 @@ func TestVolumeOperationsImpl_deleteLVGIfVolumesNotExistOrUpdate(t *testing.T) {
 	assert.True(t, k8sError.IsNotFound(err))
 
 	// try to remove again
-	isDeleted, err = svc.deleteLVGIfVolumesNotExistOrUpdate(&testLVG, volumeID, &testAC4)
+	isDeleted, err = svc.deleteLVGIfVolumesNotExistOrUpdate(context.Background(), &testLVG, volumeID, &testAC4)
 	assert.False(t, isDeleted)
 	assert.True(t, k8sError.IsNotFound(err))
 }
 
+func TestVolumeOperationsImpl_deleteLVGIfVolumesNotExistOrUpdate_PolicyLVG(t *testing.T) {
+	svc := setupVOOperationsTest(t)
+	volumeID := "volumeID"
+
+	lvg := testLVG.DeepCopy()
+	lvg.Labels = map[string]string{base.LVGPolicyLabel: "policy"}
+	lvg.Spec.VolumeRefs = []string{volumeID}
+	assert.Nil(t, svc.k8sClient.CreateCR(context.Background(), lvg.Name, lvg))
+	assert.Nil(t, svc.k8sClient.CreateCR(context.Background(), testAC4.Name, &testAC4))
+
+	// LogicalVolumeGroup of LVGPolicy is kept
+	isDeleted, err := svc.deleteLVGIfVolumesNotExistOrUpdate(context.Background(), lvg, volumeID, &testAC4)
+	assert.False(t, isDeleted)
+	assert.Nil(t, err)
+	currLVG := &lvgcrd.LogicalVolumeGroup{}
+	assert.Nil(t, svc.k8sClient.ReadCR(context.Background(), lvg.Name, "", currLVG))
+	assert.Empty(t, currLVG.Spec.VolumeRefs)
+	assert.Nil(t, svc.k8sClient.ReadCR(context.Background(), testAC4.Name, "", &accrd.AvailableCapacity{}))
+}
+
 // creates fake k8s client and creates AC CRs based on provided acs
 // returns instance of ACOperationsImpl based on created k8s client
 func setupVOOperationsTest(t *testing.T) *VolumeOperationsImpl {
@@ -19,6 +19,8 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
 
@@ -29,18 +31,28 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/clock"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/dell/csi-baremetal/pkg/base/cache"
 	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
+	"github.com/dell/csi-baremetal/pkg/base/imagesource"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockqueue"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/common"
 	"github.com/dell/csi-baremetal/pkg/controller/node"
 	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	metricsC "github.com/dell/csi-baremetal/pkg/metrics/common"
 )
 
 // NodeID is the type for node hostname
@@ -50,18 +62,42 @@ type NodeID string
 type CSIControllerService struct {
 	k8sclient *k8s.KubeClient
 
-	// mutex for csi request
-	reqMu sync.Mutex
-	log   *logrus.Entry
+	// mutex for csi request, CreateVolume with preferred node holds it for reading and serializes by node with nodeMu
+	reqMu sync.RWMutex
+	// mutexes for capacity selection on the same node, key is node ID, value is *sync.Mutex
+	nodeMu sync.Map
+	// mutexes for storage quota check in the same namespace, key is namespace, value is *sync.Mutex
+	quotaMu sync.Map
+	// limits amount of CreateVolume requests which are processed in parallel
+	createSem chan struct{}
+	log       *logrus.Entry
 
 	svc common.VolumeOperations
 
 	// to track node health status
 	nodeServicesStateMonitor *node.ServicesStateMonitor
+	// to detect volumes of removed nodes
+	volumeOwnerMonitor *node.VolumeOwnerMonitor
 
 	ready bool
 
 	crHelper *k8s.CRHelper
+	// to report events about PVCs, events aren't sent if it is nil
+	eventRecorder eventRecorder
+
+	featureChecker featureconfig.FeatureChecker
+	// node labels which are added to accessible topology of volumes in addition to node ID
+	topologyLabels []string
+	// keys of PVC labels and annotations which are propagated to Volume CR and volume context
+	pvcMetadataKeys []string
+	// PUBLISH_UNPUBLISH_VOLUME capability is reported only for external attacher which is kept
+	// for VolumeAttachments created by previous versions, otherwise attach isn't required
+	attachCompatibility bool
+	// clock of periodic routines (orphaned volumes janitor, LVGPolicy reconciler)
+	clock clock.Clock
+
+	// sources of the volume images, PVC annotations with image source are honored only if it isn't empty
+	imageSources imagesource.Allowlist
 
 	csi.IdentityServer
 	grpc_health_v1.HealthServer
@@ -77,16 +113,80 @@ func NewControllerService(k8sClient *k8s.KubeClient, logger *logrus.Logger,
 		log:                      logger.WithField("component", "CSIControllerService"),
 		svc:                      common.NewVolumeOperationsImpl(k8sClient, logger, cache.NewMemCache(), featureConf),
 		nodeServicesStateMonitor: node.NewNodeServicesStateMonitor(k8sClient, logger),
+		volumeOwnerMonitor:       node.NewVolumeOwnerMonitor(k8sClient, logger),
 		IdentityServer:           NewIdentityServer(base.PluginName, base.PluginVersion),
 		crHelper:                 k8s.NewCRHelper(k8sClient, logger),
+		featureChecker:           featureConf,
+		createSem:                make(chan struct{}, base.DefaultCreateVolumeParallelism),
+		clock:                    clock.RealClock{},
 	}
 
 	// run health monitor
 	c.nodeServicesStateMonitor.Run()
+	c.volumeOwnerMonitor.Run()
 
 	return c
 }
 
+// SetCreateVolumeParallelism sets amount of CreateVolume requests which are processed in parallel
+// Should be called before the service starts serving requests, non-positive value is ignored
+func (c *CSIControllerService) SetCreateVolumeParallelism(parallelism int) {
+	if parallelism <= 0 {
+		c.log.Warnf("Unable to set CreateVolume parallelism to %d, using %d", parallelism, cap(c.createSem))
+		return
+	}
+	c.createSem = make(chan struct{}, parallelism)
+}
+
+// SetTopologyLabels sets node labels (for example rack or zone) which are added to accessible topology of volumes,
+// they should be the same as topology labels of node service
+func (c *CSIControllerService) SetTopologyLabels(labels []string) {
+	c.topologyLabels = labels
+}
+
+// SetPVCMetadataKeys sets keys of PVC labels and annotations which are propagated to Volume CR and volume context,
+// so monitoring agents on node could associate volume with application
+func (c *CSIControllerService) SetPVCMetadataKeys(keys []string) {
+	c.pvcMetadataKeys = keys
+}
+
+// SetAttachCompatibility enables PUBLISH_UNPUBLISH_VOLUME capability, it should be enabled only while external
+// attacher is deployed to detach VolumeAttachments created when CSIDriver required attach
+func (c *CSIControllerService) SetAttachCompatibility(enabled bool) {
+	c.attachCompatibility = enabled
+}
+
+// placementStrategySetter is implemented by volume operations which plan volumes placing by themselves
+type placementStrategySetter interface {
+	SetPlacementStrategy(strategy capacityplanner.PlacementStrategy)
+}
+
+// SetPlacementStrategy sets strategy which is used to select AvailableCapacity for new volumes
+func (c *CSIControllerService) SetPlacementStrategy(strategy capacityplanner.PlacementStrategy) {
+	if setter, ok := c.svc.(placementStrategySetter); ok {
+		setter.SetPlacementStrategy(strategy)
+	}
+}
+
+// volumeTopology returns topology segments of the node where volume is placed
+// Node labels are read if topology labels are set, node ID is returned only if node can't be read
+func (c *CSIControllerService) volumeTopology(ctx context.Context, nodeID string) map[string]string {
+	segments := map[string]string{csibmnodeconst.NodeIDAnnotationKey: nodeID}
+	if len(c.topologyLabels) == 0 {
+		return segments
+	}
+	node, err := c.k8sclient.GetNodeByID(ctx, nodeID)
+	if err != nil {
+		c.log.WithField("method", "volumeTopology").
+			Warnf("Unable to read topology labels of node %s, only node ID is used: %v", nodeID, err)
+		return segments
+	}
+	for key, value := range k8s.TopologySegments(node, c.topologyLabels) {
+		segments[key] = value
+	}
+	return segments
+}
+
 // Probe is the implementation of CSI Spec Probe for IdentityServer.
 // This method checks if CSI driver is ready to serve requests
 // overrides same method from defaultIdentityServer struct
@@ -145,7 +245,7 @@ func (c *CSIControllerService) CreateVolume(ctx context.Context, req *csi.Create
 		"method":   "CreateVolume",
 		"volumeID": req.GetName(),
 	})
-	ll.Infof("Processing request: %v", req)
+	ll.Infof("Processing request: %v", util.StripSecrets(req))
 
 	if req.GetName() == "" {
 		return nil, status.Error(codes.InvalidArgument, "Volume name missing in request")
@@ -174,43 +274,348 @@ func (c *CSIControllerService) CreateVolume(ctx context.Context, req *csi.Create
 	} else {
 		mode = apiV1.ModeRAW
 	}
-	c.reqMu.Lock()
+	storageClass := util.ConvertStorageClass(req.Parameters[base.StorageTypeKey])
+	if limit := req.GetCapacityRange().GetLimitBytes(); limit > 0 && util.IsStorageClassLVG(storageClass) &&
+		capacityplanner.AlignSizeByPE(req.GetCapacityRange().GetRequiredBytes()) > limit {
+		return nil, status.Errorf(codes.OutOfRange, "size aligned by %d bytes extents exceeds limit %d bytes",
+			capacityplanner.DefaultPESize, limit)
+	}
+	scratch, err := isScratchVolume(req.GetParameters(), storageClass, mode)
+	if err != nil {
+		return nil, err
+	}
+	integrity, err := volumeIntegrity(req.GetParameters(), fsType, mode, scratch)
+	if err != nil {
+		return nil, err
+	}
+	mkfsOptions, err := volumeMkFSOptions(req.GetParameters(), fsType, mode, storageClass)
+	if err != nil {
+		return nil, err
+	}
+	formatProfile, err := volumeFormatProfile(req.GetParameters(), fsType, mode, storageClass)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateReadCache(req.GetParameters(), mode); err != nil {
+		return nil, err
+	}
+	if _, err = blockqueue.ParseSettings(req.Parameters[base.IOSchedulerKey], req.Parameters[base.NrRequestsKey],
+		req.Parameters[base.ReadAheadKBKey]); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid I/O tuning parameters: %v", err)
+	}
+	imageSource, imageChecksum, err := c.volumeImage(ctx, req.GetParameters())
+	if err != nil {
+		return nil, err
+	}
+	imageCreds, err := imageCredentials(req.GetSecrets())
+	if err != nil {
+		return nil, err
+	}
+	locations, err := c.driveLocations(ctx, req.GetParameters(), storageClass, preferredNode)
+	if err != nil {
+		return nil, errTypes.ToGRPCStatus(err)
+	}
+	if len(locations) > 0 {
+		ctxWithNamespace = context.WithValue(ctxWithNamespace, base.VolumeLocations, locations)
+	}
+	if strategy := req.Parameters[base.PlacementStrategyKey]; strategy != "" {
+		ctxWithNamespace = context.WithValue(ctxWithNamespace, base.VolumePlacementStrategy, strategy)
+	}
+	pvcLabels, pvcAnnotations, err := c.pvcMetadata(ctx, req.GetParameters())
+	if err != nil {
+		return nil, err
+	}
+	if len(pvcLabels) > 0 {
+		ctxWithNamespace = context.WithValue(ctxWithNamespace, base.VolumeLabels, pvcLabels)
+	}
+	if len(pvcAnnotations) > 0 {
+		ctxWithNamespace = context.WithValue(ctxWithNamespace, base.VolumeAnnotations, pvcAnnotations)
+	}
+	// credentials are passed to the node through the Secret, it is removed when volume is populated
+	var imageSecret string
+	if imageSource != "" && !imageCreds.IsEmpty() {
+		if imageSecret, err = c.storeImageCredentials(ctx, req.Name, imageCreds); err != nil {
+			return nil, err
+		}
+	}
+	unlock, err := c.lockForCreate(ctx, preferredNode)
+	if err != nil {
+		return nil, err
+	}
+	observe := metricsC.VolumePhaseDuration.EvaluateDurationForPhase(string(volumecrd.VolumePhaseACSelected))
+	// volume with the same name could be created earlier, it should be compatible with requested capacity
+	existing, err := c.crHelper.GetVolumeByID(ctx, req.Name)
+	if err == nil && !isCapacityCompatible(existing.Spec.Size, req.GetCapacityRange()) {
+		unlock()
+		return nil, status.Errorf(codes.AlreadyExists,
+			"Volume %s already exists with incompatible size %d", req.Name, existing.Spec.Size)
+	}
+	releaseQuota := func() {}
+	if err != nil {
+		releaseQuota, err = c.checkStorageQuota(ctx, req.Parameters[base.PVCNamespaceKey],
+			req.GetCapacityRange().GetRequiredBytes())
+		if err != nil {
+			unlock()
+			if errors.Is(err, errTypes.ErrCapacityExhausted) {
+				c.recordPVCEvent(ctx, req.GetParameters(), eventing.WarningType, eventing.StorageQuotaExceeded,
+					err.Error())
+			}
+			return nil, errTypes.ToGRPCStatus(err)
+		}
+	}
 	vol, err = c.svc.CreateVolume(ctxWithNamespace, api.Volume{
-		Id:           req.Name,
-		StorageClass: util.ConvertStorageClass(req.Parameters[base.StorageTypeKey]),
-		NodeId:       preferredNode,
-		Size:         req.GetCapacityRange().GetRequiredBytes(),
-		Mode:         mode,
-		Type:         fsType,
+		Id:            req.Name,
+		StorageClass:  storageClass,
+		NodeId:        preferredNode,
+		Size:          req.GetCapacityRange().GetRequiredBytes(),
+		Mode:          mode,
+		Type:          fsType,
+		Scratch:       scratch,
+		ImageSource:   imageSource,
+		ImageChecksum: imageChecksum,
+		ImageSecret:   imageSecret,
+		Integrity:     integrity,
+		MkFSOptions:   mkfsOptions,
+		FormatProfile: formatProfile,
 	})
-	c.reqMu.Unlock()
+	releaseQuota()
+	unlock()
 
 	if err != nil {
-		return nil, err
+		if imageSecret != "" {
+			c.removeImageCredentials(ctx, req.Name)
+		}
+		return nil, errTypes.ToGRPCStatus(err)
 	}
-
 	if vol.CSIStatus == apiV1.Creating {
+		observe()
 		ll.Infof("Waiting until volume will reach Created status. Current status - %s", vol.CSIStatus)
 		if err := c.svc.WaitStatus(ctx, vol.Id, apiV1.Failed, apiV1.Created); err != nil {
+			// volume is still created, repeated request waits for it
+			if errors.Is(err, errTypes.ErrOperationInProgress) {
+				return nil, errTypes.ToGRPCStatus(err)
+			}
 			return nil, status.Error(codes.Internal, "Unable to create volume")
 		}
 	}
+	if imageSecret != "" {
+		c.removeImageCredentials(ctx, req.Name)
+	}
 
 	ll.Infof("Construct response based on volume: %v", vol)
 	topologyList := []*csi.Topology{
-		{Segments: map[string]string{csibmnodeconst.NodeIDAnnotationKey: vol.NodeId}},
+		{Segments: c.volumeTopology(ctx, vol.NodeId)},
 	}
 
+	volumeContext := req.GetParameters()
+	if c.featureChecker.IsEnabled(featureconfig.FeatureNUMAHint) {
+		volumeContext = c.addNUMAHint(ctx, volumeContext, vol)
+	}
+	volumeContext = addPVCMetadata(volumeContext, pvcLabels, pvcAnnotations)
+
 	return &csi.CreateVolumeResponse{
 		Volume: &csi.Volume{
 			VolumeId:           req.Name,
 			CapacityBytes:      vol.Size,
-			VolumeContext:      req.GetParameters(),
+			VolumeContext:      volumeContext,
 			AccessibleTopology: topologyList,
 		},
 	}, nil
 }
 
+// lockForCreate limits amount of CreateVolume requests which select capacity at the same time and serializes them:
+// requests with the same preferred node are processed one by one, requests for different nodes are processed in parallel,
+// request without preferred node could choose any node, so it is processed exclusively
+// Returns function which releases acquired locks or error if context was done while waiting
+func (c *CSIControllerService) lockForCreate(ctx context.Context, preferredNode string) (func(), error) {
+	sem := c.createSem
+	select {
+	case sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, status.Error(codes.DeadlineExceeded, "Timeout while waiting for CreateVolume slot")
+	}
+
+	if preferredNode == "" {
+		c.reqMu.Lock()
+		return func() {
+			c.reqMu.Unlock()
+			<-sem
+		}, nil
+	}
+	c.reqMu.RLock()
+	mu, _ := c.nodeMu.LoadOrStore(preferredNode, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return func() {
+		mu.(*sync.Mutex).Unlock()
+		c.reqMu.RUnlock()
+		<-sem
+	}, nil
+}
+
+// isCapacityCompatible checks whether size of the volume satisfies CSI Spec CapacityRange
+func isCapacityCompatible(size int64, capacityRange *csi.CapacityRange) bool {
+	if size < capacityRange.GetRequiredBytes() {
+		return false
+	}
+	limit := capacityRange.GetLimitBytes()
+	return limit == 0 || size <= limit
+}
+
+// isScratchVolume checks whether scratch mode is requested in StorageClass parameters and could be used for the volume
+// scratch mode is supported only for volumes with file system on the whole drive and has to be acknowledged explicitly
+func isScratchVolume(params map[string]string, storageClass, mode string) (bool, error) {
+	if params[base.ScratchKey] != "true" {
+		return false, nil
+	}
+	switch {
+	case params[base.ScratchAllowDataRemanenceKey] != "true":
+		return false, status.Errorf(codes.InvalidArgument,
+			"scratch volumes require %s parameter to be set to true", base.ScratchAllowDataRemanenceKey)
+	case util.IsStorageClassLVG(storageClass):
+		return false, status.Errorf(codes.InvalidArgument, "scratch volumes aren't supported for storage class %s", storageClass)
+	case mode != apiV1.ModeFS:
+		return false, status.Errorf(codes.InvalidArgument, "scratch volumes aren't supported for %s mode", mode)
+	}
+	return true, nil
+}
+
+// volumeIntegrity returns integrity protection requested in StorageClass parameters, protection is supported only
+// for volumes with file system, metadata checksums are supported only by ext4 and scratch partitions aren't protected
+func volumeIntegrity(params map[string]string, fsType, mode string, scratch bool) (string, error) {
+	integrity := params[base.IntegrityKey]
+	switch integrity {
+	case "":
+		return "", nil
+	case apiV1.IntegrityDMIntegrity, apiV1.IntegrityChecksum:
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unsupported %s parameter value %s", base.IntegrityKey, integrity)
+	}
+	switch {
+	case mode != apiV1.ModeFS:
+		return "", status.Errorf(codes.InvalidArgument, "integrity protection isn't supported for %s mode", mode)
+	case scratch:
+		return "", status.Error(codes.InvalidArgument, "integrity protection isn't supported for scratch volumes")
+	case integrity == apiV1.IntegrityChecksum && fsType != string(fs.EXT4):
+		return "", status.Errorf(codes.InvalidArgument, "%s integrity protection requires %s file system, got %s",
+			integrity, fs.EXT4, fsType)
+	}
+	return integrity, nil
+}
+
+// volumeMkFSOptions returns mkfs options requested in StorageClass parameters, options are supported only
+// for volumes with file system which isn't created on zoned drive
+func volumeMkFSOptions(params map[string]string, fsType, mode, storageClass string) (string, error) {
+	opts := strings.TrimSpace(params[base.MkFSOptionsKey])
+	switch {
+	case opts == "":
+		return "", nil
+	case mode != apiV1.ModeFS:
+		return "", status.Errorf(codes.InvalidArgument, "mkfs options aren't supported for %s mode", mode)
+	case storageClass == apiV1.StorageClassZoned:
+		return "", status.Errorf(codes.InvalidArgument, "mkfs options aren't supported for storage class %s", storageClass)
+	}
+	if err := fs.ValidateMkFSOptions(fs.FileSystem(fsType), opts); err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return opts, nil
+}
+
+// volumeFormatProfile returns formatting profile requested in StorageClass parameters, profiles are supported
+// for ext3, ext4 and xfs volumes which aren't created on zoned drive, geometry of the device is read on the node
+func volumeFormatProfile(params map[string]string, fsType, mode, storageClass string) (string, error) {
+	profile := strings.TrimSpace(params[base.FormatProfileKey])
+	switch {
+	case profile == "":
+		return "", nil
+	case profile != apiV1.FormatProfileAligned && profile != apiV1.FormatProfileHugepage:
+		return "", status.Errorf(codes.InvalidArgument, "unknown format profile %s, supported values are %s and %s",
+			profile, apiV1.FormatProfileAligned, apiV1.FormatProfileHugepage)
+	case mode != apiV1.ModeFS:
+		return "", status.Errorf(codes.InvalidArgument, "format profile isn't supported for %s mode", mode)
+	case storageClass == apiV1.StorageClassZoned:
+		return "", status.Errorf(codes.InvalidArgument, "format profile isn't supported for storage class %s", storageClass)
+	}
+	switch fs.FileSystem(fsType) {
+	case fs.EXT3, fs.EXT4, fs.XFS:
+		return profile, nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "format profile isn't supported for file system %s", fsType)
+	}
+}
+
+// validateReadCache checks size of RAM read cache requested in StorageClass parameters, read cache is supported only
+// for volumes with file system because it is published as overlay, the cache itself is managed by node service
+func validateReadCache(params map[string]string, mode string) error {
+	value, ok := params[base.ReadCacheKey]
+	if !ok {
+		return nil
+	}
+	size, err := resource.ParseQuantity(value)
+	switch {
+	case err != nil:
+		return status.Errorf(codes.InvalidArgument, "invalid %s parameter value %s: %v", base.ReadCacheKey, value, err)
+	case size.Value() <= 0:
+		return status.Errorf(codes.InvalidArgument, "%s parameter should be positive, got %s", base.ReadCacheKey, value)
+	case mode != apiV1.ModeFS:
+		return status.Errorf(codes.InvalidArgument, "read cache isn't supported for %s mode", mode)
+	}
+	return nil
+}
+
+// addNUMAHint returns copy of volume context extended with NUMA node of the drives on which volume is located
+// volume context is returned as is if NUMA node isn't known
+func (c *CSIControllerService) addNUMAHint(ctx context.Context, volumeContext map[string]string, vol *api.Volume) map[string]string {
+	ll := c.log.WithFields(logrus.Fields{
+		"method":   "addNUMAHint",
+		"volumeID": vol.Id,
+	})
+
+	numaNode, err := c.volumeNUMANode(ctx, vol)
+	if err != nil {
+		ll.Warnf("Unable to determine NUMA node of volume: %v", err)
+		return volumeContext
+	}
+
+	hinted := make(map[string]string, len(volumeContext)+1)
+	for k, v := range volumeContext {
+		hinted[k] = v
+	}
+	hinted[base.NUMANodeKey] = numaNode
+	return hinted
+}
+
+// volumeNUMANode returns NUMA node of the drive on which volume is located, LogicalVolumeGroup is resolved
+// to its drives which have to share the same NUMA node
+// Returns error if drives can't be read or their NUMA node isn't known
+func (c *CSIControllerService) volumeNUMANode(ctx context.Context, vol *api.Volume) (string, error) {
+	driveUUIDs := []string{vol.Location}
+	if vol.LocationType == apiV1.LocationTypeLVM {
+		lvg := &lvgcrd.LogicalVolumeGroup{}
+		if err := c.k8sclient.ReadCR(ctx, vol.Location, "", lvg); err != nil {
+			return "", fmt.Errorf("unable to read LogicalVolumeGroup %s: %w", vol.Location, err)
+		}
+		if len(lvg.Spec.Locations) == 0 {
+			return "", fmt.Errorf("LogicalVolumeGroup %s has no drives", vol.Location)
+		}
+		driveUUIDs = lvg.Spec.Locations
+	}
+
+	numaNode := ""
+	for _, driveUUID := range driveUUIDs {
+		drive := c.crHelper.GetDriveCRByUUID(ctx, driveUUID)
+		switch {
+		case drive == nil:
+			return "", fmt.Errorf("drive %s isn't found", driveUUID)
+		case drive.Spec.NUMANode == "":
+			return "", fmt.Errorf("NUMA node of drive %s isn't known", driveUUID)
+		case numaNode != "" && numaNode != drive.Spec.NUMANode:
+			return "", fmt.Errorf("drives of LogicalVolumeGroup %s are on different NUMA nodes", vol.Location)
+		}
+		numaNode = drive.Spec.NUMANode
+	}
+	return numaNode, nil
+}
+
 // DeleteVolume is the implementation of CSI Spec DeleteVolume. This method sets Volume CR's Spec.CSIStatus to Removing.
 // And waits for Volume to be removed by Reconcile loop of appropriate Node.
 // Receives golang context and CSI Spec DeleteVolumeRequest
@@ -221,7 +626,7 @@ func (c *CSIControllerService) DeleteVolume(ctx context.Context, req *csi.Delete
 		"volumeID": req.GetVolumeId(),
 	})
 
-	ll.Infof("Processing request: %v", req)
+	ll.Infof("Processing request: %v", util.StripSecrets(req))
 
 	if req.VolumeId == "" {
 		return nil, status.Error(codes.InvalidArgument, "Volume ID must be provided")
@@ -233,15 +638,18 @@ func (c *CSIControllerService) DeleteVolume(ctx context.Context, req *csi.Delete
 	c.reqMu.Unlock()
 
 	if err != nil {
-		if k8sError.IsNotFound(err) || (status.Code(err) == codes.NotFound) {
+		if k8sError.IsNotFound(err) || errors.Is(err, errTypes.ErrorNotFound) {
 			ll.Infof("Volume doesn't exist")
 			return &csi.DeleteVolumeResponse{}, nil
 		}
 		ll.Errorf("Unable to delete volume: %v", err)
-		return nil, err
+		return nil, errTypes.ToGRPCStatus(err)
 	}
 
 	if err = c.svc.WaitStatus(ctx, req.VolumeId, apiV1.Failed, apiV1.Removed); err != nil {
+		if errors.Is(err, errTypes.ErrOperationInProgress) {
+			return nil, errTypes.ToGRPCStatus(err)
+		}
 		// we might not get DeleteVolume request again. Volume CR will have to be removed manually in this case
 		return nil, status.Error(codes.Internal, "Unable to delete volume")
 	}
@@ -249,6 +657,8 @@ func (c *CSIControllerService) DeleteVolume(ctx context.Context, req *csi.Delete
 	c.reqMu.Lock()
 	c.svc.UpdateCRsAfterVolumeDeletion(ctxWithID, req.VolumeId)
 	c.reqMu.Unlock()
+	// Secret with image credentials remains if volume creation was interrupted
+	c.removeImageCredentials(ctx, req.VolumeId)
 
 	ll.Debug("Volume was successfully deleted")
 
@@ -256,7 +666,8 @@ func (c *CSIControllerService) DeleteVolume(ctx context.Context, req *csi.Delete
 }
 
 // ControllerPublishVolume is the implementation of CSI Spec ControllerPublishVolume. This method just checks existence
-// of provided Volume CR and returns success response if the Volume CR exists.
+// of provided Volume CR and returns success response if the Volume CR exists. It is called by external attacher
+// only in attach compatibility mode, node checks the same on NodeStageVolume.
 // Receives golang context and CSI Spec ControllerPublishVolumeRequest
 // Returns CSI Spec ControllerPublishVolumeResponse or error if something went wrong
 func (c *CSIControllerService) ControllerPublishVolume(ctx context.Context,
@@ -278,10 +689,20 @@ func (c *CSIControllerService) ControllerPublishVolume(ctx context.Context,
 		return nil, status.Error(codes.InvalidArgument, "ControllerPublishVolume: Volume capabilities"+
 			" must be provided")
 	}
-	if _, err := c.crHelper.GetVolumeByID(req.VolumeId); err != nil {
+	volume, err := c.crHelper.GetVolumeByID(ctx, req.VolumeId)
+	if err != nil {
 		ll.Errorf("k8s client can't read volume CR")
 		return nil, status.Error(codes.NotFound, "Volume is not found")
 	}
+	if volume.IsForceReleased() {
+		ll.Errorf("Volume was force released")
+		return nil, status.Error(codes.FailedPrecondition, "Volume was force released, node of the volume is removed")
+	}
+	// volumes are local, so they could be published only on the node where they are placed
+	if volume.Spec.NodeId != req.NodeId {
+		ll.Errorf("Volume is located on node %s, but requested node is %s", volume.Spec.NodeId, req.NodeId)
+		return nil, status.Error(codes.NotFound, "Volume is not accessible from node")
+	}
 
 	ll.Info("Return empty response, ok.")
 
@@ -308,9 +729,46 @@ func (c *CSIControllerService) ControllerUnpublishVolume(ctx context.Context,
 	return &csi.ControllerUnpublishVolumeResponse{}, nil
 }
 
-// ValidateVolumeCapabilities is not implemented yet
-func (c *CSIControllerService) ValidateVolumeCapabilities(context.Context, *csi.ValidateVolumeCapabilitiesRequest) (*csi.ValidateVolumeCapabilitiesResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "not implemented yet")
+// ValidateVolumeCapabilities is the implementation of CSI Spec ValidateVolumeCapabilities.
+// Volumes are local, so only single node access modes are confirmed
+// Receives golang context and CSI Spec ValidateVolumeCapabilitiesRequest
+// Returns CSI Spec ValidateVolumeCapabilitiesResponse or error if volume doesn't exist or request is invalid
+func (c *CSIControllerService) ValidateVolumeCapabilities(ctx context.Context,
+	req *csi.ValidateVolumeCapabilitiesRequest) (*csi.ValidateVolumeCapabilitiesResponse, error) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method":   "ValidateVolumeCapabilities",
+		"volumeID": req.GetVolumeId(),
+	})
+
+	if req.GetVolumeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID must be provided")
+	}
+	if len(req.GetVolumeCapabilities()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume capabilities must be provided")
+	}
+	if _, err := c.crHelper.GetVolumeByID(ctx, req.GetVolumeId()); err != nil {
+		ll.Errorf("k8s client can't read volume CR: %v", err)
+		return nil, status.Error(codes.NotFound, "Volume is not found")
+	}
+
+	for _, capability := range req.GetVolumeCapabilities() {
+		mode := capability.GetAccessMode().GetMode()
+		if mode != csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER &&
+			mode != csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY {
+			ll.Infof("Access mode %s isn't supported", mode)
+			return &csi.ValidateVolumeCapabilitiesResponse{
+				Message: fmt.Sprintf("access mode %s isn't supported", mode),
+			}, nil
+		}
+	}
+
+	return &csi.ValidateVolumeCapabilitiesResponse{
+		Confirmed: &csi.ValidateVolumeCapabilitiesResponse_Confirmed{
+			VolumeContext:      req.GetVolumeContext(),
+			VolumeCapabilities: req.GetVolumeCapabilities(),
+			Parameters:         req.GetParameters(),
+		},
+	}, nil
 }
 
 // ListVolumes is not implemented yet
@@ -324,7 +782,8 @@ func (c *CSIControllerService) GetCapacity(context.Context, *csi.GetCapacityRequ
 }
 
 // ControllerGetCapabilities is the implementation of CSI Spec ControllerGetCapabilities.
-// Provides Controller capabilities of CSI driver to k8s CREATE/DELETE Volume and PUBLISH/UNPUBLISH Volume for now.
+// Provides Controller capabilities of CSI driver to k8s CREATE/DELETE Volume and EXPAND Volume,
+// PUBLISH/UNPUBLISH Volume is provided only in attach compatibility mode.
 // Receives golang context and CSI Spec ControllerGetCapabilitiesRequest
 // Returns CSI Spec ControllerGetCapabilitiesResponse and nil error
 func (c *CSIControllerService) ControllerGetCapabilities(context.Context, *csi.ControllerGetCapabilitiesRequest) (*csi.ControllerGetCapabilitiesResponse, error) {
@@ -342,13 +801,16 @@ func (c *CSIControllerService) ControllerGetCapabilities(context.Context, *csi.C
 		}
 	}
 
-	caps := make([]*csi.ControllerServiceCapability, 0)
-	for _, c := range []csi.ControllerServiceCapability_RPC_Type{
+	types := []csi.ControllerServiceCapability_RPC_Type{
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
-		csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
-	} {
-		caps = append(caps, newCap(c))
+	}
+	if c.attachCompatibility {
+		types = append(types, csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME)
+	}
+	caps := make([]*csi.ControllerServiceCapability, 0, len(types))
+	for _, t := range types {
+		caps = append(caps, newCap(t))
 	}
 
 	resp := &csi.ControllerGetCapabilitiesResponse{
@@ -387,7 +849,7 @@ func (c *CSIControllerService) ControllerExpandVolume(ctx context.Context, req *
 		"method":   "ControllerExpandVolume",
 		"volumeID": req.GetVolumeId(),
 	})
-	ll.Infof("Processing request: %v", req)
+	ll.Infof("Processing request: %v", util.StripSecrets(req))
 	var (
 		volID         = req.GetVolumeId()
 		ctxWithID     = context.WithValue(context.Background(), base.RequestUUID, volID)
@@ -398,17 +860,20 @@ func (c *CSIControllerService) ControllerExpandVolume(ctx context.Context, req *
 		return nil, status.Error(codes.InvalidArgument, "Volume name missing in request")
 	}
 
-	volume, err := c.crHelper.GetVolumeByID(volID)
+	volume, err := c.crHelper.GetVolumeByID(ctx, volID)
 
 	if err != nil {
 		return nil, status.Error(codes.NotFound, "Volume doesn't exist")
 	}
 	if volume.Spec.Size == requiredBytes || volume.Spec.Size > requiredBytes {
 		return &csi.ControllerExpandVolumeResponse{
-			CapacityBytes:         0,
+			CapacityBytes:         volume.Spec.Size,
 			NodeExpansionRequired: false,
 		}, nil
 	}
+	if volume.Spec.Integrity == apiV1.IntegrityDMIntegrity {
+		return nil, status.Error(codes.FailedPrecondition, "Volume with dm-integrity protection can't be expanded")
+	}
 
 	c.reqMu.Lock()
 	err = c.svc.ExpandVolume(ctx, volume, requiredBytes)
@@ -419,6 +884,9 @@ func (c *CSIControllerService) ControllerExpandVolume(ctx context.Context, req *
 	}
 
 	err = c.svc.WaitStatus(ctxWithID, volID, apiV1.Failed, apiV1.Resized)
+	if errors.Is(err, errTypes.ErrOperationInProgress) {
+		return nil, errTypes.ToGRPCStatus(err)
+	}
 
 	c.reqMu.Lock()
 	c.svc.UpdateCRsAfterVolumeExpansion(ctx, volID, requiredBytes)
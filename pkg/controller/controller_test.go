@@ -36,20 +36,29 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgpolicycrd"
+	quotacrd "github.com/dell/csi-baremetal/api/v1/storagequotacrd"
 	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/dell/csi-baremetal/pkg/base/cache"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/common"
 	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	eventsMocks "github.com/dell/csi-baremetal/pkg/events/mocks"
 	"github.com/dell/csi-baremetal/pkg/testutils"
 )
 
@@ -153,6 +162,106 @@ var _ = Describe("CSIControllerService CreateVolume", func() {
 			Expect(err).NotTo(BeNil())
 			Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
 		})
+		It("Scratch volume without data remanence acknowledgement", func() {
+			req := getCreateVolumeRequest("req1", 1024*53, "")
+			req.Parameters[base.ScratchKey] = "true"
+
+			_, err := controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring(base.ScratchAllowDataRemanenceKey))
+
+			req.Parameters[base.ScratchAllowDataRemanenceKey] = "true"
+			req.Parameters[base.StorageTypeKey] = apiV1.StorageClassHDDLVG
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Invalid integrity protection", func() {
+			req := getCreateVolumeRequest("req1", 1024*53, "")
+			req.Parameters[base.IntegrityKey] = "raid"
+
+			_, err := controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			// metadata checksums aren't supported by xfs
+			req.Parameters[base.IntegrityKey] = apiV1.IntegrityChecksum
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("requires ext4"))
+		})
+		It("Invalid image source", func() {
+			req := getCreateVolumeRequest("req1", 1024*53, "")
+			req.Parameters[base.ImageSourceKey] = "ftp://images.local/mnist.tar"
+
+			_, err := controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			// image source of storage class isn't in the allowlist
+			Expect(controller.SetImageSourceAllowlist("oci://registry.local")).To(BeNil())
+			req.Parameters[base.ImageSourceKey] = "https://images.local/mnist.tar"
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(controller.SetImageSourceAllowlist("https://images.local/datasets")).NotTo(BeNil())
+		})
+		It("Aligned size of LVG volume exceeds limit", func() {
+			req := getCreateVolumeRequest("req1", capacityplanner.DefaultPESize+1, "")
+			req.Parameters[base.StorageTypeKey] = apiV1.StorageClassHDDLVG
+			req.CapacityRange.LimitBytes = capacityplanner.DefaultPESize + 1
+
+			_, err := controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.OutOfRange))
+		})
+		It("Invalid mkfs options", func() {
+			req := getCreateVolumeRequest("req1", 1024*53, "")
+			req.Parameters[base.MkFSOptionsKey] = "-f -m reflink=1"
+
+			_, err := controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			// options aren't supported for block volumes
+			req.Parameters[base.MkFSOptionsKey] = "-m reflink=1"
+			req.VolumeCapabilities[0].AccessType = &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}}
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Invalid format profile", func() {
+			req := getCreateVolumeRequest("req1", 1024*53, "")
+			req.Parameters[base.FormatProfileKey] = "database"
+
+			_, err := controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			// profiles aren't supported for block volumes
+			req.Parameters[base.FormatProfileKey] = apiV1.FormatProfileHugepage
+			req.VolumeCapabilities[0].AccessType = &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}}
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Invalid read cache", func() {
+			req := getCreateVolumeRequest("req1", 1024*53, "")
+			for _, size := range []string{"4GB", "0", "-1Gi"} {
+				req.Parameters[base.ReadCacheKey] = size
+				_, err := controller.CreateVolume(testCtx, req)
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument), size)
+			}
+
+			// read cache isn't supported for block volumes
+			req.Parameters[base.ReadCacheKey] = "1Gi"
+			req.VolumeCapabilities[0].AccessType = &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}}
+			_, err := controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Invalid I/O tuning", func() {
+			req := getCreateVolumeRequest("req1", 1024*53, "")
+			req.Parameters[base.IOSchedulerKey] = "cfq"
+
+			_, err := controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			req.Parameters[base.IOSchedulerKey] = "mq-deadline"
+			req.Parameters[base.NrRequestsKey] = "-1"
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
 		It("Status Failed was set in Volume CR", func() {
 			err := testutils.AddAC(controller.k8sclient, &testAC1, &testAC2)
 			Expect(err).To(BeNil())
@@ -200,6 +309,57 @@ var _ = Describe("CSIControllerService CreateVolume", func() {
 			Expect(err).To(BeNil())
 			Expect(v.Spec.CSIStatus).To(Equal(apiV1.Failed))
 		})
+		It("Volume CR has already exists with incompatible size", func() {
+			uuid := "uuid-1234"
+			req := getCreateVolumeRequest(uuid, int64(1024*100), testNode1Name)
+			err := controller.k8sclient.CreateCR(context.Background(), req.GetName(), &vcrd.Volume{
+				ObjectMeta: k8smetav1.ObjectMeta{Name: uuid, Namespace: "default"},
+				Spec: api.Volume{
+					Id:        req.GetName(),
+					Size:      1024 * 60,
+					NodeId:    testNode1Name,
+					CSIStatus: apiV1.Created,
+				}})
+			Expect(err).To(BeNil())
+
+			resp, err := controller.CreateVolume(context.Background(), req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+		})
+		It("Storage quota of the namespace is exceeded", func() {
+			err := testutils.AddAC(controller.k8sclient, &testAC1, &testAC2)
+			Expect(err).To(BeNil())
+			quota := &quotacrd.StorageQuota{
+				ObjectMeta: k8smetav1.ObjectMeta{Name: "quota", Namespace: testNs},
+				Spec:       api.StorageQuota{Bytes: 1024 * 100, Volumes: 2},
+			}
+			Expect(controller.k8sclient.CreateCR(testCtx, quota.Name, quota)).To(BeNil())
+			Expect(controller.k8sclient.CreateCR(testCtx, "existing", &vcrd.Volume{
+				ObjectMeta: k8smetav1.ObjectMeta{Name: "existing", Namespace: testNs},
+				Spec:       api.Volume{Id: "existing", Size: 1024 * 60, CSIStatus: apiV1.Published},
+			})).To(BeNil())
+			pvc := &v1.PersistentVolumeClaim{ObjectMeta: k8smetav1.ObjectMeta{Name: "pvc", Namespace: testNs}}
+			Expect(controller.k8sclient.CreateCR(testCtx, pvc.Name, pvc)).To(BeNil())
+			recorder := new(eventsMocks.EventRecorder)
+			recorder.On("Eventf", mock.Anything, eventing.WarningType, eventing.StorageQuotaExceeded,
+				"%s", mock.Anything).Return()
+			controller.SetEventRecorder(recorder)
+
+			req := getCreateVolumeRequest("req1", 1024*53, testNode1Name)
+			req.Parameters[base.PVCNameKey] = pvc.Name
+			resp, err := controller.CreateVolume(testCtx, req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+			Expect(err.Error()).To(ContainSubstring("storage quota quota of namespace default is exceeded"))
+			recorder.AssertNumberOfCalls(GinkgoT(), "Eventf", 1)
+
+			// volume count limit
+			quota.Spec = api.StorageQuota{Volumes: 1}
+			Expect(controller.k8sclient.UpdateCR(testCtx, quota)).To(BeNil())
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+			Expect(err.Error()).To(ContainSubstring("1 of 1 volumes are used"))
+		})
 	})
 
 	Context("Success scenarios", func() {
@@ -222,6 +382,129 @@ var _ = Describe("CSIControllerService CreateVolume", func() {
 			Expect(err).To(BeNil())
 			Expect(vol.Spec.CSIStatus).To(Equal(apiV1.Created))
 		})
+		It("Deadline is exceeded while volume is created", func() {
+			err := testutils.AddAC(controller.k8sclient, &testAC1, &testAC2)
+			Expect(err).To(BeNil())
+			var (
+				req = getCreateVolumeRequest("req1", 1024*53, testNode1Name)
+				vol = &vcrd.Volume{}
+			)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			_, err = controller.CreateVolume(ctx, req)
+			Expect(status.Code(err)).To(Equal(codes.DeadlineExceeded))
+			Expect(err.Error()).To(ContainSubstring(errTypes.ErrOperationInProgress.Error()))
+			err = controller.k8sclient.ReadCR(context.Background(), "req1", testNs, vol)
+			Expect(err).To(BeNil())
+			Expect(vol.Spec.CSIStatus).To(Equal(apiV1.Creating))
+			// fake client doesn't set creation timestamp
+			vol.CreationTimestamp = k8smetav1.Now()
+			Expect(controller.k8sclient.UpdateCR(context.Background(), vol)).To(BeNil())
+
+			// repeated request returns result of the operation
+			go testutils.VolumeReconcileImitation(controller.k8sclient, "req1", testNs, apiV1.Created)
+			resp, err := controller.CreateVolume(context.Background(), req)
+			Expect(err).To(BeNil())
+			Expect(resp).ToNot(BeNil())
+		})
+		It("Volume is created within storage quota", func() {
+			err := testutils.AddAC(controller.k8sclient, &testAC1, &testAC2)
+			Expect(err).To(BeNil())
+			quota := &quotacrd.StorageQuota{
+				ObjectMeta: k8smetav1.ObjectMeta{Name: "quota", Namespace: testNs},
+				Spec:       api.StorageQuota{Bytes: 1024 * 100, Volumes: 2},
+			}
+			Expect(controller.k8sclient.CreateCR(testCtx, quota.Name, quota)).To(BeNil())
+			// volume of another namespace isn't counted
+			Expect(controller.k8sclient.CreateCR(testCtx, "foreign", &vcrd.Volume{
+				ObjectMeta: k8smetav1.ObjectMeta{Name: "foreign", Namespace: "foreign"},
+				Spec:       api.Volume{Id: "foreign", Size: 1024 * 60, CSIStatus: apiV1.Published},
+			})).To(BeNil())
+
+			go testutils.VolumeReconcileImitation(controller.k8sclient, "req1", testNs, apiV1.Created)
+			_, err = controller.CreateVolume(testCtx, getCreateVolumeRequest("req1", 1024*53, testNode1Name))
+			Expect(err).To(BeNil())
+		})
+		It("Scratch volume is created", func() {
+			err := testutils.AddAC(controller.k8sclient, &testAC1, &testAC2)
+			Expect(err).To(BeNil())
+			req := getCreateVolumeRequest("req1", 1024*53, testNode1Name)
+			req.Parameters[base.ScratchKey] = "true"
+			req.Parameters[base.ScratchAllowDataRemanenceKey] = "true"
+
+			go testutils.VolumeReconcileImitation(controller.k8sclient, "req1", testNs, apiV1.Created)
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(err).To(BeNil())
+
+			vol := &vcrd.Volume{}
+			Expect(controller.k8sclient.ReadCR(testCtx, "req1", testNs, vol)).To(BeNil())
+			Expect(vol.Spec.Scratch).To(BeTrue())
+		})
+		It("Volume with dm-integrity protection is created", func() {
+			err := testutils.AddAC(controller.k8sclient, &testAC1, &testAC2)
+			Expect(err).To(BeNil())
+			req := getCreateVolumeRequest("req1", 1024*53, testNode1Name)
+			req.Parameters[base.IntegrityKey] = apiV1.IntegrityDMIntegrity
+
+			go testutils.VolumeReconcileImitation(controller.k8sclient, "req1", testNs, apiV1.Created)
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(err).To(BeNil())
+
+			vol := &vcrd.Volume{}
+			Expect(controller.k8sclient.ReadCR(testCtx, "req1", testNs, vol)).To(BeNil())
+			Expect(vol.Spec.Integrity).To(Equal(apiV1.IntegrityDMIntegrity))
+		})
+		It("Volume with mkfs options is created", func() {
+			err := testutils.AddAC(controller.k8sclient, &testAC1, &testAC2)
+			Expect(err).To(BeNil())
+			req := getCreateVolumeRequest("req1", 1024*53, testNode1Name)
+			req.Parameters[base.MkFSOptionsKey] = "-m reflink=1"
+			req.Parameters[base.FormatProfileKey] = apiV1.FormatProfileAligned
+
+			go testutils.VolumeReconcileImitation(controller.k8sclient, "req1", testNs, apiV1.Created)
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(err).To(BeNil())
+
+			vol := &vcrd.Volume{}
+			Expect(controller.k8sclient.ReadCR(testCtx, "req1", testNs, vol)).To(BeNil())
+			Expect(vol.Spec.MkFSOptions).To(Equal("-m reflink=1"))
+			Expect(vol.Spec.FormatProfile).To(Equal(apiV1.FormatProfileAligned))
+		})
+		It("Volume is populated from image of PVC annotation", func() {
+			err := testutils.AddAC(controller.k8sclient, &testAC1, &testAC2)
+			Expect(err).To(BeNil())
+			pvc := &v1.PersistentVolumeClaim{
+				ObjectMeta: k8smetav1.ObjectMeta{Name: "pvc", Namespace: testNs,
+					Annotations: map[string]string{base.PVCAnnotationImageSource: "oci://registry.local/datasets/mnist:v2"}},
+			}
+			Expect(controller.k8sclient.CreateCR(testCtx, pvc.Name, pvc)).To(BeNil())
+			req := getCreateVolumeRequest("req1", 1024*53, testNode1Name)
+			req.Parameters[base.ImageSourceKey] = "https://images.local/mnist.tar.gz"
+			req.Parameters[base.PVCNameKey] = pvc.Name
+
+			// storage class doesn't allow override
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring(base.ImageSourceOverrideKey))
+
+			// image source of PVC isn't in the allowlist
+			req.Parameters[base.ImageSourceOverrideKey] = "true"
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(controller.SetImageSourceAllowlist("https://images.local")).To(BeNil())
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			Expect(controller.SetImageSourceAllowlist("https://images.local,oci://registry.local")).To(BeNil())
+			go testutils.VolumeReconcileImitation(controller.k8sclient, "req1", testNs, apiV1.Created)
+			_, err = controller.CreateVolume(testCtx, req)
+			Expect(err).To(BeNil())
+
+			vol := &vcrd.Volume{}
+			Expect(controller.k8sclient.ReadCR(testCtx, "req1", testNs, vol)).To(BeNil())
+			Expect(vol.Spec.ImageSource).To(Equal("oci://registry.local/datasets/mnist:v2"))
+		})
 		It("Volume CR has already exists", func() {
 			uuid := "uuid-1234"
 			capacity := int64(1024 * 42)
@@ -246,7 +529,282 @@ var _ = Describe("CSIControllerService CreateVolume", func() {
 			Expect(err).To(BeNil())
 			Expect(resp.Volume.VolumeId).To(Equal(uuid))
 			Expect(resp.Volume.CapacityBytes).To(Equal(int64(1024 * 60)))
+			Expect(resp.Volume.VolumeContext).ToNot(HaveKey(base.NUMANodeKey))
 		})
+		It("Volume context contains NUMA hint", func() {
+			uuid := "uuid-1234"
+			featureConf := featureconfig.NewFeatureConfig()
+			featureConf.Update(featureconfig.FeatureNUMAHint, true)
+			controller = NewControllerService(controller.k8sclient, testLogger, featureConf)
+
+			drive := controller.k8sclient.ConstructDriveCR(testDriveLocation1,
+				api.Drive{UUID: testDriveLocation1, NodeId: testNode1Name, NUMANode: "1"})
+			err := controller.k8sclient.CreateCR(testCtx, drive.Name, drive)
+			Expect(err).To(BeNil())
+
+			req := getCreateVolumeRequest(uuid, 1024*42, testNode1Name)
+			err = controller.k8sclient.CreateCR(testCtx, req.GetName(), &vcrd.Volume{
+				ObjectMeta: k8smetav1.ObjectMeta{
+					Name:              uuid,
+					Namespace:         testNs,
+					CreationTimestamp: k8smetav1.Time{Time: time.Now()},
+				},
+				Spec: api.Volume{
+					Id:           req.GetName(),
+					Size:         1024 * 60,
+					NodeId:       testNode1Name,
+					Location:     testDriveLocation1,
+					LocationType: apiV1.LocationTypeDrive,
+					CSIStatus:    apiV1.Created,
+				}})
+			Expect(err).To(BeNil())
+
+			resp, err := controller.CreateVolume(testCtx, req)
+			Expect(err).To(BeNil())
+			Expect(resp.Volume.VolumeContext[base.NUMANodeKey]).To(Equal("1"))
+			Expect(resp.Volume.VolumeContext[base.PVCNamespaceKey]).To(Equal(testNs))
+		})
+		It("Volume context contains NUMA hint of LogicalVolumeGroup drives", func() {
+			featureConf := featureconfig.NewFeatureConfig()
+			featureConf.Update(featureconfig.FeatureNUMAHint, true)
+			controller = NewControllerService(controller.k8sclient, testLogger, featureConf)
+
+			for _, location := range []string{testDriveLocation1, testDriveLocation2} {
+				drive := controller.k8sclient.ConstructDriveCR(location,
+					api.Drive{UUID: location, NodeId: testNode1Name, NUMANode: "1"})
+				Expect(controller.k8sclient.CreateCR(testCtx, drive.Name, drive)).To(BeNil())
+			}
+			lvg := controller.k8sclient.ConstructLVGCR("lvg-1", api.LogicalVolumeGroup{
+				Name: "lvg-1", Node: testNode1Name, Locations: []string{testDriveLocation1, testDriveLocation2}})
+			Expect(controller.k8sclient.CreateCR(testCtx, lvg.Name, lvg)).To(BeNil())
+
+			vol := &api.Volume{Id: "uuid-1234", Location: "lvg-1", LocationType: apiV1.LocationTypeLVM}
+			volumeContext := controller.addNUMAHint(testCtx, map[string]string{}, vol)
+			Expect(volumeContext[base.NUMANodeKey]).To(Equal("1"))
+
+			// drives of LogicalVolumeGroup are on different NUMA nodes
+			drive := &drivecrd.Drive{}
+			Expect(controller.k8sclient.ReadCR(testCtx, testDriveLocation2, "", drive)).To(BeNil())
+			drive.Spec.NUMANode = "0"
+			Expect(controller.k8sclient.UpdateCR(testCtx, drive)).To(BeNil())
+			volumeContext = controller.addNUMAHint(testCtx, map[string]string{}, vol)
+			Expect(volumeContext).ToNot(HaveKey(base.NUMANodeKey))
+
+			// LogicalVolumeGroup doesn't exist
+			vol.Location = "unknown-lvg"
+			volumeContext = controller.addNUMAHint(testCtx, map[string]string{}, vol)
+			Expect(volumeContext).ToNot(HaveKey(base.NUMANodeKey))
+		})
+	})
+})
+
+var _ = Describe("CSIControllerService CreateVolume PVC metadata", func() {
+	var controller *CSIControllerService
+
+	BeforeEach(func() {
+		controller = newSvc()
+		fillCache(controller, "pvc-volume", testNs)
+		Expect(testutils.AddAC(controller.k8sclient, &testAC1)).To(BeNil())
+		pvc := &v1.PersistentVolumeClaim{
+			ObjectMeta: k8smetav1.ObjectMeta{Name: "pvc", Namespace: testNs,
+				Labels:      map[string]string{"app": "db", "team": "storage"},
+				Annotations: map[string]string{"owner": "alice", "note": "skipped", apiV1.VolumeAnnotationExport: "true"}},
+		}
+		Expect(controller.k8sclient.CreateCR(testCtx, pvc.Name, pvc)).To(BeNil())
+	})
+
+	It("Selected labels and annotations are propagated", func() {
+		// internal annotation of Volume CR isn't propagated
+		controller.SetPVCMetadataKeys([]string{"app", "owner", "missing", apiV1.VolumeAnnotationExport})
+		req := getCreateVolumeRequest("pvc-volume", 1024*53, "")
+		req.Parameters[base.PVCNameKey] = "pvc"
+
+		go testutils.VolumeReconcileImitation(controller.k8sclient, "pvc-volume", testNs, apiV1.Created)
+		resp, err := controller.CreateVolume(testCtx, req)
+		Expect(err).To(BeNil())
+		Expect(resp.Volume.VolumeContext).To(HaveKeyWithValue(base.PVCMetadataKeyPrefix+"app", "db"))
+		Expect(resp.Volume.VolumeContext).To(HaveKeyWithValue(base.PVCMetadataKeyPrefix+"owner", "alice"))
+		Expect(resp.Volume.VolumeContext).NotTo(HaveKey(base.PVCMetadataKeyPrefix + "team"))
+		Expect(resp.Volume.VolumeContext).NotTo(HaveKey(base.PVCMetadataKeyPrefix + apiV1.VolumeAnnotationExport))
+		Expect(req.Parameters).NotTo(HaveKey(base.PVCMetadataKeyPrefix + "app"))
+
+		vol := &vcrd.Volume{}
+		Expect(controller.k8sclient.ReadCR(testCtx, "pvc-volume", testNs, vol)).To(BeNil())
+		Expect(vol.Labels).To(Equal(map[string]string{"app": "db"}))
+		Expect(vol.Annotations).To(Equal(map[string]string{"owner": "alice"}))
+	})
+
+	It("Metadata isn't propagated if keys aren't selected", func() {
+		req := getCreateVolumeRequest("pvc-volume", 1024*53, "")
+		req.Parameters[base.PVCNameKey] = "pvc"
+
+		go testutils.VolumeReconcileImitation(controller.k8sclient, "pvc-volume", testNs, apiV1.Created)
+		resp, err := controller.CreateVolume(testCtx, req)
+		Expect(err).To(BeNil())
+		Expect(resp.Volume.VolumeContext).NotTo(HaveKey(base.PVCMetadataKeyPrefix + "app"))
+
+		vol := &vcrd.Volume{}
+		Expect(controller.k8sclient.ReadCR(testCtx, "pvc-volume", testNs, vol)).To(BeNil())
+		Expect(vol.Labels).To(BeEmpty())
+	})
+})
+
+var _ = Describe("CSIControllerService CreateVolume image credentials", func() {
+	var controller *CSIControllerService
+
+	BeforeEach(func() {
+		controller = newSvc()
+		fillCache(controller, "image-volume", testNs)
+		Expect(testutils.AddAC(controller.k8sclient, &testAC1)).To(BeNil())
+	})
+
+	It("Inconsistent credentials are rejected", func() {
+		req := getCreateVolumeRequest("image-volume", 1024*53, "")
+		req.Parameters[base.ImageSourceKey] = "oci://registry.local/datasets/private:v1"
+		req.Secrets = map[string]string{base.ImageUsernameSecretKey: "user", base.ImageTokenSecretKey: "token"}
+
+		_, err := controller.CreateVolume(testCtx, req)
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("Credentials are passed to node through Secret", func() {
+		req := getCreateVolumeRequest("image-volume", 1024*53, "")
+		req.Parameters[base.ImageSourceKey] = "oci://registry.local/datasets/private:v1"
+		req.Secrets = map[string]string{base.ImageUsernameSecretKey: "user", base.ImagePasswordSecretKey: "secret"}
+
+		stored := make(chan *v1.Secret, 1)
+		go func() {
+			defer GinkgoRecover()
+			vol := &vcrd.Volume{}
+			Expect(controller.k8sclient.ReadCRWithAttempts(testCtx, "image-volume", testNs, vol, 10)).To(BeNil())
+			secret := &v1.Secret{}
+			Expect(controller.k8sclient.ReadCR(testCtx, vol.Spec.ImageSecret, "", secret)).To(BeNil())
+			stored <- secret
+			testutils.VolumeReconcileImitation(controller.k8sclient, "image-volume", testNs, apiV1.Created)
+		}()
+		_, err := controller.CreateVolume(testCtx, req)
+		Expect(err).To(BeNil())
+		Expect((<-stored).Data).To(Equal(map[string][]byte{
+			base.ImageUsernameSecretKey: []byte("user"),
+			base.ImagePasswordSecretKey: []byte("secret"),
+		}))
+
+		// Secret is removed when volume is populated
+		err = controller.k8sclient.ReadCR(testCtx, base.ImageSecretPrefix+"image-volume", "", &v1.Secret{})
+		Expect(k8sError.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("CSIControllerService CreateVolume pinned to drive", func() {
+	var controller *CSIControllerService
+
+	newDrive := func(uuid, sn, node string, driveLabels map[string]string) *drivecrd.Drive {
+		drive := controller.k8sclient.ConstructDriveCR(uuid, api.Drive{
+			UUID:         uuid,
+			SerialNumber: sn,
+			NodeId:       node,
+			Health:       apiV1.HealthGood,
+			Status:       apiV1.DriveStatusOnline,
+			Usage:        apiV1.DriveUsageInUse,
+			Type:         apiV1.DriveTypeHDD,
+		})
+		drive.Labels = driveLabels
+		return drive
+	}
+
+	BeforeEach(func() {
+		controller = newSvc()
+		Expect(testutils.AddAC(controller.k8sclient, &testAC1, &testAC2)).To(BeNil())
+		for _, drive := range []*drivecrd.Drive{
+			newDrive(testDriveLocation1, "sn1", testNode1Name, nil),
+			newDrive(testDriveLocation2, "sn2", testNode2Name, map[string]string{"optane": "true"}),
+		} {
+			Expect(controller.k8sclient.CreateCR(testCtx, drive.Name, drive)).To(BeNil())
+		}
+	})
+
+	It("Volume is placed on pinned drive", func() {
+		req := getCreateVolumeRequest("pinned", 1024*53, "")
+		req.Parameters[base.PinnedDriveKey] = "SN2"
+
+		go testutils.VolumeReconcileImitation(controller.k8sclient, "pinned", testNs, apiV1.Created)
+		_, err := controller.CreateVolume(testCtx, req)
+		Expect(err).To(BeNil())
+
+		vol := &vcrd.Volume{}
+		Expect(controller.k8sclient.ReadCR(testCtx, "pinned", testNs, vol)).To(BeNil())
+		Expect(vol.Spec.Location).To(Equal(testDriveLocation2))
+	})
+
+	It("PVC annotation overrides StorageClass parameter", func() {
+		pvc := &v1.PersistentVolumeClaim{
+			ObjectMeta: k8smetav1.ObjectMeta{Name: "pvc", Namespace: testNs,
+				Annotations: map[string]string{base.PVCAnnotationPinnedDriveLabel: "optane=true"}},
+		}
+		Expect(controller.k8sclient.CreateCR(testCtx, pvc.Name, pvc)).To(BeNil())
+		req := getCreateVolumeRequest("pinned", 1024*53, "")
+		req.Parameters[base.PinnedDriveKey] = "sn1"
+		req.Parameters[base.PVCNameKey] = pvc.Name
+
+		locations, err := controller.driveLocations(testCtx, req.Parameters, apiV1.StorageClassHDD, "")
+		Expect(err).To(BeNil())
+		Expect(locations).To(Equal([]string{testDriveLocation2}))
+	})
+
+	It("Pinned drive doesn't exist", func() {
+		req := getCreateVolumeRequest("pinned", 1024*53, "")
+		req.Parameters[base.PinnedDriveKey] = "unknown"
+
+		_, err := controller.CreateVolume(testCtx, req)
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("Pinned drive isn't placed on preferred node", func() {
+		req := getCreateVolumeRequest("pinned", 1024*53, testNode1Name)
+		req.Parameters[base.PinnedDriveKey] = "sn2"
+
+		_, err := controller.CreateVolume(testCtx, req)
+		Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+	})
+
+	It("Pinned drive type doesn't match storage class", func() {
+		_, err := controller.driveLocations(testCtx,
+			map[string]string{base.PinnedDriveKey: "sn1"}, apiV1.StorageClassSSDLVG, "")
+		Expect(errors.Is(err, errTypes.ErrCapacityExhausted)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("doesn't match storage class"))
+	})
+
+	It("Volume is placed on drive matching drive selector", func() {
+		req := getCreateVolumeRequest("selected", 1024*53, "")
+		req.Parameters[base.DriveSelectorKey] = "optane in (true)"
+
+		go testutils.VolumeReconcileImitation(controller.k8sclient, "selected", testNs, apiV1.Created)
+		_, err := controller.CreateVolume(testCtx, req)
+		Expect(err).To(BeNil())
+
+		vol := &vcrd.Volume{}
+		Expect(controller.k8sclient.ReadCR(testCtx, "selected", testNs, vol)).To(BeNil())
+		Expect(vol.Spec.Location).To(Equal(testDriveLocation2))
+	})
+
+	It("Drive selector doesn't match any drive", func() {
+		_, err := controller.driveLocations(testCtx,
+			map[string]string{base.DriveSelectorKey: "rack=r2"}, apiV1.StorageClassHDD, "")
+		Expect(errors.Is(err, errTypes.ErrCapacityExhausted)).To(BeTrue())
+	})
+
+	It("Invalid drive selector", func() {
+		_, err := controller.driveLocations(testCtx,
+			map[string]string{base.DriveSelectorKey: "rack in ("}, apiV1.StorageClassHDD, "")
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("Pinned drive doesn't match drive selector", func() {
+		_, err := controller.driveLocations(testCtx,
+			map[string]string{base.PinnedDriveKey: "sn1", base.DriveSelectorKey: "optane=true"},
+			apiV1.StorageClassHDD, "")
+		Expect(errors.Is(err, errTypes.ErrCapacityExhausted)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("doesn't match drive selector"))
 	})
 })
 
@@ -462,7 +1020,6 @@ var _ = Describe("CSIControllerService ControllerGetCapabilities", func() {
 			err                       error
 			expectedCapabilitiesTypes = []csi.ControllerServiceCapability_RPC_Type{
 				csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
-				csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
 				csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 			}
 		)
@@ -479,6 +1036,15 @@ var _ = Describe("CSIControllerService ControllerGetCapabilities", func() {
 		}
 		Expect(expectedCapabilitiesTypes).To(ConsistOf(currentCapabilitiesTypes))
 	})
+	It("Should return publish capability in attach compatibility mode", func() {
+		svc := newSvc()
+		svc.SetAttachCompatibility(true)
+
+		caps, err := svc.ControllerGetCapabilities(context.Background(), &csi.ControllerGetCapabilitiesRequest{})
+		Expect(err).To(BeNil())
+		Expect(caps.Capabilities).To(HaveLen(3))
+		Expect(caps.Capabilities[2].GetRpc().GetType()).To(Equal(csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME))
+	})
 })
 
 var _ = Describe("CSIControllerService health check", func() {
@@ -555,6 +1121,409 @@ var _ = Describe("CSIControllerService health check", func() {
 	})
 })
 
+var _ = Describe("CSIControllerService ControllerPublishVolume", func() {
+	var (
+		controller *CSIControllerService
+		capability = &csi.VolumeCapability{
+			AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+		}
+	)
+
+	BeforeEach(func() {
+		controller = newSvc()
+		volume := testVolume
+		err := controller.k8sclient.CreateCR(testCtx, volume.Name, &volume)
+		Expect(err).To(BeNil())
+	})
+
+	It("Volume is published on its node", func() {
+		resp, err := controller.ControllerPublishVolume(testCtx, &csi.ControllerPublishVolumeRequest{
+			VolumeId:         testVolume.Spec.Id,
+			NodeId:           testVolume.Spec.NodeId,
+			VolumeCapability: capability,
+		})
+		Expect(err).To(BeNil())
+		Expect(resp).NotTo(BeNil())
+	})
+	It("Volume isn't accessible from another node", func() {
+		resp, err := controller.ControllerPublishVolume(testCtx, &csi.ControllerPublishVolumeRequest{
+			VolumeId:         testVolume.Spec.Id,
+			NodeId:           "another-node",
+			VolumeCapability: capability,
+		})
+		Expect(resp).To(BeNil())
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+	It("Volume was force released", func() {
+		volume := &vcrd.Volume{}
+		Expect(controller.k8sclient.ReadCR(testCtx, testVolume.Name, testNs, volume)).To(BeNil())
+		volume.Annotations = map[string]string{
+			apiV1.VolumeAnnotationForceReleaseStatus: apiV1.VolumeAnnotationForceReleaseDone}
+		Expect(controller.k8sclient.UpdateCR(testCtx, volume)).To(BeNil())
+
+		resp, err := controller.ControllerPublishVolume(testCtx, &csi.ControllerPublishVolumeRequest{
+			VolumeId:         testVolume.Spec.Id,
+			NodeId:           testVolume.Spec.NodeId,
+			VolumeCapability: capability,
+		})
+		Expect(resp).To(BeNil())
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+	})
+})
+
+var _ = Describe("CSIControllerService reclaimOrphanedVolumes", func() {
+	var (
+		controller  *CSIControllerService
+		orphans     map[string]time.Time
+		gracePeriod = time.Hour
+		now         = time.Now()
+	)
+
+	BeforeEach(func() {
+		controller = newSvc()
+		fillCache(controller, testID, testNs)
+		orphans = make(map[string]time.Time)
+		volume := testVolume
+		volume.Spec.CSIStatus = apiV1.Created
+		volume.Spec.Location = testDriveLocation1
+		Expect(controller.k8sclient.CreateCR(testCtx, volume.Name, &volume)).To(BeNil())
+		ac := testAC1
+		Expect(controller.k8sclient.CreateCR(testCtx, ac.Name, &ac)).To(BeNil())
+	})
+
+	readVolume := func() *vcrd.Volume {
+		volume := &vcrd.Volume{}
+		Expect(controller.k8sclient.ReadCR(testCtx, testID, testNs, volume)).To(BeNil())
+		return volume
+	}
+
+	It("Volume with PV isn't orphaned", func() {
+		pv := &v1.PersistentVolume{ObjectMeta: k8smetav1.ObjectMeta{Name: testID}}
+		Expect(controller.k8sclient.CreateCR(testCtx, pv.Name, pv)).To(BeNil())
+		controller.reclaimOrphanedVolumes(testCtx, orphans, gracePeriod, now.Add(2*gracePeriod))
+		Expect(orphans).To(BeEmpty())
+		Expect(readVolume().Spec.CSIStatus).To(Equal(apiV1.Created))
+	})
+	It("Orphaned volume is reclaimed after grace period", func() {
+		controller.reclaimOrphanedVolumes(testCtx, orphans, gracePeriod, now)
+		Expect(orphans).To(HaveKey(testID))
+		controller.reclaimOrphanedVolumes(testCtx, orphans, gracePeriod, now.Add(gracePeriod/2))
+		Expect(readVolume().Spec.CSIStatus).To(Equal(apiV1.Created))
+
+		controller.reclaimOrphanedVolumes(testCtx, orphans, gracePeriod, now.Add(gracePeriod))
+		volume := readVolume()
+		Expect(volume.Spec.CSIStatus).To(Equal(apiV1.Removing))
+
+		// node removed the volume
+		volume.Spec.CSIStatus = apiV1.Removed
+		Expect(controller.k8sclient.UpdateCR(testCtx, volume)).To(BeNil())
+		controller.reclaimOrphanedVolumes(testCtx, orphans, gracePeriod, now.Add(gracePeriod))
+		err := controller.k8sclient.ReadCR(testCtx, testID, testNs, &vcrd.Volume{})
+		Expect(k8sError.IsNotFound(err)).To(BeTrue())
+		ac := &accrd.AvailableCapacity{}
+		Expect(controller.k8sclient.ReadCR(testCtx, testAC1Name, testNs, ac)).To(BeNil())
+		Expect(ac.Spec.Size).To(Equal(testAC1.Spec.Size + testVolume.Spec.Size))
+
+		controller.reclaimOrphanedVolumes(testCtx, orphans, gracePeriod, now.Add(gracePeriod))
+		Expect(orphans).To(BeEmpty())
+	})
+	It("Published volume isn't reclaimed", func() {
+		volume := readVolume()
+		volume.Spec.CSIStatus = apiV1.Published
+		Expect(controller.k8sclient.UpdateCR(testCtx, volume)).To(BeNil())
+		controller.reclaimOrphanedVolumes(testCtx, orphans, gracePeriod, now)
+		controller.reclaimOrphanedVolumes(testCtx, orphans, gracePeriod, now.Add(gracePeriod))
+		Expect(readVolume().Spec.CSIStatus).To(Equal(apiV1.Published))
+	})
+	It("Janitor reclaims orphaned volume by clock", func() {
+		fakeClock := clock.NewFakeClock(now)
+		controller.clock = fakeClock
+		go controller.RunOrphanedVolumesJanitor(gracePeriod)
+
+		Eventually(fakeClock.HasWaiters).Should(BeTrue())
+		fakeClock.Step(orphanedVolumesCheckInterval)
+		Eventually(fakeClock.HasWaiters).Should(BeTrue())
+		Expect(readVolume().Spec.CSIStatus).To(Equal(apiV1.Created))
+
+		fakeClock.Step(gracePeriod)
+		Eventually(func() string { return readVolume().Spec.CSIStatus }).Should(Equal(apiV1.Removing))
+	})
+})
+
+var _ = Describe("CSIControllerService reconcileLVGPolicies", func() {
+	var (
+		controller *CSIControllerService
+		driveSize  = int64(1024 * 1024 * 1024 * 100)
+		policy     = &lvgpolicycrd.LVGPolicy{
+			TypeMeta:   k8smetav1.TypeMeta{Kind: "LVGPolicy", APIVersion: apiV1.APIV1Version},
+			ObjectMeta: k8smetav1.ObjectMeta{Name: "hdd-policy"},
+			Spec:       api.LVGPolicy{StorageClass: apiV1.StorageClassHDDLVG, DriveSelector: "pool=lvg"},
+		}
+		poolLabels = map[string]string{"pool": "lvg"}
+	)
+
+	createDrive := func(uuid string, size int64, driveLabels map[string]string) {
+		drive := controller.k8sclient.ConstructDriveCR(uuid, api.Drive{
+			UUID:   uuid,
+			NodeId: testNode1Name,
+			Size:   size,
+			Health: apiV1.HealthGood,
+			Status: apiV1.DriveStatusOnline,
+			Usage:  apiV1.DriveUsageInUse,
+			Type:   apiV1.DriveTypeHDD,
+		})
+		drive.Labels = driveLabels
+		Expect(controller.k8sclient.CreateCR(testCtx, drive.Name, drive)).To(BeNil())
+		ac := controller.k8sclient.ConstructACCR(uuid+"-ac", api.AvailableCapacity{
+			Location:     uuid,
+			NodeId:       testNode1Name,
+			StorageClass: apiV1.StorageClassHDD,
+			Size:         size,
+		})
+		Expect(controller.k8sclient.CreateCR(testCtx, ac.Name, ac)).To(BeNil())
+	}
+
+	readLVGs := func() []lvgcrd.LogicalVolumeGroup {
+		lvgs := &lvgcrd.LogicalVolumeGroupList{}
+		Expect(controller.k8sclient.ReadList(testCtx, lvgs)).To(BeNil())
+		return lvgs.Items
+	}
+
+	acSize := func(location string) int64 {
+		ac, err := controller.crHelper.GetACByLocation(testCtx, location)
+		Expect(err).To(BeNil())
+		return ac.Spec.Size
+	}
+
+	BeforeEach(func() {
+		controller = newSvc()
+		p := policy.DeepCopy()
+		Expect(controller.k8sclient.CreateCR(testCtx, p.Name, p)).To(BeNil())
+	})
+
+	It("Matching drives are combined into LogicalVolumeGroup", func() {
+		createDrive("drive-b", driveSize, poolLabels)
+		createDrive("drive-a", driveSize, poolLabels)
+		createDrive("drive-c", driveSize, nil)
+
+		Expect(controller.reconcileLVGPolicies(testCtx)).To(BeNil())
+		lvgs := readLVGs()
+		Expect(lvgs).To(HaveLen(1))
+		Expect(lvgs[0].Labels[base.LVGPolicyLabel]).To(Equal(policy.Name))
+		Expect(lvgs[0].Spec.Locations).To(Equal([]string{"drive-a", "drive-b"}))
+		Expect(lvgs[0].Spec.Status).To(Equal(apiV1.Creating))
+		lvgSize := 2 * capacityplanner.SubtractLVMMetadataSize(driveSize)
+		Expect(lvgs[0].Spec.Size).To(Equal(lvgSize))
+		Expect(acSize(lvgs[0].Name)).To(Equal(lvgSize))
+		Expect(acSize("drive-a")).To(Equal(int64(0)))
+		Expect(acSize("drive-b")).To(Equal(int64(0)))
+		Expect(acSize("drive-c")).To(Equal(driveSize))
+
+		// drives are already in LogicalVolumeGroup
+		Expect(controller.reconcileLVGPolicies(testCtx)).To(BeNil())
+		Expect(readLVGs()).To(HaveLen(1))
+	})
+
+	It("Drive which isn't smaller than MaxDriveSize is skipped", func() {
+		p := &lvgpolicycrd.LVGPolicy{}
+		Expect(controller.k8sclient.Get(testCtx, k8sCl.ObjectKey{Name: policy.Name}, p)).To(BeNil())
+		p.Spec.MaxDriveSize = driveSize
+		Expect(controller.k8sclient.UpdateCR(testCtx, p)).To(BeNil())
+		createDrive("drive-a", driveSize, poolLabels)
+		createDrive("drive-b", driveSize/2, poolLabels)
+
+		Expect(controller.reconcileLVGPolicies(testCtx)).To(BeNil())
+		lvgs := readLVGs()
+		Expect(lvgs).To(HaveLen(1))
+		Expect(lvgs[0].Spec.Locations).To(Equal([]string{"drive-b"}))
+	})
+
+	It("New drive is added to created LogicalVolumeGroup", func() {
+		createDrive("drive-a", driveSize, poolLabels)
+		Expect(controller.reconcileLVGPolicies(testCtx)).To(BeNil())
+		lvg := &readLVGs()[0]
+		lvg.Spec.Status = apiV1.Created
+		Expect(controller.k8sclient.UpdateCR(testCtx, lvg)).To(BeNil())
+
+		createDrive("drive-b", driveSize, poolLabels)
+		Expect(controller.reconcileLVGPolicies(testCtx)).To(BeNil())
+		lvgs := readLVGs()
+		Expect(lvgs).To(HaveLen(1))
+		Expect(lvgs[0].Spec.Locations).To(Equal([]string{"drive-a", "drive-b"}))
+		// node increases size when volume group is extended
+		Expect(lvgs[0].Spec.Size).To(Equal(capacityplanner.SubtractLVMMetadataSize(driveSize)))
+		Expect(acSize("drive-b")).To(Equal(int64(0)))
+	})
+
+	It("Unusable LogicalVolumeGroup without volumes is removed", func() {
+		createDrive("drive-a", driveSize, poolLabels)
+		Expect(controller.reconcileLVGPolicies(testCtx)).To(BeNil())
+		lvg := &readLVGs()[0]
+		lvg.Spec.Status = apiV1.Failed
+		Expect(controller.k8sclient.UpdateCR(testCtx, lvg)).To(BeNil())
+
+		Expect(controller.reconcileLVGPolicies(testCtx)).To(BeNil())
+		Expect(readLVGs()).To(BeEmpty())
+		_, err := controller.crHelper.GetACByLocation(testCtx, lvg.Name)
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("CSIControllerService volumeTopology", func() {
+	var controller *CSIControllerService
+
+	BeforeEach(func() {
+		controller = newSvc()
+	})
+
+	It("Should return node ID if topology labels aren't set", func() {
+		Expect(controller.volumeTopology(testCtx, testNode1Name)).To(Equal(
+			map[string]string{csibmnodeconst.NodeIDAnnotationKey: testNode1Name}))
+	})
+
+	It("Should return topology labels of the node", func() {
+		controller.SetTopologyLabels([]string{"topology.kubernetes.io/zone", "example.com/rack"})
+		err := controller.k8sclient.Create(testCtx, &v1.Node{ObjectMeta: k8smetav1.ObjectMeta{
+			Name:        testNode1Name,
+			Annotations: map[string]string{csibmnodeconst.NodeIDAnnotationKey: testNode1Name},
+			Labels:      map[string]string{"example.com/rack": "rack-1"},
+		}})
+		Expect(err).To(BeNil())
+
+		Expect(controller.volumeTopology(testCtx, testNode1Name)).To(Equal(map[string]string{
+			csibmnodeconst.NodeIDAnnotationKey: testNode1Name,
+			"example.com/rack":                 "rack-1",
+		}))
+		// node isn't found
+		Expect(controller.volumeTopology(testCtx, testNode2Name)).To(Equal(
+			map[string]string{csibmnodeconst.NodeIDAnnotationKey: testNode2Name}))
+	})
+})
+
+var _ = Describe("CSIControllerService WarmStart", func() {
+	var controller *CSIControllerService
+
+	BeforeEach(func() {
+		controller = newSvc()
+	})
+
+	createVolume := func(name, location, locationType, csiStatus string, size int64) {
+		volume := testVolume
+		volume.ObjectMeta = k8smetav1.ObjectMeta{Name: name, Namespace: testNs}
+		volume.Spec.Id = name
+		volume.Spec.Location = location
+		volume.Spec.LocationType = locationType
+		volume.Spec.CSIStatus = csiStatus
+		volume.Spec.Size = size
+		Expect(controller.k8sclient.CreateCR(testCtx, name, &volume)).To(BeNil())
+	}
+
+	readACSize := func(name string) int64 {
+		ac := &accrd.AvailableCapacity{}
+		Expect(controller.k8sclient.ReadCR(testCtx, name, "", ac)).To(BeNil())
+		return ac.Spec.Size
+	}
+
+	It("AC of drive with Creating volume should be consumed", func() {
+		ac := testAC1
+		Expect(controller.k8sclient.CreateCR(testCtx, ac.Name, &ac)).To(BeNil())
+		createVolume(testID, ac.Spec.Location, apiV1.LocationTypeDrive, apiV1.Creating, ac.Spec.Size)
+
+		Expect(controller.WarmStart(testCtx)).To(BeNil())
+		Expect(readACSize(ac.Name)).To(Equal(int64(0)))
+
+		// volume created before restart should be found
+		err := controller.svc.WaitStatus(testCtx, testID, apiV1.Creating)
+		Expect(err).To(BeNil())
+	})
+
+	It("AC of LVG with Creating volume should be decreased", func() {
+		var (
+			lvgSize = int64(1024 * 1024 * 1024)
+			volSize = int64(1024 * 1024)
+			lvg     = &lvgcrd.LogicalVolumeGroup{
+				TypeMeta:   k8smetav1.TypeMeta{Kind: "LogicalVolumeGroup", APIVersion: apiV1.APIV1Version},
+				ObjectMeta: k8smetav1.ObjectMeta{Name: "lvg"},
+				Spec:       api.LogicalVolumeGroup{Name: "lvg", Node: testNode2Name, Size: lvgSize},
+			}
+			ac = testAC3
+		)
+		ac.Spec.Location = lvg.Name
+		ac.Spec.Size = lvgSize - volSize
+		Expect(controller.k8sclient.CreateCR(testCtx, lvg.Name, lvg)).To(BeNil())
+		Expect(controller.k8sclient.CreateCR(testCtx, ac.Name, &ac)).To(BeNil())
+		// Created volume is accounted in AC, Creating one isn't
+		createVolume("created", lvg.Name, apiV1.LocationTypeLVM, apiV1.Created, volSize)
+		createVolume("creating", lvg.Name, apiV1.LocationTypeLVM, apiV1.Creating, volSize)
+
+		Expect(controller.WarmStart(testCtx)).To(BeNil())
+		Expect(readACSize(ac.Name)).To(Equal(lvgSize - 2*volSize))
+	})
+
+	It("AC shouldn't be changed if volume isn't Creating", func() {
+		ac := testAC1
+		Expect(controller.k8sclient.CreateCR(testCtx, ac.Name, &ac)).To(BeNil())
+		createVolume(testID, ac.Spec.Location, apiV1.LocationTypeDrive, apiV1.Removed, ac.Spec.Size)
+
+		Expect(controller.WarmStart(testCtx)).To(BeNil())
+		Expect(readACSize(ac.Name)).To(Equal(testAC1.Spec.Size))
+	})
+})
+
+var _ = Describe("CSIControllerService ValidateVolumeCapabilities", func() {
+	var (
+		controller   *CSIControllerService
+		capabilities = []*csi.VolumeCapability{{
+			AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+		}}
+	)
+
+	BeforeEach(func() {
+		controller = newSvc()
+		volume := testVolume
+		err := controller.k8sclient.CreateCR(testCtx, volume.Name, &volume)
+		Expect(err).To(BeNil())
+	})
+
+	It("Request is invalid", func() {
+		_, err := controller.ValidateVolumeCapabilities(testCtx, &csi.ValidateVolumeCapabilitiesRequest{
+			VolumeCapabilities: capabilities,
+		})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+		_, err = controller.ValidateVolumeCapabilities(testCtx, &csi.ValidateVolumeCapabilitiesRequest{
+			VolumeId: testVolume.Spec.Id,
+		})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+	It("Volume doesn't exist", func() {
+		_, err := controller.ValidateVolumeCapabilities(testCtx, &csi.ValidateVolumeCapabilitiesRequest{
+			VolumeId:           "not-existing",
+			VolumeCapabilities: capabilities,
+		})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+	It("Capabilities are confirmed", func() {
+		resp, err := controller.ValidateVolumeCapabilities(testCtx, &csi.ValidateVolumeCapabilitiesRequest{
+			VolumeId:           testVolume.Spec.Id,
+			VolumeCapabilities: capabilities,
+		})
+		Expect(err).To(BeNil())
+		Expect(resp.GetConfirmed().GetVolumeCapabilities()).To(Equal(capabilities))
+	})
+	It("Multi node access mode isn't confirmed", func() {
+		resp, err := controller.ValidateVolumeCapabilities(testCtx, &csi.ValidateVolumeCapabilitiesRequest{
+			VolumeId: testVolume.Spec.Id,
+			VolumeCapabilities: []*csi.VolumeCapability{{
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER},
+			}},
+		})
+		Expect(err).To(BeNil())
+		Expect(resp.GetConfirmed()).To(BeNil())
+		Expect(resp.GetMessage()).NotTo(BeEmpty())
+	})
+})
+
 var _ = Describe("CSIControllerService ControllerExpandVolume", func() {
 	var (
 		controller *CSIControllerService
@@ -628,6 +1597,20 @@ var _ = Describe("CSIControllerService ControllerExpandVolume", func() {
 			Expect(err).To(BeNil())
 			Expect(volumeCrd.Spec.CSIStatus).To(Equal(apiV1.Failed))
 		})
+		It("Volume with dm-integrity protection", func() {
+			volumeCrd := &vcrd.Volume{}
+			Expect(controller.k8sclient.ReadCR(testCtx, uuid, testNs, volumeCrd)).To(BeNil())
+			volumeCrd.Spec.Integrity = apiV1.IntegrityDMIntegrity
+			Expect(controller.k8sclient.UpdateCR(testCtx, volumeCrd)).To(BeNil())
+			fillCache(controller, uuid, testNs)
+
+			resp, err := controller.ControllerExpandVolume(testCtx, &csi.ControllerExpandVolumeRequest{
+				VolumeId:      uuid,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: 1024},
+			})
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		})
 		It("Expand failed", func() {
 			var (
 				err      error
@@ -698,7 +1681,7 @@ var _ = Describe("CSIControllerService ControllerExpandVolume", func() {
 				})
 			Expect(resp).ToNot(BeNil())
 			Expect(err).To(BeNil())
-			Expect(resp.CapacityBytes).To(Equal(int64(0)))
+			Expect(resp.CapacityBytes).To(Equal(volumeCrd.Spec.Size))
 		})
 		It("Volume is expanded successfully", func() {
 			var (
@@ -726,6 +1709,81 @@ var _ = Describe("CSIControllerService ControllerExpandVolume", func() {
 	})
 })
 
+var _ = Describe("CSIControllerService CreateVolume parallelism", func() {
+	var (
+		controller *CSIControllerService
+		waitTime   = 100 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		controller = newSvc()
+	})
+
+	// lockInBackground calls lockForCreate in goroutine and returns channel which is closed when lock is acquired
+	lockInBackground := func(node string) (chan struct{}, *func()) {
+		var unlock func()
+		locked := make(chan struct{})
+		go func() {
+			unlock, _ = controller.lockForCreate(context.Background(), node)
+			close(locked)
+		}()
+		return locked, &unlock
+	}
+
+	It("Requests for different nodes should be processed in parallel", func() {
+		unlock, err := controller.lockForCreate(context.Background(), testNode1Name)
+		Expect(err).To(BeNil())
+		defer unlock()
+
+		locked, unlock2 := lockInBackground(testNode2Name)
+		Eventually(locked, waitTime).Should(BeClosed())
+		(*unlock2)()
+	})
+
+	It("Requests for the same node should be serialized", func() {
+		unlock, err := controller.lockForCreate(context.Background(), testNode1Name)
+		Expect(err).To(BeNil())
+
+		locked, unlock2 := lockInBackground(testNode1Name)
+		Consistently(locked, waitTime).ShouldNot(BeClosed())
+		unlock()
+		Eventually(locked, waitTime).Should(BeClosed())
+		(*unlock2)()
+	})
+
+	It("Request without preferred node should be processed exclusively", func() {
+		unlock, err := controller.lockForCreate(context.Background(), testNode1Name)
+		Expect(err).To(BeNil())
+
+		locked, unlock2 := lockInBackground("")
+		Consistently(locked, waitTime).ShouldNot(BeClosed())
+		unlock()
+		Eventually(locked, waitTime).Should(BeClosed())
+		(*unlock2)()
+	})
+
+	It("Amount of parallel requests should be limited", func() {
+		controller.SetCreateVolumeParallelism(1)
+		unlock, err := controller.lockForCreate(context.Background(), testNode1Name)
+		Expect(err).To(BeNil())
+
+		ctx, cancel := context.WithTimeout(context.Background(), waitTime)
+		defer cancel()
+		_, err = controller.lockForCreate(ctx, testNode2Name)
+		Expect(status.Code(err)).To(Equal(codes.DeadlineExceeded))
+
+		unlock()
+		unlock, err = controller.lockForCreate(context.Background(), testNode2Name)
+		Expect(err).To(BeNil())
+		unlock()
+	})
+
+	It("Non-positive parallelism should be ignored", func() {
+		controller.SetCreateVolumeParallelism(0)
+		Expect(cap(controller.createSem)).To(Equal(base.DefaultCreateVolumeParallelism))
+	})
+})
+
 // create and instance of CSIControllerService with scheme for working with CRD
 // create and instance of CSIControllerService with scheme for working with CRD
 func newSvc() *CSIControllerService {
@@ -0,0 +1,229 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diagnostics collects custom resources, logs and state of the devices on the nodes into a tarball
+// which is attached to support cases
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+const (
+	// DefaultLogTail is the number of the latest log lines collected from each container
+	DefaultLogTail = 5000
+
+	// podMask selects pods of the driver in its namespace
+	podMask = "csi-baremetal-"
+	// nodeAppLabel is value of app label of node pods which device state is collected from
+	nodeAppLabel = "csi-baremetal-node"
+	// nodeContainer runs commands of node service, privhelperContainer runs them if it is deployed
+	nodeContainer       = "node"
+	privhelperContainer = "privhelper"
+	// errorsFile lists items which weren't collected
+	errorsFile = "errors.txt"
+)
+
+// nodeCommands are executed in node pods, output is stored in nodes/<node name>/<name>.txt
+var nodeCommands = []struct {
+	name string
+	cmd  []string
+}{
+	{"lsblk", []string{"lsblk", "--bytes", "--output", "NAME,TYPE,SIZE,FSTYPE,PTTYPE,PARTUUID,SERIAL,MOUNTPOINT"}},
+	{"pvs", []string{"pvs"}},
+	{"vgs", []string{"vgs"}},
+	{"lvs", []string{"lvs", "--all", "--options", "+devices"}},
+	{"mounts", []string{"cat", "/proc/mounts"}},
+}
+
+// PodReader reads logs of the containers and output of the commands executed in them
+type PodReader interface {
+	// Logs returns tail lines of the container log
+	Logs(ctx context.Context, namespace, pod, container string, tail int64) ([]byte, error)
+	// Exec returns combined output of the command executed in the container
+	Exec(ctx context.Context, namespace, pod, container string, cmd []string) ([]byte, error)
+}
+
+// Collector gathers Drive, AvailableCapacity, LogicalVolumeGroup and Volume CRs, logs of the driver pods and
+// lsblk, LVM and mount state of each node. Items which can't be collected are listed in errors.txt of the tarball,
+// so the bundle is produced even when the cluster is partially broken
+type Collector struct {
+	client    *k8s.KubeClient
+	pods      PodReader
+	log       *logrus.Entry
+	logTail   int64
+	errors    []string
+	tarWriter *tar.Writer
+	now       time.Time
+}
+
+// New is the constructor for Collector
+// Receives k8s client which namespace is the namespace of the driver, reader of pods and logrus logger
+func New(client *k8s.KubeClient, pods PodReader, logger *logrus.Logger) *Collector {
+	return &Collector{
+		client:  client,
+		pods:    pods,
+		log:     logger.WithField("component", "DiagnosticsCollector"),
+		logTail: DefaultLogTail,
+	}
+}
+
+// SetLogTail sets the number of the latest log lines collected from each container
+func (c *Collector) SetLogTail(lines int64) {
+	c.logTail = lines
+}
+
+// Collect writes gzipped tarball with diagnostics into out
+// Receives golang context and writer of the tarball
+// Returns number of items which weren't collected or error if tarball wasn't written
+func (c *Collector) Collect(ctx context.Context, out io.Writer) (int, error) {
+	gz := gzip.NewWriter(out)
+	c.tarWriter = tar.NewWriter(gz)
+	c.errors = nil
+	c.now = time.Now()
+
+	if err := c.collectCRs(ctx); err != nil {
+		return 0, err
+	}
+	pods, err := c.client.GetPods(ctx, podMask)
+	if err != nil {
+		c.addError("list pods of the driver", err)
+	}
+	for _, pod := range pods {
+		if err := c.collectLogs(ctx, pod); err != nil {
+			return 0, err
+		}
+		if pod.Labels["app"] == nodeAppLabel {
+			if err := c.collectNodeState(ctx, pod); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if len(c.errors) > 0 {
+		if err := c.addFile(errorsFile, []byte(strings.Join(c.errors, "\n")+"\n")); err != nil {
+			return 0, err
+		}
+	}
+	if err := c.tarWriter.Close(); err != nil {
+		return 0, err
+	}
+	return len(c.errors), gz.Close()
+}
+
+// collectCRs stores lists of the driver custom resources in crs directory
+func (c *Collector) collectCRs(ctx context.Context) error {
+	lists := []struct {
+		name string
+		list runtime.Object
+	}{
+		{"drives", &drivecrd.DriveList{}},
+		{"availablecapacities", &accrd.AvailableCapacityList{}},
+		{"logicalvolumegroups", &lvgcrd.LogicalVolumeGroupList{}},
+		{"volumes", &volumecrd.VolumeList{}},
+	}
+	for _, l := range lists {
+		if err := c.client.ReadList(ctx, l.list); err != nil {
+			c.addError("read "+l.name, err)
+			continue
+		}
+		data, err := yaml.Marshal(l.list)
+		if err != nil {
+			c.addError("serialize "+l.name, err)
+			continue
+		}
+		if err = c.addFile(path.Join("crs", l.name+".yaml"), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectLogs stores logs of each container of the pod in logs/<pod name> directory
+func (c *Collector) collectLogs(ctx context.Context, pod *corev1.Pod) error {
+	for _, container := range pod.Spec.Containers {
+		data, err := c.pods.Logs(ctx, pod.Namespace, pod.Name, container.Name, c.logTail)
+		if err != nil {
+			c.addError(fmt.Sprintf("read logs of %s/%s", pod.Name, container.Name), err)
+			continue
+		}
+		if err = c.addFile(path.Join("logs", pod.Name, container.Name+".log"), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectNodeState stores output of node commands in nodes/<node name> directory, commands are executed
+// in privileged helper container if it is deployed because node container may lack access to the devices
+func (c *Collector) collectNodeState(ctx context.Context, pod *corev1.Pod) error {
+	container := nodeContainer
+	for _, cont := range pod.Spec.Containers {
+		if cont.Name == privhelperContainer {
+			container = privhelperContainer
+		}
+	}
+	for _, nc := range nodeCommands {
+		data, err := c.pods.Exec(ctx, pod.Namespace, pod.Name, container, nc.cmd)
+		if err != nil {
+			c.addError(fmt.Sprintf("execute %s on node %s", nc.name, pod.Spec.NodeName), err)
+			if len(data) == 0 {
+				continue
+			}
+		}
+		if err = c.addFile(path.Join("nodes", pod.Spec.NodeName, nc.name+".txt"), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addError records item which wasn't collected
+func (c *Collector) addError(item string, err error) {
+	c.log.Warnf("Unable to %s: %v", item, err)
+	c.errors = append(c.errors, fmt.Sprintf("unable to %s: %v", item, err))
+}
+
+// addFile writes file into the tarball
+func (c *Collector) addFile(name string, data []byte) error {
+	if err := c.tarWriter.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: c.now,
+	}); err != nil {
+		return err
+	}
+	_, err := c.tarWriter.Write(data)
+	return err
+}
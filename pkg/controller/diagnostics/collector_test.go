@@ -0,0 +1,124 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+const testNs = "default"
+
+var testLogger = logrus.New()
+
+// fakePodReader returns name of the container or command as output, fails commands listed in failed
+type fakePodReader struct {
+	failed    map[string]bool
+	container string
+}
+
+func (r *fakePodReader) Logs(ctx context.Context, namespace, pod, container string, tail int64) ([]byte, error) {
+	return []byte("log of " + pod + "/" + container), nil
+}
+
+func (r *fakePodReader) Exec(ctx context.Context, namespace, pod, container string, cmd []string) ([]byte, error) {
+	r.container = container
+	if r.failed[cmd[0]] {
+		return nil, errors.New("command not found")
+	}
+	return []byte(strings.Join(cmd, " ")), nil
+}
+
+func TestCollector_Collect(t *testing.T) {
+	client, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	ctx := context.Background()
+	drive := client.ConstructDriveCR("drive-1", api.Drive{UUID: "drive-1", SerialNumber: "hdd-1", Health: apiV1.HealthGood})
+	assert.Nil(t, client.CreateCR(ctx, drive.Name, drive))
+	for _, pod := range []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "csi-baremetal-node-abc", Namespace: testNs,
+				Labels: map[string]string{"app": nodeAppLabel}},
+			Spec: corev1.PodSpec{NodeName: "worker-1",
+				Containers: []corev1.Container{{Name: nodeContainer}, {Name: privhelperContainer}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "csi-baremetal-controller-xyz", Namespace: testNs,
+				Labels: map[string]string{"app": "csi-baremetal-controller"}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "controller"}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: testNs},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		},
+	} {
+		assert.Nil(t, client.Create(ctx, pod))
+	}
+
+	reader := &fakePodReader{failed: map[string]bool{"pvs": true}}
+	out := &bytes.Buffer{}
+	failed, err := New(client, reader, testLogger).Collect(ctx, out)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, failed)
+
+	files := readTarball(t, out)
+	assert.Contains(t, files["crs/drives.yaml"], "hdd-1")
+	assert.Contains(t, files, "crs/volumes.yaml")
+	assert.Equal(t, "log of csi-baremetal-controller-xyz/controller", files["logs/csi-baremetal-controller-xyz/controller.log"])
+	assert.Contains(t, files, "logs/csi-baremetal-node-abc/privhelper.log")
+	assert.NotContains(t, files, "logs/unrelated/app.log")
+	assert.Contains(t, files["nodes/worker-1/lsblk.txt"], "lsblk")
+	assert.Equal(t, "cat /proc/mounts", files["nodes/worker-1/mounts.txt"])
+	assert.NotContains(t, files, "nodes/worker-1/pvs.txt")
+	assert.Contains(t, files[errorsFile], "unable to execute pvs on node worker-1")
+	// commands are executed in privileged helper
+	assert.Equal(t, privhelperContainer, reader.container)
+}
+
+// readTarball returns content of the files from gzipped tarball by their names
+func readTarball(t *testing.T, r io.Reader) map[string]string {
+	gz, err := gzip.NewReader(r)
+	assert.Nil(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		assert.Nil(t, err)
+		data, err := ioutil.ReadAll(tr)
+		assert.Nil(t, err)
+		files[header.Name] = string(data)
+	}
+}
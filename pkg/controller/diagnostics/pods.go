@@ -0,0 +1,93 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// KubePodReader reads logs and executes commands in pods through k8s API
+type KubePodReader struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+// NewKubePodReader is the constructor for KubePodReader
+// Receives config of k8s client
+// Returns an instance of KubePodReader or error if clientset couldn't be created
+func NewKubePodReader(config *rest.Config) (*KubePodReader, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &KubePodReader{config: config, clientset: clientset}, nil
+}
+
+// Logs returns tail lines of the container log, request is canceled when ctx is done
+func (r *KubePodReader) Logs(ctx context.Context, namespace, pod, container string, tail int64) ([]byte, error) {
+	return r.clientset.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &tail,
+	}).Context(ctx).DoRaw()
+}
+
+// Exec returns output of the command executed in the container, stderr is appended to stdout
+// Stream of the SPDY executor doesn't accept context, so Exec returns ctx error when ctx is done before
+// the command completes and the stream is abandoned
+func (r *KubePodReader) Exec(ctx context.Context, namespace, pod, container string, cmd []string) ([]byte, error) {
+	req := r.clientset.CoreV1().RESTClient().Post().
+		Namespace(namespace).
+		Resource("pods").
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   cmd,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(r.config, "POST", req.URL())
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- executor.Stream(remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	}()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if stderr.Len() > 0 {
+		stdout.WriteString("\n")
+		stdout.Write(stderr.Bytes())
+	}
+	if err != nil {
+		return stdout.Bytes(), fmt.Errorf("%v, stderr: %s", err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
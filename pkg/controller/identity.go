@@ -22,15 +22,25 @@ import (
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/metrics"
 )
 
 // NewIdentityServer is the creator for defaultIdentityServer struct
 // Receives name of the driver, driver version and readiness state of the driver
 // Returns csi.IdentityServer because defaultIdentityServer struct implements it
 func NewIdentityServer(name string, version string) csi.IdentityServer {
+	return NewIdentityServerWithManifest(name, version, metrics.NewVersionInfo().Manifest())
+}
+
+// NewIdentityServerWithManifest is the creator for defaultIdentityServer struct
+// Receives name of the driver, driver version and manifest which is returned by GetPluginInfo
+// Returns csi.IdentityServer because defaultIdentityServer struct implements it
+func NewIdentityServerWithManifest(name string, version string, manifest map[string]string) csi.IdentityServer {
 	return &defaultIdentityServer{
 		name:      name,
 		version:   version,
+		manifest:  manifest,
 		readiness: true,
 	}
 }
@@ -39,17 +49,19 @@ func NewIdentityServer(name string, version string) csi.IdentityServer {
 type defaultIdentityServer struct {
 	name      string
 	version   string
+	manifest  map[string]string
 	readiness bool
 }
 
 // GetPluginInfo is the implementation of CSI Spec GetPluginInfo.
-// This method returns information about CSI driver: its name and version.
+// This method returns information about CSI driver: its name, version and build information in manifest.
 // Receives golang context and CSI Spec GetPluginInfoRequest
 // Returns CSI Spec GetPluginInfoResponse and nil error
 func (s *defaultIdentityServer) GetPluginInfo(context.Context, *csi.GetPluginInfoRequest) (*csi.GetPluginInfoResponse, error) {
 	return &csi.GetPluginInfoResponse{
 		Name:          s.name,
 		VendorVersion: s.version,
+		Manifest:      s.manifest,
 	}, nil
 }
 
@@ -0,0 +1,140 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	coreV1 "k8s.io/api/core/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/imagesource"
+)
+
+// SetImageSourceAllowlist restricts sources of the volume images, PVC annotations with image source are honored
+// only if it isn't empty, any image source of StorageClass is allowed if it is empty
+// Receives comma separated list of image sources in scheme://host format
+// Returns error if allowlist isn't valid
+func (c *CSIControllerService) SetImageSourceAllowlist(allowlist string) error {
+	list, err := imagesource.ParseAllowlist(allowlist)
+	if err != nil {
+		return err
+	}
+	c.imageSources = list
+	return nil
+}
+
+// volumeImage returns source and checksum of the image which should be written onto the volume before first publish
+// Image is set in StorageClass parameters, PVC annotations override StorageClass parameters if StorageClass
+// allows it and image source of PVC is in the allowlist, so users can't make node fetch arbitrary URLs
+// Receives golang context and parameters of CreateVolumeRequest
+// Returns empty source if volume isn't populated from image or error if image source is invalid or not allowed
+func (c *CSIControllerService) volumeImage(ctx context.Context, params map[string]string) (string, string, error) {
+	pvcAnnotations, err := c.pvcAnnotations(ctx, params)
+	if err != nil {
+		return "", "", err
+	}
+	source, checksum := params[base.ImageSourceKey], params[base.ImageChecksumKey]
+	if pvcSource, ok := pvcAnnotations[base.PVCAnnotationImageSource]; ok {
+		if params[base.ImageSourceOverrideKey] != "true" {
+			return "", "", status.Errorf(codes.InvalidArgument, "%s annotation requires %s parameter of storage class "+
+				"to be set to true", base.PVCAnnotationImageSource, base.ImageSourceOverrideKey)
+		}
+		if !c.imageSources.Allows(pvcSource) {
+			return "", "", status.Errorf(codes.InvalidArgument,
+				"image source %s of %s annotation isn't in the allowlist", pvcSource, base.PVCAnnotationImageSource)
+		}
+		source, checksum = pvcSource, pvcAnnotations[base.PVCAnnotationImageChecksum]
+	}
+	if source == "" {
+		return "", "", nil
+	}
+	if err = imagesource.Validate(source, checksum); err != nil {
+		return "", "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(c.imageSources) > 0 && !c.imageSources.Allows(source) {
+		return "", "", status.Errorf(codes.InvalidArgument, "image source %s isn't in the allowlist", source)
+	}
+	c.log.WithField("method", "volumeImage").Infof("Volume is populated from image %s", source)
+	return source, checksum, nil
+}
+
+// imageCredentials returns credentials for private image source from provisioner secrets of StorageClass
+// Receives secrets of CreateVolumeRequest
+// Returns empty credentials for public image source or error if secrets contain inconsistent credentials
+func imageCredentials(secrets map[string]string) (imagesource.Credentials, error) {
+	creds := imagesource.Credentials{
+		Username: secrets[base.ImageUsernameSecretKey],
+		Password: secrets[base.ImagePasswordSecretKey],
+		Token:    secrets[base.ImageTokenSecretKey],
+	}
+	if err := creds.Validate(); err != nil {
+		return imagesource.Credentials{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return creds, nil
+}
+
+// storeImageCredentials saves credentials for image source into the Secret in driver namespace,
+// node reads them from the Secret when volume is populated
+// Receives golang context, volume ID and credentials
+// Returns name of the Secret or error if it wasn't saved
+func (c *CSIControllerService) storeImageCredentials(ctx context.Context, volumeID string,
+	creds imagesource.Credentials) (string, error) {
+	data := make(map[string][]byte)
+	for key, value := range map[string]string{
+		base.ImageUsernameSecretKey: creds.Username,
+		base.ImagePasswordSecretKey: creds.Password,
+		base.ImageTokenSecretKey:    creds.Token,
+	} {
+		if value != "" {
+			data[key] = []byte(value)
+		}
+	}
+	secret := &coreV1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: base.ImageSecretPrefix + volumeID, Namespace: c.k8sclient.Namespace},
+		Type:       coreV1.SecretTypeOpaque,
+		Data:       data,
+	}
+	err := c.k8sclient.Create(ctx, secret)
+	if k8sError.IsAlreadyExists(err) {
+		err = c.k8sclient.Update(ctx, secret)
+	}
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "unable to store image credentials: %v", err)
+	}
+	return secret.Name, nil
+}
+
+// removeImageCredentials removes the Secret with credentials for image source of the volume,
+// it isn't needed when volume is populated or removed
+func (c *CSIControllerService) removeImageCredentials(ctx context.Context, volumeID string) {
+	secret := &coreV1.Secret{ObjectMeta: metaV1.ObjectMeta{
+		Name:      base.ImageSecretPrefix + volumeID,
+		Namespace: c.k8sclient.Namespace,
+	}}
+	if err := c.k8sclient.Delete(ctx, secret); err != nil && !k8sError.IsNotFound(err) {
+		c.log.WithFields(logrus.Fields{
+			"method":   "removeImageCredentials",
+			"volumeID": volumeID,
+		}).Errorf("Unable to remove Secret %s: %v", secret.Name, err)
+	}
+}
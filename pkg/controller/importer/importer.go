@@ -0,0 +1,340 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package importer adopts existing partitions and logical volumes as volumes of the driver, so data of hostPath or
+// local static provisioner volumes is taken under management of the driver without copying
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+)
+
+// defaultPollInterval is the interval between checks of imported volume status
+const defaultPollInterval = time.Second
+
+// Request describes existing device which is imported and PV which is created for it,
+// either DriveSerial and PartUUID or LVG and LV should be set
+type Request struct {
+	// NodeName is name of k8s node where the device is located
+	NodeName string
+	// DriveSerial is serial number of the drive which holds the partition
+	DriveSerial string
+	// PartUUID is UUID of the partition, it becomes ID of the volume
+	PartUUID string
+	// LVG is name of LogicalVolumeGroup CR or of the volume group which holds the logical volume
+	LVG string
+	// LV is name of the logical volume, it becomes ID of the volume
+	LV string
+	// FSType is expected file system of the device, file system found on the device is used if it is empty
+	FSType string
+	// StorageClass is name of k8s StorageClass of PV
+	StorageClass string
+	// ClaimNamespace and ClaimName define PVC which PV is reserved for, PV isn't reserved if ClaimName is empty
+	ClaimNamespace string
+	ClaimName      string
+	// ReclaimPolicy is reclaim policy of PV, Retain is used if it is empty
+	ReclaimPolicy corev1.PersistentVolumeReclaimPolicy
+}
+
+// Importer creates Volume CR for existing device, node checks the device and takes it into use, after that PV is
+// created for the volume. Volume ID is partition UUID or logical volume name, so node finds the device in the same
+// way as for volumes created by the driver
+type Importer struct {
+	client       *k8s.KubeClient
+	crHelper     *k8s.CRHelper
+	log          *logrus.Entry
+	pollInterval time.Duration
+}
+
+// New is the constructor for Importer
+func New(client *k8s.KubeClient, logger *logrus.Logger) *Importer {
+	return &Importer{
+		client:       client,
+		crHelper:     k8s.NewCRHelper(client, logger),
+		log:          logger.WithField("component", "Importer"),
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// Import imports existing device as volume and creates PV for it. Volume discovered by node for the same partition
+// is replaced, any other volume or PV with the same name is a conflict. Volume which node failed to import is removed
+// Receives golang context, which deadline limits waiting for node, and import request
+// Returns created PV or error if device wasn't imported
+func (i *Importer) Import(ctx context.Context, req Request) (*corev1.PersistentVolume, error) {
+	nodeID, err := i.getNodeID(ctx, req.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	volume, err := i.constructVolume(ctx, nodeID, req)
+	if err != nil {
+		return nil, err
+	}
+	if errs := validation.IsDNS1123Subdomain(volume.Id); len(errs) > 0 {
+		return nil, fmt.Errorf("volume ID %s isn't valid name of k8s object: %s", volume.Id, strings.Join(errs, ", "))
+	}
+	ll := i.log.WithFields(logrus.Fields{
+		"method":   "Import",
+		"volumeID": volume.Id,
+	})
+	if err = i.checkConflicts(ctx, volume); err != nil {
+		return nil, err
+	}
+
+	namespace := req.ClaimNamespace
+	if namespace == "" {
+		namespace = base.DefaultNamespace
+	}
+	volumeCR := i.client.ConstructVolumeCR(volume.Id, namespace, *volume)
+	volumeCR.Annotations = map[string]string{apiV1.VolumeAnnotationImport: "true"}
+	ll.Infof("Creating volume on node %s at location %s", nodeID, volume.Location)
+	if err = i.client.Create(ctx, volumeCR); err != nil {
+		return nil, fmt.Errorf("unable to create volume %s: %w", volume.Id, err)
+	}
+
+	if err = i.waitImported(ctx, volumeCR); err != nil {
+		return nil, err
+	}
+	ll.Infof("Device was imported, volume size is %d bytes, file system is %s", volumeCR.Spec.Size, volumeCR.Spec.Type)
+
+	pv := constructPV(volumeCR.Spec, req)
+	if err = i.client.Create(ctx, pv); err != nil {
+		return nil, fmt.Errorf("volume %s was imported, but PV wasn't created: %w", volume.Id, err)
+	}
+	return pv, nil
+}
+
+// getNodeID returns ID of the node from annotation set by operator
+func (i *Importer) getNodeID(ctx context.Context, nodeName string) (string, error) {
+	node := &corev1.Node{}
+	if err := i.client.Get(ctx, k8sCl.ObjectKey{Name: nodeName}, node); err != nil {
+		return "", fmt.Errorf("unable to read node %s: %w", nodeName, err)
+	}
+	id, ok := node.GetAnnotations()[csibmnodeconst.NodeIDAnnotationKey]
+	if !ok {
+		return "", fmt.Errorf("node %s isn't managed by the driver, annotation %s isn't set",
+			nodeName, csibmnodeconst.NodeIDAnnotationKey)
+	}
+	return id, nil
+}
+
+// constructVolume finds location of the device and constructs volume for it, size of the volume is set by node
+func (i *Importer) constructVolume(ctx context.Context, nodeID string, req Request) (*api.Volume, error) {
+	volume := &api.Volume{
+		NodeId:            nodeID,
+		CSIStatus:         apiV1.Creating,
+		Health:            apiV1.HealthGood,
+		OperationalStatus: apiV1.OperationalStatusOperative,
+		Usage:             apiV1.VolumeUsageInUse,
+		Mode:              apiV1.ModeFS,
+		Type:              req.FSType,
+	}
+
+	switch {
+	case req.DriveSerial != "" && req.PartUUID != "":
+		drives, err := i.crHelper.GetDriveCRs(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		}
+		for _, drive := range drives {
+			if drive.Spec.SerialNumber == req.DriveSerial {
+				volume.Id = strings.ToLower(req.PartUUID)
+				volume.Location = drive.Spec.UUID
+				volume.LocationType = apiV1.LocationTypeDrive
+				volume.StorageClass = util.ConvertDriveTypeToStorageClass(drive.Spec.Type)
+				return volume, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: drive with serial number %s isn't found on node %s",
+			errTypes.ErrDriveNotFound, req.DriveSerial, req.NodeName)
+	case req.LVG != "" && req.LV != "":
+		lvgs, err := i.crHelper.GetLVGCRs(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		}
+		for _, lvg := range lvgs {
+			if lvg.Name != req.LVG && lvg.Spec.Name != req.LVG {
+				continue
+			}
+			if len(lvg.Spec.Locations) == 0 {
+				return nil, fmt.Errorf("LogicalVolumeGroup %s has no drives", lvg.Name)
+			}
+			drive := i.crHelper.GetDriveCRByUUID(ctx, lvg.Spec.Locations[0])
+			if drive == nil {
+				return nil, fmt.Errorf("%w: drive %s of LogicalVolumeGroup %s isn't found",
+					errTypes.ErrDriveNotFound, lvg.Spec.Locations[0], lvg.Name)
+			}
+			volume.Id = req.LV
+			volume.Location = lvg.Name
+			volume.LocationType = apiV1.LocationTypeLVM
+			volume.StorageClass = lvgStorageClass(drive.Spec)
+			return volume, nil
+		}
+		return nil, fmt.Errorf("LogicalVolumeGroup %s isn't found on node %s", req.LVG, req.NodeName)
+	default:
+		return nil, errors.New("either drive serial number and partition UUID or " +
+			"LogicalVolumeGroup and logical volume should be set")
+	}
+}
+
+// lvgStorageClass returns storage class of volumes in LogicalVolumeGroup based on its first drive
+func lvgStorageClass(drive api.Drive) string {
+	if drive.IsSystem {
+		return apiV1.StorageClassSystemLVG
+	}
+	switch util.ConvertDriveTypeToStorageClass(drive.Type) {
+	case apiV1.StorageClassSSD:
+		return apiV1.StorageClassSSDLVG
+	case apiV1.StorageClassNVMe:
+		return apiV1.StorageClassNVMeLVG
+	default:
+		return apiV1.StorageClassHDDLVG
+	}
+}
+
+// checkConflicts checks that volume and PV with volume ID don't exist and that drive doesn't hold another volume,
+// volume discovered by node for the imported partition is removed
+func (i *Importer) checkConflicts(ctx context.Context, volume *api.Volume) error {
+	volumes, err := i.crHelper.GetVolumeCRs(ctx, volume.NodeId)
+	if err != nil {
+		return err
+	}
+	for j := range volumes {
+		v := &volumes[j]
+		sameID := v.Name == volume.Id || v.Spec.Id == volume.Id
+		sameDrive := volume.LocationType == apiV1.LocationTypeDrive && v.Spec.Location == volume.Location
+		if !sameID && !sameDrive {
+			continue
+		}
+		if v.Spec.CSIStatus != apiV1.Empty || v.Spec.Id != volume.Id {
+			return fmt.Errorf("volume %s already exists at location %s", v.Name, v.Spec.Location)
+		}
+		i.log.WithField("method", "checkConflicts").Infof("Removing discovered volume %s of the partition", v.Name)
+		if err = i.client.DeleteCR(ctx, v); err != nil {
+			return err
+		}
+	}
+
+	pv := &corev1.PersistentVolume{}
+	err = i.client.Get(ctx, k8sCl.ObjectKey{Name: volume.Id}, pv)
+	switch {
+	case err == nil:
+		return fmt.Errorf("PV %s already exists", volume.Id)
+	case !k8sError.IsNotFound(err):
+		return err
+	}
+	return nil
+}
+
+// waitImported waits till node imports the volume, volume is updated with the result.
+// Volume in Failed status is removed, device isn't changed by node in that case
+func (i *Importer) waitImported(ctx context.Context, volume *volumecrd.Volume) error {
+	ticker := time.NewTicker(i.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := i.client.ReadCR(ctx, volume.Name, volume.Namespace, volume); err != nil {
+			return err
+		}
+		switch volume.Spec.CSIStatus {
+		case apiV1.Created:
+			return nil
+		case apiV1.Failed:
+			// finalizer is set by node, it isn't removed for volume in Failed status
+			volume.Finalizers = nil
+			if err := i.client.UpdateCR(ctx, volume); err != nil {
+				return err
+			}
+			if err := i.client.DeleteCR(ctx, volume); err != nil {
+				return err
+			}
+			return fmt.Errorf("node failed to import device of volume %s, reason is reported in events of the volume",
+				volume.Name)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("volume %s wasn't imported in time, its status is %s", volume.Name,
+				volume.Spec.CSIStatus)
+		case <-ticker.C:
+		}
+	}
+}
+
+// constructPV constructs PV of the imported volume, PV is placed on the node of the volume
+func constructPV(volume api.Volume, req Request) *corev1.PersistentVolume {
+	policy := req.ReclaimPolicy
+	if policy == "" {
+		policy = corev1.PersistentVolumeReclaimRetain
+	}
+	mode := corev1.PersistentVolumeFilesystem
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: volume.Id},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: *resource.NewQuantity(volume.Size, resource.BinarySI),
+			},
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:       base.PluginName,
+					VolumeHandle: volume.Id,
+					FSType:       volume.Type,
+				},
+			},
+			AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			PersistentVolumeReclaimPolicy: policy,
+			StorageClassName:              req.StorageClass,
+			VolumeMode:                    &mode,
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      csibmnodeconst.NodeIDAnnotationKey,
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{volume.NodeId},
+						}},
+					}},
+				},
+			},
+		},
+	}
+	if req.ClaimName != "" {
+		pv.Spec.ClaimRef = &corev1.ObjectReference{
+			Kind:      "PersistentVolumeClaim",
+			Namespace: req.ClaimNamespace,
+			Name:      req.ClaimName,
+		}
+	}
+	return pv
+}
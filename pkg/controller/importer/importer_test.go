@@ -0,0 +1,185 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+)
+
+const (
+	testNs       = "default"
+	testNodeName = "node-1"
+	testNodeID   = "node-1-uuid"
+	testPartUUID = "3a2bfb3f-d1ec-4c55-8e44-4a4d20e2bd0c"
+)
+
+var (
+	testLogger = logrus.New()
+
+	testDrive = api.Drive{
+		UUID:         "drive-uuid",
+		SerialNumber: "hdd1-serial",
+		NodeId:       testNodeID,
+		Type:         apiV1.DriveTypeHDD,
+		Path:         "/dev/sda",
+	}
+	testLVG = api.LogicalVolumeGroup{
+		Name:      "vg-system",
+		Node:      testNodeID,
+		Locations: []string{testDrive.UUID},
+		Status:    apiV1.Created,
+	}
+)
+
+func prepareImporter(t *testing.T) *Importer {
+	client, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        testNodeName,
+		Annotations: map[string]string{csibmnodeconst.NodeIDAnnotationKey: testNodeID},
+	}}
+	assert.Nil(t, client.Create(context.Background(), node))
+	drive := client.ConstructDriveCR(testDrive.UUID, testDrive)
+	assert.Nil(t, client.Create(context.Background(), drive))
+	lvg := client.ConstructLVGCR("lvg-uuid", testLVG)
+	assert.Nil(t, client.Create(context.Background(), lvg))
+
+	i := New(client, testLogger)
+	i.pollInterval = time.Millisecond
+	return i
+}
+
+// setVolumeStatus imitates node which changes status of the imported volume
+func setVolumeStatus(i *Importer, name, status string, size int64) {
+	go func() {
+		for {
+			volume := &volumecrd.Volume{}
+			if err := i.client.ReadCR(context.Background(), name, testNs, volume); err == nil {
+				volume.Spec.CSIStatus = status
+				volume.Spec.Size = size
+				volume.Spec.Type = "xfs"
+				volume.Finalizers = []string{"dell.emc.csi/volume-cleanup"}
+				if err = i.client.UpdateCR(context.Background(), volume); err == nil {
+					return
+				}
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+}
+
+func TestImporter_Import(t *testing.T) {
+	var (
+		i   = prepareImporter(t)
+		ctx = context.Background()
+		req = Request{
+			NodeName:       testNodeName,
+			DriveSerial:    testDrive.SerialNumber,
+			PartUUID:       testPartUUID,
+			StorageClass:   "csi-baremetal-sc-hdd",
+			ClaimNamespace: testNs,
+			ClaimName:      "data",
+		}
+	)
+
+	// volume discovered by node for the partition is replaced
+	discovered := i.client.ConstructVolumeCR("discovered", testNs, api.Volume{
+		Id: testPartUUID, NodeId: testNodeID, Location: testDrive.UUID, CSIStatus: apiV1.Empty})
+	assert.Nil(t, i.client.Create(ctx, discovered))
+
+	setVolumeStatus(i, testPartUUID, apiV1.Created, 1024)
+	pv, err := i.Import(ctx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, testPartUUID, pv.Name)
+	assert.Equal(t, base.PluginName, pv.Spec.CSI.Driver)
+	assert.Equal(t, "xfs", pv.Spec.CSI.FSType)
+	capacity := pv.Spec.Capacity[corev1.ResourceStorage]
+	assert.Equal(t, int64(1024), capacity.Value())
+	assert.Equal(t, corev1.PersistentVolumeReclaimRetain, pv.Spec.PersistentVolumeReclaimPolicy)
+	assert.Equal(t, "data", pv.Spec.ClaimRef.Name)
+	assert.Equal(t, []string{testNodeID}, pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Values)
+
+	volume := &volumecrd.Volume{}
+	assert.Nil(t, i.client.ReadCR(ctx, testPartUUID, testNs, volume))
+	assert.Equal(t, testDrive.UUID, volume.Spec.Location)
+	assert.Equal(t, apiV1.StorageClassHDD, volume.Spec.StorageClass)
+	assert.Contains(t, volume.Annotations, apiV1.VolumeAnnotationImport)
+	assert.NotNil(t, i.client.ReadCR(ctx, discovered.Name, testNs, &volumecrd.Volume{}))
+
+	// volume already exists
+	_, err = i.Import(ctx, req)
+	assert.NotNil(t, err)
+}
+
+func TestImporter_Import_Failed(t *testing.T) {
+	var (
+		i   = prepareImporter(t)
+		ctx = context.Background()
+		req = Request{NodeName: testNodeName, LVG: testLVG.Name, LV: "data"}
+	)
+
+	// node failed to import, volume is removed
+	setVolumeStatus(i, req.LV, apiV1.Failed, 0)
+	_, err := i.Import(ctx, req)
+	assert.NotNil(t, err)
+	assert.NotNil(t, i.client.ReadCR(ctx, req.LV, testNs, &volumecrd.Volume{}))
+
+	// node doesn't respond
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = i.Import(ctxWithTimeout, req)
+	assert.Contains(t, err.Error(), "wasn't imported in time")
+
+	// PV already exists
+	volume := &volumecrd.Volume{}
+	assert.Nil(t, i.client.ReadCR(ctx, req.LV, testNs, volume))
+	assert.Equal(t, apiV1.StorageClassHDDLVG, volume.Spec.StorageClass)
+	assert.Nil(t, i.client.DeleteCR(ctx, volume))
+	assert.Nil(t, i.client.Create(ctx, &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: req.LV}}))
+	_, err = i.Import(ctx, req)
+	assert.Contains(t, err.Error(), "PV data already exists")
+}
+
+func TestImporter_Import_InvalidRequest(t *testing.T) {
+	var (
+		i   = prepareImporter(t)
+		ctx = context.Background()
+	)
+
+	_, err := i.Import(ctx, Request{NodeName: "node-2", LVG: testLVG.Name, LV: "data"})
+	assert.NotNil(t, err)
+	_, err = i.Import(ctx, Request{NodeName: testNodeName, LV: "data"})
+	assert.NotNil(t, err)
+	_, err = i.Import(ctx, Request{NodeName: testNodeName, DriveSerial: "unknown", PartUUID: testPartUUID})
+	assert.NotNil(t, err)
+	_, err = i.Import(ctx, Request{NodeName: testNodeName, LVG: testLVG.Name, LV: "Data_LV"})
+	assert.Contains(t, err.Error(), "isn't valid name")
+}
@@ -0,0 +1,123 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+)
+
+// orphanedVolumesCheckInterval is the interval between searches of orphaned volumes
+const orphanedVolumesCheckInterval = time.Minute
+
+// RunOrphanedVolumesJanitor periodically reclaims orphaned volumes, volume is orphaned when PV with its name
+// doesn't exist, for example when PV was deleted while controller was down. Volume is removed from the node
+// and AvailableCapacity is returned once it is orphaned longer than grace period
+// Receives grace period, janitor is disabled if it isn't positive
+func (c *CSIControllerService) RunOrphanedVolumesJanitor(gracePeriod time.Duration) {
+	if gracePeriod <= 0 {
+		return
+	}
+	c.log.WithField("method", "RunOrphanedVolumesJanitor").
+		Infof("Orphaned volumes are reclaimed after %s", gracePeriod)
+	orphans := make(map[string]time.Time)
+	for {
+		<-c.clock.After(orphanedVolumesCheckInterval)
+		c.reclaimOrphanedVolumes(context.Background(), orphans, gracePeriod, c.clock.Now())
+	}
+}
+
+// reclaimOrphanedVolumes searches orphaned volumes and reclaims the ones which grace period is expired.
+// Volume in Created status is removed on the node, Removed volume CR is deleted and its capacity is returned,
+// staged and published volumes are skipped because they are used by pods
+// Receives golang context, time when volumes became orphaned, which is updated, grace period and current time
+func (c *CSIControllerService) reclaimOrphanedVolumes(ctx context.Context, orphans map[string]time.Time,
+	gracePeriod time.Duration, now time.Time) {
+	ll := c.log.WithField("method", "reclaimOrphanedVolumes")
+
+	pvs := &corev1.PersistentVolumeList{}
+	if err := c.k8sclient.ReadList(ctx, pvs); err != nil {
+		ll.Errorf("Unable to read PVs: %v", err)
+		return
+	}
+	pvNames := make(map[string]bool, len(pvs.Items))
+	for _, pv := range pvs.Items {
+		pvNames[pv.Name] = true
+	}
+
+	volumes := &volumecrd.VolumeList{}
+	if err := c.k8sclient.ReadList(ctx, volumes); err != nil {
+		ll.Errorf("Unable to read volumes: %v", err)
+		return
+	}
+	found := make(map[string]bool, len(volumes.Items))
+	for i := range volumes.Items {
+		volume := &volumes.Items[i]
+		if volume.Spec.Ephemeral || pvNames[volume.Name] || !volume.DeletionTimestamp.IsZero() {
+			continue
+		}
+		found[volume.Name] = true
+		since, ok := orphans[volume.Name]
+		if !ok {
+			ll.Infof("Volume %s is orphaned, PV doesn't exist", volume.Name)
+			orphans[volume.Name] = now
+			continue
+		}
+		if now.Sub(since) < gracePeriod {
+			continue
+		}
+		c.reclaimVolume(volume)
+	}
+	// volume was removed or PV appeared
+	for id := range orphans {
+		if !found[id] {
+			delete(orphans, id)
+		}
+	}
+}
+
+// reclaimVolume moves orphaned volume through removal steps of DeleteVolume without waiting
+func (c *CSIControllerService) reclaimVolume(volume *volumecrd.Volume) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method":   "reclaimVolume",
+		"volumeID": volume.Name,
+	})
+	ctxWithID := context.WithValue(context.Background(), base.RequestUUID, volume.Name)
+
+	c.reqMu.Lock()
+	defer c.reqMu.Unlock()
+	switch volume.Spec.CSIStatus {
+	case apiV1.Created:
+		ll.Info("Remove orphaned volume")
+		if err := c.svc.DeleteVolume(ctxWithID, volume.Name); err != nil {
+			ll.Errorf("Unable to remove orphaned volume: %v", err)
+		}
+	case apiV1.Removed:
+		ll.Info("Orphaned volume is removed, release capacity")
+		c.svc.UpdateCRsAfterVolumeDeletion(ctxWithID, volume.Name)
+	case apiV1.Removing:
+	default:
+		ll.Debugf("Orphaned volume in %s status is not reclaimed", volume.Spec.CSIStatus)
+	}
+}
@@ -0,0 +1,322 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgpolicycrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
+
+// lvgPolicyReconcileInterval is the interval between reconciliations of LVGPolicies
+const lvgPolicyReconcileInterval = time.Minute
+
+// lvgPolicyState holds capacity which LVGPolicies are applied to
+type lvgPolicyState struct {
+	drives map[string]*drivecrd.Drive
+	// AC location to AC mapping
+	acs map[string]*accrd.AvailableCapacity
+	// names of ACs which are reserved by scheduler extender
+	reserved map[string]bool
+	// drives which are already in LogicalVolumeGroups
+	lvgDrives map[string]bool
+	lvgs      []lvgcrd.LogicalVolumeGroup
+}
+
+// RunLVGPolicyReconciler periodically applies LVGPolicies: free drives which match policy are combined into
+// LogicalVolumeGroup on each node, drives which appear later are added to it. LogicalVolumeGroup without volumes
+// is recreated from the rest of drives if some of its drives are unhealthy or removed
+func (c *CSIControllerService) RunLVGPolicyReconciler() {
+	for {
+		<-c.clock.After(lvgPolicyReconcileInterval)
+		if err := c.reconcileLVGPolicies(context.Background()); err != nil {
+			c.log.WithField("method", "RunLVGPolicyReconciler").Errorf("Unable to reconcile LVG policies: %v", err)
+		}
+	}
+}
+
+// reconcileLVGPolicies applies all LVGPolicies, capacity is changed exclusively of CreateVolume requests
+// Receives golang context
+// Returns error if resources could not be read
+func (c *CSIControllerService) reconcileLVGPolicies(ctx context.Context) error {
+	policies := &lvgpolicycrd.LVGPolicyList{}
+	if err := c.k8sclient.ReadList(ctx, policies); err != nil {
+		return err
+	}
+	if len(policies.Items) == 0 {
+		return nil
+	}
+
+	c.reqMu.Lock()
+	defer c.reqMu.Unlock()
+	state, err := c.readLVGPolicyState(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range policies.Items {
+		c.applyLVGPolicy(ctx, &policies.Items[i], state)
+	}
+	return nil
+}
+
+// readLVGPolicyState reads drives, ACs, ACRs and LogicalVolumeGroups
+func (c *CSIControllerService) readLVGPolicyState(ctx context.Context) (*lvgPolicyState, error) {
+	drives, err := c.crHelper.GetDriveCRs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	acs, err := c.crHelper.GetACCRs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lvgs, err := c.crHelper.GetLVGCRs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	acrs := &acrcrd.AvailableCapacityReservationList{}
+	if err := c.k8sclient.ReadList(ctx, acrs); err != nil {
+		return nil, err
+	}
+
+	state := &lvgPolicyState{
+		drives:    make(map[string]*drivecrd.Drive, len(drives)),
+		acs:       make(map[string]*accrd.AvailableCapacity, len(acs)),
+		reserved:  make(map[string]bool),
+		lvgDrives: make(map[string]bool),
+		lvgs:      lvgs,
+	}
+	for i := range drives {
+		state.drives[drives[i].Spec.UUID] = &drives[i]
+	}
+	for i := range acs {
+		state.acs[acs[i].Spec.Location] = &acs[i]
+	}
+	for _, acr := range acrs.Items {
+		for _, name := range acr.Spec.Reservations {
+			state.reserved[name] = true
+		}
+	}
+	for _, lvg := range lvgs {
+		for _, location := range lvg.Spec.Locations {
+			state.lvgDrives[location] = true
+		}
+	}
+	return state, nil
+}
+
+// applyLVGPolicy creates, extends or recreates LogicalVolumeGroups of the policy on each node
+func (c *CSIControllerService) applyLVGPolicy(ctx context.Context, policy *lvgpolicycrd.LVGPolicy, state *lvgPolicyState) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method": "applyLVGPolicy",
+		"policy": policy.Name,
+	})
+	if !util.IsStorageClassLVG(policy.Spec.StorageClass) {
+		ll.Errorf("Storage class %s isn't LVG storage class", policy.Spec.StorageClass)
+		return
+	}
+	selector, err := parseDriveSelector(policy.Spec.DriveSelector)
+	if err != nil {
+		ll.Error(err)
+		return
+	}
+
+	policyLVGs := make(map[string]*lvgcrd.LogicalVolumeGroup)
+	for i := range state.lvgs {
+		if state.lvgs[i].Labels[base.LVGPolicyLabel] == policy.Name {
+			policyLVGs[state.lvgs[i].Spec.Node] = &state.lvgs[i]
+		}
+	}
+	recreated := make(map[string]bool)
+	for node, lvg := range policyLVGs {
+		if len(lvg.Spec.VolumeRefs) == 0 && !isPolicyLVGUsable(lvg, state.drives) {
+			ll.Infof("LogicalVolumeGroup %s on node %s is not usable, recreate it", lvg.Name, node)
+			c.removePolicyLVG(ctx, lvg, state.acs[lvg.Name])
+			recreated[node] = true
+		}
+	}
+
+	free := make(map[string][]*accrd.AvailableCapacity)
+	for _, drive := range state.drives {
+		if ac := policyDriveAC(policy, selector, drive, state); ac != nil {
+			free[drive.Spec.NodeId] = append(free[drive.Spec.NodeId], ac)
+		}
+	}
+	for node, acs := range free {
+		if recreated[node] {
+			continue
+		}
+		sort.Slice(acs, func(i, j int) bool {
+			return acs[i].Spec.Location < acs[j].Spec.Location
+		})
+		lvg, ok := policyLVGs[node]
+		switch {
+		case !ok:
+			c.createPolicyLVG(ctx, policy, node, acs)
+		case lvg.Spec.Status == apiV1.Created && lvg.Spec.Health == apiV1.HealthGood:
+			c.extendPolicyLVG(ctx, lvg, acs)
+		}
+	}
+}
+
+// policyDriveAC returns AC of the drive if drive is free and matches policy, otherwise nil
+func policyDriveAC(policy *lvgpolicycrd.LVGPolicy, selector labels.Selector, drive *drivecrd.Drive,
+	state *lvgPolicyState) *accrd.AvailableCapacity {
+	driveSC := util.GetSubStorageClass(policy.Spec.StorageClass)
+	switch {
+	case drive.Spec.Health != apiV1.HealthGood || drive.Spec.Status != apiV1.DriveStatusOnline ||
+		drive.Spec.Usage != apiV1.DriveUsageInUse || drive.Spec.IsSystem || drive.IsHotSpare():
+		return nil
+	case util.ConvertDriveTypeToStorageClass(drive.Spec.Type) != driveSC:
+		return nil
+	case policy.Spec.MaxDriveSize > 0 && drive.Spec.Size >= policy.Spec.MaxDriveSize:
+		return nil
+	case !selector.Matches(labels.Set(drive.Labels)) || state.lvgDrives[drive.Spec.UUID]:
+		return nil
+	// drive is evacuated from LogicalVolumeGroup explicitly
+	case drive.Annotations[apiV1.DriveAnnotationEvacuationStatus] == apiV1.DriveAnnotationEvacuationStatusDone:
+		return nil
+	}
+	ac, ok := state.acs[drive.Spec.UUID]
+	// drive which AC is used by volume or reserved by scheduler extender isn't free
+	if !ok || ac.Spec.StorageClass != driveSC || ac.Spec.Size == 0 || state.reserved[ac.Name] {
+		return nil
+	}
+	return ac
+}
+
+// isPolicyLVGUsable checks that LogicalVolumeGroup is healthy and all its drives exist and are online
+func isPolicyLVGUsable(lvg *lvgcrd.LogicalVolumeGroup, drives map[string]*drivecrd.Drive) bool {
+	if lvg.Spec.Status == apiV1.Failed || lvg.Spec.Health != apiV1.HealthGood {
+		return false
+	}
+	for _, location := range lvg.Spec.Locations {
+		drive, ok := drives[location]
+		if !ok || drive.Spec.Status != apiV1.DriveStatusOnline || drive.Spec.Health != apiV1.HealthGood {
+			return false
+		}
+	}
+	return true
+}
+
+// createPolicyLVG creates LogicalVolumeGroup from drives of provided ACs and AC for it, drive ACs are set to 0
+func (c *CSIControllerService) createPolicyLVG(ctx context.Context, policy *lvgpolicycrd.LVGPolicy, node string,
+	acs []*accrd.AvailableCapacity) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method": "createPolicyLVG",
+		"policy": policy.Name,
+	})
+
+	var (
+		name      = uuid.New().String()
+		locations = make([]string, 0, len(acs))
+		size      int64
+	)
+	for _, ac := range acs {
+		locations = append(locations, ac.Spec.Location)
+		size += capacityplanner.SubtractLVMMetadataSize(ac.Spec.Size)
+	}
+	lvg := c.k8sclient.ConstructLVGCR(name, api.LogicalVolumeGroup{
+		Name:      name,
+		Node:      node,
+		Locations: locations,
+		Size:      size,
+		Status:    apiV1.Creating,
+		Health:    apiV1.HealthGood,
+	})
+	lvg.Labels = map[string]string{base.LVGPolicyLabel: policy.Name}
+	if err := c.k8sclient.CreateCR(ctx, name, lvg); err != nil {
+		ll.Errorf("Unable to create LogicalVolumeGroup: %v", err)
+		return
+	}
+	ll.Infof("LogicalVolumeGroup %s was created on node %s from drives %v", name, node, locations)
+	c.consumeDriveACs(ctx, acs)
+
+	acName := uuid.New().String()
+	ac := c.k8sclient.ConstructACCR(acName, api.AvailableCapacity{
+		Location:     name,
+		NodeId:       node,
+		StorageClass: policy.Spec.StorageClass,
+		Size:         size,
+	})
+	if err := c.k8sclient.CreateCR(ctx, acName, ac); err != nil {
+		ll.Errorf("Unable to create AC for LogicalVolumeGroup %s: %v", name, err)
+	}
+}
+
+// extendPolicyLVG adds drives of provided ACs to LogicalVolumeGroup, drive ACs are set to 0.
+// Node extends volume group and increases size of LogicalVolumeGroup and its AC
+func (c *CSIControllerService) extendPolicyLVG(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup,
+	acs []*accrd.AvailableCapacity) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method":  "extendPolicyLVG",
+		"LVGName": lvg.Name,
+	})
+
+	for _, ac := range acs {
+		lvg.Spec.Locations = append(lvg.Spec.Locations, ac.Spec.Location)
+	}
+	if err := c.k8sclient.UpdateCR(ctx, lvg); err != nil {
+		ll.Errorf("Unable to add drives to LogicalVolumeGroup: %v", err)
+		return
+	}
+	ll.Infof("Drives were added to LogicalVolumeGroup, locations: %v", lvg.Spec.Locations)
+	c.consumeDriveACs(ctx, acs)
+}
+
+// consumeDriveACs sets size of ACs of drives which were added to LogicalVolumeGroup to 0
+func (c *CSIControllerService) consumeDriveACs(ctx context.Context, acs []*accrd.AvailableCapacity) {
+	for _, ac := range acs {
+		ac.Spec.Size = 0
+		if err := c.k8sclient.UpdateCR(ctx, ac); err != nil {
+			c.log.WithField("method", "consumeDriveACs").Errorf("Unable to update AC %s: %v", ac.Name, err)
+		}
+	}
+}
+
+// removePolicyLVG removes LogicalVolumeGroup without volumes and its AC, node returns capacity of its drives
+func (c *CSIControllerService) removePolicyLVG(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup,
+	ac *accrd.AvailableCapacity) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method":  "removePolicyLVG",
+		"LVGName": lvg.Name,
+	})
+
+	if ac != nil {
+		if err := c.k8sclient.DeleteCR(ctx, ac); err != nil {
+			ll.Errorf("Unable to delete AC %s: %v", ac.Name, err)
+			return
+		}
+	}
+	if err := c.k8sclient.DeleteCR(ctx, lvg); err != nil {
+		ll.Errorf("Unable to delete LogicalVolumeGroup: %v", err)
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
 
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
@@ -67,6 +68,8 @@ type ServicesStateMonitor struct {
 	nodeHealthMap map[string]*serviceState
 	// mutex to protect map access
 	lock *sync.RWMutex
+	// clock of state timestamps and polling
+	clock clock.Clock
 }
 
 // serviceState keeps current state of the node service and last timestamp when its changed
@@ -90,6 +93,7 @@ func NewNodeServicesStateMonitor(client *k8s.KubeClient, logger *logrus.Logger)
 		crHelper:      k8s.NewCRHelper(client, logger),
 		nodeHealthMap: make(map[string]*serviceState),
 		lock:          &sync.RWMutex{},
+		clock:         clock.RealClock{},
 	}
 }
 
@@ -139,7 +143,7 @@ func (n *ServicesStateMonitor) UpdateNodeHealthCache() {
 	// obtain write lock
 	n.lock.Lock()
 	defer n.lock.Unlock()
-	currentTime := time.Now()
+	currentTime := n.clock.Now()
 	if err == nil {
 		for nodeID, podAndNode := range podToNodeMap {
 			// check pod status
@@ -183,7 +187,7 @@ func (n *ServicesStateMonitor) pollPodsStatus() {
 	for {
 		n.UpdateNodeHealthCache()
 		// sleep before next poll
-		time.Sleep(SleepBeforeNextPoll * time.Second)
+		<-n.clock.After(SleepBeforeNextPoll * time.Second)
 	}
 }
 
@@ -284,6 +288,7 @@ func calculatePodStatus(name string, isReady bool, status int, timePassed float6
 // blocking for read access
 func (n *ServicesStateMonitor) updateCRs() {
 	log := n.log.WithFields(logrus.Fields{"method": "updateCRs"})
+	ctx := context.Background()
 	for {
 		unready := make([]string, 0)
 		permanentDown := make([]string, 0)
@@ -305,7 +310,7 @@ func (n *ServicesStateMonitor) updateCRs() {
 
 		// delete AC for unready
 		for _, id := range unready {
-			err := n.crHelper.DeleteACsByNodeID(id)
+			err := n.crHelper.DeleteACsByNodeID(ctx, id)
 			if err != nil {
 				log.Tracef("Error occurred during AC deletion: %s", err)
 			}
@@ -313,19 +318,19 @@ func (n *ServicesStateMonitor) updateCRs() {
 		// mark disks as OFFLINE when permanentDown
 		// mark volumes as MISSING
 		for _, id := range permanentDown {
-			err := n.crHelper.UpdateDrivesStatusOnNode(id, apiV1.DriveStatusOffline)
+			err := n.crHelper.UpdateDrivesStatusOnNode(ctx, id, apiV1.DriveStatusOffline)
 			if err != nil {
 				log.Tracef("Error occurred during drives status update: %s", err)
 			}
 			// todo create issue to return volume back to OPERATIVE state when node is up
-			err = n.crHelper.UpdateVolumesOpStatusOnNode(id, apiV1.OperationalStatusMissing)
+			err = n.crHelper.UpdateVolumesOpStatusOnNode(ctx, id, apiV1.OperationalStatusMissing)
 			if err != nil {
 				log.Tracef("Error occurred during volumes status update: %s", err)
 			}
 		}
 
 		// sleep before next poll
-		time.Sleep(SleepBeforeNextPoll * time.Second)
+		<-n.clock.After(SleepBeforeNextPoll * time.Second)
 	}
 }
 
@@ -17,6 +17,7 @@ limitations under the License.
 package node
 
 import (
+	"context"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -24,6 +25,10 @@ import (
 
 	coreV1 "k8s.io/api/core/v1"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
 )
 
 var (
@@ -112,3 +117,46 @@ func TestPodIsUnderStartupProtection(t *testing.T) {
 		serviceState{Unready, time.Now(), false},
 		components))
 }
+
+func TestServicesStateMonitor_UpdateNodeHealthCache(t *testing.T) {
+	var (
+		ctx = context.Background()
+		ns  = "default"
+	)
+	client, err := k8s.GetFakeKubeClient(ns, logrus.New())
+	assert.Nil(t, err)
+	fakeClock := clock.NewFakeClock(time.Now())
+	monitor := NewNodeServicesStateMonitor(client, logrus.New())
+	monitor.clock = fakeClock
+
+	node := testNode.DeepCopy()
+	node.UID = types.UID(nodeID)
+	node.Status.Addresses = []coreV1.NodeAddress{{Type: coreV1.NodeHostName, Address: node.Name}}
+	assert.Nil(t, client.CreateCR(ctx, node.Name, node))
+	pod := testPod.DeepCopy()
+	pod.Name = svcPodsMask + "-1"
+	pod.Namespace = ns
+	pod.Spec.NodeName = node.Name
+	for i := range pod.Status.ContainerStatuses {
+		pod.Status.ContainerStatuses[i].Ready = true
+	}
+	assert.Nil(t, client.CreateCR(ctx, pod.Name, pod))
+
+	monitor.UpdateNodeHealthCache()
+	assert.Equal(t, []string{nodeID}, monitor.GetReadyPods())
+
+	pod.Status.ContainerStatuses[0].Ready = false
+	assert.Nil(t, client.UpdateCR(ctx, pod))
+	// status is changed only once timeout is passed
+	monitor.UpdateNodeHealthCache()
+	assert.Equal(t, Ready, monitor.nodeHealthMap[nodeID].status)
+	fakeClock.Step((UnreadyTimeout + 1) * time.Second)
+	monitor.UpdateNodeHealthCache()
+	assert.Equal(t, Unready, monitor.nodeHealthMap[nodeID].status)
+	fakeClock.Step((PermanentDownTimeout - 1) * time.Second)
+	monitor.UpdateNodeHealthCache()
+	assert.Equal(t, Unready, monitor.nodeHealthMap[nodeID].status)
+	fakeClock.Step(2 * time.Second)
+	monitor.UpdateNodeHealthCache()
+	assert.Equal(t, PermanentDown, monitor.nodeHealthMap[nodeID].status)
+}
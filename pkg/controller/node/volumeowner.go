@@ -0,0 +1,163 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+)
+
+// VolumeOwnerMonitor detects volumes which node was removed from the cluster and force releases them
+// on user request. Volumes of removed node are never unstaged and removed by the node service,
+// so pods which use them and removal of their PVs are stuck without force release
+type VolumeOwnerMonitor struct {
+	// client to read nodes and update volumes
+	client *k8s.KubeClient
+	// helper to work with custom resource definition
+	crHelper *k8s.CRHelper
+	log      *logrus.Entry
+	// clock of the check loop
+	clock clock.Clock
+}
+
+// NewVolumeOwnerMonitor is the constructor for VolumeOwnerMonitor
+func NewVolumeOwnerMonitor(client *k8s.KubeClient, logger *logrus.Logger) *VolumeOwnerMonitor {
+	return &VolumeOwnerMonitor{
+		client:   client,
+		crHelper: k8s.NewCRHelper(client, logger),
+		log:      logger.WithField("component", "VolumeOwnerMonitor"),
+		clock:    clock.RealClock{},
+	}
+}
+
+// Run spawns routine which checks volumes each SleepBeforeNextPoll seconds, the first check is delayed as well
+func (m *VolumeOwnerMonitor) Run() {
+	go func() {
+		for {
+			<-m.clock.After(SleepBeforeNextPoll * time.Second)
+			if err := m.CheckVolumes(context.Background()); err != nil {
+				m.log.WithField("method", "Run").Errorf("Unable to check owners of volumes: %v", err)
+			}
+		}
+	}()
+}
+
+// CheckVolumes sets NodeRemoved condition of the volumes and performs force release requested
+// with force-release annotation. Force release is performed only for volumes which node was removed
+// Receives golang context
+// Returns error if nodes or volumes could not be read
+func (m *VolumeOwnerMonitor) CheckVolumes(ctx context.Context) error {
+	ll := m.log.WithField("method", "CheckVolumes")
+
+	nodes, err := m.client.GetNodes(ctx)
+	if err != nil {
+		return err
+	}
+	// empty list is unexpected, volumes are not considered orphaned to avoid mass force release
+	if len(nodes) == 0 {
+		return fmt.Errorf("there are no nodes in the cluster")
+	}
+	nodeIDs := make(map[string]bool, 2*len(nodes))
+	for _, node := range nodes {
+		// node ID is a k8s node UID or value of annotation depending on driver configuration
+		nodeIDs[string(node.UID)] = true
+		if id, ok := node.GetAnnotations()[csibmnodeconst.NodeIDAnnotationKey]; ok {
+			nodeIDs[id] = true
+		}
+	}
+
+	volumes, err := m.crHelper.GetVolumeCRs(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range volumes {
+		volume := &volumes[i]
+		if volume.Spec.NodeId == "" || !volume.DeletionTimestamp.IsZero() {
+			continue
+		}
+		removed := !nodeIDs[volume.Spec.NodeId]
+		message := ""
+		if removed {
+			message = fmt.Sprintf("node %s isn't found in the cluster", volume.Spec.NodeId)
+		}
+		released := false
+		if mode, ok := volume.Annotations[apiV1.VolumeAnnotationForceRelease]; ok {
+			released = m.forceRelease(ctx, volume, mode, removed)
+		}
+		// conditions derived from the spec changed by force release are stored together with NodeRemoved
+		if !volume.SetNodeRemoved(removed, message, metav1.Now()) && !released {
+			continue
+		}
+		if removed {
+			ll.Warnf("Node of volume %s is removed", volume.Name)
+		}
+		if err := m.client.UpdateStatus(ctx, volume); err != nil {
+			ll.Errorf("Unable to update status of volume %s: %v", volume.Name, err)
+		}
+	}
+	return nil
+}
+
+// forceRelease releases volume in requested mode, force-release annotation is replaced with force-release-status.
+// In unschedulable mode volume is marked as missing and is not published anymore, in cleanup mode volume
+// is marked as removed so PV removal could be completed
+// Returns true if spec of the volume was updated, status subresource isn't updated by it
+func (m *VolumeOwnerMonitor) forceRelease(ctx context.Context, volume *volumecrd.Volume, mode string, removed bool) bool {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "forceRelease",
+		"volumeID": volume.Name,
+	})
+
+	status := apiV1.VolumeAnnotationForceReleaseDone
+	switch {
+	case !removed:
+		ll.Errorf("Force release is rejected, node %s of the volume exists", volume.Spec.NodeId)
+		status = apiV1.VolumeAnnotationForceReleaseFailed
+	case mode == apiV1.VolumeAnnotationForceReleaseUnschedulable:
+		volume.Spec.OperationalStatus = apiV1.OperationalStatusMissing
+		volume.Spec.Usage = apiV1.VolumeUsageFailed
+	case mode == apiV1.VolumeAnnotationForceReleaseCleanup:
+		volume.Spec.OperationalStatus = apiV1.OperationalStatusMissing
+		volume.Spec.CSIStatus = apiV1.Removed
+		// finalizer is set by node service which is gone with the node
+		volume.Finalizers = nil
+	default:
+		ll.Errorf("Unsupported force release mode %s", mode)
+		status = apiV1.VolumeAnnotationForceReleaseFailed
+	}
+
+	delete(volume.Annotations, apiV1.VolumeAnnotationForceRelease)
+	volume.Annotations[apiV1.VolumeAnnotationForceReleaseStatus] = status
+	ctxWithID := context.WithValue(ctx, base.RequestUUID, volume.Spec.Id)
+	if err := m.client.UpdateCR(ctxWithID, volume); err != nil {
+		ll.Errorf("Unable to force release volume: %v", err)
+		return false
+	}
+	ll.Infof("Force release in %s mode finished with status %s", mode, status)
+	return true
+}
@@ -0,0 +1,120 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+)
+
+func TestVolumeOwnerMonitor_CheckVolumes(t *testing.T) {
+	var (
+		ctx = context.Background()
+		ns  = "default"
+	)
+	client, err := k8s.GetFakeKubeClient(ns, logrus.New())
+	assert.Nil(t, err)
+	monitor := NewVolumeOwnerMonitor(client, logrus.New())
+
+	// there are no nodes
+	assert.NotNil(t, monitor.CheckVolumes(ctx))
+
+	node := &coreV1.Node{ObjectMeta: k8smetav1.ObjectMeta{Name: "node-1", UID: types.UID("uid-1"),
+		Annotations: map[string]string{csibmnodeconst.NodeIDAnnotationKey: "uuid-1"}}}
+	assert.Nil(t, client.CreateCR(ctx, node.Name, node))
+
+	createVolume := func(id, nodeID string, annotations map[string]string) {
+		volume := client.ConstructVolumeCR(id, ns, api.Volume{Id: id, NodeId: nodeID, CSIStatus: apiV1.Published,
+			OperationalStatus: apiV1.OperationalStatusOperative, Usage: apiV1.VolumeUsageInUse})
+		volume.Annotations = annotations
+		volume.Finalizers = []string{"dell.emc.csi/volume-cleanup"}
+		assert.Nil(t, client.CreateCR(ctx, id, volume))
+	}
+	readVolume := func(id string) *volumecrd.Volume {
+		volume := &volumecrd.Volume{}
+		assert.Nil(t, client.ReadCR(ctx, id, ns, volume))
+		return volume
+	}
+	createVolume("vol-uid", "uid-1", nil)
+	createVolume("vol-uuid", "uuid-1", map[string]string{apiV1.VolumeAnnotationForceRelease: "cleanup"})
+	createVolume("vol-removed", "uid-2", nil)
+	createVolume("vol-unschedulable", "uid-2",
+		map[string]string{apiV1.VolumeAnnotationForceRelease: apiV1.VolumeAnnotationForceReleaseUnschedulable})
+	createVolume("vol-cleanup", "uid-2",
+		map[string]string{apiV1.VolumeAnnotationForceRelease: apiV1.VolumeAnnotationForceReleaseCleanup})
+	createVolume("vol-late-cleanup", "uid-3", nil)
+
+	assert.Nil(t, monitor.CheckVolumes(ctx))
+
+	volume := readVolume("vol-uid")
+	assert.Nil(t, volume.GetCondition(volumecrd.VolumeConditionNodeRemoved))
+
+	// force release is rejected because node exists
+	volume = readVolume("vol-uuid")
+	assert.Equal(t, apiV1.Published, volume.Spec.CSIStatus)
+	assert.NotContains(t, volume.Annotations, apiV1.VolumeAnnotationForceRelease)
+	assert.Equal(t, apiV1.VolumeAnnotationForceReleaseFailed, volume.Annotations[apiV1.VolumeAnnotationForceReleaseStatus])
+	assert.False(t, volume.IsForceReleased())
+
+	volume = readVolume("vol-removed")
+	assert.Equal(t, coreV1.ConditionTrue, volume.GetCondition(volumecrd.VolumeConditionNodeRemoved).Status)
+	assert.Equal(t, apiV1.OperationalStatusOperative, volume.Spec.OperationalStatus)
+
+	volume = readVolume("vol-unschedulable")
+	assert.Equal(t, coreV1.ConditionTrue, volume.GetCondition(volumecrd.VolumeConditionNodeRemoved).Status)
+	assert.True(t, volume.IsForceReleased())
+	assert.Equal(t, apiV1.OperationalStatusMissing, volume.Spec.OperationalStatus)
+	assert.Equal(t, apiV1.VolumeUsageFailed, volume.Spec.Usage)
+	assert.Equal(t, apiV1.Published, volume.Spec.CSIStatus)
+
+	volume = readVolume("vol-cleanup")
+	assert.True(t, volume.IsForceReleased())
+	assert.Equal(t, apiV1.Removed, volume.Spec.CSIStatus)
+	assert.Empty(t, volume.Finalizers)
+	// status derived from the spec is stored in status subresource
+	assert.Equal(t, coreV1.ConditionTrue, volume.GetCondition(volumecrd.VolumeConditionDeletionPending).Status)
+
+	// force release is requested after NodeRemoved condition is set
+	volume = readVolume("vol-late-cleanup")
+	assert.Equal(t, coreV1.ConditionTrue, volume.GetCondition(volumecrd.VolumeConditionNodeRemoved).Status)
+	assert.Equal(t, coreV1.ConditionFalse, volume.GetCondition(volumecrd.VolumeConditionDeletionPending).Status)
+	volume.Annotations = map[string]string{apiV1.VolumeAnnotationForceRelease: apiV1.VolumeAnnotationForceReleaseCleanup}
+	assert.Nil(t, client.UpdateCR(ctx, volume))
+	assert.Nil(t, monitor.CheckVolumes(ctx))
+	volume = readVolume("vol-late-cleanup")
+	assert.True(t, volume.IsForceReleased())
+	assert.Equal(t, coreV1.ConditionTrue, volume.GetCondition(volumecrd.VolumeConditionDeletionPending).Status)
+
+	// node is back
+	node = &coreV1.Node{ObjectMeta: k8smetav1.ObjectMeta{Name: "node-2", UID: types.UID("uid-2")}}
+	assert.Nil(t, client.CreateCR(ctx, node.Name, node))
+	assert.Nil(t, monitor.CheckVolumes(ctx))
+	volume = readVolume("vol-removed")
+	assert.Equal(t, coreV1.ConditionFalse, volume.GetCondition(volumecrd.VolumeConditionNodeRemoved).Status)
+}
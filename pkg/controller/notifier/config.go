@@ -0,0 +1,131 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultCheckInterval is the default interval between checks of drives, capacity and volumes
+	DefaultCheckInterval = time.Minute
+	// DefaultStuckVolumeTimeout is the default time after which volume in Failed status is reported
+	DefaultStuckVolumeTimeout = 10 * time.Minute
+)
+
+// Config is a configuration of notifier which is read from YAML file, it is usually mounted from Secret
+// since it contains credentials of the sinks
+type Config struct {
+	CheckInterval      time.Duration   `yaml:"checkInterval"`
+	StuckVolumeTimeout time.Duration   `yaml:"stuckVolumeTimeout"`
+	Webhooks           []WebhookConfig `yaml:"webhooks"`
+	Slack              []SlackConfig   `yaml:"slack"`
+	SMTP               *SMTPConfig     `yaml:"smtp"`
+	SNMP               []SNMPConfig    `yaml:"snmp"`
+}
+
+// WebhookConfig is a generic webhook, notification is sent in JSON body of POST request
+type WebhookConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+// SlackConfig is a Slack incoming webhook
+type SlackConfig struct {
+	WebhookURL string `yaml:"webhookURL"`
+}
+
+// SMTPConfig is a mail server, notification is sent as plain text mail to all recipients,
+// PLAIN authentication is used if username is set
+type SMTPConfig struct {
+	Address  string   `yaml:"address"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// SNMPConfig is a SNMP manager, notification is sent as SNMPv2c trap over UDP.
+// Trap OID is <enterpriseOID>.0.<number of notification type>, variable bindings are
+// <enterpriseOID>.1.1 (type), .1.2 (node), .1.3 (resource) and .1.4 (message)
+type SNMPConfig struct {
+	// Address is host:port of the manager, port 162 is used if it isn't set
+	Address string `yaml:"address"`
+	// Community is public if it isn't set
+	Community     string `yaml:"community"`
+	EnterpriseOID string `yaml:"enterpriseOID"`
+}
+
+// LoadConfig reads notifier config from YAML file, validates it and sets defaults
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read notifier config %s: %w", path, err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig unmarshalls notifier config from YAML, validates it and sets defaults
+func ParseConfig(data []byte) (*Config, error) {
+	c := &Config{}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal notifier config: %w", err)
+	}
+	if c.CheckInterval < 0 || c.StuckVolumeTimeout < 0 {
+		return nil, errors.New("check interval and stuck volume timeout should be positive")
+	}
+	if c.CheckInterval == 0 {
+		c.CheckInterval = DefaultCheckInterval
+	}
+	if c.StuckVolumeTimeout == 0 {
+		c.StuckVolumeTimeout = DefaultStuckVolumeTimeout
+	}
+	for _, w := range c.Webhooks {
+		if w.URL == "" {
+			return nil, errors.New("webhook url is required")
+		}
+	}
+	for _, s := range c.Slack {
+		if s.WebhookURL == "" {
+			return nil, errors.New("slack webhookURL is required")
+		}
+	}
+	for i := range c.SNMP {
+		if c.SNMP[i].Address == "" {
+			return nil, errors.New("snmp address is required")
+		}
+		if _, err := parseOID(c.SNMP[i].EnterpriseOID); err != nil {
+			return nil, fmt.Errorf("snmp enterpriseOID is invalid: %w", err)
+		}
+		if _, _, err := net.SplitHostPort(c.SNMP[i].Address); err != nil {
+			c.SNMP[i].Address = net.JoinHostPort(c.SNMP[i].Address, defaultSNMPPort)
+		}
+		if c.SNMP[i].Community == "" {
+			c.SNMP[i].Community = defaultSNMPCommunity
+		}
+	}
+	if c.SMTP != nil && (c.SMTP.Address == "" || c.SMTP.From == "" || len(c.SMTP.To) == 0) {
+		return nil, errors.New("smtp address, from and to are required")
+	}
+	return c, nil
+}
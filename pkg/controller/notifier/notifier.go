@@ -0,0 +1,420 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notifier sends notifications about critical conditions of the driver to external systems
+// (generic webhooks, Slack, SMTP and SNMP traps): drive failed, capacity exhausted on a node, volume stuck in Failed status,
+// health of a drive changed and component of the driver failed
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	coreV1 "k8s.io/api/core/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+// types of notifications
+const (
+	DriveFailed       = "DriveFailed"
+	CapacityExhausted = "CapacityExhausted"
+	VolumeStuck       = "VolumeStuck"
+	// DriveHealthChanged is an event, it is sent on each transition of drive health
+	DriveHealthChanged = "DriveHealthChanged"
+	ComponentFailed    = "ComponentFailed"
+)
+
+// componentPodsMask selects pods of the driver in the namespace of the controller
+const componentPodsMask = "csi-baremetal"
+
+const (
+	// StateConfigMap is the name of the ConfigMap in the namespace of the controller which holds notified conditions,
+	// so they aren't sent again and stuck volumes aren't timed from scratch after restart of the controller
+	StateConfigMap = "csi-baremetal-notifier-state"
+	// stateKey is the key of the ConfigMap data with the state in JSON
+	stateKey = "state"
+)
+
+// state is a persisted part of Notifier
+type state struct {
+	Active      []string             `json:"active"`
+	FailedSince map[string]time.Time `json:"failedSince"`
+	DriveHealth map[string]string    `json:"driveHealth"`
+}
+
+// failedContainerReasons are reasons of waiting container state which mean that container failed
+var failedContainerReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// Notification describes critical condition, it is sent once when condition appears
+// and again only if condition was resolved and appeared one more time
+type Notification struct {
+	Type     string    `json:"type"`
+	Node     string    `json:"node"`
+	Resource string    `json:"resource"`
+	Message  string    `json:"message"`
+	Time     time.Time `json:"time"`
+}
+
+// String returns human readable text of the notification
+func (n Notification) String() string {
+	return fmt.Sprintf("%s on node %s: %s", n.Type, n.Node, n.Message)
+}
+
+// key identifies condition of the notification
+func (n Notification) key() string {
+	return n.Type + "/" + n.Resource
+}
+
+// Notifier periodically checks drives, capacity and volumes and sends notifications to the sinks
+type Notifier struct {
+	client *k8s.KubeClient
+	sinks  []Sink
+	config *Config
+
+	// active holds keys of notified conditions which aren't resolved yet
+	active map[string]bool
+	// failedSince holds time when volume was found in Failed status by volume name
+	failedSince map[string]time.Time
+	// driveHealth holds last seen health by drive name
+	driveHealth map[string]string
+	// savedState is the state in JSON which was stored in StateConfigMap last time
+	savedState string
+
+	log *logrus.Entry
+	// clock of the check loop
+	clock clock.Clock
+}
+
+// NewNotifier is the constructor for Notifier struct
+// Receives k8s client, notifier config and logger
+func NewNotifier(client *k8s.KubeClient, config *Config, logger *logrus.Logger) *Notifier {
+	return &Notifier{
+		client:      client,
+		sinks:       NewSinks(config),
+		config:      config,
+		active:      make(map[string]bool),
+		failedSince: make(map[string]time.Time),
+		driveHealth: make(map[string]string),
+		log:         logger.WithField("component", "Notifier"),
+		clock:       clock.RealClock{},
+	}
+}
+
+// Run spawns routine which loads state from StateConfigMap and checks conditions each check interval of the config
+// until stopCh is closed, notifications which are being sent are canceled on stop
+func (n *Notifier) Run(stopCh <-chan struct{}) {
+	ll := n.log.WithField("method", "Run")
+	ll.Infof("Notifications are sent to %d sinks", len(n.sinks))
+	ctx, cancelFn := context.WithCancel(context.Background())
+	go func() {
+		<-stopCh
+		cancelFn()
+	}()
+	go func() {
+		if err := n.LoadState(ctx); err != nil {
+			ll.Errorf("Unable to load state, active conditions are notified again: %v", err)
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				ll.Info("Notifier is stopped")
+				return
+			case <-n.clock.After(n.config.CheckInterval):
+			}
+			if err := n.Check(ctx, n.clock.Now()); err != nil {
+				ll.Errorf("Unable to check conditions: %v", err)
+			}
+		}
+	}()
+}
+
+// LoadState restores notified conditions, failed volumes and health of drives from StateConfigMap,
+// state is empty if ConfigMap doesn't exist
+func (n *Notifier) LoadState(ctx context.Context) error {
+	cm := &coreV1.ConfigMap{}
+	err := n.client.ReadCR(ctx, StateConfigMap, n.client.Namespace, cm)
+	switch {
+	case k8sError.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	st := state{}
+	if err = json.Unmarshal([]byte(cm.Data[stateKey]), &st); err != nil {
+		return fmt.Errorf("unable to decode state: %w", err)
+	}
+	n.active = make(map[string]bool, len(st.Active))
+	for _, key := range st.Active {
+		n.active[key] = true
+	}
+	if st.FailedSince != nil {
+		n.failedSince = st.FailedSince
+	}
+	if st.DriveHealth != nil {
+		n.driveHealth = st.DriveHealth
+	}
+	n.savedState = cm.Data[stateKey]
+	return nil
+}
+
+// saveState stores notified conditions, failed volumes and health of drives in StateConfigMap if they changed
+func (n *Notifier) saveState(ctx context.Context) error {
+	st := state{FailedSince: n.failedSince, DriveHealth: n.driveHealth}
+	for key := range n.active {
+		st.Active = append(st.Active, key)
+	}
+	sort.Strings(st.Active)
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	if string(data) == n.savedState {
+		return nil
+	}
+
+	cm := &coreV1.ConfigMap{}
+	err = n.client.ReadCR(ctx, StateConfigMap, n.client.Namespace, cm)
+	switch {
+	case k8sError.IsNotFound(err):
+		cm = &coreV1.ConfigMap{
+			ObjectMeta: metaV1.ObjectMeta{Name: StateConfigMap, Namespace: n.client.Namespace},
+			Data:       map[string]string{stateKey: string(data)},
+		}
+		err = n.client.Create(ctx, cm)
+	case err == nil:
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[stateKey] = string(data)
+		err = n.client.Update(ctx, cm)
+	}
+	if err != nil {
+		return err
+	}
+	n.savedState = string(data)
+	return nil
+}
+
+// Check finds critical conditions, sends notifications about new ones and stores state in StateConfigMap
+// Receives golang context and current time
+// Returns error if CRs could not be read or state could not be stored
+func (n *Notifier) Check(ctx context.Context, now time.Time) error {
+	drives := &drivecrd.DriveList{}
+	if err := n.client.ReadList(ctx, drives); err != nil {
+		return err
+	}
+	acs := &accrd.AvailableCapacityList{}
+	if err := n.client.ReadList(ctx, acs); err != nil {
+		return err
+	}
+	volumes := &volumecrd.VolumeList{}
+	if err := n.client.ReadList(ctx, volumes); err != nil {
+		return err
+	}
+	pods, err := n.client.GetPods(ctx, componentPodsMask)
+	if err != nil {
+		return err
+	}
+
+	for _, notification := range n.healthTransitions(drives.Items, now) {
+		n.send(ctx, notification)
+	}
+
+	found := n.failedDrives(drives.Items, now)
+	found = append(found, n.exhaustedNodes(acs.Items, now)...)
+	found = append(found, n.stuckVolumes(volumes.Items, now)...)
+	found = append(found, n.failedComponents(pods, now)...)
+
+	current := make(map[string]bool, len(found))
+	for _, notification := range found {
+		current[notification.key()] = true
+		if n.active[notification.key()] {
+			continue
+		}
+		n.send(ctx, notification)
+	}
+	// resolved conditions are notified again if they appear one more time
+	n.active = current
+	if err = n.saveState(ctx); err != nil {
+		return fmt.Errorf("unable to store state in ConfigMap %s: %w", StateConfigMap, err)
+	}
+	return nil
+}
+
+// failedDrives returns notifications about drives with BAD health or FAILED usage
+func (n *Notifier) failedDrives(drives []drivecrd.Drive, now time.Time) []Notification {
+	var result []Notification
+	for _, d := range drives {
+		if d.Spec.Health != apiV1.HealthBad && d.Spec.Usage != apiV1.DriveUsageFailed {
+			continue
+		}
+		result = append(result, Notification{
+			Type:     DriveFailed,
+			Node:     d.Spec.NodeId,
+			Resource: d.Name,
+			Message: fmt.Sprintf("drive %s (serial number %s, slot %s) has health %s and usage %s",
+				d.Name, d.Spec.SerialNumber, d.Spec.Slot, d.Spec.Health, d.Spec.Usage),
+			Time: now,
+		})
+	}
+	return result
+}
+
+// healthTransitions returns notifications about drives which health differs from the previous check,
+// drives which are seen for the first time aren't reported
+func (n *Notifier) healthTransitions(drives []drivecrd.Drive, now time.Time) []Notification {
+	var (
+		result []Notification
+		health = make(map[string]string, len(drives))
+	)
+	for _, d := range drives {
+		health[d.Name] = d.Spec.Health
+		previous, ok := n.driveHealth[d.Name]
+		if !ok || previous == d.Spec.Health {
+			continue
+		}
+		result = append(result, Notification{
+			Type:     DriveHealthChanged,
+			Node:     d.Spec.NodeId,
+			Resource: d.Name,
+			Message: fmt.Sprintf("health of drive %s (serial number %s, slot %s) changed from %s to %s",
+				d.Name, d.Spec.SerialNumber, d.Spec.Slot, previous, d.Spec.Health),
+			Time: now,
+		})
+	}
+	n.driveHealth = health
+	return result
+}
+
+// exhaustedNodes returns notifications about nodes which have AvailableCapacities but all of them are empty
+func (n *Notifier) exhaustedNodes(acs []accrd.AvailableCapacity, now time.Time) []Notification {
+	free := make(map[string]int64)
+	for _, ac := range acs {
+		free[ac.Spec.NodeId] += ac.Spec.Size
+	}
+	nodes := make([]string, 0, len(free))
+	for node, size := range free {
+		if size == 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Strings(nodes)
+	result := make([]Notification, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, Notification{
+			Type:     CapacityExhausted,
+			Node:     node,
+			Resource: node,
+			Message:  "there is no available capacity for new volumes",
+			Time:     now,
+		})
+	}
+	return result
+}
+
+// stuckVolumes returns notifications about volumes which are in Failed status longer than stuck volume timeout
+func (n *Notifier) stuckVolumes(volumes []volumecrd.Volume, now time.Time) []Notification {
+	var (
+		result []Notification
+		failed = make(map[string]time.Time)
+	)
+	for _, v := range volumes {
+		if v.Spec.CSIStatus != apiV1.Failed {
+			continue
+		}
+		since, ok := n.failedSince[v.Name]
+		if !ok {
+			since = now
+		}
+		failed[v.Name] = since
+		if now.Sub(since) < n.config.StuckVolumeTimeout {
+			continue
+		}
+		result = append(result, Notification{
+			Type:     VolumeStuck,
+			Node:     v.Spec.NodeId,
+			Resource: v.Namespace + "/" + v.Name,
+			Message: fmt.Sprintf("volume %s/%s is in %s status longer than %s",
+				v.Namespace, v.Name, v.Spec.CSIStatus, n.config.StuckVolumeTimeout),
+			Time: now,
+		})
+	}
+	n.failedSince = failed
+	return result
+}
+
+// failedComponents returns notifications about containers of the driver pods which are crashing or can't be started
+func (n *Notifier) failedComponents(pods []*coreV1.Pod, now time.Time) []Notification {
+	var result []Notification
+	for _, pod := range pods {
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			var reason string
+			switch {
+			case status.State.Waiting != nil && failedContainerReasons[status.State.Waiting.Reason]:
+				reason = status.State.Waiting.Reason
+			case status.State.Terminated != nil && status.State.Terminated.ExitCode != 0:
+				reason = fmt.Sprintf("%s (exit code %d)", status.State.Terminated.Reason, status.State.Terminated.ExitCode)
+			default:
+				continue
+			}
+			result = append(result, Notification{
+				Type:     ComponentFailed,
+				Node:     pod.Spec.NodeName,
+				Resource: pod.Name + "/" + status.Name,
+				Message: fmt.Sprintf("container %s of pod %s failed: %s, restarted %d times",
+					status.Name, pod.Name, reason, status.RestartCount),
+				Time: now,
+			})
+		}
+	}
+	return result
+}
+
+// send delivers notification to all sinks, delivery errors are logged
+func (n *Notifier) send(ctx context.Context, notification Notification) {
+	ll := n.log.WithFields(logrus.Fields{
+		"method":   "send",
+		"type":     notification.Type,
+		"resource": notification.Resource,
+	})
+	ll.Warn(notification.String())
+	for _, sink := range n.sinks {
+		sinkCtx, cancelFn := context.WithTimeout(ctx, sinkTimeout)
+		if err := sink.Send(sinkCtx, notification); err != nil {
+			ll.Errorf("Unable to send notification to %s sink: %v", sink.Name(), err)
+		}
+		cancelFn()
+	}
+}
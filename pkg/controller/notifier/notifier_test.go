@@ -0,0 +1,353 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+const (
+	testNS   = "default"
+	testNode = "node-1"
+)
+
+var testLogger = logrus.New()
+
+type testSink struct {
+	sent []Notification
+	err  error
+}
+
+func (s *testSink) Name() string {
+	return "test"
+}
+
+func (s *testSink) Send(ctx context.Context, n Notification) error {
+	s.sent = append(s.sent, n)
+	return s.err
+}
+
+func (s *testSink) ofType(notificationType string) []Notification {
+	var result []Notification
+	for _, n := range s.sent {
+		if n.Type == notificationType {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+func TestParseConfig(t *testing.T) {
+	c, err := ParseConfig([]byte("webhooks:\n- url: http://hook\nslack:\n- webhookURL: http://slack\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultCheckInterval, c.CheckInterval)
+	assert.Equal(t, DefaultStuckVolumeTimeout, c.StuckVolumeTimeout)
+	assert.Len(t, NewSinks(c), 2)
+
+	c, err = ParseConfig([]byte("checkInterval: 30s\nstuckVolumeTimeout: 5m\n" +
+		"smtp:\n  address: smtp:25\n  from: csi@example.com\n  to: [admin@example.com]\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 30*time.Second, c.CheckInterval)
+	assert.Equal(t, 5*time.Minute, c.StuckVolumeTimeout)
+	assert.Len(t, NewSinks(c), 1)
+
+	for _, data := range []string{
+		"webhooks:\n- headers: {}\n",
+		"slack:\n- webhookURL: \"\"\n",
+		"smtp:\n  address: smtp:25\n",
+		"checkInterval: -1s\n",
+		"unknown: field\n",
+	} {
+		_, err = ParseConfig([]byte(data))
+		assert.NotNil(t, err, data)
+	}
+}
+
+func TestNotifier_DriveFailed(t *testing.T) {
+	n, client, sink := setup(t)
+	ctx := context.Background()
+	drive := api.Drive{UUID: "uuid-1", NodeId: testNode, SerialNumber: "sn-1",
+		Health: apiV1.HealthGood, Usage: apiV1.DriveUsageInUse}
+	driveCR := client.ConstructDriveCR(drive.UUID, drive)
+	assert.Nil(t, client.CreateCR(ctx, drive.UUID, driveCR))
+
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	assert.Empty(t, sink.sent)
+
+	driveCR.Spec.Health = apiV1.HealthBad
+	assert.Nil(t, client.UpdateCR(ctx, driveCR))
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	failed := sink.ofType(DriveFailed)
+	assert.Len(t, failed, 1)
+	assert.Equal(t, testNode, failed[0].Node)
+	assert.Contains(t, failed[0].Message, "sn-1")
+
+	// notified once
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	assert.Len(t, sink.ofType(DriveFailed), 1)
+
+	// notified again after recovery
+	driveCR.Spec.Health = apiV1.HealthGood
+	assert.Nil(t, client.UpdateCR(ctx, driveCR))
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	driveCR.Spec.Usage = apiV1.DriveUsageFailed
+	assert.Nil(t, client.UpdateCR(ctx, driveCR))
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	assert.Len(t, sink.ofType(DriveFailed), 2)
+}
+
+func TestNotifier_CapacityExhausted(t *testing.T) {
+	n, client, sink := setup(t)
+	ctx := context.Background()
+	acs := []api.AvailableCapacity{
+		{Location: "uuid-1", NodeId: testNode, Size: 0},
+		{Location: "uuid-2", NodeId: testNode, Size: 0},
+		{Location: "uuid-3", NodeId: "node-2", Size: 100},
+	}
+	for _, ac := range acs {
+		assert.Nil(t, client.CreateCR(ctx, ac.Location, client.ConstructACCR(ac.Location, ac)))
+	}
+
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	assert.Len(t, sink.sent, 1)
+	assert.Equal(t, CapacityExhausted, sink.sent[0].Type)
+	assert.Equal(t, testNode, sink.sent[0].Node)
+}
+
+func TestNotifier_VolumeStuck(t *testing.T) {
+	n, client, sink := setup(t)
+	ctx := context.Background()
+	volume := api.Volume{Id: "volume-1", NodeId: testNode, CSIStatus: apiV1.Failed}
+	assert.Nil(t, client.CreateCR(ctx, volume.Id, client.ConstructVolumeCR(volume.Id, testNS, volume)))
+
+	now := time.Now()
+	assert.Nil(t, n.Check(ctx, now))
+	assert.Empty(t, sink.sent)
+	assert.Nil(t, n.Check(ctx, now.Add(DefaultStuckVolumeTimeout/2)))
+	assert.Empty(t, sink.sent)
+	assert.Nil(t, n.Check(ctx, now.Add(DefaultStuckVolumeTimeout)))
+	assert.Len(t, sink.sent, 1)
+	assert.Equal(t, VolumeStuck, sink.sent[0].Type)
+	assert.Equal(t, testNS+"/volume-1", sink.sent[0].Resource)
+}
+
+func TestNotifier_DriveHealthChanged(t *testing.T) {
+	n, client, sink := setup(t)
+	ctx := context.Background()
+	drive := api.Drive{UUID: "uuid-1", NodeId: testNode, Health: apiV1.HealthGood, Usage: apiV1.DriveUsageInUse}
+	driveCR := client.ConstructDriveCR(drive.UUID, drive)
+	assert.Nil(t, client.CreateCR(ctx, drive.UUID, driveCR))
+
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	assert.Empty(t, sink.sent)
+
+	driveCR.Spec.Health = apiV1.HealthSuspect
+	assert.Nil(t, client.UpdateCR(ctx, driveCR))
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	assert.Len(t, sink.sent, 1)
+	assert.Equal(t, DriveHealthChanged, sink.sent[0].Type)
+	assert.Contains(t, sink.sent[0].Message, "from GOOD to SUSPECT")
+
+	// each transition is sent
+	driveCR.Spec.Health = apiV1.HealthGood
+	assert.Nil(t, client.UpdateCR(ctx, driveCR))
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	assert.Len(t, sink.sent, 2)
+}
+
+func TestNotifier_ComponentFailed(t *testing.T) {
+	n, client, sink := setup(t)
+	ctx := context.Background()
+	pod := &coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "csi-baremetal-node-abc", Namespace: testNS},
+		Spec:       coreV1.PodSpec{NodeName: "worker-1"},
+		Status: coreV1.PodStatus{ContainerStatuses: []coreV1.ContainerStatus{
+			{Name: "node", State: coreV1.ContainerState{Running: &coreV1.ContainerStateRunning{}}},
+			{Name: "drivemgr", RestartCount: 3, State: coreV1.ContainerState{
+				Waiting: &coreV1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+		}},
+	}
+	assert.Nil(t, client.Create(ctx, pod))
+	other := &coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "app", Namespace: testNS},
+		Status: coreV1.PodStatus{ContainerStatuses: []coreV1.ContainerStatus{
+			{Name: "app", State: coreV1.ContainerState{Terminated: &coreV1.ContainerStateTerminated{ExitCode: 1}}},
+		}},
+	}
+	assert.Nil(t, client.Create(ctx, other))
+
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	assert.Len(t, sink.sent, 1)
+	assert.Equal(t, ComponentFailed, sink.sent[0].Type)
+	assert.Equal(t, "worker-1", sink.sent[0].Node)
+	assert.Equal(t, "csi-baremetal-node-abc/drivemgr", sink.sent[0].Resource)
+	assert.Contains(t, sink.sent[0].Message, "CrashLoopBackOff")
+}
+
+func TestNotifier_SinkError(t *testing.T) {
+	n, client, sink := setup(t)
+	ctx := context.Background()
+	sink.err = errors.New("unavailable")
+	failing := &testSink{err: errors.New("unavailable")}
+	n.sinks = []Sink{failing, sink}
+	drive := api.Drive{UUID: "uuid-1", NodeId: testNode, Health: apiV1.HealthBad}
+	assert.Nil(t, client.CreateCR(ctx, drive.UUID, client.ConstructDriveCR(drive.UUID, drive)))
+
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	assert.Len(t, failing.sent, 1)
+	assert.Len(t, sink.sent, 1)
+}
+
+func TestNotifier_State(t *testing.T) {
+	n, client, sink := setup(t)
+	ctx := context.Background()
+	drive := api.Drive{UUID: "uuid-1", NodeId: testNode, Health: apiV1.HealthBad}
+	assert.Nil(t, client.CreateCR(ctx, drive.UUID, client.ConstructDriveCR(drive.UUID, drive)))
+	assert.Nil(t, n.Check(ctx, time.Now()))
+	assert.Len(t, sink.ofType(DriveFailed), 1)
+
+	// restarted notifier doesn't notify the same condition again
+	c, err := ParseConfig([]byte(""))
+	assert.Nil(t, err)
+	restarted := NewNotifier(client, c, testLogger)
+	restartedSink := &testSink{}
+	restarted.sinks = []Sink{restartedSink}
+	assert.Nil(t, restarted.LoadState(ctx))
+	assert.Equal(t, n.driveHealth, restarted.driveHealth)
+	assert.Nil(t, restarted.Check(ctx, time.Now()))
+	assert.Empty(t, restartedSink.sent)
+
+	cm := &coreV1.ConfigMap{}
+	assert.Nil(t, client.ReadCR(ctx, StateConfigMap, testNS, cm))
+	assert.Contains(t, cm.Data[stateKey], DriveFailed+"/"+drive.UUID)
+}
+
+func TestNotifier_RunStop(t *testing.T) {
+	n, client, sink := setup(t)
+	drive := api.Drive{UUID: "uuid-1", NodeId: testNode, Health: apiV1.HealthBad}
+	assert.Nil(t, client.CreateCR(context.Background(), drive.UUID, client.ConstructDriveCR(drive.UUID, drive)))
+	fakeClock := clock.NewFakeClock(time.Now())
+	n.clock = fakeClock
+	stopCh := make(chan struct{})
+	n.Run(stopCh)
+	assert.Eventually(t, fakeClock.HasWaiters, time.Second, 10*time.Millisecond)
+
+	// conditions aren't checked after stop
+	close(stopCh)
+	time.Sleep(50 * time.Millisecond)
+	fakeClock.Step(n.config.CheckInterval)
+	assert.Never(t, func() bool { return len(sink.sent) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestSendMail_Timeout(t *testing.T) {
+	// server accepts connection, but doesn't send greeting
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelFn()
+	start := time.Now()
+	err = sendMail(ctx, listener.Addr().String(), nil, "csi@example.com", []string{"admin@example.com"}, nil)
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestSinks(t *testing.T) {
+	var (
+		bodies  []map[string]interface{}
+		headers []string
+		status  = http.StatusOK
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		headers = append(headers, r.Header.Get("Authorization"))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	c := &Config{
+		Webhooks: []WebhookConfig{{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}}},
+		Slack:    []SlackConfig{{WebhookURL: server.URL}},
+		SMTP: &SMTPConfig{Address: "smtp.example.com:587", Username: "user", Password: "pass",
+			From: "csi@example.com", To: []string{"admin@example.com"}},
+	}
+	sinks := NewSinks(c)
+	assert.Len(t, sinks, 3)
+	var mail string
+	sinks[2].(*smtpSink).send = func(ctx context.Context, addr string, a smtp.Auth, from string, to []string,
+		msg []byte) error {
+		assert.Equal(t, c.SMTP.Address, addr)
+		assert.NotNil(t, a)
+		mail = string(msg)
+		return nil
+	}
+
+	notification := Notification{Type: DriveFailed, Node: testNode, Resource: "uuid-1", Message: "drive failed"}
+	for _, s := range sinks {
+		assert.Nil(t, s.Send(context.Background(), notification), s.Name())
+	}
+	assert.Len(t, bodies, 2)
+	assert.Equal(t, DriveFailed, bodies[0]["type"])
+	assert.Equal(t, "Bearer token", headers[0])
+	assert.Equal(t, notification.String(), bodies[1]["text"])
+	assert.Empty(t, headers[1])
+	assert.True(t, strings.Contains(mail, "Subject: [csi-baremetal] "+DriveFailed))
+
+	status = http.StatusInternalServerError
+	assert.NotNil(t, sinks[0].Send(context.Background(), notification))
+}
+
+func setup(t *testing.T) (*Notifier, *k8s.KubeClient, *testSink) {
+	client, err := k8s.GetFakeKubeClient(testNS, testLogger)
+	assert.Nil(t, err)
+	c, err := ParseConfig([]byte(""))
+	assert.Nil(t, err)
+	n := NewNotifier(client, c, testLogger)
+	sink := &testSink{}
+	n.sinks = []Sink{sink}
+	return n, client, sink
+}
@@ -0,0 +1,200 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+const (
+	// sinkTimeout limits delivery of one notification to one sink
+	sinkTimeout = 30 * time.Second
+	// smtpDialTimeout limits connection to the mail server
+	smtpDialTimeout = 10 * time.Second
+)
+
+// Sink delivers notifications to external system
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, n Notification) error
+}
+
+// NewSinks creates sinks configured in c
+func NewSinks(c *Config) []Sink {
+	client := &http.Client{Timeout: sinkTimeout}
+	sinks := make([]Sink, 0, len(c.Webhooks)+len(c.Slack)+len(c.SNMP)+1)
+	for _, w := range c.Webhooks {
+		sinks = append(sinks, &webhookSink{client: client, url: w.URL, headers: w.Headers})
+	}
+	for _, s := range c.Slack {
+		sinks = append(sinks, &slackSink{client: client, url: s.WebhookURL})
+	}
+	if c.SMTP != nil {
+		sinks = append(sinks, &smtpSink{config: *c.SMTP, send: sendMail})
+	}
+	for _, s := range c.SNMP {
+		sinks = append(sinks, newSNMPSink(s))
+	}
+	return sinks
+}
+
+// webhookSink sends notification as JSON body of POST request
+type webhookSink struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+func (s *webhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *webhookSink) Send(ctx context.Context, n Notification) error {
+	return postJSON(ctx, s.client, s.url, s.headers, n)
+}
+
+// slackSink sends notification text to Slack incoming webhook
+type slackSink struct {
+	client *http.Client
+	url    string
+}
+
+func (s *slackSink) Name() string {
+	return "slack"
+}
+
+func (s *slackSink) Send(ctx context.Context, n Notification) error {
+	return postJSON(ctx, s.client, s.url, nil, map[string]string{"text": n.String()})
+}
+
+// smtpSink sends notification as plain text mail
+type smtpSink struct {
+	config SMTPConfig
+	// send is sendMail, it is replaced in UTs
+	send func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func (s *smtpSink) Name() string {
+	return "smtp"
+}
+
+func (s *smtpSink) Send(ctx context.Context, n Notification) error {
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		host, _, err := net.SplitHostPort(s.config.Address)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, host)
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: [csi-baremetal] %s\r\n\r\n%s\r\n",
+		s.config.From, strings.Join(s.config.To, ", "), n.Type, n.String())
+	return s.send(ctx, s.config.Address, auth, s.config.From, s.config.To, []byte(msg))
+}
+
+// sendMail works as smtp.SendMail, but connection is limited by smtpDialTimeout and the session by deadline of ctx
+func sendMail(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	conn, err := (&net.Dialer{Timeout: smtpDialTimeout}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			_ = conn.Close()
+			return err
+		}
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = c.Close() }()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp server doesn't support AUTH")
+		}
+		if err = c.Auth(a); err != nil {
+			return err
+		}
+	}
+	if err = c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err = c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(msg); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// postJSON sends body as JSON in POST request to url, response with status other than 2xx is an error
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
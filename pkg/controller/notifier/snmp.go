@@ -0,0 +1,214 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultSNMPPort      = "162"
+	defaultSNMPCommunity = "public"
+
+	// snmpVersion2c is a value of version field of SNMPv2c message
+	snmpVersion2c = 1
+
+	// BER tags
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagOID         = 0x06
+	tagSequence    = 0x30
+	tagTimeTicks   = 0x43
+	tagTrapV2      = 0xa7
+)
+
+var (
+	// sysUpTime.0 is the first variable binding of SNMPv2 trap
+	sysUpTimeOID = []uint32{1, 3, 6, 1, 2, 1, 1, 3, 0}
+	// snmpTrapOID.0 is the second variable binding of SNMPv2 trap, its value is OID of the trap
+	snmpTrapOID = []uint32{1, 3, 6, 1, 6, 3, 1, 1, 4, 1, 0}
+
+	// snmpTrapNumbers are last arcs of trap OIDs by notification type, they are part of the MIB and shouldn't change
+	snmpTrapNumbers = map[string]uint32{
+		DriveFailed:        1,
+		CapacityExhausted:  2,
+		VolumeStuck:        3,
+		DriveHealthChanged: 4,
+		ComponentFailed:    5,
+	}
+)
+
+// snmpSink sends notification as SNMPv2c trap
+type snmpSink struct {
+	config     SNMPConfig
+	enterprise []uint32
+	started    time.Time
+	requestID  int32
+}
+
+func newSNMPSink(config SNMPConfig) *snmpSink {
+	// OID is validated by ParseConfig
+	enterprise, _ := parseOID(config.EnterpriseOID)
+	return &snmpSink{config: config, enterprise: enterprise, started: time.Now()}
+}
+
+func (s *snmpSink) Name() string {
+	return "snmp"
+}
+
+func (s *snmpSink) Send(ctx context.Context, n Notification) error {
+	number, ok := snmpTrapNumbers[n.Type]
+	if !ok {
+		return fmt.Errorf("there is no trap for notification type %s", n.Type)
+	}
+	msg := s.trap(number, n)
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", s.config.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetWriteDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	_, err = conn.Write(msg)
+	return err
+}
+
+// trap encodes SNMPv2c message with SNMPv2-Trap-PDU for the notification
+func (s *snmpSink) trap(number uint32, n Notification) []byte {
+	oid := func(arcs ...uint32) []uint32 {
+		return append(append([]uint32{}, s.enterprise...), arcs...)
+	}
+	upTime := uint32(time.Since(s.started) / (10 * time.Millisecond))
+	varBinds := [][]byte{
+		berVarBind(sysUpTimeOID, berTLV(tagTimeTicks, berUint(upTime))),
+		berVarBind(snmpTrapOID, berOID(oid(0, number))),
+		berVarBind(oid(1, 1), berOctetString(n.Type)),
+		berVarBind(oid(1, 2), berOctetString(n.Node)),
+		berVarBind(oid(1, 3), berOctetString(n.Resource)),
+		berVarBind(oid(1, 4), berOctetString(n.Message)),
+	}
+	pdu := berTLV(tagTrapV2,
+		berInteger(int64(atomic.AddInt32(&s.requestID, 1))),
+		berInteger(0), // error-status
+		berInteger(0), // error-index
+		berTLV(tagSequence, varBinds...))
+	return berTLV(tagSequence, berInteger(snmpVersion2c), berOctetString(s.config.Community), pdu)
+}
+
+// parseOID parses dotted OID, e.g. 1.3.6.1.4.1
+func parseOID(oid string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimPrefix(oid, "."), ".")
+	if len(parts) < 2 {
+		return nil, errors.New("OID should have at least 2 arcs")
+	}
+	arcs := make([]uint32, 0, len(parts))
+	for _, part := range parts {
+		arc, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("arc %q of OID %s isn't a number", part, oid)
+		}
+		arcs = append(arcs, uint32(arc))
+	}
+	if arcs[0] > 2 || (arcs[0] < 2 && arcs[1] > 39) {
+		return nil, fmt.Errorf("OID %s has invalid first arcs", oid)
+	}
+	return arcs, nil
+}
+
+func berVarBind(oid []uint32, value []byte) []byte {
+	return berTLV(tagSequence, berOID(oid), value)
+}
+
+// berTLV encodes tag, length and concatenated values
+func berTLV(tag byte, values ...[]byte) []byte {
+	var content []byte
+	for _, v := range values {
+		content = append(content, v...)
+	}
+	result := append([]byte{tag}, berLength(len(content))...)
+	return append(result, content...)
+}
+
+// berLength encodes length in short form if it is less than 128 and in long form otherwise
+func berLength(l int) []byte {
+	if l < 0x80 {
+		return []byte{byte(l)}
+	}
+	var b []byte
+	for ; l > 0; l >>= 8 {
+		b = append([]byte{byte(l)}, b...)
+	}
+	return append([]byte{0x80 | byte(len(b))}, b...)
+}
+
+// berInteger encodes signed integer in minimal two's complement form
+func berInteger(i int64) []byte {
+	b := []byte{byte(i)}
+	for i >>= 8; ; i >>= 8 {
+		// stop when the rest is sign extension of the most significant encoded bit
+		if (i == 0 && b[0]&0x80 == 0) || (i == -1 && b[0]&0x80 != 0) {
+			break
+		}
+		b = append([]byte{byte(i)}, b...)
+	}
+	return berTLV(tagInteger, b)
+}
+
+// berUint returns content of unsigned application types (TimeTicks, Counter32, Gauge32)
+func berUint(u uint32) []byte {
+	b := []byte{byte(u)}
+	for u >>= 8; u > 0; u >>= 8 {
+		b = append([]byte{byte(u)}, b...)
+	}
+	if b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	return b
+}
+
+func berOctetString(s string) []byte {
+	return berTLV(tagOctetString, []byte(s))
+}
+
+// berOID encodes OID, first two arcs are packed into one subidentifier, each subidentifier is base-128
+func berOID(oid []uint32) []byte {
+	content := berSubID(oid[0]*40 + oid[1])
+	for _, arc := range oid[2:] {
+		content = append(content, berSubID(arc)...)
+	}
+	return berTLV(tagOID, content)
+}
+
+func berSubID(arc uint32) []byte {
+	b := []byte{byte(arc & 0x7f)}
+	for arc >>= 7; arc > 0; arc >>= 7 {
+		b = append([]byte{byte(arc&0x7f) | 0x80}, b...)
+	}
+	return b
+}
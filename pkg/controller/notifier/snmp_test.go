@@ -0,0 +1,107 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testEnterpriseOID = "1.3.6.1.4.1.674.99"
+
+func TestBEREncoding(t *testing.T) {
+	assert.Equal(t, []byte{0x06, 0x08, 0x2b, 0x06, 0x01, 0x02, 0x01, 0x01, 0x03, 0x00}, berOID(sysUpTimeOID))
+	assert.Equal(t, []byte{0x06, 0x07, 0x2b, 0x06, 0x01, 0x04, 0x01, 0x85, 0x22}, berOID([]uint32{1, 3, 6, 1, 4, 1, 674}))
+
+	assert.Equal(t, []byte{0x02, 0x01, 0x00}, berInteger(0))
+	assert.Equal(t, []byte{0x02, 0x01, 0x7f}, berInteger(127))
+	assert.Equal(t, []byte{0x02, 0x02, 0x00, 0x80}, berInteger(128))
+	assert.Equal(t, []byte{0x02, 0x01, 0xff}, berInteger(-1))
+	assert.Equal(t, []byte{0x02, 0x02, 0xff, 0x7f}, berInteger(-129))
+
+	assert.Equal(t, []byte{0x00, 0x80}, berUint(128))
+	assert.Equal(t, []byte{0x7f}, berLength(127))
+	assert.Equal(t, []byte{0x81, 0x80}, berLength(128))
+	assert.Equal(t, []byte{0x82, 0x01, 0x00}, berLength(256))
+}
+
+func TestParseOID(t *testing.T) {
+	oid, err := parseOID(".1.3.6.1.4.1.674")
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{1, 3, 6, 1, 4, 1, 674}, oid)
+
+	for _, invalid := range []string{"", "1", "1.3.a", "3.1", "1.40.1", "1..3"} {
+		_, err = parseOID(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestSNMPSink_Send(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	c, err := ParseConfig([]byte("snmp:\n- address: " + conn.LocalAddr().String() +
+		"\n  enterpriseOID: " + testEnterpriseOID + "\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, defaultSNMPCommunity, c.SNMP[0].Community)
+	sinks := NewSinks(c)
+	assert.Len(t, sinks, 1)
+
+	notification := Notification{Type: DriveFailed, Node: testNode, Resource: "uuid-1", Message: "drive failed"}
+	assert.Nil(t, sinks[0].Send(context.Background(), notification))
+
+	buf := make([]byte, 1500)
+	assert.Nil(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	size, _, err := conn.ReadFrom(buf)
+	assert.Nil(t, err)
+	msg := buf[:size]
+
+	// message header: SEQUENCE, version 2c and community
+	assert.Equal(t, byte(tagSequence), msg[0])
+	assert.True(t, bytes.Contains(msg, []byte{0x02, 0x01, 0x01, 0x04, 0x06, 'p', 'u', 'b', 'l', 'i', 'c', tagTrapV2}))
+	enterprise, _ := parseOID(testEnterpriseOID)
+	trapOID := berOID(append(enterprise, 0, snmpTrapNumbers[DriveFailed]))
+	assert.True(t, bytes.Contains(msg, append(berOID(snmpTrapOID), trapOID...)))
+	for _, value := range []string{DriveFailed, testNode, "uuid-1", "drive failed"} {
+		assert.True(t, bytes.Contains(msg, berOctetString(value)), value)
+	}
+
+	assert.NotNil(t, sinks[0].Send(context.Background(), Notification{Type: "unknown"}))
+}
+
+func TestParseConfig_SNMP(t *testing.T) {
+	c, err := ParseConfig([]byte("snmp:\n- address: nms.example.com\n  community: noc\n  enterpriseOID: " +
+		testEnterpriseOID + "\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "nms.example.com:162", c.SNMP[0].Address)
+	assert.Equal(t, "noc", c.SNMP[0].Community)
+
+	for _, data := range []string{
+		"snmp:\n- enterpriseOID: " + testEnterpriseOID + "\n",
+		"snmp:\n- address: nms.example.com\n",
+		"snmp:\n- address: nms.example.com\n  enterpriseOID: dell\n",
+	} {
+		_, err = ParseConfig([]byte(data))
+		assert.NotNil(t, err, data)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
+
+// driveLocations returns locations (drives and LogicalVolumeGroups on them) on which volume could be placed
+// Volume is pinned by serial number/WWN or by label selector of drives in StorageClass parameters,
+// PVC annotations override StorageClass parameters
+// Drive selector from StorageClass parameters restricts capacity of the StorageClass to the drives
+// which labels match it, if volume is pinned as well then pinned drives have to match drive selector
+// Scheduler extender doesn't take pinning and drive selector into account, so for WaitForFirstConsumer
+// binding mode pod should be scheduled on the node of the selected drives, otherwise volume creation fails
+// Receives golang context, parameters of CreateVolumeRequest, CSI storage class and preferred node of the volume
+// Returns nil if volume isn't restricted to drives or error if selected drives can't be used for the volume
+func (c *CSIControllerService) driveLocations(ctx context.Context, params map[string]string,
+	storageClass, preferredNode string) ([]string, error) {
+	ll := c.log.WithField("method", "driveLocations")
+
+	pvcAnnotations, err := c.pvcAnnotations(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	pinnedDrive, pinnedLabel := params[base.PinnedDriveKey], params[base.PinnedDriveLabelKey]
+	drive, hasDrive := pvcAnnotations[base.PVCAnnotationPinnedDrive]
+	label, hasLabel := pvcAnnotations[base.PVCAnnotationPinnedDriveLabel]
+	if hasDrive || hasLabel {
+		pinnedDrive, pinnedLabel = drive, label
+	}
+	driveSelector := params[base.DriveSelectorKey]
+	if pinnedDrive == "" && pinnedLabel == "" && driveSelector == "" {
+		return nil, nil
+	}
+	if pinnedDrive != "" && pinnedLabel != "" {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"volume could be pinned either to drive %s or to drives with label %s", pinnedDrive, pinnedLabel)
+	}
+	selector, err := parseDriveSelector(driveSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		pinned  = pinnedDrive != "" || pinnedLabel != ""
+		matches []drivecrd.Drive
+	)
+	if pinned {
+		matches, err = c.findPinnedDrives(ctx, pinnedDrive, pinnedLabel)
+	} else if matches, err = c.crHelper.GetDriveCRs(ctx); err != nil {
+		err = status.Errorf(codes.Internal, "unable to read drives: %v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		locations = make([]string, 0, len(matches))
+		reasons   = make([]string, 0)
+	)
+	for _, drive := range matches {
+		if !selector.Matches(labels.Set(drive.Labels)) {
+			if pinned {
+				reasons = append(reasons, fmt.Sprintf("drive %s doesn't match drive selector %s",
+					drive.Spec.SerialNumber, driveSelector))
+			}
+			continue
+		}
+		if reason := pinnedDriveUnusableReason(drive, storageClass, preferredNode); reason != "" {
+			reasons = append(reasons, fmt.Sprintf("drive %s %s", drive.Spec.SerialNumber, reason))
+			continue
+		}
+		locations = append(locations, drive.Spec.UUID)
+		if lvg, err := c.crHelper.GetLVGByDrive(ctx, drive.Spec.UUID); err == nil && lvg != nil {
+			locations = append(locations, lvg.Name)
+		}
+	}
+	if len(locations) == 0 {
+		if len(reasons) == 0 {
+			return nil, fmt.Errorf("%w: there are no drives matching drive selector %s",
+				errTypes.ErrCapacityExhausted, driveSelector)
+		}
+		return nil, fmt.Errorf("%w: selected drives can't be used for the volume: %s",
+			errTypes.ErrCapacityExhausted, strings.Join(reasons, "; "))
+	}
+	ll.Infof("Volume is restricted to locations %v", locations)
+	return locations, nil
+}
+
+// parseDriveSelector parses drive selector from StorageClass parameters, empty selector matches all drives
+func parseDriveSelector(driveSelector string) (labels.Selector, error) {
+	if driveSelector == "" {
+		return labels.Everything(), nil
+	}
+	selector, err := labels.Parse(driveSelector)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid drive selector %s: %v", driveSelector, err)
+	}
+	return selector, nil
+}
+
+// pvcAnnotations returns annotations of PVC for which volume is created, PVC name and namespace are taken
+// from parameters of CreateVolumeRequest, nil is returned if they aren't provided or PVC doesn't exist
+func (c *CSIControllerService) pvcAnnotations(ctx context.Context, params map[string]string) (map[string]string, error) {
+	name, namespace := params[base.PVCNameKey], params[base.PVCNamespaceKey]
+	if name == "" {
+		return nil, nil
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := c.k8sclient.ReadCR(ctx, name, namespace, pvc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, status.Errorf(codes.Internal, "unable to read PVC %s/%s: %v", namespace, name, err)
+	}
+	return pvc.Annotations, nil
+}
+
+// findPinnedDrives returns drives with provided serial number or WWN or drives which match label selector
+func (c *CSIControllerService) findPinnedDrives(ctx context.Context, pinnedDrive, pinnedLabel string) ([]drivecrd.Drive, error) {
+	selector := labels.Nothing()
+	if pinnedLabel != "" {
+		var err error
+		if selector, err = labels.Parse(pinnedLabel); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid drive label selector %s: %v", pinnedLabel, err)
+		}
+	}
+
+	drives, err := c.crHelper.GetDriveCRs(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to read drives: %v", err)
+	}
+	matches := make([]drivecrd.Drive, 0)
+	for _, drive := range drives {
+		if pinnedDrive != "" &&
+			(strings.EqualFold(drive.Spec.SerialNumber, pinnedDrive) ||
+				(drive.Spec.WWN != "" && strings.EqualFold(drive.Spec.WWN, pinnedDrive))) {
+			matches = append(matches, drive)
+			continue
+		}
+		if selector.Matches(labels.Set(drive.Labels)) {
+			matches = append(matches, drive)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "there are no drives for pinning %s%s", pinnedDrive, pinnedLabel)
+	}
+	return matches, nil
+}
+
+// pinnedDriveUnusableReason returns reason why volume can't be placed on the pinned drive or empty string
+func pinnedDriveUnusableReason(drive drivecrd.Drive, storageClass, preferredNode string) string {
+	switch {
+	case drive.Spec.Health != apiV1.HealthGood:
+		return fmt.Sprintf("has %s health", drive.Spec.Health)
+	case drive.Spec.Status != apiV1.DriveStatusOnline:
+		return fmt.Sprintf("has %s status", drive.Spec.Status)
+	case drive.Spec.Usage != apiV1.DriveUsageInUse:
+		return fmt.Sprintf("has %s usage", drive.Spec.Usage)
+	case drive.IsHotSpare():
+		return "is hot spare"
+	case preferredNode != "" && drive.Spec.NodeId != preferredNode:
+		return fmt.Sprintf("is placed on node %s, not on preferred node %s", drive.Spec.NodeId, preferredNode)
+	}
+	driveSC := util.ConvertDriveTypeToStorageClass(drive.Spec.Type)
+	requiredSC := storageClass
+	if util.IsStorageClassLVG(storageClass) {
+		requiredSC = util.GetSubStorageClass(storageClass)
+	}
+	if requiredSC != "" && requiredSC != apiV1.StorageClassAny && requiredSC != driveSC {
+		return fmt.Sprintf("has type %s which doesn't match storage class %s", drive.Spec.Type, storageClass)
+	}
+	return ""
+}
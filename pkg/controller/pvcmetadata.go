@@ -0,0 +1,100 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base"
+)
+
+// internalVolumeAnnotations are annotations of Volume CR which are handled by the driver,
+// PVC annotations with the same keys aren't propagated, so they don't trigger or fake volume operations
+var internalVolumeAnnotations = []string{
+	apiV1.VolumeAnnotationRelease,
+	apiV1.VolumeAnnotationReleaseStatus,
+	apiV1.VolumeAnnotationFsck,
+	apiV1.VolumeAnnotationFsckOptions,
+	apiV1.VolumeAnnotationFsckStatus,
+	apiV1.VolumeAnnotationForceRelease,
+	apiV1.VolumeAnnotationForceReleaseStatus,
+	apiV1.VolumeAnnotationStagingPath,
+	apiV1.VolumeAnnotationEraseData,
+	apiV1.VolumeAnnotationImport,
+	apiV1.VolumeAnnotationExport,
+	apiV1.VolumeAnnotationExportStatus,
+	apiV1.VolumePreviousStatus,
+	apiV1.VolumePreviousCapacity,
+}
+
+// pvcMetadata returns labels and annotations of PVC for which volume is created with keys selected by
+// SetPVCMetadataKeys, internal annotations of Volume CR are skipped. PVC name and namespace are taken from
+// parameters of CreateVolumeRequest, nil is returned if keys aren't selected, PVC isn't provided or doesn't exist
+func (c *CSIControllerService) pvcMetadata(ctx context.Context,
+	params map[string]string) (map[string]string, map[string]string, error) {
+	name, namespace := params[base.PVCNameKey], params[base.PVCNamespaceKey]
+	if len(c.pvcMetadataKeys) == 0 || name == "" {
+		return nil, nil, nil
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := c.k8sclient.ReadCR(ctx, name, namespace, pvc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, status.Errorf(codes.Internal, "unable to read PVC %s/%s: %v", namespace, name, err)
+	}
+	annotations := selectKeys(pvc.Annotations, c.pvcMetadataKeys)
+	for _, key := range internalVolumeAnnotations {
+		delete(annotations, key)
+	}
+	return selectKeys(pvc.Labels, c.pvcMetadataKeys), annotations, nil
+}
+
+// selectKeys returns entries of the map with provided keys
+func selectKeys(values map[string]string, keys []string) map[string]string {
+	selected := make(map[string]string)
+	for _, key := range keys {
+		if value, ok := values[key]; ok {
+			selected[key] = value
+		}
+	}
+	return selected
+}
+
+// addPVCMetadata returns copy of volume context with PVC labels and annotations, their keys are prefixed with
+// base.PVCMetadataKeyPrefix, annotation overrides label with the same key
+func addPVCMetadata(volumeContext, pvcLabels, pvcAnnotations map[string]string) map[string]string {
+	if len(pvcLabels) == 0 && len(pvcAnnotations) == 0 {
+		return volumeContext
+	}
+	result := make(map[string]string, len(volumeContext)+len(pvcLabels)+len(pvcAnnotations))
+	for k, v := range volumeContext {
+		result[k] = v
+	}
+	for _, metadata := range []map[string]string{pvcLabels, pvcAnnotations} {
+		for k, v := range metadata {
+			result[base.PVCMetadataKeyPrefix+k] = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	quotacrd "github.com/dell/csi-baremetal/api/v1/storagequotacrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
+)
+
+// eventRecorder interface for sending events
+type eventRecorder interface {
+	Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{})
+}
+
+// SetEventRecorder sets recorder which is used to report events about PVCs
+// Should be called before the service starts serving requests
+func (c *CSIControllerService) SetEventRecorder(recorder eventRecorder) {
+	c.eventRecorder = recorder
+}
+
+// checkStorageQuota checks whether volume of the namespace could be created within StorageQuotas of the namespace.
+// Volumes of the namespace are counted until volume CR is created, so parallel requests don't exceed quota
+// Receives golang context, namespace of PVC, quota isn't checked if it's empty, and requested size
+// Returns function which should be called once volume CR is created or error which wraps ErrCapacityExhausted
+// if quota is exceeded
+func (c *CSIControllerService) checkStorageQuota(ctx context.Context, namespace string, size int64) (func(), error) {
+	if namespace == "" {
+		return func() {}, nil
+	}
+	quotaList := &quotacrd.StorageQuotaList{}
+	if err := c.k8sclient.ReadList(ctx, quotaList); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to read storage quotas: %v", err)
+	}
+	var quotas []quotacrd.StorageQuota
+	for _, quota := range quotaList.Items {
+		if quota.Namespace == namespace {
+			quotas = append(quotas, quota)
+		}
+	}
+	if len(quotas) == 0 {
+		return func() {}, nil
+	}
+
+	value, _ := c.quotaMu.LoadOrStore(namespace, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	volumes := &volumecrd.VolumeList{}
+	if err := c.k8sclient.ReadList(ctx, volumes); err != nil {
+		mu.Unlock()
+		return nil, status.Errorf(codes.Internal, "unable to read volumes: %v", err)
+	}
+	var usedBytes, usedVolumes int64
+	for _, volume := range volumes.Items {
+		// capacity of removed volume is released
+		if volume.Namespace != namespace || volume.Spec.CSIStatus == apiV1.Removed {
+			continue
+		}
+		usedBytes += volume.Spec.Size
+		usedVolumes++
+	}
+	for _, quota := range quotas {
+		if quota.Spec.Bytes > 0 && usedBytes+size > quota.Spec.Bytes {
+			mu.Unlock()
+			return nil, fmt.Errorf("%w: storage quota %s of namespace %s is exceeded: "+
+				"%d of %d bytes are used, %d bytes are requested",
+				errTypes.ErrCapacityExhausted, quota.Name, namespace, usedBytes, quota.Spec.Bytes, size)
+		}
+		if quota.Spec.Volumes > 0 && usedVolumes >= quota.Spec.Volumes {
+			mu.Unlock()
+			return nil, fmt.Errorf("%w: storage quota %s of namespace %s is exceeded: %d of %d volumes are used",
+				errTypes.ErrCapacityExhausted, quota.Name, namespace, usedVolumes, quota.Spec.Volumes)
+		}
+	}
+	return mu.Unlock, nil
+}
+
+// recordPVCEvent sends event about PVC of CreateVolume request, PVC is taken from parameters
+// which external-provisioner adds with --extra-create-metadata
+func (c *CSIControllerService) recordPVCEvent(ctx context.Context, params map[string]string,
+	eventType, reason, message string) {
+	name, namespace := params[base.PVCNameKey], params[base.PVCNamespaceKey]
+	if c.eventRecorder == nil || name == "" || namespace == "" {
+		return
+	}
+	pvc := &coreV1.PersistentVolumeClaim{}
+	if err := c.k8sclient.ReadCR(ctx, name, namespace, pvc); err != nil {
+		c.log.WithField("method", "recordPVCEvent").Errorf("Unable to read PVC %s/%s: %v", namespace, name, err)
+		return
+	}
+	c.eventRecorder.Eventf(pvc, eventType, reason, "%s", message)
+}
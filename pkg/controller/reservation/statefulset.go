@@ -0,0 +1,324 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reservation contains reservation of capacity in advance for StatefulSet replicas which aren't created yet
+package reservation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
+
+// statefulSetReservationInterval is the interval between reconciliations of reservations
+const statefulSetReservationInterval = 30 * time.Second
+
+// StatefulSetReserver holds capacity for replicas of StatefulSets which aren't created yet.
+// Amount of replicas is set by user with reserve-replicas annotation of StatefulSet, capacity for PVCs
+// of volumeClaimTemplates which use driver's StorageClasses is held in AvailableCapacityReservations.
+// Reservation of replica is removed once StatefulSet is scaled up, so capacity is used by its pod
+type StatefulSetReserver struct {
+	client *k8s.KubeClient
+	log    *logrus.Entry
+	// clock of reconciliation loop
+	clock clock.Clock
+}
+
+// pendingClaim is PVC of StatefulSet replica which isn't created yet
+type pendingClaim struct {
+	statefulSet string
+	ordinal     int
+	volume      *genV1.Volume
+}
+
+// NewStatefulSetReserver is the constructor for StatefulSetReserver
+func NewStatefulSetReserver(client *k8s.KubeClient, logger *logrus.Logger) *StatefulSetReserver {
+	return &StatefulSetReserver{
+		client: client,
+		log:    logger.WithField("component", "StatefulSetReserver"),
+		clock:  clock.RealClock{},
+	}
+}
+
+// Run spawns routine which reconciles reservations each statefulSetReservationInterval
+func (r *StatefulSetReserver) Run() {
+	go func() {
+		for {
+			<-r.clock.After(statefulSetReservationInterval)
+			if err := r.Reconcile(context.Background()); err != nil {
+				r.log.WithField("method", "Run").Errorf("Unable to reconcile reservations: %v", err)
+			}
+		}
+	}()
+}
+
+// Reconcile creates reservations for PVCs of StatefulSet replicas which aren't created yet and removes
+// reservations of PVCs which were created or are not required anymore.
+// All volumes of the replica are reserved on the same node, replicas are spread between nodes
+// Receives golang context
+// Returns error if resources could not be read
+func (r *StatefulSetReserver) Reconcile(ctx context.Context) error {
+	ll := r.log.WithField("method", "Reconcile")
+
+	storageTypes, err := r.storageTypes(ctx)
+	if err != nil {
+		return err
+	}
+	claims, err := r.pendingClaims(ctx, storageTypes)
+	if err != nil {
+		return err
+	}
+
+	acrs := &acrcrd.AvailableCapacityReservationList{}
+	if err := r.client.ReadList(ctx, acrs); err != nil {
+		return err
+	}
+	acs := &accrd.AvailableCapacityList{}
+	if err := r.client.ReadList(ctx, acs); err != nil {
+		return err
+	}
+	acNodes := make(map[string]string, len(acs.Items))
+	for _, ac := range acs.Items {
+		acNodes[ac.Name] = ac.Spec.NodeId
+	}
+
+	// amount of volumes reserved on node for each StatefulSet
+	stsNodes := make(map[string]map[string]int)
+	for i := range acrs.Items {
+		acr := &acrs.Items[i]
+		name, ok := acr.Annotations[base.ACRAnnotationReservedFor]
+		if !ok {
+			continue
+		}
+		claim, wanted := claims[name]
+		if !wanted {
+			ll.Infof("Remove reservation %s of PVC %s", acr.Name, name)
+			if err := r.client.DeleteCR(ctx, acr); err != nil {
+				ll.Errorf("Unable to remove reservation %s: %v", acr.Name, err)
+			}
+			continue
+		}
+		delete(claims, name)
+		if stsNodes[claim.statefulSet] == nil {
+			stsNodes[claim.statefulSet] = make(map[string]int)
+		}
+		for _, acName := range acr.Spec.Reservations {
+			stsNodes[claim.statefulSet][acNodes[acName]]++
+		}
+	}
+
+	for _, replica := range groupByReplica(claims) {
+		sts := replica[0].statefulSet
+		if stsNodes[sts] == nil {
+			stsNodes[sts] = make(map[string]int)
+		}
+		node, err := r.reserve(ctx, replica, stsNodes[sts])
+		if err != nil {
+			ll.Errorf("Unable to reserve capacity for replica %d of StatefulSet %s: %v", replica[0].ordinal, sts, err)
+			continue
+		}
+		if node == "" {
+			ll.Warningf("There is no capacity to reserve for replica %d of StatefulSet %s", replica[0].ordinal, sts)
+			continue
+		}
+		stsNodes[sts][node] += len(replica)
+	}
+	return nil
+}
+
+// storageTypes returns mapping between names of driver's StorageClasses and their storage types
+func (r *StatefulSetReserver) storageTypes(ctx context.Context) (map[string]string, error) {
+	scs := &storageV1.StorageClassList{}
+	if err := r.client.ReadList(ctx, scs); err != nil {
+		return nil, err
+	}
+	storageTypes := make(map[string]string)
+	for _, sc := range scs.Items {
+		if sc.Provisioner == base.PluginName {
+			storageTypes[sc.Name] = strings.ToUpper(sc.Parameters[base.StorageTypeKey])
+		}
+	}
+	return storageTypes, nil
+}
+
+// pendingClaims returns PVCs of StatefulSet replicas which should be reserved by their <namespace>/<name>,
+// PVCs of created replicas and PVCs which exist already are skipped
+func (r *StatefulSetReserver) pendingClaims(ctx context.Context,
+	storageTypes map[string]string) (map[string]*pendingClaim, error) {
+	ll := r.log.WithField("method", "pendingClaims")
+
+	stsList := &appsV1.StatefulSetList{}
+	if err := r.client.ReadList(ctx, stsList); err != nil {
+		return nil, err
+	}
+	pvcs := &coreV1.PersistentVolumeClaimList{}
+	if err := r.client.ReadList(ctx, pvcs); err != nil {
+		return nil, err
+	}
+	pvcNames := make(map[string]bool, len(pvcs.Items))
+	for _, pvc := range pvcs.Items {
+		pvcNames[pvc.Namespace+"/"+pvc.Name] = true
+	}
+
+	claims := make(map[string]*pendingClaim)
+	for _, sts := range stsList.Items {
+		value, ok := sts.Annotations[base.StatefulSetAnnotationReserveReplicas]
+		if !ok {
+			continue
+		}
+		stsKey := sts.Namespace + "/" + sts.Name
+		reserveReplicas, err := strconv.Atoi(value)
+		if err != nil || reserveReplicas < 0 {
+			ll.Errorf("StatefulSet %s has invalid value of annotation %s: %s",
+				stsKey, base.StatefulSetAnnotationReserveReplicas, value)
+			continue
+		}
+		replicas := 1
+		if sts.Spec.Replicas != nil {
+			replicas = int(*sts.Spec.Replicas)
+		}
+		for ordinal := replicas; ordinal < reserveReplicas; ordinal++ {
+			for _, template := range sts.Spec.VolumeClaimTemplates {
+				if template.Spec.StorageClassName == nil {
+					continue
+				}
+				storageType, ok := storageTypes[*template.Spec.StorageClassName]
+				if !ok {
+					continue
+				}
+				// PVC name of StatefulSet replica is <template name>-<StatefulSet name>-<ordinal>
+				name := fmt.Sprintf("%s/%s-%s-%d", sts.Namespace, template.Name, sts.Name, ordinal)
+				if pvcNames[name] {
+					continue
+				}
+				storageReq := template.Spec.Resources.Requests[coreV1.ResourceStorage]
+				mode := ""
+				if template.Spec.VolumeMode != nil {
+					mode = string(*template.Spec.VolumeMode)
+				}
+				claims[name] = &pendingClaim{
+					statefulSet: stsKey,
+					ordinal:     ordinal,
+					volume: &genV1.Volume{
+						Id:           name,
+						StorageClass: util.ConvertStorageClass(storageType),
+						Size:         storageReq.Value(),
+						Mode:         mode,
+					},
+				}
+			}
+		}
+	}
+	return claims, nil
+}
+
+// groupByReplica groups PVCs by StatefulSet replica, replicas are ordered by StatefulSet and ordinal
+func groupByReplica(claims map[string]*pendingClaim) [][]*pendingClaim {
+	type replicaKey struct {
+		statefulSet string
+		ordinal     int
+	}
+	groups := make(map[replicaKey][]*pendingClaim)
+	keys := make([]replicaKey, 0)
+	for _, claim := range claims {
+		key := replicaKey{claim.statefulSet, claim.ordinal}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], claim)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].statefulSet != keys[j].statefulSet {
+			return keys[i].statefulSet < keys[j].statefulSet
+		}
+		return keys[i].ordinal < keys[j].ordinal
+	})
+	replicas := make([][]*pendingClaim, len(keys))
+	for i, key := range keys {
+		replicas[i] = groups[key]
+	}
+	return replicas
+}
+
+// reserve creates reservations for PVCs of the replica on the node which has the least amount of volumes
+// reserved for the StatefulSet
+// Receives golang context, PVCs of the replica and amount of volumes reserved for StatefulSet by node
+// Returns selected node or empty string if there is no capacity for the replica, error if reservation failed
+func (r *StatefulSetReserver) reserve(ctx context.Context, replica []*pendingClaim,
+	nodeLoad map[string]int) (string, error) {
+	ll := r.log.WithField("method", "reserve")
+
+	volumes := make([]*genV1.Volume, len(replica))
+	for i, claim := range replica {
+		volumes[i] = claim.volume
+	}
+	acReader := capacityplanner.NewACReader(r.client, r.log, false)
+	acrReader := capacityplanner.NewACRReader(r.client, r.log, false)
+	capManager := capacityplanner.NewCapacityManager(r.log,
+		capacityplanner.NewUnreservedACReader(r.log, acReader, acrReader))
+	plan, err := capManager.PlanVolumesPlacing(ctx, volumes)
+	if err != nil || plan == nil {
+		return "", err
+	}
+
+	// each node in plan has capacity for all volumes
+	acs := plan.GetACsForVolumes()[volumes[0]]
+	nodes := make([]string, len(acs))
+	for i, ac := range acs {
+		nodes[i] = ac.Spec.NodeId
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodeLoad[nodes[i]] != nodeLoad[nodes[j]] {
+			return nodeLoad[nodes[i]] < nodeLoad[nodes[j]]
+		}
+		return nodes[i] < nodes[j]
+	})
+	node := nodes[0]
+
+	for _, volume := range volumes {
+		ac := plan.GetACForVolume(node, volume)
+		acr := r.client.ConstructACRCR(genV1.AvailableCapacityReservation{
+			Name:         uuid.New().String(),
+			StorageClass: volume.StorageClass,
+			Size:         volume.Size,
+			Reservations: []string{ac.Name},
+		})
+		acr.Annotations = map[string]string{base.ACRAnnotationReservedFor: volume.Id}
+		ll.Infof("Reserve AvailableCapacity %s on node %s for PVC %s", ac.Name, node, volume.Id)
+		if err := r.client.CreateCR(ctx, acr.Name, acr); err != nil {
+			return "", err
+		}
+	}
+	return node, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+func TestStatefulSetReserver_Reconcile(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		ns   = "default"
+		hdd  = "csi-baremetal-sc-hdd"
+		size = int64(1024 * 1024 * 1024)
+	)
+	client, err := k8s.GetFakeKubeClient(ns, logrus.New())
+	assert.Nil(t, err)
+	reserver := NewStatefulSetReserver(client, logrus.New())
+
+	for _, sc := range []*storageV1.StorageClass{
+		{ObjectMeta: k8smetav1.ObjectMeta{Name: hdd}, Provisioner: base.PluginName,
+			Parameters: map[string]string{base.StorageTypeKey: apiV1.StorageClassHDD}},
+		{ObjectMeta: k8smetav1.ObjectMeta{Name: "foreign"}, Provisioner: "foreign"},
+	} {
+		assert.Nil(t, client.CreateCR(ctx, sc.Name, sc))
+	}
+	for name, node := range map[string]string{"ac-1": "node-1", "ac-2": "node-1", "ac-3": "node-2"} {
+		ac := client.ConstructACCR(name, api.AvailableCapacity{Location: name, NodeId: node,
+			StorageClass: apiV1.StorageClassHDD, Size: 10 * size})
+		assert.Nil(t, client.CreateCR(ctx, name, ac))
+	}
+
+	template := func(name, sc string) coreV1.PersistentVolumeClaim {
+		return coreV1.PersistentVolumeClaim{
+			ObjectMeta: k8smetav1.ObjectMeta{Name: name},
+			Spec: coreV1.PersistentVolumeClaimSpec{
+				StorageClassName: &sc,
+				Resources: coreV1.ResourceRequirements{
+					Requests: coreV1.ResourceList{coreV1.ResourceStorage: *resource.NewQuantity(size, resource.BinarySI)},
+				},
+			},
+		}
+	}
+	replicas := int32(1)
+	sts := &appsV1.StatefulSet{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: "web", Namespace: ns,
+			Annotations: map[string]string{base.StatefulSetAnnotationReserveReplicas: "4"}},
+		Spec: appsV1.StatefulSetSpec{
+			Replicas:             &replicas,
+			VolumeClaimTemplates: []coreV1.PersistentVolumeClaim{template("data", hdd), template("logs", "foreign")},
+		},
+	}
+	assert.Nil(t, client.CreateCR(ctx, sts.Name, sts))
+	// PVC of replica which was removed by scale down exists
+	pvc := &coreV1.PersistentVolumeClaim{ObjectMeta: k8smetav1.ObjectMeta{Name: "data-web-3", Namespace: ns}}
+	assert.Nil(t, client.CreateCR(ctx, pvc.Name, pvc))
+
+	reservations := func() map[string]string {
+		acrs := &acrcrd.AvailableCapacityReservationList{}
+		assert.Nil(t, client.ReadList(ctx, acrs))
+		result := make(map[string]string, len(acrs.Items))
+		for _, acr := range acrs.Items {
+			assert.Len(t, acr.Spec.Reservations, 1)
+			result[acr.Annotations[base.ACRAnnotationReservedFor]] = acr.Spec.Reservations[0]
+		}
+		return result
+	}
+	acNode := func(name string) string {
+		ac := &accrd.AvailableCapacity{}
+		assert.Nil(t, client.ReadCR(ctx, name, "", ac))
+		return ac.Spec.NodeId
+	}
+
+	assert.Nil(t, reserver.Reconcile(ctx))
+	acrs := reservations()
+	assert.Len(t, acrs, 2)
+	assert.Contains(t, acrs, "default/data-web-1")
+	assert.Contains(t, acrs, "default/data-web-2")
+	// replicas are spread between nodes
+	assert.NotEqual(t, acNode(acrs["default/data-web-1"]), acNode(acrs["default/data-web-2"]))
+
+	// reservations are not duplicated
+	assert.Nil(t, reserver.Reconcile(ctx))
+	assert.Equal(t, acrs, reservations())
+
+	// reservation of created replica is removed
+	replicas = 2
+	assert.Nil(t, client.UpdateCR(ctx, sts))
+	assert.Nil(t, reserver.Reconcile(ctx))
+	assert.Equal(t, map[string]string{"default/data-web-2": acrs["default/data-web-2"]}, reservations())
+
+	// all reservations are removed with annotation
+	sts.Annotations = nil
+	assert.Nil(t, client.UpdateCR(ctx, sts))
+	assert.Nil(t, reserver.Reconcile(ctx))
+	assert.Empty(t, reservations())
+}
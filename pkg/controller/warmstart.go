@@ -0,0 +1,143 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+)
+
+// warmStartRetryInterval is the interval between attempts to rebuild controller state from CRs
+const warmStartRetryInterval = 5 * time.Second
+
+// cacheRebuilder is implemented by volume operations which keep volumes in memory
+type cacheRebuilder interface {
+	RebuildCache(ctx context.Context) error
+}
+
+// WarmStart rebuilds in-memory state of the controller from CRs, it should be called before the controller
+// starts serving requests. Controller pod could be rescheduled in the middle of CreateVolume: volume CR is already
+// in Creating status and is converged by the node, but capacity which it took could be still available in AC.
+// Such capacity is consumed here, reservations are stored in ACR CRs and don't need to be rebuilt.
+// Blocks until state is rebuilt, retries on errors
+// Receives golang context
+// Returns error if context is done before state was rebuilt
+func (c *CSIControllerService) WarmStart(ctx context.Context) error {
+	ll := c.log.WithField("method", "WarmStart")
+	for {
+		err := c.rebuildState(ctx)
+		if err == nil {
+			ll.Info("Controller state is rebuilt from CRs")
+			return nil
+		}
+		ll.Errorf("Unable to rebuild controller state: %v, retry in %s", err, warmStartRetryInterval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(warmStartRetryInterval):
+		}
+	}
+}
+
+// rebuildState fills volume cache and fixes ACs of volumes in Creating status
+func (c *CSIControllerService) rebuildState(ctx context.Context) error {
+	if rebuilder, ok := c.svc.(cacheRebuilder); ok {
+		if err := rebuilder.RebuildCache(ctx); err != nil {
+			return fmt.Errorf("unable to rebuild volume cache: %w", err)
+		}
+	}
+	volumes, err := c.crHelper.GetVolumeCRs(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to read volumes: %w", err)
+	}
+	acs, err := c.crHelper.GetACCRs(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to read ACs: %w", err)
+	}
+	lvgs, err := c.crHelper.GetLVGCRs(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to read LVGs: %w", err)
+	}
+	return c.consumeCapacityOfCreatingVolumes(ctx, volumes, acs, lvgs)
+}
+
+// consumeCapacityOfCreatingVolumes decreases ACs which still hold capacity taken by volumes in Creating status:
+// AC of drive should be empty, AC of LVG shouldn't exceed size of LVG minus size of its volumes.
+// ACs are only decreased, so capacity which is taken by volume expansion is kept
+func (c *CSIControllerService) consumeCapacityOfCreatingVolumes(ctx context.Context, volumes []volumecrd.Volume,
+	acs []accrd.AvailableCapacity, lvgs []lvgcrd.LogicalVolumeGroup) error {
+	ll := c.log.WithField("method", "consumeCapacityOfCreatingVolumes")
+
+	acByLocation := make(map[string]*accrd.AvailableCapacity, len(acs))
+	for i := range acs {
+		acByLocation[acs[i].Spec.Location] = &acs[i]
+	}
+	lvgFreeSize := make(map[string]int64, len(lvgs))
+	for _, lvg := range lvgs {
+		lvgFreeSize[lvg.Name] = lvg.Spec.Size
+	}
+	for _, volume := range volumes {
+		if _, ok := lvgFreeSize[volume.Spec.Location]; ok {
+			lvgFreeSize[volume.Spec.Location] -= volume.Spec.Size
+		}
+	}
+
+	checked := make(map[string]bool)
+	for _, volume := range volumes {
+		location := volume.Spec.Location
+		if volume.Spec.CSIStatus != apiV1.Creating || checked[location] {
+			continue
+		}
+		checked[location] = true
+		ac, ok := acByLocation[location]
+		if !ok {
+			continue
+		}
+		var maxSize int64
+		if volume.Spec.LocationType == apiV1.LocationTypeLVM {
+			free, ok := lvgFreeSize[location]
+			if !ok {
+				continue
+			}
+			if free > 0 {
+				maxSize = free
+			}
+		}
+		if ac.Spec.Size <= maxSize {
+			continue
+		}
+		ll.Warnf("AC %s holds %d bytes taken by volume %s in %s status, decrease it to %d",
+			ac.Name, ac.Spec.Size, volume.Name, volume.Spec.CSIStatus, maxSize)
+		ctxWithID := context.WithValue(ctx, base.RequestUUID, volume.Name)
+		if err := c.k8sclient.UpdateCRWithConflictRetry(ctxWithID, ac, func() error {
+			if ac.Spec.Size > maxSize {
+				ac.Spec.Size = maxSize
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("unable to update AC %s: %w", ac.Name, err)
+		}
+	}
+	return nil
+}
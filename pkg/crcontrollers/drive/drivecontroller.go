@@ -2,10 +2,15 @@ package drive
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,38 +20,75 @@ import (
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/crashdump"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lvm"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/nvmecli"
+	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/eventing"
-	"github.com/dell/csi-baremetal/pkg/events"
 	metricsC "github.com/dell/csi-baremetal/pkg/metrics/common"
 )
 
+// driveFinalizer prevents Drive CR removal while there are Volumes or LogicalVolumeGroup placed on the drive
+const driveFinalizer = "dell.emc.csi/drive-cleanup"
+
 // Controller to reconcile drive custom resource
 type Controller struct {
 	client         *k8s.KubeClient
 	crHelper       *k8s.CRHelper
 	nodeID         string
 	driveMgrClient api.DriveServiceClient
-	eventRecorder  *events.Recorder
+	eventRecorder  eventRecorder
+	listBlk        lsblk.WrapLsblk
+	lvmOps         lvm.WrapLVM
 	log            *logrus.Entry
+	// records panics of Reconcile, nil if crash dumps are disabled
+	crashRecorder *crashdump.Recorder
+	// evacuations which are running or whose result isn't handled by Reconcile yet, key is drive name
+	evacuations   map[string]*evacuation
+	evacuationsMu sync.Mutex
+}
+
+// evacuation is data move of the drive which runs in background, err is set before done is closed
+type evacuation struct {
+	done chan struct{}
+	err  error
+}
+
+// eventRecorder interface for sending events
+type eventRecorder interface {
+	Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{})
 }
 
 // NewController creates new instance of Controller structure
 // Receives an instance of base.KubeClient, node ID and logrus logger
 // Returns an instance of Controller
-func NewController(client *k8s.KubeClient, nodeID string, serviceClient api.DriveServiceClient, eventRecorder *events.Recorder, log *logrus.Logger) *Controller {
+func NewController(client *k8s.KubeClient, nodeID string, serviceClient api.DriveServiceClient, eventRecorder eventRecorder, log *logrus.Logger) *Controller {
 	return &Controller{
 		client:         client,
 		crHelper:       k8s.NewCRHelper(client, log),
 		nodeID:         nodeID,
 		driveMgrClient: serviceClient,
 		eventRecorder:  eventRecorder,
+		listBlk:        lsblk.NewLSBLK(log),
+		lvmOps:         lvm.NewLVM(command.NewExecutor(log), log),
 		log:            log.WithField("component", "Controller"),
+		evacuations:    make(map[string]*evacuation),
 	}
 }
 
+// SetCrashRecorder makes panics of Reconcile be recorded into crash dump
+func (c *Controller) SetCrashRecorder(r *crashdump.Recorder) {
+	c.crashRecorder = r
+}
+
 // SetupWithManager registers Controller to ControllerManager
 func (c *Controller) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -80,6 +122,7 @@ func (c *Controller) filterCRs(obj runtime.Object) bool {
 // Reconcile reconciles Drive custom resources
 func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	defer metricsC.ReconcileDuration.EvaluateDurationForType("node_drive_controller")()
+	defer c.crashRecorder.Recover("drive/Reconcile", "")
 	// read name
 	driveName := req.Name
 	// TODO why do we need 60 seconds here?
@@ -100,17 +143,51 @@ func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	log.Infof("Drive changed: %v", drive)
 
+	switch {
+	case !drive.ObjectMeta.DeletionTimestamp.IsZero():
+		log.Info("Delete Drive")
+		return c.handleDriveRemoving(ctx, drive)
+	case !util.ContainsString(drive.ObjectMeta.Finalizers, driveFinalizer):
+		return c.appendFinalizer(ctx, drive)
+	}
+
+	// result of the reconcile if it succeeds, postponed firmware update is retried with it
+	result := ctrl.Result{}
+	switch drive.Annotations[apiV1.DriveAnnotationMaintenance] {
+	case apiV1.DriveAnnotationMaintenanceFirmwareUpdate:
+		postponed, err := c.postponeFirmwareUpdate(ctx, drive)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, err
+		}
+		if !postponed {
+			return c.updateFirmware(ctx, drive)
+		}
+		result = ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}
+	case apiV1.DriveAnnotationMaintenanceNVMeNamespaces:
+		return c.createNamespaces(ctx, drive)
+	case apiV1.DriveAnnotationMaintenanceEvacuate:
+		return c.evacuateDrive(ctx, drive)
+	}
+
 	usage := drive.Spec.GetUsage()
 	health := drive.Spec.GetHealth()
 	id := drive.Spec.GetUUID()
 	toUpdate := false
+	promoteHotSpare := false
 
 	switch usage {
 	case apiV1.DriveUsageInUse:
 		if health == apiV1.HealthSuspect || health == apiV1.HealthBad {
 			// TODO update health of volumes
+			// drive is released only after evacuation is finished, even if it became BAD meanwhile
+			if (health == apiV1.HealthSuspect ||
+				drive.Annotations[apiV1.DriveAnnotationEvacuationStatus] == apiV1.DriveAnnotationEvacuationStatusInProgress) &&
+				c.evacuateSuspectDrive(ctx, drive) {
+				return c.waitEvacuation(ctx, drive)
+			}
 			drive.Spec.Usage = apiV1.DriveUsageReleasing
 			toUpdate = true
+			promoteHotSpare = !drive.IsHotSpare()
 		}
 	case apiV1.DriveUsageReleasing:
 		volumes, err := c.crHelper.GetVolumesByLocation(ctx, id)
@@ -175,15 +252,512 @@ func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	// update drive CR if needed
 	if toUpdate {
+		drive.RefreshStatus(drivecrd.ReasonUsageChanged, metav1.Now())
 		if err := c.client.UpdateCR(ctx, drive); err != nil {
 			log.Errorf("Failed to update Drive %s CR", driveName)
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
 	}
+	// hot spare is promoted only after failed drive is updated, otherwise retry would promote one more drive
+	if promoteHotSpare {
+		c.promoteHotSpare(ctx, drive)
+	}
+
+	return result, nil
+}
+
+// promoteHotSpare makes the smallest suitable hot spare drive on the node available for allocation instead of failed one
+// Suitable hot spare is healthy, online, has the same type as failed drive and is not smaller than it
+func (c *Controller) promoteHotSpare(ctx context.Context, failed *drivecrd.Drive) {
+	log := c.log.WithFields(logrus.Fields{"method": "promoteHotSpare", "name": failed.Name})
+
+	drives, err := c.crHelper.GetDriveCRs(ctx, c.nodeID)
+	if err != nil {
+		log.Errorf("Failed to read Drive CRs: %v", err)
+		return
+	}
+	var spare *drivecrd.Drive
+	for i := range drives {
+		d := &drives[i]
+		if d.Name == failed.Name || !d.IsHotSpare() ||
+			d.Spec.Health != apiV1.HealthGood || d.Spec.Status != apiV1.DriveStatusOnline ||
+			d.Spec.Usage != apiV1.DriveUsageInUse ||
+			d.Spec.Type != failed.Spec.Type || d.Spec.Size < failed.Spec.Size {
+			continue
+		}
+		if spare == nil || d.Spec.Size < spare.Spec.Size {
+			spare = d
+		}
+	}
+	if spare == nil {
+		log.Infof("There is no suitable hot spare drive on the node")
+		return
+	}
+
+	delete(spare.Annotations, apiV1.DriveAnnotationHotSpare)
+	spare.Annotations[apiV1.DriveAnnotationHotSparePromotedFor] = failed.Name
+	if err := c.client.UpdateCR(ctx, spare); err != nil {
+		log.Errorf("Failed to promote hot spare drive %s: %v", spare.Name, err)
+		return
+	}
+	log.Infof("Hot spare drive %s is promoted", spare.Name)
+	eventMsg := fmt.Sprintf("Hot spare is promoted instead of failed drive %s, %s", failed.Name, spare.GetDriveDescription())
+	c.eventRecorder.Eventf(spare, eventing.NormalType, eventing.DriveHotSparePromoted, eventMsg)
+}
+
+// appendFinalizer appends finalizer to the Drive CR (update CR)
+func (c *Controller) appendFinalizer(ctx context.Context, drive *drivecrd.Drive) (ctrl.Result, error) {
+	drive.ObjectMeta.Finalizers = append(drive.ObjectMeta.Finalizers, driveFinalizer)
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		c.log.WithField("name", drive.Name).
+			Errorf("Unable to append finalizer %s to Drive: %v.", driveFinalizer, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// handleDriveRemoving removes finalizer from the Drive CR when there are no Volumes and LogicalVolumeGroup on the drive,
+// otherwise Drive CR removal is postponed until dependent CRs are cleaned up
+func (c *Controller) handleDriveRemoving(ctx context.Context, drive *drivecrd.Drive) (ctrl.Result, error) {
+	log := c.log.WithFields(logrus.Fields{"method": "handleDriveRemoving", "name": drive.Name})
+
+	if !util.ContainsString(drive.ObjectMeta.Finalizers, driveFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	volumes, err := c.crHelper.GetVolumesByLocation(ctx, drive.Spec.UUID)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, err
+	}
+	if len(volumes) > 0 {
+		log.Infof("Drive has %d volume(s), removal is postponed", len(volumes))
+		return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, nil
+	}
+
+	lvg, err := c.crHelper.GetLVGByDrive(ctx, drive.Spec.UUID)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, err
+	}
+	if lvg != nil {
+		log.Infof("Drive is used by LogicalVolumeGroup %s, removal is postponed", lvg.Name)
+		return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, nil
+	}
+
+	drive.ObjectMeta.Finalizers = util.RemoveString(drive.ObjectMeta.Finalizers, driveFinalizer)
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		log.Errorf("Unable to update Drive's finalizers: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// postponeFirmwareUpdate checks whether drive has volumes, firmware isn't flashed in such case.
+// Postponed status is stored in firmware status annotation and the rest of the reconcile isn't blocked by the update
+// Returns true if firmware update is postponed or error if volumes can't be read or Drive CR can't be updated
+func (c *Controller) postponeFirmwareUpdate(ctx context.Context, drive *drivecrd.Drive) (bool, error) {
+	log := c.log.WithFields(logrus.Fields{"method": "postponeFirmwareUpdate", "name": drive.Name})
+
+	volumes, err := c.crHelper.GetVolumesByLocation(ctx, drive.Spec.UUID)
+	if err != nil {
+		return false, err
+	}
+	if len(volumes) == 0 {
+		return false, nil
+	}
+	if drive.Annotations[apiV1.DriveAnnotationFirmwareStatus] == apiV1.DriveAnnotationFirmwareStatusPostponed {
+		return true, nil
+	}
+	log.Warnf("Drive has %d volume(s), firmware update is postponed", len(volumes))
+	drive.Annotations[apiV1.DriveAnnotationFirmwareStatus] = apiV1.DriveAnnotationFirmwareStatusPostponed
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		log.Errorf("Failed to update Drive %s CR", drive.Name)
+		return true, err
+	}
+	return true, nil
+}
+
+// updateFirmware flashes drive's firmware with the image provided in annotation, drive must not have volumes
+// Maintenance annotation is removed after the attempt, result is stored in firmware status annotation
+func (c *Controller) updateFirmware(ctx context.Context, drive *drivecrd.Drive) (ctrl.Result, error) {
+	log := c.log.WithFields(logrus.Fields{"method": "updateFirmware", "name": drive.Name})
+
+	var (
+		image = drive.Annotations[apiV1.DriveAnnotationFirmwareImage]
+		resp  *api.DriveFirmwareUpdateResponse
+		err   error
+	)
+	if strings.TrimSpace(image) == "" {
+		err = fmt.Errorf("path of firmware image isn't set in annotation %s", apiV1.DriveAnnotationFirmwareImage)
+	} else {
+		resp, err = c.driveMgrClient.UpdateFirmware(ctx, &api.DriveFirmwareUpdateRequest{
+			DriveSerialNumber: drive.Spec.SerialNumber,
+			Image:             image,
+		})
+	}
+	if err != nil {
+		log.Errorf("Failed to update firmware of drive %s with image %q, err %v", drive.Spec.SerialNumber, image, err)
+		drive.Annotations[apiV1.DriveAnnotationFirmwareStatus] = apiV1.DriveAnnotationFirmwareStatusFailed
+		eventMsg := fmt.Sprintf("Failed to update firmware: %v, %s", err, drive.GetDriveDescription())
+		c.eventRecorder.Eventf(drive, eventing.ErrorType, eventing.DriveFirmwareUpdateFailed, eventMsg)
+	} else {
+		drive.Spec.Firmware = resp.GetFirmware()
+		drive.Annotations[apiV1.DriveAnnotationFirmwareStatus] = apiV1.DriveAnnotationFirmwareStatusUpdated
+		eventMsg := fmt.Sprintf("Firmware successfully updated, %s", drive.GetDriveDescription())
+		c.eventRecorder.Eventf(drive, eventing.NormalType, eventing.DriveFirmwareUpdated, eventMsg)
+	}
+	delete(drive.Annotations, apiV1.DriveAnnotationMaintenance)
+
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		log.Errorf("Failed to update Drive %s CR", drive.Name)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// createNamespaces replaces NVMe namespaces of drive's controller with amount of namespaces provided in annotation
+// Namespaces are created only if all drives of the same controller are free, since existing namespaces are deleted.
+// Drives for new namespaces are discovered by drive manager.
+// Maintenance annotation is removed after the attempt, result is stored in namespaces status annotation
+func (c *Controller) createNamespaces(ctx context.Context, drive *drivecrd.Drive) (ctrl.Result, error) {
+	log := c.log.WithFields(logrus.Fields{"method": "createNamespaces", "name": drive.Name})
+
+	reason, err := c.busyNamespaceReason(ctx, drive)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, err
+	}
+
+	var (
+		resp  *api.DriveNamespacesResponse
+		count int
+	)
+	if reason != "" {
+		err = fmt.Errorf("namespaces of NVMe controller can't be replaced: %s", reason)
+	} else {
+		count, err = strconv.Atoi(drive.Annotations[apiV1.DriveAnnotationNVMeNamespaceCount])
+	}
+	if err == nil {
+		resp, err = c.driveMgrClient.CreateNamespaces(ctx, &api.DriveNamespacesRequest{
+			DriveSerialNumber: drive.Spec.SerialNumber,
+			Count:             int32(count),
+		})
+	}
+	if err != nil {
+		log.Errorf("Failed to create namespaces on drive %s, err %v", drive.Spec.SerialNumber, err)
+		drive.Annotations[apiV1.DriveAnnotationNVMeNamespacesStatus] = apiV1.DriveAnnotationNVMeNamespacesStatusFailed
+		eventMsg := fmt.Sprintf("Failed to create NVMe namespaces: %v, %s", err, drive.GetDriveDescription())
+		c.eventRecorder.Eventf(drive, eventing.ErrorType, eventing.DriveNamespacesFailed, eventMsg)
+	} else {
+		drive.Annotations[apiV1.DriveAnnotationNVMeNamespacesStatus] = apiV1.DriveAnnotationNVMeNamespacesStatusCreated
+		eventMsg := fmt.Sprintf("NVMe namespaces successfully created, drives %v, %s",
+			resp.GetSerialNumbers(), drive.GetDriveDescription())
+		c.eventRecorder.Eventf(drive, eventing.NormalType, eventing.DriveNamespacesCreated, eventMsg)
+	}
+	delete(drive.Annotations, apiV1.DriveAnnotationMaintenance)
+
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		log.Errorf("Failed to update Drive %s CR", drive.Name)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// busyNamespaceReason checks that all drives of NVMe controller which owns the drive are free and clean:
+// they aren't system drives, are online and in use by the driver, have AC (are handled by discovery) and don't hold
+// volumes or LVG
+// Returns reason why namespaces of the controller can't be replaced or empty string if they can
+func (c *Controller) busyNamespaceReason(ctx context.Context, drive *drivecrd.Drive) (string, error) {
+	drives, err := c.crHelper.GetDriveCRs(ctx, c.nodeID)
+	if err != nil {
+		return "", err
+	}
+	ctrlSN := nvmecli.ControllerSerialNumber(drive.Spec.SerialNumber)
+	for _, d := range drives {
+		if nvmecli.ControllerSerialNumber(d.Spec.SerialNumber) != ctrlSN {
+			continue
+		}
+		switch {
+		case d.Spec.IsSystem:
+			return fmt.Sprintf("drive %s is a system drive", d.Name), nil
+		case d.Spec.Status != apiV1.DriveStatusOnline:
+			return fmt.Sprintf("drive %s is %s", d.Name, d.Spec.Status), nil
+		case d.Spec.Usage != apiV1.DriveUsageInUse:
+			return fmt.Sprintf("usage of drive %s is %s", d.Name, d.Spec.Usage), nil
+		}
+		ac, err := c.crHelper.GetACByLocation(ctx, d.Spec.UUID)
+		if err != nil && !errors.Is(err, errTypes.ErrorNotFound) {
+			return "", err
+		}
+		if ac == nil {
+			return fmt.Sprintf("drive %s isn't handled by discovery yet", d.Name), nil
+		}
+		volumes, err := c.crHelper.GetVolumesByLocation(ctx, d.Spec.UUID)
+		if err != nil {
+			return "", err
+		}
+		if len(volumes) > 0 {
+			return fmt.Sprintf("drive %s has volumes", d.Name), nil
+		}
+		lvg, err := c.crHelper.GetLVGByDrive(ctx, d.Spec.UUID)
+		if err != nil {
+			return "", err
+		}
+		if lvg != nil {
+			return fmt.Sprintf("drive %s is used by LogicalVolumeGroup %s", d.Name, lvg.Name), nil
+		}
+	}
+	return "", nil
+}
+
+// evacuateDrive moves data of the drive to other drives of its LogicalVolumeGroup and removes drive from it,
+// capacity of the drive is returned to drive's AC.
+// Drive is requeued while data is moved, maintenance annotation is removed after the attempt and
+// result is stored in evacuation status annotation
+func (c *Controller) evacuateDrive(ctx context.Context, drive *drivecrd.Drive) (ctrl.Result, error) {
+	log := c.log.WithFields(logrus.Fields{"method": "evacuateDrive", "name": drive.Name})
+
+	lvg, err := c.crHelper.GetLVGByDrive(ctx, drive.Spec.UUID)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, err
+	}
+	switch {
+	case lvg == nil:
+		err = fmt.Errorf("drive isn't used by LogicalVolumeGroup")
+	case lvg.Spec.Status == apiV1.Creating:
+		log.Warnf("LogicalVolumeGroup %s is being created, evacuation is postponed", lvg.Name)
+		return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, nil
+	case len(lvg.Spec.Locations) == 1:
+		err = fmt.Errorf("drive is the only drive of LogicalVolumeGroup %s", lvg.Name)
+	default:
+		var finished bool
+		if finished, err = c.evacuate(ctx, lvg, drive); !finished {
+			return c.waitEvacuation(ctx, drive)
+		}
+	}
+	c.setEvacuationStatus(drive, err)
+	delete(drive.Annotations, apiV1.DriveAnnotationMaintenance)
 
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		log.Errorf("Failed to update Drive %s CR", drive.Name)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
 	return ctrl.Result{}, nil
 }
 
+// evacuateSuspectDrive evacuates SUSPECT drive from LogicalVolumeGroup with several drives, so drive could be
+// replaced without downtime of LogicalVolumeGroup's volumes. If evacuation fails LogicalVolumeGroup inherits drive's
+// health and its volumes are released
+// Returns true if evacuation is in progress, drive must not be released until it is finished
+func (c *Controller) evacuateSuspectDrive(ctx context.Context, drive *drivecrd.Drive) bool {
+	log := c.log.WithFields(logrus.Fields{"method": "evacuateSuspectDrive", "name": drive.Name})
+
+	lvg, err := c.crHelper.GetLVGByDrive(ctx, drive.Spec.UUID)
+	if err != nil || lvg == nil || len(lvg.Spec.Locations) < 2 {
+		return false
+	}
+	finished := true
+	if lvg.Spec.Status == apiV1.Created {
+		finished, err = c.evacuate(ctx, lvg, drive)
+	} else {
+		err = fmt.Errorf("LogicalVolumeGroup %s isn't created", lvg.Name)
+	}
+	if !finished {
+		return true
+	}
+	if drive.Annotations == nil {
+		drive.Annotations = map[string]string{}
+	}
+	c.setEvacuationStatus(drive, err)
+	if err == nil {
+		return false
+	}
+
+	lvg.Spec.Health = drive.Spec.Health
+	if err := c.client.UpdateCR(ctx, lvg); err != nil {
+		log.Errorf("Failed to update health of LogicalVolumeGroup %s: %v", lvg.Name, err)
+	}
+	volumes, err := c.crHelper.GetVolumesByLocation(ctx, lvg.Name)
+	if err != nil {
+		log.Errorf("Failed to read volumes of LogicalVolumeGroup %s: %v", lvg.Name, err)
+		return false
+	}
+	for _, vol := range volumes {
+		vol.Spec.Health = drive.Spec.Health
+		if vol.Spec.Usage == apiV1.VolumeUsageInUse {
+			vol.Spec.Usage = apiV1.VolumeUsageReleasing
+		}
+		if err := c.client.UpdateCR(ctx, vol); err != nil {
+			log.Errorf("Failed to update volume %s: %v", vol.Name, err)
+		}
+	}
+	return false
+}
+
+// waitEvacuation stores in-progress status of evacuation in the drive annotation and requeues the drive
+// until evacuation is finished
+func (c *Controller) waitEvacuation(ctx context.Context, drive *drivecrd.Drive) (ctrl.Result, error) {
+	result := ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}
+	if drive.Annotations[apiV1.DriveAnnotationEvacuationStatus] == apiV1.DriveAnnotationEvacuationStatusInProgress {
+		return result, nil
+	}
+	if drive.Annotations == nil {
+		drive.Annotations = map[string]string{}
+	}
+	drive.Annotations[apiV1.DriveAnnotationEvacuationStatus] = apiV1.DriveAnnotationEvacuationStatusInProgress
+	if err := c.client.UpdateCR(ctx, drive); err != nil {
+		c.log.WithFields(logrus.Fields{"method": "waitEvacuation", "name": drive.Name}).
+			Errorf("Failed to update Drive %s CR: %v", drive.Name, err)
+		return result, client.IgnoreNotFound(err)
+	}
+	return result, nil
+}
+
+// setEvacuationStatus stores result of drive evacuation in annotation and sends event
+func (c *Controller) setEvacuationStatus(drive *drivecrd.Drive, err error) {
+	if err != nil {
+		c.log.WithFields(logrus.Fields{"method": "setEvacuationStatus", "name": drive.Name}).
+			Errorf("Failed to evacuate drive %s, err %v", drive.Spec.SerialNumber, err)
+		drive.Annotations[apiV1.DriveAnnotationEvacuationStatus] = apiV1.DriveAnnotationEvacuationStatusFailed
+		eventMsg := fmt.Sprintf("Failed to evacuate drive: %v, %s", err, drive.GetDriveDescription())
+		c.eventRecorder.Eventf(drive, eventing.ErrorType, eventing.DriveEvacuationFailed, eventMsg)
+		return
+	}
+	drive.Annotations[apiV1.DriveAnnotationEvacuationStatus] = apiV1.DriveAnnotationEvacuationStatusDone
+	eventMsg := fmt.Sprintf("Drive successfully evacuated, %s", drive.GetDriveDescription())
+	c.eventRecorder.Eventf(drive, eventing.NormalType, eventing.DriveEvacuated, eventMsg)
+}
+
+// evacuate moves data of the drive to the rest of drives of LogicalVolumeGroup with pvmove in background, so Reconcile
+// isn't blocked while data is moved. Capacity of the drive is excluded from LogicalVolumeGroup's AC when evacuation is
+// started, so free space of the rest of drives must be enough to hold data of the drive. Evacuation which was
+// interrupted by restart of the node is resumed
+// Returns true if evacuation is finished and its error
+func (c *Controller) evacuate(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup, drive *drivecrd.Drive) (bool, error) {
+	c.evacuationsMu.Lock()
+	e, found := c.evacuations[drive.Name]
+	c.evacuationsMu.Unlock()
+	if found {
+		select {
+		case <-e.done:
+		default:
+			return false, nil
+		}
+		c.evacuationsMu.Lock()
+		delete(c.evacuations, drive.Name)
+		c.evacuationsMu.Unlock()
+		return true, c.completeEvacuation(ctx, lvg, drive, e.err)
+	}
+
+	resume := drive.Annotations[apiV1.DriveAnnotationEvacuationStatus] == apiV1.DriveAnnotationEvacuationStatusInProgress
+	if !resume {
+		if err := c.reserveEvacuationCapacity(ctx, lvg, drive); err != nil {
+			return true, err
+		}
+	}
+	dev, err := c.listBlk.SearchDrivePath(drive)
+	if err != nil {
+		return true, c.completeEvacuation(ctx, lvg, drive, err)
+	}
+
+	e = &evacuation{done: make(chan struct{})}
+	c.evacuationsMu.Lock()
+	c.evacuations[drive.Name] = e
+	c.evacuationsMu.Unlock()
+	go func() {
+		defer close(e.done)
+		e.err = c.movePV(lvg.Name, dev, resume)
+	}()
+	c.log.WithFields(logrus.Fields{"method": "evacuate", "name": drive.Name}).
+		Infof("Evacuation of %s from LogicalVolumeGroup %s is started, resumed: %v", dev, lvg.Name, resume)
+	return false, nil
+}
+
+// reserveEvacuationCapacity excludes capacity of the drive from LogicalVolumeGroup's AC, so it isn't allocated
+// while data of the drive is moved
+func (c *Controller) reserveEvacuationCapacity(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup,
+	drive *drivecrd.Drive) error {
+	pvSize := capacityplanner.SubtractLVMMetadataSize(drive.Spec.Size)
+	lvgAC, err := c.crHelper.GetACByLocation(ctx, lvg.Name)
+	if err != nil {
+		return fmt.Errorf("unable to read AC of LogicalVolumeGroup: %v", err)
+	}
+	if lvgAC.Spec.Size < pvSize {
+		return fmt.Errorf("free space of LogicalVolumeGroup %s isn't enough to move data of the drive", lvg.Name)
+	}
+	lvgAC.Spec.Size -= pvSize
+	if err := c.client.UpdateCR(ctx, lvgAC); err != nil {
+		return fmt.Errorf("unable to update AC of LogicalVolumeGroup: %v", err)
+	}
+	return nil
+}
+
+// movePV moves data of the PV to the rest of PVs of volume group and removes PV from volume group.
+// Resumed move is skipped if PV was already removed from volume group
+func (c *Controller) movePV(vgName, dev string, resume bool) error {
+	if resume {
+		pvs, err := c.lvmOps.GetPVsInVG(vgName)
+		if err != nil {
+			return err
+		}
+		if !util.ContainsString(pvs, dev) {
+			return nil
+		}
+	}
+	if err := c.lvmOps.PVMove(dev); err != nil {
+		return err
+	}
+	if err := c.lvmOps.VGReduce(vgName, dev); err != nil {
+		return err
+	}
+	if err := c.lvmOps.PVRemove(dev); err != nil {
+		c.log.WithFields(logrus.Fields{"method": "movePV", "VGName": vgName}).
+			Warnf("Unable to remove PV %s: %v", dev, err)
+	}
+	return nil
+}
+
+// completeEvacuation removes evacuated drive from LogicalVolumeGroup and returns its capacity to drive's AC.
+// If evacuation failed capacity of the drive is returned to LogicalVolumeGroup's AC
+// Returns error of evacuation or error of LogicalVolumeGroup update
+func (c *Controller) completeEvacuation(ctx context.Context, lvg *lvgcrd.LogicalVolumeGroup, drive *drivecrd.Drive,
+	evacuationErr error) error {
+	log := c.log.WithFields(logrus.Fields{"method": "completeEvacuation", "name": drive.Name, "LVGName": lvg.Name})
+
+	pvSize := capacityplanner.SubtractLVMMetadataSize(drive.Spec.Size)
+	if evacuationErr != nil {
+		lvgAC, err := c.crHelper.GetACByLocation(ctx, lvg.Name)
+		if err != nil {
+			log.Errorf("Unable to read AC of LogicalVolumeGroup: %v", err)
+			return evacuationErr
+		}
+		lvgAC.Spec.Size += pvSize
+		if err := c.client.UpdateCR(ctx, lvgAC); err != nil {
+			log.Errorf("Unable to restore size of AC %s: %v", lvgAC.Name, err)
+		}
+		return evacuationErr
+	}
+
+	lvg.Spec.Locations = util.RemoveString(lvg.Spec.Locations, drive.Spec.UUID)
+	lvg.Spec.Size -= pvSize
+	if err := c.client.UpdateCR(ctx, lvg); err != nil {
+		return fmt.Errorf("unable to update LogicalVolumeGroup: %v", err)
+	}
+	c.eventRecorder.Eventf(lvg, eventing.NormalType, eventing.LVGReduced,
+		"Drive %s was removed from volume group, size %d", drive.Spec.SerialNumber, lvg.Spec.Size)
+
+	driveAC, err := c.crHelper.GetACByLocation(ctx, drive.Spec.UUID)
+	if err != nil {
+		log.Errorf("Unable to read AC of the drive: %v", err)
+		return nil
+	}
+	driveAC.Spec.Size = drive.Spec.Size
+	if err := c.client.UpdateCR(ctx, driveAC); err != nil {
+		log.Errorf("Unable to update size of AC %s: %v", driveAC.Name, err)
+	}
+	return nil
+}
+
 func (c *Controller) checkAllVolsRemoved(volumes []*volumecrd.Volume) bool {
 	for _, vol := range volumes {
 		if vol.Spec.CSIStatus != apiV1.Removed {
@@ -0,0 +1,662 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drive
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	vccrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
+
+var (
+	tCtx       = context.Background()
+	testLogger = logrus.New()
+	ns         = "default"
+	nodeID     = "node1"
+	driveUUID  = "uuid-drive1"
+
+	testDriveCR = drivecrd.Drive{
+		TypeMeta:   v1.TypeMeta{Kind: "Drive", APIVersion: apiV1.APIV1Version},
+		ObjectMeta: v1.ObjectMeta{Name: driveUUID},
+		Spec: api.Drive{
+			UUID:         driveUUID,
+			SerialNumber: "hdd1",
+			Health:       apiV1.HealthGood,
+			Status:       apiV1.DriveStatusOnline,
+			Usage:        apiV1.DriveUsageInUse,
+			Type:         apiV1.DriveTypeHDD,
+			NodeId:       nodeID,
+		},
+	}
+
+	testVolumeCR = vccrd.Volume{
+		TypeMeta:   v1.TypeMeta{Kind: "Volume", APIVersion: apiV1.APIV1Version},
+		ObjectMeta: v1.ObjectMeta{Name: "volume", Namespace: ns},
+		Spec: api.Volume{
+			Id:           "volume",
+			NodeId:       nodeID,
+			Location:     driveUUID,
+			LocationType: apiV1.LocationTypeDrive,
+			CSIStatus:    apiV1.Published,
+		},
+	}
+
+	testLVGCR = lvgcrd.LogicalVolumeGroup{
+		TypeMeta:   v1.TypeMeta{Kind: "LogicalVolumeGroup", APIVersion: apiV1.APIV1Version},
+		ObjectMeta: v1.ObjectMeta{Name: "lvg"},
+		Spec: api.LogicalVolumeGroup{
+			Name:      "lvg",
+			Node:      nodeID,
+			Locations: []string{driveUUID},
+			Status:    apiV1.Created,
+		},
+	}
+)
+
+func TestReconcile_AppendFinalizer(t *testing.T) {
+	c := setup(t, testDriveCR)
+
+	res, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: driveUUID}})
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+
+	drive := &drivecrd.Drive{}
+	assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", drive))
+	assert.Contains(t, drive.ObjectMeta.Finalizers, driveFinalizer)
+}
+
+func TestReconcile_DriveRemoving(t *testing.T) {
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: driveUUID}}
+	driveToDel := testDriveCR
+	driveToDel.ObjectMeta.DeletionTimestamp = &v1.Time{Time: time.Now()}
+	driveToDel.ObjectMeta.Finalizers = []string{driveFinalizer}
+
+	t.Run("Drive has volume, removal is postponed", func(t *testing.T) {
+		c := setup(t, driveToDel)
+		volume := testVolumeCR
+		assert.Nil(t, c.client.CreateCR(tCtx, volume.Name, &volume))
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, res)
+
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", drive))
+		assert.Contains(t, drive.ObjectMeta.Finalizers, driveFinalizer)
+	})
+
+	t.Run("Drive is used by LogicalVolumeGroup, removal is postponed", func(t *testing.T) {
+		c := setup(t, driveToDel)
+		lvg := testLVGCR
+		assert.Nil(t, c.client.CreateCR(tCtx, lvg.Name, &lvg))
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, res)
+
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", drive))
+		assert.Contains(t, drive.ObjectMeta.Finalizers, driveFinalizer)
+	})
+
+	t.Run("Drive isn't used, finalizer is removed", func(t *testing.T) {
+		c := setup(t, driveToDel)
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", drive))
+		assert.NotContains(t, drive.ObjectMeta.Finalizers, driveFinalizer)
+	})
+}
+
+func TestReconcile_PromoteHotSpare(t *testing.T) {
+	failed := testDriveCR
+	failed.ObjectMeta.Finalizers = []string{driveFinalizer}
+	failed.Spec.Health = apiV1.HealthBad
+	failed.Spec.Size = 100
+
+	newSpare := func(name string, size int64, driveType string) drivecrd.Drive {
+		spare := testDriveCR
+		spare.ObjectMeta = v1.ObjectMeta{
+			Name:        name,
+			Finalizers:  []string{driveFinalizer},
+			Annotations: map[string]string{apiV1.DriveAnnotationHotSpare: apiV1.DriveAnnotationHotSpareEnabled},
+		}
+		spare.Spec.UUID = name
+		spare.Spec.SerialNumber = name
+		spare.Spec.Size = size
+		spare.Spec.Type = driveType
+		return spare
+	}
+
+	c := setup(t, failed,
+		newSpare("small", 50, apiV1.DriveTypeHDD),
+		newSpare("ssd", 100, apiV1.DriveTypeSSD),
+		newSpare("big", 200, apiV1.DriveTypeHDD),
+		newSpare("suitable", 100, apiV1.DriveTypeHDD))
+
+	res, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: driveUUID}})
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+
+	drive := &drivecrd.Drive{}
+	assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", drive))
+	assert.Equal(t, apiV1.DriveUsageReleasing, drive.Spec.Usage)
+
+	for _, name := range []string{"small", "ssd", "big"} {
+		spare := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, name, "", spare))
+		assert.True(t, spare.IsHotSpare(), name)
+	}
+	promoted := &drivecrd.Drive{}
+	assert.Nil(t, c.client.ReadCR(tCtx, "suitable", "", promoted))
+	assert.False(t, promoted.IsHotSpare())
+	assert.Equal(t, driveUUID, promoted.Annotations[apiV1.DriveAnnotationHotSparePromotedFor])
+}
+
+// firmwareDriveMgrClient imitates drive manager which flashes firmware successfully
+type firmwareDriveMgrClient struct {
+	*mocks.MockDriveMgrClient
+	image string
+}
+
+// UpdateFirmware records image and returns new firmware version
+func (m *firmwareDriveMgrClient) UpdateFirmware(ctx context.Context, in *api.DriveFirmwareUpdateRequest,
+	opts ...grpc.CallOption) (*api.DriveFirmwareUpdateResponse, error) {
+	m.image = in.Image
+	return &api.DriveFirmwareUpdateResponse{Firmware: "newFirmware"}, nil
+}
+
+func TestReconcile_UpdateFirmware(t *testing.T) {
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: driveUUID}}
+	image := "/tmp/firmware images/image.bin"
+	newDrive := func(annotations map[string]string) drivecrd.Drive {
+		drive := testDriveCR
+		drive.ObjectMeta.Finalizers = []string{driveFinalizer}
+		drive.ObjectMeta.Annotations = annotations
+		drive.Spec.Firmware = "oldFirmware"
+		return drive
+	}
+
+	t.Run("Drive has volume, update is postponed and reconcile isn't blocked", func(t *testing.T) {
+		bad := newDrive(map[string]string{
+			apiV1.DriveAnnotationMaintenance:   apiV1.DriveAnnotationMaintenanceFirmwareUpdate,
+			apiV1.DriveAnnotationFirmwareImage: image,
+		})
+		bad.Spec.Health = apiV1.HealthBad
+		c := setup(t, bad)
+		volume := testVolumeCR
+		assert.Nil(t, c.client.CreateCR(tCtx, volume.Name, &volume))
+		fwClient := &firmwareDriveMgrClient{MockDriveMgrClient: mocks.NewMockDriveMgrClient(nil)}
+		c.driveMgrClient = fwClient
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, res)
+		assert.Empty(t, fwClient.image)
+
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", drive))
+		assert.Equal(t, apiV1.DriveAnnotationMaintenanceFirmwareUpdate, drive.Annotations[apiV1.DriveAnnotationMaintenance])
+		assert.Equal(t, apiV1.DriveAnnotationFirmwareStatusPostponed, drive.Annotations[apiV1.DriveAnnotationFirmwareStatus])
+		assert.Equal(t, "oldFirmware", drive.Spec.Firmware)
+		// usage of the bad drive is changed despite of the postponed update
+		assert.Equal(t, apiV1.DriveUsageReleasing, drive.Spec.Usage)
+	})
+
+	t.Run("Image isn't set, status is failed", func(t *testing.T) {
+		c := setup(t, newDrive(map[string]string{
+			apiV1.DriveAnnotationMaintenance:   apiV1.DriveAnnotationMaintenanceFirmwareUpdate,
+			apiV1.DriveAnnotationFirmwareImage: " ",
+		}))
+		fwClient := &firmwareDriveMgrClient{MockDriveMgrClient: mocks.NewMockDriveMgrClient(nil)}
+		c.driveMgrClient = fwClient
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+		assert.Empty(t, fwClient.image)
+
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", drive))
+		assert.Empty(t, drive.Annotations[apiV1.DriveAnnotationMaintenance])
+		assert.Equal(t, apiV1.DriveAnnotationFirmwareStatusFailed, drive.Annotations[apiV1.DriveAnnotationFirmwareStatus])
+	})
+
+	t.Run("Drive manager failed, status is failed", func(t *testing.T) {
+		c := setup(t, newDrive(map[string]string{
+			apiV1.DriveAnnotationMaintenance:   apiV1.DriveAnnotationMaintenanceFirmwareUpdate,
+			apiV1.DriveAnnotationFirmwareImage: image,
+		}))
+		c.driveMgrClient = &mocks.MockDriveMgrClientFail{}
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", drive))
+		assert.Empty(t, drive.Annotations[apiV1.DriveAnnotationMaintenance])
+		assert.Equal(t, apiV1.DriveAnnotationFirmwareStatusFailed, drive.Annotations[apiV1.DriveAnnotationFirmwareStatus])
+		assert.Equal(t, "oldFirmware", drive.Spec.Firmware)
+	})
+
+	t.Run("Postponed update succeeded after volume removal", func(t *testing.T) {
+		c := setup(t, newDrive(map[string]string{
+			apiV1.DriveAnnotationMaintenance:    apiV1.DriveAnnotationMaintenanceFirmwareUpdate,
+			apiV1.DriveAnnotationFirmwareImage:  image,
+			apiV1.DriveAnnotationFirmwareStatus: apiV1.DriveAnnotationFirmwareStatusPostponed,
+		}))
+		fwClient := &firmwareDriveMgrClient{MockDriveMgrClient: mocks.NewMockDriveMgrClient(nil)}
+		c.driveMgrClient = fwClient
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+		assert.Equal(t, image, fwClient.image)
+
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", drive))
+		assert.Empty(t, drive.Annotations[apiV1.DriveAnnotationMaintenance])
+		assert.Equal(t, apiV1.DriveAnnotationFirmwareStatusUpdated, drive.Annotations[apiV1.DriveAnnotationFirmwareStatus])
+		assert.Equal(t, "newFirmware", drive.Spec.Firmware)
+	})
+}
+
+type namespacesDriveMgrClient struct {
+	*mocks.MockDriveMgrClient
+	count int32
+}
+
+// CreateNamespaces records amount of namespaces and returns serial numbers of their drives
+func (m *namespacesDriveMgrClient) CreateNamespaces(ctx context.Context, in *api.DriveNamespacesRequest,
+	opts ...grpc.CallOption) (*api.DriveNamespacesResponse, error) {
+	m.count = in.Count
+	return &api.DriveNamespacesResponse{SerialNumbers: []string{"nvme1-ns1", "nvme1-ns2"}}, nil
+}
+
+func TestReconcile_CreateNamespaces(t *testing.T) {
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: driveUUID}}
+	nvme := testDriveCR
+	nvme.ObjectMeta.Finalizers = []string{driveFinalizer}
+	nvme.ObjectMeta.Annotations = map[string]string{
+		apiV1.DriveAnnotationMaintenance:        apiV1.DriveAnnotationMaintenanceNVMeNamespaces,
+		apiV1.DriveAnnotationNVMeNamespaceCount: "2",
+	}
+	nvme.Spec.SerialNumber = "nvme1-ns1"
+	nvme.Spec.Type = apiV1.DriveTypeNVMe
+	nvme.Spec.Usage = apiV1.DriveUsageInUse
+	sibling := nvme
+	sibling.ObjectMeta = v1.ObjectMeta{Name: "uuid-drive2", Finalizers: []string{driveFinalizer}}
+	sibling.Spec.UUID = "uuid-drive2"
+	sibling.Spec.SerialNumber = "nvme1-ns2"
+
+	// drives of the controller are handled by discovery
+	createACs := func(t *testing.T, c *Controller) {
+		for _, d := range []drivecrd.Drive{nvme, sibling} {
+			ac := c.client.ConstructACCR(d.Name+"-ac", api.AvailableCapacity{Location: d.Spec.UUID, NodeId: nodeID})
+			assert.Nil(t, c.client.CreateCR(tCtx, ac.Name, ac))
+		}
+	}
+	// refused checks that namespaces weren't created, maintenance annotation is removed and status is failed
+	refused := func(t *testing.T, c *Controller) {
+		nsClient := &namespacesDriveMgrClient{MockDriveMgrClient: mocks.NewMockDriveMgrClient(nil)}
+		c.driveMgrClient = nsClient
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+		assert.Zero(t, nsClient.count)
+
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", drive))
+		assert.Empty(t, drive.Annotations[apiV1.DriveAnnotationMaintenance])
+		assert.Equal(t, apiV1.DriveAnnotationNVMeNamespacesStatusFailed,
+			drive.Annotations[apiV1.DriveAnnotationNVMeNamespacesStatus])
+	}
+
+	t.Run("Drive of the same controller has volume, creation is refused", func(t *testing.T) {
+		c := setup(t, nvme, sibling)
+		createACs(t, c)
+		volume := testVolumeCR
+		volume.Spec.Location = sibling.Spec.UUID
+		assert.Nil(t, c.client.CreateCR(tCtx, volume.Name, &volume))
+		refused(t, c)
+	})
+
+	t.Run("Drive of the same controller is system drive, creation is refused", func(t *testing.T) {
+		system := sibling
+		system.Spec.IsSystem = true
+		c := setup(t, nvme, system)
+		createACs(t, c)
+		refused(t, c)
+	})
+
+	t.Run("Drive of the same controller isn't clean, creation is refused", func(t *testing.T) {
+		notClean := sibling
+		notClean.Spec.Usage = apiV1.DriveUsageNotClean
+		c := setup(t, nvme, notClean)
+		createACs(t, c)
+		refused(t, c)
+	})
+
+	t.Run("Drive of the same controller isn't handled by discovery, creation is refused", func(t *testing.T) {
+		refused(t, setup(t, nvme, sibling))
+	})
+
+	t.Run("Drives of the controller are free, namespaces are created", func(t *testing.T) {
+		c := setup(t, nvme, sibling)
+		createACs(t, c)
+		nsClient := &namespacesDriveMgrClient{MockDriveMgrClient: mocks.NewMockDriveMgrClient(nil)}
+		c.driveMgrClient = nsClient
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+		assert.Equal(t, int32(2), nsClient.count)
+
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", drive))
+		assert.Empty(t, drive.Annotations[apiV1.DriveAnnotationMaintenance])
+		assert.Equal(t, apiV1.DriveAnnotationNVMeNamespacesStatusCreated,
+			drive.Annotations[apiV1.DriveAnnotationNVMeNamespacesStatus])
+	})
+
+	t.Run("Drive manager failed, status is set", func(t *testing.T) {
+		c := setup(t, nvme, sibling)
+		createACs(t, c)
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", drive))
+		assert.Empty(t, drive.Annotations[apiV1.DriveAnnotationMaintenance])
+		assert.Equal(t, apiV1.DriveAnnotationNVMeNamespacesStatusFailed,
+			drive.Annotations[apiV1.DriveAnnotationNVMeNamespacesStatus])
+	})
+}
+
+func TestReconcile_EvacuateDrive(t *testing.T) {
+	var (
+		req       = ctrl.Request{NamespacedName: types.NamespacedName{Name: driveUUID}}
+		driveSize = int64(100 * util.GBYTE)
+		pvSize    = capacityplanner.SubtractLVMMetadataSize(driveSize)
+		dev       = "/dev/sda"
+	)
+	drive := testDriveCR
+	drive.Spec.Size = driveSize
+	drive.ObjectMeta.Finalizers = []string{driveFinalizer}
+	drive.Annotations = map[string]string{apiV1.DriveAnnotationMaintenance: apiV1.DriveAnnotationMaintenanceEvacuate}
+
+	prepare := func(t *testing.T, lvgFree int64) (*Controller, *mocklu.MockWrapLVM) {
+		var (
+			lvmOps  = &mocklu.MockWrapLVM{}
+			listBlk = &mocklu.MockWrapLsblk{}
+			lvg     = testLVGCR
+		)
+		c := setup(t, drive)
+		c.lvmOps = lvmOps
+		c.listBlk = listBlk
+		lvg.Spec.Locations = []string{"uuid-drive0", driveUUID}
+		lvg.Spec.Size = 2 * pvSize
+		assert.Nil(t, c.client.CreateCR(tCtx, lvg.Name, &lvg))
+		for _, ac := range []*accrd.AvailableCapacity{
+			c.client.ConstructACCR("lvg-ac", api.AvailableCapacity{
+				Location: lvg.Name, NodeId: nodeID, StorageClass: apiV1.StorageClassHDDLVG, Size: lvgFree}),
+			c.client.ConstructACCR("drive-ac", api.AvailableCapacity{
+				Location: driveUUID, NodeId: nodeID, StorageClass: apiV1.StorageClassHDD}),
+		} {
+			assert.Nil(t, c.client.CreateCR(tCtx, ac.Name, ac))
+		}
+		listBlk.On("SearchDrivePath", mock.Anything).Return(dev, nil)
+		return c, lvmOps
+	}
+	readACSize := func(c *Controller, name string) int64 {
+		ac := &accrd.AvailableCapacity{}
+		assert.Nil(t, c.client.ReadCR(tCtx, name, "", ac))
+		return ac.Spec.Size
+	}
+
+	t.Run("Drive is removed from LogicalVolumeGroup", func(t *testing.T) {
+		c, lvmOps := prepare(t, pvSize)
+		moved := make(chan time.Time)
+		lvmOps.On("PVMove", dev).WaitUntil(moved).Return(nil)
+		lvmOps.On("VGReduce", testLVGCR.Name, []string{dev}).Return(nil)
+		lvmOps.On("PVRemove", dev).Return(nil)
+
+		// Reconcile isn't blocked by pvmove
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, res)
+		currDrive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", currDrive))
+		assert.Equal(t, apiV1.DriveAnnotationMaintenanceEvacuate, currDrive.Annotations[apiV1.DriveAnnotationMaintenance])
+		assert.Equal(t, apiV1.DriveAnnotationEvacuationStatusInProgress,
+			currDrive.Annotations[apiV1.DriveAnnotationEvacuationStatus])
+		assert.Equal(t, int64(0), readACSize(c, "lvg-ac"))
+
+		res, err = c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, res)
+
+		close(moved)
+		reconcileUntilEvacuated(t, c, req)
+
+		currDrive = &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", currDrive))
+		assert.Empty(t, currDrive.Annotations[apiV1.DriveAnnotationMaintenance])
+		assert.Equal(t, apiV1.DriveAnnotationEvacuationStatusDone,
+			currDrive.Annotations[apiV1.DriveAnnotationEvacuationStatus])
+		lvg := &lvgcrd.LogicalVolumeGroup{}
+		assert.Nil(t, c.client.ReadCR(tCtx, testLVGCR.Name, "", lvg))
+		assert.Equal(t, []string{"uuid-drive0"}, lvg.Spec.Locations)
+		assert.Equal(t, pvSize, lvg.Spec.Size)
+		assert.Equal(t, int64(0), readACSize(c, "lvg-ac"))
+		assert.Equal(t, driveSize, readACSize(c, "drive-ac"))
+	})
+
+	t.Run("Free space isn't enough, status is set", func(t *testing.T) {
+		c, lvmOps := prepare(t, pvSize-1)
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+		lvmOps.AssertNotCalled(t, "PVMove", dev)
+
+		currDrive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", currDrive))
+		assert.Equal(t, apiV1.DriveAnnotationEvacuationStatusFailed,
+			currDrive.Annotations[apiV1.DriveAnnotationEvacuationStatus])
+		assert.Equal(t, pvSize-1, readACSize(c, "lvg-ac"))
+	})
+
+	t.Run("pvmove failed, capacity is restored", func(t *testing.T) {
+		c, lvmOps := prepare(t, pvSize)
+		lvmOps.On("PVMove", dev).Return(errors.New("pvmove failed"))
+
+		reconcileUntilEvacuated(t, c, req)
+
+		currDrive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", currDrive))
+		assert.Equal(t, apiV1.DriveAnnotationEvacuationStatusFailed,
+			currDrive.Annotations[apiV1.DriveAnnotationEvacuationStatus])
+		lvg := &lvgcrd.LogicalVolumeGroup{}
+		assert.Nil(t, c.client.ReadCR(tCtx, testLVGCR.Name, "", lvg))
+		assert.Len(t, lvg.Spec.Locations, 2)
+		assert.Equal(t, pvSize, readACSize(c, "lvg-ac"))
+	})
+
+	t.Run("Interrupted evacuation is resumed", func(t *testing.T) {
+		// capacity was reserved before restart
+		c, lvmOps := prepare(t, 0)
+		interrupted := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", interrupted))
+		interrupted.Annotations[apiV1.DriveAnnotationEvacuationStatus] = apiV1.DriveAnnotationEvacuationStatusInProgress
+		assert.Nil(t, c.client.UpdateCR(tCtx, interrupted))
+		// PV was removed from volume group before restart
+		lvmOps.On("GetPVsInVG", testLVGCR.Name).Return([]string{"/dev/sdb"}, nil)
+
+		reconcileUntilEvacuated(t, c, req)
+		lvmOps.AssertNotCalled(t, "PVMove", dev)
+
+		currDrive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", currDrive))
+		assert.Empty(t, currDrive.Annotations[apiV1.DriveAnnotationMaintenance])
+		assert.Equal(t, apiV1.DriveAnnotationEvacuationStatusDone,
+			currDrive.Annotations[apiV1.DriveAnnotationEvacuationStatus])
+		lvg := &lvgcrd.LogicalVolumeGroup{}
+		assert.Nil(t, c.client.ReadCR(tCtx, testLVGCR.Name, "", lvg))
+		assert.Equal(t, []string{"uuid-drive0"}, lvg.Spec.Locations)
+		assert.Equal(t, int64(0), readACSize(c, "lvg-ac"))
+		assert.Equal(t, driveSize, readACSize(c, "drive-ac"))
+	})
+}
+
+func TestReconcile_EvacuateSuspectDrive(t *testing.T) {
+	var (
+		req       = ctrl.Request{NamespacedName: types.NamespacedName{Name: driveUUID}}
+		driveSize = int64(100 * util.GBYTE)
+		pvSize    = capacityplanner.SubtractLVMMetadataSize(driveSize)
+		dev       = "/dev/sda"
+	)
+	drive := testDriveCR
+	drive.Spec.Size = driveSize
+	drive.Spec.Health = apiV1.HealthSuspect
+	drive.ObjectMeta.Finalizers = []string{driveFinalizer}
+
+	prepare := func(t *testing.T, pvMoveErr error) *Controller {
+		var (
+			lvmOps  = &mocklu.MockWrapLVM{}
+			listBlk = &mocklu.MockWrapLsblk{}
+			lvg     = testLVGCR
+			volume  = testVolumeCR
+		)
+		c := setup(t, drive)
+		c.lvmOps = lvmOps
+		c.listBlk = listBlk
+		lvg.Spec.Locations = []string{"uuid-drive0", driveUUID}
+		lvg.Spec.Size = 2 * pvSize
+		lvg.Spec.Health = apiV1.HealthGood
+		assert.Nil(t, c.client.CreateCR(tCtx, lvg.Name, &lvg))
+		ac := c.client.ConstructACCR("lvg-ac", api.AvailableCapacity{
+			Location: lvg.Name, NodeId: nodeID, StorageClass: apiV1.StorageClassHDDLVG, Size: pvSize})
+		assert.Nil(t, c.client.CreateCR(tCtx, ac.Name, ac))
+		volume.Spec.Location = lvg.Name
+		volume.Spec.Usage = apiV1.VolumeUsageInUse
+		assert.Nil(t, c.client.CreateCR(tCtx, volume.Name, &volume))
+
+		listBlk.On("SearchDrivePath", mock.Anything).Return(dev, nil)
+		lvmOps.On("PVMove", dev).Return(pvMoveErr)
+		lvmOps.On("VGReduce", testLVGCR.Name, []string{dev}).Return(nil)
+		lvmOps.On("PVRemove", dev).Return(nil)
+		return c
+	}
+
+	t.Run("Drive is evacuated, volumes aren't released", func(t *testing.T) {
+		c := prepare(t, nil)
+
+		reconcileUntilEvacuated(t, c, req)
+
+		currDrive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", currDrive))
+		assert.Equal(t, apiV1.DriveUsageReleasing, currDrive.Spec.Usage)
+		assert.Equal(t, apiV1.DriveAnnotationEvacuationStatusDone,
+			currDrive.Annotations[apiV1.DriveAnnotationEvacuationStatus])
+		lvg := &lvgcrd.LogicalVolumeGroup{}
+		assert.Nil(t, c.client.ReadCR(tCtx, testLVGCR.Name, "", lvg))
+		assert.Equal(t, []string{"uuid-drive0"}, lvg.Spec.Locations)
+		assert.Equal(t, apiV1.HealthGood, lvg.Spec.Health)
+		volume := &vccrd.Volume{}
+		assert.Nil(t, c.client.ReadCR(tCtx, testVolumeCR.Name, ns, volume))
+		assert.Equal(t, apiV1.VolumeUsageInUse, volume.Spec.Usage)
+
+		// drive doesn't have volumes anymore
+		_, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", currDrive))
+		assert.Equal(t, apiV1.DriveUsageReleased, currDrive.Spec.Usage)
+	})
+
+	t.Run("Evacuation failed, volumes are released", func(t *testing.T) {
+		c := prepare(t, errors.New("pvmove failed"))
+
+		reconcileUntilEvacuated(t, c, req)
+
+		currDrive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, driveUUID, "", currDrive))
+		assert.Equal(t, apiV1.DriveUsageReleasing, currDrive.Spec.Usage)
+		assert.Equal(t, apiV1.DriveAnnotationEvacuationStatusFailed,
+			currDrive.Annotations[apiV1.DriveAnnotationEvacuationStatus])
+		lvg := &lvgcrd.LogicalVolumeGroup{}
+		assert.Nil(t, c.client.ReadCR(tCtx, testLVGCR.Name, "", lvg))
+		assert.Len(t, lvg.Spec.Locations, 2)
+		assert.Equal(t, apiV1.HealthSuspect, lvg.Spec.Health)
+		volume := &vccrd.Volume{}
+		assert.Nil(t, c.client.ReadCR(tCtx, testVolumeCR.Name, ns, volume))
+		assert.Equal(t, apiV1.VolumeUsageReleasing, volume.Spec.Usage)
+		assert.Equal(t, apiV1.HealthSuspect, volume.Spec.Health)
+	})
+}
+
+// reconcileUntilEvacuated reconciles the drive until its evacuation isn't in progress
+func reconcileUntilEvacuated(t *testing.T, c *Controller, req ctrl.Request) {
+	assert.Eventually(t, func() bool {
+		_, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, c.client.ReadCR(tCtx, req.Name, "", drive))
+		return drive.Annotations[apiV1.DriveAnnotationEvacuationStatus] != apiV1.DriveAnnotationEvacuationStatusInProgress
+	}, time.Second, 10*time.Millisecond)
+}
+
+func setup(t *testing.T, drives ...drivecrd.Drive) *Controller {
+	k8sClient, err := k8s.GetFakeKubeClient(ns, testLogger)
+	assert.Nil(t, err)
+	for _, drive := range drives {
+		drive := drive
+		assert.Nil(t, k8sClient.CreateCR(tCtx, drive.Name, &drive))
+	}
+
+	return NewController(k8sClient, nodeID, mocks.NewMockDriveMgrClient(nil), new(mocks.NoOpRecorder), testLogger)
+}
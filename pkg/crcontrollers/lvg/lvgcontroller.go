@@ -34,12 +34,15 @@ import (
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	vccrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/crashdump"
 	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lvm"
 	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
 	metricsC "github.com/dell/csi-baremetal/pkg/metrics/common"
 )
 
@@ -54,32 +57,48 @@ type Controller struct {
 	lvmOps  lvm.WrapLVM
 	e       command.CmdExecutor
 
+	eventRecorder eventRecorder
+
 	node string
 	log  *logrus.Entry
+	// records panics of Reconcile, nil if crash dumps are disabled
+	crashRecorder *crashdump.Recorder
+}
+
+// eventRecorder interface for sending events
+type eventRecorder interface {
+	Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{})
 }
 
 // NewController is the constructor for Controller struct
-// Receives an instance of base.KubeClient, ID of a node where it works and logrus logger
+// Receives an instance of base.KubeClient, ID of a node where it works, event recorder and logrus logger
 // Returns an instance of Controller
-func NewController(k8sClient *k8s.KubeClient, nodeID string, log *logrus.Logger) *Controller {
+func NewController(k8sClient *k8s.KubeClient, nodeID string, eventRecorder eventRecorder, log *logrus.Logger) *Controller {
 	e := command.NewExecutor(log)
 	return &Controller{
-		k8sClient: k8sClient,
-		crHelper:  k8s.NewCRHelper(k8sClient, log),
-		node:      nodeID,
-		log:       log.WithField("component", "Controller"),
-		e:         e,
-		lvmOps:    lvm.NewLVM(e, log),
-		listBlk:   lsblk.NewLSBLK(log),
+		k8sClient:     k8sClient,
+		crHelper:      k8s.NewCRHelper(k8sClient, log),
+		node:          nodeID,
+		log:           log.WithField("component", "Controller"),
+		e:             e,
+		lvmOps:        lvm.NewLVM(e, log),
+		listBlk:       lsblk.NewLSBLK(log),
+		eventRecorder: eventRecorder,
 	}
 }
 
+// SetCrashRecorder makes panics of Reconcile be recorded into crash dump
+func (c *Controller) SetCrashRecorder(r *crashdump.Recorder) {
+	c.crashRecorder = r
+}
+
 // Reconcile is the main Reconcile loop of Controller. This loop handles creation of VG matched to LogicalVolumeGroup CR on
 // Controller's node if LogicalVolumeGroup.Spec.Status is Creating. Also this loop handles VG deletion on the node if
 // LogicalVolumeGroup.ObjectMeta.DeletionTimestamp is not zero and VG is not placed on system drive.
 // Returns reconcile result as ctrl.Result or error if something went wrong
 func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	defer metricsC.ReconcileDuration.EvaluateDurationForType("node_lvg_controller")()
+	defer c.crashRecorder.Recover("lvg/Reconcile", "")
 	ll := c.log.WithFields(logrus.Fields{
 		"method":  "Reconcile",
 		"LVGName": req.Name,
@@ -119,6 +138,10 @@ func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, c.resetACSizeOfLVG(lvg.Name)
 	}
 
+	if _, ok := lvg.Labels[base.LVGPolicyLabel]; ok && lvg.Spec.Status == apiV1.Created {
+		return c.extendLVG(lvg)
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -163,12 +186,28 @@ func (c *Controller) handlerLVGCreation(lvg *lvgcrd.LogicalVolumeGroup) (ctrl.Re
 		ll.Errorf("Unable to create system LogicalVolumeGroup: %v", err)
 		newStatus = apiV1.Failed
 	}
+	// drives of LVGPolicy which weren't added are returned, so policy could use them again
+	var excluded int64
+	if _, ok := lvg.Labels[base.LVGPolicyLabel]; ok {
+		for _, location := range lvg.Spec.Locations {
+			if util.ContainsString(locations, location) {
+				continue
+			}
+			if size := c.restoreDriveAC(location); size > 0 {
+				excluded += capacityplanner.SubtractLVMMetadataSize(size)
+			}
+		}
+	}
 	lvg.Spec.Status = newStatus
 	lvg.Spec.Locations = locations
+	lvg.Spec.Size -= excluded
 	if err := c.k8sClient.UpdateCR(context.Background(), lvg); err != nil {
 		ll.Errorf("Unable to update LogicalVolumeGroup status to %s, error: %v.", newStatus, err)
 		return ctrl.Result{Requeue: true}, err
 	}
+	if excluded != 0 {
+		c.increaseACSize(lvg.Name, -excluded)
+	}
 
 	return ctrl.Result{}, nil
 }
@@ -189,18 +228,25 @@ func (c *Controller) handleLVGRemoving(lvg *lvgcrd.LogicalVolumeGroup) (ctrl.Res
 		return ctrl.Result{Requeue: true}, err
 	}
 	// If Kubernetes has volumes with location of LogicalVolumeGroup, which is needed to be deleted,
-	// we prevent removing, because this LogicalVolumeGroup is still used.
+	// we prevent removing, because this LogicalVolumeGroup is still used. Removal is retried later.
 	for _, item := range volumes.Items {
 		if item.Spec.Location == lvg.Name && item.DeletionTimestamp.IsZero() {
 			ll.Debugf("There are volume %v with LogicalVolumeGroup location, stop LogicalVolumeGroup deletion", item)
-			return ctrl.Result{}, nil
+			return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, nil
 		}
 	}
 	// update AC size that point on that LogicalVolumeGroup
-	c.increaseACSize(lvg.Spec.Locations[0], lvg.Spec.Size)
+	// LogicalVolumeGroup of LVGPolicy could combine several drives, capacity of each drive is returned
+	if _, ok := lvg.Labels[base.LVGPolicyLabel]; ok {
+		for _, location := range lvg.Spec.Locations {
+			c.restoreDriveAC(location)
+		}
+	} else if len(lvg.Spec.Locations) > 0 {
+		c.increaseACSize(lvg.Spec.Locations[0], lvg.Spec.Size)
+	}
 
-	drivesUUIDs := c.k8sClient.GetSystemDriveUUIDs()
-	if !util.ContainsString(drivesUUIDs, lvg.Spec.Locations[0]) {
+	drivesUUIDs := c.k8sClient.GetSystemDriveUUIDs(context.Background())
+	if len(lvg.Spec.Locations) == 0 || !util.ContainsString(drivesUUIDs, lvg.Spec.Locations[0]) {
 		// cleanup LVM artifacts
 		if err := c.removeLVGArtifacts(lvg.Name); err != nil {
 			ll.Errorf("Unable to cleanup LVM artifacts: %v", err)
@@ -287,6 +333,100 @@ func (c *Controller) createSystemLVG(lvg *lvgcrd.LogicalVolumeGroup) (locations
 	return locations, nil
 }
 
+// extendLVG adds drives which were added to LogicalVolumeGroup by LVGPolicy to the volume group and increases
+// size of LogicalVolumeGroup and its AC. Drive which can't be added is removed from LogicalVolumeGroup and its AC
+// is restored
+func (c *Controller) extendLVG(lvg *lvgcrd.LogicalVolumeGroup) (ctrl.Result, error) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method":  "extendLVG",
+		"LVGName": lvg.Name,
+	})
+
+	pvs, err := c.lvmOps.GetPVsInVG(lvg.Name)
+	if err != nil {
+		ll.Errorf("Unable to read PVs of volume group: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if len(pvs) >= len(lvg.Spec.Locations) {
+		return ctrl.Result{}, nil
+	}
+
+	var (
+		locations = make([]string, 0, len(lvg.Spec.Locations))
+		added     []string // serial numbers of drives which are added to volume group
+		size      int64
+	)
+	for _, driveUUID := range lvg.Spec.Locations {
+		drive := &drivecrd.Drive{}
+		if err := c.k8sClient.ReadCR(context.Background(), driveUUID, "", drive); err != nil {
+			ll.Errorf("Unable to read drive %s: %v", driveUUID, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		dev, err := c.listBlk.SearchDrivePath(drive)
+		if err != nil {
+			ll.Errorf("Unable to find device of drive %s: %v", driveUUID, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		if !util.ContainsString(pvs, dev) {
+			if err = c.lvmOps.PVCreate(dev); err == nil {
+				err = c.lvmOps.VGExtend(lvg.Name, dev)
+			}
+			if err != nil {
+				ll.Errorf("Unable to add device %s (drive serial %s) to volume group: %v",
+					dev, drive.Spec.SerialNumber, err)
+				c.eventRecorder.Eventf(lvg, eventing.ErrorType, eventing.LVGExpansionFailed,
+					"Unable to add drive to volume group: %v, %s", err, drive.GetDriveDescription())
+				c.restoreDriveAC(driveUUID)
+				continue
+			}
+			ll.Infof("Device %s (drive serial %s) was added to volume group", dev, drive.Spec.SerialNumber)
+			added = append(added, drive.Spec.SerialNumber)
+		}
+		locations = append(locations, driveUUID)
+		size += capacityplanner.SubtractLVMMetadataSize(drive.Spec.Size)
+	}
+
+	delta := size - lvg.Spec.Size
+	lvg.Spec.Locations = locations
+	lvg.Spec.Size = size
+	if err := c.k8sClient.UpdateCR(context.Background(), lvg); err != nil {
+		ll.Errorf("Unable to update LogicalVolumeGroup: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if delta != 0 {
+		c.increaseACSize(lvg.Name, delta)
+	}
+	if len(added) > 0 {
+		c.eventRecorder.Eventf(lvg, eventing.NormalType, eventing.LVGExpanded,
+			"Drives %v were added to volume group, size %d", added, size)
+	}
+	return ctrl.Result{}, nil
+}
+
+// restoreDriveAC sets size of AC of the drive which isn't used by LogicalVolumeGroup anymore to size of the drive,
+// returns size of the drive or 0 if drive isn't found
+func (c *Controller) restoreDriveAC(driveUUID string) int64 {
+	ll := c.log.WithFields(logrus.Fields{
+		"method":  "restoreDriveAC",
+		"driveID": driveUUID,
+	})
+
+	drive := c.crHelper.GetDriveCRByUUID(context.Background(), driveUUID)
+	if drive == nil {
+		return 0
+	}
+	ac, err := c.crHelper.GetACByLocation(context.Background(), driveUUID)
+	if err != nil {
+		ll.Errorf("Unable to read AC of the drive: %v", err)
+		return drive.Spec.Size
+	}
+	ac.Spec.Size = drive.Spec.Size
+	if err := c.k8sClient.UpdateCR(context.Background(), ac); err != nil {
+		ll.Errorf("Unable to update size of AC %s: %v", ac.Name, err)
+	}
+	return drive.Spec.Size
+}
+
 // removeLVGArtifacts removes LogicalVolumeGroup and PVs that doesn't correspond to particular LogicalVolumeGroup
 // when LogicalVolumeGroup is removed all PVs that were in that LogicalVolumeGroup becomes orphans
 func (c *Controller) removeLVGArtifacts(lvgName string) error {
@@ -346,7 +486,7 @@ func (c *Controller) resetACSizeOfLVG(lvgName string) error {
 		ac  *accrd.AvailableCapacity
 	)
 	// read AC
-	if ac, err = c.crHelper.GetACByLocation(lvgName); err == nil {
+	if ac, err = c.crHelper.GetACByLocation(context.Background(), lvgName); err == nil {
 		// update if not null already
 		if ac.Spec.Size != 0 {
 			ac.Spec.Size = 0
@@ -358,7 +498,7 @@ func (c *Controller) resetACSizeOfLVG(lvgName string) error {
 		return nil
 	}
 
-	if err == errTypes.ErrorNotFound {
+	if errors.Is(err, errTypes.ErrorNotFound) {
 		// non re-triable error
 		c.log.Errorf("AC CR for LogicalVolumeGroup %s not found", lvgName)
 		return nil
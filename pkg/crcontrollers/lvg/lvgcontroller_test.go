@@ -36,6 +36,8 @@ import (
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	vccrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lvm"
@@ -157,7 +159,7 @@ var (
 )
 
 func Test_NewLVGController(t *testing.T) {
-	c := NewController(nil, "node", testLogger)
+	c := NewController(nil, "node", new(mocks.NoOpRecorder), testLogger)
 	assert.NotNil(t, c)
 }
 
@@ -269,7 +271,7 @@ func TestReconcile_TryToDeleteLVGWithVolume(t *testing.T) {
 
 	res, err := c.Reconcile(req)
 	assert.Nil(t, err)
-	assert.Equal(t, res, ctrl.Result{})
+	assert.Equal(t, res, ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume})
 
 	lvg := &lvgcrd.LogicalVolumeGroup{}
 	err = c.k8sClient.ReadCR(tCtx, lvgToDell.Name, "", lvg)
@@ -437,7 +439,7 @@ func setup(t *testing.T, node string, lvgs ...lvgcrd.LogicalVolumeGroup) *Contro
 		assert.Nil(t, k8sClient.CreateCR(tCtx, lvg.Name, &lvg))
 	}
 
-	return NewController(k8sClient, node, testLogger)
+	return NewController(k8sClient, node, new(mocks.NoOpRecorder), testLogger)
 }
 
 func TestController_appendFinalizer(t *testing.T) {
@@ -523,3 +525,86 @@ func TestController_removeFinalizer(t *testing.T) {
 		assert.Equal(t, ctrl.Result{Requeue: true}, res)
 	})
 }
+
+func TestReconcile_ExtendPolicyLVG(t *testing.T) {
+	var (
+		lvgSize = capacityplanner.SubtractLVMMetadataSize(apiDrive1.Size)
+		fLVG    = lvgCR1
+	)
+	fLVG.Labels = map[string]string{base.LVGPolicyLabel: "policy"}
+	fLVG.Finalizers = []string{lvgFinalizer}
+	fLVG.Spec.Status = apiV1.Created
+	fLVG.Spec.Health = apiV1.HealthGood
+	fLVG.Spec.Size = lvgSize
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: fLVG.Name}}
+
+	prepare := func(t *testing.T, vgExtendErr error) (*Controller, *accrd.AvailableCapacity) {
+		var (
+			lvmOps  = &mocklu.MockWrapLVM{}
+			listBlk = &mocklu.MockWrapLsblk{}
+			lvgAC   = acCR1
+			driveAC = acCR1
+		)
+		c := setup(t, node1ID, fLVG)
+		c.lvmOps = lvmOps
+		c.listBlk = listBlk
+
+		lvgAC.Spec.Size = lvgSize
+		assert.Nil(t, c.k8sClient.CreateCR(tCtx, lvgAC.Name, &lvgAC))
+		driveAC.Name = "ac2"
+		driveAC.Spec.Location = drive2UUID
+		driveAC.Spec.StorageClass = apiV1.StorageClassHDD
+		driveAC.Spec.Size = 0
+		assert.Nil(t, c.k8sClient.CreateCR(tCtx, driveAC.Name, &driveAC))
+
+		listBlk.On("SearchDrivePath", mock.MatchedBy(func(d *drivecrd.Drive) bool {
+			return d.Spec.UUID == drive1UUID
+		})).Return("/dev/sda", nil)
+		listBlk.On("SearchDrivePath", mock.MatchedBy(func(d *drivecrd.Drive) bool {
+			return d.Spec.UUID == drive2UUID
+		})).Return("/dev/sdb", nil)
+		lvmOps.On("GetPVsInVG", fLVG.Name).Return([]string{"/dev/sda"}, nil)
+		lvmOps.On("PVCreate", "/dev/sdb").Return(nil)
+		lvmOps.On("VGExtend", fLVG.Name, []string{"/dev/sdb"}).Return(vgExtendErr)
+		return c, &driveAC
+	}
+
+	t.Run("Drive is added to volume group", func(t *testing.T) {
+		c, driveAC := prepare(t, nil)
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+
+		lvg := &lvgcrd.LogicalVolumeGroup{}
+		assert.Nil(t, c.k8sClient.ReadCR(tCtx, fLVG.Name, "", lvg))
+		assert.Equal(t, []string{drive1UUID, drive2UUID}, lvg.Spec.Locations)
+		expectedSize := lvgSize + capacityplanner.SubtractLVMMetadataSize(apiDrive2.Size)
+		assert.Equal(t, expectedSize, lvg.Spec.Size)
+		ac := &accrd.AvailableCapacity{}
+		assert.Nil(t, c.k8sClient.ReadCR(tCtx, acCR1Name, "", ac))
+		assert.Equal(t, expectedSize, ac.Spec.Size)
+		ac = &accrd.AvailableCapacity{}
+		assert.Nil(t, c.k8sClient.ReadCR(tCtx, driveAC.Name, "", ac))
+		assert.Equal(t, int64(0), ac.Spec.Size)
+	})
+
+	t.Run("Drive which can't be added is returned", func(t *testing.T) {
+		c, driveAC := prepare(t, errors.New("vgextend failed"))
+
+		res, err := c.Reconcile(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+
+		lvg := &lvgcrd.LogicalVolumeGroup{}
+		assert.Nil(t, c.k8sClient.ReadCR(tCtx, fLVG.Name, "", lvg))
+		assert.Equal(t, []string{drive1UUID}, lvg.Spec.Locations)
+		assert.Equal(t, lvgSize, lvg.Spec.Size)
+		ac := &accrd.AvailableCapacity{}
+		assert.Nil(t, c.k8sClient.ReadCR(tCtx, acCR1Name, "", ac))
+		assert.Equal(t, lvgSize, ac.Spec.Size)
+		ac = &accrd.AvailableCapacity{}
+		assert.Nil(t, c.k8sClient.ReadCR(tCtx, driveAC.Name, "", ac))
+		assert.Equal(t, apiDrive2.Size, ac.Spec.Size)
+	})
+}
@@ -51,6 +51,52 @@ const (
 	csibmNodeFinalizer = "dell.emc.csi/csibmnode-cleanup"
 )
 
+// nodeUUIDNamespace is a namespace for name-based UUIDs of Node CRs
+var nodeUUIDNamespace = uuid.MustParse("42f53ae3-9965-4d8a-b2a6-143680287d47")
+
+// nodeUUID returns UUID of Node CR for k8s node, it is derived from the most stable identity of the node to not
+// change when node re-joins the cluster, is renamed or reinstalled or Node CR is recreated, so Drive CRs and volumes
+// of the node stay bound to it. Identities are (from the most stable):
+// system UUID (from BIOS, survives reinstall), machine ID (/etc/machine-id, survives rename) and node name.
+// Identity which is reported by other active k8s nodes too (for example cloned VMs) is skipped. Deleting and
+// NotReady nodes aren't taken into account, so renamed node keeps its UUID while the object with its old name exists
+func nodeUUID(k8sNode *coreV1.Node, k8sNodes []coreV1.Node) string {
+	identities := []func(node *coreV1.Node) string{
+		func(node *coreV1.Node) string { return node.Status.NodeInfo.SystemUUID },
+		func(node *coreV1.Node) string { return node.Status.NodeInfo.MachineID },
+	}
+	for _, identity := range identities {
+		if id := identity(k8sNode); id != "" && isUniqueIdentity(k8sNode, k8sNodes, id, identity) {
+			return uuid.NewSHA1(nodeUUIDNamespace, []byte(id)).String()
+		}
+	}
+	return uuid.NewSHA1(nodeUUIDNamespace, []byte(k8sNode.Name)).String()
+}
+
+// isUniqueIdentity checks that identity isn't reported by any other active k8s node
+func isUniqueIdentity(k8sNode *coreV1.Node, k8sNodes []coreV1.Node, id string, identity func(*coreV1.Node) string) bool {
+	for i := range k8sNodes {
+		if k8sNodes[i].Name != k8sNode.Name && isNodeActive(&k8sNodes[i]) && identity(&k8sNodes[i]) == id {
+			return false
+		}
+	}
+	return true
+}
+
+// isNodeActive returns false if k8s node is being deleted or kubelet stopped reporting Ready condition for it,
+// node which hasn't posted its conditions yet is considered active
+func isNodeActive(node *coreV1.Node) bool {
+	if node.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == coreV1.NodeReady {
+			return condition.Status == coreV1.ConditionTrue
+		}
+	}
+	return true
+}
+
 // Controller is a controller for Node CR
 type Controller struct {
 	k8sClient    *k8s.KubeClient
@@ -278,7 +324,16 @@ func (bmc *Controller) reconcileForK8sNode(k8sNode *coreV1.Node) (ctrl.Result, e
 		bmNodes = bmNodeCRs.Items
 	}
 
-	matchedCRs := make([]string, 0)
+	k8sNodes := new(coreV1.NodeList)
+	if err := bmc.k8sClient.ReadList(context.Background(), k8sNodes); err != nil {
+		ll.Errorf("Unable to read k8s nodes list: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	var (
+		matchedCRs = make([]string, 0)
+		id         = nodeUUID(k8sNode, k8sNodes.Items)
+	)
 	for i := range bmNodes {
 		matchedAddresses := bmc.matchedAddressesCount(&bmNodes[i], k8sNode)
 		if len(bmNodes[i].Spec.Addresses) > 0 && matchedAddresses == len(bmNodes[i].Spec.Addresses) {
@@ -286,6 +341,10 @@ func (bmc *Controller) reconcileForK8sNode(k8sNode *coreV1.Node) (ctrl.Result, e
 			matchedCRs = append(matchedCRs, bmNode.Name)
 			continue
 		}
+		// addresses of Node CR created for this k8s node before are updated below
+		if bmNodes[i].Spec.UUID == id {
+			continue
+		}
 		if matchedAddresses > 0 {
 			ll.Errorf("There is Node %s that partially match k8s node %s. Node.Spec: %v, k8s node addresses: %v. "+
 				"Node Spec should be edited to match exactly one kubernetes node",
@@ -299,17 +358,32 @@ func (bmc *Controller) reconcileForK8sNode(k8sNode *coreV1.Node) (ctrl.Result, e
 		return ctrl.Result{}, nil
 	}
 
-	// create Node CR
+	// create Node CR or update addresses of the one created for this k8s node before
 	if len(matchedCRs) == 0 {
-		id := uuid.New().String()
 		bmNodeName := namePrefix + id
-		bmNode = bmc.k8sClient.ConstructCSIBMNodeCR(bmNodeName, api.Node{
-			UUID:      id,
-			Addresses: bmc.constructAddresses(k8sNode),
-		})
-		bmNode.Finalizers = []string{csibmNodeFinalizer}
-		if err := bmc.k8sClient.CreateCR(context.Background(), bmNodeName, bmNode); err != nil {
-			ll.Errorf("Unable to create Node CR: %v", err)
+		bmNode = &nodecrd.Node{}
+		err := bmc.k8sClient.ReadCR(context.Background(), bmNodeName, "", bmNode)
+		switch {
+		case err == nil:
+			// node re-joined the cluster with other addresses, for example after rename or reinstall
+			ll.Infof("Addresses of k8s node were changed from %v, updating Node %s", bmNode.Spec.Addresses, bmNodeName)
+			bmNode.Spec.Addresses = bmc.constructAddresses(k8sNode)
+			if err := bmc.k8sClient.UpdateCR(context.Background(), bmNode); err != nil {
+				ll.Errorf("Unable to update Node CR: %v", err)
+				return ctrl.Result{Requeue: true}, err
+			}
+		case k8sError.IsNotFound(err):
+			bmNode = bmc.k8sClient.ConstructCSIBMNodeCR(bmNodeName, api.Node{
+				UUID:      id,
+				Addresses: bmc.constructAddresses(k8sNode),
+			})
+			bmNode.Finalizers = []string{csibmNodeFinalizer}
+			if err := bmc.k8sClient.CreateCR(context.Background(), bmNodeName, bmNode); err != nil {
+				ll.Errorf("Unable to create Node CR: %v", err)
+				return ctrl.Result{Requeue: true}, err
+			}
+		default:
+			ll.Errorf("Unable to read Node CR %s: %v", bmNodeName, err)
 			return ctrl.Result{Requeue: true}, err
 		}
 	}
@@ -219,6 +219,72 @@ func Test_reconcileForK8sNode(t *testing.T) {
 		val, ok := k8sNode.GetAnnotations()[nodeIDAnnotationKey]
 		assert.True(t, ok)
 		assert.Equal(t, bmNode.Spec.UUID, val)
+		// UUID is stable for the node
+		assert.Equal(t, nodeUUID(k8sNode, nil), bmNode.Spec.UUID)
+		assert.NotEqual(t, nodeUUID(testNode2.DeepCopy(), nil), bmNode.Spec.UUID)
+	})
+
+	t.Run("Renamed node is bound to the same Node CR", func(t *testing.T) {
+		var (
+			c       = setup(t)
+			k8sNode = testNode1.DeepCopy()
+		)
+
+		k8sNode.Status.NodeInfo.SystemUUID = "system-1"
+		createObjects(t, c.k8sClient, k8sNode)
+		_, err := c.reconcileForK8sNode(k8sNode)
+		assert.Nil(t, err)
+
+		// node is reinstalled with new hostname
+		assert.Nil(t, c.k8sClient.Delete(testCtx, k8sNode))
+		renamed := testNode1.DeepCopy()
+		renamed.Name = "node-1-renamed"
+		renamed.Status.NodeInfo.SystemUUID = "system-1"
+		renamed.Status.Addresses = convertCSIBMNodeAddrsToK8sNodeAddrs(map[string]string{
+			string(coreV1.NodeHostName):   "node-1-renamed",
+			string(coreV1.NodeInternalIP): "10.10.10.1",
+		})
+		createObjects(t, c.k8sClient, renamed)
+		res, err := c.reconcileForK8sNode(renamed)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+
+		bmNodesList := &nodecrd.NodeList{}
+		assert.Nil(t, c.k8sClient.ReadList(testCtx, bmNodesList))
+		assert.Equal(t, 1, len(bmNodesList.Items))
+		assert.Equal(t, "node-1-renamed", bmNodesList.Items[0].Spec.Addresses[string(coreV1.NodeHostName)])
+
+		assert.Nil(t, c.k8sClient.ReadCR(testCtx, renamed.Name, "", renamed))
+		assert.Equal(t, bmNodesList.Items[0].Spec.UUID, renamed.GetAnnotations()[nodeIDAnnotationKey])
+	})
+
+	t.Run("Node re-joined with changed address", func(t *testing.T) {
+		var (
+			c       = setup(t)
+			k8sNode = testNode1.DeepCopy()
+			id      = nodeUUID(k8sNode, nil)
+			bmNode  = testCSIBMNode1.DeepCopy()
+		)
+
+		bmNode.Name = namePrefix + id
+		bmNode.Spec.UUID = id
+		bmNode.Spec.Addresses = map[string]string{
+			string(coreV1.NodeHostName):   "node-1",
+			string(coreV1.NodeInternalIP): "10.10.10.100",
+		}
+		createObjects(t, c.k8sClient, k8sNode, bmNode)
+
+		res, err := c.reconcileForK8sNode(k8sNode)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+
+		bmNodesList := &nodecrd.NodeList{}
+		assert.Nil(t, c.k8sClient.ReadList(testCtx, bmNodesList))
+		assert.Equal(t, 1, len(bmNodesList.Items))
+		assert.Equal(t, testCSIBMNode1.Spec.Addresses, bmNodesList.Items[0].Spec.Addresses)
+
+		assert.Nil(t, c.k8sClient.ReadCR(testCtx, k8sNode.Name, "", k8sNode))
+		assert.Equal(t, id, k8sNode.GetAnnotations()[nodeIDAnnotationKey])
 	})
 
 	t.Run("K8s node addresses length is 0", func(t *testing.T) {
@@ -294,6 +360,47 @@ func Test_reconcileForK8sNode(t *testing.T) {
 	})
 }
 
+func Test_nodeUUID(t *testing.T) {
+	var (
+		node1 = testNode1.DeepCopy()
+		node2 = testNode2.DeepCopy()
+	)
+	node1.Status.NodeInfo = coreV1.NodeSystemInfo{SystemUUID: "system-1", MachineID: "machine-1"}
+	node2.Status.NodeInfo = coreV1.NodeSystemInfo{SystemUUID: "system-2", MachineID: "machine-2"}
+	id := nodeUUID(node1, []coreV1.Node{*node1, *node2})
+
+	// renamed node gets the same UUID
+	renamed := node1.DeepCopy()
+	renamed.Name = "node-1-renamed"
+	assert.Equal(t, id, nodeUUID(renamed, []coreV1.Node{*renamed, *node2}))
+
+	// renamed node keeps UUID while object with old name is NotReady or being deleted
+	notReady := node1.DeepCopy()
+	notReady.Status.Conditions = []coreV1.NodeCondition{{Type: coreV1.NodeReady, Status: coreV1.ConditionUnknown}}
+	assert.Equal(t, id, nodeUUID(renamed, []coreV1.Node{*notReady, *renamed, *node2}))
+	deleting := node1.DeepCopy()
+	deleting.DeletionTimestamp = &metaV1.Time{Time: time.Now()}
+	assert.Equal(t, id, nodeUUID(renamed, []coreV1.Node{*deleting, *renamed, *node2}))
+	ready := node1.DeepCopy()
+	ready.Status.Conditions = []coreV1.NodeCondition{{Type: coreV1.NodeReady, Status: coreV1.ConditionTrue}}
+	assert.NotEqual(t, id, nodeUUID(renamed, []coreV1.Node{*ready, *renamed, *node2}))
+
+	// reinstalled node gets the same UUID
+	reinstalled := node1.DeepCopy()
+	reinstalled.Status.NodeInfo.MachineID = "machine-3"
+	assert.Equal(t, id, nodeUUID(reinstalled, []coreV1.Node{*reinstalled, *node2}))
+
+	// system UUID isn't unique, machine ID is used
+	node2.Status.NodeInfo.SystemUUID = "system-1"
+	byMachineID := nodeUUID(node1, []coreV1.Node{*node1, *node2})
+	assert.NotEqual(t, id, byMachineID)
+	assert.NotEqual(t, byMachineID, nodeUUID(node2, []coreV1.Node{*node1, *node2}))
+
+	// node name is used if node doesn't report identities
+	assert.Equal(t, nodeUUID(testNode1.DeepCopy(), nil), nodeUUID(testNode1.DeepCopy(), []coreV1.Node{testNode1}))
+	assert.NotEqual(t, nodeUUID(testNode1.DeepCopy(), nil), nodeUUID(testNode2.DeepCopy(), nil))
+}
+
 func Test_reconcileForCSIBMNode(t *testing.T) {
 	t.Run("Node is being deleted. Annotation was removed.", func(t *testing.T) {
 		var (
@@ -0,0 +1,299 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crds installs and upgrades CSI custom resource definitions from manifests shipped with the operator
+package crds
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultEstablishTimeout is how long Installer waits for created or upgraded CRD to be served
+	DefaultEstablishTimeout = 30 * time.Second
+	establishInterval       = time.Second
+
+	crdKind = "CustomResourceDefinition"
+)
+
+// Installer creates and upgrades CRDs and migrates custom resources which are stored in version other than
+// storage version of CRD, so CRDs stay in sync with the driver instead of being installed once by helm
+type Installer struct {
+	client           k8sCl.Client
+	establishTimeout time.Duration
+	log              *logrus.Entry
+}
+
+// NewInstaller is the constructor for Installer
+// Receives controller-runtime client with apiextensions v1beta1 registered in its scheme and logrus logger
+func NewInstaller(client k8sCl.Client, logger *logrus.Logger) *Installer {
+	return &Installer{
+		client:           client,
+		establishTimeout: DefaultEstablishTimeout,
+		log:              logger.WithField("component", "CRDInstaller"),
+	}
+}
+
+// SetEstablishTimeout sets how long Installer waits for CRD to be established, 0 disables waiting
+func (i *Installer) SetEstablishTimeout(timeout time.Duration) {
+	i.establishTimeout = timeout
+}
+
+// LoadDir reads CRD manifests from *.yaml files of dir, documents of other kinds are skipped
+func LoadDir(dir string) ([]*apiextv1beta1.CustomResourceDefinition, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		if !f.IsDir() && (strings.HasSuffix(f.Name(), ".yaml") || strings.HasSuffix(f.Name(), ".yml")) {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var crds []*apiextv1beta1.CustomResourceDefinition
+	for _, name := range names {
+		fileCRDs, err := loadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("unable to load %s: %v", name, err)
+		}
+		crds = append(crds, fileCRDs...)
+	}
+	return crds, nil
+}
+
+// loadFile decodes CRDs from multi-document YAML file
+func loadFile(path string) ([]*apiextv1beta1.CustomResourceDefinition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var (
+		crds    []*apiextv1beta1.CustomResourceDefinition
+		decoder = yaml.NewYAMLOrJSONDecoder(f, 4096)
+	)
+	for {
+		crd := &apiextv1beta1.CustomResourceDefinition{}
+		if err = decoder.Decode(crd); err != nil {
+			if err == io.EOF {
+				return crds, nil
+			}
+			return nil, err
+		}
+		if crd.Kind != crdKind || crd.Name == "" {
+			continue
+		}
+		crds = append(crds, crd)
+	}
+}
+
+// Install creates or upgrades each of crds, waits until it is established and migrates custom resources stored
+// in previous versions. Returns error on the first CRD which couldn't be installed
+func (i *Installer) Install(ctx context.Context, crds []*apiextv1beta1.CustomResourceDefinition) error {
+	for _, crd := range crds {
+		if err := i.apply(ctx, crd); err != nil {
+			return fmt.Errorf("unable to install CRD %s: %v", crd.Name, err)
+		}
+	}
+	return nil
+}
+
+// apply creates CRD or updates spec, labels and annotations of existing one to desired
+func (i *Installer) apply(ctx context.Context, desired *apiextv1beta1.CustomResourceDefinition) error {
+	ll := i.log.WithField("crd", desired.Name)
+
+	current := &apiextv1beta1.CustomResourceDefinition{}
+	err := i.client.Get(ctx, k8sCl.ObjectKey{Name: desired.Name}, current)
+	switch {
+	case k8sError.IsNotFound(err):
+		crd := desired.DeepCopy()
+		crd.Status = apiextv1beta1.CustomResourceDefinitionStatus{}
+		if err = i.client.Create(ctx, crd); err != nil {
+			return err
+		}
+		ll.Info("CRD is created")
+		return i.waitEstablished(ctx, desired.Name)
+	case err != nil:
+		return err
+	}
+
+	// apiserver rejects removal of version which still has objects stored in it,
+	// such version is kept not served until objects are migrated to the new storage version
+	if missing := missingStoredVersions(current, desired); len(missing) > 0 {
+		ll.Infof("Versions %v are removed, migrating stored objects first", missing)
+		spec := desired.Spec.DeepCopy()
+		for _, version := range missing {
+			spec.Versions = append(spec.Versions, apiextv1beta1.CustomResourceDefinitionVersion{Name: version})
+		}
+		if err = i.update(ctx, current, desired, spec); err != nil {
+			return err
+		}
+		if err = i.migrate(ctx, current); err != nil {
+			return err
+		}
+	}
+
+	if err = i.update(ctx, current, desired, &desired.Spec); err != nil {
+		return err
+	}
+	return i.migrate(ctx, current)
+}
+
+// update sets spec, labels and annotations of desired to current CRD if they differ and waits until it is established
+func (i *Installer) update(ctx context.Context, current, desired *apiextv1beta1.CustomResourceDefinition,
+	spec *apiextv1beta1.CustomResourceDefinitionSpec) error {
+	// fields defaulted by apiserver are ignored, but removed versions and printer columns have to be detected
+	upToDate := equality.Semantic.DeepDerivative(*spec, current.Spec) &&
+		len(spec.Versions) == len(current.Spec.Versions) &&
+		len(spec.AdditionalPrinterColumns) == len(current.Spec.AdditionalPrinterColumns)
+	if current.Labels == nil {
+		current.Labels = map[string]string{}
+	}
+	for key, value := range desired.Labels {
+		upToDate = upToDate && current.Labels[key] == value
+		current.Labels[key] = value
+	}
+	if current.Annotations == nil {
+		current.Annotations = map[string]string{}
+	}
+	for key, value := range desired.Annotations {
+		upToDate = upToDate && current.Annotations[key] == value
+		current.Annotations[key] = value
+	}
+	if upToDate {
+		return nil
+	}
+
+	current.Spec = *spec.DeepCopy()
+	if err := i.client.Update(ctx, current); err != nil {
+		return err
+	}
+	i.log.WithField("crd", current.Name).Info("CRD is upgraded")
+	return i.waitEstablished(ctx, current.Name)
+}
+
+// migrate rewrites custom resources of crd in its storage version if other versions are listed in stored versions,
+// after that only storage version is left in status.storedVersions, so previous versions could be removed
+func (i *Installer) migrate(ctx context.Context, crd *apiextv1beta1.CustomResourceDefinition) error {
+	storage := storageVersion(crd)
+	if storage == "" || !hasStaleStoredVersion(crd, storage) {
+		return nil
+	}
+	ll := i.log.WithField("crd", crd.Name)
+	ll.Infof("Migrating objects stored in %v to %s", crd.Status.StoredVersions, storage)
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   crd.Spec.Group,
+		Version: storage,
+		Kind:    crd.Spec.Names.ListKind,
+	})
+	if err := i.client.List(ctx, list); err != nil {
+		return err
+	}
+	for idx := range list.Items {
+		// update without changes makes apiserver to write object in the current storage version
+		if err := i.client.Update(ctx, &list.Items[idx]); err != nil && !k8sError.IsNotFound(err) {
+			return fmt.Errorf("unable to migrate %s: %v", list.Items[idx].GetName(), err)
+		}
+	}
+
+	crd.Status.StoredVersions = []string{storage}
+	if err := i.client.Status().Update(ctx, crd); err != nil {
+		return err
+	}
+	ll.Infof("%d objects are migrated to %s", len(list.Items), storage)
+	return nil
+}
+
+// waitEstablished waits until CRD has Established condition
+func (i *Installer) waitEstablished(ctx context.Context, name string) error {
+	if i.establishTimeout == 0 {
+		return nil
+	}
+	return wait.PollImmediate(establishInterval, i.establishTimeout, func() (bool, error) {
+		crd := &apiextv1beta1.CustomResourceDefinition{}
+		if err := i.client.Get(ctx, k8sCl.ObjectKey{Name: name}, crd); err != nil {
+			return false, err
+		}
+		for _, cond := range crd.Status.Conditions {
+			if cond.Type == apiextv1beta1.Established && cond.Status == apiextv1beta1.ConditionTrue {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// storageVersion returns name of the version marked as storage one
+func storageVersion(crd *apiextv1beta1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return crd.Spec.Version
+}
+
+// hasStaleStoredVersion checks whether objects of crd could be stored in version other than storage one
+func hasStaleStoredVersion(crd *apiextv1beta1.CustomResourceDefinition, storage string) bool {
+	for _, v := range crd.Status.StoredVersions {
+		if v != storage {
+			return true
+		}
+	}
+	return false
+}
+
+// missingStoredVersions returns stored versions of current CRD which are absent in desired one
+func missingStoredVersions(current, desired *apiextv1beta1.CustomResourceDefinition) []string {
+	var missing []string
+	for _, stored := range current.Status.StoredVersions {
+		found := false
+		for _, v := range desired.Spec.Versions {
+			if v.Name == stored {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, stored)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,149 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+const driverCRDsDir = "../../../../charts/csi-baremetal-driver/crds"
+
+var (
+	testCtx    = context.Background()
+	testLogger = logrus.New()
+)
+
+func newTestInstaller(t *testing.T) (*Installer, k8sCl.Client) {
+	scheme, err := k8s.PrepareScheme()
+	assert.Nil(t, err)
+	assert.Nil(t, apiextv1beta1.AddToScheme(scheme))
+	client := fake.NewFakeClientWithScheme(scheme)
+	installer := NewInstaller(client, testLogger)
+	installer.SetEstablishTimeout(0)
+	return installer, client
+}
+
+func loadVolumeCRD(t *testing.T) *apiextv1beta1.CustomResourceDefinition {
+	crds, err := LoadDir(driverCRDsDir)
+	assert.Nil(t, err)
+	for _, crd := range crds {
+		if crd.Name == "volumes.csi-baremetal.dell.com" {
+			return crd
+		}
+	}
+	t.Fatal("volumes CRD is not found")
+	return nil
+}
+
+func TestLoadDir(t *testing.T) {
+	crds, err := LoadDir(driverCRDsDir)
+	assert.Nil(t, err)
+	assert.Len(t, crds, 7)
+	for _, crd := range crds {
+		assert.Equal(t, "csi-baremetal.dell.com", crd.Spec.Group)
+		assert.NotNil(t, crd.Spec.Validation)
+	}
+
+	volumeCRD := loadVolumeCRD(t)
+	assert.NotEmpty(t, volumeCRD.Spec.AdditionalPrinterColumns)
+
+	_, err = LoadDir("/not/existing/dir")
+	assert.NotNil(t, err)
+}
+
+func TestInstaller_Install(t *testing.T) {
+	installer, client := newTestInstaller(t)
+	desired := loadVolumeCRD(t)
+
+	// create
+	assert.Nil(t, installer.Install(testCtx, []*apiextv1beta1.CustomResourceDefinition{desired}))
+	crd := &apiextv1beta1.CustomResourceDefinition{}
+	assert.Nil(t, client.Get(testCtx, k8sCl.ObjectKey{Name: desired.Name}, crd))
+	assert.Equal(t, desired.Spec.AdditionalPrinterColumns, crd.Spec.AdditionalPrinterColumns)
+	resourceVersion := crd.ResourceVersion
+
+	// up to date CRD is not updated
+	assert.Nil(t, installer.Install(testCtx, []*apiextv1beta1.CustomResourceDefinition{desired}))
+	assert.Nil(t, client.Get(testCtx, k8sCl.ObjectKey{Name: desired.Name}, crd))
+	assert.Equal(t, resourceVersion, crd.ResourceVersion)
+
+	// drifted CRD is upgraded
+	crd.Spec.AdditionalPrinterColumns = crd.Spec.AdditionalPrinterColumns[:1]
+	crd.Spec.Validation = nil
+	assert.Nil(t, client.Update(testCtx, crd))
+	assert.Nil(t, installer.Install(testCtx, []*apiextv1beta1.CustomResourceDefinition{desired}))
+	assert.Nil(t, client.Get(testCtx, k8sCl.ObjectKey{Name: desired.Name}, crd))
+	assert.Equal(t, desired.Spec.AdditionalPrinterColumns, crd.Spec.AdditionalPrinterColumns)
+	assert.Equal(t, desired.Spec.Validation, crd.Spec.Validation)
+}
+
+func TestInstaller_InstallMigratesStoredVersions(t *testing.T) {
+	installer, client := newTestInstaller(t)
+	desired := loadVolumeCRD(t)
+
+	// CRD of the previous release stores objects in removed v1alpha1 version
+	old := desired.DeepCopy()
+	old.Spec.Versions = []apiextv1beta1.CustomResourceDefinitionVersion{
+		{Name: "v1", Served: true},
+		{Name: "v1alpha1", Served: true, Storage: true},
+	}
+	old.Status = apiextv1beta1.CustomResourceDefinitionStatus{StoredVersions: []string{"v1alpha1"}}
+	assert.Nil(t, client.Create(testCtx, old))
+
+	volume := &volumecrd.Volume{
+		TypeMeta:   metaV1.TypeMeta{Kind: "Volume", APIVersion: "csi-baremetal.dell.com/v1"},
+		ObjectMeta: metaV1.ObjectMeta{Name: "pvc-1", Namespace: "default"},
+	}
+	assert.Nil(t, client.Create(testCtx, volume))
+	assert.Nil(t, client.Get(testCtx, k8sCl.ObjectKey{Name: "pvc-1", Namespace: "default"}, volume))
+	resourceVersion := volume.ResourceVersion
+
+	assert.Nil(t, installer.Install(testCtx, []*apiextv1beta1.CustomResourceDefinition{desired}))
+
+	crd := &apiextv1beta1.CustomResourceDefinition{}
+	assert.Nil(t, client.Get(testCtx, k8sCl.ObjectKey{Name: desired.Name}, crd))
+	assert.Equal(t, []string{"v1"}, crd.Status.StoredVersions)
+	assert.Len(t, crd.Spec.Versions, 1)
+	assert.Equal(t, "v1", crd.Spec.Versions[0].Name)
+
+	assert.Nil(t, client.Get(testCtx, k8sCl.ObjectKey{Name: "pvc-1", Namespace: "default"}, volume))
+	assert.NotEqual(t, resourceVersion, volume.ResourceVersion)
+}
+
+func TestMissingStoredVersions(t *testing.T) {
+	current := &apiextv1beta1.CustomResourceDefinition{
+		Status: apiextv1beta1.CustomResourceDefinitionStatus{StoredVersions: []string{"v1alpha1", "v1"}},
+	}
+	desired := &apiextv1beta1.CustomResourceDefinition{
+		Spec: apiextv1beta1.CustomResourceDefinitionSpec{
+			Versions: []apiextv1beta1.CustomResourceDefinitionVersion{{Name: "v1", Served: true, Storage: true}},
+		},
+	}
+	assert.Equal(t, []string{"v1alpha1"}, missingStoredVersions(current, desired))
+	assert.Equal(t, "v1", storageVersion(desired))
+}
@@ -0,0 +1,237 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package csidriver creates and maintains CSIDriver object of the driver with fields derived from enabled features,
+// so the object doesn't drift from the deployed driver as a manually maintained manifest does
+package csidriver
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/clock"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+)
+
+const (
+	// FSGroupPolicyNone means that kubelet doesn't change ownership and permissions of volumes
+	FSGroupPolicyNone = "None"
+	// FSGroupPolicyFile means that kubelet always applies fsGroup of the pod, it is the default since volumes
+	// of the driver are local file systems which support ownership change
+	FSGroupPolicyFile = "File"
+	// FSGroupPolicyReadWriteOnceWithFSType means that fsGroup is applied only to RWO volumes with fsType set
+	FSGroupPolicyReadWriteOnceWithFSType = "ReadWriteOnceWithFSType"
+
+	// ManagedByLabel marks CSIDriver object which is maintained by operator
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	// ManagedByValue is the value of ManagedByLabel
+	ManagedByValue = "csi-baremetal-operator"
+
+	// ResyncInterval is the interval between checks of CSIDriver object, edited or deleted object is corrected by them
+	ResyncInterval = time.Minute
+
+	kind = "CSIDriver"
+)
+
+// apiVersions are versions of CSIDriver API in the order of preference,
+// v1 is served since k8s 1.18 and v1beta1 is removed in k8s 1.22
+var apiVersions = []string{"storage.k8s.io/v1", "storage.k8s.io/v1beta1"}
+
+// Features are features of deployed driver which CSIDriver fields depend on
+type Features struct {
+	// Attacher is true if external-attacher sidecar is deployed with controller service in attach compatibility mode,
+	// attach isn't required anyway
+	Attacher bool
+	// EphemeralVolumes is true if inline ephemeral volumes are allowed in pods
+	EphemeralVolumes bool
+	// FSGroupPolicy is one of FSGroupPolicy* values
+	FSGroupPolicy string
+}
+
+// Validate returns error if features can't be used for CSIDriver object
+func (f Features) Validate() error {
+	switch f.FSGroupPolicy {
+	case FSGroupPolicyNone, FSGroupPolicyFile, FSGroupPolicyReadWriteOnceWithFSType:
+		return nil
+	default:
+		return fmt.Errorf("unknown fsGroupPolicy %s, supported values are %s, %s, %s", f.FSGroupPolicy,
+			FSGroupPolicyNone, FSGroupPolicyFile, FSGroupPolicyReadWriteOnceWithFSType)
+	}
+}
+
+// Spec returns spec of CSIDriver object for the features in unstructured form
+func Spec(f Features) map[string]interface{} {
+	modes := []interface{}{"Persistent"}
+	if f.EphemeralVolumes {
+		modes = append(modes, "Ephemeral")
+	}
+	return map[string]interface{}{
+		// volumes are local and ControllerPublishVolume does nothing, so VolumeAttachment round-trip is skipped,
+		// attacher deployed in compatibility mode only detaches VolumeAttachments created by previous versions
+		"attachRequired": false,
+		// node service reads pod name and namespace from NodePublishVolume request
+		"podInfoOnMount": true,
+		// capacity is tracked by the scheduler extender, GetCapacity isn't implemented
+		"storageCapacity":      false,
+		"volumeLifecycleModes": modes,
+		"fsGroupPolicy":        f.FSGroupPolicy,
+	}
+}
+
+// Manager creates and maintains CSIDriver object of the driver
+type Manager struct {
+	client   k8sCl.Client
+	features Features
+	log      *logrus.Entry
+	// clock of the resync loop
+	clock clock.Clock
+}
+
+// NewManager is the constructor for Manager
+// Receives controller-runtime client, features of deployed driver and logrus logger
+func NewManager(client k8sCl.Client, features Features, logger *logrus.Logger) *Manager {
+	return &Manager{
+		client:   client,
+		features: features,
+		log:      logger.WithField("component", "CSIDriverManager"),
+		clock:    clock.RealClock{},
+	}
+}
+
+// Start ensures CSIDriver object each ResyncInterval until stopCh is closed, so edits or deletion of the object
+// are corrected, errors are logged and the object is ensured again on the next resync.
+// Manager implements manager.Runnable of controller-runtime
+func (m *Manager) Start(stopCh <-chan struct{}) error {
+	ll := m.log.WithField("method", "Start")
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	go func() {
+		<-stopCh
+		cancelFn()
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			ll.Info("CSIDriver resync is stopped")
+			return nil
+		case <-m.clock.After(ResyncInterval):
+		}
+		if err := m.Ensure(ctx); err != nil {
+			ll.Errorf("Unable to maintain CSIDriver: %v", err)
+		}
+	}
+}
+
+// Ensure creates CSIDriver object or recreates existing one if its spec differs from spec of the features,
+// the newest CSIDriver API version served by apiserver is used
+// Returns error if object couldn't be read, created or recreated
+func (m *Manager) Ensure(ctx context.Context) error {
+	if err := m.features.Validate(); err != nil {
+		return err
+	}
+	var err error
+	for _, apiVersion := range apiVersions {
+		if err = m.ensure(ctx, apiVersion); !meta.IsNoMatchError(err) {
+			return err
+		}
+		m.log.Infof("CSIDriver %s isn't served", apiVersion)
+	}
+	return err
+}
+
+// ensure creates or recreates CSIDriver object of apiVersion
+func (m *Manager) ensure(ctx context.Context, apiVersion string) error {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":     "ensure",
+		"apiVersion": apiVersion,
+	})
+
+	desired := &unstructured.Unstructured{Object: map[string]interface{}{"spec": Spec(m.features)}}
+	desired.SetAPIVersion(apiVersion)
+	desired.SetKind(kind)
+	desired.SetName(base.PluginName)
+	desired.SetLabels(map[string]string{ManagedByLabel: ManagedByValue})
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(desired.GroupVersionKind())
+	err := m.client.Get(ctx, k8sCl.ObjectKey{Name: base.PluginName}, current)
+	switch {
+	case k8sError.IsNotFound(err):
+		if err = m.client.Create(ctx, desired); err != nil {
+			return fmt.Errorf("unable to create CSIDriver %s: %v", base.PluginName, err)
+		}
+		ll.Infof("CSIDriver is created with spec %v", desired.Object["spec"])
+		return nil
+	case err != nil:
+		return err
+	}
+
+	if specUpToDate(current, desired) {
+		if current.GetLabels()[ManagedByLabel] == ManagedByValue {
+			return nil
+		}
+		// object created by the chart is taken over
+		labels := current.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[ManagedByLabel] = ManagedByValue
+		current.SetLabels(labels)
+		if err = m.client.Update(ctx, current); err != nil {
+			return fmt.Errorf("unable to update CSIDriver %s: %v", base.PluginName, err)
+		}
+		ll.Info("CSIDriver is taken over")
+		return nil
+	}
+
+	// spec of CSIDriver is immutable, so the object is recreated, kubelet and attach/detach controller
+	// read it on each operation and don't cache it
+	ll.Infof("CSIDriver spec %v differs from %v, recreating", current.Object["spec"], desired.Object["spec"])
+	if err = m.client.Delete(ctx, current); err != nil && !k8sError.IsNotFound(err) {
+		return fmt.Errorf("unable to delete CSIDriver %s: %v", base.PluginName, err)
+	}
+	if err = m.client.Create(ctx, desired); err != nil {
+		return fmt.Errorf("unable to create CSIDriver %s: %v", base.PluginName, err)
+	}
+	ll.Info("CSIDriver is recreated")
+	return nil
+}
+
+// specUpToDate returns true if each field of desired spec is equal in current spec, fields missing in object
+// created by operator aren't compared, since they are dropped by apiserver which doesn't support them
+func specUpToDate(current, desired *unstructured.Unstructured) bool {
+	currentSpec, _, _ := unstructured.NestedMap(current.Object, "spec")
+	desiredSpec, _, _ := unstructured.NestedMap(desired.Object, "spec")
+	managed := current.GetLabels()[ManagedByLabel] == ManagedByValue
+	for field, value := range desiredSpec {
+		currentValue, ok := currentSpec[field]
+		if !ok && managed {
+			continue
+		}
+		if !reflect.DeepEqual(currentValue, value) {
+			return false
+		}
+	}
+	return true
+}
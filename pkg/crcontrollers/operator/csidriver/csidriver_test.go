@@ -0,0 +1,161 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csidriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/clock"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+var (
+	testCtx      = context.Background()
+	testLogger   = logrus.New()
+	testFeatures = Features{EphemeralVolumes: true, FSGroupPolicy: FSGroupPolicyFile}
+)
+
+func newTestManager(t *testing.T, features Features) (*Manager, k8sCl.Client) {
+	scheme, err := k8s.PrepareScheme()
+	assert.Nil(t, err)
+	client := fake.NewFakeClientWithScheme(scheme)
+	return NewManager(client, features, testLogger), client
+}
+
+func readCSIDriver(t *testing.T, client k8sCl.Client) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersions[0])
+	obj.SetKind(kind)
+	assert.Nil(t, client.Get(testCtx, k8sCl.ObjectKey{Name: base.PluginName}, obj))
+	return obj
+}
+
+func TestSpec(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{
+		"attachRequired":       false,
+		"podInfoOnMount":       true,
+		"storageCapacity":      false,
+		"volumeLifecycleModes": []interface{}{"Persistent", "Ephemeral"},
+		"fsGroupPolicy":        FSGroupPolicyFile,
+	}, Spec(testFeatures))
+
+	spec := Spec(Features{Attacher: true, FSGroupPolicy: FSGroupPolicyNone})
+	assert.Equal(t, false, spec["attachRequired"])
+	assert.Equal(t, []interface{}{"Persistent"}, spec["volumeLifecycleModes"])
+}
+
+func TestManager_Ensure(t *testing.T) {
+	m, client := newTestManager(t, testFeatures)
+
+	// object is created
+	assert.Nil(t, m.Ensure(testCtx))
+	obj := readCSIDriver(t, client)
+	assert.Equal(t, ManagedByValue, obj.GetLabels()[ManagedByLabel])
+	attachRequired, _, _ := unstructured.NestedBool(obj.Object, "spec", "attachRequired")
+	assert.False(t, attachRequired)
+
+	// up to date object isn't changed
+	assert.Nil(t, m.Ensure(testCtx))
+	assert.Equal(t, obj.GetResourceVersion(), readCSIDriver(t, client).GetResourceVersion())
+
+	// fields which aren't served by apiserver are ignored
+	unstructured.RemoveNestedField(obj.Object, "spec", "storageCapacity")
+	assert.Nil(t, client.Update(testCtx, obj))
+	obj = readCSIDriver(t, client)
+	assert.Nil(t, m.Ensure(testCtx))
+	assert.Equal(t, obj.GetResourceVersion(), readCSIDriver(t, client).GetResourceVersion())
+
+	// object is recreated once features are changed
+	m, _ = newTestManager(t, Features{FSGroupPolicy: FSGroupPolicyFile})
+	m.client = client
+	assert.Nil(t, m.Ensure(testCtx))
+	modes, _, _ := unstructured.NestedStringSlice(readCSIDriver(t, client).Object, "spec", "volumeLifecycleModes")
+	assert.Equal(t, []string{"Persistent"}, modes)
+
+	m.features.FSGroupPolicy = "Always"
+	assert.NotNil(t, m.Ensure(testCtx))
+}
+
+func TestManager_Start(t *testing.T) {
+	m, client := newTestManager(t, testFeatures)
+	fakeClock := clock.NewFakeClock(time.Now())
+	m.clock = fakeClock
+	assert.Nil(t, m.Ensure(testCtx))
+
+	stopCh := make(chan struct{})
+	stopped := make(chan error)
+	go func() {
+		stopped <- m.Start(stopCh)
+	}()
+
+	// deleted object is created again on resync
+	assert.Nil(t, client.Delete(testCtx, readCSIDriver(t, client)))
+	assert.Eventually(t, fakeClock.HasWaiters, time.Second, 10*time.Millisecond)
+	fakeClock.Step(ResyncInterval)
+	assert.Eventually(t, func() bool {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersions[0])
+		obj.SetKind(kind)
+		return client.Get(testCtx, k8sCl.ObjectKey{Name: base.PluginName}, obj) == nil
+	}, time.Second, 10*time.Millisecond)
+
+	close(stopCh)
+	select {
+	case err := <-stopped:
+		assert.Nil(t, err)
+	case <-time.After(time.Second):
+		t.Error("resync isn't stopped")
+	}
+}
+
+func TestManager_EnsureTakeOver(t *testing.T) {
+	m, client := newTestManager(t, testFeatures)
+
+	// object created by the chart is labeled if it is up to date
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": Spec(testFeatures)}}
+	obj.SetAPIVersion(apiVersions[0])
+	obj.SetKind(kind)
+	obj.SetName(base.PluginName)
+	assert.Nil(t, client.Create(testCtx, obj))
+
+	assert.Nil(t, m.Ensure(testCtx))
+	assert.Equal(t, ManagedByValue, readCSIDriver(t, client).GetLabels()[ManagedByLabel])
+
+	// missing fields of object created by the chart are updated
+	assert.Nil(t, client.Delete(testCtx, obj))
+	obj = &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{
+		"attachRequired": false,
+		"podInfoOnMount": true,
+	}}}
+	obj.SetAPIVersion(apiVersions[0])
+	obj.SetKind(kind)
+	obj.SetName(base.PluginName)
+	assert.Nil(t, client.Create(testCtx, obj))
+
+	assert.Nil(t, m.Ensure(testCtx))
+	policy, _, _ := unstructured.NestedString(readCSIDriver(t, client).Object, "spec", "fsGroupPolicy")
+	assert.Equal(t, FSGroupPolicyFile, policy)
+}
@@ -17,7 +17,13 @@ limitations under the License.
 package basemgr
 
 import (
+	"context"
+	"fmt"
+	"os/exec"
 	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
@@ -26,9 +32,24 @@ import (
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ipmi"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsscsi"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ndctl"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/numa"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/nvmecli"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ses"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/smartctl"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/zoned"
+	metricsC "github.com/dell/csi-baremetal/pkg/metrics/common"
+)
+
+const (
+	// DefaultProbeWorkers is an amount of drives which are probed (SMART, enclosure location, NUMA node) in parallel
+	DefaultProbeWorkers = 8
+	// DefaultDiscoveryTimeout limits the whole discovery, drives list isn't returned if it isn't completed in time
+	DefaultDiscoveryTimeout = 2 * time.Minute
+	// DefaultRescanInterval is a period after which drive is probed again even if its state in sysfs isn't changed
+	DefaultRescanInterval = 5 * time.Minute
 )
 
 // BaseManager is a drive manager based on Linux system utils
@@ -38,23 +59,60 @@ type BaseManager struct {
 	lsscsi   lsscsi.WrapLsscsi
 	smartctl smartctl.WrapSmartctl
 	nvme     nvmecli.WrapNvmecli
+	ses      ses.WrapSES
+	numa     numa.WrapNUMA
+	zoned    zoned.WrapZoned
+	// PMEM discovery is disabled if ndctl isn't available
+	ndctl ndctl.WrapNdctl
+	// correlation of IPMI SEL records with drives is disabled if it is nil
+	ipmi ipmi.WrapIpmi
+	// vendor tool for flashing drive firmware, firmware update is disabled if it is empty
+	firmwareTool string
+	// amount of drives which are probed in parallel, drives are probed one by one if it is less than 2
+	probeWorkers int
+	// timeout of the whole discovery, discovery isn't limited if it is 0
+	discoveryTimeout time.Duration
+	// results of SCSI drives probing, drives which state isn't changed aren't probed again
+	// NVMe drives aren't cached since their health, temperature and endurance are taken from nvme list on each discovery
+	scsiCache *probeCache
 }
 
 // GetDrivesList gets api.Drive slice using Linux system utils
+// Returns error if discovery isn't completed in time, partial list isn't returned since node treats
+// drives absent in the list as removed
 func (mgr BaseManager) GetDrivesList() ([]*api.Drive, error) {
 	ll := mgr.log.WithField("method", "GetDrivesList")
+	defer metricsC.DriveDiscoveryDuration.EvaluateDurationForType("all")()
+
+	ctx := context.Background()
+	if mgr.discoveryTimeout > 0 {
+		var cancelFn context.CancelFunc
+		ctx, cancelFn = context.WithTimeout(ctx, mgr.discoveryTimeout)
+		defer cancelFn()
+	}
 	var (
-		devices    []*api.Drive
-		nvmDevices []*api.Drive
-		err        error
+		devices     []*api.Drive
+		nvmDevices  []*api.Drive
+		pmemDevices []*api.Drive
+		err         error
 	)
-	if devices, err = mgr.GetSCSIDevices(); err != nil {
+	if devices, err = mgr.GetSCSIDevices(ctx); err != nil {
 		ll.Errorf("Failed to initialize devices, Error: %v", err)
 	}
-	if nvmDevices, err = mgr.GetNVMDevices(); err != nil {
+	if ctx.Err() == nil {
+		if nvmDevices, err = mgr.GetNVMDevices(ctx); err != nil {
+			ll.Errorf("Failed to initialize devices, Error: %v", err)
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, status.Errorf(codes.DeadlineExceeded, "discovery isn't completed in %s", mgr.discoveryTimeout)
+	}
+	if pmemDevices, err = mgr.GetPMEMDevices(); err != nil {
 		ll.Errorf("Failed to initialize devices, Error: %v", err)
 	}
 	devices = append(devices, nvmDevices...)
+	mgr.fillSELEvents(devices)
+	devices = append(devices, pmemDevices...)
 	return devices, nil
 }
 
@@ -63,87 +121,416 @@ func (mgr *BaseManager) Locate(serialNumber string, action int32) (int32, error)
 	return -1, status.Error(codes.Unimplemented, "method Locate not implemented in BaseManager")
 }
 
+// UpdateFirmware implements UpdateFirmware method of DriveManager interface
+// Flashes drive with vendor firmware tool and returns firmware version reported by drive after that
+func (mgr *BaseManager) UpdateFirmware(serialNumber string, image string) (string, error) {
+	ll := mgr.log.WithField("method", "UpdateFirmware")
+	if mgr.firmwareTool == "" {
+		return "", status.Error(codes.Unimplemented, "firmware tool isn't configured for BaseManager")
+	}
+	drive, err := mgr.getDriveBySN(serialNumber)
+	if err != nil {
+		return "", err
+	}
+	ll.Infof("Updating firmware of drive %s (%s), current firmware %s, image %s",
+		serialNumber, drive.Path, drive.Firmware, image)
+	// vendor tool receives device path and image path, arguments are passed as is since image path could contain spaces
+	if _, _, err = mgr.exec.RunCmd(exec.Command(mgr.firmwareTool, drive.Path, image)); err != nil {
+		return "", err
+	}
+	// read firmware version after update
+	if drive, err = mgr.getDriveBySN(serialNumber); err != nil {
+		return "", err
+	}
+	return drive.Firmware, nil
+}
+
+// CreateNamespaces implements CreateNamespaces method of DriveManager interface
+// Replaces namespaces of NVMe controller which owns the drive with provided amount of namespaces of equal size
+// Returns serial numbers of drives which correspond to created namespaces
+func (mgr *BaseManager) CreateNamespaces(serialNumber string, count int32) ([]string, error) {
+	ll := mgr.log.WithField("method", "CreateNamespaces")
+	nvmeDevices, err := mgr.nvme.GetNVMDevices()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ctrlSN  = nvmecli.ControllerSerialNumber(serialNumber)
+		devices []nvmecli.NVMDevice
+		found   bool
+	)
+	for _, device := range nvmeDevices {
+		if device.SerialNumber != ctrlSN {
+			continue
+		}
+		devices = append(devices, device)
+	}
+	for _, device := range devices {
+		if driveSerialNumber(device, len(devices)) == serialNumber {
+			found = true
+		}
+	}
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "NVMe drive with serial number %s is not found", serialNumber)
+	}
+	ll.Infof("Creating %d namespaces on NVMe controller %s, %d namespaces are deleted", count, ctrlSN, len(devices))
+	namespaces, err := mgr.nvme.CreateNamespaces(devices, int(count))
+	if err != nil {
+		return nil, err
+	}
+	serialNumbers := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		serialNumbers = append(serialNumbers,
+			driveSerialNumber(nvmecli.NVMDevice{SerialNumber: ctrlSN, NameSpace: ns}, len(namespaces)))
+	}
+	return serialNumbers, nil
+}
+
+// SetFirmwareTool sets vendor tool which is used for flashing drive firmware
+// Tool is invoked as "<tool> <device path> <image path>"
+func (mgr *BaseManager) SetFirmwareTool(tool string) {
+	mgr.firmwareTool = tool
+}
+
+// SetDiscoveryLimits sets amount of drives which are probed in parallel and timeout of the whole discovery
+func (mgr *BaseManager) SetDiscoveryLimits(workers int, timeout time.Duration) {
+	mgr.probeWorkers = workers
+	mgr.discoveryTimeout = timeout
+}
+
+// SetRescanInterval sets period after which drive is probed again even if its state isn't changed,
+// drives are probed on each discovery if interval is 0
+func (mgr *BaseManager) SetRescanInterval(interval time.Duration) {
+	mgr.scsiCache = newProbeCache(interval)
+}
+
+// probeAll calls probe for indexes [0, n) using bounded amount of workers
+// Returns error if ctx is done before all indexes are probed, commands of the probes which are still running
+// are killed by ctx, so their workers finish shortly after that
+func (mgr *BaseManager) probeAll(ctx context.Context, n int, probe func(i int)) error {
+	workers := mgr.probeWorkers
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	var (
+		indexes  = make(chan int)
+		finished = make(chan struct{})
+		probed   int32
+		wg       sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				probe(i)
+				atomic.AddInt32(&probed, 1)
+			}
+		}()
+	}
+	go func() {
+		defer close(indexes)
+		for i := 0; i < n; i++ {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		if int(atomic.LoadInt32(&probed)) == n {
+			return nil
+		}
+		return ctx.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// getDriveBySN searches drive with provided serial number among the discovered drives
+func (mgr *BaseManager) getDriveBySN(serialNumber string) (*api.Drive, error) {
+	drives, err := mgr.GetDrivesList()
+	if err != nil {
+		return nil, err
+	}
+	for _, drive := range drives {
+		if drive.SerialNumber == serialNumber {
+			return drive, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "drive with serial number %s is not found", serialNumber)
+}
+
 // New is a constructor BaseManager
 func New(exec command.CmdExecutor, logger *logrus.Logger) *BaseManager {
 	return &BaseManager{
 		exec:     exec,
 		log:      logger.WithField("component", "BaseManager"),
-		lsscsi:   lsscsi.NewLSSCSI(exec, logger),
+		lsscsi:   newSCSIWrapper(exec, logger),
 		smartctl: smartctl.NewSMARTCTL(exec),
 		nvme:     nvmecli.NewNVMECLI(exec, logger),
+		ndctl:    newPMEMWrapper(exec, logger),
+		ses:      ses.NewSES(logger),
+		numa:     numa.NewNUMA(logger),
+		zoned:    zoned.NewZoned(exec),
+
+		probeWorkers:     DefaultProbeWorkers,
+		discoveryTimeout: DefaultDiscoveryTimeout,
+		scsiCache:        newProbeCache(DefaultRescanInterval),
+	}
+}
+
+// newPMEMWrapper returns ndctl wrapper or nil if ndctl isn't available, PMEM devices aren't discovered in this case
+func newPMEMWrapper(exec command.CmdExecutor, logger *logrus.Logger) ndctl.WrapNdctl {
+	if command.IsAvailable(ndctl.NdctlCmdImpl) {
+		return ndctl.NewNDCTL(exec)
 	}
+	logger.WithField("component", "BaseManager").
+		Infof("%s isn't found on this system, PMEM devices aren't discovered", ndctl.NdctlCmdImpl)
+	return nil
+}
+
+// newSCSIWrapper chooses implementation of SCSI discovery according to architecture and available system utils
+func newSCSIWrapper(exec command.CmdExecutor, logger *logrus.Logger) lsscsi.WrapLsscsi {
+	if preferLsscsi && command.IsAvailable(lsscsi.LsscsiCmd) {
+		return lsscsi.NewLSSCSI(exec, logger)
+	}
+	logger.WithField("component", "BaseManager").
+		Infof("%s isn't used on this system, SCSI devices are read from sysfs", lsscsi.LsscsiCmd)
+	return lsscsi.NewSysfsSCSI(logger)
 }
 
 // GetSCSIDevices get []*api.Drive using lsscsi system util
-func (mgr *BaseManager) GetSCSIDevices() ([]*api.Drive, error) {
+// Devices are probed in parallel, error is returned if ctx is done before all of them are probed
+func (mgr *BaseManager) GetSCSIDevices(ctx context.Context) ([]*api.Drive, error) {
 	ll := mgr.log.WithField("method", "GetSCSIDevices")
-	allDevices := make([]*api.Drive, 0)
+	defer metricsC.DriveDiscoveryDuration.EvaluateDurationForType("scsi")()
+
 	scsiDevices, err := mgr.lsscsi.GetSCSIDevices()
 	if err != nil {
 		ll.Errorf("Failed to get SCSI allDevices, Error: %v", err)
 		return nil, err
 	}
-	for _, device := range scsiDevices {
-		allDevices = append(allDevices, &api.Drive{
-			Path:     device.Path,
-			Firmware: device.Firmware,
-			VID:      device.Vendor,
-			PID:      device.Model,
-			Size:     device.Size,
+	var (
+		drives  = make([]*api.Drive, len(scsiDevices))
+		reasons = make([]string, len(scsiDevices))
+	)
+	if err = mgr.probeAll(ctx, len(scsiDevices), func(i int) {
+		drives[i], reasons[i] = mgr.scsiCache.probe(scsiDevices[i].Path, func() (*api.Drive, string) {
+			return mgr.scsiDrive(ctx, scsiDevices[i])
 		})
+	}); err != nil {
+		ll.Errorf("Failed to probe %d SCSI devices, Error: %v", len(scsiDevices), err)
+		return nil, err
 	}
+	paths := make([]string, 0, len(scsiDevices))
+	for _, device := range scsiDevices {
+		paths = append(paths, device.Path)
+	}
+	mgr.scsiCache.retain(paths)
+
 	devices := make([]*api.Drive, 0)
-	for i, device := range allDevices {
-		smartInfo, err := mgr.smartctl.GetDriveInfoByPath(device.Path)
-		if err != nil {
-			// We don't fail whole drivemgr because of error with just one device, we don't add it in allDevices slice
-			ll.Errorf("Failed to get SMART information for Device %v, Error: %v", allDevices[i], err)
-		} else {
-			allDevices[i].SerialNumber = smartInfo.SerialNumber
-			if allDevices[i].SerialNumber != "" && allDevices[i].VID != "" && allDevices[i].PID != "" {
-				if smartInfo.Rotation > 0 {
-					allDevices[i].Type = apiV1.DriveTypeHDD
-				} else {
-					allDevices[i].Type = apiV1.DriveTypeSSD
-				}
-				if smartInfo.SmartStatus["passed"] {
-					allDevices[i].Health = apiV1.HealthGood
-				} else {
-					allDevices[i].Health = apiV1.HealthBad
-				}
-				devices = append(devices, allDevices[i])
-			} else {
-				ll.Errorf("Device has empty VID, PID or SN field: %v", allDevices[i])
-			}
+	for i, drive := range drives {
+		if reasons[i] != "" {
+			// We don't fail whole drivemgr because of error with just one device, we don't add it in devices slice
+			ll.Errorf("Device %v is skipped: %s", drive, reasons[i])
+			continue
 		}
+		devices = append(devices, drive)
 	}
 	return devices, nil
 }
 
+// scsiDrive converts SCSI device to api.Drive and fills it with SMART information, location and NUMA node
+// Returns drive and reason why it should be excluded from discovery or empty string
+func (mgr *BaseManager) scsiDrive(ctx context.Context, device *lsscsi.SCSIDevice) (*api.Drive, string) {
+	drive := &api.Drive{
+		Path:     device.Path,
+		Firmware: device.Firmware,
+		VID:      device.Vendor,
+		PID:      device.Model,
+		Size:     device.Size,
+	}
+	smartInfo, err := mgr.smartctl.GetDriveInfoByPath(ctx, drive.Path)
+	if err != nil {
+		return drive, fmt.Sprintf("failed to get SMART information: %v", err)
+	}
+	drive.SerialNumber = smartInfo.SerialNumber
+	drive.WWN = smartInfo.WWN.String()
+	drive.Temperature = smartInfo.CurrentTemperature()
+	drive.Endurance = smartInfo.Endurance()
+	if drive.SerialNumber == "" || drive.VID == "" || drive.PID == "" {
+		return drive, "device has empty VID, PID or SN field"
+	}
+	if smartInfo.Rotation > 0 {
+		drive.Type = apiV1.DriveTypeHDD
+	} else {
+		drive.Type = apiV1.DriveTypeSSD
+	}
+	if smartInfo.SmartStatus["passed"] {
+		drive.Health = apiV1.HealthGood
+	} else {
+		drive.Health = apiV1.HealthBad
+	}
+	mgr.fillDriveLocation(drive)
+	mgr.fillNUMANode(drive)
+	mgr.fillZonedType(drive)
+	return drive, ""
+}
+
+// fillDriveLocation fills enclosure ID, slot and backplane of the drive if it is placed in SCSI enclosure
+func (mgr *BaseManager) fillDriveLocation(drive *api.Drive) {
+	location, err := mgr.ses.GetDriveLocation(drive.Path)
+	if err != nil {
+		// location is optional, drive is reported without it
+		mgr.log.WithField("method", "fillDriveLocation").
+			Warnf("Failed to get enclosure location for device %s, Error: %v", drive.Path, err)
+		return
+	}
+	if location != nil {
+		drive.Enclosure = location.EnclosureID
+		drive.Slot = location.Slot
+		drive.Backplane = location.Backplane
+	}
+}
+
+// fillNUMANode fills NUMA node of the drive's PCIe/HBA path
+func (mgr *BaseManager) fillNUMANode(drive *api.Drive) {
+	node, err := mgr.numa.GetDeviceNUMANode(drive.Path)
+	if err != nil {
+		// NUMA node is optional, drive is reported without it
+		mgr.log.WithField("method", "fillNUMANode").
+			Warnf("Failed to get NUMA node for device %s, Error: %v", drive.Path, err)
+		return
+	}
+	drive.NUMANode = node
+}
+
+// fillZonedType marks host-managed SMR and ZNS drives with zoned type, since they require sequential writes
+// and can't be allocated as regular drives
+func (mgr *BaseManager) fillZonedType(drive *api.Drive) {
+	model, err := mgr.zoned.GetZonedModel(drive.Path)
+	if err != nil {
+		mgr.log.WithField("method", "fillZonedType").
+			Warnf("Failed to get zoned model for device %s, Error: %v", drive.Path, err)
+		return
+	}
+	if model == zoned.ModelHostManaged {
+		drive.Type = apiV1.DriveTypeZoned
+	}
+}
+
 // GetNVMDevices get []*api.Drive using nvme_cli system util
-func (mgr *BaseManager) GetNVMDevices() ([]*api.Drive, error) {
+// Devices are probed in parallel, error is returned if ctx is done before all of them are probed
+func (mgr *BaseManager) GetNVMDevices(ctx context.Context) ([]*api.Drive, error) {
 	ll := mgr.log.WithField("method", "GetNVMDevices")
+	defer metricsC.DriveDiscoveryDuration.EvaluateDurationForType("nvme")()
+
 	devices := make([]*api.Drive, 0)
 	nvmeDevices, err := mgr.nvme.GetNVMDevices()
 	if err != nil {
 		ll.Errorf("Failed to get NVMe devices, Error: %v", err)
 		return nil, err
 	}
+	// namespaces of the same controller share serial number
+	namespaces := make(map[string]int)
 	for _, device := range nvmeDevices {
-		if device.Vendor != 0 && device.ModelNumber != "" && device.SerialNumber != "" {
-			devices = append(devices, &api.Drive{
-				Health:       device.Health,
-				PID:          device.ModelNumber,
-				VID:          strconv.Itoa(device.Vendor),
-				SerialNumber: device.SerialNumber,
-				Type:         apiV1.DriveTypeNVMe,
-				Size:         device.PhysicalSize,
-				Firmware:     device.Firmware,
-				Path:         device.DevicePath,
-			})
-		} else {
-			ll.Errorf("Device has empty VID, PID or SN field: %v", device)
+		namespaces[device.SerialNumber]++
+	}
+	var (
+		drives  = make([]*api.Drive, len(nvmeDevices))
+		reasons = make([]string, len(nvmeDevices))
+	)
+	if err = mgr.probeAll(ctx, len(nvmeDevices), func(i int) {
+		drives[i], reasons[i] = mgr.nvmeDrive(nvmeDevices[i])
+	}); err != nil {
+		ll.Errorf("Failed to probe %d NVMe devices, Error: %v", len(nvmeDevices), err)
+		return nil, err
+	}
+	for i, device := range nvmeDevices {
+		drive := drives[i]
+		if drive != nil {
+			drive.SerialNumber = driveSerialNumber(device, namespaces[device.SerialNumber])
+		}
+		if reasons[i] != "" {
+			ll.Errorf("Device %v is skipped: %s", device, reasons[i])
+			continue
 		}
+		devices = append(devices, drive)
+	}
+	return devices, nil
+}
+
+// driveSerialNumber returns serial number of drive for NVMe device
+// Each namespace is a separate drive if controller has several namespaces
+func driveSerialNumber(device nvmecli.NVMDevice, namespaces int) string {
+	if namespaces > 1 {
+		return nvmecli.NamespaceSerialNumber(device.SerialNumber, device.NameSpace)
+	}
+	return device.SerialNumber
+}
+
+// nvmeDrive converts NVMe device to api.Drive
+// Returns drive and reason why it should be excluded from discovery or empty string
+func (mgr *BaseManager) nvmeDrive(device nvmecli.NVMDevice) (*api.Drive, string) {
+	if device.Vendor == 0 || device.ModelNumber == "" || device.SerialNumber == "" {
+		return nil, "device has empty VID, PID or SN field"
+	}
+	drive := &api.Drive{
+		Health:       device.Health,
+		PID:          device.ModelNumber,
+		VID:          strconv.Itoa(device.Vendor),
+		SerialNumber: device.SerialNumber,
+		Type:         apiV1.DriveTypeNVMe,
+		Size:         device.PhysicalSize,
+		Firmware:     device.Firmware,
+		Path:         device.DevicePath,
+		Temperature:  device.Temperature,
+	}
+	if device.PercentageUsed != nil {
+		drive.Endurance = smartctl.EnduranceFromPercentageUsed(*device.PercentageUsed)
+	}
+	mgr.fillNUMANode(drive)
+	mgr.fillZonedType(drive)
+	return drive, ""
+}
+
+// GetPMEMDevices get []*api.Drive for PMEM namespaces in fsdax mode using ndctl system util
+func (mgr *BaseManager) GetPMEMDevices() ([]*api.Drive, error) {
+	devices := make([]*api.Drive, 0)
+	if mgr.ndctl == nil {
+		return devices, nil
+	}
+	pmemDevices, err := mgr.ndctl.GetPMEMDevices()
+	if err != nil {
+		mgr.log.WithField("method", "GetPMEMDevices").Errorf("Failed to get PMEM devices, Error: %v", err)
+		return nil, err
+	}
+	for _, device := range pmemDevices {
+		devices = append(devices, mgr.pmemDrive(device))
 	}
 	return devices, nil
 }
+
+// pmemDrive converts PMEM namespace to api.Drive, UUID of the namespace is used as serial number
+func (mgr *BaseManager) pmemDrive(device ndctl.PMEMDevice) *api.Drive {
+	drive := &api.Drive{
+		Health:       apiV1.HealthGood,
+		PID:          device.Dev,
+		SerialNumber: device.UUID,
+		Type:         apiV1.DriveTypePMEM,
+		Size:         device.Size,
+		Path:         device.Path(),
+	}
+	mgr.fillNUMANode(drive)
+	return drive
+}
@@ -17,17 +17,24 @@ limitations under the License.
 package basemgr
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsscsi"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ndctl"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/nvmecli"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ses"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/smartctl"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/zoned"
 	"github.com/dell/csi-baremetal/pkg/mocks"
 	"github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
 )
@@ -39,6 +46,7 @@ func TestLoopBackManager_GetNVMDevicesSuccess(t *testing.T) {
 		mockexec = &mocks.GoMockExecutor{}
 		manager  = New(mockexec, logger)
 		mockNvme = &linuxutils.MockWrapNvmecli{}
+		mockNUMA = &linuxutils.MockWrapNUMA{}
 	)
 	nvmeDevice := make([]nvmecli.NVMDevice, 0)
 	nvmeDevice = append(nvmeDevice, nvmecli.NVMDevice{
@@ -49,15 +57,22 @@ func TestLoopBackManager_GetNVMDevicesSuccess(t *testing.T) {
 		Vendor:       2311,
 		PhysicalSize: 1000,
 		Health:       apiV1.HealthGood,
+		Temperature:  41,
 	})
+	var percentageUsed int64 = 7
+	nvmeDevice[0].PercentageUsed = &percentageUsed
 	mockNvme.On("GetNVMDevices", mock.Anything).
 		Return(nvmeDevice, nil).Once()
 
+	mockNUMA.On("GetDeviceNUMANode", "testPath").Return("1", nil)
+
 	manager.nvme = mockNvme
-	devices, err := manager.GetNVMDevices()
+	manager.numa = mockNUMA
+	devices, err := manager.GetNVMDevices(context.Background())
 
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(devices))
+	assert.Equal(t, "1", devices[0].NUMANode)
 	assert.Equal(t, "testPath", devices[0].Path)
 	assert.Equal(t, "testFirmware", devices[0].Firmware)
 	assert.Equal(t, "testModel", devices[0].PID)
@@ -65,7 +80,20 @@ func TestLoopBackManager_GetNVMDevicesSuccess(t *testing.T) {
 	assert.Equal(t, int64(1000), devices[0].Size)
 	assert.Equal(t, apiV1.HealthGood, devices[0].Health)
 	assert.Equal(t, apiV1.DriveTypeNVMe, devices[0].Type)
+	assert.Equal(t, int32(41), devices[0].Temperature)
+	assert.Equal(t, int64(93), devices[0].Endurance)
 	assert.Equal(t, "2311", devices[0].VID)
+
+	// NVMe drives aren't cached, health and temperature of the next nvme list are reported
+	nvmeDevice[0].Health, nvmeDevice[0].Temperature, nvmeDevice[0].PercentageUsed = apiV1.HealthBad, 75, nil
+	mockNvme.On("GetNVMDevices", mock.Anything).Return(nvmeDevice, nil).Once()
+	devices, err = manager.GetNVMDevices(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(devices))
+	assert.Equal(t, apiV1.HealthBad, devices[0].Health)
+	assert.Equal(t, int32(75), devices[0].Temperature)
+	assert.Equal(t, int64(0), devices[0].Endurance)
 }
 
 func TestLoopBackManager_GetNVMDevicesEmptyVidPidSn(t *testing.T) {
@@ -88,7 +116,7 @@ func TestLoopBackManager_GetNVMDevicesEmptyVidPidSn(t *testing.T) {
 		Return(nvmeDevice, nil).Once()
 
 	manager.nvme = mockNvme
-	devices, err := manager.GetNVMDevices()
+	devices, err := manager.GetNVMDevices(context.Background())
 
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(devices))
@@ -104,7 +132,7 @@ func TestLoopBackManager_GetNVMDevicesFail(t *testing.T) {
 		Return([]nvmecli.NVMDevice{}, fmt.Errorf("error")).Once()
 
 	manager.nvme = mockNvme
-	_, err := manager.GetNVMDevices()
+	_, err := manager.GetNVMDevices(context.Background())
 
 	assert.NotNil(t, err)
 }
@@ -115,13 +143,17 @@ func TestLoopBackManager_GetSCSIDevices(t *testing.T) {
 		manager      = New(mockexec, logger)
 		mockLsscsi   = &linuxutils.MockWrapLsscsi{}
 		mockSmartctl = &linuxutils.MockWrapSmartctl{}
+		mockSES      = &linuxutils.MockWrapSES{}
 	)
 
 	smart := &smartctl.DeviceSMARTInfo{
 		SerialNumber: "testSN",
 		SmartStatus:  make(map[string]bool),
 		Rotation:     0,
+		Temperature:  &smartctl.DeviceTemp{Current: 35},
 	}
+	var percentageUsed int64 = 40
+	smart.PercentageUsed = &percentageUsed
 	smart.SmartStatus["passed"] = true
 	scsiDevice := make([]*lsscsi.SCSIDevice, 0)
 	scsiDevice = append(scsiDevice, &lsscsi.SCSIDevice{
@@ -135,16 +167,25 @@ func TestLoopBackManager_GetSCSIDevices(t *testing.T) {
 	mockLsscsi.On("GetSCSIDevices", mock.Anything).
 		Return(scsiDevice, nil)
 
-	mockSmartctl.On("GetDriveInfoByPath", "testPath").
+	mockSmartctl.On("GetDriveInfoByPath", mock.Anything, "testPath").
 		Return(smart, nil)
 
+	mockSES.On("GetDriveLocation", "testPath").
+		Return(&ses.DriveLocation{EnclosureID: "testEnclosure", Slot: "1", Backplane: "/dev/sg5"}, nil).Once()
+	mockSES.On("GetDriveLocation", "testPath").
+		Return((*ses.DriveLocation)(nil), fmt.Errorf("error"))
+
 	manager.lsscsi = mockLsscsi
 	manager.smartctl = mockSmartctl
+	manager.ses = mockSES
 
-	devices, err := manager.GetSCSIDevices()
+	devices, err := manager.GetSCSIDevices(context.Background())
 
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(devices))
+	assert.Equal(t, "testEnclosure", devices[0].Enclosure)
+	assert.Equal(t, "1", devices[0].Slot)
+	assert.Equal(t, "/dev/sg5", devices[0].Backplane)
 	assert.Equal(t, "testVendor", devices[0].VID)
 	assert.Equal(t, "testPath", devices[0].Path)
 	assert.Equal(t, "testFirmware", devices[0].Firmware)
@@ -153,10 +194,12 @@ func TestLoopBackManager_GetSCSIDevices(t *testing.T) {
 	assert.Equal(t, int64(1000), devices[0].Size)
 	assert.Equal(t, apiV1.HealthGood, devices[0].Health)
 	assert.Equal(t, apiV1.DriveTypeSSD, devices[0].Type)
+	assert.Equal(t, int32(35), devices[0].Temperature)
+	assert.Equal(t, int64(60), devices[0].Endurance)
 
 	smart.SmartStatus["passed"] = false
 	smart.Rotation = 7200
-	devices, err = manager.GetSCSIDevices()
+	devices, err = manager.GetSCSIDevices(context.Background())
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(devices))
 	assert.Equal(t, apiV1.HealthBad, devices[0].Health)
@@ -189,13 +232,13 @@ func TestLoopBackManager_GetSCSIDevicesEmptyVidPidSn(t *testing.T) {
 	mockLsscsi.On("GetSCSIDevices", mock.Anything).
 		Return(scsiDevice, nil)
 
-	mockSmartctl.On("GetDriveInfoByPath", "testPath").
+	mockSmartctl.On("GetDriveInfoByPath", mock.Anything, "testPath").
 		Return(smart, nil)
 
 	manager.lsscsi = mockLsscsi
 	manager.smartctl = mockSmartctl
 
-	devices, err := manager.GetSCSIDevices()
+	devices, err := manager.GetSCSIDevices(context.Background())
 
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(devices))
@@ -219,13 +262,13 @@ func TestLoopBackManager_GetSCSIDevicesFail(t *testing.T) {
 	mockLsscsi.On("GetSCSIDevices", mock.Anything).
 		Return(scsiDevice, nil)
 
-	mockSmartctl.On("GetDriveInfoByPath", "testPath").
+	mockSmartctl.On("GetDriveInfoByPath", mock.Anything, "testPath").
 		Return(&smartctl.DeviceSMARTInfo{}, fmt.Errorf("error"))
 
 	manager.smartctl = mockSmartctl
 	manager.lsscsi = mockLsscsi
 
-	devs, err := manager.GetSCSIDevices()
+	devs, err := manager.GetSCSIDevices(context.Background())
 
 	assert.Nil(t, err)
 	assert.Equal(t, len(devs), 0)
@@ -241,7 +284,7 @@ func TestLoopBackManager_GetSCSIDevicesLsscsiFail(t *testing.T) {
 		Return([]*lsscsi.SCSIDevice{}, fmt.Errorf("error"))
 	manager.lsscsi = mockLsscsi
 
-	_, err := manager.GetSCSIDevices()
+	_, err := manager.GetSCSIDevices(context.Background())
 
 	assert.NotNil(t, err)
 }
@@ -260,6 +303,7 @@ func TestLoopBackManager_GetDrivesListFail(t *testing.T) {
 
 	manager.lsscsi = mockLsscsi
 	manager.nvme = mockNvme
+	manager.ndctl = nil
 
 	_, err := manager.GetDrivesList()
 
@@ -281,6 +325,7 @@ func TestLoopBackManager_GetDrivesListLsscsiFail(t *testing.T) {
 	mockNvme.On("GetNVMDevices", mock.Anything).
 		Return([]nvmecli.NVMDevice{}, nil)
 	manager.nvme = mockNvme
+	manager.ndctl = nil
 
 	_, err := manager.GetDrivesList()
 
@@ -293,16 +338,231 @@ func TestLoopBackManager_GetDrivesListSuccess(t *testing.T) {
 		manager    = New(mockexec, logger)
 		mockLsscsi = &linuxutils.MockWrapLsscsi{}
 		mockNvme   = &linuxutils.MockWrapNvmecli{}
+		mockNdctl  = &linuxutils.MockWrapNdctl{}
+		mockNUMA   = &linuxutils.MockWrapNUMA{}
 	)
 	mockNvme.On("GetNVMDevices", mock.Anything).
 		Return([]nvmecli.NVMDevice{}, nil)
 
 	mockLsscsi.On("GetSCSIDevices", mock.Anything).
 		Return([]*lsscsi.SCSIDevice{}, nil)
+	mockNdctl.On("GetPMEMDevices").
+		Return([]ndctl.PMEMDevice{{Dev: "namespace0.0", Mode: ndctl.ModeFSDAX, Size: 1024,
+			UUID: "pmem-uuid", BlockDev: "pmem0"}}, nil)
+	mockNUMA.On("GetDeviceNUMANode", "/dev/pmem0").Return("0", nil)
 	manager.lsscsi = mockLsscsi
 	manager.nvme = mockNvme
+	manager.ndctl = mockNdctl
+	manager.numa = mockNUMA
 
-	_, err := manager.GetDrivesList()
+	drives, err := manager.GetDrivesList()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(drives))
+	assert.Equal(t, apiV1.DriveTypePMEM, drives[0].Type)
+	assert.Equal(t, "pmem-uuid", drives[0].SerialNumber)
+	assert.Equal(t, "/dev/pmem0", drives[0].Path)
+	assert.Equal(t, int64(1024), drives[0].Size)
+}
+
+func TestBaseManager_GetSCSIDevicesParallel(t *testing.T) {
+	var (
+		mockexec     = &mocks.GoMockExecutor{}
+		manager      = New(mockexec, logger)
+		mockLsscsi   = &linuxutils.MockWrapLsscsi{}
+		mockSmartctl = &linuxutils.MockWrapSmartctl{}
+		mockSES      = &linuxutils.MockWrapSES{}
+		scsiDevices  []*lsscsi.SCSIDevice
+		probeTime    = 100 * time.Millisecond
+	)
+	for i := 0; i < 10; i++ {
+		path := fmt.Sprintf("/dev/sd%c", 'a'+i)
+		scsiDevices = append(scsiDevices, &lsscsi.SCSIDevice{Path: path, Vendor: "testVendor", Model: "testModel"})
+		mockSmartctl.On("GetDriveInfoByPath", mock.Anything, path).After(probeTime).
+			Return(&smartctl.DeviceSMARTInfo{SerialNumber: "sn-" + path, SmartStatus: map[string]bool{"passed": true}}, nil)
+	}
+	mockLsscsi.On("GetSCSIDevices", mock.Anything).Return(scsiDevices, nil)
+	mockSES.On("GetDriveLocation", mock.Anything).Return((*ses.DriveLocation)(nil), nil)
+	manager.lsscsi = mockLsscsi
+	manager.smartctl = mockSmartctl
+	manager.ses = mockSES
+	manager.SetDiscoveryLimits(5, time.Minute)
+
+	start := time.Now()
+	devices, err := manager.GetSCSIDevices(context.Background())
+
+	assert.Nil(t, err)
+	assert.Less(t, int64(time.Since(start)), int64(len(scsiDevices))*int64(probeTime))
+	assert.Equal(t, len(scsiDevices), len(devices))
+	// order of devices is kept
+	for i, device := range devices {
+		assert.Equal(t, scsiDevices[i].Path, device.Path)
+		assert.Equal(t, "sn-"+scsiDevices[i].Path, device.SerialNumber)
+	}
+}
+
+func TestBaseManager_GetDrivesListTimeout(t *testing.T) {
+	var (
+		mockexec     = &mocks.GoMockExecutor{}
+		manager      = New(mockexec, logger)
+		mockLsscsi   = &linuxutils.MockWrapLsscsi{}
+		mockSmartctl = &linuxutils.MockWrapSmartctl{}
+		mockNvme     = &linuxutils.MockWrapNvmecli{}
+		interrupted  = make(chan struct{})
+	)
+	mockLsscsi.On("GetSCSIDevices", mock.Anything).Return([]*lsscsi.SCSIDevice{
+		{Path: "/dev/sda", Vendor: "testVendor", Model: "testModel"},
+		{Path: "/dev/sdb", Vendor: "testVendor", Model: "testModel"},
+	}, nil)
+	// hung drive, smartctl is killed once discovery ctx is done
+	mockSmartctl.On("GetDriveInfoByPath", mock.Anything, "/dev/sda").Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+		close(interrupted)
+	}).Return(&smartctl.DeviceSMARTInfo{}, fmt.Errorf("killed"))
+	mockNvme.On("GetNVMDevices", mock.Anything).Return([]nvmecli.NVMDevice{}, nil)
+	manager.lsscsi = mockLsscsi
+	manager.smartctl = mockSmartctl
+	manager.nvme = mockNvme
+	manager.ndctl = nil
+	manager.SetDiscoveryLimits(1, 50*time.Millisecond)
+
+	drives, err := manager.GetDrivesList()
+
+	assert.Nil(t, drives)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	select {
+	case <-interrupted:
+	case <-time.After(time.Second):
+		t.Error("probe of hung drive isn't interrupted")
+	}
+}
+
+func TestBaseManager_UpdateFirmware(t *testing.T) {
+	var (
+		mockexec   = &mocks.GoMockExecutor{}
+		manager    = New(mockexec, logger)
+		mockLsscsi = &linuxutils.MockWrapLsscsi{}
+		mockNvme   = &linuxutils.MockWrapNvmecli{}
+		device     = nvmecli.NVMDevice{
+			DevicePath:   "/dev/nvme0n1",
+			Firmware:     "oldFirmware",
+			ModelNumber:  "testModel",
+			SerialNumber: "testSN",
+			Vendor:       2311,
+		}
+		image      = "/tmp/firmware images/image.bin"
+		updateArgs = []string{"fwtool", device.DevicePath, image}
+	)
+	manager.lsscsi = mockLsscsi
+	manager.nvme = mockNvme
+	manager.ndctl = nil
+	mockLsscsi.On("GetSCSIDevices", mock.Anything).
+		Return([]*lsscsi.SCSIDevice{}, nil)
+
+	// firmware tool isn't configured
+	_, err := manager.UpdateFirmware("testSN", image)
+	assert.NotNil(t, err)
+
+	manager.SetFirmwareTool("fwtool")
+
+	// drive isn't found
+	mockNvme.On("GetNVMDevices", mock.Anything).
+		Return([]nvmecli.NVMDevice{device}, nil).Once()
+	_, err = manager.UpdateFirmware("anotherSN", image)
+	assert.NotNil(t, err)
+
+	// vendor tool failed
+	mockNvme.On("GetNVMDevices", mock.Anything).
+		Return([]nvmecli.NVMDevice{device}, nil).Once()
+	mockexec.OnCommandArgs(updateArgs...).Return("", "", fmt.Errorf("error")).Once()
+	_, err = manager.UpdateFirmware("testSN", image)
+	assert.NotNil(t, err)
+
+	// success
+	updated := device
+	updated.Firmware = "newFirmware"
+	mockNvme.On("GetNVMDevices", mock.Anything).
+		Return([]nvmecli.NVMDevice{device}, nil).Once()
+	mockNvme.On("GetNVMDevices", mock.Anything).
+		Return([]nvmecli.NVMDevice{updated}, nil).Once()
+	mockexec.OnCommandArgs(updateArgs...).Return("", "", nil).Once()
+	firmware, err := manager.UpdateFirmware("testSN", image)
+	assert.Nil(t, err)
+	assert.Equal(t, "newFirmware", firmware)
+}
+
+func TestBaseManager_CreateNamespaces(t *testing.T) {
+	var (
+		mockexec   = &mocks.GoMockExecutor{}
+		manager    = New(mockexec, logger)
+		mockLsscsi = &linuxutils.MockWrapLsscsi{}
+		mockNvme   = &linuxutils.MockWrapNvmecli{}
+		device     = nvmecli.NVMDevice{
+			DevicePath:   "/dev/nvme0n1",
+			ModelNumber:  "testModel",
+			SerialNumber: "testSN",
+			Vendor:       2311,
+			NameSpace:    1,
+		}
+	)
+	manager.lsscsi = mockLsscsi
+	manager.nvme = mockNvme
+	mockLsscsi.On("GetSCSIDevices", mock.Anything).
+		Return([]*lsscsi.SCSIDevice{}, nil)
+	mockNvme.On("GetNVMDevices", mock.Anything).
+		Return([]nvmecli.NVMDevice{device}, nil).Once()
+
+	// drive isn't found
+	_, err := manager.CreateNamespaces("anotherSN", 2)
+	assert.NotNil(t, err)
+
+	// success
+	mockNvme.On("GetNVMDevices", mock.Anything).
+		Return([]nvmecli.NVMDevice{device}, nil).Once()
+	mockNvme.On("CreateNamespaces", []nvmecli.NVMDevice{device}, 2).
+		Return([]int{1, 2}, nil).Once()
+	serialNumbers, err := manager.CreateNamespaces("testSN", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"testSN-ns1", "testSN-ns2"}, serialNumbers)
+
+	// each namespace is discovered as separate drive
+	second := device
+	second.DevicePath = "/dev/nvme0n2"
+	second.NameSpace = 2
+	mockNvme.On("GetNVMDevices", mock.Anything).
+		Return([]nvmecli.NVMDevice{device, second}, nil).Once()
+	drives, err := manager.GetNVMDevices(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(drives))
+	assert.Equal(t, "testSN-ns1", drives[0].SerialNumber)
+	assert.Equal(t, "testSN-ns2", drives[1].SerialNumber)
+}
+
+func TestBaseManager_ZonedDrive(t *testing.T) {
+	var (
+		manager   = New(&mocks.GoMockExecutor{}, logger)
+		mockNvme  = &linuxutils.MockWrapNvmecli{}
+		mockZoned = &linuxutils.MockWrapZoned{}
+		device    = nvmecli.NVMDevice{
+			DevicePath:   "/dev/nvme0n1",
+			ModelNumber:  "testModel",
+			SerialNumber: "testSN",
+			Vendor:       2311,
+		}
+	)
+	manager.nvme = mockNvme
+	manager.zoned = mockZoned
+	mockNvme.On("GetNVMDevices", mock.Anything).
+		Return([]nvmecli.NVMDevice{device}, nil)
+
+	mockZoned.On("GetZonedModel", device.DevicePath).Return(zoned.ModelHostManaged, nil).Once()
+	drives, err := manager.GetNVMDevices(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, apiV1.DriveTypeZoned, drives[0].Type)
 
+	// host-aware drive is used as regular drive
+	mockZoned.On("GetZonedModel", device.DevicePath).Return(zoned.ModelHostAware, nil).Once()
+	drives, err = manager.GetNVMDevices(context.Background())
 	assert.Nil(t, err)
+	assert.Equal(t, apiV1.DriveTypeNVMe, drives[0].Type)
 }
@@ -0,0 +1,88 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package basemgr
+
+import (
+	"strconv"
+	"strings"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// Keys of SMART attributes which are reported in drive health details
+const (
+	smartAttrTemperature = "temperature"
+	smartAttrEndurance   = "endurance"
+)
+
+// GetDrivesDetails implements DriveDetailsManager interface, it is served by v2 API of drive manager
+// Returns drives with health details, slot info and temperature or error if discovery failed
+func (mgr *BaseManager) GetDrivesDetails() ([]*api.DriveDetails, error) {
+	drives, err := mgr.GetDrivesList()
+	if err != nil {
+		return nil, err
+	}
+	details := make([]*api.DriveDetails, 0, len(drives))
+	for _, drive := range drives {
+		details = append(details, &api.DriveDetails{
+			Drive:  drive,
+			Health: driveHealthDetails(drive),
+			Slot: &api.DriveSlotInfo{
+				Enclosure: drive.Enclosure,
+				Slot:      drive.Slot,
+				Bay:       drive.Bay,
+				Backplane: drive.Backplane,
+			},
+			Temperature: drive.Temperature,
+		})
+	}
+	return details, nil
+}
+
+// driveHealthDetails returns reason of the drive health and SMART attributes which were read during discovery
+func driveHealthDetails(drive *api.Drive) *api.DriveHealthDetails {
+	details := &api.DriveHealthDetails{
+		Reason:          healthReason(drive),
+		SmartAttributes: map[string]string{},
+	}
+	if drive.Temperature > 0 {
+		details.SmartAttributes[smartAttrTemperature] = strconv.Itoa(int(drive.Temperature))
+	}
+	if drive.Endurance > 0 {
+		details.SmartAttributes[smartAttrEndurance] = strconv.FormatInt(drive.Endurance, 10)
+	}
+	return details
+}
+
+// healthReason returns reason of the drive health which isn't GOOD, empty string is returned for GOOD drive
+// Health of NVMe drives is based on critical warning of SMART log, health of SCSI drives on SMART self-assessment
+func healthReason(drive *api.Drive) string {
+	isNVMe := drive.Type == apiV1.DriveTypeNVMe || strings.HasPrefix(drive.Path, "/dev/nvme")
+	switch {
+	case drive.Health == apiV1.HealthGood:
+		return ""
+	case drive.Health == apiV1.HealthUnknown:
+		return "SMART information isn't available"
+	case isNVMe && drive.Health == apiV1.HealthSuspect:
+		return "NVMe critical warning: available spare is below threshold or volatile memory backup failed"
+	case isNVMe:
+		return "NVMe critical warning: reliability is degraded or media is read-only"
+	default:
+		return "SMART overall-health self-assessment test failed"
+	}
+}
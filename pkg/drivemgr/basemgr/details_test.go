@@ -0,0 +1,46 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package basemgr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+func Test_driveHealthDetails(t *testing.T) {
+	details := driveHealthDetails(&api.Drive{Health: apiV1.HealthGood, Temperature: 40, Endurance: 97})
+	assert.Empty(t, details.Reason)
+	assert.Equal(t, map[string]string{smartAttrTemperature: "40", smartAttrEndurance: "97"}, details.SmartAttributes)
+
+	details = driveHealthDetails(&api.Drive{Health: apiV1.HealthUnknown})
+	assert.Equal(t, "SMART information isn't available", details.Reason)
+	assert.Empty(t, details.SmartAttributes)
+}
+
+func Test_healthReason(t *testing.T) {
+	assert.Equal(t, "SMART overall-health self-assessment test failed",
+		healthReason(&api.Drive{Type: apiV1.DriveTypeHDD, Path: "/dev/sda", Health: apiV1.HealthBad}))
+	assert.Contains(t, healthReason(&api.Drive{Type: apiV1.DriveTypeNVMe, Health: apiV1.HealthSuspect}),
+		"available spare")
+	// zoned NVMe drive
+	assert.Contains(t, healthReason(&api.Drive{Type: apiV1.DriveTypeZoned, Path: "/dev/nvme0n1",
+		Health: apiV1.HealthBad}), "read-only")
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package basemgr
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/pkg/base/capabilities"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsscsi"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ndctl"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/nvmecli"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/smartctl"
+)
+
+// Device buses which are reported in DeviceReport
+const (
+	BusSCSI = "SCSI"
+	BusNVMe = "NVMe"
+	BusPMEM = "PMEM"
+)
+
+// ToolReport describes availability of system util which is used for discovery
+type ToolReport struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	Usage     string `json:"usage"`
+}
+
+// DeviceReport describes device which was found during discovery and whether it is reported as a drive
+type DeviceReport struct {
+	Path     string     `json:"path"`
+	Bus      string     `json:"bus"`
+	Included bool       `json:"included"`
+	Reason   string     `json:"reason,omitempty"`
+	Drive    *api.Drive `json:"drive,omitempty"`
+}
+
+// Report is the result of discovery diagnostic, it explains why Drive CRs are absent on the node
+type Report struct {
+	Tools    []ToolReport   `json:"tools"`
+	Problems []string       `json:"problems"`
+	Devices  []DeviceReport `json:"devices"`
+}
+
+// Diagnose runs the same discovery as GetDrivesList but collects reasons why devices are included or excluded,
+// availability of system utils and permission problems instead of logging them
+// Returns Report
+func (mgr *BaseManager) Diagnose() *Report {
+	report := &Report{
+		Tools:    diagnoseTools(),
+		Problems: diagnosePermissions(capabilities.ProcSelfStatus),
+		Devices:  make([]DeviceReport, 0),
+	}
+	for _, tool := range report.Tools {
+		if !tool.Available && tool.Name != lsscsi.LsscsiCmd && tool.Name != ndctl.NdctlCmdImpl {
+			report.Problems = append(report.Problems,
+				fmt.Sprintf("%s isn't found in PATH, it is required for %s", tool.Name, tool.Usage))
+		}
+	}
+
+	scsiDevices, err := mgr.lsscsi.GetSCSIDevices()
+	if err != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("failed to list SCSI devices: %v", err))
+	}
+	for _, device := range scsiDevices {
+		drive, reason := mgr.scsiDrive(context.Background(), device)
+		report.Devices = append(report.Devices, DeviceReport{
+			Path: device.Path, Bus: BusSCSI, Included: reason == "", Reason: reason, Drive: drive})
+	}
+
+	nvmeDevices, err := mgr.nvme.GetNVMDevices()
+	if err != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("failed to list NVMe devices: %v", err))
+	}
+	for _, device := range nvmeDevices {
+		drive, reason := mgr.nvmeDrive(device)
+		report.Devices = append(report.Devices, DeviceReport{
+			Path: device.DevicePath, Bus: BusNVMe, Included: reason == "", Reason: reason, Drive: drive})
+	}
+
+	if mgr.ndctl != nil {
+		pmemDevices, err := mgr.ndctl.GetPMEMDevices()
+		if err != nil {
+			report.Problems = append(report.Problems, fmt.Sprintf("failed to list PMEM devices: %v", err))
+		}
+		for _, device := range pmemDevices {
+			report.Devices = append(report.Devices, DeviceReport{
+				Path: device.Path(), Bus: BusPMEM, Included: true, Drive: mgr.pmemDrive(device)})
+		}
+	}
+
+	if len(report.Devices) == 0 {
+		report.Problems = append(report.Problems, "no devices were found, Drive CRs will not be created")
+	}
+	return report
+}
+
+// diagnoseTools checks availability of system utils which are used for discovery
+func diagnoseTools() []ToolReport {
+	scsiUsage := "SCSI discovery, sysfs is used if it is absent"
+	if !preferLsscsi {
+		scsiUsage = "nothing on this architecture, SCSI devices are read from sysfs"
+	}
+	tools := []ToolReport{
+		{Name: lsscsi.LsscsiCmd, Usage: scsiUsage},
+		{Name: smartctl.SmartctlCmdImpl, Usage: "serial number, type, health and temperature of SCSI drives"},
+		{Name: nvmecli.NVMCliCmdImpl, Usage: "NVMe discovery"},
+		{Name: ndctl.NdctlCmdImpl, Usage: "PMEM discovery, PMEM isn't discovered if it is absent"},
+	}
+	for i := range tools {
+		tools[i].Available = command.IsAvailable(tools[i].Name)
+	}
+	return tools
+}
+
+// diagnosePermissions checks whether process has enough privileges to query drives
+// Receives path to the process status file
+// Returns list of found problems
+func diagnosePermissions(statusPath string) []string {
+	problems := make([]string, 0)
+	if os.Geteuid() != 0 {
+		problems = append(problems, "process isn't run as root, SMART and NVMe queries are likely to be denied")
+	}
+	capStatus, err := capabilities.ReadStatus(statusPath)
+	if err != nil {
+		return append(problems, fmt.Sprintf("unable to read capabilities: %v", err))
+	}
+	if !capStatus.Has(capabilities.CapSysAdmin) {
+		problems = append(problems, "CAP_SYS_ADMIN is missing, SMART and NVMe admin commands are likely to be denied")
+	}
+	return problems
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package basemgr
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsscsi"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ndctl"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/nvmecli"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ses"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/smartctl"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	"github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
+
+func TestBaseManager_Diagnose(t *testing.T) {
+	var (
+		manager      = New(&mocks.GoMockExecutor{}, logger)
+		mockLsscsi   = &linuxutils.MockWrapLsscsi{}
+		mockSmartctl = &linuxutils.MockWrapSmartctl{}
+		mockNvme     = &linuxutils.MockWrapNvmecli{}
+		mockNdctl    = &linuxutils.MockWrapNdctl{}
+		mockSES      = &linuxutils.MockWrapSES{}
+		mockNUMA     = &linuxutils.MockWrapNUMA{}
+	)
+	mockLsscsi.On("GetSCSIDevices", mock.Anything).Return([]*lsscsi.SCSIDevice{
+		{Path: "/dev/sda", Vendor: "vendor", Model: "model"},
+		{Path: "/dev/sdb", Vendor: "vendor", Model: "model"},
+	}, nil)
+	mockSmartctl.On("GetDriveInfoByPath", mock.Anything, "/dev/sda").
+		Return(&smartctl.DeviceSMARTInfo{SerialNumber: "sn-a", SmartStatus: map[string]bool{"passed": true}}, nil)
+	mockSmartctl.On("GetDriveInfoByPath", mock.Anything, "/dev/sdb").
+		Return(&smartctl.DeviceSMARTInfo{}, fmt.Errorf("permission denied"))
+	mockNvme.On("GetNVMDevices", mock.Anything).Return([]nvmecli.NVMDevice{{DevicePath: "/dev/nvme0n1"}}, nil)
+	mockNdctl.On("GetPMEMDevices").
+		Return([]ndctl.PMEMDevice{{Dev: "namespace0.0", Mode: ndctl.ModeFSDAX, UUID: "pmem-uuid", BlockDev: "pmem0"}}, nil)
+	mockSES.On("GetDriveLocation", mock.Anything).Return((*ses.DriveLocation)(nil), nil)
+	mockNUMA.On("GetDeviceNUMANode", mock.Anything).Return("", nil)
+
+	manager.lsscsi = mockLsscsi
+	manager.smartctl = mockSmartctl
+	manager.nvme = mockNvme
+	manager.ndctl = mockNdctl
+	manager.ses = mockSES
+	manager.numa = mockNUMA
+
+	report := manager.Diagnose()
+	assert.Len(t, report.Tools, 4)
+	assert.Len(t, report.Devices, 4)
+
+	assert.True(t, report.Devices[0].Included)
+	assert.Equal(t, "sn-a", report.Devices[0].Drive.SerialNumber)
+	assert.False(t, report.Devices[1].Included)
+	assert.Contains(t, report.Devices[1].Reason, "permission denied")
+	assert.False(t, report.Devices[2].Included)
+	assert.Equal(t, BusNVMe, report.Devices[2].Bus)
+	assert.Equal(t, "device has empty VID, PID or SN field", report.Devices[2].Reason)
+	assert.True(t, report.Devices[3].Included)
+	assert.Equal(t, BusPMEM, report.Devices[3].Bus)
+	assert.Equal(t, "/dev/pmem0", report.Devices[3].Path)
+}
+
+func Test_diagnosePermissions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diagnose")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	statusPath := filepath.Join(dir, "status")
+
+	assert.Nil(t, ioutil.WriteFile(statusPath, []byte("CapEff:\t0000000000000000\n"), 0644))
+	assert.Contains(t, diagnosePermissions(statusPath),
+		"CAP_SYS_ADMIN is missing, SMART and NVMe admin commands are likely to be denied")
+
+	assert.Nil(t, ioutil.WriteFile(statusPath, []byte("CapEff:\t0000003fffffffff\n"), 0644))
+	assert.NotContains(t, diagnosePermissions(statusPath),
+		"CAP_SYS_ADMIN is missing, SMART and NVMe admin commands are likely to be denied")
+
+	problems := diagnosePermissions(filepath.Join(dir, "absent"))
+	assert.Contains(t, problems[len(problems)-1], "unable to read capabilities")
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package basemgr
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils"
+)
+
+// probeCache keeps results of drive probing, device is probed again only if its state in sysfs (WWN, size and
+// partition table) is changed or result is older than rescan interval, since SMART health, temperature and endurance
+// aren't reflected in sysfs
+type probeCache struct {
+	sysfs          string
+	rescanInterval time.Duration
+	clock          clock.PassiveClock
+
+	sync.Mutex
+	entries map[string]*probeEntry
+}
+
+// probeEntry is a result of device probing together with device state at that moment
+type probeEntry struct {
+	state    string
+	drive    *api.Drive
+	probedAt time.Time
+}
+
+// newProbeCache is a constructor for probeCache, cache is disabled if rescanInterval isn't positive
+func newProbeCache(rescanInterval time.Duration) *probeCache {
+	return &probeCache{
+		sysfs:          linuxutils.SysfsPath,
+		rescanInterval: rescanInterval,
+		clock:          clock.RealClock{},
+		entries:        make(map[string]*probeEntry),
+	}
+}
+
+// probe returns drive cached for device path if device state isn't changed, otherwise calls probeFn
+// Only drives which weren't skipped are cached, so failed probe is repeated on the next discovery
+func (c *probeCache) probe(path string, probeFn func() (*api.Drive, string)) (drive *api.Drive, reason string) {
+	if c == nil || c.rescanInterval <= 0 {
+		return probeFn()
+	}
+	state, err := c.deviceState(path)
+	if err != nil {
+		// device state is unknown, cache can't be used
+		return probeFn()
+	}
+
+	c.Lock()
+	entry, ok := c.entries[path]
+	c.Unlock()
+	if ok && entry.state == state && c.clock.Since(entry.probedAt) < c.rescanInterval {
+		return proto.Clone(entry.drive).(*api.Drive), ""
+	}
+
+	drive, reason = probeFn()
+	c.Lock()
+	defer c.Unlock()
+	if reason != "" || drive == nil {
+		delete(c.entries, path)
+		return drive, reason
+	}
+	c.entries[path] = &probeEntry{state: state, drive: proto.Clone(drive).(*api.Drive), probedAt: c.clock.Now()}
+	return drive, reason
+}
+
+// retain removes cached results of devices which aren't present anymore
+func (c *probeCache) retain(paths []string) {
+	if c == nil {
+		return
+	}
+	present := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		present[path] = true
+	}
+	c.Lock()
+	defer c.Unlock()
+	for path := range c.entries {
+		if !present[path] {
+			delete(c.entries, path)
+		}
+	}
+}
+
+// deviceState returns hash of WWN, size and partition table of block device read from sysfs
+func (c *probeCache) deviceState(path string) (string, error) {
+	name := filepath.Base(path)
+	blockDir := filepath.Join(c.sysfs, "block", name)
+	size, err := readSysfsAttr(filepath.Join(blockDir, "size"))
+	if err != nil {
+		return "", err
+	}
+	// SCSI disks report WWN in device directory, NVMe namespaces in block directory
+	wwid, err := readSysfsAttr(filepath.Join(blockDir, "device", "wwid"))
+	if err != nil {
+		wwid, _ = readSysfsAttr(filepath.Join(blockDir, "wwid"))
+	}
+	entries, err := ioutil.ReadDir(blockDir)
+	if err != nil {
+		return "", err
+	}
+	partitions := make([]string, 0)
+	for _, entry := range entries {
+		partDir := filepath.Join(blockDir, entry.Name())
+		if !strings.HasPrefix(entry.Name(), name) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(partDir, "partition")); err != nil {
+			continue
+		}
+		start, _ := readSysfsAttr(filepath.Join(partDir, "start"))
+		partSize, _ := readSysfsAttr(filepath.Join(partDir, "size"))
+		partitions = append(partitions, fmt.Sprintf("%s:%s:%s", entry.Name(), start, partSize))
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s", wwid, size, strings.Join(partitions, ","))))
+	return fmt.Sprintf("%x", hash), nil
+}
+
+// readSysfsAttr returns trimmed content of sysfs attribute
+func readSysfsAttr(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
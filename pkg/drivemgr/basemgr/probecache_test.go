@@ -0,0 +1,124 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package basemgr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+)
+
+func writeSysfsAttr(t *testing.T, path, value string) {
+	assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.Nil(t, ioutil.WriteFile(path, []byte(value+"\n"), 0644))
+}
+
+func TestProbeCache_probe(t *testing.T) {
+	root, err := ioutil.TempDir("", "sysfs")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(root) }()
+
+	blockDir := filepath.Join(root, "block", "sda")
+	writeSysfsAttr(t, filepath.Join(blockDir, "size"), "2048")
+	writeSysfsAttr(t, filepath.Join(blockDir, "device", "wwid"), "naa.5000c500a1b2c3d4")
+
+	cache := newProbeCache(time.Minute)
+	cache.sysfs = root
+	fakeClock := clock.NewFakeClock(time.Now())
+	cache.clock = fakeClock
+	probes := 0
+	probeFn := func() (*api.Drive, string) {
+		probes++
+		return &api.Drive{SerialNumber: "sn", Path: "/dev/sda"}, ""
+	}
+
+	drive, reason := cache.probe("/dev/sda", probeFn)
+	assert.Equal(t, "", reason)
+	assert.Equal(t, "sn", drive.SerialNumber)
+	assert.Equal(t, 1, probes)
+
+	// state isn't changed, cached copy is returned
+	drive.SerialNumber = "changed"
+	drive, _ = cache.probe("/dev/sda", probeFn)
+	assert.Equal(t, "sn", drive.SerialNumber)
+	assert.Equal(t, 1, probes)
+
+	// partition is created
+	writeSysfsAttr(t, filepath.Join(blockDir, "sda1", "partition"), "1")
+	writeSysfsAttr(t, filepath.Join(blockDir, "sda1", "start"), "2048")
+	writeSysfsAttr(t, filepath.Join(blockDir, "sda1", "size"), "1024")
+	cache.probe("/dev/sda", probeFn)
+	assert.Equal(t, 2, probes)
+	cache.probe("/dev/sda", probeFn)
+	assert.Equal(t, 2, probes)
+
+	// drive is replaced
+	writeSysfsAttr(t, filepath.Join(blockDir, "device", "wwid"), "naa.5000c500ffffffff")
+	cache.probe("/dev/sda", probeFn)
+	assert.Equal(t, 3, probes)
+
+	// result is expired
+	fakeClock.Step(time.Minute - time.Second)
+	cache.probe("/dev/sda", probeFn)
+	assert.Equal(t, 3, probes)
+	fakeClock.Step(time.Second)
+	cache.probe("/dev/sda", probeFn)
+	assert.Equal(t, 4, probes)
+
+	// device without sysfs state is always probed
+	cache.probe("/dev/sdb", probeFn)
+	cache.probe("/dev/sdb", probeFn)
+	assert.Equal(t, 6, probes)
+
+	// skipped drive isn't cached
+	failedProbe := func() (*api.Drive, string) {
+		probes++
+		return &api.Drive{Path: "/dev/sda"}, "failed to get SMART information"
+	}
+	fakeClock.Step(time.Minute)
+	_, reason = cache.probe("/dev/sda", failedProbe)
+	assert.NotEqual(t, "", reason)
+	cache.probe("/dev/sda", probeFn)
+	assert.Equal(t, 8, probes)
+
+	// removed device is evicted
+	cache.retain([]string{"/dev/sdc"})
+	assert.Empty(t, cache.entries)
+}
+
+func TestProbeCache_disabled(t *testing.T) {
+	var (
+		cache  *probeCache
+		probes int
+	)
+	probeFn := func() (*api.Drive, string) {
+		probes++
+		return &api.Drive{}, ""
+	}
+	cache.probe("/dev/sda", probeFn)
+	cache.retain(nil)
+	cache = newProbeCache(0)
+	cache.probe("/dev/sda", probeFn)
+	assert.Equal(t, 2, probes)
+}
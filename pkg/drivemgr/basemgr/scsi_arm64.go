@@ -0,0 +1,24 @@
+//go:build arm64
+// +build arm64
+
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package basemgr
+
+// preferLsscsi defines whether lsscsi util should be used for SCSI discovery when it is available
+// lsscsi is often absent or too old (no --no-nvme option) on arm64 distributions, so sysfs is preferred
+const preferLsscsi = false
@@ -0,0 +1,101 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package basemgr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ipmi"
+)
+
+// SELEventsLimit is a maximum amount of the newest SEL records which are reported for the drive
+const SELEventsLimit = 5
+
+const (
+	// selDriveSensorType is a prefix of SEL sensor of drive bays
+	selDriveSensorType = "Drive Slot / Bay"
+	// selBackplaneSensor is a part of SEL sensor name of backplanes, records of backplanes are reported for all
+	// drives which are placed in enclosure
+	selBackplaneSensor = "backplane"
+)
+
+// selSlotRegexp matches number of drive bay at the end of the sensor name, e.g. "Drive 5" or "#0x05"
+var selSlotRegexp = regexp.MustCompile(`(?:#0x([0-9a-fA-F]+)|(\d+))\s*$`)
+
+// SetIPMI enables correlation of IPMI SEL records with drive bays and backplanes, correlation is disabled if it is nil
+func (mgr *BaseManager) SetIPMI(i ipmi.WrapIpmi) {
+	mgr.ipmi = i
+}
+
+// fillSELEvents attaches the newest SEL records of the drive bay or backplane to the drives,
+// drives are reported without records if SEL isn't available
+func (mgr *BaseManager) fillSELEvents(drives []*api.Drive) {
+	if mgr.ipmi == nil {
+		return
+	}
+	records, err := mgr.ipmi.GetSELRecords()
+	if err != nil {
+		mgr.log.WithField("method", "fillSELEvents").Warnf("Failed to read IPMI SEL: %v", err)
+		return
+	}
+	for _, drive := range drives {
+		slot, hasSlot := parseSlot(drive.Slot)
+		var events []string
+		for _, record := range records {
+			recordSlot, isDrive := selRecordSlot(record)
+			backplane := strings.Contains(strings.ToLower(record.Sensor), selBackplaneSensor)
+			if (isDrive && hasSlot && recordSlot == slot) || (backplane && drive.Enclosure != "") {
+				events = append(events, record.String())
+			}
+		}
+		if len(events) > SELEventsLimit {
+			events = events[len(events)-SELEventsLimit:]
+		}
+		drive.SELEvents = events
+	}
+}
+
+// selRecordSlot returns number of drive bay of the SEL record
+// Returns false if record isn't related to drive bay or sensor doesn't contain number
+func selRecordSlot(record ipmi.SELRecord) (int, bool) {
+	if !strings.HasPrefix(record.Sensor, selDriveSensorType) {
+		return 0, false
+	}
+	match := selSlotRegexp.FindStringSubmatch(record.Sensor)
+	if match == nil {
+		return 0, false
+	}
+	if match[1] != "" {
+		slot, err := strconv.ParseInt(match[1], 16, 32)
+		return int(slot), err == nil
+	}
+	slot, err := strconv.Atoi(match[2])
+	return slot, err == nil
+}
+
+// parseSlot returns number of the drive slot, e.g. 1 for "1" or "Slot 01"
+func parseSlot(slot string) (int, bool) {
+	match := selSlotRegexp.FindStringSubmatch(slot)
+	if match == nil || match[2] == "" {
+		return 0, false
+	}
+	number, err := strconv.Atoi(match[2])
+	return number, err == nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package basemgr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ipmi"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	"github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
+
+func TestBaseManager_FillSELEvents(t *testing.T) {
+	manager := New(&mocks.GoMockExecutor{}, logger)
+	drives := []*api.Drive{
+		{SerialNumber: "sn-1", Enclosure: "enclosure", Slot: "1"},
+		{SerialNumber: "sn-2", Enclosure: "enclosure", Slot: "Slot 05"},
+		{SerialNumber: "sn-3"},
+	}
+	// correlation is disabled
+	manager.fillSELEvents(drives)
+	for _, drive := range drives {
+		assert.Empty(t, drive.SELEvents)
+	}
+
+	records := []ipmi.SELRecord{
+		{ID: "1", Date: "10/14/2026", Time: "09:12:44", Sensor: "Drive Slot / Bay Drive 1", Event: "Drive Fault",
+			Direction: "Asserted"},
+		{ID: "2", Date: "10/14/2026", Time: "09:13:01", Sensor: "Drive Slot / Bay #0x05", Event: "Drive Present",
+			Direction: "Deasserted"},
+		{ID: "3", Date: "10/14/2026", Time: "09:14:00", Sensor: "Cable / Interconnect Backplane Cable",
+			Event: "Config Error", Direction: "Asserted"},
+		{ID: "4", Date: "10/14/2026", Time: "09:15:00", Sensor: "Power Unit #0x01", Event: "Redundancy Lost"},
+		{ID: "5", Date: "10/14/2026", Time: "09:16:00", Sensor: "Drive Slot / Bay Drive 11", Event: "Drive Fault"},
+	}
+	mockIPMI := &linuxutils.MockWrapIpmi{}
+	mockIPMI.On("GetSELRecords").Return(records, nil).Once()
+	manager.SetIPMI(mockIPMI)
+	manager.fillSELEvents(drives)
+	assert.Equal(t, []string{records[0].String(), records[2].String()}, drives[0].SELEvents)
+	assert.Equal(t, []string{records[1].String(), records[2].String()}, drives[1].SELEvents)
+	assert.Empty(t, drives[2].SELEvents)
+
+	// only the newest records are reported
+	records = nil
+	for i := 0; i < SELEventsLimit+2; i++ {
+		records = append(records, ipmi.SELRecord{ID: fmt.Sprint(i), Time: fmt.Sprintf("09:%02d:00", i),
+			Sensor: "Drive Slot / Bay Drive 1", Event: "Drive Fault"})
+	}
+	mockIPMI.On("GetSELRecords").Return(records, nil).Once()
+	manager.fillSELEvents(drives)
+	assert.Len(t, drives[0].SELEvents, SELEventsLimit)
+	assert.Equal(t, records[len(records)-1].String(), drives[0].SELEvents[SELEventsLimit-1])
+
+	// drives are reported without records if SEL isn't available
+	mockIPMI.On("GetSELRecords").Return([]ipmi.SELRecord(nil), errors.New("ipmitool failed")).Once()
+	manager.fillSELEvents(drives)
+	assert.Len(t, drives[0].SELEvents, SELEventsLimit)
+}
+
+func TestSELRecordSlot(t *testing.T) {
+	for sensor, expected := range map[string]int{
+		"Drive Slot / Bay Drive 5":  5,
+		"Drive Slot / Bay #0x1a":    26,
+		"Drive Slot / Bay Disk 12 ": 12,
+	} {
+		slot, ok := selRecordSlot(ipmi.SELRecord{Sensor: sensor})
+		assert.True(t, ok, sensor)
+		assert.Equal(t, expected, slot, sensor)
+	}
+	for _, sensor := range []string{"Drive Slot / Bay", "Power Unit #0x01", "Backplane 1"} {
+		_, ok := selRecordSlot(ipmi.SELRecord{Sensor: sensor})
+		assert.False(t, ok, sensor)
+	}
+}
@@ -26,4 +26,17 @@ type DriveManager interface {
 	// manipulate of drive's led state, receive drive serial number and type of action
 	// returns current led status or error
 	Locate(serialNumber string, action int32) (currentStatus int32, err error)
+	// flash drive's firmware, receive drive serial number and path to the firmware image
+	// returns firmware version reported by drive after update or error
+	UpdateFirmware(serialNumber string, image string) (firmware string, err error)
+	// replace NVMe namespaces of drive's controller with provided amount of namespaces of equal size
+	// returns serial numbers of drives which correspond to created namespaces or error
+	CreateNamespaces(serialNumber string, count int32) (serialNumbers []string, err error)
+}
+
+// DriveDetailsManager is the optional interface for managers which provide v2 API details about drives
+// such as health reason, slot info and temperature
+type DriveDetailsManager interface {
+	// get list of drives with details
+	GetDrivesDetails() ([]*api.DriveDetails, error)
 }
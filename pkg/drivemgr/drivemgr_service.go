@@ -76,3 +76,40 @@ func (svc *DriveServiceServerImpl) Locate(ctx context.Context, in *api.DriveLoca
 
 	return &api.DriveLocateResponse{Status: currentStatus}, nil
 }
+
+// UpdateFirmware invokes DriveManager's UpdateFirmware method for flashing drive's firmware
+func (svc *DriveServiceServerImpl) UpdateFirmware(ctx context.Context, in *api.DriveFirmwareUpdateRequest) (*api.DriveFirmwareUpdateResponse, error) {
+	firmware, err := svc.mgr.UpdateFirmware(in.GetDriveSerialNumber(), in.GetImage())
+	if err != nil {
+		svc.log.Errorf("Unable to update firmware of device %s, image %s: %v", in.GetDriveSerialNumber(), in.GetImage(), err)
+		// keep status code (e.g. Unimplemented) if DriveManager provided it
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &api.DriveFirmwareUpdateResponse{Firmware: firmware}, nil
+}
+
+// CreateNamespaces invokes DriveManager's CreateNamespaces method for carving NVMe drive into namespaces
+func (svc *DriveServiceServerImpl) CreateNamespaces(ctx context.Context, in *api.DriveNamespacesRequest) (*api.DriveNamespacesResponse, error) {
+	serialNumbers, err := svc.mgr.CreateNamespaces(in.GetDriveSerialNumber(), in.GetCount())
+	if err != nil {
+		svc.log.Errorf("Unable to create %d namespaces on device %s: %v", in.GetCount(), in.GetDriveSerialNumber(), err)
+		// keep status code (e.g. Unimplemented) if DriveManager provided it
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &api.DriveNamespacesResponse{SerialNumbers: serialNumbers}, nil
+}
+
+// GetVersion negotiates API version with the client
+// Receives go context and VersionRequest with versions supported by the client
+// Returns VersionResponse with the highest version which is supported by both sides
+func (svc *DriveServiceServerImpl) GetVersion(ctx context.Context, in *api.VersionRequest) (*api.VersionResponse, error) {
+	return &api.VersionResponse{Version: chooseAPIVersion(in.GetSupported())}, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivemgr
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// DriveServiceV2ServerImpl is the implementation of v2 gRPC server of drive manager
+// If DriveManager doesn't implement DriveDetailsManager details are built from v1 drives
+type DriveServiceV2ServerImpl struct {
+	mgr DriveManager
+	log *logrus.Entry
+}
+
+// NewDriveServerV2 is the constructor for DriveServiceV2ServerImpl struct
+// Receives logrus logger and implementation of DriveManager as parameters
+// Returns an instance of DriveServiceV2ServerImpl
+func NewDriveServerV2(logger *logrus.Logger, manager DriveManager) DriveServiceV2ServerImpl {
+	return DriveServiceV2ServerImpl{
+		log: logger.WithField("component", "DriveServiceV2ServerImpl"),
+		mgr: manager,
+	}
+}
+
+// GetDrivesList returns drives with details and sends the response over gRPC
+// Receives go context and DrivesRequest which contains node id
+// Returns DrivesDetailsResponse with slice of api.DriveDetails structs
+func (svc *DriveServiceV2ServerImpl) GetDrivesList(ctx context.Context, req *api.DrivesRequest) (*api.DrivesDetailsResponse, error) {
+	var (
+		details []*api.DriveDetails
+		err     error
+	)
+	if dm, ok := svc.mgr.(DriveDetailsManager); ok {
+		details, err = dm.GetDrivesDetails()
+	} else {
+		details, err = svc.detailsFromDrives()
+	}
+	if err != nil {
+		svc.log.Errorf("DriveManager failed with error: %s", err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	for _, d := range details {
+		if d.Drive == nil {
+			continue
+		}
+		d.Drive.NodeId = req.NodeId
+		// All drives are ONLINE by default
+		if d.Drive.Status == "" {
+			d.Drive.Status = apiV1.DriveStatusOnline
+		}
+	}
+	return &api.DrivesDetailsResponse{Disks: details}, nil
+}
+
+// detailsFromDrives builds details from v1 drives, only slot info and temperature are known for them
+func (svc *DriveServiceV2ServerImpl) detailsFromDrives() ([]*api.DriveDetails, error) {
+	drives, err := svc.mgr.GetDrivesList()
+	if err != nil {
+		return nil, err
+	}
+	details := make([]*api.DriveDetails, 0, len(drives))
+	for _, drive := range drives {
+		details = append(details, &api.DriveDetails{
+			Drive: drive,
+			Slot: &api.DriveSlotInfo{
+				Enclosure: drive.Enclosure,
+				Slot:      drive.Slot,
+				Bay:       drive.Bay,
+				Backplane: drive.Backplane,
+			},
+			Temperature: drive.Temperature,
+		})
+	}
+	return details, nil
+}
@@ -129,6 +129,16 @@ func (mgr *IDRACManager) Locate(serialNumber string, action int32) (int32, error
 	return -1, status.Error(codes.Unimplemented, "method Locate not implemented in IDRACManager")
 }
 
+// UpdateFirmware implements UpdateFirmware method of DriveManager interface
+func (mgr *IDRACManager) UpdateFirmware(serialNumber string, image string) (string, error) {
+	return "", status.Error(codes.Unimplemented, "method UpdateFirmware not implemented in IDRACManager")
+}
+
+// CreateNamespaces implements CreateNamespaces method of DriveManager interface
+func (mgr *IDRACManager) CreateNamespaces(serialNumber string, count int32) ([]string, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateNamespaces not implemented in IDRACManager")
+}
+
 // getControllerURLs returns slice of all controllers url in Storage
 func (mgr *IDRACManager) getControllerURLs() []string {
 	endpoint := fmt.Sprintf("https://%s%s", mgr.ip, storageURL)
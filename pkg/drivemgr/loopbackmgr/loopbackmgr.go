@@ -33,6 +33,7 @@ import (
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/bytesize"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/base/util"
@@ -52,17 +53,34 @@ const (
 	rootPath          = "/"
 	imagesFolder      = "/host/home"
 	createFileCmdTmpl = "dd if=/dev/zero of=%s bs=1M count=%d"
-	deleteFileCmdTmpl = "rm -rf %s"
+	// creates sparse file which doesn't allocate space until written
+	createSparseFileCmdTmpl = "truncate -s %dM %s"
+	deleteFileCmdTmpl       = "rm -rf %s"
 	// requires root privileges
 	losetupCmd                      = "losetup"
 	readLoopBackDevicesMappingCmd   = losetupCmd + " -O NAME,BACK-FILE"
 	setupLoopBackDeviceCmdTmpl      = losetupCmd + " -fP --show %s"
 	detachLoopBackDeviceCmdTmpl     = losetupCmd + " -d %s"
 	findUnusedLoopBackDeviceCmdTmpl = losetupCmd + " -f"
+	// loop devices have major number 7 and minor number equal to the index of the device
+	createLoopDeviceNodeCmdTmpl = "mknod %s b 7 %d"
+	loopDevicePathFmt           = "/dev/loop%d"
 
 	configPath = "/etc/config/config.yaml"
 )
 
+// Modes of devices emulation
+const (
+	// ModeLoop creates files filled with zeroes and binds them to loop devices
+	ModeLoop = "loop"
+	// ModeSparse creates sparse files and binds them to loop devices, it allows to simulate hundreds of drives
+	// without consuming disk space on the node
+	ModeSparse = "sparse"
+	// ModeMetadata doesn't create files and loop devices at all, drives are only reported through DriveManager API.
+	// It is used for scale testing of controllers and scheduler extender, volumes can't be created on such drives
+	ModeMetadata = "metadata"
+)
+
 /*
 LoopBackManager is created for testing purposes only!
 It allows to deploy CSI driver on your laptop with minikube or kind.
@@ -100,12 +118,15 @@ type Node struct {
 	NodeID     string            `yaml:"nodeID"`
 	DriveCount int               `yaml:"driveCount"`
 	Drives     []*LoopBackDevice `yaml:"drives"`
+	// Unavailable makes manager fail drive list requests, it imitates drive manager crash in chaos tests
+	Unavailable bool `yaml:"unavailable"`
 }
 
 // Config struct is the configuration for LoopBackManager. It contains default settings and settings for each node
 type Config struct {
 	DefaultDriveCount int     `yaml:"defaultDrivePerNodeCount"`
 	DefaultDriveSize  string  `yaml:"defaultDriveSize"`
+	Mode              string  `yaml:"mode"`
 	Nodes             []*Node `yaml:"nodes"`
 }
 
@@ -383,12 +404,18 @@ func (mgr *LoopBackManager) overrideDevicesFromNodeConfig(deviceCount int, devic
 // createDefaultDevices initialized LoopBackManager's devices with default devices
 // Receives deviceCount that represents amount of devices to create
 func (mgr *LoopBackManager) createDefaultDevices(deviceCount int) {
+	metadataMode := mgr.getMode() == ModeMetadata
 	for i := 0; i < deviceCount; i++ {
 		deviceID := uuid.New().ID()
 		device := &LoopBackDevice{
 			SerialNumber: fmt.Sprintf("LOOPBACK%d", deviceID),
 			fileName:     fmt.Sprintf(imagesFolder+"/%s-%d.img", mgr.nodeID, deviceID),
 		}
+		// there are no image files to recover devices from in ModeMetadata,
+		// serial number should be the same after restart to not produce new drives
+		if metadataMode {
+			device.SerialNumber = fmt.Sprintf("LOOPBACK-%s-%d", mgr.nodeID, len(mgr.devices))
+		}
 		// If device Size is not specified then use default size from config
 		if device.Size == "" && mgr.config != nil && mgr.config.DefaultDriveSize != "" {
 			device.Size = mgr.config.DefaultDriveSize
@@ -398,14 +425,33 @@ func (mgr *LoopBackManager) createDefaultDevices(deviceCount int) {
 	}
 }
 
+// getMode returns devices emulation mode from config, ModeLoop is used if mode isn't set or unknown
+func (mgr *LoopBackManager) getMode() string {
+	if mgr.config == nil {
+		return ModeLoop
+	}
+	switch mgr.config.Mode {
+	case ModeLoop, ModeSparse, ModeMetadata:
+		return mgr.config.Mode
+	case "":
+		return ModeLoop
+	default:
+		mgr.log.WithField("method", "getMode").Warnf("Unknown mode %s, %s is used", mgr.config.Mode, ModeLoop)
+		return ModeLoop
+	}
+}
+
 // deleteLoopbackDevice detach specified loopback device and delete according file
 func (mgr *LoopBackManager) deleteLoopbackDevice(device *LoopBackDevice) {
 	ll := mgr.log.WithField("method", "deleteLoopbackDevice")
-	_, _, err := mgr.exec.RunCmd(fmt.Sprintf(detachLoopBackDeviceCmdTmpl, device.devicePath))
-	if err != nil {
-		ll.Errorf("Unable to detach loopback device %s", device.devicePath)
+	// device isn't bound to loop device in ModeMetadata
+	if device.devicePath != "" {
+		_, _, err := mgr.exec.RunCmd(fmt.Sprintf(detachLoopBackDeviceCmdTmpl, device.devicePath))
+		if err != nil {
+			ll.Errorf("Unable to detach loopback device %s", device.devicePath)
+		}
 	}
-	_, _, err = mgr.exec.RunCmd(fmt.Sprintf(deleteFileCmdTmpl, device.fileName))
+	_, _, err := mgr.exec.RunCmd(fmt.Sprintf(deleteFileCmdTmpl, device.fileName))
 	if err != nil {
 		ll.Errorf("Unable to delete file %s", device.fileName)
 	}
@@ -417,6 +463,13 @@ func (mgr *LoopBackManager) Init() {
 	ll := mgr.log.WithField("method", "Init")
 	mgr.Lock()
 	defer mgr.Unlock()
+
+	mode := mgr.getMode()
+	if mode == ModeMetadata {
+		ll.Infof("Mode %s is used, %d devices are simulated without files and loop devices", mode, len(mgr.devices))
+		return
+	}
+
 	fsOps := fs.NewFSImpl(mgr.exec)
 	// go through the list of devices and register if needed
 	for i := 0; i < len(mgr.devices); i++ {
@@ -426,7 +479,7 @@ func (mgr *LoopBackManager) Init() {
 		}
 		// wil create files in home dir. we might need to store them on host to test FI
 		file := mgr.devices[i].fileName
-		sizeBytes, err := util.StrToBytes(mgr.devices[i].Size)
+		sizeBytes, err := bytesize.Parse(mgr.devices[i].Size)
 		if err != nil {
 			ll.Errorf("Failed to convert device size to bytes. Continue for next device")
 			continue
@@ -438,14 +491,18 @@ func (mgr *LoopBackManager) Init() {
 			if err != nil {
 				ll.Fatal("Failed to check root fs space")
 			}
-			bytes, err := util.StrToBytes(threshold)
+			bytes, err := bytesize.Parse(threshold)
 			if err != nil {
 				ll.Fatalf("Parsing threshold %s failed", threshold)
 			}
 			if freeBytes < bytes {
 				ll.Fatal("Not enough space on root fs")
 			}
-			_, stderr, errcode := mgr.exec.RunCmd(fmt.Sprintf(createFileCmdTmpl, file, sizeMb))
+			createCmd := fmt.Sprintf(createFileCmdTmpl, file, sizeMb)
+			if mode == ModeSparse {
+				createCmd = fmt.Sprintf(createSparseFileCmdTmpl, sizeMb, file)
+			}
+			_, stderr, errcode := mgr.exec.RunCmd(createCmd)
 			if errcode != nil {
 				ll.Fatalf("Unable to create file %s with size %d MB: %s", file, sizeMb, stderr)
 			}
@@ -470,9 +527,11 @@ func (mgr *LoopBackManager) Init() {
 		}
 		if !found {
 			// check that system has unused device for troubleshooting purposes
-			_, _, err = mgr.exec.RunCmd(findUnusedLoopBackDeviceCmdTmpl)
+			unused, _, err := mgr.exec.RunCmd(findUnusedLoopBackDeviceCmdTmpl)
 			if err != nil {
 				ll.Error("System doesn't have unused loopback devices")
+			} else {
+				mgr.ensureLoopDeviceNode(strings.TrimSpace(unused))
 			}
 
 			// create new device
@@ -486,11 +545,34 @@ func (mgr *LoopBackManager) Init() {
 	}
 }
 
+// ensureLoopDeviceNode creates device node of loop device if it doesn't exist. Loop devices are allocated by
+// the host kernel, but in kind /dev of the node container isn't devtmpfs of the host, so nodes of devices which
+// weren't used before don't appear there and losetup fails to bind a file to them
+func (mgr *LoopBackManager) ensureLoopDeviceNode(devicePath string) {
+	ll := mgr.log.WithField("method", "ensureLoopDeviceNode")
+	var index int
+	if _, err := fmt.Sscanf(devicePath, loopDevicePathFmt, &index); err != nil {
+		ll.Warnf("Unexpected loop device path %s: %v", devicePath, err)
+		return
+	}
+	if _, err := os.Stat(devicePath); err == nil {
+		return
+	}
+	if _, stderr, err := mgr.exec.RunCmd(fmt.Sprintf(createLoopDeviceNodeCmdTmpl, devicePath, index)); err != nil {
+		ll.Errorf("Unable to create device node %s: %s", devicePath, stderr)
+		return
+	}
+	ll.Infof("Device node %s is created", devicePath)
+}
+
 // GetDrivesList returns list of loopback devices as *api.Drive slice
 // Returns *api.Drive slice or error if something went wrong
 func (mgr *LoopBackManager) GetDrivesList() ([]*api.Drive, error) {
 	mgr.Lock()
 	defer mgr.Unlock()
+	if mgr.isUnavailable() {
+		return nil, status.Error(codes.Unavailable, "drive manager is unavailable by config")
+	}
 	drives := make([]*api.Drive, 0, len(mgr.devices))
 	for i := 0; i < len(mgr.devices); i++ {
 		var driveStatus string
@@ -499,7 +581,7 @@ func (mgr *LoopBackManager) GetDrivesList() ([]*api.Drive, error) {
 		} else {
 			driveStatus = apiV1.DriveStatusOnline
 		}
-		sizeBytes, _ := util.StrToBytes(mgr.devices[i].Size)
+		sizeBytes, _ := bytesize.Parse(mgr.devices[i].Size)
 		drive := &api.Drive{
 			VID:          mgr.devices[i].VendorID,
 			PID:          mgr.devices[i].ProductID,
@@ -515,6 +597,19 @@ func (mgr *LoopBackManager) GetDrivesList() ([]*api.Drive, error) {
 	return drives, nil
 }
 
+// isUnavailable checks whether manager of the node is set unavailable in config
+func (mgr *LoopBackManager) isUnavailable() bool {
+	if mgr.config == nil {
+		return false
+	}
+	for _, node := range mgr.config.Nodes {
+		if node.NodeID == mgr.nodeName {
+			return node.Unavailable
+		}
+	}
+	return false
+}
+
 // Locate implements Locate method of DriveManager interface
 func (mgr *LoopBackManager) Locate(serialNumber string, action int32) (int32, error) {
 	for i, device := range mgr.devices {
@@ -534,6 +629,16 @@ func (mgr *LoopBackManager) Locate(serialNumber string, action int32) (int32, er
 	return -1, status.Error(codes.InvalidArgument, "Wrong arguments for Locate methods")
 }
 
+// UpdateFirmware implements UpdateFirmware method of DriveManager interface
+func (mgr *LoopBackManager) UpdateFirmware(serialNumber string, image string) (string, error) {
+	return "", status.Error(codes.Unimplemented, "method UpdateFirmware not implemented in LoopBackManager")
+}
+
+// CreateNamespaces implements CreateNamespaces method of DriveManager interface
+func (mgr *LoopBackManager) CreateNamespaces(serialNumber string, count int32) ([]string, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateNamespaces not implemented in LoopBackManager")
+}
+
 // GetBackFileToLoopMap return mapping between backing file and loopback devices
 // Multiple loopback devices can be created from on backing file.
 func (mgr *LoopBackManager) GetBackFileToLoopMap() (map[string][]string, error) {
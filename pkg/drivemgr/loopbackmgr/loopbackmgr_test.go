@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/mocks"
 )
 
@@ -298,6 +299,12 @@ func TestLoopBackManager_GetDrivesList(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, defaultNumberOfDevices, len(drives))
 	assert.Equal(t, apiV1.DriveStatusOffline, drives[indexOfDriveToOffline].Status)
+
+	// manager is set unavailable in config
+	manager.config = &Config{Nodes: []*Node{{NodeID: manager.nodeName, Unavailable: true}}}
+	drives, err = manager.GetDrivesList()
+	assert.NotNil(t, err)
+	assert.Nil(t, drives)
 }
 
 func TestLoopBackManager_attemptToRecoverDevicesFromConfig(t *testing.T) {
@@ -381,3 +388,77 @@ func TestLoopBackManager_attemptToRecoverDevicesFromDefaults(t *testing.T) {
 	manager.attemptToRecoverDevices(testImagesPath)
 	assert.Equal(t, len(manager.devices), 1)
 }
+
+func TestLoopBackManager_InitMetadataMode(t *testing.T) {
+	var mockexec = &mocks.GoMockExecutor{}
+	var manager = NewLoopBackManager(mockexec, "", "", logger)
+	manager.config = &Config{Mode: ModeMetadata}
+	driveCount := 300
+
+	manager.createDefaultDevices(driveCount)
+	// no commands are expected, mock executor fails on any
+	manager.Init()
+
+	drives, err := manager.GetDrivesList()
+	assert.Nil(t, err)
+	assert.Equal(t, driveCount, len(drives))
+	for _, drive := range drives {
+		assert.Equal(t, apiV1.DriveStatusOnline, drive.Status)
+		assert.Empty(t, drive.Path)
+	}
+
+	// serial numbers are kept after restart
+	restarted := NewLoopBackManager(mockexec, "", "", logger)
+	restarted.config = &Config{Mode: ModeMetadata}
+	restarted.createDefaultDevices(driveCount)
+	for i, device := range restarted.devices {
+		assert.Equal(t, manager.devices[i].SerialNumber, device.SerialNumber)
+	}
+}
+
+func TestLoopBackManager_InitSparseMode(t *testing.T) {
+	var mockexec = &mocks.GoMockExecutor{}
+	var manager = NewLoopBackManager(mockexec, "", "", logger)
+	manager.config = &Config{Mode: ModeSparse}
+	device := &LoopBackDevice{SerialNumber: "sparse", fileName: "/tmp/not-existing-sparse.img"}
+	device.fillEmptyFieldsWithDefaults()
+	manager.devices = []*LoopBackDevice{device}
+
+	mockexec.OnCommand(fmt.Sprintf(fs.CheckSpaceCmdImpl, rootPath)).
+		Return("Mounted on Avail\n/ 10000M", "", nil)
+	mockexec.OnCommand(fmt.Sprintf(createSparseFileCmdTmpl, 101, device.fileName)).Return("", "", nil)
+	mockexec.OnCommand(readLoopBackDevicesMappingCmd).Return("", "", nil)
+	mockexec.OnCommand(findUnusedLoopBackDeviceCmdTmpl).Return("/dev/loop0", "", nil)
+	// device node could be absent in test environment
+	mockexec.OnCommand(fmt.Sprintf(createLoopDeviceNodeCmdTmpl, "/dev/loop0", 0)).Return("", "", nil)
+	mockexec.OnCommand(fmt.Sprintf(setupLoopBackDeviceCmdTmpl, device.fileName)).Return("/dev/loop0\n", "", nil)
+
+	manager.Init()
+
+	assert.Equal(t, "/dev/loop0", manager.devices[0].devicePath)
+}
+
+func TestLoopBackManager_getMode(t *testing.T) {
+	var manager = NewLoopBackManager(&mocks.GoMockExecutor{}, "", "", logger)
+	assert.Equal(t, ModeLoop, manager.getMode())
+
+	for mode, expected := range map[string]string{
+		"": ModeLoop, ModeLoop: ModeLoop, ModeSparse: ModeSparse, ModeMetadata: ModeMetadata, "unknown": ModeLoop,
+	} {
+		manager.config = &Config{Mode: mode}
+		assert.Equal(t, expected, manager.getMode())
+	}
+}
+
+func TestLoopBackManager_ensureLoopDeviceNode(t *testing.T) {
+	var mockexec = &mocks.GoMockExecutor{}
+	var manager = NewLoopBackManager(mockexec, "", "", logger)
+
+	missing := "/dev/loop987654"
+	mockexec.OnCommand(fmt.Sprintf(createLoopDeviceNodeCmdTmpl, missing, 987654)).Return("", "", nil).Once()
+	manager.ensureLoopDeviceNode(missing)
+	mockexec.AssertExpectations(t)
+
+	// unexpected paths are ignored, mock executor fails on any command
+	manager.ensureLoopDeviceNode("/dev/sda")
+}
@@ -0,0 +1,346 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivemgr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+)
+
+// backend is the drive manager registered in Registry
+type backend struct {
+	name   string
+	client api.DriveServiceClient
+	// client of v2 API, nil if v2 isn't served by the drive manager
+	v2Client api.DriveServiceV2Client
+	// API version negotiated with the drive manager, 0 until negotiation succeeds, accessed atomically
+	version int32
+	// drives from the last successful GetDrivesList
+	drives []*api.Drive
+	// health details of the drives from the last successful GetDrivesList, key is serial number
+	health map[string]*api.DriveHealthDetails
+}
+
+// Registry holds several drive managers which serve one node, for example basemgr for SATA drives
+// and Redfish based manager for NVMe drives behind BMC. Registry implements DriveServiceClient:
+// inventories of all drive managers are merged, Locate, UpdateFirmware and CreateNamespaces
+// are routed to the owner of the drive.
+// If the same drive is reported by several drive managers (same WWN or serial number) the drive manager
+// which was registered first wins. If drive manager fails, drives from its last successful response are used,
+// otherwise they would be marked as missing by the node.
+// Drives are requested by v2 API from drive managers which negotiated it, v1 is used for the rest of them
+type Registry struct {
+	sync.RWMutex
+	backends []*backend
+	// drive key (WWN or serial number) -> name of the drive manager which owns the drive
+	owners map[string]string
+	// serial number -> health details reported by v2 API for the drive
+	health map[string]*api.DriveHealthDetails
+	log    *logrus.Entry
+}
+
+// NewRegistry is the constructor for Registry struct
+// Receives logrus logger
+// Returns an instance of Registry without drive managers
+func NewRegistry(logger *logrus.Logger) *Registry {
+	return &Registry{
+		owners: make(map[string]string),
+		health: make(map[string]*api.DriveHealthDetails),
+		log:    logger.WithField("component", "DriveMgrRegistry"),
+	}
+}
+
+// Register adds drive manager to the registry, drive managers registered earlier have higher priority
+// Receives unique name of the drive manager (endpoint could be used) and client for it
+// Returns error if drive manager with such name is already registered
+func (r *Registry) Register(name string, client api.DriveServiceClient) error {
+	return r.RegisterWithV2(name, client, nil)
+}
+
+// RegisterWithV2 adds drive manager which could serve v2 API to the registry
+// Receives unique name of the drive manager, clients of v1 and v2 API for it, v2Client could be nil
+// Returns error if drive manager with such name is already registered
+func (r *Registry) RegisterWithV2(name string, client api.DriveServiceClient, v2Client api.DriveServiceV2Client) error {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, b := range r.backends {
+		if b.name == name {
+			return fmt.Errorf("drive manager %s is already registered", name)
+		}
+	}
+	r.backends = append(r.backends, &backend{name: name, client: client, v2Client: v2Client})
+	r.log.Infof("Drive manager %s is registered", name)
+	return nil
+}
+
+// Len returns amount of registered drive managers
+func (r *Registry) Len() int {
+	r.RLock()
+	defer r.RUnlock()
+
+	return len(r.backends)
+}
+
+// GetDrivesList requests drives from all registered drive managers and merges them
+// Drive manager failure doesn't fail the whole request, error is returned only if all of them failed.
+// Drive managers are requested without holding the lock, so slow drive manager doesn't block routing of other requests
+func (r *Registry) GetDrivesList(ctx context.Context, in *api.DrivesRequest,
+	opts ...grpc.CallOption) (*api.DrivesResponse, error) {
+	ll := r.log.WithField("method", "GetDrivesList")
+
+	backends := r.snapshot()
+	if len(backends) == 0 {
+		return nil, status.Error(codes.Unavailable, "there are no registered drive managers")
+	}
+
+	var (
+		responses = make([][]*api.Drive, len(backends))
+		health    = make([]map[string]*api.DriveHealthDetails, len(backends))
+		succeeded = make([]bool, len(backends))
+		errs      = make([]string, 0)
+	)
+	for i, b := range backends {
+		drives, details, err := r.requestDrives(ctx, b, in, opts...)
+		if err != nil {
+			ll.Errorf("Failed to get drives from drive manager %s, last known drives are used: %v", b.name, err)
+			errs = append(errs, fmt.Sprintf("%s: %v", b.name, err))
+			continue
+		}
+		responses[i], health[i], succeeded[i] = drives, details, true
+	}
+	if len(errs) == len(backends) {
+		return nil, status.Error(codes.Unavailable, strings.Join(errs, "; "))
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	var (
+		drives       = make([]*api.Drive, 0)
+		owners       = make(map[string]string)
+		drivesHealth = make(map[string]*api.DriveHealthDetails)
+	)
+	for i, b := range backends {
+		if succeeded[i] {
+			b.drives, b.health = responses[i], health[i]
+		}
+		for _, drive := range b.drives {
+			if owner, ok := lookupOwner(owners, drive); ok {
+				ll.Warnf("Drive SN %s, WWN %s is reported by drive managers %s and %s, drive from %s is used",
+					drive.SerialNumber, drive.WWN, owner, b.name, owner)
+				continue
+			}
+			// Locate, UpdateFirmware and CreateNamespaces are requested by serial number
+			owners[drive.SerialNumber] = b.name
+			if drive.WWN != "" {
+				owners[drive.WWN] = b.name
+			}
+			if details, ok := b.health[drive.SerialNumber]; ok {
+				drivesHealth[drive.SerialNumber] = details
+			}
+			// cached drives are copied since caller could modify them
+			driveCopy := *drive
+			drives = append(drives, &driveCopy)
+		}
+	}
+	r.owners = owners
+	r.health = drivesHealth
+	return &api.DrivesResponse{Disks: drives}, nil
+}
+
+// requestDrives requests drives from the drive manager, v2 API is used if drive manager negotiated it.
+// API version is negotiated on the first request, drive manager is requested by v1 until negotiation succeeds
+// or if it doesn't implement v2 API
+// Returns drives and their health details by serial number, details are empty for v1 API
+func (r *Registry) requestDrives(ctx context.Context, b *backend, in *api.DrivesRequest,
+	opts ...grpc.CallOption) ([]*api.Drive, map[string]*api.DriveHealthDetails, error) {
+	ll := r.log.WithField("method", "requestDrives")
+
+	if b.v2Client != nil && atomic.LoadInt32(&b.version) == 0 {
+		if version, err := NegotiateAPIVersion(ctx, b.client); err != nil {
+			ll.Warnf("Unable to negotiate API version with drive manager %s, v1 is used: %v", b.name, err)
+		} else {
+			ll.Infof("Drive manager %s API version: v%d", b.name, version)
+			atomic.StoreInt32(&b.version, version)
+		}
+	}
+
+	if b.v2Client != nil && atomic.LoadInt32(&b.version) >= APIVersionV2 {
+		resp, err := b.v2Client.GetDrivesList(ctx, in, opts...)
+		if err == nil {
+			drives, health := drivesFromDetails(resp.GetDisks())
+			return drives, health, nil
+		}
+		if status.Code(err) != codes.Unimplemented {
+			return nil, nil, err
+		}
+		ll.Warnf("Drive manager %s doesn't implement v2 API, v1 is used", b.name)
+		atomic.StoreInt32(&b.version, APIVersionV1)
+	}
+
+	resp, err := b.client.GetDrivesList(ctx, in, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.GetDisks(), map[string]*api.DriveHealthDetails{}, nil
+}
+
+// drivesFromDetails converts v2 drive details to drives, temperature and slot info from details are used
+// if they aren't set in the drive
+// Returns drives and their health details by serial number
+func drivesFromDetails(details []*api.DriveDetails) ([]*api.Drive, map[string]*api.DriveHealthDetails) {
+	var (
+		drives = make([]*api.Drive, 0, len(details))
+		health = make(map[string]*api.DriveHealthDetails)
+	)
+	for _, d := range details {
+		drive := d.GetDrive()
+		if drive == nil {
+			continue
+		}
+		if drive.Temperature == 0 {
+			drive.Temperature = d.GetTemperature()
+		}
+		if slot := d.GetSlot(); slot != nil && drive.Slot == "" {
+			drive.Enclosure, drive.Slot, drive.Bay, drive.Backplane = slot.Enclosure, slot.Slot, slot.Bay, slot.Backplane
+		}
+		if d.GetHealth() != nil {
+			health[drive.SerialNumber] = d.GetHealth()
+		}
+		drives = append(drives, drive)
+	}
+	return drives, health
+}
+
+// HealthDetails returns health details of the drive reported by v2 API on the last GetDrivesList
+// Receives serial number of the drive
+// Returns nil if drive manager of the drive doesn't serve v2 API or didn't report details
+func (r *Registry) HealthDetails(serialNumber string) *api.DriveHealthDetails {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.health[serialNumber]
+}
+
+// Locate routes request to the drive manager which owns the drive
+func (r *Registry) Locate(ctx context.Context, in *api.DriveLocateRequest,
+	opts ...grpc.CallOption) (*api.DriveLocateResponse, error) {
+	client, err := r.ownerOf(in.GetDriveSerialNumber())
+	if err != nil {
+		return nil, err
+	}
+	return client.Locate(ctx, in, opts...)
+}
+
+// UpdateFirmware routes request to the drive manager which owns the drive
+func (r *Registry) UpdateFirmware(ctx context.Context, in *api.DriveFirmwareUpdateRequest,
+	opts ...grpc.CallOption) (*api.DriveFirmwareUpdateResponse, error) {
+	client, err := r.ownerOf(in.GetDriveSerialNumber())
+	if err != nil {
+		return nil, err
+	}
+	return client.UpdateFirmware(ctx, in, opts...)
+}
+
+// CreateNamespaces routes request to the drive manager which owns the drive
+func (r *Registry) CreateNamespaces(ctx context.Context, in *api.DriveNamespacesRequest,
+	opts ...grpc.CallOption) (*api.DriveNamespacesResponse, error) {
+	client, err := r.ownerOf(in.GetDriveSerialNumber())
+	if err != nil {
+		return nil, err
+	}
+	return client.CreateNamespaces(ctx, in, opts...)
+}
+
+// GetVersion returns the highest API version which is used with all registered drive managers,
+// negotiated versions are kept for the following GetDrivesList requests
+func (r *Registry) GetVersion(ctx context.Context, in *api.VersionRequest,
+	opts ...grpc.CallOption) (*api.VersionResponse, error) {
+	backends := r.snapshot()
+	if len(backends) == 0 {
+		return nil, status.Error(codes.Unavailable, "there are no registered drive managers")
+	}
+	var version int32
+	for _, b := range backends {
+		v, err := NegotiateAPIVersion(ctx, b.client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to negotiate API version with drive manager %s: %v", b.name, err)
+		}
+		if b.v2Client == nil {
+			// v2 API isn't requested from drive manager without v2 client
+			v = APIVersionV1
+		} else {
+			atomic.StoreInt32(&b.version, v)
+		}
+		if version == 0 || v < version {
+			version = v
+		}
+	}
+	return &api.VersionResponse{Version: version}, nil
+}
+
+// snapshot returns registered drive managers, the list could be used after the lock is released
+func (r *Registry) snapshot() []*backend {
+	r.RLock()
+	defer r.RUnlock()
+
+	backends := make([]*backend, len(r.backends))
+	copy(backends, r.backends)
+	return backends
+}
+
+// ownerOf returns client of the drive manager which reported the drive with provided serial number
+func (r *Registry) ownerOf(serialNumber string) (api.DriveServiceClient, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	// the only drive manager owns all drives
+	if len(r.backends) == 1 {
+		return r.backends[0].client, nil
+	}
+	if name, ok := r.owners[serialNumber]; ok {
+		for _, b := range r.backends {
+			if b.name == name {
+				return b.client, nil
+			}
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "drive %s isn't reported by any drive manager", serialNumber)
+}
+
+// lookupOwner returns name of the drive manager which already reported the drive, WWN is checked first
+// since serial numbers reported by different drive managers could be formatted differently
+func lookupOwner(owners map[string]string, drive *api.Drive) (string, bool) {
+	if drive.WWN != "" {
+		if owner, ok := owners[drive.WWN]; ok {
+			return owner, true
+		}
+	}
+	owner, ok := owners[drive.SerialNumber]
+	return owner, ok
+}
@@ -0,0 +1,221 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivemgr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry(logrus.New())
+	assert.Nil(t, r.Register("sata", mocks.NewMockDriveMgrClient(nil)))
+	assert.NotNil(t, r.Register("sata", mocks.NewMockDriveMgrClient(nil)))
+	assert.Equal(t, 1, r.Len())
+}
+
+func TestRegistry_GetDrivesList(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		sata     = &api.Drive{SerialNumber: "sata-1", WWN: "0x5000c500a1b2c3d4"}
+		nvme     = &api.Drive{SerialNumber: "nvme-1"}
+		nvmeDup  = &api.Drive{SerialNumber: "nvme-1"}
+		sataDup  = &api.Drive{SerialNumber: "SATA-1 ", WWN: "0x5000c500a1b2c3d4"}
+		sataMgr  = mocks.NewMockDriveMgrClient([]*api.Drive{sata, nvmeDup})
+		nvmeMgr  = mocks.NewMockDriveMgrClient([]*api.Drive{nvme, sataDup})
+		registry = NewRegistry(logrus.New())
+	)
+
+	_, err := registry.GetDrivesList(ctx, &api.DrivesRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	assert.Nil(t, registry.Register("sata", sataMgr))
+	assert.Nil(t, registry.Register("nvme", nvmeMgr))
+
+	// duplicates are resolved by serial number and WWN in favor of the first drive manager
+	resp, err := registry.GetDrivesList(ctx, &api.DrivesRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, []*api.Drive{sata, nvmeDup}, resp.Disks)
+
+	client, err := registry.ownerOf("nvme-1")
+	assert.Nil(t, err)
+	assert.Equal(t, sataMgr, client)
+	_, err = registry.ownerOf("unknown")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// last known drives are used if drive manager fails
+	failed := NewRegistry(logrus.New())
+	assert.Nil(t, failed.Register("nvme", nvmeMgr))
+	assert.Nil(t, failed.Register("fail", &mocks.MockDriveMgrClientFail{}))
+	resp, err = failed.GetDrivesList(ctx, &api.DrivesRequest{})
+	assert.Nil(t, err)
+	assert.Len(t, resp.Disks, 2)
+
+	onlyFailed := NewRegistry(logrus.New())
+	assert.Nil(t, onlyFailed.Register("fail", &mocks.MockDriveMgrClientFail{}))
+	_, err = onlyFailed.GetDrivesList(ctx, &api.DrivesRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+// blockingDriveMgrClient is a drive manager which doesn't respond to GetDrivesList until it is released
+type blockingDriveMgrClient struct {
+	*mocks.MockDriveMgrClient
+	called  chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingDriveMgrClient) GetDrivesList(ctx context.Context, in *api.DrivesRequest,
+	opts ...grpc.CallOption) (*api.DrivesResponse, error) {
+	close(c.called)
+	<-c.release
+	return c.MockDriveMgrClient.GetDrivesList(ctx, in, opts...)
+}
+
+func TestRegistry_GetDrivesListDoesNotBlock(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		registry = NewRegistry(logrus.New())
+		done     = make(chan struct{})
+	)
+	slow := &blockingDriveMgrClient{MockDriveMgrClient: mocks.NewMockDriveMgrClient(nil),
+		called: make(chan struct{}), release: make(chan struct{})}
+	assert.Nil(t, registry.Register("slow", slow))
+	go func() {
+		defer close(done)
+		_, err := registry.GetDrivesList(ctx, &api.DrivesRequest{})
+		assert.Nil(t, err)
+	}()
+	<-slow.called
+
+	// registry isn't locked while drive manager is requested
+	registered := make(chan error)
+	go func() { registered <- registry.Register("fast", mocks.NewMockDriveMgrClient(nil)) }()
+	select {
+	case err := <-registered:
+		assert.Nil(t, err)
+	case <-time.After(time.Second):
+		t.Error("registry is locked during GetDrivesList request")
+	}
+	close(slow.release)
+	<-done
+}
+
+func TestRegistry_GetVersion(t *testing.T) {
+	r := NewRegistry(logrus.New())
+	assert.Nil(t, r.Register("sata", mocks.NewMockDriveMgrClient(nil)))
+
+	version, err := NegotiateAPIVersion(context.Background(), r)
+	assert.Nil(t, err)
+	assert.Equal(t, APIVersionV1, version)
+}
+
+// v2DriveMgrClient is a drive manager which negotiates v2 API
+type v2DriveMgrClient struct {
+	*mocks.MockDriveMgrClient
+}
+
+func (c *v2DriveMgrClient) GetVersion(ctx context.Context, in *api.VersionRequest,
+	opts ...grpc.CallOption) (*api.VersionResponse, error) {
+	return &api.VersionResponse{Version: APIVersionV2}, nil
+}
+
+// driveMgrV2ClientStub is a client of v2 API which returns provided details or error
+type driveMgrV2ClientStub struct {
+	details []*api.DriveDetails
+	err     error
+	calls   int
+}
+
+func (c *driveMgrV2ClientStub) GetDrivesList(ctx context.Context, in *api.DrivesRequest,
+	opts ...grpc.CallOption) (*api.DrivesDetailsResponse, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &api.DrivesDetailsResponse{Disks: c.details}, nil
+}
+
+func TestRegistry_GetDrivesListV2(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		v1     = &api.Drive{SerialNumber: "v1-1"}
+		health = &api.DriveHealthDetails{Reason: "SMART overall-health self-assessment test failed"}
+	)
+
+	t.Run("Drives are requested by v2 API", func(t *testing.T) {
+		registry := NewRegistry(logrus.New())
+		v2Client := &driveMgrV2ClientStub{details: []*api.DriveDetails{{
+			Drive:       &api.Drive{SerialNumber: "v2-1"},
+			Health:      health,
+			Slot:        &api.DriveSlotInfo{Enclosure: "enc", Slot: "3"},
+			Temperature: 42,
+		}}}
+		assert.Nil(t, registry.RegisterWithV2("v2", &v2DriveMgrClient{mocks.NewMockDriveMgrClient(nil)}, v2Client))
+		assert.Nil(t, registry.RegisterWithV2("v1", mocks.NewMockDriveMgrClient([]*api.Drive{v1}),
+			&driveMgrV2ClientStub{}))
+
+		resp, err := registry.GetDrivesList(ctx, &api.DrivesRequest{})
+		assert.Nil(t, err)
+		assert.Len(t, resp.Disks, 2)
+		assert.Equal(t, "v2-1", resp.Disks[0].SerialNumber)
+		assert.Equal(t, int32(42), resp.Disks[0].Temperature)
+		assert.Equal(t, "3", resp.Disks[0].Slot)
+		assert.Equal(t, v1.SerialNumber, resp.Disks[1].SerialNumber)
+		assert.Equal(t, health, registry.HealthDetails("v2-1"))
+		assert.Nil(t, registry.HealthDetails(v1.SerialNumber))
+		assert.Equal(t, 1, v2Client.calls)
+
+		version, err := NegotiateAPIVersion(ctx, registry)
+		assert.Nil(t, err)
+		assert.Equal(t, APIVersionV1, version)
+	})
+
+	t.Run("v1 is used if v2 isn't implemented", func(t *testing.T) {
+		registry := NewRegistry(logrus.New())
+		v2Client := &driveMgrV2ClientStub{err: status.Error(codes.Unimplemented, "unknown service")}
+		assert.Nil(t, registry.RegisterWithV2("v2",
+			&v2DriveMgrClient{mocks.NewMockDriveMgrClient([]*api.Drive{v1})}, v2Client))
+
+		for i := 0; i < 2; i++ {
+			resp, err := registry.GetDrivesList(ctx, &api.DrivesRequest{})
+			assert.Nil(t, err)
+			assert.Equal(t, []*api.Drive{v1}, resp.Disks)
+		}
+		// v2 API isn't requested again
+		assert.Equal(t, 1, v2Client.calls)
+	})
+
+	t.Run("v2 API failure isn't hidden by v1", func(t *testing.T) {
+		registry := NewRegistry(logrus.New())
+		v2Client := &driveMgrV2ClientStub{err: status.Error(codes.Internal, "discovery failed")}
+		assert.Nil(t, registry.RegisterWithV2("v2",
+			&v2DriveMgrClient{mocks.NewMockDriveMgrClient([]*api.Drive{v1})}, v2Client))
+
+		_, err := registry.GetDrivesList(ctx, &api.DrivesRequest{})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivemgr
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+)
+
+// Versions of drive manager gRPC API
+const (
+	// APIVersionV1 is served by DriveService, drive managers without GetVersion method support only it
+	APIVersionV1 int32 = 1
+	// APIVersionV2 is served by DriveServiceV2 together with v1, it extends drives with health, slot and temperature
+	APIVersionV2 int32 = 2
+)
+
+// SupportedAPIVersions contains API versions which are supported by current build
+var SupportedAPIVersions = []int32{APIVersionV1, APIVersionV2}
+
+// chooseAPIVersion returns the highest version which is supported by both sides, v1 is used if there is no such version
+func chooseAPIVersion(clientVersions []int32) int32 {
+	chosen := APIVersionV1
+	for _, cv := range clientVersions {
+		for _, sv := range SupportedAPIVersions {
+			if cv == sv && cv > chosen {
+				chosen = cv
+			}
+		}
+	}
+	return chosen
+}
+
+// NegotiateAPIVersion requests API version which should be used for communication with drive manager
+// Receives golang context and DriveServiceClient
+// Returns negotiated API version, v1 if drive manager doesn't support negotiation, or error if request failed
+func NegotiateAPIVersion(ctx context.Context, client api.DriveServiceClient) (int32, error) {
+	resp, err := client.GetVersion(ctx, &api.VersionRequest{Supported: SupportedAPIVersions})
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return APIVersionV1, nil
+		}
+		return 0, err
+	}
+	return resp.GetVersion(), nil
+}
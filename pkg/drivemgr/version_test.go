@@ -0,0 +1,79 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivemgr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+type driveManagerStub struct {
+	drives []*api.Drive
+}
+
+func (d *driveManagerStub) GetDrivesList() ([]*api.Drive, error) {
+	return d.drives, nil
+}
+
+func (d *driveManagerStub) Locate(serialNumber string, action int32) (int32, error) {
+	return 0, nil
+}
+
+func (d *driveManagerStub) UpdateFirmware(serialNumber string, image string) (string, error) {
+	return "", nil
+}
+
+func (d *driveManagerStub) CreateNamespaces(serialNumber string, count int32) ([]string, error) {
+	return nil, nil
+}
+
+func Test_chooseAPIVersion(t *testing.T) {
+	assert.Equal(t, APIVersionV2, chooseAPIVersion([]int32{APIVersionV1, APIVersionV2}))
+	assert.Equal(t, APIVersionV1, chooseAPIVersion([]int32{APIVersionV1}))
+	assert.Equal(t, APIVersionV2, chooseAPIVersion([]int32{APIVersionV2, 100}))
+	assert.Equal(t, APIVersionV1, chooseAPIVersion(nil))
+}
+
+func TestNegotiateAPIVersion(t *testing.T) {
+	// drive manager without GetVersion method
+	version, err := NegotiateAPIVersion(context.Background(), mocks.NewMockDriveMgrClient(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, APIVersionV1, version)
+
+	version, err = NegotiateAPIVersion(context.Background(), &mocks.MockDriveMgrClientFail{})
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(0), version)
+}
+
+func TestDriveServiceV2ServerImpl_GetDrivesList(t *testing.T) {
+	drive := &api.Drive{SerialNumber: "sn-1", Enclosure: "enc", Slot: "1", Bay: "2", Backplane: "bp"}
+	svc := NewDriveServerV2(logrus.New(), &driveManagerStub{drives: []*api.Drive{drive}})
+
+	resp, err := svc.GetDrivesList(context.Background(), &api.DrivesRequest{NodeId: "node-1"})
+	assert.Nil(t, err)
+	assert.Len(t, resp.Disks, 1)
+	assert.Equal(t, "node-1", resp.Disks[0].Drive.NodeId)
+	assert.Equal(t, apiV1.DriveStatusOnline, resp.Disks[0].Drive.Status)
+	assert.Equal(t, &api.DriveSlotInfo{Enclosure: "enc", Slot: "1", Bay: "2", Backplane: "bp"}, resp.Disks[0].Slot)
+}
@@ -26,11 +26,24 @@ const (
 
 // Volume event reason list
 const (
-	VolumeDiscovered    = "VolumeDiscovered"
-	VolumeBadHealth     = "VolumeBadHealth"
-	VolumeUnknownHealth = "VolumeUnknownHealth"
-	VolumeGoodHealth    = "VolumeGoodHealth"
-	VolumeSuspectHealth = "VolumeSuspectHealth"
+	VolumeDiscovered     = "VolumeDiscovered"
+	VolumeBadHealth      = "VolumeBadHealth"
+	VolumeUnknownHealth  = "VolumeUnknownHealth"
+	VolumeGoodHealth     = "VolumeGoodHealth"
+	VolumeSuspectHealth  = "VolumeSuspectHealth"
+	VolumeIntegrityError = "VolumeIntegrityError"
+	VolumeOnDiskMissing  = "VolumeOnDiskMissing"
+	VolumeFsckCompleted  = "VolumeFsckCompleted"
+	VolumeFsckFailed     = "VolumeFsckFailed"
+	VolumeFSMismatch     = "VolumeFSMismatch"
+	VolumeReformatted    = "VolumeReformatted"
+	VolumeImported       = "VolumeImported"
+	VolumeImportFailed   = "VolumeImportFailed"
+	VolumeExported       = "VolumeExported"
+	VolumeExportFailed   = "VolumeExportFailed"
+	VolumePrewarmFailed  = "VolumePrewarmFailed"
+	VolumeIOTuningFailed = "VolumeIOTuningFailed"
+	StorageQuotaExceeded = "StorageQuotaExceeded"
 
 	DriveDiscovered           = "DriveDiscovered"
 	DriveHealthSuspect        = "DriveHealthSuspect"
@@ -42,4 +55,23 @@ const (
 	DriveReplacementFailed    = "DriveReplacementFailed"
 	DriveReadyForReplacement  = "DriveReadyForReplacement"
 	DriveSuccessfullyReplaced = "DriveSuccessfullyReplaced"
+	DriveFirmwareUpdated      = "DriveFirmwareUpdated"
+	DriveFirmwareUpdateFailed = "DriveFirmwareUpdateFailed"
+	DriveNamespacesCreated    = "DriveNamespacesCreated"
+	DriveNamespacesFailed     = "DriveNamespacesFailed"
+	DriveHotSparePromoted     = "DriveHotSparePromoted"
+	DriveTemperatureHigh      = "DriveTemperatureHigh"
+	DriveTemperatureNormal    = "DriveTemperatureNormal"
+	DriveIOErrorsHigh         = "DriveIOErrorsHigh"
+	DriveIOErrorsNormal       = "DriveIOErrorsNormal"
+	DriveSELEvent             = "DriveSELEvent"
+	DriveEvacuated            = "DriveEvacuated"
+	DriveEvacuationFailed     = "DriveEvacuationFailed"
+	DriveNotClean             = "DriveNotClean"
+	DriveCleared              = "DriveCleared"
+	DriveEraseFailed          = "DriveEraseFailed"
+
+	LVGExpanded        = "LVGExpanded"
+	LVGExpansionFailed = "LVGExpansionFailed"
+	LVGReduced         = "LVGReduced"
 )
@@ -0,0 +1,42 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/dell/csi-baremetal/pkg/metrics"
+)
+
+// ExtenderFilterDuration used to collect durations of filter requests of scheduler extender
+var ExtenderFilterDuration = metrics.NewMetrics(prometheus.HistogramOpts{
+	Name:    "extender_filter_duration_seconds",
+	Help:    "duration of the scheduler extender filter request",
+	Buckets: metrics.ExtendedDefBuckets,
+})
+
+// ExtenderFilteredOutNodes used to count nodes filtered out by scheduler extender by reason
+var ExtenderFilteredOutNodes = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "extender_filtered_out_nodes_total",
+	Help: "amount of nodes filtered out by scheduler extender",
+}, []string{"reason"})
+
+// nolint: gochecknoinits
+func init() {
+	prometheus.MustRegister(ExtenderFilterDuration.Collect())
+	prometheus.MustRegister(ExtenderFilteredOutNodes)
+}
@@ -29,7 +29,15 @@ var KubeclientDuration = metrics.NewMetrics(prometheus.HistogramOpts{
 	Buckets: prometheus.DefBuckets,
 }, "method")
 
+// KubeclientThrottleDuration used to collect time which k8s api calls wait for client-side rate limiter
+var KubeclientThrottleDuration = metrics.NewMetrics(prometheus.HistogramOpts{
+	Name:    "kubeclient_throttle_duration_seconds",
+	Help:    "time which k8s api calls wait for client-side rate limiter",
+	Buckets: prometheus.DefBuckets,
+})
+
 // nolint: gochecknoinits
 func init() {
 	prometheus.MustRegister(KubeclientDuration.Collect())
+	prometheus.MustRegister(KubeclientThrottleDuration.Collect())
 }
@@ -0,0 +1,42 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/dell/csi-baremetal/pkg/metrics"
+)
+
+// LVMLockWaitDuration used to collect durations of waiting for the VG lock before VG mutation,
+// "type" label is a type of the mutation (lvcreate, lvremove, etc.)
+var LVMLockWaitDuration = metrics.NewMetrics(prometheus.HistogramOpts{
+	Name:    "lvm_lock_wait_duration_seconds",
+	Help:    "Duration of waiting for the VG lock before VG mutation",
+	Buckets: metrics.ExtendedDefBuckets,
+}, "type")
+
+// LVMLockQueue is an amount of VG mutations which are waiting for the VG lock
+var LVMLockQueue = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "lvm_lock_queue_length",
+	Help: "Amount of VG mutations which are waiting for the VG lock",
+})
+
+// nolint: gochecknoinits
+func init() {
+	prometheus.MustRegister(LVMLockWaitDuration.Collect(), LVMLockQueue)
+}
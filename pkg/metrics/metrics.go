@@ -18,10 +18,12 @@ limitations under the License.
 package metrics
 
 import (
+	"net/http"
 	"time"
 
-	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var (
@@ -31,15 +33,12 @@ var (
 	Revision string
 )
 
-// BuildInfo used to expose information of the current build
-var BuildInfo = prometheus.NewGaugeFunc(
-	prometheus.GaugeOpts{
-		Name:        "build_info",
-		Help:        "A metric with a constant '1' value labeled by version, revision, branch",
-		ConstLabels: prometheus.Labels{"version": base.PluginVersion, "revision": Revision, "branch": Branch},
-	},
-	func() float64 { return 1 },
-)
+// Handler returns HTTP handler which exposes CSI metrics together with metrics of k8s API client
+// (rest_client_request_latency_seconds and rest_client_requests_total) collected by controller-runtime
+func Handler() http.Handler {
+	return promhttp.HandlerFor(prometheus.Gatherers{prometheus.DefaultGatherer, ctrlmetrics.Registry},
+		promhttp.HandlerOpts{})
+}
 
 // Statistic is a common interface for histogram metrics
 type Statistic interface {
@@ -81,6 +80,11 @@ func (m *Metrics) EvaluateDurationForType(t string) func() {
 	return m.EvaluateDuration(prometheus.Labels{"type": t})
 }
 
+// EvaluateDurationForPhase evaluate duration of the volume provisioning phase with "phase" label
+func (m *Metrics) EvaluateDurationForPhase(phase string) func() {
+	return m.EvaluateDuration(prometheus.Labels{"phase": phase})
+}
+
 // Collect returns prometheus.Collector slice with OperationsDuration histogram
 func (m *Metrics) Collect() prometheus.Collector {
 	return m.OperationsDuration
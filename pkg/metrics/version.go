@@ -0,0 +1,99 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// VersionInfo describes build of the running component together with API versions it works with.
+// It is exposed through GetPluginInfo manifest, /version endpoint and build_info metric
+type VersionInfo struct {
+	Version            string `json:"version"`
+	Revision           string `json:"revision"`
+	Branch             string `json:"branch"`
+	CRDVersion         string `json:"crdVersion"`
+	DriveMgrAPIVersion string `json:"driveMgrAPIVersion,omitempty"`
+}
+
+// NewVersionInfo returns VersionInfo of the current build, DriveMgrAPIVersion is left empty
+func NewVersionInfo() *VersionInfo {
+	return &VersionInfo{
+		Version:    base.PluginVersion,
+		Revision:   Revision,
+		Branch:     Branch,
+		CRDVersion: apiV1.APIV1Version,
+	}
+}
+
+// SetDriveMgrAPIVersion sets API version negotiated with connected drive managers
+func (v *VersionInfo) SetDriveMgrAPIVersion(version int32) {
+	v.DriveMgrAPIVersion = "v" + strconv.Itoa(int(version))
+}
+
+// Manifest returns version information as GetPluginInfo manifest, empty values are omitted
+func (v *VersionInfo) Manifest() map[string]string {
+	manifest := map[string]string{}
+	for key, value := range map[string]string{
+		"revision":             v.Revision,
+		"branch":               v.Branch,
+		"crd-version":          v.CRDVersion,
+		"drivemgr-api-version": v.DriveMgrAPIVersion,
+	} {
+		if value != "" {
+			manifest[key] = value
+		}
+	}
+	return manifest
+}
+
+// Collector returns build_info constant gauge labeled by version information
+func (v *VersionInfo) Collector() prometheus.Collector {
+	return prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "build_info",
+			Help: "A metric with a constant '1' value labeled by version, revision, branch, crd_version " +
+				"and drivemgr_api_version",
+			ConstLabels: prometheus.Labels{
+				"version":              v.Version,
+				"revision":             v.Revision,
+				"branch":               v.Branch,
+				"crd_version":          v.CRDVersion,
+				"drivemgr_api_version": v.DriveMgrAPIVersion,
+			},
+		},
+		func() float64 { return 1 },
+	)
+}
+
+// Handler returns HTTP handler which responds with version information in JSON
+func (v *VersionInfo) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "only GET is allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	})
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+func TestVersionInfo_Manifest(t *testing.T) {
+	info := NewVersionInfo()
+	info.Revision = "abc123"
+	info.Branch = ""
+
+	manifest := info.Manifest()
+	assert.Equal(t, "abc123", manifest["revision"])
+	assert.Equal(t, apiV1.APIV1Version, manifest["crd-version"])
+	_, ok := manifest["branch"]
+	assert.False(t, ok)
+	_, ok = manifest["drivemgr-api-version"]
+	assert.False(t, ok)
+
+	info.SetDriveMgrAPIVersion(2)
+	assert.Equal(t, "v2", info.Manifest()["drivemgr-api-version"])
+}
+
+func TestVersionInfo_Handler(t *testing.T) {
+	info := NewVersionInfo()
+	info.SetDriveMgrAPIVersion(1)
+
+	rec := httptest.NewRecorder()
+	info.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var got VersionInfo
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, *info, got)
+
+	rec = httptest.NewRecorder()
+	info.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/version", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestVersionInfo_Collector(t *testing.T) {
+	info := NewVersionInfo()
+	info.SetDriveMgrAPIVersion(2)
+
+	registry := prometheus.NewRegistry()
+	assert.Nil(t, registry.Register(info.Collector()))
+
+	families, err := registry.Gather()
+	assert.Nil(t, err)
+	assert.Len(t, families, 1)
+	assert.Equal(t, "build_info", families[0].GetName())
+	labels := map[string]string{}
+	for _, l := range families[0].GetMetric()[0].GetLabel() {
+		labels[l.GetName()] = l.GetValue()
+	}
+	assert.Equal(t, "v2", labels["drivemgr_api_version"])
+	assert.Equal(t, apiV1.APIV1Version, labels["crd_version"])
+}
@@ -47,6 +47,21 @@ func (m *MockDriveMgrClientFail) Locate(ctx context.Context, in *api.DriveLocate
 	return nil, errors.New("locate failed")
 }
 
+// UpdateFirmware is a stub for UpdateFirmware DriveManager's method
+func (m *MockDriveMgrClientFail) UpdateFirmware(ctx context.Context, in *api.DriveFirmwareUpdateRequest, opts ...grpc.CallOption) (*api.DriveFirmwareUpdateResponse, error) {
+	return nil, errors.New("firmware update failed")
+}
+
+// CreateNamespaces is a stub for CreateNamespaces DriveManager's method
+func (m *MockDriveMgrClientFail) CreateNamespaces(ctx context.Context, in *api.DriveNamespacesRequest, opts ...grpc.CallOption) (*api.DriveNamespacesResponse, error) {
+	return nil, errors.New("namespaces creation failed")
+}
+
+// GetVersion is the simulation of failure during DriveManager's GetVersion
+func (m *MockDriveMgrClientFail) GetVersion(ctx context.Context, in *api.VersionRequest, opts ...grpc.CallOption) (*api.VersionResponse, error) {
+	return nil, errors.New("drivemgr error")
+}
+
 // NewMockDriveMgrClient returns new instance of MockDriveMgrClient
 // Receives slice of api.Drive which would be used in imitation of GetDrivesList
 func NewMockDriveMgrClient(drives []*api.Drive) *MockDriveMgrClient {
@@ -76,3 +91,18 @@ func (m *MockDriveMgrClient) GetDrivesList(ctx context.Context, in *api.DrivesRe
 func (m *MockDriveMgrClient) Locate(ctx context.Context, in *api.DriveLocateRequest, opts ...grpc.CallOption) (*api.DriveLocateResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Locate not implemented in MockDriveMgrClient")
 }
+
+// UpdateFirmware is a stub for UpdateFirmware DriveManager's method
+func (m *MockDriveMgrClient) UpdateFirmware(ctx context.Context, in *api.DriveFirmwareUpdateRequest, opts ...grpc.CallOption) (*api.DriveFirmwareUpdateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateFirmware not implemented in MockDriveMgrClient")
+}
+
+// CreateNamespaces is a stub for CreateNamespaces DriveManager's method
+func (m *MockDriveMgrClient) CreateNamespaces(ctx context.Context, in *api.DriveNamespacesRequest, opts ...grpc.CallOption) (*api.DriveNamespacesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateNamespaces not implemented in MockDriveMgrClient")
+}
+
+// GetVersion is a stub for GetVersion DriveManager's method, imitates drive manager which supports only v1 API
+func (m *MockDriveMgrClient) GetVersion(ctx context.Context, in *api.VersionRequest, opts ...grpc.CallOption) (*api.VersionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetVersion not implemented in MockDriveMgrClient")
+}
@@ -19,6 +19,7 @@ package mocks
 import (
 	"errors"
 	"fmt"
+	"os/exec"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -181,8 +182,13 @@ func (g *GoMockExecutor) RunCmdWithAttempts(cmd interface{}, attempts int, timeo
 	return args.String(0), args.String(1), args.Error(2)
 }
 
-// RunCmd simulates execution of a command with OnCommand where user can set what the method should return
+// RunCmd simulates execution of a command with OnCommand where user can set what the method should return,
+// command which is passed as exec.Cmd is matched by its arguments which are set with OnCommandArgs
 func (g *GoMockExecutor) RunCmd(cmd interface{}, opts ...command.Options) (string, string, error) {
+	if cmdObj, ok := cmd.(*exec.Cmd); ok {
+		args := g.Mock.Called(cmdObj.Args)
+		return args.String(0), args.String(1), args.Error(2)
+	}
 	args := g.Mock.Called(cmd.(string))
 	return args.String(0), args.String(1), args.Error(2)
 }
@@ -194,6 +200,13 @@ func (g *GoMockExecutor) OnCommand(cmd string) *mock.Call {
 	return g.On(RunCmd, cmd)
 }
 
+// OnCommandArgs is the method of mock.Mock where user can set what to return on command passed as exec.Cmd
+// For example e.OnCommandArgs("fwtool", "/dev/sda", "/tmp/fw image.bin").Return("", "", nil)
+// Returns mock.Call where need to set what to return with Return() method
+func (g *GoMockExecutor) OnCommandArgs(args ...string) *mock.Call {
+	return g.On(RunCmd, args)
+}
+
 // OnCommandWithAttempts is the method of mock.Mock where user can set what to return on specified command
 // For example e.OnCommandWithAttempts("/sbin/lvm pvcreate --yes /dev/sda", 5, time.Second).Return("", "", errors.New("pvcreate failed"))
 // Returns mock.Call where need to set what to return with Return() method
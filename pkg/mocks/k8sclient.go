@@ -68,6 +68,24 @@ func (k *K8Client) Patch(ctx context.Context, obj runtime.Object, patch client.P
 	return args.Error(0)
 }
 
+// Status returns StatusWriter which doesn't do anything, status of CRs isn't checked with K8Client
+func (k *K8Client) Status() client.StatusWriter {
+	return noOpStatusWriter{}
+}
+
+// noOpStatusWriter is the implementation of client.StatusWriter which doesn't do anything
+type noOpStatusWriter struct{}
+
+// Update does nothing
+func (noOpStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	return nil
+}
+
+// Patch does nothing
+func (noOpStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return nil
+}
+
 // DeleteAllOf is mock implementation of DeleteAllOf method from client.Writer interface
 func (k *K8Client) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...client.DeleteAllOfOption) error {
 	args := k.Mock.Called(ctx, obj, opts)
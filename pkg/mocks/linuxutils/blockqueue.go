@@ -0,0 +1,42 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linuxutils
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockqueue"
+)
+
+// MockWrapBlockQueue is a mock implementation of WrapBlockQueue interface from blockqueue package
+type MockWrapBlockQueue struct {
+	mock.Mock
+}
+
+// Apply is a mock implementations
+func (m *MockWrapBlockQueue) Apply(devicePath string, settings blockqueue.Settings) error {
+	args := m.Mock.Called(devicePath, settings)
+
+	return args.Error(0)
+}
+
+// Geometry is a mock implementations
+func (m *MockWrapBlockQueue) Geometry(devicePath string) (blockqueue.Geometry, error) {
+	args := m.Mock.Called(devicePath)
+
+	return args.Get(0).(blockqueue.Geometry), args.Error(1)
+}
@@ -55,8 +55,11 @@ func (m *MockWrapFS) RmDir(src string) error {
 	return args.Error(0)
 }
 
-// CreateFS is a mock implementations
-func (m *MockWrapFS) CreateFS(fsType fs.FileSystem, device string) error {
+// CreateFS is a mock implementations, options are passed to the mock only if they are set
+func (m *MockWrapFS) CreateFS(fsType fs.FileSystem, device string, opts ...string) error {
+	if len(opts) > 0 {
+		return m.Mock.Called(fsType, device, opts).Error(0)
+	}
 	args := m.Mock.Called(fsType, device)
 
 	return args.Error(0)
@@ -103,3 +106,10 @@ func (m *MockWrapFS) Unmount(src string) error {
 
 	return args.Error(0)
 }
+
+// CheckFS is a mock implementations
+func (m *MockWrapFS) CheckFS(fsType fs.FileSystem, device string, repair bool, opts string) (fs.FSCheckResult, error) {
+	args := m.Mock.Called(fsType, device, repair, opts)
+
+	return args.Get(0).(fs.FSCheckResult), args.Error(1)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linuxutils
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWrapIntegrity is a mock implementation of WrapIntegrity interface from integrity package
+type MockWrapIntegrity struct {
+	mock.Mock
+}
+
+// Format is a mock implementations
+func (m *MockWrapIntegrity) Format(device string) error {
+	args := m.Mock.Called(device)
+
+	return args.Error(0)
+}
+
+// Open is a mock implementations
+func (m *MockWrapIntegrity) Open(device, name string) error {
+	args := m.Mock.Called(device, name)
+
+	return args.Error(0)
+}
+
+// Close is a mock implementations
+func (m *MockWrapIntegrity) Close(name string) error {
+	args := m.Mock.Called(name)
+
+	return args.Error(0)
+}
+
+// IsOpened is a mock implementations
+func (m *MockWrapIntegrity) IsOpened(name string) (bool, error) {
+	args := m.Mock.Called(name)
+
+	return args.Bool(0), args.Error(1)
+}
+
+// GetMismatches is a mock implementations
+func (m *MockWrapIntegrity) GetMismatches(name string) (int64, error) {
+	args := m.Mock.Called(name)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// CreateChecksumFS is a mock implementations, options are passed to the mock only if they are set
+func (m *MockWrapIntegrity) CreateChecksumFS(device string, opts ...string) error {
+	if len(opts) > 0 {
+		return m.Mock.Called(device, opts).Error(0)
+	}
+	args := m.Mock.Called(device)
+
+	return args.Error(0)
+}
+
+// GetFSErrorCount is a mock implementations
+func (m *MockWrapIntegrity) GetFSErrorCount(device string) (int64, error) {
+	args := m.Mock.Called(device)
+
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,42 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linuxutils
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWrapIOErrors is a mock implementation of WrapIOErrors interface from ioerrors package
+type MockWrapIOErrors struct {
+	mock.Mock
+}
+
+// GetErrorCount is a mock implementations
+func (m *MockWrapIOErrors) GetErrorCount(devicePath string) (int64, error) {
+	args := m.Mock.Called(devicePath)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// TailKernelLog is a mock implementations
+func (m *MockWrapIOErrors) TailKernelLog(ctx context.Context) error {
+	args := m.Mock.Called(ctx)
+
+	return args.Error(0)
+}
@@ -60,6 +60,27 @@ func (m *MockWrapLVM) VGRemove(name string) error {
 	return args.Error(0)
 }
 
+// VGExtend is a mock implementations
+func (m *MockWrapLVM) VGExtend(name string, pvs ...string) error {
+	args := m.Mock.Called(name, pvs)
+
+	return args.Error(0)
+}
+
+// VGReduce is a mock implementations
+func (m *MockWrapLVM) VGReduce(name string, pvs ...string) error {
+	args := m.Mock.Called(name, pvs)
+
+	return args.Error(0)
+}
+
+// PVMove is a mock implementations
+func (m *MockWrapLVM) PVMove(name string) error {
+	args := m.Mock.Called(name)
+
+	return args.Error(0)
+}
+
 // LVCreate is a mock implementations
 func (m *MockWrapLVM) LVCreate(name, size, vgName string) error {
 	args := m.Mock.Called(name, size, vgName)
@@ -106,6 +127,17 @@ func (m *MockWrapLVM) GetLVsInVG(vgName string) ([]string, error) {
 	return args.Get(0).([]string), args.Error(1)
 }
 
+// GetPVsInVG is a mock implementations
+func (m *MockWrapLVM) GetPVsInVG(vgName string) ([]string, error) {
+	args := m.Mock.Called(vgName)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
 // GetAllPVs is a mock implementations
 func (m *MockWrapLVM) GetAllPVs() ([]string, error) {
 	args := m.Mock.Called()
@@ -33,3 +33,10 @@ func (m *MockWrapNvmecli) GetNVMDevices() ([]nvmecli.NVMDevice, error) {
 
 	return args.Get(0).([]nvmecli.NVMDevice), args.Error(1)
 }
+
+// CreateNamespaces is a mock implementations
+func (m *MockWrapNvmecli) CreateNamespaces(devices []nvmecli.NVMDevice, count int) ([]int, error) {
+	args := m.Mock.Called(devices, count)
+
+	return args.Get(0).([]int), args.Error(1)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package linuxutils
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
 
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/smartctl"
@@ -28,8 +30,8 @@ type MockWrapSmartctl struct {
 }
 
 // GetDriveInfoByPath is a mock implementations
-func (m *MockWrapSmartctl) GetDriveInfoByPath(path string) (*smartctl.DeviceSMARTInfo, error) {
-	args := m.Mock.Called(path)
+func (m *MockWrapSmartctl) GetDriveInfoByPath(ctx context.Context, path string) (*smartctl.DeviceSMARTInfo, error) {
+	args := m.Mock.Called(ctx, path)
 
 	return args.Get(0).(*smartctl.DeviceSMARTInfo), args.Error(1)
 }
@@ -24,8 +24,13 @@ type MockFsOpts struct {
 }
 
 // PrepareAndPerformMount is a mock implementation
-func (m *MockFsOpts) PrepareAndPerformMount(src, dst string, bindMount, dstIsDir bool) error {
-	args := m.Mock.Called(src, dst, bindMount, dstIsDir)
+// Mount options are passed to the mock only if they are provided
+func (m *MockFsOpts) PrepareAndPerformMount(src, dst string, bindMount, dstIsDir bool, opts ...string) error {
+	callArgs := []interface{}{src, dst, bindMount, dstIsDir}
+	for _, opt := range opts {
+		callArgs = append(callArgs, opt)
+	}
+	args := m.Mock.Called(callArgs...)
 
 	return args.Error(0)
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package provisioners
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
@@ -34,27 +36,43 @@ func GetMockProvisionerSuccess(everytimePath string) *MockProvisioner {
 	mp.On("PrepareVolume", mock.Anything).Return(nil)
 	mp.On("ReleaseVolume", mock.Anything).Return(nil)
 	mp.On("GetVolumePath", mock.Anything).Return(everytimePath, nil)
+	mp.On("ReformatVolume", mock.Anything).Return(nil)
+	mp.On("VolumeExists", mock.Anything).Return(true, nil)
 
 	return &mp
 }
 
 // PrepareVolume is the mock implementation of PrepareVolume method from Provisioner interface
-func (m *MockProvisioner) PrepareVolume(volume api.Volume) error {
+func (m *MockProvisioner) PrepareVolume(ctx context.Context, volume api.Volume) error {
 	args := m.Mock.Called(volume)
 
 	return args.Error(0)
 }
 
 // ReleaseVolume is the mock implementation of ReleaseVolume method from Provisioner interface
-func (m *MockProvisioner) ReleaseVolume(volume api.Volume) error {
+func (m *MockProvisioner) ReleaseVolume(ctx context.Context, volume api.Volume) error {
 	args := m.Mock.Called(volume)
 
 	return args.Error(0)
 }
 
 // GetVolumePath is the mock implementation of GetVolumePath method from Provisioner interface
-func (m *MockProvisioner) GetVolumePath(volume api.Volume) (string, error) {
+func (m *MockProvisioner) GetVolumePath(ctx context.Context, volume api.Volume) (string, error) {
 	args := m.Mock.Called(volume)
 
 	return args.String(0), args.Error(1)
 }
+
+// ReformatVolume is the mock implementation of ReformatVolume method from Provisioner interface
+func (m *MockProvisioner) ReformatVolume(ctx context.Context, volume api.Volume) error {
+	args := m.Mock.Called(volume)
+
+	return args.Error(0)
+}
+
+// VolumeExists is the mock implementation of VolumeExists method from Provisioner interface
+func (m *MockProvisioner) VolumeExists(ctx context.Context, volume api.Volume) (bool, error) {
+	args := m.Mock.Called(volume)
+
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// partitionDeviceType is the type of partition in lsblk output
+const partitionDeviceType = "part"
+
+// findDriveData inspects block device of the drive and describes data found on it:
+// partition table, partitions, file system, RAID or LVM member signatures and devices on top of the drive
+// Receives path of the drive
+// Returns description of found data, empty string means that drive is clean, or error if drive can't be inspected
+func (m *VolumeManager) findDriveData(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("path of the drive is unknown")
+	}
+	devices, err := m.listBlk.GetBlockDevices(path)
+	if err != nil {
+		return "", err
+	}
+	if len(devices) == 0 {
+		return "", fmt.Errorf("block device %s not found", path)
+	}
+	return describeDeviceData(devices[0]), nil
+}
+
+// describeDeviceData returns description of data found on the block device by lsblk, empty if there is no data
+func describeDeviceData(device lsblk.BlockDevice) string {
+	var found []string
+	if device.PTType != "" {
+		found = append(found, device.PTType+" partition table")
+	}
+	if device.FSType != "" {
+		found = append(found, device.FSType+" signature")
+	}
+	for _, child := range device.Children {
+		found = append(found, fmt.Sprintf("%s %s", child.Type, child.Name))
+	}
+	return strings.Join(found, ", ")
+}
+
+// clearDrive checks whether drive with NOT_CLEAN usage could be used for allocation. Drive is cleared if its data was
+// removed by operator or if operator confirmed data erase with erase-data annotation which value is equal to drive
+// serial number, partitions and signatures on the drive are wiped by node in that case.
+// Usage of the cleared drive is changed to IN_USE
+// Receives golang context and Drive CR with NOT_CLEAN usage
+// Returns true if drive was cleared
+func (m *VolumeManager) clearDrive(ctx context.Context, drive *drivecrd.Drive) bool {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":    "clearDrive",
+		"driveUUID": drive.Spec.UUID,
+	})
+
+	confirmation, eraseRequested := drive.Annotations[apiV1.DriveAnnotationEraseData]
+	if eraseRequested {
+		if confirmation != drive.Spec.SerialNumber {
+			ll.Warnf("Value of %s annotation %s doesn't match drive serial number, data isn't erased",
+				apiV1.DriveAnnotationEraseData, confirmation)
+			return false
+		}
+		if err := m.eraseDriveData(drive); err != nil {
+			ll.Errorf("Unable to erase data on drive: %v", err)
+			m.sendEventForDrive(drive, eventing.ErrorType, eventing.DriveEraseFailed,
+				"Failed to erase data on drive: %v. ", err)
+			return false
+		}
+		delete(drive.Annotations, apiV1.DriveAnnotationEraseData)
+	}
+
+	data, err := m.findDriveData(drive.Spec.Path)
+	switch {
+	case err != nil:
+		ll.Errorf("Unable to inspect drive: %v", err)
+	case data != "":
+		ll.Debugf("Drive isn't clean: %s", data)
+	default:
+		ll.Infof("Drive is clean, changing usage to %s", apiV1.DriveUsageInUse)
+		drive.Spec.Usage = apiV1.DriveUsageInUse
+		drive.RefreshStatus(drivecrd.ReasonUsageChanged, metav1.Now())
+	}
+
+	cleared := drive.Spec.Usage == apiV1.DriveUsageInUse
+	// drive CR is updated if annotation was removed or usage was changed
+	if eraseRequested || cleared {
+		if err = m.k8sClient.UpdateCR(ctx, drive); err != nil {
+			ll.Errorf("Unable to update drive CR: %v", err)
+			return false
+		}
+	}
+	if cleared {
+		m.sendEventForDrive(drive, eventing.NormalType, eventing.DriveCleared,
+			"Drive is clean and is used for allocation. ")
+	}
+	return cleared
+}
+
+// eraseDriveData wipes signatures on the partitions of the drive and on the drive itself, operations are recorded
+// in audit log. Devices on top of the drive (e.g. RAID or LVM) aren't removed, they should be released by operator
+func (m *VolumeManager) eraseDriveData(drive *drivecrd.Drive) error {
+	if drive.Spec.Path == "" {
+		return errors.New("path of the drive is unknown")
+	}
+	devices, err := m.listBlk.GetBlockDevices(drive.Spec.Path)
+	if err != nil {
+		return err
+	}
+	var partitions []string
+	for _, device := range devices {
+		for _, child := range device.Children {
+			if child.Type != partitionDeviceType {
+				return fmt.Errorf("%s %s is on top of the drive, it should be released first", child.Type, child.Name)
+			}
+			partitions = append(partitions, child.Name)
+		}
+	}
+	for _, device := range append(partitions, drive.Spec.Path) {
+		err = m.fsOps.WipeFS(device)
+		m.audit.Record(audit.OperationWipe, drive.Spec.UUID, device, drive.Spec.SerialNumber, err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+)
+
+func TestDescribeDeviceData(t *testing.T) {
+	assert.Empty(t, describeDeviceData(bdev1))
+
+	device := bdev1
+	device.PTType = "gpt"
+	device.Children = []lsblk.BlockDevice{{Name: "/dev/sda1", Type: partitionDeviceType}}
+	assert.Equal(t, "gpt partition table, part /dev/sda1", describeDeviceData(device))
+
+	device = bdev1
+	device.FSType = "linux_raid_member"
+	device.Children = []lsblk.BlockDevice{{Name: "/dev/md0", Type: "raid1"}}
+	assert.Equal(t, "linux_raid_member signature, raid1 /dev/md0", describeDeviceData(device))
+}
+
+func TestVolumeManager_updateDrivesCRs_NotClean(t *testing.T) {
+	var (
+		vm      = prepareSuccessVolumeManager(t)
+		listBlk = &mocklu.MockWrapLsblk{}
+		rec     = &mocks.NoOpRecorder{}
+		dirty   = bdev2
+	)
+	vm.listBlk = listBlk
+	vm.recorder = rec
+	dirty.FSType = "xfs"
+	listBlk.On("GetBlockDevices", drive1.Path).Return([]lsblk.BlockDevice{bdev1}, nil)
+	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{dirty}, nil)
+
+	updates, err := vm.updateDrivesCRs(testCtx, getDriveMgrRespBasedOnDrives(drive1, drive2))
+	assert.Nil(t, err)
+	assert.Len(t, updates.Created, 2)
+	for _, d := range updates.Created {
+		if d.Spec.SerialNumber == drive2.SerialNumber {
+			assert.Equal(t, apiV1.DriveUsageNotClean, d.Spec.Usage)
+		} else {
+			assert.Equal(t, apiV1.DriveUsageInUse, d.Spec.Usage)
+		}
+	}
+	assert.Len(t, rec.Calls, 1)
+	assert.Equal(t, eventing.DriveNotClean, rec.Calls[0].Reason)
+
+	// no AC is created for NOT_CLEAN drive
+	assert.Nil(t, vm.discoverAvailableCapacity(testCtx))
+	acItems := getACCRsListItems(t, vm.k8sClient)
+	assert.Len(t, acItems, 1)
+	assert.Equal(t, drive1.SerialNumber, driveSerialByUUID(t, vm, acItems[0].Spec.Location))
+
+	// data is removed by operator, drive is cleared during next discovery
+	listBlk.ExpectedCalls = nil
+	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev2}, nil)
+	assert.Nil(t, vm.discoverAvailableCapacity(testCtx))
+	assert.Len(t, getACCRsListItems(t, vm.k8sClient), 2)
+	assert.Equal(t, eventing.DriveCleared, rec.Calls[len(rec.Calls)-1].Reason)
+}
+
+func TestVolumeManager_clearDrive(t *testing.T) {
+	var (
+		vm      = prepareSuccessVolumeManager(t)
+		listBlk = &mocklu.MockWrapLsblk{}
+		fsOps   = &mockProv.MockFsOpts{}
+		rec     = &mocks.NoOpRecorder{}
+		dirty   = bdev1
+		drive   = testDriveCR
+	)
+	vm.listBlk = listBlk
+	vm.fsOps = fsOps
+	vm.recorder = rec
+	dirty.PTType = "gpt"
+	dirty.Children = []lsblk.BlockDevice{{Name: "/dev/sda1", Type: partitionDeviceType}}
+	drive.Spec.Usage = apiV1.DriveUsageNotClean
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, drive.Name, &drive))
+
+	readDrive := func() *drivecrd.Drive {
+		d := &drivecrd.Drive{}
+		assert.Nil(t, vm.k8sClient.ReadCR(testCtx, drive.Name, "", d))
+		return d
+	}
+
+	// data is still on the drive
+	listBlk.On("GetBlockDevices", drive.Spec.Path).Return([]lsblk.BlockDevice{dirty}, nil).Once()
+	assert.False(t, vm.clearDrive(testCtx, readDrive()))
+
+	// erase isn't confirmed with serial number
+	d := readDrive()
+	d.Annotations = map[string]string{apiV1.DriveAnnotationEraseData: "yes"}
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, d))
+	assert.False(t, vm.clearDrive(testCtx, readDrive()))
+	fsOps.AssertNotCalled(t, "WipeFS", drive.Spec.Path)
+
+	// devices on top of the drive must be released first
+	raid := bdev1
+	raid.Children = []lsblk.BlockDevice{{Name: "/dev/md0", Type: "raid1"}}
+	d = readDrive()
+	d.Annotations[apiV1.DriveAnnotationEraseData] = drive.Spec.SerialNumber
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, d))
+	listBlk.On("GetBlockDevices", drive.Spec.Path).Return([]lsblk.BlockDevice{raid}, nil).Once()
+	assert.False(t, vm.clearDrive(testCtx, readDrive()))
+	assert.Equal(t, eventing.DriveEraseFailed, rec.Calls[len(rec.Calls)-1].Reason)
+
+	// wipe failed
+	listBlk.On("GetBlockDevices", drive.Spec.Path).Return([]lsblk.BlockDevice{dirty}, nil).Once()
+	fsOps.On("WipeFS", "/dev/sda1").Return(errors.New("device is busy")).Once()
+	assert.False(t, vm.clearDrive(testCtx, readDrive()))
+	assert.Equal(t, apiV1.DriveUsageNotClean, readDrive().Spec.Usage)
+
+	// data is erased
+	listBlk.On("GetBlockDevices", drive.Spec.Path).Return([]lsblk.BlockDevice{dirty}, nil).Once()
+	listBlk.On("GetBlockDevices", drive.Spec.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Once()
+	fsOps.On("WipeFS", "/dev/sda1").Return(nil).Once()
+	fsOps.On("WipeFS", drive.Spec.Path).Return(nil).Once()
+	assert.True(t, vm.clearDrive(testCtx, readDrive()))
+	d = readDrive()
+	assert.Equal(t, apiV1.DriveUsageInUse, d.Spec.Usage)
+	assert.NotContains(t, d.Annotations, apiV1.DriveAnnotationEraseData)
+	assert.Equal(t, eventing.DriveCleared, rec.Calls[len(rec.Calls)-1].Reason)
+	fsOps.AssertExpectations(t)
+}
+
+func driveSerialByUUID(t *testing.T, vm *VolumeManager, uuid string) string {
+	d := &drivecrd.Drive{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, uuid, "", d))
+	return d.Spec.SerialNumber
+}
@@ -0,0 +1,187 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ioerrors"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// DefaultIOErrorCheckInterval is an interval between checks of I/O error counters of the drives
+const DefaultIOErrorCheckInterval = time.Minute
+
+// DefaultIOErrorThreshold is the number of I/O errors of the drive during the last hour starting from which
+// drive gets IOErrors condition, intermittent errors of cabling or backplane are counted before volumes are corrupted
+const DefaultIOErrorThreshold = 10
+
+// ioErrorWindow is a period in which I/O errors are summed to calculate error rate of the drive
+const ioErrorWindow = time.Hour
+
+// ioErrorSample is a value of I/O error counter of the drive at a certain point
+type ioErrorSample struct {
+	time  time.Time
+	count int64
+}
+
+// ioErrorMonitor counts I/O errors of the drives and keeps counters of the last hour to calculate error rate
+type ioErrorMonitor struct {
+	ops   ioerrors.WrapIOErrors
+	clock clock.PassiveClock
+	// zero threshold disables IOErrors condition
+	threshold int64
+	count     *prometheus.GaugeVec
+	// samples of error counters by drive serial number, the oldest one is taken before the window start
+	samples map[string][]ioErrorSample
+}
+
+// newIOErrorMonitor is the constructor for ioErrorMonitor
+func newIOErrorMonitor(logger *logrus.Logger) *ioErrorMonitor {
+	return &ioErrorMonitor{
+		ops:       ioerrors.NewIOErrors(logger),
+		clock:     clock.RealClock{},
+		threshold: DefaultIOErrorThreshold,
+		count: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "drive_io_errors",
+			Help: "number of I/O errors of the drive since boot of the node",
+		}, []string{"serial_number"}),
+		samples: make(map[string][]ioErrorSample),
+	}
+}
+
+// observe remembers error counter of the drive and drops samples which are out of the window
+// Returns number of errors during the last hour, it is 0 on the first observation or after counter reset
+func (e *ioErrorMonitor) observe(serialNumber string, count int64, now time.Time) int64 {
+	e.count.With(prometheus.Labels{"serial_number": serialNumber}).Set(float64(count))
+
+	samples := e.samples[serialNumber]
+	// counter is reset when device is attached again
+	if len(samples) > 0 && samples[len(samples)-1].count > count {
+		samples = nil
+	}
+	samples = append(samples, ioErrorSample{time: now, count: count})
+	windowStart := now.Add(-ioErrorWindow)
+	for len(samples) > 1 && !samples[1].time.After(windowStart) {
+		samples = samples[1:]
+	}
+	e.samples[serialNumber] = samples
+	return count - samples[0].count
+}
+
+// forget removes samples and metric of the drives which aren't checked anymore
+func (e *ioErrorMonitor) forget(checked map[string]bool) {
+	for serialNumber := range e.samples {
+		if !checked[serialNumber] {
+			delete(e.samples, serialNumber)
+			e.count.Delete(prometheus.Labels{"serial_number": serialNumber})
+		}
+	}
+}
+
+// SetIOErrorThreshold sets number of I/O errors of the drive during the last hour starting from which drive gets
+// IOErrors condition, zero value disables the condition and events, negative value is ignored
+func (m *VolumeManager) SetIOErrorThreshold(threshold int) {
+	if threshold < 0 {
+		m.log.Warnf("Unable to set I/O error threshold to %d, using %d", threshold, m.ioErrors.threshold)
+		return
+	}
+	m.ioErrors.threshold = int64(threshold)
+}
+
+// CheckIOErrors reads I/O error counters of the online drives of the node and stores number of errors since boot and
+// during the last hour in the status of Drive CR, drive which errors reach threshold gets IOErrors condition
+// and warning event. Drive CR is updated only if number of errors or condition was changed
+// Receives golang context
+// Returns error if drives can't be listed
+func (m *VolumeManager) CheckIOErrors(ctx context.Context) error {
+	ll := m.log.WithField("method", "CheckIOErrors")
+
+	drives, err := m.cachedCrHelper.GetDriveCRs(ctx, m.nodeID)
+	if err != nil {
+		return err
+	}
+	now := m.ioErrors.clock.Now()
+	checked := make(map[string]bool, len(drives))
+	for i := range drives {
+		drive := drives[i].DeepCopy()
+		if drive.Spec.Path == "" || drive.Spec.Status != apiV1.DriveStatusOnline {
+			continue
+		}
+		count, err := m.ioErrors.ops.GetErrorCount(drive.Spec.Path)
+		if err != nil {
+			if errors.Is(err, ioerrors.ErrNotSupported) {
+				ll.Debugf("I/O errors of drive %s aren't counted: %v", drive.Spec.SerialNumber, err)
+			} else {
+				ll.Warnf("Unable to count I/O errors of drive %s: %v", drive.Spec.SerialNumber, err)
+			}
+			continue
+		}
+		checked[drive.Spec.SerialNumber] = true
+		lastHour := m.ioErrors.observe(drive.Spec.SerialNumber, count, now)
+		m.setIOErrors(ctx, drive, count, lastHour, metav1.NewTime(now))
+	}
+	m.ioErrors.forget(checked)
+	return nil
+}
+
+// setIOErrors updates I/O errors in the status of Drive CR and sends event if IOErrors condition was changed
+func (m *VolumeManager) setIOErrors(ctx context.Context, drive *drivecrd.Drive, count, lastHour int64,
+	now metav1.Time) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":  "setIOErrors",
+		"driveID": drive.Name,
+	})
+
+	exceeded := func() bool {
+		condition := drive.GetCondition(drivecrd.DriveConditionIOErrors)
+		return condition != nil && condition.Status == corev1.ConditionTrue
+	}
+	wasExceeded := exceeded()
+	if !drive.SetIOErrors(count, lastHour, m.ioErrors.threshold, now) {
+		return
+	}
+	// drive CR is updated by discovery concurrently
+	err := m.k8sClient.UpdateCRWithConflictRetry(ctx, drive, func() error {
+		drive.SetIOErrors(count, lastHour, m.ioErrors.threshold, now)
+		return nil
+	})
+	if err != nil {
+		ll.Errorf("Unable to update I/O errors of drive %s: %v", drive.Spec.SerialNumber, err)
+		return
+	}
+	switch isExceeded := exceeded(); {
+	case isExceeded && !wasExceeded:
+		ll.Warnf("Drive %s has %d I/O errors during the last hour", drive.Spec.SerialNumber, lastHour)
+		m.sendEventForDrive(drive, eventing.WarningType, eventing.DriveIOErrorsHigh,
+			"Drive has %d I/O errors during the last hour, threshold is %d, check cabling and backplane of the drive. ",
+			lastHour, m.ioErrors.threshold)
+	case !isExceeded && wasExceeded:
+		m.sendEventForDrive(drive, eventing.NormalType, eventing.DriveIOErrorsNormal,
+			"Drive has %d I/O errors during the last hour, threshold is %d. ", lastHour, m.ioErrors.threshold)
+	}
+}
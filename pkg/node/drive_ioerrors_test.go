@@ -0,0 +1,118 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ioerrors"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
+
+func TestIOErrorMonitor_observe(t *testing.T) {
+	var (
+		e   = newIOErrorMonitor(testLogger)
+		now = time.Now()
+	)
+
+	assert.Equal(t, int64(0), e.observe("hdd1", 5, now))
+	assert.Equal(t, int64(3), e.observe("hdd1", 8, now.Add(30*time.Minute)))
+	// errors before the last hour aren't taken into account
+	assert.Equal(t, int64(1), e.observe("hdd1", 9, now.Add(90*time.Minute)))
+	assert.Equal(t, int64(0), e.observe("hdd1", 9, now.Add(3*time.Hour)))
+	// counter was reset
+	assert.Equal(t, int64(0), e.observe("hdd1", 1, now.Add(4*time.Hour)))
+
+	e.forget(map[string]bool{})
+	assert.Empty(t, e.samples)
+}
+
+func TestVolumeManager_CheckIOErrors(t *testing.T) {
+	var (
+		vm      = prepareSuccessVolumeManager(t)
+		ops     = &mocklu.MockWrapIOErrors{}
+		rec     = &mocks.NoOpRecorder{}
+		fc      = clock.NewFakeClock(time.Now())
+		scsi    = disk1
+		nvme    = disk2
+		offline = api.Drive{UUID: "offline-drive", SerialNumber: "hdd3", NodeId: nodeID,
+			Status: apiV1.DriveStatusOffline, Path: "/dev/sdc"}
+	)
+	vm.recorder = rec
+	vm.ioErrors.ops = ops
+	vm.ioErrors.clock = fc
+	vm.SetIOErrorThreshold(10)
+
+	scsi.Status, scsi.Path = apiV1.DriveStatusOnline, "/dev/sda"
+	nvme.Status, nvme.Path = apiV1.DriveStatusOnline, "/dev/nvme0n1"
+	for _, d := range []api.Drive{scsi, nvme, offline} {
+		assert.Nil(t, vm.k8sClient.CreateCR(testCtx, d.UUID, vm.k8sClient.ConstructDriveCR(d.UUID, d)))
+	}
+	ops.On("GetErrorCount", nvme.Path).Return(int64(0), fmt.Errorf("%w for %s", ioerrors.ErrNotSupported, nvme.Path))
+
+	status := func() drivecrd.DriveStatus {
+		drive := &drivecrd.Drive{}
+		assert.Nil(t, vm.k8sClient.ReadCR(testCtx, scsi.UUID, "", drive))
+		return drive.Status
+	}
+
+	ops.On("GetErrorCount", scsi.Path).Return(int64(2), nil).Once()
+	assert.Nil(t, vm.CheckIOErrors(testCtx))
+	assert.Equal(t, int64(2), status().IOErrors.Count)
+	assert.Equal(t, int64(0), status().IOErrors.LastHour)
+	assert.Empty(t, rec.Calls)
+
+	// burst of errors
+	fc.Step(time.Minute)
+	ops.On("GetErrorCount", scsi.Path).Return(int64(14), nil).Once()
+	assert.Nil(t, vm.CheckIOErrors(testCtx))
+	assert.Equal(t, int64(12), status().IOErrors.LastHour)
+	driveStatus := status()
+	condition := (&drivecrd.Drive{Status: driveStatus}).GetCondition(drivecrd.DriveConditionIOErrors)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Len(t, rec.Calls, 1)
+	assert.Equal(t, eventing.DriveIOErrorsHigh, rec.Calls[0].Reason)
+
+	// errors of the burst are out of the window
+	fc.Step(2 * time.Hour)
+	ops.On("GetErrorCount", scsi.Path).Return(int64(14), nil).Once()
+	assert.Nil(t, vm.CheckIOErrors(testCtx))
+	assert.Equal(t, int64(0), status().IOErrors.LastHour)
+	driveStatus = status()
+	condition = (&drivecrd.Drive{Status: driveStatus}).GetCondition(drivecrd.DriveConditionIOErrors)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Len(t, rec.Calls, 2)
+	assert.Equal(t, eventing.DriveIOErrorsNormal, rec.Calls[1].Reason)
+
+	// NVMe drive isn't counted without kernel log, offline drive isn't checked
+	nvmeCR := &drivecrd.Drive{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, nvme.UUID, "", nvmeCR))
+	assert.Nil(t, nvmeCR.Status.IOErrors)
+	ops.AssertExpectations(t)
+	ops.AssertNotCalled(t, "GetErrorCount", offline.Path)
+}
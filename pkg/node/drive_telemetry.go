@@ -0,0 +1,128 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+)
+
+// DefaultEnduranceHysteresis is the minimal change of drive endurance (in percents) which is stored in Drive CR,
+// smaller changes are exposed by metrics only to not load API server with constant CR updates
+const DefaultEnduranceHysteresis = 5
+
+// DefaultDriveTemperatureThreshold is the drive temperature (in Celsius) starting from which drive is reported
+// as overheated, it is the maximal operating temperature of the most of enterprise HDDs
+const DefaultDriveTemperatureThreshold = 60
+
+// temperatureHysteresis is the amount of degrees on which overheated drive should cool down below threshold
+// to be reported as normal, it prevents flapping events when temperature fluctuates around threshold
+const temperatureHysteresis = 3
+
+// driveTelemetry exposes fast-changing attributes of the drives as metrics
+// and decides whether their change is significant enough to be written into Drive CR
+type driveTelemetry struct {
+	endurance           *prometheus.GaugeVec
+	enduranceHysteresis int64
+	temperature         *prometheus.GaugeVec
+	// zero threshold disables detection of overheated drives
+	temperatureThreshold int32
+	// serial numbers of the drives which exceeded temperature threshold
+	overheated map[string]bool
+}
+
+// thresholdCrossing is a change of drive temperature relative to the threshold since previous discovery
+type thresholdCrossing struct {
+	temperature int32
+	overheated  bool
+}
+
+// newDriveTelemetry is the constructor for driveTelemetry
+func newDriveTelemetry() *driveTelemetry {
+	return &driveTelemetry{
+		endurance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "drive_endurance_percent",
+			Help: "endurance of the drive reported by drive manager on the last discovery",
+		}, []string{"serial_number"}),
+		enduranceHysteresis: DefaultEnduranceHysteresis,
+		temperature: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "drive_temperature_celsius",
+			Help: "temperature of the drive reported by drive manager on the last discovery",
+		}, []string{"serial_number"}),
+		temperatureThreshold: DefaultDriveTemperatureThreshold,
+		overheated:           make(map[string]bool),
+	}
+}
+
+// observe exposes telemetry of the drives reported by drive manager, metrics of absent drives are removed
+// Returns drives (by serial number) which temperature crossed threshold since previous discovery
+func (t *driveTelemetry) observe(drives []*api.Drive) map[string]thresholdCrossing {
+	t.endurance.Reset()
+	t.temperature.Reset()
+	crossings := make(map[string]thresholdCrossing)
+	reported := make(map[string]bool, len(drives))
+	for _, drive := range drives {
+		if drive.SerialNumber == "" {
+			continue
+		}
+		labels := prometheus.Labels{"serial_number": drive.SerialNumber}
+		t.endurance.With(labels).Set(float64(drive.Endurance))
+		// drive doesn't report temperature
+		if drive.Temperature == 0 {
+			continue
+		}
+		t.temperature.With(labels).Set(float64(drive.Temperature))
+		reported[drive.SerialNumber] = true
+		if crossed, overheated := t.crossedThreshold(drive); crossed {
+			crossings[drive.SerialNumber] = thresholdCrossing{temperature: drive.Temperature, overheated: overheated}
+		}
+	}
+	for serialNumber := range t.overheated {
+		if !reported[serialNumber] {
+			delete(t.overheated, serialNumber)
+		}
+	}
+	return crossings
+}
+
+// crossedThreshold checks whether drive temperature crossed threshold and remembers the state of the drive
+// Returns true if threshold was crossed and whether drive is overheated now
+func (t *driveTelemetry) crossedThreshold(drive *api.Drive) (bool, bool) {
+	if t.temperatureThreshold == 0 {
+		return false, false
+	}
+	switch {
+	case !t.overheated[drive.SerialNumber] && drive.Temperature >= t.temperatureThreshold:
+		t.overheated[drive.SerialNumber] = true
+		return true, true
+	case t.overheated[drive.SerialNumber] && drive.Temperature <= t.temperatureThreshold-temperatureHysteresis:
+		delete(t.overheated, drive.SerialNumber)
+		return true, false
+	}
+	return false, t.overheated[drive.SerialNumber]
+}
+
+// significant checks whether telemetry reported by drive manager differs from stored in Drive CR
+// at least by hysteresis
+func (t *driveTelemetry) significant(stored, reported *api.Drive) bool {
+	diff := reported.Endurance - stored.Endurance
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff > 0 && diff >= t.enduranceHysteresis
+}
@@ -0,0 +1,145 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package faults contains fault injection switches of the node service, they are used by chaos e2e tests
+// to check that the system converges after failures in the middle of volume operations
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	coreV1 "k8s.io/api/core/v1"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Point is a place in the node service where fault could be injected
+type Point string
+
+// Fault points
+const (
+	// StageVolume is in NodeStageVolume before volume is mounted to the staging path
+	StageVolume Point = "stage-volume"
+	// CreateFS is in volume provisioning before file system is created on the volume
+	CreateFS Point = "create-fs"
+	// Discover is in discovery before drives are requested from drive manager
+	Discover Point = "discover"
+)
+
+// NodeAnnotation is the annotation of k8s Node which holds faults injected on the node
+// Format is comma-separated list of <point>=delay:<duration> or <point>=error:<message>,
+// for example "stage-volume=delay:1m,create-fs=error:disk is gone"
+const NodeAnnotation = "csi-baremetal.dell.com/faults"
+
+// Fault delays execution of the point and/or fails it
+type Fault struct {
+	Delay time.Duration
+	Err   error
+}
+
+// Parse parses faults from value of NodeAnnotation
+// Returns faults by points or error if value has wrong format
+func Parse(value string) (map[Point]Fault, error) {
+	faults := make(map[Point]Fault)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		pointAndFault := strings.SplitN(item, "=", 2)
+		if len(pointAndFault) != 2 {
+			return nil, fmt.Errorf("fault %s should be in <point>=<type>:<value> format", item)
+		}
+		typeAndValue := strings.SplitN(pointAndFault[1], ":", 2)
+		if len(typeAndValue) != 2 {
+			return nil, fmt.Errorf("fault %s should be in <point>=<type>:<value> format", item)
+		}
+		point := Point(pointAndFault[0])
+		fault := faults[point]
+		switch typeAndValue[0] {
+		case "delay":
+			delay, err := time.ParseDuration(typeAndValue[1])
+			if err != nil {
+				return nil, fmt.Errorf("wrong delay of fault %s: %w", item, err)
+			}
+			fault.Delay = delay
+		case "error":
+			fault.Err = errors.New(typeAndValue[1])
+		default:
+			return nil, fmt.Errorf("unknown type %s of fault %s", typeAndValue[0], item)
+		}
+		faults[point] = fault
+	}
+	return faults, nil
+}
+
+// Injector injects faults which are set in NodeAnnotation of k8s Node, nil Injector doesn't inject anything
+type Injector struct {
+	reader   k8sCl.Reader
+	nodeName string
+	log      *logrus.Entry
+}
+
+// NewInjector is the constructor for Injector
+// Receives reader of k8s objects and name of the k8s Node where node service works
+func NewInjector(reader k8sCl.Reader, nodeName string, logger *logrus.Logger) *Injector {
+	return &Injector{
+		reader:   reader,
+		nodeName: nodeName,
+		log:      logger.WithField("component", "FaultInjector"),
+	}
+}
+
+// Inject delays execution and returns error if fault is set for the point
+// Delay is interrupted if context is done, faults aren't injected if k8s Node can't be read
+func (i *Injector) Inject(ctx context.Context, point Point) error {
+	if i == nil {
+		return nil
+	}
+	ll := i.log.WithFields(logrus.Fields{"method": "Inject", "point": point})
+
+	node := &coreV1.Node{}
+	if err := i.reader.Get(ctx, k8sCl.ObjectKey{Name: i.nodeName}, node); err != nil {
+		ll.Warnf("Unable to read node %s: %v", i.nodeName, err)
+		return nil
+	}
+	faults, err := Parse(node.Annotations[NodeAnnotation])
+	if err != nil {
+		ll.Warnf("Unable to parse faults of node %s: %v", i.nodeName, err)
+		return nil
+	}
+	fault, ok := faults[point]
+	if !ok {
+		return nil
+	}
+	if fault.Delay > 0 {
+		ll.Warnf("Injecting delay %s", fault.Delay)
+		select {
+		case <-time.After(fault.Delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if fault.Err != nil {
+		ll.Warnf("Injecting error: %v", fault.Err)
+		return fault.Err
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faults
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+const testNode = "node-1"
+
+func TestParse(t *testing.T) {
+	faults, err := Parse("stage-volume=delay:1m, create-fs=error:disk is gone,create-fs=delay:1s")
+	assert.Nil(t, err)
+	assert.Len(t, faults, 2)
+	assert.Equal(t, time.Minute, faults[StageVolume].Delay)
+	assert.Nil(t, faults[StageVolume].Err)
+	assert.Equal(t, time.Second, faults[CreateFS].Delay)
+	assert.EqualError(t, faults[CreateFS].Err, "disk is gone")
+
+	faults, err = Parse("")
+	assert.Nil(t, err)
+	assert.Empty(t, faults)
+
+	for _, value := range []string{"stage-volume", "stage-volume=delay", "stage-volume=delay:1x", "discover=crash:1"} {
+		_, err = Parse(value)
+		assert.NotNil(t, err, value)
+	}
+}
+
+func TestInjector_Inject(t *testing.T) {
+	logger := logrus.New()
+	kubeClient, err := k8s.GetFakeKubeClient("default", logger)
+	assert.Nil(t, err)
+
+	// nil injector and absent node don't inject faults
+	var injector *Injector
+	assert.Nil(t, injector.Inject(context.Background(), Discover))
+	injector = NewInjector(kubeClient, testNode, logger)
+	assert.Nil(t, injector.Inject(context.Background(), Discover))
+
+	node := &coreV1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        testNode,
+		Annotations: map[string]string{NodeAnnotation: "discover=error:drive manager is gone,stage-volume=delay:1h"},
+	}}
+	assert.Nil(t, kubeClient.Create(context.Background(), node))
+
+	assert.EqualError(t, injector.Inject(context.Background(), Discover), "drive manager is gone")
+	assert.Nil(t, injector.Inject(context.Background(), CreateFS))
+
+	// delay is interrupted by context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, injector.Inject(ctx, StageVolume))
+}
@@ -0,0 +1,136 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// File system mismatch policies, they define how volume is staged if its device holds file system
+// of another type than the one which is requested for the volume
+const (
+	// FSMismatchFail means that staging of the volume fails until mismatch is resolved by user
+	FSMismatchFail = "fail"
+	// FSMismatchReuse means that existing file system is mounted and type of the volume is updated to match it
+	FSMismatchReuse = "reuse"
+	// FSMismatchReformat means that file system is recreated if data erase is confirmed with erase-data annotation
+	// of the volume, otherwise staging fails
+	FSMismatchReformat = "reformat"
+)
+
+// SetFSMismatchPolicy sets how volume which device holds file system of another type is handled on stage
+// Returns error if policy is unknown
+func (s *CSINodeService) SetFSMismatchPolicy(policy string) error {
+	switch policy {
+	case FSMismatchFail, FSMismatchReuse, FSMismatchReformat:
+		s.fsMismatchPolicy = policy
+		return nil
+	}
+	return fmt.Errorf("unknown file system mismatch policy %s, supported values are %s, %s, %s",
+		policy, FSMismatchFail, FSMismatchReuse, FSMismatchReformat)
+}
+
+// checkVolumeFS compares file system on the device of the volume with the type of the volume before it is staged
+// and resolves mismatch in according with the policy. Data is erased only in reformat policy and only if erase-data
+// annotation of the volume is equal to volume ID, the annotation is removed once it is checked against file system
+// Receives volume CR and device which holds file system of the volume
+// Returns gRPC status error if volume shouldn't be staged
+func (s *CSINodeService) checkVolumeFS(ctx context.Context, volume *volumecrd.Volume, device string) error {
+	ll := s.log.WithFields(logrus.Fields{
+		"method":   "checkVolumeFS",
+		"volumeID": volume.Spec.Id,
+	})
+
+	if volume.Spec.Mode != apiV1.ModeFS || volume.Spec.Type == "" {
+		return nil
+	}
+	currFS, err := s.fsOps.GetFSType(device)
+	if err != nil {
+		ll.Errorf("Unable to determine file system on %s: %v", device, err)
+		return status.Error(codes.Internal, "failed to stage volume: unable to determine file system")
+	}
+
+	eraseConfirmation, eraseRequested := volume.Annotations[apiV1.VolumeAnnotationEraseData]
+	if currFS == fs.FileSystem(volume.Spec.Type) {
+		if !eraseRequested {
+			return nil
+		}
+		// confirmation isn't kept, otherwise data could be erased on mismatch which isn't known to user yet
+		ll.Infof("File system matches, %s annotation is removed", apiV1.VolumeAnnotationEraseData)
+		return s.removeEraseDataAnnotation(volume)
+	}
+
+	found := string(currFS)
+	if found == "" {
+		found = "no"
+	}
+	message := fmt.Sprintf("device %s holds %s file system, volume requires %s", device, found, volume.Spec.Type)
+
+	switch {
+	case s.fsMismatchPolicy == FSMismatchReuse && currFS != "":
+		ll.Warnf("%s, existing file system is reused", message)
+		volume.Spec.Type = string(currFS)
+		ctxWithID := context.WithValue(ctx, base.RequestUUID, volume.Spec.Id)
+		if err = s.k8sClient.UpdateCR(ctxWithID, volume); err != nil {
+			ll.Errorf("Unable to update file system type of the volume: %v", err)
+			return status.Error(codes.Internal, "failed to stage volume: update volume CR error")
+		}
+		s.recorder.Eventf(volume, eventing.WarningType, eventing.VolumeFSMismatch,
+			"%s, existing file system is reused", message)
+		return nil
+	case s.fsMismatchPolicy == FSMismatchReformat && eraseConfirmation == volume.Spec.Id:
+		ll.Warnf("%s, file system is recreated since data erase is confirmed", message)
+		if err = s.getProvisionerForVolume(&volume.Spec).ReformatVolume(ctx, volume.Spec); err != nil {
+			ll.Errorf("Unable to recreate file system: %v", err)
+			return status.Error(codes.Internal, "failed to stage volume: reformat error")
+		}
+		s.recorder.Eventf(volume, eventing.WarningType, eventing.VolumeReformatted,
+			"%s, file system is recreated and data of the volume is erased", message)
+		return s.removeEraseDataAnnotation(volume)
+	}
+
+	if s.fsMismatchPolicy == FSMismatchReformat {
+		message += fmt.Sprintf(", set %s annotation of the volume to %s to recreate file system and erase data",
+			apiV1.VolumeAnnotationEraseData, volume.Spec.Id)
+	}
+	ll.Error(message)
+	s.recorder.Eventf(volume, eventing.WarningType, eventing.VolumeFSMismatch, "%s", message)
+	return status.Errorf(codes.FailedPrecondition, "failed to stage volume: %s", message)
+}
+
+// removeEraseDataAnnotation removes erase-data annotation of the volume
+func (s *CSINodeService) removeEraseDataAnnotation(volume *volumecrd.Volume) error {
+	delete(volume.Annotations, apiV1.VolumeAnnotationEraseData)
+	ctxWithID := context.WithValue(context.Background(), base.RequestUUID, volume.Spec.Id)
+	if err := s.k8sClient.UpdateCR(ctxWithID, volume); err != nil {
+		s.log.WithField("volumeID", volume.Spec.Id).
+			Errorf("Unable to remove %s annotation: %v", apiV1.VolumeAnnotationEraseData, err)
+		return status.Error(codes.Internal, "failed to stage volume: update volume CR error")
+	}
+	return nil
+}
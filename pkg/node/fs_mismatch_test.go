@@ -0,0 +1,132 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
+)
+
+func TestCSINodeService_SetFSMismatchPolicy(t *testing.T) {
+	svc := newNodeService()
+	assert.Equal(t, FSMismatchFail, svc.fsMismatchPolicy)
+
+	assert.Nil(t, svc.SetFSMismatchPolicy(FSMismatchReformat))
+	assert.Equal(t, FSMismatchReformat, svc.fsMismatchPolicy)
+
+	assert.NotNil(t, svc.SetFSMismatchPolicy("format"))
+	assert.Equal(t, FSMismatchReformat, svc.fsMismatchPolicy)
+}
+
+func TestCSINodeService_NodeStageVolume_FSMismatch(t *testing.T) {
+	var (
+		svc      = newNodeService()
+		provMock = &mockProv.MockProvisioner{}
+		fsMock   = &mockProv.MockFsOpts{}
+		rec      = &mocks.NoOpRecorder{}
+		device   = "/dev/sda1"
+		req      = getNodeStageRequest(testV2ID, *testVolumeCap)
+		target   = path.Join(req.GetStagingTargetPath(), stagingFileName)
+	)
+	svc.provisioners = map[p.VolumeType]p.Provisioner{p.DriveBasedVolumeType: provMock}
+	svc.fsOps = fsMock
+	svc.recorder = rec
+
+	readVolume := func() *vcrd.Volume {
+		volume := &vcrd.Volume{}
+		assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testV2ID, testNs, volume))
+		return volume
+	}
+	resetVolume := func(annotations map[string]string) {
+		volume := readVolume()
+		volume.Spec.Mode = apiV1.ModeFS
+		volume.Spec.Type = string(fs.XFS)
+		volume.Spec.CSIStatus = apiV1.Created
+		volume.Annotations = annotations
+		assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, volume))
+	}
+	lastReason := func() string {
+		return rec.Calls[len(rec.Calls)-1].Reason
+	}
+
+	resetVolume(nil)
+	provMock.On("GetVolumePath", readVolume().Spec).Return(device, nil)
+	fsMock.On("GetFSType", device).Return(fs.EXT4, nil)
+
+	// staging fails by default, data isn't touched
+	_, err := svc.NodeStageVolume(testCtx, req)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, eventing.VolumeFSMismatch, lastReason())
+	assert.Equal(t, apiV1.Created, readVolume().Spec.CSIStatus)
+
+	// reformat isn't performed without confirmation or with confirmation of another volume
+	assert.Nil(t, svc.SetFSMismatchPolicy(FSMismatchReformat))
+	_, err = svc.NodeStageVolume(testCtx, req)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, err.Error(), apiV1.VolumeAnnotationEraseData)
+
+	resetVolume(map[string]string{apiV1.VolumeAnnotationEraseData: testV1ID})
+	provMock.On("GetVolumePath", readVolume().Spec).Return(device, nil)
+	_, err = svc.NodeStageVolume(testCtx, req)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	provMock.AssertNotCalled(t, "ReformatVolume", readVolume().Spec)
+
+	// file system is recreated once data erase is confirmed
+	resetVolume(map[string]string{apiV1.VolumeAnnotationEraseData: testV2ID})
+	volume := readVolume()
+	provMock.On("GetVolumePath", volume.Spec).Return(device, nil)
+	provMock.On("ReformatVolume", volume.Spec).Return(nil).Once()
+	fsMock.On("PrepareAndPerformMount", device, target, true, false).Return(nil)
+	_, err = svc.NodeStageVolume(testCtx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, eventing.VolumeReformatted, lastReason())
+	volume = readVolume()
+	assert.NotContains(t, volume.Annotations, apiV1.VolumeAnnotationEraseData)
+	assert.Equal(t, apiV1.VolumeReady, volume.Spec.CSIStatus)
+	provMock.AssertNumberOfCalls(t, "ReformatVolume", 1)
+
+	// existing file system is reused and type of the volume is updated
+	assert.Nil(t, svc.SetFSMismatchPolicy(FSMismatchReuse))
+	resetVolume(nil)
+	provMock.On("GetVolumePath", readVolume().Spec).Return(device, nil)
+	reused := readVolume().Spec
+	reused.Type = string(fs.EXT4)
+	provMock.On("GetVolumePath", reused).Return(device, nil)
+	_, err = svc.NodeStageVolume(testCtx, req)
+	assert.Nil(t, err)
+	assert.Equal(t, string(fs.EXT4), readVolume().Spec.Type)
+
+	// device without file system isn't reused
+	resetVolume(nil)
+	fsMock.ExpectedCalls = nil
+	fsMock.On("GetFSType", device).Return(fs.FileSystem(""), nil)
+	_, err = svc.NodeStageVolume(testCtx, req)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	provMock.AssertNumberOfCalls(t, "ReformatVolume", 1)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authnV1 "k8s.io/api/authentication/v1"
+	authzV1 "k8s.io/api/authorization/v1"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// Authorizer checks whether request is allowed to perform verb on the CSI resource
+// Returns HTTP status code and error if request isn't allowed
+type Authorizer interface {
+	Authorize(r *http.Request, verb, resource string) (int, error)
+}
+
+// ReviewAuthorizer authorizes requests by k8s RBAC: bearer token of the request is authenticated by TokenReview and
+// permission of the user is checked by SubjectAccessReview, so the same roles are used as for kubectl access to CRs
+type ReviewAuthorizer struct {
+	client k8sCl.Client
+}
+
+// NewReviewAuthorizer is the constructor for ReviewAuthorizer struct
+// Receives k8s client which is allowed to create TokenReviews and SubjectAccessReviews
+func NewReviewAuthorizer(client k8sCl.Client) *ReviewAuthorizer {
+	return &ReviewAuthorizer{client: client}
+}
+
+// Authorize implements Authorizer interface
+// Returns 401 if token is missing or invalid, 403 if user isn't allowed to perform verb and 500 if review failed
+func (a *ReviewAuthorizer) Authorize(r *http.Request, verb, resource string) (int, error) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		return http.StatusUnauthorized, errors.New("bearer token is required")
+	}
+
+	tokenReview := &authnV1.TokenReview{Spec: authnV1.TokenReviewSpec{Token: token}}
+	if err := a.client.Create(r.Context(), tokenReview); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("unable to review token: %w", err)
+	}
+	if !tokenReview.Status.Authenticated {
+		return http.StatusUnauthorized, errors.New("token isn't authenticated")
+	}
+
+	user := tokenReview.Status.User
+	extra := make(map[string]authzV1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authzV1.ExtraValue(v)
+	}
+	accessReview := &authzV1.SubjectAccessReview{Spec: authzV1.SubjectAccessReviewSpec{
+		ResourceAttributes: &authzV1.ResourceAttributes{
+			Group:    apiV1.CSICRsGroupVersion,
+			Resource: resource,
+			Verb:     verb,
+		},
+		User:   user.Username,
+		Groups: user.Groups,
+		Extra:  extra,
+		UID:    user.UID,
+	}}
+	if err := a.client.Create(r.Context(), accessReview); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("unable to review access: %w", err)
+	}
+	if !accessReview.Status.Allowed {
+		return http.StatusForbidden, fmt.Errorf("user %s isn't allowed to %s %s", user.Username, verb, resource)
+	}
+	return http.StatusOK, nil
+}
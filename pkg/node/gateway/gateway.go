@@ -0,0 +1,220 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gateway contains REST facade of the node for the tools which can't use gRPC or CRDs, e.g. fleet dashboards.
+// It exposes drives, available capacity and volumes of the node and safe operations: discovery and locate LED
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/status"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+const (
+	// DrivesPath lists drives of the node on GET request
+	DrivesPath = "/drives"
+	// CapacityPath lists available capacity of the node on GET request
+	CapacityPath = "/capacity"
+	// VolumesPath lists volumes of the node on GET request
+	VolumesPath = "/volumes"
+	// DiscoveryPath triggers discovery of the node drives on POST request
+	DiscoveryPath = "/discovery"
+	// locateSuffix manipulates LED of the drive on POST request to /drives/<serial number>/locate?action=<action>,
+	// supported actions are start, stop and status
+	locateSuffix = "/locate"
+
+	// locateTimeout limits call of drive manager
+	locateTimeout = 30 * time.Second
+)
+
+// locateActions maps action query parameter to action of drive manager Locate call
+var locateActions = map[string]int32{
+	"start":  apiV1.LocateStart,
+	"stop":   apiV1.LocateStop,
+	"status": apiV1.LocateStatus,
+}
+
+// DiscoveryTrigger requests discovery of the node drives
+type DiscoveryTrigger interface {
+	TriggerDiscovery()
+}
+
+// LocateResponse is a response of locate request
+type LocateResponse struct {
+	SerialNumber string `json:"serialNumber"`
+	// LED is "on" or "off"
+	LED string `json:"led"`
+}
+
+// Gateway serves REST requests of the node, each request is authorized by Authorizer
+type Gateway struct {
+	crHelper   *k8s.CRHelper
+	drives     api.DriveServiceClient
+	discovery  DiscoveryTrigger
+	authorizer Authorizer
+	nodeID     string
+	log        *logrus.Entry
+}
+
+// NewGateway is the constructor for Gateway struct
+// Receives k8s client which is used to read CRs and authorize requests, drive manager client, trigger of discovery
+// and ID of the node
+// Returns an instance of Gateway
+func NewGateway(k8sClient *k8s.KubeClient, drives api.DriveServiceClient, discovery DiscoveryTrigger,
+	nodeID string, logger *logrus.Logger) *Gateway {
+	return &Gateway{
+		crHelper:   k8s.NewCRHelper(k8sClient, logger),
+		drives:     drives,
+		discovery:  discovery,
+		authorizer: NewReviewAuthorizer(k8sClient),
+		nodeID:     nodeID,
+		log:        logger.WithField("component", "Gateway"),
+	}
+}
+
+// Handler returns HTTP handler of the gateway which serves paths under prefix
+func (g *Gateway) Handler(prefix string) http.Handler {
+	return http.StripPrefix(strings.TrimSuffix(prefix, "/"), http.HandlerFunc(g.serve))
+}
+
+func (g *Gateway) serve(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path := r.URL.Path
+	switch {
+	case path == DrivesPath:
+		g.list(w, r, "drives", func() (interface{}, error) {
+			drives, err := g.crHelper.GetDriveCRs(ctx, g.nodeID)
+			specs := make([]api.Drive, 0, len(drives))
+			for _, d := range drives {
+				specs = append(specs, d.Spec)
+			}
+			return specs, err
+		})
+	case path == CapacityPath:
+		g.list(w, r, "availablecapacities", func() (interface{}, error) {
+			acs, err := g.crHelper.GetACCRs(ctx, g.nodeID)
+			specs := make([]api.AvailableCapacity, 0, len(acs))
+			for _, ac := range acs {
+				specs = append(specs, ac.Spec)
+			}
+			return specs, err
+		})
+	case path == VolumesPath:
+		g.list(w, r, "volumes", func() (interface{}, error) {
+			volumes, err := g.crHelper.GetVolumeCRs(ctx, g.nodeID)
+			specs := make([]api.Volume, 0, len(volumes))
+			for _, v := range volumes {
+				specs = append(specs, v.Spec)
+			}
+			return specs, err
+		})
+	case path == DiscoveryPath:
+		if !g.allow(w, r, http.MethodPost, "drives") {
+			return
+		}
+		g.discovery.TriggerDiscovery()
+		w.WriteHeader(http.StatusAccepted)
+	case strings.HasPrefix(path, DrivesPath+"/") && strings.HasSuffix(path, locateSuffix):
+		serial := strings.TrimSuffix(strings.TrimPrefix(path, DrivesPath+"/"), locateSuffix)
+		if serial == "" || strings.Contains(serial, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		if !g.allow(w, r, http.MethodPost, "drives") {
+			return
+		}
+		g.locate(w, r, serial)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// list writes JSON result of read on GET request
+func (g *Gateway) list(w http.ResponseWriter, r *http.Request, resource string, read func() (interface{}, error)) {
+	if !g.allow(w, r, http.MethodGet, resource) {
+		return
+	}
+	items, err := read()
+	if err != nil {
+		g.log.WithField("method", "list").Errorf("Unable to read %s: %v", resource, err)
+		http.Error(w, "unable to read "+resource, http.StatusInternalServerError)
+		return
+	}
+	g.writeJSON(w, items)
+}
+
+// locate calls drive manager to manipulate LED of the drive with serial number
+func (g *Gateway) locate(w http.ResponseWriter, r *http.Request, serial string) {
+	action, ok := locateActions[r.URL.Query().Get("action")]
+	if !ok {
+		http.Error(w, "action must be one of start, stop or status", http.StatusBadRequest)
+		return
+	}
+	ctx, cancelFn := context.WithTimeout(r.Context(), locateTimeout)
+	defer cancelFn()
+	resp, err := g.drives.Locate(ctx, &api.DriveLocateRequest{DriveSerialNumber: serial, Action: action})
+	if err != nil {
+		g.log.WithField("method", "locate").Errorf("Unable to locate drive %s: %v", serial, err)
+		http.Error(w, status.Convert(err).Message(), http.StatusBadGateway)
+		return
+	}
+	led := "off"
+	if resp.GetStatus() == apiV1.LocateStatusOn {
+		led = "on"
+	}
+	g.writeJSON(w, LocateResponse{SerialNumber: serial, LED: led})
+}
+
+// allow checks method of the request and authorizes it, error response is written if request isn't allowed
+// GET requests need list permission for the resource, POST requests need update permission
+func (g *Gateway) allow(w http.ResponseWriter, r *http.Request, method, resource string) bool {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		http.Error(w, "method isn't allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	verb := "list"
+	if method == http.MethodPost {
+		verb = "update"
+	}
+	code, err := g.authorizer.Authorize(r, verb, resource)
+	if err != nil {
+		g.log.WithFields(logrus.Fields{
+			"method": "allow",
+			"path":   r.URL.Path,
+		}).Warnf("Request isn't authorized: %v", err)
+		http.Error(w, err.Error(), code)
+		return false
+	}
+	return true
+}
+
+func (g *Gateway) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		g.log.WithField("method", "writeJSON").Errorf("Unable to write response: %v", err)
+	}
+}
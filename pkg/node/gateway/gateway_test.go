@@ -0,0 +1,179 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+const (
+	testNS     = "default"
+	testNodeID = "node-1"
+	testPrefix = "/api/v1"
+)
+
+var testLogger = logrus.New()
+
+type testAuthorizer struct {
+	code int
+	err  error
+}
+
+func (a *testAuthorizer) Authorize(r *http.Request, verb, resource string) (int, error) {
+	return a.code, a.err
+}
+
+type testDiscovery struct {
+	triggered int
+}
+
+func (d *testDiscovery) TriggerDiscovery() {
+	d.triggered++
+}
+
+type testDriveMgr struct {
+	api.DriveServiceClient
+	request *api.DriveLocateRequest
+}
+
+func (m *testDriveMgr) Locate(ctx context.Context, in *api.DriveLocateRequest,
+	opts ...grpc.CallOption) (*api.DriveLocateResponse, error) {
+	m.request = in
+	if in.DriveSerialNumber == "unknown" {
+		return nil, errors.New("drive isn't found")
+	}
+	return &api.DriveLocateResponse{Status: apiV1.LocateStatusOn}, nil
+}
+
+func TestGateway_List(t *testing.T) {
+	g, client, _, _ := setup(t)
+	ctx := context.Background()
+	for _, d := range []api.Drive{{UUID: "uuid-1", NodeId: testNodeID}, {UUID: "uuid-2", NodeId: "node-2"}} {
+		assert.Nil(t, client.CreateCR(ctx, d.UUID, client.ConstructDriveCR(d.UUID, d)))
+	}
+	ac := api.AvailableCapacity{Location: "uuid-1", NodeId: testNodeID, Size: 100}
+	assert.Nil(t, client.CreateCR(ctx, "ac-1", client.ConstructACCR("ac-1", ac)))
+	volume := api.Volume{Id: "volume-1", NodeId: testNodeID, Location: "uuid-1"}
+	assert.Nil(t, client.CreateCR(ctx, volume.Id, client.ConstructVolumeCR(volume.Id, testNS, volume)))
+
+	resp := serve(g, http.MethodGet, testPrefix+DrivesPath)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var drives []api.Drive
+	assert.Nil(t, json.Unmarshal(resp.Body.Bytes(), &drives))
+	assert.Len(t, drives, 1)
+	assert.Equal(t, "uuid-1", drives[0].UUID)
+
+	resp = serve(g, http.MethodGet, testPrefix+CapacityPath)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var acs []api.AvailableCapacity
+	assert.Nil(t, json.Unmarshal(resp.Body.Bytes(), &acs))
+	assert.Equal(t, []api.AvailableCapacity{ac}, acs)
+
+	resp = serve(g, http.MethodGet, testPrefix+VolumesPath)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var volumes []api.Volume
+	assert.Nil(t, json.Unmarshal(resp.Body.Bytes(), &volumes))
+	assert.Len(t, volumes, 1)
+	assert.Equal(t, "volume-1", volumes[0].Id)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(g, http.MethodPost, testPrefix+DrivesPath).Code)
+	assert.Equal(t, http.StatusNotFound, serve(g, http.MethodGet, testPrefix+"/unknown").Code)
+}
+
+func TestGateway_Discovery(t *testing.T) {
+	g, _, discovery, _ := setup(t)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(g, http.MethodGet, testPrefix+DiscoveryPath).Code)
+	assert.Equal(t, 0, discovery.triggered)
+	assert.Equal(t, http.StatusAccepted, serve(g, http.MethodPost, testPrefix+DiscoveryPath).Code)
+	assert.Equal(t, 1, discovery.triggered)
+}
+
+func TestGateway_Locate(t *testing.T) {
+	g, _, _, driveMgr := setup(t)
+
+	resp := serve(g, http.MethodPost, testPrefix+"/drives/serial-1/locate?action=start")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, &api.DriveLocateRequest{DriveSerialNumber: "serial-1", Action: apiV1.LocateStart}, driveMgr.request)
+	var locate LocateResponse
+	assert.Nil(t, json.Unmarshal(resp.Body.Bytes(), &locate))
+	assert.Equal(t, LocateResponse{SerialNumber: "serial-1", LED: "on"}, locate)
+
+	assert.Equal(t, http.StatusBadRequest, serve(g, http.MethodPost, testPrefix+"/drives/serial-1/locate?action=blink").Code)
+	assert.Equal(t, http.StatusBadGateway, serve(g, http.MethodPost, testPrefix+"/drives/unknown/locate?action=stop").Code)
+	assert.Equal(t, http.StatusNotFound, serve(g, http.MethodPost, testPrefix+"/drives//locate?action=stop").Code)
+}
+
+func TestGateway_Unauthorized(t *testing.T) {
+	g, _, discovery, _ := setup(t)
+	g.authorizer = &testAuthorizer{code: http.StatusForbidden, err: errors.New("denied")}
+
+	assert.Equal(t, http.StatusForbidden, serve(g, http.MethodGet, testPrefix+DrivesPath).Code)
+	assert.Equal(t, http.StatusForbidden, serve(g, http.MethodPost, testPrefix+DiscoveryPath).Code)
+	assert.Equal(t, 0, discovery.triggered)
+}
+
+func TestReviewAuthorizer(t *testing.T) {
+	client, err := k8s.GetFakeKubeClient(testNS, testLogger)
+	assert.Nil(t, err)
+	a := NewReviewAuthorizer(client)
+
+	req := httptest.NewRequest(http.MethodGet, DrivesPath, nil)
+	code, err := a.Authorize(req, "list", "drives")
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	code, err = a.Authorize(req, "list", "drives")
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// fake client doesn't authenticate tokens
+	req.Header.Set("Authorization", "Bearer token")
+	code, err = a.Authorize(req, "list", "drives")
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func setup(t *testing.T) (*Gateway, *k8s.KubeClient, *testDiscovery, *testDriveMgr) {
+	client, err := k8s.GetFakeKubeClient(testNS, testLogger)
+	assert.Nil(t, err)
+	discovery := &testDiscovery{}
+	driveMgr := &testDriveMgr{}
+	g := NewGateway(client, driveMgr, discovery, testNodeID, testLogger)
+	g.authorizer = &testAuthorizer{code: http.StatusOK}
+	return g, client, discovery, driveMgr
+}
+
+func serve(g *Gateway, method, target string) *httptest.ResponseRecorder {
+	resp := httptest.NewRecorder()
+	g.Handler(testPrefix+"/").ServeHTTP(resp, httptest.NewRequest(method, target, nil))
+	return resp
+}
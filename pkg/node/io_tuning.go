@@ -0,0 +1,71 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockqueue"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// SetIOTuning sets queue settings (I/O scheduler, nr_requests, read_ahead_kb) by drive type which are applied to
+// the drive of the volume on stage, settings from StorageClass parameters take precedence over them
+func (s *CSINodeService) SetIOTuning(byDriveType map[string]blockqueue.Settings) {
+	s.ioTuning = byDriveType
+}
+
+// tuneDriveQueue applies queue settings of the drive type merged with settings from StorageClass parameters to
+// the drive of the volume. Settings are shared by all volumes of the drive, so the last staged volume wins.
+// Tuning is best effort, failure is reported with event of the volume and doesn't fail staging
+// Receives volume CR and volume context of NodeStageVolumeRequest
+func (s *CSINodeService) tuneDriveQueue(ctx context.Context, volume *volumecrd.Volume, volumeContext map[string]string) {
+	ll := s.log.WithFields(logrus.Fields{
+		"method":   "tuneDriveQueue",
+		"volumeID": volume.Spec.Id,
+	})
+
+	settings, err := blockqueue.ParseSettings(volumeContext[base.IOSchedulerKey], volumeContext[base.NrRequestsKey],
+		volumeContext[base.ReadAheadKBKey])
+	if err != nil {
+		ll.Errorf("Invalid I/O tuning parameters: %v", err)
+		s.recorder.Eventf(volume, eventing.WarningType, eventing.VolumeIOTuningFailed, "Invalid I/O tuning parameters: %v", err)
+		return
+	}
+	if settings.IsEmpty() && len(s.ioTuning) == 0 {
+		return
+	}
+	drive, err := s.crHelper.GetDriveCRByVolume(ctx, volume)
+	if err != nil || drive == nil {
+		ll.Warnf("Unable to find drive of the volume, queue isn't tuned: %v", err)
+		return
+	}
+	settings = s.ioTuning[strings.ToUpper(drive.Spec.Type)].Merge(settings)
+	if settings.IsEmpty() {
+		return
+	}
+	if err = s.blockQueue.Apply(drive.Spec.Path, settings); err != nil {
+		ll.Errorf("Unable to tune queue of drive %s: %v", drive.Spec.Path, err)
+		s.recorder.Eventf(volume, eventing.WarningType, eventing.VolumeIOTuningFailed,
+			"Unable to tune queue of drive %s: %v", drive.Spec.Path, err)
+	}
+}
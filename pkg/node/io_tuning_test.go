@@ -0,0 +1,78 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockqueue"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	"github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
+
+func TestCSINodeService_TuneDriveQueue(t *testing.T) {
+	var (
+		svc    = newNodeService()
+		rec    = &mocks.NoOpRecorder{}
+		queue  = &linuxutils.MockWrapBlockQueue{}
+		volume = testVolumeCR1.DeepCopy()
+		device = "/dev/sda"
+	)
+	svc.recorder = rec
+	svc.blockQueue = queue
+
+	drive := &drivecrd.Drive{}
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, disk1.UUID, "", drive))
+	drive.Spec.Type = apiV1.DriveTypeHDD
+	drive.Spec.Path = device
+	assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, drive))
+
+	// tuning isn't configured
+	svc.tuneDriveQueue(testCtx, volume, map[string]string{})
+	queue.AssertNotCalled(t, "Apply")
+
+	// StorageClass parameters take precedence over settings of the drive type
+	svc.SetIOTuning(map[string]blockqueue.Settings{
+		apiV1.DriveTypeHDD:  {Scheduler: blockqueue.SchedulerMQDeadline, NrRequests: 256},
+		apiV1.DriveTypeNVMe: {Scheduler: blockqueue.SchedulerNone},
+	})
+	expected := blockqueue.Settings{Scheduler: blockqueue.SchedulerBFQ, NrRequests: 256, ReadAheadKB: 4096}
+	queue.On("Apply", device, expected).Return(nil).Once()
+	svc.tuneDriveQueue(testCtx, volume, map[string]string{
+		base.IOSchedulerKey: blockqueue.SchedulerBFQ,
+		base.ReadAheadKBKey: "4096",
+	})
+	queue.AssertExpectations(t)
+	assert.Empty(t, rec.Calls)
+
+	// failure is reported with event
+	queue.On("Apply", device, blockqueue.Settings{Scheduler: blockqueue.SchedulerMQDeadline, NrRequests: 256}).
+		Return(errors.New("invalid argument")).Once()
+	svc.tuneDriveQueue(testCtx, volume, map[string]string{})
+	assert.Len(t, rec.Calls, 1)
+	assert.Equal(t, eventing.VolumeIOTuningFailed, rec.Calls[0].Reason)
+
+	svc.tuneDriveQueue(testCtx, volume, map[string]string{base.NrRequestsKey: "0"})
+	assert.Len(t, rec.Calls, 2)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package journal contains write-ahead journal of multi-step node operations, it is stored on the host,
+// so operations interrupted by crash of the node service are finished or reverted on start
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
+
+// Operation is a multi-step operation with the volume
+type Operation string
+
+// Journaled operations
+const (
+	// OperationCreate creates partition or logical volume and file system
+	OperationCreate Operation = "create"
+	// OperationDelete removes partition or logical volume
+	OperationDelete Operation = "delete"
+	// OperationStage mounts device of the volume into staging path
+	OperationStage Operation = "stage"
+)
+
+// Steps of the operations which are recorded once they are finished
+const (
+	// StepProvisioned is recorded when partition or logical volume with file system was created
+	StepProvisioned = "provisioned"
+	// StepReleased is recorded when partition or logical volume was removed
+	StepReleased = "released"
+	// StepMounted is recorded when device was mounted into staging path
+	StepMounted = "mounted"
+)
+
+const entrySuffix = ".json"
+
+// Entry is a record about operation which isn't finished yet
+type Entry struct {
+	Operation Operation `json:"operation"`
+	// Volume holds spec of the volume, so operation could be reverted without Volume CR
+	Volume api.Volume `json:"volume"`
+	// StagingPath is set for stage operation
+	StagingPath string    `json:"stagingPath,omitempty"`
+	Started     time.Time `json:"started"`
+	// Steps lists finished steps of the operation
+	Steps []string `json:"steps,omitempty"`
+}
+
+// HasStep returns true if step of the operation was finished
+func (e *Entry) HasStep(step string) bool {
+	return util.ContainsString(e.Steps, step)
+}
+
+// Journal keeps entry of each unfinished operation in a separate file named by volume ID,
+// files are replaced atomically, so entry read after crash is either previous or new one.
+// Methods of nil Journal do nothing, so journaling could be disabled
+type Journal struct {
+	dir string
+}
+
+// New is the constructor for Journal
+// Receives directory of the journal on the host, it is created on the first write
+func New(dir string) *Journal {
+	return &Journal{dir: dir}
+}
+
+// Begin records start of the operation, entry of the previous operation with the volume is replaced
+// Returns error if entry wasn't stored, operation must not be started in this case
+func (j *Journal) Begin(op Operation, volume api.Volume, stagingPath string) error {
+	if j == nil {
+		return nil
+	}
+	return j.write(&Entry{Operation: op, Volume: volume, StagingPath: stagingPath, Started: time.Now()})
+}
+
+// Step records finished step of the operation with the volume
+func (j *Journal) Step(volumeID, step string) error {
+	if j == nil {
+		return nil
+	}
+	entry, err := j.read(j.path(volumeID))
+	if err != nil {
+		return err
+	}
+	if !entry.HasStep(step) {
+		entry.Steps = append(entry.Steps, step)
+	}
+	return j.write(entry)
+}
+
+// Finish removes entry of the operation with the volume, missing entry isn't an error
+func (j *Journal) Finish(volumeID string) error {
+	if j == nil {
+		return nil
+	}
+	if err := os.Remove(j.path(volumeID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Pending returns entries of unfinished operations, missing directory means there are no entries
+func (j *Journal) Pending() ([]*Entry, error) {
+	if j == nil {
+		return nil, nil
+	}
+	files, err := ioutil.ReadDir(j.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*Entry, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), entrySuffix) {
+			continue
+		}
+		entry, err := j.read(filepath.Join(j.dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (j *Journal) path(volumeID string) string {
+	return filepath.Join(j.dir, volumeID+entrySuffix)
+}
+
+func (j *Journal) read(path string) (*Entry, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entry := &Entry{}
+	if err = json.Unmarshal(data, entry); err != nil {
+		return nil, fmt.Errorf("journal entry %s is corrupted: %w", path, err)
+	}
+	return entry, nil
+}
+
+// write stores entry into temporary file which is synced and renamed over the previous entry
+func (j *Journal) write(entry *Entry) error {
+	if err := os.MkdirAll(j.dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(j.dir, ".entry-")
+	if err != nil {
+		return err
+	}
+	// temporary file is left only if entry wasn't stored
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), j.path(entry.Volume.Id)); err != nil {
+		return err
+	}
+	return syncDir(j.dir)
+}
+
+// syncDir makes rename durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = d.Close() }()
+	return d.Sync()
+}
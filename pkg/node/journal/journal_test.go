@@ -0,0 +1,90 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package journal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+)
+
+func TestJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	j := New(filepath.Join(dir, "journal"))
+
+	// directory is created on the first write
+	entries, err := j.Pending()
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	volume := api.Volume{Id: "pvc-1", Location: "drive-1"}
+	assert.Nil(t, j.Begin(OperationCreate, volume, ""))
+	assert.Nil(t, j.Step(volume.Id, StepProvisioned))
+	assert.Nil(t, j.Step(volume.Id, StepProvisioned))
+	assert.Nil(t, j.Begin(OperationStage, api.Volume{Id: "pvc-2"}, "/staging"))
+
+	entries, err = j.Pending()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	for _, entry := range entries {
+		switch entry.Volume.Id {
+		case volume.Id:
+			assert.Equal(t, OperationCreate, entry.Operation)
+			assert.Equal(t, volume, entry.Volume)
+			assert.Equal(t, []string{StepProvisioned}, entry.Steps)
+		default:
+			assert.Equal(t, OperationStage, entry.Operation)
+			assert.Equal(t, "/staging", entry.StagingPath)
+			assert.False(t, entry.HasStep(StepMounted))
+		}
+	}
+
+	// the next operation replaces entry
+	assert.Nil(t, j.Begin(OperationDelete, volume, ""))
+	assert.Nil(t, j.Finish("pvc-2"))
+	assert.Nil(t, j.Finish("pvc-2"))
+	entries, err = j.Pending()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, OperationDelete, entries[0].Operation)
+	assert.Empty(t, entries[0].Steps)
+
+	// step of unknown operation isn't recorded
+	assert.NotNil(t, j.Step("pvc-3", StepMounted))
+
+	// corrupted entry is reported
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "journal", "pvc-4.json"), []byte("{"), 0600))
+	_, err = j.Pending()
+	assert.NotNil(t, err)
+}
+
+func TestJournal_Nil(t *testing.T) {
+	var j *Journal
+	assert.Nil(t, j.Begin(OperationCreate, api.Volume{Id: "pvc-1"}, ""))
+	assert.Nil(t, j.Step("pvc-1", StepProvisioned))
+	assert.Nil(t, j.Finish("pvc-1"))
+	entries, err := j.Pending()
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/clock"
 )
 
 const (
@@ -39,6 +40,12 @@ type LivenessHelper interface {
 
 // NewLivenessCheckHelper returns new instance of LivenessCheckHelper
 func NewLivenessCheckHelper(logger *logrus.Logger, ttl *time.Duration, timeout *time.Duration) *LivenessCheckHelper {
+	return newLivenessCheckHelper(logger, ttl, timeout, clock.RealClock{})
+}
+
+// newLivenessCheckHelper returns new instance of LivenessCheckHelper which measures TTL and timeout by provided clock
+func newLivenessCheckHelper(logger *logrus.Logger, ttl *time.Duration, timeout *time.Duration,
+	clk clock.PassiveClock) *LivenessCheckHelper {
 	tTTL := LivenessDefaultTTL
 	if ttl != nil {
 		tTTL = *ttl
@@ -50,7 +57,8 @@ func NewLivenessCheckHelper(logger *logrus.Logger, ttl *time.Duration, timeout *
 	return &LivenessCheckHelper{
 		ttl:     tTTL,
 		timeout: tTimeout,
-		lastOK:  time.Now(),
+		clock:   clk,
+		lastOK:  clk.Now(),
 		isOK:    true,
 		logger:  logger.WithField("component", "LivenessCheckHelper"),
 	}
@@ -63,6 +71,7 @@ type LivenessCheckHelper struct {
 
 	ttl     time.Duration
 	timeout time.Duration
+	clock   clock.PassiveClock
 
 	lastOK time.Time
 	isOK   bool
@@ -71,7 +80,7 @@ type LivenessCheckHelper struct {
 // OK marks check as OK, update TTL
 func (h *LivenessCheckHelper) OK() {
 	h.m.Lock()
-	h.lastOK = time.Now()
+	h.lastOK = h.clock.Now()
 	h.isOK = true
 	h.logger.Debug("updated with OK")
 	h.m.Unlock()
@@ -89,7 +98,7 @@ func (h *LivenessCheckHelper) Fail() {
 func (h *LivenessCheckHelper) Check() bool {
 	h.m.RLock()
 	defer h.m.RUnlock()
-	if time.Now().Before(h.lastOK.Add(h.ttl)) {
+	if h.clock.Now().Before(h.lastOK.Add(h.ttl)) {
 		// ttl not expired yet
 		h.logger.Debug("Check: OK")
 		return true
@@ -99,7 +108,7 @@ func (h *LivenessCheckHelper) Check() bool {
 		h.logger.Debug("Check: failed")
 		return false
 	}
-	if time.Since(h.lastOK) > h.timeout {
+	if h.clock.Since(h.lastOK) > h.timeout {
 		// hard timeout: fail not detected, but there are no OKs for a long time
 		h.logger.Warn("Check: failed, hard timeout")
 		return false
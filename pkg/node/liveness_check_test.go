@@ -22,12 +22,14 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
 )
 
 func TestNodeLivenessCheck(t *testing.T) {
 
 	logger := logrus.New()
 	logger.SetLevel(logrus.DebugLevel)
+	fakeClock := clock.NewFakeClock(time.Now())
 
 	t.Run("OK by default", func(t *testing.T) {
 		check := NewLivenessCheckHelper(logger, nil, nil)
@@ -42,31 +44,31 @@ func TestNodeLivenessCheck(t *testing.T) {
 
 	t.Run("Marked as failed, ttl expired", func(t *testing.T) {
 		ttl := time.Millisecond * 10
-		check := NewLivenessCheckHelper(logger, &ttl, nil)
+		check := newLivenessCheckHelper(logger, &ttl, nil, fakeClock)
 		check.Fail()
-		time.Sleep(ttl)
+		fakeClock.Step(ttl)
 		assert.False(t, check.Check())
 	})
 
 	t.Run("Marked OK, no updates for a long time", func(t *testing.T) {
 		ttl := time.Millisecond * 10
-		check := NewLivenessCheckHelper(logger, &ttl, nil)
-		time.Sleep(ttl)
+		check := newLivenessCheckHelper(logger, &ttl, nil, fakeClock)
+		fakeClock.Step(ttl)
 		assert.True(t, check.Check())
 	})
 	t.Run("Marked OK, no updates for a long time, timeout expired", func(t *testing.T) {
 		ttl := time.Millisecond * 10
 		timeout := time.Millisecond * 20
-		check := NewLivenessCheckHelper(logger, &ttl, &timeout)
-		time.Sleep(timeout)
+		check := newLivenessCheckHelper(logger, &ttl, &timeout, fakeClock)
+		fakeClock.Step(timeout + time.Nanosecond)
 		assert.False(t, check.Check())
 	})
 
 	t.Run("Recover", func(t *testing.T) {
 		ttl := time.Millisecond * 10
-		check := NewLivenessCheckHelper(logger, &ttl, nil)
+		check := newLivenessCheckHelper(logger, &ttl, nil, fakeClock)
 		check.Fail()
-		time.Sleep(ttl)
+		fakeClock.Step(ttl)
 		assert.False(t, check.Check())
 		check.OK()
 		assert.True(t, check.Check())
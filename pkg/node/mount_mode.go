@@ -0,0 +1,126 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base/capabilities"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+)
+
+// Mount modes of the node service
+const (
+	// MountModeAuto means that nsenter is used if mount syscall could be restricted by seccomp and node pod is run
+	// with hostPID, mount operations are not possible otherwise
+	MountModeAuto = "auto"
+	// MountModeDirect means that mount/umount are run in the node container
+	MountModeDirect = "direct"
+	// MountModeNsenter means that mount/umount are run in the host mount namespace via nsenter
+	MountModeNsenter = "nsenter"
+)
+
+// mountCmds are the commands which are run via nsenter in the nsenter mount mode
+var mountCmds = []string{"mount", "umount"}
+
+// procPath is a mount point of procfs, nsenter enters mount namespace of its pid 1
+var procPath = "/proc"
+
+// kthreadd is a kernel thread with pid 2, kernel threads are visible only in the pid namespace of the host
+const kthreadd = "kthreadd"
+
+// checkHostPID verifies that pid 1 seen by the node container is init of the host, so nsenter --target 1 enters
+// the host mount namespace. It is so only if node pod is run with hostPID
+// Returns error if pid 1 is in the mount namespace of the container or pid namespace isn't the host one
+func checkHostPID() error {
+	targetNs, err := os.Readlink(filepath.Join(procPath, "1/ns/mnt"))
+	if err != nil {
+		return fmt.Errorf("unable to read mount namespace of pid 1: %w", err)
+	}
+	selfNs, err := os.Readlink(filepath.Join(procPath, "self/ns/mnt"))
+	if err != nil {
+		return fmt.Errorf("unable to read mount namespace of the node service: %w", err)
+	}
+	if targetNs == selfNs {
+		return errors.New("pid 1 is in the mount namespace of the node container, node pod isn't run with hostPID")
+	}
+	comm, err := ioutil.ReadFile(filepath.Join(procPath, "2/comm"))
+	if err != nil || strings.TrimSpace(string(comm)) != kthreadd {
+		return errors.New("pid namespace of the node container isn't the host one, node pod isn't run with hostPID")
+	}
+	return nil
+}
+
+// PrepareMountExecutor checks capabilities and seccomp mode of the node container and chooses the way
+// mount operations are performed in according with the mount mode
+// Receives CmdExecutor, mount mode, capabilities status of the node process and logrus logger
+// Returns CmdExecutor for the node service and error if mount operations are not possible in the node container,
+// in that case node service should not be marked as ready
+func PrepareMountExecutor(e command.CmdExecutor, mode string, status *capabilities.Status,
+	logger *logrus.Logger) (command.CmdExecutor, error) {
+	ll := logger.WithField("method", "PrepareMountExecutor")
+
+	switch mode {
+	case MountModeAuto, MountModeDirect, MountModeNsenter:
+	default:
+		return e, fmt.Errorf("unknown mount mode %s, supported values are %s, %s, %s",
+			mode, MountModeAuto, MountModeDirect, MountModeNsenter)
+	}
+
+	// CAP_SYS_ADMIN is required both for mount and for entering host mount namespace
+	if !status.Has(capabilities.CapSysAdmin) {
+		return e, errors.New("CAP_SYS_ADMIN capability is missing, mount operations are not possible. " +
+			"Run node container as privileged or use privileged helper")
+	}
+
+	useNsenter := mode == MountModeNsenter
+	if mode == MountModeAuto && status.SeccompFiltered() {
+		if command.IsAvailable(command.NsenterCmd) {
+			// running nsenter in the namespace of another container of the pod is worse than failing
+			if err := checkHostPID(); err != nil {
+				return e, fmt.Errorf("syscalls are filtered by seccomp (mode %d) and %s can't be used: %v. "+
+					"Use %s mount mode, which runs node pod with hostPID, or privileged helper",
+					status.Seccomp, command.NsenterCmd, err, MountModeNsenter)
+			}
+			ll.Warnf("Syscalls are filtered by seccomp (mode %d), mount operations will be run via %s",
+				status.Seccomp, command.NsenterCmd)
+			useNsenter = true
+		} else {
+			ll.Warnf("Syscalls are filtered by seccomp (mode %d) and %s isn't available, mount operations could fail",
+				status.Seccomp, command.NsenterCmd)
+		}
+	}
+
+	if !useNsenter {
+		return e, nil
+	}
+	if !command.IsAvailable(command.NsenterCmd) {
+		return e, fmt.Errorf("mount mode is %s but %s isn't available", MountModeNsenter, command.NsenterCmd)
+	}
+	if err := checkHostPID(); err != nil {
+		return e, fmt.Errorf("mount mode is %s but host mount namespace can't be entered: %w", MountModeNsenter, err)
+	}
+	ll.Infof("Mount operations are run in the host mount namespace")
+	return command.NewNsenterExecutor(e, logger, mountCmds...), nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dell/csi-baremetal/pkg/base/capabilities"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+// prepareProc creates procfs with mount namespaces of pid 1 and the current process and kernel thread
+// which is visible in the host pid namespace only
+func prepareProc(t *testing.T, hostMntNs, kernelThread bool) {
+	dir, err := ioutil.TempDir("", "proc")
+	assert.Nil(t, err)
+	procPath = dir
+	t.Cleanup(func() {
+		procPath = "/proc"
+		_ = os.RemoveAll(dir)
+	})
+
+	initNs := "mnt:[4026532100]"
+	if hostMntNs {
+		initNs = "mnt:[4026531840]"
+	}
+	for pid, ns := range map[string]string{"1": initNs, "self": "mnt:[4026532100]"} {
+		assert.Nil(t, os.MkdirAll(filepath.Join(dir, pid, "ns"), 0700))
+		assert.Nil(t, os.Symlink(ns, filepath.Join(dir, pid, "ns/mnt")))
+	}
+	if kernelThread {
+		assert.Nil(t, os.MkdirAll(filepath.Join(dir, "2"), 0700))
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "2/comm"), []byte(kthreadd+"\n"), 0600))
+	}
+}
+
+func TestCheckHostPID(t *testing.T) {
+	prepareProc(t, true, true)
+	assert.Nil(t, checkHostPID())
+
+	// pid 1 is the node service itself
+	prepareProc(t, false, false)
+	assert.NotNil(t, checkHostPID())
+
+	// pid 1 is the pause container of the pod with shared process namespace
+	prepareProc(t, true, false)
+	assert.NotNil(t, checkHostPID())
+}
+
+func TestPrepareMountExecutor(t *testing.T) {
+	defer func() { command.LookPath = exec.LookPath }()
+	command.LookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+	prepareProc(t, true, true)
+
+	var (
+		e          = &mocks.GoMockExecutor{}
+		privileged = &capabilities.Status{Effective: 1 << capabilities.CapSysAdmin}
+		filtered   = &capabilities.Status{Effective: 1 << capabilities.CapSysAdmin, Seccomp: capabilities.SeccompFilter}
+	)
+
+	res, err := PrepareMountExecutor(e, MountModeAuto, privileged, testLogger)
+	assert.Nil(t, err)
+	assert.Equal(t, e, res)
+
+	res, err = PrepareMountExecutor(e, MountModeAuto, filtered, testLogger)
+	assert.Nil(t, err)
+	assert.IsType(t, &command.NsenterExecutor{}, res)
+
+	res, err = PrepareMountExecutor(e, MountModeDirect, filtered, testLogger)
+	assert.Nil(t, err)
+	assert.Equal(t, e, res)
+
+	res, err = PrepareMountExecutor(e, MountModeNsenter, privileged, testLogger)
+	assert.Nil(t, err)
+	assert.IsType(t, &command.NsenterExecutor{}, res)
+
+	// node pod isn't run with hostPID
+	prepareProc(t, false, false)
+	_, err = PrepareMountExecutor(e, MountModeAuto, filtered, testLogger)
+	assert.NotNil(t, err)
+	_, err = PrepareMountExecutor(e, MountModeNsenter, privileged, testLogger)
+	assert.NotNil(t, err)
+	res, err = PrepareMountExecutor(e, MountModeAuto, privileged, testLogger)
+	assert.Nil(t, err)
+	assert.Equal(t, e, res)
+
+	_, err = PrepareMountExecutor(e, MountModeAuto, &capabilities.Status{}, testLogger)
+	assert.NotNil(t, err)
+
+	_, err = PrepareMountExecutor(e, "unknown", privileged, testLogger)
+	assert.NotNil(t, err)
+
+	command.LookPath = func(file string) (string, error) { return "", exec.ErrNotFound }
+	res, err = PrepareMountExecutor(e, MountModeAuto, filtered, testLogger)
+	assert.Nil(t, err)
+	assert.Equal(t, e, res)
+
+	_, err = PrepareMountExecutor(e, MountModeNsenter, privileged, testLogger)
+	assert.NotNil(t, err)
+}
@@ -33,19 +33,29 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/utils/keymutex"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/bytesize"
 	"github.com/dell/csi-baremetal/pkg/base/cache"
 	"github.com/dell/csi-baremetal/pkg/base/command"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockqueue"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/common"
 	"github.com/dell/csi-baremetal/pkg/controller"
 	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	"github.com/dell/csi-baremetal/pkg/metrics"
+	metricsC "github.com/dell/csi-baremetal/pkg/metrics/common"
+	"github.com/dell/csi-baremetal/pkg/node/faults"
+	"github.com/dell/csi-baremetal/pkg/node/journal"
 )
 
 const stagingFileName = "dev"
@@ -64,6 +74,24 @@ type CSINodeService struct {
 
 	// used for locking requests on each volume
 	volMu keymutex.KeyMutex
+	// reason why node svc can't serve requests even after initialization, for example missing capabilities
+	readinessErr error
+	readinessMu  sync.RWMutex
+	// node labels which are reported as topology keys in addition to node ID
+	topologyLabels []string
+	// how volume which device holds file system of another type is handled on stage
+	fsMismatchPolicy string
+	// total size of RAM read caches of the volumes published on the node, 0 means no limit,
+	// readCacheMu serializes check of the limit and mount of the cache
+	readCacheLimit int64
+	readCacheMu    sync.Mutex
+	// command which is run on publish of the volume with "command" prewarm mode
+	prewarmCommand string
+	// queue settings of the drives by drive type which are applied on stage
+	blockQueue blockqueue.WrapBlockQueue
+	ioTuning   map[string]blockqueue.Settings
+	// clock of discovery and integrity check loops
+	clock clock.Clock
 }
 
 const (
@@ -78,23 +106,28 @@ const (
 )
 
 // NewCSINodeService is the constructor for CSINodeService struct
-// Receives an instance of DriveServiceClient to interact with DriveManager, ID of a node where it works, logrus logger
-// and base.KubeClient
+// Receives an instance of DriveServiceClient to interact with DriveManager, CmdExecutor which runs system utils,
+// ID of a node where it works, logrus logger and base.KubeClient
 // Returns an instance of CSINodeService
 func NewCSINodeService(client api.DriveServiceClient,
+	e command.CmdExecutor,
 	nodeID string,
 	logger *logrus.Logger,
 	k8sClient *k8s.KubeClient,
 	k8sCache k8s.CRReader,
 	recorder eventRecorder,
 	featureConf featureconfig.FeatureChecker) *CSINodeService {
-	e := command.NewExecutor(logger)
+	realClock := clock.RealClock{}
 	s := &CSINodeService{
 		VolumeManager:  *NewVolumeManager(client, e, logger, k8sClient, k8sCache, recorder, nodeID),
 		svc:            common.NewVolumeOperationsImpl(k8sClient, logger, cache.NewMemCache(), featureConf),
 		IdentityServer: controller.NewIdentityServer(base.PluginName, base.PluginVersion),
 		volMu:          keymutex.NewHashed(0),
-		livenessCheck:  NewLivenessCheckHelper(logger, nil, nil),
+		livenessCheck:  newLivenessCheckHelper(logger, nil, nil, realClock),
+		clock:          realClock,
+
+		fsMismatchPolicy: FSMismatchFail,
+		blockQueue:       blockqueue.NewBlockQueue(e, logger),
 	}
 	s.log = logger.WithField("component", "CSINodeService")
 	return s
@@ -142,7 +175,7 @@ func (s *CSINodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStage
 		"volumeID": req.GetVolumeId(),
 	})
 
-	ll.Infof("locking volume on request: %v", req)
+	ll.Infof("locking volume on request: %v", util.StripSecrets(req))
 	s.volMu.LockKey(req.GetVolumeId())
 	defer func() {
 		err := s.volMu.UnlockKey(req.GetVolumeId())
@@ -166,13 +199,23 @@ func (s *CSINodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStage
 	}
 
 	volumeID := req.VolumeId
-	volumeCR, err := s.crHelper.GetVolumeByID(volumeID)
+	volumeCR, err := s.crHelper.GetVolumeByID(ctx, volumeID)
 	if err != nil {
 		message := fmt.Sprintf("Unable to find volume with ID %s", volumeID)
 		ll.Error(message)
 		return nil, status.Error(codes.NotFound, message)
 	}
 
+	if volumeCR.IsForceReleased() {
+		ll.Error("Volume was force released")
+		return nil, status.Error(codes.FailedPrecondition, "volume was force released, node of the volume is removed")
+	}
+	// volumes are local, without ControllerPublishVolume it is checked only here
+	if volumeCR.Spec.NodeId != s.nodeID {
+		ll.Errorf("Volume is located on node %s", volumeCR.Spec.NodeId)
+		return nil, status.Error(codes.NotFound, "volume is not accessible from node")
+	}
+
 	currStatus := volumeCR.Spec.CSIStatus
 	// if currStatus not in [Created (first call), VolumeReady (retry), Published (multiple pods)]
 	if currStatus != apiV1.Created && currStatus != apiV1.VolumeReady && currStatus != apiV1.Published {
@@ -184,31 +227,77 @@ func (s *CSINodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStage
 
 	targetPath := getStagingPath(ll, req.GetStagingTargetPath())
 
-	partition, err := s.getProvisionerForVolume(&volumeCR.Spec).GetVolumePath(volumeCR.Spec)
+	if !s.tryLockDevice(volumeID) {
+		ll.Warn("File system check of the volume is in progress")
+		return nil, status.Error(codes.Unavailable, "failed to stage volume: file system check is in progress")
+	}
+	defer s.unlockDevice(volumeID)
+
+	partition, err := s.getProvisionerForVolume(&volumeCR.Spec).GetVolumePath(ctx, volumeCR.Spec)
 	if err != nil {
 		ll.Errorf("failed to get partition, for volume %v: %v", volumeCR.Spec, err)
 		return nil, status.Error(codes.Internal, "failed to stage volume: partition error")
 	}
 	ll.Infof("Work with partition %s", partition)
+	if err = s.faults.Inject(ctx, faults.StageVolume); err != nil {
+		ll.Errorf("Injected failure: %v", err)
+		return nil, status.Error(codes.Internal, "failed to stage volume: injected failure")
+	}
+	// file system is already checked if volume is published
+	if currStatus != apiV1.Published {
+		if err = s.checkVolumeFS(ctx, volumeCR, partition); err != nil {
+			return nil, err
+		}
+	}
 
 	var (
 		resp        = &csi.NodeStageVolumeResponse{}
 		errToReturn error
 		newStatus   = apiV1.VolumeReady
+		observe     = metricsC.VolumePhaseDuration.EvaluateDurationForPhase(string(volumecrd.VolumePhaseStaged))
 	)
+	// the first staging interrupted by crash is reverted on start, kubelet repeats it
+	journaled := currStatus == apiV1.Created
+	if journaled {
+		if err := s.journal.Begin(journal.OperationStage, volumeCR.Spec, targetPath); err != nil {
+			ll.Errorf("Unable to record staging in journal: %v", err)
+			return nil, status.Error(codes.Internal, "failed to stage volume: journal error")
+		}
+	}
 	if err := s.fsOps.PrepareAndPerformMount(partition, targetPath, true, false); err != nil {
 		ll.Errorf("Unable to prepare and mount: %v. Going to set volumes status to failed", err)
 		newStatus = apiV1.Failed
 		resp, errToReturn = nil, status.Error(codes.Internal, "failed to stage volume: mount error")
+	} else if currStatus == apiV1.Created {
+		observe()
+		s.journalStep(volumeID, journal.StepMounted)
+	}
+	if errToReturn == nil {
+		// queue settings aren't persistent, so they are applied on each stage, e.g. after node reboot
+		s.tuneDriveQueue(ctx, volumeCR, req.GetVolumeContext())
+	}
+
+	// staging path is remembered to restore the mount after node reboot
+	stagingPathChanged := false
+	if newStatus != apiV1.Failed && volumeCR.Annotations[apiV1.VolumeAnnotationStagingPath] != targetPath {
+		if volumeCR.Annotations == nil {
+			volumeCR.Annotations = map[string]string{}
+		}
+		volumeCR.Annotations[apiV1.VolumeAnnotationStagingPath] = targetPath
+		stagingPathChanged = true
 	}
 
-	if currStatus != apiV1.VolumeReady || newStatus == apiV1.Failed {
+	if currStatus != apiV1.VolumeReady || newStatus == apiV1.Failed || stagingPathChanged {
 		volumeCR.Spec.CSIStatus = newStatus
-		if err := s.crHelper.UpdateVolumeCRSpec(volumeCR.Name, volumeCR.Namespace, volumeCR.Spec); err != nil {
+		ctxWithID := context.WithValue(context.Background(), base.RequestUUID, volumeID)
+		if err := s.k8sClient.UpdateCR(ctxWithID, volumeCR); err != nil {
 			ll.Errorf("Unable to set volume status to %s: %v", newStatus, err)
-			resp, errToReturn = nil, fmt.Errorf("failed to stage volume: update volume CR error")
+			return nil, fmt.Errorf("failed to stage volume: update volume CR error")
 		}
 	}
+	if journaled {
+		s.journalFinish(volumeID)
+	}
 
 	return resp, errToReturn
 }
@@ -223,7 +312,7 @@ func (s *CSINodeService) NodeUnstageVolume(ctx context.Context, req *csi.NodeUns
 		"volumeID": req.GetVolumeId(),
 	})
 
-	ll.Infof("locking volume on request: %v", req)
+	ll.Infof("locking volume on request: %v", util.StripSecrets(req))
 	s.volMu.LockKey(req.GetVolumeId())
 	defer func() {
 		err := s.volMu.UnlockKey(req.GetVolumeId())
@@ -242,7 +331,7 @@ func (s *CSINodeService) NodeUnstageVolume(ctx context.Context, req *csi.NodeUns
 	if len(req.GetStagingTargetPath()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Stage Path missing in request")
 	}
-	volumeCR, err := s.crHelper.GetVolumeByID(req.GetVolumeId())
+	volumeCR, err := s.crHelper.GetVolumeByID(ctx, req.GetVolumeId())
 	if err != nil {
 		return nil, status.Error(codes.NotFound, "Unable to find volume")
 	}
@@ -259,6 +348,7 @@ func (s *CSINodeService) NodeUnstageVolume(ctx context.Context, req *csi.NodeUns
 	}
 
 	volumeCR.Spec.CSIStatus = apiV1.Created
+	delete(volumeCR.Annotations, apiV1.VolumeAnnotationStagingPath)
 
 	var (
 		resp        = &csi.NodeUnstageVolumeResponse{}
@@ -289,7 +379,7 @@ func (s *CSINodeService) NodePublishVolume(ctx context.Context, req *csi.NodePub
 		"volumeID": req.GetVolumeId(),
 	})
 
-	ll.Infof("locking volume on request: %v", req)
+	ll.Infof("locking volume on request: %v", util.StripSecrets(req))
 	s.volMu.LockKey(req.GetVolumeId())
 	defer func() {
 		err := s.volMu.UnlockKey(req.GetVolumeId())
@@ -340,7 +430,7 @@ func (s *CSINodeService) NodePublishVolume(ctx context.Context, req *csi.NodePub
 			ll.Errorf("Failed to create inline volume: %v", err)
 			return nil, status.Error(codes.Internal, "unable to create inline volume")
 		}
-		srcPath, err = s.getProvisionerForVolume(vol).GetVolumePath(*vol)
+		srcPath, err = s.getProvisionerForVolume(vol).GetVolumePath(ctx, *vol)
 		if err != nil {
 			ll.Errorf("failed to get partition for volume %v: %v", vol, err)
 			return nil, status.Error(codes.Internal, "failed to publish inline volume: partition error")
@@ -349,7 +439,7 @@ func (s *CSINodeService) NodePublishVolume(ctx context.Context, req *csi.NodePub
 		return nil, status.Error(codes.InvalidArgument, "Staging Path missing in request")
 	}
 
-	volumeCR, err := s.crHelper.GetVolumeByID(volumeID)
+	volumeCR, err := s.crHelper.GetVolumeByID(ctx, volumeID)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "Unable to find volume")
 	}
@@ -367,13 +457,36 @@ func (s *CSINodeService) NodePublishVolume(ctx context.Context, req *csi.NodePub
 		resp        = &csi.NodePublishVolumeResponse{}
 		newStatus   = apiV1.Published
 		errToReturn error
+		observe     = metricsC.VolumePhaseDuration.EvaluateDurationForPhase(string(volumecrd.VolumePhasePublished))
 	)
 
 	_, isBlock := req.GetVolumeCapability().GetAccessType().(*csi.VolumeCapability_Block)
-	if err := s.fsOps.PrepareAndPerformMount(srcPath, dstPath, isBlock, !isBlock); err != nil {
+	var mountOpts []string
+	if !isBlock && volumeCR.Spec.StorageClass == apiV1.StorageClassPMEM {
+		// file system on persistent memory is mounted with direct access
+		mountOpts = append(mountOpts, fs.DAXOption)
+	}
+	cacheSize, err := readCacheSize(req.GetVolumeContext())
+	if err != nil {
+		return nil, err
+	}
+	if cacheSize > 0 && !isBlock {
+		err = s.publishWithReadCache(srcPath, dstPath, cacheSize)
+		if errors.Is(err, errTypes.ErrCapacityExhausted) {
+			ll.Error(err)
+			return nil, errTypes.ToGRPCStatus(err)
+		}
+	} else {
+		err = s.fsOps.PrepareAndPerformMount(srcPath, dstPath, isBlock, !isBlock, mountOpts...)
+	}
+	if err != nil {
 		ll.Errorf("Unable to mount volume: %v", err)
 		newStatus = apiV1.Failed
 		resp, errToReturn = nil, fmt.Errorf("failed to publish volume: mount error")
+	} else if currStatus != apiV1.Published {
+		observe()
+		// page cache is shared by all pods which use the volume, so it is populated on the first publish only
+		s.prewarmVolume(ctx, volumeCR, req.GetVolumeContext(), dstPath, isBlock)
 	}
 
 	var podName string
@@ -421,7 +534,7 @@ func (s *CSINodeService) createInlineVolume(ctx context.Context, volumeID string
 	}
 	ctxWithNamespace := context.WithValue(ctx, base.VolumeNamespace, namespace)
 
-	if bytes, err = util.StrToBytes(bytesStr); err != nil {
+	if bytes, err = bytesize.Parse(bytesStr); err != nil {
 		return nil, err
 	}
 
@@ -473,7 +586,7 @@ func (s *CSINodeService) NodeUnpublishVolume(ctx context.Context, req *csi.NodeU
 		"volumeID": req.GetVolumeId(),
 	})
 
-	ll.Infof("locking volume on request: %v", req)
+	ll.Infof("locking volume on request: %v", util.StripSecrets(req))
 	s.volMu.LockKey(req.GetVolumeId())
 	defer func() {
 		err := s.volMu.UnlockKey(req.GetVolumeId())
@@ -493,7 +606,7 @@ func (s *CSINodeService) NodeUnpublishVolume(ctx context.Context, req *csi.NodeU
 		return nil, status.Error(codes.InvalidArgument, "Target Path missing in request")
 	}
 
-	volumeCR, err := s.crHelper.GetVolumeByID(req.GetVolumeId())
+	volumeCR, err := s.crHelper.GetVolumeByID(ctx, req.GetVolumeId())
 	if err != nil {
 		return nil, status.Error(codes.NotFound, "Unable to find volume")
 	}
@@ -516,6 +629,10 @@ func (s *CSINodeService) NodeUnpublishVolume(ctx context.Context, req *csi.NodeU
 		}
 		return nil, status.Error(codes.Internal, "unmount error")
 	}
+	if err := s.teardownReadCache(req.GetTargetPath()); err != nil {
+		ll.Errorf("Unable to tear down read cache: %v", err)
+		return nil, status.Error(codes.Internal, "read cache unmount error")
+	}
 
 	volumeCR.Spec.Owners = nil
 	// k8s doesn't call DeleteVolume for inline volumes, so we perform DeleteVolume operation in Unpublish request
@@ -530,11 +647,14 @@ func (s *CSINodeService) NodeUnpublishVolume(ctx context.Context, req *csi.NodeU
 				return &csi.NodeUnpublishVolumeResponse{}, nil
 			}
 			ll.Errorf("Unable to delete volume: %v", err)
-			return nil, err
+			return nil, errTypes.ToGRPCStatus(err)
 		}
 
 		if err = s.svc.WaitStatus(ctx, req.VolumeId, apiV1.Failed, apiV1.Removed); err != nil {
 			ll.Warn("Status wasn't reached")
+			if errors.Is(err, errTypes.ErrOperationInProgress) {
+				return nil, errTypes.ToGRPCStatus(err)
+			}
 			return nil, status.Error(codes.Internal, "Unable to delete volume")
 		}
 		s.reqMu.Lock()
@@ -591,6 +711,16 @@ func (s *CSINodeService) NodeGetInfo(ctx context.Context, req *csi.NodeGetInfoRe
 			csibmnodeconst.NodeIDAnnotationKey: s.nodeID,
 		},
 	}
+	if len(s.topologyLabels) > 0 {
+		node, err := s.k8sClient.GetNodeByID(ctx, s.nodeID)
+		if err != nil {
+			ll.Errorf("Unable to read node: %v", err)
+			return nil, status.Error(codes.Unavailable, "unable to read topology labels of the node")
+		}
+		for key, value := range k8s.TopologySegments(node, s.topologyLabels) {
+			topology.Segments[key] = value
+		}
+	}
 
 	ll.Infof("NodeGetInfo created topology: %v", topology)
 
@@ -606,6 +736,14 @@ func (s *CSINodeService) Check(ctx context.Context, req *grpc_health_v1.HealthCh
 		"method": "Check",
 	})
 
+	s.readinessMu.RLock()
+	readinessErr := s.readinessErr
+	s.readinessMu.RUnlock()
+	if readinessErr != nil {
+		ll.Errorf("Node svc is not able to serve requests: %v", readinessErr)
+		return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING}, nil
+	}
+
 	if !s.initialized {
 		ll.Info("Node svc is not ready yet")
 		return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING}, nil
@@ -614,6 +752,24 @@ func (s *CSINodeService) Check(ctx context.Context, req *grpc_health_v1.HealthCh
 	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
 }
 
+// SetTopologyLabels sets node labels (for example rack or zone) which are reported as topology keys by NodeGetInfo,
+// labels which aren't set on the node are skipped
+func (s *CSINodeService) SetTopologyLabels(labels []string) {
+	s.topologyLabels = labels
+}
+
+// SetVersionInfo sets version information which is returned in manifest of GetPluginInfo
+func (s *CSINodeService) SetVersionInfo(info *metrics.VersionInfo) {
+	s.IdentityServer = controller.NewIdentityServerWithManifest(base.PluginName, base.PluginVersion, info.Manifest())
+}
+
+// SetReadinessError sets reason why node svc can't serve requests, node svc is reported as not ready while it is set
+func (s *CSINodeService) SetReadinessError(err error) {
+	s.readinessMu.Lock()
+	defer s.readinessMu.Unlock()
+	s.readinessErr = err
+}
+
 // Watch is used by clients to receive updates when the svc status changes.
 // Watch only dummy implemented just to satisfy the interface.
 func (s *CSINodeService) Watch(req *grpc_health_v1.HealthCheckRequest, srv grpc_health_v1.Health_WatchServer) error {
@@ -31,14 +31,19 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	"github.com/dell/csi-baremetal/pkg/metrics"
 	"github.com/dell/csi-baremetal/pkg/mocks"
 	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
 	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
@@ -105,6 +110,21 @@ var _ = Describe("CSINodeService NodePublish()", func() {
 			Expect(err).To(BeNil())
 			Expect(len(volumeCR.Spec.Owners)).To(Equal(1))
 		})
+		It("Should publish PMEM volume with DAX", func() {
+			req := getNodePublishRequest(testV1ID, targetPath, *testVolumeCap)
+			vol1 := testVolumeCR1
+			vol1.Spec.StorageClass = apiV1.StorageClassPMEM
+			err := node.k8sClient.UpdateCR(testCtx, &vol1)
+			Expect(err).To(BeNil())
+
+			fsOps.On("PrepareAndPerformMount",
+				path.Join(req.GetStagingTargetPath(), stagingFileName), req.GetTargetPath(), false, true, fs.DAXOption).
+				Return(nil)
+
+			resp, err := node.NodePublishVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+		})
 	})
 
 	Context("NodePublish() failure", func() {
@@ -210,6 +230,10 @@ var _ = Describe("CSINodeService NodeStage()", func() {
 			err = node.k8sClient.ReadCR(testCtx, testVolume1.Id, "", volumeCR)
 			Expect(err).To(BeNil())
 			Expect(volumeCR.Spec.CSIStatus).To(Equal(apiV1.VolumeReady))
+			err = node.k8sClient.ReadCR(testCtx, testVolume2.Id, "", volumeCR)
+			Expect(err).To(BeNil())
+			Expect(volumeCR.Annotations[apiV1.VolumeAnnotationStagingPath]).
+				To(Equal(path.Join(req.GetStagingTargetPath(), stagingFileName)))
 		})
 		It("Should stage, volume CR with VolumeReady status", func() {
 			req := getNodeStageRequest(testVolume1.Id, *testVolumeCap)
@@ -270,6 +294,16 @@ var _ = Describe("CSINodeService NodeStage()", func() {
 			Expect(err).NotTo(BeNil())
 			Expect(status.Code(err)).To(Equal(codes.NotFound))
 		})
+		It("Should fail, because volume is located on other node", func() {
+			req := getNodeStageRequest(testVolume1.Id, *testVolumeCap)
+			vol1 := testVolumeCR1
+			vol1.Spec.NodeId = "other-node"
+			Expect(node.k8sClient.UpdateCR(testCtx, &vol1)).To(BeNil())
+
+			resp, err := node.NodeStageVolume(testCtx, req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
 		It("Should fail because partition path wasn't found", func() {
 			req := getNodeStageRequest(testVolume1.Id, *testVolumeCap)
 			prov.On("GetVolumePath", testVolume1).
@@ -281,6 +315,15 @@ var _ = Describe("CSINodeService NodeStage()", func() {
 			Expect(err.Error()).To(ContainSubstring("partition error"))
 			Expect(status.Code(err)).To(Equal(codes.Internal))
 		})
+		It("Should fail while file system check of the volume is running", func() {
+			req := getNodeStageRequest(testVolume1.Id, *testVolumeCap)
+			Expect(node.tryLockDevice(testVolume1.Id)).To(BeTrue())
+			defer node.unlockDevice(testVolume1.Id)
+
+			resp, err := node.NodeStageVolume(testCtx, req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+		})
 		It("Failed because PrepareAndPerformMount had failed", func() {
 			req := getNodeStageRequest(testVolume2.Id, *testVolumeCap)
 			partitionPath := "/partition/path/for/volume1"
@@ -531,6 +574,27 @@ var _ = Describe("CSINodeService NodeGetInfo()", func() {
 		Expect(ok).To(BeTrue())
 		Expect(val).To(Equal(nodeID))
 	})
+	It("Should return topology labels of the node", func() {
+		node := newNodeService()
+		node.SetTopologyLabels([]string{"topology.kubernetes.io/zone", "example.com/rack"})
+
+		_, err := node.NodeGetInfo(testCtx, &csi.NodeGetInfoRequest{})
+		Expect(status.Code(err)).To(Equal(codes.Unavailable))
+
+		err = node.k8sClient.Create(testCtx, &coreV1.Node{ObjectMeta: metaV1.ObjectMeta{
+			Name:        "node",
+			Annotations: map[string]string{csibmnodeconst.NodeIDAnnotationKey: nodeID},
+			Labels:      map[string]string{"topology.kubernetes.io/zone": "zone-a", "app": "test"},
+		}})
+		Expect(err).To(BeNil())
+
+		resp, err := node.NodeGetInfo(testCtx, &csi.NodeGetInfoRequest{})
+		Expect(err).To(BeNil())
+		Expect(resp.AccessibleTopology.Segments).To(Equal(map[string]string{
+			csibmnodeconst.NodeIDAnnotationKey: nodeID,
+			"topology.kubernetes.io/zone":      "zone-a",
+		}))
+	})
 })
 
 var _ = Describe("CSINodeService NodeGetCapabilities()", func() {
@@ -569,6 +633,15 @@ var _ = Describe("CSINodeService Check()", func() {
 		Expect(resp).ToNot(BeNil())
 		Expect(resp.Status).To(Equal(grpc_health_v1.HealthCheckResponse_NOT_SERVING))
 	})
+	It("Should return not serving if readiness error is set", func() {
+		node := newNodeService()
+		node.initialized = true
+		node.SetReadinessError(errors.New("CAP_SYS_ADMIN capability is missing"))
+
+		resp, err := node.Check(testCtx, &grpc_health_v1.HealthCheckRequest{})
+		Expect(err).To(BeNil())
+		Expect(resp.Status).To(Equal(grpc_health_v1.HealthCheckResponse_NOT_SERVING))
+	})
 })
 
 var _ = Describe("CSINodeService InlineVolumes", func() {
@@ -686,6 +759,21 @@ var _ = Describe("CSINodeService Probe()", func() {
 	})
 })
 
+var _ = Describe("CSINodeService GetPluginInfo()", func() {
+	It("Should return drive manager API version in manifest", func() {
+		node := newNodeService()
+		info := metrics.NewVersionInfo()
+		info.SetDriveMgrAPIVersion(2)
+		node.SetVersionInfo(info)
+
+		resp, err := node.GetPluginInfo(testCtx, &csi.GetPluginInfoRequest{})
+		Expect(err).To(BeNil())
+		Expect(resp.GetVendorVersion()).To(Equal(base.PluginVersion))
+		Expect(resp.GetManifest()["drivemgr-api-version"]).To(Equal("v2"))
+		Expect(resp.GetManifest()["crd-version"]).To(Equal(apiV1.APIV1Version))
+	})
+})
+
 func getNodePublishRequest(volumeID, targetPath string, volumeCap csi.VolumeCapability) *csi.NodePublishVolumeRequest {
 	return &csi.NodePublishVolumeRequest{
 		VolumeId:          volumeID,
@@ -724,7 +812,7 @@ func newNodeService() *CSINodeService {
 	if err != nil {
 		panic(err)
 	}
-	node := NewCSINodeService(client, nodeID, testLogger, kubeClient, kubeClient,
+	node := NewCSINodeService(client, command.NewExecutor(testLogger), nodeID, testLogger, kubeClient, kubeClient,
 		new(mocks.NoOpRecorder), featureconfig.NewFeatureConfig())
 
 	driveCR1 := node.k8sClient.ConstructDriveCR(disk1.UUID, disk1)
@@ -0,0 +1,106 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"time"
+)
+
+// initialDiscoveryWaitTime is the time before the first discovery and between discoveries until one succeeds
+const initialDiscoveryWaitTime = 10 * time.Second
+
+// RunDiscovery performs Discover method each discovery interval or once it is triggered, e.g. by REST gateway,
+// discovery is repeated more often until it succeeds for the first time. Result is reported to the liveness helper
+// Receives golang context which stops the loop and function which returns discovery interval, it is requested
+// before each iteration, so interval could be changed without restart
+func (s *CSINodeService) RunDiscovery(ctx context.Context, interval func() time.Duration) {
+	ll := s.log.WithField("method", "RunDiscovery")
+
+	waitTime := initialDiscoveryWaitTime
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(waitTime):
+		case <-s.DiscoveryTriggered():
+			ll.Info("Discovery is triggered")
+		}
+		if err := s.Discover(ctx); err != nil {
+			s.livenessCheck.Fail()
+			ll.Errorf("Discover finished with error: %v", err)
+		} else {
+			s.livenessCheck.OK()
+			ll.Tracef("Discover finished successful")
+			// Increase wait time, because we don't need to call API often after node initialization
+			waitTime = interval()
+		}
+	}
+}
+
+// RunIntegrityCheck performs VerifyIntegrity method each interval
+// Receives golang context which stops the loop and interval between checks
+func (s *CSINodeService) RunIntegrityCheck(ctx context.Context, interval time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(interval):
+		}
+		if err := s.VerifyIntegrity(ctx); err != nil {
+			s.log.WithField("method", "RunIntegrityCheck").Errorf("Integrity check finished with error: %v", err)
+		}
+	}
+}
+
+// RunOnDiskCheck performs VerifyOnDisk method each interval
+// Receives golang context which stops the loop and interval between checks
+func (s *CSINodeService) RunOnDiskCheck(ctx context.Context, interval time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(interval):
+		}
+		if err := s.VerifyOnDisk(ctx); err != nil {
+			s.log.WithField("method", "RunOnDiskCheck").Errorf("On-disk check finished with error: %v", err)
+		}
+	}
+}
+
+// RunIOErrorCheck performs CheckIOErrors method each interval, I/O errors of the drives without error counter in sysfs
+// are counted in kernel log which is tailed until context is done
+// Receives golang context which stops the loop and interval between checks
+func (s *CSINodeService) RunIOErrorCheck(ctx context.Context, interval time.Duration) {
+	ll := s.log.WithField("method", "RunIOErrorCheck")
+
+	go func() {
+		if err := s.ioErrors.ops.TailKernelLog(ctx); err != nil {
+			ll.Warnf("I/O errors of drives without error counter aren't counted: %v", err)
+		}
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(interval):
+		}
+		if err := s.CheckIOErrors(ctx); err != nil {
+			ll.Errorf("I/O error check finished with error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+// countingLiveness counts results of discoveries
+type countingLiveness struct {
+	ok, fail int32
+}
+
+func (c *countingLiveness) OK()         { atomic.AddInt32(&c.ok, 1) }
+func (c *countingLiveness) Fail()       { atomic.AddInt32(&c.fail, 1) }
+func (c *countingLiveness) Check() bool { return true }
+
+func (c *countingLiveness) results() (int32, int32) {
+	return atomic.LoadInt32(&c.ok), atomic.LoadInt32(&c.fail)
+}
+
+func TestCSINodeService_RunDiscovery(t *testing.T) {
+	var (
+		svc       = newNodeService()
+		fakeClock = clock.NewFakeClock(time.Now())
+		liveness  = &countingLiveness{}
+		stopped   = make(chan struct{})
+	)
+	ctx, cancel := context.WithCancel(testCtx)
+	svc.clock = fakeClock
+	svc.livenessCheck = liveness
+	// discovery fails until drive manager responds
+	svc.driveMgrClient = &mocks.MockDriveMgrClientFail{}
+
+	go func() {
+		svc.RunDiscovery(ctx, func() time.Duration { return time.Minute })
+		close(stopped)
+	}()
+	// results are polled synchronously, assert.Eventually runs condition in goroutine on each tick
+	// and they could outlive it
+	waitResults := func(ok, fail int32) {
+		deadline := time.Now().Add(time.Second)
+		for {
+			o, f := liveness.results()
+			if o == ok && f == fail && fakeClock.HasWaiters() {
+				return
+			}
+			if time.Now().After(deadline) {
+				assert.Fail(t, "unexpected discovery results", "expected %d/%d, got %d/%d", ok, fail, o, f)
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	waitResults(0, 0)
+	fakeClock.Step(initialDiscoveryWaitTime)
+	waitResults(0, 1)
+	// failed discovery is repeated after initial wait time
+	svc.driveMgrClient = mocks.NewMockDriveMgrClient(mocks.DriveMgrRespDrives)
+	fakeClock.Step(initialDiscoveryWaitTime)
+	waitResults(1, 1)
+
+	// discovery interval is used once discovery succeeded
+	fakeClock.Step(initialDiscoveryWaitTime)
+	waitResults(1, 1)
+	fakeClock.Step(time.Minute - initialDiscoveryWaitTime)
+	waitResults(2, 1)
+
+	// triggered discovery doesn't wait for the interval
+	svc.TriggerDiscovery()
+	waitResults(3, 1)
+
+	cancel()
+	<-stopped
+}
@@ -0,0 +1,206 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preflight contains validation of the node which is performed before node service declares itself ready:
+// availability of the system utils, kernel modules, udev database and host directories
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dell/csi-baremetal/api/v1/nodecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+)
+
+const (
+	// SysModulePath is a directory which holds entries of the loaded and built in kernel modules
+	SysModulePath = "/sys/module"
+	// UdevDataPath is a directory of the udev database
+	UdevDataPath = "/run/udev/data"
+)
+
+var (
+	// RequiredTools is a list of system utils which are used for volumes provisioning
+	RequiredTools = []string{"lsblk", "parted", "partprobe", "sgdisk", "wipefs", "lvm", "findmnt",
+		"mount", "umount", "mkfs.xfs", "mkfs.ext4", "mkfs.ext3"}
+	// RequiredModules is a list of kernel modules which are used for volumes provisioning
+	RequiredModules = []string{"dm_mod"}
+	// RequiredHostPaths is a list of host directories which should be mounted into the node container,
+	// kubelet directories are checked in addition to them with the required mount propagation
+	RequiredHostPaths = []string{"/dev", "/sys", "/run/udev", "/run/lvm", "/run/lock"}
+)
+
+// Validator performs pre-flight checks of the node
+type Validator struct {
+	tools        []string
+	modules      []string
+	hostPaths    []string
+	kubeletPaths []string
+	// propagation which is required for kubelet directories
+	propagation fs.MountPropagation
+
+	sysModulePath string
+	udevDataPath  string
+	mountInfoPath string
+
+	log *logrus.Entry
+}
+
+// NewValidator is the constructor for Validator struct, required tools, modules and host paths are checked,
+// kubelet directories are required to receive mounts from the host by default
+// Receives root directory of kubelet on the node and logrus logger
+// Returns an instance of Validator
+func NewValidator(kubeletDir string, logger *logrus.Logger) *Validator {
+	return &Validator{
+		tools:         RequiredTools,
+		modules:       RequiredModules,
+		hostPaths:     RequiredHostPaths,
+		kubeletPaths:  []string{path.Join(kubeletDir, base.KubeletPodsDir), path.Join(kubeletDir, base.KubeletCSIPluginsDir)},
+		propagation:   fs.PropagationSlave,
+		sysModulePath: SysModulePath,
+		udevDataPath:  UdevDataPath,
+		mountInfoPath: fs.MountInfoFile,
+		log:           logger.WithField("component", "PreflightValidator"),
+	}
+}
+
+// SetMountPropagation sets propagation which is required for kubelet directories, it should be shared
+// if mounts are performed in the node container, otherwise they aren't visible to kubelet
+func (v *Validator) SetMountPropagation(propagation fs.MountPropagation) {
+	v.propagation = propagation
+}
+
+// Validate runs all pre-flight checks, failed check doesn't stop validation
+// Returns results of the checks and error which lists failed checks or nil if all checks were passed
+func (v *Validator) Validate() ([]nodecrd.NodeCheck, error) {
+	ll := v.log.WithField("method", "Validate")
+
+	checks := make([]nodecrd.NodeCheck, 0, len(v.tools)+len(v.modules)+len(v.hostPaths)+len(v.kubeletPaths)+1)
+	for _, tool := range v.tools {
+		checks = append(checks, v.checkTool(tool))
+	}
+	for _, module := range v.modules {
+		checks = append(checks, v.checkModule(module))
+	}
+	checks = append(checks, v.checkUdev())
+
+	mountPoints, err := fs.ReadMountPoints(v.mountInfoPath)
+	for _, hostPath := range v.hostPaths {
+		checks = append(checks, checkHostPath(mountPoints, err, hostPath, fs.PropagationPrivate))
+	}
+	for _, kubeletPath := range v.kubeletPaths {
+		checks = append(checks, checkHostPath(mountPoints, err, kubeletPath, v.propagation))
+	}
+
+	var failed []string
+	for _, check := range checks {
+		if !check.Passed {
+			ll.Errorf("%s check %s failed: %s", check.Type, check.Name, check.Message)
+			failed = append(failed, fmt.Sprintf("%s %s", check.Type, check.Name))
+		}
+	}
+	if len(failed) > 0 {
+		return checks, fmt.Errorf("node pre-flight checks failed: %s", strings.Join(failed, ", "))
+	}
+	ll.Infof("All %d pre-flight checks passed", len(checks))
+	return checks, nil
+}
+
+// checkTool checks whether system util could be found in PATH
+func (v *Validator) checkTool(tool string) nodecrd.NodeCheck {
+	check := nodecrd.NodeCheck{Type: nodecrd.NodeCheckTool, Name: tool, Passed: true}
+	if _, err := command.LookPath(tool); err != nil {
+		check.Passed, check.Message = false, err.Error()
+	}
+	return check
+}
+
+// checkModule checks whether kernel module is loaded or built in
+func (v *Validator) checkModule(module string) nodecrd.NodeCheck {
+	check := nodecrd.NodeCheck{Type: nodecrd.NodeCheckKernelModule, Name: module, Passed: true}
+	if _, err := os.Stat(path.Join(v.sysModulePath, module)); err != nil {
+		check.Passed, check.Message = false, fmt.Sprintf("module isn't loaded: %v", err)
+	}
+	return check
+}
+
+// checkUdev checks whether udev database of the host could be read, it is used by lsblk to get drives properties
+func (v *Validator) checkUdev() nodecrd.NodeCheck {
+	check := nodecrd.NodeCheck{Type: nodecrd.NodeCheckUdev, Name: v.udevDataPath, Passed: true}
+	f, err := os.Open(v.udevDataPath)
+	if err == nil {
+		_, err = f.Readdirnames(1)
+		_ = f.Close()
+	}
+	if err != nil {
+		check.Passed, check.Message = false, fmt.Sprintf("udev database isn't accessible: %v", err)
+	}
+	return check
+}
+
+// checkHostPath checks whether host directory is mounted into the container with the required propagation
+// Receives mount points of the container and error of their reading, directory and required propagation
+func checkHostPath(mountPoints []fs.MountPoint, readErr error, hostPath string,
+	propagation fs.MountPropagation) nodecrd.NodeCheck {
+	check := nodecrd.NodeCheck{Type: nodecrd.NodeCheckHostPath, Name: hostPath, Passed: true}
+	if readErr != nil {
+		check.Passed, check.Message = false, fmt.Sprintf("unable to read mount table: %v", readErr)
+		return check
+	}
+	mp, ok := fs.FindMount(mountPoints, hostPath)
+	switch {
+	case !ok:
+		check.Passed, check.Message = false, "is not mounted into the container"
+	case !mp.Propagation.Satisfies(propagation):
+		check.Passed, check.Message = false,
+			fmt.Sprintf("mount propagation is %s, but %s is required", mp.Propagation, propagation)
+	}
+	return check
+}
+
+// Report stores results of the pre-flight checks in the status of the Node CR which corresponds to the node,
+// Node CR is matched by id or by hostname
+// Receives golang context, kubernetes client, id and name of the node, results of the checks
+// Returns error if Node CR wasn't found or couldn't be updated
+func Report(ctx context.Context, client *k8s.KubeClient, nodeID, nodeName string, checks []nodecrd.NodeCheck) error {
+	nodes := &nodecrd.NodeList{}
+	if err := client.ReadList(ctx, nodes); err != nil {
+		return fmt.Errorf("unable to read Node CRs: %w", err)
+	}
+	for i := range nodes.Items {
+		bmNode := &nodes.Items[i]
+		if bmNode.Spec.UUID != nodeID && bmNode.Spec.Addresses[string(corev1.NodeHostName)] != nodeName {
+			continue
+		}
+		bmNode.SetValidationResult(checks, metav1.Now())
+		if err := client.UpdateStatus(ctx, bmNode); err != nil {
+			return fmt.Errorf("unable to update status of Node CR %s: %w", bmNode.Name, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("unable to find Node CR for node %s", nodeName)
+}
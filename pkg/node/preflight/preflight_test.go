@@ -0,0 +1,144 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/api/v1/nodecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+)
+
+var testLogger = logrus.New()
+
+// prepareValidator creates Validator which checks files in the temporary directory
+func prepareValidator(t *testing.T) (*Validator, string) {
+	dir, err := ioutil.TempDir("", "preflight")
+	assert.Nil(t, err)
+
+	v := NewValidator(base.DefaultKubeletDir, testLogger)
+	assert.Contains(t, v.kubeletPaths, "/var/lib/kubelet/pods")
+	v.tools = []string{"lsblk", "parted"}
+	v.modules = []string{"dm_mod"}
+	v.hostPaths = []string{"/dev", "/run/udev"}
+	v.kubeletPaths = []string{"/var/lib/kubelet/pods"}
+	v.sysModulePath = path.Join(dir, "module")
+	v.udevDataPath = path.Join(dir, "udev")
+	v.mountInfoPath = path.Join(dir, "mountinfo")
+
+	assert.Nil(t, os.MkdirAll(path.Join(v.sysModulePath, "dm_mod"), 0755))
+	assert.Nil(t, os.MkdirAll(v.udevDataPath, 0755))
+	assert.Nil(t, ioutil.WriteFile(path.Join(v.udevDataPath, "b8:0"), nil, 0644))
+	assert.Nil(t, ioutil.WriteFile(v.mountInfoPath, []byte(
+		"1250 1232 0:5 / /dev rw,nosuid - devtmpfs udev rw\n"+
+			"1255 1232 0:24 /udev /run/udev rw,nosuid - tmpfs tmpfs rw\n"+
+			"1260 1232 8:1 /pods /var/lib/kubelet/pods rw master:1 - ext4 /dev/sda1 rw\n"), 0644))
+	return v, dir
+}
+
+func TestValidator_Validate(t *testing.T) {
+	defer func() { command.LookPath = exec.LookPath }()
+	command.LookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+
+	v, dir := prepareValidator(t)
+	defer os.RemoveAll(dir)
+
+	checks, err := v.Validate()
+	assert.Nil(t, err)
+	assert.Len(t, checks, 7)
+	for _, check := range checks {
+		assert.True(t, check.Passed, check.Name)
+	}
+
+	// mounts are performed in the node container, so they have to propagate to the host
+	v.SetMountPropagation(fs.PropagationShared)
+	_, err = v.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "HostPath /var/lib/kubelet/pods")
+	v.SetMountPropagation(fs.PropagationSlave)
+
+	// missed tool, module, empty udev database and host path which isn't mounted
+	command.LookPath = func(file string) (string, error) {
+		if file == "parted" {
+			return "", exec.ErrNotFound
+		}
+		return "/usr/bin/" + file, nil
+	}
+	v.modules = append(v.modules, "dm_integrity")
+	v.hostPaths = append(v.hostPaths, "/run/lvm")
+	assert.Nil(t, os.Remove(path.Join(v.udevDataPath, "b8:0")))
+
+	checks, err = v.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Tool parted")
+	assert.Contains(t, err.Error(), "KernelModule dm_integrity")
+	assert.Contains(t, err.Error(), "Udev")
+	assert.Contains(t, err.Error(), "HostPath /run/lvm")
+	failed := 0
+	for _, check := range checks {
+		if !check.Passed {
+			failed++
+			assert.NotEmpty(t, check.Message)
+		}
+	}
+	assert.Equal(t, 4, failed)
+
+	// mount table isn't readable
+	v.mountInfoPath = path.Join(dir, "notexist")
+	_, err = v.Validate()
+	assert.Contains(t, err.Error(), "HostPath /dev")
+}
+
+func TestReport(t *testing.T) {
+	kubeClient, err := k8s.GetFakeKubeClient("default", testLogger)
+	assert.Nil(t, err)
+	ctx := context.Background()
+
+	checks := []nodecrd.NodeCheck{{Type: nodecrd.NodeCheckTool, Name: "lsblk", Passed: true}}
+	err = Report(ctx, kubeClient, "node-uuid", "node-1", checks)
+	assert.NotNil(t, err)
+
+	bmNode := kubeClient.ConstructCSIBMNodeCR("csibmnode-1",
+		api.Node{UUID: "node-uuid", Addresses: map[string]string{"Hostname": "node-1"}})
+	assert.Nil(t, kubeClient.CreateCR(ctx, bmNode.Name, bmNode))
+
+	assert.Nil(t, Report(ctx, kubeClient, "node-uuid", "node-1", checks))
+	stored := &nodecrd.Node{}
+	assert.Nil(t, kubeClient.Get(ctx, k8sCl.ObjectKey{Name: bmNode.Name}, stored))
+	assert.True(t, stored.Status.Validated)
+	assert.Equal(t, checks, stored.Status.Checks)
+
+	// node is matched by hostname if id is taken from k8s node
+	checks = append(checks, nodecrd.NodeCheck{Type: nodecrd.NodeCheckKernelModule, Name: "dm_mod", Message: "module isn't loaded"})
+	assert.Nil(t, Report(ctx, kubeClient, "k8s-node-uid", "node-1", checks))
+	assert.Nil(t, kubeClient.Get(ctx, k8sCl.ObjectKey{Name: bmNode.Name}, stored))
+	assert.False(t, stored.Status.Validated)
+	assert.Len(t, stored.Status.Checks, 2)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// Prewarm modes which are set by base.PVCAnnotationPrewarm annotation
+const (
+	// PrewarmReadahead means that data of the volume is read sequentially: whole device of block volume or
+	// all files of file system volume (file pages are cached separately from pages of the device)
+	PrewarmReadahead = "readahead"
+	// PrewarmCommand means that prewarm command of the node is run with target path of the volume as the last argument
+	PrewarmCommand = "command"
+)
+
+// prewarmBufferSize is a size of reads which populate page cache
+const prewarmBufferSize = 1 << 20
+
+// SetPrewarmCommand sets command which is run on publish of the volume with "command" prewarm mode,
+// the mode isn't supported if command is empty
+func (s *CSINodeService) SetPrewarmCommand(cmd string) {
+	s.prewarmCommand = strings.TrimSpace(cmd)
+}
+
+// prewarmMode returns prewarm mode from annotation of PVC of the volume, PVC name and namespace are taken from
+// volume context, empty string is returned if PVC isn't known or isn't annotated
+func (s *CSINodeService) prewarmMode(ctx context.Context, volumeContext map[string]string) (string, error) {
+	name, namespace := volumeContext[base.PVCNameKey], volumeContext[base.PVCNamespaceKey]
+	if name == "" {
+		return "", nil
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := s.k8sClient.ReadCR(ctx, name, namespace, pvc); err != nil {
+		return "", fmt.Errorf("unable to read PVC %s/%s: %w", namespace, name, err)
+	}
+	return pvc.Annotations[base.PVCAnnotationPrewarm], nil
+}
+
+// prewarmVolume populates page cache with data of the published volume before the application starts, it is
+// interrupted once the context is done. Prewarm is best effort, so failure is only reported with event of the volume
+// Receives golang context, volume CR, context of NodePublishVolumeRequest, target path and whether volume is block
+func (s *CSINodeService) prewarmVolume(ctx context.Context, volume *volumecrd.Volume, volumeContext map[string]string,
+	targetPath string, isBlock bool) {
+	ll := s.log.WithFields(logrus.Fields{
+		"method":   "prewarmVolume",
+		"volumeID": volume.Spec.Id,
+	})
+
+	mode, err := s.prewarmMode(ctx, volumeContext)
+	if err != nil {
+		ll.Warnf("Unable to determine prewarm mode: %v", err)
+		return
+	}
+	switch mode {
+	case "":
+		return
+	case PrewarmReadahead:
+		var read int64
+		if isBlock {
+			read, err = readAhead(ctx, targetPath)
+		} else {
+			read, err = readAheadFiles(ctx, targetPath)
+		}
+		ll.Infof("%d bytes of %s are read into page cache", read, targetPath)
+	case PrewarmCommand:
+		err = s.runPrewarmCommand(ctx, targetPath)
+	default:
+		err = fmt.Errorf("unsupported value %s of %s annotation, supported values are %s, %s",
+			mode, base.PVCAnnotationPrewarm, PrewarmReadahead, PrewarmCommand)
+	}
+	if err != nil {
+		ll.Errorf("Prewarm failed: %v", err)
+		s.recorder.Eventf(volume, eventing.WarningType, eventing.VolumePrewarmFailed, "Prewarm (%s) failed: %v", mode, err)
+	}
+}
+
+// runPrewarmCommand runs prewarm command of the node for the target path
+func (s *CSINodeService) runPrewarmCommand(ctx context.Context, targetPath string) error {
+	if s.prewarmCommand == "" {
+		return errors.New("prewarm command isn't configured for the node")
+	}
+	args := append(strings.Fields(s.prewarmCommand), targetPath)
+	_, stderr, err := s.executor.RunCmd(exec.CommandContext(ctx, args[0], args[1:]...))
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr))
+	}
+	return nil
+}
+
+// readAheadFiles sequentially reads regular files under the root, unreadable files are skipped
+// Returns amount of read bytes and the last error
+func readAheadFiles(ctx context.Context, root string) (int64, error) {
+	var (
+		total   int64
+		lastErr error
+	)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			lastErr = err
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		read, err := readAhead(ctx, path)
+		total += read
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			lastErr = err
+		}
+		return nil
+	})
+	if err != nil {
+		return total, err
+	}
+	return total, lastErr
+}
+
+// readAhead sequentially reads the file or device, reading is stopped once the context is done
+// Returns amount of read bytes
+func readAhead(ctx context.Context, path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var (
+		total int64
+		buf   = make([]byte, prewarmBufferSize)
+	)
+	for {
+		if err = ctx.Err(); err != nil {
+			return total, err
+		}
+		n, err := f.Read(buf)
+		total += int64(n)
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+func TestReadAheadFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prewarm")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "data"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "model.bin"), make([]byte, prewarmBufferSize+10), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "data", "part"), make([]byte, 20), 0644))
+
+	read, err := readAheadFiles(testCtx, dir)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(prewarmBufferSize+30), read)
+
+	// prewarm is interrupted once context is done
+	ctx, cancel := context.WithCancel(testCtx)
+	cancel()
+	read, err = readAheadFiles(ctx, dir)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int64(0), read)
+}
+
+func TestCSINodeService_PrewarmVolume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prewarm")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	var (
+		svc    = newNodeService()
+		rec    = &mocks.NoOpRecorder{}
+		volume = testVolumeCR1.DeepCopy()
+		pvc    = &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "pvc-1", Namespace: testNs}}
+		target = filepath.Join(dir, "mount")
+		volCtx = map[string]string{base.PVCNameKey: pvc.Name, base.PVCNamespaceKey: pvc.Namespace}
+	)
+	svc.recorder = rec
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, pvc.Name, pvc))
+	setMode := func(mode string) {
+		pvc.Annotations = map[string]string{base.PVCAnnotationPrewarm: mode}
+		assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, pvc))
+	}
+
+	// PVC isn't annotated
+	svc.prewarmVolume(testCtx, volume, volCtx, target, false)
+	assert.Empty(t, rec.Calls)
+
+	// prewarm command is run with target path
+	setMode(PrewarmCommand)
+	svc.prewarmVolume(testCtx, volume, volCtx, target, false)
+	assert.NotEmpty(t, rec.Calls)
+	assert.Equal(t, eventing.VolumePrewarmFailed, rec.Calls[0].Reason)
+	svc.SetPrewarmCommand("mkdir -p")
+	svc.prewarmVolume(testCtx, volume, volCtx, target, false)
+	assert.Len(t, rec.Calls, 1)
+	_, err = os.Stat(target)
+	assert.Nil(t, err)
+
+	// volume is read sequentially
+	setMode(PrewarmReadahead)
+	svc.prewarmVolume(testCtx, volume, volCtx, target, false)
+	assert.Len(t, rec.Calls, 1)
+	svc.prewarmVolume(testCtx, volume, volCtx, filepath.Join(dir, "device"), true)
+	assert.Len(t, rec.Calls, 2)
+
+	setMode("vmtouch")
+	svc.prewarmVolume(testCtx, volume, volCtx, target, false)
+	assert.Len(t, rec.Calls, 3)
+	assert.Equal(t, eventing.VolumePrewarmFailed, rec.Calls[2].Reason)
+}
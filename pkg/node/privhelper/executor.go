@@ -0,0 +1,188 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privhelper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+)
+
+// Executor is the implementation of CmdExecutor which proxies privileged commands to the privileged helper
+// and runs other commands locally
+type Executor struct {
+	client api.PrivilegedHelperClient
+	local  command.CmdExecutor
+	log    *logrus.Entry
+}
+
+// NewExecutor is the constructor for Executor struct
+// Receives PrivilegedHelperClient, CmdExecutor for not privileged commands and logrus logger
+// Returns an instance of Executor
+func NewExecutor(client api.PrivilegedHelperClient, local command.CmdExecutor, logger *logrus.Logger) *Executor {
+	return &Executor{
+		client: client,
+		local:  local,
+		log:    logger.WithField("component", "PrivilegedExecutor"),
+	}
+}
+
+// SetLevel sets logrus Level for the local executor
+func (e *Executor) SetLevel(level logrus.Level) {
+	e.local.SetLevel(level)
+}
+
+// RunCmd runs specified command via privileged helper if command requires privileged access, otherwise locally
+// Receives command as empty interface. It could be string or instance of exec.Cmd
+// Returns stdout as string, stderr as string and golang error if something went wrong
+func (e *Executor) RunCmd(cmd interface{}, opts ...command.Options) (string, string, error) {
+	var args []string
+	switch c := cmd.(type) {
+	case string:
+		args = strings.Fields(c)
+	case *exec.Cmd:
+		args = c.Args
+	default:
+		return "", "", fmt.Errorf("could not interpret command from %v", cmd)
+	}
+
+	if !IsPrivileged(args) {
+		return e.local.RunCmd(cmd, opts...)
+	}
+
+	e.log.WithField("method", "RunCmd").Debugf("Run %v via privileged helper", args)
+	resp, err := e.send(context.Background(), args)
+	if err != nil {
+		return "", "", fmt.Errorf("privileged helper failed to run command: %w", err)
+	}
+	if resp.Error != "" {
+		return resp.Stdout, resp.Stderr, errors.New(resp.Error)
+	}
+	return resp.Stdout, resp.Stderr, nil
+}
+
+// send sends command to the helper, file system operations are sent as typed requests
+func (e *Executor) send(ctx context.Context, args []string) (*api.CmdResponse, error) {
+	name := filepath.Base(args[0])
+	switch {
+	case name == mountCmd:
+		req, err := parseMount(args[1:])
+		if err != nil {
+			return nil, err
+		}
+		return e.client.Mount(ctx, req)
+	case name == umountCmd && len(args) == 2:
+		return e.client.Unmount(ctx, &api.PathRequest{Path: args[1]})
+	case name == mkdirCmd && len(args) == 3 && args[1] == "-p":
+		return e.client.MkDir(ctx, &api.PathRequest{Path: args[2]})
+	case name == rmCmd && len(args) == 3 && args[1] == "-rf":
+		return e.client.RmDir(ctx, &api.PathRequest{Path: args[2]})
+	case name == wipefsCmd && len(args) == 3 && args[1] == "-af":
+		return e.client.WipeFS(ctx, &api.PathRequest{Path: args[2]})
+	case strings.HasPrefix(name, mkfsPrefix):
+		return e.client.MkFS(ctx, parseMkFS(strings.TrimPrefix(name, mkfsPrefix), args[1:]))
+	}
+	return e.client.RunCmd(ctx, &api.CmdRequest{Args: args})
+}
+
+// parseMount splits mount arguments into options, source and target, source is omitted on remount
+func parseMount(args []string) (*api.MountRequest, error) {
+	var (
+		req        = &api.MountRequest{}
+		positional []string
+	)
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case typeOpt, optionOpt:
+			if i+1 < len(args) {
+				req.Opts = append(req.Opts, args[i], args[i+1])
+				i++
+				continue
+			}
+			req.Opts = append(req.Opts, args[i])
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				req.Opts = append(req.Opts, args[i])
+				continue
+			}
+			positional = append(positional, args[i])
+		}
+	}
+	switch len(positional) {
+	case 1:
+		req.Target = positional[0]
+	case 2:
+		req.Source, req.Target = positional[0], positional[1]
+	default:
+		return nil, fmt.Errorf("unable to parse source and target of mount from %v", args)
+	}
+	return req, nil
+}
+
+// parseMkFS takes the first absolute path from mkfs arguments as device, the rest are options
+func parseMkFS(fsType string, args []string) *api.MkFSRequest {
+	req := &api.MkFSRequest{FsType: fsType}
+	for _, arg := range args {
+		if req.Device == "" && filepath.IsAbs(arg) {
+			req.Device = arg
+			continue
+		}
+		req.Opts = append(req.Opts, arg)
+	}
+	return req
+}
+
+// WriteSysfs writes attribute of block device request queue via privileged helper,
+// sysfs is read-only in not privileged node container
+// Receives path of the attribute and its value
+// Returns error if helper rejected the request or the write failed
+func (e *Executor) WriteSysfs(path, value string) error {
+	e.log.WithField("method", "WriteSysfs").Debugf("Write %s to %s via privileged helper", value, path)
+	resp, err := e.client.WriteSysfs(context.Background(), &api.SysfsRequest{Path: path, Value: value})
+	if err != nil {
+		return fmt.Errorf("privileged helper failed to write %s: %w", path, err)
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+// RunCmdWithAttempts runs specified command with given attempts and timeout between attempts
+// Receives command as empty interface, It could be string or instance of exec.Cmd; number of attempts; timeout.
+// Returns stdout as string, stderr as string and golang error if something went wrong
+func (e *Executor) RunCmdWithAttempts(cmd interface{}, attempts int, timeout time.Duration,
+	opts ...command.Options) (stdout string, stderr string, err error) {
+	for i := 0; i < attempts; i++ {
+		if stdout, stderr, err = e.RunCmd(cmd, opts...); err == nil {
+			return stdout, stderr, nil
+		}
+		e.log.WithField("method", "RunCmdWithAttempts").
+			Warnf("Unable to execute cmd: %v. Attempt %d out of %d.", err, i, attempts)
+		<-time.After(timeout)
+	}
+	return stdout, stderr, fmt.Errorf("failed to execute command after %d attempt, error: %w", attempts, err)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privhelper
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	bindOpt   = "--bind"
+	typeOpt   = "-t"
+	optionOpt = "-o"
+)
+
+// pseudoFS are the sources of mount which aren't paths (read cache of the node service)
+var pseudoFS = map[string]bool{
+	"tmpfs":   true,
+	"overlay": true,
+}
+
+// overlayDirOpts are the overlay mount options with colon separated directories
+var overlayDirOpts = map[string]bool{
+	"lowerdir": true,
+	"upperdir": true,
+	"workdir":  true,
+}
+
+// pathValidator checks that paths of the privileged operations are under the allowed roots,
+// so the helper couldn't be used to mount, format or remove arbitrary paths of the host
+type pathValidator struct {
+	devRoots []string
+	dirRoots []string
+}
+
+// newPathValidator is the constructor for pathValidator struct
+// Receives roots of the devices and roots of the directories, symlinked roots are allowed by both paths
+// Returns an instance of pathValidator
+func newPathValidator(devRoots, dirRoots []string) *pathValidator {
+	expand := func(roots []string) []string {
+		var res []string
+		for _, root := range roots {
+			root = filepath.Clean(root)
+			res = append(res, root)
+			if resolved := resolve(root); resolved != root {
+				res = append(res, resolved)
+			}
+		}
+		return res
+	}
+	return &pathValidator{devRoots: expand(devRoots), dirRoots: expand(dirRoots)}
+}
+
+// device validates path of the device
+// Returns cleaned path or error if it isn't under roots of the devices
+func (v *pathValidator) device(p string) (string, error) {
+	return under(p, v.devRoots)
+}
+
+// dir validates path of the directory
+// Returns cleaned path or error if it isn't under roots of the directories
+func (v *pathValidator) dir(p string) (string, error) {
+	return under(p, v.dirRoots)
+}
+
+// anyPath validates path which could be either device or directory
+// Returns cleaned path or error if it isn't under any root
+func (v *pathValidator) anyPath(p string) (string, error) {
+	return under(p, append(append([]string{}, v.devRoots...), v.dirRoots...))
+}
+
+// mountSource validates source of mount, it could be device, directory or pseudo file system
+func (v *pathValidator) mountSource(src string) (string, error) {
+	if pseudoFS[src] {
+		return src, nil
+	}
+	return v.anyPath(src)
+}
+
+// mountOpts validates mount options: bind, file system type and options with directories of overlay
+func (v *pathValidator) mountOpts(opts []string) error {
+	for i := 0; i < len(opts); i++ {
+		switch opt := opts[i]; opt {
+		case bindOpt:
+		case typeOpt, optionOpt:
+			if i+1 == len(opts) || strings.HasPrefix(opts[i+1], "-") {
+				return fmt.Errorf("mount option %s requires value", opt)
+			}
+			i++
+			if opt == optionOpt {
+				if err := v.overlayDirs(opts[i]); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("mount option %s isn't supported", opt)
+		}
+	}
+	return nil
+}
+
+// overlayDirs validates directories of overlay in comma separated mount options
+func (v *pathValidator) overlayDirs(options string) error {
+	for _, option := range strings.Split(options, ",") {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 || !overlayDirOpts[kv[0]] {
+			continue
+		}
+		for _, dir := range strings.Split(kv[1], ":") {
+			if _, err := v.dir(dir); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// args validates arguments of the util: absolute paths and values of key=value arguments (dd) which are paths
+func (v *pathValidator) args(args []string) error {
+	for _, arg := range args {
+		if kv := strings.SplitN(arg, "=", 2); len(kv) == 2 && !strings.HasPrefix(arg, "-") {
+			arg = kv[1]
+		}
+		if !filepath.IsAbs(arg) {
+			continue
+		}
+		if _, err := v.anyPath(arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queueAttr validates path of block device request queue attribute: <sysfs>/block/<device>/queue/<attribute>
+// Returns cleaned path or error if path isn't an attribute of request queue
+func queueAttr(sysfs, p string) (string, error) {
+	if !filepath.IsAbs(p) {
+		return "", fmt.Errorf("path %q isn't absolute", p)
+	}
+	p = filepath.Clean(p)
+	rel, err := filepath.Rel(filepath.Join(sysfs, "block"), p)
+	parts := strings.Split(rel, string(filepath.Separator))
+	if err != nil || len(parts) != 3 || parts[0] == ".." || parts[1] != "queue" || !queueAttrs[parts[2]] {
+		return "", fmt.Errorf("path %s isn't an attribute of block device request queue", p)
+	}
+	return p, nil
+}
+
+// under checks that path and its target, if path or some of its parents is a symlink, are inside one of the roots
+// Returns cleaned path or error
+func under(p string, roots []string) (string, error) {
+	if !filepath.IsAbs(p) {
+		return "", fmt.Errorf("path %q isn't absolute", p)
+	}
+	p = filepath.Clean(p)
+	resolved := resolve(p)
+	if inside(p, roots) && inside(resolved, roots) {
+		return p, nil
+	}
+	return "", fmt.Errorf("path %s is outside of %s", p, strings.Join(roots, ", "))
+}
+
+// inside checks whether path is strictly inside one of the roots, root itself isn't allowed
+func inside(p string, roots []string) bool {
+	for _, root := range roots {
+		if strings.HasPrefix(p, strings.TrimSuffix(root, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve evaluates symlinks of the longest existing part of the path, the rest is appended as is
+func resolve(p string) string {
+	dir, rest := p, ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return p
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+}
@@ -0,0 +1,308 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package privhelper contains privileged helper which runs operations that require privileged access
+// (mount, mkfs, partitioning, LVM) on behalf of the node service and executor which proxies such operations
+// to the helper over unix domain socket. It allows to run node container as non-root with minimal capabilities.
+//
+// File system operations (mount, umount, mkdir, rm, wipefs, mkfs) are sent as typed requests, other device
+// management utils are sent as argument lists. The helper never runs commands via shell and checks that devices
+// are under /dev and directories are under kubelet directory, where staging and target paths of the volumes are.
+// Attributes of block device request queue are written by the helper too, since sysfs is read-only in not privileged
+// containers.
+//
+// The helper doesn't cover nsenter: mount modes of the node service are applied only if the helper isn't used.
+// Prewarm command (for example vmtouch) only reads files of the published volume, so it is run in the node container.
+package privhelper
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+)
+
+// File system operations which are sent to the helper as typed requests
+const (
+	mountCmd  = "mount"
+	umountCmd = "umount"
+	mkdirCmd  = "mkdir"
+	rmCmd     = "rm"
+	wipefsCmd = "wipefs"
+	// mkfsPrefix is a prefix of the file system creation utils (mkfs.xfs, mkfs.ext4 and so on)
+	mkfsPrefix = "mkfs."
+)
+
+// fsCmds are the commands which are sent to the helper as typed requests
+var fsCmds = map[string]bool{
+	mountCmd:  true,
+	umountCmd: true,
+	mkdirCmd:  true,
+	rmCmd:     true,
+	wipefsCmd: true,
+}
+
+// utilCmds is a list of device management utils which require privileged access and are run by the helper as is,
+// their path arguments are validated
+var utilCmds = map[string]bool{
+	// wipefs is allowed only to probe signatures, they are erased by WipeFS
+	wipefsCmd:   true,
+	"parted":    true,
+	"partprobe": true,
+	"sgdisk":    true,
+	"lvm":       true,
+	"vgs":       true,
+	// integrity protection of the volumes
+	"integritysetup": true,
+	"dmsetup":        true,
+	"dumpe2fs":       true,
+	// file system check and repair
+	"e2fsck":     true,
+	"xfs_repair": true,
+	// zoned block devices
+	"blkzone": true,
+	// loop device, image and probe write of the self-test
+	"losetup":  true,
+	"truncate": true,
+	"dd":       true,
+}
+
+// mkfsTypes are the file systems which could be created by the helper
+var mkfsTypes = map[string]bool{
+	string(fs.XFS):   true,
+	string(fs.EXT3):  true,
+	string(fs.EXT4):  true,
+	string(fs.F2FS):  true,
+	string(fs.BTRFS): true,
+}
+
+// queueAttrs are the attributes of block device request queue which could be written by the helper
+var queueAttrs = map[string]bool{
+	"scheduler":     true,
+	"nr_requests":   true,
+	"read_ahead_kb": true,
+}
+
+// devRoot is the directory where devices have to be
+const devRoot = "/dev"
+
+// IsPrivileged checks whether command (name followed by arguments) requires privileged access
+func IsPrivileged(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	name := filepath.Base(args[0])
+	return fsCmds[name] || utilCmds[name] || strings.HasPrefix(name, mkfsPrefix)
+}
+
+// Server is the implementation of PrivilegedHelperServer which runs privileged operations
+type Server struct {
+	e     command.CmdExecutor
+	paths *pathValidator
+	sysfs string
+	log   *logrus.Entry
+}
+
+// NewServer is the constructor for Server struct
+// Receives CmdExecutor which is used to run commands, kubelet directory under which directories of the operations
+// have to be and logrus logger
+// Returns an instance of Server
+func NewServer(e command.CmdExecutor, kubeletDir string, logger *logrus.Logger) *Server {
+	return &Server{
+		e:     e,
+		paths: newPathValidator([]string{devRoot}, []string{kubeletDir}),
+		sysfs: linuxutils.SysfsPath,
+		log:   logger.WithField("component", "PrivilegedHelper"),
+	}
+}
+
+// Mount mounts source to the target directory
+// Receives golang context and MountRequest with source, target and mount options
+// Returns CmdResponse with stdout, stderr and error of mount or grpc error if request isn't allowed
+func (s *Server) Mount(ctx context.Context, req *api.MountRequest) (*api.CmdResponse, error) {
+	ll := s.log.WithField("method", "Mount")
+
+	target, err := s.paths.dir(req.GetTarget())
+	if err != nil {
+		return nil, s.reject(ll, err)
+	}
+	if err := s.paths.mountOpts(req.GetOpts()); err != nil {
+		return nil, s.reject(ll, err)
+	}
+	args := append([]string{mountCmd}, req.GetOpts()...)
+	if req.GetSource() != "" {
+		src, err := s.paths.mountSource(req.GetSource())
+		if err != nil {
+			return nil, s.reject(ll, err)
+		}
+		args = append(args, src)
+	}
+	return s.run(ctx, append(args, target)), nil
+}
+
+// Unmount unmounts the directory
+// Receives golang context and PathRequest with the directory
+// Returns CmdResponse with stdout, stderr and error of umount or grpc error if request isn't allowed
+func (s *Server) Unmount(ctx context.Context, req *api.PathRequest) (*api.CmdResponse, error) {
+	path, err := s.paths.dir(req.GetPath())
+	if err != nil {
+		return nil, s.reject(s.log.WithField("method", "Unmount"), err)
+	}
+	return s.run(ctx, []string{umountCmd, path}), nil
+}
+
+// MkDir creates directory with its parents
+// Receives golang context and PathRequest with the directory
+// Returns CmdResponse with stdout, stderr and error of mkdir or grpc error if request isn't allowed
+func (s *Server) MkDir(ctx context.Context, req *api.PathRequest) (*api.CmdResponse, error) {
+	path, err := s.paths.dir(req.GetPath())
+	if err != nil {
+		return nil, s.reject(s.log.WithField("method", "MkDir"), err)
+	}
+	return s.run(ctx, []string{mkdirCmd, "-p", path}), nil
+}
+
+// RmDir removes directory recursively
+// Receives golang context and PathRequest with the directory
+// Returns CmdResponse with stdout, stderr and error of rm or grpc error if request isn't allowed
+func (s *Server) RmDir(ctx context.Context, req *api.PathRequest) (*api.CmdResponse, error) {
+	path, err := s.paths.dir(req.GetPath())
+	if err != nil {
+		return nil, s.reject(s.log.WithField("method", "RmDir"), err)
+	}
+	return s.run(ctx, []string{rmCmd, "-rf", path}), nil
+}
+
+// WipeFS erases file system signatures of the device
+// Receives golang context and PathRequest with the device
+// Returns CmdResponse with stdout, stderr and error of wipefs or grpc error if request isn't allowed
+func (s *Server) WipeFS(ctx context.Context, req *api.PathRequest) (*api.CmdResponse, error) {
+	device, err := s.paths.device(req.GetPath())
+	if err != nil {
+		return nil, s.reject(s.log.WithField("method", "WipeFS"), err)
+	}
+	return s.run(ctx, []string{wipefsCmd, "-af", device}), nil
+}
+
+// MkFS creates file system on the device
+// Receives golang context and MkFSRequest with file system type, device and additional mkfs options
+// Returns CmdResponse with stdout, stderr and error of mkfs or grpc error if request isn't allowed
+func (s *Server) MkFS(ctx context.Context, req *api.MkFSRequest) (*api.CmdResponse, error) {
+	ll := s.log.WithField("method", "MkFS")
+
+	if !mkfsTypes[req.GetFsType()] {
+		return nil, s.reject(ll, fmt.Errorf("file system %s isn't supported", req.GetFsType()))
+	}
+	device, err := s.paths.device(req.GetDevice())
+	if err != nil {
+		return nil, s.reject(ll, err)
+	}
+	if err := s.paths.args(req.GetOpts()); err != nil {
+		return nil, s.reject(ll, err)
+	}
+	args := append([]string{mkfsPrefix + req.GetFsType()}, req.GetOpts()...)
+	return s.run(ctx, append(args, device)), nil
+}
+
+// RunCmd runs device management util from the allowlist, other commands are rejected
+// Receives golang context and CmdRequest with command name and arguments
+// Returns CmdResponse with stdout, stderr and error of the command or grpc error if command isn't allowed
+func (s *Server) RunCmd(ctx context.Context, req *api.CmdRequest) (*api.CmdResponse, error) {
+	ll := s.log.WithField("method", "RunCmd")
+
+	args := req.GetArgs()
+	if len(args) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "command must be provided")
+	}
+	name := filepath.Base(args[0])
+	if !utilCmds[name] {
+		return nil, s.reject(ll, fmt.Errorf("command %s is not allowed", args[0]))
+	}
+	if name == wipefsCmd && !probesOnly(args[1:]) {
+		return nil, s.reject(ll, fmt.Errorf("%s is allowed only to probe signatures", wipefsCmd))
+	}
+	if err := s.paths.args(args[1:]); err != nil {
+		return nil, s.reject(ll, err)
+	}
+	// util is looked up in PATH of the helper
+	return s.run(ctx, append([]string{name}, args[1:]...)), nil
+}
+
+// WriteSysfs writes attribute of block device request queue, other attributes of sysfs are rejected
+// Receives golang context and SysfsRequest with path of the attribute and its value
+// Returns CmdResponse with error of the write or grpc error if attribute isn't allowed
+func (s *Server) WriteSysfs(ctx context.Context, req *api.SysfsRequest) (*api.CmdResponse, error) {
+	ll := s.log.WithField("method", "WriteSysfs")
+
+	path, err := queueAttr(s.sysfs, req.GetPath())
+	if err != nil {
+		return nil, s.reject(ll, err)
+	}
+	if strings.TrimSpace(req.GetValue()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "value must be provided")
+	}
+	resp := &api.CmdResponse{}
+	if err := ioutil.WriteFile(path, []byte(req.GetValue()), 0644); err != nil {
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
+
+// run runs command without shell, so arguments are passed as is
+func (s *Server) run(ctx context.Context, args []string) *api.CmdResponse {
+	stdout, stderr, err := s.e.RunCmd(exec.CommandContext(ctx, args[0], args[1:]...))
+	resp := &api.CmdResponse{Stdout: stdout, Stderr: stderr}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	return resp
+}
+
+// reject logs reason of the rejected request and converts it to grpc error
+func (s *Server) reject(ll *logrus.Entry, err error) error {
+	ll.Warnf("Request is rejected: %v", err)
+	return status.Error(codes.PermissionDenied, err.Error())
+}
+
+// wipefsProbeOpts are the wipefs options which only print signatures of the device
+var wipefsProbeOpts = map[string]bool{
+	"--output":     true,
+	"--noheadings": true,
+	"--json":       true,
+	"--parsable":   true,
+	"--no-act":     true,
+}
+
+// probesOnly checks that wipefs arguments contain only options which print signatures without erasing them
+func probesOnly(args []string) bool {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") && !wipefsProbeOpts[arg] {
+			return false
+		}
+	}
+	return true
+}
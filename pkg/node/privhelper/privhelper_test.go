@@ -0,0 +1,343 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privhelper
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+var (
+	testLogger = logrus.New()
+	testCtx    = context.Background()
+)
+
+// helperClient calls Server directly instead of sending requests over the socket
+type helperClient struct {
+	s *Server
+}
+
+func (c *helperClient) Mount(ctx context.Context, in *api.MountRequest, opts ...grpc.CallOption) (*api.CmdResponse, error) {
+	return c.s.Mount(ctx, in)
+}
+
+func (c *helperClient) Unmount(ctx context.Context, in *api.PathRequest, opts ...grpc.CallOption) (*api.CmdResponse, error) {
+	return c.s.Unmount(ctx, in)
+}
+
+func (c *helperClient) MkDir(ctx context.Context, in *api.PathRequest, opts ...grpc.CallOption) (*api.CmdResponse, error) {
+	return c.s.MkDir(ctx, in)
+}
+
+func (c *helperClient) RmDir(ctx context.Context, in *api.PathRequest, opts ...grpc.CallOption) (*api.CmdResponse, error) {
+	return c.s.RmDir(ctx, in)
+}
+
+func (c *helperClient) WipeFS(ctx context.Context, in *api.PathRequest, opts ...grpc.CallOption) (*api.CmdResponse, error) {
+	return c.s.WipeFS(ctx, in)
+}
+
+func (c *helperClient) MkFS(ctx context.Context, in *api.MkFSRequest, opts ...grpc.CallOption) (*api.CmdResponse, error) {
+	return c.s.MkFS(ctx, in)
+}
+
+func (c *helperClient) RunCmd(ctx context.Context, in *api.CmdRequest, opts ...grpc.CallOption) (*api.CmdResponse, error) {
+	return c.s.RunCmd(ctx, in)
+}
+
+func (c *helperClient) WriteSysfs(ctx context.Context, in *api.SysfsRequest, opts ...grpc.CallOption) (*api.CmdResponse, error) {
+	return c.s.WriteSysfs(ctx, in)
+}
+
+// argsExecutor checks that helper runs exec.Cmd with expected arguments
+type argsExecutor struct {
+	mock.Mock
+}
+
+func (a *argsExecutor) RunCmd(cmd interface{}, opts ...command.Options) (string, string, error) {
+	args := a.Called(cmd.(*exec.Cmd).Args)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (a *argsExecutor) RunCmdWithAttempts(cmd interface{}, attempts int, timeout time.Duration,
+	opts ...command.Options) (string, string, error) {
+	return a.RunCmd(cmd, opts...)
+}
+
+func (a *argsExecutor) SetLevel(level logrus.Level) {}
+
+func (a *argsExecutor) onArgs(args ...string) *mock.Call {
+	return a.On("RunCmd", args)
+}
+
+// prepareServer creates server with temporary kubelet directory
+func prepareServer(t *testing.T) (*Server, *argsExecutor, string) {
+	kubeletDir, err := ioutil.TempDir("", "kubelet")
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(kubeletDir) })
+	e := &argsExecutor{}
+	return NewServer(e, kubeletDir, testLogger), e, kubeletDir
+}
+
+func TestIsPrivileged(t *testing.T) {
+	assert.True(t, IsPrivileged([]string{"mount", "/dev/sda", "/mnt"}))
+	assert.True(t, IsPrivileged([]string{"/sbin/lvm", "pvcreate", "--yes", "/dev/sda"}))
+	assert.True(t, IsPrivileged([]string{"mkfs.xfs", "/dev/sda1"}))
+	assert.True(t, IsPrivileged([]string{"losetup", "-d", "/dev/loop0"}))
+	assert.False(t, IsPrivileged([]string{"lsblk", "--json"}))
+	assert.False(t, IsPrivileged([]string{}))
+}
+
+func TestServer_Mount(t *testing.T) {
+	s, e, kubeletDir := prepareServer(t)
+	var (
+		staging = filepath.Join(kubeletDir, "plugins/kubernetes.io/csi/pv/pvc-1/globalmount")
+		target  = filepath.Join(kubeletDir, "pods/pod-1/volumes/kubernetes.io~csi/pvc-1/mount")
+	)
+
+	e.onArgs("mount", "--bind", staging, target).Return("", "", nil).Times(1)
+	resp, err := s.Mount(testCtx, &api.MountRequest{Source: staging, Target: target, Opts: []string{"--bind"}})
+	assert.Nil(t, err)
+	assert.Empty(t, resp.Error)
+
+	e.onArgs("mount", "-o", "remount,ro", staging).Return("", "busy", errors.New("exit status 32")).Times(1)
+	resp, err = s.Mount(testCtx, &api.MountRequest{Target: staging, Opts: []string{"-o", "remount,ro"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "busy", resp.Stderr)
+	assert.Equal(t, "exit status 32", resp.Error)
+
+	lowerDirs := "ro,lowerdir=" + staging + ":" + filepath.Join(kubeletDir, "pods/pod-1/cache")
+	e.onArgs("mount", "-t", "overlay", "-o", lowerDirs, "overlay", target).Return("", "", nil).Times(1)
+	_, err = s.Mount(testCtx, &api.MountRequest{Source: "overlay", Target: target,
+		Opts: []string{"-t", "overlay", "-o", lowerDirs}})
+	assert.Nil(t, err)
+
+	// paths outside of kubelet directory and devices
+	_, err = s.Mount(testCtx, &api.MountRequest{Source: "/dev/sda1", Target: "/etc"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.Mount(testCtx, &api.MountRequest{Source: "/etc", Target: target, Opts: []string{"--bind"}})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.Mount(testCtx, &api.MountRequest{Source: "/dev/sda1", Target: filepath.Join(kubeletDir, "../etc")})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.Mount(testCtx, &api.MountRequest{Source: "overlay", Target: target,
+		Opts: []string{"-t", "overlay", "-o", "lowerdir=/etc"}})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	// unsupported options
+	_, err = s.Mount(testCtx, &api.MountRequest{Source: staging, Target: target, Opts: []string{"--move"}})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.Mount(testCtx, &api.MountRequest{Source: staging, Target: target, Opts: []string{"-o"}})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// symlink inside of kubelet directory which points outside of it
+	assert.Nil(t, os.MkdirAll(filepath.Join(kubeletDir, "pods"), 0700))
+	assert.Nil(t, os.Symlink("/etc", filepath.Join(kubeletDir, "pods/link")))
+	_, err = s.Mount(testCtx, &api.MountRequest{Source: "/dev/sda1", Target: filepath.Join(kubeletDir, "pods/link/x")})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	e.AssertExpectations(t)
+}
+
+func TestServer_FSOperations(t *testing.T) {
+	s, e, kubeletDir := prepareServer(t)
+	dir := filepath.Join(kubeletDir, "plugins/kubernetes.io/csi/pv/pvc-1/globalmount")
+
+	e.onArgs("mkdir", "-p", dir).Return("", "", nil).Times(1)
+	_, err := s.MkDir(testCtx, &api.PathRequest{Path: dir})
+	assert.Nil(t, err)
+	e.onArgs("umount", dir).Return("", "", nil).Times(1)
+	_, err = s.Unmount(testCtx, &api.PathRequest{Path: dir})
+	assert.Nil(t, err)
+	e.onArgs("rm", "-rf", dir).Return("", "", nil).Times(1)
+	_, err = s.RmDir(testCtx, &api.PathRequest{Path: dir})
+	assert.Nil(t, err)
+	e.onArgs("wipefs", "-af", "/dev/sda").Return("", "", nil).Times(1)
+	_, err = s.WipeFS(testCtx, &api.PathRequest{Path: "/dev/sda"})
+	assert.Nil(t, err)
+	e.onArgs("mkfs.ext4", "-E", "lazy_itable_init=1", "/dev/sda1").Return("", "", nil).Times(1)
+	_, err = s.MkFS(testCtx, &api.MkFSRequest{FsType: "ext4", Device: "/dev/sda1", Opts: []string{"-E", "lazy_itable_init=1"}})
+	assert.Nil(t, err)
+
+	// kubelet directory itself and paths outside of it
+	_, err = s.RmDir(testCtx, &api.PathRequest{Path: kubeletDir})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.RmDir(testCtx, &api.PathRequest{Path: "/"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.MkDir(testCtx, &api.PathRequest{Path: "relative/dir"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.WipeFS(testCtx, &api.PathRequest{Path: dir})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.MkFS(testCtx, &api.MkFSRequest{FsType: "vfat", Device: "/dev/sda1"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.MkFS(testCtx, &api.MkFSRequest{FsType: "ext4", Device: "/dev/sda1", Opts: []string{"-d", "/etc"}})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	e.AssertExpectations(t)
+}
+
+func TestServer_RunCmd(t *testing.T) {
+	s, e, kubeletDir := prepareServer(t)
+
+	e.onArgs("lvm", "pvcreate", "--yes", "/dev/sda").Return("out", "", nil).Times(1)
+	resp, err := s.RunCmd(testCtx, &api.CmdRequest{Args: []string{"/sbin/lvm", "pvcreate", "--yes", "/dev/sda"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "out", resp.Stdout)
+
+	e.onArgs("wipefs", "/dev/sda", "--output", "TYPE", "--noheadings").Return("xfs", "", nil).Times(1)
+	resp, err = s.RunCmd(testCtx, &api.CmdRequest{Args: []string{"wipefs", "/dev/sda", "--output", "TYPE", "--noheadings"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "xfs", resp.Stdout)
+
+	probe := filepath.Join(kubeletDir, "plugins/selftest/mnt/probe")
+	e.onArgs("dd", "if=/dev/zero", "of="+probe, "bs=4096").Return("", "", nil).Times(1)
+	_, err = s.RunCmd(testCtx, &api.CmdRequest{Args: []string{"dd", "if=/dev/zero", "of=" + probe, "bs=4096"}})
+	assert.Nil(t, err)
+
+	// file system operations are sent as typed requests only
+	for _, args := range [][]string{
+		{"mount", "/dev/sda1", "/etc"},
+		{"rm", "-rf", "/"},
+		{"wipefs", "-af", "/dev/sda"},
+		{"wipefs", "--offset", "0", "/dev/sda"},
+		{"cat", "/etc/shadow"},
+		{"dd", "if=/dev/sda", "of=/etc/sda.img"},
+		{"losetup", "-f", "--show", "/etc/image"},
+	} {
+		_, err = s.RunCmd(testCtx, &api.CmdRequest{Args: args})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err), args)
+	}
+
+	_, err = s.RunCmd(testCtx, &api.CmdRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	e.AssertExpectations(t)
+}
+
+func TestServer_WriteSysfs(t *testing.T) {
+	s, _, _ := prepareServer(t)
+	sysfs, err := ioutil.TempDir("", "sysfs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(sysfs)
+	s.sysfs = sysfs
+	queueDir := filepath.Join(sysfs, "block/sda/queue")
+	assert.Nil(t, os.MkdirAll(queueDir, 0755))
+
+	resp, err := s.WriteSysfs(testCtx, &api.SysfsRequest{Path: filepath.Join(queueDir, "scheduler"), Value: "mq-deadline"})
+	assert.Nil(t, err)
+	assert.Empty(t, resp.Error)
+	data, err := ioutil.ReadFile(filepath.Join(queueDir, "scheduler"))
+	assert.Nil(t, err)
+	assert.Equal(t, "mq-deadline", string(data))
+
+	// device without request queue
+	resp, err = s.WriteSysfs(testCtx, &api.SysfsRequest{Path: filepath.Join(sysfs, "block/sdb/queue/nr_requests"), Value: "128"})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, resp.Error)
+
+	// other attributes of sysfs
+	for _, path := range []string{
+		filepath.Join(queueDir, "rotational"),
+		filepath.Join(sysfs, "block/sda/device/delete"),
+		filepath.Join(sysfs, "block/queue/scheduler"),
+		filepath.Join(queueDir, "../../sdb/../../../etc/queue/scheduler"),
+		"/etc/shadow",
+		"block/sda/queue/scheduler",
+	} {
+		_, err = s.WriteSysfs(testCtx, &api.SysfsRequest{Path: path, Value: "1"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err), path)
+	}
+	_, err = s.WriteSysfs(testCtx, &api.SysfsRequest{Path: filepath.Join(queueDir, "scheduler")})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestExecutor_WriteSysfs(t *testing.T) {
+	s, _, _ := prepareServer(t)
+	sysfs, err := ioutil.TempDir("", "sysfs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(sysfs)
+	s.sysfs = sysfs
+	queueDir := filepath.Join(sysfs, "block/sda/queue")
+	assert.Nil(t, os.MkdirAll(queueDir, 0755))
+	e := NewExecutor(&helperClient{s: s}, &mocks.GoMockExecutor{}, testLogger)
+
+	assert.Nil(t, e.WriteSysfs(filepath.Join(queueDir, "read_ahead_kb"), "4096"))
+	data, err := ioutil.ReadFile(filepath.Join(queueDir, "read_ahead_kb"))
+	assert.Nil(t, err)
+	assert.Equal(t, "4096", string(data))
+
+	err = e.WriteSysfs(filepath.Join(sysfs, "block/sda/device/delete"), "1")
+	assert.Equal(t, codes.PermissionDenied, status.Code(errors.Unwrap(err)))
+}
+
+func TestExecutor_RunCmd(t *testing.T) {
+	s, helperExecutor, kubeletDir := prepareServer(t)
+	localExecutor := &mocks.GoMockExecutor{}
+	e := NewExecutor(&helperClient{s: s}, localExecutor, testLogger)
+	target := filepath.Join(kubeletDir, "pods/pod-1/mount")
+
+	helperExecutor.onArgs("mkfs.ext4", "-E", "lazy_itable_init=1", "/dev/sda1").Return("", "", nil).Times(1)
+	_, _, err := e.RunCmd("mkfs.ext4 /dev/sda1 -E lazy_itable_init=1")
+	assert.Nil(t, err)
+
+	helperExecutor.onArgs("mount", "-o", "dax", "/dev/pmem0", target).Return("", "", nil).Times(1)
+	_, _, err = e.RunCmd("mount -o dax /dev/pmem0 " + target)
+	assert.Nil(t, err)
+
+	helperExecutor.onArgs("wipefs", "-af", "/dev/sda").Return("", "", errors.New("error")).Times(1)
+	_, _, err = e.RunCmd("wipefs -af /dev/sda")
+	assert.Equal(t, "error", err.Error())
+
+	_, _, err = e.RunCmd("rm -rf /")
+	assert.Equal(t, codes.PermissionDenied, status.Code(errors.Unwrap(err)))
+
+	localExecutor.OnCommand("lsblk /dev/sda").Return("out", "", nil).Times(1)
+	stdout, _, err := e.RunCmd("lsblk /dev/sda")
+	assert.Nil(t, err)
+	assert.Equal(t, "out", stdout)
+
+	helperExecutor.AssertExpectations(t)
+	localExecutor.AssertExpectations(t)
+}
+
+func TestParseMount(t *testing.T) {
+	req, err := parseMount([]string{"--bind", "-o", "ro", "/src", "/dst"})
+	assert.Nil(t, err)
+	assert.Equal(t, &api.MountRequest{Source: "/src", Target: "/dst", Opts: []string{"--bind", "-o", "ro"}}, req)
+
+	req, err = parseMount([]string{"-o", "remount,ro", "/dst"})
+	assert.Nil(t, err)
+	assert.Equal(t, &api.MountRequest{Target: "/dst", Opts: []string{"-o", "remount,ro"}}, req)
+
+	_, err = parseMount([]string{"--bind"})
+	assert.NotNil(t, err)
+}
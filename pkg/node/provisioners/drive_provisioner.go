@@ -18,21 +18,28 @@ package provisioners
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
 	"github.com/dell/csi-baremetal/pkg/base/command"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockqueue"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/integrity"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/partitionhelper"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/zoned"
 	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/node/faults"
 	uw "github.com/dell/csi-baremetal/pkg/node/provisioners/utilwrappers"
 )
 
@@ -51,9 +58,21 @@ type DriveProvisioner struct {
 	fsOps fs.WrapFS
 	// partOps uses for operations with partitions
 	partOps uw.PartitionOperations
+	// intOps uses for integrity protection of volumes
+	intOps integrity.WrapIntegrity
+	// zonedOps uses for volumes on zoned drives which are formatted without partitions
+	zonedOps zoned.WrapZoned
+	// blockQueue reads I/O geometry of devices for format profiles
+	blockQueue blockqueue.WrapBlockQueue
 
 	k8sClient *k8s.KubeClient
 	crHelper  *k8s.CRHelper
+	// phases tracks time of partition and FS creation
+	phases *PhaseTracker
+	// audit records format, wipe and removal of partitions
+	audit *audit.Logger
+	// faults injects failures for chaos testing
+	faults *faults.Injector
 
 	log *logrus.Entry
 }
@@ -64,18 +83,36 @@ func NewDriveProvisioner(
 	k *k8s.KubeClient,
 	log *logrus.Logger) *DriveProvisioner {
 	return &DriveProvisioner{
-		listBlk:   lsblk.NewLSBLK(log),
-		fsOps:     fs.NewFSImpl(e),
-		partOps:   uw.NewPartitionOperationsImpl(e, log),
-		k8sClient: k,
-		crHelper:  k8s.NewCRHelper(k, log),
-		log:       log.WithField("component", "DriveProvisioner"),
+		listBlk:    lsblk.NewLSBLK(log),
+		fsOps:      fs.NewFSImpl(e),
+		partOps:    uw.NewPartitionOperationsImpl(e, log),
+		intOps:     integrity.NewIntegrity(e),
+		zonedOps:   zoned.NewZoned(e),
+		blockQueue: blockqueue.NewBlockQueue(e, log),
+		k8sClient:  k,
+		crHelper:   k8s.NewCRHelper(k, log),
+		log:        log.WithField("component", "DriveProvisioner"),
 	}
 }
 
+// SetPhaseTracker sets tracker for provisioning phases of the volumes
+func (d *DriveProvisioner) SetPhaseTracker(t *PhaseTracker) {
+	d.phases = t
+}
+
+// SetAuditLogger sets logger for destructive operations
+func (d *DriveProvisioner) SetAuditLogger(a *audit.Logger) {
+	d.audit = a
+}
+
+// SetFaultInjector sets injector of failures which is used for chaos testing
+func (d *DriveProvisioner) SetFaultInjector(i *faults.Injector) {
+	d.faults = i
+}
+
 // PrepareVolume create partition and FS based on vol attributes.
 // After that partition is ready for mount operations
-func (d *DriveProvisioner) PrepareVolume(vol api.Volume) error {
+func (d *DriveProvisioner) PrepareVolume(ctx context.Context, vol api.Volume) error {
 	ll := d.log.WithFields(logrus.Fields{
 		"method":   "PrepareVolume",
 		"volumeID": vol.Id,
@@ -83,14 +120,18 @@ func (d *DriveProvisioner) PrepareVolume(vol api.Volume) error {
 	ll.Infof("Processing for volume %v", vol)
 
 	var (
-		ctxWithID = context.WithValue(context.Background(), base.RequestUUID, vol.Id)
+		ctxWithID = context.WithValue(ctx, base.RequestUUID, vol.Id)
 		drive     = &drivecrd.Drive{}
 		err       error
 	)
 
 	// read Drive CR based on Volume.Location (vol.Location == Drive.UUID == Drive.Name)
 	if err = d.k8sClient.ReadCR(ctxWithID, vol.Location, "", drive); err != nil {
-		return fmt.Errorf("failed to read drive CR with name %s, error %v", vol.Location, err)
+		return fmt.Errorf("failed to read drive CR with name %s, error %w", vol.Location, err)
+	}
+	// data of NOT_CLEAN drive wasn't created by the driver, it isn't overwritten until operator clears the drive
+	if drive.Spec.Usage == apiV1.DriveUsageNotClean {
+		return fmt.Errorf("%w: drive %s has %s usage", errTypes.ErrNotClean, drive.Spec.SerialNumber, drive.Spec.Usage)
 	}
 
 	ll.Infof("Search device file for drive with S/N %s", drive.Spec.SerialNumber)
@@ -103,7 +144,30 @@ func (d *DriveProvisioner) PrepareVolume(vol api.Volume) error {
 		return nil
 	}
 
+	target := auditTarget{requestID: vol.Id, serial: drive.Spec.SerialNumber}
+	if vol.StorageClass == apiV1.StorageClassZoned {
+		return d.prepareZonedVolume(target, vol, device)
+	}
 	partUUID, _ := util.GetVolumeUUID(vol.Id)
+	if warmUUID, ok := drive.Annotations[apiV1.DriveAnnotationScratchPartition]; ok {
+		// annotation is removed before any disk operation, partition is either reused or released below
+		if err = d.setScratchPartition(ctxWithID, drive, ""); err != nil {
+			return err
+		}
+		// warm partition is formatted with default options, so it isn't reused if volume requires mkfs options
+		if vol.Scratch && !vol.Ephemeral && vol.MkFSOptions == "" && vol.FormatProfile == "" {
+			started := time.Now()
+			if err = d.reuseScratchPartition(target, device, warmUUID, partUUID, fs.FileSystem(vol.Type)); err == nil {
+				d.phases.Record(vol.Id, volumecrd.VolumePhaseFormatted, started)
+				return nil
+			}
+			ll.Warnf("Unable to reuse warm partition %s: %v", warmUUID, err)
+		}
+		if err = d.releaseScratchPartition(target, device, warmUUID); err != nil {
+			return fmt.Errorf("unable to release warm partition %s: %w", warmUUID, err)
+		}
+	}
+
 	part := uw.Partition{
 		Device:    device,
 		TableType: partitionhelper.PartitionGPT,
@@ -114,30 +178,42 @@ func (d *DriveProvisioner) PrepareVolume(vol api.Volume) error {
 	}
 
 	ll.Infof("Create partition %v on device %s and set UUID", part, device)
+	started := time.Now()
 	partPtr, err := d.partOps.PreparePartition(part)
 	if err != nil {
 		ll.Errorf("Unable to prepare partition: %v", err)
 		return fmt.Errorf("unable to prepare partition for volume %v", vol)
 	}
+	d.phases.Record(vol.Id, volumecrd.VolumePhasePartitionCreated, started)
 	ll.Infof("Partition was created successfully %v", partPtr)
 
 	// create FS
-	return d.fsOps.CreateFS(fs.FileSystem(vol.Type), partPtr.GetFullPath())
+	if err = d.faults.Inject(ctx, faults.CreateFS); err != nil {
+		return err
+	}
+	started = time.Now()
+	err = createVolumeFS(d.intOps, d.fsOps, d.blockQueue, vol, partPtr.GetFullPath())
+	d.audit.Record(audit.OperationFormat, target.requestID, partPtr.GetFullPath(), target.serial, err)
+	if err != nil {
+		return err
+	}
+	d.phases.Record(vol.Id, volumecrd.VolumePhaseFormatted, started)
+	return nil
 }
 
 // ReleaseVolume remove FS and partition based on vol attributes.
 // After that partition is completely removed
-func (d *DriveProvisioner) ReleaseVolume(vol api.Volume) error {
+func (d *DriveProvisioner) ReleaseVolume(ctx context.Context, vol api.Volume) error {
 	ll := d.log.WithFields(logrus.Fields{
 		"method":   "ReleaseVolume",
 		"volumeID": vol.Id,
 	})
 	ll.Infof("Processing for volume %v", vol)
 
-	drive := d.crHelper.GetDriveCRByUUID(vol.Location)
+	drive := d.crHelper.GetDriveCRByUUID(ctx, vol.Location)
 
 	if drive == nil {
-		return errors.New("unable to find drive by vol location")
+		return fmt.Errorf("%w: unable to find drive by location %s", errTypes.ErrDriveNotFound, vol.Location)
 	}
 	ll.Debugf("Got drive %v", drive)
 
@@ -149,6 +225,7 @@ func (d *DriveProvisioner) ReleaseVolume(vol api.Volume) error {
 	ll.Debugf("Got device %s", device)
 
 	var (
+		target      = auditTarget{requestID: vol.Id, serial: drive.Spec.SerialNumber}
 		partUUID, _ = util.GetVolumeUUID(vol.Id)
 		part        = uw.Partition{
 			Device:   device,
@@ -157,75 +234,264 @@ func (d *DriveProvisioner) ReleaseVolume(vol api.Volume) error {
 		}
 	)
 
+	if vol.StorageClass == apiV1.StorageClassZoned {
+		return d.releaseZonedVolume(target, device)
+	}
+
 	// TODO: temporary solution because of ephemeral volumes volume id - https://github.com/dell/csi-baremetal/issues/87
 	if vol.Ephemeral {
 		part.PartUUID, err = d.partOps.GetPartitionUUID(device, DefaultPartitionNumber)
 		if err != nil {
-			return d.wipeDevice(device,
-				fmt.Errorf("unable to determine partition UUID for ephemeral volume: %v", err), ll)
+			return d.wipeDevice(target, device,
+				fmt.Errorf("unable to determine partition UUID for ephemeral volume: %w", err), ll)
 		}
 	}
 
 	part.Name = d.partOps.SearchPartName(device, part.PartUUID)
 	if part.Name == "" {
-		return d.wipeDevice(device,
+		return d.wipeDevice(target, device,
 			fmt.Errorf("unable to find partition name for volume %s", vol.Id), ll)
 	}
 
+	if err = closeIntegrityDevice(d.intOps, vol); err != nil {
+		return err
+	}
+
+	if vol.Scratch {
+		if err = d.keepScratchPartition(target, drive, part, fs.FileSystem(vol.Type)); err == nil {
+			return nil
+		}
+		ll.Warnf("Unable to keep partition of scratch volume, it will be released: %v", err)
+	}
+
 	// wipe FS on partition
-	if err = d.fsOps.WipeFS(part.GetFullPath()); err != nil {
+	if err = d.wipeFS(target, part.GetFullPath()); err != nil {
 		return err
 	}
 
-	err = d.partOps.ReleasePartition(part)
+	err = d.releasePartition(target, part)
 	if err != nil {
-		return fmt.Errorf("unable to release partition: %v", err)
+		return fmt.Errorf("unable to release partition: %w", err)
 	}
 
 	// wipe all superblocks (wipe partition table signature)
-	return d.fsOps.WipeFS(device)
+	return d.wipeFS(target, device)
+}
+
+// ReformatVolume wipes file system on the partition of the volume and creates file system of the volume type,
+// dm-integrity device of the volume is kept and file system is recreated on top of it.
+// All data of the volume is destroyed, so it should be called only if user has confirmed data erase
+func (d *DriveProvisioner) ReformatVolume(ctx context.Context, vol api.Volume) error {
+	ll := d.log.WithFields(logrus.Fields{
+		"method":   "ReformatVolume",
+		"volumeID": vol.Id,
+	})
+
+	if vol.Mode == apiV1.ModeRAW {
+		return fmt.Errorf("volume in %s mode has no file system", vol.Mode)
+	}
+	drive := d.crHelper.GetDriveCRByUUID(ctx, vol.Location)
+	if drive == nil {
+		return fmt.Errorf("%w: unable to find drive by location %s", errTypes.ErrDriveNotFound, vol.Location)
+	}
+	device, err := d.GetVolumePath(ctx, vol)
+	if err != nil {
+		return err
+	}
+	ll.Warnf("Recreate %s file system on %s, data of the volume is erased", vol.Type, device)
+
+	target := auditTarget{requestID: vol.Id, serial: drive.Spec.SerialNumber}
+	if vol.StorageClass == apiV1.StorageClassZoned {
+		if err = d.releaseZonedVolume(target, device); err != nil {
+			return err
+		}
+		err = d.zonedOps.CreateFS(fs.FileSystem(vol.Type), device)
+	} else {
+		if err = d.wipeFS(target, device); err != nil {
+			return err
+		}
+		err = recreateVolumeFS(d.intOps, d.fsOps, d.blockQueue, vol, device)
+	}
+	d.audit.Record(audit.OperationFormat, target.requestID, device, target.serial, err)
+	return err
+}
+
+// prepareZonedVolume creates zone aware file system on the whole zoned drive,
+// zoned drives aren't partitioned since partitions of zoned block devices aren't supported by kernel
+func (d *DriveProvisioner) prepareZonedVolume(target auditTarget, vol api.Volume, device string) error {
+	if vol.Integrity != "" {
+		return fmt.Errorf("integrity protection %s isn't supported for volumes on zoned drives", vol.Integrity)
+	}
+	if err := d.faults.Inject(context.Background(), faults.CreateFS); err != nil {
+		return err
+	}
+	started := time.Now()
+	err := d.zonedOps.CreateFS(fs.FileSystem(vol.Type), device)
+	d.audit.Record(audit.OperationFormat, target.requestID, device, target.serial, err)
+	if err != nil {
+		return err
+	}
+	d.phases.Record(vol.Id, volumecrd.VolumePhaseFormatted, started)
+	return nil
+}
+
+// releaseZonedVolume resets all zones of the drive and wipes file system signatures left in conventional zones
+func (d *DriveProvisioner) releaseZonedVolume(target auditTarget, device string) error {
+	if err := d.zonedOps.ResetZones(device); err != nil {
+		return err
+	}
+	return d.wipeFS(target, device)
+}
+
+// keepScratchPartition reformats partition of released scratch volume instead of its removal and marks it
+// in drive annotation as warm, after that partition could be reused by next scratch volume on the drive
+func (d *DriveProvisioner) keepScratchPartition(target auditTarget, drive *drivecrd.Drive, part uw.Partition,
+	fsType fs.FileSystem) error {
+	if err := d.wipeFS(target, part.GetFullPath()); err != nil {
+		return err
+	}
+	if err := d.createFS(target, fsType, part.GetFullPath()); err != nil {
+		return err
+	}
+	ctxWithID := context.WithValue(context.Background(), base.RequestUUID, part.PartUUID)
+	return d.setScratchPartition(ctxWithID, drive, part.PartUUID)
+}
+
+// reuseScratchPartition assigns warm partition with UUID warmUUID to the volume with partition UUID partUUID,
+// file system is recreated only if it differs from the required one
+func (d *DriveProvisioner) reuseScratchPartition(target auditTarget, device, warmUUID, partUUID string,
+	fsType fs.FileSystem) error {
+	part := uw.Partition{Device: device, Num: DefaultPartitionNumber}
+	if part.Name = d.partOps.SearchPartName(device, warmUUID); part.Name == "" {
+		return fmt.Errorf("unable to find partition name on device %s", device)
+	}
+	if currFS, err := d.fsOps.GetFSType(part.GetFullPath()); err != nil || currFS != fsType {
+		if err = d.wipeFS(target, part.GetFullPath()); err != nil {
+			return err
+		}
+		if err = d.createFS(target, fsType, part.GetFullPath()); err != nil {
+			return err
+		}
+	}
+	return d.partOps.SetPartitionUUID(device, DefaultPartitionNumber, partUUID)
+}
+
+// releaseScratchPartition completely removes warm partition with UUID warmUUID from device
+func (d *DriveProvisioner) releaseScratchPartition(target auditTarget, device, warmUUID string) error {
+	part := uw.Partition{Device: device, Num: DefaultPartitionNumber, PartUUID: warmUUID}
+	if part.Name = d.partOps.SearchPartName(device, warmUUID); part.Name != "" {
+		if err := d.wipeFS(target, part.GetFullPath()); err != nil {
+			return err
+		}
+	}
+	if err := d.releasePartition(target, part); err != nil {
+		return err
+	}
+	return d.wipeFS(target, device)
+}
+
+// setScratchPartition sets UUID of warm partition in drive annotation, empty UUID removes annotation
+func (d *DriveProvisioner) setScratchPartition(ctx context.Context, drive *drivecrd.Drive, partUUID string) error {
+	if partUUID == "" {
+		delete(drive.Annotations, apiV1.DriveAnnotationScratchPartition)
+	} else {
+		if drive.Annotations == nil {
+			drive.Annotations = make(map[string]string)
+		}
+		drive.Annotations[apiV1.DriveAnnotationScratchPartition] = partUUID
+	}
+	if err := d.k8sClient.UpdateCR(ctx, drive); err != nil {
+		return fmt.Errorf("unable to update scratch partition annotation of drive %s: %w", drive.Name, err)
+	}
+	return nil
 }
 
 // wipeDevice check is there any partition on device or not,
 // if there are no partition - wipe device and return nil, if any - returns error that had been provided
 // device - device to check, err - error to return, ll - logger for logging
-func (d *DriveProvisioner) wipeDevice(device string, err error, ll *logrus.Entry) error {
+func (d *DriveProvisioner) wipeDevice(target auditTarget, device string, err error, ll *logrus.Entry) error {
 	// DriveProvisioner assumes that there could be only one partition per drive
 	bdevs, sErr := d.listBlk.GetBlockDevices(device)
 	if sErr == nil && (len(bdevs) == 0 || bdevs[0].Children == nil) {
 		ll.Infof("No partitions found for device %s", device)
-		return d.fsOps.WipeFS(device) // wipe partition table
+		return d.wipeFS(target, device) // wipe partition table
 	}
 	return err
 }
 
+// wipeFS wipes file system and partition table signatures on device and records it in audit log
+func (d *DriveProvisioner) wipeFS(target auditTarget, device string) error {
+	err := d.fsOps.WipeFS(device)
+	d.audit.Record(audit.OperationWipe, target.requestID, device, target.serial, err)
+	return err
+}
+
+// createFS creates file system on device and records it in audit log
+func (d *DriveProvisioner) createFS(target auditTarget, fsType fs.FileSystem, device string) error {
+	err := d.fsOps.CreateFS(fsType, device)
+	d.audit.Record(audit.OperationFormat, target.requestID, device, target.serial, err)
+	return err
+}
+
+// releasePartition removes partition and records it in audit log
+func (d *DriveProvisioner) releasePartition(target auditTarget, part uw.Partition) error {
+	err := d.partOps.ReleasePartition(part)
+	d.audit.Record(audit.OperationPartitionDelete, target.requestID, part.GetFullPath(), target.serial, err)
+	return err
+}
+
+// VolumeExists checks whether partition of the volume is found in partition table of the drive,
+// partition table is re-read before the search, so partitions which were wiped are detected
+// Volume in raw mode and volume on zoned drive occupy the whole drive, so they exist while drive device exists
+// Returns error if drive of the volume isn't found
+func (d *DriveProvisioner) VolumeExists(ctx context.Context, vol api.Volume) (bool, error) {
+	drive := d.crHelper.GetDriveCRByUUID(ctx, vol.Location)
+	if drive == nil {
+		return false, fmt.Errorf("%w: unable to find drive by location %s", errTypes.ErrDriveNotFound, vol.Location)
+	}
+	device, err := d.listBlk.SearchDrivePath(drive)
+	if err != nil {
+		return false, fmt.Errorf("unable to find device for drive with S/N %s: %w", drive.Spec.SerialNumber, err)
+	}
+	if vol.Mode == apiV1.ModeRAW || vol.StorageClass == apiV1.StorageClassZoned {
+		return true, nil
+	}
+	partUUID, _ := util.GetVolumeUUID(vol.Id)
+	return d.partOps.SearchPartName(device, partUUID) != "", nil
+}
+
 // GetVolumePath constructs full partition path - /dev/DEVICE_NAME+PARTITION_NAME
-func (d *DriveProvisioner) GetVolumePath(vol api.Volume) (string, error) {
+func (d *DriveProvisioner) GetVolumePath(ctx context.Context, vol api.Volume) (string, error) {
 	ll := d.log.WithFields(logrus.Fields{
 		"method":   "GetVolumePath",
 		"volumeID": vol.Id,
 	})
 
-	drive := d.crHelper.GetDriveCRByUUID(vol.Location)
+	drive := d.crHelper.GetDriveCRByUUID(ctx, vol.Location)
 
 	if drive == nil {
-		return "", fmt.Errorf("unable to find drive by location %s", vol.Location)
+		return "", fmt.Errorf("%w: unable to find drive by location %s", errTypes.ErrDriveNotFound, vol.Location)
 	}
 	ll.Debugf("Got drive %v", drive)
 
 	// get deviceFile path
 	device, err := d.listBlk.SearchDrivePath(drive)
 	if err != nil {
-		return "", fmt.Errorf("unable to find device for drive with S/N %s: %v", vol.Location, err)
+		return "", fmt.Errorf("unable to find device for drive with S/N %s: %w", vol.Location, err)
 	}
 	ll.Debugf("Got device %s", device)
 
+	// file system of the volume on zoned drive is created on the whole device
+	if vol.StorageClass == apiV1.StorageClassZoned {
+		return device, nil
+	}
+
 	var volumeUUID = vol.Id
 	// TODO: temporary solution because of ephemeral volumes volume id - https://github.com/dell/csi-baremetal/issues/87
 	if vol.Ephemeral {
 		volumeUUID, err = d.partOps.GetPartitionUUID(device, DefaultPartitionNumber)
 		if err != nil {
-			return "", fmt.Errorf("unable to determine partition UUID: %v", err)
+			return "", fmt.Errorf("unable to determine partition UUID: %w", err)
 		}
 	}
 	if vol.Mode == apiV1.ModeRAW {
@@ -237,5 +503,5 @@ func (d *DriveProvisioner) GetVolumePath(vol api.Volume) (string, error) {
 	if partNum == "" {
 		return "", fmt.Errorf("unable to find part name for device %s by uuid %s", device, volumeUUID)
 	}
-	return device + partNum, nil
+	return volumeDevicePath(d.intOps, vol, device+partNum)
 }
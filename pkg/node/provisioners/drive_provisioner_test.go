@@ -17,6 +17,12 @@ limitations under the License.
 package provisioners
 
 import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -24,8 +30,12 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
 	"github.com/dell/csi-baremetal/pkg/base/command"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/partitionhelper"
@@ -92,8 +102,15 @@ func TestDriveProvisioner_PrepareVolume_Success(t *testing.T) {
 	mockFS.On("CreateFS", fs.FileSystem(testVolume2.Type), expectedPart.GetFullPath()).
 		Return(nil)
 
-	err = dp.PrepareVolume(testVolume2)
+	phases := NewPhaseTracker()
+	dp.SetPhaseTracker(phases)
+	err = dp.PrepareVolume(testCtx, testVolume2)
 	assert.Nil(t, err)
+
+	recorded := phases.Pop(testVolume2.Id)
+	assert.Contains(t, recorded, volumecrd.VolumePhasePartitionCreated)
+	assert.Contains(t, recorded, volumecrd.VolumePhaseFormatted)
+	assert.Nil(t, phases.Pop(testVolume2.Id))
 }
 
 func TestDriveProvisioner_PrepareVolume_Fail(t *testing.T) {
@@ -103,10 +120,19 @@ func TestDriveProvisioner_PrepareVolume_Fail(t *testing.T) {
 	)
 
 	// drive CR isn't exist
-	err = dp.PrepareVolume(testVolume2)
+	err = dp.PrepareVolume(testCtx, testVolume2)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "failed to read drive CR with name")
 
+	// data of NOT_CLEAN drive isn't overwritten
+	notCleanDrive := testDriveCR.DeepCopy()
+	notCleanDrive.Spec.Usage = apiV1.DriveUsageNotClean
+	err = dp.k8sClient.CreateCR(testCtx, notCleanDrive.Name, notCleanDrive)
+	assert.Nil(t, err)
+	err = dp.PrepareVolume(testCtx, testVolume2)
+	assert.True(t, errors.Is(err, errTypes.ErrNotClean))
+	assert.Nil(t, dp.k8sClient.DeleteCR(testCtx, notCleanDrive))
+
 	// add drive CR
 	err = dp.k8sClient.CreateCR(testCtx, testDriveCR.Name, &testDriveCR)
 	assert.Nil(t, err)
@@ -115,7 +141,7 @@ func TestDriveProvisioner_PrepareVolume_Fail(t *testing.T) {
 	mockLsblk.On("SearchDrivePath", mock.Anything).
 		Return("", errTest).Once()
 
-	err = dp.PrepareVolume(testVolume2)
+	err = dp.PrepareVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Equal(t, errTest, err)
 
@@ -127,7 +153,7 @@ func TestDriveProvisioner_PrepareVolume_Fail(t *testing.T) {
 	mockPH.On("PreparePartition", mock.Anything).
 		Return(&uw.Partition{}, errTest).Once()
 
-	err = dp.PrepareVolume(testVolume2)
+	err = dp.PrepareVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unable to prepare partition for volume")
 
@@ -136,7 +162,7 @@ func TestDriveProvisioner_PrepareVolume_Fail(t *testing.T) {
 		Return(&uw.Partition{}, nil).Once()
 	mockFS.On("CreateFS", fs.FileSystem(testVolume2.Type), mock.Anything).Return(errTest)
 
-	err = dp.PrepareVolume(testVolume2)
+	err = dp.PrepareVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Equal(t, errTest, err)
 }
@@ -172,7 +198,7 @@ func TestDriveProvisioner_ReleaseVolume_Success(t *testing.T) {
 	mockPH.On("ReleasePartition", part).Return(nil)
 	mockFS.On("WipeFS", deviceFile).Return(nil).Once()
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Nil(t, err)
 
 	// SearchPartName failed but partition isn't exist (was removed before)
@@ -183,10 +209,127 @@ func TestDriveProvisioner_ReleaseVolume_Success(t *testing.T) {
 	mockLsblk.On("GetBlockDevices", deviceFile).Return(nil, nil).Once()
 	mockFS.On("WipeFS", deviceFile).Return(nil).Once()
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Nil(t, err)
 }
 
+func TestDriveProvisioner_ReleaseVolume_Audit(t *testing.T) {
+	var (
+		dp, mockLsblk, mockPH, mockFS = setupTestDriveProvisioner()
+		deviceFile                    = "/dev/sda"
+		partName                      = "p1"
+	)
+	dir, err := ioutil.TempDir("", "audit")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	auditPath := filepath.Join(dir, "audit.log")
+	auditLog := audit.NewLogger()
+	assert.Nil(t, auditLog.SetOutputFile(auditPath))
+	dp.SetAuditLogger(auditLog)
+
+	err = dp.k8sClient.CreateCR(testCtx, testDriveCR.Name, &testDriveCR)
+	assert.Nil(t, err)
+
+	mockLsblk.On("SearchDrivePath",
+		mock.MatchedBy(func(d *drivecrd.Drive) bool { return d.Name == testDriveCR.Name })).
+		Return(deviceFile, nil).Once()
+	mockPH.On("SearchPartName", deviceFile, testVolume2.Id).Return(partName, nil).Once()
+	mockFS.On("WipeFS", deviceFile+partName).Return(nil).Once()
+	mockPH.On("ReleasePartition", mock.Anything).Return(nil)
+	mockFS.On("WipeFS", deviceFile).Return(errTest).Once()
+
+	err = dp.ReleaseVolume(testCtx, testVolume2)
+	assert.NotNil(t, err)
+
+	data, err := ioutil.ReadFile(auditPath)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 3)
+	expected := []struct{ operation, device, outcome string }{
+		{audit.OperationWipe, deviceFile + partName, audit.OutcomeSuccess},
+		{audit.OperationPartitionDelete, deviceFile + partName, audit.OutcomeSuccess},
+		{audit.OperationWipe, deviceFile, audit.OutcomeFailure},
+	}
+	for i, line := range lines {
+		record := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &record))
+		assert.Equal(t, expected[i].operation, record["operation"])
+		assert.Equal(t, expected[i].device, record["device"])
+		assert.Equal(t, expected[i].outcome, record["outcome"])
+		assert.Equal(t, testVolume2.Id, record["requestID"])
+		assert.Equal(t, testDriveCR.Spec.SerialNumber, record["serial"])
+	}
+}
+
+func TestDriveProvisioner_ScratchVolume(t *testing.T) {
+	var (
+		dp, mockLsblk, mockPH, mockFS = setupTestDriveProvisioner()
+		deviceFile                    = "/dev/sda"
+		partName                      = "p1"
+		scratchVolume                 = testVolume2
+		nextVolume                    = testVolume2
+	)
+	scratchVolume.Scratch = true
+	nextVolume.Id = "volume-3-id"
+	nextVolume.Scratch = true
+
+	assert.Nil(t, dp.k8sClient.CreateCR(testCtx, testDriveCR.Name, testDriveCR.DeepCopy()))
+	mockLsblk.On("SearchDrivePath", mock.Anything).Return(deviceFile, nil)
+	mockPH.On("SearchPartName", deviceFile, scratchVolume.Id).Return(partName)
+
+	// partition is reformatted and kept on release
+	mockFS.On("WipeFS", deviceFile+partName).Return(nil).Once()
+	mockFS.On("CreateFS", fs.FileSystem(scratchVolume.Type), deviceFile+partName).Return(nil).Once()
+
+	assert.Nil(t, dp.ReleaseVolume(testCtx, scratchVolume))
+	drive := &drivecrd.Drive{}
+	assert.Nil(t, dp.k8sClient.ReadCR(testCtx, testDriveCR.Name, "", drive))
+	assert.Equal(t, scratchVolume.Id, drive.Annotations[apiV1.DriveAnnotationScratchPartition])
+	mockPH.AssertNotCalled(t, "ReleasePartition", mock.Anything)
+
+	// warm partition is reused by next scratch volume without mkfs
+	mockFS.On("GetFSType", deviceFile+partName).Return(fs.FileSystem(nextVolume.Type), nil).Once()
+	mockPH.MockWrapPartition.On("SetPartitionUUID", deviceFile, DefaultPartitionNumber, nextVolume.Id).
+		Return(nil).Once()
+
+	phases := NewPhaseTracker()
+	dp.SetPhaseTracker(phases)
+	assert.Nil(t, dp.PrepareVolume(testCtx, nextVolume))
+	assert.Contains(t, phases.Pop(nextVolume.Id), volumecrd.VolumePhaseFormatted)
+	mockPH.AssertNotCalled(t, "PreparePartition", mock.Anything)
+	mockFS.AssertNumberOfCalls(t, "CreateFS", 1)
+	drive = &drivecrd.Drive{}
+	assert.Nil(t, dp.k8sClient.ReadCR(testCtx, testDriveCR.Name, "", drive))
+	assert.NotContains(t, drive.Annotations, apiV1.DriveAnnotationScratchPartition)
+}
+
+func TestDriveProvisioner_PrepareVolume_ReleaseScratchPartition(t *testing.T) {
+	var (
+		dp, mockLsblk, mockPH, mockFS = setupTestDriveProvisioner()
+		deviceFile                    = "/dev/sda"
+		warmPartName                  = "p1"
+		warmUUID                      = "warm-uuid"
+		drive                         = testDriveCR.DeepCopy()
+	)
+	drive.Annotations = map[string]string{apiV1.DriveAnnotationScratchPartition: warmUUID}
+	assert.Nil(t, dp.k8sClient.CreateCR(testCtx, drive.Name, drive))
+
+	// volume without scratch mode requires warm partition to be released
+	mockLsblk.On("SearchDrivePath", mock.Anything).Return(deviceFile, nil)
+	mockPH.On("SearchPartName", deviceFile, warmUUID).Return(warmPartName).Once()
+	mockFS.On("WipeFS", deviceFile+warmPartName).Return(nil).Once()
+	mockPH.On("ReleasePartition", mock.Anything).Return(nil).Once()
+	mockFS.On("WipeFS", deviceFile).Return(nil).Once()
+	mockPH.On("PreparePartition", mock.Anything).
+		Return(&uw.Partition{Device: deviceFile, Name: warmPartName}, nil).Once()
+	mockFS.On("CreateFS", fs.FileSystem(testVolume2.Type), deviceFile+warmPartName).Return(nil).Once()
+
+	dp.SetPhaseTracker(NewPhaseTracker())
+	assert.Nil(t, dp.PrepareVolume(testCtx, testVolume2))
+	mockPH.AssertExpectations(t)
+	mockFS.AssertExpectations(t)
+}
+
 func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	var (
 		dp, mockLsblk, mockPH, mockFS = setupTestDriveProvisioner()
@@ -194,9 +337,8 @@ func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	)
 
 	// failed to find DriveCR
-	err = dp.ReleaseVolume(api.Volume{})
-	assert.Error(t, err)
-	assert.EqualError(t, err, "unable to find drive by vol location")
+	err = dp.ReleaseVolume(testCtx, api.Volume{})
+	assert.True(t, errors.Is(err, errTypes.ErrDriveNotFound))
 
 	err = dp.k8sClient.CreateCR(testCtx, testDriveCR.Name, &testDriveCR)
 	assert.Nil(t, err)
@@ -206,7 +348,7 @@ func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 		mock.MatchedBy(func(d *drivecrd.Drive) bool { return d.Name == testDriveCR.Name })).
 		Return("", errTest).Once()
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unable to find device for drive with S/N")
 
@@ -221,7 +363,7 @@ func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	mockLsblk.On("GetBlockDevices", deviceFile).
 		Return(nil, errTest)
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unable to find partition name")
 
@@ -233,7 +375,7 @@ func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	// WipeFS failed
 	mockFS.On("WipeFS", deviceFile+partName).Return(errTest).Once()
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Equal(t, errTest, err)
 
@@ -241,7 +383,7 @@ func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	mockFS.On("WipeFS", mock.Anything).Return(nil).Once()
 	mockPH.On("ReleasePartition", mock.Anything).Return(errTest).Once()
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unable to release partition")
 
@@ -250,11 +392,85 @@ func TestDriveProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	mockPH.On("ReleasePartition", mock.Anything).Return(nil)
 	mockFS.On("WipeFS", deviceFile).Return(errTest)
 
-	err = dp.ReleaseVolume(testVolume2)
+	err = dp.ReleaseVolume(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Equal(t, errTest, err)
 }
 
+func TestDriveProvisioner_ZonedVolume(t *testing.T) {
+	var (
+		dp, mockLsblk, _, mockFS = setupTestDriveProvisioner()
+		mockZoned                = &mocklu.MockWrapZoned{}
+		device                   = "/dev/sda"
+		vol                      = testVolume2
+	)
+	dp.zonedOps = mockZoned
+	vol.StorageClass = apiV1.StorageClassZoned
+	vol.Type = string(fs.F2FS)
+
+	err := dp.k8sClient.CreateCR(testCtx, testDriveCR.Name, &testDriveCR)
+	assert.Nil(t, err)
+	mockLsblk.On("SearchDrivePath",
+		mock.MatchedBy(func(d *drivecrd.Drive) bool { return d.Name == testDriveCR.Name })).
+		Return(device, nil)
+
+	// file system is created on the whole device without partition
+	mockZoned.On("CreateFS", fs.F2FS, device).Return(nil).Once()
+	assert.Nil(t, dp.PrepareVolume(testCtx, vol))
+
+	path, err := dp.GetVolumePath(testCtx, vol)
+	assert.Nil(t, err)
+	assert.Equal(t, device, path)
+
+	// integrity protection isn't supported
+	withIntegrity := vol
+	withIntegrity.Integrity = apiV1.IntegrityDMIntegrity
+	assert.NotNil(t, dp.PrepareVolume(testCtx, withIntegrity))
+
+	// zones are reset on release
+	mockZoned.On("ResetZones", device).Return(errTest).Once()
+	assert.Equal(t, errTest, dp.ReleaseVolume(testCtx, vol))
+
+	mockZoned.On("ResetZones", device).Return(nil).Once()
+	mockFS.On("WipeFS", device).Return(nil).Once()
+	assert.Nil(t, dp.ReleaseVolume(testCtx, vol))
+
+	// zones are reset before file system is recreated
+	mockZoned.On("ResetZones", device).Return(nil).Once()
+	mockFS.On("WipeFS", device).Return(nil).Once()
+	mockZoned.On("CreateFS", fs.F2FS, device).Return(nil).Once()
+	assert.Nil(t, dp.ReformatVolume(testCtx, vol))
+	mockZoned.AssertExpectations(t)
+}
+
+func TestDriveProvisioner_ReformatVolume(t *testing.T) {
+	var (
+		dp, mockLsblk, mockPH, mockFS = setupTestDriveProvisioner()
+		deviceFile                    = "/dev/sda"
+		partName                      = "p1"
+		vol                           = testVolume2
+	)
+	err := dp.k8sClient.CreateCR(testCtx, testDriveCR.Name, &testDriveCR)
+	assert.Nil(t, err)
+	mockLsblk.On("SearchDrivePath",
+		mock.MatchedBy(func(d *drivecrd.Drive) bool { return d.Name == testDriveCR.Name })).
+		Return(deviceFile, nil)
+	mockPH.On("SearchPartName", deviceFile, vol.Id).Return(partName, nil)
+
+	// partition is kept, file system is recreated on it
+	mockFS.On("WipeFS", deviceFile+partName).Return(nil).Once()
+	mockFS.On("CreateFS", fs.FileSystem(vol.Type), deviceFile+partName).Return(nil).Once()
+	assert.Nil(t, dp.ReformatVolume(testCtx, vol))
+
+	mockFS.On("WipeFS", deviceFile+partName).Return(errTest).Once()
+	assert.Equal(t, errTest, dp.ReformatVolume(testCtx, vol))
+	mockFS.AssertNumberOfCalls(t, "CreateFS", 1)
+
+	vol.Mode = apiV1.ModeRAW
+	assert.NotNil(t, dp.ReformatVolume(testCtx, vol))
+	mockPH.AssertNotCalled(t, "ReleasePartition", mock.Anything)
+}
+
 func TestDriveProvisioner_GetVolumePath_Success(t *testing.T) {
 	var (
 		dp, mockLsblk, mockPH, _ = setupTestDriveProvisioner()
@@ -276,7 +492,7 @@ func TestDriveProvisioner_GetVolumePath_Success(t *testing.T) {
 	mockPH.On("SearchPartName", deviceFile, testVolume2.Id).
 		Return(partName, nil).Once()
 
-	fullPath, err = dp.GetVolumePath(testVolume2)
+	fullPath, err = dp.GetVolumePath(testCtx, testVolume2)
 	assert.Nil(t, err)
 	assert.Equal(t, deviceFile+partName, fullPath)
 }
@@ -289,7 +505,7 @@ func TestDriveProvisioner_GetVolumePath_Fail(t *testing.T) {
 	)
 
 	// failed to find DriveCR
-	fullPath, err = dp.GetVolumePath(api.Volume{})
+	fullPath, err = dp.GetVolumePath(testCtx, api.Volume{})
 	assert.Error(t, err)
 	assert.Equal(t, "", fullPath)
 	assert.Contains(t, err.Error(), "unable to find drive by location")
@@ -302,7 +518,7 @@ func TestDriveProvisioner_GetVolumePath_Fail(t *testing.T) {
 		mock.MatchedBy(func(d *drivecrd.Drive) bool { return d.Name == testDriveCR.Name })).
 		Return("", errTest).Once()
 
-	fullPath, err = dp.GetVolumePath(testVolume2)
+	fullPath, err = dp.GetVolumePath(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Equal(t, "", fullPath)
 	assert.Contains(t, err.Error(), "unable to find device for drive with S/N")
@@ -314,8 +530,41 @@ func TestDriveProvisioner_GetVolumePath_Fail(t *testing.T) {
 	mockPH.On("SearchPartName", deviceFile, testVolume2.Id).
 		Return("").Once()
 
-	fullPath, err = dp.GetVolumePath(testVolume2)
+	fullPath, err = dp.GetVolumePath(testCtx, testVolume2)
 	assert.Error(t, err)
 	assert.Equal(t, "", fullPath)
 	assert.Contains(t, err.Error(), "unable to find part name for device")
 }
+
+func TestDriveProvisioner_VolumeExists(t *testing.T) {
+	var (
+		dp, mockLsblk, mockPH, _ = setupTestDriveProvisioner()
+		deviceFile               = "/dev/sda"
+	)
+
+	// drive CR doesn't exist
+	_, err := dp.VolumeExists(testCtx, testVolume2)
+	assert.True(t, errors.Is(err, errTypes.ErrDriveNotFound))
+
+	assert.Nil(t, dp.k8sClient.CreateCR(testCtx, testDriveCR.Name, &testDriveCR))
+	mockLsblk.On("SearchDrivePath", mock.Anything).Return(deviceFile, nil)
+
+	mockPH.On("SearchPartName", deviceFile, testVolume2.Id).Return("p1").Once()
+	exists, err := dp.VolumeExists(testCtx, testVolume2)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	// partition table was wiped
+	mockPH.On("SearchPartName", deviceFile, testVolume2.Id).Return("").Once()
+	exists, err = dp.VolumeExists(testCtx, testVolume2)
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	// raw volume occupies the whole drive
+	rawVolume := testVolume2
+	rawVolume.Mode = apiV1.ModeRAW
+	exists, err = dp.VolumeExists(testCtx, rawVolume)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	mockPH.AssertExpectations(t)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioners
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockqueue"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+)
+
+const (
+	// profileBlockSize is a block size of file systems created with format profile, it matches page size,
+	// so direct I/O aligned by page is aligned by file system blocks
+	profileBlockSize = 4096
+	// hugepageSize is a size of huge page (2MiB) by which hugepage profile aligns file system allocation
+	hugepageSize = 2 << 20
+)
+
+// formatProfileOptions extends mkfs options of the volume with block size and stripe geometry derived
+// from I/O geometry of the device according to format profile, options set explicitly aren't overridden
+// Receives file system type, format profile, device geometry and validated mkfs options of the volume
+// Returns mkfs options
+func formatProfileOptions(fsType fs.FileSystem, profile string, g blockqueue.Geometry, opts []string) []string {
+	var (
+		explicit  = map[string]int{}
+		blockSize = int64(profileBlockSize)
+		unit      int64
+		width     int64
+	)
+	result := append([]string{}, opts...)
+	for i := 0; i+1 < len(opts); i += 2 {
+		explicit[opts[i]] = i + 1
+	}
+
+	// RAID and striped LVM report chunk as minimum I/O size and full stripe as optimal I/O size
+	if g.OptimalIOSize > g.MinimumIOSize && g.MinimumIOSize >= blockSize &&
+		g.MinimumIOSize%blockSize == 0 && g.OptimalIOSize%g.MinimumIOSize == 0 {
+		unit, width = g.MinimumIOSize, g.OptimalIOSize
+	}
+	if profile == apiV1.FormatProfileHugepage {
+		if unit == 0 {
+			unit, width = hugepageSize, hugepageSize
+		} else {
+			// huge page boundaries are kept on stripe boundaries
+			unit = lcm(unit, hugepageSize)
+			width = lcm(width, unit)
+		}
+	}
+
+	switch fsType {
+	case fs.EXT3, fs.EXT4:
+		if i, ok := explicit["-b"]; ok {
+			if blockSize, _ = strconv.ParseInt(opts[i], 10, 64); blockSize <= 0 || unit%blockSize != 0 {
+				unit = 0
+			}
+		} else {
+			result = append(result, "-b", strconv.FormatInt(blockSize, 10))
+		}
+		if unit == 0 {
+			break
+		}
+		stripe := fmt.Sprintf("stride=%d,stripe_width=%d", unit/blockSize, width/blockSize)
+		// mke2fs uses only the last -E option, so stripe geometry is merged with extended options
+		if i, ok := explicit["-E"]; ok {
+			if !strings.Contains(opts[i], "stride") && !strings.Contains(opts[i], "stripe") {
+				result[i] = opts[i] + "," + stripe
+			}
+		} else {
+			defaults := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(fs.SpeedUpFsCreationOpts), "-E"))
+			result = append(result, "-E", defaults+","+stripe)
+		}
+	case fs.XFS:
+		if _, ok := explicit["-b"]; !ok {
+			result = append(result, "-b", fmt.Sprintf("size=%d", blockSize))
+		}
+		if _, ok := explicit["-s"]; !ok && g.PhysicalBlockSize > g.LogicalBlockSize && g.PhysicalBlockSize <= blockSize {
+			result = append(result, "-s", fmt.Sprintf("size=%d", g.PhysicalBlockSize))
+		}
+		// su also matches sunit option
+		if i, ok := explicit["-d"]; unit > 0 && (!ok || !strings.Contains(opts[i], "su")) {
+			result = append(result, "-d", fmt.Sprintf("su=%d,sw=%d", unit, width/unit))
+		}
+	}
+	return result
+}
+
+// lcm returns least common multiple of positive numbers
+func lcm(a, b int64) int64 {
+	x, y := a, b
+	for y != 0 {
+		x, y = y, x%y
+	}
+	return a / x * b
+}
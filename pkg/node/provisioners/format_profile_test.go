@@ -0,0 +1,60 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockqueue"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+)
+
+func TestFormatProfileOptions(t *testing.T) {
+	var (
+		disk = blockqueue.Geometry{LogicalBlockSize: 512, PhysicalBlockSize: 512, MinimumIOSize: 512}
+		// RAID with 512KiB chunk and 3 data disks
+		raid = blockqueue.Geometry{LogicalBlockSize: 512, PhysicalBlockSize: 4096, MinimumIOSize: 524288,
+			OptimalIOSize: 1572864}
+	)
+
+	// device without striping gets only block size
+	assert.Equal(t, []string{"-b", "4096"}, formatProfileOptions(fs.EXT4, apiV1.FormatProfileAligned, disk, nil))
+	assert.Equal(t, []string{"-b", "size=4096"}, formatProfileOptions(fs.XFS, apiV1.FormatProfileAligned, disk, nil))
+
+	// stripe geometry keeps default extended options of mke2fs
+	assert.Equal(t, []string{"-b", "4096", "-E", "lazy_journal_init=1,lazy_itable_init=1,discard,stride=128,stripe_width=384"},
+		formatProfileOptions(fs.EXT4, apiV1.FormatProfileAligned, raid, nil))
+	assert.Equal(t, []string{"-O", "^has_journal", "-E", "discard,stride=128,stripe_width=384", "-b", "4096"},
+		formatProfileOptions(fs.EXT4, apiV1.FormatProfileAligned, raid, []string{"-O", "^has_journal", "-E", "discard"}))
+	assert.Equal(t, []string{"-b", "size=4096", "-s", "size=4096", "-d", "su=524288,sw=3"},
+		formatProfileOptions(fs.XFS, apiV1.FormatProfileAligned, raid, nil))
+
+	// explicit options aren't overridden
+	assert.Equal(t, []string{"-E", "stride=16", "-b", "4096"},
+		formatProfileOptions(fs.EXT4, apiV1.FormatProfileAligned, raid, []string{"-E", "stride=16"}))
+	assert.Equal(t, []string{"-b", "size=1024", "-d", "su=64k,sw=2", "-s", "size=4096"},
+		formatProfileOptions(fs.XFS, apiV1.FormatProfileAligned, raid, []string{"-b", "size=1024", "-d", "su=64k,sw=2"}))
+
+	// allocation is aligned by huge pages
+	assert.Equal(t, []string{"-b", "4096", "-E", "lazy_journal_init=1,lazy_itable_init=1,discard,stride=512,stripe_width=512"},
+		formatProfileOptions(fs.EXT4, apiV1.FormatProfileHugepage, disk, nil))
+	assert.Equal(t, []string{"-b", "size=4096", "-s", "size=4096", "-d", "su=2097152,sw=3"},
+		formatProfileOptions(fs.XFS, apiV1.FormatProfileHugepage, raid, nil))
+}
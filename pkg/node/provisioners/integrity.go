@@ -0,0 +1,121 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioners
+
+import (
+	"fmt"
+	"strings"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockqueue"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/integrity"
+)
+
+// createVolumeFS creates file system of the volume on the device, device is covered by dm-integrity first
+// or file system is created with metadata checksums if volume requires integrity protection,
+// mkfs options of the volume are validated before they are passed to mkfs
+func createVolumeFS(intOps integrity.WrapIntegrity, fsOps fs.WrapFS, queue blockqueue.WrapBlockQueue,
+	vol api.Volume, device string) error {
+	opts, err := mkfsOptions(vol)
+	if err != nil {
+		return err
+	}
+	if vol.Integrity == apiV1.IntegrityDMIntegrity {
+		name := integrity.DeviceName(vol.Id)
+		if err = intOps.Format(device); err != nil {
+			return err
+		}
+		if err = intOps.Open(device, name); err != nil {
+			return err
+		}
+		device = integrity.DevicePath(name)
+	}
+	return makeVolumeFS(intOps, fsOps, queue, vol, device, opts)
+}
+
+// recreateVolumeFS creates file system of the volume on the device which already holds file system of the volume
+// (dm-integrity device for volumes covered by dm-integrity), e.g. device returned by GetVolumePath
+func recreateVolumeFS(intOps integrity.WrapIntegrity, fsOps fs.WrapFS, queue blockqueue.WrapBlockQueue,
+	vol api.Volume, device string) error {
+	opts, err := mkfsOptions(vol)
+	if err != nil {
+		return err
+	}
+	return makeVolumeFS(intOps, fsOps, queue, vol, device, opts)
+}
+
+// makeVolumeFS runs mkfs for the volume on the device, metadata checksums are enabled if volume requires them,
+// block size and stripe geometry are derived from the device if volume has format profile
+func makeVolumeFS(intOps integrity.WrapIntegrity, fsOps fs.WrapFS, queue blockqueue.WrapBlockQueue, vol api.Volume,
+	device string, opts []string) error {
+	if vol.FormatProfile != "" {
+		geometry, err := queue.Geometry(device)
+		if err != nil {
+			return fmt.Errorf("unable to apply format profile %s: %w", vol.FormatProfile, err)
+		}
+		opts = formatProfileOptions(fs.FileSystem(vol.Type), vol.FormatProfile, geometry, opts)
+	}
+	if vol.Integrity == apiV1.IntegrityChecksum {
+		return intOps.CreateChecksumFS(device, opts...)
+	}
+	return fsOps.CreateFS(fs.FileSystem(vol.Type), device, opts...)
+}
+
+// mkfsOptions validates mkfs options of the volume and splits them into arguments
+func mkfsOptions(vol api.Volume) ([]string, error) {
+	if vol.MkFSOptions == "" {
+		return nil, nil
+	}
+	if err := fs.ValidateMkFSOptions(fs.FileSystem(vol.Type), vol.MkFSOptions); err != nil {
+		return nil, err
+	}
+	return strings.Fields(vol.MkFSOptions), nil
+}
+
+// volumeDevicePath returns path of the device which holds file system of the volume,
+// dm-integrity device on top of the provided device is opened if it isn't active (e.g. after node reboot)
+func volumeDevicePath(intOps integrity.WrapIntegrity, vol api.Volume, device string) (string, error) {
+	if vol.Integrity != apiV1.IntegrityDMIntegrity {
+		return device, nil
+	}
+	name := integrity.DeviceName(vol.Id)
+	opened, err := intOps.IsOpened(name)
+	if err != nil {
+		return "", err
+	}
+	if !opened {
+		if err = intOps.Open(device, name); err != nil {
+			return "", err
+		}
+	}
+	return integrity.DevicePath(name), nil
+}
+
+// closeIntegrityDevice deactivates dm-integrity device of the volume if it is active
+func closeIntegrityDevice(intOps integrity.WrapIntegrity, vol api.Volume) error {
+	if vol.Integrity != apiV1.IntegrityDMIntegrity {
+		return nil
+	}
+	name := integrity.DeviceName(vol.Id)
+	opened, err := intOps.IsOpened(name)
+	if err != nil || !opened {
+		return err
+	}
+	return intOps.Close(name)
+}
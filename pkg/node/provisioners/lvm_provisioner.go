@@ -17,42 +17,77 @@ limitations under the License.
 package provisioners
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockqueue"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/integrity"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lvm"
 	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/node/faults"
 )
 
 // LVMProvisioner is a implementation of Provisioner interface
 // Work with volumes based on Volume Groups
 type LVMProvisioner struct {
-	lvmOps   lvm.WrapLVM
-	fsOps    fs.WrapFS
-	crHelper *k8s.CRHelper
-	log      *logrus.Entry
+	lvmOps lvm.WrapLVM
+	fsOps  fs.WrapFS
+	intOps integrity.WrapIntegrity
+	// blockQueue reads I/O geometry of logical volumes for format profiles
+	blockQueue blockqueue.WrapBlockQueue
+	crHelper   *k8s.CRHelper
+	// phases tracks time of LV and FS creation
+	phases *PhaseTracker
+	// audit records format, wipe and removal of logical volumes
+	audit *audit.Logger
+	// faults injects failures for chaos testing
+	faults *faults.Injector
+	log    *logrus.Entry
 }
 
 // NewLVMProvisioner is a constructor for LVMProvisioner
 func NewLVMProvisioner(e command.CmdExecutor, k *k8s.KubeClient, log *logrus.Logger) *LVMProvisioner {
 	return &LVMProvisioner{
-		lvmOps:   lvm.NewLVM(e, log),
-		fsOps:    fs.NewFSImpl(e),
-		crHelper: k8s.NewCRHelper(k, log),
-		log:      log.WithField("component", "LVMProvisioner"),
+		lvmOps:     lvm.NewLVM(e, log),
+		fsOps:      fs.NewFSImpl(e),
+		intOps:     integrity.NewIntegrity(e),
+		blockQueue: blockqueue.NewBlockQueue(e, log),
+		crHelper:   k8s.NewCRHelper(k, log),
+		log:        log.WithField("component", "LVMProvisioner"),
 	}
 }
 
+// SetPhaseTracker sets tracker for provisioning phases of the volumes
+func (l *LVMProvisioner) SetPhaseTracker(t *PhaseTracker) {
+	l.phases = t
+}
+
+// SetAuditLogger sets logger for destructive operations
+func (l *LVMProvisioner) SetAuditLogger(a *audit.Logger) {
+	l.audit = a
+}
+
+// SetFaultInjector sets injector of failures which is used for chaos testing
+func (l *LVMProvisioner) SetFaultInjector(i *faults.Injector) {
+	l.faults = i
+}
+
 // PrepareVolume search volume group based on vol attributes, creates Logical Volume
 // and create file system on it. After that Logical Volume is ready for mount operations
-func (l *LVMProvisioner) PrepareVolume(vol api.Volume) error {
+func (l *LVMProvisioner) PrepareVolume(ctx context.Context, vol api.Volume) error {
 	ll := l.log.WithFields(logrus.Fields{
 		"method":   "PrepareVolume",
 		"volumeID": vol.Id,
@@ -64,47 +99,65 @@ func (l *LVMProvisioner) PrepareVolume(vol api.Volume) error {
 		err    error
 	)
 
-	// prepare size in megabytes for the argument
-	size, _ := util.ToSizeUnit(vol.Size, util.BYTE, util.MBYTE)
-	sizeStr := strconv.FormatInt(size, 10)
-	sizeStr += "m"
+	// size is passed in bytes, it is aligned by controller with extent size, so lvcreate doesn't round it up
+	// and LV has exactly the same size as the one which is accounted in AC
+	sizeStr := strconv.FormatInt(capacityplanner.AlignSizeByPE(vol.Size), 10) + "b"
 
-	vgName, err = l.getVGName(&vol)
+	vgName, err = l.getVGName(ctx, &vol)
 	if err != nil {
 		return err
 	}
 
 	// create lv with name /dev/VG_NAME/vol.Id
 	ll.Infof("Creating LV %s sizeof %s in VG %s", vol.Id, sizeStr, vgName)
+	started := time.Now()
 	if err = l.lvmOps.LVCreate(vol.Id, sizeStr, vgName); err != nil {
-		return fmt.Errorf("unable to create LV: %v", err)
+		return fmt.Errorf("unable to create LV: %w", err)
 	}
+	l.phases.Record(vol.Id, volumecrd.VolumePhasePartitionCreated, started)
 
 	deviceFile := fmt.Sprintf("/dev/%s/%s", vgName, vol.Id)
 	ll.Debugf("Creating FS on %s", deviceFile)
 	if vol.Mode == apiV1.ModeRAW {
 		return nil
 	}
-	return l.fsOps.CreateFS(fs.FileSystem(vol.Type), deviceFile)
+	if err = l.faults.Inject(ctx, faults.CreateFS); err != nil {
+		return err
+	}
+	started = time.Now()
+	err = createVolumeFS(l.intOps, l.fsOps, l.blockQueue, vol, deviceFile)
+	target := l.auditTarget(ctx, vol)
+	l.audit.Record(audit.OperationFormat, target.requestID, deviceFile, target.serial, err)
+	if err != nil {
+		return err
+	}
+	l.phases.Record(vol.Id, volumecrd.VolumePhaseFormatted, started)
+	return nil
 }
 
 // ReleaseVolume search volume group based on vol attributes, remove Logical Volume
 // and wipe file system on it. After that Logical Volume that had consumed by vol is completely removed
-func (l *LVMProvisioner) ReleaseVolume(vol api.Volume) error {
+func (l *LVMProvisioner) ReleaseVolume(ctx context.Context, vol api.Volume) error {
 	ll := logrus.WithFields(logrus.Fields{
 		"method":   "ReleaseVolume",
 		"volumeID": vol.Id,
 	})
 	ll.Infof("Processing for volume %v", vol)
 
-	deviceFile, err := l.GetVolumePath(vol)
+	deviceFile, err := l.getLVPath(ctx, &vol)
 	if err != nil {
-		return fmt.Errorf("unable to determine full path of the volume: %v", err)
+		return fmt.Errorf("unable to determine full path of the volume: %w", err)
+	}
+	if err = closeIntegrityDevice(l.intOps, vol); err != nil {
+		return err
 	}
 
-	if err := l.fsOps.WipeFS(deviceFile); err != nil {
+	target := l.auditTarget(ctx, vol)
+	err = l.fsOps.WipeFS(deviceFile)
+	l.audit.Record(audit.OperationWipe, target.requestID, deviceFile, target.serial, err)
+	if err != nil {
 		// check whether such LV (deviceFile) exist or not
-		vgName, sErr := l.getVGName(&vol)
+		vgName, sErr := l.getVGName(ctx, &vol)
 		if sErr != nil {
 			return fmt.Errorf("unable to remove LV %s: %v and unable to determine VG name: %v",
 				deviceFile, err, sErr)
@@ -118,30 +171,111 @@ func (l *LVMProvisioner) ReleaseVolume(vol api.Volume) error {
 			ll.Infof("LV %s has been already removed", deviceFile)
 			return nil
 		}
-		return fmt.Errorf("failed to wipe FS on device %s: %v", deviceFile, err)
+		return fmt.Errorf("failed to wipe FS on device %s: %w", deviceFile, err)
 	}
 
-	return l.lvmOps.LVRemove(deviceFile)
+	err = l.lvmOps.LVRemove(deviceFile)
+	l.audit.Record(audit.OperationLVRemove, target.requestID, deviceFile, target.serial, err)
+	return err
+}
+
+// ReformatVolume wipes file system on the logical volume and creates file system of the volume type,
+// dm-integrity device of the volume is kept and file system is recreated on top of it.
+// All data of the volume is destroyed, so it should be called only if user has confirmed data erase
+func (l *LVMProvisioner) ReformatVolume(ctx context.Context, vol api.Volume) error {
+	ll := l.log.WithFields(logrus.Fields{
+		"method":   "ReformatVolume",
+		"volumeID": vol.Id,
+	})
+
+	if vol.Mode == apiV1.ModeRAW {
+		return fmt.Errorf("volume in %s mode has no file system", vol.Mode)
+	}
+	device, err := l.GetVolumePath(ctx, vol)
+	if err != nil {
+		return err
+	}
+	ll.Warnf("Recreate %s file system on %s, data of the volume is erased", vol.Type, device)
+
+	target := l.auditTarget(ctx, vol)
+	err = l.fsOps.WipeFS(device)
+	l.audit.Record(audit.OperationWipe, target.requestID, device, target.serial, err)
+	if err != nil {
+		return err
+	}
+	err = recreateVolumeFS(l.intOps, l.fsOps, l.blockQueue, vol, device)
+	l.audit.Record(audit.OperationFormat, target.requestID, device, target.serial, err)
+	return err
 }
 
 // GetVolumePath search Volume Group name by vol attributes and construct
 // full path to the volume using template: /dev/VG_NAME/LV_NAME
-func (l *LVMProvisioner) GetVolumePath(vol api.Volume) (string, error) {
+// path of dm-integrity device on top of LV is returned for volumes with integrity protection
+func (l *LVMProvisioner) GetVolumePath(ctx context.Context, vol api.Volume) (string, error) {
 	ll := l.log.WithFields(logrus.Fields{
 		"method":   "GetVolumePath",
 		"volumeID": vol.Id,
 	})
 	ll.Debugf("Processing for %v", vol)
 
-	vgName, err := l.getVGName(&vol)
+	lvPath, err := l.getLVPath(ctx, &vol)
 	if err != nil {
 		return "", err
 	}
+	return volumeDevicePath(l.intOps, vol, lvPath)
+}
+
+// VolumeExists checks whether logical volume of the volume is found in its volume group
+// Returns error if volume group can't be determined or its logical volumes can't be listed
+func (l *LVMProvisioner) VolumeExists(ctx context.Context, vol api.Volume) (bool, error) {
+	vgName, err := l.getVGName(ctx, &vol)
+	if err != nil {
+		return false, err
+	}
+	lvs, err := l.lvmOps.GetLVsInVG(vgName)
+	if err != nil {
+		return false, fmt.Errorf("unable to list LVs in VG %s: %w", vgName, err)
+	}
+	return util.ContainsString(lvs, vol.Id), nil
+}
 
-	return fmt.Sprintf("/dev/%s/%s", vgName, vol.Id), nil // /dev/VG_NAME/LV_NAME
+// getLVPath returns full path to the logical volume of vol: /dev/VG_NAME/LV_NAME
+func (l *LVMProvisioner) getLVPath(ctx context.Context, vol *api.Volume) (string, error) {
+	vgName, err := l.getVGName(ctx, vol)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/dev/%s/%s", vgName, vol.Id), nil
+}
+
+// auditTarget returns audit target of vol, serial numbers of the drives of the underlying LVG are comma-separated
+// serial is empty if LVG CR can't be read
+func (l *LVMProvisioner) auditTarget(ctx context.Context, vol api.Volume) auditTarget {
+	target := auditTarget{requestID: vol.Id}
+	if l.audit == nil {
+		return target
+	}
+	lvgs, err := l.crHelper.GetLVGCRs(ctx)
+	if err != nil {
+		l.log.Warnf("Unable to read LVG CRs for audit of volume %s: %v", vol.Id, err)
+		return target
+	}
+	serials := make([]string, 0)
+	for _, lvg := range lvgs {
+		if lvg.Name != vol.Location {
+			continue
+		}
+		for _, driveUUID := range lvg.Spec.Locations {
+			if drive := l.crHelper.GetDriveCRByUUID(ctx, driveUUID); drive != nil {
+				serials = append(serials, drive.Spec.SerialNumber)
+			}
+		}
+	}
+	target.serial = strings.Join(serials, ",")
+	return target
 }
 
-func (l *LVMProvisioner) getVGName(vol *api.Volume) (string, error) {
+func (l *LVMProvisioner) getVGName(ctx context.Context, vol *api.Volume) (string, error) {
 	var vgName = vol.Location
 
 	// Volume.Location is an LVG CR name, LVG CR name in general is the same as a real VG name on node,
@@ -149,9 +283,9 @@ func (l *LVMProvisioner) getVGName(vol *api.Volume) (string, error) {
 	// we need to read appropriate LVG CR and use LVG CR.Spec.Name as VG name
 	if vol.StorageClass == apiV1.StorageClassSystemLVG {
 		var err error
-		vgName, err = l.crHelper.GetVGNameByLVGCRName(vol.Location)
+		vgName, err = l.crHelper.GetVGNameByLVGCRName(ctx, vol.Location)
 		if err != nil {
-			return "", fmt.Errorf("unable to determine VG name: %v", err)
+			return "", fmt.Errorf("unable to determine VG name: %w", err)
 		}
 	}
 	return vgName, nil
@@ -18,15 +18,19 @@ package provisioners
 
 import (
 	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockqueue"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/integrity"
 	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
 	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
 )
@@ -54,14 +58,17 @@ func setupTestLVMProvisioner() {
 func TestLVMProvisioner_PrepareVolume_Success(t *testing.T) {
 	setupTestLVMProvisioner()
 
-	lvmOps.On("LVCreate", testVolume1.Id, mock.Anything, testVolume1.Location).
+	// size is aligned with extent size and passed in bytes
+	vol := testVolume1
+	vol.Size = capacityplanner.DefaultPESize + 1
+	lvmOps.On("LVCreate", vol.Id, strconv.FormatInt(2*capacityplanner.DefaultPESize, 10)+"b", vol.Location).
 		Return(nil).Times(1)
 
-	devFile := fmt.Sprintf("/dev/%s/%s", testVolume1.Location, testVolume1.Id)
-	fsOps.On("CreateFS", fs.FileSystem(testVolume1.Type), devFile).
+	devFile := fmt.Sprintf("/dev/%s/%s", vol.Location, vol.Id)
+	fsOps.On("CreateFS", fs.FileSystem(vol.Type), devFile).
 		Return(nil).Times(1)
 
-	err := lp.PrepareVolume(testVolume1)
+	err := lp.PrepareVolume(testCtx, vol)
 	assert.Nil(t, err)
 }
 
@@ -74,7 +81,7 @@ func TestLVMProvisioner_PrepareVolume_Fail(t *testing.T) {
 	// in that case vgName will be searching in CRs and here we get error
 	vol.StorageClass = apiV1.StorageClassSystemLVG
 
-	err = lp.PrepareVolume(vol)
+	err = lp.PrepareVolume(testCtx, vol)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to determine VG name")
 
@@ -82,7 +89,7 @@ func TestLVMProvisioner_PrepareVolume_Fail(t *testing.T) {
 	lvmOps.On("LVCreate", testVolume1.Id, mock.Anything, testVolume1.Location).
 		Return(errTest).Times(1)
 
-	err = lp.PrepareVolume(testVolume1)
+	err = lp.PrepareVolume(testCtx, testVolume1)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to create LV")
 
@@ -94,7 +101,7 @@ func TestLVMProvisioner_PrepareVolume_Fail(t *testing.T) {
 	fsOps.On("CreateFS", fs.FileSystem(testVolume1.Type), devFile).
 		Return(errTest).Times(1)
 
-	err = lp.PrepareVolume(testVolume1)
+	err = lp.PrepareVolume(testCtx, testVolume1)
 	assert.NotNil(t, err)
 	assert.Equal(t, errTest, err)
 }
@@ -110,14 +117,14 @@ func TestLVMProvisioner_ReleaseVolume_Success(t *testing.T) {
 	fsOps.On("WipeFS", devFile).Return(nil).Times(1)
 	lvmOps.On("LVRemove", devFile).Return(nil).Times(1)
 
-	err = lp.ReleaseVolume(testVolume1)
+	err = lp.ReleaseVolume(testCtx, testVolume1)
 	assert.Nil(t, err)
 
 	// WipeFS failed, LV isn't exist - ReleaseVolume success
 	fsOps.On("WipeFS", devFile).Return(errTest).Times(1)
 	lvmOps.On("GetLVsInVG", testVolume1.Location).Return(nil, nil).Times(1)
 
-	err = lp.ReleaseVolume(testVolume1)
+	err = lp.ReleaseVolume(testCtx, testVolume1)
 	assert.Nil(t, err)
 }
 
@@ -131,7 +138,7 @@ func TestLVMProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	// in that case vgName will be searching in CRs and here we get error
 	vol.StorageClass = apiV1.StorageClassSystemLVG
 
-	err = lp.PrepareVolume(vol)
+	err = lp.PrepareVolume(testCtx, vol)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to determine VG name")
 
@@ -140,7 +147,7 @@ func TestLVMProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	fsOps.On("WipeFS", devFile).Return(errTest).Times(1)
 	lvmOps.On("GetLVsInVG", testVolume1.Location).Return([]string{testVolume1.Id}, nil).Times(1)
 
-	err = lp.ReleaseVolume(testVolume1)
+	err = lp.ReleaseVolume(testCtx, testVolume1)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "failed to wipe FS")
 
@@ -148,7 +155,7 @@ func TestLVMProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	fsOps.On("WipeFS", devFile).Return(errTest).Times(1)
 	lvmOps.On("GetLVsInVG", testVolume1.Location).Return(nil, errTest).Times(1)
 
-	err = lp.ReleaseVolume(testVolume1)
+	err = lp.ReleaseVolume(testCtx, testVolume1)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to remove LV")
 	assert.Contains(t, err.Error(), "and unable to list LVs in VG")
@@ -159,7 +166,7 @@ func TestLVMProvisioner_ReleaseVolume_Fail(t *testing.T) {
 	lvmOps.On("GetLVsInVG", testVolume1.Location).
 		Return([]string{testVolume1.Id}, nil).Times(1)
 
-	err = lp.ReleaseVolume(testVolume1)
+	err = lp.ReleaseVolume(testCtx, testVolume1)
 	assert.NotNil(t, err)
 	assert.Equal(t, errTest, err)
 }
@@ -168,16 +175,167 @@ func TestLVMProvisioner_GetVolumePath_Success(t *testing.T) {
 	setupTestLVMProvisioner()
 
 	expectedPath := fmt.Sprintf("/dev/%s/%s", testVolume1.Location, testVolume1.Id)
-	currentPath, err := lp.GetVolumePath(testVolume1)
+	currentPath, err := lp.GetVolumePath(testCtx, testVolume1)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedPath, currentPath)
 }
 
+func TestLVMProvisioner_VolumeExists(t *testing.T) {
+	setupTestLVMProvisioner()
+
+	lvmOps.On("GetLVsInVG", testVolume1.Location).Return([]string{testVolume1.Id}, nil).Once()
+	exists, err := lp.VolumeExists(testCtx, testVolume1)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	lvmOps.On("GetLVsInVG", testVolume1.Location).Return([]string{}, nil).Once()
+	exists, err = lp.VolumeExists(testCtx, testVolume1)
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	lvmOps.On("GetLVsInVG", testVolume1.Location).Return(nil, errTest).Once()
+	_, err = lp.VolumeExists(testCtx, testVolume1)
+	assert.NotNil(t, err)
+}
+
 func TestLVMProvisioner_getVGName_Success(t *testing.T) {
 	setupTestLVMProvisioner()
 
 	// not a system drive (SSDLVG)
-	vgName, err := lp.getVGName(&testVolume1)
+	vgName, err := lp.getVGName(testCtx, &testVolume1)
 	assert.Nil(t, err)
 	assert.Equal(t, testVolume1.Location, vgName)
 }
+
+func TestLVMProvisioner_Integrity(t *testing.T) {
+	setupTestLVMProvisioner()
+	intOps := &mocklu.MockWrapIntegrity{}
+	lp.intOps = intOps
+
+	var (
+		vol        = testVolume1
+		devFile    = fmt.Sprintf("/dev/%s/%s", testVolume1.Location, testVolume1.Id)
+		mapperName = integrity.DeviceName(testVolume1.Id)
+		mapperPath = integrity.DevicePath(mapperName)
+	)
+	vol.Integrity = apiV1.IntegrityDMIntegrity
+
+	// file system is created on top of dm-integrity device
+	lvmOps.On("LVCreate", vol.Id, mock.Anything, vol.Location).Return(nil).Times(1)
+	intOps.On("Format", devFile).Return(nil).Times(1)
+	intOps.On("Open", devFile, mapperName).Return(nil).Times(1)
+	fsOps.On("CreateFS", fs.FileSystem(vol.Type), mapperPath).Return(nil).Times(1)
+	assert.Nil(t, lp.PrepareVolume(testCtx, vol))
+
+	// integrity device is opened if it isn't active
+	intOps.On("IsOpened", mapperName).Return(false, nil).Times(1)
+	intOps.On("Open", devFile, mapperName).Return(nil).Times(1)
+	path, err := lp.GetVolumePath(testCtx, vol)
+	assert.Nil(t, err)
+	assert.Equal(t, mapperPath, path)
+
+	intOps.On("IsOpened", mapperName).Return(false, errTest).Times(1)
+	_, err = lp.GetVolumePath(testCtx, vol)
+	assert.NotNil(t, err)
+
+	// integrity device is closed before LV removal
+	intOps.On("IsOpened", mapperName).Return(true, nil).Times(1)
+	intOps.On("Close", mapperName).Return(nil).Times(1)
+	fsOps.On("WipeFS", devFile).Return(nil).Times(1)
+	lvmOps.On("LVRemove", devFile).Return(nil).Times(1)
+	assert.Nil(t, lp.ReleaseVolume(testCtx, vol))
+
+	intOps.On("IsOpened", mapperName).Return(true, nil).Times(1)
+	intOps.On("Close", mapperName).Return(errTest).Times(1)
+	assert.Equal(t, errTest, lp.ReleaseVolume(testCtx, vol))
+
+	// file system with metadata checksums is created directly on LV
+	vol.Integrity = apiV1.IntegrityChecksum
+	lvmOps.On("LVCreate", vol.Id, mock.Anything, vol.Location).Return(nil).Times(1)
+	intOps.On("CreateChecksumFS", devFile).Return(nil).Times(1)
+	assert.Nil(t, lp.PrepareVolume(testCtx, vol))
+	path, err = lp.GetVolumePath(testCtx, vol)
+	assert.Nil(t, err)
+	assert.Equal(t, devFile, path)
+
+	intOps.AssertExpectations(t)
+}
+
+func TestLVMProvisioner_ReformatVolume(t *testing.T) {
+	setupTestLVMProvisioner()
+	intOps := &mocklu.MockWrapIntegrity{}
+	lp.intOps = intOps
+
+	var (
+		vol        = testVolume1
+		devFile    = fmt.Sprintf("/dev/%s/%s", testVolume1.Location, testVolume1.Id)
+		mapperName = integrity.DeviceName(testVolume1.Id)
+		mapperPath = integrity.DevicePath(mapperName)
+	)
+
+	fsOps.On("WipeFS", devFile).Return(nil).Once()
+	fsOps.On("CreateFS", fs.FileSystem(vol.Type), devFile).Return(nil).Once()
+	assert.Nil(t, lp.ReformatVolume(testCtx, vol))
+
+	fsOps.On("WipeFS", devFile).Return(errTest).Once()
+	assert.Equal(t, errTest, lp.ReformatVolume(testCtx, vol))
+
+	// dm-integrity device isn't formatted again, file system is recreated on top of it
+	vol.Integrity = apiV1.IntegrityDMIntegrity
+	intOps.On("IsOpened", mapperName).Return(true, nil).Once()
+	fsOps.On("WipeFS", mapperPath).Return(nil).Once()
+	fsOps.On("CreateFS", fs.FileSystem(vol.Type), mapperPath).Return(nil).Once()
+	assert.Nil(t, lp.ReformatVolume(testCtx, vol))
+
+	vol.Integrity = apiV1.IntegrityChecksum
+	fsOps.On("WipeFS", devFile).Return(nil).Once()
+	intOps.On("CreateChecksumFS", devFile).Return(nil).Once()
+	assert.Nil(t, lp.ReformatVolume(testCtx, vol))
+
+	intOps.AssertExpectations(t)
+	intOps.AssertNotCalled(t, "Format", mock.Anything)
+	lvmOps.AssertNotCalled(t, "LVRemove", mock.Anything)
+}
+
+func TestLVMProvisioner_MkFSOptions(t *testing.T) {
+	setupTestLVMProvisioner()
+
+	var (
+		vol     = testVolume1
+		devFile = fmt.Sprintf("/dev/%s/%s", testVolume1.Location, testVolume1.Id)
+	)
+	vol.MkFSOptions = "-m reflink=1 -i size=512"
+
+	lvmOps.On("LVCreate", vol.Id, mock.Anything, vol.Location).Return(nil)
+	fsOps.On("CreateFS", fs.XFS, devFile, []string{"-m", "reflink=1", "-i", "size=512"}).Return(nil).Times(1)
+	assert.Nil(t, lp.PrepareVolume(testCtx, vol))
+
+	// options are validated before mkfs
+	vol.MkFSOptions = "-f -m reflink=1"
+	assert.NotNil(t, lp.PrepareVolume(testCtx, vol))
+	fsOps.AssertNumberOfCalls(t, "CreateFS", 1)
+}
+
+func TestLVMProvisioner_FormatProfile(t *testing.T) {
+	setupTestLVMProvisioner()
+	queue := &mocklu.MockWrapBlockQueue{}
+	lp.blockQueue = queue
+
+	var (
+		vol     = testVolume1
+		devFile = fmt.Sprintf("/dev/%s/%s", testVolume1.Location, testVolume1.Id)
+	)
+	vol.FormatProfile = apiV1.FormatProfileAligned
+
+	// LV striped over 4 PVs with 64KiB stripe size
+	queue.On("Geometry", devFile).Return(blockqueue.Geometry{LogicalBlockSize: 512, PhysicalBlockSize: 4096,
+		MinimumIOSize: 65536, OptimalIOSize: 262144}, nil).Once()
+	lvmOps.On("LVCreate", vol.Id, mock.Anything, vol.Location).Return(nil)
+	fsOps.On("CreateFS", fs.XFS, devFile, []string{"-b", "size=4096", "-s", "size=4096", "-d", "su=65536,sw=4"}).
+		Return(nil).Once()
+	assert.Nil(t, lp.PrepareVolume(testCtx, vol))
+
+	queue.On("Geometry", devFile).Return(blockqueue.Geometry{}, errTest).Once()
+	assert.NotNil(t, lp.PrepareVolume(testCtx, vol))
+	fsOps.AssertNumberOfCalls(t, "CreateFS", 1)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioners
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/metrics/common"
+)
+
+// PhaseTracker holds time when provisioning phases of the volumes were finished on the node
+// until they are stored in Volume CR
+type PhaseTracker struct {
+	sync.Mutex
+	phases map[string]map[volumecrd.VolumePhase]metav1.Time
+}
+
+// NewPhaseTracker is a constructor for PhaseTracker
+func NewPhaseTracker() *PhaseTracker {
+	return &PhaseTracker{phases: make(map[string]map[volumecrd.VolumePhase]metav1.Time)}
+}
+
+// Record observes duration of the phase which was started at provided time and saves time when phase was finished
+// Duration is observed even if tracker isn't set
+func (t *PhaseTracker) Record(volumeID string, phase volumecrd.VolumePhase, started time.Time) {
+	common.VolumePhaseDuration.OperationsDuration.With(prometheus.Labels{"phase": string(phase)}).
+		Observe(time.Since(started).Seconds())
+	if t == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	if t.phases[volumeID] == nil {
+		t.phases[volumeID] = make(map[volumecrd.VolumePhase]metav1.Time)
+	}
+	t.phases[volumeID][phase] = metav1.Now()
+}
+
+// Pop returns phases which were recorded for the volume and forgets them
+func (t *PhaseTracker) Pop(volumeID string) map[volumecrd.VolumePhase]metav1.Time {
+	if t == nil {
+		return nil
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	phases := t.phases[volumeID]
+	delete(t.phases, volumeID)
+	return phases
+}
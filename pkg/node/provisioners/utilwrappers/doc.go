@@ -18,7 +18,9 @@ limitations under the License.
 Package utilwrappers consists of code that manipulates by os utils and use code from linuxutils for that
 
 Interfaces descriptions:
-1. FSOperations works with file system and holds compound methods for interacting with it
+1. FSOperations works with file system and holds compound methods for interacting with it.
+On Windows nodes it is backed by winutils/fs (NTFS, mklink based staging) which runs cmdlets itself instead of
+CSI Proxy, so node has to run as HostProcess container. Partitioning, LVM and drive discovery are still Linux only
 2. PartitionOperations works with partition on the system and holds compound methods for interacting with it
 */
 package utilwrappers
@@ -19,18 +19,21 @@ package utilwrappers
 import (
 	"fmt"
 	"os"
+	"runtime"
 
 	"github.com/sirupsen/logrus"
+	"k8s.io/mount-utils"
 
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	winfs "github.com/dell/csi-baremetal/pkg/base/winutils/fs"
 )
 
 // FSOperations is holds idempotent methods that consists of WrapFS methods
 type FSOperations interface {
 	// PrepareAndPerformMount composite methods which is prepare source and destination directories
-	// and performs mount operation from src to dst
-	PrepareAndPerformMount(src, dst string, bindMount, dstIsDir bool) error
+	// and performs mount operation from src to dst with additional mount options
+	PrepareAndPerformMount(src, dst string, bindMount, dstIsDir bool, opts ...string) error
 	// UnmountWithCheck unmount operation
 	UnmountWithCheck(path string) error
 	fs.WrapFS
@@ -43,18 +46,25 @@ type FSOperationsImpl struct {
 }
 
 // NewFSOperationsImpl constructor for FSOperationsImpl and returns pointer on it
+// WrapFS implementation is chosen according to the OS of the node
 func NewFSOperationsImpl(e command.CmdExecutor, log *logrus.Logger) *FSOperationsImpl {
+	var wrapFS fs.WrapFS = fs.NewFSImpl(e)
+	if runtime.GOOS == "windows" {
+		wrapFS = winfs.NewFSImpl(e)
+	}
 	return &FSOperationsImpl{
-		WrapFS: fs.NewFSImpl(e),
+		WrapFS: wrapFS,
 		log:    log.WithField("component", "FSOperations"),
 	}
 }
 
 // PrepareAndPerformMount (idempotent) implementation of FSOperations method
 // create (if isn't exist) dst folder on node and perform mount from src to dst
-// if bindMount set to true - mount operation will contain "--bind" option
-// if error occurs and dst has created during current method call then dst will be removed
-func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount, dstIsDir bool) error {
+// if bindMount set to true - mount operation will contain "--bind" option and result of the bind is verified
+// opts are passed to mount operation as is, e.g. fs.DAXOption
+// corrupted mount point which is left from the previous mount is unmounted before mount
+// if error occurs, partially mounted dst is unmounted and dst is removed if it has created during current method call
+func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount, dstIsDir bool, opts ...string) error {
 	ll := fsOp.log.WithFields(logrus.Fields{
 		"method": "PrepareAndPerformMount",
 	})
@@ -63,6 +73,13 @@ func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount,
 	// check whether dst path exist or no, if yes - assume that it is not a first provision for volume
 	wasCreated := false
 	_, err := os.Stat(dst)
+	if mount.IsCorruptedMnt(err) {
+		// e.g. "transport endpoint is not connected" if underlying device was removed
+		ll.Warnf("%s is a corrupted mount point: %v. Unmounting it.", dst, err)
+		if err = fsOp.Unmount(dst); err != nil {
+			return fmt.Errorf("unable to unmount corrupted mount point %s: %w", dst, err)
+		}
+	}
 	if err != nil {
 		if !os.IsNotExist(err) {
 			return err
@@ -82,7 +99,7 @@ func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount,
 		alreadyMounted, err := fsOp.IsMounted(dst)
 		if err != nil {
 			_ = fsOp.RmDir(dst)
-			return fmt.Errorf("unable to determine whether %s is a mountpoint or no: %v", dst, err)
+			return fmt.Errorf("unable to determine whether %s is a mountpoint or no: %w", dst, err)
 		}
 		if alreadyMounted {
 			ll.Infof("%s has already mounted to %s", src, dst)
@@ -90,25 +107,65 @@ func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount,
 		}
 	}
 
-	var opts string
+	var bindOpt string
 	if bindMount {
-		opts = fs.BindOption
+		bindOpt = fs.BindOption
 	}
-	if err := fsOp.Mount(src, dst, opts); err != nil {
-		if wasCreated {
-			_ = fsOp.RmDir(dst)
+	if err := fsOp.Mount(src, dst, append([]string{bindOpt}, opts...)...); err != nil {
+		fsOp.cleanupMountPoint(dst, wasCreated)
+		return fmt.Errorf("unable to mount %s to %s: %w", src, dst, err)
+	}
+	if bindMount {
+		if err := verifyBindMount(src, dst); err != nil {
+			fsOp.cleanupMountPoint(dst, wasCreated)
+			return err
 		}
-		return fmt.Errorf("unable to mount %s to %s: %v", src, dst, err)
 	}
 	return nil
 }
 
+// verifyBindMount checks that dst refers to the same file as src after bind mount,
+// it fails if bind was mounted in another mount namespace and wasn't propagated into the current one
+func verifyBindMount(src, dst string) error {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("unable to verify bind mount of %s: %w", src, err)
+	}
+	dstInfo, err := os.Stat(dst)
+	if err != nil {
+		return fmt.Errorf("unable to verify bind mount to %s: %w", dst, err)
+	}
+	if !os.SameFile(srcInfo, dstInfo) {
+		return fmt.Errorf("%s isn't bound to %s, check mount propagation of the node container", src, dst)
+	}
+	return nil
+}
+
+// cleanupMountPoint unmounts partially mounted dst and removes it if it was created by the caller,
+// errors are only logged because they are secondary to the error of the mount
+func (fsOp *FSOperationsImpl) cleanupMountPoint(dst string, wasCreated bool) {
+	ll := fsOp.log.WithField("method", "cleanupMountPoint")
+	if mounted, err := fsOp.IsMounted(dst); err != nil || mounted {
+		if err = fsOp.Unmount(dst); err != nil {
+			ll.Errorf("Unable to unmount %s: %v", dst, err)
+			return
+		}
+	}
+	if wasCreated {
+		_ = fsOp.RmDir(dst)
+	}
+}
+
 // UnmountWithCheck idempotent implemetation of unmount operation
-// check whether path is mounted and only if yes - try to unmount
+// check whether path is mounted and only if yes - try to unmount, corrupted mount point is always unmounted
 func (fsOp *FSOperationsImpl) UnmountWithCheck(path string) error {
+	if _, err := os.Stat(path); mount.IsCorruptedMnt(err) {
+		fsOp.log.WithField("method", "Unmount").Warnf("%s is a corrupted mount point: %v", path, err)
+		return fsOp.Unmount(path)
+	}
 	isMounted, err := fsOp.IsMounted(path)
 	if err != nil {
-		return fmt.Errorf("unable to check wthether path mounted or no: %v", err)
+		return fmt.Errorf("unable to check wthether path mounted or no: %w", err)
 	}
 	if !isMounted {
 		fsOp.log.WithField("method", "Unmount").Infof("Path %s is not mounted", path)
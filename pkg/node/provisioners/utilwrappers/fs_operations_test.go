@@ -92,6 +92,7 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Fail(t *testing.T) {
 	dst = "/some/not-existed/path"
 	wrapFS.On("MkDir", dst).Return(nil).Once()
 	wrapFS.On("Mount", src, dst, bindOption).Return(expectedErr).Once()
+	wrapFS.On("IsMounted", dst).Return(false, nil).Once()
 	wrapFS.On("RmDir", dst).Return(nil).Once()
 
 	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
@@ -101,13 +102,34 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Fail(t *testing.T) {
 
 	// mount operations failed and dst wasn't created during current call (do not expect RmDir)
 	dst = "/var" // existed path, different from such that used before - /tmp, (for check AssertNotCalled)
-	wrapFS.On("IsMounted", dst).Return(false, nil).Once()
+	wrapFS.On("IsMounted", dst).Return(false, nil).Twice()
 	wrapFS.On("Mount", src, dst, bindOption).Return(expectedErr).Once()
 
 	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
 	assert.Error(t, err)
 	wrapFS.AssertCalled(t, "IsMounted", dst)
 	wrapFS.AssertNotCalled(t, "RmDir", dst)
+
+	// mount operation failed but left dst mounted (expect Unmount)
+	wrapFS.On("IsMounted", dst).Return(false, nil).Once()
+	wrapFS.On("Mount", src, dst, bindOption).Return(expectedErr).Once()
+	wrapFS.On("IsMounted", dst).Return(true, nil).Once()
+	wrapFS.On("Unmount", dst).Return(nil).Once()
+
+	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
+	assert.Error(t, err)
+	wrapFS.AssertCalled(t, "Unmount", dst)
+
+	// bind mount succeeded, but dst doesn't refer to src (bind wasn't propagated)
+	wrapFS.On("IsMounted", dst).Return(false, nil).Once()
+	wrapFS.On("Mount", src, dst, []string{fs.BindOption}).Return(nil).Once()
+	wrapFS.On("IsMounted", dst).Return(true, nil).Once()
+	wrapFS.On("Unmount", dst).Return(nil).Once()
+
+	err = fsOps.PrepareAndPerformMount(src, dst, true, true)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "isn't bound")
+	wrapFS.AssertExpectations(t)
 }
 
 func TestFSOperationsImpl_MountWithCheck_Success(t *testing.T) {
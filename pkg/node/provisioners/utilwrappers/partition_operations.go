@@ -100,7 +100,7 @@ func (d *PartitionOperationsImpl) PreparePartition(p Partition) (*Partition, err
 
 	exist, err := d.IsPartitionExists(p.Device, p.Num)
 	if err != nil {
-		return nil, fmt.Errorf("unable to determine partition existence: %v", err)
+		return nil, fmt.Errorf("unable to determine partition existence: %w", err)
 	}
 
 	if exist { // check partition UUID
@@ -117,27 +117,27 @@ func (d *PartitionOperationsImpl) PreparePartition(p Partition) (*Partition, err
 
 	// create partition table
 	if err = d.CreatePartitionTable(p.Device, p.TableType); err != nil {
-		return nil, fmt.Errorf("unable to create partition table: %v", err)
+		return nil, fmt.Errorf("unable to create partition table: %w", err)
 	}
 
 	// create partition
 	if err = d.CreatePartition(p.Device, p.Label); err != nil {
-		return nil, fmt.Errorf("unable to create partition: %v", err)
+		return nil, fmt.Errorf("unable to create partition: %w", err)
 	}
 	_ = d.SyncPartitionTable(p.Device)
 
 	if p.Ephemeral {
 		p.PartUUID, err = d.GetPartitionUUID(p.Device, p.Num)
 		if err != nil {
-			return nil, fmt.Errorf("unable to get partition UUID for ephemeral volume: %v", err)
+			return nil, fmt.Errorf("unable to get partition UUID for ephemeral volume: %w", err)
 		}
 	} else if err = d.SetPartitionUUID(p.Device, p.Num, p.PartUUID); err != nil {
-		return nil, fmt.Errorf("unable to set partition UUID: %v", err)
+		return nil, fmt.Errorf("unable to set partition UUID: %w", err)
 	}
 
 	p.Name = d.SearchPartName(p.Device, p.PartUUID)
 	if p.Name == "" {
-		return nil, fmt.Errorf("unable to determine partition name after it being created: %v", err)
+		return nil, fmt.Errorf("unable to determine partition name after it being created: %w", err)
 	}
 
 	return &p, nil
@@ -153,7 +153,7 @@ func (d *PartitionOperationsImpl) ReleasePartition(p Partition) error {
 
 	exist, err := d.IsPartitionExists(p.Device, p.Num)
 	if err != nil {
-		return fmt.Errorf("unable to determine partition existence: %v", err)
+		return fmt.Errorf("unable to determine partition existence: %w", err)
 	}
 	if exist {
 		return d.DeletePartition(p.Device, p.Num)
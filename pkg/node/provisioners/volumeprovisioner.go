@@ -20,7 +20,11 @@ limitations under the License.
 // and encapsulates all low-level work with these objects.
 package provisioners
 
-import api "github.com/dell/csi-baremetal/api/generated/v1"
+import (
+	"context"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+)
 
 // VolumeType is used for describing class of volume depending on underlying structures
 // volume could be based on partitions, logical volume and so on
@@ -36,9 +40,19 @@ const (
 // Provisioner is a high-level interface that encapsulates all low-level work with volumes on node
 type Provisioner interface {
 	// Prepare volume for mount
-	PrepareVolume(volume api.Volume) error
+	PrepareVolume(ctx context.Context, volume api.Volume) error
 	// Completely release underlying resources that had consumed by volume
-	ReleaseVolume(volume api.Volume) error
+	ReleaseVolume(ctx context.Context, volume api.Volume) error
 	// Return full path of device file that represent volume on node
-	GetVolumePath(volume api.Volume) (string, error)
+	GetVolumePath(ctx context.Context, volume api.Volume) (string, error)
+	// Recreate file system of the volume, all data of the volume is destroyed
+	ReformatVolume(ctx context.Context, volume api.Volume) error
+	// Check whether partition or logical volume of the volume exists on node
+	VolumeExists(ctx context.Context, volume api.Volume) (bool, error)
+}
+
+// auditTarget identifies request and drive for which destructive operation is performed
+type auditTarget struct {
+	requestID string
+	serial    string
 }
@@ -0,0 +1,248 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
+	linuxfs "github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+)
+
+const (
+	// readCacheDir is a name of directory next to the target path where tmpfs of the read cache is mounted,
+	// it is derived from the target path because NodeUnpublishVolume receives only target path
+	readCacheDir = "readcache"
+	tmpfsType    = "tmpfs"
+	overlayType  = "overlay"
+)
+
+// SetReadCacheLimit sets total size of RAM read caches of the volumes published on the node,
+// publishing of the volume which cache doesn't fit the limit fails, 0 means no limit
+// Receives limit as resource quantity, e.g. "16Gi"
+// Returns error if limit isn't valid
+func (s *CSINodeService) SetReadCacheLimit(limit string) error {
+	quantity, err := resource.ParseQuantity(limit)
+	if err != nil {
+		return fmt.Errorf("invalid read cache limit %s: %w", limit, err)
+	}
+	if quantity.Value() < 0 {
+		return fmt.Errorf("read cache limit should not be negative, got %s", limit)
+	}
+	s.readCacheLimit = quantity.Value()
+	return nil
+}
+
+// readCacheSize returns size of RAM read cache requested by StorageClass, 0 if read cache isn't requested
+func readCacheSize(volumeContext map[string]string) (int64, error) {
+	value, ok := volumeContext[base.ReadCacheKey]
+	if !ok {
+		return 0, nil
+	}
+	size, err := resource.ParseQuantity(value)
+	if err != nil || size.Value() <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid %s value %s", base.ReadCacheKey, value)
+	}
+	return size.Value(), nil
+}
+
+// readCachePath returns directory of read cache of the volume published to the target path
+func readCachePath(targetPath string) string {
+	return filepath.Join(filepath.Dir(targetPath), readCacheDir)
+}
+
+// publishWithReadCache mounts tmpfs of the provided size next to the target path, copies files of the staged volume
+// into it and mounts read-only overlay of the cache over the staged volume to the target path.
+// Files which don't fit the cache are read from the drive. Cache is torn down if publishing fails
+// Receives staging path of the volume, target path and size of the cache in bytes
+// Returns error which wraps ErrCapacityExhausted if cache doesn't fit the read cache limit of the node
+func (s *CSINodeService) publishWithReadCache(srcPath, dstPath string, size int64) error {
+	ll := s.log.WithField("method", "publishWithReadCache")
+
+	// lower layers of overlay shouldn't be changed while it is mounted, so cache isn't refilled
+	if mounted, err := s.fsOps.IsMounted(dstPath); err == nil && mounted {
+		ll.Infof("%s has already been published with read cache", dstPath)
+		return nil
+	}
+
+	cachePath := readCachePath(dstPath)
+	s.readCacheMu.Lock()
+	if s.readCacheLimit > 0 {
+		used, err := readCacheUsage(linuxfs.MountInfoFile)
+		if err != nil {
+			s.readCacheMu.Unlock()
+			return fmt.Errorf("unable to calculate memory used by read caches: %w", err)
+		}
+		if used+size > s.readCacheLimit {
+			s.readCacheMu.Unlock()
+			return fmt.Errorf("%w: read cache of %d bytes exceeds limit of the node, %d of %d bytes are used",
+				errTypes.ErrCapacityExhausted, size, used, s.readCacheLimit)
+		}
+	}
+	err := s.fsOps.PrepareAndPerformMount(tmpfsType, cachePath, false, true,
+		"-t "+tmpfsType, fmt.Sprintf("-o size=%d,mode=0755", size))
+	s.readCacheMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("unable to mount read cache: %w", err)
+	}
+
+	copied, err := fillReadCache(srcPath, cachePath, size)
+	if err != nil {
+		ll.Warnf("Read cache %s is filled partially: %v", cachePath, err)
+	}
+	ll.Infof("%d bytes of %s are copied into read cache", copied, srcPath)
+
+	lowerDirs := fmt.Sprintf("-o ro,lowerdir=%s:%s", cachePath, srcPath)
+	if err = s.fsOps.PrepareAndPerformMount(overlayType, dstPath, false, true, "-t "+overlayType, lowerDirs); err != nil {
+		if cleanupErr := s.teardownReadCache(dstPath); cleanupErr != nil {
+			ll.Errorf("Unable to tear down read cache: %v", cleanupErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// teardownReadCache unmounts and removes read cache of the volume published to the target path,
+// it does nothing if volume was published without cache
+func (s *CSINodeService) teardownReadCache(targetPath string) error {
+	cachePath := readCachePath(targetPath)
+	if _, err := os.Stat(cachePath); os.IsNotExist(err) {
+		return nil
+	}
+	if err := s.fsOps.UnmountWithCheck(cachePath); err != nil {
+		return err
+	}
+	return s.fsOps.RmDir(cachePath)
+}
+
+// fillReadCache copies directories and regular files from src into the cache keeping their permissions, owners and
+// modification time, files which don't fit the size are skipped. Copy isn't interrupted by errors of separate files
+// Returns amount of copied bytes and the last error
+func fillReadCache(src, cache string, size int64) (int64, error) {
+	var (
+		copied  int64
+		lastErr error
+		dirs    []string
+		infos   []os.FileInfo
+	)
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			lastErr = err
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(cache, rel)
+		switch {
+		case info.IsDir():
+			if err = os.MkdirAll(target, 0700); err != nil {
+				// content of the directory can't be cached
+				lastErr = err
+				return filepath.SkipDir
+			}
+			dirs, infos = append(dirs, target), append(infos, info)
+		case info.Mode().IsRegular():
+			if copied+info.Size() > size {
+				return nil
+			}
+			if err = copyFile(path, target); err != nil {
+				_ = os.Remove(target)
+				lastErr = err
+				return nil
+			}
+			copied += info.Size()
+			if err = copyAttributes(target, info); err != nil {
+				lastErr = err
+			}
+		}
+		// symlinks, devices and sockets are served from the drive
+		return nil
+	})
+	if err != nil {
+		return copied, err
+	}
+	// attributes of directories are set once their content is copied, children first
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err = copyAttributes(dirs[i], infos[i]); err != nil {
+			lastErr = err
+		}
+	}
+	return copied, lastErr
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// copyAttributes sets permissions, owner and modification time of the file in the cache as they are on the drive,
+// otherwise overlay would expose attributes of the cache to the pod
+func copyAttributes(path string, info os.FileInfo) error {
+	if err := os.Chmod(path, info.Mode()); err != nil {
+		return err
+	}
+	if uid, gid, ok := fileOwner(info); ok {
+		if err := os.Lchown(path, uid, gid); err != nil {
+			return err
+		}
+	}
+	return os.Chtimes(path, info.ModTime(), info.ModTime())
+}
+
+// readCacheUsage returns total size of read caches mounted on the node
+// Receives path of mountinfo file, e.g. linuxfs.MountInfoFile
+func readCacheUsage(mountInfoFile string) (int64, error) {
+	mountPoints, err := linuxfs.ReadMountPoints(mountInfoFile)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, mp := range mountPoints {
+		if filepath.Base(mp.Path) != readCacheDir {
+			continue
+		}
+		size, err := fsSize(mp.Path)
+		if err != nil {
+			// e.g. directory of the cache was removed
+			continue
+		}
+		total += size
+	}
+	return total, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+)
+
+func TestCSINodeService_SetReadCacheLimit(t *testing.T) {
+	svc := newNodeService()
+	assert.Nil(t, svc.SetReadCacheLimit("16Gi"))
+	assert.Equal(t, int64(16<<30), svc.readCacheLimit)
+
+	assert.NotNil(t, svc.SetReadCacheLimit("16GB"))
+	assert.NotNil(t, svc.SetReadCacheLimit("-1"))
+	assert.Equal(t, int64(16<<30), svc.readCacheLimit)
+}
+
+func TestCSINodeService_NodePublishVolume_ReadCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "readcache")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	var (
+		svc     = newNodeService()
+		fsMock  = &mockProv.MockFsOpts{}
+		staging = filepath.Join(dir, "stage")
+		src     = filepath.Join(staging, stagingFileName)
+		target  = filepath.Join(dir, "pod", "mount")
+		cache   = filepath.Join(dir, "pod", readCacheDir)
+		overlay = fmt.Sprintf("-o ro,lowerdir=%s:%s", cache, src)
+		req     = getNodePublishRequest(testV1ID, target, *testVolumeCap)
+	)
+	svc.fsOps = fsMock
+	req.StagingTargetPath = staging
+	req.VolumeContext[base.ReadCacheKey] = "64"
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(src, "data"), 0750))
+	for name, size := range map[string]int{"model.bin": 10, "data/part": 20, "big": 100} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(src, name), make([]byte, size), 0640))
+	}
+
+	fsMock.On("IsMounted", target).Return(false, nil)
+	fsMock.On("PrepareAndPerformMount", tmpfsType, cache, false, true, "-t tmpfs", "-o size=64,mode=0755").
+		Return(nil)
+	fsMock.On("PrepareAndPerformMount", overlayType, target, false, true, "-t overlay", overlay).
+		Return(nil).Once()
+	_, err = svc.NodePublishVolume(testCtx, req)
+	assert.Nil(t, err)
+
+	// files which fit the cache are copied with their permissions
+	for _, name := range []string{"model.bin", "data/part"} {
+		info, err := os.Stat(filepath.Join(cache, name))
+		assert.Nil(t, err, name)
+		assert.Equal(t, os.FileMode(0640), info.Mode().Perm(), name)
+	}
+	info, err := os.Stat(filepath.Join(cache, "data"))
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0750), info.Mode().Perm())
+	_, err = os.Stat(filepath.Join(cache, "big"))
+	assert.True(t, os.IsNotExist(err))
+
+	// cache is removed on unpublish
+	fsMock.On("UnmountWithCheck", target).Return(nil)
+	fsMock.On("UnmountWithCheck", cache).Return(nil)
+	fsMock.On("RmDir", cache).Return(nil)
+	_, err = svc.NodeUnpublishVolume(testCtx, getNodeUnpublishRequest(testV1ID, target))
+	assert.Nil(t, err)
+	fsMock.AssertCalled(t, "RmDir", cache)
+
+	// cache doesn't fit the limit of the node
+	svc.readCacheLimit = 32
+	_, err = svc.NodePublishVolume(testCtx, req)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// cache is torn down if overlay isn't mounted
+	svc.readCacheLimit = 0
+	fsMock.On("PrepareAndPerformMount", overlayType, target, false, true, "-t overlay", overlay).
+		Return(errors.New("overlay isn't supported")).Once()
+	_, err = svc.NodePublishVolume(testCtx, req)
+	assert.NotNil(t, err)
+	fsMock.AssertNumberOfCalls(t, "RmDir", 2)
+}
+
+func TestReadCacheUsage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "readcache")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	cache := filepath.Join(dir, readCacheDir)
+	assert.Nil(t, os.Mkdir(cache, 0755))
+	size, err := fsSize(cache)
+	assert.Nil(t, err)
+
+	mountInfo := filepath.Join(dir, "mountinfo")
+	assert.Nil(t, ioutil.WriteFile(mountInfo, []byte(
+		"22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw\n"+
+			"1240 22 0:120 / "+cache+" rw,relatime shared:1 - tmpfs tmpfs rw,size=64k\n"+
+			"1241 22 0:121 / "+filepath.Join(dir, "removed", readCacheDir)+" rw,relatime shared:1 - tmpfs tmpfs rw\n"),
+		0644))
+	used, err := readCacheUsage(mountInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, size, used)
+
+	_, err = readCacheUsage(filepath.Join(dir, "missing"))
+	assert.NotNil(t, err)
+}
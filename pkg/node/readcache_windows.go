@@ -0,0 +1,35 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"os"
+)
+
+// fileOwner isn't supported on Windows, files in the cache are owned by the node service
+func fileOwner(info os.FileInfo) (int, int, bool) {
+	return 0, 0, false
+}
+
+// fsSize isn't supported on Windows, read cache is based on tmpfs
+func fsSize(path string) (int64, error) {
+	return 0, errors.New("read cache isn't supported on windows")
+}
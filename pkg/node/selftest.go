@@ -0,0 +1,154 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+)
+
+const (
+	// selfTestDir is the directory of the self-test relative to the kubelet root directory,
+	// it is visible both in the node container and on the host
+	selfTestDir = base.KubeletCSIPluginsDir + "/csi-baremetal-selftest"
+	// selfTestImageSizeMb is size of the file which backs loop device of the self-test
+	selfTestImageSizeMb = 64
+
+	selfTestCreateImageCmdTmpl = "truncate -s %dM %s"
+	selfTestAttachCmdTmpl      = "losetup -f --show %s"
+	selfTestDetachCmdTmpl      = "losetup -d %s"
+	selfTestWriteCmdTmpl       = "dd if=/dev/zero of=%s bs=4096 count=1 conv=fsync"
+)
+
+// SelfTestStep is result of one step of the self-test
+type SelfTestStep struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SelfTestReport is result of the self-test, steps are listed in the order of execution
+type SelfTestReport struct {
+	NodeID string         `json:"nodeID"`
+	Passed bool           `json:"passed"`
+	Steps  []SelfTestStep `json:"steps"`
+}
+
+// RunSelfTest verifies storage path of the node end-to-end: creates loop device backed by a small file, formats,
+// mounts and writes it, after that unmounts, detaches and removes it. Cleanup steps are run even if previous
+// step failed, self-tests are run one at a time
+// Returns report of the self-test
+func (m *VolumeManager) RunSelfTest() *SelfTestReport {
+	m.selfTestMu.Lock()
+	defer m.selfTestMu.Unlock()
+
+	ll := m.log.WithField("method", "RunSelfTest")
+	report := &SelfTestReport{NodeID: m.nodeID, Passed: true}
+	step := func(name string, run func() error) bool {
+		start := time.Now()
+		err := run()
+		result := SelfTestStep{Name: name, Passed: err == nil, Duration: time.Since(start).String()}
+		if err != nil {
+			result.Error = err.Error()
+			report.Passed = false
+			ll.Errorf("Self-test step %s failed: %v", name, err)
+		}
+		report.Steps = append(report.Steps, result)
+		return err == nil
+	}
+
+	var (
+		dir        = filepath.Join(m.kubeletDir, selfTestDir)
+		image      = filepath.Join(dir, "disk.img")
+		mountPoint = filepath.Join(dir, "mnt")
+		device     string
+	)
+	if !step("create", func() error {
+		if err := m.fsOps.MkDir(dir); err != nil {
+			return err
+		}
+		_, _, err := m.executor.RunCmd(fmt.Sprintf(selfTestCreateImageCmdTmpl, selfTestImageSizeMb, image))
+		return err
+	}) {
+		return report
+	}
+	defer step("delete", func() error { return m.fsOps.RmDir(dir) })
+
+	if !step("attach", func() error {
+		stdout, _, err := m.executor.RunCmd(fmt.Sprintf(selfTestAttachCmdTmpl, image))
+		device = strings.TrimSpace(stdout)
+		if err == nil && device == "" {
+			err = fmt.Errorf("losetup didn't return loop device of %s", image)
+		}
+		return err
+	}) {
+		return report
+	}
+	defer step("detach", func() error {
+		_, _, err := m.executor.RunCmd(fmt.Sprintf(selfTestDetachCmdTmpl, device))
+		return err
+	})
+
+	if !step("format", func() error { return m.fsOps.CreateFS(fs.EXT4, device) }) {
+		return report
+	}
+	if !step("mount", func() error { return m.fsOps.PrepareAndPerformMount(device, mountPoint, false, true) }) {
+		return report
+	}
+	defer step("unmount", func() error { return m.fsOps.UnmountWithCheck(mountPoint) })
+
+	step("write", func() error {
+		_, _, err := m.executor.RunCmd(fmt.Sprintf(selfTestWriteCmdTmpl, filepath.Join(mountPoint, "probe")))
+		return err
+	})
+	return report
+}
+
+// SelfTestHandler returns HTTP handler which runs the self-test on POST request and responds with JSON report,
+// status is 200 if the self-test passed and 500 otherwise
+func (m *VolumeManager) SelfTestHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "self-test is run by POST request", http.StatusMethodNotAllowed)
+			return
+		}
+		report := m.RunSelfTest()
+		m.log.WithFields(logrus.Fields{
+			"method": "SelfTestHandler",
+			"passed": report.Passed,
+		}).Info("Self-test is finished")
+
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Passed {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			m.log.Errorf("Unable to write self-test report: %v", err)
+		}
+	})
+}
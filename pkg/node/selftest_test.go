@@ -0,0 +1,105 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+)
+
+func TestVolumeManager_RunSelfTest(t *testing.T) {
+	var (
+		dir        = filepath.Join("/var/lib/kubelet", selfTestDir)
+		image      = filepath.Join(dir, "disk.img")
+		mountPoint = filepath.Join(dir, "mnt")
+		loopDevice = "/dev/loop7"
+	)
+	prepare := func(mountErr error) (*VolumeManager, *mocks.GoMockExecutor, *mockProv.MockFsOpts) {
+		vm := prepareSuccessVolumeManager(t)
+		vm.SetKubeletDir("/var/lib/kubelet")
+		e := &mocks.GoMockExecutor{}
+		fsOps := &mockProv.MockFsOpts{}
+		vm.executor = e
+		vm.fsOps = fsOps
+		fsOps.On("MkDir", dir).Return(nil)
+		fsOps.On("RmDir", dir).Return(nil)
+		fsOps.On("CreateFS", fs.EXT4, loopDevice).Return(nil)
+		fsOps.On("PrepareAndPerformMount", loopDevice, mountPoint, false, true).Return(mountErr)
+		fsOps.On("UnmountWithCheck", mountPoint).Return(nil)
+		e.OnCommand(fmt.Sprintf(selfTestCreateImageCmdTmpl, selfTestImageSizeMb, image)).Return("", "", nil)
+		e.OnCommand(fmt.Sprintf(selfTestAttachCmdTmpl, image)).Return(loopDevice+"\n", "", nil)
+		e.OnCommand(fmt.Sprintf(selfTestDetachCmdTmpl, loopDevice)).Return("", "", nil)
+		e.OnCommand(fmt.Sprintf(selfTestWriteCmdTmpl, filepath.Join(mountPoint, "probe"))).Return("", "", nil)
+		return vm, e, fsOps
+	}
+	stepNames := func(report *SelfTestReport) []string {
+		names := make([]string, 0, len(report.Steps))
+		for _, s := range report.Steps {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+
+	t.Run("Passed", func(t *testing.T) {
+		vm, e, fsOps := prepare(nil)
+		report := vm.RunSelfTest()
+		assert.True(t, report.Passed)
+		assert.Equal(t, nodeID, report.NodeID)
+		assert.Equal(t, []string{"create", "attach", "format", "mount", "write", "unmount", "detach", "delete"},
+			stepNames(report))
+		e.AssertExpectations(t)
+		fsOps.AssertExpectations(t)
+	})
+
+	t.Run("Mount failed", func(t *testing.T) {
+		vm, _, fsOps := prepare(errors.New("mount failed"))
+		report := vm.RunSelfTest()
+		assert.False(t, report.Passed)
+		// loop device and its file are removed
+		assert.Equal(t, []string{"create", "attach", "format", "mount", "detach", "delete"}, stepNames(report))
+		assert.Equal(t, "mount failed", report.Steps[3].Error)
+		fsOps.AssertNotCalled(t, "UnmountWithCheck", mountPoint)
+	})
+
+	t.Run("HTTP", func(t *testing.T) {
+		vm, _, _ := prepare(errors.New("mount failed"))
+		handler := vm.SelfTestHandler()
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/selftest", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/selftest", nil))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		report := &SelfTestReport{}
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), report))
+		assert.False(t, report.Passed)
+		assert.Len(t, report.Steps, 6)
+	})
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+)
+
+// ShutdownHookTimeout is the time in which volumes are flushed and remounted read-only on node shutdown,
+// it fits into termination grace period of node pod (30 seconds)
+const ShutdownHookTimeout = 25 * time.Second
+
+const (
+	// nodeShutdownMessage is a part of the message of Ready condition which kubelet sets on graceful node shutdown,
+	// kubelet holds systemd inhibitor lock until pods are terminated
+	nodeShutdownMessage = "node is shutting down"
+	// syncCmd flushes dirty pages of all file systems
+	syncCmd = "sync"
+	// remountReadOnlyOpts remounts file system of the mount point read-only, kernel writes back dirty data
+	// and marks journal clean, bind mounts of the file system become read-only too
+	remountReadOnlyOpts = "-o remount,ro"
+)
+
+// RemountReadOnlyOnShutdown flushes file systems and remounts staged volumes of the node read-only if kubelet reported
+// graceful shutdown of the node, so journal isn't replayed and fsck isn't needed on the next boot. Volumes are kept
+// mounted as is when node service is stopped for other reasons, e.g. upgrade of the driver
+// Receives golang context and name of k8s node
+// Returns error if node can't be read or some of the volumes were not remounted
+func (s *CSINodeService) RemountReadOnlyOnShutdown(ctx context.Context, nodeName string) error {
+	ll := s.log.WithField("method", "RemountReadOnlyOnShutdown")
+
+	shuttingDown, err := s.isNodeShuttingDown(ctx, nodeName)
+	if err != nil {
+		return fmt.Errorf("unable to check whether node is shutting down: %w", err)
+	}
+	if !shuttingDown {
+		ll.Info("Node isn't shutting down, volumes are kept mounted")
+		return nil
+	}
+
+	ll.Info("Node is shutting down, flush and remount volumes read-only")
+	if _, _, err := s.executor.RunCmd(syncCmd); err != nil {
+		ll.Errorf("Unable to flush file systems: %v", err)
+	}
+	volumes, err := s.crHelper.GetVolumeCRs(ctx, s.nodeID)
+	if err != nil {
+		return fmt.Errorf("unable to read volumes of the node: %w", err)
+	}
+	failed := 0
+	for i := range volumes {
+		volume := &volumes[i]
+		stagingPath, ok := volume.Annotations[apiV1.VolumeAnnotationStagingPath]
+		if !ok || volume.Spec.Mode == apiV1.ModeRAW ||
+			(volume.Spec.CSIStatus != apiV1.VolumeReady && volume.Spec.CSIStatus != apiV1.Published) {
+			continue
+		}
+		if err := s.remountReadOnly(ctx, volume, stagingPath); err != nil {
+			ll.Errorf("Unable to remount volume %s read-only: %v", volume.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d volume(s) were not remounted read-only", failed)
+	}
+	return nil
+}
+
+// isNodeShuttingDown checks whether kubelet reported graceful shutdown in Ready condition of k8s node
+func (s *CSINodeService) isNodeShuttingDown(ctx context.Context, nodeName string) (bool, error) {
+	k8sNode := &corev1.Node{}
+	if err := s.k8sClient.Get(ctx, client.ObjectKey{Name: nodeName}, k8sNode); err != nil {
+		return false, err
+	}
+	for _, condition := range k8sNode.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status != corev1.ConditionTrue &&
+				strings.Contains(condition.Message, nodeShutdownMessage), nil
+		}
+	}
+	return false, nil
+}
+
+// remountReadOnly remounts file system of the volume read-only if its staging path is mounted
+func (s *CSINodeService) remountReadOnly(ctx context.Context, volume *volumecrd.Volume, stagingPath string) error {
+	ll := s.log.WithFields(logrus.Fields{
+		"method":   "remountReadOnly",
+		"volumeID": volume.Name,
+	})
+
+	s.volMu.LockKey(volume.Spec.Id)
+	defer func() {
+		if err := s.volMu.UnlockKey(volume.Spec.Id); err != nil {
+			ll.Warnf("Unlocking volume with error %s", err)
+		}
+	}()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	mounted, err := s.fsOps.IsMounted(stagingPath)
+	if err != nil {
+		return err
+	}
+	if !mounted {
+		return nil
+	}
+	ll.Infof("Remount staging path %s read-only", stagingPath)
+	return s.fsOps.Mount("", stagingPath, remountReadOnlyOpts)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+)
+
+func TestCSINodeService_RemountReadOnlyOnShutdown(t *testing.T) {
+	var (
+		svc          = newNodeService()
+		fsOps        = &mockProv.MockFsOpts{}
+		nodeName     = "node-1"
+		stagingPath1 = "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-1/globalmount"
+		stagingPath2 = "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-2/globalmount"
+		k8sNode      = &corev1.Node{
+			ObjectMeta: k8smetav1.ObjectMeta{Name: nodeName},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue, Message: "kubelet is posting ready status"},
+			}},
+		}
+	)
+	svc.fsOps = fsOps
+	svc.executor = mocks.NewMockExecutor(map[string]mocks.CmdOut{syncCmd: mocks.EmptyOutSuccess})
+
+	// node doesn't exist
+	assert.NotNil(t, svc.RemountReadOnlyOnShutdown(testCtx, nodeName))
+
+	setStaged := func(id, status, stagingPath string) {
+		volume := vcrd.Volume{}
+		assert.Nil(t, svc.k8sClient.ReadCR(testCtx, id, "", &volume))
+		volume.Spec.CSIStatus = status
+		volume.Annotations = map[string]string{apiV1.VolumeAnnotationStagingPath: stagingPath}
+		assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, &volume))
+	}
+	// volume 1 is published, volume 2 is staged, volume 3 isn't staged
+	setStaged(testV1ID, apiV1.Published, stagingPath1)
+	setStaged(testV2ID, apiV1.VolumeReady, stagingPath2)
+
+	// node service is stopped on upgrade, volumes aren't touched
+	assert.Nil(t, svc.k8sClient.Create(testCtx, k8sNode))
+	assert.Nil(t, svc.RemountReadOnlyOnShutdown(testCtx, nodeName))
+	fsOps.AssertNotCalled(t, "IsMounted", stagingPath1)
+
+	// kubelet reported graceful shutdown
+	k8sNode.Status.Conditions[0].Status = corev1.ConditionFalse
+	k8sNode.Status.Conditions[0].Message = "node is shutting down"
+	assert.Nil(t, svc.k8sClient.Update(testCtx, k8sNode))
+
+	fsOps.On("IsMounted", stagingPath1).Return(true, nil).Once()
+	fsOps.On("IsMounted", stagingPath2).Return(false, nil).Once()
+	fsOps.On("Mount", "", stagingPath1, []string{remountReadOnlyOpts}).Return(nil).Once()
+	assert.Nil(t, svc.RemountReadOnlyOnShutdown(testCtx, nodeName))
+	fsOps.AssertExpectations(t)
+
+	// file system is busy
+	fsOps.On("IsMounted", stagingPath1).Return(true, nil).Once()
+	fsOps.On("IsMounted", stagingPath2).Return(false, nil).Once()
+	fsOps.On("Mount", "", stagingPath1, []string{remountReadOnlyOpts}).
+		Return(errors.New("mount point is busy")).Once()
+	assert.NotNil(t, svc.RemountReadOnlyOnShutdown(testCtx, nodeName))
+	fsOps.AssertExpectations(t)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	errTypes "github.com/dell/csi-baremetal/pkg/base/error"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+const (
+	// partitionByUUIDPath is the directory of partition links named by partition UUID, they don't depend on device names
+	partitionByUUIDPath = "/dev/disk/by-partuuid/"
+	// exportedLVRefPrefix marks logical volume in volume refs of LogicalVolumeGroup which was handed back to the OS,
+	// such refs aren't volume IDs, so the volume group is kept like the one on the system drive
+	exportedLVRefPrefix = "exported-"
+)
+
+// handleExportRequest hands the volume with export annotation back to the OS: data and device are kept, volume CR is
+// removed without releasing the device. Export is postponed until the volume is unstaged. Drive of the exported
+// volume gets NOT_CLEAN usage and its AvailableCapacity is removed, so the drive isn't used until operator clears it.
+// Volume ID is replaced by exported logical volume in volume refs of LogicalVolumeGroup, so the volume group isn't
+// removed with the last volume.
+// Device of the exported volume is reported in VolumeExported event
+// Receives golang context and volume CR
+// Returns reconcile result as ctrl.Result or error if something went wrong
+func (m *VolumeManager) handleExportRequest(ctx context.Context, volume *volumecrd.Volume) (ctrl.Result, error) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "handleExportRequest",
+		"volumeID": volume.Name,
+	})
+
+	if volume.Spec.CSIStatus != apiV1.Created {
+		ll.Infof("Export is postponed until volume in %s status is unstaged", volume.Spec.CSIStatus)
+		return ctrl.Result{}, nil
+	}
+	// volume could be staged after it was read by reconcile, status is read again once device is locked
+	if !m.tryLockDevice(volume.Spec.Id) {
+		ll.Info("Volume is being staged, export is requeued")
+		return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, nil
+	}
+	defer m.unlockDevice(volume.Spec.Id)
+	if err := m.k8sClient.ReadCR(ctx, volume.Name, volume.Namespace, volume); err != nil {
+		ll.Errorf("Unable to read volume: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if volume.Spec.CSIStatus != apiV1.Created {
+		ll.Info("Volume was staged meanwhile, export is requeued")
+		return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, nil
+	}
+
+	device, err := m.exportDevice(ctx, &volume.Spec)
+	if err != nil {
+		ll.Errorf("Unable to export volume: %v", err)
+		delete(volume.Annotations, apiV1.VolumeAnnotationExport)
+		volume.Annotations[apiV1.VolumeAnnotationExportStatus] = apiV1.VolumeAnnotationExportFailed
+		if updateErr := m.k8sClient.UpdateCR(ctx, volume); updateErr != nil {
+			ll.Errorf("Unable to record result of export: %v", updateErr)
+			return ctrl.Result{Requeue: true}, updateErr
+		}
+		m.recorder.Eventf(volume, eventing.WarningType, eventing.VolumeExportFailed, "volume wasn't exported: %v", err)
+		return ctrl.Result{}, nil
+	}
+
+	if util.IsStorageClassLVG(volume.Spec.StorageClass) {
+		if err = m.leaveLVG(ctx, &volume.Spec); err != nil {
+			ll.Errorf("Unable to remove volume from LogicalVolumeGroup: %v", err)
+			return ctrl.Result{Requeue: true}, err
+		}
+	} else if err = m.leaveDrive(ctx, volume.Spec.Location); err != nil {
+		ll.Errorf("Unable to exclude drive of the volume from allocation: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	volume.Finalizers = util.RemoveString(volume.Finalizers, volumeFinalizer)
+	if err = m.k8sClient.UpdateCR(ctx, volume); err != nil {
+		ll.Errorf("Unable to remove finalizer: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if err = m.k8sClient.DeleteCR(ctx, volume); err != nil {
+		ll.Errorf("Unable to remove volume: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	ll.Infof("Volume is exported, device %s is kept", device)
+	m.recorder.Eventf(volume, eventing.NormalType, eventing.VolumeExported,
+		"volume is exported, data is kept on device %s", device)
+	return ctrl.Result{}, nil
+}
+
+// exportDevice checks that the volume could be exported and returns stable path of its device:
+// partition path by UUID for the volume on the drive or logical volume path for the volume in LogicalVolumeGroup
+func (m *VolumeManager) exportDevice(ctx context.Context, volume *api.Volume) (string, error) {
+	switch {
+	case volume.Ephemeral:
+		return "", errors.New("ephemeral volume can't be exported")
+	case volume.Integrity != "":
+		// data is accessible only through dm-integrity device which is closed on node reboot
+		return "", errors.New("volume with integrity protection can't be exported")
+	}
+	device, err := m.getProvisionerForVolume(volume).GetVolumePath(ctx, *volume)
+	if err != nil {
+		return "", fmt.Errorf("unable to find device: %w", err)
+	}
+	if util.IsStorageClassLVG(volume.StorageClass) || volume.Mode == apiV1.ModeRAW ||
+		volume.StorageClass == apiV1.StorageClassZoned {
+		return device, nil
+	}
+	partUUID, err := util.GetVolumeUUID(volume.Id)
+	if err != nil {
+		return "", err
+	}
+	return partitionByUUIDPath + partUUID, nil
+}
+
+// leaveDrive changes usage of the drive to NOT_CLEAN and removes its AvailableCapacity,
+// AvailableCapacity is created again when operator clears the drive
+func (m *VolumeManager) leaveDrive(ctx context.Context, driveUUID string) error {
+	drive := &drivecrd.Drive{}
+	if err := m.k8sClient.ReadCR(ctx, driveUUID, "", drive); err != nil {
+		return err
+	}
+	if drive.Spec.Usage == apiV1.DriveUsageInUse {
+		drive.Spec.Usage = apiV1.DriveUsageNotClean
+		drive.RefreshStatus(drivecrd.ReasonUsageChanged, metav1.Now())
+		if err := m.k8sClient.UpdateCR(ctx, drive); err != nil {
+			return err
+		}
+	}
+	ac, err := m.crHelper.GetACByLocation(ctx, driveUUID)
+	switch {
+	case errors.Is(err, errTypes.ErrorNotFound):
+		return nil
+	case err != nil:
+		return err
+	}
+	return m.k8sClient.DeleteCR(ctx, ac)
+}
+
+// leaveLVG replaces volume ID by exported logical volume in volume refs of LogicalVolumeGroup in one update
+func (m *VolumeManager) leaveLVG(ctx context.Context, volume *api.Volume) error {
+	lvg := &lvgcrd.LogicalVolumeGroup{}
+	if err := m.k8sClient.ReadCR(ctx, volume.Location, "", lvg); err != nil {
+		return err
+	}
+	exported := exportedLVRefPrefix + volume.Id
+	refs := util.RemoveString(lvg.Spec.VolumeRefs, volume.Id)
+	if !util.ContainsString(refs, exported) {
+		refs = append(refs, exported)
+	}
+	lvg.Spec.VolumeRefs = refs
+	return m.k8sClient.UpdateCR(ctx, lvg)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
+)
+
+func TestVolumeManager_handleExportRequest(t *testing.T) {
+	var (
+		vm      = prepareSuccessVolumeManager(t)
+		rec     = &mocks.NoOpRecorder{}
+		drive   = testDriveCR
+		ac      = acCR
+		testVol = volCR
+		req     = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNs, Name: testVol.Name}}
+	)
+	vm.recorder = rec
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{
+		p.DriveBasedVolumeType: mockProv.GetMockProvisionerSuccess("/dev/sda1")})
+
+	drive.Spec.Usage = apiV1.DriveUsageInUse
+	ac.Spec.Location = drive.Spec.UUID
+	ac.Spec.Size = 0
+	testVol.Spec.CSIStatus = apiV1.Published
+	testVol.Finalizers = []string{volumeFinalizer}
+	testVol.Annotations = map[string]string{apiV1.VolumeAnnotationExport: "true"}
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, drive.Name, &drive))
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, ac.Name, &ac))
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, testVol.Name, &testVol))
+
+	// export is postponed while volume is published
+	_, err := vm.Reconcile(req)
+	assert.Nil(t, err)
+	volume := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testVol.Name, testNs, volume))
+
+	// volume is unstaged
+	volume.Spec.CSIStatus = apiV1.Created
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, volume))
+	res, err := vm.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.NotNil(t, vm.k8sClient.ReadCR(testCtx, testVol.Name, testNs, &vcrd.Volume{}))
+	assert.NotNil(t, vm.k8sClient.ReadCR(testCtx, ac.Name, "", &accrd.AvailableCapacity{}))
+	updatedDrive := &drivecrd.Drive{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, drive.Name, "", updatedDrive))
+	assert.Equal(t, apiV1.DriveUsageNotClean, updatedDrive.Spec.Usage)
+	last := rec.Calls[len(rec.Calls)-1]
+	assert.Equal(t, eventing.VolumeExported, last.Reason)
+	assert.Equal(t, []interface{}{partitionByUUIDPath + testVol.Spec.Id}, last.Args)
+}
+
+func TestVolumeManager_handleExportRequest_LVG(t *testing.T) {
+	var (
+		vm      = prepareSuccessVolumeManager(t)
+		rec     = &mocks.NoOpRecorder{}
+		device  = "/dev/vg/" + testVolumeLVGCR.Name
+		lvg     = testLVGCR
+		testVol = testVolumeLVGCR
+		req     = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNs, Name: testVol.Name}}
+	)
+	vm.recorder = rec
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{
+		p.LVMBasedVolumeType: mockProv.GetMockProvisionerSuccess(device)})
+
+	lvg.Spec.VolumeRefs = []string{testVol.Spec.Id}
+	testVol.Spec.CSIStatus = apiV1.Created
+	testVol.Spec.Integrity = "crc32c"
+	testVol.Annotations = map[string]string{apiV1.VolumeAnnotationExport: "true"}
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, lvg.Name, &lvg))
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, testVol.Name, &testVol))
+
+	// volume with integrity protection isn't exported
+	_, err := vm.Reconcile(req)
+	assert.Nil(t, err)
+	volume := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testVol.Name, testNs, volume))
+	assert.NotContains(t, volume.Annotations, apiV1.VolumeAnnotationExport)
+	assert.Equal(t, apiV1.VolumeAnnotationExportFailed, volume.Annotations[apiV1.VolumeAnnotationExportStatus])
+	assert.Equal(t, eventing.VolumeExportFailed, rec.Calls[len(rec.Calls)-1].Reason)
+
+	// volume ID is replaced by exported logical volume in LVG
+	volume.Spec.Integrity = ""
+	volume.Annotations[apiV1.VolumeAnnotationExport] = "true"
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, volume))
+	_, err = vm.Reconcile(req)
+	assert.Nil(t, err)
+	assert.NotNil(t, vm.k8sClient.ReadCR(testCtx, testVol.Name, testNs, &vcrd.Volume{}))
+	updatedLVG := &lvgcrd.LogicalVolumeGroup{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, lvg.Name, "", updatedLVG))
+	assert.Equal(t, []string{exportedLVRefPrefix + testVol.Spec.Id}, updatedLVG.Spec.VolumeRefs)
+	assert.False(t, util.HasNameWithPrefix(updatedLVG.Spec.VolumeRefs))
+	assert.Equal(t, []interface{}{device}, rec.Calls[len(rec.Calls)-1].Args)
+}
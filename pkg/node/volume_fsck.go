@@ -0,0 +1,131 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// handleFsckRequest runs file system check requested with fsck annotation of the volume. File system is checked
+// only when it isn't mounted, check of staged volume is postponed until the volume is unstaged, staging of the volume
+// is rejected with retryable error while the check is running.
+// Result is recorded in fsck-status annotation and FilesystemErrors condition, fsck annotation is removed afterwards
+// Receives golang context and volume CR
+// Returns reconcile result as ctrl.Result or error if something went wrong
+func (m *VolumeManager) handleFsckRequest(ctx context.Context, volume *volumecrd.Volume) (ctrl.Result, error) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "handleFsckRequest",
+		"volumeID": volume.Name,
+	})
+
+	mode := volume.Annotations[apiV1.VolumeAnnotationFsck]
+	if mode != apiV1.VolumeAnnotationFsckCheck && mode != apiV1.VolumeAnnotationFsckRepair {
+		return m.finishFsck(ctx, volume, "", fmt.Errorf("unsupported fsck mode %s", mode))
+	}
+	if volume.Spec.Mode != apiV1.ModeFS {
+		return m.finishFsck(ctx, volume, "", fmt.Errorf("volume in %s mode has no file system", volume.Spec.Mode))
+	}
+	if volume.Spec.CSIStatus != apiV1.Created {
+		ll.Infof("File system check is postponed until volume in %s status is unstaged", volume.Spec.CSIStatus)
+		if pending := volume.GetCondition(volumecrd.VolumeConditionFsckPending); pending == nil ||
+			pending.Status != corev1.ConditionTrue {
+			volume.SetFsckPending(true, metav1.Now())
+			if err := m.k8sClient.UpdateStatus(ctx, volume); err != nil {
+				ll.Warnf("Unable to set FsckPending condition: %v", err)
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	release, ok := m.acquireOperationSlot(ctx, volume)
+	if !ok {
+		return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, nil
+	}
+	defer release()
+
+	// volume could be staged after it was read by reconcile, status is read again once device is locked
+	if !m.tryLockDevice(volume.Spec.Id) {
+		ll.Info("Volume is being staged, file system check is requeued")
+		return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, nil
+	}
+	defer m.unlockDevice(volume.Spec.Id)
+	if err := m.k8sClient.ReadCR(ctx, volume.Name, volume.Namespace, volume); err != nil {
+		ll.Errorf("Unable to read volume: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if volume.Spec.CSIStatus != apiV1.Created {
+		ll.Info("Volume was staged meanwhile, file system check is requeued")
+		return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, nil
+	}
+
+	device, err := m.getProvisionerForVolume(&volume.Spec).GetVolumePath(ctx, volume.Spec)
+	if err != nil {
+		return m.finishFsck(ctx, volume, "", fmt.Errorf("unable to determine device of volume: %w", err))
+	}
+	ll.Infof("Run file system check in %s mode on %s", mode, device)
+	result, err := m.fsOps.CheckFS(fs.FileSystem(volume.Spec.Type), device,
+		mode == apiV1.VolumeAnnotationFsckRepair, volume.Annotations[apiV1.VolumeAnnotationFsckOptions])
+	return m.finishFsck(ctx, volume, result, err)
+}
+
+// finishFsck records result of the file system check, removes fsck annotation and sends event
+func (m *VolumeManager) finishFsck(ctx context.Context, volume *volumecrd.Volume,
+	result fs.FSCheckResult, checkErr error) (ctrl.Result, error) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "finishFsck",
+		"volumeID": volume.Name,
+	})
+
+	status, eventType, reason := apiV1.VolumeAnnotationFsckDone, eventing.NormalType, eventing.VolumeFsckCompleted
+	message := fmt.Sprintf("file system check finished, result: %s", result)
+	if checkErr != nil {
+		status, eventType, reason = apiV1.VolumeAnnotationFsckFailed, eventing.WarningType, eventing.VolumeFsckFailed
+		message = fmt.Sprintf("file system check failed: %v", checkErr)
+		ll.Error(message)
+	} else {
+		if result == fs.FSCheckErrorsLeft {
+			eventType = eventing.WarningType
+		}
+		ll.Info(message)
+	}
+
+	delete(volume.Annotations, apiV1.VolumeAnnotationFsck)
+	volume.Annotations[apiV1.VolumeAnnotationFsckStatus] = status
+	if err := m.k8sClient.UpdateCR(ctx, volume); err != nil {
+		ll.Errorf("Unable to record result of file system check: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	// check isn't repeated since annotation is removed, so failure of the condition update is only logged
+	volume.SetFsckResult(result == fs.FSCheckErrorsLeft, message, metav1.Now())
+	if err := m.k8sClient.UpdateStatus(ctx, volume); err != nil {
+		ll.Errorf("Unable to set FilesystemErrors condition: %v", err)
+	}
+	m.recorder.Eventf(volume, eventType, reason, "%s", message)
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
+)
+
+func TestVolumeManager_handleFsckRequest(t *testing.T) {
+	var (
+		vm      = prepareSuccessVolumeManager(t)
+		fsOps   = &mockProv.MockFsOpts{}
+		rec     = &mocks.NoOpRecorder{}
+		device  = "/dev/sda1"
+		testVol = volCR
+		req     = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNs, Name: testVol.Name}}
+	)
+	vm.fsOps = fsOps
+	vm.recorder = rec
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{
+		p.DriveBasedVolumeType: mockProv.GetMockProvisionerSuccess(device)})
+
+	testVol.Spec.CSIStatus = apiV1.Published
+	testVol.Annotations = map[string]string{apiV1.VolumeAnnotationFsck: apiV1.VolumeAnnotationFsckRepair}
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, testVol.Name, &testVol))
+
+	readVolume := func() *vcrd.Volume {
+		volume := &vcrd.Volume{}
+		assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testVol.Name, testNs, volume))
+		return volume
+	}
+
+	// check is postponed while volume is published
+	_, err := vm.Reconcile(req)
+	assert.Nil(t, err)
+	volume := readVolume()
+	assert.Equal(t, corev1.ConditionTrue, volume.GetCondition(vcrd.VolumeConditionFsckPending).Status)
+	assert.Contains(t, volume.Annotations, apiV1.VolumeAnnotationFsck)
+
+	// volume is unstaged, file system is repaired
+	volume.Spec.CSIStatus = apiV1.Created
+	volume.Annotations[apiV1.VolumeAnnotationFsckOptions] = "-D"
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, volume))
+
+	// volume is being staged
+	assert.True(t, vm.tryLockDevice(testVol.Spec.Id))
+	res, err := vm.Reconcile(req)
+	assert.Nil(t, err)
+	assert.NotZero(t, res.RequeueAfter)
+	assert.Contains(t, readVolume().Annotations, apiV1.VolumeAnnotationFsck)
+	vm.unlockDevice(testVol.Spec.Id)
+
+	fsOps.On("CheckFS", fs.FileSystem(testVol.Spec.Type), device, true, "-D").
+		Return(fs.FSCheckRepaired, nil).Times(1)
+	res, err = vm.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	volume = readVolume()
+	assert.NotContains(t, volume.Annotations, apiV1.VolumeAnnotationFsck)
+	assert.Equal(t, apiV1.VolumeAnnotationFsckDone, volume.Annotations[apiV1.VolumeAnnotationFsckStatus])
+	assert.Equal(t, corev1.ConditionFalse, volume.GetCondition(vcrd.VolumeConditionFsckPending).Status)
+	assert.Equal(t, corev1.ConditionFalse, volume.GetCondition(vcrd.VolumeConditionFilesystemErrors).Status)
+	assert.Contains(t, volume.GetCondition(vcrd.VolumeConditionFilesystemErrors).Message, string(fs.FSCheckRepaired))
+	assert.Equal(t, eventing.VolumeFsckCompleted, rec.Calls[len(rec.Calls)-1].Reason)
+
+	// errors are found in check only mode
+	volume.Annotations[apiV1.VolumeAnnotationFsck] = apiV1.VolumeAnnotationFsckCheck
+	delete(volume.Annotations, apiV1.VolumeAnnotationFsckOptions)
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, volume))
+	fsOps.On("CheckFS", fs.FileSystem(testVol.Spec.Type), device, false, "").
+		Return(fs.FSCheckErrorsLeft, nil).Times(1)
+	_, err = vm.Reconcile(req)
+	assert.Nil(t, err)
+	volume = readVolume()
+	assert.Equal(t, corev1.ConditionTrue, volume.GetCondition(vcrd.VolumeConditionFilesystemErrors).Status)
+
+	// check failed
+	volume.Annotations[apiV1.VolumeAnnotationFsck] = apiV1.VolumeAnnotationFsckCheck
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, volume))
+	fsOps.On("CheckFS", fs.FileSystem(testVol.Spec.Type), device, false, "").
+		Return(fs.FSCheckResult(""), errors.New("device is busy")).Times(1)
+	_, err = vm.Reconcile(req)
+	assert.Nil(t, err)
+	volume = readVolume()
+	assert.Equal(t, apiV1.VolumeAnnotationFsckFailed, volume.Annotations[apiV1.VolumeAnnotationFsckStatus])
+	assert.Contains(t, volume.GetCondition(vcrd.VolumeConditionFilesystemErrors).Message, "device is busy")
+	assert.Equal(t, eventing.VolumeFsckFailed, rec.Calls[len(rec.Calls)-1].Reason)
+
+	// unsupported mode
+	volume.Annotations[apiV1.VolumeAnnotationFsck] = "scan"
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, volume))
+	_, err = vm.Reconcile(req)
+	assert.Nil(t, err)
+	volume = readVolume()
+	assert.NotContains(t, volume.Annotations, apiV1.VolumeAnnotationFsck)
+	assert.Equal(t, apiV1.VolumeAnnotationFsckFailed, volume.Annotations[apiV1.VolumeAnnotationFsckStatus])
+
+	fsOps.AssertExpectations(t)
+}
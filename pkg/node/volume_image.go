@@ -0,0 +1,140 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/imagesource"
+)
+
+// imageMountDir is a directory where volumes are mounted for extraction of images, it is placed under kubelet
+// plugins directory to be accessible in the node container and in the host mount namespace
+const imageMountDir = "csi-baremetal-images"
+
+// SetImageSourceAllowlist restricts sources of the volume images, any source is allowed if allowlist is empty
+// Receives comma separated list of image sources in scheme://host format
+// Returns error if allowlist isn't valid
+func (m *VolumeManager) SetImageSourceAllowlist(allowlist string) error {
+	list, err := imagesource.ParseAllowlist(allowlist)
+	if err != nil {
+		return err
+	}
+	m.imageFetcher.SetAllowlist(list)
+	return nil
+}
+
+// populateVolume writes image from volume spec onto the prepared volume. Raw image is written onto block volume,
+// tar archive (optionally gzip compressed) is extracted into file system of the volume
+// Receives golang context and volume spec
+// Returns error if image wasn't fetched, written or doesn't match its checksum
+func (m *VolumeManager) populateVolume(ctx context.Context, vol *api.Volume) error {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "populateVolume",
+		"volumeID": vol.Id,
+	})
+	ll.Infof("Populate volume from image %s", vol.ImageSource)
+
+	ctx, cancel := context.WithTimeout(ctx, base.DefaultTimeoutForVolumeOperations)
+	defer cancel()
+	started := time.Now()
+
+	device, err := m.getProvisionerForVolume(vol).GetVolumePath(ctx, *vol)
+	if err != nil {
+		return fmt.Errorf("unable to determine device of volume: %w", err)
+	}
+	creds, err := m.imageCredentials(ctx, vol)
+	if err != nil {
+		return err
+	}
+	image, err := m.imageFetcher.Open(ctx, vol.ImageSource, creds)
+	if err != nil {
+		return fmt.Errorf("unable to fetch image %s: %w", vol.ImageSource, err)
+	}
+	defer func() { _ = image.Close() }()
+	var content io.Reader = image
+	if vol.ImageChecksum != "" {
+		if content, err = imagesource.NewVerifyingReader(image, vol.ImageChecksum); err != nil {
+			return err
+		}
+	}
+
+	if vol.Mode == apiV1.ModeRAW {
+		err = imagesource.WriteRaw(content, device)
+	} else {
+		err = m.extractImage(content, device, vol.Id)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write image %s: %w", vol.ImageSource, err)
+	}
+	m.phases.Record(vol.Id, volumecrd.VolumePhasePopulated, started)
+	ll.Infof("Volume was populated in %s", time.Since(started))
+	return nil
+}
+
+// imageCredentials reads credentials for private image source from the Secret which is created by controller
+// Returns empty credentials if image source is public or error if the Secret can't be read
+func (m *VolumeManager) imageCredentials(ctx context.Context, vol *api.Volume) (imagesource.Credentials, error) {
+	if vol.ImageSecret == "" {
+		return imagesource.Credentials{}, nil
+	}
+	secret := &corev1.Secret{}
+	if err := m.k8sClient.ReadCR(ctx, vol.ImageSecret, "", secret); err != nil {
+		return imagesource.Credentials{}, fmt.Errorf("unable to read image credentials from Secret %s: %v",
+			vol.ImageSecret, err)
+	}
+	return imagesource.Credentials{
+		Username: string(secret.Data[base.ImageUsernameSecretKey]),
+		Password: string(secret.Data[base.ImagePasswordSecretKey]),
+		Token:    string(secret.Data[base.ImageTokenSecretKey]),
+	}, nil
+}
+
+// extractImage mounts file system of the volume to the temporary directory and extracts image into it
+func (m *VolumeManager) extractImage(image io.Reader, device, volumeID string) error {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "extractImage",
+		"volumeID": volumeID,
+	})
+
+	dir := filepath.Join(m.kubeletDir, base.KubeletCSIPluginsDir, imageMountDir, volumeID)
+	if err := m.fsOps.PrepareAndPerformMount(device, dir, false, true); err != nil {
+		return err
+	}
+	defer func() {
+		if err := m.fsOps.UnmountWithCheck(dir); err != nil {
+			ll.Errorf("Unable to unmount %s: %v", dir, err)
+			return
+		}
+		if err := os.Remove(dir); err != nil {
+			ll.Warnf("Unable to remove %s: %v", dir, err)
+		}
+	}()
+	return imagesource.ExtractTar(image, dir)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/imagesource"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
+)
+
+func TestVolumeManager_prepareVolumeFromRawImage(t *testing.T) {
+	var (
+		content = []byte("raw image content")
+		server  = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(content)
+		}))
+	)
+	defer server.Close()
+	device, err := ioutil.TempFile("", "device")
+	assert.Nil(t, err)
+	defer func() { _ = os.Remove(device.Name()) }()
+	assert.Nil(t, device.Close())
+
+	vm := prepareSuccessVolumeManager(t)
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{
+		p.DriveBasedVolumeType: mockProv.GetMockProvisionerSuccess(device.Name())})
+	testVol := volCR
+	testVol.Spec.Mode = apiV1.ModeRAW
+	testVol.Spec.ImageSource = server.URL + "/image.img"
+	testVol.Spec.ImageChecksum = fmt.Sprintf("%s%x", imagesource.ChecksumSHA256Prefix, sha256.Sum256(content))
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, testVol.Name, &testVol))
+
+	_, err = vm.prepareVolume(testCtx, &testVol)
+	assert.Nil(t, err)
+	written, err := ioutil.ReadFile(device.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, content, written)
+
+	volume := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testVol.Name, testNs, volume))
+	assert.Equal(t, apiV1.Created, volume.Spec.CSIStatus)
+	assert.Contains(t, volume.Status.PhaseTimestamps, vcrd.VolumePhasePopulated)
+
+	// image doesn't match checksum
+	testVol.Spec.ImageChecksum = imagesource.ChecksumSHA256Prefix + fmt.Sprintf("%064d", 0)
+	_, err = vm.prepareVolume(testCtx, &testVol)
+	assert.NotNil(t, err)
+	volume = &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testVol.Name, testNs, volume))
+	assert.Equal(t, apiV1.Failed, volume.Spec.CSIStatus)
+
+	// image source isn't in the allowlist of the node
+	assert.NotNil(t, vm.SetImageSourceAllowlist("images.local"))
+	assert.Nil(t, vm.SetImageSourceAllowlist("https://images.local"))
+	assert.NotNil(t, vm.populateVolume(testCtx, &testVol.Spec))
+}
+
+func TestVolumeManager_populateVolumeFromArchive(t *testing.T) {
+	archive := &bytes.Buffer{}
+	tw := tar.NewWriter(archive)
+	assert.Nil(t, tw.WriteHeader(&tar.Header{Name: "dataset.csv", Typeflag: tar.TypeReg, Mode: 0644, Size: 5}))
+	_, err := tw.Write([]byte("1,2,3"))
+	assert.Nil(t, err)
+	assert.Nil(t, tw.Close())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive.Bytes())
+	}))
+	defer server.Close()
+
+	mountDir, err := ioutil.TempDir("", "images")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(mountDir) }()
+
+	var (
+		vm      = prepareSuccessVolumeManager(t)
+		fsOps   = &mockProv.MockFsOpts{}
+		testVol = volCR
+		dir     = filepath.Join(mountDir, base.KubeletCSIPluginsDir, imageMountDir, testVol.Spec.Id)
+	)
+	vm.SetKubeletDir(mountDir)
+	vm.fsOps = fsOps
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{
+		p.DriveBasedVolumeType: mockProv.GetMockProvisionerSuccess("/dev/sda1")})
+	// mount is imitated by creation of the directory
+	fsOps.On("PrepareAndPerformMount", "/dev/sda1", dir, false, true).
+		Return(nil).Run(func(_ mock.Arguments) { assert.Nil(t, os.MkdirAll(dir, 0755)) })
+	fsOps.On("UnmountWithCheck", dir).Return(nil).Run(func(_ mock.Arguments) {
+		content, err := ioutil.ReadFile(filepath.Join(dir, "dataset.csv"))
+		assert.Nil(t, err)
+		assert.Equal(t, "1,2,3", string(content))
+		// unmount leaves empty mount point
+		assert.Nil(t, os.RemoveAll(dir))
+		assert.Nil(t, os.Mkdir(dir, 0755))
+	})
+	testVol.Spec.ImageSource = server.URL + "/dataset.tar"
+
+	assert.Nil(t, vm.populateVolume(testCtx, &testVol.Spec))
+	fsOps.AssertExpectations(t)
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+
+	// image isn't available
+	testVol.Spec.ImageSource = "http://127.0.0.1:0/dataset.tar"
+	assert.NotNil(t, vm.populateVolume(testCtx, &testVol.Spec))
+}
+
+func TestVolumeManager_populateVolumeWithCredentials(t *testing.T) {
+	var (
+		content = []byte("private image content")
+		server  = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write(content)
+		}))
+	)
+	defer server.Close()
+	device, err := ioutil.TempFile("", "device")
+	assert.Nil(t, err)
+	defer func() { _ = os.Remove(device.Name()) }()
+	assert.Nil(t, device.Close())
+
+	vm := prepareSuccessVolumeManager(t)
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{
+		p.DriveBasedVolumeType: mockProv.GetMockProvisionerSuccess(device.Name())})
+	testVol := volCR
+	testVol.Spec.Mode = apiV1.ModeRAW
+	testVol.Spec.ImageSource = server.URL + "/private.img"
+	testVol.Spec.ImageSecret = base.ImageSecretPrefix + testVol.Spec.Id
+
+	// Secret wasn't created by controller
+	assert.NotNil(t, vm.populateVolume(testCtx, &testVol.Spec))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: testVol.Spec.ImageSecret, Namespace: testNs},
+		Data: map[string][]byte{
+			base.ImageUsernameSecretKey: []byte("user"),
+			base.ImagePasswordSecretKey: []byte("secret"),
+		},
+	}
+	assert.Nil(t, vm.k8sClient.Create(testCtx, secret))
+	assert.Nil(t, vm.populateVolume(testCtx, &testVol.Spec))
+	written, err := ioutil.ReadFile(device.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, content, written)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// importVolume adopts existing partition or logical volume for the volume with import annotation instead of creating
+// it. Device is searched by volume ID in the same way as for created volumes: partition with volume UUID on the drive
+// or logical volume with volume ID name in the volume group. File system type and size of the volume are taken
+// from device if they aren't set. Drive with NOT_CLEAN usage is taken into use since its data belongs to the volume.
+// Volume gets Failed status if device isn't found or it doesn't match the volume, device is never modified
+// Receives golang context and volume CR in Creating status
+// Returns reconcile result as ctrl.Result or error if something went wrong
+func (m *VolumeManager) importVolume(ctx context.Context, volume *volumecrd.Volume) (ctrl.Result, error) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "importVolume",
+		"volumeID": volume.Spec.Id,
+	})
+
+	var (
+		newStatus         = apiV1.Created
+		eventType, reason = eventing.NormalType, eventing.VolumeImported
+		message           string
+	)
+	if err := m.adoptDevice(ctx, &volume.Spec); err != nil {
+		ll.Errorf("Unable to import volume: %v. Set volume status to Failed", err)
+		newStatus = apiV1.Failed
+		eventType, reason = eventing.WarningType, eventing.VolumeImportFailed
+		message = fmt.Sprintf("unable to import existing device: %v", err)
+	} else {
+		message = fmt.Sprintf("existing device was imported as volume of %d bytes", volume.Spec.Size)
+		ll.Info(message)
+	}
+
+	volume.Spec.CSIStatus = newStatus
+	if updateErr := m.k8sClient.UpdateCRWithAttempts(ctx, volume, 5); updateErr != nil {
+		ll.Errorf("Unable to update volume status to %s: %v", newStatus, updateErr)
+		return ctrl.Result{Requeue: true}, updateErr
+	}
+	m.recorder.Eventf(volume, eventType, reason, "%s", message)
+	return ctrl.Result{}, nil
+}
+
+// adoptDevice finds device of the imported volume, fills file system type and size of the volume and
+// takes location of the volume into use
+func (m *VolumeManager) adoptDevice(ctx context.Context, volume *api.Volume) error {
+	isLVG := util.IsStorageClassLVG(volume.StorageClass)
+	if !isLVG {
+		if err := m.checkDriveLocationFree(ctx, volume); err != nil {
+			return err
+		}
+	}
+
+	device, err := m.getProvisionerForVolume(volume).GetVolumePath(ctx, *volume)
+	if err != nil {
+		return fmt.Errorf("unable to find device: %w", err)
+	}
+	bdevs, err := m.listBlk.GetBlockDevices(device)
+	if err != nil {
+		return fmt.Errorf("unable to inspect device %s: %w", device, err)
+	}
+	if len(bdevs) == 0 {
+		return fmt.Errorf("block device %s not found", device)
+	}
+	bdev := bdevs[0]
+
+	if volume.Mode == apiV1.ModeFS {
+		switch {
+		case bdev.FSType == "":
+			return fmt.Errorf("there is no file system on device %s", device)
+		case volume.Type == "":
+			volume.Type = bdev.FSType
+		case volume.Type != bdev.FSType:
+			return fmt.Errorf("device %s has %s file system, but %s is requested", device, bdev.FSType, volume.Type)
+		}
+	}
+	switch {
+	case volume.Size == 0:
+		volume.Size = bdev.Size.Int64
+	// volume on the drive takes the whole drive, so only size of logical volume is checked
+	case isLVG && volume.Size > bdev.Size.Int64:
+		return fmt.Errorf("device %s has %d bytes, but %d bytes are requested", device, bdev.Size.Int64, volume.Size)
+	}
+
+	if isLVG {
+		return m.adoptLVGLocation(ctx, volume)
+	}
+	return m.takeDriveIntoUse(ctx, volume.Location)
+}
+
+// adoptLVGLocation adds imported logical volume to volume refs of LogicalVolumeGroup, ref of the logical volume
+// exported earlier is replaced in the same update. Capacity of the logical volume was already excluded from free space
+// of the volume group, so AvailableCapacity isn't changed
+func (m *VolumeManager) adoptLVGLocation(ctx context.Context, volume *api.Volume) error {
+	lvg := &lvgcrd.LogicalVolumeGroup{}
+	if err := m.k8sClient.ReadCR(ctx, volume.Location, "", lvg); err != nil {
+		return fmt.Errorf("unable to read LogicalVolumeGroup %s: %w", volume.Location, err)
+	}
+	if lvg.Spec.Status != apiV1.Created {
+		return fmt.Errorf("LogicalVolumeGroup %s is in %s status", lvg.Name, lvg.Spec.Status)
+	}
+	exported := exportedLVRefPrefix + volume.Id
+	if util.ContainsString(lvg.Spec.VolumeRefs, volume.Id) && !util.ContainsString(lvg.Spec.VolumeRefs, exported) {
+		return nil
+	}
+	refs := util.RemoveString(lvg.Spec.VolumeRefs, exported)
+	if !util.ContainsString(refs, volume.Id) {
+		refs = append(refs, volume.Id)
+	}
+	lvg.Spec.VolumeRefs = refs
+	return m.k8sClient.UpdateCR(ctx, lvg)
+}
+
+// checkDriveLocationFree checks that drive doesn't hold other volumes, volume CRs with Empty status which were
+// created by discovery for unknown partitions aren't taken into account
+func (m *VolumeManager) checkDriveLocationFree(ctx context.Context, volume *api.Volume) error {
+	volumes, err := m.crHelper.GetVolumesByLocation(ctx, volume.Location)
+	if err != nil {
+		return err
+	}
+	for _, v := range volumes {
+		if v.Spec.Id != volume.Id && v.Spec.CSIStatus != apiV1.Empty {
+			return fmt.Errorf("drive %s already holds volume %s", volume.Location, v.Spec.Id)
+		}
+	}
+	return nil
+}
+
+// takeDriveIntoUse changes usage of NOT_CLEAN drive to IN_USE and sets size of drive AvailableCapacity to 0,
+// otherwise the drive could be selected for another volume until next discovery
+func (m *VolumeManager) takeDriveIntoUse(ctx context.Context, driveUUID string) error {
+	drive := &drivecrd.Drive{}
+	if err := m.k8sClient.ReadCR(ctx, driveUUID, "", drive); err != nil {
+		return fmt.Errorf("unable to read drive %s: %w", driveUUID, err)
+	}
+	if drive.Spec.Usage == apiV1.DriveUsageNotClean {
+		drive.Spec.Usage = apiV1.DriveUsageInUse
+		drive.RefreshStatus(drivecrd.ReasonUsageChanged, metav1.Now())
+		if err := m.k8sClient.UpdateCR(ctx, drive); err != nil {
+			return fmt.Errorf("unable to change usage of drive %s: %w", driveUUID, err)
+		}
+	}
+	if ac, err := m.crHelper.GetACByLocation(ctx, driveUUID); err == nil && ac.Spec.Size != 0 {
+		ac.Spec.Size = 0
+		if err = m.k8sClient.UpdateCR(ctx, ac); err != nil {
+			return fmt.Errorf("unable to update AC %s: %w", ac.Name, err)
+		}
+	}
+	return nil
+}
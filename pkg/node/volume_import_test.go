@@ -0,0 +1,140 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
+)
+
+func TestVolumeManager_importVolume(t *testing.T) {
+	var (
+		vm      = prepareSuccessVolumeManager(t)
+		listBlk = &mocklu.MockWrapLsblk{}
+		rec     = &mocks.NoOpRecorder{}
+		device  = "/dev/sda1"
+		part    = lsblk.BlockDevice{Name: device, FSType: "ext4", Size: lsblk.CustomInt64{Int64: 1024 * 1024 * 1024}}
+		drive   = testDriveCR
+		ac      = acCR
+	)
+	vm.listBlk = listBlk
+	vm.recorder = rec
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{
+		p.DriveBasedVolumeType: mockProv.GetMockProvisionerSuccess(device)})
+	listBlk.On("GetBlockDevices", device).Return([]lsblk.BlockDevice{part}, nil)
+
+	drive.Spec.Usage = apiV1.DriveUsageNotClean
+	ac.Spec.Location = drive.Spec.UUID
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, drive.Name, &drive))
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, ac.Name, &ac))
+
+	importVolume := func(volume vcrd.Volume) *vcrd.Volume {
+		volume.Spec.CSIStatus = apiV1.Creating
+		volume.Spec.Type = ""
+		volume.Spec.Size = 0
+		volume.Annotations = map[string]string{apiV1.VolumeAnnotationImport: "true"}
+		assert.Nil(t, vm.k8sClient.CreateCR(testCtx, volume.Name, &volume))
+		res, err := vm.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNs, Name: volume.Name}})
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+		result := &vcrd.Volume{}
+		assert.Nil(t, vm.k8sClient.ReadCR(testCtx, volume.Name, testNs, result))
+		return result
+	}
+
+	// partition is adopted, drive is taken into use
+	volume := importVolume(volCR)
+	assert.Equal(t, apiV1.Created, volume.Spec.CSIStatus)
+	assert.Equal(t, part.FSType, volume.Spec.Type)
+	assert.Equal(t, part.Size.Int64, volume.Spec.Size)
+	assert.Equal(t, eventing.VolumeImported, rec.Calls[len(rec.Calls)-1].Reason)
+	updatedDrive := &drivecrd.Drive{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, drive.Name, "", updatedDrive))
+	assert.Equal(t, apiV1.DriveUsageInUse, updatedDrive.Spec.Usage)
+	updatedAC := &accrd.AvailableCapacity{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, ac.Name, "", updatedAC))
+	assert.Equal(t, int64(0), updatedAC.Spec.Size)
+
+	// drive holds another volume
+	another := volCR
+	another.Name = "another-volume"
+	another.Spec.Id = another.Name
+	volume = importVolume(another)
+	assert.Equal(t, apiV1.Failed, volume.Spec.CSIStatus)
+	assert.Equal(t, eventing.VolumeImportFailed, rec.Calls[len(rec.Calls)-1].Reason)
+}
+
+func TestVolumeManager_importVolume_LVG(t *testing.T) {
+	var (
+		vm      = prepareSuccessVolumeManager(t)
+		listBlk = &mocklu.MockWrapLsblk{}
+		rec     = &mocks.NoOpRecorder{}
+		device  = "/dev/vg/" + testVolumeLVGCR.Name
+		lv      = lsblk.BlockDevice{Name: device, Size: lsblk.CustomInt64{Int64: 1024 * 1024 * 1024}}
+		lvg     = testLVGCR
+	)
+	vm.listBlk = listBlk
+	vm.recorder = rec
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{
+		p.LVMBasedVolumeType: mockProv.GetMockProvisionerSuccess(device)})
+	listBlk.On("GetBlockDevices", device).Return([]lsblk.BlockDevice{lv}, nil)
+	// logical volume was exported earlier
+	lvg.Spec.VolumeRefs = []string{exportedLVRefPrefix + testVolumeLVGCR.Spec.Id}
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, lvg.Name, &lvg))
+
+	volume := testVolumeLVGCR
+	volume.Spec.Size = 0
+	volume.Annotations = map[string]string{apiV1.VolumeAnnotationImport: "true"}
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, volume.Name, &volume))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNs, Name: volume.Name}}
+
+	// logical volume has no file system
+	_, err := vm.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, volume.Name, testNs, &volume))
+	assert.Equal(t, apiV1.Failed, volume.Spec.CSIStatus)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, lvg.Name, "", &lvg))
+	assert.NotContains(t, lvg.Spec.VolumeRefs, volume.Spec.Id)
+
+	// file system is found, logical volume is added to LVG
+	lv.FSType = volume.Spec.Type
+	listBlk.ExpectedCalls = nil
+	listBlk.On("GetBlockDevices", device).Return([]lsblk.BlockDevice{lv}, nil)
+	volume.Spec.CSIStatus = apiV1.Creating
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, &volume))
+	_, err = vm.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, volume.Name, testNs, &volume))
+	assert.Equal(t, apiV1.Created, volume.Spec.CSIStatus)
+	assert.Equal(t, lv.Size.Int64, volume.Spec.Size)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, lvg.Name, "", &lvg))
+	assert.Equal(t, []string{volume.Spec.Id}, lvg.Spec.VolumeRefs)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/integrity"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// DefaultIntegrityCheckInterval is an interval between integrity checks of the volumes on the node
+const DefaultIntegrityCheckInterval = 5 * time.Minute
+
+// VerifyIntegrity checks volumes with integrity protection on the node for corrupted data detected by dm-integrity
+// or by ext4 metadata checksums, result is reflected in IntegrityError condition of the Volume CR.
+// Condition isn't reset once corruption was detected because dm-integrity counter is reset on node reboot
+// Receives golang context
+// Returns error if volumes can't be listed
+func (m *VolumeManager) VerifyIntegrity(ctx context.Context) error {
+	ll := m.log.WithField("method", "VerifyIntegrity")
+
+	volumes, err := m.cachedCrHelper.GetVolumeCRs(ctx, m.nodeID)
+	if err != nil {
+		return err
+	}
+	for i := range volumes {
+		vol := &volumes[i]
+		if vol.Spec.Integrity == "" || !isIntegrityCheckable(vol.Spec.CSIStatus) {
+			continue
+		}
+		errCount, checked, err := m.countIntegrityErrors(ctx, &vol.Spec)
+		if err != nil {
+			ll.Warnf("Unable to check integrity of volume %s: %v", vol.Name, err)
+			continue
+		}
+		if checked {
+			m.setIntegrityResult(ctx, vol, errCount)
+		}
+	}
+	return nil
+}
+
+// isIntegrityCheckable returns true if volume with provided CSI status exists on the node and isn't being removed
+func isIntegrityCheckable(csiStatus string) bool {
+	switch csiStatus {
+	case apiV1.Created, apiV1.VolumeReady, apiV1.Published, apiV1.Resizing, apiV1.Resized:
+		return true
+	}
+	return false
+}
+
+// countIntegrityErrors returns amount of integrity errors of the volume, checked is false if volume can't be checked
+// at the moment, e.g. dm-integrity device isn't active because volume wasn't staged after node reboot
+func (m *VolumeManager) countIntegrityErrors(ctx context.Context, vol *api.Volume) (errCount int64, checked bool, err error) {
+	if vol.Integrity == apiV1.IntegrityDMIntegrity {
+		name := integrity.DeviceName(vol.Id)
+		opened, err := m.intOps.IsOpened(name)
+		if err != nil || !opened {
+			return 0, false, err
+		}
+		errCount, err = m.intOps.GetMismatches(name)
+		return errCount, err == nil, err
+	}
+	device, err := m.getProvisionerForVolume(vol).GetVolumePath(ctx, *vol)
+	if err != nil {
+		return 0, false, err
+	}
+	errCount, err = m.intOps.GetFSErrorCount(device)
+	return errCount, err == nil, err
+}
+
+// setIntegrityResult updates IntegrityError condition of the volume and sends event if corruption was detected,
+// Volume CR is updated only if condition was changed
+func (m *VolumeManager) setIntegrityResult(ctx context.Context, volume *volumecrd.Volume, errCount int64) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "setIntegrityResult",
+		"volumeID": volume.Name,
+	})
+
+	prev := volume.GetCondition(volumecrd.VolumeConditionIntegrityError)
+	corrupted := errCount > 0 || (prev != nil && prev.Status == corev1.ConditionTrue)
+	message := "no integrity errors detected"
+	switch {
+	case errCount > 0 && volume.Spec.Integrity == apiV1.IntegrityDMIntegrity:
+		message = fmt.Sprintf("%d checksum mismatches detected by dm-integrity", errCount)
+	case errCount > 0:
+		message = fmt.Sprintf("%d errors recorded in file system superblock", errCount)
+	case corrupted:
+		message = prev.Message
+	}
+	if prev != nil && prev.Message == message && (prev.Status == corev1.ConditionTrue) == corrupted {
+		return
+	}
+
+	volume.SetIntegrityError(corrupted, message, metav1.Now())
+	if err := m.k8sClient.UpdateStatus(ctx, volume); err != nil {
+		ll.Errorf("Unable to set IntegrityError condition: %v", err)
+		return
+	}
+	if errCount > 0 {
+		ll.Errorf("Volume data is corrupted: %s", message)
+		m.recorder.Eventf(volume, eventing.WarningType, eventing.VolumeIntegrityError,
+			"Integrity check of the volume on node %s failed: %s", volume.Spec.NodeId, message)
+	}
+}
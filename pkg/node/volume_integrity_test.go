@@ -0,0 +1,102 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/integrity"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
+)
+
+func TestVolumeManager_VerifyIntegrity(t *testing.T) {
+	var (
+		vm         = prepareSuccessVolumeManager(t)
+		intOps     = &mocklu.MockWrapIntegrity{}
+		rec        = &mocks.NoOpRecorder{}
+		device     = "/dev/sda1"
+		dmVol      = volCR
+		csumVol    = volCR
+		mapperName = integrity.DeviceName(dmVol.Spec.Id)
+	)
+	vm.intOps = intOps
+	vm.recorder = rec
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{
+		p.DriveBasedVolumeType: mockProv.GetMockProvisionerSuccess(device)})
+
+	dmVol.Spec.CSIStatus = apiV1.Published
+	dmVol.Spec.Integrity = apiV1.IntegrityDMIntegrity
+	csumVol.Name, csumVol.Spec.Id = "checksum-volume", "checksum-volume"
+	csumVol.Spec.CSIStatus = apiV1.VolumeReady
+	csumVol.Spec.Integrity = apiV1.IntegrityChecksum
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, dmVol.Name, &dmVol))
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, csumVol.Name, &csumVol))
+
+	condition := func(name string) *vcrd.VolumeCondition {
+		volume := &vcrd.Volume{}
+		assert.Nil(t, vm.k8sClient.ReadCR(testCtx, name, testNs, volume))
+		return volume.GetCondition(vcrd.VolumeConditionIntegrityError)
+	}
+
+	// no errors
+	intOps.On("IsOpened", mapperName).Return(true, nil).Times(1)
+	intOps.On("GetMismatches", mapperName).Return(int64(0), nil).Times(1)
+	intOps.On("GetFSErrorCount", device).Return(int64(0), nil).Times(1)
+	assert.Nil(t, vm.VerifyIntegrity(testCtx))
+	assert.Equal(t, corev1.ConditionFalse, condition(dmVol.Name).Status)
+	assert.Equal(t, corev1.ConditionFalse, condition(csumVol.Name).Status)
+	assert.Empty(t, rec.Calls)
+
+	// mismatches detected by dm-integrity
+	intOps.On("IsOpened", mapperName).Return(true, nil).Times(1)
+	intOps.On("GetMismatches", mapperName).Return(int64(3), nil).Times(1)
+	intOps.On("GetFSErrorCount", device).Return(int64(0), nil).Times(1)
+	assert.Nil(t, vm.VerifyIntegrity(testCtx))
+	assert.Equal(t, corev1.ConditionTrue, condition(dmVol.Name).Status)
+	assert.Contains(t, condition(dmVol.Name).Message, "3 checksum mismatches")
+	assert.Equal(t, corev1.ConditionFalse, condition(csumVol.Name).Status)
+	assert.Len(t, rec.Calls, 1)
+	assert.Equal(t, eventing.VolumeIntegrityError, rec.Calls[0].Reason)
+
+	// counter is reset after reopening of the device, condition is kept, check of other volume failed
+	intOps.On("IsOpened", mapperName).Return(true, nil).Times(1)
+	intOps.On("GetMismatches", mapperName).Return(int64(0), nil).Times(1)
+	intOps.On("GetFSErrorCount", device).Return(int64(0), errors.New("dumpe2fs failed")).Times(1)
+	assert.Nil(t, vm.VerifyIntegrity(testCtx))
+	assert.Equal(t, corev1.ConditionTrue, condition(dmVol.Name).Status)
+	assert.Contains(t, condition(dmVol.Name).Message, "3 checksum mismatches")
+	assert.Equal(t, corev1.ConditionFalse, condition(csumVol.Name).Status)
+
+	// errors recorded in ext4 superblock, dm-integrity device isn't active
+	intOps.On("IsOpened", mapperName).Return(false, nil).Times(1)
+	intOps.On("GetFSErrorCount", device).Return(int64(1), nil).Times(1)
+	assert.Nil(t, vm.VerifyIntegrity(testCtx))
+	assert.Equal(t, corev1.ConditionTrue, condition(csumVol.Name).Status)
+	assert.Contains(t, condition(csumVol.Name).Message, "1 errors recorded")
+
+	intOps.AssertExpectations(t)
+}
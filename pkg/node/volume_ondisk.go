@@ -0,0 +1,94 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// DefaultOnDiskCheckInterval is an interval between checks that allocations of the volumes exist on the node
+const DefaultOnDiskCheckInterval = 10 * time.Minute
+
+// VerifyOnDisk compares locations of the Volume CRs of the node against partitions and logical volumes on the node,
+// volume which allocation has vanished (e.g. partition table was wiped outside of the driver) gets OnDiskMissing
+// condition, so the problem is visible before the next publish of the volume fails. Condition is reset once
+// allocation is found again, e.g. partition table was restored
+// Receives golang context
+// Returns error if volumes can't be listed
+func (m *VolumeManager) VerifyOnDisk(ctx context.Context) error {
+	ll := m.log.WithField("method", "VerifyOnDisk")
+
+	volumes, err := m.cachedCrHelper.GetVolumeCRs(ctx, m.nodeID)
+	if err != nil {
+		return err
+	}
+	for i := range volumes {
+		vol := &volumes[i]
+		// inline volumes live as long as the pod and are recreated with it
+		if vol.Spec.Ephemeral || !isIntegrityCheckable(vol.Spec.CSIStatus) {
+			continue
+		}
+		exists, err := m.getProvisionerForVolume(&vol.Spec).VolumeExists(ctx, vol.Spec)
+		if err != nil {
+			ll.Warnf("Unable to check allocation of volume %s: %v", vol.Name, err)
+			continue
+		}
+		m.setOnDiskResult(ctx, vol, !exists)
+	}
+	return nil
+}
+
+// setOnDiskResult updates OnDiskMissing condition of the volume and sends event if allocation has vanished,
+// Volume CR is updated only if condition was changed
+func (m *VolumeManager) setOnDiskResult(ctx context.Context, volume *volumecrd.Volume, missing bool) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "setOnDiskResult",
+		"volumeID": volume.Name,
+	})
+
+	allocation := fmt.Sprintf("partition on drive %s", volume.Spec.Location)
+	if util.IsStorageClassLVG(volume.Spec.StorageClass) {
+		allocation = fmt.Sprintf("logical volume in LogicalVolumeGroup %s", volume.Spec.Location)
+	}
+	message := allocation + " is found"
+	if missing {
+		message = allocation + " isn't found"
+	}
+	if !volume.SetOnDiskMissing(missing, message, metav1.Now()) {
+		return
+	}
+	if err := m.k8sClient.UpdateStatus(ctx, volume); err != nil {
+		ll.Errorf("Unable to set OnDiskMissing condition: %v", err)
+		return
+	}
+	if missing {
+		ll.Errorf("Allocation of the volume has vanished: %s", message)
+		m.recorder.Eventf(volume, eventing.WarningType, eventing.VolumeOnDiskMissing,
+			"Allocation of the volume on node %s has vanished: %s", volume.Spec.NodeId, message)
+	} else {
+		ll.Infof("Allocation of the volume is found again: %s", message)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2021 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
+)
+
+func TestVolumeManager_VerifyOnDisk(t *testing.T) {
+	var (
+		vm          = prepareSuccessVolumeManager(t)
+		prov        = &mockProv.MockProvisioner{}
+		rec         = &mocks.NoOpRecorder{}
+		vol         = volCR
+		creatingVol = volCR
+	)
+	vm.recorder = rec
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{p.DriveBasedVolumeType: prov})
+
+	vol.Spec.CSIStatus = apiV1.Published
+	// volume which is being created isn't checked
+	creatingVol.Name, creatingVol.Spec.Id = "creating-volume", "creating-volume"
+	creatingVol.Spec.CSIStatus = apiV1.Creating
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, vol.Name, &vol))
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, creatingVol.Name, &creatingVol))
+
+	condition := func() *vcrd.VolumeCondition {
+		volume := &vcrd.Volume{}
+		assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, testNs, volume))
+		return volume.GetCondition(vcrd.VolumeConditionOnDiskMissing)
+	}
+
+	// allocation exists, condition isn't added
+	prov.On("VolumeExists", vol.Spec).Return(true, nil).Once()
+	assert.Nil(t, vm.VerifyOnDisk(testCtx))
+	assert.Nil(t, condition())
+
+	// partition was wiped
+	prov.On("VolumeExists", vol.Spec).Return(false, nil).Once()
+	assert.Nil(t, vm.VerifyOnDisk(testCtx))
+	assert.Equal(t, corev1.ConditionTrue, condition().Status)
+	assert.Contains(t, condition().Message, "isn't found")
+	assert.Len(t, rec.Calls, 1)
+	assert.Equal(t, eventing.VolumeOnDiskMissing, rec.Calls[0].Reason)
+
+	// drive can't be inspected, condition is kept
+	prov.On("VolumeExists", vol.Spec).Return(false, errors.New("lsblk failed")).Once()
+	assert.Nil(t, vm.VerifyOnDisk(testCtx))
+	assert.Equal(t, corev1.ConditionTrue, condition().Status)
+
+	// partition table was restored
+	prov.On("VolumeExists", vol.Spec).Return(true, nil).Once()
+	assert.Nil(t, vm.VerifyOnDisk(testCtx))
+	assert.Equal(t, corev1.ConditionFalse, condition().Status)
+	assert.Len(t, rec.Calls, 1)
+
+	prov.AssertExpectations(t)
+}
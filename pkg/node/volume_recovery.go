@@ -0,0 +1,150 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/node/journal"
+)
+
+// RecoverOperations finishes or reverts operations which were interrupted by crash of the node service,
+// decision depends on the recorded steps and Volume CR status only. Creation which didn't provision the volume
+// is reverted and the volume is created again by Reconcile, provisioned volume gets Created status. Removal is
+// finished. The first staging is reverted by unmount of the staging path, kubelet repeats it.
+// Should be called on start before volumes are reconciled and CSI calls are served
+// Returns error if some of the operations were not recovered, their journal entries are kept for the next start
+func (m *VolumeManager) RecoverOperations(ctx context.Context) error {
+	ll := m.log.WithField("method", "RecoverOperations")
+
+	entries, err := m.journal.Pending()
+	if err != nil {
+		return fmt.Errorf("unable to read operation journal: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	volumes, err := m.crHelper.GetVolumeCRs(ctx, m.nodeID)
+	if err != nil {
+		return fmt.Errorf("unable to read volumes of the node: %w", err)
+	}
+	volumeByID := make(map[string]*volumecrd.Volume, len(volumes))
+	for i := range volumes {
+		volumeByID[volumes[i].Spec.Id] = &volumes[i]
+	}
+
+	failed := 0
+	for _, entry := range entries {
+		volume := volumeByID[entry.Volume.Id]
+		ll.Infof("Recovering %s operation with volume %s, finished steps: %v", entry.Operation, entry.Volume.Id, entry.Steps)
+		switch entry.Operation {
+		case journal.OperationCreate:
+			err = m.recoverCreate(ctx, entry, volume)
+		case journal.OperationDelete:
+			err = m.recoverDelete(ctx, entry, volume)
+		case journal.OperationStage:
+			err = m.recoverStage(entry, volume)
+		default:
+			err = fmt.Errorf("unknown operation %s", entry.Operation)
+		}
+		if err != nil {
+			ll.Errorf("Unable to recover %s operation with volume %s: %v", entry.Operation, entry.Volume.Id, err)
+			failed++
+			continue
+		}
+		m.journalFinish(entry.Volume.Id)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d operation(s) were not recovered", failed)
+	}
+	return nil
+}
+
+// recoverCreate sets Created status of provisioned volume, otherwise removes what was created,
+// volume which couldn't be cleaned gets Failed status
+func (m *VolumeManager) recoverCreate(ctx context.Context, entry *journal.Entry, volume *volumecrd.Volume) error {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "recoverCreate",
+		"volumeID": entry.Volume.Id,
+	})
+
+	if volume != nil && volume.Spec.CSIStatus != apiV1.Creating {
+		return nil
+	}
+	if volume != nil && entry.HasStep(journal.StepProvisioned) {
+		ll.Info("Volume was provisioned, roll forward to Created status")
+		volume.Spec.CSIStatus = apiV1.Created
+		return m.k8sClient.UpdateCR(ctx, volume)
+	}
+
+	ll.Info("Roll back volume creation")
+	err := m.getProvisionerForVolume(&entry.Volume).ReleaseVolume(ctx, entry.Volume)
+	if err == nil || volume == nil {
+		return err
+	}
+	ll.Errorf("Unable to release partially created volume: %v. Set status to Failed", err)
+	volume.Spec.CSIStatus = apiV1.Failed
+	return m.k8sClient.UpdateCR(ctx, volume)
+}
+
+// recoverDelete releases the volume if it wasn't released and sets Removed status
+func (m *VolumeManager) recoverDelete(ctx context.Context, entry *journal.Entry, volume *volumecrd.Volume) error {
+	if volume == nil || volume.Spec.CSIStatus != apiV1.Removing {
+		return nil
+	}
+	if entry.HasStep(journal.StepReleased) {
+		m.log.WithField("volumeID", entry.Volume.Id).Info("Volume was released, roll forward to Removed status")
+		volume.Spec.CSIStatus = apiV1.Removed
+		return m.k8sClient.UpdateCR(ctx, volume)
+	}
+	// volume gets Removed or Failed status
+	_, err := m.handleRemovingStatus(ctx, volume)
+	return err
+}
+
+// recoverStage unmounts staging path of the volume which status wasn't changed by the first staging
+func (m *VolumeManager) recoverStage(entry *journal.Entry, volume *volumecrd.Volume) error {
+	if volume == nil || volume.Spec.CSIStatus != apiV1.Created {
+		return nil
+	}
+	mounted, err := m.fsOps.IsMounted(entry.StagingPath)
+	if err != nil || !mounted {
+		return err
+	}
+	m.log.WithField("volumeID", entry.Volume.Id).Infof("Roll back staging, unmount %s", entry.StagingPath)
+	return m.fsOps.UnmountWithCheck(entry.StagingPath)
+}
+
+// journalStep records finished step of the operation, failure is only logged because the step is already done
+// and the operation is recovered based on Volume CR status
+func (m *VolumeManager) journalStep(volumeID, step string) {
+	if err := m.journal.Step(volumeID, step); err != nil {
+		m.log.WithField("volumeID", volumeID).Warnf("Unable to record step %s in journal: %v", step, err)
+	}
+}
+
+// journalFinish removes journal entry of the finished operation
+func (m *VolumeManager) journalFinish(volumeID string) {
+	if err := m.journal.Finish(volumeID); err != nil {
+		m.log.WithField("volumeID", volumeID).Warnf("Unable to remove journal entry: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+	"github.com/dell/csi-baremetal/pkg/node/journal"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
+)
+
+func TestVolumeManager_RecoverOperations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	prepare := func(status string) (*CSINodeService, *mockProv.MockProvisioner, *mockProv.MockFsOpts, vcrd.Volume) {
+		svc := newNodeService()
+		prov := &mockProv.MockProvisioner{}
+		fsOps := &mockProv.MockFsOpts{}
+		svc.provisioners = map[p.VolumeType]p.Provisioner{
+			p.DriveBasedVolumeType: prov,
+			p.LVMBasedVolumeType:   prov,
+		}
+		svc.fsOps = fsOps
+		svc.journal = journal.New(dir)
+
+		volume := vcrd.Volume{}
+		assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testV1ID, "", &volume))
+		volume.Spec.CSIStatus = status
+		assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, &volume))
+		return svc, prov, fsOps, volume
+	}
+	recoveredStatus := func(svc *CSINodeService) string {
+		entries, err := svc.journal.Pending()
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+		volume := vcrd.Volume{}
+		assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testV1ID, "", &volume))
+		return volume.Spec.CSIStatus
+	}
+
+	t.Run("Creation is reverted", func(t *testing.T) {
+		svc, prov, _, volume := prepare(apiV1.Creating)
+		assert.Nil(t, svc.journal.Begin(journal.OperationCreate, volume.Spec, ""))
+		prov.On("ReleaseVolume", volume.Spec).Return(nil).Once()
+
+		assert.Nil(t, svc.RecoverOperations(testCtx))
+		prov.AssertExpectations(t)
+		// volume is created again by Reconcile
+		assert.Equal(t, apiV1.Creating, recoveredStatus(svc))
+	})
+
+	t.Run("Creation isn't reverted", func(t *testing.T) {
+		svc, prov, _, volume := prepare(apiV1.Creating)
+		assert.Nil(t, svc.journal.Begin(journal.OperationCreate, volume.Spec, ""))
+		prov.On("ReleaseVolume", volume.Spec).Return(errors.New("wipefs failed")).Once()
+
+		assert.Nil(t, svc.RecoverOperations(testCtx))
+		assert.Equal(t, apiV1.Failed, recoveredStatus(svc))
+	})
+
+	t.Run("Creation is finished", func(t *testing.T) {
+		svc, prov, _, volume := prepare(apiV1.Creating)
+		assert.Nil(t, svc.journal.Begin(journal.OperationCreate, volume.Spec, ""))
+		assert.Nil(t, svc.journal.Step(volume.Spec.Id, journal.StepProvisioned))
+
+		assert.Nil(t, svc.RecoverOperations(testCtx))
+		prov.AssertNotCalled(t, "ReleaseVolume", volume.Spec)
+		assert.Equal(t, apiV1.Created, recoveredStatus(svc))
+	})
+
+	t.Run("Removal is finished", func(t *testing.T) {
+		svc, _, _, volume := prepare(apiV1.Removing)
+		assert.Nil(t, svc.journal.Begin(journal.OperationDelete, volume.Spec, ""))
+		assert.Nil(t, svc.journal.Step(volume.Spec.Id, journal.StepReleased))
+
+		assert.Nil(t, svc.RecoverOperations(testCtx))
+		assert.Equal(t, apiV1.Removed, recoveredStatus(svc))
+	})
+
+	t.Run("Staging is reverted", func(t *testing.T) {
+		stagingPath := "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-1/globalmount"
+		svc, _, fsOps, volume := prepare(apiV1.Created)
+		assert.Nil(t, svc.journal.Begin(journal.OperationStage, volume.Spec, stagingPath))
+		assert.Nil(t, svc.journal.Step(volume.Spec.Id, journal.StepMounted))
+		fsOps.On("IsMounted", stagingPath).Return(true, nil).Once()
+		fsOps.On("UnmountWithCheck", stagingPath).Return(nil).Once()
+
+		assert.Nil(t, svc.RecoverOperations(testCtx))
+		fsOps.AssertExpectations(t)
+		assert.Equal(t, apiV1.Created, recoveredStatus(svc))
+	})
+
+	t.Run("Entry is kept", func(t *testing.T) {
+		stagingPath := "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-1/globalmount"
+		svc, _, fsOps, volume := prepare(apiV1.Created)
+		assert.Nil(t, svc.journal.Begin(journal.OperationStage, volume.Spec, stagingPath))
+		fsOps.On("IsMounted", stagingPath).Return(false, errors.New("findmnt failed")).Once()
+
+		assert.NotNil(t, svc.RecoverOperations(testCtx))
+		entries, err := svc.journal.Pending()
+		assert.Nil(t, err)
+		assert.Len(t, entries, 1)
+		assert.Nil(t, svc.journal.Finish(volume.Spec.Id))
+	})
+}
+
+func TestVolumeManager_prepareVolumeJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	vm := prepareSuccessVolumeManager(t)
+	vm.journal = journal.New(dir)
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{p.DriveBasedVolumeType: mockProv.GetMockProvisionerSuccess("/dev/sda1")})
+	volume := volCR.DeepCopy()
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, volume.Name, volume))
+
+	_, err = vm.prepareVolume(testCtx, volume)
+	assert.Nil(t, err)
+	assert.Equal(t, apiV1.Created, volume.Spec.CSIStatus)
+	// entry of the finished operation is removed
+	entries, err := vm.journal.Pending()
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
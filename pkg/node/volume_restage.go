@@ -0,0 +1,100 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+)
+
+// RestageVolumes mounts volumes of the node which were staged before node reboot and whose staging path isn't
+// mounted anymore. Volumes are detected with Volume CRs of the node which have VolumeReady or Published status
+// and staging-path annotation. Should be called on start before CSI calls are served, otherwise kubelet could
+// publish the volume from empty staging path
+// Returns error if some of the volumes were not restaged
+func (s *CSINodeService) RestageVolumes(ctx context.Context) error {
+	ll := s.log.WithFields(logrus.Fields{
+		"method": "RestageVolumes",
+	})
+
+	volumes, err := s.crHelper.GetVolumeCRs(ctx, s.nodeID)
+	if err != nil {
+		return fmt.Errorf("unable to read volumes of the node: %w", err)
+	}
+
+	failed := 0
+	for i := range volumes {
+		volume := &volumes[i]
+		stagingPath, ok := volume.Annotations[apiV1.VolumeAnnotationStagingPath]
+		if !ok || volume.Spec.Ephemeral ||
+			(volume.Spec.CSIStatus != apiV1.VolumeReady && volume.Spec.CSIStatus != apiV1.Published) {
+			continue
+		}
+		if err := s.restageVolume(ctx, volume, stagingPath); err != nil {
+			ll.Errorf("Unable to restage volume %s: %v", volume.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d volume(s) were not restaged", failed)
+	}
+	return nil
+}
+
+// restageVolume mounts device of the volume into the staging path if it isn't mounted
+func (s *CSINodeService) restageVolume(ctx context.Context, volume *volumecrd.Volume, stagingPath string) error {
+	ll := s.log.WithFields(logrus.Fields{
+		"method":   "restageVolume",
+		"volumeID": volume.Name,
+	})
+
+	s.volMu.LockKey(volume.Spec.Id)
+	defer func() {
+		if err := s.volMu.UnlockKey(volume.Spec.Id); err != nil {
+			ll.Warnf("Unlocking volume with error %s", err)
+		}
+	}()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	mounted, err := s.fsOps.IsMounted(stagingPath)
+	if err != nil {
+		return err
+	}
+	if mounted {
+		ll.Debugf("Staging path %s is mounted", stagingPath)
+		return nil
+	}
+
+	if !s.tryLockDevice(volume.Spec.Id) {
+		return fmt.Errorf("file system check is in progress")
+	}
+	defer s.unlockDevice(volume.Spec.Id)
+
+	device, err := s.getProvisionerForVolume(&volume.Spec).GetVolumePath(ctx, volume.Spec)
+	if err != nil {
+		return fmt.Errorf("unable to determine device of volume: %w", err)
+	}
+	ll.Infof("Mount %s into staging path %s", device, stagingPath)
+	return s.fsOps.PrepareAndPerformMount(device, stagingPath, true, false)
+}
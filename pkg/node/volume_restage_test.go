@@ -0,0 +1,81 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
+)
+
+func TestCSINodeService_RestageVolumes(t *testing.T) {
+	var (
+		svc          = newNodeService()
+		prov         = &mockProv.MockProvisioner{}
+		fsOps        = &mockProv.MockFsOpts{}
+		device       = "/dev/sda1"
+		stagingPath1 = "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-1/globalmount/dev"
+		stagingPath2 = "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-2/globalmount/dev"
+	)
+	svc.fsOps = fsOps
+	svc.provisioners = map[p.VolumeType]p.Provisioner{
+		p.DriveBasedVolumeType: prov,
+		p.LVMBasedVolumeType:   prov,
+	}
+
+	setStaged := func(id, status, stagingPath string) vcrd.Volume {
+		volume := vcrd.Volume{}
+		assert.Nil(t, svc.k8sClient.ReadCR(testCtx, id, "", &volume))
+		volume.Spec.CSIStatus = status
+		volume.Annotations = map[string]string{apiV1.VolumeAnnotationStagingPath: stagingPath}
+		assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, &volume))
+		return volume
+	}
+	// volume 1 is published and isn't mounted after reboot, volume 2 is still mounted, volume 3 isn't staged
+	vol1 := setStaged(testV1ID, apiV1.Published, stagingPath1)
+	setStaged(testV2ID, apiV1.VolumeReady, stagingPath2)
+
+	fsOps.On("IsMounted", stagingPath1).Return(false, nil).Once()
+	fsOps.On("IsMounted", stagingPath2).Return(true, nil).Once()
+	prov.On("GetVolumePath", vol1.Spec).Return(device, nil).Once()
+	fsOps.On("PrepareAndPerformMount", device, stagingPath1, true, false).Return(nil).Once()
+	assert.Nil(t, svc.RestageVolumes(testCtx))
+
+	// mount failed
+	fsOps.On("IsMounted", stagingPath1).Return(false, nil).Once()
+	fsOps.On("IsMounted", stagingPath2).Return(true, nil).Once()
+	prov.On("GetVolumePath", vol1.Spec).Return(device, nil).Once()
+	fsOps.On("PrepareAndPerformMount", device, stagingPath1, true, false).
+		Return(errors.New("mount error")).Once()
+	assert.NotNil(t, svc.RestageVolumes(testCtx))
+
+	// file system check is running
+	assert.True(t, svc.tryLockDevice(testV1ID))
+	fsOps.On("IsMounted", stagingPath1).Return(false, nil).Once()
+	fsOps.On("IsMounted", stagingPath2).Return(true, nil).Once()
+	assert.NotNil(t, svc.RestageVolumes(testCtx))
+	svc.unlockDevice(testV1ID)
+
+	fsOps.AssertExpectations(t)
+	prov.AssertExpectations(t)
+}
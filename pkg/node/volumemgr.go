@@ -20,14 +20,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/keymutex"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -38,14 +43,19 @@ import (
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/audit"
 	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/crashdump"
+	"github.com/dell/csi-baremetal/pkg/base/imagesource"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/integrity"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lvm"
 	ph "github.com/dell/csi-baremetal/pkg/base/linuxutils/partitionhelper"
@@ -54,6 +64,8 @@ import (
 	"github.com/dell/csi-baremetal/pkg/eventing"
 	"github.com/dell/csi-baremetal/pkg/metrics"
 	metricsC "github.com/dell/csi-baremetal/pkg/metrics/common"
+	"github.com/dell/csi-baremetal/pkg/node/faults"
+	"github.com/dell/csi-baremetal/pkg/node/journal"
 	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
 	"github.com/dell/csi-baremetal/pkg/node/provisioners/utilwrappers"
 )
@@ -62,11 +74,19 @@ const volumeFinalizer = "dell.emc.csi/volume-cleanup"
 
 const deleteVolumeFailedMsg = "Failed to remove volume %s with error: %s"
 
+// journalDir is the directory of the operation journal relative to the kubelet root directory, it is kept on the host
+const journalDir = base.KubeletCSIPluginsDir + "/csi-baremetal-journal"
+
 // eventRecorder interface for sending events
 type eventRecorder interface {
 	Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{})
 }
 
+// healthDetailsProvider is implemented by drive manager clients which receive drives by v2 API, e.g. drivemgr.Registry
+type healthDetailsProvider interface {
+	HealthDetails(serialNumber string) *api.DriveHealthDetails
+}
+
 // VolumeManager is the struct to perform volume operations on node side with real storage devices
 type VolumeManager struct {
 	// for interacting with kubernetes objects
@@ -82,6 +102,16 @@ type VolumeManager struct {
 	driveMgrClient api.DriveServiceClient
 	// holds implementations of Provisioner interface
 	provisioners map[p.VolumeType]p.Provisioner
+	// holds time of provisioning phases which are finished by provisioners
+	phases *p.PhaseTracker
+	// records destructive operations performed by provisioners
+	audit *audit.Logger
+	// injects failures for chaos testing, nil if fault injection is disabled
+	faults *faults.Injector
+	// records panics of Reconcile, nil if crash dumps are disabled
+	crashRecorder *crashdump.Recorder
+	// records multi-step operations to finish or revert them after crash, nil until kubelet directory is set
+	journal *journal.Journal
 
 	// uses for operations with partitions
 	partOps ph.WrapPartition
@@ -91,9 +121,19 @@ type VolumeManager struct {
 	lvmOps lvm.WrapLVM
 	// uses for running lsblk util
 	listBlk lsblk.WrapLsblk
+	// runs commands which aren't wrapped by linuxutils, e.g. losetup of the self-test
+	executor command.CmdExecutor
+	// allows only one self-test at a time
+	selfTestMu sync.Mutex
+	// requests discovery out of the discovery interval, requests are coalesced while discovery is pending
+	discoveryTrigger chan struct{}
 
 	// uses for searching suitable Available Capacity
 	acProvider common.AvailableCapacityOperations
+	// downloads images which are written onto the volumes
+	imageFetcher *imagesource.Fetcher
+	// reads results of integrity checks of the volumes
+	intOps integrity.WrapIntegrity
 
 	// kubernetes node ID
 	nodeID string
@@ -108,6 +148,13 @@ type VolumeManager struct {
 	recorder eventRecorder
 	// reconcile lock
 	volMu keymutex.KeyMutex
+	// volumes which devices are used by staging or file system check, these operations exclude each other
+	busyDevices map[string]bool
+	devMu       sync.Mutex
+	// limits amount of partitioning, mkfs and wipefs operations which run on the node in parallel
+	opSem chan struct{}
+	// root directory of kubelet on the node
+	kubeletDir string
 	// systemDrivesUUIDs represent system drive uuids, used to avoid unnecessary calls to Kubernetes API.
 	// We use slice in case of RAID and multiple system disks
 	systemDrivesUUIDs []string
@@ -115,6 +162,10 @@ type VolumeManager struct {
 	// metrics
 	metricDriveMgrDuration metrics.Statistic
 	metricDriveMgrCount    prometheus.Gauge
+	// exposes fast-changing drive attributes which are stored in Drive CR only on significant change
+	telemetry *driveTelemetry
+	// counts I/O errors of the drives and calculates their rate during the last hour
+	ioErrors *ioErrorMonitor
 }
 
 // driveStates internal struct, holds info about drive updates
@@ -151,6 +202,8 @@ const (
 	VolumeOperationsTimeout = 900 * time.Second
 	// amount of reconcile requests that could be processed simultaneously
 	maxConcurrentReconciles = 15
+	// DefaultVolumeOperationsLimit is the amount of volumes which could be created or removed on the node simultaneously
+	DefaultVolumeOperationsLimit = 5
 )
 
 // NewVolumeManager is the constructor for VolumeManager struct
@@ -173,13 +226,25 @@ func NewVolumeManager(
 		Name: "discovery_drive_count",
 		Help: "last drive count discovered",
 	})
-	for _, c := range []prometheus.Collector{driveMgrDuration.Collect(), driveMgrCount} {
+	telemetry := newDriveTelemetry()
+	ioErrors := newIOErrorMonitor(logger)
+	for _, c := range []prometheus.Collector{driveMgrDuration.Collect(), driveMgrCount, telemetry.endurance,
+		telemetry.temperature, ioErrors.count} {
 		if err := prometheus.Register(c); err != nil {
 			logger.WithField("component", "NewVolumeManager").
 				Errorf("Failed to register metric: %v", err)
 		}
 	}
 
+	phases := p.NewPhaseTracker()
+	driveProvisioner := p.NewDriveProvisioner(executor, k8sClient, logger)
+	driveProvisioner.SetPhaseTracker(phases)
+	lvmProvisioner := p.NewLVMProvisioner(executor, k8sClient, logger)
+	lvmProvisioner.SetPhaseTracker(phases)
+	auditLog := audit.NewLogger()
+	driveProvisioner.SetAuditLogger(auditLog)
+	lvmProvisioner.SetAuditLogger(auditLog)
+
 	vm := &VolumeManager{
 		k8sClient:      k8sClient,
 		k8sCache:       k8sCache,
@@ -187,22 +252,33 @@ func NewVolumeManager(
 		cachedCrHelper: k8s.NewCRHelper(k8sClient, logger).SetReader(k8sCache),
 		driveMgrClient: client,
 		acProvider:     common.NewACOperationsImpl(k8sClient, logger),
+		imageFetcher:   imagesource.NewFetcher(logger),
+		intOps:         integrity.NewIntegrity(executor),
 		provisioners: map[p.VolumeType]p.Provisioner{
-			p.DriveBasedVolumeType: p.NewDriveProvisioner(executor, k8sClient, logger),
-			p.LVMBasedVolumeType:   p.NewLVMProvisioner(executor, k8sClient, logger),
+			p.DriveBasedVolumeType: driveProvisioner,
+			p.LVMBasedVolumeType:   lvmProvisioner,
 		},
+		phases:                 phases,
+		audit:                  auditLog,
 		fsOps:                  utilwrappers.NewFSOperationsImpl(executor, logger),
 		lvmOps:                 lvm.NewLVM(executor, logger),
 		listBlk:                lsblk.NewLSBLK(logger),
+		executor:               executor,
 		partOps:                ph.NewWrapPartitionImpl(executor, logger),
 		nodeID:                 nodeID,
 		log:                    logger.WithField("component", "VolumeManager"),
 		recorder:               recorder,
 		discoverSystemLVG:      true,
 		volMu:                  keymutex.NewHashed(0),
+		busyDevices:            make(map[string]bool),
+		opSem:                  make(chan struct{}, DefaultVolumeOperationsLimit),
+		kubeletDir:             base.DefaultKubeletDir,
 		systemDrivesUUIDs:      make([]string, 0),
 		metricDriveMgrDuration: driveMgrDuration,
 		metricDriveMgrCount:    driveMgrCount,
+		telemetry:              telemetry,
+		ioErrors:               ioErrors,
+		discoveryTrigger:       make(chan struct{}, 1),
 	}
 	return vm
 }
@@ -213,12 +289,71 @@ func (m *VolumeManager) SetProvisioners(provs map[p.VolumeType]p.Provisioner) {
 	m.provisioners = provs
 }
 
+// SetKubeletDir sets root directory of kubelet on the node, it is used for distributions with non-standard location
+// journal of the operations is stored under it
+func (m *VolumeManager) SetKubeletDir(dir string) {
+	m.kubeletDir = dir
+	m.journal = journal.New(filepath.Join(dir, journalDir))
+}
+
+// SetAuditLogFile makes audit records of destructive operations be appended to file instead of stdout
+func (m *VolumeManager) SetAuditLogFile(path string) error {
+	return m.audit.SetOutputFile(path)
+}
+
+// SetCrashRecorder makes panics of Reconcile be recorded into crash dump and status of the volume
+func (m *VolumeManager) SetCrashRecorder(r *crashdump.Recorder) {
+	m.crashRecorder = r
+}
+
+// SetFaultInjector enables injection of failures for chaos testing in volume manager and provisioners
+func (m *VolumeManager) SetFaultInjector(i *faults.Injector) {
+	m.faults = i
+	for _, prov := range m.provisioners {
+		if injectable, ok := prov.(interface{ SetFaultInjector(*faults.Injector) }); ok {
+			injectable.SetFaultInjector(i)
+		}
+	}
+}
+
+// SetEnduranceHysteresis sets minimal change of drive endurance (in percents) which is written into Drive CR,
+// negative value is ignored
+func (m *VolumeManager) SetEnduranceHysteresis(hysteresis int) {
+	if hysteresis < 0 {
+		m.log.Warnf("Unable to set endurance hysteresis to %d, using %d", hysteresis, m.telemetry.enduranceHysteresis)
+		return
+	}
+	m.telemetry.enduranceHysteresis = int64(hysteresis)
+}
+
+// SetDriveTemperatureThreshold sets drive temperature (in Celsius) starting from which drive is reported as overheated,
+// zero value disables overheating events, negative value is ignored
+func (m *VolumeManager) SetDriveTemperatureThreshold(threshold int) {
+	if threshold < 0 {
+		m.log.Warnf("Unable to set drive temperature threshold to %d, using %d",
+			threshold, m.telemetry.temperatureThreshold)
+		return
+	}
+	m.telemetry.temperatureThreshold = int32(threshold)
+}
+
+// SetVolumeOperationsLimit sets amount of volumes which could be created or removed on the node simultaneously
+// Should be called before the manager starts, non-positive value is ignored
+func (m *VolumeManager) SetVolumeOperationsLimit(limit int) {
+	if limit <= 0 {
+		m.log.Warnf("Unable to set volume operations limit to %d, using %d", limit, cap(m.opSem))
+		return
+	}
+	m.opSem = make(chan struct{}, limit)
+}
+
 // Reconcile is the main Reconcile loop of VolumeManager. This loop handles creation of volumes matched to Volume CR on
 // VolumeManagers's node if Volume.Spec.CSIStatus is Creating. Also this loop handles volume deletion on the node if
 // Volume.Spec.CSIStatus is Removing.
 // Returns reconcile result as ctrl.Result or error if something went wrong
 func (m *VolumeManager) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	defer metricsC.ReconcileDuration.EvaluateDurationForType("node_volume_controller")()
+	defer m.crashRecorder.Recover("volume/Reconcile", req.Name)
 	m.volMu.LockKey(req.Name)
 	ll := m.log.WithFields(logrus.Fields{
 		"method":   "Reconcile",
@@ -241,6 +376,10 @@ func (m *VolumeManager) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	if err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	if err := m.refreshStatus(ctx, volume); err != nil {
+		ll.Errorf("Unable to refresh status of Volume: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
 	if volume.DeletionTimestamp.IsZero() {
 		if !util.ContainsString(volume.ObjectMeta.Finalizers, volumeFinalizer) && volume.Spec.CSIStatus != apiV1.Empty {
 			ll.Debug("Appending finalizer for volume")
@@ -261,7 +400,7 @@ func (m *VolumeManager) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			// todo can we do polling instead?
 			ll.Infof("Volume %s is removed. Updating related", volume.Name)
 			// drive must be present in the system
-			drive, _ := m.crHelper.GetDriveCRByVolume(volume)
+			drive, _ := m.crHelper.GetDriveCRByVolume(ctx, volume)
 			if drive != nil {
 				m.addVolumeStatusAnnotation(drive, volume.Name, apiV1.Removed)
 				if err := m.k8sClient.UpdateCR(ctx, drive); err != nil {
@@ -287,8 +426,19 @@ func (m *VolumeManager) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		}
 	}
 	ll.Infof("Processing for status %s", volume.Spec.CSIStatus)
+	// creation and removal run partitioning, mkfs and wipefs, their amount is limited to not saturate node IO
+	if volume.Spec.CSIStatus == apiV1.Creating || volume.Spec.CSIStatus == apiV1.Removing {
+		release, ok := m.acquireOperationSlot(ctx, volume)
+		if !ok {
+			return ctrl.Result{RequeueAfter: base.DefaultRequeueForVolume}, nil
+		}
+		defer release()
+	}
 	switch volume.Spec.CSIStatus {
 	case apiV1.Creating:
+		if _, ok := volume.Annotations[apiV1.VolumeAnnotationImport]; ok {
+			return m.importVolume(ctx, volume)
+		}
 		if util.IsStorageClassLVG(volume.Spec.StorageClass) {
 			return m.handleCreatingVolumeInLVG(ctx, volume)
 		}
@@ -317,9 +467,70 @@ func (m *VolumeManager) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			return m.updateVolumeAndDriveUsageStatus(ctx, volume, apiV1.VolumeUsageFailed, apiV1.DriveUsageFailed)
 		}
 	}
+	if _, ok := volume.Annotations[apiV1.VolumeAnnotationExport]; ok && volume.DeletionTimestamp.IsZero() {
+		return m.handleExportRequest(ctx, volume)
+	}
+	if _, ok := volume.Annotations[apiV1.VolumeAnnotationFsck]; ok && volume.DeletionTimestamp.IsZero() {
+		return m.handleFsckRequest(ctx, volume)
+	}
 	return ctrl.Result{}, nil
 }
 
+// tryLockDevice marks device of the volume as used by staging or file system check without waiting,
+// these operations are run from CSI calls and reconcile loop and must not use the device at the same time
+// Returns true if device was not used
+func (m *VolumeManager) tryLockDevice(volumeID string) bool {
+	m.devMu.Lock()
+	defer m.devMu.Unlock()
+	if m.busyDevices[volumeID] {
+		return false
+	}
+	m.busyDevices[volumeID] = true
+	return true
+}
+
+// unlockDevice releases device of the volume locked with tryLockDevice
+func (m *VolumeManager) unlockDevice(volumeID string) {
+	m.devMu.Lock()
+	defer m.devMu.Unlock()
+	delete(m.busyDevices, volumeID)
+}
+
+// acquireOperationSlot takes one of the slots for volume operations on the node without waiting.
+// If all slots are busy the volume is marked as Pending and the request should be requeued,
+// Pending condition is reset when the slot is acquired
+// Returns function which releases the slot and true if slot was acquired
+func (m *VolumeManager) acquireOperationSlot(ctx context.Context, volume *volumecrd.Volume) (func(), bool) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "acquireOperationSlot",
+		"volumeID": volume.Name,
+	})
+
+	sem := m.opSem
+	pending := volume.GetCondition(volumecrd.VolumeConditionPending)
+	queued := pending != nil && pending.Status == corev1.ConditionTrue
+	select {
+	case sem <- struct{}{}:
+		if queued {
+			volume.SetPending(false, metav1.Now())
+			if err := m.k8sClient.UpdateStatus(ctx, volume); err != nil {
+				ll.Warnf("Unable to reset Pending condition: %v", err)
+			}
+		}
+		return func() { <-sem }, true
+	default:
+	}
+
+	ll.Infof("Limit of %d concurrent volume operations is reached, operation is queued", cap(sem))
+	if !queued {
+		volume.SetPending(true, metav1.Now())
+		if err := m.k8sClient.UpdateStatus(ctx, volume); err != nil {
+			ll.Warnf("Unable to set Pending condition: %v", err)
+		}
+	}
+	return nil, false
+}
+
 func (m *VolumeManager) updateVolumeAndDriveUsageStatus(ctx context.Context, volume *volumecrd.Volume,
 	volumeStatus, driveStatus string) (ctrl.Result, error) {
 	ll := m.log.WithFields(logrus.Fields{
@@ -334,7 +545,7 @@ func (m *VolumeManager) updateVolumeAndDriveUsageStatus(ctx context.Context, vol
 			volume.Name, volume.Spec.Usage, err)
 		return ctrl.Result{Requeue: true}, err
 	}
-	drive, err := m.crHelper.GetDriveCRByVolume(volume)
+	drive, err := m.crHelper.GetDriveCRByVolume(ctx, volume)
 	if err != nil {
 		ll.Errorf("Unable to read drive CR, error: %v", err)
 		return ctrl.Result{Requeue: true}, err
@@ -421,7 +632,19 @@ func (m *VolumeManager) prepareVolume(ctx context.Context, volume *volumecrd.Vol
 
 	newStatus := apiV1.Created
 
-	err := m.getProvisionerForVolume(&volume.Spec).PrepareVolume(volume.Spec)
+	// creation interrupted by crash is reverted on start unless the volume was provisioned
+	if err := m.journal.Begin(journal.OperationCreate, volume.Spec, ""); err != nil {
+		ll.Errorf("Unable to record volume creation in journal: %v", err)
+		return ctrl.Result{Requeue: true, RequeueAfter: base.DefaultRequeueForVolume}, err
+	}
+	err := m.getProvisionerForVolume(&volume.Spec).PrepareVolume(ctx, volume.Spec)
+	if err == nil && volume.Spec.ImageSource != "" {
+		err = m.populateVolume(ctx, &volume.Spec)
+	}
+	if err == nil {
+		m.journalStep(volume.Spec.Id, journal.StepProvisioned)
+	}
+	phases := m.phases.Pop(volume.Spec.Id)
 	if err != nil {
 		ll.Errorf("Unable to create volume size of %d bytes: %v. Set volume status to Failed", volume.Spec.Size, err)
 		newStatus = apiV1.Failed
@@ -432,6 +655,14 @@ func (m *VolumeManager) prepareVolume(ctx context.Context, volume *volumecrd.Vol
 		ll.Errorf("Unable to update volume status to %s: %v", newStatus, updateErr)
 		return ctrl.Result{Requeue: true}, updateErr
 	}
+	m.journalFinish(volume.Spec.Id)
+	// timestamps are informational, volume is created even if they weren't stored
+	for phase, at := range phases {
+		volume.RecordPhase(phase, at)
+	}
+	if statusErr := m.k8sClient.UpdateStatus(ctx, volume); statusErr != nil {
+		ll.Errorf("Unable to record provisioning phases: %v", statusErr)
+	}
 
 	return ctrl.Result{}, err
 }
@@ -449,10 +680,15 @@ func (m *VolumeManager) handleRemovingStatus(ctx context.Context, volume *volume
 		err       error
 		newStatus string
 	)
-	if err = m.getProvisionerForVolume(&volume.Spec).ReleaseVolume(volume.Spec); err != nil {
+	// removal interrupted by crash is finished on start
+	if err = m.journal.Begin(journal.OperationDelete, volume.Spec, ""); err != nil {
+		ll.Errorf("Unable to record volume removal in journal: %v", err)
+		return ctrl.Result{Requeue: true, RequeueAfter: base.DefaultRequeueForVolume}, err
+	}
+	if err = m.getProvisionerForVolume(&volume.Spec).ReleaseVolume(ctx, volume.Spec); err != nil {
 		ll.Errorf("Failed to remove volume - %s. Error: %v. Set status to Failed", volume.Spec.Id, err)
 		newStatus = apiV1.Failed
-		drive := m.crHelper.GetDriveCRByUUID(volume.Spec.Location)
+		drive := m.crHelper.GetDriveCRByUUID(ctx, volume.Spec.Location)
 		if drive != nil {
 			drive.Spec.Usage = apiV1.DriveUsageFailed
 			if err := m.k8sClient.UpdateCRWithAttempts(ctx, drive, 5); err != nil {
@@ -465,15 +701,29 @@ func (m *VolumeManager) handleRemovingStatus(ctx context.Context, volume *volume
 	} else {
 		ll.Infof("Volume - %s was successfully removed. Set status to Removed", volume.Spec.Id)
 		newStatus = apiV1.Removed
+		m.journalStep(volume.Spec.Id, journal.StepReleased)
 	}
 	volume.Spec.CSIStatus = newStatus
 	if updateErr := m.k8sClient.UpdateCRWithAttempts(ctx, volume, 10); updateErr != nil {
 		ll.Error("Unable to set new status for volume")
 		return ctrl.Result{Requeue: true}, updateErr
 	}
+	m.journalFinish(volume.Spec.Id)
 	return ctrl.Result{}, err
 }
 
+// refreshStatus stores conditions of the volume which are derived from its spec if they are outdated,
+// spec is changed by both controller and node services, so status is refreshed once for each change
+// Returns error if status couldn't be stored
+func (m *VolumeManager) refreshStatus(ctx context.Context, volume *volumecrd.Volume) error {
+	refreshed := volume.DeepCopy()
+	refreshed.RefreshStatus(metav1.Now())
+	if reflect.DeepEqual(refreshed.Status, volume.Status) {
+		return nil
+	}
+	return m.k8sClient.UpdateStatus(ctx, volume)
+}
+
 // SetupWithManager registers VolumeManager to ControllerManager
 func (m *VolumeManager) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -510,14 +760,31 @@ func (m *VolumeManager) isCorrespondedToNodePredicate(obj runtime.Object) bool {
 	return false
 }
 
+// TriggerDiscovery requests discovery without waiting for the discovery interval, it doesn't wait for discovery,
+// requests which are made while discovery is pending are coalesced
+func (m *VolumeManager) TriggerDiscovery() {
+	select {
+	case m.discoveryTrigger <- struct{}{}:
+	default:
+	}
+}
+
+// DiscoveryTriggered returns channel which receives value when discovery is requested by TriggerDiscovery
+func (m *VolumeManager) DiscoveryTriggered() <-chan struct{} {
+	return m.discoveryTrigger
+}
+
 // Discover inspects actual drives structs from DriveManager and create volume object if partition exist on some of them
 // (in case of VolumeManager restart). Updates Drives CRs based on gathered from DriveManager information.
 // Also this method creates AC CRs. Performs at some intervals in a goroutine
 // Returns error if something went wrong during discovering
-func (m *VolumeManager) Discover() error {
-	ctx, cancelFn := context.WithTimeout(context.Background(), DiscoverDrivesTimeout)
+func (m *VolumeManager) Discover(ctx context.Context) error {
+	ctx, cancelFn := context.WithTimeout(ctx, DiscoverDrivesTimeout)
 	defer cancelFn()
 
+	if err := m.faults.Inject(ctx, faults.Discover); err != nil {
+		return err
+	}
 	driveMgrDoneFunc := m.metricDriveMgrDuration.EvaluateDuration(prometheus.Labels{})
 	drivesResponse, err := m.driveMgrClient.GetDrivesList(ctx, &api.DrivesRequest{NodeId: m.nodeID})
 	driveMgrDoneFunc()
@@ -525,26 +792,28 @@ func (m *VolumeManager) Discover() error {
 		return err
 	}
 	m.metricDriveMgrCount.Set(float64(len(drivesResponse.Disks)))
+	crossings := m.telemetry.observe(drivesResponse.Disks)
 
 	updates, err := m.updateDrivesCRs(ctx, drivesResponse.Disks)
 	if err != nil {
-		return fmt.Errorf("updateDrivesCRs return error: %v", err)
+		return fmt.Errorf("updateDrivesCRs return error: %w", err)
 	}
 	m.handleDriveUpdates(ctx, updates)
+	m.createEventsForDriveTemperature(updates, crossings)
 
 	if m.discoverSystemLVG {
-		if err = m.discoverLVGOnSystemDrive(); err != nil {
+		if err = m.discoverLVGOnSystemDrive(ctx); err != nil {
 			m.log.WithField("method", "Discover").
 				Errorf("unable to inspect system LogicalVolumeGroup: %v", err)
 		}
 	}
 
-	if err = m.discoverVolumeCRs(); err != nil {
-		return fmt.Errorf("discoverVolumeCRs return error: %v", err)
+	if err = m.discoverVolumeCRs(ctx); err != nil {
+		return fmt.Errorf("discoverVolumeCRs return error: %w", err)
 	}
 
 	if err = m.discoverAvailableCapacity(ctx); err != nil {
-		return fmt.Errorf("discoverAvailableCapacity return error: %v", err)
+		return fmt.Errorf("discoverAvailableCapacity return error: %w", err)
 	}
 
 	m.initialized = true
@@ -567,7 +836,7 @@ func (m *VolumeManager) updateDrivesCRs(ctx context.Context, drivesFromMgr []*ap
 		err            error
 	)
 
-	if driveCRs, err = m.cachedCrHelper.GetDriveCRs(m.nodeID); err != nil {
+	if driveCRs, err = m.cachedCrHelper.GetDriveCRs(ctx, m.nodeID); err != nil {
 		return nil, err
 	}
 	firstIteration = len(driveCRs) == 0
@@ -585,7 +854,8 @@ func (m *VolumeManager) updateDrivesCRs(ctx context.Context, drivesFromMgr []*ap
 				if searchSystemDrives && driveCR.Spec.IsSystem {
 					m.systemDrivesUUIDs = append(m.systemDrivesUUIDs, driveCR.Spec.UUID)
 				}
-				if driveCR.Equals(drivePtr) {
+				telemetryChanged := m.telemetry.significant(&driveCR.Spec, drivePtr)
+				if driveCR.Equals(drivePtr) && !telemetryChanged {
 					updates.AddNotChanged(&driveCR)
 				} else {
 					previousState := driveCR.DeepCopy()
@@ -593,9 +863,14 @@ func (m *VolumeManager) updateDrivesCRs(ctx context.Context, drivesFromMgr []*ap
 					drivePtr.UUID = driveCR.Spec.UUID
 					drivePtr.Usage = driveCR.Spec.Usage
 					drivePtr.IsSystem = driveCR.Spec.IsSystem
+					// insignificant telemetry changes are exposed by metrics only
+					if !telemetryChanged {
+						drivePtr.Endurance = driveCR.Spec.Endurance
+					}
 
-					toUpdate := driveCR
+					toUpdate := *driveCR.DeepCopy()
 					toUpdate.Spec = *drivePtr
+					toUpdate.RefreshStatus(drivecrd.ReasonDriveMgrUpdate, metav1.Now())
 					if err := m.k8sClient.UpdateCR(ctx, &toUpdate); err != nil {
 						ll.Errorf("Failed to update drive CR (health/status) %v, error %v", toUpdate, err)
 						updates.AddNotChanged(previousState)
@@ -628,9 +903,26 @@ func (m *VolumeManager) updateDrivesCRs(ctx context.Context, drivesFromMgr []*ap
 				m.systemDrivesUUIDs = append(m.systemDrivesUUIDs, toCreateSpec.UUID)
 			}
 			toCreateSpec.IsSystem = isSystem
+			// drive with data which wasn't written by the driver isn't used until it is cleared by operator,
+			// drive which can't be inspected is considered as not clean, it is checked again on next discovery
+			var data string
+			if !isSystem {
+				if data, err = m.findDriveData(drivePtr.Path); err != nil {
+					data = fmt.Sprintf("unable to inspect drive: %v", err)
+				}
+				if data != "" {
+					ll.Warnf("Drive %s isn't clean: %s", drivePtr.SerialNumber, data)
+					toCreateSpec.Usage = apiV1.DriveUsageNotClean
+				}
+			}
 			driveCR := m.k8sClient.ConstructDriveCR(toCreateSpec.UUID, toCreateSpec)
+			driveCR.RefreshStatus(drivecrd.ReasonDiscovered, metav1.Now())
 			if err := m.k8sClient.CreateCR(ctx, driveCR.Name, driveCR); err != nil {
 				ll.Errorf("Failed to create drive CR %v, error: %v", driveCR, err)
+			} else if data != "" {
+				m.sendEventForDrive(driveCR, eventing.WarningType, eventing.DriveNotClean,
+					"Drive holds data (%s), it isn't used until data is removed or %s annotation is set to "+
+						"drive serial number. ", data, apiV1.DriveAnnotationEraseData)
 			}
 			updates.AddCreated(driveCR)
 			driveCRs = append(driveCRs, *driveCR)
@@ -652,16 +944,17 @@ func (m *VolumeManager) updateDrivesCRs(ctx context.Context, drivesFromMgr []*ap
 		}
 
 		if !wasDiscovered {
-			if m.isDriveInLVG(d.Spec) {
+			if m.isDriveInLVG(ctx, d.Spec) {
 				continue
 			}
 
 			ll.Warnf("Set status %s for drive %v", apiV1.DriveStatusOffline, d.Spec)
 			previousState := d.DeepCopy()
-			toUpdate := d
+			toUpdate := *d.DeepCopy()
 			// TODO: which operational status should be in case when there is drive CR that doesn't have corresponding drive from drivemgr response
 			toUpdate.Spec.Status = apiV1.DriveStatusOffline
 			toUpdate.Spec.Health = apiV1.HealthUnknown
+			toUpdate.RefreshStatus(drivecrd.ReasonNotDiscovered, metav1.Now())
 			if err := m.k8sClient.UpdateCR(ctx, &toUpdate); err != nil {
 				ll.Errorf("Failed to update drive CR %v, error %v", toUpdate, err)
 				updates.AddNotChanged(previousState)
@@ -681,8 +974,8 @@ func (m *VolumeManager) handleDriveUpdates(ctx context.Context, updates *driveUp
 }
 
 // isDriveInLVG check whether drive is a part of some LogicalVolumeGroup or no
-func (m *VolumeManager) isDriveInLVG(d api.Drive) bool {
-	lvgs, err := m.cachedCrHelper.GetLVGCRs(m.nodeID)
+func (m *VolumeManager) isDriveInLVG(ctx context.Context, d api.Drive) bool {
+	lvgs, err := m.cachedCrHelper.GetLVGCRs(ctx, m.nodeID)
 	if err != nil {
 		m.log.WithFields(logrus.Fields{
 			"method":    "isDriveInLVG",
@@ -702,12 +995,12 @@ func (m *VolumeManager) isDriveInLVG(d api.Drive) bool {
 // discoverVolumeCRs matches system block devices with driveCRs
 // searches drives in driveCRs that are not have volume and if there are some partitions on them - try to read
 // partition uuid and create volume CR object
-func (m *VolumeManager) discoverVolumeCRs() error {
+func (m *VolumeManager) discoverVolumeCRs(ctx context.Context) error {
 	ll := m.log.WithFields(logrus.Fields{
 		"method": "discoverVolumeCRs",
 	})
 
-	driveCRs, err := m.cachedCrHelper.GetDriveCRs(m.nodeID)
+	driveCRs, err := m.cachedCrHelper.GetDriveCRs(ctx, m.nodeID)
 	if err != nil {
 		return err
 	}
@@ -715,14 +1008,14 @@ func (m *VolumeManager) discoverVolumeCRs() error {
 	// explore each drive from driveCRs
 	blockDevices, err := m.listBlk.GetBlockDevices("")
 	if err != nil {
-		return fmt.Errorf("unable to get list of block devices: %v", err)
+		return fmt.Errorf("unable to get list of block devices: %w", err)
 	}
 
-	volumeCRs, err := m.cachedCrHelper.GetVolumeCRs(m.nodeID)
+	volumeCRs, err := m.cachedCrHelper.GetVolumeCRs(ctx, m.nodeID)
 	if err != nil {
 		return err
 	}
-	lvgCRs, err := m.cachedCrHelper.GetLVGCRs(m.nodeID)
+	lvgCRs, err := m.cachedCrHelper.GetLVGCRs(ctx, m.nodeID)
 	if err != nil {
 		return err
 	}
@@ -744,7 +1037,11 @@ func (m *VolumeManager) discoverVolumeCRs() error {
 	}
 
 	for _, drive := range driveCRs {
-		if drive.Spec.IsSystem && m.isDriveInLVG(drive.Spec) {
+		if drive.Spec.IsSystem && m.isDriveInLVG(ctx, drive.Spec) {
+			continue
+		}
+		// partitions of not clean drive weren't created by the driver
+		if drive.Spec.Usage == apiV1.DriveUsageNotClean {
 			continue
 		}
 		bdev, ok := bdevMap[strings.ToLower(drive.Spec.Path)]
@@ -766,6 +1063,10 @@ func (m *VolumeManager) discoverVolumeCRs() error {
 			if _, ok := locations[drive.Spec.UUID]; ok {
 				continue
 			}
+			// warm partition of deleted scratch volume is reused by next scratch volume
+			if _, ok := drive.Annotations[apiV1.DriveAnnotationScratchPartition]; ok {
+				continue
+			}
 
 			partUUID := bdev.Children[0].PartUUID
 			if partUUID == "" {
@@ -787,7 +1088,7 @@ func (m *VolumeManager) discoverVolumeCRs() error {
 				CSIStatus:    apiV1.Empty,
 			})
 
-			ctxWithID := context.WithValue(context.Background(), base.RequestUUID, volumeCR.Name)
+			ctxWithID := context.WithValue(ctx, base.RequestUUID, volumeCR.Name)
 			if err = m.k8sClient.CreateCR(ctxWithID, volUUID, volumeCR); err != nil {
 				ll.Errorf("Unable to create volume CR %s: %v", volUUID, err)
 			}
@@ -799,19 +1100,20 @@ func (m *VolumeManager) discoverVolumeCRs() error {
 // DiscoverAvailableCapacity inspect current available capacity on nodes and fill AC CRs. This method manages only
 // hardware available capacity such as HDD or SSD. If drive is healthy and online and also it is not used in LVGs
 // and it doesn't contain volume then this drive is in AvailableCapacity CRs.
+// AC isn't created for drive with NOT_CLEAN usage until it is cleared.
 // Returns error if at least one drive from cache was handled badly
 func (m *VolumeManager) discoverAvailableCapacity(ctx context.Context) error {
 	ll := m.log.WithField("method", "discoverAvailableCapacity")
 
-	acs, err := m.cachedCrHelper.GetACCRs(m.nodeID)
+	acs, err := m.cachedCrHelper.GetACCRs(ctx, m.nodeID)
 	if err != nil {
-		return fmt.Errorf("unable to read AC list: %v", err)
+		return fmt.Errorf("unable to read AC list: %w", err)
 	}
-	volumes, err := m.cachedCrHelper.GetVolumeCRs(m.nodeID)
+	volumes, err := m.cachedCrHelper.GetVolumeCRs(ctx, m.nodeID)
 	if err != nil {
 		return err
 	}
-	driveCRs, err := m.cachedCrHelper.GetDriveCRs(m.nodeID)
+	driveCRs, err := m.cachedCrHelper.GetDriveCRs(ctx, m.nodeID)
 	if err != nil {
 		return err
 	}
@@ -836,6 +1138,26 @@ func (m *VolumeManager) discoverAvailableCapacity(ctx context.Context) error {
 			// AC that points on such drive was removed before (if they had existed)
 			continue
 		}
+		if drive.Spec.Usage == apiV1.DriveUsageNotClean {
+			drive := drive
+			if !m.clearDrive(ctx, &drive) {
+				continue
+			}
+		}
+		if drive.IsHotSpare() {
+			if _, volumeExist := volumeLocations[drive.Spec.UUID]; !volumeExist {
+				// hot spare is excluded from allocation until it is promoted
+				if ac, acExist := acsLocations[drive.Spec.UUID]; acExist {
+					ll.Infof("Removing AC %s based on hot spare drive %s", ac.Name, drive.Name)
+					if err = m.k8sClient.DeleteCR(ctx, ac); err != nil {
+						ll.Errorf("Unable to delete AC CR %s: %v", ac.Name, err)
+						wasError = true
+					}
+				}
+				continue
+			}
+			ll.Warnf("Drive %s is designated as hot spare but it has volumes, designation is ignored", drive.Name)
+		}
 		// check whether there is Volume CR that points on same drive
 		if _, volumeExist := volumeLocations[drive.Spec.UUID]; volumeExist {
 			// check whether appropriate AC exists or not
@@ -851,7 +1173,11 @@ func (m *VolumeManager) discoverAvailableCapacity(ctx context.Context) error {
 				continue
 			}
 		}
-		if _, acExist := acsLocations[drive.Spec.UUID]; acExist {
+		if ac, acExist := acsLocations[drive.Spec.UUID]; acExist {
+			// drive could be reported smaller than AC, e.g. after size is read exactly instead of rounded by lsscsi
+			if ac.Spec.Size > drive.Spec.Size {
+				m.shrinkAC(ctx, ac, drive.Spec.Size)
+			}
 			continue
 		}
 
@@ -868,7 +1194,7 @@ func (m *VolumeManager) discoverAvailableCapacity(ctx context.Context) error {
 		}
 
 		if drive.Spec.IsSystem {
-			if m.isDriveInLVG(drive.Spec) {
+			if m.isDriveInLVG(ctx, drive.Spec) {
 				capacity.Size = 0
 			}
 		}
@@ -890,11 +1216,36 @@ func (m *VolumeManager) discoverAvailableCapacity(ctx context.Context) error {
 	return nil
 }
 
+// shrinkAC reduces size of drive AC to the size of the drive
+// AC which is held by reservation isn't changed, since capacity could be already promised to the volume,
+// it is shrunk on the next discovery after reservation is released
+func (m *VolumeManager) shrinkAC(ctx context.Context, ac *accrd.AvailableCapacity, size int64) {
+	ll := m.log.WithField("method", "shrinkAC")
+
+	acrList := &acrcrd.AvailableCapacityReservationList{}
+	if err := m.k8sClient.ReadList(ctx, acrList); err != nil {
+		ll.Errorf("Unable to read reservations of AC %s: %v", ac.Name, err)
+		return
+	}
+	for _, acr := range acrList.Items {
+		if util.ContainsString(acr.Spec.Reservations, ac.Name) {
+			ll.Warnf("AC %s is larger than drive %s but it is reserved by %s, it isn't shrunk until reservation is released",
+				ac.Name, ac.Spec.Location, acr.Name)
+			return
+		}
+	}
+	ll.Infof("Shrink AC %s from %d to %d bytes according to size of drive %s", ac.Name, ac.Spec.Size, size, ac.Spec.Location)
+	ac.Spec.Size = size
+	if err := m.k8sClient.UpdateCR(ctx, ac); err != nil {
+		ll.Errorf("Unable to update size of AC %s: %v", ac.Name, err)
+	}
+}
+
 // discoverLVGOnSystemDrive discovers LogicalVolumeGroup configuration on system SSD drive and creates LogicalVolumeGroup CR and AC CR,
 // return nil in case of success. If system drive is not SSD or LogicalVolumeGroup CR that points in system VG is exists - return nil.
 // If system VG free space is less then threshold - AC CR will not be created but LogicalVolumeGroup will.
 // Returns error in case of error on any step
-func (m *VolumeManager) discoverLVGOnSystemDrive() error {
+func (m *VolumeManager) discoverLVGOnSystemDrive(ctx context.Context) error {
 	ll := m.log.WithField("method", "discoverLVGOnSystemDrive")
 
 	if len(m.systemDrivesUUIDs) == 0 {
@@ -912,7 +1263,7 @@ func (m *VolumeManager) discoverLVGOnSystemDrive() error {
 	)
 
 	// 1. check whether LogicalVolumeGroup CR that holds info about LogicalVolumeGroup configuration on the system drive exists or not
-	lvgs, err := m.cachedCrHelper.GetLVGCRs(m.nodeID)
+	lvgs, err := m.cachedCrHelper.GetLVGCRs(ctx, m.nodeID)
 	if err != nil {
 		return err
 	}
@@ -922,20 +1273,20 @@ func (m *VolumeManager) discoverLVGOnSystemDrive() error {
 				return err
 			}
 			ll.Infof("LogicalVolumeGroup CR that points on system VG is exists: %v", lvg)
-			return m.createACIfFreeSpace(lvg.Name, apiV1.StorageClassSystemLVG, vgFreeSpace)
+			return m.createACIfFreeSpace(ctx, lvg.Name, apiV1.StorageClassSystemLVG, vgFreeSpace)
 		}
 	}
 
 	// 2. check whether there is LogicalVolumeGroup configuration on the system drive or not
 	var driveCR = new(drivecrd.Drive)
 	// TODO: handle situation when there is more then one system drive
-	if err = m.k8sCache.ReadCR(context.Background(), m.systemDrivesUUIDs[0], "", driveCR); err != nil {
+	if err = m.k8sCache.ReadCR(ctx, m.systemDrivesUUIDs[0], "", driveCR); err != nil {
 		return err
 	}
 
 	pvs, err := m.lvmOps.GetAllPVs()
 	if err != nil {
-		return fmt.Errorf("unable to list PVs on the system: %v", err)
+		return fmt.Errorf("unable to list PVs on the system: %w", err)
 	}
 
 	var systemPVName string
@@ -958,15 +1309,15 @@ func (m *VolumeManager) discoverLVGOnSystemDrive() error {
 	var vgName string
 	vgName, err = m.lvmOps.GetVGNameByPVName(systemPVName)
 	if err != nil {
-		return fmt.Errorf("unable to detect system VG name: %v", err)
+		return fmt.Errorf("unable to detect system VG name: %w", err)
 	}
 
 	if vgFreeSpace, err = m.lvmOps.GetVgFreeSpace(vgName); err != nil {
-		return fmt.Errorf("unable to determine VG %s free space: %v", vgName, err)
+		return fmt.Errorf("unable to determine VG %s free space: %w", vgName, err)
 	}
 	lvs, err := m.lvmOps.GetLVsInVG(vgName)
 	if err != nil {
-		return fmt.Errorf("unable to determine LVs in system VG %s: %v", vgName, err)
+		return fmt.Errorf("unable to determine LVs in system VG %s: %w", vgName, err)
 	}
 
 	// 5. create LogicalVolumeGroup CR
@@ -982,12 +1333,12 @@ func (m *VolumeManager) discoverLVGOnSystemDrive() error {
 			Health:     apiV1.HealthGood,
 		}
 		vgCR = m.k8sClient.ConstructLVGCR(vgCRName, vg)
-		ctx  = context.WithValue(context.Background(), base.RequestUUID, vg.Name)
 	)
+	ctx = context.WithValue(ctx, base.RequestUUID, vg.Name)
 	if err = m.k8sClient.CreateCR(ctx, vg.Name, vgCR); err != nil {
-		return fmt.Errorf("unable to create LogicalVolumeGroup CR %v, error: %v", vgCR, err)
+		return fmt.Errorf("unable to create LogicalVolumeGroup CR %v, error: %w", vgCR, err)
 	}
-	return m.createACIfFreeSpace(vgCRName, apiV1.StorageClassSystemLVG, vgFreeSpace)
+	return m.createACIfFreeSpace(ctx, vgCRName, apiV1.StorageClassSystemLVG, vgFreeSpace)
 }
 
 // getProvisionerForVolume returns appropriate Provisioner implementation for volume
@@ -1013,7 +1364,7 @@ func (m *VolumeManager) handleDriveStatusChange(ctx context.Context, drive *api.
 	// Handle resources without LogicalVolumeGroup
 	// Remove AC based on disk with health BAD, SUSPECT, UNKNOWN
 	if drive.Health != apiV1.HealthGood || drive.Status == apiV1.DriveStatusOffline {
-		if ac, err := m.cachedCrHelper.GetACByLocation(drive.UUID); err == nil {
+		if ac, err := m.cachedCrHelper.GetACByLocation(ctx, drive.UUID); err == nil {
 			ll.Infof("Removing AC %s based on unhealthy location %s", ac.Name, ac.Spec.Location)
 			if err := m.k8sClient.DeleteCR(ctx, ac); err != nil {
 				ll.Errorf("Failed to delete unhealthy available capacity CR: %v", err)
@@ -1021,6 +1372,12 @@ func (m *VolumeManager) handleDriveStatusChange(ctx context.Context, drive *api.
 		}
 	}
 	lvg, err := m.cachedCrHelper.GetLVGByDrive(ctx, drive.UUID)
+	// SUSPECT drive of LogicalVolumeGroup with several drives is evacuated by drive controller without downtime,
+	// LogicalVolumeGroup and its volumes inherit drive's health only if evacuation fails
+	if lvg != nil && drive.Health == apiV1.HealthSuspect && len(lvg.Spec.Locations) > 1 {
+		ll.Infof("Drive will be evacuated from LogicalVolumeGroup %s", lvg.Name)
+		return
+	}
 	if lvg != nil {
 		lvg.Spec.Health = drive.Health
 		if err := m.k8sClient.UpdateCR(ctx, lvg); err != nil {
@@ -1074,7 +1431,7 @@ func (m *VolumeManager) drivesAreTheSame(drive1, drive2 *api.Drive) bool {
 // createACIfFreeSpace creates AC CR if there is a free space on drive
 // Receive context, drive location, storage class, size of available capacity
 // Return error
-func (m *VolumeManager) createACIfFreeSpace(location string, sc string, size int64) error {
+func (m *VolumeManager) createACIfFreeSpace(ctx context.Context, location string, sc string, size int64) error {
 	ll := m.log.WithFields(logrus.Fields{
 		"method": "createACIfFreeSpace",
 	})
@@ -1082,7 +1439,7 @@ func (m *VolumeManager) createACIfFreeSpace(location string, sc string, size int
 		size++ // if size is 0 it field will not display for CR
 	}
 	// check whether AC exists
-	if ac, _ := m.cachedCrHelper.GetACByLocation(location); ac != nil {
+	if ac, _ := m.cachedCrHelper.GetACByLocation(ctx, location); ac != nil {
 		return nil
 	}
 
@@ -1094,8 +1451,8 @@ func (m *VolumeManager) createACIfFreeSpace(location string, sc string, size int
 			StorageClass: sc,
 			Size:         size,
 		})
-		if err := m.k8sClient.CreateCR(context.Background(), acName, acCR); err != nil {
-			return fmt.Errorf("unable to create AC based on system LogicalVolumeGroup, error: %v", err)
+		if err := m.k8sClient.CreateCR(ctx, acName, acCR); err != nil {
+			return fmt.Errorf("unable to create AC based on system LogicalVolumeGroup, error: %w", err)
 		}
 		ll.Infof("Created AC %v for lvg %s", acCR, location)
 		return nil
@@ -1112,8 +1469,10 @@ func (m *VolumeManager) createEventsForDriveUpdates(updates *driveUpdates) {
 			createdDrive.Spec.SerialNumber, createdDrive.Spec.NodeId)
 		m.createEventForDriveHealthChange(
 			createdDrive, apiV1.HealthUnknown, createdDrive.Spec.Health)
+		m.createEventsForSELRecords(createdDrive, nil)
 	}
 	for _, updDrive := range updates.Updated {
+		m.createEventsForSELRecords(updDrive.CurrentState, updDrive.PreviousState.Spec.SELEvents)
 		if updDrive.CurrentState.Spec.Health != updDrive.PreviousState.Spec.Health {
 			m.createEventForDriveHealthChange(
 				updDrive.CurrentState, updDrive.PreviousState.Spec.Health, updDrive.CurrentState.Spec.Health)
@@ -1125,6 +1484,43 @@ func (m *VolumeManager) createEventsForDriveUpdates(updates *driveUpdates) {
 	}
 }
 
+// createEventsForSELRecords creates events for IPMI SEL records of the drive which weren't reported previously
+func (m *VolumeManager) createEventsForSELRecords(drive *drivecrd.Drive, previous []string) {
+	known := make(map[string]bool, len(previous))
+	for _, record := range previous {
+		known[record] = true
+	}
+	for _, record := range drive.Spec.SELEvents {
+		if !known[record] {
+			m.sendEventForDrive(drive, eventing.WarningType, eventing.DriveSELEvent, "IPMI SEL record: %s.", record)
+		}
+	}
+}
+
+// createEventsForDriveTemperature creates events for drives which temperature crossed threshold
+func (m *VolumeManager) createEventsForDriveTemperature(updates *driveUpdates, crossings map[string]thresholdCrossing) {
+	if len(crossings) == 0 {
+		return
+	}
+	drives := append(append([]*drivecrd.Drive{}, updates.Created...), updates.NotChanged...)
+	for _, updDrive := range updates.Updated {
+		drives = append(drives, updDrive.CurrentState)
+	}
+	for _, drive := range drives {
+		crossing, ok := crossings[drive.Spec.SerialNumber]
+		if !ok {
+			continue
+		}
+		if crossing.overheated {
+			m.sendEventForDrive(drive, eventing.WarningType, eventing.DriveTemperatureHigh,
+				"Drive temperature %dC exceeds threshold %dC.", crossing.temperature, m.telemetry.temperatureThreshold)
+		} else {
+			m.sendEventForDrive(drive, eventing.NormalType, eventing.DriveTemperatureNormal,
+				"Drive temperature %dC is below threshold %dC.", crossing.temperature, m.telemetry.temperatureThreshold)
+		}
+	}
+}
+
 func (m *VolumeManager) createEventForDriveHealthChange(
 	drive *drivecrd.Drive, prevHealth, currentHealth string) {
 	healthMsgTemplate := "Drive health is: %s, previous state: %s."
@@ -1144,6 +1540,14 @@ func (m *VolumeManager) createEventForDriveHealthChange(
 	default:
 		return
 	}
+	// reason of the health is known if drive manager serves v2 API
+	if provider, ok := m.driveMgrClient.(healthDetailsProvider); ok && currentHealth != apiV1.HealthGood {
+		if details := provider.HealthDetails(drive.Spec.SerialNumber); details.GetReason() != "" {
+			m.sendEventForDrive(drive, eventType, reason, healthMsgTemplate+" Reason: %s.",
+				currentHealth, prevHealth, details.GetReason())
+			return
+		}
+	}
 	m.sendEventForDrive(drive, eventType, reason,
 		healthMsgTemplate, currentHealth, prevHealth)
 }
@@ -1188,7 +1592,7 @@ func (m *VolumeManager) isDriveSystem(path string) (bool, error) {
 // Returns true if device has root mountpoint, false in opposite
 func (m *VolumeManager) isRootMountpoint(devs []lsblk.BlockDevice) bool {
 	for _, device := range devs {
-		if strings.TrimSpace(device.MountPoint) == base.KubeletRootPath ||
+		if strings.TrimSpace(device.MountPoint) == filepath.Join(m.kubeletDir, base.KubeletPodsDir) ||
 			strings.HasPrefix(strings.TrimSpace(device.MountPoint), base.HostRootPath) {
 			return true
 		}
@@ -1217,7 +1621,7 @@ func (m *VolumeManager) handleExpandingStatus(ctx context.Context, volume *volum
 	ll := m.log.WithFields(logrus.Fields{
 		"method": "handleExpandingStatus",
 	})
-	volumePath, err := m.provisioners[p.LVMBasedVolumeType].GetVolumePath(volume.Spec)
+	volumePath, err := m.provisioners[p.LVMBasedVolumeType].GetVolumePath(ctx, volume.Spec)
 	if err != nil {
 		ll.Errorf("Failed to get volume path, err: %v", err)
 		return ctrl.Result{Requeue: true}, err
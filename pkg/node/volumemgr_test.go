@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -28,6 +29,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
 	k8sError "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -247,6 +249,75 @@ func TestReconcile_MultipleRequest(t *testing.T) {
 	assert.Equal(t, apiV1.Created, volume.Spec.CSIStatus)
 }
 
+func TestReconcile_VolumeOperationsLimit(t *testing.T) {
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNs, Name: volCR.Name}}
+	kubeClient, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	vm := NewVolumeManager(nil, nil, testLogger, kubeClient, kubeClient, new(mocks.NoOpRecorder), nodeID)
+	vm.SetVolumeOperationsLimit(1)
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{p.DriveBasedVolumeType: mockProv.GetMockProvisionerSuccess("/some/path")})
+
+	testVol := volCR
+	testVol.Spec.CSIStatus = apiV1.Creating
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, testVol.Name, &testVol))
+
+	// another volume is being created
+	vm.opSem <- struct{}{}
+	res, err := vm.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, base.DefaultRequeueForVolume, res.RequeueAfter)
+
+	volume := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, req.Name, testNs, volume))
+	assert.Equal(t, apiV1.Creating, volume.Spec.CSIStatus)
+	assert.Equal(t, corev1.ConditionTrue, volume.GetCondition(vcrd.VolumeConditionPending).Status)
+
+	// slot is released
+	<-vm.opSem
+	res, err = vm.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, req.Name, testNs, volume))
+	assert.Equal(t, apiV1.Created, volume.Spec.CSIStatus)
+	assert.Equal(t, corev1.ConditionFalse, volume.GetCondition(vcrd.VolumeConditionPending).Status)
+	assert.Equal(t, 0, len(vm.opSem))
+
+	// non-positive limit is ignored
+	vm.SetVolumeOperationsLimit(0)
+	assert.Equal(t, 1, cap(vm.opSem))
+}
+
+func TestAcquireOperationSlot_ResetPending(t *testing.T) {
+	kubeClient, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	vm := NewVolumeManager(nil, nil, testLogger, kubeClient, kubeClient, new(mocks.NoOpRecorder), nodeID)
+	vm.SetVolumeOperationsLimit(1)
+
+	testVol := volCR
+	testVol.Spec.CSIStatus = apiV1.Creating
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, testVol.Name, &testVol))
+
+	// another volume is being created
+	vm.opSem <- struct{}{}
+	volume := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testVol.Name, testNs, volume))
+	release, ok := vm.acquireOperationSlot(testCtx, volume)
+	assert.False(t, ok)
+	assert.Nil(t, release)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testVol.Name, testNs, volume))
+	assert.Equal(t, corev1.ConditionTrue, volume.GetCondition(vcrd.VolumeConditionPending).Status)
+
+	// slot is released, Pending is reset while volume is still being created
+	<-vm.opSem
+	release, ok = vm.acquireOperationSlot(testCtx, volume)
+	assert.True(t, ok)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testVol.Name, testNs, volume))
+	assert.Equal(t, apiV1.Creating, volume.Spec.CSIStatus)
+	assert.Equal(t, corev1.ConditionFalse, volume.GetCondition(vcrd.VolumeConditionPending).Status)
+	release()
+	assert.Equal(t, 0, len(vm.opSem))
+}
+
 func TestReconcile_SuccessNotFound(t *testing.T) {
 	kubeClient, err := k8s.GetFakeKubeClient(testNs, testLogger)
 	assert.Nil(t, err)
@@ -550,6 +621,31 @@ func TestReconcile_ReconcileDefaultStatus(t *testing.T) {
 	assert.Equal(t, res, ctrl.Result{})
 }
 
+func TestReconcile_RefreshStatus(t *testing.T) {
+	var (
+		vm     = prepareSuccessVolumeManager(t)
+		req    = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNs, Name: volCR.Name}}
+		volume = volCR
+	)
+	volume.Spec.CSIStatus = apiV1.Published
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, volume.Name, &volume))
+
+	_, err := vm.Reconcile(req)
+	assert.Nil(t, err)
+	refreshed := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, volume.Name, testNs, refreshed))
+	published := refreshed.GetCondition(vcrd.VolumeConditionPublished)
+	assert.NotNil(t, published)
+	assert.Equal(t, corev1.ConditionTrue, published.Status)
+
+	// status is up to date, it isn't written again
+	_, err = vm.Reconcile(req)
+	assert.Nil(t, err)
+	current := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, volume.Name, testNs, current))
+	assert.Equal(t, refreshed.ResourceVersion, current.ResourceVersion)
+}
+
 func TestNewVolumeManager_SetProvisioners(t *testing.T) {
 	vm := NewVolumeManager(nil, mocks.EmptyExecutorSuccess{},
 		logrus.New(), nil, nil, new(mocks.NoOpRecorder), nodeID)
@@ -568,7 +664,7 @@ func TestVolumeManager_DiscoverFail(t *testing.T) {
 		vm = prepareSuccessVolumeManager(t)
 		vm.driveMgrClient = &mocks.MockDriveMgrClientFail{}
 
-		err = vm.Discover()
+		err = vm.Discover(testCtx)
 		assert.NotNil(t, err)
 		assert.Equal(t, "drivemgr error", err.Error())
 	})
@@ -581,7 +677,7 @@ func TestVolumeManager_DiscoverFail(t *testing.T) {
 			nil, testLogger, kubeClient, kubeClient, nil, nodeID)
 		mockK8sClient.On("List", mock.Anything, mock.Anything, mock.Anything).Return(testErr).Once()
 
-		err = vm.Discover()
+		err = vm.Discover(testCtx)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "updateDrivesCRs return error")
 	})
@@ -598,7 +694,7 @@ func TestVolumeManager_DiscoverFail(t *testing.T) {
 		mockK8sClient.On("List", mock.Anything, &lvgcrd.LogicalVolumeGroupList{}, mock.Anything).Return(nil)
 		mockK8sClient.On("List", mock.Anything, &vcrd.VolumeList{}, mock.Anything).Return(testErr)
 
-		err = vm.Discover()
+		err = vm.Discover(testCtx)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "discoverVolumeCRs return error")
 	})
@@ -616,7 +712,7 @@ func TestVolumeManager_DiscoverFail(t *testing.T) {
 		mockK8sClient.On("List", mock.Anything, &lvgcrd.LogicalVolumeGroupList{}, mock.Anything).Return(nil)
 		mockK8sClient.On("List", mock.Anything, &accrd.AvailableCapacityList{}, mock.Anything).Return(testErr).Once()
 		vm.discoverSystemLVG = false
-		err = vm.Discover()
+		err = vm.Discover(testCtx)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "discoverAvailableCapacity return error")
 	})
@@ -635,10 +731,10 @@ func TestVolumeManager_DiscoverSuccess(t *testing.T) {
 	vm.listBlk = listBlk
 
 	listBlk.On("GetBlockDevices", "").Return([]lsblk.BlockDevice{bdev1, bdev2}, nil).Once()
-	listBlk.On("GetBlockDevices", drive1.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Once()
-	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev2}, nil).Once()
+	listBlk.On("GetBlockDevices", drive1.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Twice()
+	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev2}, nil).Twice()
 	// expect that Volume CRs won't be created because of all drives don't have children
-	err = vm.Discover()
+	err = vm.Discover(testCtx)
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(getVolumeCRsListItems(t, vm.k8sClient)))
 	assert.Equal(t, 2, len(getACCRsListItems(t, vm.k8sClient)))
@@ -667,10 +763,10 @@ func TestVolumeManager_Discover_noncleanDisk(t *testing.T) {
 	listBlk := &mocklu.MockWrapLsblk{}
 	vm.listBlk = listBlk
 	listBlk.On("GetBlockDevices", "").Return([]lsblk.BlockDevice{bdev1, bdev2}, nil).Once()
-	listBlk.On("GetBlockDevices", drive1.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Once()
-	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev2}, nil).Once()
+	listBlk.On("GetBlockDevices", drive1.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Twice()
+	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev2}, nil).Twice()
 
-	err := vm.Discover()
+	err := vm.Discover(testCtx)
 	assert.Nil(t, err)
 
 	vItems = getVolumeCRsListItems(t, vm.k8sClient)
@@ -685,7 +781,7 @@ func TestVolumeManager_Discover_noncleanDisk(t *testing.T) {
 	bdev2WithChildren.Children = []lsblk.BlockDevice{{Name: "/dev/sda1", PartUUID: testPartUUID}}
 	listBlk.On("GetBlockDevices", "").Return([]lsblk.BlockDevice{bdev1, bdev2WithChildren}, nil).Once()
 
-	err = vm.Discover()
+	err = vm.Discover(testCtx)
 	assert.Nil(t, err)
 
 	vItems = getVolumeCRsListItems(t, vm.k8sClient)
@@ -720,10 +816,10 @@ func TestVolumeManager_DiscoverAvailableCapacitySuccess(t *testing.T) {
 	listBlk := &mocklu.MockWrapLsblk{}
 	vm.listBlk = listBlk
 	listBlk.On("GetBlockDevices", "").Return([]lsblk.BlockDevice{bdev1, bdev2}, nil).Once()
-	listBlk.On("GetBlockDevices", drive1.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Once()
-	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev2}, nil).Once()
+	listBlk.On("GetBlockDevices", drive1.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Twice()
+	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev2}, nil).Twice()
 
-	err := vm.Discover()
+	err := vm.Discover(testCtx)
 	assert.Nil(t, err)
 
 	assert.Nil(t, err)
@@ -744,10 +840,10 @@ func TestVolumeManager_DiscoverAvailableCapacityDriveUnhealthy(t *testing.T) {
 	vm.driveMgrClient = mocks.NewMockDriveMgrClient(getDriveMgrRespBasedOnDrives(drive1, d2))
 	vm.listBlk = listBlk
 	listBlk.On("GetBlockDevices", "").Return([]lsblk.BlockDevice{bdev1, bdev2}, nil).Once()
-	listBlk.On("GetBlockDevices", drive1.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Once()
-	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev2}, nil).Once()
+	listBlk.On("GetBlockDevices", drive1.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Twice()
+	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev2}, nil).Twice()
 
-	err = vm.Discover()
+	err = vm.Discover(testCtx)
 	assert.Nil(t, err)
 
 	acList := &accrd.AvailableCapacityList{}
@@ -757,6 +853,52 @@ func TestVolumeManager_DiscoverAvailableCapacityDriveUnhealthy(t *testing.T) {
 	assert.Equal(t, 1, len(acList.Items))
 }
 
+func TestVolumeManager_DiscoverAvailableCapacityHotSpare(t *testing.T) {
+	d1, d2 := drive1, drive2
+	vm := prepareSuccessVolumeManagerWithDrives([]*api.Drive{&d1, &d2}, t)
+
+	spare := &drivecrd.Drive{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, d2.UUID, "", spare))
+	spare.Annotations = map[string]string{apiV1.DriveAnnotationHotSpare: apiV1.DriveAnnotationHotSpareEnabled}
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, spare))
+	// AC was created before drive was designated as hot spare
+	spareAC := vm.k8sClient.ConstructACCR("spare-ac", api.AvailableCapacity{Location: d2.UUID, NodeId: nodeID, Size: d2.Size})
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, spareAC.Name, spareAC))
+
+	assert.Nil(t, vm.discoverAvailableCapacity(testCtx))
+	acs := getACCRsListItems(t, vm.k8sClient)
+	assert.Len(t, acs, 1)
+	assert.Equal(t, d1.UUID, acs[0].Spec.Location)
+
+	// promoted hot spare is available for allocation
+	delete(spare.Annotations, apiV1.DriveAnnotationHotSpare)
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, spare))
+	assert.Nil(t, vm.discoverAvailableCapacity(testCtx))
+	assert.Len(t, getACCRsListItems(t, vm.k8sClient), 2)
+}
+
+func TestVolumeManager_DiscoverAvailableCapacityShrink(t *testing.T) {
+	d1, d2 := drive1, drive2
+	vm := prepareSuccessVolumeManagerWithDrives([]*api.Drive{&d1, &d2}, t)
+
+	// ACs were created when drives were reported larger
+	ac1 := vm.k8sClient.ConstructACCR("ac-1", api.AvailableCapacity{Location: d1.UUID, NodeId: nodeID, Size: d1.Size * 2})
+	ac2 := vm.k8sClient.ConstructACCR("ac-2", api.AvailableCapacity{Location: d2.UUID, NodeId: nodeID, Size: d2.Size * 2})
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, ac1.Name, ac1))
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, ac2.Name, ac2))
+	acr := vm.k8sClient.ConstructACRCR(api.AvailableCapacityReservation{Name: "acr", Reservations: []string{ac2.Name}})
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, acr.Name, acr))
+
+	assert.Nil(t, vm.discoverAvailableCapacity(testCtx))
+	sizes := make(map[string]int64)
+	for _, ac := range getACCRsListItems(t, vm.k8sClient) {
+		sizes[ac.Name] = ac.Spec.Size
+	}
+	assert.Equal(t, d1.Size, sizes[ac1.Name])
+	// reserved AC isn't changed
+	assert.Equal(t, d2.Size*2, sizes[ac2.Name])
+}
+
 func TestVolumeManager_updatesDrivesCRs_Success(t *testing.T) {
 	vm := prepareSuccessVolumeManager(t)
 	driveMgrRespDrives := getDriveMgrRespBasedOnDrives(drive1, drive2)
@@ -764,7 +906,7 @@ func TestVolumeManager_updatesDrivesCRs_Success(t *testing.T) {
 
 	updates, err := vm.updateDrivesCRs(testCtx, driveMgrRespDrives)
 	assert.Nil(t, err)
-	driveCRs, err := vm.crHelper.GetDriveCRs(vm.nodeID)
+	driveCRs, err := vm.crHelper.GetDriveCRs(testCtx, vm.nodeID)
 	assert.Nil(t, err)
 	assert.Equal(t, len(driveCRs), 2)
 	assert.Len(t, updates.Created, 2)
@@ -772,25 +914,34 @@ func TestVolumeManager_updatesDrivesCRs_Success(t *testing.T) {
 	driveMgrRespDrives[0].Health = apiV1.HealthBad
 	updates, err = vm.updateDrivesCRs(testCtx, driveMgrRespDrives)
 	assert.Nil(t, err)
-	assert.Equal(t, vm.crHelper.GetDriveCRByUUID(driveMgrRespDrives[0].UUID).Spec.Health, apiV1.HealthBad)
+	driveCR := vm.crHelper.GetDriveCRByUUID(testCtx, driveMgrRespDrives[0].UUID)
+	assert.Equal(t, driveCR.Spec.Health, apiV1.HealthBad)
+	assert.Equal(t, corev1.ConditionFalse, driveCR.GetCondition(drivecrd.DriveConditionHealthy).Status)
+	assert.Equal(t, corev1.ConditionTrue, driveCR.GetCondition(drivecrd.DriveConditionFailed).Status)
+	assert.Equal(t, drivecrd.ReasonDriveMgrUpdate, driveCR.GetCondition(drivecrd.DriveConditionFailed).Reason)
+	assert.Len(t, driveCR.Status.History, 2)
 	assert.Len(t, updates.Updated, 1)
 	assert.Len(t, updates.NotChanged, 1)
 
 	drives := driveMgrRespDrives[1:]
 	updates, err = vm.updateDrivesCRs(testCtx, drives)
 	assert.Nil(t, err)
-	assert.Equal(t, vm.crHelper.GetDriveCRByUUID(driveMgrRespDrives[0].UUID).Spec.Health, apiV1.HealthUnknown)
-	assert.Equal(t, vm.crHelper.GetDriveCRByUUID(driveMgrRespDrives[0].UUID).Spec.Status, apiV1.DriveStatusOffline)
+	driveCR = vm.crHelper.GetDriveCRByUUID(testCtx, driveMgrRespDrives[0].UUID)
+	assert.Equal(t, driveCR.Spec.Health, apiV1.HealthUnknown)
+	assert.Equal(t, driveCR.Spec.Status, apiV1.DriveStatusOffline)
+	assert.Equal(t, corev1.ConditionFalse, driveCR.GetCondition(drivecrd.DriveConditionDiscovered).Status)
+	assert.Equal(t, corev1.ConditionFalse, driveCR.GetCondition(drivecrd.DriveConditionFailed).Status)
+	assert.Equal(t, drivecrd.ReasonNotDiscovered, driveCR.Status.History[2].Reason)
 	assert.Len(t, updates.Updated, 1)
 	assert.Len(t, updates.NotChanged, 1)
 
 	vm = prepareSuccessVolumeManager(t)
-	driveCRs, err = vm.crHelper.GetDriveCRs(vm.nodeID)
+	driveCRs, err = vm.crHelper.GetDriveCRs(testCtx, vm.nodeID)
 	assert.Nil(t, err)
 	assert.Empty(t, driveCRs)
 	updates, err = vm.updateDrivesCRs(testCtx, driveMgrRespDrives)
 	assert.Nil(t, err)
-	driveCRs, err = vm.crHelper.GetDriveCRs(vm.nodeID)
+	driveCRs, err = vm.crHelper.GetDriveCRs(testCtx, vm.nodeID)
 	assert.Nil(t, err)
 	assert.Equal(t, len(driveCRs), 2)
 	assert.Len(t, updates.Created, 2)
@@ -804,7 +955,7 @@ func TestVolumeManager_updatesDrivesCRs_Success(t *testing.T) {
 	})
 	updates, err = vm.updateDrivesCRs(testCtx, driveMgrRespDrives)
 	assert.Nil(t, err)
-	driveCRs, err = vm.crHelper.GetDriveCRs(vm.nodeID)
+	driveCRs, err = vm.crHelper.GetDriveCRs(testCtx, vm.nodeID)
 	assert.Nil(t, err)
 	assert.Equal(t, len(driveCRs), 3)
 	assert.Len(t, updates.Created, 1)
@@ -820,11 +971,143 @@ func TestVolumeManager_updatesDrivesCRs_Success(t *testing.T) {
 	})
 	updates, err = vm.updateDrivesCRs(testCtx, driveMgrRespDrives)
 	assert.Nil(t, err)
-	driveCRs, err = vm.crHelper.GetDriveCRs(vm.nodeID)
+	driveCRs, err = vm.crHelper.GetDriveCRs(testCtx, vm.nodeID)
 	assert.Nil(t, err)
 	assert.Equal(t, len(driveCRs), 3)
 }
 
+func TestVolumeManager_updatesDrivesCRs_EnduranceHysteresis(t *testing.T) {
+	vm := prepareSuccessVolumeManager(t)
+	getDriveCR := func(t *testing.T, vm *VolumeManager) drivecrd.Drive {
+		driveCRs, err := vm.crHelper.GetDriveCRs(testCtx, vm.nodeID)
+		assert.Nil(t, err)
+		assert.Len(t, driveCRs, 1)
+		return driveCRs[0]
+	}
+	driveMgrRespDrives := getDriveMgrRespBasedOnDrives(drive1)
+	driveMgrRespDrives[0].Endurance = 90
+
+	_, err := vm.updateDrivesCRs(testCtx, driveMgrRespDrives)
+	assert.Nil(t, err)
+
+	// change is less than hysteresis, CR isn't updated
+	driveMgrRespDrives[0].Endurance = 90 - DefaultEnduranceHysteresis + 1
+	updates, err := vm.updateDrivesCRs(testCtx, driveMgrRespDrives)
+	assert.Nil(t, err)
+	assert.Len(t, updates.NotChanged, 1)
+	driveCR := getDriveCR(t, vm)
+	assert.Equal(t, int64(90), driveCR.Spec.Endurance)
+
+	// insignificant change is not written together with other fields
+	driveMgrRespDrives[0].Health = apiV1.HealthSuspect
+	updates, err = vm.updateDrivesCRs(testCtx, driveMgrRespDrives)
+	assert.Nil(t, err)
+	assert.Len(t, updates.Updated, 1)
+	driveCR = getDriveCR(t, vm)
+	assert.Equal(t, apiV1.HealthSuspect, driveCR.Spec.Health)
+	assert.Equal(t, int64(90), driveCR.Spec.Endurance)
+
+	// significant change is written
+	driveMgrRespDrives[0].Endurance = 90 - DefaultEnduranceHysteresis
+	updates, err = vm.updateDrivesCRs(testCtx, driveMgrRespDrives)
+	assert.Nil(t, err)
+	assert.Len(t, updates.Updated, 1)
+	driveCR = getDriveCR(t, vm)
+	assert.Equal(t, int64(90-DefaultEnduranceHysteresis), driveCR.Spec.Endurance)
+
+	// every change is written with zero hysteresis
+	vm.SetEnduranceHysteresis(0)
+	driveMgrRespDrives[0].Endurance--
+	updates, err = vm.updateDrivesCRs(testCtx, driveMgrRespDrives)
+	assert.Nil(t, err)
+	assert.Len(t, updates.Updated, 1)
+}
+
+func TestVolumeManager_createEventsForDriveTemperature(t *testing.T) {
+	vm := prepareSuccessVolumeManager(t)
+	rec := &mocks.NoOpRecorder{}
+	vm.recorder = rec
+	listBlk := &mocklu.MockWrapLsblk{}
+	listBlk.On("GetBlockDevices", drive1.Path).Return([]lsblk.BlockDevice{bdev1}, nil)
+	vm.listBlk = listBlk
+
+	driveMgrRespDrives := getDriveMgrRespBasedOnDrives(drive1)
+	discover := func(temperature int32) {
+		driveMgrRespDrives[0].Temperature = temperature
+		crossings := vm.telemetry.observe(driveMgrRespDrives)
+		updates, err := vm.updateDrivesCRs(testCtx, driveMgrRespDrives)
+		assert.Nil(t, err)
+		vm.createEventsForDriveTemperature(updates, crossings)
+	}
+	lastReason := func() string {
+		if len(rec.Calls) == 0 {
+			return ""
+		}
+		return rec.Calls[len(rec.Calls)-1].Reason
+	}
+
+	discover(DefaultDriveTemperatureThreshold - 1)
+	assert.Empty(t, rec.Calls)
+
+	discover(DefaultDriveTemperatureThreshold)
+	assert.Len(t, rec.Calls, 1)
+	assert.Equal(t, eventing.DriveTemperatureHigh, lastReason())
+
+	// drive is still overheated within hysteresis
+	discover(DefaultDriveTemperatureThreshold - temperatureHysteresis + 1)
+	assert.Len(t, rec.Calls, 1)
+
+	discover(DefaultDriveTemperatureThreshold - temperatureHysteresis)
+	assert.Len(t, rec.Calls, 2)
+	assert.Equal(t, eventing.DriveTemperatureNormal, lastReason())
+
+	// events are disabled with zero threshold
+	vm.SetDriveTemperatureThreshold(0)
+	discover(DefaultDriveTemperatureThreshold + 10)
+	assert.Len(t, rec.Calls, 2)
+}
+
+func TestVolumeManager_createEventsForSELRecords(t *testing.T) {
+	vm := prepareSuccessVolumeManager(t)
+	rec := &mocks.NoOpRecorder{}
+	vm.recorder = rec
+	listBlk := &mocklu.MockWrapLsblk{}
+	listBlk.On("GetBlockDevices", drive1.Path).Return([]lsblk.BlockDevice{bdev1}, nil)
+	vm.listBlk = listBlk
+
+	driveMgrRespDrives := getDriveMgrRespBasedOnDrives(drive1)
+	selEvents := func() int {
+		count := 0
+		for _, call := range rec.Calls {
+			if call.Reason == eventing.DriveSELEvent {
+				count++
+			}
+		}
+		return count
+	}
+	discover := func(records ...string) {
+		driveMgrRespDrives[0].SELEvents = records
+		updates, err := vm.updateDrivesCRs(testCtx, driveMgrRespDrives)
+		assert.Nil(t, err)
+		vm.createEventsForDriveUpdates(updates)
+	}
+
+	discover("10/14/2026 09:12:44 | Drive Slot / Bay Drive 1 | Drive Fault | Asserted")
+	assert.Equal(t, 1, selEvents())
+
+	// only new records are raised
+	discover("10/14/2026 09:12:44 | Drive Slot / Bay Drive 1 | Drive Fault | Asserted",
+		"10/14/2026 09:20:00 | Drive Slot / Bay Drive 1 | Drive Fault | Deasserted")
+	assert.Equal(t, 2, selEvents())
+	discover("10/14/2026 09:12:44 | Drive Slot / Bay Drive 1 | Drive Fault | Asserted",
+		"10/14/2026 09:20:00 | Drive Slot / Bay Drive 1 | Drive Fault | Deasserted")
+	assert.Equal(t, 2, selEvents())
+
+	drives, err := vm.crHelper.GetDriveCRs(testCtx, nodeID)
+	assert.Nil(t, err)
+	assert.Len(t, drives[0].Spec.SELEvents, 2)
+}
+
 func TestVolumeManager_updatesDrivesCRs_Fail(t *testing.T) {
 	mockK8sClient := &mocks.K8Client{}
 	kubeClient := k8s.NewKubeClient(mockK8sClient, testLogger, testNs)
@@ -896,6 +1179,31 @@ func TestVolumeManager_handleDriveStatusChange(t *testing.T) {
 	assert.Equal(t, apiV1.HealthBad, updatedLVG.Spec.Health)
 }
 
+func TestVolumeManager_handleDriveStatusChange_SuspectLVGDrive(t *testing.T) {
+	vm := prepareSuccessVolumeManagerWithDrives(nil, t)
+
+	lvg := testLVGCR
+	lvg.Spec.Locations = []string{"another-drive", driveUUID}
+	lvg.Spec.Health = apiV1.HealthGood
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, testLVGName, &lvg))
+	vol := volCR
+	vol.Spec.Location = testLVGName
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, testID, &vol))
+
+	drive := drive1
+	drive.UUID = driveUUID
+	drive.Health = apiV1.HealthSuspect
+
+	// drive is evacuated by drive controller, LogicalVolumeGroup and volume aren't changed
+	vm.handleDriveStatusChange(testCtx, &drive)
+	updatedLVG := &lvgcrd.LogicalVolumeGroup{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testLVGName, "", updatedLVG))
+	assert.Equal(t, apiV1.HealthGood, updatedLVG.Spec.Health)
+	rVolume := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, testID, volCR.Namespace, rVolume))
+	assert.Equal(t, vol.Spec.Usage, rVolume.Spec.Usage)
+}
+
 func Test_discoverLVGOnSystemDrive_LVGAlreadyExists(t *testing.T) {
 	var (
 		m     = prepareSuccessVolumeManager(t)
@@ -916,7 +1224,7 @@ func Test_discoverLVGOnSystemDrive_LVGAlreadyExists(t *testing.T) {
 	err = m.k8sClient.CreateCR(testCtx, lvgCR.Name, lvgCR)
 	assert.Nil(t, err)
 
-	err = m.discoverLVGOnSystemDrive()
+	err = m.discoverLVGOnSystemDrive(testCtx)
 	assert.Nil(t, err)
 
 	err = m.k8sClient.ReadList(testCtx, &lvgList)
@@ -936,7 +1244,7 @@ func Test_discoverLVGOnSystemDrive_LVGAlreadyExists(t *testing.T) {
 	err = m.k8sClient.CreateCR(testCtx, lvgCR.Name, lvgCR)
 	assert.Nil(t, err)
 
-	err = m.discoverLVGOnSystemDrive()
+	err = m.discoverLVGOnSystemDrive(testCtx)
 	assert.Nil(t, err)
 
 	err = m.k8sClient.ReadList(testCtx, &lvgList)
@@ -977,7 +1285,7 @@ func Test_discoverLVGOnSystemDrive_LVGCreatedACNo(t *testing.T) {
 
 	// expect success, LogicalVolumeGroup CR and AC CR was created
 	m.systemDrivesUUIDs = append(m.systemDrivesUUIDs, systemDriveCR.Spec.UUID)
-	err = m.discoverLVGOnSystemDrive()
+	err = m.discoverLVGOnSystemDrive(testCtx)
 	assert.Nil(t, err)
 
 	err = m.k8sClient.ReadList(testCtx, &lvgList)
@@ -1005,7 +1313,7 @@ func Test_discoverLVGOnSystemDrive_LVGCreatedACNo(t *testing.T) {
 	assert.Nil(t, m.k8sClient.CreateCR(testCtx, systemDriveCR.Name, &systemDriveCR))
 	m.systemDrivesUUIDs = append(m.systemDrivesUUIDs, systemDriveCR.Spec.UUID)
 
-	err = m.discoverLVGOnSystemDrive()
+	err = m.discoverLVGOnSystemDrive(testCtx)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unable to determine LVs in system VG")
 
@@ -1085,6 +1393,38 @@ func TestVolumeManager_createEventsForDriveUpdates(t *testing.T) {
 		assert.True(t, expectEvent(drive1CR, eventing.ErrorType, eventing.DriveStatusOffline))
 		assert.True(t, expectEvent(drive1CR, eventing.WarningType, eventing.DriveHealthUnknown))
 	})
+
+	t.Run("Health reason is reported by v2 API", func(t *testing.T) {
+		init()
+		mgr.driveMgrClient = &healthDetailsDriveMgrClient{
+			MockDriveMgrClient: mocks.NewMockDriveMgrClient(nil),
+			details: map[string]*api.DriveHealthDetails{
+				drive1CR.Spec.SerialNumber: {Reason: "SMART overall-health self-assessment test failed"},
+			},
+		}
+		modifiedDrive := drive1CR.DeepCopy()
+		modifiedDrive.Spec.Health = apiV1.HealthBad
+
+		upd := &driveUpdates{
+			Updated: []updatedDrive{{
+				PreviousState: drive1CR,
+				CurrentState:  modifiedDrive}},
+		}
+		mgr.createEventsForDriveUpdates(upd)
+		assert.True(t, expectEvent(drive1CR, eventing.ErrorType, eventing.DriveHealthFailure))
+		assert.Contains(t, rec.Calls[0].MessageFmt, "Reason: %s.")
+		assert.Contains(t, rec.Calls[0].Args, "SMART overall-health self-assessment test failed")
+	})
+}
+
+// healthDetailsDriveMgrClient is a drive manager client which knows health details of the drives
+type healthDetailsDriveMgrClient struct {
+	*mocks.MockDriveMgrClient
+	details map[string]*api.DriveHealthDetails
+}
+
+func (c *healthDetailsDriveMgrClient) HealthDetails(serialNumber string) *api.DriveHealthDetails {
+	return c.details[serialNumber]
 }
 
 func TestVolumeManager_isShouldBeReconciled(t *testing.T) {
@@ -1126,12 +1466,12 @@ func TestVolumeManager_isDriveIsInLVG(t *testing.T) {
 		},
 	}
 	// there are no LogicalVolumeGroup CRs
-	assert.False(t, vm.isDriveInLVG(drive1))
+	assert.False(t, vm.isDriveInLVG(testCtx, drive1))
 	// create LogicalVolumeGroup CR
 	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, lvgCR.Name, &lvgCR))
 
-	assert.True(t, vm.isDriveInLVG(drive1))
-	assert.False(t, vm.isDriveInLVG(drive2))
+	assert.True(t, vm.isDriveInLVG(testCtx, drive1))
+	assert.False(t, vm.isDriveInLVG(testCtx, drive2))
 }
 
 func TestVolumeManager_handleExpandingStatus(t *testing.T) {
@@ -1244,7 +1584,7 @@ func TestVolumeManager_isDriveSystem(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, false, isSystem)
 
-	bdev1.MountPoint = base.KubeletRootPath
+	bdev1.MountPoint = filepath.Join(base.DefaultKubeletDir, base.KubeletPodsDir)
 	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev1}, nil).Once()
 	vm.listBlk = listBlk
 	isSystem, err = vm.isDriveSystem("/dev/sdb")
@@ -0,0 +1,90 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Objects decodes rendered manifests into objects in the order of documents
+func Objects(manifests []byte) ([]*unstructured.Unstructured, error) {
+	var (
+		objs    []*unstructured.Unstructured
+		decoder = yaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifests), 4096)
+	)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				return objs, nil
+			}
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("object without kind or name: %v", obj.Object)
+		}
+		objs = append(objs, obj)
+	}
+}
+
+// Apply creates objects which don't exist and patches existing ones in the order of objs
+// Existing objects are patched by JSON merge patch with the manifest instead of being replaced, so fields which
+// are set by API server or other controllers (e.g. clusterIP of Service) are kept and immutable fields aren't reset
+func Apply(ctx context.Context, client k8sCl.Client, objs []*unstructured.Unstructured, logger *logrus.Logger) error {
+	for _, obj := range objs {
+		ll := logger.WithFields(logrus.Fields{
+			"component": "Apply",
+			"kind":      obj.GetKind(),
+			"name":      obj.GetName(),
+		})
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(obj.GroupVersionKind())
+		err := client.Get(ctx, k8sCl.ObjectKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}, current)
+		switch {
+		case k8sError.IsNotFound(err):
+			if err = client.Create(ctx, obj.DeepCopy()); err != nil {
+				return fmt.Errorf("unable to create %s %s: %v", obj.GetKind(), obj.GetName(), err)
+			}
+			ll.Info("Object is created")
+		case err != nil:
+			return fmt.Errorf("unable to read %s %s: %v", obj.GetKind(), obj.GetName(), err)
+		default:
+			patch, err := obj.MarshalJSON()
+			if err != nil {
+				return fmt.Errorf("unable to encode %s %s: %v", obj.GetKind(), obj.GetName(), err)
+			}
+			if err = client.Patch(ctx, current, k8sCl.ConstantPatch(types.MergePatchType, patch)); err != nil {
+				return fmt.Errorf("unable to patch %s %s: %v", obj.GetKind(), obj.GetName(), err)
+			}
+			ll.Info("Object is patched")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,349 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package render renders manifests of csi-baremetal-driver chart without helm, so manifests could be generated
+// deterministically in air-gapped environments and applied by operator
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	templatesDir = "templates"
+	crdsDir      = "crds"
+	valuesFile   = "values.yaml"
+	chartFile    = "Chart.yaml"
+
+	// DefaultReleaseName is the name of release used if Config.ReleaseName is empty
+	DefaultReleaseName = "csi-baremetal"
+	// DefaultNamespace is the namespace used if Config.Namespace is empty
+	DefaultNamespace = "default"
+
+	// noValue is printed by text/template for missing values, helm replaces it with empty string
+	noValue = "<no value>"
+)
+
+// Config is the typed configuration of rendered manifests. Values are taken from values.yaml of the chart,
+// overridden by ValuesFiles, after that by non-empty typed fields and at last by Set
+type Config struct {
+	// ChartPath is the directory of csi-baremetal-driver chart
+	ChartPath string
+	// ReleaseName is used as .Release.Name in templates
+	ReleaseName string
+	// Namespace is used as .Release.Namespace in templates
+	Namespace string
+	// Registry overrides global.registry value
+	Registry string
+	// Tag overrides image.tag value
+	Tag string
+	// DriveMgr overrides drivemgr.type value
+	DriveMgr string
+	// IncludeCRDs adds manifests from crds directory of the chart before templates
+	IncludeCRDs bool
+	// ValuesFiles are YAML files with values like helm -f
+	ValuesFiles []string
+	// Set contains values by dot-separated path like helm --set, for example node.mountMode=bidirectional
+	Set map[string]string
+}
+
+// chart is the part of Chart.yaml available in templates as .Chart
+type chart struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Render renders manifests of the chart by cfg to out. Documents are separated by "---" and are preceded by
+// "# Source:" comment with path of the template, templates are rendered in alphabetical order and
+// documents which are empty after rendering are skipped, so the result depends only on cfg and the chart
+func Render(cfg Config, out io.Writer) error {
+	values, err := buildValues(cfg)
+	if err != nil {
+		return err
+	}
+	chartData, err := ioutil.ReadFile(filepath.Join(cfg.ChartPath, chartFile))
+	if err != nil {
+		return err
+	}
+	var ch chart
+	if err = yaml.Unmarshal(chartData, &ch); err != nil {
+		return fmt.Errorf("unable to parse %s: %v", chartFile, err)
+	}
+
+	data := map[string]interface{}{
+		"Values": values,
+		"Chart":  map[string]interface{}{"Name": ch.Name, "Version": ch.Version},
+		"Release": map[string]interface{}{
+			"Name":      valueOrDefault(cfg.ReleaseName, DefaultReleaseName),
+			"Namespace": valueOrDefault(cfg.Namespace, DefaultNamespace),
+		},
+	}
+
+	if cfg.IncludeCRDs {
+		if err = renderDir(out, cfg.ChartPath, crdsDir, ch.Name, nil); err != nil {
+			return err
+		}
+	}
+	return renderDir(out, cfg.ChartPath, templatesDir, ch.Name, data)
+}
+
+// renderDir writes documents of YAML files of dir in alphabetical order, files are executed as templates with data
+// if it isn't nil
+func renderDir(out io.Writer, chartPath, dir, chartName string, data interface{}) error {
+	files, err := ioutil.ReadDir(filepath.Join(chartPath, dir))
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".yaml") && !strings.HasPrefix(f.Name(), "_") {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		content, err := ioutil.ReadFile(filepath.Join(chartPath, dir, name))
+		if err != nil {
+			return err
+		}
+		rendered := string(content)
+		if data != nil {
+			tmpl, err := template.New(name).Funcs(funcMap()).Option("missingkey=zero").Parse(rendered)
+			if err != nil {
+				return fmt.Errorf("unable to parse template %s: %v", name, err)
+			}
+			var buf bytes.Buffer
+			if err = tmpl.Execute(&buf, data); err != nil {
+				return fmt.Errorf("unable to render template %s: %v", name, err)
+			}
+			rendered = strings.Replace(buf.String(), noValue, "", -1)
+		}
+		if err = writeDocuments(out, chartName+"/"+dir+"/"+name, rendered); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDocuments writes non-empty YAML documents of content to out with source comment
+func writeDocuments(out io.Writer, source, content string) error {
+	for _, doc := range splitDocuments(content) {
+		if _, err := fmt.Fprintf(out, "---\n# Source: %s\n%s\n", source, doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitDocuments splits YAML stream by "---" lines and drops documents which contain only comments and spaces
+func splitDocuments(content string) []string {
+	var (
+		docs    []string
+		current []string
+	)
+	flush := func() {
+		doc := strings.Trim(strings.Join(current, "\n"), "\n")
+		for _, line := range current {
+			trimmed := strings.TrimSpace(line)
+			if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+				docs = append(docs, doc)
+				break
+			}
+		}
+		current = nil
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimRight(line, " \t\r") == "---" {
+			flush()
+			continue
+		}
+		current = append(current, strings.TrimRight(line, " \t\r"))
+	}
+	flush()
+	return docs
+}
+
+// buildValues merges values of the chart, values files, typed fields and Set of cfg
+func buildValues(cfg Config) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, file := range append([]string{filepath.Join(cfg.ChartPath, valuesFile)}, cfg.ValuesFiles...) {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		fileValues := map[string]interface{}{}
+		if err = yaml.Unmarshal(data, &fileValues); err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %v", file, err)
+		}
+		mergeValues(values, fileValues)
+	}
+
+	typed := map[string]string{
+		"global.registry": cfg.Registry,
+		"image.tag":       cfg.Tag,
+		"drivemgr.type":   cfg.DriveMgr,
+	}
+	for _, path := range sortedKeys(typed) {
+		if typed[path] == "" {
+			continue
+		}
+		if err := setValue(values, path, typed[path]); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range sortedKeys(cfg.Set) {
+		if err := setValue(values, path, parseValue(cfg.Set[path])); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// mergeValues merges src into dst recursively, values of src have precedence
+func mergeValues(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeValues(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// setValue sets value by dot-separated path, intermediate maps are created if absent
+func setValue(values map[string]interface{}, path string, value interface{}) error {
+	keys := strings.Split(path, ".")
+	current := values
+	for i, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			if current[key] != nil {
+				return fmt.Errorf("unable to set %s: %s is not a map", path, strings.Join(keys[:i+1], "."))
+			}
+			next = map[string]interface{}{}
+			current[key] = next
+		}
+		current = next
+	}
+	current[keys[len(keys)-1]] = value
+	return nil
+}
+
+// parseValue converts value of Set the same way as helm --set: booleans, integers and null are typed,
+// lists are written as {a,b}
+func parseValue(value string) interface{} {
+	switch {
+	case value == "null":
+		return nil
+	case value == "true" || value == "false":
+		return value == "true"
+	case strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}"):
+		list := []interface{}{}
+		if inner := strings.TrimSpace(value[1 : len(value)-1]); inner != "" {
+			for _, item := range strings.Split(inner, ",") {
+				list = append(list, parseValue(strings.TrimSpace(item)))
+			}
+		}
+		return list
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	return value
+}
+
+// funcMap returns helm (sprig) functions which are used in templates of the chart
+func funcMap() template.FuncMap {
+	return template.FuncMap{
+		"default": func(def interface{}, given ...interface{}) interface{} {
+			if len(given) == 0 || isEmpty(given[0]) {
+				return def
+			}
+			return given[0]
+		},
+		"join": func(sep string, items interface{}) string {
+			v := reflect.ValueOf(items)
+			if items == nil || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+				return fmt.Sprint(items)
+			}
+			strs := make([]string, v.Len())
+			for i := range strs {
+				strs[i] = fmt.Sprint(v.Index(i).Interface())
+			}
+			return strings.Join(strs, sep)
+		},
+		"replace": func(old, new, src string) string {
+			return strings.Replace(src, old, new, -1)
+		},
+		"quote": func(value interface{}) string {
+			if value == nil {
+				return `""`
+			}
+			return strconv.Quote(fmt.Sprint(value))
+		},
+	}
+}
+
+// isEmpty checks whether value is empty in terms of helm default function
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func valueOrDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,180 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const driverChartPath = "../../charts/csi-baremetal-driver"
+
+func render(t *testing.T, cfg Config) []*unstructured.Unstructured {
+	var out bytes.Buffer
+	assert.Nil(t, Render(cfg, &out))
+	objs, err := Objects(out.Bytes())
+	assert.Nil(t, err)
+	return objs
+}
+
+func findObject(objs []*unstructured.Unstructured, kind, name string) *unstructured.Unstructured {
+	for _, obj := range objs {
+		if obj.GetKind() == kind && obj.GetName() == name {
+			return obj
+		}
+	}
+	return nil
+}
+
+func containerArgs(t *testing.T, obj *unstructured.Unstructured, container string) []string {
+	containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	assert.Nil(t, err)
+	for _, c := range containers {
+		if c.(map[string]interface{})["name"] == container {
+			args, _, err := unstructured.NestedStringSlice(c.(map[string]interface{}), "args")
+			assert.Nil(t, err)
+			return args
+		}
+	}
+	t.Fatalf("container %s is not found", container)
+	return nil
+}
+
+func TestRender(t *testing.T) {
+	cfg := Config{
+		ChartPath: driverChartPath,
+		Namespace: "csi",
+		Registry:  "registry.local:5000",
+		Tag:       "1.2.3",
+		Set:       map[string]string{"log.level": "debug"},
+	}
+	objs := render(t, cfg)
+
+	controller := findObject(objs, "Deployment", "csi-baremetal-controller")
+	assert.NotNil(t, controller)
+	assert.Equal(t, "csi", controller.GetNamespace())
+	assert.Contains(t, containerArgs(t, controller, "controller"), "--loglevel=debug")
+	containers, _, _ := unstructured.NestedSlice(controller.Object, "spec", "template", "spec", "containers")
+	for _, c := range containers {
+		image := c.(map[string]interface{})["image"].(string)
+		assert.True(t, strings.HasPrefix(image, "registry.local:5000/"), image)
+	}
+	assert.NotNil(t, findObject(objs, "DaemonSet", "csi-baremetal-node"))
+	assert.Nil(t, findObject(objs, "CustomResourceDefinition", "volumes.csi-baremetal.dell.com"))
+
+	// rendering is deterministic
+	var first, second bytes.Buffer
+	assert.Nil(t, Render(cfg, &first))
+	assert.Nil(t, Render(cfg, &second))
+	assert.Equal(t, first.String(), second.String())
+	assert.NotContains(t, first.String(), noValue)
+}
+
+func TestRender_CRDsAndValuesFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "render")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	valuesPath := filepath.Join(dir, "values.yaml")
+	assert.Nil(t, ioutil.WriteFile(valuesPath, []byte("config:\n  deploy: true\n"), 0644))
+
+	objs := render(t, Config{ChartPath: driverChartPath, IncludeCRDs: true, ValuesFiles: []string{valuesPath}})
+	assert.Equal(t, "CustomResourceDefinition", objs[0].GetKind())
+	assert.NotNil(t, findObject(objs, "CustomResourceDefinition", "volumes.csi-baremetal.dell.com"))
+	configMap := findObject(objs, "ConfigMap", DefaultReleaseName+"-csi-config")
+	assert.NotNil(t, configMap)
+	assert.Equal(t, DefaultNamespace, configMap.GetNamespace())
+
+	// Set has precedence over values files
+	objs = render(t, Config{
+		ChartPath:   driverChartPath,
+		ValuesFiles: []string{valuesPath},
+		Set:         map[string]string{"config.deploy": "false"},
+	})
+	assert.Nil(t, findObject(objs, "ConfigMap", DefaultReleaseName+"-csi-config"))
+
+	assert.NotNil(t, Render(Config{ChartPath: "/not/existing/chart"}, &bytes.Buffer{}))
+	assert.NotNil(t, Render(Config{ChartPath: driverChartPath, Set: map[string]string{"log.level.value": "x"}},
+		&bytes.Buffer{}))
+}
+
+func TestParseValue(t *testing.T) {
+	assert.Equal(t, true, parseValue("true"))
+	assert.Equal(t, int64(10), parseValue("10"))
+	assert.Equal(t, "1.2.3", parseValue("1.2.3"))
+	assert.Nil(t, parseValue("null"))
+	assert.Equal(t, []interface{}{"rack", int64(1)}, parseValue("{rack, 1}"))
+	assert.Equal(t, []interface{}{}, parseValue("{}"))
+}
+
+func TestSplitDocuments(t *testing.T) {
+	docs := splitDocuments("# comment only\n---\na: 1\n---\n\n---\nb: 2  \n")
+	assert.Equal(t, []string{"a: 1", "b: 2"}, docs)
+}
+
+func TestApply(t *testing.T) {
+	client := fake.NewFakeClientWithScheme(clientgoscheme.Scheme)
+	objs, err := Objects([]byte("---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n  namespace: default\n" +
+		"data:\n  key: value\n"))
+	assert.Nil(t, err)
+	assert.Len(t, objs, 1)
+
+	ctx := context.Background()
+	assert.Nil(t, Apply(ctx, client, objs, logrus.New()))
+	cm := &coreV1.ConfigMap{}
+	assert.Nil(t, client.Get(ctx, k8sCl.ObjectKey{Namespace: "default", Name: "cm"}, cm))
+	assert.Equal(t, "value", cm.Data["key"])
+
+	assert.Nil(t, unstructured.SetNestedField(objs[0].Object, "updated", "data", "key"))
+	assert.Nil(t, Apply(ctx, client, objs, logrus.New()))
+	assert.Nil(t, client.Get(ctx, k8sCl.ObjectKey{Namespace: "default", Name: "cm"}, cm))
+	assert.Equal(t, "updated", cm.Data["key"])
+
+	_, err = Objects([]byte("apiVersion: v1\nkind: ConfigMap\n"))
+	assert.NotNil(t, err)
+}
+
+func TestApply_KeepsServerFields(t *testing.T) {
+	ctx := context.Background()
+	svc := &coreV1.Service{
+		ObjectMeta: metaV1.ObjectMeta{Name: "svc", Namespace: "default"},
+		Spec:       coreV1.ServiceSpec{ClusterIP: "10.0.0.1", Ports: []coreV1.ServicePort{{Port: 80}}},
+	}
+	client := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, svc)
+	objs, err := Objects([]byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: svc\n  namespace: default\n" +
+		"spec:\n  ports:\n  - port: 8080\n"))
+	assert.Nil(t, err)
+
+	assert.Nil(t, Apply(ctx, client, objs, logrus.New()))
+	assert.Nil(t, client.Get(ctx, k8sCl.ObjectKey{Namespace: "default", Name: "svc"}, svc))
+	// clusterIP is immutable, it isn't reset by the manifest
+	assert.Equal(t, "10.0.0.1", svc.Spec.ClusterIP)
+	assert.Equal(t, int32(8080), svc.Spec.Ports[0].Port)
+}
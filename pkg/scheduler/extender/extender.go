@@ -20,26 +20,34 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strings"
 	"sync"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	coreV1 "k8s.io/api/core/v1"
 	storageV1 "k8s.io/api/storage/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api/v1"
 
 	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
 	v1 "github.com/dell/csi-baremetal/api/v1"
 	volcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/bytesize"
 	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	fc "github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/capacitymanager"
 	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
+	metricsC "github.com/dell/csi-baremetal/pkg/metrics/common"
 )
 
 // Extender holds http handlers for scheduler extender endpoints and implements logic for nodes filtering
@@ -54,6 +62,83 @@ type Extender struct {
 	sync.Mutex
 	logger                 *logrus.Entry
 	capacityManagerBuilder capacityplanner.CapacityManagerBuilder
+	capacity               capacitymanager.Manager
+	// default placement strategy, it is overridden by placementStrategy parameter of StorageClass
+	strategy capacityplanner.PlacementStrategy
+	// placement strategies from StorageClass parameters by names, they are shared between requests
+	strategies   map[string]capacityplanner.PlacementStrategy
+	strategiesMu sync.Mutex
+}
+
+// reasons of filtering out the node, they are used as label of extender_filtered_out_nodes_total metric
+const (
+	reasonNoCapacity = "insufficient_capacity"
+	reasonNoNodeID   = "unknown_node_id"
+)
+
+// annotations of the default StorageClass, beta one is still set by some distributions
+const (
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	defaultStorageClassBetaAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// filterReasonMessages are messages for the reasons of filtering out the node which are returned to scheduler
+var filterReasonMessages = map[string]string{
+	reasonNoCapacity: "Node doesn't contain required amount of AvailableCapacity",
+	reasonNoNodeID:   "Node ID annotation isn't set, node isn't registered by operator",
+}
+
+// FilterExplanation is a response of debug endpoint, it explains why each node passed or failed the storage filter
+type FilterExplanation struct {
+	Pod         string            `json:"pod"`
+	Volumes     []*genV1.Volume   `json:"volumes"`
+	PassedNodes []string          `json:"passedNodes"`
+	FailedNodes map[string]string `json:"failedNodes"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// ephemeralVolumesArgs holds claim templates of generic ephemeral volumes (pod.spec.volumes[].ephemeral) from
+// ExtenderArgs, they aren't a part of used k8s API version, so they are decoded from the request separately
+type ephemeralVolumesArgs struct {
+	Pod *struct {
+		Spec struct {
+			Volumes []struct {
+				Name      string `json:"name"`
+				Ephemeral *struct {
+					VolumeClaimTemplate *struct {
+						Spec coreV1.PersistentVolumeClaimSpec `json:"spec"`
+					} `json:"volumeClaimTemplate"`
+				} `json:"ephemeral"`
+			} `json:"volumes"`
+		} `json:"spec"`
+	} `json:"pod"`
+}
+
+// decodeExtenderArgs decodes ExtenderArgs JSON into args
+// Returns claim specs of pod generic ephemeral volumes by volume names
+func decodeExtenderArgs(body []byte, args *schedulerapi.ExtenderArgs) (map[string]*coreV1.PersistentVolumeClaimSpec, error) {
+	if err := json.Unmarshal(body, args); err != nil {
+		return nil, err
+	}
+	return parseEphemeralClaims(body)
+}
+
+// parseEphemeralClaims returns claim specs of pod generic ephemeral volumes by volume names from ExtenderArgs JSON
+func parseEphemeralClaims(args []byte) (map[string]*coreV1.PersistentVolumeClaimSpec, error) {
+	var ephemeralArgs ephemeralVolumesArgs
+	if err := json.Unmarshal(args, &ephemeralArgs); err != nil {
+		return nil, err
+	}
+	claims := make(map[string]*coreV1.PersistentVolumeClaimSpec)
+	if ephemeralArgs.Pod == nil {
+		return claims, nil
+	}
+	for _, v := range ephemeralArgs.Pod.Spec.Volumes {
+		if v.Ephemeral != nil && v.Ephemeral.VolumeClaimTemplate != nil {
+			claims[v.Name] = &v.Ephemeral.VolumeClaimTemplate.Spec
+		}
+	}
+	return claims, nil
 }
 
 // NewExtender returns new instance of Extender struct
@@ -66,9 +151,34 @@ func NewExtender(logger *logrus.Logger, kubeClient *k8s.KubeClient,
 		featureChecker:         featureConf,
 		logger:                 logger.WithField("component", "Extender"),
 		capacityManagerBuilder: &capacityplanner.DefaultCapacityManagerBuilder{},
+		capacity:               capacitymanager.NewManagerImpl(kubeClient, logger),
+		strategies:             map[string]capacityplanner.PlacementStrategy{},
 	}, nil
 }
 
+// SetPlacementStrategy sets strategy which is used to select AvailableCapacity on node for volumes
+// which StorageClass doesn't set placementStrategy parameter
+func (e *Extender) SetPlacementStrategy(strategy capacityplanner.PlacementStrategy) {
+	e.strategy = strategy
+	e.capacityManagerBuilder = &capacityplanner.DefaultCapacityManagerBuilder{Strategy: strategy}
+}
+
+// placementStrategy returns placement strategy by name, strategies are shared between requests
+func (e *Extender) placementStrategy(name string) (capacityplanner.PlacementStrategy, error) {
+	e.strategiesMu.Lock()
+	defer e.strategiesMu.Unlock()
+	if strategy, ok := e.strategies[name]; ok {
+		return strategy, nil
+	}
+	strategy, err := capacityplanner.NewPlacementStrategy(name, e.k8sClient,
+		e.logger.WithField("component", "PlacementStrategy"))
+	if err != nil {
+		return nil, err
+	}
+	e.strategies[name] = strategy
+	return strategy, nil
+}
+
 // FilterHandler extracts ExtenderArgs struct from req and writes ExtenderFilterResult to the w
 func (e *Extender) FilterHandler(w http.ResponseWriter, req *http.Request) {
 	sessionUUID := uuid.New().String()
@@ -77,16 +187,22 @@ func (e *Extender) FilterHandler(w http.ResponseWriter, req *http.Request) {
 		"method":      "FilterHandler",
 	})
 	ll.Infof("Processing request: %v", req)
+	defer metricsC.ExtenderFilterDuration.EvaluateDuration(prometheus.Labels{})()
 
 	w.Header().Set("Content-Type", "application/json")
 	resp := json.NewEncoder(w)
 
 	var (
-		extenderArgs schedulerapi.ExtenderArgs
-		extenderRes  = &schedulerapi.ExtenderFilterResult{}
+		extenderArgs    schedulerapi.ExtenderArgs
+		extenderRes     = &schedulerapi.ExtenderFilterResult{}
+		ephemeralClaims map[string]*coreV1.PersistentVolumeClaimSpec
 	)
 
-	if err := json.NewDecoder(req.Body).Decode(&extenderArgs); err != nil {
+	body, err := ioutil.ReadAll(req.Body)
+	if err == nil {
+		ephemeralClaims, err = decodeExtenderArgs(body, &extenderArgs)
+	}
+	if err != nil {
 		ll.Errorf("Unable to decode request body: %v", err)
 		extenderRes.Error = err.Error()
 		if err := resp.Encode(extenderRes); err != nil {
@@ -97,7 +213,7 @@ func (e *Extender) FilterHandler(w http.ResponseWriter, req *http.Request) {
 
 	ll.Info("Filtering")
 	ctxWithVal := context.WithValue(req.Context(), base.RequestUUID, sessionUUID)
-	volumes, err := e.gatherVolumesByProvisioner(ctxWithVal, extenderArgs.Pod)
+	volumes, strategies, err := e.gatherVolumesByProvisioner(ctxWithVal, extenderArgs.Pod, ephemeralClaims)
 	if err != nil {
 		extenderRes.Error = err.Error()
 		if err := resp.Encode(extenderRes); err != nil {
@@ -109,7 +225,7 @@ func (e *Extender) FilterHandler(w http.ResponseWriter, req *http.Request) {
 
 	e.Lock()
 	defer e.Unlock()
-	matchedNodes, failedNodes, err := e.filter(ctxWithVal, extenderArgs.Nodes.Items, volumes)
+	matchedNodes, failedNodes, err := e.filter(ctxWithVal, extenderArgs.Nodes.Items, volumes, strategies)
 	if err != nil {
 		ll.Errorf("filter finished with error: %v", err)
 		extenderRes.Error = err.Error()
@@ -170,6 +286,80 @@ func (e *Extender) PrioritizeHandler(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// DebugHandler explains why each node passed or failed the storage filter for the pod provided in "pod" query parameter
+// in the form <namespace>/<name>, AvailableCapacity isn't reserved. Writes FilterExplanation to the w
+func (e *Extender) DebugHandler(w http.ResponseWriter, req *http.Request) {
+	sessionUUID := uuid.New().String()
+	ll := e.logger.WithFields(logrus.Fields{
+		"sessionUUID": sessionUUID,
+		"method":      "DebugHandler",
+	})
+	ll.Infof("Processing request: %v", req)
+
+	w.Header().Set("Content-Type", "application/json")
+	resp := json.NewEncoder(w)
+
+	podKey := req.URL.Query().Get("pod")
+	explanation := &FilterExplanation{Pod: podKey}
+	ctxWithVal := context.WithValue(req.Context(), base.RequestUUID, sessionUUID)
+	if err := e.explain(ctxWithVal, podKey, explanation); err != nil {
+		ll.Errorf("Unable to explain filter result for pod %s: %v", podKey, err)
+		explanation.Error = err.Error()
+	}
+	if err := resp.Encode(explanation); err != nil {
+		ll.Errorf("Unable to write response %v: %v", explanation, err)
+	}
+}
+
+// explain fills explanation of storage filter result for the pod with key <namespace>/<name> on all k8s nodes
+func (e *Extender) explain(ctx context.Context, podKey string, explanation *FilterExplanation) error {
+	keyParts := strings.Split(podKey, "/")
+	if len(keyParts) != 2 || keyParts[0] == "" || keyParts[1] == "" {
+		return fmt.Errorf("pod should be provided in the form <namespace>/<name>, got %q", podKey)
+	}
+	// pod is read as unstructured object to not lose generic ephemeral volumes which aren't a part of used k8s API
+	podObj := &unstructured.Unstructured{}
+	podObj.SetGroupVersionKind(coreV1.SchemeGroupVersion.WithKind("Pod"))
+	if err := e.k8sClient.ReadCR(ctx, keyParts[1], keyParts[0], podObj); err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]interface{}{"pod": podObj.Object})
+	if err != nil {
+		return err
+	}
+	var args schedulerapi.ExtenderArgs
+	ephemeralClaims, err := decodeExtenderArgs(body, &args)
+	if err != nil {
+		return err
+	}
+	nodes, err := e.k8sClient.GetNodes(ctx)
+	if err != nil {
+		return err
+	}
+
+	var strategies map[string]string
+	if explanation.Volumes, strategies, err = e.gatherVolumesByProvisioner(ctx, args.Pod, ephemeralClaims); err != nil {
+		return err
+	}
+	e.Lock()
+	defer e.Unlock()
+	matchedNodes, failedReasons, _, err := e.planNodes(ctx, nodes, explanation.Volumes, strategies,
+		capacityplanner.NewACReader(e.k8sClient, e.logger, true),
+		capacityplanner.NewACRReader(e.k8sClient, e.logger, true))
+	if err != nil {
+		return err
+	}
+	explanation.PassedNodes = make([]string, 0, len(matchedNodes))
+	for _, node := range matchedNodes {
+		explanation.PassedNodes = append(explanation.PassedNodes, node.Name)
+	}
+	explanation.FailedNodes = make(map[string]string, len(failedReasons))
+	for node, reason := range failedReasons {
+		explanation.FailedNodes[node] = filterReasonMessages[reason]
+	}
+	return nil
+}
+
 // BindHandler does bind of a pod to specific node
 // todo - not implemented. Was used for testing purposes ONLY (fault injection)!
 func (e *Extender) BindHandler(w http.ResponseWriter, req *http.Request) {
@@ -205,7 +395,10 @@ func (e *Extender) BindHandler(w http.ResponseWriter, req *http.Request) {
 
 // gatherVolumesByProvisioner search all volumes in pod' spec that should be provisioned
 // by provisioner e.provisioner and construct genV1.Volume struct for each of such volume
-func (e *Extender) gatherVolumesByProvisioner(ctx context.Context, pod *coreV1.Pod) ([]*genV1.Volume, error) {
+// ephemeralClaims are claim specs of generic ephemeral volumes of the pod by volume names
+// Returns volumes and names of placement strategies by volume IDs for volumes which StorageClass sets it
+func (e *Extender) gatherVolumesByProvisioner(ctx context.Context, pod *coreV1.Pod,
+	ephemeralClaims map[string]*coreV1.PersistentVolumeClaimSpec) ([]*genV1.Volume, map[string]string, error) {
 	ll := e.logger.WithFields(logrus.Fields{
 		"sessionUUID": ctx.Value(base.RequestUUID),
 		"method":      "gatherVolumesByProvisioner",
@@ -215,10 +408,26 @@ func (e *Extender) gatherVolumesByProvisioner(ctx context.Context, pod *coreV1.P
 	scs, err := e.scNameStorageTypeMapping(ctx)
 	if err != nil {
 		ll.Errorf("Unable to collect storage classes: %v", err)
-		return nil, err
+		return nil, nil, err
+	}
+	scStrategies, err := e.scNamePlacementStrategyMapping(ctx)
+	if err != nil {
+		ll.Errorf("Unable to collect placement strategies of storage classes: %v", err)
+		return nil, nil, err
 	}
 
 	volumes := make([]*genV1.Volume, 0)
+	strategies := make(map[string]string)
+	addPVCVolume := func(pvc *coreV1.PersistentVolumeClaim) {
+		volume := e.volumeFromPVC(pvc, scs)
+		if volume == nil {
+			return
+		}
+		volumes = append(volumes, volume)
+		if strategy := scStrategies[*pvc.Spec.StorageClassName]; strategy != "" {
+			strategies[volume.Id] = strategy
+		}
+	}
 	for _, v := range pod.Spec.Volumes {
 		// check whether there are Ephemeral volumes or no
 		if v.CSI != nil {
@@ -232,45 +441,81 @@ func (e *Extender) gatherVolumesByProvisioner(ctx context.Context, pod *coreV1.P
 			}
 			continue
 		}
+		if claim, ok := ephemeralClaims[v.Name]; ok {
+			// PVC of generic ephemeral volume is created by k8s after the pod, its name is <pod name>-<volume name>
+			pvcName := pod.Name + "-" + v.Name
+			pvc := &coreV1.PersistentVolumeClaim{}
+			err := e.k8sCache.ReadCR(ctx, pvcName, pod.Namespace, pvc)
+			switch {
+			case k8sError.IsNotFound(err):
+				pvc = &coreV1.PersistentVolumeClaim{
+					ObjectMeta: metaV1.ObjectMeta{Name: pvcName, Namespace: pod.Namespace},
+					Spec:       *claim,
+				}
+				// default StorageClass is set by k8s admission when PVC is created from the template
+				if pvc.Spec.StorageClassName == nil {
+					defaultSC, err := e.defaultStorageClassName(ctx)
+					if err != nil {
+						ll.Errorf("Unable to find default storage class: %v", err)
+						return nil, nil, err
+					}
+					if defaultSC != "" {
+						pvc.Spec.StorageClassName = &defaultSC
+					}
+				}
+			case err != nil:
+				ll.Errorf("Unable to read PVC %s in NS %s: %v. ", pvcName, pod.Namespace, err)
+				return nil, nil, err
+			}
+			addPVCVolume(pvc)
+			continue
+		}
 		if v.PersistentVolumeClaim != nil {
 			pvc := &coreV1.PersistentVolumeClaim{}
 			err := e.k8sCache.ReadCR(ctx, v.PersistentVolumeClaim.ClaimName, pod.Namespace, pvc)
 			if err != nil {
 				ll.Errorf("Unable to read PVC %s in NS %s: %v. ", v.PersistentVolumeClaim.ClaimName, pod.Namespace, err)
-				return nil, err
-			}
-			if pvc.Spec.StorageClassName == nil {
-				continue
-			}
-			if _, ok := scs[*pvc.Spec.StorageClassName]; !ok {
-				continue
+				return nil, nil, err
 			}
-			if pvc.Status.Phase == coreV1.ClaimBound || pvc.Status.Phase == coreV1.ClaimLost {
-				continue
-			}
-			if storageType, ok := scs[*pvc.Spec.StorageClassName]; ok {
-				storageReq, ok := pvc.Spec.Resources.Requests[coreV1.ResourceStorage]
-				if !ok {
-					ll.Errorf("There is no key for storage resource for PVC %s", pvc.Name)
-					storageReq = resource.Quantity{}
-				}
+			addPVCVolume(pvc)
+		}
+	}
+	return volumes, strategies, nil
+}
 
-				mode := ""
-				if pvc.Spec.VolumeMode != nil {
-					mode = string(*pvc.Spec.VolumeMode)
-				}
+// volumeFromPVC constructs genV1.Volume for PVC which should be provisioned by e.provisioner
+// scs - mapping of storage classes of e.provisioner to storage types
+// Returns nil if PVC is provisioned by other provisioner or is already bound
+func (e *Extender) volumeFromPVC(pvc *coreV1.PersistentVolumeClaim, scs map[string]string) *genV1.Volume {
+	if pvc.Spec.StorageClassName == nil {
+		return nil
+	}
+	storageType, ok := scs[*pvc.Spec.StorageClassName]
+	if !ok {
+		return nil
+	}
+	if pvc.Status.Phase == coreV1.ClaimBound || pvc.Status.Phase == coreV1.ClaimLost {
+		return nil
+	}
+	storageReq, ok := pvc.Spec.Resources.Requests[coreV1.ResourceStorage]
+	if !ok {
+		e.logger.WithField("method", "volumeFromPVC").
+			Errorf("There is no key for storage resource for PVC %s", pvc.Name)
+		storageReq = resource.Quantity{}
+	}
 
-				volumes = append(volumes, &genV1.Volume{
-					Id:           pvc.Name,
-					StorageClass: util.ConvertStorageClass(storageType),
-					Size:         storageReq.Value(),
-					Mode:         mode,
-					Ephemeral:    false,
-				})
-			}
-		}
+	mode := ""
+	if pvc.Spec.VolumeMode != nil {
+		mode = string(*pvc.Spec.VolumeMode)
+	}
+
+	return &genV1.Volume{
+		Id:           pvc.Name,
+		StorageClass: util.ConvertStorageClass(storageType),
+		Size:         storageReq.Value(),
+		Mode:         mode,
+		Ephemeral:    false,
 	}
-	return volumes, nil
 }
 
 // constructVolumeFromCSISource constructs genV1.Volume based on fields from coreV1.Volume.CSI
@@ -293,7 +538,7 @@ func (e *Extender) constructVolumeFromCSISource(v *coreV1.CSIVolumeSource) (vol
 		return vol, fmt.Errorf("unable to detect size from attributes %v", v.VolumeAttributes)
 	}
 
-	size, err := util.StrToBytes(sizeStr)
+	size, err := bytesize.Parse(sizeStr)
 	if err != nil {
 		return vol, fmt.Errorf("unable to convert string %s to bytes: %v", sizeStr, err)
 	}
@@ -306,8 +551,8 @@ func (e *Extender) constructVolumeFromCSISource(v *coreV1.CSIVolumeSource) (vol
 // nodes - list of node candidate, volumes - requested volumes
 // returns: matchedNodes - list of nodes on which volumes could be provisioned
 // failedNodesMap - represents the filtered out nodes, with node names and failure messages
-func (e *Extender) filter(ctx context.Context, nodes []coreV1.Node, volumes []*genV1.Volume) (matchedNodes []coreV1.Node,
-	failedNodesMap schedulerapi.FailedNodesMap, err error) {
+func (e *Extender) filter(ctx context.Context, nodes []coreV1.Node, volumes []*genV1.Volume,
+	strategies map[string]string) (matchedNodes []coreV1.Node, failedNodesMap schedulerapi.FailedNodesMap, err error) {
 	if len(volumes) == 0 {
 		return nodes, failedNodesMap, err
 	}
@@ -315,39 +560,72 @@ func (e *Extender) filter(ctx context.Context, nodes []coreV1.Node, volumes []*g
 	// TODO: do not read all ACs and ACRs for each request: https://github.com/dell/csi-baremetal/issues/89
 	acReader := capacityplanner.NewACReader(e.k8sClient, e.logger, true)
 	acrReader := capacityplanner.NewACRReader(e.k8sClient, e.logger, true)
+	matchedNodes, failedReasons, placingPlan, err := e.planNodes(ctx, nodes, volumes, strategies, acReader, acrReader)
+	if err != nil {
+		return matchedNodes, failedNodesMap, err
+	}
+
+	failedNodesMap = schedulerapi.FailedNodesMap{}
+	for node, reason := range failedReasons {
+		failedNodesMap[node] = filterReasonMessages[reason]
+		metricsC.ExtenderFilteredOutNodes.With(prometheus.Labels{"reason": reason}).Inc()
+	}
+	if len(matchedNodes) != 0 {
+		if err = e.capacity.Reserve(ctx, placingPlan); err != nil {
+			e.logger.Errorf("failed to create reservation: %s", err.Error())
+		}
+	}
+
+	return matchedNodes, failedNodesMap, err
+}
+
+// planNodes plans placing of the volumes on the nodes using provided AC and ACR readers
+// strategies - names of placement strategies by volume IDs which override the default strategy
+// Returns nodes on which volumes could be placed, reasons of filtering out other nodes by node names and placing plan
+func (e *Extender) planNodes(ctx context.Context, nodes []coreV1.Node, volumes []*genV1.Volume,
+	strategies map[string]string, acReader capacityplanner.CapacityReader,
+	acrReader capacityplanner.ReservationReader) ([]coreV1.Node, map[string]string,
+	*capacityplanner.VolumesPlacingPlan, error) {
+	failedReasons := make(map[string]string)
+	if len(volumes) == 0 {
+		return nodes, failedReasons, nil, nil
+	}
+
+	capManagerBuilder := e.capacityManagerBuilder
+	if len(strategies) > 0 {
+		volumeStrategies := make(map[string]capacityplanner.PlacementStrategy, len(strategies))
+		for volumeID, name := range strategies {
+			strategy, err := e.placementStrategy(name)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			volumeStrategies[volumeID] = strategy
+		}
+		capManagerBuilder = &capacityplanner.DefaultCapacityManagerBuilder{Strategy: e.strategy,
+			VolumeStrategies: volumeStrategies}
+	}
 	reservedCapReader := capacityplanner.NewUnreservedACReader(e.logger, acReader, acrReader)
-	capManager := e.capacityManagerBuilder.GetCapacityManager(e.logger, reservedCapReader)
+	capManager := capManagerBuilder.GetCapacityManager(e.logger, reservedCapReader)
 
 	placingPlan, err := capManager.PlanVolumesPlacing(ctx, volumes)
 	if err != nil {
-		return matchedNodes, failedNodesMap, err
+		return nil, nil, nil, err
 	}
 
-	noACForNodeMsg := "Node doesn't contain required amount of AvailableCapacity"
-
-	failedNodesMap = schedulerapi.FailedNodesMap{}
+	var matchedNodes []coreV1.Node
 	for _, node := range nodes {
-		if placingPlan == nil {
-			failedNodesMap[node.Name] = noACForNodeMsg
+		nodeID := e.getNodeID(node)
+		if nodeID == "" {
+			failedReasons[node.Name] = reasonNoNodeID
 			continue
 		}
-		node := node
-		placingForNode := placingPlan.GetVolumesToACMapping(e.getNodeID(node))
-		if placingForNode == nil {
-			failedNodesMap[node.Name] = noACForNodeMsg
+		if placingPlan == nil || placingPlan.GetVolumesToACMapping(nodeID) == nil {
+			failedReasons[node.Name] = reasonNoCapacity
 			continue
 		}
 		matchedNodes = append(matchedNodes, node)
 	}
-	if len(matchedNodes) != 0 {
-		reservationHelper := capacityplanner.NewReservationHelper(e.logger, e.k8sClient, acReader, acrReader)
-		err = reservationHelper.CreateReservation(ctx, placingPlan)
-		if err != nil {
-			e.logger.Errorf("failed to create reservation: %s", err.Error())
-		}
-	}
-
-	return matchedNodes, failedNodesMap, err
+	return matchedNodes, failedReasons, placingPlan, nil
 }
 
 func (e *Extender) score(nodes []coreV1.Node) ([]schedulerapi.HostPriority, error) {
@@ -433,6 +711,48 @@ func (e *Extender) scNameStorageTypeMapping(ctx context.Context) (map[string]str
 	return scNameTypeMap, nil
 }
 
+// scNamePlacementStrategyMapping reads k8s storage class resources and collect map with key storage class name
+// and value .parameters.placementStrategy for that sc, collect only sc of e.provisioner which set the parameter
+func (e *Extender) scNamePlacementStrategyMapping(ctx context.Context) (map[string]string, error) {
+	scs := storageV1.StorageClassList{}
+
+	if err := e.k8sCache.ReadList(ctx, &scs); err != nil {
+		return nil, err
+	}
+
+	scNameStrategyMap := map[string]string{}
+	for _, sc := range scs.Items {
+		if strategy := sc.Parameters[base.PlacementStrategyKey]; sc.Provisioner == e.provisioner && strategy != "" {
+			scNameStrategyMap[sc.Name] = strategy
+		}
+	}
+	return scNameStrategyMap, nil
+}
+
+// defaultStorageClassName returns name of the default storage class of the cluster, the newest one is chosen
+// if several storage classes are marked as default, empty string is returned if there is no default storage class
+func (e *Extender) defaultStorageClassName(ctx context.Context) (string, error) {
+	scs := storageV1.StorageClassList{}
+	if err := e.k8sCache.ReadList(ctx, &scs); err != nil {
+		return "", err
+	}
+
+	var defaultSC *storageV1.StorageClass
+	for i, sc := range scs.Items {
+		if sc.Annotations[defaultStorageClassAnnotation] != "true" &&
+			sc.Annotations[defaultStorageClassBetaAnnotation] != "true" {
+			continue
+		}
+		if defaultSC == nil || defaultSC.CreationTimestamp.Before(&sc.CreationTimestamp) {
+			defaultSC = &scs.Items[i]
+		}
+	}
+	if defaultSC == nil {
+		return "", nil
+	}
+	return defaultSC.Name, nil
+}
+
 // getNodeID returns node ID, it could be a k8s node UID or value of annotation
 func (e *Extender) getNodeID(node coreV1.Node) string {
 	if e.featureChecker.IsEnabled(fc.FeatureNodeIDFromAnnotation) {
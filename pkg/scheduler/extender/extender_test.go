@@ -18,7 +18,10 @@ package extender
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 
@@ -38,10 +41,12 @@ import (
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
 	volcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/bytesize"
 	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	fc "github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/capacitymanager"
 	csibmnodeconst "github.com/dell/csi-baremetal/pkg/crcontrollers/operator/common"
 )
 
@@ -146,17 +151,96 @@ func TestExtender_gatherVolumesByProvisioner_Success(t *testing.T) {
 	// create PVCs and SC
 	applyObjs(t, e.k8sClient, &testPVC1, &testPVC2, &testSC1)
 
-	volumes, err := e.gatherVolumesByProvisioner(testCtx, &pod)
+	volumes, _, err := e.gatherVolumesByProvisioner(testCtx, &pod, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(volumes))
 }
 
+func TestExtender_gatherVolumesByProvisioner_PlacementStrategy(t *testing.T) {
+	e := setup(t)
+	sc := testSC1.DeepCopy()
+	sc.Parameters[base.PlacementStrategyKey] = capacityplanner.SpreadStrategy
+	applyObjs(t, e.k8sClient, &testPVC1, sc)
+
+	pod := testPod
+	pod.Spec.Volumes = []coreV1.Volume{
+		{VolumeSource: coreV1.VolumeSource{CSI: &testCSIVolumeSrc}},
+		{VolumeSource: coreV1.VolumeSource{
+			PersistentVolumeClaim: &coreV1.PersistentVolumeClaimVolumeSource{ClaimName: testPVC1Name}}},
+	}
+	volumes, strategies, err := e.gatherVolumesByProvisioner(testCtx, &pod, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(volumes))
+	assert.Equal(t, map[string]string{testPVC1Name: capacityplanner.SpreadStrategy}, strategies)
+}
+
+func TestExtender_gatherVolumesByProvisioner_GenericEphemeral(t *testing.T) {
+	e := setup(t)
+	args := []byte(`{"pod": {"metadata": {"name": "pod1"}, "spec": {"volumes": [
+		{"name": "scratch", "ephemeral": {"volumeClaimTemplate": {"spec": {
+			"storageClassName": "` + testSCName1 + `", "volumeMode": "Block",
+			"resources": {"requests": {"storage": "10Gi"}}}}}},
+		{"name": "config", "configMap": {"name": "config"}}]}}}`)
+	claims, err := parseEphemeralClaims(args)
+	assert.Nil(t, err)
+	assert.Len(t, claims, 1)
+
+	pod := testPod
+	pod.Spec.Volumes = []coreV1.Volume{{Name: "scratch"}, {Name: "config"}}
+	applyObjs(t, e.k8sClient, &testSC1)
+
+	// PVC isn't created yet, volume is constructed from the template
+	volumes, _, err := e.gatherVolumesByProvisioner(testCtx, &pod, claims)
+	assert.Nil(t, err)
+	assert.Len(t, volumes, 1)
+	assert.Equal(t, testPodName+"-scratch", volumes[0].Id)
+	assert.Equal(t, int64(10*1024*1024*1024), volumes[0].Size)
+	assert.Equal(t, string(coreV1.PersistentVolumeBlock), volumes[0].Mode)
+	assert.Equal(t, util.ConvertStorageClass(testStorageType), volumes[0].StorageClass)
+
+	// PVC is created and bound
+	pvc := testPVC1
+	pvc.Name = testPodName + "-scratch"
+	pvc.Status.Phase = coreV1.ClaimBound
+	applyObjs(t, e.k8sClient, &pvc)
+	volumes, _, err = e.gatherVolumesByProvisioner(testCtx, &pod, claims)
+	assert.Nil(t, err)
+	assert.Len(t, volumes, 0)
+}
+
+func TestExtender_gatherVolumesByProvisioner_GenericEphemeralDefaultSC(t *testing.T) {
+	e := setup(t)
+	args := []byte(`{"pod": {"metadata": {"name": "pod1"}, "spec": {"volumes": [
+		{"name": "scratch", "ephemeral": {"volumeClaimTemplate": {"spec": {
+			"resources": {"requests": {"storage": "10Gi"}}}}}}]}}}`)
+	claims, err := parseEphemeralClaims(args)
+	assert.Nil(t, err)
+
+	pod := testPod
+	pod.Spec.Volumes = []coreV1.Volume{{Name: "scratch"}}
+	applyObjs(t, e.k8sClient, &testSC1, &testSC2)
+
+	// there is no default storage class
+	volumes, _, err := e.gatherVolumesByProvisioner(testCtx, &pod, claims)
+	assert.Nil(t, err)
+	assert.Len(t, volumes, 0)
+
+	// storage class of the driver is the default one
+	sc := testSC1
+	sc.Annotations = map[string]string{defaultStorageClassAnnotation: "true"}
+	assert.Nil(t, e.k8sClient.Update(testCtx, &sc))
+	volumes, _, err = e.gatherVolumesByProvisioner(testCtx, &pod, claims)
+	assert.Nil(t, err)
+	assert.Len(t, volumes, 1)
+	assert.Equal(t, util.ConvertStorageClass(testStorageType), volumes[0].StorageClass)
+}
+
 func TestExtender_gatherVolumesByProvisioner_Fail(t *testing.T) {
 	e := setup(t)
 
 	// sc mapping empty
 	pod := testPod
-	volumes, err := e.gatherVolumesByProvisioner(testCtx, &pod)
+	volumes, _, err := e.gatherVolumesByProvisioner(testCtx, &pod, nil)
 	assert.Nil(t, volumes)
 	assert.NotNil(t, err)
 
@@ -171,7 +255,7 @@ func TestExtender_gatherVolumesByProvisioner_Fail(t *testing.T) {
 	// create SC
 	applyObjs(t, e.k8sClient, &testSC1)
 
-	volumes, err = e.gatherVolumesByProvisioner(testCtx, &pod)
+	volumes, _, err = e.gatherVolumesByProvisioner(testCtx, &pod, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(volumes))
 	assert.True(t, volumes[0].Ephemeral)
@@ -188,7 +272,7 @@ func TestExtender_gatherVolumesByProvisioner_Fail(t *testing.T) {
 			},
 		},
 	})
-	volumes, err = e.gatherVolumesByProvisioner(testCtx, &pod)
+	volumes, _, err = e.gatherVolumesByProvisioner(testCtx, &pod, nil)
 	assert.Nil(t, volumes)
 	assert.NotNil(t, err)
 
@@ -206,7 +290,7 @@ func TestExtender_gatherVolumesByProvisioner_Fail(t *testing.T) {
 		},
 	}}
 
-	volumes, err = e.gatherVolumesByProvisioner(testCtx, &pod)
+	volumes, _, err = e.gatherVolumesByProvisioner(testCtx, &pod, nil)
 	assert.Nil(t, err)
 	assert.NotNil(t, volumes)
 	assert.Equal(t, 1, len(volumes))
@@ -215,7 +299,7 @@ func TestExtender_gatherVolumesByProvisioner_Fail(t *testing.T) {
 
 func TestExtender_constructVolumeFromCSISource_Success(t *testing.T) {
 	e := setup(t)
-	expectedSize, err := util.StrToBytes(testSizeStr)
+	expectedSize, err := bytesize.Parse(testSizeStr)
 	assert.Nil(t, err)
 	expectedVolume := &genV1.Volume{
 		StorageClass: util.ConvertStorageClass(testStorageType),
@@ -266,6 +350,47 @@ func TestExtender_constructVolumeFromCSISource_Fail(t *testing.T) {
 	assert.Contains(t, err.Error(), sizeStr)
 }
 
+func TestExtender_planNodes_PlacementStrategy(t *testing.T) {
+	var (
+		e       = setup(t)
+		nodeUID = "node-1111-uuid"
+		nodes   = []coreV1.Node{{ObjectMeta: metaV1.ObjectMeta{UID: types.UID(nodeUID), Name: "NODE-1"}}}
+		small   = e.k8sClient.ConstructACCR(uuid.New().String(),
+			genV1.AvailableCapacity{NodeId: nodeUID, StorageClass: v1.StorageClassHDD, Size: 50 * int64(util.GBYTE)})
+		large = e.k8sClient.ConstructACCR(uuid.New().String(),
+			genV1.AvailableCapacity{NodeId: nodeUID, StorageClass: v1.StorageClassHDD, Size: 100 * int64(util.GBYTE)})
+		volume = &genV1.Volume{Id: testPVC1Name, StorageClass: v1.StorageClassHDD, Size: 10 * int64(util.GBYTE)}
+	)
+	applyObjs(t, e.k8sClient, small, large)
+	plan := func(strategies map[string]string) (*capacityplanner.VolumesPlacingPlan, error) {
+		_, _, placingPlan, err := e.planNodes(testCtx, nodes, []*genV1.Volume{volume}, strategies,
+			capacityplanner.NewACReader(e.k8sClient, e.logger, true),
+			capacityplanner.NewACRReader(e.k8sClient, e.logger, true))
+		return placingPlan, err
+	}
+
+	// default strategy is best-fit
+	placingPlan, err := plan(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, small.Name, placingPlan.GetACForVolume(nodeUID, volume).Name)
+
+	// strategy of storage class overrides the default one
+	placingPlan, err = plan(map[string]string{testPVC1Name: capacityplanner.WorstFitStrategy})
+	assert.Nil(t, err)
+	assert.Equal(t, large.Name, placingPlan.GetACForVolume(nodeUID, volume).Name)
+
+	// default strategy is set for extender
+	strategy, err := capacityplanner.NewPlacementStrategy(capacityplanner.WorstFitStrategy, e.k8sClient, e.logger)
+	assert.Nil(t, err)
+	e.SetPlacementStrategy(strategy)
+	placingPlan, err = plan(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, large.Name, placingPlan.GetACForVolume(nodeUID, volume).Name)
+
+	_, err = plan(map[string]string{testPVC1Name: "first-fit"})
+	assert.NotNil(t, err)
+}
+
 func TestExtender_filterSuccess(t *testing.T) {
 	var (
 		node1Name = "NODE-1"
@@ -286,7 +411,7 @@ func TestExtender_filterSuccess(t *testing.T) {
 
 	// empty volumes
 	e = setup(t)
-	matched, failed, err := e.filter(testCtx, nodes, nil)
+	matched, failed, err := e.filter(testCtx, nodes, nil, nil)
 	assert.Nil(t, err)
 	assert.Nil(t, failed)
 	assert.Equal(t, len(nodes), len(matched))
@@ -387,7 +512,7 @@ func TestExtender_filterSuccess(t *testing.T) {
 	}
 
 	for _, testCase := range testCases {
-		matchedNodes, failedNode, err := e.filter(testCtx, nodes, testCase.Volumes)
+		matchedNodes, failedNode, err := e.filter(testCtx, nodes, testCase.Volumes, nil)
 		assert.Equal(t, len(nodes)-len(matchedNodes), len(failedNode), testCase.Msg)
 		matchedNodeNames := getNodeNames(matchedNodes)
 		assert.Equal(t, len(testCase.ExpectedNodeNames), len(matchedNodes),
@@ -415,6 +540,47 @@ func TestExtender_filterSuccess(t *testing.T) {
 	}
 }
 
+func TestExtender_DebugHandler(t *testing.T) {
+	e := setup(t)
+	nodes := []coreV1.Node{
+		{ObjectMeta: metaV1.ObjectMeta{UID: types.UID("node-1111-uuid"), Name: "NODE-1"}},
+		{ObjectMeta: metaV1.ObjectMeta{UID: types.UID("node-2222-uuid"), Name: "NODE-2"}},
+	}
+	ac := e.k8sClient.ConstructACCR(uuid.New().String(), genV1.AvailableCapacity{
+		NodeId: "node-1111-uuid", StorageClass: util.ConvertStorageClass(testStorageType), Size: int64(util.GBYTE)})
+	pod := testPod
+	pod.Spec.Volumes = []coreV1.Volume{{
+		Name: "data",
+		VolumeSource: coreV1.VolumeSource{
+			PersistentVolumeClaim: &coreV1.PersistentVolumeClaimVolumeSource{ClaimName: testPVC1Name},
+		},
+	}}
+	applyObjs(t, e.k8sClient, &nodes[0], &nodes[1], ac, &testSC1, &testPVC1, &pod)
+
+	explain := func(podKey string) *FilterExplanation {
+		w := httptest.NewRecorder()
+		e.DebugHandler(w, httptest.NewRequest(http.MethodGet, "/debug?pod="+podKey, nil))
+		explanation := &FilterExplanation{}
+		assert.Nil(t, json.NewDecoder(w.Body).Decode(explanation))
+		return explanation
+	}
+
+	explanation := explain(testNs + "/" + testPodName)
+	assert.Empty(t, explanation.Error)
+	assert.Len(t, explanation.Volumes, 1)
+	assert.Equal(t, []string{"NODE-1"}, explanation.PassedNodes)
+	assert.Equal(t, map[string]string{"NODE-2": filterReasonMessages[reasonNoCapacity]}, explanation.FailedNodes)
+
+	// node without ID annotation
+	e.featureChecker.(*fc.FeatureConfig).Update(fc.FeatureNodeIDFromAnnotation, true)
+	explanation = explain(testNs + "/" + testPodName)
+	assert.Empty(t, explanation.PassedNodes)
+	assert.Equal(t, filterReasonMessages[reasonNoNodeID], explanation.FailedNodes["NODE-1"])
+
+	assert.NotEmpty(t, explain(testPodName).Error)
+	assert.NotEmpty(t, explain(testNs+"/not-existing-pod").Error)
+}
+
 func TestExtender_getSCNameStorageType_Success(t *testing.T) {
 	e := setup(t)
 	// create 2 storage classes
@@ -449,6 +615,8 @@ func setup(t *testing.T) *Extender {
 		provisioner:            testProvisioner,
 		logger:                 testLogger.WithField("component", "Extender"),
 		capacityManagerBuilder: &capacityplanner.DefaultCapacityManagerBuilder{},
+		capacity:               capacitymanager.NewManagerImpl(kubeClient, testLogger),
+		strategies:             map[string]capacityplanner.PlacementStrategy{},
 	}
 }
 
@@ -0,0 +1,345 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulator is a test-kit for scheduler extender, it starts extender on fake k8s client with synthetic
+// Drive and AvailableCapacity CRs and replays scheduling requests of pods from JSON fixture, so capacity edge cases
+// reported by users could be covered by regression tests
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api/v1"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/bytesize"
+	fc "github.com/dell/csi-baremetal/pkg/base/featureconfig"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/scheduler/extender"
+)
+
+const (
+	// Namespace is the namespace of pods, PVCs and CRs created by simulator
+	Namespace = "default"
+	// Provisioner is the name of provisioner of storage classes created by simulator
+	Provisioner = "csi-baremetal"
+
+	filterPath = "/filter"
+)
+
+// Fixture describes cluster state and scheduling requests which are replayed one by one,
+// sizes are strings accepted by bytesize.Parse, e.g. "100Gi" or "4TB"
+type Fixture struct {
+	Nodes []Node `json:"nodes"`
+	// StorageClasses maps name of storage class to storage type, e.g. "csi-baremetal-sc-hddlvg": "HDDLVG"
+	StorageClasses map[string]string `json:"storageClasses"`
+	// Drives are created as Drive CRs, AvailableCapacity with the size of the drive is created for each of them
+	Drives []Drive `json:"drives"`
+	// AvailableCapacities are created in addition to ones of Drives, e.g. for LVG
+	AvailableCapacities []AvailableCapacity `json:"availableCapacities"`
+	Pods                []Pod               `json:"pods"`
+}
+
+// Node is k8s node, UID is used as node ID
+type Node struct {
+	Name string `json:"name"`
+	UID  string `json:"uid"`
+}
+
+// Drive is a drive on the node with Name
+type Drive struct {
+	Node   string `json:"node"`
+	Serial string `json:"serial"`
+	Type   string `json:"type"`
+	Size   string `json:"size"`
+}
+
+// AvailableCapacity is a capacity of StorageClass on the node with Name
+type AvailableCapacity struct {
+	Node         string `json:"node"`
+	StorageClass string `json:"storageClass"`
+	Size         string `json:"size"`
+}
+
+// Pod is a scheduling request of pod with PVCs of Volumes
+type Pod struct {
+	Name    string   `json:"name"`
+	Volumes []Volume `json:"volumes"`
+	// ExpectedNodes are names of nodes which are expected to pass filter, order isn't important
+	ExpectedNodes []string `json:"expectedNodes"`
+	// ExpectedError is true if filter is expected to fail
+	ExpectedError bool `json:"expectedError"`
+}
+
+// Volume is PVC of the pod
+type Volume struct {
+	StorageClass string `json:"storageClass"`
+	Size         string `json:"size"`
+}
+
+// Result is the result of scheduling request of the pod
+type Result struct {
+	Pod         string
+	PassedNodes []string
+	FailedNodes schedulerapi.FailedNodesMap
+	Error       string
+}
+
+// Simulator serves filter endpoint of extender on state created from Fixture
+type Simulator struct {
+	fixture *Fixture
+	client  *k8s.KubeClient
+	server  *httptest.Server
+	nodeIDs map[string]string
+}
+
+// LoadFixture reads Fixture from JSON file
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fixture := &Fixture{}
+	if err = json.Unmarshal(data, fixture); err != nil {
+		return nil, fmt.Errorf("unable to parse fixture %s: %v", path, err)
+	}
+	return fixture, nil
+}
+
+// New creates CRs of the fixture in fake k8s client and starts extender, Close should be called at the end
+func New(fixture *Fixture, logger *logrus.Logger) (*Simulator, error) {
+	client, err := k8s.GetFakeKubeClient(Namespace, logger)
+	if err != nil {
+		return nil, err
+	}
+	s := &Simulator{
+		fixture: fixture,
+		client:  client,
+		nodeIDs: make(map[string]string, len(fixture.Nodes)),
+	}
+	for _, node := range fixture.Nodes {
+		s.nodeIDs[node.Name] = node.UID
+	}
+	if err = s.createObjects(context.Background()); err != nil {
+		return nil, err
+	}
+
+	e, err := extender.NewExtender(logger, k8s.NewKubeClient(client, logger, Namespace),
+		k8s.NewKubeCache(client, logger), Provisioner, fc.NewFeatureConfig())
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(filterPath, e.FilterHandler)
+	s.server = httptest.NewServer(mux)
+	return s, nil
+}
+
+// Close stops extender
+func (s *Simulator) Close() {
+	s.server.Close()
+}
+
+// Client returns k8s client of the simulator, it could be used to change state between requests
+func (s *Simulator) Client() *k8s.KubeClient {
+	return s.client
+}
+
+// Replay sends scheduling requests of all pods of the fixture in order, reservations of previous pods
+// are kept, so pods compete for capacity as pending pods of a real cluster
+// Returns results of all requests and descriptions of results which differ from expected ones
+func (s *Simulator) Replay() ([]*Result, []string, error) {
+	var (
+		results    = make([]*Result, 0, len(s.fixture.Pods))
+		mismatches []string
+	)
+	for _, pod := range s.fixture.Pods {
+		res, err := s.Filter(pod)
+		if err != nil {
+			return nil, nil, err
+		}
+		results = append(results, res)
+
+		expected := append([]string{}, pod.ExpectedNodes...)
+		sort.Strings(expected)
+		switch {
+		case pod.ExpectedError != (res.Error != ""):
+			mismatches = append(mismatches, fmt.Sprintf("pod %s: expected error %v, got %q",
+				pod.Name, pod.ExpectedError, res.Error))
+		case res.Error == "" && !reflect.DeepEqual(expected, res.PassedNodes):
+			mismatches = append(mismatches, fmt.Sprintf("pod %s: expected nodes %v, got %v, failed nodes %v",
+				pod.Name, expected, res.PassedNodes, res.FailedNodes))
+		}
+	}
+	return results, mismatches, nil
+}
+
+// Filter creates PVCs of the pod and sends filter request for it with all nodes of the fixture
+func (s *Simulator) Filter(pod Pod) (*Result, error) {
+	ctx := context.Background()
+	k8sPod := &coreV1.Pod{
+		TypeMeta:   metaV1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metaV1.ObjectMeta{Name: pod.Name, Namespace: Namespace},
+	}
+	for i, vol := range pod.Volumes {
+		size, err := bytesize.Parse(vol.Size)
+		if err != nil {
+			return nil, fmt.Errorf("pod %s: %v", pod.Name, err)
+		}
+		storageClass := vol.StorageClass
+		pvc := &coreV1.PersistentVolumeClaim{
+			ObjectMeta: metaV1.ObjectMeta{Name: fmt.Sprintf("%s-%d", pod.Name, i), Namespace: Namespace},
+			Spec: coreV1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClass,
+				Resources: coreV1.ResourceRequirements{
+					Requests: coreV1.ResourceList{coreV1.ResourceStorage: *resource.NewQuantity(size, resource.BinarySI)},
+				},
+			},
+		}
+		if err = s.client.Create(ctx, pvc); err != nil {
+			return nil, err
+		}
+		k8sPod.Spec.Volumes = append(k8sPod.Spec.Volumes, coreV1.Volume{
+			Name: pvc.Name,
+			VolumeSource: coreV1.VolumeSource{
+				PersistentVolumeClaim: &coreV1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+			},
+		})
+	}
+
+	args := schedulerapi.ExtenderArgs{Pod: k8sPod, Nodes: &coreV1.NodeList{}}
+	for _, node := range s.fixture.Nodes {
+		args.Nodes.Items = append(args.Nodes.Items, coreV1.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: node.Name, UID: types.UID(node.UID)},
+		})
+	}
+	body, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.Post(s.server.URL+filterPath, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var filterRes schedulerapi.ExtenderFilterResult
+	if err = json.NewDecoder(resp.Body).Decode(&filterRes); err != nil {
+		return nil, err
+	}
+
+	res := &Result{Pod: pod.Name, PassedNodes: []string{}, FailedNodes: filterRes.FailedNodes, Error: filterRes.Error}
+	if filterRes.Nodes != nil {
+		for _, node := range filterRes.Nodes.Items {
+			res.PassedNodes = append(res.PassedNodes, node.Name)
+		}
+	}
+	sort.Strings(res.PassedNodes)
+	return res, nil
+}
+
+// createObjects creates storage classes, Drive and AvailableCapacity CRs of the fixture
+func (s *Simulator) createObjects(ctx context.Context) error {
+	for name, storageType := range s.fixture.StorageClasses {
+		sc := &storageV1.StorageClass{
+			ObjectMeta:  metaV1.ObjectMeta{Name: name},
+			Provisioner: Provisioner,
+			Parameters:  map[string]string{base.StorageTypeKey: storageType},
+		}
+		if err := s.client.Create(ctx, sc); err != nil {
+			return err
+		}
+	}
+
+	for _, drive := range s.fixture.Drives {
+		nodeID, size, err := s.parse(drive.Node, drive.Size)
+		if err != nil {
+			return fmt.Errorf("drive %s: %v", drive.Serial, err)
+		}
+		driveUUID := uuid.New().String()
+		driveCR := s.client.ConstructDriveCR(driveUUID, genV1.Drive{
+			UUID:         driveUUID,
+			NodeId:       nodeID,
+			SerialNumber: drive.Serial,
+			Type:         drive.Type,
+			Size:         size,
+			Health:       v1.HealthGood,
+			Status:       v1.DriveStatusOnline,
+		})
+		if err = s.client.CreateCR(ctx, driveUUID, driveCR); err != nil {
+			return err
+		}
+		if err = s.createAC(ctx, genV1.AvailableCapacity{
+			Location:     driveUUID,
+			NodeId:       nodeID,
+			StorageClass: util.ConvertDriveTypeToStorageClass(drive.Type),
+			Size:         size,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, ac := range s.fixture.AvailableCapacities {
+		nodeID, size, err := s.parse(ac.Node, ac.Size)
+		if err != nil {
+			return fmt.Errorf("available capacity %s on %s: %v", ac.StorageClass, ac.Node, err)
+		}
+		if err = s.createAC(ctx, genV1.AvailableCapacity{
+			Location:     uuid.New().String(),
+			NodeId:       nodeID,
+			StorageClass: ac.StorageClass,
+			Size:         size,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Simulator) createAC(ctx context.Context, ac genV1.AvailableCapacity) error {
+	name := uuid.New().String()
+	return s.client.CreateCR(ctx, name, s.client.ConstructACCR(name, ac))
+}
+
+// parse returns ID of the node with name and size in bytes
+func (s *Simulator) parse(node, size string) (string, int64, error) {
+	nodeID, ok := s.nodeIDs[node]
+	if !ok {
+		return "", 0, fmt.Errorf("unknown node %s", node)
+	}
+	sizeBytes, err := bytesize.Parse(size)
+	if err != nil {
+		return "", 0, err
+	}
+	return nodeID, sizeBytes, nil
+}
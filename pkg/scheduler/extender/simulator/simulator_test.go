@@ -0,0 +1,56 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulator_Replay(t *testing.T) {
+	fixture, err := LoadFixture("testdata/capacity.json")
+	assert.Nil(t, err)
+
+	s, err := New(fixture, logrus.New())
+	assert.Nil(t, err)
+	defer s.Close()
+
+	results, mismatches, err := s.Replay()
+	assert.Nil(t, err)
+	assert.Len(t, results, len(fixture.Pods))
+	for _, res := range results {
+		t.Logf("%s: passed %v, failed %v, error %q", res.Pod, res.PassedNodes, res.FailedNodes, res.Error)
+	}
+	assert.Empty(t, mismatches)
+}
+
+func TestSimulator_New_Fail(t *testing.T) {
+	_, err := LoadFixture("testdata/not-existing.json")
+	assert.NotNil(t, err)
+
+	_, err = New(&Fixture{Drives: []Drive{{Node: "unknown", Serial: "hdd-1", Type: "HDD", Size: "1Gi"}}},
+		logrus.New())
+	assert.NotNil(t, err)
+
+	_, err = New(&Fixture{
+		Nodes:  []Node{{Name: "node-1", UID: "node-1-uid"}},
+		Drives: []Drive{{Node: "node-1", Serial: "hdd-1", Type: "HDD", Size: "large"}},
+	}, logrus.New())
+	assert.NotNil(t, err)
+}
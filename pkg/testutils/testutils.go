@@ -55,7 +55,7 @@ func ReadVolumeAndChangeStatus(k8sClient *k8s.KubeClient, volumeID string, names
 		ctx      = context.WithValue(context.Background(), base.RequestUUID, volumeID)
 	)
 
-	if err := k8sClient.ReadCRWithAttempts(volumeID, namespace, v, attempts); err != nil {
+	if err := k8sClient.ReadCRWithAttempts(ctx, volumeID, namespace, v, attempts); err != nil {
 		return err
 	}
 
@@ -35,9 +35,10 @@ type LoopBackManagerConfigDevice struct {
 
 // LoopBackManagerConfigNode struct represents particular configuration of LoopBackManager for specified node
 type LoopBackManagerConfigNode struct {
-	NodeID     *string                       `yaml:"nodeID,omitempty"`
-	DriveCount *int                          `yaml:"driveCount,omitempty"`
-	Drives     []LoopBackManagerConfigDevice `yaml:"drives,omitempty"`
+	NodeID      *string                       `yaml:"nodeID,omitempty"`
+	DriveCount  *int                          `yaml:"driveCount,omitempty"`
+	Drives      []LoopBackManagerConfigDevice `yaml:"drives,omitempty"`
+	Unavailable *bool                         `yaml:"unavailable,omitempty"`
 }
 
 // LoopBackManagerConfig struct is the configuration for LoopBackManager.
@@ -0,0 +1,313 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenarios
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/test/e2e/framework"
+	e2elog "k8s.io/kubernetes/test/e2e/framework/log"
+	e2enode "k8s.io/kubernetes/test/e2e/framework/node"
+	e2epod "k8s.io/kubernetes/test/e2e/framework/pod"
+	"k8s.io/kubernetes/test/e2e/storage/testsuites"
+
+	"github.com/dell/csi-baremetal/pkg/node/faults"
+	"github.com/dell/csi-baremetal/test/e2e/common"
+)
+
+const (
+	nodeDaemonSetName      = "csi-baremetal-node"
+	nodeContainerName      = "node"
+	faultInjectionArg      = "--faultinjection=true"
+	controlPlaneLabel      = "node-role.kubernetes.io/master"
+	apiServerManifest      = "/etc/kubernetes/manifests/kube-apiserver.yaml"
+	chaosConvergeTimeout   = time.Minute * 10
+	chaosComponentsTimeout = time.Minute * 5
+)
+
+// DefineChaosTestSuite defines custom csi-baremetal chaos tests
+// Each test injects failure in the middle of volume operation and checks that the system converges
+func DefineChaosTestSuite(driver testsuites.TestDriver) {
+	ginkgo.Context("Baremetal-csi chaos tests", func() {
+		defineChaosTest(driver)
+	})
+}
+
+func defineChaosTest(driver testsuites.TestDriver) {
+	var (
+		pod           *corev1.Pod
+		pvc           *corev1.PersistentVolumeClaim
+		k8sSC         *storagev1.StorageClass
+		executor      = common.GetExecutor()
+		driverCleanup func()
+		ns            string
+		// restore functions which should be called in cleanup if test hasn't called them
+		restoreFns []func()
+		f          = framework.NewDefaultFramework("chaos")
+	)
+
+	init := func() {
+		var (
+			perTestConf *testsuites.PerTestConfig
+			err         error
+		)
+		ns = f.Namespace.Name
+		pod, pvc, restoreFns = nil, nil, nil
+
+		perTestConf, driverCleanup = driver.PrepareTest(f)
+		enableFaultInjection(f)
+
+		k8sSC = driver.(*baremetalDriver).GetDynamicProvisionStorageClass(perTestConf, "xfs")
+		k8sSC, err = f.ClientSet.StorageV1().StorageClasses().Create(k8sSC)
+		framework.ExpectNoError(err)
+	}
+
+	cleanup := func() {
+		e2elog.Logf("Starting cleanup for test Chaos")
+		for i := len(restoreFns) - 1; i >= 0; i-- {
+			restoreFns[i]()
+		}
+		common.CleanupAfterCustomTest(f, driverCleanup, []*corev1.Pod{pod}, []*corev1.PersistentVolumeClaim{pvc})
+	}
+
+	// once wraps restore function so it could be called by test and by cleanup
+	once := func(fn func()) func() {
+		done := false
+		restore := func() {
+			if !done {
+				done = true
+				fn()
+			}
+		}
+		restoreFns = append(restoreFns, restore)
+		return restore
+	}
+
+	createPVCAndPod := func() {
+		var err error
+		pvc, err = f.ClientSet.CoreV1().PersistentVolumeClaims(ns).
+			Create(constructPVC(ns, driver.(testsuites.DynamicPVTestDriver).GetClaimSize(), k8sSC.Name, pvcName))
+		framework.ExpectNoError(err)
+
+		// pod isn't waited for running since faults are injected in the middle of its volume setup
+		pod = common.MakePod(ns, nil, []*corev1.PersistentVolumeClaim{pvc}, false, "sleep 3600")
+		pod, err = f.ClientSet.CoreV1().Pods(ns).Create(pod)
+		framework.ExpectNoError(err)
+		e2elog.Logf("Pod %s with PVC %s created.", pod.Name, pvc.Name)
+	}
+
+	expectConverged := func() {
+		err := e2epod.WaitTimeoutForPodRunningInNamespace(f.ClientSet, pod.Name, ns, chaosConvergeTimeout)
+		framework.ExpectNoError(err)
+
+		pvc, err = f.ClientSet.CoreV1().PersistentVolumeClaims(ns).Get(pvc.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err)
+		if pvc.Status.Phase != corev1.ClaimBound {
+			framework.Failf("PVC %s is in %s phase, expected %s", pvc.Name, pvc.Status.Phase, corev1.ClaimBound)
+		}
+		e2elog.Logf("Pod %s is running with bound PVC %s", pod.Name, pvc.Name)
+	}
+
+	ginkgo.It("Pod should become running after drive manager was killed during NodeStage", func() {
+		init()
+		defer cleanup()
+
+		// NodeStage is hanging on each node until drive manager is killed
+		nodes, err := f.ClientSet.CoreV1().Nodes().List(metav1.ListOptions{})
+		framework.ExpectNoError(err)
+		for _, node := range nodes.Items {
+			nodeName := node.Name
+			setNodeFaults(f, nodeName, fmt.Sprintf("%s=delay:5m", faults.StageVolume))
+			once(func() { setNodeFaults(f, nodeName, "") })
+		}
+
+		createPVCAndPod()
+		nodeName := waitForPodScheduled(f, pod)
+
+		// make drive manager unavailable and kill it while volume is being staged
+		defaultDriveCount := 3
+		unavailable := true
+		conf := &common.LoopBackManagerConfig{DefaultDriveCount: &defaultDriveCount,
+			Nodes: []common.LoopBackManagerConfigNode{{NodeID: &nodeName, Unavailable: &unavailable}}}
+		applyLMConfig(f, conf)
+		once(func() { applyLMConfig(f, &common.LoopBackManagerConfig{DefaultDriveCount: &defaultDriveCount}) })
+
+		cmd := fmt.Sprintf("docker exec %s sh -c 'crictl stop $(crictl ps -q --name drivemgr)'", nodeName)
+		_, _, err = executor.RunCmd(cmd)
+		framework.ExpectNoError(err)
+
+		// remove faults and let components recover
+		for _, restore := range restoreFns {
+			restore()
+		}
+
+		expectConverged()
+	})
+
+	ginkgo.It("Pod should become running after API server was unavailable during CreateVolume", func() {
+		init()
+		defer cleanup()
+
+		createPVCAndPod()
+
+		// since test is run in Kind k8s cluster, node's name is the same as a name of docker container
+		masters, err := f.ClientSet.CoreV1().Nodes().List(metav1.ListOptions{LabelSelector: controlPlaneLabel})
+		framework.ExpectNoError(err)
+		if len(masters.Items) == 0 {
+			framework.Failf("Control plane node isn't found")
+		}
+		controlPlaneNodeName := masters.Items[0].Name
+
+		// kubelet stops static pod of API server when its manifest is removed
+		movedManifest := "/tmp/kube-apiserver.yaml"
+		cmd := fmt.Sprintf("docker exec %s mv %s %s", controlPlaneNodeName, apiServerManifest, movedManifest)
+		_, _, err = executor.RunCmd(cmd)
+		framework.ExpectNoError(err)
+		restoreAPIServer := once(func() {
+			cmd := fmt.Sprintf("docker exec %s mv %s %s", controlPlaneNodeName, movedManifest, apiServerManifest)
+			_, _, err := executor.RunCmd(cmd)
+			framework.ExpectNoError(err)
+			waitForAPIServer(f)
+		})
+
+		time.Sleep(time.Minute)
+		restoreAPIServer()
+
+		expectConverged()
+	})
+
+	ginkgo.It("Pod should become running after node was rebooted during volume formatting", func() {
+		init()
+		defer cleanup()
+
+		nodes, err := f.ClientSet.CoreV1().Nodes().List(metav1.ListOptions{})
+		framework.ExpectNoError(err)
+		for _, node := range nodes.Items {
+			nodeName := node.Name
+			setNodeFaults(f, nodeName, fmt.Sprintf("%s=delay:5m", faults.CreateFS))
+			once(func() { setNodeFaults(f, nodeName, "") })
+		}
+
+		createPVCAndPod()
+		nodeName := waitForPodScheduled(f, pod)
+
+		// wait until volume is being created
+		err = wait.PollImmediate(time.Second*5, chaosComponentsTimeout, func() (bool, error) {
+			return len(getUObjList(f, common.VolumeGVR).Items) > 0, nil
+		})
+		framework.ExpectNoError(err)
+
+		// since test is run in Kind k8s cluster, node's name is the same as a name of docker container
+		cmd := fmt.Sprintf("docker stop %s", nodeName)
+		_, _, err = executor.RunCmd(cmd)
+		framework.ExpectNoError(err)
+		startNode := once(func() {
+			_, _, err := executor.RunCmd(fmt.Sprintf("docker start %s", nodeName))
+			framework.ExpectNoError(err)
+		})
+
+		if !e2enode.WaitForNodeToBeNotReady(f.ClientSet, nodeName, chaosComponentsTimeout) {
+			framework.Failf("Node %s still ready", nodeName)
+		}
+		startNode()
+		if !e2enode.WaitForNodeToBeReady(f.ClientSet, nodeName, chaosComponentsTimeout) {
+			framework.Failf("Node %s still NotReady", nodeName)
+		}
+
+		// remove faults and let provisioning continue
+		for _, restore := range restoreFns {
+			restore()
+		}
+
+		expectConverged()
+	})
+}
+
+// enableFaultInjection adds fault injection flag to node daemonset and waits until its pods are updated
+func enableFaultInjection(f *framework.Framework) {
+	dsClient := f.ClientSet.AppsV1().DaemonSets(f.Namespace.Name)
+	ds, err := dsClient.Get(nodeDaemonSetName, metav1.GetOptions{})
+	framework.ExpectNoError(err)
+
+	for i, c := range ds.Spec.Template.Spec.Containers {
+		if c.Name == nodeContainerName && !strings.Contains(strings.Join(c.Args, " "), faultInjectionArg) {
+			ds.Spec.Template.Spec.Containers[i].Args = append(c.Args, faultInjectionArg)
+		}
+	}
+	_, err = dsClient.Update(ds)
+	framework.ExpectNoError(err)
+
+	err = wait.PollImmediate(time.Second*5, chaosComponentsTimeout, func() (bool, error) {
+		ds, err := dsClient.Get(nodeDaemonSetName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return ds.Status.ObservedGeneration >= ds.Generation &&
+			ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled &&
+			ds.Status.NumberReady == ds.Status.DesiredNumberScheduled, nil
+	})
+	framework.ExpectNoError(err)
+}
+
+// setNodeFaults sets faults annotation of k8s node, empty value removes annotation
+func setNodeFaults(f *framework.Framework, nodeName, value string) {
+	node, err := f.ClientSet.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+	framework.ExpectNoError(err)
+
+	if value == "" {
+		delete(node.Annotations, faults.NodeAnnotation)
+	} else {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[faults.NodeAnnotation] = value
+	}
+	_, err = f.ClientSet.CoreV1().Nodes().Update(node)
+	framework.ExpectNoError(err)
+	e2elog.Logf("Faults of node %s set to %q", nodeName, value)
+}
+
+// waitForPodScheduled waits until pod is scheduled and returns name of its node
+func waitForPodScheduled(f *framework.Framework, pod *corev1.Pod) string {
+	var nodeName string
+	err := wait.PollImmediate(time.Second*2, chaosComponentsTimeout, func() (bool, error) {
+		p, err := f.ClientSet.CoreV1().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		nodeName = p.Spec.NodeName
+		return nodeName != "", nil
+	})
+	framework.ExpectNoError(err)
+	return nodeName
+}
+
+// waitForAPIServer waits until API server responds again
+func waitForAPIServer(f *framework.Framework) {
+	err := wait.PollImmediate(time.Second*5, chaosComponentsTimeout, func() (bool, error) {
+		_, err := f.ClientSet.CoreV1().Namespaces().Get(f.Namespace.Name, metav1.GetOptions{})
+		return err == nil, nil
+	})
+	framework.ExpectNoError(err)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenarios
+
+import (
+	"time"
+
+	"github.com/onsi/ginkgo"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/kubernetes/test/e2e/framework"
+	e2elog "k8s.io/kubernetes/test/e2e/framework/log"
+	"k8s.io/kubernetes/test/e2e/storage/testsuites"
+
+	"github.com/dell/csi-baremetal/test/e2e/common"
+)
+
+// kindSmokeWriteCmd writes data on the volume and reads it back, pod becomes ready only if data is read
+const kindSmokeWriteCmd = "echo csi-baremetal > /mnt/volume1/data && sync && " +
+	"grep -q csi-baremetal /mnt/volume1/data && sleep 3600"
+
+// DefineKindSmokeTestSuite defines smoke test which is run in kind cluster with loopback drives (make test-kind)
+func DefineKindSmokeTestSuite(driver testsuites.TestDriver) {
+	ginkgo.Context("Baremetal-csi driver kind smoke tests", func() {
+		kindSmokeTest(driver)
+	})
+}
+
+// kindSmokeTest provisions a volume on loopback drive, writes data on it and deletes it
+func kindSmokeTest(driver testsuites.TestDriver) {
+	var (
+		pod           *corev1.Pod
+		pvcs          []*corev1.PersistentVolumeClaim
+		k8sSC         *storagev1.StorageClass
+		driverCleanup func()
+		ns            string
+		f             = framework.NewDefaultFramework("kind-smoke")
+	)
+
+	init := func() {
+		var (
+			perTestConf *testsuites.PerTestConfig
+			err         error
+		)
+		ns = f.Namespace.Name
+		perTestConf, driverCleanup = driver.PrepareTest(f)
+		k8sSC = driver.(*baremetalDriver).GetStorageClassWithStorageType(perTestConf, "HDD")
+		k8sSC, err = f.ClientSet.StorageV1().StorageClasses().Create(k8sSC)
+		framework.ExpectNoError(err)
+	}
+
+	cleanup := func() {
+		e2elog.Logf("Starting cleanup for kind smoke test")
+		var pods []*corev1.Pod
+		if pod != nil {
+			pods = append(pods, pod)
+		}
+		common.CleanupAfterCustomTest(f, driverCleanup, pods, pvcs)
+	}
+
+	ginkgo.It("should provision, write and delete volume in kind", func() {
+		init()
+		defer cleanup()
+		pvcs = createPVCs(f, 1, driver.(testsuites.DynamicPVTestDriver).GetClaimSize(), k8sSC.Name, ns)
+		var err error
+		pod, err = common.CreatePod(f.ClientSet, ns, nil, pvcs, false, kindSmokeWriteCmd)
+		framework.ExpectNoError(err)
+
+		pv, err := framework.GetBoundPV(f.ClientSet, pvcs[0])
+		framework.ExpectNoError(err)
+		common.CleanupAfterCustomTest(f, nil, []*corev1.Pod{pod}, pvcs)
+		framework.ExpectNoError(framework.WaitForPersistentVolumeDeleted(f.ClientSet, pv.Name,
+			5*time.Second, 2*time.Minute))
+		pod, pvcs = nil, nil
+	})
+}
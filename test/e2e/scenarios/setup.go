@@ -90,5 +90,7 @@ var _ = utils.SIGDescribe("CSI Volumes", func() {
 		DefineDifferentSCTestSuite(curDriver)
 		DefineStressTestSuite(curDriver)
 		DefineSchedulerTestSuite(curDriver)
+		DefineChaosTestSuite(curDriver)
+		DefineKindSmokeTestSuite(curDriver)
 	})
 })
@@ -53,6 +53,8 @@ var (
 	version            = "test"
 	testNs             = "default"
 	nodeId             = "localhost"
+	// how often Discover and VolumeManager's Reconcile imitation are triggered
+	reconcileInterval = time.Second
 
 	testDrives = []*api.Drive{
 		{
@@ -140,8 +142,8 @@ func newNodeSvc(kubeClient *k8s.KubeClient, nodeReady chan<- bool) {
 
 	go func() {
 		var doOnce sync.Once
-		for range time.Tick(10 * time.Second) {
-			err := csiNodeService.Discover()
+		for range time.Tick(reconcileInterval) {
+			err := csiNodeService.Discover(context.Background())
 			if err != nil {
 				ll.Fatalf("Discover failed: %v", err)
 			}
@@ -165,7 +167,7 @@ func prepareNodeMock(kubeClient *k8s.KubeClient, log *logrus.Logger) *node.CSINo
 	e := mocks.NewMockExecutor(map[string]mocks.CmdOut{fmt.Sprintf(lsblk.CmdTmpl, ""): {Stdout: mocks.LsblkTwoDevicesStr}})
 	e.SetSuccessIfNotFound(true)
 
-	nodeService := node.NewCSINodeService(nil, nodeId, log, kubeClient, kubeClient,
+	nodeService := node.NewCSINodeService(nil, e, nodeId, log, kubeClient, kubeClient,
 		new(mocks.NoOpRecorder), featureconfig.NewFeatureConfig())
 
 	nodeService.VolumeManager = *node.NewVolumeManager(c, e, log, kubeClient, kubeClient, new(mocks.NoOpRecorder), nodeId)
@@ -178,7 +180,7 @@ func prepareNodeMock(kubeClient *k8s.KubeClient, log *logrus.Logger) *node.CSINo
 
 // imitateVolumeManagerReconcile imitates working of VolumeManager's Reconcile loop under not k8s env.
 func imitateVolumeManagerReconcile(kubeClient *k8s.KubeClient) {
-	for range time.Tick(10 * time.Second) {
+	for range time.Tick(reconcileInterval) {
 		volumes := &vcrd.VolumeList{}
 		_ = kubeClient.ReadList(context.Background(), volumes)
 		for _, v := range volumes.Items {
@@ -37,6 +37,7 @@ CR_CONTROLLERS := crcontrollers
 
 ### components
 NODE             := node
+PRIV_HELPER      := privhelper
 DRIVE_MANAGER    := drivemgr
 CONTROLLER       := controller
 SCHEDULER        := scheduler
@@ -48,6 +49,9 @@ PLUGIN           := plugin
 BASE_DRIVE_MGR     := basemgr
 LOOPBACK_DRIVE_MGR := loopbackmgr
 DRIVE_MANAGER_TYPE := ${BASE_DRIVE_MGR}
+DRIVE_DOCTOR       := doctor
+IMPORTER           := importer
+DIAGNOSTICS        := collect-diagnostics
 
 # external components
 CSI_PROVISIONER := csi-provisioner
@@ -58,7 +62,10 @@ LIVENESS_PROBE  := livenessprobe
 BUSYBOX         := busybox
 
 HEALTH_PROBE    	 := health_probe
-HEALTH_PROBE_BIN_URL := https://github.com/grpc-ecosystem/grpc-health-probe/releases/download/v0.3.1/grpc_health_probe-linux-amd64
+HEALTH_PROBE_BIN_URL := https://github.com/grpc-ecosystem/grpc-health-probe/releases/download/v0.3.1/grpc_health_probe-linux-${ARCH}
+
+### target architecture of binaries, amd64 or arm64
+ARCH            ?= amd64
 
 ### go env vars
 GO_ENV_VARS     := GO111MODULE=on ${GOPRIVATE_PART} ${GOPROXY_PART}